@@ -8,8 +8,10 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Quiet hours need time zones; the runtime image has none
 
 	"github.com/mansoorceksport/metamorph/internal/config"
+	"github.com/mansoorceksport/metamorph/internal/infrastructure/notify"
 	"github.com/mansoorceksport/metamorph/internal/middleware"
 	"github.com/mansoorceksport/metamorph/internal/server"
 	"github.com/mansoorceksport/metamorph/internal/telemetry"
@@ -72,6 +74,16 @@ func main() {
 	}
 	log.Println("✓ Firebase initialized")
 
+	var pushClient notify.FCMClient
+	if cfg.Firebase.PushEnabled {
+		messagingClient, err := firebaseApp.Messaging(ctx)
+		if err != nil {
+			log.Fatalf("Failed to get Firebase Messaging client: %v", err)
+		}
+		pushClient = messagingClient
+		log.Println("✓ Push notifications enabled")
+	}
+
 	// Connect to MongoDB with OpenTelemetry instrumentation
 	ctxMongo, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -107,6 +119,9 @@ func main() {
 		DB:       0,
 	})
 	defer redisClient.Close()
+	if cfg.OTEL.Enabled {
+		redisClient.AddHook(telemetry.NewRedisHook())
+	}
 
 	// Ping Redis to verify connection
 	if err := redisClient.Ping(context.Background()).Err(); err != nil {
@@ -120,6 +135,7 @@ func main() {
 		MongoDB:     mongoDB,
 		RedisClient: redisClient,
 		AuthClient:  authClient,
+		PushClient:  pushClient,
 	})
 
 	// Graceful shutdown
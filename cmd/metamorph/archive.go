@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+func newArchiveCommand(g *globals) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Move old data into archive collections",
+	}
+	cmd.AddCommand(newArchiveWorkoutsCommand(g))
+	return cmd
+}
+
+// newArchiveWorkoutsCommand moves set logs and planned exercises of finished schedules older
+// than --months into compressed archive collections. Daily volumes and personal bests are not
+// touched, and workout detail reads fall back to the archive.
+func newArchiveWorkoutsCommand(g *globals) *cobra.Command {
+	var months, batch int
+
+	cmd := &cobra.Command{
+		Use:   "workouts",
+		Short: "Archive workout detail of old schedules",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if months < 1 {
+				return fmt.Errorf("--months must be at least 1")
+			}
+			if g.dryRun {
+				return fmt.Errorf("archive workouts does not support --dry-run")
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Hour)
+			defer cancel()
+
+			db, disconnect, err := g.connect(ctx)
+			if err != nil {
+				return err
+			}
+			defer disconnect()
+
+			archive := repository.NewMongoWorkoutArchiveRepository(db)
+			cutoff := time.Now().AddDate(0, -months, 0)
+			log.Printf("Archiving workout detail of schedules that started before %s", cutoff.Format(time.DateOnly))
+
+			var schedules, setLogs, planned int
+			for {
+				summary, err := archive.ArchiveBefore(ctx, cutoff, batch)
+				if summary != nil {
+					schedules += summary.Schedules
+					setLogs += summary.SetLogs
+					planned += summary.PlannedExercises
+				}
+				if err != nil {
+					return fmt.Errorf("archival stopped after %d schedules: %w", schedules, err)
+				}
+				if summary.Schedules < batch {
+					break
+				}
+			}
+
+			return g.print(cmd, newSummary("archive workouts", false).
+				Set("cutoff", cutoff.Format(time.DateOnly)).
+				Set("schedules", schedules).
+				Set("set_logs", setLogs).
+				Set("planned_exercises", planned))
+		},
+	}
+	cmd.Flags().IntVar(&months, "months", 12, "Archive schedules that started more than this many months ago")
+	cmd.Flags().IntVar(&batch, "batch", 500, "Schedules archived per batch")
+	return cmd
+}
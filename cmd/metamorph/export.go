@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os/signal"
+	"syscall"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/infrastructure/warehouse"
+	"github.com/mansoorceksport/metamorph/internal/repository"
+	"github.com/mansoorceksport/metamorph/internal/service"
+	"github.com/spf13/cobra"
+)
+
+func newExportCommand(g *globals) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Stream data to external systems",
+	}
+	cmd.AddCommand(newExportWarehouseCommand(g))
+	return cmd
+}
+
+// newExportWarehouseCommand streams scan, session and invoice changes of opted-in tenants
+// (Tenant.WarehouseExport) into the configured warehouse sink. It resumes from its last
+// checkpoint, so it can be restarted at any time. Requires MongoDB running as a replica set.
+//
+//	WAREHOUSE_SINK=clickhouse CLICKHOUSE_URL=https://... metamorph export warehouse
+//	WAREHOUSE_SINK=stdout metamorph export warehouse > events.jsonl
+func newExportWarehouseCommand(g *globals) *cobra.Command {
+	return &cobra.Command{
+		Use:   "warehouse",
+		Short: "Stream changes of opted-in tenants into the warehouse sink until interrupted",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if g.dryRun {
+				return fmt.Errorf("export warehouse does not support --dry-run")
+			}
+
+			cfg := g.cfg.Warehouse
+			var sink domain.WarehouseSink
+			switch cfg.Sink {
+			case "clickhouse":
+				sink = warehouse.NewClickHouseSink(warehouse.ClickHouseConfig{
+					URL:      cfg.ClickHouseURL,
+					Database: cfg.ClickHouseDatabase,
+					Table:    cfg.ClickHouseTable,
+					User:     cfg.ClickHouseUser,
+					Password: cfg.ClickHousePassword,
+				})
+			case "stdout":
+				// Rows own stdout with this sink; progress already goes to stderr
+				sink = warehouse.NewJSONLinesSink(cmd.OutOrStdout())
+			default:
+				return fmt.Errorf("unknown WAREHOUSE_SINK %q (expected clickhouse or stdout)", cfg.Sink)
+			}
+			if cfg.PseudonymKey == "" {
+				log.Println("Warning: WAREHOUSE_PSEUDONYM_KEY is empty; anonymized pseudonyms can be reversed by hashing known IDs")
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+
+			db, disconnect, err := g.connect(ctx)
+			if err != nil {
+				return err
+			}
+			defer disconnect()
+
+			exporter := service.NewWarehouseExporter(
+				sink,
+				repository.NewMongoTenantRepository(db),
+				repository.NewMongoUserRepository(db),
+				cfg.PseudonymKey,
+			)
+
+			// The feed name keys the checkpoint; a different sink should use its own
+			feed := repository.NewMongoChangeFeed(db, "warehouse_export:"+cfg.Sink)
+
+			log.Printf("Exporting changes to %s", cfg.Sink)
+			if err := feed.Watch(ctx, service.WarehouseCollections(), exporter.HandleChanges); err != nil && ctx.Err() == nil {
+				return fmt.Errorf("warehouse export stopped: %w", err)
+			}
+			log.Println("Warehouse export stopped")
+			return nil
+		},
+	}
+}
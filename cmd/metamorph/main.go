@@ -0,0 +1,91 @@
+// Command metamorph is the operations CLI: seeding, data migrations, recalculations, archival
+// and warehouse export. Every subcommand reads the same configuration as the API (MONGODB_URI,
+// MONGODB_DATABASE, ...), which --mongo and --db override, and ends with a summary printed as
+// text or, with --output json, as a single JSON object on stdout. Progress goes to stderr.
+//
+//	go run ./cmd/metamorph seed exercises
+//	go run ./cmd/metamorph migrate focus-area --dry-run=false
+//	go run ./cmd/metamorph recalc volumes --member <id> --output json
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mansoorceksport/metamorph/internal/config"
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// globals holds the flags and configuration shared by every subcommand
+type globals struct {
+	mongoURI string
+	database string
+	dryRun   bool
+	output   string
+
+	cfg *config.Config
+}
+
+func main() {
+	// Summaries go to stdout; keep progress out of the way of --output json
+	log.SetOutput(os.Stderr)
+	log.SetFlags(0)
+
+	if err := newRootCommand().Execute(); err != nil {
+		log.Printf("Error: %v", err)
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	// Lets migrate flip the --dry-run default without replacing the root's config loading
+	cobra.EnableTraverseRunHooks = true
+
+	g := &globals{}
+	root := &cobra.Command{
+		Use:           "metamorph",
+		Short:         "Metamorph operations CLI",
+		SilenceUsage:  true,
+		SilenceErrors: true, // main logs the error to stderr
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if g.output != "text" && g.output != "json" {
+				return fmt.Errorf("--output must be text or json")
+			}
+			g.cfg = config.FromEnv()
+			if g.mongoURI != "" {
+				g.cfg.MongoDB.URI = g.mongoURI
+			}
+			if g.database != "" {
+				g.cfg.MongoDB.Database = g.database
+			}
+			return nil
+		},
+	}
+	flags := root.PersistentFlags()
+	flags.StringVar(&g.mongoURI, "mongo", "", "MongoDB URI (default: MONGODB_URI)")
+	flags.StringVar(&g.database, "db", "", "Database name (default: MONGODB_DATABASE)")
+	flags.BoolVar(&g.dryRun, "dry-run", false, "Preview changes without writing")
+	flags.StringVarP(&g.output, "output", "o", "text", "Summary format: text or json")
+
+	root.AddCommand(
+		newSeedCommand(g),
+		newMigrateCommand(g),
+		newRecalcCommand(g),
+		newArchiveCommand(g),
+		newExportCommand(g),
+	)
+	return root
+}
+
+// connect opens the configured database. The returned func disconnects the client.
+func (g *globals) connect(ctx context.Context) (*mongo.Database, func(), error) {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(g.cfg.MongoDB.URI))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+	return client.Database(g.cfg.MongoDB.Database), func() { client.Disconnect(context.Background()) }, nil
+}
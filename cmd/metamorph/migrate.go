@@ -0,0 +1,426 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// newMigrateCommand groups one-off data migrations. They default to --dry-run so a bare
+// invocation only previews; pass --dry-run=false to write.
+func newMigrateCommand(g *globals) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Backfill data written before a feature existed (dry run unless --dry-run=false)",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("dry-run") {
+				g.dryRun = true
+			}
+			return nil
+		},
+	}
+	cmd.AddCommand(
+		newMigrateFocusAreaCommand(g),
+		newMigrateCreditLedgerCommand(g),
+		newMigrateMoneyCommand(g),
+	)
+	return cmd
+}
+
+// Pattern matching rules for inferring focus area from session_goal
+var focusPatterns = map[string]*regexp.Regexp{
+	"LEG_DAY":    regexp.MustCompile(`(?i)(leg|squat|lunge|calf|quad|hamstring|glute)`),
+	"UPPER_BODY": regexp.MustCompile(`(?i)(upper|shoulder|arm|bicep|tricep)`),
+	"BACK_DAY":   regexp.MustCompile(`(?i)(back|lat|row|pull|deadlift)`),
+	"CHEST_DAY":  regexp.MustCompile(`(?i)(chest|bench|push.?up|pec)`),
+	"FULL_BODY":  regexp.MustCompile(`(?i)(full.?body|total.?body|circuit)`),
+	"FUNCTIONAL": regexp.MustCompile(`(?i)(functional|cardio|hiit|conditioning|endurance)`),
+	"CORE":       regexp.MustCompile(`(?i)(core|abs|plank|crunch)`),
+}
+
+func inferFocusArea(sessionGoal string) string {
+	if sessionGoal == "" {
+		return ""
+	}
+
+	for focus, pattern := range focusPatterns {
+		if pattern.MatchString(sessionGoal) {
+			return focus
+		}
+	}
+	return "" // No match, leave empty
+}
+
+func newMigrateFocusAreaCommand(g *globals) *cobra.Command {
+	return &cobra.Command{
+		Use:   "focus-area",
+		Short: "Infer schedule and daily volume focus areas from session goals",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Minute)
+			defer cancel()
+
+			db, disconnect, err := g.connect(ctx)
+			if err != nil {
+				return err
+			}
+			defer disconnect()
+
+			schedulesCol := db.Collection("schedules")
+			volumesCol := db.Collection("daily_volumes")
+
+			// --- Step 1: Migrate Schedules ---
+			// Find schedules with session_goal but no focus_area
+			filter := bson.M{
+				"session_goal": bson.M{"$exists": true, "$ne": ""},
+				"$or": []bson.M{
+					{"focus_area": bson.M{"$exists": false}},
+					{"focus_area": ""},
+				},
+			}
+
+			cursor, err := schedulesCol.Find(ctx, filter)
+			if err != nil {
+				return fmt.Errorf("failed to query schedules: %w", err)
+			}
+			defer cursor.Close(ctx)
+
+			var scheduleUpdates, scheduleMatched, scheduleSkipped int
+			for cursor.Next(ctx) {
+				scheduleMatched++
+				var doc bson.M
+				if err := cursor.Decode(&doc); err != nil {
+					continue
+				}
+
+				sessionGoal, _ := doc["session_goal"].(string)
+				inferredFocus := inferFocusArea(sessionGoal)
+
+				if inferredFocus == "" {
+					scheduleSkipped++
+					continue
+				}
+
+				scheduleID := doc["_id"]
+				log.Printf("  Schedule %v: \"%s\" -> %s", scheduleID, truncate(sessionGoal, 40), inferredFocus)
+
+				if !g.dryRun {
+					_, err := schedulesCol.UpdateByID(ctx, scheduleID, bson.M{
+						"$set": bson.M{"focus_area": inferredFocus},
+					})
+					if err != nil {
+						log.Printf("  ERROR updating schedule %v: %v", scheduleID, err)
+						continue
+					}
+				}
+				scheduleUpdates++
+			}
+
+			// --- Step 2: Migrate Daily Volumes ---
+			// For each schedule that has focus_area, update the corresponding volume
+			scheduleCursor, err := schedulesCol.Find(ctx, bson.M{
+				"focus_area": bson.M{"$exists": true, "$ne": ""},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to query schedules with focus_area: %w", err)
+			}
+			defer scheduleCursor.Close(ctx)
+
+			var volumeUpdates int
+			for scheduleCursor.Next(ctx) {
+				var doc bson.M
+				if err := scheduleCursor.Decode(&doc); err != nil {
+					continue
+				}
+
+				scheduleID := doc["_id"]
+				focusArea, _ := doc["focus_area"].(string)
+
+				// Find and update the corresponding volume
+				volumeFilter := bson.M{
+					"schedule_id": scheduleID,
+					"$or": []bson.M{
+						{"focus_area": bson.M{"$exists": false}},
+						{"focus_area": ""},
+					},
+				}
+
+				if g.dryRun {
+					count, _ := volumesCol.CountDocuments(ctx, volumeFilter)
+					if count > 0 {
+						log.Printf("  Volume for schedule %v -> %s", scheduleID, focusArea)
+						volumeUpdates += int(count)
+					}
+					continue
+				}
+
+				result, err := volumesCol.UpdateMany(ctx, volumeFilter, bson.M{
+					"$set": bson.M{"focus_area": focusArea},
+				})
+				if err != nil {
+					log.Printf("  ERROR updating volume for schedule %v: %v", scheduleID, err)
+					continue
+				}
+				volumeUpdates += int(result.ModifiedCount)
+			}
+
+			return g.print(cmd, newSummary("migrate focus-area", g.dryRun).
+				Set("schedules_matched", scheduleMatched).
+				Set("schedules_updated", scheduleUpdates).
+				Set("schedules_skipped", scheduleSkipped).
+				Set("volumes_updated", volumeUpdates))
+		},
+	}
+}
+
+// newMigrateCreditLedgerCommand backfills the credit ledger for contracts created before it
+// existed. For each contract without ledger entries it replays: the purchase, one consumption
+// per completed schedule, and a reconciling adjustment if the result differs from the legacy
+// remaining_sessions counter (so balances never change as a side effect of migrating).
+func newMigrateCreditLedgerCommand(g *globals) *cobra.Command {
+	return &cobra.Command{
+		Use:   "credit-ledger",
+		Short: "Backfill credit ledger entries for contracts that predate the ledger",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Minute)
+			defer cancel()
+
+			db, disconnect, err := g.connect(ctx)
+			if err != nil {
+				return err
+			}
+			defer disconnect()
+
+			contractsCol := db.Collection("pt_contracts")
+			schedulesCol := db.Collection("schedules")
+			creditsCol := db.Collection("credit_transactions")
+
+			cursor, err := contractsCol.Find(ctx, bson.M{})
+			if err != nil {
+				return fmt.Errorf("failed to query contracts: %w", err)
+			}
+			defer cursor.Close(ctx)
+
+			var migrated, skipped, reconciled, entries int
+			for cursor.Next(ctx) {
+				var contract domain.PTContract
+				if err := cursor.Decode(&contract); err != nil {
+					continue
+				}
+
+				existing, err := creditsCol.CountDocuments(ctx, bson.M{"contract_id": contract.ID})
+				if err != nil {
+					log.Printf("  ERROR checking ledger for contract %s: %v", contract.ID, err)
+					continue
+				}
+				if existing > 0 {
+					skipped++
+					continue
+				}
+
+				txns, err := replayContract(ctx, schedulesCol, &contract)
+				if err != nil {
+					log.Printf("  ERROR replaying contract %s: %v", contract.ID, err)
+					continue
+				}
+
+				last := txns[len(txns)-1]
+				if last.Type == domain.CreditTypeAdjusted {
+					reconciled++
+				}
+				log.Printf("  Contract %s: %d entries, balance %d", contract.ID, len(txns), last.BalanceAfter)
+
+				if !g.dryRun {
+					docs := make([]interface{}, len(txns))
+					for i, t := range txns {
+						docs[i] = t
+					}
+					if _, err := creditsCol.InsertMany(ctx, docs); err != nil {
+						log.Printf("  ERROR writing ledger for contract %s: %v", contract.ID, err)
+						continue
+					}
+					oid, _ := primitive.ObjectIDFromHex(contract.ID)
+					_, err := contractsCol.UpdateByID(ctx, oid, bson.M{
+						"$set": bson.M{"ledger_sequence": last.Sequence},
+					})
+					if err != nil {
+						log.Printf("  ERROR updating contract %s: %v", contract.ID, err)
+						continue
+					}
+				}
+				migrated++
+				entries += len(txns)
+			}
+
+			return g.print(cmd, newSummary("migrate credit-ledger", g.dryRun).
+				Set("contracts_migrated", migrated).
+				Set("ledger_entries", entries).
+				Set("contracts_reconciled", reconciled).
+				Set("contracts_skipped", skipped))
+		},
+	}
+}
+
+// moneyFields are the amounts stored as bare numbers before they carried a currency
+var moneyFields = []struct{ collection, field string }{
+	{"packages", "price"},
+	{"pt_packages", "price"},
+	{"pt_contracts", "price"},
+	{"invoices", "amount"},
+}
+
+func newMigrateMoneyCommand(g *globals) *cobra.Command {
+	return &cobra.Command{
+		Use:   "money",
+		Short: "Rewrite numeric prices and invoice amounts as {amount, currency} (run before credit-ledger)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Minute)
+			defer cancel()
+
+			db, disconnect, err := g.connect(ctx)
+			if err != nil {
+				return err
+			}
+			defer disconnect()
+
+			summary := newSummary("migrate money", g.dryRun)
+			var total, rounded int
+			for _, f := range moneyFields {
+				col := db.Collection(f.collection)
+				cursor, err := col.Find(ctx, bson.M{f.field: bson.M{"$type": "number"}},
+					options.Find().SetProjection(bson.M{f.field: 1}))
+				if err != nil {
+					return fmt.Errorf("failed to query %s: %w", f.collection, err)
+				}
+
+				var migrated int
+				for cursor.Next(ctx) {
+					var doc bson.M
+					if err := cursor.Decode(&doc); err != nil {
+						continue
+					}
+					var major float64
+					switch v := doc[f.field].(type) {
+					case float64:
+						major = v
+					case int32:
+						major = float64(v)
+					case int64:
+						major = float64(v)
+					default:
+						continue // Decimal128 was never written
+					}
+					money := domain.MoneyFromMajor(major, domain.DefaultCurrency)
+					if float64(money.Minor) != major {
+						rounded++
+						log.Printf("  %s %v: %s %v rounded to %s", f.collection, doc["_id"], f.field, major, money)
+					}
+
+					if !g.dryRun {
+						_, err := col.UpdateByID(ctx, doc["_id"], bson.M{"$set": bson.M{f.field: money}})
+						if err != nil {
+							log.Printf("  ERROR updating %s %v: %v", f.collection, doc["_id"], err)
+							continue
+						}
+					}
+					migrated++
+				}
+				err = cursor.Err()
+				cursor.Close(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to read %s: %w", f.collection, err)
+				}
+				log.Printf("%s.%s: %d documents", f.collection, f.field, migrated)
+				summary.Set(f.collection, migrated)
+				total += migrated
+			}
+
+			return g.print(cmd, summary.
+				Set("documents_migrated", total).
+				Set("amounts_rounded", rounded))
+		},
+	}
+}
+
+// replayContract rebuilds a contract's ledger from its completed sessions
+func replayContract(ctx context.Context, schedulesCol *mongo.Collection, contract *domain.PTContract) ([]*domain.CreditTransaction, error) {
+	var txns []*domain.CreditTransaction
+	var balance int
+	appendTxn := func(txn *domain.CreditTransaction) {
+		balance += txn.Amount
+		txn.TenantID = contract.TenantID
+		txn.ContractID = contract.ID
+		txn.MemberID = contract.MemberID
+		txn.Sequence = int64(len(txns) + 1)
+		txn.BalanceAfter = balance
+		txns = append(txns, txn)
+	}
+
+	appendTxn(&domain.CreditTransaction{
+		Type:           domain.CreditTypePurchased,
+		Amount:         contract.TotalSessions,
+		Note:           "Package purchased",
+		IdempotencyKey: "purchased:" + contract.ID,
+		CreatedAt:      contract.CreatedAt,
+	})
+
+	opts := options.Find().SetSort(bson.D{{Key: "start_time", Value: 1}})
+	cursor, err := schedulesCol.Find(ctx, bson.M{
+		"contract_id": contract.ID,
+		"status":      domain.ScheduleStatusCompleted,
+		"deleted_at":  bson.M{"$exists": false},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var schedule domain.Schedule
+		if err := cursor.Decode(&schedule); err != nil {
+			continue
+		}
+		// Legacy decrements stopped at zero, so extra completions did not consume anything
+		if balance <= 0 {
+			break
+		}
+		appendTxn(&domain.CreditTransaction{
+			Type:           domain.CreditTypeConsumed,
+			Amount:         -1,
+			ScheduleID:     schedule.ID,
+			ActorID:        schedule.CoachID,
+			IdempotencyKey: "consumed:" + schedule.ID,
+			CreatedAt:      schedule.UpdatedAt,
+		})
+	}
+
+	if diff := contract.RemainingSessions - balance; diff != 0 {
+		appendTxn(&domain.CreditTransaction{
+			Type:      domain.CreditTypeAdjusted,
+			Amount:    diff,
+			Note:      "Reconciled with remaining sessions during ledger migration",
+			CreatedAt: contract.UpdatedAt,
+		})
+	}
+	return txns, nil
+}
+
+func truncate(s string, maxLen int) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if len(s) > maxLen {
+		return s[:maxLen-3] + "..."
+	}
+	return s
+}
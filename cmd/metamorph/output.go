@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// summary is the result every subcommand ends with. Fields keep their insertion order in both
+// the text and the JSON form.
+type summary struct {
+	command string
+	dryRun  bool
+	keys    []string
+	values  map[string]interface{}
+}
+
+func newSummary(command string, dryRun bool) *summary {
+	return &summary{command: command, dryRun: dryRun, values: map[string]interface{}{}}
+}
+
+// Set records a result field, overwriting an earlier value with the same key
+func (s *summary) Set(key string, value interface{}) *summary {
+	if _, ok := s.values[key]; !ok {
+		s.keys = append(s.keys, key)
+	}
+	s.values[key] = value
+	return s
+}
+
+// Write prints the summary in the requested format ("text" or "json")
+func (s *summary) Write(w io.Writer, format string) error {
+	if format == "json" {
+		return json.NewEncoder(w).Encode(s)
+	}
+
+	fmt.Fprintf(w, "=== %s ===\n", s.command)
+	for _, k := range s.keys {
+		fmt.Fprintf(w, "%s: %+v\n", k, s.values[k])
+	}
+	if s.dryRun {
+		fmt.Fprintln(w, "\n⚠️  This was a DRY RUN. No data was modified.")
+		fmt.Fprintln(w, "Run with --dry-run=false to apply changes.")
+	}
+	return nil
+}
+
+// MarshalJSON renders {"command": ..., "dry_run": ..., "result": {...}} with ordered result keys
+func (s *summary) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(`{"command":`)
+	command, _ := json.Marshal(s.command)
+	buf.Write(command)
+	fmt.Fprintf(&buf, `,"dry_run":%t,"result":{`, s.dryRun)
+	for i, k := range s.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		value, err := json.Marshal(s.values[k])
+		if err != nil {
+			return nil, fmt.Errorf("summary field %s: %w", k, err)
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteString("}}")
+	return buf.Bytes(), nil
+}
+
+// print writes a subcommand's summary to its stdout in the format chosen with --output
+func (g *globals) print(cmd *cobra.Command, s *summary) error {
+	return s.Write(cmd.OutOrStdout(), g.output)
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/repository"
+	"github.com/mansoorceksport/metamorph/internal/service"
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newRecalcCommand(g *globals) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recalc",
+		Short: "Recompute derived workout data",
+	}
+	cmd.AddCommand(
+		newRecalcVolumesCommand(g),
+		newRecalcWorkoutEventsCommand(g),
+	)
+	return cmd
+}
+
+// Simplified documents read and written by recalc volumes
+type volumeSchedule struct {
+	ID        string    `bson:"_id"`
+	MemberID  string    `bson:"member_id"`
+	TenantID  string    `bson:"tenant_id"`
+	Status    string    `bson:"status"`
+	StartTime time.Time `bson:"start_time"`
+}
+
+type volumeSetLog struct {
+	ID         string  `bson:"_id"`
+	ScheduleID string  `bson:"schedule_id"`
+	MemberID   string  `bson:"member_id"`
+	ExerciseID string  `bson:"exercise_id"`
+	Weight     float64 `bson:"weight"`
+	Reps       int     `bson:"reps"`
+	Completed  bool    `bson:"completed"`
+}
+
+type volumeRecord struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID      string             `bson:"tenant_id"`
+	MemberID      string             `bson:"member_id"`
+	ScheduleID    string             `bson:"schedule_id"`
+	Date          time.Time          `bson:"date"`
+	TotalVolume   float64            `bson:"total_volume"`
+	TotalSets     int                `bson:"total_sets"`
+	TotalReps     int                `bson:"total_reps"`
+	TotalWeight   float64            `bson:"total_weight"`
+	ExerciseCount int                `bson:"exercise_count"`
+	CreatedAt     time.Time          `bson:"created_at"`
+}
+
+// newRecalcVolumesCommand regenerates a member's daily_volumes from the set logs of their
+// completed schedules. Existing volume records of the member are deleted first.
+func newRecalcVolumesCommand(g *globals) *cobra.Command {
+	var memberID string
+
+	cmd := &cobra.Command{
+		Use:   "volumes",
+		Short: "Rebuild a member's daily volume aggregations from their set logs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
+			defer cancel()
+
+			db, disconnect, err := g.connect(ctx)
+			if err != nil {
+				return err
+			}
+			defer disconnect()
+
+			schedulesCol := db.Collection("schedules")
+			setLogsCol := db.Collection("set_logs")
+			volumesCol := db.Collection("daily_volumes")
+
+			cursor, err := schedulesCol.Find(ctx, bson.M{
+				"member_id": memberID,
+				"status":    "Completed", // Match domain.ScheduleStatusCompleted
+			})
+			if err != nil {
+				return fmt.Errorf("failed to query schedules: %w", err)
+			}
+			var schedules []volumeSchedule
+			if err := cursor.All(ctx, &schedules); err != nil {
+				return fmt.Errorf("failed to decode schedules: %w", err)
+			}
+			log.Printf("Found %d completed schedules for member %s", len(schedules), memberID)
+
+			out := newSummary("recalc volumes", g.dryRun).
+				Set("member_id", memberID).
+				Set("schedules_processed", len(schedules))
+			if len(schedules) == 0 {
+				return g.print(cmd, out)
+			}
+
+			var deleted int64
+			if !g.dryRun {
+				result, err := volumesCol.DeleteMany(ctx, bson.M{"member_id": memberID})
+				if err != nil {
+					return fmt.Errorf("failed to delete existing volumes: %w", err)
+				}
+				deleted = result.DeletedCount
+			} else {
+				deleted, _ = volumesCol.CountDocuments(ctx, bson.M{"member_id": memberID})
+			}
+
+			var totalVolumesCreated int
+			var grandTotalVolume float64
+			for _, schedule := range schedules {
+				setCursor, err := setLogsCol.Find(ctx, bson.M{"schedule_id": schedule.ID})
+				if err != nil {
+					log.Printf("  Schedule %s: failed to fetch set logs: %v", schedule.ID, err)
+					continue
+				}
+				var setLogs []volumeSetLog
+				if err := setCursor.All(ctx, &setLogs); err != nil {
+					log.Printf("  Schedule %s: failed to decode set logs: %v", schedule.ID, err)
+					continue
+				}
+
+				// Calculate aggregates
+				var totalVolume, totalWeight float64
+				var totalReps, totalSets int
+				exerciseIDs := make(map[string]bool)
+				for _, set := range setLogs {
+					if set.Completed && set.Weight > 0 && set.Reps > 0 {
+						totalVolume += set.Weight * float64(set.Reps)
+						totalWeight += set.Weight
+						totalReps += set.Reps
+						totalSets++
+						exerciseIDs[set.ExerciseID] = true
+					}
+				}
+
+				log.Printf("  Schedule %s (%s): %d sets, %d reps, %.0f kg",
+					schedule.ID, schedule.StartTime.Format(time.DateOnly), totalSets, totalReps, totalVolume)
+				if totalSets == 0 {
+					continue
+				}
+
+				if !g.dryRun {
+					_, err := volumesCol.InsertOne(ctx, volumeRecord{
+						TenantID:      schedule.TenantID,
+						MemberID:      schedule.MemberID,
+						ScheduleID:    schedule.ID,
+						Date:          schedule.StartTime,
+						TotalVolume:   totalVolume,
+						TotalSets:     totalSets,
+						TotalReps:     totalReps,
+						TotalWeight:   totalWeight,
+						ExerciseCount: len(exerciseIDs),
+						CreatedAt:     time.Now(),
+					})
+					if err != nil {
+						log.Printf("  Schedule %s: failed to insert volume: %v", schedule.ID, err)
+						continue
+					}
+				}
+				totalVolumesCreated++
+				grandTotalVolume += totalVolume
+			}
+
+			return g.print(cmd, out.
+				Set("volumes_deleted", deleted).
+				Set("volumes_created", totalVolumesCreated).
+				Set("total_volume_kg", grandTotalVolume))
+		},
+	}
+	cmd.Flags().StringVar(&memberID, "member", "", "Member ID to recalculate volumes for (required)")
+	cmd.MarkFlagRequired("member")
+	return cmd
+}
+
+// newRecalcWorkoutEventsCommand recomputes derived workout data (daily volumes, personal bests)
+// by replaying the workout event log
+func newRecalcWorkoutEventsCommand(g *globals) *cobra.Command {
+	var scheduleID string
+
+	cmd := &cobra.Command{
+		Use:   "workout-events",
+		Short: "Replay the workout event log to rebuild daily volumes and personal bests",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if g.dryRun {
+				return fmt.Errorf("recalc workout-events does not support --dry-run")
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Minute)
+			defer cancel()
+
+			db, disconnect, err := g.connect(ctx)
+			if err != nil {
+				return err
+			}
+			defer disconnect()
+
+			setLogRepo := repository.NewMongoSetLogRepository(db)
+			pbRepo := repository.NewMongoPersonalBestRepository(db)
+
+			// Consumers are subscribed without passing the log to the workout service,
+			// so replaying never appends new events
+			events := service.NewWorkoutEventLog(repository.NewMongoWorkoutEventRepository(db), clock.Real{})
+			workoutService := service.NewWorkoutService(
+				repository.NewMongoExerciseRepository(db),
+				repository.NewMongoTemplateRepository(db),
+				repository.NewMongoWorkoutSessionRepository(db),
+				repository.NewMongoScheduleRepository(db),
+				setLogRepo,
+				pbRepo,
+				repository.NewMongoDailyVolumeRepository(db),
+				nil,
+				clock.Real{},
+			)
+			events.Subscribe("volume aggregator", workoutService)
+			events.Subscribe("PB detector", service.NewPersonalBestDetector(setLogRepo, pbRepo,
+				repository.NewMongoScheduleRepository(db), repository.NewMongoExerciseRepository(db), repository.NewMongoTenantRepository(db)))
+
+			if scheduleID != "" {
+				n, err := events.Rebuild(ctx, scheduleID)
+				if err != nil {
+					return fmt.Errorf("failed to rebuild schedule %s: %w", scheduleID, err)
+				}
+				return g.print(cmd, newSummary("recalc workout-events", false).
+					Set("schedule_id", scheduleID).
+					Set("events_replayed", n))
+			}
+
+			n, err := events.RebuildAll(ctx)
+			if err != nil {
+				return fmt.Errorf("rebuild stopped after %d schedules: %w", n, err)
+			}
+			return g.print(cmd, newSummary("recalc workout-events", false).
+				Set("schedules_rebuilt", n))
+		},
+	}
+	cmd.Flags().StringVar(&scheduleID, "schedule", "", "Only replay this schedule's events")
+	return cmd
+}
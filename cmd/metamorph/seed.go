@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/repository"
+	"github.com/mansoorceksport/metamorph/internal/service"
+	"github.com/spf13/cobra"
+)
+
+func newSeedCommand(g *globals) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Seed reference, demo and load-test data",
+	}
+	cmd.AddCommand(
+		newSeedExercisesCommand(g),
+		newSeedTemplatesCommand(g),
+		newSeedCatalogCommand(g),
+		newSeedDemoCommand(g),
+		newSeedLoadCommand(g),
+	)
+	return cmd
+}
+
+// defaultCatalog is the global exercise library and workout templates
+//
+//go:embed catalogs/default.yaml
+var defaultCatalog []byte
+
+// catalogSection keeps only one section of a catalog, for seed exercises and seed templates
+type catalogSection func(c *domain.SeedCatalog) *domain.SeedCatalog
+
+func newSeedExercisesCommand(g *globals) *cobra.Command {
+	return newSeedCatalogSectionCommand(g, "exercises", "Upsert the global exercise library",
+		func(c *domain.SeedCatalog) *domain.SeedCatalog {
+			return &domain.SeedCatalog{Exercises: c.Exercises}
+		})
+}
+
+func newSeedTemplatesCommand(g *globals) *cobra.Command {
+	return newSeedCatalogSectionCommand(g, "templates", "Upsert the global workout templates (run seed exercises first)",
+		func(c *domain.SeedCatalog) *domain.SeedCatalog {
+			return &domain.SeedCatalog{Templates: c.Templates}
+		})
+}
+
+func newSeedCatalogSectionCommand(g *globals, use, short string, section catalogSection) *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			catalog, err := readCatalog(file)
+			if err != nil {
+				return err
+			}
+			return g.applyCatalog(cmd, "seed "+use, section(catalog), "")
+		},
+	}
+	cmd.Flags().StringVar(&file, "file", "", "YAML or JSON catalog to read instead of the built-in one")
+	return cmd
+}
+
+// newSeedCatalogCommand applies every section of a catalog file. With --tenant it is how a
+// tenant's own packages and branch equipment are loaded during onboarding.
+func newSeedCatalogCommand(g *globals) *cobra.Command {
+	var file, tenantID string
+
+	cmd := &cobra.Command{
+		Use:   "catalog",
+		Short: "Upsert exercises, templates and, for a tenant, packages and equipment from a catalog file",
+		Long: `Upsert the entries of a YAML (.yaml, .yml) or JSON (.json) catalog by name.
+Missing entries are created, changed ones are updated and nothing is deleted, so a catalog can
+be edited and applied again. The file is validated before anything is written.
+
+  exercises:                 # global library
+    - name: Goblet Squat
+      muscle_group: Legs
+      equipment: Dumbbell
+      video_url: https://...   # optional, as is reference_url
+  templates:                 # global; exercises by name, from this file or the library
+    - name: Full Body
+      gender: All              # Male, Female or All (default)
+      exercises: [Goblet Squat, Push Up]
+  packages:                  # needs --tenant
+    - branch: Downtown         # optional when the tenant has one branch
+      name: 10 Session Pack
+      total_sessions: 10       # 10, 20, 30, 40 or 50
+      price: 1500000
+      active: true             # default
+  equipment:                 # needs --tenant
+    - branch: Downtown
+      name: Barbell
+      quantity: 4
+      available: true          # default`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			catalog, err := readCatalog(file)
+			if err != nil {
+				return err
+			}
+			return g.applyCatalog(cmd, "seed catalog", catalog, tenantID)
+		},
+	}
+	cmd.Flags().StringVar(&file, "file", "", "YAML or JSON catalog file (required)")
+	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant that owns the catalog's packages and equipment")
+	cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+// readCatalog parses a catalog file, or the built-in catalog when file is empty
+func readCatalog(file string) (*domain.SeedCatalog, error) {
+	if file == "" {
+		return service.ParseSeedCatalog("default.yaml", defaultCatalog)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+	return service.ParseSeedCatalog(file, data)
+}
+
+func (g *globals) applyCatalog(cmd *cobra.Command, command string, catalog *domain.SeedCatalog, tenantID string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
+	defer cancel()
+
+	db, disconnect, err := g.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer disconnect()
+
+	if tenantID != "" {
+		if _, err := repository.NewMongoTenantRepository(db).GetByID(ctx, tenantID); err != nil {
+			return fmt.Errorf("tenant %s: %w", tenantID, err)
+		}
+	}
+
+	seeder := service.NewSeedCatalogService(
+		repository.NewMongoExerciseRepository(db),
+		repository.NewMongoTemplateRepository(db),
+		repository.NewMongoPTPackageRepository(db),
+		repository.NewMongoEquipmentRepository(db),
+		repository.NewMongoBranchRepository(db),
+	)
+	result, err := seeder.Apply(ctx, catalog, tenantID, g.dryRun)
+	if err != nil {
+		return err
+	}
+	for _, issue := range result.Issues {
+		log.Printf("Warning: %s", issue)
+	}
+
+	out := newSummary(command, g.dryRun)
+	if tenantID != "" {
+		out.Set("tenant_id", tenantID)
+	}
+	if len(catalog.Exercises) > 0 {
+		out.Set("exercises", result.Exercises)
+	}
+	if len(catalog.Templates) > 0 {
+		out.Set("templates", result.Templates)
+	}
+	if len(catalog.Packages) > 0 {
+		out.Set("packages", result.Packages)
+	}
+	if len(catalog.Equipment) > 0 {
+		out.Set("equipment", result.Equipment)
+	}
+	return g.print(cmd, out.Set("issues", len(result.Issues)))
+}
+
+// newSeedDemoCommand is the CLI equivalent of POST /v1/platform/tenants/:id/seed-demo
+func newSeedDemoCommand(g *globals) *cobra.Command {
+	var tenantID string
+	var deleteDemo bool
+
+	cmd := &cobra.Command{
+		Use:   "demo",
+		Short: "Seed (or remove) sales-demo data for a tenant",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if g.dryRun {
+				return fmt.Errorf("seed demo does not support --dry-run")
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
+			defer cancel()
+
+			db, disconnect, err := g.connect(ctx)
+			if err != nil {
+				return err
+			}
+			defer disconnect()
+
+			demoService := service.NewDemoService(
+				repository.NewMongoDemoDataRepository(db),
+				repository.NewMongoTenantRepository(db),
+				repository.NewMongoBranchRepository(db),
+				repository.NewMongoExerciseRepository(db),
+				clock.Real{},
+			)
+
+			if deleteDemo {
+				summary, err := demoService.DeleteDemo(ctx, tenantID)
+				if err != nil {
+					return fmt.Errorf("failed to delete demo data: %w", err)
+				}
+				return g.print(cmd, newSummary("seed demo --delete", false).
+					Set("tenant_id", tenantID).
+					Set("deleted", summary.Deleted))
+			}
+
+			summary, err := demoService.SeedDemo(ctx, tenantID)
+			if err != nil {
+				return fmt.Errorf("failed to seed demo data: %w", err)
+			}
+			return g.print(cmd, newSummary("seed demo", false).
+				Set("tenant_id", tenantID).
+				Set("coaches", summary.Coaches).
+				Set("members", summary.Members).
+				Set("contracts", summary.Contracts).
+				Set("schedules", summary.Schedules).
+				Set("set_logs", summary.SetLogs).
+				Set("scans", summary.Scans).
+				Set("invoices", summary.Invoices))
+		},
+	}
+	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant ID to seed demo data into (required)")
+	cmd.Flags().BoolVar(&deleteDemo, "delete", false, "Delete the tenant's demo data instead of seeding it")
+	cmd.MarkFlagRequired("tenant")
+	return cmd
+}
@@ -0,0 +1,656 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/oklog/ulid/v2"
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// seed load generates synthetic tenants with a realistic amount of history for load testing
+// the dashboard and history endpoints. Each member gets back-to-back PT contracts, ~N sessions
+// a week with planned exercises, set logs, daily volumes and credit ledger entries, plus
+// periodic InBody scans. Example (100 tenants × 500 members × 1 year):
+//
+//	go run ./cmd/metamorph seed load --tenants 100 --members 500 --days 365
+//
+// Data goes to a dedicated database (default "homgym_load" unless --db is given); use --drop to
+// start clean. Run `seed exercises --db homgym_load` first so sessions reference real exercises.
+
+// loadTestDatabase keeps load-test data out of the configured database by default
+const loadTestDatabase = "homgym_load"
+
+type settings struct {
+	tenants          int
+	coachesPerTenant int
+	membersPerTenant int
+	days             int
+	sessionsPerWeek  int
+	exercisesPerDay  int
+	setsPerExercise  int
+	scanEveryDays    int
+	batchSize        int
+}
+
+type stats struct {
+	users, contracts, schedules, plans, setLogs, volumes, credits, scans atomic.Int64
+}
+
+type exerciseRef struct {
+	ID   string
+	Name string
+}
+
+var focusAreas = []string{
+	domain.FocusAreaLegDay,
+	domain.FocusAreaUpperBody,
+	domain.FocusAreaBackDay,
+	domain.FocusAreaChestDay,
+	domain.FocusAreaFullBody,
+	domain.FocusAreaCore,
+}
+
+func newSeedLoadCommand(g *globals) *cobra.Command {
+	var cfg settings
+	var drop bool
+	var workers int
+	var seed int64
+
+	cmd := &cobra.Command{
+		Use:   "load",
+		Short: "Generate synthetic tenants with a year of history for load testing",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("db") {
+				g.cfg.MongoDB.Database = loadTestDatabase
+			}
+
+			out := newSummary("seed load", g.dryRun).
+				Set("database", g.cfg.MongoDB.Database).
+				Set("tenants", cfg.tenants).
+				Set("coaches_per_tenant", cfg.coachesPerTenant).
+				Set("members_per_tenant", cfg.membersPerTenant).
+				Set("days", cfg.days).
+				Set("sessions_per_week", cfg.sessionsPerWeek)
+			if g.dryRun {
+				weeks := cfg.days / 7
+				out.Set("estimated_schedules", cfg.tenants*cfg.membersPerTenant*weeks*cfg.sessionsPerWeek)
+				return g.print(cmd, out)
+			}
+
+			ctx := cmd.Context()
+			db, disconnect, err := g.connect(ctx)
+			if err != nil {
+				return err
+			}
+			defer disconnect()
+
+			if drop {
+				if err := db.Drop(ctx); err != nil {
+					return fmt.Errorf("failed to drop database: %w", err)
+				}
+			}
+
+			exercises, err := loadExercises(ctx, db)
+			if err != nil {
+				return fmt.Errorf("failed to load exercises: %w", err)
+			}
+			if len(exercises) == 0 {
+				return fmt.Errorf("no exercises found; run `seed exercises` against this database first")
+			}
+
+			log.Printf("Seeding %d tenants × %d coaches × %d members, %d days, %d sessions/week into %s",
+				cfg.tenants, cfg.coachesPerTenant, cfg.membersPerTenant, cfg.days, cfg.sessionsPerWeek, g.cfg.MongoDB.Database)
+
+			// Unique per run so repeated runs don't collide on join codes and emails
+			runID := strconv.FormatInt(time.Now().Unix(), 36)
+			started := time.Now()
+			st := &stats{}
+
+			tenantCh := make(chan int)
+			var wg sync.WaitGroup
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for t := range tenantCh {
+						gen := &generator{
+							db:        db,
+							cfg:       cfg,
+							stats:     st,
+							exercises: exercises,
+							rnd:       rand.New(rand.NewSource(seed + int64(t))),
+							runID:     runID,
+							now:       started,
+						}
+						if err := gen.tenant(ctx, t); err != nil {
+							log.Printf("  ERROR seeding tenant %d: %v", t, err)
+							continue
+						}
+						log.Printf("  Tenant %d/%d done (%s)", t+1, cfg.tenants, time.Since(started).Round(time.Second))
+					}
+				}()
+			}
+			for t := 0; t < cfg.tenants; t++ {
+				tenantCh <- t
+			}
+			close(tenantCh)
+			wg.Wait()
+
+			return g.print(cmd, out.
+				Set("users", st.users.Load()).
+				Set("contracts", st.contracts.Load()).
+				Set("credit_entries", st.credits.Load()).
+				Set("schedules", st.schedules.Load()).
+				Set("planned_exercises", st.plans.Load()).
+				Set("set_logs", st.setLogs.Load()).
+				Set("daily_volumes", st.volumes.Load()).
+				Set("scans", st.scans.Load()).
+				Set("elapsed", time.Since(started).Round(time.Second).String()))
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&drop, "drop", false, "Drop the database before seeding")
+	flags.IntVar(&workers, "workers", 4, "Tenants generated in parallel")
+	flags.Int64Var(&seed, "seed", 1, "Random seed (same seed, same data shape)")
+	flags.IntVar(&cfg.tenants, "tenants", 10, "Number of tenants")
+	flags.IntVar(&cfg.coachesPerTenant, "coaches", 10, "Coaches per tenant")
+	flags.IntVar(&cfg.membersPerTenant, "members", 100, "Members per tenant")
+	flags.IntVar(&cfg.days, "days", 365, "Days of history")
+	flags.IntVar(&cfg.sessionsPerWeek, "sessions-per-week", 2, "PT sessions per member per week")
+	flags.IntVar(&cfg.exercisesPerDay, "exercises", 4, "Exercises per session")
+	flags.IntVar(&cfg.setsPerExercise, "sets", 3, "Sets per exercise")
+	flags.IntVar(&cfg.scanEveryDays, "scan-every", 30, "Days between InBody scans per member (0 disables)")
+	flags.IntVar(&cfg.batchSize, "batch", 1000, "Documents per InsertMany")
+	return cmd
+}
+
+func loadExercises(ctx context.Context, db *mongo.Database) ([]exerciseRef, error) {
+	cursor, err := db.Collection("exercises").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var refs []exerciseRef
+	for cursor.Next(ctx) {
+		var ex domain.Exercise
+		if err := cursor.Decode(&ex); err != nil {
+			continue
+		}
+		refs = append(refs, exerciseRef{ID: ex.ID, Name: ex.Name})
+	}
+	return refs, cursor.Err()
+}
+
+// generator builds one tenant's data. Not safe for concurrent use; each worker owns one.
+type generator struct {
+	db        *mongo.Database
+	cfg       settings
+	stats     *stats
+	exercises []exerciseRef
+	rnd       *rand.Rand
+	runID     string
+	now       time.Time
+}
+
+// memberPlan is the per-member state carried across the simulated year
+type memberPlan struct {
+	id       string
+	coachID  string
+	contract *domain.PTContract
+	sequence int64
+}
+
+func (g *generator) tenant(ctx context.Context, t int) error {
+	tenantName := fmt.Sprintf("Load Gym %d", t+1)
+
+	tenantIDs, err := g.insert(ctx, "tenants", []interface{}{&domain.Tenant{
+		Name:      tenantName,
+		JoinCode:  fmt.Sprintf("LOAD-%s-%d", g.runID, t),
+		CreatedAt: g.start(),
+	}})
+	if err != nil {
+		return err
+	}
+	tenantID := tenantIDs[0]
+
+	branchIDs, err := g.insert(ctx, "branches", []interface{}{&domain.Branch{
+		TenantID:  tenantID,
+		Name:      tenantName + " Main",
+		JoinCode:  fmt.Sprintf("LOADB-%s-%d", g.runID, t),
+		CreatedAt: g.start(),
+		UpdatedAt: g.start(),
+	}})
+	if err != nil {
+		return err
+	}
+	branchID := branchIDs[0]
+
+	// Users get ULIDs like the ones the user repository assigns
+	var users []interface{}
+	for c := 0; c < g.cfg.coachesPerTenant; c++ {
+		users = append(users, &domain.User{
+			ID:           ulid.Make().String(),
+			Email:        fmt.Sprintf("coach%d.t%d.%s@load.test", c, t, g.runID),
+			Name:         fmt.Sprintf("Coach %d", c+1),
+			Roles:        []string{domain.RoleCoach},
+			TenantID:     tenantID,
+			HomeBranchID: branchID,
+			CreatedAt:    g.start(),
+			UpdatedAt:    g.start(),
+		})
+	}
+	for m := 0; m < g.cfg.membersPerTenant; m++ {
+		users = append(users, &domain.User{
+			ID:           ulid.Make().String(),
+			Email:        fmt.Sprintf("member%d.t%d.%s@load.test", m, t, g.runID),
+			Name:         fmt.Sprintf("Member %d", m+1),
+			Roles:        []string{domain.RoleMember},
+			TenantID:     tenantID,
+			BranchAccess: []string{branchID},
+			CreatedAt:    g.start(),
+			UpdatedAt:    g.start(),
+		})
+	}
+	userIDs, err := g.insert(ctx, "users", users)
+	if err != nil {
+		return err
+	}
+	g.stats.users.Add(int64(len(userIDs)))
+	coachIDs := userIDs[:g.cfg.coachesPerTenant]
+	memberIDs := userIDs[g.cfg.coachesPerTenant:]
+
+	// Package templates
+	packageSizes := []int{10, 20}
+	var pkgs []interface{}
+	for _, size := range packageSizes {
+		pkgs = append(pkgs, &domain.PTPackage{
+			TenantID:      tenantID,
+			BranchID:      branchID,
+			Name:          fmt.Sprintf("%d Session Pack", size),
+			TotalSessions: size,
+			Price:         domain.NewMoney(int64(size)*200000, domain.DefaultCurrency),
+			Active:        true,
+			CreatedAt:     g.start(),
+			UpdatedAt:     g.start(),
+		})
+	}
+	packageIDs, err := g.insert(ctx, "pt_packages", pkgs)
+	if err != nil {
+		return err
+	}
+
+	// Members are simulated one at a time to keep memory flat for large tenants
+	for i, memberID := range memberIDs {
+		plan := &memberPlan{id: memberID, coachID: coachIDs[i%len(coachIDs)]}
+		if err := g.member(ctx, tenantID, branchID, packageIDs, packageSizes, plan); err != nil {
+			return fmt.Errorf("member %s: %w", memberID, err)
+		}
+	}
+	return nil
+}
+
+func (g *generator) member(ctx context.Context, tenantID, branchID string, packageIDs []string, packageSizes []int, plan *memberPlan) error {
+	var (
+		contracts []*domain.PTContract
+		credits   []*domain.CreditTransaction
+		schedules []interface{}
+		sessions  []*sessionDraft
+	)
+
+	newContract := func(at time.Time) error {
+		p := g.rnd.Intn(len(packageIDs))
+		contract := &domain.PTContract{
+			TenantID:          tenantID,
+			BranchID:          branchID,
+			PackageID:         packageIDs[p],
+			MemberID:          plan.id,
+			CoachID:           plan.coachID,
+			TotalSessions:     packageSizes[p],
+			RemainingSessions: packageSizes[p],
+			Price:             domain.NewMoney(int64(packageSizes[p])*200000, domain.DefaultCurrency),
+			Status:            domain.PackageStatusActive,
+			CreatedAt:         at,
+			UpdatedAt:         at,
+		}
+		ids, err := g.insert(ctx, "pt_contracts", []interface{}{contract})
+		if err != nil {
+			return err
+		}
+		contract.ID = ids[0]
+		contracts = append(contracts, contract)
+		plan.contract = contract
+		plan.sequence = 1
+		credits = append(credits, &domain.CreditTransaction{
+			TenantID:       tenantID,
+			ContractID:     contract.ID,
+			MemberID:       plan.id,
+			Sequence:       1,
+			Type:           domain.CreditTypePurchased,
+			Amount:         contract.TotalSessions,
+			BalanceAfter:   contract.TotalSessions,
+			Note:           "Package purchased",
+			IdempotencyKey: "purchased:" + contract.ID,
+			CreatedAt:      at,
+		})
+		return nil
+	}
+
+	if err := newContract(g.start()); err != nil {
+		return err
+	}
+
+	// Walk the year week by week; sessions land on random weekdays at gym hours
+	for day := 0; day < g.cfg.days; day += 7 {
+		for s := 0; s < g.cfg.sessionsPerWeek; s++ {
+			startTime := g.start().AddDate(0, 0, day+g.rnd.Intn(7)).
+				Truncate(24 * time.Hour).
+				Add(time.Duration(6+g.rnd.Intn(14)) * time.Hour)
+			if startTime.After(g.now) {
+				continue
+			}
+			if plan.contract.RemainingSessions == 0 {
+				if err := newContract(startTime.Add(-24 * time.Hour)); err != nil {
+					return err
+				}
+			}
+
+			status := domain.ScheduleStatusCompleted
+			switch r := g.rnd.Float64(); {
+			case r < 0.05:
+				status = domain.ScheduleStatusNoShow
+			case r < 0.12:
+				status = domain.ScheduleStatusCancelled
+			}
+
+			focus := focusAreas[g.rnd.Intn(len(focusAreas))]
+			schedule := &domain.Schedule{
+				TenantID:   tenantID,
+				BranchID:   branchID,
+				ContractID: plan.contract.ID,
+				CoachID:    plan.coachID,
+				MemberID:   plan.id,
+				StartTime:  startTime,
+				EndTime:    startTime.Add(time.Hour),
+				Status:     status,
+				FocusArea:  focus,
+				CreatedAt:  startTime.Add(-72 * time.Hour),
+				UpdatedAt:  startTime.Add(time.Hour),
+			}
+			schedules = append(schedules, schedule)
+			if status == domain.ScheduleStatusCompleted {
+				sessions = append(sessions, &sessionDraft{schedule: schedule, contract: plan.contract})
+				plan.contract.RemainingSessions--
+				plan.contract.UpdatedAt = schedule.UpdatedAt
+			}
+		}
+	}
+
+	// Schedules first so sessions can reference their IDs
+	scheduleIDs, err := g.insert(ctx, "schedules", schedules)
+	if err != nil {
+		return err
+	}
+	for i, id := range scheduleIDs {
+		schedules[i].(*domain.Schedule).ID = id
+	}
+	g.stats.schedules.Add(int64(len(scheduleIDs)))
+
+	// Consumption ledger entries, in session order per contract
+	sequences := make(map[string]int64, len(contracts))
+	balances := make(map[string]int, len(contracts))
+	for _, c := range contracts {
+		sequences[c.ID] = 1
+		balances[c.ID] = c.TotalSessions
+	}
+	for _, s := range sessions {
+		id := s.contract.ID
+		sequences[id]++
+		balances[id]--
+		credits = append(credits, &domain.CreditTransaction{
+			TenantID:       tenantID,
+			ContractID:     id,
+			MemberID:       plan.id,
+			Sequence:       sequences[id],
+			Type:           domain.CreditTypeConsumed,
+			Amount:         -1,
+			BalanceAfter:   balances[id],
+			ScheduleID:     s.schedule.ID,
+			ActorID:        plan.coachID,
+			IdempotencyKey: "consumed:" + s.schedule.ID,
+			CreatedAt:      s.schedule.UpdatedAt,
+		})
+	}
+	creditDocs := make([]interface{}, len(credits))
+	for i, c := range credits {
+		creditDocs[i] = c
+	}
+	if _, err := g.insert(ctx, "credit_transactions", creditDocs); err != nil {
+		return err
+	}
+	g.stats.credits.Add(int64(len(credits)))
+
+	// Contract balances and statuses reflect the simulated consumption
+	for _, c := range contracts {
+		status := domain.PackageStatusActive
+		if c.RemainingSessions == 0 {
+			status = domain.PackageStatusDepleted
+		}
+		oid, _ := primitive.ObjectIDFromHex(c.ID)
+		_, err := g.db.Collection("pt_contracts").UpdateByID(ctx, oid, bson.M{"$set": bson.M{
+			"remaining_sessions": c.RemainingSessions,
+			"ledger_sequence":    sequences[c.ID],
+			"status":             status,
+			"updated_at":         c.UpdatedAt,
+		}})
+		if err != nil {
+			return err
+		}
+	}
+	g.stats.contracts.Add(int64(len(contracts)))
+
+	if err := g.workouts(ctx, tenantID, branchID, plan, sessions); err != nil {
+		return err
+	}
+	return g.scans(ctx, plan)
+}
+
+type sessionDraft struct {
+	schedule *domain.Schedule
+	contract *domain.PTContract
+}
+
+// workouts writes planned exercises, set logs and daily volumes for completed sessions.
+// Working weights ramp up slowly over the year so PB and volume charts have a trend.
+func (g *generator) workouts(ctx context.Context, tenantID, branchID string, plan *memberPlan, sessions []*sessionDraft) error {
+	var (
+		workoutDocs []interface{}
+		planDocs    []interface{}
+		planSets    [][]*domain.SetLogDocument
+		volumeDocs  []interface{}
+	)
+	baseWeights := make(map[string]float64)
+
+	for n, s := range sessions {
+		sched := s.schedule
+		workoutDocs = append(workoutDocs, &domain.WorkoutSession{
+			TenantID:   tenantID,
+			BranchID:   branchID,
+			ScheduleID: sched.ID,
+			CoachID:    sched.CoachID,
+			MemberID:   sched.MemberID,
+			CreatedAt:  sched.StartTime,
+			UpdatedAt:  sched.EndTime,
+		})
+
+		volume := &domain.DailyVolume{
+			TenantID:   tenantID,
+			MemberID:   plan.id,
+			ScheduleID: sched.ID,
+			FocusArea:  sched.FocusArea,
+			Date:       sched.StartTime.Truncate(24 * time.Hour),
+			CreatedAt:  sched.EndTime,
+		}
+
+		for e := 0; e < g.cfg.exercisesPerDay; e++ {
+			ex := g.exercises[g.rnd.Intn(len(g.exercises))]
+			if _, ok := baseWeights[ex.ID]; !ok {
+				baseWeights[ex.ID] = float64(10 + g.rnd.Intn(50))
+			}
+			weight := math.Round((baseWeights[ex.ID]*(1+0.003*float64(n)))/2.5) * 2.5
+
+			planDocs = append(planDocs, &domain.PlannedExercise{
+				ScheduleID:  sched.ID,
+				ExerciseID:  ex.ID,
+				Name:        ex.Name,
+				TargetSets:  g.cfg.setsPerExercise,
+				TargetReps:  10,
+				RestSeconds: 90,
+				Order:       e,
+			})
+
+			var sets []*domain.SetLogDocument
+			for i := 1; i <= g.cfg.setsPerExercise; i++ {
+				reps := 8 + g.rnd.Intn(5)
+				sets = append(sets, &domain.SetLogDocument{
+					ScheduleID: sched.ID,
+					MemberID:   plan.id,
+					ExerciseID: ex.ID,
+					SetIndex:   i,
+					Weight:     weight,
+					Reps:       reps,
+					Completed:  true,
+					CreatedAt:  sched.StartTime,
+					UpdatedAt:  sched.StartTime,
+				})
+				volume.TotalVolume += weight * float64(reps)
+				volume.TotalSets++
+				volume.TotalReps += reps
+				volume.TotalWeight += weight
+			}
+			planSets = append(planSets, sets)
+			volume.ExerciseCount++
+		}
+		volumeDocs = append(volumeDocs, volume)
+	}
+
+	if _, err := g.insert(ctx, "workout_sessions", workoutDocs); err != nil {
+		return err
+	}
+
+	planIDs, err := g.insert(ctx, "planned_exercises", planDocs)
+	if err != nil {
+		return err
+	}
+	g.stats.plans.Add(int64(len(planIDs)))
+
+	var setDocs []interface{}
+	for i, sets := range planSets {
+		for _, set := range sets {
+			set.PlannedExerciseID = planIDs[i]
+			setDocs = append(setDocs, set)
+		}
+	}
+	if _, err := g.insert(ctx, "set_logs", setDocs); err != nil {
+		return err
+	}
+	g.stats.setLogs.Add(int64(len(setDocs)))
+
+	if _, err := g.insert(ctx, "daily_volumes", volumeDocs); err != nil {
+		return err
+	}
+	g.stats.volumes.Add(int64(len(volumeDocs)))
+	return nil
+}
+
+// scans writes one InBody scan every scanEveryDays, with body composition improving over time
+func (g *generator) scans(ctx context.Context, plan *memberPlan) error {
+	if g.cfg.scanEveryDays <= 0 {
+		return nil
+	}
+
+	startWeight := 65 + g.rnd.Float64()*30
+	startPBF := 18 + g.rnd.Float64()*15
+	height := 1.6 + g.rnd.Float64()*0.3
+	total := g.cfg.days / g.cfg.scanEveryDays
+
+	var docs []interface{}
+	for i := 0; i <= total; i++ {
+		progress := float64(i) / math.Max(1, float64(total))
+		weight := round1(startWeight * (1 - 0.05*progress))
+		pbf := round1(startPBF * (1 - 0.2*progress))
+		fatMass := round1(weight * pbf / 100)
+
+		record := &domain.InBodyRecord{
+			UserID:       plan.id,
+			TestDateTime: g.start().AddDate(0, 0, i*g.cfg.scanEveryDays).Add(8 * time.Hour),
+			Weight:       weight,
+			SMM:          round1((weight - fatMass) * 0.55),
+			BodyFatMass:  fatMass,
+			BMI:          round1(weight / (height * height)),
+			PBF:          pbf,
+			BMR:          int(370 + 21.6*(weight-fatMass)),
+			FatFreeMass:  round1(weight - fatMass),
+			InBodyScore:  math.Round(70 + 10*progress),
+		}
+		record.Metadata.ProcessedAt = record.TestDateTime
+		docs = append(docs, record)
+	}
+
+	ids, err := g.insert(ctx, "inbody_records", docs)
+	if err != nil {
+		return err
+	}
+	g.stats.scans.Add(int64(len(ids)))
+	return nil
+}
+
+// insert writes docs in batches and returns their generated IDs (hex) in input order
+func (g *generator) insert(ctx context.Context, collection string, docs []interface{}) ([]string, error) {
+	ids := make([]string, 0, len(docs))
+	col := g.db.Collection(collection)
+	opts := options.InsertMany().SetOrdered(false)
+
+	for start := 0; start < len(docs); start += g.cfg.batchSize {
+		end := start + g.cfg.batchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+		result, err := col.InsertMany(ctx, docs[start:end], opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to insert into %s: %w", collection, err)
+		}
+		for _, id := range result.InsertedIDs {
+			if oid, ok := id.(primitive.ObjectID); ok {
+				ids = append(ids, oid.Hex())
+			} else {
+				ids = append(ids, fmt.Sprint(id))
+			}
+		}
+	}
+	return ids, nil
+}
+
+// start is the first day of simulated history
+func (g *generator) start() time.Time {
+	return g.now.AddDate(0, 0, -g.cfg.days)
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,218 @@
+// Package audit records the mutating requests made through the API: who made them, in which
+// tenant, what they touched and how it changed.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+const (
+	beforeKey = "audit_before"
+	afterKey  = "audit_after"
+
+	// maxBodyBytes caps the response bodies kept as an entity's after state
+	maxBodyBytes = 64 << 10
+
+	redacted = "[redacted]"
+)
+
+// sensitiveKeys are redacted wherever they appear in a recorded entity
+var sensitiveKeys = []string{"password", "secret", "token", "api_key", "signature"}
+
+// ignoredChanges are bookkeeping fields left out of the changes
+var ignoredChanges = map[string]bool{"updated_at": true, "version": true}
+
+// Middleware records every POST, PUT, PATCH and DELETE request once it has been handled.
+// Mount it after authentication so the actor and tenant are known. Writes happen in the
+// background; a failed write is logged and never fails the request.
+func Middleware(repo domain.AuditLogRepository, clk domain.Clock) fiber.Handler {
+	clk = clock.OrReal(clk)
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		default:
+			return c.Next()
+		}
+
+		err := c.Next()
+
+		entry := entryFor(c, err)
+		entry.CreatedAt = clk.Now()
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := repo.Record(ctx, entry); err != nil {
+				log.Printf("audit: failed to record %s %s: %v", entry.Method, entry.Path, err)
+			}
+		}()
+		return err
+	}
+}
+
+// Before records the entity as it was before the request changed it. Handlers call it once
+// they have loaded the entity, so the log can show what changed; v is copied straight away.
+func Before(c *fiber.Ctx, v interface{}) {
+	if m := toMap(v); m != nil {
+		c.Locals(beforeKey, m)
+	}
+}
+
+// After records the entity as the request left it, for handlers whose response isn't the entity
+func After(c *fiber.Ctx, v interface{}) {
+	if m := toMap(v); m != nil {
+		c.Locals(afterKey, m)
+	}
+}
+
+// entryFor builds the audit log for a handled request; err is what the handlers returned
+func entryFor(c *fiber.Ctx, err error) *domain.AuditLog {
+	route := c.Route().Path
+	entityType, entityParam := entityOf(route)
+
+	entry := &domain.AuditLog{
+		Method:     c.Method(),
+		Route:      route,
+		Path:       c.Path(),
+		EntityType: entityType,
+		Status:     statusOf(c, err),
+		IPAddress:  c.IP(),
+		UserAgent:  c.Get(fiber.HeaderUserAgent),
+	}
+	if entityParam != "" {
+		entry.EntityID = c.Params(entityParam)
+	}
+	entry.ActorID, _ = c.Locals("userID").(string)
+	entry.ActorRoles, _ = c.Locals("roles").([]string)
+	entry.TenantID, _ = c.Locals("tenant_id").(string)
+
+	entry.Before, _ = c.Locals(beforeKey).(map[string]interface{})
+	if entry.Status >= 300 {
+		return entry
+	}
+	entry.After, _ = c.Locals(afterKey).(map[string]interface{})
+	if entry.After == nil {
+		entry.After = responseBody(c)
+	}
+	if entry.Before != nil && entry.After != nil {
+		entry.Changes = Diff(entry.Before, entry.After)
+	}
+	return entry
+}
+
+// Diff returns the top-level fields whose values differ between before and after. Fields
+// missing from after are left out: responses often omit fields the request didn't change.
+func Diff(before, after map[string]interface{}) map[string]domain.AuditChange {
+	changes := map[string]domain.AuditChange{}
+	for key, to := range after {
+		if ignoredChanges[key] {
+			continue
+		}
+		from, ok := before[key]
+		if ok && reflect.DeepEqual(from, to) {
+			continue
+		}
+		changes[key] = domain.AuditChange{From: from, To: to}
+	}
+	return changes
+}
+
+// Redact replaces the values of sensitive keys, at any depth, and returns m
+func Redact(m map[string]interface{}) map[string]interface{} {
+	for key, v := range m {
+		if isSensitive(key) {
+			m[key] = redacted
+			continue
+		}
+		redactValue(v)
+	}
+	return m
+}
+
+func redactValue(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		Redact(v)
+	case []interface{}:
+		for _, item := range v {
+			redactValue(item)
+		}
+	}
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// entityOf names what a route changes: the segment before its first parameter together
+// with that parameter (/v1/tenant-admin/users/:id/restore -> "users", "id"), or the last
+// segment if it has none (/v1/tenant-admin/minor-policy -> "minor-policy", "")
+func entityOf(route string) (entityType, param string) {
+	segments := strings.Split(strings.Trim(route, "/"), "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			if i == 0 {
+				return "", ""
+			}
+			return segments[i-1], strings.TrimSuffix(strings.TrimPrefix(segment, ":"), "?")
+		}
+	}
+	last := segments[len(segments)-1]
+	if last == "*" {
+		return "", ""
+	}
+	return last, ""
+}
+
+// statusOf returns the response status, including for errors the app's error handler has yet to write
+func statusOf(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	return fiber.StatusInternalServerError
+}
+
+// responseBody decodes a JSON object response, or returns nil
+func responseBody(c *fiber.Ctx) map[string]interface{} {
+	body := c.Response().Body()
+	if len(body) == 0 || len(body) > maxBodyBytes || body[0] != '{' {
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(body, &m); err != nil {
+		return nil
+	}
+	return Redact(m)
+}
+
+// toMap copies v into a redacted JSON object, as the API would present it
+func toMap(v interface{}) map[string]interface{} {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil
+	}
+	return Redact(m)
+}
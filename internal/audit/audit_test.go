@@ -0,0 +1,137 @@
+package audit
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	before := map[string]interface{}{"name": "Old", "email": "a@b.c", "updated_at": "t1", "roles": []interface{}{"member"}}
+	after := map[string]interface{}{"name": "New", "email": "a@b.c", "updated_at": "t2", "roles": []interface{}{"member"}, "phone": "123"}
+
+	changes := Diff(before, after)
+
+	assert.Equal(t, map[string]domain.AuditChange{
+		"name":  {From: "Old", To: "New"},
+		"phone": {From: nil, To: "123"},
+	}, changes)
+}
+
+func TestRedact(t *testing.T) {
+	m := Redact(map[string]interface{}{
+		"name":     "Ann",
+		"password": "hunter2",
+		"webhook":  map[string]interface{}{"SigningSecret": "s"},
+		"tokens":   []interface{}{map[string]interface{}{"refresh_token": "r"}},
+	})
+
+	assert.Equal(t, "Ann", m["name"])
+	assert.Equal(t, redacted, m["password"])
+	assert.Equal(t, redacted, m["webhook"].(map[string]interface{})["SigningSecret"])
+	assert.Equal(t, redacted, m["tokens"])
+}
+
+func TestEntityOf(t *testing.T) {
+	cases := []struct {
+		route, entityType, param string
+	}{
+		{"/v1/tenant-admin/users/:id", "users", "id"},
+		{"/v1/tenant-admin/users/:id/restore", "users", "id"},
+		{"/v1/pro/members/:id/guardian-consent", "members", "id"},
+		{"/v1/platform/users/:userId/memberships/:tenant_id", "users", "userId"},
+		{"/v1/tenant-admin/minor-policy", "minor-policy", ""},
+		{"/v1/tenant-admin/branches/", "branches", ""},
+	}
+	for _, tc := range cases {
+		entityType, param := entityOf(tc.route)
+		assert.Equal(t, tc.entityType, entityType, tc.route)
+		assert.Equal(t, tc.param, param, tc.route)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	now := time.Date(2025, 6, 16, 10, 0, 0, 0, time.UTC)
+
+	newApp := func(repo domain.AuditLogRepository) *fiber.App {
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("userID", "admin-1")
+			c.Locals("roles", []string{domain.RoleTenantAdmin})
+			c.Locals("tenant_id", "tenant-1")
+			return c.Next()
+		})
+		app.Use(Middleware(repo, clock.NewFake(now)))
+		app.Get("/users/:id", func(c *fiber.Ctx) error {
+			return c.JSON(fiber.Map{"id": c.Params("id")})
+		})
+		app.Put("/users/:id", func(c *fiber.Ctx) error {
+			Before(c, fiber.Map{"id": c.Params("id"), "name": "Old", "password": "x"})
+			return c.JSON(fiber.Map{"id": c.Params("id"), "name": "New", "password": "y"})
+		})
+		app.Delete("/users/:id", func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+		})
+		return app
+	}
+
+	record := func(t *testing.T, method, path string) *domain.AuditLog {
+		repo := mocks.NewAuditLogRepository(t)
+		recorded := make(chan *domain.AuditLog, 1)
+		repo.On("Record", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { recorded <- args.Get(1).(*domain.AuditLog) }).
+			Return(nil)
+
+		req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		_, err := newApp(repo).Test(req)
+		require.NoError(t, err)
+
+		select {
+		case entry := <-recorded:
+			return entry
+		case <-time.After(time.Second):
+			t.Fatal("audit log not recorded")
+			return nil
+		}
+	}
+
+	t.Run("records the change with the actor and entity", func(t *testing.T) {
+		entry := record(t, fiber.MethodPut, "/users/u1")
+
+		assert.Equal(t, "admin-1", entry.ActorID)
+		assert.Equal(t, []string{domain.RoleTenantAdmin}, entry.ActorRoles)
+		assert.Equal(t, "tenant-1", entry.TenantID)
+		assert.Equal(t, "/users/:id", entry.Route)
+		assert.Equal(t, "users", entry.EntityType)
+		assert.Equal(t, "u1", entry.EntityID)
+		assert.Equal(t, fiber.StatusOK, entry.Status)
+		assert.Equal(t, now, entry.CreatedAt)
+		assert.Equal(t, redacted, entry.Before["password"])
+		assert.Equal(t, map[string]domain.AuditChange{"name": {From: "Old", To: "New"}}, entry.Changes)
+	})
+
+	t.Run("records failed requests without an after state", func(t *testing.T) {
+		entry := record(t, fiber.MethodDelete, "/users/u1")
+
+		assert.Equal(t, fiber.StatusNotFound, entry.Status)
+		assert.Nil(t, entry.After)
+		assert.Empty(t, entry.Changes)
+	})
+
+	t.Run("skips reads", func(t *testing.T) {
+		repo := mocks.NewAuditLogRepository(t)
+
+		_, err := newApp(repo).Test(httptest.NewRequest(fiber.MethodGet, "/users/u1", nil))
+		require.NoError(t, err)
+	})
+}
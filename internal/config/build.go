@@ -0,0 +1,49 @@
+package config
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Build identification, set at link time:
+//
+//	go build -ldflags "-X github.com/mansoorceksport/metamorph/internal/config.Version=v1.4.0 \
+//	  -X github.com/mansoorceksport/metamorph/internal/config.Commit=$(git rev-parse HEAD)"
+//
+// Commit and BuildTime fall back to the VCS stamp Go embeds when building from a checkout.
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// BuildInfo identifies the running binary
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time,omitempty"`
+	Modified  bool   `json:"modified,omitempty"` // Built from a checkout with uncommitted changes
+	GoVersion string `json:"go_version"`
+}
+
+// Build returns the build identification of the running binary
+func Build() BuildInfo {
+	info := BuildInfo{Version: Version, Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = s.Value
+				}
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+	return info
+}
@@ -7,8 +7,12 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/mansoorceksport/metamorph/internal/domain"
 )
 
+// defaultJWTSecret is only fit for local development
+const defaultJWTSecret = "metamorph-dev-secret-change-in-production"
+
 // Config holds all application configuration
 type Config struct {
 	Server     ServerConfig
@@ -16,15 +20,28 @@ type Config struct {
 	Redis      RedisConfig
 	Firebase   FirebaseConfig
 	OpenRouter OpenRouterConfig
+	Form       FormFeedbackConfig
 	S3         S3Config
 	JWT        JWTConfig
 	OTEL       OTELConfig
+	Errors     ErrorReportingConfig
+	Warehouse  WarehouseConfig
+	Jobs       JobsConfig
+	Meeting    MeetingConfig
+	Email      EmailConfig
+	RateLimits RateLimitsConfig
 }
 
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
 	Port            string
-	MaxUploadSizeMB int64
+	MaxUploadSizeMB int64 // Upload routes (scans, documents, signatures)
+	MaxJSONBodyKB   int64 // Every other route
+	MaxVideoSizeMB  int64 // Form-check videos on sets
+	MaxVideoSeconds int64
+
+	MaxDemoVideoSizeMB  int64 // Exercise demonstration videos
+	MaxDemoVideoSeconds int64
 }
 
 type S3Config struct {
@@ -38,6 +55,9 @@ type S3Config struct {
 type MongoDBConfig struct {
 	URI      string
 	Database string
+	// AnalyticsReadPreference routes lag-tolerant analytics reads (dashboards, trends,
+	// volume history), e.g. "secondaryPreferred". Empty keeps them on the primary.
+	AnalyticsReadPreference string
 }
 
 // RedisConfig holds Redis connection configuration
@@ -51,6 +71,7 @@ type FirebaseConfig struct {
 	ProjectID   string
 	PrivateKey  string // Base64 encoded
 	ClientEmail string
+	PushEnabled bool // Send push notifications through Firebase Cloud Messaging; otherwise they're only logged
 }
 
 // OpenRouterConfig holds OpenRouter API configuration
@@ -59,6 +80,13 @@ type OpenRouterConfig struct {
 	Model  string
 }
 
+// FormFeedbackConfig holds the AI form review of set videos. Tenants still have to opt in.
+type FormFeedbackConfig struct {
+	Enabled    bool
+	Model      string // Vision model on OpenRouter; defaults to OPENROUTER_MODEL
+	FFmpegPath string
+}
+
 // JWTConfig holds JWT token configuration
 type JWTConfig struct {
 	Secret             string
@@ -77,20 +105,105 @@ type OTELConfig struct {
 	Environment    string
 }
 
+// ErrorReportingConfig holds the Sentry-compatible error tracker settings
+type ErrorReportingConfig struct {
+	Enabled     bool
+	DSN         string
+	Environment string
+	Release     string
+}
+
+// WarehouseConfig holds the BI export settings used by `metamorph export warehouse`
+type WarehouseConfig struct {
+	Sink               string // "clickhouse" or "stdout" (JSON lines)
+	ClickHouseURL      string
+	ClickHouseDatabase string
+	ClickHouseTable    string
+	ClickHouseUser     string
+	ClickHousePassword string
+	PseudonymKey       string // HMAC key for the pseudonyms in anonymized exports; keep it stable
+}
+
+// JobsConfig holds the settings of the periodic background jobs
+type JobsConfig struct {
+	ArchiveAfterMonths int64 // Archive workout detail older than this; 0 disables the job
+	ReminderMinutes    int64 // How often session reminders and unconfirmed-session alerts are sent; 0 disables them
+	ScanImageDays      int64 // Keep original scan images this long, then downscale them; 0 keeps them forever
+
+	// Days an installment may be overdue before its contract is suspended, unless the plan sets its own
+	InstallmentGraceDays int64
+
+	// Days deleted users, branches and tenants can be restored before they are purged; 0 never purges
+	DeletedRetentionDays int64
+}
+
+// MeetingConfig selects how video links for online sessions are generated
+type MeetingConfig struct {
+	Provider         string // "zoom", "room" (Jitsi-style links) or empty to only accept links coaches paste
+	ZoomAccountID    string
+	ZoomClientID     string
+	ZoomClientSecret string
+	RoomBaseURL      string
+}
+
+// EmailConfig selects the provider email notifications go out through
+type EmailConfig struct {
+	Provider       string // "smtp", "sendgrid" or empty to only log emails
+	From           string // Sender address; tenants' names are shown with it
+	SMTPHost       string
+	SMTPPort       string
+	SMTPUsername   string
+	SMTPPassword   string
+	SendGridAPIKey string
+}
+
+// RateLimitsConfig holds the request limits of the route groups that need them, each as
+// "requests/period": "10/1m" allows 10 requests at once, then one every 6 seconds. "0"
+// turns a limit off. See domain.ParseRateLimit.
+type RateLimitsConfig struct {
+	Enabled         bool
+	AuthPerIP       string // Sign-in, token refresh and invite acceptance
+	DigitizePerUser string // AI scan digitizing by members and coaches
+	DigitizePerIP   string
+	PaymentsPerUser string // Checkouts and installment payments
+	PaymentsPerIP   string
+}
+
 // Load reads configuration from environment variables
 // It attempts to load from .env file first, then falls back to system env vars
 func Load() (*Config, error) {
+	cfg := FromEnv()
+
+	// Validate required fields
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("config validation failed: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// FromEnv reads the configuration without validating it. Offline tools (cmd/metamorph) use it
+// because they only need MongoDB and do not run the API's Firebase or OpenRouter clients.
+func FromEnv() *Config {
 	// Try to load .env file (ignore error if not found)
 	_ = godotenv.Load()
 
-	cfg := &Config{
+	return &Config{
 		Server: ServerConfig{
 			Port:            getEnv("PORT", "8080"),
 			MaxUploadSizeMB: getEnvAsInt64("MAX_UPLOAD_SIZE_MB", 5),
+			MaxJSONBodyKB:   getEnvAsInt64("MAX_JSON_BODY_KB", 256),
+			MaxVideoSizeMB:  getEnvAsInt64("MAX_VIDEO_SIZE_MB", 50),
+			MaxVideoSeconds: getEnvAsInt64("MAX_VIDEO_SECONDS", 60),
+
+			MaxDemoVideoSizeMB:  getEnvAsInt64("MAX_DEMO_VIDEO_SIZE_MB", 100),
+			MaxDemoVideoSeconds: getEnvAsInt64("MAX_DEMO_VIDEO_SECONDS", 180),
 		},
 		MongoDB: MongoDBConfig{
 			URI:      getEnv("MONGODB_URI", "mongodb://localhost:27017"),
 			Database: getEnv("MONGODB_DATABASE", "homgym"),
+
+			AnalyticsReadPreference: getEnv("MONGODB_ANALYTICS_READ_PREFERENCE", ""),
 		},
 		Redis: RedisConfig{
 			Addr:     getEnv("REDIS_ADDR", "localhost:6379"),
@@ -100,11 +213,17 @@ func Load() (*Config, error) {
 			ProjectID:   getEnv("FIREBASE_PROJECT_ID", ""),
 			PrivateKey:  getEnv("FIREBASE_PRIVATE_KEY", ""),
 			ClientEmail: getEnv("FIREBASE_CLIENT_EMAIL", ""),
+			PushEnabled: getEnvAsBool("FCM_PUSH_ENABLED", false),
 		},
 		OpenRouter: OpenRouterConfig{
 			APIKey: getEnv("OPENROUTER_API_KEY", ""),
 			Model:  getEnv("OPENROUTER_MODEL", "google/gemini-2.0-flash-001"),
 		},
+		Form: FormFeedbackConfig{
+			Enabled:    getEnvAsBool("AI_FORM_FEEDBACK_ENABLED", false),
+			Model:      getEnv("AI_FORM_FEEDBACK_MODEL", getEnv("OPENROUTER_MODEL", "google/gemini-2.0-flash-001")),
+			FFmpegPath: getEnv("FFMPEG_PATH", "ffmpeg"),
+		},
 		S3: S3Config{
 			Endpoint:  getEnv("S3_ENDPOINT", "http://localhost:8333"),
 			PublicURL: getEnv("S3_PUBLIC_URL", getEnv("S3_ENDPOINT", "http://localhost:8333")), // Falls back to Endpoint if not set
@@ -112,7 +231,7 @@ func Load() (*Config, error) {
 			Bucket:    getEnv("S3_BUCKET", "inbody-scans"),
 		},
 		JWT: JWTConfig{
-			Secret:             getEnv("JWT_SECRET", "metamorph-dev-secret-change-in-production"),
+			Secret:             getEnv("JWT_SECRET", defaultJWTSecret),
 			AccessTokenExpiry:  getDurationEnv("JWT_ACCESS_TOKEN_EXPIRY", 15*time.Minute),
 			RefreshTokenExpiry: getDurationEnv("JWT_REFRESH_TOKEN_EXPIRY", 7*24*time.Hour),
 		},
@@ -125,14 +244,54 @@ func Load() (*Config, error) {
 			ServiceVersion: getEnv("OTEL_SERVICE_VERSION", "1.0.0"),
 			Environment:    getEnv("OTEL_ENVIRONMENT", "development"),
 		},
-	}
+		Errors: ErrorReportingConfig{
+			Enabled:     getEnvAsBool("ERROR_REPORTING_ENABLED", false),
+			DSN:         getEnv("SENTRY_DSN", ""),
+			Environment: getEnv("SENTRY_ENVIRONMENT", "development"),
+			Release:     getEnv("SENTRY_RELEASE", ""),
+		},
+		Jobs: JobsConfig{
+			ArchiveAfterMonths: getEnvAsInt64("ARCHIVE_AFTER_MONTHS", 0),
+			ReminderMinutes:    getEnvAsInt64("REMINDER_INTERVAL_MINUTES", 5),
+			ScanImageDays:      getEnvAsInt64("SCAN_IMAGE_RETENTION_DAYS", 0),
 
-	// Validate required fields
-	if err := cfg.Validate(); err != nil {
-		return nil, fmt.Errorf("config validation failed: %w", err)
+			InstallmentGraceDays: getEnvAsInt64("INSTALLMENT_GRACE_DAYS", 7),
+			DeletedRetentionDays: getEnvAsInt64("DELETED_RETENTION_DAYS", 30),
+		},
+		Warehouse: WarehouseConfig{
+			Sink:               getEnv("WAREHOUSE_SINK", "stdout"),
+			ClickHouseURL:      getEnv("CLICKHOUSE_URL", "http://localhost:8123"),
+			ClickHouseDatabase: getEnv("CLICKHOUSE_DATABASE", "default"),
+			ClickHouseTable:    getEnv("CLICKHOUSE_TABLE", "metamorph_events"),
+			ClickHouseUser:     getEnv("CLICKHOUSE_USER", "default"),
+			ClickHousePassword: getEnv("CLICKHOUSE_PASSWORD", ""),
+			PseudonymKey:       getEnv("WAREHOUSE_PSEUDONYM_KEY", ""),
+		},
+		Meeting: MeetingConfig{
+			Provider:         getEnv("MEETING_PROVIDER", ""),
+			ZoomAccountID:    getEnv("ZOOM_ACCOUNT_ID", ""),
+			ZoomClientID:     getEnv("ZOOM_CLIENT_ID", ""),
+			ZoomClientSecret: getEnv("ZOOM_CLIENT_SECRET", ""),
+			RoomBaseURL:      getEnv("MEETING_ROOM_BASE_URL", "https://meet.jit.si"),
+		},
+		Email: EmailConfig{
+			Provider:       getEnv("EMAIL_PROVIDER", ""),
+			From:           getEnv("EMAIL_FROM", ""),
+			SMTPHost:       getEnv("SMTP_HOST", ""),
+			SMTPPort:       getEnv("SMTP_PORT", "587"),
+			SMTPUsername:   getEnv("SMTP_USERNAME", ""),
+			SMTPPassword:   getEnv("SMTP_PASSWORD", ""),
+			SendGridAPIKey: getEnv("SENDGRID_API_KEY", ""),
+		},
+		RateLimits: RateLimitsConfig{
+			Enabled:         getEnvAsBool("RATE_LIMIT_ENABLED", true),
+			AuthPerIP:       getEnv("RATE_LIMIT_AUTH_PER_IP", "20/1m"),
+			DigitizePerUser: getEnv("RATE_LIMIT_DIGITIZE_PER_USER", "10/1h"),
+			DigitizePerIP:   getEnv("RATE_LIMIT_DIGITIZE_PER_IP", "30/1h"),
+			PaymentsPerUser: getEnv("RATE_LIMIT_PAYMENTS_PER_USER", "10/1m"),
+			PaymentsPerIP:   getEnv("RATE_LIMIT_PAYMENTS_PER_IP", "30/1m"),
+		},
 	}
-
-	return cfg, nil
 }
 
 // Validate checks that all required configuration is present
@@ -149,6 +308,42 @@ func (c *Config) Validate() error {
 	if c.OpenRouter.APIKey == "" {
 		return fmt.Errorf("OPENROUTER_API_KEY is required")
 	}
+	if c.Errors.Enabled && c.Errors.DSN == "" {
+		return fmt.Errorf("SENTRY_DSN is required when ERROR_REPORTING_ENABLED is set")
+	}
+	switch c.Meeting.Provider {
+	case "", "room":
+	case "zoom":
+		if c.Meeting.ZoomAccountID == "" || c.Meeting.ZoomClientID == "" || c.Meeting.ZoomClientSecret == "" {
+			return fmt.Errorf("ZOOM_ACCOUNT_ID, ZOOM_CLIENT_ID and ZOOM_CLIENT_SECRET are required when MEETING_PROVIDER is zoom")
+		}
+	default:
+		return fmt.Errorf("MEETING_PROVIDER must be zoom, room or empty")
+	}
+	switch c.Email.Provider {
+	case "":
+	case "smtp":
+		if c.Email.SMTPHost == "" || c.Email.From == "" {
+			return fmt.Errorf("SMTP_HOST and EMAIL_FROM are required when EMAIL_PROVIDER is smtp")
+		}
+	case "sendgrid":
+		if c.Email.SendGridAPIKey == "" || c.Email.From == "" {
+			return fmt.Errorf("SENDGRID_API_KEY and EMAIL_FROM are required when EMAIL_PROVIDER is sendgrid")
+		}
+	default:
+		return fmt.Errorf("EMAIL_PROVIDER must be smtp, sendgrid or empty")
+	}
+	for _, limit := range []struct{ env, value string }{
+		{"RATE_LIMIT_AUTH_PER_IP", c.RateLimits.AuthPerIP},
+		{"RATE_LIMIT_DIGITIZE_PER_USER", c.RateLimits.DigitizePerUser},
+		{"RATE_LIMIT_DIGITIZE_PER_IP", c.RateLimits.DigitizePerIP},
+		{"RATE_LIMIT_PAYMENTS_PER_USER", c.RateLimits.PaymentsPerUser},
+		{"RATE_LIMIT_PAYMENTS_PER_IP", c.RateLimits.PaymentsPerIP},
+	} {
+		if _, err := domain.ParseRateLimit(limit.value); err != nil {
+			return fmt.Errorf("%s: %w", limit.env, err)
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+)
+
+// PublicConfig is the effective configuration with every secret left out. Secrets are
+// reported only as whether they are set, and credentials embedded in URLs are masked.
+type PublicConfig struct {
+	Server struct {
+		Port            string `json:"port"`
+		MaxUploadSizeMB int64  `json:"max_upload_size_mb"`
+		MaxJSONBodyKB   int64  `json:"max_json_body_kb"`
+		MaxVideoSizeMB  int64  `json:"max_video_size_mb"`
+		MaxVideoSeconds int64  `json:"max_video_seconds"`
+
+		MaxDemoVideoSizeMB  int64 `json:"max_demo_video_size_mb"`
+		MaxDemoVideoSeconds int64 `json:"max_demo_video_seconds"`
+	} `json:"server"`
+	MongoDB struct {
+		URI                     string `json:"uri"`
+		Database                string `json:"database"`
+		AnalyticsReadPreference string `json:"analytics_read_preference"`
+	} `json:"mongodb"`
+	Redis struct {
+		Addr        string `json:"addr"`
+		PasswordSet bool   `json:"password_set"`
+	} `json:"redis"`
+	Firebase struct {
+		ProjectID   string `json:"project_id"`
+		ClientEmail string `json:"client_email"`
+	} `json:"firebase"`
+	AI struct {
+		Model               string `json:"model"`
+		APIKeySet           bool   `json:"api_key_set"`
+		FormFeedbackEnabled bool   `json:"form_feedback_enabled"`
+		FormFeedbackModel   string `json:"form_feedback_model"`
+		FFmpegPath          string `json:"ffmpeg_path"`
+	} `json:"ai"`
+	S3 struct {
+		Endpoint  string `json:"endpoint"`
+		PublicURL string `json:"public_url"`
+		Region    string `json:"region"`
+		Bucket    string `json:"bucket"`
+	} `json:"s3"`
+	JWT struct {
+		AccessTokenExpiry  string `json:"access_token_expiry"`
+		RefreshTokenExpiry string `json:"refresh_token_expiry"`
+		DefaultSecret      bool   `json:"default_secret"` // Still the development secret
+	} `json:"jwt"`
+	Telemetry struct {
+		Enabled        bool   `json:"enabled"`
+		Endpoint       string `json:"endpoint"`
+		ServiceName    string `json:"service_name"`
+		ServiceVersion string `json:"service_version"`
+		Environment    string `json:"environment"`
+	} `json:"telemetry"`
+	ErrorReporting struct {
+		Enabled     bool   `json:"enabled"`
+		Environment string `json:"environment"`
+		Release     string `json:"release"`
+	} `json:"error_reporting"`
+	Warehouse struct {
+		Sink               string `json:"sink"`
+		ClickHouseURL      string `json:"clickhouse_url"`
+		ClickHouseDatabase string `json:"clickhouse_database"`
+		ClickHouseTable    string `json:"clickhouse_table"`
+		PseudonymKeySet    bool   `json:"pseudonym_key_set"`
+	} `json:"warehouse"`
+	Jobs struct {
+		ArchiveAfterMonths   int64 `json:"archive_after_months"`
+		ReminderMinutes      int64 `json:"reminder_minutes"`
+		ScanImageDays        int64 `json:"scan_image_days"`
+		DeletedRetentionDays int64 `json:"deleted_retention_days"`
+	} `json:"jobs"`
+	Meeting struct {
+		Provider            string `json:"provider"`
+		ZoomClientID        string `json:"zoom_client_id"`
+		ZoomClientSecretSet bool   `json:"zoom_client_secret_set"`
+		RoomBaseURL         string `json:"room_base_url"`
+	} `json:"meeting"`
+	Email struct {
+		Provider          string `json:"provider"`
+		From              string `json:"from"`
+		SMTPHost          string `json:"smtp_host"`
+		SMTPPort          string `json:"smtp_port"`
+		SMTPUsername      string `json:"smtp_username"`
+		SMTPPasswordSet   bool   `json:"smtp_password_set"`
+		SendGridAPIKeySet bool   `json:"sendgrid_api_key_set"`
+	} `json:"email"`
+	RateLimits struct {
+		Enabled         bool   `json:"enabled"`
+		AuthPerIP       string `json:"auth_per_ip"`
+		DigitizePerUser string `json:"digitize_per_user"`
+		DigitizePerIP   string `json:"digitize_per_ip"`
+		PaymentsPerUser string `json:"payments_per_user"`
+		PaymentsPerIP   string `json:"payments_per_ip"`
+	} `json:"rate_limits"`
+}
+
+// Public returns the configuration as it is safe to show to operators
+func (c *Config) Public() PublicConfig {
+	var p PublicConfig
+
+	p.Server.Port = c.Server.Port
+	p.Server.MaxUploadSizeMB = c.Server.MaxUploadSizeMB
+	p.Server.MaxJSONBodyKB = c.Server.MaxJSONBodyKB
+	p.Server.MaxVideoSizeMB = c.Server.MaxVideoSizeMB
+	p.Server.MaxVideoSeconds = c.Server.MaxVideoSeconds
+	p.Server.MaxDemoVideoSizeMB = c.Server.MaxDemoVideoSizeMB
+	p.Server.MaxDemoVideoSeconds = c.Server.MaxDemoVideoSeconds
+
+	p.MongoDB.URI = redactURL(c.MongoDB.URI)
+	p.MongoDB.Database = c.MongoDB.Database
+	p.MongoDB.AnalyticsReadPreference = c.MongoDB.AnalyticsReadPreference
+
+	p.Redis.Addr = c.Redis.Addr
+	p.Redis.PasswordSet = c.Redis.Password != ""
+
+	p.Firebase.ProjectID = c.Firebase.ProjectID
+	p.Firebase.ClientEmail = c.Firebase.ClientEmail
+
+	p.AI.Model = c.OpenRouter.Model
+	p.AI.APIKeySet = c.OpenRouter.APIKey != ""
+	p.AI.FormFeedbackEnabled = c.Form.Enabled
+	p.AI.FormFeedbackModel = c.Form.Model
+	p.AI.FFmpegPath = c.Form.FFmpegPath
+
+	p.S3.Endpoint = redactURL(c.S3.Endpoint)
+	p.S3.PublicURL = c.S3.PublicURL
+	p.S3.Region = c.S3.Region
+	p.S3.Bucket = c.S3.Bucket
+
+	p.JWT.AccessTokenExpiry = c.JWT.AccessTokenExpiry.String()
+	p.JWT.RefreshTokenExpiry = c.JWT.RefreshTokenExpiry.String()
+	p.JWT.DefaultSecret = c.JWT.Secret == defaultJWTSecret
+
+	p.Telemetry.Enabled = c.OTEL.Enabled
+	p.Telemetry.Endpoint = c.OTEL.Endpoint
+	p.Telemetry.ServiceName = c.OTEL.ServiceName
+	p.Telemetry.ServiceVersion = c.OTEL.ServiceVersion
+	p.Telemetry.Environment = c.OTEL.Environment
+
+	p.ErrorReporting.Enabled = c.Errors.Enabled
+	p.ErrorReporting.Environment = c.Errors.Environment
+	p.ErrorReporting.Release = c.Errors.Release
+
+	p.Warehouse.Sink = c.Warehouse.Sink
+	p.Warehouse.ClickHouseURL = redactURL(c.Warehouse.ClickHouseURL)
+	p.Warehouse.ClickHouseDatabase = c.Warehouse.ClickHouseDatabase
+	p.Warehouse.ClickHouseTable = c.Warehouse.ClickHouseTable
+	p.Warehouse.PseudonymKeySet = c.Warehouse.PseudonymKey != ""
+
+	p.Jobs.ArchiveAfterMonths = c.Jobs.ArchiveAfterMonths
+	p.Jobs.ReminderMinutes = c.Jobs.ReminderMinutes
+	p.Jobs.ScanImageDays = c.Jobs.ScanImageDays
+	p.Jobs.DeletedRetentionDays = c.Jobs.DeletedRetentionDays
+
+	p.Meeting.Provider = c.Meeting.Provider
+	p.Meeting.ZoomClientID = c.Meeting.ZoomClientID
+	p.Meeting.ZoomClientSecretSet = c.Meeting.ZoomClientSecret != ""
+	p.Meeting.RoomBaseURL = c.Meeting.RoomBaseURL
+
+	p.Email.Provider = c.Email.Provider
+	p.Email.From = c.Email.From
+	p.Email.SMTPHost = c.Email.SMTPHost
+	p.Email.SMTPPort = c.Email.SMTPPort
+	p.Email.SMTPUsername = c.Email.SMTPUsername
+	p.Email.SMTPPasswordSet = c.Email.SMTPPassword != ""
+	p.Email.SendGridAPIKeySet = c.Email.SendGridAPIKey != ""
+
+	p.RateLimits.Enabled = c.RateLimits.Enabled
+	p.RateLimits.AuthPerIP = c.RateLimits.AuthPerIP
+	p.RateLimits.DigitizePerUser = c.RateLimits.DigitizePerUser
+	p.RateLimits.DigitizePerIP = c.RateLimits.DigitizePerIP
+	p.RateLimits.PaymentsPerUser = c.RateLimits.PaymentsPerUser
+	p.RateLimits.PaymentsPerIP = c.RateLimits.PaymentsPerIP
+	return p
+}
+
+// redactURL masks the password of a URL with user info. Values that don't parse as URLs
+// get their whole user info masked by hand.
+func redactURL(raw string) string {
+	if u, err := url.Parse(raw); err == nil {
+		return u.Redacted()
+	}
+	scheme, rest, ok := strings.Cut(raw, "://")
+	if !ok {
+		return raw
+	}
+	if at := strings.LastIndex(rest, "@"); at >= 0 {
+		return scheme + "://xxxxx@" + rest[at+1:]
+	}
+	return raw
+}
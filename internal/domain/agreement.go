@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrAgreementNotFound      = errors.New("contract agreement not found")
+	ErrAgreementAlreadySigned = errors.New("contract agreement already signed")
+	ErrSignatureRequired      = errors.New("signature image or typed consent is required")
+	ErrInvalidSignatureImage  = errors.New("signature image must be a PNG or JPEG")
+	ErrSignatureImageNotKept  = errors.New("drawn signatures can't be stored; sign by typing your name")
+)
+
+// Agreement Status Constants
+const (
+	AgreementStatusPending = "pending"
+	AgreementStatusSigned  = "signed"
+)
+
+// Signature Method Constants
+const (
+	SignatureMethodImage = "image"
+	SignatureMethodTyped = "typed"
+)
+
+// DefaultContractTemplate is used when a tenant has not configured its own agreement text.
+// Placeholders are replaced when the agreement is generated.
+const DefaultContractTemplate = `PERSONAL TRAINING AGREEMENT
+
+This agreement is made between {{tenant_name}} and {{member_name}} ({{member_email}}).
+
+Package: {{package_name}}
+Sessions: {{total_sessions}}
+Price: {{price}}
+Contract Reference: {{contract_id}}
+Date: {{date}}
+
+The member agrees to attend scheduled sessions on time. Sessions cancelled without notice may be counted as used. Sessions are non-transferable and non-refundable unless required by law.
+
+The member confirms they are fit to participate in physical training and will inform their coach of any injury or medical condition.`
+
+// ContractAgreement is the signable document generated for a PTContract
+type ContractAgreement struct {
+	ID                string     `json:"id" bson:"_id,omitempty"`
+	TenantID          string     `json:"tenant_id" bson:"tenant_id"`
+	ContractID        string     `json:"contract_id" bson:"contract_id"`
+	MemberID          string     `json:"member_id" bson:"member_id"`
+	Body              string     `json:"body" bson:"body"`                                                   // Rendered template text
+	DocumentURL       string     `json:"document_url,omitempty" bson:"document_url,omitempty"`               // Unsigned PDF
+	SignedDocumentURL string     `json:"signed_document_url,omitempty" bson:"signed_document_url,omitempty"` // PDF including signature block
+	Status            string     `json:"status" bson:"status"`                                               // pending, signed
+	Signature         *Signature `json:"signature,omitempty" bson:"signature,omitempty"`
+	CreatedAt         time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" bson:"updated_at"`
+}
+
+// Signature captures how and where a member signed an agreement
+type Signature struct {
+	Method    string    `json:"method" bson:"method"`                             // image, typed
+	TypedName string    `json:"typed_name,omitempty" bson:"typed_name,omitempty"` // For typed consent
+	ImageURL  string    `json:"image_url,omitempty" bson:"image_url,omitempty"`   // For drawn signatures
+	IPAddress string    `json:"ip_address" bson:"ip_address"`                     // Captured from request
+	UserAgent string    `json:"user_agent,omitempty" bson:"user_agent,omitempty"` // Captured from request
+	SignedAt  time.Time `json:"signed_at" bson:"signed_at"`
+}
+
+type ContractAgreementRepository interface {
+	Create(ctx context.Context, agreement *ContractAgreement) error
+	GetByContractID(ctx context.Context, contractID string) (*ContractAgreement, error)
+	// MarkSigned saves the agreement's signature and signed document if it is still pending,
+	// or returns ErrAgreementAlreadySigned
+	MarkSigned(ctx context.Context, agreement *ContractAgreement) error
+}
@@ -74,6 +74,19 @@ type AnalyticsHistory struct {
 	History  []TrendData     `json:"history"`
 }
 
+// CachedAnalyticsHistory is an analytics history as cached, with when its scans were read
+type CachedAnalyticsHistory struct {
+	History    *AnalyticsHistory `json:"history"`
+	ComputedAt time.Time         `json:"computed_at"`
+}
+
+// HistoryFreshness tells a client how current the analytics history it was served is
+type HistoryFreshness struct {
+	Cached     bool      `json:"cached"`
+	Stale      bool      `json:"stale"` // The member's scans changed since; a refresh is under way
+	ComputedAt time.Time `json:"computed_at"`
+}
+
 // MemberAnalytics represents a single member's analytics data for dashboard cards
 type MemberAnalytics struct {
 	MemberID string  `json:"member_id" bson:"member_id"`
@@ -84,7 +97,8 @@ type MemberAnalytics struct {
 	Trend    string  `json:"trend" bson:"trend"` // "rising" | "declining" | "stable"
 }
 
-// DashboardSummary contains six analytics lists for the Coach Command Center
+// DashboardSummary contains six analytics lists for the Coach Command Center, and the
+// clients' upcoming package renewals
 type DashboardSummary struct {
 	RisingStars        []MemberAnalytics `json:"rising_stars"`
 	ChurnRisk          []MemberAnalytics `json:"churn_risk"`
@@ -92,6 +106,9 @@ type DashboardSummary struct {
 	StrengthWins       []MemberAnalytics `json:"strength_wins"`
 	PackageHealth      []MemberAnalytics `json:"package_health"`
 	Consistent         []MemberAnalytics `json:"consistent"`
+
+	// Clients set to renew soon, and renewals waiting for payment
+	UpcomingRenewals []UpcomingRenewal `json:"upcoming_renewals"`
 }
 
 // DashboardService defines the interface for dashboard analytics operations
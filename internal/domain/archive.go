@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ArchiveSummary counts what an archival run moved to cold storage
+type ArchiveSummary struct {
+	Schedules        int `json:"schedules"`
+	SetLogs          int `json:"set_logs"`
+	PlannedExercises int `json:"planned_exercises"`
+}
+
+// WorkoutArchiveRepository moves the workout detail (set logs, planned exercises) of old,
+// finished schedules into compressed archive collections. Schedules themselves, daily volumes
+// and personal bests stay hot; detail reads fall back to the archive transparently.
+type WorkoutArchiveRepository interface {
+	// ArchiveBefore archives up to limit finished schedules that started before cutoff
+	ArchiveBefore(ctx context.Context, cutoff time.Time, limit int) (*ArchiveSummary, error)
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ScheduleTagAssessment marks the sessions an assessment was recorded in
+const ScheduleTagAssessment = "assessment"
+
+// Assessment metrics, as named in trends
+const (
+	AssessmentPushUps     = "push_up_max"
+	AssessmentPlank       = "plank_seconds"
+	AssessmentSitAndReach = "sit_and_reach_cm"
+	AssessmentRestingHR   = "resting_hr"
+)
+
+var ErrInvalidAssessment = errors.New("assessment needs at least one result: push_up_max 0-500, plank_seconds 0-3600, sit_and_reach_cm -50 to 100, resting_hr 25-220")
+
+// Assessment holds the results of a fitness test. Each test is optional since members
+// don't always do all of them; a nil result means the test wasn't taken.
+type Assessment struct {
+	ID            string    `json:"id" bson:"_id,omitempty"`
+	TenantID      string    `json:"tenant_id" bson:"tenant_id"`
+	MemberID      string    `json:"member_id" bson:"member_id"`
+	CoachID       string    `json:"coach_id" bson:"coach_id"`
+	ScheduleID    string    `json:"schedule_id,omitempty" bson:"schedule_id,omitempty"` // Session the test was taken in, if any
+	TakenAt       time.Time `json:"taken_at" bson:"taken_at"`
+	PushUpMax     *int      `json:"push_up_max,omitempty" bson:"push_up_max,omitempty"`
+	PlankSeconds  *int      `json:"plank_seconds,omitempty" bson:"plank_seconds,omitempty"`
+	SitAndReachCM *float64  `json:"sit_and_reach_cm,omitempty" bson:"sit_and_reach_cm,omitempty"` // Past the toes is positive
+	RestingHR     *int      `json:"resting_hr,omitempty" bson:"resting_hr,omitempty"`             // Beats per minute
+	Notes         string    `json:"notes,omitempty" bson:"notes,omitempty"`
+	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// Validate checks that at least one test was taken and every result is plausible
+func (a *Assessment) Validate() error {
+	results := a.Results()
+	if len(results) == 0 {
+		return ErrInvalidAssessment
+	}
+	bounds := map[string][2]float64{
+		AssessmentPushUps:     {0, 500},
+		AssessmentPlank:       {0, 3600},
+		AssessmentSitAndReach: {-50, 100},
+		AssessmentRestingHR:   {25, 220},
+	}
+	for metric, v := range results {
+		if b := bounds[metric]; v < b[0] || v > b[1] {
+			return ErrInvalidAssessment
+		}
+	}
+	return nil
+}
+
+// Results returns the taken tests by metric name
+func (a *Assessment) Results() map[string]float64 {
+	results := make(map[string]float64, 4)
+	if a.PushUpMax != nil {
+		results[AssessmentPushUps] = float64(*a.PushUpMax)
+	}
+	if a.PlankSeconds != nil {
+		results[AssessmentPlank] = float64(*a.PlankSeconds)
+	}
+	if a.SitAndReachCM != nil {
+		results[AssessmentSitAndReach] = *a.SitAndReachCM
+	}
+	if a.RestingHR != nil {
+		results[AssessmentRestingHR] = float64(*a.RestingHR)
+	}
+	return results
+}
+
+// AssessmentPoint is one result of a metric over time
+type AssessmentPoint struct {
+	Date  time.Time `json:"date"`
+	Value float64   `json:"value"`
+}
+
+// AssessmentTrend follows one metric across a member's assessments, oldest first.
+// Improved accounts for resting HR getting better as it drops.
+type AssessmentTrend struct {
+	Metric   string            `json:"metric"`
+	Points   []AssessmentPoint `json:"points"`
+	First    float64           `json:"first"`
+	Latest   float64           `json:"latest"`
+	Change   float64           `json:"change"`
+	Improved bool              `json:"improved"`
+}
+
+// AssessmentRepository stores assessments
+type AssessmentRepository interface {
+	Create(ctx context.Context, a *Assessment) error
+	Update(ctx context.Context, a *Assessment) error
+	GetBySchedule(ctx context.Context, tenantID, scheduleID string) (*Assessment, error)
+	// ListByMember returns the member's assessments, newest first
+	ListByMember(ctx context.Context, tenantID, memberID string, limit int) ([]*Assessment, error)
+}
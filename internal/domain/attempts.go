@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTooManyAttempts is returned while a caller is throttled after repeated failures
+var ErrTooManyAttempts = errors.New("too many failed attempts; try again later")
+
+// AttemptState is the failure history of one throttling key
+type AttemptState struct {
+	Failures      int
+	LastFailureAt time.Time
+}
+
+// AttemptTracker counts failures per key (e.g. a user or IP guessing join codes).
+// State is forgotten after window passes without a new failure.
+type AttemptTracker interface {
+	// Get returns the key's state; a zero state when it has none
+	Get(ctx context.Context, key string) (*AttemptState, error)
+	RecordFailure(ctx context.Context, key string, at time.Time, window time.Duration) (*AttemptState, error)
+	Reset(ctx context.Context, key string) error
+}
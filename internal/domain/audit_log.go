@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrInvalidAuditLogQuery = errors.New("invalid audit log query: from must be before to")
+
+// AuditLog records a mutating API request: who made it, in which tenant, what it touched
+// and, where known, the entity before and after
+type AuditLog struct {
+	ID         string   `json:"id" bson:"_id,omitempty"`
+	ActorID    string   `json:"actor_id" bson:"actor_id"`
+	ActorRoles []string `json:"actor_roles,omitempty" bson:"actor_roles,omitempty"`
+	TenantID   string   `json:"tenant_id,omitempty" bson:"tenant_id,omitempty"` // Empty for platform requests outside a tenant
+	Method     string   `json:"method" bson:"method"`
+	Route      string   `json:"route" bson:"route"` // Route pattern, e.g. /v1/tenant-admin/users/:id
+	Path       string   `json:"path" bson:"path"`
+	EntityType string   `json:"entity_type,omitempty" bson:"entity_type,omitempty"` // e.g. "users"
+	EntityID   string   `json:"entity_id,omitempty" bson:"entity_id,omitempty"`
+	Status     int      `json:"status" bson:"status"`
+
+	// Secrets are redacted. Before is only known when the handler recorded it; After is the
+	// entity the request returned.
+	Before  map[string]interface{} `json:"before,omitempty" bson:"before,omitempty"`
+	After   map[string]interface{} `json:"after,omitempty" bson:"after,omitempty"`
+	Changes map[string]AuditChange `json:"changes,omitempty" bson:"changes,omitempty"` // Fields that differ between Before and After
+
+	IPAddress string    `json:"ip_address,omitempty" bson:"ip_address,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty" bson:"user_agent,omitempty"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// AuditChange is a field's value before and after a request
+type AuditChange struct {
+	From interface{} `json:"from" bson:"from"`
+	To   interface{} `json:"to" bson:"to"`
+}
+
+// AuditLogQuery filters a page of the audit log, newest first
+type AuditLogQuery struct {
+	TenantID   string // Empty searches every tenant (platform only)
+	ActorID    string
+	EntityType string
+	EntityID   string
+	From       *time.Time // Inclusive
+	To         *time.Time // Exclusive
+	Page       PageQuery
+}
+
+// Normalized checks the date range and clamps the page size
+func (q AuditLogQuery) Normalized() (AuditLogQuery, error) {
+	if q.From != nil && q.To != nil && !q.From.Before(*q.To) {
+		return q, ErrInvalidAuditLogQuery
+	}
+	q.Page = q.Page.Normalized()
+	return q, nil
+}
+
+type AuditLogRepository interface {
+	Record(ctx context.Context, log *AuditLog) error
+	List(ctx context.Context, q AuditLogQuery) (*Page[*AuditLog], error)
+}
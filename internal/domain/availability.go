@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrOutsideAvailability = errors.New("session is outside the coach's working hours at this branch")
+	ErrInvalidAvailability = errors.New("invalid availability window: use HH:MM times with start before end")
+)
+
+// AvailabilityWindow is a weekly slot in which a coach takes sessions at one branch.
+// Times are wall-clock HH:MM and compared in the schedule's own time zone.
+type AvailabilityWindow struct {
+	BranchID string       `json:"branch_id" bson:"branch_id"`
+	Weekday  time.Weekday `json:"weekday" bson:"weekday"` // 0 = Sunday
+	Start    string       `json:"start" bson:"start"`     // "07:00"
+	End      string       `json:"end" bson:"end"`         // "12:30"
+}
+
+// Minutes returns the window as minutes since midnight
+func (w AvailabilityWindow) Minutes() (start, end int, err error) {
+	s, err1 := time.Parse("15:04", w.Start)
+	e, err2 := time.Parse("15:04", w.End)
+	if err1 != nil || err2 != nil || w.Weekday < time.Sunday || w.Weekday > time.Saturday {
+		return 0, 0, ErrInvalidAvailability
+	}
+	start, end = s.Hour()*60+s.Minute(), e.Hour()*60+e.Minute()
+	if start >= end {
+		return 0, 0, ErrInvalidAvailability
+	}
+	return start, end, nil
+}
+
+// CoachAvailability holds a coach's weekly working hours across their branches.
+// A coach without any windows can be booked at any time.
+type CoachAvailability struct {
+	CoachID   string               `json:"coach_id" bson:"_id"`
+	TenantID  string               `json:"tenant_id" bson:"tenant_id"`
+	Windows   []AvailabilityWindow `json:"windows" bson:"windows"`
+	UpdatedAt time.Time            `json:"updated_at" bson:"updated_at"`
+}
+
+// Covers reports whether a session from start to end at branchID fits inside one window.
+// An empty branchID accepts a window at any branch, as online sessions need no floor space.
+func (a *CoachAvailability) Covers(branchID string, start, end time.Time) bool {
+	if len(a.Windows) == 0 {
+		return true
+	}
+	if end.YearDay() != start.YearDay() || end.Year() != start.Year() {
+		return false // Sessions don't span midnight
+	}
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+	for _, w := range a.Windows {
+		if (branchID != "" && w.BranchID != branchID) || w.Weekday != start.Weekday() {
+			continue
+		}
+		ws, we, err := w.Minutes()
+		if err == nil && from >= ws && to <= we {
+			return true
+		}
+	}
+	return false
+}
+
+// WeeklyMinutes sums the window lengths per branch
+func (a *CoachAvailability) WeeklyMinutes() map[string]int {
+	totals := make(map[string]int)
+	for _, w := range a.Windows {
+		if s, e, err := w.Minutes(); err == nil {
+			totals[w.BranchID] += e - s
+		}
+	}
+	return totals
+}
+
+// BranchUtilization is how much of a coach's time at one branch was booked in a period
+type BranchUtilization struct {
+	BranchID         string  `json:"branch_id"` // Empty for older sessions recorded without a branch
+	Sessions         int     `json:"sessions"`  // Excludes cancelled sessions
+	Completed        int     `json:"completed"`
+	NoShow           int     `json:"no_show"`
+	Cancelled        int     `json:"cancelled"`
+	BookedMinutes    int     `json:"booked_minutes"`
+	AvailableMinutes int     `json:"available_minutes"`     // 0 when no hours are set for the branch
+	Utilization      float64 `json:"utilization,omitempty"` // Booked / available, 0..1+
+}
+
+type CoachAvailabilityRepository interface {
+	// Get returns ErrNotFound when the coach has never set their hours
+	Get(ctx context.Context, coachID string) (*CoachAvailability, error)
+	Upsert(ctx context.Context, availability *CoachAvailability) error
+}
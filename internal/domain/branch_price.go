@@ -0,0 +1,40 @@
+package domain
+
+import "errors"
+
+var ErrInvalidBranchPrice = errors.New("branch prices need a branch, a non-negative amount and one price per branch, on packages sold at every branch")
+
+// BranchPrice is what a package costs at one branch, where it differs from the package price.
+// Franchises price the same package by location.
+type BranchPrice struct {
+	BranchID string `json:"branch_id" bson:"branch_id"`
+	Price    Money  `json:"price" bson:"price"`
+}
+
+// PriceAt returns the package's price at the branch, and whether it is the branch's own
+// price rather than the package's
+func (p *PTPackage) PriceAt(branchID string) (Money, bool) {
+	for _, bp := range p.BranchPrices {
+		if bp.BranchID == branchID {
+			return bp.Price, true
+		}
+	}
+	return p.Price, false
+}
+
+// NormalizeBranchPrices validates the overrides and sets their currencies as NewMoney does.
+// Only packages sold at every branch take them; a branch's own package has its own price.
+func (p *PTPackage) NormalizeBranchPrices() error {
+	if len(p.BranchPrices) > 0 && p.BranchID != "" {
+		return ErrInvalidBranchPrice
+	}
+	seen := make(map[string]bool, len(p.BranchPrices))
+	for i, bp := range p.BranchPrices {
+		if bp.BranchID == "" || bp.Price.Minor < 0 || seen[bp.BranchID] {
+			return ErrInvalidBranchPrice
+		}
+		seen[bp.BranchID] = true
+		p.BranchPrices[i].Price = NewMoney(bp.Price.Minor, bp.Price.Currency)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPTPackage_PriceAt(t *testing.T) {
+	pkg := &PTPackage{Price: NewMoney(2_500_000, "IDR"), BranchPrices: []BranchPrice{{BranchID: "jakarta", Price: NewMoney(3_000_000, "IDR")}}}
+
+	price, branchPriced := pkg.PriceAt("jakarta")
+	assert.Equal(t, NewMoney(3_000_000, "IDR"), price)
+	assert.True(t, branchPriced)
+
+	price, branchPriced = pkg.PriceAt("bandung")
+	assert.Equal(t, NewMoney(2_500_000, "IDR"), price)
+	assert.False(t, branchPriced)
+}
+
+func TestPTPackage_NormalizeBranchPrices(t *testing.T) {
+	pkg := &PTPackage{BranchPrices: []BranchPrice{{BranchID: "jakarta", Price: Money{Minor: 3_000_000}}}}
+	assert.NoError(t, pkg.NormalizeBranchPrices())
+	assert.Equal(t, "IDR", pkg.BranchPrices[0].Price.Currency)
+
+	for name, pkg := range map[string]*PTPackage{
+		"duplicate branch": {BranchPrices: []BranchPrice{{BranchID: "jakarta"}, {BranchID: "jakarta"}}},
+		"negative price":   {BranchPrices: []BranchPrice{{BranchID: "jakarta", Price: Money{Minor: -1}}}},
+		"missing branch":   {BranchPrices: []BranchPrice{{Price: Money{Minor: 1}}}},
+		"branch package":   {BranchID: "bandung", BranchPrices: []BranchPrice{{BranchID: "jakarta"}}},
+	} {
+		assert.ErrorIs(t, pkg.NormalizeBranchPrices(), ErrInvalidBranchPrice, name)
+	}
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "time"
+
+// Clock is the time source for services and jobs. Production code uses the system clock;
+// tests inject a fake so trial expiry, schedule windows and digest timing are deterministic.
+type Clock interface {
+	Now() time.Time
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidCreditType   = errors.New("invalid credit transaction type")
+	ErrInvalidCreditAmount = errors.New("credit amount must be greater than zero")
+	ErrInsufficientCredits = errors.New("contract does not have enough remaining sessions")
+	ErrDuplicateCredit     = errors.New("credit transaction already recorded")
+)
+
+// Credit Transaction Types
+const (
+	CreditTypeOpening   = "opening"   // Balance carried over from the legacy remaining_sessions counter
+	CreditTypePurchased = "purchased" // Sessions bought with the contract
+	CreditTypeConsumed  = "consumed"  // Session completed (linked to a schedule)
+	CreditTypeRefunded  = "refunded"  // Session credit returned to the contract
+	CreditTypeFrozen    = "frozen"    // Credits held back (e.g., membership pause)
+	CreditTypeUnfrozen  = "unfrozen"  // Frozen credits released
+	CreditTypeExpired   = "expired"   // Credits lost at contract expiry
+	CreditTypeAdjusted  = "adjusted"  // Manual correction by an admin (positive or negative)
+)
+
+// CreditTypeSign returns +1 for types that add credits, -1 for types that remove them,
+// and 0 for types whose direction is carried by the amount itself (adjusted).
+func CreditTypeSign(creditType string) (int, bool) {
+	switch creditType {
+	case CreditTypeOpening, CreditTypePurchased, CreditTypeRefunded, CreditTypeUnfrozen:
+		return 1, true
+	case CreditTypeConsumed, CreditTypeFrozen, CreditTypeExpired:
+		return -1, true
+	case CreditTypeAdjusted:
+		return 0, true
+	}
+	return 0, false
+}
+
+// CreditTransaction is a single, immutable entry in a contract's session credit ledger.
+// The ledger is append-only: entries are never updated, and a contract's balance is the
+// BalanceAfter of its highest Sequence. PTContract.RemainingSessions is a projection of it.
+type CreditTransaction struct {
+	ID             string    `json:"id" bson:"_id,omitempty"`
+	TenantID       string    `json:"tenant_id" bson:"tenant_id"`
+	ContractID     string    `json:"contract_id" bson:"contract_id"`
+	MemberID       string    `json:"member_id" bson:"member_id"`
+	Sequence       int64     `json:"sequence" bson:"sequence"` // Per-contract, gapless; unique with contract_id
+	Type           string    `json:"type" bson:"type"`
+	Amount         int       `json:"amount" bson:"amount"`                               // Signed: +10 purchased, -1 consumed
+	BalanceAfter   int       `json:"balance_after" bson:"balance_after"`                 // Running balance after this entry
+	ScheduleID     string    `json:"schedule_id,omitempty" bson:"schedule_id,omitempty"` // For consumed/refunded sessions
+	Note           string    `json:"note,omitempty" bson:"note,omitempty"`               // Reason shown on the statement
+	ActorID        string    `json:"actor_id,omitempty" bson:"actor_id,omitempty"`       // User who caused the movement
+	IdempotencyKey string    `json:"-" bson:"idempotency_key,omitempty"`                 // e.g. "consumed:<schedule_id>"; unique
+	CreatedAt      time.Time `json:"created_at" bson:"created_at"`
+}
+
+// ContractStatement is a member-facing ledger of a contract's credit movements
+type ContractStatement struct {
+	Contract     *PTContract          `json:"contract"`
+	Transactions []*CreditTransaction `json:"transactions"`
+	Summary      StatementSummary     `json:"summary"`
+}
+
+// StatementSummary totals each movement type as a positive count
+type StatementSummary struct {
+	Opening   int `json:"opening"`
+	Purchased int `json:"purchased"`
+	Consumed  int `json:"consumed"`
+	Refunded  int `json:"refunded"`
+	Frozen    int `json:"frozen"`
+	Unfrozen  int `json:"unfrozen"`
+	Expired   int `json:"expired"`
+	Adjusted  int `json:"adjusted"` // Net manual adjustments (may be negative)
+	Balance   int `json:"balance"`
+}
+
+// BuildContractStatement totals a contract's ledger entries (oldest first)
+func BuildContractStatement(contract *PTContract, txns []*CreditTransaction) *ContractStatement {
+	if txns == nil {
+		txns = []*CreditTransaction{}
+	}
+	statement := &ContractStatement{Contract: contract, Transactions: txns}
+
+	s := &statement.Summary
+	for _, t := range txns {
+		switch t.Type {
+		case CreditTypeOpening:
+			s.Opening += t.Amount
+		case CreditTypePurchased:
+			s.Purchased += t.Amount
+		case CreditTypeConsumed:
+			s.Consumed -= t.Amount
+		case CreditTypeRefunded:
+			s.Refunded += t.Amount
+		case CreditTypeFrozen:
+			s.Frozen -= t.Amount
+		case CreditTypeUnfrozen:
+			s.Unfrozen += t.Amount
+		case CreditTypeExpired:
+			s.Expired -= t.Amount
+		case CreditTypeAdjusted:
+			s.Adjusted += t.Amount
+		}
+	}
+	if len(txns) > 0 {
+		s.Balance = txns[len(txns)-1].BalanceAfter
+	}
+	return statement
+}
+
+type CreditTransactionRepository interface {
+	// Append assigns the next Sequence and BalanceAfter and inserts the entry. Concurrent appends
+	// to the same contract are serialized by the unique (contract_id, sequence) index.
+	// Returns ErrInsufficientCredits if the balance would go negative and ErrDuplicateCredit
+	// if the IdempotencyKey has already been used.
+	Append(ctx context.Context, txn *CreditTransaction) error
+	// GetLatest returns the entry with the highest sequence, or nil if the ledger is empty
+	GetLatest(ctx context.Context, contractID string) (*CreditTransaction, error)
+	// GetByContract returns a contract's transactions, oldest first
+	GetByContract(ctx context.Context, contractID string) ([]*CreditTransaction, error)
+}
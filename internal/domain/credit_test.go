@@ -0,0 +1,63 @@
+package domain
+
+import "testing"
+
+func TestBuildContractStatement(t *testing.T) {
+	tests := []struct {
+		name          string
+		txns          []*CreditTransaction
+		wantBalance   int
+		wantConsumed  int
+		wantPurchased int
+		wantOpening   int
+	}{
+		{
+			name:        "empty ledger",
+			wantBalance: 0,
+		},
+		{
+			name: "purchase and consumption",
+			txns: []*CreditTransaction{
+				{Sequence: 1, Type: CreditTypePurchased, Amount: 10, BalanceAfter: 10},
+				{Sequence: 2, Type: CreditTypeConsumed, Amount: -1, BalanceAfter: 9},
+				{Sequence: 3, Type: CreditTypeConsumed, Amount: -1, BalanceAfter: 8},
+			},
+			wantBalance:   8,
+			wantConsumed:  2,
+			wantPurchased: 10,
+		},
+		{
+			name: "migrated contract with refund",
+			txns: []*CreditTransaction{
+				{Sequence: 1, Type: CreditTypeOpening, Amount: 5, BalanceAfter: 5},
+				{Sequence: 2, Type: CreditTypeConsumed, Amount: -1, BalanceAfter: 4},
+				{Sequence: 3, Type: CreditTypeRefunded, Amount: 1, BalanceAfter: 5},
+			},
+			wantBalance:  5,
+			wantConsumed: 1,
+			wantOpening:  5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildContractStatement(&PTContract{ID: "c1"}, tt.txns)
+
+			if got.Summary.Balance != tt.wantBalance {
+				t.Errorf("Balance = %d, want %d", got.Summary.Balance, tt.wantBalance)
+			}
+			if got.Summary.Consumed != tt.wantConsumed {
+				t.Errorf("Consumed = %d, want %d", got.Summary.Consumed, tt.wantConsumed)
+			}
+			if got.Summary.Purchased != tt.wantPurchased {
+				t.Errorf("Purchased = %d, want %d", got.Summary.Purchased, tt.wantPurchased)
+			}
+			if got.Summary.Opening != tt.wantOpening {
+				t.Errorf("Opening = %d, want %d", got.Summary.Opening, tt.wantOpening)
+			}
+			if got.Transactions == nil {
+				t.Error("Transactions should never be nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidCustomFieldSchema = errors.New("invalid custom field schema")
+	ErrInvalidCustomFields      = errors.New("invalid custom fields")
+)
+
+// What custom fields can be defined on
+const (
+	CustomFieldEntityMember   = "member"
+	CustomFieldEntityContract = "contract"
+)
+
+// Custom field types
+const (
+	CustomFieldText    = "text"
+	CustomFieldNumber  = "number"
+	CustomFieldDate    = "date" // "2006-01-02"
+	CustomFieldBoolean = "boolean"
+	CustomFieldSelect  = "select" // One of the field's options
+)
+
+// Limits on the custom field schema
+const (
+	MaxCustomFields            = 30 // Per entity
+	MaxCustomFieldLabelLength  = 100
+	MaxCustomFieldOptions      = 50
+	MaxCustomFieldOptionLength = 100
+	MaxCustomFieldTextLength   = 500 // Longest text value, and a text field's default MaxLength
+)
+
+var customFieldKey = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+
+// CustomField is one field a tenant tracks on its members or contracts, e.g. a locker
+// number or an insurance ID
+type CustomField struct {
+	Key      string `bson:"key" json:"key"` // e.g. "locker_number"; the column in exports
+	Label    string `bson:"label" json:"label"`
+	Entity   string `bson:"entity" json:"entity"` // CustomFieldEntityMember or CustomFieldEntityContract
+	Type     string `bson:"type" json:"type"`
+	Required bool   `bson:"required,omitempty" json:"required,omitempty"`
+
+	Options   []string `bson:"options,omitempty" json:"options,omitempty"`       // Select fields
+	MaxLength int      `bson:"max_length,omitempty" json:"max_length,omitempty"` // Text fields; 0 is MaxCustomFieldTextLength
+	Min       *float64 `bson:"min,omitempty" json:"min,omitempty"`               // Number fields
+	Max       *float64 `bson:"max,omitempty" json:"max,omitempty"`
+}
+
+// CustomFieldSchema is a tenant's custom fields, in the order forms and exports show them
+type CustomFieldSchema []CustomField
+
+// CustomFieldValues maps a custom field's key to its value: a string for text, date and
+// select fields, a float64 for numbers and a bool for booleans
+type CustomFieldValues map[string]interface{}
+
+// Validate checks each field's key, type and limits, and that keys are unique per entity
+func (s CustomFieldSchema) Validate() error {
+	seen := make(map[string]bool)
+	count := make(map[string]int)
+	for _, f := range s {
+		if err := f.validate(); err != nil {
+			return err
+		}
+		if seen[f.Entity+"."+f.Key] {
+			return fmt.Errorf("%w: %s field %s is defined twice", ErrInvalidCustomFieldSchema, f.Entity, f.Key)
+		}
+		seen[f.Entity+"."+f.Key] = true
+		if count[f.Entity]++; count[f.Entity] > MaxCustomFields {
+			return fmt.Errorf("%w: more than %d %s fields", ErrInvalidCustomFieldSchema, MaxCustomFields, f.Entity)
+		}
+	}
+	return nil
+}
+
+func (f CustomField) validate() error {
+	if !customFieldKey.MatchString(f.Key) {
+		return fmt.Errorf("%w: key %q must be lowercase letters, digits and underscores, starting with a letter", ErrInvalidCustomFieldSchema, f.Key)
+	}
+	if strings.TrimSpace(f.Label) == "" || utf8.RuneCountInString(f.Label) > MaxCustomFieldLabelLength {
+		return fmt.Errorf("%w: %s label must be 1 to %d characters", ErrInvalidCustomFieldSchema, f.Key, MaxCustomFieldLabelLength)
+	}
+	if f.Entity != CustomFieldEntityMember && f.Entity != CustomFieldEntityContract {
+		return fmt.Errorf("%w: %s entity must be member or contract", ErrInvalidCustomFieldSchema, f.Key)
+	}
+	switch f.Type {
+	case CustomFieldText:
+		if f.MaxLength < 0 || f.MaxLength > MaxCustomFieldTextLength {
+			return fmt.Errorf("%w: %s max_length must be 0 to %d", ErrInvalidCustomFieldSchema, f.Key, MaxCustomFieldTextLength)
+		}
+	case CustomFieldNumber:
+		if f.Min != nil && f.Max != nil && *f.Min > *f.Max {
+			return fmt.Errorf("%w: %s min is above max", ErrInvalidCustomFieldSchema, f.Key)
+		}
+	case CustomFieldSelect:
+		if len(f.Options) == 0 || len(f.Options) > MaxCustomFieldOptions {
+			return fmt.Errorf("%w: %s needs 1 to %d options", ErrInvalidCustomFieldSchema, f.Key, MaxCustomFieldOptions)
+		}
+		for i, opt := range f.Options {
+			if strings.TrimSpace(opt) == "" || utf8.RuneCountInString(opt) > MaxCustomFieldOptionLength {
+				return fmt.Errorf("%w: %s options must be 1 to %d characters", ErrInvalidCustomFieldSchema, f.Key, MaxCustomFieldOptionLength)
+			}
+			if slices.Contains(f.Options[:i], opt) {
+				return fmt.Errorf("%w: %s option %q is listed twice", ErrInvalidCustomFieldSchema, f.Key, opt)
+			}
+		}
+	case CustomFieldDate, CustomFieldBoolean:
+	default:
+		return fmt.Errorf("%w: %s type must be text, number, date, boolean or select", ErrInvalidCustomFieldSchema, f.Key)
+	}
+	return nil
+}
+
+// For returns the fields of the entity, in order
+func (s CustomFieldSchema) For(entity string) []CustomField {
+	var fields []CustomField
+	for _, f := range s {
+		if f.Entity == entity {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Apply returns the entity's values after the changes. A null or empty value clears the
+// field. Values of fields no longer in the schema are dropped, and every required field
+// must end up with a value.
+func (s CustomFieldSchema) Apply(entity string, current CustomFieldValues, changes map[string]interface{}) (CustomFieldValues, error) {
+	fields := s.For(entity)
+	values := make(CustomFieldValues)
+	for _, f := range fields {
+		if v, ok := current[f.Key]; ok {
+			values[f.Key] = v
+		}
+	}
+	for key, raw := range changes {
+		i := slices.IndexFunc(fields, func(f CustomField) bool { return f.Key == key })
+		if i < 0 {
+			return nil, fmt.Errorf("%w: no %s field %s", ErrInvalidCustomFields, entity, key)
+		}
+		v, err := fields[i].normalize(raw)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			delete(values, key)
+		} else {
+			values[key] = v
+		}
+	}
+	for _, f := range fields {
+		if _, ok := values[f.Key]; f.Required && !ok {
+			return nil, fmt.Errorf("%w: %s is required", ErrInvalidCustomFields, f.Key)
+		}
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return values, nil
+}
+
+// normalize checks the value against the field, returning nil for an empty value
+func (f CustomField) normalize(raw interface{}) (interface{}, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	if s, ok := raw.(string); ok {
+		raw = strings.TrimSpace(s)
+		if raw == "" {
+			return nil, nil
+		}
+	}
+	invalid := func(want string) error {
+		return fmt.Errorf("%w: %s must be %s", ErrInvalidCustomFields, f.Key, want)
+	}
+
+	switch f.Type {
+	case CustomFieldText:
+		s, ok := raw.(string)
+		limit := f.MaxLength
+		if limit == 0 {
+			limit = MaxCustomFieldTextLength
+		}
+		if !ok || utf8.RuneCountInString(s) > limit {
+			return nil, invalid(fmt.Sprintf("text of at most %d characters", limit))
+		}
+		return s, nil
+	case CustomFieldNumber:
+		var n float64
+		switch v := raw.(type) {
+		case float64:
+			n = v
+		case int:
+			n = float64(v)
+		case int32:
+			n = float64(v)
+		case int64:
+			n = float64(v)
+		default:
+			return nil, invalid("a number")
+		}
+		if f.Min != nil && n < *f.Min {
+			return nil, invalid(fmt.Sprintf("at least %s", FormatCustomField(*f.Min)))
+		}
+		if f.Max != nil && n > *f.Max {
+			return nil, invalid(fmt.Sprintf("at most %s", FormatCustomField(*f.Max)))
+		}
+		return n, nil
+	case CustomFieldDate:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, invalid("a date (YYYY-MM-DD)")
+		}
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			return nil, invalid("a date (YYYY-MM-DD)")
+		}
+		return s, nil
+	case CustomFieldBoolean:
+		b, ok := raw.(bool)
+		if !ok {
+			return nil, invalid("true or false")
+		}
+		return b, nil
+	case CustomFieldSelect:
+		s, ok := raw.(string)
+		if !ok || !slices.Contains(f.Options, s) {
+			return nil, invalid("one of " + strings.Join(f.Options, ", "))
+		}
+		return s, nil
+	}
+	return nil, invalid("a known type")
+}
+
+// FormatCustomField renders a custom field value for a CSV cell
+func FormatCustomField(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomFieldSchema_Validate(t *testing.T) {
+	five := 5.0
+	one := 1.0
+	locker := CustomField{Key: "locker_number", Label: "Locker", Entity: CustomFieldEntityMember, Type: CustomFieldText}
+
+	assert.NoError(t, CustomFieldSchema{
+		locker,
+		{Key: "locker_number", Label: "Locker", Entity: CustomFieldEntityContract, Type: CustomFieldNumber, Min: &one, Max: &five},
+		{Key: "plan", Label: "Plan", Entity: CustomFieldEntityContract, Type: CustomFieldSelect, Options: []string{"gold", "silver"}},
+	}.Validate(), "the same key on different entities")
+
+	for name, schema := range map[string]CustomFieldSchema{
+		"bad key":          {{Key: "Locker Number", Label: "Locker", Entity: CustomFieldEntityMember, Type: CustomFieldText}},
+		"no label":         {{Key: "locker", Label: " ", Entity: CustomFieldEntityMember, Type: CustomFieldText}},
+		"unknown entity":   {{Key: "locker", Label: "Locker", Entity: "branch", Type: CustomFieldText}},
+		"unknown type":     {{Key: "locker", Label: "Locker", Entity: CustomFieldEntityMember, Type: "file"}},
+		"duplicate key":    {locker, locker},
+		"select no option": {{Key: "plan", Label: "Plan", Entity: CustomFieldEntityContract, Type: CustomFieldSelect}},
+		"repeated option":  {{Key: "plan", Label: "Plan", Entity: CustomFieldEntityContract, Type: CustomFieldSelect, Options: []string{"gold", "gold"}}},
+		"min above max":    {{Key: "age", Label: "Age", Entity: CustomFieldEntityMember, Type: CustomFieldNumber, Min: &five, Max: &one}},
+		"text too long":    {{Key: "note", Label: "Note", Entity: CustomFieldEntityMember, Type: CustomFieldText, MaxLength: MaxCustomFieldTextLength + 1}},
+	} {
+		assert.ErrorIs(t, schema.Validate(), ErrInvalidCustomFieldSchema, name)
+	}
+}
+
+func TestCustomFieldSchema_Apply(t *testing.T) {
+	zero := 0.0
+	schema := CustomFieldSchema{
+		{Key: "insurance_id", Label: "Insurance ID", Entity: CustomFieldEntityMember, Type: CustomFieldText, Required: true, MaxLength: 8},
+		{Key: "locker", Label: "Locker", Entity: CustomFieldEntityMember, Type: CustomFieldNumber, Min: &zero},
+		{Key: "joined", Label: "Joined", Entity: CustomFieldEntityMember, Type: CustomFieldDate},
+		{Key: "vip", Label: "VIP", Entity: CustomFieldEntityMember, Type: CustomFieldBoolean},
+		{Key: "plan", Label: "Plan", Entity: CustomFieldEntityContract, Type: CustomFieldSelect, Options: []string{"gold", "silver"}},
+	}
+
+	t.Run("checks and merges the changes", func(t *testing.T) {
+		values, err := schema.Apply(CustomFieldEntityMember, CustomFieldValues{"insurance_id": "AX-1", "locker": 3.0, "retired": "x"},
+			map[string]interface{}{"locker": nil, "joined": "2026-01-31", "vip": true, "insurance_id": " AX-2 "})
+
+		require.NoError(t, err)
+		assert.Equal(t, CustomFieldValues{"insurance_id": "AX-2", "joined": "2026-01-31", "vip": true}, values, "drops cleared and retired fields")
+	})
+
+	t.Run("requires required fields", func(t *testing.T) {
+		_, err := schema.Apply(CustomFieldEntityMember, nil, map[string]interface{}{"locker": 4.0})
+		assert.ErrorIs(t, err, ErrInvalidCustomFields)
+
+		_, err = schema.Apply(CustomFieldEntityMember, CustomFieldValues{"insurance_id": "AX-1"}, map[string]interface{}{"insurance_id": ""})
+		assert.ErrorIs(t, err, ErrInvalidCustomFields)
+	})
+
+	t.Run("rejects values of the wrong type", func(t *testing.T) {
+		current := CustomFieldValues{"insurance_id": "AX-1"}
+		for name, changes := range map[string]map[string]interface{}{
+			"unknown field":  {"shoe_size": 42.0},
+			"other entity":   {"plan": "gold"},
+			"text too long":  {"insurance_id": "AX-123456"},
+			"number as text": {"locker": "12"},
+			"below min":      {"locker": -1.0},
+			"bad date":       {"joined": "31/01/2026"},
+			"boolean text":   {"vip": "yes"},
+		} {
+			_, err := schema.Apply(CustomFieldEntityMember, current, changes)
+			assert.ErrorIs(t, err, ErrInvalidCustomFields, name)
+		}
+		_, err := schema.Apply(CustomFieldEntityContract, nil, map[string]interface{}{"plan": "bronze"})
+		assert.ErrorIs(t, err, ErrInvalidCustomFields, "not an option")
+	})
+
+	t.Run("returns nil without values", func(t *testing.T) {
+		values, err := schema.Apply(CustomFieldEntityContract, CustomFieldValues{"plan": "gold"}, map[string]interface{}{"plan": nil})
+
+		require.NoError(t, err)
+		assert.Nil(t, values)
+	})
+}
+
+func TestFormatCustomField(t *testing.T) {
+	assert.Equal(t, "", FormatCustomField(nil))
+	assert.Equal(t, "12", FormatCustomField(12.0))
+	assert.Equal(t, "1.5", FormatCustomField(1.5))
+	assert.Equal(t, "true", FormatCustomField(true))
+	assert.Equal(t, "2026-01-31", FormatCustomField("2026-01-31"))
+}
+
+func TestUser_CustomFields(t *testing.T) {
+	user := &User{TenantID: "gym", CustomFields: CustomFieldValues{"locker": 1.0},
+		Memberships: []TenantMembership{{TenantID: "studio", CustomFields: CustomFieldValues{"locker": 2.0}}}}
+
+	require.NoError(t, user.SetCustomFields("studio", CustomFieldValues{"locker": 3.0}))
+	assert.Equal(t, CustomFieldValues{"locker": 1.0}, user.CustomFieldsIn("gym"))
+	assert.Equal(t, CustomFieldValues{"locker": 3.0}, user.CustomFieldsIn("studio"))
+	assert.ErrorIs(t, user.SetCustomFields("other", nil), ErrNotTenantMember)
+
+	scoped, err := user.ScopedTo("studio")
+	require.NoError(t, err)
+	assert.Equal(t, CustomFieldValues{"locker": 3.0}, scoped.CustomFields)
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+var ErrInvalidDashboardLayout = errors.New("widgets must be known types listed at most once, with the low threshold below the high one")
+
+// Widgets of the tenant-admin dashboard
+const (
+	WidgetRevenue           = "revenue"            // Payments collected this month, in minor units
+	WidgetActiveContracts   = "active_contracts"   // Contracts with sessions left
+	WidgetSalesConversion   = "sales_conversion"   // Percent of members who viewed a package and paid, last 30 days
+	WidgetOpenSubstitutions = "open_substitutions" // Sessions waiting for a cover coach
+	WidgetLeaderboard       = "leaderboard"        // Best progress score of last week, with the top five
+	WidgetCoachesOnline     = "coaches_online"     // Coaches with the app open, with the presence board
+)
+
+// DashboardWidgetTypes lists every widget, in the default order
+var DashboardWidgetTypes = []string{WidgetRevenue, WidgetActiveContracts, WidgetSalesConversion, WidgetOpenSubstitutions, WidgetLeaderboard, WidgetCoachesOnline}
+
+// WidgetThreshold flags a widget whose value leaves the range the owner cares about
+type WidgetThreshold struct {
+	Low  *float64 `json:"low,omitempty" bson:"low,omitempty"`   // Alert below this
+	High *float64 `json:"high,omitempty" bson:"high,omitempty"` // Alert above this
+}
+
+// Breached reports whether value is outside the threshold
+func (t *WidgetThreshold) Breached(value float64) bool {
+	return t != nil && ((t.Low != nil && value < *t.Low) || (t.High != nil && value > *t.High))
+}
+
+// DashboardWidget is one card of the dashboard
+type DashboardWidget struct {
+	Type      string           `json:"type" bson:"type"`
+	Threshold *WidgetThreshold `json:"threshold,omitempty" bson:"threshold,omitempty"`
+}
+
+// DashboardLayout is a tenant admin's choice of cards, in display order. Widgets left out
+// are hidden.
+type DashboardLayout struct {
+	ID        string            `json:"-" bson:"_id"`
+	TenantID  string            `json:"tenant_id" bson:"tenant_id"`
+	UserID    string            `json:"user_id" bson:"user_id"`
+	Widgets   []DashboardWidget `json:"widgets" bson:"widgets"`
+	UpdatedAt time.Time         `json:"updated_at,omitempty" bson:"updated_at"`
+}
+
+// DefaultDashboardLayout shows every widget without thresholds
+func DefaultDashboardLayout(tenantID, userID string) *DashboardLayout {
+	layout := &DashboardLayout{TenantID: tenantID, UserID: userID, Widgets: make([]DashboardWidget, len(DashboardWidgetTypes))}
+	for i, kind := range DashboardWidgetTypes {
+		layout.Widgets[i] = DashboardWidget{Type: kind}
+	}
+	return layout
+}
+
+// Validate checks every widget is known, listed once, and has a sensible threshold
+func (l *DashboardLayout) Validate() error {
+	seen := make(map[string]bool, len(l.Widgets))
+	for _, w := range l.Widgets {
+		if !slices.Contains(DashboardWidgetTypes, w.Type) || seen[w.Type] {
+			return ErrInvalidDashboardLayout
+		}
+		seen[w.Type] = true
+		if t := w.Threshold; t != nil && t.Low != nil && t.High != nil && *t.Low >= *t.High {
+			return ErrInvalidDashboardLayout
+		}
+	}
+	return nil
+}
+
+// DashboardLayoutRepository stores one layout per tenant admin and tenant
+type DashboardLayoutRepository interface {
+	// Get returns ErrNotFound when the user never saved a layout in the tenant
+	Get(ctx context.Context, tenantID, userID string) (*DashboardLayout, error)
+	Upsert(ctx context.Context, layout *DashboardLayout) error
+}
+
+// WidgetValue is a widget filled in for the dashboard. A widget whose data failed to load
+// carries the error instead, so one source being down doesn't blank the dashboard.
+type WidgetValue struct {
+	DashboardWidget
+	Value float64     `json:"value"`
+	Alert bool        `json:"alert"`          // Value breaches the threshold
+	Data  interface{} `json:"data,omitempty"` // Detail for the card, e.g. the leaderboard entries
+	Error string      `json:"error,omitempty"`
+}
+
+// TenantDashboard is the dashboard payload: the layout and its widgets' values, in order
+type TenantDashboard struct {
+	Layout  *DashboardLayout `json:"layout"`
+	Widgets []WidgetValue    `json:"widgets"`
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashboardLayout_Validate(t *testing.T) {
+	low, high := 10.0, 5.0
+	assert.NoError(t, DefaultDashboardLayout("gym", "admin-1").Validate())
+	assert.NoError(t, (&DashboardLayout{Widgets: []DashboardWidget{}}).Validate()) // Everything hidden
+
+	for name, widgets := range map[string][]DashboardWidget{
+		"unknown type":       {{Type: "weather"}},
+		"listed twice":       {{Type: WidgetRevenue}, {Type: WidgetRevenue}},
+		"inverted threshold": {{Type: WidgetRevenue, Threshold: &WidgetThreshold{Low: &low, High: &high}}},
+	} {
+		assert.ErrorIs(t, (&DashboardLayout{Widgets: widgets}).Validate(), ErrInvalidDashboardLayout, name)
+	}
+}
+
+func TestWidgetThreshold_Breached(t *testing.T) {
+	low, high := 5.0, 10.0
+	threshold := &WidgetThreshold{Low: &low, High: &high}
+
+	assert.True(t, threshold.Breached(4))
+	assert.False(t, threshold.Breached(5))
+	assert.True(t, threshold.Breached(11))
+	assert.False(t, (*WidgetThreshold)(nil).Breached(100))
+}
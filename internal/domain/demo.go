@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrDemoDataExists = errors.New("tenant already has demo data; delete it before seeding again")
+
+// DemoDataset is a generated, self-contained set of demo records for one tenant.
+// IDs are pre-assigned (ObjectID hex) so records can reference each other before saving.
+type DemoDataset struct {
+	Branches         []*Branch
+	Users            []*User
+	Packages         []*PTPackage
+	Contracts        []*PTContract
+	Credits          []*CreditTransaction
+	Schedules        []*Schedule
+	WorkoutSessions  []*WorkoutSession
+	PlannedExercises []*PlannedExercise
+	SetLogs          []*SetLogDocument
+	DailyVolumes     []*DailyVolume
+	PersonalBests    []*PersonalBest
+	Scans            []*InBodyRecord
+	Invoices         []*Invoice
+}
+
+// DemoSummary counts the records created or deleted for a demo tenant
+type DemoSummary struct {
+	Coaches   int   `json:"coaches"`
+	Members   int   `json:"members"`
+	Contracts int   `json:"contracts"`
+	Schedules int   `json:"schedules"`
+	SetLogs   int   `json:"set_logs"`
+	Scans     int   `json:"scans"`
+	Invoices  int   `json:"invoices"`
+	Deleted   int64 `json:"deleted,omitempty"`
+}
+
+// DemoDataRepository stores demo records tagged with their tenant (demo: true, demo_tenant_id)
+// so they can be told apart from real data and removed in bulk.
+type DemoDataRepository interface {
+	Save(ctx context.Context, tenantID string, dataset *DemoDataset) error
+	HasDemoData(ctx context.Context, tenantID string) (bool, error)
+	// DeleteAll removes every record tagged as demo data for the tenant and returns the count
+	DeleteAll(ctx context.Context, tenantID string) (int64, error)
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Device platforms a push token can come from
+const (
+	PlatformAndroid = "android"
+	PlatformIOS     = "ios"
+	PlatformWeb     = "web"
+)
+
+// MaxDeviceTokensPerUser bounds the devices a user receives pushes on; registering another
+// drops the one seen longest ago
+const MaxDeviceTokensPerUser = 10
+
+var ErrInvalidDeviceToken = errors.New("device token and a platform of android, ios or web are required")
+
+// DeviceToken is a Firebase Cloud Messaging registration token of one of a user's devices.
+// A token belongs to the user who registered it last: a shared tablet follows whoever
+// signed in.
+type DeviceToken struct {
+	Token      string    `json:"token" bson:"_id"`
+	UserID     string    `json:"user_id" bson:"user_id"`
+	Platform   string    `json:"platform" bson:"platform"`
+	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at" bson:"last_seen_at"` // Last registered; apps register on every start
+}
+
+// Validate checks the token and platform the app sent
+func (t *DeviceToken) Validate() error {
+	if t.Token == "" || len(t.Token) > 4096 {
+		return ErrInvalidDeviceToken
+	}
+	switch t.Platform {
+	case PlatformAndroid, PlatformIOS, PlatformWeb:
+		return nil
+	}
+	return ErrInvalidDeviceToken
+}
+
+type DeviceTokenRepository interface {
+	// Register saves the token for its user, moving it from any other user, and keeps the
+	// user's MaxDeviceTokensPerUser most recently seen tokens
+	Register(ctx context.Context, token *DeviceToken) error
+	// Unregister removes the user's token; unknown tokens are ignored
+	Unregister(ctx context.Context, userID, token string) error
+	ListByUser(ctx context.Context, userID string) ([]*DeviceToken, error)
+	// Delete removes tokens the push provider no longer accepts, whoever they belong to
+	Delete(ctx context.Context, tokens []string) error
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrDocumentNotFound          = errors.New("document not found")
+	ErrRequiredDocumentsUnsigned = errors.New("member has not signed all required documents")
+)
+
+// Document is a waiver or policy a tenant asks its members to accept
+type Document struct {
+	ID            string    `json:"id" bson:"_id,omitempty"`
+	TenantID      string    `json:"tenant_id" bson:"tenant_id"`
+	Title         string    `json:"title" bson:"title"`
+	Description   string    `json:"description,omitempty" bson:"description,omitempty"`
+	FileURL       string    `json:"file_url,omitempty" bson:"file_url,omitempty"` // Uploaded waiver (PDF/image)
+	Required      bool      `json:"required" bson:"required"`                     // Shown as outstanding until accepted
+	BlocksBooking bool      `json:"blocks_booking" bson:"blocks_booking"`         // If required and unsigned, sessions cannot be booked
+	Active        bool      `json:"active" bson:"active"`                         // Inactive documents are hidden from members
+	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// DocumentAcceptance records a member accepting a Document
+type DocumentAcceptance struct {
+	ID         string    `json:"id" bson:"_id,omitempty"`
+	TenantID   string    `json:"tenant_id" bson:"tenant_id"`
+	DocumentID string    `json:"document_id" bson:"document_id"`
+	MemberID   string    `json:"member_id" bson:"member_id"`
+	TypedName  string    `json:"typed_name,omitempty" bson:"typed_name,omitempty"`
+	IPAddress  string    `json:"ip_address" bson:"ip_address"`
+	UserAgent  string    `json:"user_agent,omitempty" bson:"user_agent,omitempty"`
+	AcceptedAt time.Time `json:"accepted_at" bson:"accepted_at"`
+}
+
+// DocumentStatus is a member's compliance status for a single document
+type DocumentStatus struct {
+	Document   *Document  `json:"document"`
+	Accepted   bool       `json:"accepted"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+}
+
+// DocumentCompliance summarizes a member's document status (used on client profiles)
+type DocumentCompliance struct {
+	Compliant      bool              `json:"compliant"`       // All required documents accepted
+	BookingBlocked bool              `json:"booking_blocked"` // A booking-blocking document is outstanding
+	Outstanding    int               `json:"outstanding"`     // Required documents not yet accepted
+	Documents      []*DocumentStatus `json:"documents"`
+}
+
+// BuildDocumentCompliance matches a member's acceptances against the tenant's active documents.
+// Only required documents count towards Outstanding and BookingBlocked.
+func BuildDocumentCompliance(docs []*Document, acceptances []*DocumentAcceptance) *DocumentCompliance {
+	accepted := make(map[string]time.Time, len(acceptances))
+	for _, a := range acceptances {
+		accepted[a.DocumentID] = a.AcceptedAt
+	}
+
+	compliance := &DocumentCompliance{Documents: make([]*DocumentStatus, 0, len(docs))}
+	for _, doc := range docs {
+		status := &DocumentStatus{Document: doc}
+		if at, ok := accepted[doc.ID]; ok {
+			acceptedAt := at
+			status.Accepted = true
+			status.AcceptedAt = &acceptedAt
+		} else if doc.Required {
+			compliance.Outstanding++
+			if doc.BlocksBooking {
+				compliance.BookingBlocked = true
+			}
+		}
+		compliance.Documents = append(compliance.Documents, status)
+	}
+	compliance.Compliant = compliance.Outstanding == 0
+	return compliance
+}
+
+type DocumentRepository interface {
+	Create(ctx context.Context, doc *Document) error
+	GetByID(ctx context.Context, id string) (*Document, error)
+	GetByTenant(ctx context.Context, tenantID string, activeOnly bool) ([]*Document, error)
+	Update(ctx context.Context, doc *Document) error
+}
+
+type DocumentAcceptanceRepository interface {
+	// Upsert records an acceptance, replacing any earlier one for the same document and member
+	Upsert(ctx context.Context, acceptance *DocumentAcceptance) error
+	GetByMember(ctx context.Context, memberID string) ([]*DocumentAcceptance, error)
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildDocumentCompliance(t *testing.T) {
+	waiver := &Document{ID: "waiver", Required: true, BlocksBooking: true}
+	policy := &Document{ID: "policy", Required: true}
+	optional := &Document{ID: "newsletter"}
+	signed := &DocumentAcceptance{DocumentID: "waiver", AcceptedAt: time.Now()}
+
+	tests := []struct {
+		name            string
+		docs            []*Document
+		acceptances     []*DocumentAcceptance
+		wantCompliant   bool
+		wantBlocked     bool
+		wantOutstanding int
+	}{
+		{
+			name:          "no documents",
+			wantCompliant: true,
+		},
+		{
+			name:            "unsigned blocking waiver",
+			docs:            []*Document{waiver, optional},
+			wantCompliant:   false,
+			wantBlocked:     true,
+			wantOutstanding: 1,
+		},
+		{
+			name:          "signed waiver, optional outstanding",
+			docs:          []*Document{waiver, optional},
+			acceptances:   []*DocumentAcceptance{signed},
+			wantCompliant: true,
+		},
+		{
+			name:            "required but non-blocking outstanding",
+			docs:            []*Document{waiver, policy},
+			acceptances:     []*DocumentAcceptance{signed},
+			wantCompliant:   false,
+			wantBlocked:     false,
+			wantOutstanding: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildDocumentCompliance(tt.docs, tt.acceptances)
+
+			if got.Compliant != tt.wantCompliant {
+				t.Errorf("Compliant = %v, want %v", got.Compliant, tt.wantCompliant)
+			}
+			if got.BookingBlocked != tt.wantBlocked {
+				t.Errorf("BookingBlocked = %v, want %v", got.BookingBlocked, tt.wantBlocked)
+			}
+			if got.Outstanding != tt.wantOutstanding {
+				t.Errorf("Outstanding = %d, want %d", got.Outstanding, tt.wantOutstanding)
+			}
+			if len(got.Documents) != len(tt.docs) {
+				t.Errorf("len(Documents) = %d, want %d", len(got.Documents), len(tt.docs))
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+	"unicode/utf8"
+)
+
+var ErrInvalidEmailBranding = errors.New("invalid email branding")
+
+// EmailMessage is one email to one recipient
+type EmailMessage struct {
+	To       string
+	ToName   string
+	FromName string // Shown as the sender; the address is the provider's
+	ReplyTo  string
+	Subject  string
+	Text     string
+	HTML     string
+}
+
+// EmailSender delivers emails through a provider such as SMTP or SendGrid
+type EmailSender interface {
+	Send(ctx context.Context, msg *EmailMessage) error
+}
+
+// EmailTemplateTypes are the notification types tenants can give their own email template
+var EmailTemplateTypes = []string{
+	NotificationInvitation,
+	NotificationContractCreated,
+	NotificationLowSessions,
+	NotificationInvoiceReceipt,
+	NotificationReportReady,
+}
+
+// Limits on the email branding
+const (
+	MaxEmailSenderNameLength = 100
+	MaxEmailFooterLength     = 500
+	MaxEmailSubjectLength    = 200
+	MaxEmailBodyLength       = 20000
+)
+
+var emailAccentColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// EmailBranding is how a tenant's emails look. Emails without a template of the tenant's
+// use the default one, in the tenant's colors.
+type EmailBranding struct {
+	SenderName  string `bson:"sender_name,omitempty" json:"sender_name,omitempty"` // Defaults to the tenant's name
+	ReplyTo     string `bson:"reply_to,omitempty" json:"reply_to,omitempty"`
+	AccentColor string `bson:"accent_color,omitempty" json:"accent_color,omitempty"` // e.g. "#E4572E"
+	Footer      string `bson:"footer,omitempty" json:"footer,omitempty"`             // e.g. the gym's address
+
+	// Notification type -> template; see EmailTemplateTypes
+	Templates map[string]EmailTemplate `bson:"templates,omitempty" json:"templates,omitempty"`
+}
+
+// EmailTemplate overrides the email of one notification type. Both fields are Go templates
+// over EmailData, e.g. "Welcome to {{.Tenant}}, {{.Name}}"; Body renders HTML, escaped.
+type EmailTemplate struct {
+	Subject string `bson:"subject" json:"subject"`
+	Body    string `bson:"body" json:"body"`
+}
+
+// EmailData is what email templates can use
+type EmailData struct {
+	Tenant      string
+	LogoURL     string
+	AccentColor string
+	Footer      string
+	Name        string // The recipient's
+	Title       string // The notification's
+	Body        string
+	Link        string            // Deep link into the app, if any
+	Data        map[string]string // The notification's data, e.g. {{index .Data "contract_id"}}
+}
+
+// defaultAccentColor is used by tenants without one
+const defaultAccentColor = "#111827"
+
+const defaultEmailSubject = `{{.Title}}`
+
+const defaultEmailBody = `<div style="font-family:Arial,sans-serif;max-width:560px;margin:0 auto;color:#111827">
+{{if .LogoURL}}<img src="{{.LogoURL}}" alt="{{.Tenant}}" style="max-height:48px">{{else}}<h2 style="color:{{.AccentColor}}">{{.Tenant}}</h2>{{end}}
+<h1 style="font-size:20px;border-bottom:3px solid {{.AccentColor}};padding-bottom:8px">{{.Title}}</h1>
+<p>Hi {{.Name}},</p>
+<p>{{.Body}}</p>
+{{if .Link}}<p><a href="{{.Link}}" style="background:{{.AccentColor}};color:#fff;padding:10px 16px;text-decoration:none;border-radius:4px">Open in the app</a></p>{{end}}
+{{if .Footer}}<p style="color:#6b7280;font-size:12px">{{.Footer}}</p>{{end}}
+</div>`
+
+// Validate checks the lengths, colors and reply-to address, and that every template is for
+// a type in EmailTemplateTypes and renders
+func (b *EmailBranding) Validate() error {
+	if utf8.RuneCountInString(b.SenderName) > MaxEmailSenderNameLength {
+		return fmt.Errorf("%w: sender_name is longer than %d characters", ErrInvalidEmailBranding, MaxEmailSenderNameLength)
+	}
+	if utf8.RuneCountInString(b.Footer) > MaxEmailFooterLength {
+		return fmt.Errorf("%w: footer is longer than %d characters", ErrInvalidEmailBranding, MaxEmailFooterLength)
+	}
+	if b.AccentColor != "" && !emailAccentColor.MatchString(b.AccentColor) {
+		return fmt.Errorf("%w: accent_color must look like #E4572E", ErrInvalidEmailBranding)
+	}
+	if b.ReplyTo != "" {
+		if _, err := mail.ParseAddress(b.ReplyTo); err != nil {
+			return fmt.Errorf("%w: reply_to is not an email address", ErrInvalidEmailBranding)
+		}
+	}
+	sample := &EmailData{Tenant: "Gym", Name: "Member", Title: "Title", Body: "Body", Data: map[string]string{}}
+	for kind, tpl := range b.Templates {
+		if !slices.Contains(EmailTemplateTypes, kind) {
+			return fmt.Errorf("%w: no email template for %s", ErrInvalidEmailBranding, kind)
+		}
+		if strings.TrimSpace(tpl.Subject) == "" || utf8.RuneCountInString(tpl.Subject) > MaxEmailSubjectLength {
+			return fmt.Errorf("%w: %s subject must be 1 to %d characters", ErrInvalidEmailBranding, kind, MaxEmailSubjectLength)
+		}
+		if strings.TrimSpace(tpl.Body) == "" || len(tpl.Body) > MaxEmailBodyLength {
+			return fmt.Errorf("%w: %s body must be 1 to %d bytes", ErrInvalidEmailBranding, kind, MaxEmailBodyLength)
+		}
+		if _, _, err := renderEmailTemplate(tpl, sample); err != nil {
+			return fmt.Errorf("%w: %s template: %v", ErrInvalidEmailBranding, kind, err)
+		}
+	}
+	return nil
+}
+
+// RenderEmail turns the notification into the email to the recipient, branded for the
+// tenant. tenant may be nil for notifications outside any tenant.
+func RenderEmail(tenant *Tenant, to *User, n *Notification) (*EmailMessage, error) {
+	data := &EmailData{
+		Tenant:      "Metamorph",
+		AccentColor: defaultAccentColor,
+		Name:        to.Name,
+		Title:       n.Title,
+		Body:        n.Body,
+		Data:        n.Data,
+	}
+	if data.Name == "" {
+		data.Name = "there"
+	}
+	if n.Link != nil {
+		data.Link = n.Link.URL()
+	}
+	tpl := EmailTemplate{Subject: defaultEmailSubject, Body: defaultEmailBody}
+	var branding EmailBranding
+	if tenant != nil {
+		branding = tenant.EmailBranding
+		data.Tenant, data.LogoURL, data.Footer = tenant.Name, tenant.LogoURL, branding.Footer
+		if branding.AccentColor != "" {
+			data.AccentColor = branding.AccentColor
+		}
+		if custom, ok := branding.Templates[n.Type]; ok {
+			tpl = custom
+		}
+	}
+
+	subject, html, err := renderEmailTemplate(tpl, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s email: %w", n.Type, err)
+	}
+	from := branding.SenderName
+	if from == "" {
+		from = data.Tenant
+	}
+	text := n.Title + "\n\n" + n.Body
+	if data.Link != "" {
+		text += "\n\n" + data.Link
+	}
+	if data.Footer != "" {
+		text += "\n\n" + data.Footer
+	}
+	return &EmailMessage{
+		To:       to.Email,
+		ToName:   to.Name,
+		FromName: from,
+		ReplyTo:  branding.ReplyTo,
+		Subject:  subject,
+		Text:     text,
+		HTML:     html,
+	}, nil
+}
+
+// renderEmailTemplate renders the subject as plain text on one line, and the body as HTML
+func renderEmailTemplate(tpl EmailTemplate, data *EmailData) (subject, body string, err error) {
+	st, err := texttemplate.New("subject").Option("missingkey=zero").Parse(tpl.Subject)
+	if err != nil {
+		return "", "", err
+	}
+	bt, err := htmltemplate.New("body").Option("missingkey=zero").Parse(tpl.Body)
+	if err != nil {
+		return "", "", err
+	}
+	var sb, bb bytes.Buffer
+	if err := st.Execute(&sb, data); err != nil {
+		return "", "", err
+	}
+	if err := bt.Execute(&bb, data); err != nil {
+		return "", "", err
+	}
+	// A subject is one header line
+	subject = strings.Join(strings.Fields(sb.String()), " ")
+	return subject, bb.String(), nil
+}
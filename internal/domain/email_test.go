@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailBranding_Validate(t *testing.T) {
+	valid := &EmailBranding{
+		SenderName:  "Iron Gym",
+		ReplyTo:     "desk@irongym.id",
+		AccentColor: "#E4572E",
+		Templates: map[string]EmailTemplate{
+			NotificationInvitation: {Subject: "Welcome to {{.Tenant}}", Body: "<p>Hi {{.Name}}</p>"},
+		},
+	}
+	assert.NoError(t, valid.Validate())
+
+	for name, branding := range map[string]*EmailBranding{
+		"bad color":       {AccentColor: "red"},
+		"bad reply-to":    {ReplyTo: "front desk"},
+		"unknown type":    {Templates: map[string]EmailTemplate{"schedule.changed": {Subject: "s", Body: "b"}}},
+		"empty subject":   {Templates: map[string]EmailTemplate{NotificationInvitation: {Subject: " ", Body: "b"}}},
+		"broken template": {Templates: map[string]EmailTemplate{NotificationInvitation: {Subject: "{{.Name", Body: "b"}}},
+		"unknown field":   {Templates: map[string]EmailTemplate{NotificationInvitation: {Subject: "s", Body: "{{.Phone}}"}}},
+	} {
+		assert.ErrorIs(t, branding.Validate(), ErrInvalidEmailBranding, name)
+	}
+}
+
+func TestRenderEmail(t *testing.T) {
+	to := &User{Email: "budi@example.com", Name: "Budi"}
+	n := &Notification{
+		Type:  NotificationLowSessions,
+		Title: "Your package is running low",
+		Body:  "2 sessions left <soon>",
+		Link:  &DeepLink{Screen: ScreenContract, ContractID: "c1"},
+		Data:  map[string]string{"remaining": "2"},
+	}
+
+	t.Run("default template outside a tenant", func(t *testing.T) {
+		msg, err := RenderEmail(nil, to, n)
+		require.NoError(t, err)
+		assert.Equal(t, "Metamorph", msg.FromName)
+		assert.Equal(t, "Your package is running low", msg.Subject)
+		assert.Contains(t, msg.HTML, "2 sessions left &lt;soon&gt;")
+		assert.Contains(t, msg.HTML, defaultAccentColor)
+		assert.Contains(t, msg.Text, "metamorph://contract?contract_id=c1")
+	})
+
+	t.Run("tenant template and branding", func(t *testing.T) {
+		tenant := &Tenant{Name: "Iron Gym", EmailBranding: EmailBranding{
+			ReplyTo: "desk@irongym.id",
+			Footer:  "Jl. Sudirman 1",
+			Templates: map[string]EmailTemplate{
+				NotificationLowSessions: {Subject: "{{.Name}}, only {{index .Data \"remaining\"}}\nleft", Body: "<b>{{.Body}}</b>"},
+			},
+		}}
+		msg, err := RenderEmail(tenant, to, n)
+		require.NoError(t, err)
+		assert.Equal(t, "Iron Gym", msg.FromName)
+		assert.Equal(t, "desk@irongym.id", msg.ReplyTo)
+		assert.Equal(t, "Budi, only 2 left", msg.Subject)
+		assert.Equal(t, "<b>2 sessions left &lt;soon&gt;</b>", msg.HTML)
+		assert.Contains(t, msg.Text, "Jl. Sudirman 1")
+	})
+}
@@ -0,0 +1,191 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrEquipmentNotFound  = errors.New("equipment not found")
+	ErrDuplicateEquipment = errors.New("branch already has equipment with this name")
+	ErrInvalidEquipment   = errors.New("equipment needs a name and a quantity of zero or more")
+	ErrInvalidMaintenance = errors.New("invalid maintenance entry")
+)
+
+// Equipment is a kind of kit a branch owns. It matches Exercise.Equipment by name,
+// ignoring case, so "Barbell" here covers every barbell exercise in the library.
+type Equipment struct {
+	ID        string    `json:"id" bson:"_id"`
+	TenantID  string    `json:"tenant_id" bson:"tenant_id"`
+	BranchID  string    `json:"branch_id" bson:"branch_id"`
+	Name      string    `json:"name" bson:"name"`
+	Quantity  int       `json:"quantity" bson:"quantity"`
+	Available bool      `json:"available" bson:"available"` // False while out of service
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+
+	// Units reported out of order and not repaired yet, see ApplyMaintenance
+	OutOfOrder      int        `json:"out_of_order" bson:"out_of_order"`
+	OutOfOrderSince *time.Time `json:"out_of_order_since,omitempty" bson:"out_of_order_since,omitempty"`
+	LastServicedAt  *time.Time `json:"last_serviced_at,omitempty" bson:"last_serviced_at,omitempty"`
+}
+
+// InService is the number of units members can use right now
+func (e *Equipment) InService() int {
+	if !e.Available || e.OutOfOrder >= e.Quantity {
+		return 0
+	}
+	return e.Quantity - e.OutOfOrder
+}
+
+type EquipmentRepository interface {
+	Create(ctx context.Context, item *Equipment) error
+	GetByID(ctx context.Context, id string) (*Equipment, error)
+	ListByBranch(ctx context.Context, branchID string) ([]*Equipment, error)
+	Update(ctx context.Context, item *Equipment) error
+	Delete(ctx context.Context, id string) error
+}
+
+// Maintenance entry kinds
+const (
+	MaintenanceOutOfOrder = "out_of_order" // Units broke down
+	MaintenanceRepaired   = "repaired"     // Units are back in service
+	MaintenanceServiced   = "serviced"     // Routine service; units stay in service
+)
+
+// MaxMaintenanceNoteLength caps the note on a maintenance entry
+const MaxMaintenanceNoteLength = 1000
+
+// EquipmentMaintenance is one entry in an equipment's maintenance log
+type EquipmentMaintenance struct {
+	ID            string    `json:"id" bson:"_id"`
+	TenantID      string    `json:"tenant_id" bson:"tenant_id"`
+	BranchID      string    `json:"branch_id" bson:"branch_id"`
+	EquipmentID   string    `json:"equipment_id" bson:"equipment_id"`
+	EquipmentName string    `json:"equipment_name" bson:"equipment_name"` // Kept for the log once the equipment is deleted
+	Kind          string    `json:"kind" bson:"kind"`
+	Units         int       `json:"units" bson:"units"` // Broken or repaired; 0 for a service
+	Note          string    `json:"note,omitempty" bson:"note,omitempty"`
+	LoggedBy      string    `json:"logged_by" bson:"logged_by"`
+	LoggedAt      time.Time `json:"logged_at" bson:"logged_at"`
+}
+
+type EquipmentMaintenanceRepository interface {
+	Create(ctx context.Context, entry *EquipmentMaintenance) error
+	// ListByEquipment returns the equipment's log, latest first
+	ListByEquipment(ctx context.Context, equipmentID string, limit int) ([]*EquipmentMaintenance, error)
+	// ListByTenant returns the entries logged in [from, to), of one branch or, with an
+	// empty branchID, all of them
+	ListByTenant(ctx context.Context, tenantID, branchID string, from, to time.Time) ([]*EquipmentMaintenance, error)
+}
+
+// ApplyMaintenance records entry on the equipment: broken units leave service until they
+// are repaired. An entry without units counts one.
+func (e *Equipment) ApplyMaintenance(entry *EquipmentMaintenance, now time.Time) error {
+	entry.Note = strings.TrimSpace(entry.Note)
+	if len([]rune(entry.Note)) > MaxMaintenanceNoteLength {
+		return fmt.Errorf("%w: note is longer than %d characters", ErrInvalidMaintenance, MaxMaintenanceNoteLength)
+	}
+	if entry.Units < 0 {
+		return fmt.Errorf("%w: units must not be negative", ErrInvalidMaintenance)
+	}
+	if entry.Units == 0 && entry.Kind != MaintenanceServiced {
+		entry.Units = 1
+	}
+
+	switch entry.Kind {
+	case MaintenanceOutOfOrder:
+		if e.OutOfOrder+entry.Units > e.Quantity {
+			return fmt.Errorf("%w: only %d of %d units are in order", ErrInvalidMaintenance, e.Quantity-e.OutOfOrder, e.Quantity)
+		}
+		e.OutOfOrder += entry.Units
+		if e.OutOfOrderSince == nil {
+			e.OutOfOrderSince = &now
+		}
+	case MaintenanceRepaired:
+		if entry.Units > e.OutOfOrder {
+			return fmt.Errorf("%w: only %d units are out of order", ErrInvalidMaintenance, e.OutOfOrder)
+		}
+		e.OutOfOrder -= entry.Units
+		if e.OutOfOrder == 0 {
+			e.OutOfOrderSince = nil
+		}
+	case MaintenanceServiced:
+		entry.Units = 0
+		e.LastServicedAt = &now
+	default:
+		return fmt.Errorf("%w: kind must be %s, %s or %s", ErrInvalidMaintenance, MaintenanceOutOfOrder, MaintenanceRepaired, MaintenanceServiced)
+	}
+
+	entry.TenantID, entry.BranchID, entry.EquipmentID, entry.EquipmentName = e.TenantID, e.BranchID, e.ID, e.Name
+	entry.LoggedAt = now
+	return nil
+}
+
+// MaintenanceReport summarises the state and upkeep of a tenant's equipment per branch
+type MaintenanceReport struct {
+	From     time.Time           `json:"from"`
+	To       time.Time           `json:"to"`
+	Branches []BranchMaintenance `json:"branches"`
+}
+
+// BranchMaintenance is one branch in a MaintenanceReport. The counts are of entries logged
+// in the report's period; OutOfOrder is the equipment out of order now.
+type BranchMaintenance struct {
+	BranchID   string                `json:"branch_id"`
+	BranchName string                `json:"branch_name"`
+	Equipment  int                   `json:"equipment"` // Kinds of kit on the inventory
+	OutOfOrder []OutOfOrderEquipment `json:"out_of_order"`
+	Reported   int                   `json:"reported"` // Units reported out of order
+	Repaired   int                   `json:"repaired"` // Units repaired
+	Serviced   int                   `json:"serviced"` // Services logged
+}
+
+// OutOfOrderEquipment is equipment with units out of order or taken out of service
+type OutOfOrderEquipment struct {
+	EquipmentID string     `json:"equipment_id"`
+	Name        string     `json:"name"`
+	Quantity    int        `json:"quantity"`
+	OutOfOrder  int        `json:"out_of_order"`
+	Since       *time.Time `json:"since,omitempty"`
+	DaysOut     int        `json:"days_out"`
+}
+
+// NeedsEquipment is false for bodyweight exercises, which any branch supports
+func (e *Exercise) NeedsEquipment() bool {
+	switch equipmentKey(e.Equipment) {
+	case "", "none", "bodyweight", "body weight":
+		return false
+	}
+	return true
+}
+
+// EquipmentInventory is what a branch can offer right now, by equipment name
+type EquipmentInventory map[string]bool
+
+// NewEquipmentInventory indexes a branch's equipment. Items out of service or with no
+// units in order don't count.
+func NewEquipmentInventory(items []*Equipment) EquipmentInventory {
+	inv := make(EquipmentInventory, len(items))
+	for _, item := range items {
+		key := equipmentKey(item.Name)
+		inv[key] = inv[key] || item.InService() > 0
+	}
+	return inv
+}
+
+// Supports reports whether the branch has what ex needs. A branch that never recorded
+// its inventory is assumed to have everything, so suggestions stay quiet until it does.
+func (inv EquipmentInventory) Supports(ex *Exercise) bool {
+	if len(inv) == 0 || !ex.NeedsEquipment() {
+		return true
+	}
+	return inv[equipmentKey(ex.Equipment)]
+}
+
+func equipmentKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
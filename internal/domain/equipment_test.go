@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEquipmentInventory_Supports(t *testing.T) {
+	inv := NewEquipmentInventory([]*Equipment{
+		{Name: "Barbell", Quantity: 4, Available: true},
+		{Name: "Cable Machine", Quantity: 1, Available: false},
+		{Name: "Kettlebell", Quantity: 0, Available: true},
+		{Name: "Treadmill", Quantity: 2, Available: true, OutOfOrder: 2},
+		{Name: "Rower", Quantity: 2, Available: true, OutOfOrder: 1},
+	})
+
+	assert.True(t, inv.Supports(&Exercise{Equipment: "barbell "}), "names match loosely")
+	assert.False(t, inv.Supports(&Exercise{Equipment: "Cable Machine"}), "out of service")
+	assert.False(t, inv.Supports(&Exercise{Equipment: "Kettlebell"}), "none left")
+	assert.False(t, inv.Supports(&Exercise{Equipment: "Treadmill"}), "all out of order")
+	assert.True(t, inv.Supports(&Exercise{Equipment: "Rower"}), "one still works")
+	assert.False(t, inv.Supports(&Exercise{Equipment: "Smith Machine"}), "not owned")
+	assert.True(t, inv.Supports(&Exercise{Equipment: "Bodyweight"}))
+	assert.True(t, inv.Supports(&Exercise{}))
+
+	assert.True(t, NewEquipmentInventory(nil).Supports(&Exercise{Equipment: "Smith Machine"}), "no inventory recorded yet")
+}
+
+func TestEquipment_ApplyMaintenance(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	item := &Equipment{ID: "eq-1", TenantID: "gym", BranchID: "north", Name: "Treadmill", Quantity: 3, Available: true}
+
+	broken := &EquipmentMaintenance{Kind: MaintenanceOutOfOrder, Note: "  belt slipping "}
+	require.NoError(t, item.ApplyMaintenance(broken, now))
+	assert.Equal(t, 1, broken.Units, "one unit by default")
+	assert.Equal(t, "belt slipping", broken.Note)
+	assert.Equal(t, "north", broken.BranchID)
+	assert.Equal(t, "Treadmill", broken.EquipmentName)
+	assert.Equal(t, 2, item.InService())
+
+	require.NoError(t, item.ApplyMaintenance(&EquipmentMaintenance{Kind: MaintenanceOutOfOrder, Units: 1}, now.Add(time.Hour)))
+	assert.Equal(t, now, *item.OutOfOrderSince, "out of order since the first breakdown")
+	assert.ErrorIs(t, item.ApplyMaintenance(&EquipmentMaintenance{Kind: MaintenanceOutOfOrder, Units: 2}, now), ErrInvalidMaintenance)
+
+	assert.ErrorIs(t, item.ApplyMaintenance(&EquipmentMaintenance{Kind: MaintenanceRepaired, Units: 3}, now), ErrInvalidMaintenance)
+	require.NoError(t, item.ApplyMaintenance(&EquipmentMaintenance{Kind: MaintenanceRepaired, Units: 2}, now))
+	assert.Equal(t, 3, item.InService())
+	assert.Nil(t, item.OutOfOrderSince)
+
+	serviced := &EquipmentMaintenance{Kind: MaintenanceServiced, Units: 2}
+	require.NoError(t, item.ApplyMaintenance(serviced, now))
+	assert.Zero(t, serviced.Units)
+	assert.Equal(t, now, *item.LastServicedAt)
+
+	assert.ErrorIs(t, item.ApplyMaintenance(&EquipmentMaintenance{Kind: "replaced"}, now), ErrInvalidMaintenance)
+	assert.ErrorIs(t, item.ApplyMaintenance(&EquipmentMaintenance{Kind: MaintenanceRepaired, Units: -1}, now), ErrInvalidMaintenance)
+}
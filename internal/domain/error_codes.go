@@ -0,0 +1,263 @@
+package domain
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrorCode is the stable, machine-readable name of an error that API responses carry
+// next to the human message, so clients can branch on it instead of the wording
+type ErrorCode string
+
+// Codes of errors without a domain error of their own, by HTTP status
+const (
+	CodeBadRequest           ErrorCode = "BAD_REQUEST"
+	CodeUnauthenticated      ErrorCode = "UNAUTHENTICATED"
+	CodePaymentRequired      ErrorCode = "PAYMENT_REQUIRED"
+	CodeForbidden            ErrorCode = "FORBIDDEN"
+	CodeNotFound             ErrorCode = "NOT_FOUND"
+	CodeConflict             ErrorCode = "CONFLICT"
+	CodePreconditionFailed   ErrorCode = "PRECONDITION_FAILED"
+	CodePayloadTooLarge      ErrorCode = "PAYLOAD_TOO_LARGE"
+	CodeUnsupportedMediaType ErrorCode = "UNSUPPORTED_MEDIA_TYPE"
+	CodeRateLimited          ErrorCode = "RATE_LIMITED"
+	CodeServiceUnavailable   ErrorCode = "SERVICE_UNAVAILABLE"
+	CodeInternal             ErrorCode = "INTERNAL"
+)
+
+// ErrorCodeEntry ties a domain error to its code and the HTTP status it's answered with
+type ErrorCodeEntry struct {
+	Code    ErrorCode `json:"code"`
+	Status  int       `json:"status"`
+	Message string    `json:"message"`
+	err     error
+}
+
+func codeFor(err error, code ErrorCode, status int) ErrorCodeEntry {
+	return ErrorCodeEntry{Code: code, Status: status, Message: err.Error(), err: err}
+}
+
+// errorCatalog is the registry of domain errors. Codes are part of the API: add new ones
+// freely but never rename or reuse a code.
+var errorCatalog = []ErrorCodeEntry{
+	// Common
+	codeFor(ErrNotFound, CodeNotFound, http.StatusNotFound),
+	codeFor(ErrForbidden, CodeForbidden, http.StatusForbidden),
+	codeFor(ErrTenantScopeViolation, "TENANT_SCOPE_VIOLATION", http.StatusForbidden),
+	codeFor(ErrInvalidID, "INVALID_ID", http.StatusBadRequest),
+	codeFor(ErrInvalidCursor, "INVALID_CURSOR", http.StatusBadRequest),
+	codeFor(ErrInvalidSearchQuery, "INVALID_SEARCH_QUERY", http.StatusBadRequest),
+	codeFor(ErrVersionConflict, "VERSION_CONFLICT", http.StatusConflict),
+	codeFor(ErrLockNotAcquired, "RESOURCE_BUSY", http.StatusConflict),
+	codeFor(ErrTooManyAttempts, "TOO_MANY_ATTEMPTS", http.StatusTooManyRequests),
+	codeFor(ErrRateLimited, CodeRateLimited, http.StatusTooManyRequests),
+	codeFor(ErrCurrencyMismatch, "CURRENCY_MISMATCH", http.StatusBadRequest),
+	codeFor(ErrInvalidIdempotencyKey, "INVALID_IDEMPOTENCY_KEY", http.StatusBadRequest),
+	codeFor(ErrIdempotencyKeyInUse, "IDEMPOTENCY_KEY_IN_USE", http.StatusConflict),
+	codeFor(ErrIdempotencyKeyReused, "IDEMPOTENCY_KEY_REUSED", http.StatusUnprocessableEntity),
+
+	// Users, tenants and branches
+	codeFor(ErrNotTenantMember, "NOT_TENANT_MEMBER", http.StatusForbidden),
+	codeFor(ErrUserDeleted, "USER_DELETED", http.StatusForbidden),
+	codeFor(ErrInvalidUserQuery, "INVALID_USER_QUERY", http.StatusBadRequest),
+	codeFor(ErrNoWorkingBranch, "NO_WORKING_BRANCH", http.StatusBadRequest),
+	codeFor(ErrBranchNotAllowed, "BRANCH_NOT_ALLOWED", http.StatusForbidden),
+	codeFor(ErrBranchMismatch, "BRANCH_MISMATCH", http.StatusBadRequest),
+	codeFor(ErrWeakJoinCode, "WEAK_JOIN_CODE", http.StatusBadRequest),
+	codeFor(ErrInvalidAISettings, "INVALID_AI_SETTINGS", http.StatusBadRequest),
+	codeFor(ErrInvalidEmailBranding, "INVALID_EMAIL_BRANDING", http.StatusBadRequest),
+	codeFor(ErrInvalidCustomFieldSchema, "INVALID_CUSTOM_FIELD_SCHEMA", http.StatusBadRequest),
+	codeFor(ErrInvalidCustomFields, "INVALID_CUSTOM_FIELDS", http.StatusBadRequest),
+	codeFor(ErrAIQuotaExceeded, "AI_QUOTA_EXCEEDED", http.StatusTooManyRequests),
+	codeFor(ErrNotSandbox, "NOT_SANDBOX", http.StatusConflict),
+	codeFor(ErrDemoDataExists, "DEMO_DATA_EXISTS", http.StatusConflict),
+	codeFor(ErrInvalidTransferPolicy, "INVALID_TRANSFER_POLICY", http.StatusBadRequest),
+	codeFor(ErrInvalidTransferTarget, "INVALID_TRANSFER_TARGET", http.StatusBadRequest),
+	codeFor(ErrGuardianConsentRequired, "GUARDIAN_CONSENT_REQUIRED", http.StatusPreconditionFailed),
+	codeFor(ErrInvalidGuardianConsent, "INVALID_GUARDIAN_CONSENT", http.StatusBadRequest),
+	codeFor(ErrInvalidDateOfBirth, "INVALID_DATE_OF_BIRTH", http.StatusBadRequest),
+	codeFor(ErrInvalidMinorAge, "INVALID_MINOR_AGE", http.StatusBadRequest),
+	codeFor(ErrHealthConsentRequired, "HEALTH_CONSENT_REQUIRED", http.StatusForbidden),
+	codeFor(ErrHealthConsentOutdated, "HEALTH_CONSENT_OUTDATED", http.StatusConflict),
+	codeFor(ErrInvalidHealthDataPolicy, "INVALID_HEALTH_DATA_POLICY", http.StatusBadRequest),
+	codeFor(ErrTenantDeactivated, "TENANT_DEACTIVATED", http.StatusForbidden),
+	codeFor(ErrOffboardingStarted, "OFFBOARDING_STARTED", http.StatusConflict),
+	codeFor(ErrOffboardingConfirmation, "OFFBOARDING_CONFIRMATION", http.StatusBadRequest),
+	codeFor(ErrInvalidOffboardingGrace, "INVALID_OFFBOARDING_GRACE", http.StatusBadRequest),
+	codeFor(ErrOffboardingNotCancellable, "OFFBOARDING_NOT_CANCELLABLE", http.StatusConflict),
+	codeFor(ErrInvalidAuditLogQuery, "INVALID_AUDIT_LOG_QUERY", http.StatusBadRequest),
+	codeFor(ErrInvalidInvite, "INVALID_INVITE", http.StatusBadRequest),
+	codeFor(ErrInviteNotFound, "INVITE_NOT_FOUND", http.StatusNotFound),
+	codeFor(ErrInviteEmailMismatch, "INVITE_EMAIL_MISMATCH", http.StatusForbidden),
+	codeFor(ErrInviteEmailUnverified, "INVITE_EMAIL_UNVERIFIED", http.StatusForbidden),
+	codeFor(ErrInviteMemberExists, "INVITE_MEMBER_EXISTS", http.StatusConflict),
+	codeFor(ErrInviteAccountLinked, "ACCOUNT_ALREADY_LINKED", http.StatusConflict),
+
+	// Packages, contracts and credits
+	codeFor(ErrPackageDepleted, "PACKAGE_DEPLETED", http.StatusBadRequest),
+	codeFor(ErrInsufficientCredits, "INSUFFICIENT_CREDITS", http.StatusBadRequest),
+	codeFor(ErrInvalidCreditType, "INVALID_CREDIT_TYPE", http.StatusBadRequest),
+	codeFor(ErrInvalidCreditAmount, "INVALID_CREDIT_AMOUNT", http.StatusBadRequest),
+	codeFor(ErrDuplicateCredit, "DUPLICATE_CREDIT", http.StatusConflict),
+	codeFor(ErrInvalidSessionAmount, "INVALID_SESSION_AMOUNT", http.StatusBadRequest),
+	codeFor(ErrContractNotFound, "CONTRACT_NOT_FOUND", http.StatusNotFound),
+	codeFor(ErrContractSuspended, "CONTRACT_SUSPENDED", http.StatusPaymentRequired),
+	codeFor(ErrContractRenewed, "CONTRACT_RENEWED", http.StatusConflict),
+	codeFor(ErrPackageTemplateNotFound, "PACKAGE_NOT_FOUND", http.StatusNotFound),
+	codeFor(ErrInvalidPackageValidity, "INVALID_PACKAGE_VALIDITY", http.StatusBadRequest),
+	codeFor(ErrInvalidBranchPrice, "INVALID_BRANCH_PRICE", http.StatusBadRequest),
+	codeFor(ErrAgreementNotFound, "AGREEMENT_NOT_FOUND", http.StatusNotFound),
+	codeFor(ErrAgreementAlreadySigned, "AGREEMENT_ALREADY_SIGNED", http.StatusConflict),
+	codeFor(ErrSignatureRequired, "SIGNATURE_REQUIRED", http.StatusBadRequest),
+	codeFor(ErrInvalidSignatureImage, "INVALID_SIGNATURE_IMAGE", http.StatusBadRequest),
+	codeFor(ErrSignatureImageNotKept, "SIGNATURE_IMAGE_NOT_KEPT", http.StatusBadRequest),
+	codeFor(ErrDocumentNotFound, "DOCUMENT_NOT_FOUND", http.StatusNotFound),
+	codeFor(ErrRequiredDocumentsUnsigned, "DOCUMENTS_UNSIGNED", http.StatusPreconditionFailed),
+
+	// Payments
+	codeFor(ErrInvalidInstallments, "INVALID_INSTALLMENTS", http.StatusBadRequest),
+	codeFor(ErrInstallmentPlanPaid, "INSTALLMENT_PLAN_PAID", http.StatusConflict),
+	codeFor(ErrInstallmentPlanExists, "INSTALLMENT_PLAN_EXISTS", http.StatusConflict),
+	codeFor(ErrInvalidPaymentChannel, "INVALID_PAYMENT_CHANNEL", http.StatusBadRequest),
+	codeFor(ErrInvalidPaymentMethod, "INVALID_PAYMENT_METHOD", http.StatusBadRequest),
+	codeFor(ErrInvalidManualPayment, "INVALID_MANUAL_PAYMENT", http.StatusBadRequest),
+	codeFor(ErrNotContractInvoice, "NOT_CONTRACT_INVOICE", http.StatusBadRequest),
+	codeFor(ErrInvoiceAlreadyPaid, "INVOICE_ALREADY_PAID", http.StatusConflict),
+	codeFor(ErrInvalidSettlementFile, "INVALID_SETTLEMENT_FILE", http.StatusBadRequest),
+
+	// Scheduling
+	codeFor(ErrScheduleNotFound, "SCHEDULE_NOT_FOUND", http.StatusNotFound),
+	codeFor(ErrUnauthorizedReschedule, "RESCHEDULE_NOT_ALLOWED", http.StatusForbidden),
+	codeFor(ErrOutsideAvailability, "OUTSIDE_AVAILABILITY", http.StatusConflict),
+	codeFor(ErrInvalidAvailability, "INVALID_AVAILABILITY", http.StatusBadRequest),
+	codeFor(ErrInvalidScheduleBatch, "INVALID_SCHEDULE_BATCH", http.StatusBadRequest),
+	codeFor(ErrInvalidScheduleTags, "INVALID_SCHEDULE_TAGS", http.StatusBadRequest),
+	codeFor(ErrInvalidScheduleLabel, "INVALID_SCHEDULE_LABEL", http.StatusBadRequest),
+	codeFor(ErrInvalidModality, "INVALID_MODALITY", http.StatusBadRequest),
+	codeFor(ErrInvalidMeetingURL, "INVALID_MEETING_URL", http.StatusBadRequest),
+	codeFor(ErrInvalidConfirmation, "INVALID_CONFIRMATION", http.StatusBadRequest),
+	codeFor(ErrSessionNotConfirmable, "SESSION_NOT_CONFIRMABLE", http.StatusConflict),
+	codeFor(ErrInvalidConfirmationAlert, "INVALID_CONFIRMATION_ALERT", http.StatusBadRequest),
+	codeFor(ErrInvalidUnavailability, "INVALID_UNAVAILABILITY", http.StatusBadRequest),
+	codeFor(ErrSubstitutionClosed, "SUBSTITUTION_CLOSED", http.StatusConflict),
+	codeFor(ErrSubstitutionExists, "SUBSTITUTION_EXISTS", http.StatusConflict),
+	codeFor(ErrCannotCover, "SCHEDULE_CONFLICT", http.StatusConflict),
+	codeFor(ErrSessionChanged, "SESSION_CHANGED", http.StatusConflict),
+	codeFor(ErrHandoverNotFound, "HANDOVER_NOT_FOUND", http.StatusNotFound),
+	codeFor(ErrInvalidClientNotes, "INVALID_CLIENT_NOTES", http.StatusBadRequest),
+	codeFor(ErrInvalidContractHandoff, "INVALID_CONTRACT_HANDOFF", http.StatusConflict),
+
+	// Workouts and sessions
+	codeFor(ErrSessionNotFound, "WORKOUT_SESSION_NOT_FOUND", http.StatusNotFound),
+	codeFor(ErrExerciseULIDNotFound, "SESSION_EXERCISE_NOT_FOUND", http.StatusNotFound),
+	codeFor(ErrSelfWorkoutInProgress, "WORKOUT_IN_PROGRESS", http.StatusConflict),
+	codeFor(ErrSelfWorkoutClosed, "WORKOUT_CLOSED", http.StatusConflict),
+	codeFor(ErrSelfWorkoutExpired, "WORKOUT_EXPIRED", http.StatusConflict),
+	codeFor(ErrSessionPlanNotFound, "SESSION_NOT_PLANNED", http.StatusNotFound),
+	codeFor(ErrSessionAlreadyHeld, "SESSION_ALREADY_HELD", http.StatusConflict),
+	codeFor(ErrSessionAlreadyPlanned, "SESSION_ALREADY_PLANNED", http.StatusConflict),
+	codeFor(ErrInvalidSessionEffort, "INVALID_SESSION_EFFORT", http.StatusBadRequest),
+	codeFor(ErrSessionNotCompleted, "SESSION_NOT_COMPLETED", http.StatusConflict),
+	codeFor(ErrTemplateNotFound, "TEMPLATE_NOT_FOUND", http.StatusNotFound),
+	codeFor(ErrExerciseNotFound, "EXERCISE_NOT_FOUND", http.StatusNotFound),
+	codeFor(ErrDuplicateExercise, "DUPLICATE_EXERCISE", http.StatusConflict),
+	codeFor(ErrInvalidExerciseImport, "INVALID_EXERCISE_IMPORT", http.StatusBadRequest),
+	codeFor(ErrInvalidExerciseScope, "INVALID_EXERCISE_SCOPE", http.StatusBadRequest),
+	codeFor(ErrInvalidExerciseMerge, "INVALID_EXERCISE_MERGE", http.StatusBadRequest),
+	codeFor(ErrEquipmentNotFound, "EQUIPMENT_NOT_FOUND", http.StatusNotFound),
+	codeFor(ErrDuplicateEquipment, "DUPLICATE_EQUIPMENT", http.StatusConflict),
+	codeFor(ErrInvalidEquipment, "INVALID_EQUIPMENT", http.StatusBadRequest),
+	codeFor(ErrInvalidMaintenance, "INVALID_MAINTENANCE", http.StatusBadRequest),
+	codeFor(ErrInvalidAssessment, "INVALID_ASSESSMENT", http.StatusBadRequest),
+
+	// Media
+	codeFor(ErrSetVideoNotFound, "VIDEO_NOT_FOUND", http.StatusNotFound),
+	codeFor(ErrVideoTooLarge, "VIDEO_TOO_LARGE", http.StatusRequestEntityTooLarge),
+	codeFor(ErrVideoTooLong, "VIDEO_TOO_LONG", http.StatusBadRequest),
+	codeFor(ErrVideoResolution, "VIDEO_RESOLUTION_TOO_HIGH", http.StatusBadRequest),
+	codeFor(ErrUnsupportedVideo, "UNSUPPORTED_VIDEO", http.StatusBadRequest),
+	codeFor(ErrInvalidComment, "INVALID_COMMENT", http.StatusBadRequest),
+	codeFor(ErrSessionPhotoNotFound, "PHOTO_NOT_FOUND", http.StatusNotFound),
+	codeFor(ErrPhotoTooLarge, "PHOTO_TOO_LARGE", http.StatusRequestEntityTooLarge),
+	codeFor(ErrUnsupportedPhoto, "UNSUPPORTED_PHOTO", http.StatusBadRequest),
+	codeFor(ErrPhotoSessionNotCompleted, "PHOTO_SESSION_NOT_COMPLETED", http.StatusConflict),
+	codeFor(ErrPhotoConsentRequired, "PHOTO_CONSENT_REQUIRED", http.StatusForbidden),
+	codeFor(ErrInvalidShareLink, "INVALID_SHARE_LINK", http.StatusBadRequest),
+	codeFor(ErrShareLinkNotFound, "SHARE_LINK_NOT_FOUND", http.StatusNotFound),
+	codeFor(ErrShareLinkMinor, "SHARE_LINK_MINOR", http.StatusForbidden),
+	codeFor(ErrInvalidScanCSV, "INVALID_SCAN_CSV", http.StatusBadRequest),
+	codeFor(ErrScanImageUnavailable, "SCAN_IMAGE_UNAVAILABLE", http.StatusConflict),
+	codeFor(ErrScanRevisionNotFound, "SCAN_REVISION_NOT_FOUND", http.StatusNotFound),
+
+	// Imports, jobs and reports
+	codeFor(ErrUnknownImportSource, "UNKNOWN_IMPORT_SOURCE", http.StatusBadRequest),
+	codeFor(ErrInvalidImportFile, "INVALID_IMPORT_FILE", http.StatusBadRequest),
+	codeFor(ErrInvalidImportCoach, "INVALID_IMPORT_COACH", http.StatusBadRequest),
+	codeFor(ErrImportStarted, "IMPORT_STARTED", http.StatusConflict),
+	codeFor(ErrInvalidSeedCatalog, "INVALID_SEED_CATALOG", http.StatusBadRequest),
+	codeFor(ErrJobNotRetryable, "JOB_NOT_RETRYABLE", http.StatusConflict),
+	codeFor(ErrInvalidReportPeriod, "INVALID_REPORT_PERIOD", http.StatusBadRequest),
+	codeFor(ErrInvalidReportSchedule, "INVALID_REPORT_SCHEDULE", http.StatusBadRequest),
+	codeFor(ErrInvalidWebhook, "INVALID_WEBHOOK", http.StatusBadRequest),
+	codeFor(ErrInvalidDashboardLayout, "INVALID_DASHBOARD_LAYOUT", http.StatusBadRequest),
+	codeFor(ErrInvalidProgressWeights, "INVALID_PROGRESS_WEIGHTS", http.StatusBadRequest),
+	codeFor(ErrInvalidPBRules, "INVALID_PB_RULES", http.StatusBadRequest),
+	codeFor(ErrInvalidSyncBatch, "INVALID_SYNC_BATCH", http.StatusBadRequest),
+	codeFor(ErrInvalidSyncToken, "INVALID_SYNC_TOKEN", http.StatusBadRequest),
+
+	// Notifications and widgets
+	codeFor(ErrInvalidReminderLeadTimes, "INVALID_REMINDER_LEAD_TIMES", http.StatusBadRequest),
+	codeFor(ErrInvalidQuietHours, "INVALID_QUIET_HOURS", http.StatusBadRequest),
+	codeFor(ErrInvalidChannel, "INVALID_CHANNEL", http.StatusBadRequest),
+	codeFor(ErrInvalidDeviceToken, "INVALID_DEVICE_TOKEN", http.StatusBadRequest),
+	codeFor(ErrInvalidWidgetToken, "INVALID_WIDGET_TOKEN", http.StatusBadRequest),
+	codeFor(ErrWidgetUnauthorized, "WIDGET_UNAUTHORIZED", http.StatusUnauthorized),
+	codeFor(ErrWidgetScopeDenied, "WIDGET_SCOPE_DENIED", http.StatusForbidden),
+}
+
+// ErrorCatalog returns every registered error code, for clients and docs
+func ErrorCatalog() []ErrorCodeEntry {
+	return append([]ErrorCodeEntry(nil), errorCatalog...)
+}
+
+// LookupError returns the registry entry of err or the first error it wraps
+func LookupError(err error) (ErrorCodeEntry, bool) {
+	for _, entry := range errorCatalog {
+		if errors.Is(err, entry.err) {
+			return entry, true
+		}
+	}
+	return ErrorCodeEntry{}, false
+}
+
+// StatusErrorCode is the generic code of an HTTP error status
+func StatusErrorCode(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthenticated
+	case http.StatusPaymentRequired:
+		return CodePaymentRequired
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusPreconditionFailed:
+		return CodePreconditionFailed
+	case http.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case http.StatusUnsupportedMediaType:
+		return CodeUnsupportedMediaType
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	}
+	if status >= 400 && status < 500 {
+		return CodeBadRequest
+	}
+	return CodeInternal
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCatalog_CodesAreUnique(t *testing.T) {
+	seen := map[ErrorCode]bool{}
+	for _, entry := range ErrorCatalog() {
+		assert.False(t, seen[entry.Code], "code %s is used twice", entry.Code)
+		seen[entry.Code] = true
+		assert.GreaterOrEqual(t, entry.Status, 400, entry.Code)
+	}
+}
+
+func TestLookupError(t *testing.T) {
+	entry, ok := LookupError(fmt.Errorf("failed to consume credit: %w", ErrPackageDepleted))
+	assert.True(t, ok)
+	assert.Equal(t, ErrorCode("PACKAGE_DEPLETED"), entry.Code)
+	assert.Equal(t, http.StatusBadRequest, entry.Status)
+
+	_, ok = LookupError(fmt.Errorf("mongo down"))
+	assert.False(t, ok)
+}
+
+func TestStatusErrorCode(t *testing.T) {
+	assert.Equal(t, CodeNotFound, StatusErrorCode(http.StatusNotFound))
+	assert.Equal(t, CodeUnauthenticated, StatusErrorCode(http.StatusUnauthorized))
+	assert.Equal(t, CodeBadRequest, StatusErrorCode(http.StatusTeapot))
+	assert.Equal(t, CodeInternal, StatusErrorCode(http.StatusBadGateway))
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ErrorReport describes a server error or panic for an external error tracker
+type ErrorReport struct {
+	Err        error
+	Panic      bool // The request panicked; Stack holds the goroutine stack
+	Stack      string
+	Method     string
+	Path       string
+	StatusCode int
+	Tags       map[string]string // correlation_id, route, tenant_id, role
+	OccurredAt time.Time
+}
+
+// ErrorReporter sends error reports to an error tracker. Report must not block the request:
+// implementations queue the report and may drop it under load.
+type ErrorReporter interface {
+	Report(ctx context.Context, report *ErrorReport)
+}
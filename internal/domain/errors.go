@@ -7,4 +7,13 @@ var (
 	ErrNotFound  = errors.New("record not found")
 	ErrForbidden = errors.New("access forbidden: you don't own this resource")
 	ErrInvalidID = errors.New("invalid id")
+
+	// ErrTenantScopeViolation is returned for a record of another tenant than the caller's
+	ErrTenantScopeViolation = errors.New("resource belongs to another tenant")
+
+	// ErrInvalidCursor is returned when a pagination cursor can't be decoded
+	ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+	// ErrVersionConflict is returned when an update was based on a stale copy of the record
+	ErrVersionConflict = errors.New("record was modified by another request; reload and try again")
 )
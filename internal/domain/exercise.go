@@ -3,27 +3,80 @@ package domain
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 )
 
 var (
 	ErrExerciseNotFound  = errors.New("exercise not found")
 	ErrDuplicateExercise = errors.New("exercise name already exists")
+
+	ErrInvalidExerciseImport = errors.New("invalid exercise import")
+	ErrInvalidExerciseScope  = errors.New("invalid exercise scope: use 'all', 'global' or 'tenant'")
+)
+
+// Exercise scopes. Global exercises are the shared library; tenant exercises are private to
+// the gym that created them and override global ones of the same name in its library.
+const (
+	ExerciseScopeAll    = "all"
+	ExerciseScopeGlobal = "global"
+	ExerciseScopeTenant = "tenant"
 )
 
-// Exercise represents a move in the global library
+// ValidateExerciseScope accepts the scopes above, with empty meaning all
+func ValidateExerciseScope(scope string) error {
+	switch scope {
+	case "", ExerciseScopeAll, ExerciseScopeGlobal, ExerciseScopeTenant:
+		return nil
+	}
+	return ErrInvalidExerciseScope
+}
+
+// Exercise represents a move in the global library, or in one tenant's private library
 type Exercise struct {
 	ID           string    `json:"id" bson:"_id,omitempty"`
 	ClientID     string    `json:"client_id,omitempty" bson:"client_id,omitempty"` // Frontend ULID for dual-identity handshake
-	Name         string    `json:"name" bson:"name"`                               // Unique Index
+	TenantID     string    `json:"tenant_id,omitempty" bson:"tenant_id,omitempty"` // Empty for the global library
+	Name         string    `json:"name" bson:"name"`                               // Unique per library
 	MuscleGroup  string    `json:"muscle_group" bson:"muscle_group"`               // e.g., "Legs", "Chest"
 	Equipment    string    `json:"equipment" bson:"equipment"`                     // e.g., "Barbell", "Dumbbell"
 	VideoURL     string    `json:"video_url" bson:"video_url"`
 	ReferenceURL string    `json:"reference_url" bson:"reference_url"` // Image or link showing the movement
 	CreatedAt    time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" bson:"updated_at"`
+
+	// Demonstration clip hosted in file storage; VideoURL stays as the fallback
+	Video       *ExerciseVideo `json:"video,omitempty" bson:"video,omitempty"`
+	PlaybackURL string         `json:"playback_url,omitempty" bson:"-"` // Signed URL of Video, or VideoURL; set when served
+}
+
+// VisibleTo reports whether the tenant's coaches can use the exercise
+func (e *Exercise) VisibleTo(tenantID string) bool {
+	return e.TenantID == "" || e.TenantID == tenantID
+}
+
+// MergeExerciseLibraries drops the global exercises that a tenant exercise of the same name
+// (ignoring case) overrides, keeping the order of the rest
+func MergeExerciseLibraries(exercises []*Exercise) []*Exercise {
+	overridden := map[string]bool{}
+	for _, ex := range exercises {
+		if ex.TenantID != "" {
+			overridden[strings.ToLower(strings.TrimSpace(ex.Name))] = true
+		}
+	}
+	merged := make([]*Exercise, 0, len(exercises))
+	for _, ex := range exercises {
+		if ex.TenantID == "" && overridden[strings.ToLower(strings.TrimSpace(ex.Name))] {
+			continue
+		}
+		merged = append(merged, ex)
+	}
+	return merged
 }
 
+// ExerciseRepository stores the libraries. List filters: "name", "muscle_group", and
+// "tenant_id" with "scope" (ExerciseScope*, default all). Without a tenant_id only the
+// global library is listed.
 type ExerciseRepository interface {
 	Create(ctx context.Context, exercise *Exercise) error
 	GetByID(ctx context.Context, id string) (*Exercise, error)
@@ -31,5 +84,7 @@ type ExerciseRepository interface {
 	GetByIDs(ctx context.Context, ids []string) ([]*Exercise, error)       // Batch lookup for N+1 prevention
 	List(ctx context.Context, filter map[string]interface{}) ([]*Exercise, error)
 	Update(ctx context.Context, exercise *Exercise) error
+	// SetVideo replaces the exercise's hosted clip; nil removes it
+	SetVideo(ctx context.Context, id string, video *ExerciseVideo) error
 	Delete(ctx context.Context, id string) error
 }
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrInvalidExerciseMerge = errors.New("merge needs a canonical exercise and at least one other exercise to fold into it")
+
+// DefaultDuplicateThreshold is the similarity from which two exercises are reported
+const DefaultDuplicateThreshold = 0.8
+
+// equipmentWords are dropped when comparing names: "Barbell Bench Press" is usually
+// "Bench Press" entered by someone more specific. Smith and trap bar aren't here, since
+// they change the movement.
+var equipmentWords = map[string]bool{
+	"barbell": true, "bb": true, "dumbbell": true, "db": true, "machine": true,
+	"cable": true, "kettlebell": true, "kb": true, "band": true, "bodyweight": true,
+}
+
+// ExerciseDuplicate is a pair of catalog exercises that are probably the same move
+type ExerciseDuplicate struct {
+	Exercise  *Exercise `json:"exercise"`
+	Duplicate *Exercise `json:"duplicate"`
+	Score     float64   `json:"score"` // 0-1
+}
+
+// ExerciseMerge records duplicates folded into a canonical exercise, and how many
+// references were moved
+type ExerciseMerge struct {
+	ID             string   `json:"id" bson:"_id,omitempty"`
+	CanonicalID    string   `json:"canonical_id" bson:"canonical_id"`
+	DuplicateIDs   []string `json:"duplicate_ids" bson:"duplicate_ids"`
+	DuplicateNames []string `json:"duplicate_names" bson:"duplicate_names"` // The exercises are deleted
+	ActorID        string   `json:"actor_id" bson:"actor_id"`
+
+	SetLogs          int64 `json:"set_logs" bson:"set_logs"`
+	PlannedExercises int64 `json:"planned_exercises" bson:"planned_exercises"`
+	Templates        int64 `json:"templates" bson:"templates"`
+	PersonalBests    int64 `json:"personal_bests" bson:"personal_bests"` // Moved or dropped in favour of a better one
+
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// ExerciseMergeRepository re-points every reference to duplicate exercises at the
+// canonical one. The rewrites span collections, so run them inside a transaction.
+type ExerciseMergeRepository interface {
+	// RepointSetLogs moves set logs, archived ones included
+	RepointSetLogs(ctx context.Context, duplicateIDs []string, canonicalID string) (int64, error)
+	// RepointPlannedExercises moves planned exercises, archived ones included
+	RepointPlannedExercises(ctx context.Context, duplicateIDs []string, canonicalID string) (int64, error)
+	// RepointTemplates replaces the duplicates in template exercise lists, keeping the
+	// first occurrence when a template ends up listing the canonical exercise twice
+	RepointTemplates(ctx context.Context, duplicateIDs []string, canonicalID string) (int64, error)
+	// MergePersonalBests keeps each member's best lift across the exercises, on the canonical one
+	MergePersonalBests(ctx context.Context, duplicateIDs []string, canonicalID string) (int64, error)
+	// RepointWorkoutEvents moves logged events, so replaying them rebuilds the same PBs
+	RepointWorkoutEvents(ctx context.Context, duplicateIDs []string, canonicalID string) (int64, error)
+
+	Record(ctx context.Context, merge *ExerciseMerge) error
+}
+
+// nameTokens splits an exercise name into lowercase words without equipment words, with
+// plurals folded so "Bicep Curls" matches "Biceps Curl"
+func nameTokens(name string) []string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := make([]string, 0, len(words))
+	for _, w := range words {
+		if equipmentWords[w] {
+			continue
+		}
+		if len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") {
+			w = strings.TrimSuffix(w, "s")
+		}
+		tokens = append(tokens, w)
+	}
+	return tokens
+}
+
+// ExerciseSimilarity scores how alike two exercise names are, from 0 to 1. It's the
+// better of word overlap (ignoring equipment words) and edit distance, which catches typos.
+func ExerciseSimilarity(a, b string) float64 {
+	ta, tb := nameTokens(a), nameTokens(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	set := make(map[string]bool, len(ta))
+	for _, t := range ta {
+		set[t] = true
+	}
+	union := len(set)
+	shared := 0
+	seen := make(map[string]bool, len(tb))
+	for _, t := range tb {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		if set[t] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	overlap := float64(shared) / float64(union)
+
+	ja, jb := strings.Join(ta, " "), strings.Join(tb, " ")
+	edit := 1 - float64(levenshtein(ja, jb))/float64(max(len([]rune(ja)), len([]rune(jb))))
+	return max(overlap, edit)
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// FindDuplicateExercises reports the pairs of exercises scoring at least threshold, most
+// similar first. Exercises of different muscle groups are never paired.
+func FindDuplicateExercises(exercises []*Exercise, threshold float64) []ExerciseDuplicate {
+	pairs := []ExerciseDuplicate{}
+	for i, a := range exercises {
+		for _, b := range exercises[i+1:] {
+			if a.MuscleGroup != "" && b.MuscleGroup != "" && !strings.EqualFold(a.MuscleGroup, b.MuscleGroup) {
+				continue
+			}
+			if score := ExerciseSimilarity(a.Name, b.Name); score >= threshold {
+				pairs = append(pairs, ExerciseDuplicate{Exercise: a, Duplicate: b, Score: score})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].Score > pairs[j].Score })
+	return pairs
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExerciseSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, ExerciseSimilarity("Bench Press", "Barbell Bench Press"), "equipment words are ignored")
+	assert.Equal(t, 1.0, ExerciseSimilarity("Bicep Curl", "Biceps Curls"), "plurals fold")
+	assert.Equal(t, 1.0, ExerciseSimilarity("Pull Up", "Pull-up"))
+	assert.Greater(t, ExerciseSimilarity("Lat Pulldown", "Lat Pull Down"), DefaultDuplicateThreshold, "typo-level differences")
+
+	assert.Less(t, ExerciseSimilarity("Bench Press", "Incline Bench Press"), DefaultDuplicateThreshold, "a variation is a different move")
+	assert.Less(t, ExerciseSimilarity("Deadlift", "Romanian Deadlift"), DefaultDuplicateThreshold)
+	assert.Less(t, ExerciseSimilarity("Leg Press", "Leg Curl"), DefaultDuplicateThreshold)
+	assert.Zero(t, ExerciseSimilarity("Barbell", "Bench Press"))
+}
+
+func TestFindDuplicateExercises(t *testing.T) {
+	bench := &Exercise{ID: "1", Name: "Bench Press", MuscleGroup: "Chest"}
+	barbellBench := &Exercise{ID: "2", Name: "Barbell Bench Press", MuscleGroup: "chest"}
+	pulldown := &Exercise{ID: "3", Name: "Lat Pulldown", MuscleGroup: "Back"}
+	pullDown := &Exercise{ID: "4", Name: "Lat Pull Down"}
+	row := &Exercise{ID: "5", Name: "Row", MuscleGroup: "Back"}
+	uprightRow := &Exercise{ID: "6", Name: "Rows", MuscleGroup: "Shoulders"}
+
+	pairs := FindDuplicateExercises([]*Exercise{bench, barbellBench, pulldown, pullDown, row, uprightRow}, DefaultDuplicateThreshold)
+
+	require.Len(t, pairs, 2, "exercises of different muscle groups aren't paired")
+	assert.Equal(t, bench, pairs[0].Exercise)
+	assert.Equal(t, barbellBench, pairs[0].Duplicate)
+	assert.Equal(t, pulldown, pairs[1].Exercise)
+	assert.Equal(t, pullDown, pairs[1].Duplicate)
+}
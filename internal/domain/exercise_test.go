@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExercise_VisibleTo(t *testing.T) {
+	global := &Exercise{Name: "Squat"}
+	private := &Exercise{Name: "House Squat", TenantID: "tenant-1"}
+
+	assert.True(t, global.VisibleTo("tenant-1"))
+	assert.True(t, global.VisibleTo(""))
+	assert.True(t, private.VisibleTo("tenant-1"))
+	assert.False(t, private.VisibleTo("tenant-2"))
+	assert.False(t, private.VisibleTo(""))
+}
+
+func TestMergeExerciseLibraries(t *testing.T) {
+	squat := &Exercise{ID: "1", Name: "Squat"}
+	bench := &Exercise{ID: "2", Name: "Bench Press"}
+	ourSquat := &Exercise{ID: "3", Name: " squat ", TenantID: "tenant-1"}
+	sled := &Exercise{ID: "4", Name: "Sled Push", TenantID: "tenant-1"}
+
+	merged := MergeExerciseLibraries([]*Exercise{squat, bench, ourSquat, sled})
+
+	assert.Equal(t, []*Exercise{bench, ourSquat, sled}, merged, "the tenant's squat replaces the global one")
+}
+
+func TestValidateExerciseScope(t *testing.T) {
+	for _, scope := range []string{"", ExerciseScopeAll, ExerciseScopeGlobal, ExerciseScopeTenant} {
+		assert.NoError(t, ValidateExerciseScope(scope), scope)
+	}
+	assert.ErrorIs(t, ValidateExerciseScope("private"), ErrInvalidExerciseScope)
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrVideoResolution = errors.New("video resolution is too high: resize it to 1080p or lower before uploading")
+
+// Bounds for exercise demonstration clips uploaded without a VideoTranscoder, so what is
+// streamed to phones stays a sensible size
+const (
+	MaxDemoVideoLongSide  = 1920
+	MaxDemoVideoShortSide = 1080
+)
+
+// ExerciseVideo is a demonstration clip uploaded for an exercise. Its file isn't public;
+// apps play it through short-lived signed URLs.
+type ExerciseVideo struct {
+	URL             string    `json:"-" bson:"url"` // As returned by FileRepository.Upload
+	ContentType     string    `json:"content_type" bson:"content_type"`
+	SizeBytes       int64     `json:"size_bytes" bson:"size_bytes"`
+	DurationSeconds float64   `json:"duration_seconds" bson:"duration_seconds"`
+	Transcoded      bool      `json:"transcoded" bson:"transcoded"`
+	UploadedBy      string    `json:"uploaded_by" bson:"uploaded_by"`
+	UploadedAt      time.Time `json:"uploaded_at" bson:"uploaded_at"`
+}
+
+// VideoTranscoder prepares an uploaded clip for streaming, e.g. re-encoding it to H.264 at
+// 720p, and returns the result with its content type. It is the hook for whatever pipeline
+// a deployment has; without one, uploads must already be sized for streaming.
+type VideoTranscoder interface {
+	Transcode(ctx context.Context, video []byte, contentType string) ([]byte, string, error)
+}
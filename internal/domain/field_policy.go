@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// FieldPolicy lists, per role, the JSON fields of a resource that role may see of it. A role
+// mapped to nil sees every field. Responses are filtered by the caller's most privileged
+// role, so policies are defined here once instead of as trimmed copies in each handler.
+type FieldPolicy map[string][]string
+
+// viewerRoles orders roles from most to least privileged
+var viewerRoles = []string{RoleSuperAdmin, RoleTenantAdmin, RoleCoach, RoleMember}
+
+// ViewerRole returns the most privileged of roles. Callers without a known role are treated
+// as members, who see the least.
+func ViewerRole(roles []string) string {
+	for _, role := range viewerRoles {
+		if slices.Contains(roles, role) {
+			return role
+		}
+	}
+	return RoleMember
+}
+
+// Filter serializes v keeping only the fields a caller with roles may see
+func (p FieldPolicy) Filter(v any, roles []string) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	allowed, ok := p[ViewerRole(roles)]
+	out := make(map[string]any, len(fields))
+	for name, value := range fields {
+		if ok && allowed == nil || slices.Contains(allowed, name) {
+			out[name] = value
+		}
+	}
+	return out, nil
+}
+
+// UserFieldPolicy is what each role sees of other users. Coaches get what they need to work
+// with their members, not entitlement or login details; members only see who their coach is.
+// Tenant admins don't see the auth provider's ID or the user's memberships in other tenants.
+var UserFieldPolicy = FieldPolicy{
+	RoleSuperAdmin: nil,
+	RoleTenantAdmin: {
+		"id", "email", "name", "roles", "tenant_id", "home_branch_id", "branch_access", "avatar_url",
+		"date_of_birth", "demo", "working_branch_ids", "first_login_at", "last_login_at", "login_count",
+		"created_at", "updated_at", "deleted_at", "version", "trial_end_date", "subscription_end_date",
+		"custom_fields",
+	},
+	RoleCoach: {
+		"id", "email", "name", "roles", "tenant_id", "home_branch_id", "branch_access", "avatar_url", "date_of_birth", "created_at",
+		"custom_fields",
+	},
+	RoleMember: {"id", "name", "avatar_url", "home_branch_id", "working_branch_ids"},
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewerRole(t *testing.T) {
+	assert.Equal(t, RoleTenantAdmin, ViewerRole([]string{RoleMember, RoleCoach, RoleTenantAdmin}))
+	assert.Equal(t, RoleCoach, ViewerRole([]string{RoleMember, RoleCoach}))
+	assert.Equal(t, RoleMember, ViewerRole(nil))
+}
+
+func TestUserFieldPolicy(t *testing.T) {
+	end := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	user := &User{ID: "u1", FirebaseUID: "fb-1", Email: "coach@gym.test", Name: "Coach", Roles: []string{RoleCoach},
+		SubscriptionEndDate: &end, Memberships: []TenantMembership{{TenantID: "other-gym"}}}
+
+	t.Run("members don't see emails", func(t *testing.T) {
+		fields, err := UserFieldPolicy.Filter(user, []string{RoleMember})
+		require.NoError(t, err)
+		assert.Contains(t, fields, "name")
+		assert.NotContains(t, fields, "email")
+	})
+
+	t.Run("coaches don't see entitlements", func(t *testing.T) {
+		fields, err := UserFieldPolicy.Filter(user, []string{RoleMember, RoleCoach})
+		require.NoError(t, err)
+		assert.Contains(t, fields, "email")
+		assert.NotContains(t, fields, "subscription_end_date")
+		assert.NotContains(t, fields, "firebase_uid")
+	})
+
+	t.Run("tenant admins don't see other tenants", func(t *testing.T) {
+		fields, err := UserFieldPolicy.Filter(user, []string{RoleTenantAdmin})
+		require.NoError(t, err)
+		assert.Contains(t, fields, "subscription_end_date")
+		assert.NotContains(t, fields, "memberships")
+	})
+
+	t.Run("super admins see everything", func(t *testing.T) {
+		fields, err := UserFieldPolicy.Filter(user, []string{RoleSuperAdmin})
+		require.NoError(t, err)
+		assert.Contains(t, fields, "memberships")
+		assert.Contains(t, fields, "firebase_uid")
+	})
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
 // FileRepository defines the interface for file storage operations
@@ -9,6 +10,13 @@ type FileRepository interface {
 	// Upload saves a file and returns its access URL
 	Upload(ctx context.Context, file []byte, filename string, contentType string) (string, error)
 
+	// Download returns the contents of a file previously returned by Upload
+	Download(ctx context.Context, fileURL string) ([]byte, error)
+
 	// Delete removes a file from storage
 	Delete(ctx context.Context, fileURL string) error
+
+	// SignedURL returns a URL that grants read access to a file previously returned by
+	// Upload until ttl has passed
+	SignedURL(ctx context.Context, fileURL string, ttl time.Duration) (string, error)
 }
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrAIQuotaExceeded = errors.New("monthly AI quota exceeded")
+
+// Form feedback statuses
+const (
+	FormFeedbackPending = "pending"
+	FormFeedbackDone    = "done"
+	FormFeedbackFailed  = "failed"
+)
+
+// FormFeedback is the AI review of a form-check video. It is queued when the video is
+// uploaded and filled in by a background job.
+type FormFeedback struct {
+	Status       string            `json:"status" bson:"status"`
+	Summary      string            `json:"summary,omitempty" bson:"summary,omitempty"`
+	Observations []FormObservation `json:"observations,omitempty" bson:"observations,omitempty"`
+	Model        string            `json:"model,omitempty" bson:"model,omitempty"`
+	Error        string            `json:"error,omitempty" bson:"error,omitempty"`
+	RequestedAt  time.Time         `json:"requested_at" bson:"requested_at"`
+	AnalyzedAt   *time.Time        `json:"analyzed_at,omitempty" bson:"analyzed_at,omitempty"`
+}
+
+// FormObservation is one thing the model noticed, with a cue the member can act on
+type FormObservation struct {
+	Issue     string   `json:"issue" bson:"issue"`
+	Cue       string   `json:"cue" bson:"cue"`
+	Severity  string   `json:"severity" bson:"severity"` // "info", "minor" or "major"
+	AtSeconds *float64 `json:"at_seconds,omitempty" bson:"at_seconds,omitempty"`
+}
+
+// VideoFrame is a still taken from a video
+type VideoFrame struct {
+	AtSeconds float64
+	Image     []byte // JPEG
+}
+
+// FrameExtractor takes evenly spaced stills from a stored video
+type FrameExtractor interface {
+	ExtractFrames(ctx context.Context, videoURL string, duration time.Duration, count int) ([]VideoFrame, error)
+}
+
+// FormAnalyzer reviews the technique shown in frames of a set of the named exercise
+type FormAnalyzer interface {
+	AnalyzeForm(ctx context.Context, exercise string, frames []VideoFrame) (*FormFeedback, error)
+}
+
+// AIUsageRepository counts the AI requests each tenant makes per calendar month
+type AIUsageRepository interface {
+	// Consume records one request in the month of at, or returns ErrAIQuotaExceeded if
+	// the tenant already made limit requests that month. A limit of 0 means unlimited.
+	Consume(ctx context.Context, tenantID string, at time.Time, limit int) error
+}
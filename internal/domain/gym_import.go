@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrUnknownImportSource = errors.New("unknown import source: use glofox or mindbody")
+	ErrInvalidImportFile   = errors.New("invalid import file")
+	ErrInvalidImportCoach  = errors.New("import coach must be a coach of this tenant")
+	ErrImportStarted       = errors.New("import has already been started")
+)
+
+// Gym management systems a tenant can migrate from
+const (
+	ImportSourceGlofox   = "glofox"
+	ImportSourceMindbody = "mindbody"
+)
+
+// Export files of a gym import. Only members is required; memberships become contracts
+// and bookings become schedules.
+const (
+	ImportFileMembers     = "members"
+	ImportFileMemberships = "memberships"
+	ImportFileBookings    = "bookings"
+)
+
+// Gym import statuses. An import is created as a preview and only writes anything once
+// it is started.
+const (
+	GymImportStatusPreview   = "preview"
+	GymImportStatusRunning   = "running"
+	GymImportStatusCompleted = "completed"
+	GymImportStatusFailed    = "failed"
+)
+
+// ImportedMember is a member row of an export, keyed by the source system's ID
+type ImportedMember struct {
+	ExternalID string `json:"external_id" bson:"external_id"`
+	Name       string `json:"name" bson:"name"`
+	Email      string `json:"email" bson:"email"`
+	Phone      string `json:"phone,omitempty" bson:"phone,omitempty"`
+}
+
+// ImportedMembership is a session pack a member bought in the source system
+type ImportedMembership struct {
+	ExternalID        string     `json:"external_id" bson:"external_id"`
+	MemberExternalID  string     `json:"member_external_id" bson:"member_external_id"`
+	Name              string     `json:"name" bson:"name"`
+	TotalSessions     int        `json:"total_sessions" bson:"total_sessions"`
+	RemainingSessions int        `json:"remaining_sessions" bson:"remaining_sessions"`
+	Price             float64    `json:"price" bson:"price"` // As in the source file, in major units of DefaultCurrency
+	StartDate         time.Time  `json:"start_date" bson:"start_date"`
+	EndDate           *time.Time `json:"end_date,omitempty" bson:"end_date,omitempty"`
+}
+
+// ImportedBooking is a past or upcoming session; Status is already a Schedule status
+type ImportedBooking struct {
+	ExternalID       string    `json:"external_id" bson:"external_id"`
+	MemberExternalID string    `json:"member_external_id" bson:"member_external_id"`
+	Coach            string    `json:"coach,omitempty" bson:"coach,omitempty"` // Name or email as exported
+	StartTime        time.Time `json:"start_time" bson:"start_time"`
+	EndTime          time.Time `json:"end_time" bson:"end_time"`
+	Status           string    `json:"status" bson:"status"`
+}
+
+// GymImportData is what the adapters read from the export files
+type GymImportData struct {
+	Members     []ImportedMember     `json:"members" bson:"members"`
+	Memberships []ImportedMembership `json:"memberships" bson:"memberships"`
+	Bookings    []ImportedBooking    `json:"bookings" bson:"bookings"`
+}
+
+// GymImportIssue is a row left out of the import and why
+type GymImportIssue struct {
+	File       string `json:"file" bson:"file"`
+	Row        int    `json:"row,omitempty" bson:"row,omitempty"` // Line in the file, for rows that couldn't be read
+	ExternalID string `json:"external_id,omitempty" bson:"external_id,omitempty"`
+	Message    string `json:"message" bson:"message"`
+}
+
+// GymImportSummary counts the rows that will be imported
+type GymImportSummary struct {
+	NewMembers      int `json:"new_members" bson:"new_members"`
+	ExistingMembers int `json:"existing_members" bson:"existing_members"` // Matched to users of the tenant by email
+	Memberships     int `json:"memberships" bson:"memberships"`
+	Bookings        int `json:"bookings" bson:"bookings"`
+	Skipped         int `json:"skipped" bson:"skipped"` // Rows with an issue
+}
+
+// GymImportResult counts what a run created
+type GymImportResult struct {
+	MembersCreated   int `json:"members_created" bson:"members_created"`
+	MembersMatched   int `json:"members_matched" bson:"members_matched"`
+	PackagesCreated  int `json:"packages_created" bson:"packages_created"`
+	ContractsCreated int `json:"contracts_created" bson:"contracts_created"`
+	SchedulesCreated int `json:"schedules_created" bson:"schedules_created"`
+}
+
+// GymImportProgress maps the external IDs already imported to the records created for
+// them, so a failed run can be retried without duplicating anything
+type GymImportProgress struct {
+	Members   map[string]string `bson:"members,omitempty"`
+	Contracts map[string]string `bson:"contracts,omitempty"`
+	Schedules map[string]string `bson:"schedules,omitempty"`
+}
+
+// GymImport is a tenant's migration from another gym management system
+type GymImport struct {
+	ID        string            `json:"id" bson:"_id,omitempty"`
+	TenantID  string            `json:"tenant_id" bson:"tenant_id"`
+	BranchID  string            `json:"branch_id" bson:"branch_id"`
+	CoachID   string            `json:"coach_id" bson:"coach_id"` // Gets contracts, and bookings whose trainer isn't matched
+	Source    string            `json:"source" bson:"source"`
+	Status    string            `json:"status" bson:"status"`
+	Summary   GymImportSummary  `json:"summary" bson:"summary"`
+	Issues    []GymImportIssue  `json:"issues,omitempty" bson:"issues,omitempty"`
+	Preview   *GymImportData    `json:"preview,omitempty" bson:"-"` // First rows of each file, for review
+	Data      *GymImportData    `json:"-" bson:"data,omitempty"`
+	Progress  GymImportProgress `json:"-" bson:"progress"`
+	Result    GymImportResult   `json:"result" bson:"result"`
+	Error     string            `json:"error,omitempty" bson:"error,omitempty"`
+	CreatedBy string            `json:"created_by" bson:"created_by"`
+
+	CreatedAt  time.Time  `json:"created_at" bson:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty" bson:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty" bson:"finished_at,omitempty"`
+}
+
+// GymImportAdapter reads the export files of one gym management system
+type GymImportAdapter interface {
+	Source() string
+	// Parse reads one export file; times without a zone are in loc. Rows that can't be
+	// read are returned as issues, while a file that isn't the expected export is an error.
+	Parse(file string, data []byte, loc *time.Location) (*GymImportData, []GymImportIssue, error)
+}
+
+type GymImportRepository interface {
+	Create(ctx context.Context, imp *GymImport) error
+	GetByID(ctx context.Context, tenantID, id string) (*GymImport, error)
+	// Start moves a previewed import to running; ErrImportStarted if it isn't a preview
+	Start(ctx context.Context, tenantID, id string, at time.Time) error
+	// Update saves status, progress, result, issues and error
+	Update(ctx context.Context, imp *GymImport) error
+	// ListByTenant returns the tenant's imports newest first, without their data
+	ListByTenant(ctx context.Context, tenantID string, limit int) ([]*GymImport, error)
+}
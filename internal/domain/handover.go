@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Why a client changed coach
+const (
+	HandoverReasonCover    = "cover"             // A substitute took over one session
+	HandoverReasonTransfer = "contract_transfer" // The contract moved to another coach for good
+)
+
+// HandoverRecentSessions is how many of the member's last completed sessions a handover shows
+const HandoverRecentSessions = 3
+
+// Bounds for the notes coaches keep on a client
+const (
+	MaxClientNoteItems  = 20
+	MaxClientNoteLength = 500
+)
+
+var (
+	ErrHandoverNotFound       = errors.New("handover not found")
+	ErrInvalidClientNotes     = errors.New("client notes take up to 20 injuries and 20 preferences of up to 500 characters each")
+	ErrInvalidContractHandoff = errors.New("only a contract with sessions left can move, and only to another coach")
+)
+
+// ClientNotes is what coaches keep on record about a member at a tenant, for whoever
+// coaches them next
+type ClientNotes struct {
+	TenantID    string    `json:"tenant_id" bson:"tenant_id"`
+	MemberID    string    `json:"member_id" bson:"member_id"`
+	Injuries    []string  `json:"injuries" bson:"injuries"`       // e.g. "Left knee: no deep squats"
+	Preferences []string  `json:"preferences" bson:"preferences"` // e.g. "Prefers morning sessions"
+	UpdatedBy   string    `json:"updated_by,omitempty" bson:"updated_by,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// Validate bounds the number and length of the notes
+func (n *ClientNotes) Validate() error {
+	for _, items := range [][]string{n.Injuries, n.Preferences} {
+		if len(items) > MaxClientNoteItems {
+			return ErrInvalidClientNotes
+		}
+		for _, item := range items {
+			if item == "" || len(item) > MaxClientNoteLength {
+				return ErrInvalidClientNotes
+			}
+		}
+	}
+	return nil
+}
+
+// Handover briefs a coach taking over a client: assembled when a substitute claims a
+// session or a contract moves to them, and kept as it was then. The receiving coach
+// acknowledges having read it.
+type Handover struct {
+	ID          string `json:"id" bson:"_id,omitempty"`
+	TenantID    string `json:"tenant_id" bson:"tenant_id"`
+	MemberID    string `json:"member_id" bson:"member_id"`
+	MemberName  string `json:"member_name" bson:"member_name"`
+	ContractID  string `json:"contract_id,omitempty" bson:"contract_id,omitempty"`
+	ScheduleID  string `json:"schedule_id,omitempty" bson:"schedule_id,omitempty"` // The covered session
+	Reason      string `json:"reason" bson:"reason"`
+	FromCoachID string `json:"from_coach_id" bson:"from_coach_id"`
+	ToCoachID   string `json:"to_coach_id" bson:"to_coach_id"`
+	Note        string `json:"note,omitempty" bson:"note,omitempty"` // From whoever moved the contract
+
+	Program        []HandoverExercise `json:"program" bson:"program"` // What the member has been training lately
+	Injuries       []string           `json:"injuries" bson:"injuries"`
+	Preferences    []string           `json:"preferences" bson:"preferences"`
+	RecentSessions []HandoverSession  `json:"recent_sessions" bson:"recent_sessions"` // Newest first
+
+	CreatedAt      time.Time  `json:"created_at" bson:"created_at"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty" bson:"acknowledged_at,omitempty"`
+}
+
+// HandoverExercise is an exercise of the member's current program with the last top set
+type HandoverExercise struct {
+	ExerciseID string    `json:"exercise_id" bson:"exercise_id"`
+	Name       string    `json:"name" bson:"name"`
+	Weight     float64   `json:"weight" bson:"weight"`
+	Reps       int       `json:"reps" bson:"reps"`
+	LastDone   time.Time `json:"last_done" bson:"last_done"`
+}
+
+// HandoverSession sums up one of the member's recent sessions
+type HandoverSession struct {
+	ScheduleID  string    `json:"schedule_id" bson:"schedule_id"`
+	Date        time.Time `json:"date" bson:"date"`
+	CoachID     string    `json:"coach_id" bson:"coach_id"`
+	SessionGoal string    `json:"session_goal,omitempty" bson:"session_goal,omitempty"`
+	Remarks     string    `json:"remarks,omitempty" bson:"remarks,omitempty"`
+	RPE         *int      `json:"rpe,omitempty" bson:"rpe,omitempty"`
+	Exercises   []string  `json:"exercises" bson:"exercises"` // Names, in the order first logged
+	Sets        int       `json:"sets" bson:"sets"`
+	Volume      float64   `json:"volume" bson:"volume"` // kg
+}
+
+type HandoverRepository interface {
+	Create(ctx context.Context, handover *Handover) error
+	// GetByID returns ErrHandoverNotFound for unknown IDs
+	GetByID(ctx context.Context, id string) (*Handover, error)
+	// ListByCoach returns the handovers to the coach at the tenant, newest first
+	ListByCoach(ctx context.Context, tenantID, coachID string, pendingOnly bool) ([]*Handover, error)
+	// Acknowledge marks the coach's handover read; acknowledging again keeps the first time.
+	// Returns ErrHandoverNotFound unless the handover is to the coach.
+	Acknowledge(ctx context.Context, id, coachID string, at time.Time) error
+	// GetNotes returns the member's notes at the tenant, empty when none were saved
+	GetNotes(ctx context.Context, tenantID, memberID string) (*ClientNotes, error)
+	SaveNotes(ctx context.Context, notes *ClientNotes) error
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrHealthConsentRequired   = errors.New("member has not consented to the processing of their health data")
+	ErrHealthConsentOutdated   = errors.New("the health data policy has changed; review the current version before consenting")
+	ErrInvalidHealthDataPolicy = errors.New("health data policy text is required and must differ from the current version")
+)
+
+// Consent statuses, as shown to the member and their coaches
+const (
+	HealthConsentGranted   = "granted"   // Consented to the current policy
+	HealthConsentOutdated  = "outdated"  // Consented to an earlier policy; must consent again
+	HealthConsentWithdrawn = "withdrawn" // Withdrew consent
+	HealthConsentMissing   = "missing"   // Never asked, or never answered
+)
+
+// DefaultHealthDataPolicyText is version 1 of the policy, in force until the platform
+// publishes its own
+const DefaultHealthDataPolicyText = `We process the body composition scans you or your coach upload (InBody results such as weight, body fat, muscle mass and water) to track your progress and plan your training. Scan images are read by an AI service to extract these values. Your results are visible to you and to the coaches of your gym, and are never sold or used for advertising. You can withdraw this consent at any time; scans will then no longer be processed, and you can ask your gym to delete the ones already stored.`
+
+// HealthDataPolicy is a version of the text members consent to before their scans are
+// processed. Every change of the text is a new version, and consent to an earlier version
+// no longer counts.
+type HealthDataPolicy struct {
+	ID          string    `json:"id,omitempty" bson:"_id,omitempty"`
+	Version     int       `json:"version" bson:"version"`
+	Text        string    `json:"text" bson:"text"`
+	Digest      string    `json:"digest" bson:"digest"` // SHA-256 of Text, stored with each consent
+	PublishedBy string    `json:"published_by,omitempty" bson:"published_by,omitempty"`
+	PublishedAt time.Time `json:"published_at" bson:"published_at"`
+}
+
+// NewHealthDataPolicy returns a version of the policy with text, or ErrInvalidHealthDataPolicy
+// for an empty text
+func NewHealthDataPolicy(version int, text string) (*HealthDataPolicy, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, ErrInvalidHealthDataPolicy
+	}
+	return &HealthDataPolicy{Version: version, Text: text, Digest: PolicyDigest(text)}, nil
+}
+
+// DefaultHealthDataPolicy is the built-in version 1
+func DefaultHealthDataPolicy() *HealthDataPolicy {
+	policy, _ := NewHealthDataPolicy(1, DefaultHealthDataPolicyText)
+	return policy
+}
+
+// PolicyDigest fingerprints a policy text
+func PolicyDigest(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// HealthConsent records a member granting or withdrawing consent. Records are never
+// changed; the latest one is the member's answer.
+type HealthConsent struct {
+	ID            string    `json:"id" bson:"_id,omitempty"`
+	UserID        string    `json:"user_id" bson:"user_id"`
+	TenantID      string    `json:"tenant_id,omitempty" bson:"tenant_id,omitempty"` // Tenant the member was in when answering
+	Granted       bool      `json:"granted" bson:"granted"`                         // False for a withdrawal
+	PolicyVersion int       `json:"policy_version" bson:"policy_version"`
+	PolicyDigest  string    `json:"policy_digest" bson:"policy_digest"`
+	IPAddress     string    `json:"ip_address,omitempty" bson:"ip_address,omitempty"`
+	UserAgent     string    `json:"user_agent,omitempty" bson:"user_agent,omitempty"`
+	RecordedAt    time.Time `json:"recorded_at" bson:"recorded_at"`
+}
+
+// HealthConsentStatus is where a member stands against the current policy. The apps ask
+// for consent again whenever Current is false.
+type HealthConsentStatus struct {
+	Status  string            `json:"status"`
+	Current bool              `json:"current"` // Scans may be processed
+	Policy  *HealthDataPolicy `json:"policy"`
+	Consent *HealthConsent    `json:"consent,omitempty"` // The member's latest answer
+}
+
+// BuildHealthConsentStatus matches a member's latest answer, nil if none, against policy.
+// Consent only counts for the exact version and text it was given to.
+func BuildHealthConsentStatus(policy *HealthDataPolicy, latest *HealthConsent) *HealthConsentStatus {
+	status := &HealthConsentStatus{Status: HealthConsentMissing, Policy: policy, Consent: latest}
+	switch {
+	case latest == nil:
+	case !latest.Granted:
+		status.Status = HealthConsentWithdrawn
+	case latest.PolicyVersion != policy.Version || latest.PolicyDigest != policy.Digest:
+		status.Status = HealthConsentOutdated
+	default:
+		status.Status = HealthConsentGranted
+		status.Current = true
+	}
+	return status
+}
+
+type HealthConsentRepository interface {
+	// GetCurrentPolicy returns the latest published policy, or ErrNotFound if none was
+	GetCurrentPolicy(ctx context.Context) (*HealthDataPolicy, error)
+	// CreatePolicy publishes a policy; ErrVersionConflict if its version already exists
+	CreatePolicy(ctx context.Context, policy *HealthDataPolicy) error
+	// Record appends a consent or withdrawal
+	Record(ctx context.Context, consent *HealthConsent) error
+	// GetLatest returns the user's latest answer, or ErrNotFound if they never answered
+	GetLatest(ctx context.Context, userID string) (*HealthConsent, error)
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildHealthConsentStatus(t *testing.T) {
+	policy := DefaultHealthDataPolicy()
+	granted := &HealthConsent{Granted: true, PolicyVersion: policy.Version, PolicyDigest: policy.Digest}
+
+	tests := []struct {
+		name    string
+		latest  *HealthConsent
+		status  string
+		current bool
+	}{
+		{"never answered", nil, HealthConsentMissing, false},
+		{"consented to the current policy", granted, HealthConsentGranted, true},
+		{"withdrew", &HealthConsent{Granted: false, PolicyVersion: policy.Version, PolicyDigest: policy.Digest}, HealthConsentWithdrawn, false},
+		{"consented to an earlier version", &HealthConsent{Granted: true, PolicyVersion: 0, PolicyDigest: policy.Digest}, HealthConsentOutdated, false},
+		{"consented to different text", &HealthConsent{Granted: true, PolicyVersion: policy.Version, PolicyDigest: PolicyDigest("other")}, HealthConsentOutdated, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := BuildHealthConsentStatus(policy, tt.latest)
+			assert.Equal(t, tt.status, status.Status)
+			assert.Equal(t, tt.current, status.Current)
+		})
+	}
+}
+
+func TestNewHealthDataPolicy(t *testing.T) {
+	_, err := NewHealthDataPolicy(2, " \n")
+	assert.ErrorIs(t, err, ErrInvalidHealthDataPolicy)
+
+	policy, err := NewHealthDataPolicy(2, " Scans are processed. ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Scans are processed.", policy.Text)
+	assert.Equal(t, PolicyDigest("Scans are processed."), policy.Digest)
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// IdempotencyKeyHeader names the header clients send so a retried request isn't carried out twice
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// MaxIdempotencyKeyLength bounds the header; UUIDs and ULIDs fit with room to spare
+const MaxIdempotencyKeyLength = 255
+
+var (
+	ErrInvalidIdempotencyKey = errors.New("Idempotency-Key must be 1 to 255 printable ASCII characters")
+	ErrIdempotencyKeyInUse   = errors.New("a request with this Idempotency-Key is still being processed")
+	ErrIdempotencyKeyReused  = errors.New("this Idempotency-Key was already used for a different request")
+)
+
+// IdempotentResponse is what a request sent with an Idempotency-Key answered, kept to replay
+// when the request is retried. Until the first request completes only its Fingerprint is known.
+type IdempotentResponse struct {
+	Fingerprint string `json:"fingerprint"` // See RequestFingerprint
+	Completed   bool   `json:"completed"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// IdempotencyStore remembers the responses to requests sent with an Idempotency-Key
+type IdempotencyStore interface {
+	// Begin reserves key for a request with the fingerprint until the lease runs out, so a
+	// key whose request never finished frees itself. It returns nil if the key was free,
+	// otherwise what is stored for it: a completed response or the one in flight.
+	Begin(ctx context.Context, key, fingerprint string, lease time.Duration) (*IdempotentResponse, error)
+	// Complete stores the response to replay for key, kept for ttl
+	Complete(ctx context.Context, key string, response *IdempotentResponse, ttl time.Duration) error
+	// Release frees key, so a request that failed can be retried with it
+	Release(ctx context.Context, key string) error
+}
+
+// ValidIdempotencyKey reports whether key is a usable Idempotency-Key header value
+func ValidIdempotencyKey(key string) bool {
+	if key == "" || len(key) > MaxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// RequestFingerprint identifies what a request asked for, so a key reused for a different
+// request is told apart from a retry
+func RequestFingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
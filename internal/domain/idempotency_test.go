@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidIdempotencyKey(t *testing.T) {
+	assert.True(t, ValidIdempotencyKey("01J0Z8Q8X6N3V5K7M9P1R2S4T6"))
+	assert.True(t, ValidIdempotencyKey("checkout-2b7e1516-28ae-4d2a"))
+	assert.True(t, ValidIdempotencyKey(strings.Repeat("k", MaxIdempotencyKeyLength)))
+
+	assert.False(t, ValidIdempotencyKey(""))
+	assert.False(t, ValidIdempotencyKey(strings.Repeat("k", MaxIdempotencyKeyLength+1)))
+	assert.False(t, ValidIdempotencyKey("two words"))
+	assert.False(t, ValidIdempotencyKey("clé"))
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	body := []byte(`{"package_id":"p1","payment_method":"BCA"}`)
+	fp := RequestFingerprint("POST", "/v1/me/payments/checkout", body)
+
+	assert.Equal(t, fp, RequestFingerprint("POST", "/v1/me/payments/checkout", []byte(`{"package_id":"p1","payment_method":"BCA"}`)), "a retry")
+	assert.NotEqual(t, fp, RequestFingerprint("POST", "/v1/me/payments/checkout", []byte(`{"package_id":"p2","payment_method":"BCA"}`)))
+	assert.NotEqual(t, fp, RequestFingerprint("POST", "/v1/pro/contracts", body))
+	assert.NotEqual(t, fp, RequestFingerprint("PUT", "/v1/me/payments/checkout", body))
+}
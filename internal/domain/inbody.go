@@ -3,15 +3,13 @@ package domain
 import (
 	"context"
 	"time"
-
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // InBodyRecord represents a digitized InBody scan result
 type InBodyRecord struct {
-	ID           string             `bson:"_id,omitempty" json:"id"`
-	UserID       primitive.ObjectID `bson:"user_id" json:"user_id"` // Stored as ObjectID
-	TestDateTime time.Time          `bson:"test_date_time" json:"test_date_time"`
+	ID           string    `bson:"_id,omitempty" json:"id"`
+	UserID       string    `bson:"user_id" json:"user_id"` // Stored with the same type as the user's _id
+	TestDateTime time.Time `bson:"test_date_time" json:"test_date_time"`
 
 	// Core Metrics
 	Weight      float64 `bson:"weight" json:"weight"`
@@ -47,7 +45,15 @@ type InBodyRecord struct {
 	Metadata struct {
 		ImageURL    string    `bson:"image_url" json:"image_url"`
 		ProcessedAt time.Time `bson:"processed_at" json:"processed_at"`
+		Model       string    `bson:"model,omitempty" json:"model,omitempty"`   // AI model behind the current values
+		Source      string    `bson:"source,omitempty" json:"source,omitempty"` // How the scan got here when not digitized, e.g. "lookinbody_csv"
+
+		// Set once the original image has been downscaled by the retention job
+		ImageArchivedAt *time.Time `bson:"image_archived_at,omitempty" json:"image_archived_at,omitempty"`
 	} `bson:"metadata" json:"metadata"`
+
+	// Incremented on every update; Update fails with ErrVersionConflict if stale
+	Version int64 `bson:"version" json:"version"`
 }
 
 // SegmentalData represents body composition for different body segments
@@ -126,15 +132,18 @@ type InBodyMetrics struct {
 	SegmentalLean *SegmentalData `json:"segmental_lean,omitempty"`
 	SegmentalFat  *SegmentalData `json:"segmental_fat,omitempty"`
 	Analysis      *BodyAnalysis  `json:"analysis,omitempty"`
+
+	// Model is filled in by the digitizer, not the AI
+	Model string `json:"-"`
 }
 
 // TrendSummary represents an AI-generated trend recap for a user
 type TrendSummary struct {
-	ID              string             `bson:"_id,omitempty" json:"id"`
-	UserID          primitive.ObjectID `bson:"user_id" json:"user_id"`
-	SummaryText     string             `bson:"summary_text" json:"summary_text"`
-	LastGeneratedAt time.Time          `bson:"last_generated_at" json:"last_generated_at"`
-	IncludedScanIDs []string           `bson:"included_scan_ids" json:"included_scan_ids"`
+	ID              string    `bson:"_id,omitempty" json:"id"`
+	UserID          string    `bson:"user_id" json:"user_id"`
+	SummaryText     string    `bson:"summary_text" json:"summary_text"`
+	LastGeneratedAt time.Time `bson:"last_generated_at" json:"last_generated_at"`
+	IncludedScanIDs []string  `bson:"included_scan_ids" json:"included_scan_ids"`
 }
 
 // InBodyRepository defines the interface for InBodyRecord persistence
@@ -143,6 +152,9 @@ type InBodyRepository interface {
 	// Create saves a new InBodyRecord to the database
 	Create(ctx context.Context, record *InBodyRecord) error
 
+	// CreateMany saves several new records at once
+	CreateMany(ctx context.Context, records []*InBodyRecord) error
+
 	// GetLatestByUserID retrieves the most recent scan for a user
 	GetLatestByUserID(ctx context.Context, userID string) (*InBodyRecord, error)
 
@@ -155,7 +167,8 @@ type InBodyRepository interface {
 	// FindByID retrieves a single scan by its ID
 	FindByID(ctx context.Context, id string) (*InBodyRecord, error)
 
-	// Update modifies an existing scan record
+	// Update modifies an existing scan record if record.Version still matches the stored version.
+	// Returns ErrVersionConflict otherwise; on success record.Version is incremented.
 	Update(ctx context.Context, id string, record *InBodyRecord) error
 
 	// Delete removes a scan record by its ID
@@ -177,6 +190,13 @@ type InBodyRepository interface {
 	// FindPaginatedByUserID retrieves scans with cursor-based pagination and date filtering
 	// Returns lightweight ScanListItem records for efficient list rendering
 	FindPaginatedByUserID(ctx context.Context, userID string, query *ScanListQuery) (*ScanListResult, error)
+
+	// ListOriginalImages returns up to limit scans processed before the cutoff whose
+	// original image hasn't been archived yet, oldest first
+	ListOriginalImages(ctx context.Context, before time.Time, limit int) ([]*InBodyRecord, error)
+
+	// SetImageArchived points a scan at its archived image without touching its version
+	SetImageArchived(ctx context.Context, id string, imageURL string, at time.Time) error
 }
 
 // CacheRepository defines the interface for caching operations
@@ -212,6 +232,20 @@ type CacheRepository interface {
 	// InvalidateScan removes a cached scan by its ID
 	InvalidateScan(ctx context.Context, scanID string) error
 
+	// SetAnalyticsHistory caches a user's analytics history for one limit with TTL
+	SetAnalyticsHistory(ctx context.Context, userID string, limit int, entry *CachedAnalyticsHistory, ttl time.Duration) error
+
+	// GetAnalyticsHistory retrieves a cached analytics history
+	// Returns nil if not found or expired
+	GetAnalyticsHistory(ctx context.Context, userID string, limit int) (*CachedAnalyticsHistory, error)
+
+	// MarkScansChanged records when a user's scans last changed, so cached histories
+	// computed before then are known to be stale
+	MarkScansChanged(ctx context.Context, userID string, at time.Time, ttl time.Duration) error
+
+	// GetScansChangedAt returns when a user's scans last changed, or zero if not recorded
+	GetScansChangedAt(ctx context.Context, userID string) (time.Time, error)
+
 	// Member Endpoint Caching Methods
 
 	// SetMemberDashboard caches member dashboard data
@@ -267,9 +301,29 @@ type ScanService interface {
 	// GetScanByID retrieves a single scan with ownership verification
 	GetScanByID(ctx context.Context, userID string, scanID string) (*InBodyRecord, error)
 
-	// UpdateScan updates specific metrics with ownership verification
+	// UpdateScan updates specific metrics with ownership verification.
+	// If updates contains "version", it must match the stored version (ErrVersionConflict otherwise).
 	UpdateScan(ctx context.Context, userID string, scanID string, updates map[string]interface{}) (*InBodyRecord, error)
 
 	// DeleteScan removes a scan and its associated image with ownership verification
 	DeleteScan(ctx context.Context, userID string, scanID string) error
+
+	// SaveCorrection stores corrected, an edited copy of original, and records which
+	// values changedBy changed as a ScanRevision
+	SaveCorrection(ctx context.Context, original *InBodyRecord, corrected *InBodyRecord, changedBy string) error
+
+	// ReExtract runs the stored image through the AI again and replaces the scan's values.
+	// The values it replaces, coach corrections included, are kept as a ScanRevision.
+	ReExtract(ctx context.Context, scanID string, changedBy string) (*InBodyRecord, error)
+
+	// ImportCSV adds the scans of a LookinBody CSV export to the user's history, skipping
+	// those already there. Times without a zone are read in loc.
+	ImportCSV(ctx context.Context, userID string, data []byte, loc *time.Location) (*ScanImportReport, error)
+
+	// ListRevisions returns the scan's revision history, newest first
+	ListRevisions(ctx context.Context, scanID string) ([]*ScanRevision, error)
+
+	// RevertScan restores the values kept in a revision. The values it replaces become a
+	// revision of their own, so a revert can be undone.
+	RevertScan(ctx context.Context, scanID string, revisionID string, changedBy string) (*InBodyRecord, error)
 }
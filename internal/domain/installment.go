@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// PackageStatusSuspended marks a contract whose installment is overdue beyond the grace
+// period. No sessions can be booked until the member catches up.
+const PackageStatusSuspended = "Suspended"
+
+// InvoiceStatusPartiallyPaid is an installment invoice with some but not all of it paid
+const InvoiceStatusPartiallyPaid = "partially_paid"
+
+// Installment plan bounds
+const (
+	MinInstallments = 2
+	MaxInstallments = 12
+)
+
+var (
+	ErrInvalidInstallments   = errors.New("installments need 2 to 12 positive amounts adding up to the contract price, due in order")
+	ErrInstallmentPlanPaid   = errors.New("this installment plan is already paid")
+	ErrInstallmentPlanExists = errors.New("contract already has an installment plan")
+	ErrContractSuspended     = errors.New("contract is suspended until the overdue installment is paid")
+)
+
+// Installment is one part of a contract's price, due on a date
+type Installment struct {
+	Number           int        `json:"number" bson:"number"` // 1-based
+	Amount           int64      `json:"amount" bson:"amount"` // Minor units of the invoice's currency
+	DueDate          time.Time  `json:"due_date" bson:"due_date"`
+	PaidAmount       int64      `json:"paid_amount" bson:"paid_amount"`
+	PaidAt           *time.Time `json:"paid_at,omitempty" bson:"paid_at,omitempty"` // When it was paid in full
+	PaymentSessionID string     `json:"-" bson:"payment_session_id,omitempty"`      // Last VA issued for it
+}
+
+// Outstanding is what is left to pay on the installment
+func (i *Installment) Outstanding() int64 {
+	return max(i.Amount-i.PaidAmount, 0)
+}
+
+// InvoicePayment is one payment received for an invoice, reported by the payment webhook
+// or recorded by the front desk
+type InvoicePayment struct {
+	Reference string    `json:"reference" bson:"reference"` // Provider session and transaction, for de-duplication
+	Amount    int64     `json:"amount" bson:"amount"`
+	PaidAt    time.Time `json:"paid_at" bson:"paid_at"`
+
+	// Manual payments; Channel is empty for payments recorded before it was tracked
+	Channel    string `json:"channel,omitempty" bson:"channel,omitempty"` // provider, cash, bank_transfer
+	RecordedBy string `json:"recorded_by,omitempty" bson:"recorded_by,omitempty"`
+	Receipt    string `json:"receipt,omitempty" bson:"receipt,omitempty"` // Receipt number or bank transfer reference
+	Note       string `json:"note,omitempty" bson:"note,omitempty"`
+}
+
+// ValidateInstallments checks a plan: within bounds, positive amounts, strictly later due
+// dates, and a total matching the contract price
+func ValidateInstallments(installments []Installment, total int64) error {
+	if len(installments) < MinInstallments || len(installments) > MaxInstallments {
+		return ErrInvalidInstallments
+	}
+	var sum int64
+	for i, inst := range installments {
+		if inst.Amount <= 0 || inst.DueDate.IsZero() {
+			return ErrInvalidInstallments
+		}
+		if i > 0 && !inst.DueDate.After(installments[i-1].DueDate) {
+			return ErrInvalidInstallments
+		}
+		sum += inst.Amount
+	}
+	if sum != total {
+		return ErrInvalidInstallments
+	}
+	return nil
+}
+
+// IsInstallmentPlan reports whether the invoice is paid in installments
+func (inv *Invoice) IsInstallmentPlan() bool {
+	return len(inv.Installments) > 0
+}
+
+// NextInstallment returns the earliest installment not yet paid in full, or nil
+func (inv *Invoice) NextInstallment() *Installment {
+	for i := range inv.Installments {
+		if inv.Installments[i].Outstanding() > 0 {
+			return &inv.Installments[i]
+		}
+	}
+	return nil
+}
+
+// ApplyPayment spreads a payment over the installments in order and updates the status.
+// Anything paid beyond the total is kept in PaidAmount.
+func (inv *Invoice) ApplyPayment(p InvoicePayment) {
+	inv.Payments = append(inv.Payments, p)
+	inv.PaidAmount += p.Amount
+
+	left := p.Amount
+	for i := range inv.Installments {
+		inst := &inv.Installments[i]
+		due := inst.Outstanding()
+		if due == 0 || left == 0 {
+			continue
+		}
+		paid := min(due, left)
+		inst.PaidAmount += paid
+		left -= paid
+		if inst.Outstanding() == 0 {
+			at := p.PaidAt
+			inst.PaidAt = &at
+		}
+	}
+
+	switch {
+	case inv.NextInstallment() == nil:
+		inv.Status = InvoiceStatusPaid
+	case inv.PaidAmount > 0:
+		inv.Status = InvoiceStatusPartiallyPaid
+	}
+}
+
+// OverdueSince returns the due date of the earliest unpaid installment if it is before
+// now, and whether there is one
+func (inv *Invoice) OverdueSince(now time.Time) (time.Time, bool) {
+	next := inv.NextInstallment()
+	if next == nil || !next.DueDate.Before(now) {
+		return time.Time{}, false
+	}
+	return next.DueDate, true
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateInstallments(t *testing.T) {
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	plan := []Installment{
+		{Amount: 1_000_000, DueDate: day},
+		{Amount: 1_000_000, DueDate: day.AddDate(0, 1, 0)},
+		{Amount: 1_000_000, DueDate: day.AddDate(0, 2, 0)},
+	}
+	assert.NoError(t, ValidateInstallments(plan, 3_000_000))
+	assert.ErrorIs(t, ValidateInstallments(plan, 2_500_000), ErrInvalidInstallments)
+	assert.ErrorIs(t, ValidateInstallments(plan[:1], 1_000_000), ErrInvalidInstallments)
+
+	outOfOrder := []Installment{plan[1], plan[0]}
+	assert.ErrorIs(t, ValidateInstallments(outOfOrder, 2_000_000), ErrInvalidInstallments)
+}
+
+func TestInvoice_ApplyPayment(t *testing.T) {
+	due := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	inv := &Invoice{Amount: NewMoney(300, "IDR"), Installments: []Installment{
+		{Number: 1, Amount: 100, DueDate: due},
+		{Number: 2, Amount: 100, DueDate: due.AddDate(0, 1, 0)},
+		{Number: 3, Amount: 100, DueDate: due.AddDate(0, 2, 0)},
+	}}
+
+	inv.ApplyPayment(InvoicePayment{Amount: 60, PaidAt: due})
+	assert.Equal(t, InvoiceStatusPartiallyPaid, inv.Status)
+	assert.Nil(t, inv.Installments[0].PaidAt)
+	since, overdue := inv.OverdueSince(due.AddDate(0, 0, 3))
+	require.True(t, overdue)
+	assert.Equal(t, due, since)
+
+	// Pays off the first and half of the second
+	inv.ApplyPayment(InvoicePayment{Amount: 90, PaidAt: due.AddDate(0, 0, 2)})
+	require.NotNil(t, inv.Installments[0].PaidAt)
+	assert.Equal(t, int64(50), inv.Installments[1].PaidAmount)
+	assert.Equal(t, 2, inv.NextInstallment().Number)
+	_, overdue = inv.OverdueSince(due.AddDate(0, 0, 3))
+	assert.False(t, overdue)
+
+	inv.ApplyPayment(InvoicePayment{Amount: 150, PaidAt: due.AddDate(0, 2, 0)})
+	assert.Equal(t, InvoiceStatusPaid, inv.Status)
+	assert.Nil(t, inv.NextInstallment())
+	assert.Len(t, inv.Payments, 3)
+}
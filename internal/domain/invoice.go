@@ -17,8 +17,9 @@ const (
 type Invoice struct {
 	ID               string    `bson:"_id,omitempty" json:"id"`
 	UserID           string    `bson:"user_id,omitempty" json:"user_id"`
+	TenantID         string    `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"` // Tenant the member checked out in
 	PackageID        string    `bson:"package_id,omitempty" json:"package_id"`
-	Amount           int64     `bson:"amount,omitempty" json:"amount"` // Amount in smallest currency unit
+	Amount           Money     `bson:"amount" json:"amount"`
 	Status           string    `bson:"status,omitempty" json:"status"` // pending, paid, expired, failed
 	VANumber         string    `bson:"va_number,omitempty" json:"va_number"`
 	PaymentMethod    string    `bson:"payment_method,omitempty" json:"payment_method"` // BCA, Mandiri, BNI
@@ -26,6 +27,25 @@ type Invoice struct {
 	ExpiryDate       time.Time `bson:"expiry_date,omitempty" json:"expiry_date"` // VA expires after 24h
 	CreatedAt        time.Time `bson:"created_at,omitempty" json:"created_at"`
 	UpdatedAt        time.Time `bson:"updated_at,omitempty" json:"updated_at"`
+
+	// Set once the member was reminded to pay before the VA expires; cleared when it reopens
+	RemindedAt *time.Time `bson:"reminded_at,omitempty" json:"reminded_at,omitempty"`
+
+	// PT contract invoices paid in installments. The VA fields above are for the installment
+	// being paid now; PaidAmount and Payments track what came in through the webhook or the
+	// front desk. Contracts sold at the front desk get a paid invoice without installments,
+	// automatic renewals a pending one with a VA at the member's chosen bank.
+	ContractID   string           `bson:"contract_id,omitempty" json:"contract_id,omitempty"`
+	Installments []Installment    `bson:"installments,omitempty" json:"installments,omitempty"`
+	PaidAmount   int64            `bson:"paid_amount,omitempty" json:"paid_amount,omitempty"` // Minor units of Amount's currency
+	Payments     []InvoicePayment `bson:"payments,omitempty" json:"payments,omitempty"`
+	GraceDays    int              `bson:"grace_days,omitempty" json:"grace_days,omitempty"`     // Days an installment may be late before the contract is suspended
+	SuspendedAt  *time.Time       `bson:"suspended_at,omitempty" json:"suspended_at,omitempty"` // Set while the contract is suspended for it
+
+	// Where the contract was sold and whether at that branch's own price, so revenue
+	// reports can attribute payments to branches
+	BranchID     string `bson:"branch_id,omitempty" json:"branch_id,omitempty"`
+	BranchPriced bool   `bson:"branch_priced,omitempty" json:"branch_priced,omitempty"`
 }
 
 // InvoiceRepository defines operations for managing invoices
@@ -35,6 +55,37 @@ type InvoiceRepository interface {
 	GetByUserID(ctx context.Context, userID string) ([]*Invoice, error)
 	GetPendingByUserAndPackage(ctx context.Context, userID, packageID string) (*Invoice, error)
 	GetByPaymentSessionID(ctx context.Context, sessionID string) (*Invoice, error)
-	UpdateStatus(ctx context.Context, id string, status string) error
+	// MarkPaid marks a pending invoice paid, returning false if it is no longer pending
+	MarkPaid(ctx context.Context, id string, at time.Time) (bool, error)
 	Update(ctx context.Context, invoice *Invoice) error
+
+	// GetByContractID returns the contract's latest installment plan
+	GetByContractID(ctx context.Context, contractID string) (*Invoice, error)
+	// ListInstallmentPlansDue returns unpaid installment plans with an installment due
+	// before the given time, and plans whose contract is suspended
+	ListInstallmentPlansDue(ctx context.Context, before time.Time) ([]*Invoice, error)
+	// RecordPayment saves the invoice's installments, paid amount and status along with
+	// the payment. It returns false if a payment with the same reference was already recorded.
+	RecordPayment(ctx context.Context, invoice *Invoice, payment InvoicePayment) (bool, error)
+	// SetSuspended records that the contract was suspended over the invoice; nil clears it
+	SetSuspended(ctx context.Context, id string, at *time.Time) error
+	// ListPaidBetween returns the tenant's invoices with a payment in [from, to), and paid
+	// invoices without recorded payments last updated in it
+	ListPaidBetween(ctx context.Context, tenantID string, from, to time.Time) ([]*Invoice, error)
+
+	// ListCheckoutsExpiringBefore returns pending checkout invoices, those not for a PT
+	// contract, whose VA expires before the given time and that still need something at now:
+	// the VA already expired, or the member wasn't reminded yet
+	ListCheckoutsExpiringBefore(ctx context.Context, now, before time.Time) ([]*Invoice, error)
+	// Expire marks a pending invoice whose VA expired by now expired. It returns false if
+	// the invoice was paid or reopened meanwhile.
+	Expire(ctx context.Context, id string, now time.Time) (bool, error)
+	// MarkReminded records the payment reminder, returning false if one was already sent
+	MarkReminded(ctx context.Context, id string, at time.Time) (bool, error)
+	// GetLapsedCheckout returns the member's latest checkout invoice for the package whose
+	// VA expired unpaid by now, whether or not it was marked expired yet
+	GetLapsedCheckout(ctx context.Context, userID, packageID string, now time.Time) (*Invoice, error)
+	// Reopen saves the invoice's new VA and amount and makes it pending again, if it is
+	// still the lapsed invoice at the VA of previousSessionID. It returns false otherwise.
+	Reopen(ctx context.Context, invoice *Invoice, previousSessionID string) (bool, error)
 }
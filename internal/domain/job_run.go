@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrJobNotRetryable = errors.New("job run cannot be retried")
+
+// Job run statuses
+const (
+	JobRunStatusRunning   = "running"
+	JobRunStatusSucceeded = "succeeded"
+	JobRunStatusFailed    = "failed"
+)
+
+// JobRun records one execution of a background job
+type JobRun struct {
+	ID         string                 `json:"id" bson:"_id,omitempty"`
+	Type       string                 `json:"type" bson:"type"`
+	Status     string                 `json:"status" bson:"status"`
+	TenantID   string                 `json:"tenant_id,omitempty" bson:"tenant_id,omitempty"` // Affected tenant, for tenant-scoped work
+	Params     map[string]interface{} `json:"params,omitempty" bson:"params,omitempty"`       // What the run worked on (e.g. schedule_id); reused on retry
+	Error      string                 `json:"error,omitempty" bson:"error,omitempty"`
+	RetryOf    string                 `json:"retry_of,omitempty" bson:"retry_of,omitempty"` // Run this one retried
+	DurationMS int64                  `json:"duration_ms" bson:"duration_ms"`
+	CreatedAt  time.Time              `json:"created_at" bson:"created_at"` // When the run started
+	FinishedAt *time.Time             `json:"finished_at,omitempty" bson:"finished_at,omitempty"`
+}
+
+// JobRunFilter narrows a job run listing; empty fields match everything
+type JobRunFilter struct {
+	Type     string
+	Status   string
+	TenantID string
+}
+
+type JobRunRepository interface {
+	Create(ctx context.Context, run *JobRun) error
+	// Finish stores the outcome (status, error, duration, finished_at) of a run
+	Finish(ctx context.Context, run *JobRun) error
+	GetByID(ctx context.Context, id string) (*JobRun, error)
+	// List returns one page of runs, newest first
+	List(ctx context.Context, filter JobRunFilter, q PageQuery) (*Page[*JobRun], error)
+}
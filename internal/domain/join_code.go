@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"errors"
+	"unicode"
+)
+
+// MinJoinCodeLength is the minimum number of letters and digits in a join code
+const MinJoinCodeLength = 8
+
+var ErrWeakJoinCode = errors.New("join code must have at least 8 letters and digits, mixing both")
+
+// ValidateJoinCode rejects codes that are cheap to guess. Join codes admit anyone who knows
+// them into a tenant or branch, so a short or all-digit code is effectively public.
+func ValidateJoinCode(code string) error {
+	var alnum int
+	var hasLetter, hasDigit bool
+	for _, r := range code {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+			alnum++
+		case unicode.IsDigit(r):
+			hasDigit = true
+			alnum++
+		case r == '-' || r == '_':
+		default:
+			return ErrWeakJoinCode
+		}
+	}
+	if alnum < MinJoinCodeLength || !hasLetter || !hasDigit {
+		return ErrWeakJoinCode
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateJoinCode(t *testing.T) {
+	assert.NoError(t, ValidateJoinCode("HOMG-7K2QX9"))
+	assert.NoError(t, ValidateJoinCode("gym2025north"))
+
+	assert.ErrorIs(t, ValidateJoinCode("HOM-1234"), ErrWeakJoinCode, "too short")
+	assert.ErrorIs(t, ValidateJoinCode("12345678"), ErrWeakJoinCode, "digits only")
+	assert.ErrorIs(t, ValidateJoinCode("ABCDEFGHIJ"), ErrWeakJoinCode, "letters only")
+	assert.ErrorIs(t, ValidateJoinCode("ABCD 12345"), ErrWeakJoinCode, "whitespace")
+}
@@ -13,5 +13,6 @@ type MetamorphClaims struct {
 	TenantID     string   `json:"tenant_id"`
 	HomeBranchID string   `json:"home_branch_id,omitempty"`
 	BranchAccess []string `json:"branch_access,omitempty"`
+	TenantIDs    []string `json:"tenant_ids,omitempty"` // Tenants the user can switch the token to
 	jwt.RegisteredClaims
 }
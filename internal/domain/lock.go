@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrLockNotAcquired = errors.New("another request is already processing this resource")
+
+// Locker provides short-lived distributed mutual exclusion across API instances.
+// Acquire waits up to wait for the lock; the lock expires on its own after ttl so a
+// crashed holder can't block the resource forever. The returned release func is idempotent.
+type Locker interface {
+	Acquire(ctx context.Context, key string, ttl, wait time.Duration) (release func(), err error)
+}
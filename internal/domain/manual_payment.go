@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Payment channels. Provider payments come in through the VA webhook; the others are
+// recorded by the front desk.
+const (
+	PaymentChannelProvider     = "provider"
+	PaymentChannelCash         = "cash"
+	PaymentChannelBankTransfer = "bank_transfer"
+)
+
+var (
+	ErrInvalidPaymentChannel = errors.New("payment channel must be cash or bank_transfer")
+	ErrInvalidManualPayment  = errors.New("manual payment needs a positive amount no larger than what is outstanding")
+	ErrNotContractInvoice    = errors.New("only PT contract invoices can be paid manually")
+	ErrInvoiceAlreadyPaid    = errors.New("invoice is already paid")
+)
+
+// IsManualPaymentChannel reports whether the channel is one the front desk records
+func IsManualPaymentChannel(channel string) bool {
+	return channel == PaymentChannelCash || channel == PaymentChannelBankTransfer
+}
+
+// PaymentTotal counts payments and sums their amounts
+type PaymentTotal struct {
+	Count  int   `json:"count"`
+	Amount int64 `json:"amount"`
+}
+
+// Add counts one more payment of the amount
+func (t *PaymentTotal) Add(amount int64) {
+	t.Count++
+	t.Amount += amount
+}
+
+// UnattributedBranch is the by_branch key of payments on invoices without a branch:
+// membership checkouts, and contract invoices from before branches were recorded on them
+const UnattributedBranch = "unattributed"
+
+// BranchRevenue is what a branch took in, split by whether its contracts were sold at the
+// branch's own price or at the package price
+type BranchRevenue struct {
+	BranchPrice PaymentTotal `json:"branch_price"`
+	BasePrice   PaymentTotal `json:"base_price"`
+}
+
+// ReconciledPayment is one payment in a reconciliation report
+type ReconciledPayment struct {
+	InvoiceID  string    `json:"invoice_id"`
+	ContractID string    `json:"contract_id,omitempty"`
+	BranchID   string    `json:"branch_id,omitempty"`
+	UserID     string    `json:"user_id"`
+	Channel    string    `json:"channel"`
+	Amount     int64     `json:"amount"`
+	PaidAt     time.Time `json:"paid_at"`
+	RecordedBy string    `json:"recorded_by,omitempty"`
+	Receipt    string    `json:"receipt,omitempty"`
+}
+
+// PaymentReconciliation splits a tenant's payments over a period into what the provider
+// settled and what the front desk took in by hand. Amounts are minor units of Currency.
+type PaymentReconciliation struct {
+	Currency  string                  `json:"currency"`
+	From      time.Time               `json:"from"`
+	To        time.Time               `json:"to"`
+	Provider  PaymentTotal            `json:"provider"`
+	Manual    PaymentTotal            `json:"manual"`
+	ByChannel map[string]PaymentTotal `json:"by_channel"`
+	Payments  []ReconciledPayment     `json:"payments"`
+
+	// Totals by the branch that sold the contract, keyed UnattributedBranch when unknown
+	ByBranch map[string]BranchRevenue `json:"by_branch"`
+}
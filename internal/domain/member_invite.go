@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// MemberInviteTTL is how long an invite can be accepted
+const MemberInviteTTL = 7 * 24 * time.Hour
+
+var (
+	ErrInvalidInvite         = errors.New("an invite needs the member's email and name")
+	ErrInviteNotFound        = errors.New("invite not found, expired or already used")
+	ErrInviteEmailMismatch   = errors.New("this invite is for another email address")
+	ErrInviteEmailUnverified = errors.New("verify your email address before accepting the invite")
+	ErrInviteMemberExists    = errors.New("a member with this email already belongs to the gym")
+	ErrInviteAccountLinked   = errors.New("email already linked to different account")
+)
+
+// MemberInvite lets someone a coach signed up join the gym with their own account. The
+// member accepts it by signing in with the invited email; the coach's package, if any, is
+// sold to them then. Only a hash of the secret is stored.
+type MemberInvite struct {
+	ID          string     `bson:"_id,omitempty" json:"id"`
+	TenantID    string     `bson:"tenant_id" json:"tenant_id"`
+	CoachID     string     `bson:"coach_id" json:"coach_id"`
+	Email       string     `bson:"email" json:"email"` // Lower case
+	Name        string     `bson:"name" json:"name"`
+	PackageID   string     `bson:"package_id,omitempty" json:"package_id,omitempty"`
+	DateOfBirth *time.Time `bson:"date_of_birth,omitempty" json:"date_of_birth,omitempty"`
+	TokenHash   string     `bson:"token_hash" json:"-"`
+	CreatedAt   time.Time  `bson:"created_at" json:"created_at"`
+	ExpiresAt   time.Time  `bson:"expires_at" json:"expires_at"`
+	AcceptedAt  *time.Time `bson:"accepted_at,omitempty" json:"accepted_at,omitempty"`
+	MemberID    string     `bson:"member_id,omitempty" json:"member_id,omitempty"` // Who accepted it
+	RevokedAt   *time.Time `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+}
+
+// Pending reports whether the invite can still be accepted at now
+func (i *MemberInvite) Pending(now time.Time) bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil && now.Before(i.ExpiresAt)
+}
+
+// Normalize tidies the email and name and checks both are there
+func (i *MemberInvite) Normalize() error {
+	i.Email = strings.ToLower(strings.TrimSpace(i.Email))
+	i.Name = strings.TrimSpace(i.Name)
+	if i.Email == "" || i.Name == "" || !strings.Contains(i.Email, "@") {
+		return ErrInvalidInvite
+	}
+	return nil
+}
+
+// InviteLink is the link that opens the app on the invite, e.g. for an SMS
+func InviteLink(secret string) *DeepLink {
+	return &DeepLink{Screen: ScreenAcceptInvite, Token: secret}
+}
+
+type MemberInviteRepository interface {
+	Create(ctx context.Context, invite *MemberInvite) error
+	// FindByHash returns ErrNotFound for unknown hashes; used and revoked invites are returned
+	FindByHash(ctx context.Context, hash string) (*MemberInvite, error)
+	// ListPending returns the coach's invites not accepted, revoked or expired at now,
+	// newest first
+	ListPending(ctx context.Context, tenantID, coachID string, now time.Time) ([]*MemberInvite, error)
+	// Accept marks a pending invite accepted by memberID. It returns ErrNotFound when the
+	// invite was used, revoked or expired in the meantime, so each invite is used once.
+	Accept(ctx context.Context, id, memberID string, at time.Time) error
+	// Revoke returns ErrNotFound when the invite isn't one of the coach's pending invites
+	Revoke(ctx context.Context, coachID, id string, at time.Time) error
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemberInvite_Normalize(t *testing.T) {
+	invite := &MemberInvite{Email: " Budi@Example.com ", Name: " Budi "}
+	assert.NoError(t, invite.Normalize())
+	assert.Equal(t, "budi@example.com", invite.Email)
+	assert.Equal(t, "Budi", invite.Name)
+
+	assert.ErrorIs(t, (&MemberInvite{Email: "budi", Name: "Budi"}).Normalize(), ErrInvalidInvite)
+	assert.ErrorIs(t, (&MemberInvite{Email: "budi@example.com"}).Normalize(), ErrInvalidInvite)
+}
+
+func TestMemberInvite_Pending(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	invite := &MemberInvite{ExpiresAt: now.Add(time.Hour)}
+	assert.True(t, invite.Pending(now))
+	assert.False(t, invite.Pending(now.Add(time.Hour)), "expired")
+
+	invite.AcceptedAt = &now
+	assert.False(t, invite.Pending(now), "used")
+}
+
+func TestInviteLink(t *testing.T) {
+	assert.Equal(t, "metamorph://accept_invite?token=inv_abc", InviteLink("inv_abc").URL())
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidTransferPolicy = errors.New("invalid transfer policy: use move or copy")
+	ErrInvalidTransferTarget = errors.New("transfer target must be a different branch of the destination tenant")
+)
+
+// Cross-tenant transfer policies. Scans and personal bests belong to the member, not a
+// tenant, so they follow the member under either policy.
+const (
+	// TransferPolicyMove re-tags the member's contracts, sessions and credit history to the
+	// destination; the source tenant keeps nothing
+	TransferPolicyMove = "move"
+	// TransferPolicyCopy leaves the source tenant's records in place and gives the destination
+	// copies of the member's finished sessions, detached from the source contracts
+	TransferPolicyCopy = "copy"
+)
+
+// MemberTransfer is the audit record of a member moved to another branch or tenant
+type MemberTransfer struct {
+	ID           string `json:"id" bson:"_id,omitempty"`
+	MemberID     string `json:"member_id" bson:"member_id"`
+	ActorID      string `json:"actor_id" bson:"actor_id"`
+	Reason       string `json:"reason,omitempty" bson:"reason,omitempty"`
+	FromTenantID string `json:"from_tenant_id" bson:"from_tenant_id"`
+	ToTenantID   string `json:"to_tenant_id" bson:"to_tenant_id"`
+	FromBranchID string `json:"from_branch_id,omitempty" bson:"from_branch_id,omitempty"` // Empty: every branch the member had
+	ToBranchID   string `json:"to_branch_id" bson:"to_branch_id"`
+	Policy       string `json:"policy,omitempty" bson:"policy,omitempty"` // Cross-tenant transfers only
+
+	ContractsMoved    int64 `json:"contracts_moved" bson:"contracts_moved"`
+	SchedulesMoved    int64 `json:"schedules_moved" bson:"schedules_moved"`
+	SchedulesCopied   int64 `json:"schedules_copied" bson:"schedules_copied"`
+	SessionsCancelled int   `json:"sessions_cancelled" bson:"sessions_cancelled"` // Upcoming sessions at the old location
+
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// MemberTransferRepository rewrites a member's records in bulk and keeps the transfer log
+type MemberTransferRepository interface {
+	// MoveBranchContracts re-tags the member's active contracts in the tenant from fromBranchID
+	// (any other branch when empty) to toBranchID
+	MoveBranchContracts(ctx context.Context, memberID, tenantID, fromBranchID, toBranchID string) (int64, error)
+	// MoveHistory re-tags the member's contracts, schedules and workout sessions from one
+	// tenant to another, placing them at toBranchID. The credit ledger is append-only and
+	// stays as written; statements are looked up by contract, so they follow the contracts.
+	MoveHistory(ctx context.Context, memberID, fromTenantID, toTenantID, toBranchID string) (contracts, schedules int64, err error)
+	// CopyHistory inserts copies of the member's completed and no-show schedules into the
+	// destination tenant
+	CopyHistory(ctx context.Context, memberID, fromTenantID, toTenantID, toBranchID string) (int64, error)
+
+	Record(ctx context.Context, transfer *MemberTransfer) error
+	ListByMember(ctx context.Context, memberID string) ([]*MemberTransfer, error)
+}
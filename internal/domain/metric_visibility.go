@@ -0,0 +1,151 @@
+package domain
+
+// MetricVisibility is a coach's choice of which scan metrics a client sees of themselves.
+// Hidden metrics are zeroed in member-facing responses, which list them as hidden_metrics;
+// coaches and admins always see everything.
+type MetricVisibility struct {
+	HideBodyFat bool `bson:"hide_body_fat" json:"hide_body_fat"` // PBF, fat mass, visceral fat
+	HideWeight  bool `bson:"hide_weight" json:"hide_weight"`     // Weight and what's derived from it
+	// Only training volume: no body composition and no personal bests
+	VolumeOnly bool `bson:"volume_only" json:"volume_only"`
+}
+
+// Metric groups reported in hidden_metrics
+const (
+	MetricBodyFat         = "body_fat"
+	MetricWeight          = "weight"
+	MetricBodyComposition = "body_composition"
+	MetricPersonalBests   = "personal_bests"
+)
+
+// MemberMetricVisibility combines the visibility of a member's contracts: a metric any of
+// them hides stays hidden
+func MemberMetricVisibility(contracts []*PTContract) MetricVisibility {
+	var v MetricVisibility
+	for _, contract := range contracts {
+		if c := contract.MetricVisibility; c != nil {
+			v.HideBodyFat = v.HideBodyFat || c.HideBodyFat
+			v.HideWeight = v.HideWeight || c.HideWeight
+			v.VolumeOnly = v.VolumeOnly || c.VolumeOnly
+		}
+	}
+	return v
+}
+
+func (v MetricVisibility) hidesBodyFat() bool { return v.HideBodyFat || v.VolumeOnly }
+func (v MetricVisibility) hidesWeight() bool  { return v.HideWeight || v.VolumeOnly }
+
+// Restricted reports whether anything is hidden
+func (v MetricVisibility) Restricted() bool {
+	return v.HideBodyFat || v.HideWeight || v.VolumeOnly
+}
+
+// HidesPersonalBests reports whether personal bests are left out
+func (v MetricVisibility) HidesPersonalBests() bool {
+	return v.VolumeOnly
+}
+
+// HiddenMetrics names the metric groups hidden, for clients to leave out rather than show zeros
+func (v MetricVisibility) HiddenMetrics() []string {
+	hidden := []string{}
+	if v.hidesBodyFat() {
+		hidden = append(hidden, MetricBodyFat)
+	}
+	if v.hidesWeight() {
+		hidden = append(hidden, MetricWeight)
+	}
+	if v.VolumeOnly {
+		hidden = append(hidden, MetricBodyComposition, MetricPersonalBests)
+	}
+	return hidden
+}
+
+// Scan returns a copy of record without the hidden metrics. The AI analysis is dropped
+// whenever anything is hidden, as its text discusses them all.
+func (v MetricVisibility) Scan(record *InBodyRecord) *InBodyRecord {
+	if record == nil || !v.Restricted() {
+		return record
+	}
+	out := *record
+	out.Analysis = nil
+	if v.hidesBodyFat() {
+		out.BodyFatMass, out.PBF, out.VisceralFatLevel, out.FatControl = 0, 0, 0, 0
+		out.SegmentalFat = nil
+	}
+	if v.hidesWeight() {
+		out.Weight, out.BMI, out.ObesityDegree, out.TargetWeight, out.WeightControl = 0, 0, 0, 0, 0
+	}
+	if v.VolumeOnly {
+		out.SMM, out.FatFreeMass, out.MuscleControl, out.InBodyScore, out.WaistHipRatio = 0, 0, 0, 0, 0
+		out.BMR, out.RecommendedCalorieIntake = 0, 0
+		out.SegmentalLean = nil
+	}
+	return &out
+}
+
+// ScanList returns a copy of result without the hidden metrics
+func (v MetricVisibility) ScanList(result *ScanListResult) *ScanListResult {
+	if result == nil || !v.Restricted() {
+		return result
+	}
+	out := *result
+	out.Items = make([]ScanListItem, len(result.Items))
+	for i, item := range result.Items {
+		if v.hidesBodyFat() {
+			item.PBF = 0
+		}
+		if v.hidesWeight() {
+			item.Weight = 0
+		}
+		if v.VolumeOnly {
+			item.SMM = 0
+		}
+		out.Items[i] = item
+	}
+	return &out
+}
+
+// History returns a copy of history without the hidden metrics
+func (v MetricVisibility) History(history *AnalyticsHistory) *AnalyticsHistory {
+	if history == nil || !v.Restricted() {
+		return history
+	}
+	out := AnalyticsHistory{Progress: history.Progress, History: make([]TrendData, len(history.History))}
+	if v.hidesBodyFat() {
+		out.Progress.BodyFatChange = 0
+	}
+	if v.hidesWeight() {
+		out.Progress.WeightChange = 0
+	}
+	if v.VolumeOnly {
+		out.Progress.MuscleGained = 0
+	}
+
+	for i, point := range history.History {
+		if v.hidesBodyFat() {
+			point.CoreMetrics.PBF = 0
+		}
+		if v.hidesWeight() {
+			point.CoreMetrics.Weight = 0
+		}
+		if v.VolumeOnly {
+			point.CoreMetrics.SMM = 0
+			point.ExtendedMetrics, point.SegmentalTrends = nil, nil
+		}
+		if ext := point.ExtendedMetrics; ext != nil && (v.hidesBodyFat() || v.hidesWeight()) {
+			e := *ext
+			if v.hidesBodyFat() {
+				e.FatControl = 0
+			}
+			if v.hidesWeight() {
+				e.ObesityDegree, e.TargetWeight, e.WeightControl = 0, 0, 0
+			}
+			point.ExtendedMetrics = &e
+		}
+		if seg := point.SegmentalTrends; seg != nil && v.hidesBodyFat() {
+			point.SegmentalTrends = &SegmentalTrend{Lean: seg.Lean}
+		}
+		out.History[i] = point
+	}
+	return &out
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricVisibility_Scan(t *testing.T) {
+	record := &InBodyRecord{ID: "scan-1", Weight: 80, BMI: 24.7, PBF: 18.5, BodyFatMass: 14.8, SMM: 37.2,
+		SegmentalFat: &SegmentalData{}, SegmentalLean: &SegmentalData{}, Analysis: &BodyAnalysis{}}
+
+	assert.Same(t, record, MetricVisibility{}.Scan(record), "nothing hidden")
+
+	noFat := MetricVisibility{HideBodyFat: true}.Scan(record)
+	assert.Zero(t, noFat.PBF)
+	assert.Zero(t, noFat.BodyFatMass)
+	assert.Nil(t, noFat.SegmentalFat)
+	assert.Nil(t, noFat.Analysis)
+	assert.Equal(t, 80.0, noFat.Weight)
+	assert.NotNil(t, noFat.SegmentalLean)
+	assert.Equal(t, 18.5, record.PBF, "the record itself is left alone")
+
+	volumeOnly := MetricVisibility{VolumeOnly: true}.Scan(record)
+	assert.Zero(t, volumeOnly.Weight)
+	assert.Zero(t, volumeOnly.BMI)
+	assert.Zero(t, volumeOnly.SMM)
+	assert.Nil(t, volumeOnly.SegmentalLean)
+	assert.Equal(t, "scan-1", volumeOnly.ID)
+}
+
+func TestMetricVisibility_History(t *testing.T) {
+	history := &AnalyticsHistory{
+		Progress: ProgressSummary{TotalScans: 1, WeightChange: -2, BodyFatChange: -1.5, MuscleGained: 0.8},
+		History: []TrendData{{
+			Date:            time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC),
+			CoreMetrics:     CoreTrendMetric{Weight: 78, SMM: 37, PBF: 17},
+			ExtendedMetrics: &ExtendedMetrics{TargetWeight: 75, FatControl: -3, MuscleControl: 1},
+		}},
+	}
+
+	got := MetricVisibility{HideWeight: true}.History(history)
+
+	assert.Equal(t, ProgressSummary{TotalScans: 1, BodyFatChange: -1.5, MuscleGained: 0.8}, got.Progress)
+	assert.Equal(t, CoreTrendMetric{SMM: 37, PBF: 17}, got.History[0].CoreMetrics)
+	assert.Equal(t, &ExtendedMetrics{FatControl: -3, MuscleControl: 1}, got.History[0].ExtendedMetrics)
+	assert.Equal(t, 75.0, history.History[0].ExtendedMetrics.TargetWeight, "the history itself is left alone")
+}
+
+func TestMemberMetricVisibility(t *testing.T) {
+	visibility := MemberMetricVisibility([]*PTContract{
+		{MetricVisibility: &MetricVisibility{VolumeOnly: true}},
+		{},
+	})
+
+	assert.True(t, visibility.HidesPersonalBests())
+	assert.Equal(t, []string{MetricBodyFat, MetricWeight, MetricBodyComposition, MetricPersonalBests}, visibility.HiddenMetrics())
+	assert.Empty(t, MemberMetricVisibility(nil).HiddenMetrics())
+}
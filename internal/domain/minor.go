@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrGuardianConsentRequired = errors.New("members under the gym's minimum age need a guardian's consent first")
+	ErrInvalidGuardianConsent  = errors.New("guardian consent needs the guardian's name, relationship, and an email or phone number")
+	ErrInvalidDateOfBirth      = errors.New("date of birth must be a past date in YYYY-MM-DD format")
+	ErrInvalidMinorAge         = errors.New("minor age must be between 13 and 21")
+)
+
+// Age below which members are minors: they need a guardian's consent to train, and their
+// data isn't analyzed by AI
+const (
+	DefaultMinorAge = 18
+	MinMinorAge     = 13
+	MaxMinorAge     = 21
+)
+
+// ParseDateOfBirth parses a YYYY-MM-DD date of birth, which must be before today. An empty
+// string clears it (nil).
+func ParseDateOfBirth(s string, now time.Time) (*time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	dob, err := time.Parse("2006-01-02", s)
+	if err != nil || !dob.Before(now) {
+		return nil, ErrInvalidDateOfBirth
+	}
+	return &dob, nil
+}
+
+// AgeOn returns the age in whole years on the day of t of someone born on dob
+func AgeOn(dob, t time.Time) int {
+	y1, m1, d1 := dob.Date()
+	y2, m2, d2 := t.Date()
+	age := y2 - y1
+	if m2 < m1 || (m2 == m1 && d2 < d1) {
+		age--
+	}
+	return age
+}
+
+// ValidateMinorAge checks a tenant's minor age; 0 uses DefaultMinorAge
+func ValidateMinorAge(age int) error {
+	if age != 0 && (age < MinMinorAge || age > MaxMinorAge) {
+		return ErrInvalidMinorAge
+	}
+	return nil
+}
+
+// MinorAgeLimit returns the age below which the tenant's members are minors
+func (t *Tenant) MinorAgeLimit() int {
+	if t.MinorAge > 0 {
+		return t.MinorAge
+	}
+	return DefaultMinorAge
+}
+
+// IsMinorIn reports whether the user is a minor under the tenant's age limit on the day of
+// now. Users without a date of birth are taken to be adults.
+func (u *User) IsMinorIn(tenant *Tenant, now time.Time) bool {
+	return u.DateOfBirth != nil && AgeOn(*u.DateOfBirth, now) < tenant.MinorAgeLimit()
+}
+
+// GuardianConsent records a parent or guardian allowing a minor to train, taken by the gym
+// (e.g. from a signed form). Records are never changed; the latest one counts, and a
+// revocation is a record with Granted false.
+type GuardianConsent struct {
+	ID           string    `json:"id" bson:"_id,omitempty"`
+	TenantID     string    `json:"tenant_id" bson:"tenant_id"`
+	MemberID     string    `json:"member_id" bson:"member_id"`
+	GuardianName string    `json:"guardian_name" bson:"guardian_name"`
+	Relationship string    `json:"relationship" bson:"relationship"` // e.g. "mother", "legal guardian"
+	Email        string    `json:"email,omitempty" bson:"email,omitempty"`
+	Phone        string    `json:"phone,omitempty" bson:"phone,omitempty"`
+	Granted      bool      `json:"granted" bson:"granted"`
+	Note         string    `json:"note,omitempty" bson:"note,omitempty"` // e.g. where the signed form is kept
+	RecordedBy   string    `json:"recorded_by" bson:"recorded_by"`
+	RecordedAt   time.Time `json:"recorded_at" bson:"recorded_at"`
+}
+
+// Validate checks that a consent names the guardian and how to reach them
+func (g *GuardianConsent) Validate() error {
+	g.GuardianName = strings.TrimSpace(g.GuardianName)
+	g.Relationship = strings.TrimSpace(g.Relationship)
+	g.Email = strings.TrimSpace(g.Email)
+	g.Phone = strings.TrimSpace(g.Phone)
+	if g.GuardianName == "" || g.Relationship == "" || (g.Email == "" && g.Phone == "") {
+		return ErrInvalidGuardianConsent
+	}
+	return nil
+}
+
+// MinorStatus is what coaches and admins see of a member's age restrictions
+type MinorStatus struct {
+	Minor           bool             `json:"minor"`
+	Age             *int             `json:"age,omitempty"` // Unknown without a date of birth
+	MinorAge        int              `json:"minor_age"`     // The tenant's age limit
+	ConsentRequired bool             `json:"consent_required"`
+	Guardian        *GuardianConsent `json:"guardian,omitempty"` // Latest consent or revocation
+}
+
+// BuildMinorStatus works out the member's restrictions in tenant on the day of now, given
+// their latest guardian consent record (nil if none)
+func BuildMinorStatus(member *User, tenant *Tenant, latest *GuardianConsent, now time.Time) *MinorStatus {
+	status := &MinorStatus{MinorAge: tenant.MinorAgeLimit(), Guardian: latest}
+	if member.DateOfBirth != nil {
+		age := AgeOn(*member.DateOfBirth, now)
+		status.Age = &age
+	}
+	status.Minor = member.IsMinorIn(tenant, now)
+	status.ConsentRequired = status.Minor && (latest == nil || !latest.Granted)
+	return status
+}
+
+type GuardianConsentRepository interface {
+	// Record appends a consent or revocation
+	Record(ctx context.Context, consent *GuardianConsent) error
+	// GetLatest returns the member's latest record in the tenant, or ErrNotFound
+	GetLatest(ctx context.Context, tenantID, memberID string) (*GuardianConsent, error)
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgeOn(t *testing.T) {
+	dob := time.Date(2008, 3, 15, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 17, AgeOn(dob, time.Date(2026, 3, 14, 23, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 18, AgeOn(dob, time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 18, AgeOn(dob, time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestParseDateOfBirth(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	dob, err := ParseDateOfBirth("", now)
+	assert.NoError(t, err)
+	assert.Nil(t, dob)
+
+	dob, err = ParseDateOfBirth(" 2010-02-28 ", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2010, 2, 28, 0, 0, 0, 0, time.UTC), *dob)
+
+	for _, s := range []string{"28/02/2010", "2010-02-30", "2027-01-01"} {
+		_, err := ParseDateOfBirth(s, now)
+		assert.ErrorIs(t, err, ErrInvalidDateOfBirth, s)
+	}
+}
+
+func TestBuildMinorStatus(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	dob := time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC) // 16
+	granted := &GuardianConsent{Granted: true}
+
+	tests := []struct {
+		name     string
+		dob      *time.Time
+		minorAge int
+		latest   *GuardianConsent
+		minor    bool
+		required bool
+	}{
+		{"no date of birth is taken as an adult", nil, 0, nil, false, false},
+		{"minor without consent", &dob, 0, nil, true, true},
+		{"minor with consent", &dob, 0, granted, true, false},
+		{"minor whose consent was revoked", &dob, 0, &GuardianConsent{Granted: false}, true, true},
+		{"adult under the tenant's lower age", &dob, 16, nil, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := BuildMinorStatus(&User{DateOfBirth: tt.dob}, &Tenant{MinorAge: tt.minorAge}, tt.latest, now)
+			assert.Equal(t, tt.minor, status.Minor)
+			assert.Equal(t, tt.required, status.ConsentRequired)
+		})
+	}
+}
+
+func TestGuardianConsent_Validate(t *testing.T) {
+	consent := &GuardianConsent{GuardianName: " Jane Doe ", Relationship: "mother"}
+	assert.ErrorIs(t, consent.Validate(), ErrInvalidGuardianConsent)
+
+	consent.Phone = "+62 812 555 0101"
+	assert.NoError(t, consent.Validate())
+	assert.Equal(t, "Jane Doe", consent.GuardianName)
+
+	assert.ErrorIs(t, ValidateMinorAge(12), ErrInvalidMinorAge)
+	assert.NoError(t, ValidateMinorAge(0))
+	assert.NoError(t, ValidateMinorAge(16))
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is what amounts are in unless they say otherwise. Amounts stored before
+// they carried a currency are in it.
+const DefaultCurrency = "IDR"
+
+var ErrCurrencyMismatch = errors.New("amounts are in different currencies")
+
+// currencyExponents is the number of decimals of each currency's minor unit. Rupiah are
+// charged in whole rupiah, so IDR has none.
+var currencyExponents = map[string]int{
+	"IDR": 0,
+	"SGD": 2,
+	"MYR": 2,
+	"USD": 2,
+}
+
+// CurrencyExponent returns the number of decimals of the currency's minor unit
+func CurrencyExponent(currency string) int {
+	if exp, ok := currencyExponents[strings.ToUpper(currency)]; ok {
+		return exp
+	}
+	return 2
+}
+
+// Money is an amount in the minor unit of its currency. Prices, invoices and reports use it
+// so amounts are never floats: converting from a major-unit value rounds once, half away
+// from zero, to the minor unit (see MoneyFromMajor).
+type Money struct {
+	Minor    int64  `json:"amount" bson:"amount"`
+	Currency string `json:"currency" bson:"currency"`
+}
+
+// NewMoney returns minor units of the currency, or of DefaultCurrency if it is empty
+func NewMoney(minor int64, currency string) Money {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	return Money{Minor: minor, Currency: strings.ToUpper(currency)}
+}
+
+// MoneyFromMajor converts an amount in major units, as in a price list or an import file,
+// rounding half away from zero to the currency's minor unit. It rounds the decimal the
+// float was written as, so 12.495 SGD is 12.50 even though 12.495*100 is 1249.4999...
+func MoneyFromMajor(major float64, currency string) Money {
+	m := NewMoney(0, currency)
+	exp := CurrencyExponent(m.Currency)
+	whole, frac, _ := strings.Cut(strconv.FormatFloat(math.Abs(major), 'f', -1, 64), ".")
+	frac += strings.Repeat("0", exp+1)
+	m.Minor, _ = strconv.ParseInt(whole+frac[:exp], 10, 64)
+	if frac[exp] >= '5' {
+		m.Minor++
+	}
+	if major < 0 {
+		m.Minor = -m.Minor
+	}
+	return m
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Minor == 0
+}
+
+// Add returns the sum of both amounts, which must be in the same currency
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	return Money{Minor: m.Minor + o.Minor, Currency: m.Currency}, nil
+}
+
+// Split divides the amount into n parts that add up to it. The minor units that don't
+// divide evenly go to the first parts, so no part differs from another by more than one.
+func (m Money) Split(n int) []Money {
+	if n <= 0 {
+		return nil
+	}
+	parts := make([]Money, n)
+	base, rem := m.Minor/int64(n), m.Minor%int64(n)
+	for i := range parts {
+		parts[i] = Money{Minor: base, Currency: m.Currency}
+		if int64(i) < rem {
+			parts[i].Minor++
+		} else if int64(i) < -rem {
+			parts[i].Minor--
+		}
+	}
+	return parts
+}
+
+// String formats the amount in major units, e.g. "IDR 490000" or "SGD 12.50"
+func (m Money) String() string {
+	exp := CurrencyExponent(m.Currency)
+	if exp == 0 {
+		return fmt.Sprintf("%s %d", m.Currency, m.Minor)
+	}
+	sign, minor := "", m.Minor
+	if minor < 0 {
+		sign, minor = "-", -minor
+	}
+	scale := int64(math.Pow10(exp))
+	return fmt.Sprintf("%s %s%d.%0*d", m.Currency, sign, minor/scale, exp, minor%scale)
+}
+
+// UnmarshalJSON accepts {"amount", "currency"} as well as a bare number in major units of
+// DefaultCurrency, which clients and caches sent before amounts carried a currency
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var major float64
+	if err := json.Unmarshal(data, &major); err == nil {
+		*m = MoneyFromMajor(major, DefaultCurrency)
+		return nil
+	}
+	type money Money // Without this method
+	var v money
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*m = NewMoney(v.Minor, v.Currency)
+	return nil
+}
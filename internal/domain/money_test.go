@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoneyFromMajor(t *testing.T) {
+	assert.Equal(t, Money{Minor: 2_500_000, Currency: "IDR"}, MoneyFromMajor(2_499_999.5, ""))
+	assert.Equal(t, Money{Minor: 1250, Currency: "SGD"}, MoneyFromMajor(12.495, "sgd"))
+	assert.Equal(t, Money{Minor: -3, Currency: "USD"}, MoneyFromMajor(-0.025, "USD"))
+}
+
+func TestMoney_Split(t *testing.T) {
+	parts := NewMoney(1_000_000, "IDR").Split(3)
+	assert.Equal(t, []int64{333_334, 333_333, 333_333}, []int64{parts[0].Minor, parts[1].Minor, parts[2].Minor})
+
+	var sum int64
+	for _, p := range NewMoney(-10, "IDR").Split(4) {
+		sum += p.Minor
+	}
+	assert.Equal(t, int64(-10), sum)
+	assert.Nil(t, NewMoney(10, "IDR").Split(0))
+}
+
+func TestMoney_Add(t *testing.T) {
+	sum, err := NewMoney(100, "IDR").Add(NewMoney(50, "IDR"))
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(150, "IDR"), sum)
+
+	_, err = NewMoney(100, "IDR").Add(NewMoney(50, "SGD"))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "IDR 490000", NewMoney(490_000, "IDR").String())
+	assert.Equal(t, "SGD 12.05", NewMoney(1205, "SGD").String())
+	assert.Equal(t, "USD -0.50", NewMoney(-50, "USD").String())
+}
+
+func TestMoney_UnmarshalJSON(t *testing.T) {
+	var v struct {
+		Price Money `json:"price"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"price":{"amount":1205,"currency":"sgd"}}`), &v))
+	assert.Equal(t, NewMoney(1205, "SGD"), v.Price)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"price":{"amount":490000}}`), &v))
+	assert.Equal(t, NewMoney(490_000, "IDR"), v.Price)
+
+	// Bare numbers are major units, as clients sent them before amounts carried a currency
+	require.NoError(t, json.Unmarshal([]byte(`{"price":2500000.4}`), &v))
+	assert.Equal(t, NewMoney(2_500_000, "IDR"), v.Price)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"price":"abc"}`), &v))
+}
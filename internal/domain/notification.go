@@ -0,0 +1,289 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/url"
+	"slices"
+	"time"
+)
+
+var (
+	ErrInvalidReminderLeadTimes = errors.New("reminder lead times must be between 5 minutes and 7 days, at most 5, without duplicates")
+	ErrInvalidQuietHours        = errors.New("quiet hours need HH:MM start and end times and a valid IANA time zone")
+	ErrInvalidChannel           = errors.New("unknown notification channel")
+)
+
+// Notification channels
+const (
+	ChannelPush     = "push"
+	ChannelEmail    = "email"
+	ChannelWhatsApp = "whatsapp"
+)
+
+// Channels lists every notification channel
+var Channels = []string{ChannelPush, ChannelEmail, ChannelWhatsApp}
+
+// DefaultChannels is where a notification goes when the user hasn't chosen for its type
+var DefaultChannels = []string{ChannelPush}
+
+// typeDefaultChannels overrides DefaultChannels for types that belong somewhere else
+var typeDefaultChannels = map[string][]string{
+	NotificationReportReady:     {ChannelEmail},
+	NotificationInvitation:      {ChannelEmail},
+	NotificationInvoiceReceipt:  {ChannelEmail},
+	NotificationContractCreated: {ChannelPush, ChannelEmail},
+	NotificationLowSessions:     {ChannelPush, ChannelEmail},
+}
+
+// Notification types
+const (
+	NotificationScheduleReminder  = "schedule.reminder"
+	NotificationSessionPlan       = "schedule.plan"
+	NotificationUnconfirmed       = "schedule.unconfirmed"  // To the coach: the member hasn't confirmed
+	NotificationDeclined          = "schedule.declined"     // To the coach: the member can't make it
+	NotificationCoverNeeded       = "schedule.cover_needed" // To a branch's coaches: sessions they can claim
+	NotificationCovered           = "schedule.covered"      // To the member and substitute: who coaches the session now
+	NotificationScheduleBooked    = "schedule.booked"       // To the member: their coach booked a session
+	NotificationScheduleChanged   = "schedule.changed"      // To the member: their coach moved a session or changed where it happens
+	NotificationScheduleCancelled = "schedule.cancelled"    // To the member: their session was cancelled
+	NotificationMemberRescheduled = "schedule.moved"        // To the coach: a member moved a session, which awaits confirmation
+	NotificationSessionCompleted  = "schedule.completed"    // To the member: their coach completed the session
+	NotificationPersonalBest      = "pb.new"                // To the member and the session's coach: a new personal best
+	NotificationHandover          = "client.handover"       // To a coach: a brief on a client they take over
+	NotificationInvitation        = "account.invited"       // To a new user: their account was set up for them
+	NotificationContractCreated   = "contract.created"      // To the member: a PT package was bought for them
+	NotificationLowSessions       = "contract.low_sessions" // To the member: their package is almost used up
+	NotificationCreditsExpiring   = "contract.expiring"     // To the member and coach: unused sessions expire soon
+	NotificationCreditsExpired    = "contract.expired"      // To the member and coach: unused sessions expired
+	NotificationContractRenewed   = "contract.renewed"      // To the member: their package renewed and its invoice awaits payment
+	NotificationInvoiceExpiring   = "invoice.expiring"      // To the member: the VA of their unpaid checkout expires soon
+	NotificationInvoiceReceipt    = "invoice.receipt"       // To the member: a payment on their invoice came in
+	NotificationReportReady       = "report.ready"          // To a tenant admin: a scheduled report was generated
+	NotificationHealthConsent     = "consent.health"        // To the member: their coach needs consent before processing scans
+)
+
+// DefaultReminderLeadMinutes is used by tenants that haven't configured reminders: a day and
+// an hour before the session
+var DefaultReminderLeadMinutes = []int{24 * 60, 60}
+
+// Bounds for reminder lead times
+const (
+	MinReminderLeadMinutes = 5
+	MaxReminderLeadMinutes = 7 * 24 * 60
+	MaxReminderLeadTimes   = 5
+)
+
+// Notification is a message to one user, delivered through a NotificationSender
+type Notification struct {
+	UserID   string            `json:"user_id" bson:"user_id"`
+	TenantID string            `json:"tenant_id,omitempty" bson:"tenant_id,omitempty"`
+	Type     string            `json:"type" bson:"type"`
+	Title    string            `json:"title" bson:"title"`
+	Body     string            `json:"body" bson:"body"`
+	Data     map[string]string `json:"data,omitempty" bson:"data,omitempty"` // e.g. schedule_id, for the app to open the right screen
+	Link     *DeepLink         `json:"link,omitempty" bson:"link,omitempty"` // Screen tapping the notification opens
+}
+
+// Payload flattens the notification into the string map push providers hand to the app:
+// its type, its data and, with a link, the screen, the IDs it needs and the link's URL
+func (n *Notification) Payload() map[string]string {
+	out := make(map[string]string, len(n.Data)+5)
+	maps.Copy(out, n.Data)
+	out["type"] = n.Type
+	if n.Link != nil {
+		maps.Copy(out, n.Link.params())
+		out["screen"] = n.Link.Screen
+		out["link"] = n.Link.URL()
+	}
+	return out
+}
+
+// App screens a notification can open, with the IDs they need
+const (
+	ScreenSchedule      = "schedule"       // A session: ScheduleID
+	ScreenSessionPlan   = "session_plan"   // A session's planned exercises: ScheduleID
+	ScreenContract      = "contract"       // A PT package and its sessions: ContractID
+	ScreenCoverOffers   = "cover_offers"   // Sessions colleagues can claim: OfferID to highlight one
+	ScreenHealthConsent = "health_consent" // The health data policy, to consent to
+	ScreenInvoice       = "invoice"        // An invoice and the VA to pay it at: InvoiceID
+	ScreenHandover      = "handover"       // A coach's brief on a client they take over: HandoverID
+	ScreenAcceptInvite  = "accept_invite"  // Joining a gym on a coach's invite: Token
+)
+
+// DeepLinkScheme is the URL scheme the member and coach apps register
+const DeepLinkScheme = "metamorph"
+
+// DeepLink is where in the app a notification takes the user
+type DeepLink struct {
+	Screen     string `json:"screen" bson:"screen"`
+	ScheduleID string `json:"schedule_id,omitempty" bson:"schedule_id,omitempty"`
+	ContractID string `json:"contract_id,omitempty" bson:"contract_id,omitempty"`
+	OfferID    string `json:"offer_id,omitempty" bson:"offer_id,omitempty"`
+	InvoiceID  string `json:"invoice_id,omitempty" bson:"invoice_id,omitempty"`
+	HandoverID string `json:"handover_id,omitempty" bson:"handover_id,omitempty"`
+	Token      string `json:"token,omitempty" bson:"token,omitempty"` // An invite's secret
+}
+
+// URL renders the link in the apps' URL scheme, e.g. metamorph://schedule?schedule_id=42
+func (l *DeepLink) URL() string {
+	q := url.Values{}
+	for k, v := range l.params() {
+		q.Set(k, v)
+	}
+	u := url.URL{Scheme: DeepLinkScheme, Host: l.Screen, RawQuery: q.Encode()}
+	return u.String()
+}
+
+func (l *DeepLink) params() map[string]string {
+	params := map[string]string{}
+	for k, v := range map[string]string{"schedule_id": l.ScheduleID, "contract_id": l.ContractID, "offer_id": l.OfferID, "invoice_id": l.InvoiceID, "handover_id": l.HandoverID, "token": l.Token} {
+		if v != "" {
+			params[k] = v
+		}
+	}
+	return params
+}
+
+// NotificationSender delivers notifications over one channel
+type NotificationSender interface {
+	Channel() string
+	Send(ctx context.Context, n *Notification) error
+}
+
+// NotificationPreferences are a user's own notification choices
+type NotificationPreferences struct {
+	UserID          string              `json:"user_id" bson:"_id"`
+	RemindersOptOut bool                `json:"reminders_opt_out" bson:"reminders_opt_out"`
+	QuietHours      *QuietHours         `json:"quiet_hours,omitempty" bson:"quiet_hours,omitempty"`
+	Channels        map[string][]string `json:"channels,omitempty" bson:"channels,omitempty"` // Notification type -> channels; an empty list mutes the type
+	UpdatedAt       time.Time           `json:"updated_at" bson:"updated_at"`
+}
+
+// ChannelsFor returns the channels the user wants notifications of this type on
+func (p *NotificationPreferences) ChannelsFor(notificationType string) []string {
+	if channels, ok := p.Channels[notificationType]; ok {
+		return channels
+	}
+	if channels, ok := typeDefaultChannels[notificationType]; ok {
+		return channels
+	}
+	return DefaultChannels
+}
+
+// ValidateChannels checks that every chosen channel exists
+func ValidateChannels(channels map[string][]string) error {
+	for _, list := range channels {
+		for _, c := range list {
+			if !slices.Contains(Channels, c) {
+				return ErrInvalidChannel
+			}
+		}
+	}
+	return nil
+}
+
+// QuietHours is a daily do-not-disturb period in the user's time zone. End may be earlier
+// than Start for a period that runs past midnight, e.g. 22:00 to 07:00.
+type QuietHours struct {
+	Start    string `json:"start" bson:"start"`       // "22:00"
+	End      string `json:"end" bson:"end"`           // "07:00"
+	Timezone string `json:"timezone" bson:"timezone"` // e.g. "Asia/Jakarta"; UTC when empty
+}
+
+// Validate checks the times and time zone
+func (q QuietHours) Validate() error {
+	_, _, _, err := q.parse()
+	return err
+}
+
+// Contains reports whether t falls within the quiet hours
+func (q QuietHours) Contains(t time.Time) bool {
+	start, end, loc, err := q.parse()
+	if err != nil {
+		return false
+	}
+	local := t.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+func (q QuietHours) parse() (start, end int, loc *time.Location, err error) {
+	s, err1 := time.Parse("15:04", q.Start)
+	e, err2 := time.Parse("15:04", q.End)
+	loc, err3 := time.LoadLocation(q.Timezone)
+	if err1 != nil || err2 != nil || err3 != nil || q.Start == q.End {
+		return 0, 0, nil, ErrInvalidQuietHours
+	}
+	return s.Hour()*60 + s.Minute(), e.Hour()*60 + e.Minute(), loc, nil
+}
+
+// TenantNotificationSettings are the notification defaults a tenant sets for its members
+type TenantNotificationSettings struct {
+	TenantID            string    `json:"tenant_id" bson:"_id"`
+	ReminderLeadMinutes []int     `json:"reminder_lead_minutes" bson:"reminder_lead_minutes"` // Session reminders this long before the start
+	UpdatedAt           time.Time `json:"updated_at" bson:"updated_at"`
+
+	// Coaches are alerted this long before a session the member hasn't confirmed; 0 turns
+	// alerts off and nil uses DefaultConfirmationAlertMinutes
+	ConfirmationAlertMinutes *int `json:"confirmation_alert_minutes,omitempty" bson:"confirmation_alert_minutes,omitempty"`
+}
+
+// ConfirmationAlertLead is when coaches get alerted about unconfirmed sessions, 0 for never
+func (s *TenantNotificationSettings) ConfirmationAlertLead() time.Duration {
+	minutes := DefaultConfirmationAlertMinutes
+	if s.ConfirmationAlertMinutes != nil {
+		minutes = *s.ConfirmationAlertMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// ValidateReminderLeadMinutes checks a tenant's reminder lead times
+func ValidateReminderLeadMinutes(leads []int) error {
+	if len(leads) > MaxReminderLeadTimes {
+		return ErrInvalidReminderLeadTimes
+	}
+	seen := make(map[int]bool, len(leads))
+	for _, m := range leads {
+		if m < MinReminderLeadMinutes || m > MaxReminderLeadMinutes || seen[m] {
+			return ErrInvalidReminderLeadTimes
+		}
+		seen[m] = true
+	}
+	return nil
+}
+
+// NotificationPreferencesRepository stores user preferences and tenant notification
+// settings. The getters return defaults for users and tenants that never saved any.
+type NotificationPreferencesRepository interface {
+	GetUser(ctx context.Context, userID string) (*NotificationPreferences, error)
+	UpsertUser(ctx context.Context, prefs *NotificationPreferences) error
+	GetTenant(ctx context.Context, tenantID string) (*TenantNotificationSettings, error)
+	UpsertTenant(ctx context.Context, settings *TenantNotificationSettings) error
+	// ListTenants returns every tenant that saved settings
+	ListTenants(ctx context.Context) ([]*TenantNotificationSettings, error)
+}
+
+// InboxItem is a notification kept for the in-app inbox, whatever channels it was sent on
+type InboxItem struct {
+	ID           string `json:"id" bson:"_id"`
+	Notification `bson:",inline"`
+
+	ReadAt    *time.Time `json:"read_at,omitempty" bson:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" bson:"created_at"`
+}
+
+// InboxRepository stores each user's in-app notifications
+type InboxRepository interface {
+	Create(ctx context.Context, item *InboxItem) error
+	// List returns the user's notifications newest first, optionally only unread ones
+	List(ctx context.Context, userID string, unreadOnly bool, q PageQuery) (*Page[*InboxItem], error)
+	CountUnread(ctx context.Context, userID string) (int64, error)
+	// MarkRead returns ErrNotFound unless the notification belongs to the user
+	MarkRead(ctx context.Context, userID, id string, at time.Time) error
+	MarkAllRead(ctx context.Context, userID string, at time.Time) (int64, error)
+}
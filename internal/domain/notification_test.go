@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuietHours_Contains(t *testing.T) {
+	overnight := QuietHours{Start: "22:00", End: "07:00", Timezone: "Asia/Jakarta"} // UTC+7
+
+	assert.True(t, overnight.Contains(time.Date(2025, 6, 16, 20, 0, 0, 0, time.UTC)), "03:00 in Jakarta")
+	assert.True(t, overnight.Contains(time.Date(2025, 6, 16, 15, 0, 0, 0, time.UTC)), "22:00 in Jakarta")
+	assert.False(t, overnight.Contains(time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC)), "07:00 in Jakarta")
+	assert.False(t, overnight.Contains(time.Date(2025, 6, 16, 5, 0, 0, 0, time.UTC)), "noon in Jakarta")
+
+	siesta := QuietHours{Start: "13:00", End: "15:00"}
+	assert.True(t, siesta.Contains(time.Date(2025, 6, 16, 14, 30, 0, 0, time.UTC)))
+	assert.False(t, siesta.Contains(time.Date(2025, 6, 16, 15, 0, 0, 0, time.UTC)))
+}
+
+func TestQuietHours_Validate(t *testing.T) {
+	assert.NoError(t, QuietHours{Start: "22:00", End: "07:00", Timezone: "Europe/London"}.Validate())
+	assert.ErrorIs(t, QuietHours{Start: "22:00", End: "22:00"}.Validate(), ErrInvalidQuietHours)
+	assert.ErrorIs(t, QuietHours{Start: "10pm", End: "07:00"}.Validate(), ErrInvalidQuietHours)
+	assert.ErrorIs(t, QuietHours{Start: "22:00", End: "07:00", Timezone: "Mars/Olympus"}.Validate(), ErrInvalidQuietHours)
+}
+
+func TestNotificationPreferences_ChannelsFor(t *testing.T) {
+	prefs := &NotificationPreferences{Channels: map[string][]string{
+		NotificationScheduleReminder: {ChannelEmail, ChannelWhatsApp},
+		"invoice.paid":               {},
+	}}
+
+	assert.Equal(t, []string{ChannelEmail, ChannelWhatsApp}, prefs.ChannelsFor(NotificationScheduleReminder))
+	assert.Empty(t, prefs.ChannelsFor("invoice.paid"), "muted")
+	assert.Equal(t, DefaultChannels, prefs.ChannelsFor("other"))
+	assert.Equal(t, []string{ChannelEmail}, prefs.ChannelsFor(NotificationReportReady))
+	assert.ErrorIs(t, ValidateChannels(map[string][]string{"x": {"sms"}}), ErrInvalidChannel)
+}
+
+func TestNotification_Payload(t *testing.T) {
+	n := &Notification{
+		Type: NotificationScheduleBooked,
+		Data: map[string]string{"start_time": "2026-10-20T09:00:00Z"},
+		Link: &DeepLink{Screen: ScreenSchedule, ScheduleID: "s 1"},
+	}
+	assert.Equal(t, map[string]string{
+		"type":        NotificationScheduleBooked,
+		"start_time":  "2026-10-20T09:00:00Z",
+		"screen":      ScreenSchedule,
+		"schedule_id": "s 1",
+		"link":        "metamorph://schedule?schedule_id=s+1",
+	}, n.Payload())
+
+	assert.Equal(t, map[string]string{"type": NotificationScheduleReminder}, (&Notification{Type: NotificationScheduleReminder}).Payload())
+}
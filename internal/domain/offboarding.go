@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrOffboardingStarted        = errors.New("tenant is already being off-boarded")
+	ErrOffboardingConfirmation   = errors.New("confirm the off-boarding with the tenant's exact name")
+	ErrInvalidOffboardingGrace   = errors.New("grace period must be between 7 and 90 days")
+	ErrOffboardingNotCancellable = errors.New("off-boarding can only be cancelled during the grace period")
+	ErrTenantDeactivated         = errors.New("this gym's account has been deactivated")
+)
+
+// Grace period between deactivating a tenant and erasing its members' personal data
+const (
+	DefaultOffboardingGraceDays = 30
+	MinOffboardingGraceDays     = 7
+	MaxOffboardingGraceDays     = 90
+)
+
+// Off-boarding stages, in order. A failed step keeps its stage, with Error set, and is
+// picked up again by the next run.
+const (
+	OffboardingStageExporting   = "exporting"   // Generating the archive of all the tenant's data
+	OffboardingStageGrace       = "grace"       // Tenant deactivated; can still be cancelled
+	OffboardingStageAnonymizing = "anonymizing" // Erasing the members' personal data
+	OffboardingStagePurging     = "purging"     // Deleting the tenant's records
+	OffboardingStageCompleted   = "completed"
+	OffboardingStageCancelled   = "cancelled"
+)
+
+// OffboardingProgress counts what each stage has done so far
+type OffboardingProgress struct {
+	ExportedRecords   map[string]int `json:"exported_records,omitempty" bson:"exported_records,omitempty"` // Dataset -> records
+	ArchiveBytes      int64          `json:"archive_bytes,omitempty" bson:"archive_bytes,omitempty"`
+	MembersAnonymized int            `json:"members_anonymized" bson:"members_anonymized"`
+	RecordsPurged     int64          `json:"records_purged" bson:"records_purged"`
+}
+
+// TenantOffboarding is the guarded deletion of a tenant. Its data is exported, the tenant is
+// deactivated for a grace period in which it can be restored, and only then are members
+// anonymized and everything purged. The record outlives the tenant as proof of deletion.
+type TenantOffboarding struct {
+	ID          string              `json:"id" bson:"_id"`
+	TenantID    string              `json:"tenant_id" bson:"tenant_id"`
+	TenantName  string              `json:"tenant_name" bson:"tenant_name"`
+	Stage       string              `json:"stage" bson:"stage"`
+	Progress    OffboardingProgress `json:"progress" bson:"progress"`
+	ArchiveURL  string              `json:"-" bson:"archive_url,omitempty"` // Served through a signed URL
+	GraceDays   int                 `json:"grace_days" bson:"grace_days"`
+	GraceUntil  *time.Time          `json:"grace_until,omitempty" bson:"grace_until,omitempty"`
+	Error       string              `json:"error,omitempty" bson:"error,omitempty"`
+	RequestedBy string              `json:"requested_by" bson:"requested_by"`
+
+	CreatedAt     time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" bson:"updated_at"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty" bson:"deactivated_at,omitempty"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty" bson:"finished_at,omitempty"` // Completed or cancelled
+}
+
+// Active reports whether the off-boarding still has work to do
+func (o *TenantOffboarding) Active() bool {
+	return o.Stage != OffboardingStageCompleted && o.Stage != OffboardingStageCancelled
+}
+
+// Cancellable reports whether the tenant can still be restored: during the grace period,
+// or when the export failed before anything was deactivated
+func (o *TenantOffboarding) Cancellable() bool {
+	return o.Stage == OffboardingStageGrace || (o.Stage == OffboardingStageExporting && o.Error != "")
+}
+
+// ValidateOffboardingGrace checks a requested grace period in days
+func ValidateOffboardingGrace(days int) error {
+	if days < MinOffboardingGraceDays || days > MaxOffboardingGraceDays {
+		return ErrInvalidOffboardingGrace
+	}
+	return nil
+}
+
+type TenantOffboardingRepository interface {
+	// Create returns ErrOffboardingStarted if the tenant has an active off-boarding
+	Create(ctx context.Context, o *TenantOffboarding) error
+	// GetLatest returns the tenant's most recent off-boarding, or ErrNotFound
+	GetLatest(ctx context.Context, tenantID string) (*TenantOffboarding, error)
+	// Update saves stage, progress, archive, grace, error and timestamps
+	Update(ctx context.Context, o *TenantOffboarding) error
+	// ListActive returns the off-boardings that aren't completed or cancelled, oldest first
+	ListActive(ctx context.Context) ([]*TenantOffboarding, error)
+}
+
+// AnonymizedBatch is what one anonymization pass did. Files are the stored scans and
+// signatures the members' records no longer refer to, for the caller to delete.
+type AnonymizedBatch struct {
+	Members int
+	Files   []string
+}
+
+// TenantDataRepository reads and erases everything a tenant owns. Users who also belong to
+// another tenant keep their account and personal records; only the tenant's part is removed.
+type TenantDataRepository interface {
+	// Export calls write with every record of the tenant as JSON, dataset by dataset, and
+	// returns how many records each dataset had
+	Export(ctx context.Context, tenantID string, write func(dataset string, record []byte) error) (map[string]int, error)
+	// RevokeSessions revokes the refresh tokens of sessions in the tenant
+	RevokeSessions(ctx context.Context, tenantID string) error
+	// AnonymizeMembers replaces the personal data of up to limit members who belong only to
+	// the tenant and haven't been anonymized yet
+	AnonymizeMembers(ctx context.Context, tenantID string, limit int) (*AnonymizedBatch, error)
+	// Purge deletes the tenant, its records and the users who belong only to it, and
+	// returns how many documents were removed. It can be run again after a failure.
+	Purge(ctx context.Context, tenantID string) (int64, error)
+}
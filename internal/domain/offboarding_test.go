@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantOffboardingCancellable(t *testing.T) {
+	assert.True(t, (&TenantOffboarding{Stage: OffboardingStageGrace}).Cancellable())
+	assert.True(t, (&TenantOffboarding{Stage: OffboardingStageExporting, Error: "export failed"}).Cancellable())
+	assert.False(t, (&TenantOffboarding{Stage: OffboardingStageExporting}).Cancellable())
+	assert.False(t, (&TenantOffboarding{Stage: OffboardingStageAnonymizing}).Cancellable())
+	assert.False(t, (&TenantOffboarding{Stage: OffboardingStageCompleted}).Active())
+}
+
+func TestValidateOffboardingGrace(t *testing.T) {
+	assert.NoError(t, ValidateOffboardingGrace(DefaultOffboardingGraceDays))
+	assert.NoError(t, ValidateOffboardingGrace(MinOffboardingGraceDays))
+	assert.NoError(t, ValidateOffboardingGrace(MaxOffboardingGraceDays))
+	assert.ErrorIs(t, ValidateOffboardingGrace(6), ErrInvalidOffboardingGrace)
+	assert.ErrorIs(t, ValidateOffboardingGrace(91), ErrInvalidOffboardingGrace)
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Outbox topics. Subsystems that react to a topic register a handler on the relay.
+const (
+	OutboxTopicScanChanged     = "scan.changed"     // A member's scan was created, updated or deleted
+	OutboxTopicScanDigitized   = "scan.digitized"   // A scan was read from an InBody sheet; the key is the scan
+	OutboxTopicScheduleChanged = "schedule.changed" // A session was booked, changed or cancelled; see ScheduleChange*
+	OutboxTopicContractCreated = "contract.created" // A PT package was bought for a member
+	// A completed session left the contract running low; "remaining" holds the sessions left
+	OutboxTopicContractLowSessions = "contract.low_sessions"
+)
+
+// Changes reported under the "change" key of OutboxTopicScheduleChanged messages
+const (
+	ScheduleChangeBooked      = "booked"
+	ScheduleChangeRescheduled = "rescheduled"
+	ScheduleChangeModality    = "modality" // Moved online or back to the gym
+	// A batch of sessions was booked at once; the key is the first session and "count" the
+	// number booked
+	ScheduleChangeBatchBooked = "batch_booked"
+	ScheduleChangeCancelled   = "cancelled"
+	// The member moved their session, which now awaits the coach's confirmation
+	ScheduleChangeMemberRescheduled = "member_rescheduled"
+)
+
+// Outbox message statuses
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusDelivered = "delivered"
+	OutboxStatusDead      = "dead" // Gave up after MaxOutboxAttempts
+)
+
+// MaxOutboxAttempts is how many deliveries are tried before a message is marked dead
+const MaxOutboxAttempts = 10
+
+// OutboxMessage is a side effect recorded in the same transaction as the change that caused it,
+// then delivered at-least-once by the outbox relay
+type OutboxMessage struct {
+	ID            string                 `json:"id" bson:"_id,omitempty"`
+	Topic         string                 `json:"topic" bson:"topic"`
+	TenantID      string                 `json:"tenant_id,omitempty" bson:"tenant_id,omitempty"`
+	Key           string                 `json:"key" bson:"key"` // Entity the message is about, e.g. a user ID
+	Payload       map[string]interface{} `json:"payload,omitempty" bson:"payload,omitempty"`
+	Status        string                 `json:"status" bson:"status"`
+	Attempts      int                    `json:"attempts" bson:"attempts"`
+	LastError     string                 `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	NextAttemptAt time.Time              `json:"next_attempt_at" bson:"next_attempt_at"`
+	CreatedAt     time.Time              `json:"created_at" bson:"created_at"`
+	DeliveredAt   *time.Time             `json:"delivered_at,omitempty" bson:"delivered_at,omitempty"`
+}
+
+type OutboxRepository interface {
+	Add(ctx context.Context, msg *OutboxMessage) error
+	// Claim leases the oldest due pending message until now+lease so other relays skip it.
+	// Returns nil when nothing is due.
+	Claim(ctx context.Context, now time.Time, lease time.Duration) (*OutboxMessage, error)
+	MarkDelivered(ctx context.Context, id string, at time.Time) error
+	// MarkFailed records the error and schedules the next attempt; dead messages are never retried
+	MarkFailed(ctx context.Context, id string, lastError string, nextAttemptAt time.Time, dead bool) error
+}
+
+// Transactor runs fn in a database transaction. Repository calls made with the ctx passed
+// to fn take part in it; if fn returns an error nothing is committed.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -10,11 +10,15 @@ type Package struct {
 	ID             string    `bson:"_id,omitempty" json:"id"`
 	Name           string    `bson:"name,omitempty" json:"name"`
 	Description    string    `bson:"description,omitempty" json:"description"`
-	Price          int64     `bson:"price,omitempty" json:"price"` // Price in smallest currency unit (e.g., IDR)
+	Price          Money     `bson:"price" json:"price"`
 	DurationMonths int       `bson:"duration_months,omitempty" json:"duration_months"`
 	IsActive       bool      `bson:"is_active,omitempty" json:"is_active"`
 	CreatedAt      time.Time `bson:"created_at,omitempty" json:"created_at"`
 	UpdatedAt      time.Time `bson:"updated_at,omitempty" json:"updated_at"`
+
+	// The PT package whose sessions a paid checkout credits to the member's contract on it;
+	// empty for plain subscriptions
+	PTPackageID string `bson:"pt_package_id,omitempty" json:"pt_package_id,omitempty"`
 }
 
 // PackageRepository defines operations for managing packages
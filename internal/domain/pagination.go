@@ -0,0 +1,32 @@
+package domain
+
+// Default and maximum page sizes for cursor-paginated list endpoints
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// PageQuery requests one page of a list, newest first.
+// Cursor is the NextCursor of the previous page; empty starts from the beginning.
+type PageQuery struct {
+	Limit  int
+	Cursor string
+}
+
+// Normalized returns the query with Limit clamped to [1, MaxPageLimit]
+func (q PageQuery) Normalized() PageQuery {
+	if q.Limit <= 0 {
+		q.Limit = DefaultPageLimit
+	}
+	if q.Limit > MaxPageLimit {
+		q.Limit = MaxPageLimit
+	}
+	return q
+}
+
+// Page is one page of a cursor-paginated list
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "testing"
+
+func TestPageQueryNormalized(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{name: "unset uses the default", limit: 0, want: DefaultPageLimit},
+		{name: "negative uses the default", limit: -5, want: DefaultPageLimit},
+		{name: "within range is kept", limit: 30, want: 30},
+		{name: "above max is capped", limit: 1000, want: MaxPageLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PageQuery{Limit: tt.limit, Cursor: "c"}.Normalized()
+			if got.Limit != tt.want {
+				t.Errorf("Limit = %d, want %d", got.Limit, tt.want)
+			}
+			if got.Cursor != "c" {
+				t.Errorf("Cursor = %q, want it unchanged", got.Cursor)
+			}
+		})
+	}
+}
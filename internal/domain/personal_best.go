@@ -2,9 +2,74 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
+var ErrInvalidPBRules = errors.New("invalid PB rules")
+
+// PBRules decide which sets can count as a personal best
+type PBRules struct {
+	CompletedOnly bool `bson:"completed_only" json:"completed_only"` // Sets not ticked off as completed don't count
+	MinReps       int  `bson:"min_reps" json:"min_reps"`
+	MaxReps       int  `bson:"max_reps" json:"max_reps"` // 0 is no limit
+	// Sessions the member rated harder than this don't count; 0 is no limit, and unrated
+	// sessions always count
+	MaxRPE int `bson:"max_rpe" json:"max_rpe"`
+	// Machine- or band-assisted movements, recognised by "assisted" in the exercise name
+	ExcludeAssisted     bool     `bson:"exclude_assisted" json:"exclude_assisted"`
+	ExcludedExerciseIDs []string `bson:"excluded_exercise_ids,omitempty" json:"excluded_exercise_ids,omitempty"`
+}
+
+// DefaultPBRules apply to tenants that haven't set their own: the heaviest completed set counts
+var DefaultPBRules = PBRules{CompletedOnly: true}
+
+func (r PBRules) Validate() error {
+	if r.MinReps < 0 || r.MaxReps < 0 {
+		return fmt.Errorf("%w: reps can't be negative", ErrInvalidPBRules)
+	}
+	if r.MaxReps > 0 && r.MaxReps < r.MinReps {
+		return fmt.Errorf("%w: max_reps is below min_reps", ErrInvalidPBRules)
+	}
+	if r.MaxRPE < 0 || r.MaxRPE > 10 {
+		return fmt.Errorf("%w: max_rpe must be 1-10, or 0 for no limit", ErrInvalidPBRules)
+	}
+	return nil
+}
+
+// Counts reports whether a set may count as a personal best. sessionRPE is nil for a
+// session the member didn't rate.
+func (r PBRules) Counts(set *SetLogDocument, sessionRPE *int) bool {
+	switch {
+	case set.Weight <= 0 || set.DeletedAt != nil:
+		return false
+	case r.CompletedOnly && !set.Completed:
+		return false
+	case set.Reps < r.MinReps || (r.MaxReps > 0 && set.Reps > r.MaxReps):
+		return false
+	case r.MaxRPE > 0 && sessionRPE != nil && *sessionRPE > r.MaxRPE:
+		return false
+	}
+	return !slices.Contains(r.ExcludedExerciseIDs, set.ExerciseID)
+}
+
+// ExcludesExercise reports whether the rules leave an exercise out altogether
+func (r PBRules) ExcludesExercise(exercise *Exercise) bool {
+	return slices.Contains(r.ExcludedExerciseIDs, exercise.ID) ||
+		(r.ExcludeAssisted && strings.Contains(strings.ToLower(exercise.Name), "assisted"))
+}
+
+// PersonalBestRules returns the tenant's PB rules, or the defaults
+func (t *Tenant) PersonalBestRules() PBRules {
+	if t == nil || t.PBRules == nil {
+		return DefaultPBRules
+	}
+	return *t.PBRules
+}
+
 // PersonalBest tracks a member's personal best for an exercise
 type PersonalBest struct {
 	ID         string    `json:"id" bson:"_id,omitempty"`
@@ -26,4 +91,6 @@ type PersonalBestRepository interface {
 	Upsert(ctx context.Context, pb *PersonalBest) (bool, error) // Returns true if PB was updated
 	// GetByMember retrieves all PBs for a member
 	GetByMember(ctx context.Context, memberID string) ([]*PersonalBest, error)
+	// ReplaceForMember swaps all of a member's PBs for pbs, keeping their AchievedAt
+	ReplaceForMember(ctx context.Context, memberID string, pbs []*PersonalBest) error
 }
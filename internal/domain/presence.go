@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Presence of a coach, from the heartbeats the coach app sends while it is open
+const (
+	PresenceInSession = "in_session" // App open during one of their sessions
+	PresenceOnline    = "online"
+	PresenceOffline   = "offline"
+)
+
+// PresenceTTL is how long a heartbeat keeps a coach online. The app beats every 30 seconds,
+// so a coach drops off within two minutes of closing it or losing signal.
+const PresenceTTL = 2 * time.Minute
+
+// CoachPresence is where a coach stands on the floor right now
+type CoachPresence struct {
+	CoachID    string     `json:"coach_id"`
+	Name       string     `json:"name"`
+	Status     string     `json:"status"` // in_session, online or offline
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+	Session    *Schedule  `json:"session,omitempty"` // Their session running now, even when offline
+}
+
+// PresenceBoard is the tenant's coaches, in session first, then online, then offline
+type PresenceBoard struct {
+	InSession int              `json:"in_session"`
+	Online    int              `json:"online"` // Including those in session
+	Coaches   []*CoachPresence `json:"coaches"`
+}
+
+// PresenceRepository keeps the last heartbeat of each user per tenant for PresenceTTL
+type PresenceRepository interface {
+	Heartbeat(ctx context.Context, tenantID, userID string, at time.Time) error
+	// SeenSince returns the last heartbeat of the tenant's users seen since the time
+	SeenSince(ctx context.Context, tenantID string, since time.Time) (map[string]time.Time, error)
+}
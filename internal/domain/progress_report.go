@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// MaxReportDays bounds the period of a progress report to a year
+const MaxReportDays = 366
+
+var ErrInvalidReportPeriod = errors.New("a report covers 1 to 366 days, ending no later than today")
+
+// ProgressReport is the data behind a client progress report PDF, returned with a link
+// the coach can share with the member
+type ProgressReport struct {
+	MemberID      string               `json:"member_id"`
+	MemberName    string               `json:"member_name"`
+	From          time.Time            `json:"from"`
+	To            time.Time            `json:"to"`
+	Scans         []*InBodyRecord      `json:"scans"`  // Oldest first
+	Volume        []WeeklyVolume       `json:"volume"` // One entry per week of the period
+	PersonalBests []ReportPersonalBest `json:"personal_bests"`
+	Attendance    ReportAttendance     `json:"attendance"`
+	Photos        []*SessionPhoto      `json:"photos,omitempty"`  // Session photos, when the member agreed to them in reports
+	Summary       string               `json:"summary,omitempty"` // AI narrative; empty when AI is unavailable or out of quota
+	DocumentURL   string               `json:"-"`
+	ShareURL      string               `json:"share_url"`
+	ShareExpires  time.Time            `json:"share_expires_at"`
+	GeneratedAt   time.Time            `json:"generated_at"`
+}
+
+// WeeklyVolume is the training volume of the week starting at WeekStart
+type WeeklyVolume struct {
+	WeekStart time.Time `json:"week_start"`
+	Volume    float64   `json:"volume"`
+	Sessions  int       `json:"sessions"`
+}
+
+// ReportPersonalBest is a PB set during the report's period
+type ReportPersonalBest struct {
+	Exercise   string    `json:"exercise"`
+	Weight     float64   `json:"weight"`
+	Reps       int       `json:"reps"`
+	AchievedAt time.Time `json:"achieved_at"`
+}
+
+// ReportAttendance counts the member's sessions in the report's period
+type ReportAttendance struct {
+	Completed int `json:"completed"`
+	NoShow    int `json:"no_show"`
+	Cancelled int `json:"cancelled"`
+}
+
+// Rate is the share of attended sessions among those the member was expected at, 0-100
+func (a ReportAttendance) Rate() int {
+	if a.Completed+a.NoShow == 0 {
+		return 0
+	}
+	return a.Completed * 100 / (a.Completed + a.NoShow)
+}
+
+// ProgressSummarizer writes the narrative of a progress report in the tenant's voice
+type ProgressSummarizer interface {
+	SummarizeProgress(ctx context.Context, report *ProgressReport, tenant *Tenant) (string, error)
+}
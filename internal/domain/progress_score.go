@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidProgressWeights = errors.New("invalid progress score weights")
+
+// ProgressWeights sets how much each component counts towards a progress score. Only the
+// ratios matter: the score is the weighted average of the components.
+type ProgressWeights struct {
+	Attendance      float64 `bson:"attendance" json:"attendance"`
+	Volume          float64 `bson:"volume" json:"volume"`
+	BodyComposition float64 `bson:"body_composition" json:"body_composition"`
+	PersonalBests   float64 `bson:"personal_bests" json:"personal_bests"`
+}
+
+// DefaultProgressWeights apply to tenants that haven't set their own
+var DefaultProgressWeights = ProgressWeights{Attendance: 35, Volume: 25, BodyComposition: 25, PersonalBests: 15}
+
+func (w ProgressWeights) Validate() error {
+	if w.Attendance < 0 || w.Volume < 0 || w.BodyComposition < 0 || w.PersonalBests < 0 {
+		return fmt.Errorf("%w: weights can't be negative", ErrInvalidProgressWeights)
+	}
+	if w.Attendance+w.Volume+w.BodyComposition+w.PersonalBests == 0 {
+		return fmt.Errorf("%w: at least one weight must be above zero", ErrInvalidProgressWeights)
+	}
+	return nil
+}
+
+// ProgressScoreWeights returns the tenant's weights, or the defaults
+func (t *Tenant) ProgressScoreWeights() ProgressWeights {
+	if t == nil || t.ProgressWeights == nil {
+		return DefaultProgressWeights
+	}
+	return *t.ProgressWeights
+}
+
+// ProgressComponents are the parts of a progress score, each 0-100
+type ProgressComponents struct {
+	Attendance      int `bson:"attendance" json:"attendance"`
+	Volume          int `bson:"volume" json:"volume"`
+	BodyComposition int `bson:"body_composition" json:"body_composition"`
+	PersonalBests   int `bson:"personal_bests" json:"personal_bests"`
+}
+
+// ProgressScore is a member's 0-100 progress over the four weeks up to the end of a week
+type ProgressScore struct {
+	ID         string             `bson:"_id" json:"id"` // member:week, so rescoring a week replaces it
+	TenantID   string             `bson:"tenant_id" json:"tenant_id"`
+	MemberID   string             `bson:"member_id" json:"member_id"`
+	WeekStart  time.Time          `bson:"week_start" json:"week_start"` // Monday 00:00 UTC
+	Score      int                `bson:"score" json:"score"`
+	Components ProgressComponents `bson:"components" json:"components"`
+	Weights    ProgressWeights    `bson:"weights" json:"weights"` // As they were when scored
+	ComputedAt time.Time          `bson:"computed_at" json:"computed_at"`
+}
+
+type ProgressScoreRepository interface {
+	Upsert(ctx context.Context, score *ProgressScore) error
+	// ListByMember returns the member's scores within the tenant, newest week first
+	ListByMember(ctx context.Context, tenantID, memberID string, limit int) ([]*ProgressScore, error)
+	// Leaderboard returns the week's highest scores in the tenant
+	Leaderboard(ctx context.Context, tenantID string, weekStart time.Time, limit int) ([]*ProgressScore, error)
+}
+
+// LeaderboardEntry is a ranked progress score with the member's name
+type LeaderboardEntry struct {
+	Rank int    `json:"rank"`
+	Name string `json:"name"`
+	*ProgressScore
+}
@@ -15,6 +15,7 @@ var (
 	ErrPackageTemplateNotFound = errors.New("pt package template not found")
 	ErrUnauthorizedReschedule  = errors.New("unauthorized to reschedule this session")
 	ErrBranchMismatch          = errors.New("branch mismatch: package, member, and coach must belong to the same branch")
+	ErrInvalidPackageValidity  = errors.New("validity_months cannot be negative")
 )
 
 // PT Package Constants
@@ -24,6 +25,10 @@ const (
 	PackageStatusExpired  = "Expired"
 )
 
+// LowSessionsThreshold: a contract with fewer sessions left is running low. Coaches see it
+// on their dashboard, and the member is warned with every session completed from there.
+const LowSessionsThreshold = 3
+
 // Schedule Status Constants
 const (
 	ScheduleStatusScheduled           = "Scheduled"
@@ -52,6 +57,11 @@ var ValidFocusAreas = []string{
 	FocusAreaCore, FocusAreaOther,
 }
 
+// ValidSessionAmount reports whether n is one of the package tiers (10, 20, 30, 40 or 50)
+func ValidSessionAmount(n int) bool {
+	return n >= 10 && n <= 50 && n%10 == 0
+}
+
 // PTPackage represents a generic package Template offered by a Branch/Tenant
 // e.g., "10 Sessions Promo - Downtown Branch"
 type PTPackage struct {
@@ -60,10 +70,16 @@ type PTPackage struct {
 	BranchID      string    `json:"branch_id" bson:"branch_id"` // Packages are often branch-specific for pricing/availability
 	Name          string    `json:"name" bson:"name"`
 	TotalSessions int       `json:"total_sessions" bson:"total_sessions"` // 10, 20, 30, 40, 50
-	Price         float64   `json:"price" bson:"price"`
+	Price         Money     `json:"price" bson:"price"`
 	Active        bool      `json:"active" bson:"active"` // If false, no new contracts can be created from this
 	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at" bson:"updated_at"`
+
+	// Prices at branches that charge differently; contracts at other branches pay Price
+	BranchPrices []BranchPrice `json:"branch_prices,omitempty" bson:"branch_prices,omitempty"`
+
+	// Months the sessions may be used after purchase; 0 means they never expire
+	ValidityMonths int `json:"validity_months,omitempty" bson:"validity_months,omitempty"`
 }
 
 // PTContract represents a specific purchase of a Package by a Member, assigned to a Coach
@@ -75,31 +91,72 @@ type PTContract struct {
 	MemberID          string    `json:"member_id" bson:"member_id"`
 	CoachID           string    `json:"coach_id" bson:"coach_id"`
 	TotalSessions     int       `json:"total_sessions" bson:"total_sessions"`         // Copied from Package at time of purchase
-	RemainingSessions int       `json:"remaining_sessions" bson:"remaining_sessions"` // decrements on completion
-	Price             float64   `json:"price" bson:"price"`                           // Copied from Package at time of purchase
-	Status            string    `json:"status" bson:"status"`                         // Active, Depleted, Expired
+	RemainingSessions int       `json:"remaining_sessions" bson:"remaining_sessions"` // Projection of the credit ledger balance
+	Price             Money     `json:"price" bson:"price"`                           // Copied from Package at time of purchase
+	Status            string    `json:"status" bson:"status"`                         // Active, Depleted, Expired, Suspended, Pending_Payment
+	LedgerSequence    int64     `json:"-" bson:"ledger_sequence,omitempty"`           // Last credit ledger entry reflected in RemainingSessions
 	CreatedAt         time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at" bson:"updated_at"`
+
+	// Coaches who covered one of its sessions while the contract's coach was unavailable
+	CoverCoachIDs []string `json:"cover_coach_ids,omitempty" bson:"cover_coach_ids,omitempty"`
+
+	// Price is the package's price at BranchID rather than its base price
+	BranchPriced bool `json:"branch_priced,omitempty" bson:"branch_priced,omitempty"`
+
+	// When the remaining sessions expire, set for packages with a validity, and when the
+	// member and coach were warned
+	ExpiresAt      *time.Time `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	ExpiryWarnedAt *time.Time `json:"expiry_warned_at,omitempty" bson:"expiry_warned_at,omitempty"`
+
+	// Which of their metrics the member may see; nil shows everything
+	MetricVisibility *MetricVisibility `json:"metric_visibility,omitempty" bson:"metric_visibility,omitempty"`
+
+	// The member's opt-in to renew the package when it runs out, when it was renewed and,
+	// on the renewal, the contract it continues
+	AutoRenew *AutoRenewal `json:"auto_renew,omitempty" bson:"auto_renew,omitempty"`
+	RenewedAt *time.Time   `json:"renewed_at,omitempty" bson:"renewed_at,omitempty"`
+	RenewalOf string       `json:"renewal_of,omitempty" bson:"renewal_of,omitempty"`
+
+	// Values of the tenant's custom contract fields (see Tenant.CustomFields)
+	CustomFields CustomFieldValues `json:"custom_fields,omitempty" bson:"custom_fields,omitempty"`
 }
 
 // Schedule represents a single PT session, linked to a Contract
 type Schedule struct {
-	ID          string     `json:"id" bson:"_id,omitempty"`
-	ClientID    string     `json:"client_id,omitempty" bson:"client_id,omitempty"` // Frontend ULID for dual-identity handshake
-	TenantID    string     `json:"tenant_id" bson:"tenant_id"`
-	BranchID    string     `json:"branch_id" bson:"branch_id"`
-	ContractID  string     `json:"contract_id" bson:"contract_id"` // Replaces PackageID reference
-	CoachID     string     `json:"coach_id" bson:"coach_id"`
-	MemberID    string     `json:"member_id" bson:"member_id"`
-	StartTime   time.Time  `json:"start_time" bson:"start_time"`
-	EndTime     time.Time  `json:"end_time" bson:"end_time"`
-	Status      string     `json:"status" bson:"status"`
-	SessionGoal string     `json:"session_goal,omitempty" bson:"session_goal,omitempty"` // e.g., "Leg Day - Hypertrophy Focus"
-	FocusArea   string     `json:"focus_area,omitempty" bson:"focus_area,omitempty"`     // LEG_DAY, UPPER_BODY, BACK_DAY, etc.
-	Remarks     string     `json:"remarks,omitempty" bson:"remarks,omitempty"`           // Coach notes
-	DeletedAt   *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`     // Soft delete timestamp
-	CreatedAt   time.Time  `json:"created_at" bson:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" bson:"updated_at"`
+	ID          string         `json:"id" bson:"_id,omitempty"`
+	ClientID    string         `json:"client_id,omitempty" bson:"client_id,omitempty"` // Frontend ULID for dual-identity handshake
+	TenantID    string         `json:"tenant_id" bson:"tenant_id"`
+	BranchID    string         `json:"branch_id" bson:"branch_id"`
+	ContractID  string         `json:"contract_id" bson:"contract_id"` // Replaces PackageID reference
+	CoachID     string         `json:"coach_id" bson:"coach_id"`
+	MemberID    string         `json:"member_id" bson:"member_id"`
+	StartTime   time.Time      `json:"start_time" bson:"start_time"`
+	EndTime     time.Time      `json:"end_time" bson:"end_time"`
+	Status      string         `json:"status" bson:"status"`
+	SessionGoal string         `json:"session_goal,omitempty" bson:"session_goal,omitempty"` // e.g., "Leg Day - Hypertrophy Focus"
+	FocusArea   string         `json:"focus_area,omitempty" bson:"focus_area,omitempty"`     // LEG_DAY, UPPER_BODY, BACK_DAY, etc.
+	Remarks     string         `json:"remarks,omitempty" bson:"remarks,omitempty"`           // Coach notes
+	DeletedAt   *time.Time     `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`     // Soft delete timestamp
+	ArchivedAt  *time.Time     `json:"archived_at,omitempty" bson:"archived_at,omitempty"`   // Set logs moved to cold storage
+	CopiedFrom  string         `json:"copied_from,omitempty" bson:"copied_from,omitempty"`   // Source schedule of a member transferred with the copy policy
+	CoveredFor  string         `json:"covered_for,omitempty" bson:"covered_for,omitempty"`   // Coach whose session a substitute took over
+	SelfLogged  bool           `json:"self_logged,omitempty" bson:"self_logged,omitempty"`   // Member trained alone; no coach and no contract credit
+	PlanNotes   string         `json:"plan_notes,omitempty" bson:"plan_notes,omitempty"`     // Coach's brief for the member, unlike Remarks
+	PlanShared  *time.Time     `json:"plan_shared_at,omitempty" bson:"plan_shared_at,omitempty"`
+	Tags        []string       `json:"tags,omitempty" bson:"tags,omitempty"`               // Free-form, lowercase: "assessment", "trial"
+	Label       string         `json:"label,omitempty" bson:"label,omitempty"`             // Short calendar caption
+	Color       string         `json:"color,omitempty" bson:"color,omitempty"`             // Calendar color, "#RRGGBB"
+	Modality    string         `json:"modality,omitempty" bson:"modality,omitempty"`       // in_person or online; empty means in person
+	MeetingURL  string         `json:"meeting_url,omitempty" bson:"meeting_url,omitempty"` // Video link of an online session
+	Effort      *SessionEffort `json:"effort,omitempty" bson:"effort,omitempty"`           // Heart rate and RPE of a completed session
+	CreatedAt   time.Time      `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at" bson:"updated_at"`
+
+	// Whether the member will attend, answered from a reminder, and when the coach was alerted
+	// that they hadn't answered
+	Confirmation   *SessionConfirmation `json:"confirmation,omitempty" bson:"confirmation,omitempty"`
+	CoachAlertedAt *time.Time           `json:"coach_alerted_at,omitempty" bson:"coach_alerted_at,omitempty"`
 }
 
 // Repositories
@@ -124,7 +181,9 @@ type PTContractRepository interface {
 	GetActiveByMember(ctx context.Context, memberID string) ([]*PTContract, error)
 	GetActiveByCoach(ctx context.Context, coachID string) ([]*PTContract, error)
 	GetByTenant(ctx context.Context, tenantID string) ([]*PTContract, error)
-	DecrementSession(ctx context.Context, contractID string) error
+	ListByTenant(ctx context.Context, tenantID string, page PageQuery) (*Page[*PTContract], error) // Newest first, cursor-paginated
+	// SyncBalance projects the credit ledger balance onto RemainingSessions (ignores stale sequences)
+	SyncBalance(ctx context.Context, contractID string, remaining int, sequence int64) error
 	UpdateStatus(ctx context.Context, contractID string, status string) error
 	// GetLowSessionsByCoach returns contracts with remaining sessions below threshold
 	GetLowSessionsByCoach(ctx context.Context, coachID string, threshold int) ([]*PTContract, error)
@@ -134,6 +193,38 @@ type PTContractRepository interface {
 	GetFirstActiveContractByCoachAndMember(ctx context.Context, coachID, memberID string) (*PTContract, error)
 	// GetByMemberAndCoach returns all contracts between a member and coach
 	GetByMemberAndCoach(ctx context.Context, memberID, coachID string) ([]*PTContract, error)
+	// GetByMemberAndPackage returns the member's contracts on a PT package, newest first
+	GetByMemberAndPackage(ctx context.Context, memberID, packageID string) ([]*PTContract, error)
+	// AddCoverCoach records a substitute coach who took over one of the contract's sessions
+	AddCoverCoach(ctx context.Context, contractID, coachID string) error
+	// ReassignCoach moves the contract from one coach to another, returning false if it no
+	// longer belongs to fromCoachID
+	ReassignCoach(ctx context.Context, contractID, fromCoachID, toCoachID string) (bool, error)
+	// ListExpiringBefore returns the active and suspended contracts of every tenant whose
+	// sessions expire before the given time
+	ListExpiringBefore(ctx context.Context, before time.Time) ([]*PTContract, error)
+	// MarkExpiryWarned records that the contract's expiry was announced. It returns false if
+	// it already was.
+	MarkExpiryWarned(ctx context.Context, contractID string, at time.Time) (bool, error)
+	// SetMetricVisibility replaces what the member may see of their metrics; nil shows everything
+	SetMetricVisibility(ctx context.Context, contractID string, visibility *MetricVisibility) error
+	// SetAutoRenew replaces the member's auto-renewal opt-in; nil turns it off
+	SetAutoRenew(ctx context.Context, contractID string, renewal *AutoRenewal) error
+	// SetCustomFields replaces the contract's custom field values; nil clears them
+	SetCustomFields(ctx context.Context, contractID string, values CustomFieldValues) error
+	// ClaimRenewal records that the contract is being renewed. It returns false if it already
+	// was; ReleaseRenewal undoes the claim when the renewal couldn't be created.
+	ClaimRenewal(ctx context.Context, contractID string, at time.Time) (bool, error)
+	ReleaseRenewal(ctx context.Context, contractID string) error
+	// ListRenewalsDue returns the contracts of every tenant set to renew that haven't been
+	// renewed and have no sessions left or expired before now
+	ListRenewalsDue(ctx context.Context, now time.Time) ([]*PTContract, error)
+	// ListRenewalsByCoach returns the coach's contracts set to renew that haven't been, and
+	// renewals still waiting for payment
+	ListRenewalsByCoach(ctx context.Context, coachID string) ([]*PTContract, error)
+	// ActivatePending makes a contract waiting for payment active with the given expiry. It
+	// returns false if the contract wasn't waiting for payment.
+	ActivatePending(ctx context.Context, contractID string, expiresAt *time.Time) (bool, error)
 }
 
 type ScheduleRepository interface {
@@ -143,7 +234,10 @@ type ScheduleRepository interface {
 	GetByCoach(ctx context.Context, coachID string, from, to time.Time) ([]*Schedule, error)
 	GetByCoachAllStatuses(ctx context.Context, coachID string, from, to time.Time) ([]*Schedule, error) // For hydration - includes cancelled
 	GetByMember(ctx context.Context, memberID string, from, to time.Time) ([]*Schedule, error)
+	// ListStartingBetween returns the schedules of every tenant starting in [from, to) with one of the statuses
+	ListStartingBetween(ctx context.Context, from, to time.Time, statuses []string) ([]*Schedule, error)
 	List(ctx context.Context, tenantID string, filterOpts map[string]interface{}) ([]*Schedule, error)
+	ListPage(ctx context.Context, tenantID string, filterOpts map[string]interface{}, page PageQuery) (*Page[*Schedule], error) // Newest first, cursor-paginated
 	Update(ctx context.Context, schedule *Schedule) error
 	UpdateStatus(ctx context.Context, id string, status string) error
 	Delete(ctx context.Context, id string) error
@@ -153,6 +247,21 @@ type ScheduleRepository interface {
 	CountByContractsAndStatus(ctx context.Context, contractIDs []string, statuses []string) (map[string]int, error)
 	// GetAttendanceByCoach fetches all schedules for a coach within the last N days
 	GetAttendanceByCoach(ctx context.Context, coachID string, days int) ([]*Schedule, error)
-	// GetMemberScheduleStats returns schedule status counts for a member
+	// GetMemberScheduleStats returns PT session status counts for a member, without self-logged workouts
 	GetMemberScheduleStats(ctx context.Context, memberID string) (completed int, cancelled int, noShow int, err error)
+	// SetConfirmation records the member's answer. nil clears it along with the coach alert,
+	// for a session that moved.
+	SetConfirmation(ctx context.Context, id string, confirmation *SessionConfirmation) error
+	// MarkCoachAlerted records that the coach was alerted about an unconfirmed session. It
+	// returns false if the member answered meanwhile or the coach was already alerted.
+	MarkCoachAlerted(ctx context.Context, id string, at time.Time) (bool, error)
+	// Reassign hands an upcoming session from one coach to another, noting whose session it
+	// was (empty when it went back to its own coach). It returns false if the session no
+	// longer belongs to fromCoachID or isn't upcoming.
+	Reassign(ctx context.Context, id, fromCoachID, toCoachID, coveredFor string) (bool, error)
+	// CountByTag groups the tenant's PT sessions starting in [from, to) by tag, most used first.
+	// An empty coachID counts every coach.
+	CountByTag(ctx context.Context, tenantID, coachID string, from, to time.Time) ([]ScheduleTagCount, error)
+	// ListChangedByCoach returns the coach's schedules updated after since, soft-deleted ones included
+	ListChangedByCoach(ctx context.Context, coachID string, since time.Time) ([]*Schedule, error)
 }
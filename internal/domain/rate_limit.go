@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit lets a client send Requests at once, then refills them evenly over Per. The
+// zero RateLimit doesn't limit anything.
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// Enabled reports whether the limit is enforced
+func (l RateLimit) Enabled() bool {
+	return l.Requests > 0 && l.Per > 0
+}
+
+func (l RateLimit) String() string {
+	if !l.Enabled() {
+		return "0"
+	}
+	return fmt.Sprintf("%d/%s", l.Requests, l.Per)
+}
+
+// ParseRateLimit reads a limit written as "requests/period", e.g. "10/1m" or "100/1h".
+// "0" and "" turn the limit off.
+func ParseRateLimit(s string) (RateLimit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return RateLimit{}, nil
+	}
+	count, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("rate limit %q must look like 10/1m", s)
+	}
+	requests, err := strconv.Atoi(count)
+	if err != nil || requests < 0 {
+		return RateLimit{}, fmt.Errorf("rate limit %q must start with a number of requests", s)
+	}
+	per, err := time.ParseDuration(period)
+	if err != nil || per <= 0 {
+		return RateLimit{}, fmt.Errorf("rate limit %q must end with a period such as 1m", s)
+	}
+	return RateLimit{Requests: requests, Per: per}, nil
+}
+
+// Bucket is the bucket of one key, e.g. a client IP or user, and the limit it refills at
+type Bucket struct {
+	Key   string
+	Limit RateLimit
+}
+
+// TokenBucket limits requests per key, with a bucket per key
+type TokenBucket interface {
+	// Take takes a request from each of the buckets if every one has a request left, and
+	// otherwise from none of them, reporting how long until they all have. Buckets with a
+	// disabled limit are ignored.
+	Take(ctx context.Context, buckets []Bucket) (bool, time.Duration, error)
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	limit, err := ParseRateLimit(" 10/1m ")
+	require.NoError(t, err)
+	assert.Equal(t, RateLimit{Requests: 10, Per: time.Minute}, limit)
+	assert.True(t, limit.Enabled())
+	assert.Equal(t, "10/1m0s", limit.String())
+
+	for _, off := range []string{"", "0", "0/1m"} {
+		limit, err := ParseRateLimit(off)
+		require.NoError(t, err, off)
+		assert.False(t, limit.Enabled(), off)
+	}
+
+	for _, bad := range []string{"10", "ten/1m", "-1/1m", "10/minute", "10/0s"} {
+		_, err := ParseRateLimit(bad)
+		assert.Error(t, err, bad)
+	}
+}
@@ -9,7 +9,8 @@ import (
 type RefreshToken struct {
 	ID        string    `bson:"_id,omitempty" json:"id"`
 	UserID    string    `bson:"user_id" json:"user_id"`
-	TokenHash string    `bson:"token_hash" json:"-"` // SHA256 hash, never expose
+	TenantID  string    `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"` // Tenant the session is scoped to; empty for the primary
+	TokenHash string    `bson:"token_hash" json:"-"`                            // SHA256 hash, never expose
 	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
 	CreatedAt time.Time `bson:"created_at" json:"created_at"`
 	UserAgent string    `bson:"user_agent" json:"user_agent"` // Device tracking
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// PackageStatusPendingPayment marks a contract renewed automatically whose invoice isn't paid
+// yet. Its sessions are credited, and its validity starts, once it is.
+const PackageStatusPendingPayment = "Pending_Payment"
+
+var (
+	ErrInvalidPaymentMethod = errors.New("invalid payment_method, must be BCA, Mandiri, or BNI")
+	ErrContractRenewed      = errors.New("contract has already been renewed")
+)
+
+// PaymentMethods are the banks a VA can be issued at
+var PaymentMethods = []string{"BCA", "Mandiri", "BNI"}
+
+// ValidPaymentMethod reports whether method is one of PaymentMethods
+func ValidPaymentMethod(method string) bool {
+	return slices.Contains(PaymentMethods, method)
+}
+
+// AutoRenewal is a member's standing instruction to buy the same package again when a
+// contract's sessions run out or expire, paying by VA at PaymentMethod
+type AutoRenewal struct {
+	PaymentMethod string    `json:"payment_method" bson:"payment_method"`
+	EnabledAt     time.Time `json:"enabled_at" bson:"enabled_at"`
+}
+
+// Why a contract renewed
+const (
+	RenewalReasonDepleted = "depleted"
+	RenewalReasonExpired  = "expired"
+)
+
+// RenewalDue returns why the contract should renew now, or "" if it shouldn't: it isn't set
+// to renew, already was, or still has sessions to use before its expiry
+func (c *PTContract) RenewalDue(now time.Time) string {
+	if c.AutoRenew == nil || c.RenewedAt != nil {
+		return ""
+	}
+	switch c.Status {
+	case PackageStatusActive, PackageStatusDepleted, PackageStatusExpired:
+	default:
+		return ""
+	}
+	if c.ExpiresAt != nil && !c.ExpiresAt.After(now) {
+		return RenewalReasonExpired
+	}
+	if c.RemainingSessions <= 0 {
+		return RenewalReasonDepleted
+	}
+	return ""
+}
+
+// Where an upcoming renewal stands
+const (
+	RenewalStatusUpcoming       = "upcoming"        // Will renew when the sessions run out or expire
+	RenewalStatusPendingPayment = "pending_payment" // Renewed; the invoice isn't paid yet
+)
+
+// UpcomingRenewal is one of a coach's clients whose package is about to renew, or has and is
+// waiting for the member to pay
+type UpcomingRenewal struct {
+	ContractID        string     `json:"contract_id"`
+	MemberID          string     `json:"member_id"`
+	Name              string     `json:"name"`
+	Status            string     `json:"status"`
+	RemainingSessions int        `json:"remaining_sessions"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+	RenewalOf         string     `json:"renewal_of,omitempty"` // Contract a pending renewal continues
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPTContract_RenewalDue(t *testing.T) {
+	now := time.Date(2025, 6, 16, 10, 0, 0, 0, time.UTC)
+	yesterday, tomorrow := now.AddDate(0, 0, -1), now.AddDate(0, 0, 1)
+	contract := func(remaining int, change func(c *PTContract)) *PTContract {
+		c := &PTContract{Status: PackageStatusActive, RemainingSessions: remaining, AutoRenew: &AutoRenewal{PaymentMethod: "BCA"}}
+		if change != nil {
+			change(c)
+		}
+		return c
+	}
+
+	cases := []struct {
+		name     string
+		contract *PTContract
+		want     string
+	}{
+		{"sessions left", contract(3, nil), ""},
+		{"used up", contract(0, nil), RenewalReasonDepleted},
+		{"expired with sessions left", contract(3, func(c *PTContract) { c.ExpiresAt, c.Status = &yesterday, PackageStatusExpired }), RenewalReasonExpired},
+		{"expiring later", contract(3, func(c *PTContract) { c.ExpiresAt = &tomorrow }), ""},
+		{"already renewed", contract(0, func(c *PTContract) { c.RenewedAt = &yesterday }), ""},
+		{"suspended", contract(0, func(c *PTContract) { c.Status = PackageStatusSuspended }), ""},
+		{"not opted in", contract(0, func(c *PTContract) { c.AutoRenew = nil }), ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.contract.RenewalDue(now))
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"time"
+)
+
+var ErrInvalidReportSchedule = errors.New("invalid report schedule: choose a known report, a weekly or monthly frequency, and email recipients or an https webhook")
+
+// Reports that can be scheduled
+const (
+	ScheduledReportRevenue          = "revenue"           // Payments collected, one row per payment
+	ScheduledReportAttendance       = "attendance"        // Sessions per member by outcome
+	ScheduledReportCoachUtilization = "coach_utilization" // Booked against available hours per coach and branch
+)
+
+var ScheduledReportKinds = []string{ScheduledReportRevenue, ScheduledReportAttendance, ScheduledReportCoachUtilization}
+
+// Report frequencies. A weekly report covers the last Monday-to-Sunday week and a monthly
+// one the last calendar month, in UTC.
+const (
+	ReportWeekly  = "weekly"
+	ReportMonthly = "monthly"
+)
+
+// Report deliveries
+const (
+	ReportDeliveryEmail   = "email"   // A link to the file, to each recipient's email
+	ReportDeliveryWebhook = "webhook" // A signed JSON POST with the link
+)
+
+// ReportSchedule has a tenant report generated after every period and delivered
+type ReportSchedule struct {
+	ID         string     `json:"id" bson:"_id,omitempty"`
+	TenantID   string     `json:"tenant_id" bson:"tenant_id"`
+	Report     string     `json:"report" bson:"report"`
+	Frequency  string     `json:"frequency" bson:"frequency"`
+	Delivery   string     `json:"delivery" bson:"delivery"`
+	Recipients []string   `json:"recipients,omitempty" bson:"recipients,omitempty"` // User IDs, for email
+	WebhookURL string     `json:"webhook_url,omitempty" bson:"webhook_url,omitempty"`
+	Secret     string     `json:"-" bson:"secret,omitempty"` // Signs webhook bodies
+	Paused     bool       `json:"paused" bson:"paused"`
+	CreatedBy  string     `json:"created_by" bson:"created_by"`
+	NextRunAt  time.Time  `json:"next_run_at" bson:"next_run_at"`
+	LastRunAt  *time.Time `json:"last_run_at,omitempty" bson:"last_run_at,omitempty"`
+	LastError  string     `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" bson:"updated_at"`
+}
+
+// Validate checks the report, frequency and delivery. Email recipients are checked
+// against the tenant by the service.
+func (s *ReportSchedule) Validate() error {
+	if !slices.Contains(ScheduledReportKinds, s.Report) || (s.Frequency != ReportWeekly && s.Frequency != ReportMonthly) {
+		return ErrInvalidReportSchedule
+	}
+	switch s.Delivery {
+	case ReportDeliveryEmail:
+		if len(s.Recipients) == 0 {
+			return ErrInvalidReportSchedule
+		}
+	case ReportDeliveryWebhook:
+		u, err := url.Parse(s.WebhookURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return ErrInvalidReportSchedule
+		}
+	default:
+		return ErrInvalidReportSchedule
+	}
+	return nil
+}
+
+// PeriodBoundary returns the start of the period containing t
+func (s *ReportSchedule) PeriodBoundary(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if s.Frequency == ReportMonthly {
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)) // Back to Monday
+}
+
+// NextBoundary returns the start of the period after the one containing t, which is when
+// that period's report is due
+func (s *ReportSchedule) NextBoundary(t time.Time) time.Time {
+	start := s.PeriodBoundary(t)
+	if s.Frequency == ReportMonthly {
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 7)
+}
+
+// ReportScheduleRepository stores the tenants' report schedules
+type ReportScheduleRepository interface {
+	Create(ctx context.Context, schedule *ReportSchedule) error
+	// GetByID returns ErrNotFound when the schedule doesn't exist
+	GetByID(ctx context.Context, id string) (*ReportSchedule, error)
+	ListByTenant(ctx context.Context, tenantID string) ([]*ReportSchedule, error)
+	Update(ctx context.Context, schedule *ReportSchedule) error
+	Delete(ctx context.Context, id string) error
+	// ListDue returns the unpaused schedules of every tenant whose next run is at or before now
+	ListDue(ctx context.Context, now time.Time) ([]*ReportSchedule, error)
+	// Claim moves a due schedule from its run at due to next. It returns false if another
+	// runner claimed that run first.
+	Claim(ctx context.Context, id string, due, next time.Time) (bool, error)
+	// RecordRun notes when the schedule last ran and its error, empty on success
+	RecordRun(ctx context.Context, id string, ranAt time.Time, runErr string) error
+}
+
+// ReportFile is a generated report, stored and linked for delivery
+type ReportFile struct {
+	ScheduleID string    `json:"schedule_id"`
+	TenantID   string    `json:"tenant_id"`
+	Report     string    `json:"report"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"` // Exclusive
+	Rows       int       `json:"rows"`
+	URL        string    `json:"url"`
+	Expires    time.Time `json:"expires_at"`
+}
+
+// ReportWebhook posts a delivered report to a tenant's endpoint
+type ReportWebhook interface {
+	// Post sends body to url, signed with secret. Non-2xx answers are errors.
+	Post(ctx context.Context, url, secret string, body []byte) error
+}
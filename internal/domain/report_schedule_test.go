@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportSchedule_Validate(t *testing.T) {
+	valid := ReportSchedule{Report: ScheduledReportRevenue, Frequency: ReportWeekly, Delivery: ReportDeliveryEmail, Recipients: []string{"admin-1"}}
+	assert.NoError(t, valid.Validate())
+
+	for name, change := range map[string]func(s *ReportSchedule){
+		"unknown report":   func(s *ReportSchedule) { s.Report = "payroll" },
+		"daily":            func(s *ReportSchedule) { s.Frequency = "daily" },
+		"no recipients":    func(s *ReportSchedule) { s.Recipients = nil },
+		"plain http hook":  func(s *ReportSchedule) { s.Delivery, s.WebhookURL = ReportDeliveryWebhook, "http://hooks.example.com" },
+		"unknown delivery": func(s *ReportSchedule) { s.Delivery = "fax" },
+	} {
+		s := valid
+		change(&s)
+		assert.ErrorIs(t, s.Validate(), ErrInvalidReportSchedule, name)
+	}
+}
+
+func TestReportSchedule_Boundaries(t *testing.T) {
+	sunday := time.Date(2025, 6, 22, 23, 0, 0, 0, time.UTC)
+	weekly, monthly := &ReportSchedule{Frequency: ReportWeekly}, &ReportSchedule{Frequency: ReportMonthly}
+
+	assert.Equal(t, time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC), weekly.PeriodBoundary(sunday))
+	assert.Equal(t, time.Date(2025, 6, 23, 0, 0, 0, 0, time.UTC), weekly.NextBoundary(sunday))
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), monthly.PeriodBoundary(sunday))
+	assert.Equal(t, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), monthly.NextBoundary(sunday))
+}
@@ -2,24 +2,96 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+var ErrInvalidAISettings = errors.New("invalid AI settings")
+
 // Tenant represents a gym brand using the platform
 type Tenant struct {
-	ID         string     `bson:"_id,omitempty" json:"id"`
-	Name       string     `bson:"name" json:"name"`
-	JoinCode   string     `bson:"join_code" json:"join_code"` // Unique code for members to join
-	LogoURL    string     `bson:"logo_url" json:"logo_url"`
-	AISettings AISettings `bson:"ai_settings" json:"ai_settings"`
-	CreatedAt  time.Time  `bson:"created_at" json:"created_at"`
+	ID               string        `bson:"_id,omitempty" json:"id"`
+	Name             string        `bson:"name" json:"name"`
+	JoinCode         string        `bson:"join_code" json:"join_code"` // Unique code for members to join
+	LogoURL          string        `bson:"logo_url" json:"logo_url"`
+	AISettings       AISettings    `bson:"ai_settings" json:"ai_settings"`
+	EmailBranding    EmailBranding `bson:"email_branding,omitempty" json:"email_branding"`
+	ContractTemplate string        `bson:"contract_template,omitempty" json:"contract_template,omitempty"` // Agreement text for PT contracts; empty uses DefaultContractTemplate
+	WarehouseExport  string        `bson:"warehouse_export,omitempty" json:"warehouse_export,omitempty"`   // BI export opt-in: "", "anonymized" or "full"
+	Sandbox          bool          `bson:"sandbox,omitempty" json:"sandbox,omitempty"`                     // Test tenant for integration partners, see SandboxRepository
+	DeactivatedAt    *time.Time    `bson:"deactivated_at,omitempty" json:"deactivated_at,omitempty"`       // Being off-boarded; no one can sign in
+	MinorAge         int           `bson:"minor_age,omitempty" json:"minor_age,omitempty"`                 // Members younger are minors; 0 uses DefaultMinorAge
+	DeletedAt        *time.Time    `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`               // Soft-deleted; see TenantRepository.Delete
+	CreatedAt        time.Time     `bson:"created_at" json:"created_at"`
+
+	// Progress score weighting; nil uses DefaultProgressWeights
+	ProgressWeights *ProgressWeights `bson:"progress_weights,omitempty" json:"progress_weights,omitempty"`
+
+	// Which sets count as personal bests; nil uses DefaultPBRules
+	PBRules *PBRules `bson:"pb_rules,omitempty" json:"pb_rules,omitempty"`
+
+	// Fields the tenant tracks on its members and contracts
+	CustomFields CustomFieldSchema `bson:"custom_fields,omitempty" json:"custom_fields,omitempty"`
 }
 
-// AISettings defines the persona and style for the AI digitizer
+// AISettings defines the persona and style for the AI digitizer, and which optional AI
+// features the tenant has
 type AISettings struct {
-	Tone    string `bson:"tone" json:"tone"`       // e.g., "Encouraging", "Aggressive", "Tactical"
-	Style   string `bson:"style" json:"style"`     // e.g., "Concise", "Detailed"
-	Persona string `bson:"persona" json:"persona"` // e.g., "Drill Sergeant", "Supportive Coach"
+	Tone         string `bson:"tone" json:"tone"`                   // e.g., "Encouraging", "Aggressive", "Tactical"
+	Style        string `bson:"style" json:"style"`                 // e.g., "Concise", "Detailed"
+	Persona      string `bson:"persona" json:"persona"`             // e.g., "Drill Sergeant", "Supportive Coach"
+	FormFeedback bool   `bson:"form_feedback" json:"form_feedback"` // AI review of form-check videos
+	MonthlyQuota int    `bson:"monthly_quota" json:"monthly_quota"` // AI requests per month; 0 is unlimited
+
+	// Prompt overrides, added to the digitizer's prompts as plain text
+	Instructions string   `bson:"instructions,omitempty" json:"instructions,omitempty"`   // e.g., "Mention our Saturday bootcamp"
+	Language     string   `bson:"language,omitempty" json:"language,omitempty"`           // Language of the coaching text, e.g., "Bahasa Indonesia"
+	BannedTopics []string `bson:"banned_topics,omitempty" json:"banned_topics,omitempty"` // e.g., "supplements", "diet pills"
+}
+
+// Limits on the AI settings, keeping tenant text a small part of the prompt
+const (
+	MaxAISettingLength      = 100
+	MaxAIInstructionsLength = 1000
+	MaxAILanguageLength     = 40
+	MaxAIBannedTopics       = 20
+	MaxAIBannedTopicLength  = 60
+)
+
+// Validate checks the lengths of the free-text settings. Lengths count characters, not
+// bytes, so non-Latin scripts get the same room.
+func (s AISettings) Validate() error {
+	fields := []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{"tone", s.Tone, MaxAISettingLength},
+		{"style", s.Style, MaxAISettingLength},
+		{"persona", s.Persona, MaxAISettingLength},
+		{"instructions", s.Instructions, MaxAIInstructionsLength},
+		{"language", s.Language, MaxAILanguageLength},
+	}
+	for _, f := range fields {
+		if utf8.RuneCountInString(f.value) > f.max {
+			return fmt.Errorf("%w: %s is longer than %d characters", ErrInvalidAISettings, f.name, f.max)
+		}
+	}
+	if len(s.BannedTopics) > MaxAIBannedTopics {
+		return fmt.Errorf("%w: at most %d banned topics", ErrInvalidAISettings, MaxAIBannedTopics)
+	}
+	for _, topic := range s.BannedTopics {
+		if n := utf8.RuneCountInString(strings.TrimSpace(topic)); n == 0 || n > MaxAIBannedTopicLength {
+			return fmt.Errorf("%w: banned topics must be 1 to %d characters", ErrInvalidAISettings, MaxAIBannedTopicLength)
+		}
+	}
+	if s.MonthlyQuota < 0 {
+		return fmt.Errorf("%w: monthly_quota can't be negative", ErrInvalidAISettings)
+	}
+	return nil
 }
 
 // CoachAssignment represents a link between a coach and a member
@@ -38,16 +110,26 @@ type TenantRepository interface {
 	GetByJoinCode(ctx context.Context, code string) (*Tenant, error)
 	GetAll(ctx context.Context) ([]*Tenant, error)
 	Update(ctx context.Context, tenant *Tenant) error
+	// SetDeactivated marks the tenant deactivated at the time given, or active again for nil
+	SetDeactivated(ctx context.Context, id string, at *time.Time) error
+	// Delete soft-deletes the tenant at the time given: it is hidden from every query, so no
+	// one can sign in to it, until restored or purged with its data once past the retention period
+	Delete(ctx context.Context, id string, at time.Time) error
+	// Restore brings back a soft-deleted tenant, or ErrNotFound
+	Restore(ctx context.Context, id string, at time.Time) error
+	// ListDeleted returns the tenants soft-deleted before the time given, latest first
+	ListDeleted(ctx context.Context, before time.Time) ([]*Tenant, error)
 }
 
 // Branch represents a specific location within a tenant
 type Branch struct {
-	ID        string    `bson:"_id,omitempty" json:"id"`
-	TenantID  string    `bson:"tenant_id" json:"tenant_id"`
-	Name      string    `bson:"name" json:"name"`
-	JoinCode  string    `bson:"join_code" json:"join_code"` // Unique code for members to join branch
-	CreatedAt time.Time `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+	ID        string     `bson:"_id,omitempty" json:"id"`
+	TenantID  string     `bson:"tenant_id" json:"tenant_id"`
+	Name      string     `bson:"name" json:"name"`
+	JoinCode  string     `bson:"join_code" json:"join_code"` // Unique code for members to join branch
+	CreatedAt time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time  `bson:"updated_at" json:"updated_at"`
+	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"` // Soft-deleted; see BranchRepository.Delete
 }
 
 // BranchRepository defines operations for managing branches
@@ -57,8 +139,16 @@ type BranchRepository interface {
 	GetByJoinCode(ctx context.Context, code string) (*Branch, error)
 	GetByTenantID(ctx context.Context, tenantID string) ([]*Branch, error)
 	Update(ctx context.Context, branch *Branch) error
-	Delete(ctx context.Context, id string) error
+	// Delete soft-deletes the branch at the time given: it is hidden from every query until
+	// restored, or purged once past the retention period
+	Delete(ctx context.Context, id string, at time.Time) error
 	GetAll(ctx context.Context) ([]*Branch, error)
+	// Restore brings back a soft-deleted branch of tenantID ("" for any), or ErrNotFound
+	Restore(ctx context.Context, id, tenantID string, at time.Time) error
+	// ListDeleted returns the soft-deleted branches of tenantID ("" for all), latest first
+	ListDeleted(ctx context.Context, tenantID string) ([]*Branch, error)
+	// PurgeDeleted permanently removes branches soft-deleted before the time given
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 }
 
 // AssignmentRepository defines operations for managing coach-member links
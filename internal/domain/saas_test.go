@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAISettings_Validate(t *testing.T) {
+	valid := AISettings{
+		Tone:         "Encouraging",
+		Instructions: strings.Repeat("é", MaxAIInstructionsLength), // counted in characters
+		Language:     "Bahasa Indonesia",
+		BannedTopics: []string{"supplements"},
+	}
+	assert.NoError(t, valid.Validate())
+
+	tooLong := valid
+	tooLong.Persona = strings.Repeat("x", MaxAISettingLength+1)
+	assert.ErrorIs(t, tooLong.Validate(), ErrInvalidAISettings)
+
+	blankTopic := valid
+	blankTopic.BannedTopics = []string{"supplements", "  "}
+	assert.ErrorIs(t, blankTopic.Validate(), ErrInvalidAISettings)
+
+	tooMany := valid
+	tooMany.BannedTopics = make([]string, MaxAIBannedTopics+1)
+	for i := range tooMany.BannedTopics {
+		tooMany.BannedTopics[i] = "topic"
+	}
+	assert.ErrorIs(t, tooMany.Validate(), ErrInvalidAISettings)
+
+	negativeQuota := valid
+	negativeQuota.MonthlyQuota = -1
+	assert.ErrorIs(t, negativeQuota.Validate(), ErrInvalidAISettings)
+}
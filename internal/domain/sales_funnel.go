@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Sales funnel stages, in the order a member moves through them
+const (
+	SalesStagePageView         = "page_view"
+	SalesStageCheckoutStarted  = "checkout_started"
+	SalesStageInvoiceCreated   = "invoice_created"
+	SalesStagePaymentCompleted = "payment_completed"
+)
+
+// SalesStages lists the funnel stages from top to bottom
+var SalesStages = []string{
+	SalesStagePageView,
+	SalesStageCheckoutStarted,
+	SalesStageInvoiceCreated,
+	SalesStagePaymentCompleted,
+}
+
+// SalesEvent is one step a member took towards buying a package. Page views are reported
+// by the client; the other stages are recorded by the payment flow.
+type SalesEvent struct {
+	ID         string    `bson:"_id,omitempty" json:"id"`
+	TenantID   string    `bson:"tenant_id" json:"tenant_id"`
+	PackageID  string    `bson:"package_id" json:"package_id"`
+	UserID     string    `bson:"user_id" json:"user_id"`
+	Stage      string    `bson:"stage" json:"stage"`
+	InvoiceID  string    `bson:"invoice_id,omitempty" json:"invoice_id,omitempty"`
+	OccurredAt time.Time `bson:"occurred_at" json:"occurred_at"`
+}
+
+// SalesStageCount is how often a stage was reached for a package, and by how many members
+type SalesStageCount struct {
+	PackageID string `bson:"package_id" json:"package_id"`
+	Stage     string `bson:"stage" json:"stage"`
+	Events    int    `bson:"events" json:"events"`
+	Members   int    `bson:"members" json:"members"`
+}
+
+// SalesEventRepository stores sales funnel events
+type SalesEventRepository interface {
+	Record(ctx context.Context, event *SalesEvent) error
+	// CountByStage groups a tenant's events in [from, to) by package and stage
+	CountByStage(ctx context.Context, tenantID string, from, to time.Time) ([]SalesStageCount, error)
+}
+
+// FunnelStep is one stage of a package funnel. Conversion is the share of the previous
+// step's members that reached this one; the first step is always 1.
+type FunnelStep struct {
+	Stage      string  `json:"stage"`
+	Events     int     `json:"events"`
+	Members    int     `json:"members"`
+	Conversion float64 `json:"conversion"`
+}
+
+// PackageFunnel is the funnel of a single package. Overall is paying members over
+// members who viewed it.
+type PackageFunnel struct {
+	PackageID string       `json:"package_id"`
+	Name      string       `json:"name"`
+	Steps     []FunnelStep `json:"steps"`
+	Overall   float64      `json:"overall_conversion"`
+}
+
+// SalesFunnel is the per-package funnel of a tenant over a period
+type SalesFunnel struct {
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"`
+	Packages []PackageFunnel `json:"packages"`
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrNotSandbox = errors.New("tenant is not a sandbox")
+
+// CapturedNotification is a notification a sandbox tenant would have sent on a channel.
+// Sandbox notifications are stored for the integration partner to inspect instead of
+// reaching real phones and inboxes.
+type CapturedNotification struct {
+	ID           string `json:"id" bson:"_id"`
+	Channel      string `json:"channel" bson:"channel"`
+	Notification `bson:",inline"`
+	CreatedAt    time.Time `json:"created_at" bson:"created_at"`
+}
+
+// SandboxReset counts the records removed by resetting a sandbox tenant
+type SandboxReset struct {
+	Users         int64 `json:"users"`
+	Records       int64 `json:"records"` // Contracts, sessions, scans, invoices, ...
+	Notifications int64 `json:"notifications"`
+}
+
+// SandboxRepository stores what sandbox tenants send and wipes their data
+type SandboxRepository interface {
+	CaptureNotification(ctx context.Context, n *CapturedNotification) error
+	ListNotifications(ctx context.Context, tenantID string, q PageQuery) (*Page[*CapturedNotification], error) // Newest first
+
+	// Reset removes the tenant's members and coaches with everything they generated, and its
+	// captured notifications. The tenant, its branches, packages, equipment, documents,
+	// settings and tenant admins are kept so the partner can start over without re-provisioning.
+	Reset(ctx context.Context, tenantID string) (*SandboxReset, error)
+}
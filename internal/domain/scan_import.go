@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Outcome of one row of a scan import
+const (
+	ScanImportImported  = "imported"
+	ScanImportDuplicate = "duplicate" // A scan with the same test date and time already exists
+	ScanImportInvalid   = "invalid"
+)
+
+var ErrInvalidScanCSV = errors.New("not a LookinBody CSV export")
+
+// ScanImportRow reports what happened to one data row; Row counts lines from 1 so it
+// matches what a spreadsheet shows
+type ScanImportRow struct {
+	Row      int        `json:"row"`
+	Status   string     `json:"status"`
+	TestDate *time.Time `json:"test_date,omitempty"`
+	ScanID   string     `json:"scan_id,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// ScanImportReport summarises an import of a member's scan history
+type ScanImportReport struct {
+	Imported   int             `json:"imported"`
+	Duplicates int             `json:"duplicates"`
+	Invalid    int             `json:"invalid"`
+	Rows       []ScanImportRow `json:"rows"`
+}
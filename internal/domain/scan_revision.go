@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrScanImageUnavailable = errors.New("scan has no stored image to extract from")
+	ErrScanRevisionNotFound = errors.New("scan revision not found")
+)
+
+// Reasons a scan's values were replaced
+const (
+	ScanRevisionEdit      = "edit"
+	ScanRevisionReExtract = "re_extract"
+	ScanRevisionRevert    = "revert"
+)
+
+// ScanRevision keeps values a scan had before they were replaced, so earlier AI output
+// and coach corrections aren't lost
+type ScanRevision struct {
+	ID        string       `bson:"_id" json:"id"`
+	ScanID    string       `bson:"scan_id" json:"scan_id"`
+	UserID    string       `bson:"user_id" json:"user_id"`
+	Version   int64        `bson:"version" json:"version"` // Scan version the values belonged to
+	Reason    string       `bson:"reason" json:"reason"`
+	ChangedBy string       `bson:"changed_by" json:"changed_by"`
+	Values    InBodyRecord `bson:"values" json:"values"`
+	CreatedAt time.Time    `bson:"created_at" json:"created_at"`
+
+	// What the change did to Values, by JSON field name
+	Changes []ScanFieldChange `bson:"changes" json:"changes"`
+}
+
+// ScanFieldChange is one field a revision changed. From and To are only set for numeric
+// metrics; segmental data and the AI analysis are listed by name.
+type ScanFieldChange struct {
+	Field string   `bson:"field" json:"field"`
+	From  *float64 `bson:"from,omitempty" json:"from,omitempty"`
+	To    *float64 `bson:"to,omitempty" json:"to,omitempty"`
+}
+
+type ScanRevisionRepository interface {
+	Create(ctx context.Context, revision *ScanRevision) error
+	GetByID(ctx context.Context, id string) (*ScanRevision, error)
+	// ListByScanID returns a scan's revisions, newest first
+	ListByScanID(ctx context.Context, scanID string) ([]*ScanRevision, error)
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrInvalidScheduleBatch = errors.New("a batch needs between 1 and 200 slots")
+
+// MaxScheduleBatch bounds one bulk booking: a 12-week program at five sessions a week, with room to spare
+const MaxScheduleBatch = 200
+
+// Reasons a slot of a bulk booking was skipped
+const (
+	SkipReasonInvalidTime         = "invalid_time"         // Ends before it starts
+	SkipReasonConflict            = "conflict"             // Overlaps a session of the coach or the member, or another slot
+	SkipReasonOutsideAvailability = "outside_availability" // Outside the coach's working hours at the branch
+	SkipReasonNoCredits           = "no_credits"           // The contract has no credits left for it
+)
+
+// ScheduleSlot is one session of a bulk booking. SessionGoal and FocusArea override the
+// batch's when set, so a program can vary the focus from session to session.
+type ScheduleSlot struct {
+	ClientID    string    `json:"client_id,omitempty"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+	SessionGoal string    `json:"session_goal,omitempty"`
+	FocusArea   string    `json:"focus_area,omitempty"`
+}
+
+// SkippedSlot is a slot of a bulk booking that wasn't booked, and why
+type SkippedSlot struct {
+	ScheduleSlot
+	Reason string `json:"reason"`
+}
+
+// ScheduleBatchResult summarizes a bulk booking
+type ScheduleBatchResult struct {
+	Created []*Schedule   `json:"created"`
+	Skipped []SkippedSlot `json:"skipped"`
+}
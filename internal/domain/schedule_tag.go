@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	MaxScheduleTags        = 10
+	maxScheduleTagLength   = 32
+	maxScheduleLabelLength = 40
+)
+
+var (
+	ErrInvalidScheduleTags  = errors.New("tags must be at most 10 words of up to 32 letters, digits, '-' or '_'")
+	ErrInvalidScheduleLabel = errors.New("label must be at most 40 characters and color a hex code like #3B82F6")
+)
+
+var scheduleColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// NormalizeScheduleTags lowercases and de-duplicates tags so "Trial" and "trial " are one
+// tag in filters and reports
+func NormalizeScheduleTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || slices.Contains(normalized, tag) {
+			continue
+		}
+		if len(tag) > maxScheduleTagLength || strings.IndexFunc(tag, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_'
+		}) >= 0 {
+			return nil, ErrInvalidScheduleTags
+		}
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > MaxScheduleTags {
+		return nil, ErrInvalidScheduleTags
+	}
+	return normalized, nil
+}
+
+// ValidateScheduleLabel checks a calendar label and color; both are optional
+func ValidateScheduleLabel(label, color string) error {
+	if len([]rune(label)) > maxScheduleLabelLength || (color != "" && !scheduleColorPattern.MatchString(color)) {
+		return ErrInvalidScheduleLabel
+	}
+	return nil
+}
+
+// HasTag reports whether the schedule carries tag, ignoring case
+func (s *Schedule) HasTag(tag string) bool {
+	return slices.Contains(s.Tags, strings.ToLower(strings.TrimSpace(tag)))
+}
+
+// ScheduleTagCount summarises the sessions carrying one tag by outcome
+type ScheduleTagCount struct {
+	Tag       string `json:"tag" bson:"_id"`
+	Total     int    `json:"total" bson:"total"`
+	Scheduled int    `json:"scheduled" bson:"scheduled"`
+	Completed int    `json:"completed" bson:"completed"`
+	Cancelled int    `json:"cancelled" bson:"cancelled"`
+	NoShow    int    `json:"no_show" bson:"no_show"`
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeScheduleTags(t *testing.T) {
+	tags, err := NormalizeScheduleTags([]string{" Trial", "assessment", "trial", "", "body_comp"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"trial", "assessment", "body_comp"}, tags)
+
+	_, err = NormalizeScheduleTags([]string{"first session"})
+	assert.ErrorIs(t, err, ErrInvalidScheduleTags, "spaces")
+	_, err = NormalizeScheduleTags([]string{strings.Repeat("x", 33)})
+	assert.ErrorIs(t, err, ErrInvalidScheduleTags, "too long")
+	_, err = NormalizeScheduleTags(strings.Split("a,b,c,d,e,f,g,h,i,j,k", ","))
+	assert.ErrorIs(t, err, ErrInvalidScheduleTags, "too many")
+}
+
+func TestValidateScheduleLabel(t *testing.T) {
+	assert.NoError(t, ValidateScheduleLabel("", ""))
+	assert.NoError(t, ValidateScheduleLabel("Trial w/ Andi", "#3b82F6"))
+
+	assert.ErrorIs(t, ValidateScheduleLabel("", "blue"), ErrInvalidScheduleLabel)
+	assert.ErrorIs(t, ValidateScheduleLabel("", "#FFF"), ErrInvalidScheduleLabel)
+	assert.ErrorIs(t, ValidateScheduleLabel(strings.Repeat("x", 41), ""), ErrInvalidScheduleLabel)
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var ErrInvalidSearchQuery = errors.New("search text must be 2 to 100 characters")
+
+// Kinds of search results
+const (
+	SearchKindClient   = "client"
+	SearchKindSchedule = "schedule"
+	SearchKindExercise = "exercise"
+	SearchKindScan     = "scan"
+)
+
+// Search limits, per kind of result
+const (
+	DefaultSearchLimit = 5
+	MaxSearchLimit     = 20
+)
+
+// SearchQuery is a coach's search: the text and what it may find
+type SearchQuery struct {
+	Text      string
+	TenantID  string
+	CoachID   string   // Their sessions
+	MemberIDs []string // Their clients, and the clients' scans
+	Limit     int      // Per kind
+}
+
+// Normalized trims the text and bounds the limit. The text must be 2 to 100 characters.
+func (q SearchQuery) Normalized() (SearchQuery, error) {
+	q.Text = strings.TrimSpace(q.Text)
+	if n := utf8.RuneCountInString(q.Text); n < 2 || n > 100 {
+		return q, ErrInvalidSearchQuery
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultSearchLimit
+	}
+	if q.Limit > MaxSearchLimit {
+		q.Limit = MaxSearchLimit
+	}
+	return q, nil
+}
+
+// Scored is a record matching a text search, with its relevance
+type Scored[T any] struct {
+	Item  T
+	Score float64
+}
+
+// SearchResult is one hit of a search, whatever its kind, with what the app shows of it
+type SearchResult struct {
+	Kind     string     `json:"kind"` // client, schedule, exercise or scan
+	ID       string     `json:"id"`
+	Title    string     `json:"title"`
+	Subtitle string     `json:"subtitle,omitempty"`
+	MemberID string     `json:"member_id,omitempty"` // Whose session or scan it is
+	Date     *time.Time `json:"date,omitempty"`
+	Score    float64    `json:"score"`
+}
+
+// SearchResults are a search's hits, most relevant first
+type SearchResults struct {
+	Query   string          `json:"query"`
+	Results []*SearchResult `json:"results"`
+}
+
+// SearchRepository finds records by text, most relevant first, at most limit of each kind
+type SearchRepository interface {
+	// SearchMembers matches the members' names and emails
+	SearchMembers(ctx context.Context, memberIDs []string, text string, limit int) ([]Scored[*User], error)
+	// SearchSchedules matches the coach's live sessions in the tenant by goal, notes, tags and label
+	SearchSchedules(ctx context.Context, tenantID, coachID, text string, limit int) ([]Scored[*Schedule], error)
+	// SearchExercises matches the global and the tenant's exercise libraries by name, muscle
+	// group and equipment
+	SearchExercises(ctx context.Context, tenantID, text string, limit int) ([]Scored[*Exercise], error)
+	// SearchScans matches the members' scans by their analysis
+	SearchScans(ctx context.Context, memberIDs []string, text string, limit int) ([]Scored[*InBodyRecord], error)
+}
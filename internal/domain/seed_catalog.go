@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var ErrInvalidSeedCatalog = errors.New("invalid seed catalog")
+
+// Template genders accepted in catalogs; empty means "All"
+var templateGenders = map[string]bool{"": true, "All": true, "Male": true, "Female": true}
+
+// SeedCatalog is reference data read by `metamorph seed` from a YAML or JSON file. Exercises
+// and templates are global; packages and equipment belong to the tenant the catalog is
+// applied to, which is how a tenant brings its own offering when onboarding.
+// Every entry is upserted by name, so a catalog can be re-applied after editing it.
+type SeedCatalog struct {
+	Exercises []CatalogExercise  `json:"exercises,omitempty" yaml:"exercises,omitempty"`
+	Templates []CatalogTemplate  `json:"templates,omitempty" yaml:"templates,omitempty"`
+	Packages  []CatalogPackage   `json:"packages,omitempty" yaml:"packages,omitempty"`
+	Equipment []CatalogEquipment `json:"equipment,omitempty" yaml:"equipment,omitempty"`
+}
+
+type CatalogExercise struct {
+	Name         string `json:"name" yaml:"name"`
+	MuscleGroup  string `json:"muscle_group" yaml:"muscle_group"`
+	Equipment    string `json:"equipment" yaml:"equipment"`
+	VideoURL     string `json:"video_url,omitempty" yaml:"video_url,omitempty"`
+	ReferenceURL string `json:"reference_url,omitempty" yaml:"reference_url,omitempty"`
+}
+
+// CatalogTemplate lists its exercises by name; they may come from the same catalog or
+// already be in the library
+type CatalogTemplate struct {
+	Name      string   `json:"name" yaml:"name"`
+	Gender    string   `json:"gender,omitempty" yaml:"gender,omitempty"` // "Male", "Female" or "All" (default)
+	Exercises []string `json:"exercises" yaml:"exercises"`
+}
+
+// CatalogPackage is a PT package of the tenant. Branch is a branch name and may be left
+// out when the tenant has a single branch.
+type CatalogPackage struct {
+	Branch        string  `json:"branch,omitempty" yaml:"branch,omitempty"`
+	Name          string  `json:"name" yaml:"name"`
+	TotalSessions int     `json:"total_sessions" yaml:"total_sessions"`
+	Price         float64 `json:"price" yaml:"price"`                       // In major units of DefaultCurrency
+	Active        *bool   `json:"active,omitempty" yaml:"active,omitempty"` // Defaults to true
+}
+
+// CatalogEquipment is kit a tenant branch owns, see Equipment
+type CatalogEquipment struct {
+	Branch    string `json:"branch,omitempty" yaml:"branch,omitempty"`
+	Name      string `json:"name" yaml:"name"`
+	Quantity  int    `json:"quantity" yaml:"quantity"`
+	Available *bool  `json:"available,omitempty" yaml:"available,omitempty"` // Defaults to true
+}
+
+// SeedCatalogCounts is what happened to one section of a catalog
+type SeedCatalogCounts struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+}
+
+// SeedCatalogResult summarizes applying a catalog. Issues are entries that were skipped,
+// such as a template naming an exercise that exists nowhere.
+type SeedCatalogResult struct {
+	Exercises SeedCatalogCounts `json:"exercises"`
+	Templates SeedCatalogCounts `json:"templates"`
+	Packages  SeedCatalogCounts `json:"packages"`
+	Equipment SeedCatalogCounts `json:"equipment"`
+	Issues    []string          `json:"issues,omitempty"`
+}
+
+// Validate checks the catalog without touching the database and returns every problem,
+// so a file can be fixed in one pass. Packages and equipment need a tenant.
+func (c *SeedCatalog) Validate(tenantScoped bool) []string {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	seen := map[string]bool{}
+	for i, ex := range c.Exercises {
+		at := fmt.Sprintf("exercises[%d]", i)
+		name := strings.TrimSpace(ex.Name)
+		if name == "" {
+			add("%s: name is required", at)
+		} else if key := strings.ToLower(name); seen[key] {
+			add("%s: duplicate exercise %q", at, name)
+		} else {
+			seen[key] = true
+		}
+		if strings.TrimSpace(ex.MuscleGroup) == "" {
+			add("%s: muscle_group is required", at)
+		}
+		if ex.VideoURL != "" && !isHTTPURL(ex.VideoURL) {
+			add("%s: video_url must be an http(s) URL", at)
+		}
+		if ex.ReferenceURL != "" && !isHTTPURL(ex.ReferenceURL) {
+			add("%s: reference_url must be an http(s) URL", at)
+		}
+	}
+
+	seen = map[string]bool{}
+	for i, tpl := range c.Templates {
+		at := fmt.Sprintf("templates[%d]", i)
+		name := strings.TrimSpace(tpl.Name)
+		if name == "" {
+			add("%s: name is required", at)
+		} else if key := strings.ToLower(name); seen[key] {
+			add("%s: duplicate template %q", at, name)
+		} else {
+			seen[key] = true
+		}
+		if !templateGenders[tpl.Gender] {
+			add("%s: gender must be Male, Female or All", at)
+		}
+		if len(tpl.Exercises) == 0 {
+			add("%s: exercises must not be empty", at)
+		}
+		for j, ex := range tpl.Exercises {
+			if strings.TrimSpace(ex) == "" {
+				add("%s.exercises[%d]: exercise name is required", at, j)
+			}
+		}
+	}
+
+	if !tenantScoped && (len(c.Packages) > 0 || len(c.Equipment) > 0) {
+		add("packages and equipment belong to a tenant; apply the catalog with a tenant")
+	}
+
+	seen = map[string]bool{}
+	for i, pkg := range c.Packages {
+		at := fmt.Sprintf("packages[%d]", i)
+		name := strings.TrimSpace(pkg.Name)
+		if name == "" {
+			add("%s: name is required", at)
+		} else if key := strings.ToLower(strings.TrimSpace(pkg.Branch) + "/" + name); seen[key] {
+			add("%s: duplicate package %q", at, name)
+		} else {
+			seen[key] = true
+		}
+		if !ValidSessionAmount(pkg.TotalSessions) {
+			add("%s: %v", at, ErrInvalidSessionAmount)
+		}
+		if pkg.Price < 0 {
+			add("%s: price must not be negative", at)
+		}
+	}
+
+	seen = map[string]bool{}
+	for i, item := range c.Equipment {
+		at := fmt.Sprintf("equipment[%d]", i)
+		name := strings.TrimSpace(item.Name)
+		if name == "" {
+			add("%s: name is required", at)
+		} else if key := strings.ToLower(strings.TrimSpace(item.Branch) + "/" + equipmentKey(name)); seen[key] {
+			add("%s: duplicate equipment %q", at, name)
+		} else {
+			seen[key] = true
+		}
+		if item.Quantity < 0 {
+			add("%s: quantity must not be negative", at)
+		}
+	}
+	return problems
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
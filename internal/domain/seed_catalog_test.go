@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeedCatalog_Validate(t *testing.T) {
+	inactive := false
+	valid := &SeedCatalog{
+		Exercises: []CatalogExercise{{Name: "Goblet Squat", MuscleGroup: "Legs", Equipment: "Dumbbell", VideoURL: "https://example.com/v"}},
+		Templates: []CatalogTemplate{{Name: "Legs", Exercises: []string{"Goblet Squat"}}},
+		Packages:  []CatalogPackage{{Name: "10 Pack", TotalSessions: 10, Price: 100, Active: &inactive}},
+		Equipment: []CatalogEquipment{{Branch: "North", Name: "Dumbbell", Quantity: 10}},
+	}
+	assert.Empty(t, valid.Validate(true))
+	assert.Equal(t, []string{"packages and equipment belong to a tenant; apply the catalog with a tenant"}, valid.Validate(false))
+
+	invalid := &SeedCatalog{
+		Exercises: []CatalogExercise{
+			{Name: "Push Up", MuscleGroup: "Chest", VideoURL: "youtube"},
+			{Name: "push up ", MuscleGroup: ""},
+		},
+		Templates: []CatalogTemplate{{Name: "", Gender: "Any"}},
+		Packages:  []CatalogPackage{{Name: "Pack", TotalSessions: 15, Price: -1}},
+		Equipment: []CatalogEquipment{{Name: "Barbell", Quantity: 1}, {Name: "barbell", Quantity: -2}},
+	}
+	assert.Equal(t, []string{
+		"exercises[0]: video_url must be an http(s) URL",
+		`exercises[1]: duplicate exercise "push up"`,
+		"exercises[1]: muscle_group is required",
+		"templates[0]: name is required",
+		"templates[0]: gender must be Male, Female or All",
+		"templates[0]: exercises must not be empty",
+		"packages[0]: invalid session amount (must be 10, 20, 30, 40, or 50)",
+		"packages[0]: price must not be negative",
+		`equipment[1]: duplicate equipment "barbell"`,
+		"equipment[1]: quantity must not be negative",
+	}, invalid.Validate(true))
+}
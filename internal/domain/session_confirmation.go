@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// A member's answer to a session reminder
+const (
+	ConfirmationConfirmed = "confirmed"
+	ConfirmationDeclined  = "declined"
+
+	ConfirmationUnconfirmed = "unconfirmed" // Upcoming session the member hasn't answered for
+)
+
+// DefaultConfirmationAlertMinutes is how long before an unconfirmed session its coach is
+// alerted, for tenants that haven't set it
+const DefaultConfirmationAlertMinutes = 3 * 60
+
+var (
+	ErrInvalidConfirmation      = errors.New("status must be confirmed or declined")
+	ErrSessionNotConfirmable    = errors.New("only upcoming sessions can be confirmed")
+	ErrInvalidConfirmationAlert = errors.New("confirmation_alert_minutes must be between 0 (off) and 7 days")
+)
+
+// SessionConfirmation is the member's answer to whether they'll attend a session. Schedules
+// without one are unconfirmed; rescheduling clears it.
+type SessionConfirmation struct {
+	Status      string    `json:"status" bson:"status"`                 // confirmed or declined
+	Note        string    `json:"note,omitempty" bson:"note,omitempty"` // e.g. why the member can't make it
+	RespondedAt time.Time `json:"responded_at" bson:"responded_at"`
+}
+
+// ConfirmationStatus is what a coach's agenda shows for the session: the member's answer,
+// unconfirmed while an upcoming session has none, and empty once the session is over
+func (s *Schedule) ConfirmationStatus() string {
+	if s.Confirmation != nil {
+		return s.Confirmation.Status
+	}
+	if s.Status == ScheduleStatusScheduled || s.Status == ScheduleStatusPendingConfirmation {
+		return ConfirmationUnconfirmed
+	}
+	return ""
+}
+
+// Validate checks that the member either confirmed or declined
+func (c *SessionConfirmation) Validate() error {
+	if c.Status != ConfirmationConfirmed && c.Status != ConfirmationDeclined {
+		return ErrInvalidConfirmation
+	}
+	return nil
+}
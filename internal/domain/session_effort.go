@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Where a session's effort data came from
+const (
+	EffortSourceManual   = "manual"
+	EffortSourceWearable = "wearable"
+)
+
+var (
+	ErrInvalidSessionEffort = errors.New("effort needs rpe 1-10 or heart rates of 30-230 bpm, with avg_hr not above max_hr")
+	ErrSessionNotCompleted  = errors.New("effort can only be recorded for a completed session")
+)
+
+// SessionEffort is how hard a completed session was: heart rate from a watch or chest
+// strap, and the member's rating of perceived exertion (RPE, 1-10)
+type SessionEffort struct {
+	AvgHR      *int      `json:"avg_hr,omitempty" bson:"avg_hr,omitempty"`
+	MaxHR      *int      `json:"max_hr,omitempty" bson:"max_hr,omitempty"`
+	RPE        *int      `json:"rpe,omitempty" bson:"rpe,omitempty"`
+	Source     string    `json:"source" bson:"source"`                     // manual or wearable
+	Device     string    `json:"device,omitempty" bson:"device,omitempty"` // e.g. "Garmin Forerunner 255"
+	RecordedAt time.Time `json:"recorded_at" bson:"recorded_at"`
+}
+
+// Validate checks that something was measured and every value is plausible
+func (e *SessionEffort) Validate() error {
+	if e.AvgHR == nil && e.MaxHR == nil && e.RPE == nil {
+		return ErrInvalidSessionEffort
+	}
+	for _, hr := range []*int{e.AvgHR, e.MaxHR} {
+		if hr != nil && (*hr < 30 || *hr > 230) {
+			return ErrInvalidSessionEffort
+		}
+	}
+	if e.AvgHR != nil && e.MaxHR != nil && *e.AvgHR > *e.MaxHR {
+		return ErrInvalidSessionEffort
+	}
+	if e.RPE != nil && (*e.RPE < 1 || *e.RPE > 10) {
+		return ErrInvalidSessionEffort
+	}
+	if e.Source != EffortSourceManual && e.Source != EffortSourceWearable {
+		return ErrInvalidSessionEffort
+	}
+	return nil
+}
+
+// Merge overlays the values measured in update, so a wearable's heart rate and the coach's
+// RPE can arrive separately without overwriting each other
+func (e *SessionEffort) Merge(update SessionEffort) SessionEffort {
+	merged := update
+	if e == nil {
+		return merged
+	}
+	if merged.AvgHR == nil {
+		merged.AvgHR = e.AvgHR
+	}
+	if merged.MaxHR == nil {
+		merged.MaxHR = e.MaxHR
+	}
+	if merged.RPE == nil {
+		merged.RPE = e.RPE
+	}
+	if merged.Device == "" {
+		merged.Device = e.Device
+	}
+	return merged
+}
+
+// Load is the session's training load by the session-RPE method: RPE times minutes.
+// Sessions without an RPE have no load.
+func (s *Schedule) Load() float64 {
+	if s.Effort == nil || s.Effort.RPE == nil {
+		return 0
+	}
+	return float64(*s.Effort.RPE) * s.EndTime.Sub(s.StartTime).Minutes()
+}
+
+// WeeklyLoad sums a member's completed sessions in one week (Monday 00:00 UTC)
+type WeeklyLoad struct {
+	WeekStart  time.Time `json:"week_start"`
+	Sessions   int       `json:"sessions"`
+	WithEffort int       `json:"with_effort"` // Sessions that have any effort data
+	Load       float64   `json:"load"`
+	AvgRPE     float64   `json:"avg_rpe,omitempty"`
+	AvgHR      float64   `json:"avg_hr,omitempty"`
+	MaxHR      int       `json:"max_hr,omitempty"`
+}
+
+// TrainingLoad is a member's weekly load and how the last week compares to the last four.
+// ACWR, the acute:chronic workload ratio, is the last 7 days' load over the weekly average
+// of the last 28; around 0.8-1.3 is usually considered safe.
+type TrainingLoad struct {
+	MemberID    string       `json:"member_id"`
+	Weeks       []WeeklyLoad `json:"weeks"` // Oldest first
+	AcuteLoad   float64      `json:"acute_load"`
+	ChronicLoad float64      `json:"chronic_load"` // Weekly average
+	ACWR        float64      `json:"acwr,omitempty"`
+	Flag        string       `json:"flag,omitempty"` // "spike" or "detraining"
+}
+
+// Training load flags
+const (
+	LoadFlagSpike      = "spike"
+	LoadFlagDetraining = "detraining"
+)
+
+// WeeklyReviewMember is one member's line in the coach's weekly review
+type WeeklyReviewMember struct {
+	MemberID   string     `json:"member_id"`
+	MemberName string     `json:"member_name"`
+	Week       WeeklyLoad `json:"week"`
+	ACWR       float64    `json:"acwr,omitempty"`
+	Flag       string     `json:"flag,omitempty"`
+}
+
+// CoachWeeklyReview summarises the effort of a coach's members over one week
+type CoachWeeklyReview struct {
+	WeekStart     time.Time            `json:"week_start"`
+	Sessions      int                  `json:"sessions"`
+	MissingEffort int                  `json:"missing_effort"` // Completed sessions nobody recorded effort for
+	Members       []WeeklyReviewMember `json:"members"`
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net/url"
+)
+
+// Session modalities. Schedules created before modalities existed have none and are
+// in person.
+const (
+	SessionModalityInPerson = "in_person"
+	SessionModalityOnline   = "online"
+)
+
+var (
+	ErrInvalidModality   = errors.New("modality must be in_person or online")
+	ErrInvalidMeetingURL = errors.New("meeting_url must be an https link and is only allowed on online sessions")
+)
+
+// MeetingLinkGenerator creates a video meeting for an online session and returns its join
+// link. Implementations wrap a provider such as Zoom.
+type MeetingLinkGenerator interface {
+	CreateMeeting(ctx context.Context, schedule *Schedule) (string, error)
+}
+
+// IsOnline reports whether the session takes place over video
+func (s *Schedule) IsOnline() bool {
+	return s.Modality == SessionModalityOnline
+}
+
+// NormalizeModality defaults an empty modality to in person and checks that only online
+// sessions carry a meeting link
+func (s *Schedule) NormalizeModality() error {
+	switch s.Modality {
+	case "":
+		s.Modality = SessionModalityInPerson
+	case SessionModalityInPerson, SessionModalityOnline:
+	default:
+		return ErrInvalidModality
+	}
+	if s.MeetingURL == "" {
+		return nil
+	}
+	u, err := url.Parse(s.MeetingURL)
+	if !s.IsOnline() || err != nil || u.Scheme != "https" || u.Host == "" {
+		return ErrInvalidMeetingURL
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrSessionPhotoNotFound     = errors.New("photo not found")
+	ErrPhotoTooLarge            = errors.New("photo file is too large")
+	ErrUnsupportedPhoto         = errors.New("unsupported photo: upload a JPEG or PNG image")
+	ErrPhotoSessionNotCompleted = errors.New("photos can only be attached to a completed session")
+	ErrPhotoConsentRequired     = errors.New("member has not agreed to session photos")
+)
+
+// SessionPhoto is a picture a coach took during a session, shown to the member with the
+// workout
+type SessionPhoto struct {
+	ID          string    `json:"id" bson:"_id"`
+	TenantID    string    `json:"tenant_id" bson:"tenant_id"`
+	ScheduleID  string    `json:"schedule_id" bson:"schedule_id"`
+	MemberID    string    `json:"member_id" bson:"member_id"`
+	UploadedBy  string    `json:"uploaded_by" bson:"uploaded_by"`
+	URL         string    `json:"url" bson:"url"`
+	ContentType string    `json:"content_type" bson:"content_type"`
+	SizeBytes   int64     `json:"size_bytes" bson:"size_bytes"`
+	Caption     string    `json:"caption,omitempty" bson:"caption,omitempty"`
+	TakenAt     time.Time `json:"taken_at" bson:"taken_at"` // Start of the session
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+}
+
+// PhotoConsent is what a member allows their gym's coaches to do with session photos.
+// Members who never answered have allowed neither.
+type PhotoConsent struct {
+	TenantID  string    `json:"tenant_id" bson:"tenant_id"`
+	MemberID  string    `json:"member_id" bson:"member_id"`
+	Capture   bool      `json:"capture" bson:"capture"` // Coaches may attach photos to the member's sessions
+	Reports   bool      `json:"reports" bson:"reports"` // Photos are included in progress reports
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+type SessionPhotoRepository interface {
+	Create(ctx context.Context, photo *SessionPhoto) error
+	GetByID(ctx context.Context, id string) (*SessionPhoto, error)
+	ListBySchedule(ctx context.Context, scheduleID string) ([]*SessionPhoto, error)
+	// ListByMember returns the member's photos at the tenant taken in [from, to), oldest first
+	ListByMember(ctx context.Context, tenantID, memberID string, from, to time.Time) ([]*SessionPhoto, error)
+	Delete(ctx context.Context, id string) error
+
+	// GetConsent returns the member's consent at the tenant; nothing allowed if never saved
+	GetConsent(ctx context.Context, tenantID, memberID string) (*PhotoConsent, error)
+	SaveConsent(ctx context.Context, consent *PhotoConsent) error
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrSessionPlanNotFound   = errors.New("this session hasn't been planned yet")
+	ErrSessionAlreadyHeld    = errors.New("only upcoming sessions can be planned")
+	ErrSessionAlreadyPlanned = errors.New("this session already has exercises; edit them instead of applying a template")
+)
+
+// SessionPlan is what a member sees of an upcoming session before it starts: the
+// exercises the coach lined up and their brief, without any logging detail
+type SessionPlan struct {
+	ScheduleID  string          `json:"schedule_id"`
+	CoachID     string          `json:"coach_id"`
+	StartTime   time.Time       `json:"start_time"`
+	SessionGoal string          `json:"session_goal,omitempty"`
+	FocusArea   string          `json:"focus_area,omitempty"`
+	Notes       string          `json:"notes,omitempty"`
+	Exercises   []*PlanExercise `json:"exercises"`
+	SharedAt    *time.Time      `json:"shared_at,omitempty"`
+}
+
+// PlanExercise is one planned exercise with its targets
+type PlanExercise struct {
+	ExerciseID  string `json:"exercise_id"`
+	Name        string `json:"name"`
+	TargetSets  int    `json:"target_sets"`
+	TargetReps  int    `json:"target_reps"`
+	RestSeconds int    `json:"rest_seconds"`
+	Notes       string `json:"notes,omitempty"`
+}
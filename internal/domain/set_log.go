@@ -46,4 +46,20 @@ type SetLogRepository interface {
 	DeleteByPlannedExerciseID(ctx context.Context, plannedExerciseID string) error
 	// DeleteByScheduleID removes all set logs for a schedule (cascade)
 	DeleteByScheduleID(ctx context.Context, scheduleID string) error
+	// GetLastPerformance returns the member's sets of an exercise from the most recent
+	// schedule other than excludeScheduleID in which they completed it, ordered by set
+	// index; nil when there is none
+	GetLastPerformance(ctx context.Context, memberID, exerciseID, excludeScheduleID string) ([]*SetLogDocument, error)
+	// ListChangedSince returns the schedules' set logs that were updated after since,
+	// soft-deleted ones included
+	ListChangedSince(ctx context.Context, scheduleIDs []string, since time.Time) ([]*SetLogDocument, error)
+}
+
+// LastPerformance is how a member did an exercise the previous time, for pre-filling
+// weights and reps while logging
+type LastPerformance struct {
+	ExerciseID  string            `json:"exercise_id"`
+	ScheduleID  string            `json:"schedule_id,omitempty"`
+	PerformedAt *time.Time        `json:"performed_at,omitempty"`
+	Sets        []*SetLogDocument `json:"sets"`
 }
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrSetVideoNotFound = errors.New("video not found")
+	ErrVideoTooLarge    = errors.New("video file is too large")
+	ErrVideoTooLong     = errors.New("video is too long")
+	ErrUnsupportedVideo = errors.New("unsupported video: upload an MP4 or MOV file")
+	ErrInvalidComment   = errors.New("comment needs text and a time within the video")
+)
+
+// SetVideo is a short form-check clip attached to one logged set
+type SetVideo struct {
+	ID              string         `json:"id" bson:"_id"`
+	TenantID        string         `json:"tenant_id" bson:"tenant_id"`
+	ScheduleID      string         `json:"schedule_id" bson:"schedule_id"`
+	SetLogID        string         `json:"set_log_id" bson:"set_log_id"`
+	MemberID        string         `json:"member_id" bson:"member_id"`
+	UploadedBy      string         `json:"uploaded_by" bson:"uploaded_by"`
+	URL             string         `json:"url" bson:"url"`
+	ContentType     string         `json:"content_type" bson:"content_type"`
+	SizeBytes       int64          `json:"size_bytes" bson:"size_bytes"`
+	DurationSeconds float64        `json:"duration_seconds" bson:"duration_seconds"`
+	Comments        []VideoComment `json:"comments" bson:"comments"`
+	FormFeedback    *FormFeedback  `json:"form_feedback,omitempty" bson:"form_feedback,omitempty"`
+	CreatedAt       time.Time      `json:"created_at" bson:"created_at"`
+}
+
+// VideoComment is a coach's note on a video, optionally pinned to a moment in it
+type VideoComment struct {
+	ID        string    `json:"id" bson:"id"`
+	AuthorID  string    `json:"author_id" bson:"author_id"`
+	Text      string    `json:"text" bson:"text"`
+	AtSeconds *float64  `json:"at_seconds,omitempty" bson:"at_seconds,omitempty"` // e.g. 3.5 for "here, at the bottom of the squat"
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+type SetVideoRepository interface {
+	Create(ctx context.Context, video *SetVideo) error
+	GetByID(ctx context.Context, id string) (*SetVideo, error)
+	ListBySchedule(ctx context.Context, scheduleID string) ([]*SetVideo, error)
+	AddComment(ctx context.Context, videoID string, comment *VideoComment) error
+	Delete(ctx context.Context, id string) error
+	SetFormFeedback(ctx context.Context, videoID string, feedback *FormFeedback) error
+	// ListPendingFeedback returns the oldest videos still waiting for AI form feedback
+	ListPendingFeedback(ctx context.Context, limit int) ([]*SetVideo, error)
+}
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Settlement line statuses. A line is either a row of the provider's settlement file or,
+// for not_settled, a provider payment we recorded that the file doesn't contain.
+const (
+	SettlementMatched        = "matched"
+	SettlementAmountMismatch = "amount_mismatch" // Settled amount differs from what we recorded
+	SettlementMissingWebhook = "missing_webhook" // Settled, but the webhook never recorded the payment
+	SettlementUnknown        = "unknown"         // No invoice of the tenant for the session
+	SettlementDuplicate      = "duplicate"       // Same transaction listed again in the file
+	SettlementNotSettled     = "not_settled"     // Recorded in the period but missing from the file
+)
+
+// MaxSettlementRows bounds the rows of an uploaded settlement file
+const MaxSettlementRows = 5000
+
+var ErrInvalidSettlementFile = errors.New("invalid settlement file")
+
+// SettlementRow is one transaction of a provider settlement file
+type SettlementRow struct {
+	Line      int
+	SessionID string
+	TrxID     int64 // 0 if the file doesn't list transactions
+	Amount    int64
+	Fee       int64
+	SettledAt *time.Time
+}
+
+// SettlementItem is the outcome of matching one settled transaction or recorded payment
+type SettlementItem struct {
+	Status         string     `json:"status" bson:"status"`
+	Line           int        `json:"line,omitempty" bson:"line,omitempty"` // Row of the file, 0 for not_settled
+	SessionID      string     `json:"session_id" bson:"session_id"`
+	TrxID          int64      `json:"trx_id,omitempty" bson:"trx_id,omitempty"`
+	InvoiceID      string     `json:"invoice_id,omitempty" bson:"invoice_id,omitempty"`
+	UserID         string     `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	SettledAmount  int64      `json:"settled_amount" bson:"settled_amount"`
+	RecordedAmount int64      `json:"recorded_amount" bson:"recorded_amount"`
+	Fee            int64      `json:"fee,omitempty" bson:"fee,omitempty"`
+	SettledAt      *time.Time `json:"settled_at,omitempty" bson:"settled_at,omitempty"`
+	PaidAt         *time.Time `json:"paid_at,omitempty" bson:"paid_at,omitempty"`
+}
+
+// SettlementSummary counts the lines of a reconciliation by status
+type SettlementSummary struct {
+	Rows            int   `json:"rows" bson:"rows"`
+	Matched         int   `json:"matched" bson:"matched"`
+	AmountMismatch  int   `json:"amount_mismatch" bson:"amount_mismatch"`
+	MissingWebhooks int   `json:"missing_webhooks" bson:"missing_webhooks"`
+	Unknown         int   `json:"unknown" bson:"unknown"`
+	Duplicates      int   `json:"duplicates" bson:"duplicates"`
+	NotSettled      int   `json:"not_settled" bson:"not_settled"`
+	SettledAmount   int64 `json:"settled_amount" bson:"settled_amount"`
+	Fees            int64 `json:"fees" bson:"fees"`
+}
+
+// Count adds an item to the summary
+func (s *SettlementSummary) Count(item SettlementItem) {
+	switch item.Status {
+	case SettlementMatched:
+		s.Matched++
+	case SettlementAmountMismatch:
+		s.AmountMismatch++
+	case SettlementMissingWebhook:
+		s.MissingWebhooks++
+	case SettlementUnknown:
+		s.Unknown++
+	case SettlementDuplicate:
+		s.Duplicates++
+	case SettlementNotSettled:
+		s.NotSettled++
+		return
+	}
+	s.Rows++
+	s.SettledAmount += item.SettledAmount
+	s.Fees += item.Fee
+}
+
+// SettlementReconciliation is a provider settlement file matched against the tenant's
+// invoices. Provider payments are only checked for absence from the file when a period is
+// given.
+type SettlementReconciliation struct {
+	ID         string            `json:"id" bson:"_id"`
+	TenantID   string            `json:"tenant_id" bson:"tenant_id"`
+	FileName   string            `json:"file_name" bson:"file_name"`
+	UploadedBy string            `json:"uploaded_by" bson:"uploaded_by"`
+	PeriodFrom *time.Time        `json:"period_from,omitempty" bson:"period_from,omitempty"`
+	PeriodTo   *time.Time        `json:"period_to,omitempty" bson:"period_to,omitempty"`
+	Currency   string            `json:"currency" bson:"currency"` // Of every amount in it, in minor units
+	Summary    SettlementSummary `json:"summary" bson:"summary"`
+	Items      []SettlementItem  `json:"items,omitempty" bson:"items"`
+	CreatedAt  time.Time         `json:"created_at" bson:"created_at"`
+}
+
+// SettlementRepository stores settlement reconciliations
+type SettlementRepository interface {
+	Create(ctx context.Context, rec *SettlementReconciliation) error
+	GetByID(ctx context.Context, id string) (*SettlementReconciliation, error)
+	// ListByTenant pages the tenant's reconciliations, newest first, without their items
+	ListByTenant(ctx context.Context, tenantID string, q PageQuery) (*Page[*SettlementReconciliation], error)
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// What a share link shows
+const (
+	ShareKindPersonalBest = "personal_best" // One personal best
+	ShareKindMonth        = "month"         // A month of training at the gym
+)
+
+var (
+	ErrInvalidShareLink  = errors.New("share a personal best by its ID, or a month up to the current one as YYYY-MM")
+	ErrShareLinkNotFound = errors.New("share link not found or revoked")
+	ErrShareLinkMinor    = errors.New("members under the gym's minor age can't share public links")
+)
+
+// ShareLink is a public link to a card of one of a member's achievements, for posting on
+// social media. Anyone with the link can see the card until the member revokes it. Only a
+// hash of the secret is stored.
+type ShareLink struct {
+	ID        string     `bson:"_id,omitempty" json:"id"`
+	TenantID  string     `bson:"tenant_id" json:"tenant_id"`
+	MemberID  string     `bson:"member_id" json:"member_id"`
+	Kind      string     `bson:"kind" json:"kind"`
+	SubjectID string     `bson:"subject_id" json:"subject_id"` // The PB's ID, or the month as YYYY-MM
+	TokenHash string     `bson:"token_hash" json:"-"`
+	Card      ShareCard  `bson:"card" json:"card"`
+	CreatedAt time.Time  `bson:"created_at" json:"created_at"`
+	RevokedAt *time.Time `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+}
+
+// ShareCard is what the public sees of an achievement, captured when the link is made. It
+// carries the member's first name and the achievement only: no IDs, contact details or
+// body measurements.
+type ShareCard struct {
+	Kind      string      `bson:"kind" json:"kind"`
+	FirstName string      `bson:"first_name" json:"first_name"`
+	Title     string      `bson:"title" json:"title"`       // e.g. "New Back Squat personal best"
+	Headline  string      `bson:"headline" json:"headline"` // e.g. "100 kg × 5"
+	Stats     []ShareStat `bson:"stats,omitempty" json:"stats,omitempty"`
+	Date      time.Time   `bson:"date" json:"date"` // When the PB was set, or the first of the month
+}
+
+// ShareStat is one figure on a card, e.g. "Sessions: 12"
+type ShareStat struct {
+	Label string `bson:"label" json:"label"`
+	Value string `bson:"value" json:"value"`
+}
+
+// SharedAchievement is a card as served publicly, under the gym's current name and logo
+type SharedAchievement struct {
+	ShareCard
+	Gym     string `json:"gym"`
+	LogoURL string `json:"logo_url,omitempty"`
+}
+
+// ShareLinkRepository stores share links
+type ShareLinkRepository interface {
+	Create(ctx context.Context, link *ShareLink) error
+	// FindByHash returns ErrNotFound for unknown hashes; revoked links are returned
+	FindByHash(ctx context.Context, hash string) (*ShareLink, error)
+	// ListByMember returns the member's links at the tenant, newest first
+	ListByMember(ctx context.Context, tenantID, memberID string) ([]*ShareLink, error)
+	// Revoke returns ErrNotFound when the link isn't one of the member's active links
+	Revoke(ctx context.Context, memberID, id string, at time.Time) error
+}
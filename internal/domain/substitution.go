@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Substitution offer states
+const (
+	SubstitutionOpen      = "open"
+	SubstitutionClaimed   = "claimed"   // Another coach took the session over
+	SubstitutionCancelled = "cancelled" // Withdrawn by a tenant admin; the session stays with its coach
+)
+
+// MaxUnavailableDays bounds how far ahead a coach can mark themselves unavailable at once
+const MaxUnavailableDays = 30
+
+var (
+	ErrInvalidUnavailability = errors.New("unavailable period must end after it starts and last at most 30 days")
+	ErrSubstitutionClosed    = errors.New("this session was already claimed or withdrawn")
+	ErrSubstitutionExists    = errors.New("this session is already offered for cover")
+	ErrCannotCover           = errors.New("coach can't cover this session: they're unavailable or already booked at that time")
+	ErrSessionChanged        = errors.New("the session was moved, cancelled or reassigned after it was offered")
+)
+
+// SubstitutionOffer puts one session of an unavailable coach up for the other coaches at
+// its branch to claim. The claiming coach becomes the session's coach.
+type SubstitutionOffer struct {
+	ID         string    `json:"id" bson:"_id,omitempty"`
+	TenantID   string    `json:"tenant_id" bson:"tenant_id"`
+	BranchID   string    `json:"branch_id" bson:"branch_id"`
+	ScheduleID string    `json:"schedule_id" bson:"schedule_id"`
+	ContractID string    `json:"contract_id" bson:"contract_id"`
+	MemberID   string    `json:"member_id" bson:"member_id"`
+	CoachID    string    `json:"coach_id" bson:"coach_id"` // The unavailable coach
+	Reason     string    `json:"reason,omitempty" bson:"reason,omitempty"`
+	StartTime  time.Time `json:"start_time" bson:"start_time"`
+	EndTime    time.Time `json:"end_time" bson:"end_time"`
+	Status     string    `json:"status" bson:"status"`
+
+	ClaimedBy string     `json:"claimed_by,omitempty" bson:"claimed_by,omitempty"` // Substitute coach
+	ClosedBy  string     `json:"closed_by,omitempty" bson:"closed_by,omitempty"`   // Who claimed, assigned or withdrew it
+	ClosedAt  *time.Time `json:"closed_at,omitempty" bson:"closed_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" bson:"created_at"`
+}
+
+type SubstitutionRepository interface {
+	// Create returns ErrSubstitutionExists if the schedule already has an open offer
+	Create(ctx context.Context, offer *SubstitutionOffer) error
+	GetByID(ctx context.Context, id string) (*SubstitutionOffer, error)
+	// ListOpen returns the tenant's open offers at the branches starting after from, soonest first
+	ListOpen(ctx context.Context, tenantID string, branchIDs []string, from time.Time) ([]*SubstitutionOffer, error)
+	// ListByTenant pages through the tenant's offers, newest first. An empty status lists all.
+	ListByTenant(ctx context.Context, tenantID, status string, page PageQuery) (*Page[*SubstitutionOffer], error)
+	// Close moves an open offer to status, recording the substitute (if any) and the actor.
+	// It returns false if the offer was no longer open.
+	Close(ctx context.Context, id, status, claimedBy, actorID string, at time.Time) (bool, error)
+}
@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrInvalidSyncBatch = errors.New("invalid sync batch")
+	ErrInvalidSyncToken = errors.New("invalid sync token")
+)
+
+// MaxSyncMutations bounds one sync request: a long day of offline sessions fits comfortably
+const MaxSyncMutations = 500
+
+// SyncHorizon is how far either side of now the coach app keeps sessions; a first sync
+// (without a token) returns the sessions in it
+const SyncHorizon = 30 * 24 * time.Hour
+
+// Mutations the coach app can queue while offline
+const (
+	SyncSetLogUpsert   = "set_log.upsert"  // Log or correct a set, created on first sight of its client_id
+	SyncSetLogDelete   = "set_log.delete"  // Remove a set
+	SyncScheduleStatus = "schedule.status" // Complete, cancel or mark a session as a no-show
+	SyncMemberCreate   = "member.create"   // Sign up a walk-in client
+)
+
+// Outcomes of a mutation
+const (
+	SyncApplied   = "applied"
+	SyncDuplicate = "duplicate" // Already applied by an earlier sync; the first result is repeated
+	SyncRejected  = "rejected"  // Invalid or not the coach's to change; retrying won't help
+)
+
+// SyncMutation is one change queued by the coach app. ID is a ULID the app gives it, so a
+// batch resent after a dropped response is applied once. Exactly the payload for Type is set.
+type SyncMutation struct {
+	ID             string            `json:"id"`
+	Type           string            `json:"type"`
+	SetLog         *SyncSetLog       `json:"set_log,omitempty"`
+	ScheduleStatus *SyncStatusChange `json:"schedule_status,omitempty"`
+	Member         *SyncMember       `json:"member,omitempty"`
+}
+
+// SyncSetLog is a set as the coach app has it. The set is found by ClientID;
+// PlannedExerciseID (a server ID or client ULID) is needed to create it.
+type SyncSetLog struct {
+	ClientID          string  `json:"client_id"`
+	PlannedExerciseID string  `json:"planned_exercise_id,omitempty"`
+	SetIndex          int     `json:"set_index"`
+	Weight            float64 `json:"weight"`
+	Reps              int     `json:"reps"`
+	Remarks           string  `json:"remarks,omitempty"`
+	Completed         bool    `json:"completed"`
+}
+
+// SyncStatusChange moves a session, found by server ID or client ULID, to Status
+type SyncStatusChange struct {
+	ScheduleID string `json:"schedule_id"`
+	Status     string `json:"status"`
+}
+
+// SyncMember is a client the coach signed up offline
+type SyncMember struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// SyncResult is what became of one mutation. ServerID is the ID of what it created or
+// changed, for the app to swap in for its ULID.
+type SyncResult struct {
+	MutationID string `json:"mutation_id"`
+	Type       string `json:"type"`
+	Status     string `json:"status"`
+	ServerID   string `json:"server_id,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// SyncDelta is the server state that changed since the app's last sync, its own mutations
+// included. Soft-deleted schedules and set logs are returned with deleted_at set.
+type SyncDelta struct {
+	Schedules []*Schedule       `json:"schedules"`
+	SetLogs   []*SetLogDocument `json:"set_logs"`
+	Members   []*User           `json:"members"`
+}
+
+// SyncOutcome answers a sync request. SyncToken is passed with the next one.
+type SyncOutcome struct {
+	Results   []SyncResult `json:"results"`
+	Delta     SyncDelta    `json:"delta"`
+	SyncToken string       `json:"sync_token"`
+}
+
+// ValidateSyncBatch checks the batch's shape; whether each mutation can be applied is
+// answered per mutation
+func ValidateSyncBatch(mutations []SyncMutation) error {
+	if len(mutations) > MaxSyncMutations {
+		return fmt.Errorf("%w: at most %d mutations, sync in smaller batches", ErrInvalidSyncBatch, MaxSyncMutations)
+	}
+	seen := make(map[string]bool, len(mutations))
+	for _, m := range mutations {
+		if m.ID == "" {
+			return fmt.Errorf("%w: every mutation needs an id", ErrInvalidSyncBatch)
+		}
+		if seen[m.ID] {
+			return fmt.Errorf("%w: mutation %s appears twice", ErrInvalidSyncBatch, m.ID)
+		}
+		seen[m.ID] = true
+	}
+	return nil
+}
+
+// SyncToken marks the point in time a delta was read up to. It is opaque to clients.
+func SyncToken(at time.Time) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(at.UnixNano(), 10)))
+}
+
+// ParseSyncToken returns the time a token marks; the zero time for an empty token
+func ParseSyncToken(token string) (time.Time, error) {
+	if token == "" {
+		return time.Time{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return time.Time{}, ErrInvalidSyncToken
+	}
+	nanos, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || nanos <= 0 {
+		return time.Time{}, ErrInvalidSyncToken
+	}
+	return time.Unix(0, nanos).UTC(), nil
+}
+
+// SyncLogEntry remembers the result of an applied mutation so a replay gets the same answer
+type SyncLogEntry struct {
+	MutationID string    `bson:"_id" json:"mutation_id"`
+	CoachID    string    `bson:"coach_id" json:"coach_id"`
+	Type       string    `bson:"type" json:"type"`
+	Status     string    `bson:"status" json:"status"`
+	ServerID   string    `bson:"server_id,omitempty" json:"server_id,omitempty"`
+	Error      string    `bson:"error,omitempty" json:"error,omitempty"`
+	AppliedAt  time.Time `bson:"applied_at" json:"applied_at"`
+}
+
+// SyncLogRepository stores the results of applied mutations. Entries expire after a while;
+// apps don't hold on to mutations for longer.
+type SyncLogRepository interface {
+	// GetByIDs returns the coach's entries among mutationIDs, by mutation ID
+	GetByIDs(ctx context.Context, coachID string, mutationIDs []string) (map[string]*SyncLogEntry, error)
+	Record(ctx context.Context, entry *SyncLogEntry) error
+}
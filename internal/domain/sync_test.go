@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncToken(t *testing.T) {
+	at := time.Date(2025, 6, 16, 10, 0, 0, 123, time.UTC)
+
+	parsed, err := ParseSyncToken(SyncToken(at))
+	require.NoError(t, err)
+	assert.True(t, at.Equal(parsed))
+
+	parsed, err = ParseSyncToken("")
+	require.NoError(t, err)
+	assert.True(t, parsed.IsZero(), "first sync")
+
+	_, err = ParseSyncToken("not a token!")
+	assert.ErrorIs(t, err, ErrInvalidSyncToken)
+	_, err = ParseSyncToken("MTIzYWJj") // "123abc"
+	assert.ErrorIs(t, err, ErrInvalidSyncToken)
+}
+
+func TestValidateSyncBatch(t *testing.T) {
+	assert.NoError(t, ValidateSyncBatch(nil))
+	assert.NoError(t, ValidateSyncBatch([]SyncMutation{{ID: "a"}, {ID: "b"}}))
+
+	assert.ErrorIs(t, ValidateSyncBatch([]SyncMutation{{ID: ""}}), ErrInvalidSyncBatch, "missing id")
+	assert.ErrorIs(t, ValidateSyncBatch([]SyncMutation{{ID: "a"}, {ID: "a"}}), ErrInvalidSyncBatch, "repeated id")
+	assert.ErrorIs(t, ValidateSyncBatch(make([]SyncMutation, MaxSyncMutations+1)), ErrInvalidSyncBatch, "too many")
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Timeline entry types
+const (
+	TimelineSessionCompleted = "session_completed"
+	TimelinePersonalBest     = "personal_best"
+	TimelineScan             = "scan"
+	TimelineGoalReached      = "goal_reached" // A scan reached the target weight of the one before
+	TimelineContractStarted  = "contract_started"
+	TimelineContractRenewed  = "contract_renewed"
+)
+
+// TimelineEntry is one event in a member's activity feed. Entries are projected from the
+// workout event log, the outbox and the services that raise PBs and renewals.
+type TimelineEntry struct {
+	ID       string `json:"id" bson:"_id,omitempty"`
+	TenantID string `json:"tenant_id" bson:"tenant_id"`
+	MemberID string `json:"member_id" bson:"member_id"`
+	// Key identifies the event the entry was projected from, so replaying it records nothing new
+	Key        string                 `json:"-" bson:"key"`
+	Type       string                 `json:"type" bson:"type"`
+	Title      string                 `json:"title" bson:"title"`
+	Data       map[string]interface{} `json:"data,omitempty" bson:"data,omitempty"` // Type-specific details (schedule_id, scan_id, ...)
+	OccurredAt time.Time              `json:"occurred_at" bson:"occurred_at"`
+	CreatedAt  time.Time              `json:"created_at" bson:"created_at"`
+}
+
+// TimelineRepository stores members' timelines
+type TimelineRepository interface {
+	// Record saves the entry unless one with its key already exists
+	Record(ctx context.Context, entry *TimelineEntry) error
+	// List returns a page of the member's timeline, most recent event first. An empty
+	// tenantID lists the entries of all the member's tenants.
+	List(ctx context.Context, tenantID, memberID string, q PageQuery) (*Page[*TimelineEntry], error)
+}
+
+// ReachedTargetWeight reports whether latest reached the target weight the InBody sheet of
+// previous set, coming from either side of it
+func ReachedTargetWeight(previous, latest *InBodyRecord) bool {
+	if previous == nil || latest == nil || previous.TargetWeight <= 0 || previous.Weight <= 0 || latest.Weight <= 0 {
+		return false
+	}
+	target := previous.TargetWeight
+	if math.Abs(previous.Weight-target) < 0.1 {
+		return false // Already there
+	}
+	if previous.Weight > target {
+		return latest.Weight <= target
+	}
+	return latest.Weight >= target
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReachedTargetWeight(t *testing.T) {
+	scan := func(weight, target float64) *InBodyRecord {
+		return &InBodyRecord{Weight: weight, TargetWeight: target}
+	}
+
+	cases := []struct {
+		name             string
+		previous, latest *InBodyRecord
+		want             bool
+	}{
+		{"lost down to the target", scan(80, 75), scan(75, 74), true},
+		{"lost past the target", scan(80, 75), scan(74.2, 74), true},
+		{"still above", scan(80, 75), scan(76, 75), false},
+		{"gained up to the target", scan(60, 64), scan(64.3, 65), true},
+		{"still below", scan(60, 64), scan(62, 65), false},
+		{"already at the target", scan(75, 75), scan(74, 74), false},
+		{"no target on the sheet", scan(80, 0), scan(70, 0), false},
+		{"first scan", nil, scan(75, 74), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ReachedTargetWeight(tc.previous, tc.latest))
+		})
+	}
+}
@@ -2,9 +2,36 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"time"
 )
 
+var ErrNotTenantMember = errors.New("user is not a member of this tenant")
+
+// ErrUserDeleted is returned when signing in or syncing an account a gym deleted. It
+// stays deleted until restored or purged; signing up again with it would collide.
+var ErrUserDeleted = errors.New("this account has been deleted")
+
+var ErrInvalidUserQuery = errors.New("sort must be newest, oldest or name, and role a known role")
+
+var (
+	ErrNoWorkingBranch  = errors.New("coach must be assigned to a home branch")
+	ErrBranchNotAllowed = errors.New("coach does not work at this branch")
+)
+
+// TenantMembership gives a user roles in a tenant besides their primary one,
+// e.g. a coach working for several franchise gyms
+type TenantMembership struct {
+	TenantID         string   `bson:"tenant_id" json:"tenant_id"`
+	Roles            []string `bson:"roles" json:"roles"`
+	HomeBranchID     string   `bson:"home_branch_id,omitempty" json:"home_branch_id,omitempty"`
+	BranchAccess     []string `bson:"branch_access,omitempty" json:"branch_access,omitempty"`
+	WorkingBranchIDs []string `bson:"working_branch_ids,omitempty" json:"working_branch_ids,omitempty"`
+
+	CustomFields CustomFieldValues `bson:"custom_fields,omitempty" json:"custom_fields,omitempty"`
+}
+
 // User represents a unified identity with multiple roles
 type User struct {
 	ID           string   `bson:"_id,omitempty" json:"id"`
@@ -15,14 +42,31 @@ type User struct {
 	TenantID     string   `bson:"tenant_id" json:"tenant_id"`
 	HomeBranchID string   `bson:"home_branch_id" json:"home_branch_id"` // For coaches
 	BranchAccess []string `bson:"branch_access" json:"branch_access"`   // For members: list of accessible branch IDs
+	AvatarURL    string   `bson:"avatar_url,omitempty" json:"avatar_url,omitempty"`
+	Demo         bool     `bson:"demo,omitempty" json:"demo,omitempty"` // Generated sales-demo user (see DemoDataRepository)
+
+	// Date only (midnight UTC); members under their tenant's minor age need a guardian's consent
+	DateOfBirth *time.Time `bson:"date_of_birth,omitempty" json:"date_of_birth,omitempty"`
+
+	// Other branches a coach also works at; sessions can be booked at any of these or the home branch
+	WorkingBranchIDs []string `bson:"working_branch_ids,omitempty" json:"working_branch_ids,omitempty"`
+
+	// Memberships in other tenants. TenantID, Roles and branches above describe the primary
+	// tenant; a token is scoped to one of them at a time (see ScopedTo).
+	Memberships []TenantMembership `bson:"memberships,omitempty" json:"memberships,omitempty"`
+
+	// Values of the primary tenant's custom member fields (see Tenant.CustomFields)
+	CustomFields CustomFieldValues `bson:"custom_fields,omitempty" json:"custom_fields,omitempty"`
 
 	// Activity Tracking
 	FirstLoginAt *time.Time `bson:"first_login_at,omitempty" json:"first_login_at,omitempty"`
 	LastLoginAt  *time.Time `bson:"last_login_at,omitempty" json:"last_login_at,omitempty"`
 	LoginCount   int        `bson:"login_count" json:"login_count"`
 
-	CreatedAt time.Time `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+	CreatedAt time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time  `bson:"updated_at" json:"updated_at"`
+	Version   int64      `bson:"version" json:"version"`                           // Incremented on every update; Update fails with ErrVersionConflict if stale
+	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"` // Soft-deleted; see UserRepository.Delete
 
 	// Entitlement
 	TrialEndDate        *time.Time `bson:"trial_end_date,omitempty" json:"trial_end_date,omitempty"`
@@ -47,6 +91,131 @@ func (u *User) HasRole(role string) bool {
 	return false
 }
 
+// TenantIDs lists every tenant the user belongs to, primary first
+func (u *User) TenantIDs() []string {
+	var ids []string
+	if u.TenantID != "" {
+		ids = append(ids, u.TenantID)
+	}
+	for _, m := range u.Memberships {
+		if m.TenantID != u.TenantID {
+			ids = append(ids, m.TenantID)
+		}
+	}
+	return ids
+}
+
+// ScopedTo returns a copy of the user with the roles and branches they hold in tenantID,
+// as used for a token scoped to that tenant. The copy must not be saved: it would replace
+// the primary tenant. An empty tenantID means the primary tenant.
+func (u *User) ScopedTo(tenantID string) (*User, error) {
+	if tenantID == "" || tenantID == u.TenantID {
+		return u, nil
+	}
+	for _, m := range u.Memberships {
+		if m.TenantID == tenantID {
+			scoped := *u
+			scoped.TenantID = m.TenantID
+			scoped.Roles = m.Roles
+			scoped.HomeBranchID = m.HomeBranchID
+			scoped.WorkingBranchIDs = m.WorkingBranchIDs
+			scoped.BranchAccess = m.BranchAccess
+			scoped.CustomFields = m.CustomFields
+			return &scoped, nil
+		}
+	}
+	return nil, ErrNotTenantMember
+}
+
+// CustomFieldsIn returns the user's custom field values in the tenant
+func (u *User) CustomFieldsIn(tenantID string) CustomFieldValues {
+	if tenantID == u.TenantID {
+		return u.CustomFields
+	}
+	for _, m := range u.Memberships {
+		if m.TenantID == tenantID {
+			return m.CustomFields
+		}
+	}
+	return nil
+}
+
+// SetCustomFields replaces the user's custom field values in the tenant
+func (u *User) SetCustomFields(tenantID string, values CustomFieldValues) error {
+	if tenantID == u.TenantID {
+		u.CustomFields = values
+		return nil
+	}
+	for i := range u.Memberships {
+		if u.Memberships[i].TenantID == tenantID {
+			u.Memberships[i].CustomFields = values
+			return nil
+		}
+	}
+	return ErrNotTenantMember
+}
+
+// CoachBranchIDs lists the branches a coach works at, home branch first
+func (u *User) CoachBranchIDs() []string {
+	var ids []string
+	if u.HomeBranchID != "" {
+		ids = append(ids, u.HomeBranchID)
+	}
+	for _, id := range u.WorkingBranchIDs {
+		if id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// WorksAt reports whether the coach can be booked at branchID
+func (u *User) WorksAt(branchID string) bool {
+	return branchID != "" && slices.Contains(u.CoachBranchIDs(), branchID)
+}
+
+// ScheduleBranch picks the branch for a new session. An explicit choice must be one of the
+// coach's branches; otherwise preferred (usually the contract's branch) is used when the
+// coach works there, falling back to the home branch.
+func (u *User) ScheduleBranch(requested, preferred string) (string, error) {
+	branches := u.CoachBranchIDs()
+	if len(branches) == 0 {
+		return "", ErrNoWorkingBranch
+	}
+	if requested != "" {
+		if !u.WorksAt(requested) {
+			return "", ErrBranchNotAllowed
+		}
+		return requested, nil
+	}
+	if u.WorksAt(preferred) {
+		return preferred, nil
+	}
+	return branches[0], nil
+}
+
+// SetMembership adds or replaces the membership for m.TenantID
+func (u *User) SetMembership(m TenantMembership) {
+	for i := range u.Memberships {
+		if u.Memberships[i].TenantID == m.TenantID {
+			u.Memberships[i] = m
+			return
+		}
+	}
+	u.Memberships = append(u.Memberships, m)
+}
+
+// RemoveMembership drops the membership for tenantID and reports whether there was one
+func (u *User) RemoveMembership(tenantID string) bool {
+	for i, m := range u.Memberships {
+		if m.TenantID == tenantID {
+			u.Memberships = append(u.Memberships[:i], u.Memberships[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 // UserRepository defines operations for managing users
 type UserRepository interface {
 	// Core CRUD operations
@@ -54,9 +223,17 @@ type UserRepository interface {
 	GetByID(ctx context.Context, id string) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
 	GetByFirebaseUID(ctx context.Context, uid string) (*User, error)
-	Update(ctx context.Context, user *User) error
+	Update(ctx context.Context, user *User) error // Optimistic: returns ErrVersionConflict if user.Version is stale
 	UpdateFirebaseUID(ctx context.Context, userID string, firebaseUID string) error
-	Delete(ctx context.Context, id string) error
+	// Delete soft-deletes the user at the time given: they are hidden from every query until
+	// restored, or purged once past the retention period
+	Delete(ctx context.Context, id string, at time.Time) error
+	// Restore brings back a soft-deleted user of tenantID ("" for any), or ErrNotFound
+	Restore(ctx context.Context, id, tenantID string, at time.Time) error
+	// ListDeleted returns the soft-deleted users of tenantID ("" for all), latest first
+	ListDeleted(ctx context.Context, tenantID string) ([]*User, error)
+	// PurgeDeleted permanently removes users soft-deleted before the time given
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 
 	// Upsert operations
 	UpsertByFirebaseUID(ctx context.Context, user *User) error
@@ -73,6 +250,7 @@ type UserRepository interface {
 	GetAll(ctx context.Context) ([]*User, error)
 	GetByRole(ctx context.Context, role string) ([]*User, error)
 	GetByTenant(ctx context.Context, tenantID string) ([]*User, error)
+	ListUsers(ctx context.Context, q UserListQuery) (*Page[*User], error) // Filtered, sorted and cursor-paginated
 	GetByTenantAndRole(ctx context.Context, tenantID string, role string) ([]*User, error)
 }
 
@@ -83,3 +261,38 @@ const (
 	RoleSuperAdmin  = "super_admin"  // Platform Owner (Metamorph) - no tenant restriction
 	RoleTenantAdmin = "tenant_admin" // Gym Owner - restricted to specific tenant
 )
+
+// Roles lists every role
+var Roles = []string{RoleCoach, RoleMember, RoleSuperAdmin, RoleTenantAdmin}
+
+// Orders of a user list. Pages are cursor-paginated in each of them.
+const (
+	UserSortNewest = "newest" // Default
+	UserSortOldest = "oldest"
+	UserSortName   = "name" // A to Z, ignoring case
+)
+
+// UserListQuery filters and orders a page of users
+type UserListQuery struct {
+	TenantID string // Primary tenant; empty lists users of every tenant
+	Role     string // Only users with this role
+	Search   string // Case-insensitive part of the name or email
+	Sort     string
+	Page     PageQuery
+}
+
+// Normalized returns the query with the default sort and a clamped page size, or
+// ErrInvalidUserQuery for an unknown sort or role
+func (q UserListQuery) Normalized() (UserListQuery, error) {
+	if q.Sort == "" {
+		q.Sort = UserSortNewest
+	}
+	if !slices.Contains([]string{UserSortNewest, UserSortOldest, UserSortName}, q.Sort) {
+		return q, ErrInvalidUserQuery
+	}
+	if q.Role != "" && !slices.Contains(Roles, q.Role) {
+		return q, ErrInvalidUserQuery
+	}
+	q.Page = q.Page.Normalized()
+	return q, nil
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUser_ScopedTo(t *testing.T) {
+	user := &User{
+		ID:           "coach-1",
+		TenantID:     "gym-a",
+		Roles:        []string{RoleCoach},
+		HomeBranchID: "a-north",
+		Memberships: []TenantMembership{
+			{TenantID: "gym-b", Roles: []string{RoleCoach, RoleMember}, HomeBranchID: "b-east"},
+		},
+	}
+
+	assert.Equal(t, []string{"gym-a", "gym-b"}, user.TenantIDs())
+
+	primary, err := user.ScopedTo("")
+	require.NoError(t, err)
+	assert.Same(t, user, primary)
+
+	scoped, err := user.ScopedTo("gym-b")
+	require.NoError(t, err)
+	assert.Equal(t, "gym-b", scoped.TenantID)
+	assert.Equal(t, "b-east", scoped.HomeBranchID)
+	assert.True(t, scoped.HasRole(RoleMember))
+	assert.Equal(t, "gym-a", user.TenantID, "the original is untouched")
+
+	_, err = user.ScopedTo("gym-c")
+	assert.ErrorIs(t, err, ErrNotTenantMember)
+}
+
+func TestUser_Memberships(t *testing.T) {
+	user := &User{TenantID: "gym-a"}
+
+	user.SetMembership(TenantMembership{TenantID: "gym-b", Roles: []string{RoleCoach}})
+	user.SetMembership(TenantMembership{TenantID: "gym-b", Roles: []string{RoleMember}})
+	require.Len(t, user.Memberships, 1)
+	assert.Equal(t, []string{RoleMember}, user.Memberships[0].Roles)
+
+	assert.True(t, user.RemoveMembership("gym-b"))
+	assert.False(t, user.RemoveMembership("gym-b"))
+	assert.Empty(t, user.Memberships)
+}
+
+func TestUser_ScheduleBranch(t *testing.T) {
+	coach := &User{HomeBranchID: "north", WorkingBranchIDs: []string{"east", "north"}}
+	assert.Equal(t, []string{"north", "east"}, coach.CoachBranchIDs())
+
+	branch, err := coach.ScheduleBranch("east", "")
+	require.NoError(t, err)
+	assert.Equal(t, "east", branch)
+
+	branch, err = coach.ScheduleBranch("", "east")
+	require.NoError(t, err)
+	assert.Equal(t, "east", branch, "the contract's branch when the coach works there")
+
+	branch, err = coach.ScheduleBranch("", "south")
+	require.NoError(t, err)
+	assert.Equal(t, "north", branch, "otherwise the home branch")
+
+	_, err = coach.ScheduleBranch("south", "")
+	assert.ErrorIs(t, err, ErrBranchNotAllowed)
+
+	_, err = (&User{}).ScheduleBranch("", "north")
+	assert.ErrorIs(t, err, ErrNoWorkingBranch)
+}
+
+func TestUserListQuery_Normalized(t *testing.T) {
+	q, err := UserListQuery{}.Normalized()
+	assert.NoError(t, err)
+	assert.Equal(t, UserSortNewest, q.Sort)
+	assert.Equal(t, DefaultPageLimit, q.Page.Limit)
+
+	q, err = UserListQuery{Role: RoleCoach, Sort: UserSortName, Page: PageQuery{Limit: 500}}.Normalized()
+	assert.NoError(t, err)
+	assert.Equal(t, MaxPageLimit, q.Page.Limit)
+
+	_, err = UserListQuery{Sort: "email"}.Normalized()
+	assert.ErrorIs(t, err, ErrInvalidUserQuery)
+	_, err = UserListQuery{Role: "owner"}.Normalized()
+	assert.ErrorIs(t, err, ErrInvalidUserQuery)
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// WarehouseSchemaVersion is stamped on every exported row.
+// Bump it whenever the shape of WarehouseRow or of an entity's Data changes incompatibly.
+const WarehouseSchemaVersion = 1
+
+// Tenant warehouse export modes
+const (
+	WarehouseExportOff        = ""
+	WarehouseExportAnonymized = "anonymized" // Personal data dropped, people replaced by stable pseudonyms
+	WarehouseExportFull       = "full"
+)
+
+// Exported entities
+const (
+	WarehouseEntityScan    = "scan"
+	WarehouseEntitySession = "session"
+	WarehouseEntityInvoice = "invoice"
+)
+
+// Change operations
+const (
+	ChangeOpInsert = "insert"
+	ChangeOpUpdate = "update"
+	ChangeOpDelete = "delete"
+)
+
+// ChangeEvent is one document change read from the database's change feed
+type ChangeEvent struct {
+	Collection string
+	Op         string
+	DocumentID string
+	Document   map[string]interface{} // Full document after the change; nil for deletes
+	OccurredAt time.Time
+}
+
+// ChangeFeed streams document changes from the given collections to handle, in batches.
+// A batch is only checkpointed once handle returns nil, so after a restart unacknowledged
+// changes are delivered again (at-least-once).
+type ChangeFeed interface {
+	Watch(ctx context.Context, collections []string, handle func(ctx context.Context, batch []*ChangeEvent) error) error
+}
+
+// WarehouseRow is the versioned record written to the analytics warehouse
+type WarehouseRow struct {
+	SchemaVersion int                    `json:"schema_version"`
+	Entity        string                 `json:"entity"`
+	Op            string                 `json:"op"`
+	TenantID      string                 `json:"tenant_id"`
+	EntityID      string                 `json:"entity_id"`
+	OccurredAt    time.Time              `json:"occurred_at"`
+	Anonymized    bool                   `json:"anonymized"`
+	Data          map[string]interface{} `json:"data,omitempty"`
+}
+
+// WarehouseSink writes rows to a warehouse (ClickHouse, a JSON-lines stream, ...)
+type WarehouseSink interface {
+	Write(ctx context.Context, rows []*WarehouseRow) error
+}
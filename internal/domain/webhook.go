@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"time"
+)
+
+var ErrInvalidWebhook = errors.New("invalid webhook")
+
+// Webhook events a tenant can subscribe to
+const (
+	WebhookEventScheduleCompleted = "schedule.completed" // A coach completed a session
+	WebhookEventContractCreated   = "contract.created"   // A PT package was bought for a member
+	WebhookEventScanDigitized     = "scan.digitized"     // A member's InBody sheet was read into a scan
+	WebhookEventPBAchieved        = "pb.achieved"        // A session raised a member's personal best
+)
+
+// WebhookEvents lists every event a webhook can subscribe to
+var WebhookEvents = []string{
+	WebhookEventScheduleCompleted,
+	WebhookEventContractCreated,
+	WebhookEventScanDigitized,
+	WebhookEventPBAchieved,
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed" // Gave up after MaxWebhookAttempts
+)
+
+// MaxWebhookAttempts is how many times a delivery is posted before it is marked failed
+const MaxWebhookAttempts = 8
+
+// Webhook is a tenant's HTTPS endpoint that receives the events it subscribes to.
+// Every request is signed with the webhook's secret.
+type Webhook struct {
+	ID          string    `json:"id" bson:"_id,omitempty"`
+	TenantID    string    `json:"tenant_id" bson:"tenant_id"`
+	URL         string    `json:"url" bson:"url"`
+	Events      []string  `json:"events" bson:"events"`
+	Description string    `json:"description,omitempty" bson:"description,omitempty"`
+	Secret      string    `json:"-" bson:"secret"` // Shown once, when the webhook is created
+	Active      bool      `json:"active" bson:"active"`
+	CreatedBy   string    `json:"created_by" bson:"created_by"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// Validate checks the URL is HTTPS to a host that isn't plainly internal, and the events
+// are known. Names are resolved by WebhookPoster.CheckURL.
+func (w *Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("%w: url must be an https URL", ErrInvalidWebhook)
+	}
+	if ip := net.ParseIP(u.Hostname()); u.Hostname() == "localhost" || (ip != nil && !PublicIP(ip)) {
+		return fmt.Errorf("%w: url must point to a public host", ErrInvalidWebhook)
+	}
+	if len(w.Events) == 0 {
+		return fmt.Errorf("%w: subscribe to at least one event", ErrInvalidWebhook)
+	}
+	for _, event := range w.Events {
+		if !slices.Contains(WebhookEvents, event) {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidWebhook, event)
+		}
+	}
+	return nil
+}
+
+// PublicIP reports whether webhooks may be posted to ip: it isn't loopback, private,
+// link-local or unspecified, so a tenant can't make us reach into our own network
+func PublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsUnspecified()
+}
+
+// WebhookDelivery is one event posted to one webhook, kept as the webhook's delivery log.
+// EventID identifies the occurrence, so an event published twice is delivered once.
+type WebhookDelivery struct {
+	ID            string     `json:"id" bson:"_id,omitempty"`
+	TenantID      string     `json:"tenant_id" bson:"tenant_id"`
+	WebhookID     string     `json:"webhook_id" bson:"webhook_id"`
+	Event         string     `json:"event" bson:"event"`
+	EventID       string     `json:"event_id" bson:"event_id"`
+	Body          string     `json:"body" bson:"body"` // The JSON posted, as signed
+	Status        string     `json:"status" bson:"status"`
+	Attempts      int        `json:"attempts" bson:"attempts"`
+	LastError     string     `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" bson:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at" bson:"created_at"`
+	LastAttemptAt *time.Time `json:"last_attempt_at,omitempty" bson:"last_attempt_at,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty" bson:"delivered_at,omitempty"`
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *Webhook) error
+	GetByID(ctx context.Context, id string) (*Webhook, error)
+	ListByTenant(ctx context.Context, tenantID string) ([]*Webhook, error)
+	// ListSubscribed returns the tenant's active webhooks subscribed to event
+	ListSubscribed(ctx context.Context, tenantID, event string) ([]*Webhook, error)
+	Update(ctx context.Context, webhook *Webhook) error
+	Delete(ctx context.Context, id string) error
+}
+
+type WebhookDeliveryRepository interface {
+	// Create records a pending delivery; false means the webhook already has this EventID
+	Create(ctx context.Context, delivery *WebhookDelivery) (bool, error)
+	// Claim leases the oldest due pending delivery until now+lease and counts the attempt.
+	// Returns nil when nothing is due.
+	Claim(ctx context.Context, now time.Time, lease time.Duration) (*WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id string, at time.Time) error
+	// MarkFailed records the error and schedules the next attempt; failed deliveries are never retried
+	MarkFailed(ctx context.Context, id string, lastError string, at, nextAttemptAt time.Time, failed bool) error
+	ListByWebhook(ctx context.Context, webhookID string, q PageQuery) (*Page[*WebhookDelivery], error) // Newest first
+	DeleteByWebhook(ctx context.Context, webhookID string) error
+}
+
+// WebhookPoster posts a signed JSON body to a webhook
+type WebhookPoster interface {
+	Post(ctx context.Context, url, secret string, body []byte) error
+	// CheckURL resolves the URL's host and fails with ErrInvalidWebhook unless every
+	// address it has is a PublicIP
+	CheckURL(ctx context.Context, url string) error
+}
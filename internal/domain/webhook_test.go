@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublicIP(t *testing.T) {
+	for _, ip := range []string{"93.184.216.34", "2606:2800:220:1:248:1893:25c8:1946"} {
+		assert.True(t, PublicIP(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{"127.0.0.1", "::1", "10.0.0.5", "172.16.3.4", "192.168.1.1", "fd00::1",
+		"169.254.169.254", "fe80::1", "0.0.0.0", "::", "::ffff:127.0.0.1"} {
+		assert.False(t, PublicIP(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestWebhook_Validate(t *testing.T) {
+	events := []string{WebhookEventPBAchieved}
+	assert.NoError(t, (&Webhook{URL: "https://hooks.example.com/in", Events: events}).Validate())
+
+	for _, url := range []string{"http://hooks.example.com", "https://localhost/in", "https://127.0.0.1:8443/in",
+		"https://[::1]/in", "https://169.254.169.254/latest/meta-data", "https://10.1.2.3/in"} {
+		assert.ErrorIs(t, (&Webhook{URL: url, Events: events}).Validate(), ErrInvalidWebhook, url)
+	}
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+// Widget token scopes: what an embedded widget may read
+const (
+	WidgetScopeCoaches   = "coaches"
+	WidgetScopeTimetable = "timetable"
+	WidgetScopePackages  = "packages"
+)
+
+// Widget requests per minute: the default for a new token and the most a tenant may allow
+const (
+	DefaultWidgetRateLimit = 60
+	MaxWidgetRateLimit     = 600
+)
+
+var (
+	ErrInvalidWidgetToken = errors.New("widget token needs a name, at least one known scope and a rate limit of at most 600 requests a minute")
+	ErrWidgetUnauthorized = errors.New("missing, revoked or unknown widget token")
+	ErrWidgetScopeDenied  = errors.New("widget token does not allow this resource or origin")
+	ErrRateLimited        = errors.New("rate limit exceeded; try again later")
+)
+
+// WidgetToken is a public, read-only credential a tenant puts in the embed code of a widget
+// on its own website. Only a hash of the secret is stored; Prefix lets admins tell tokens apart.
+type WidgetToken struct {
+	ID             string     `bson:"_id,omitempty" json:"id"`
+	TenantID       string     `bson:"tenant_id" json:"tenant_id"`
+	Name           string     `bson:"name" json:"name"`
+	TokenHash      string     `bson:"token_hash" json:"-"`
+	Prefix         string     `bson:"prefix" json:"prefix"`
+	Scopes         []string   `bson:"scopes" json:"scopes"`
+	AllowedOrigins []string   `bson:"allowed_origins,omitempty" json:"allowed_origins,omitempty"` // Empty allows any site
+	RateLimit      int        `bson:"rate_limit" json:"rate_limit"`                               // Requests per minute
+	CreatedBy      string     `bson:"created_by" json:"created_by"`
+	CreatedAt      time.Time  `bson:"created_at" json:"created_at"`
+	RevokedAt      *time.Time `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+}
+
+// Validate checks the settings an admin chose, filling in the default rate limit
+func (t *WidgetToken) Validate() error {
+	if t.Name == "" || len(t.Scopes) == 0 || t.RateLimit < 0 || t.RateLimit > MaxWidgetRateLimit {
+		return ErrInvalidWidgetToken
+	}
+	for _, scope := range t.Scopes {
+		if scope != WidgetScopeCoaches && scope != WidgetScopeTimetable && scope != WidgetScopePackages {
+			return ErrInvalidWidgetToken
+		}
+	}
+	if t.RateLimit == 0 {
+		t.RateLimit = DefaultWidgetRateLimit
+	}
+	return nil
+}
+
+// Permits reports whether the token may read scope when embedded on origin. Requests
+// without an Origin header (server-side fetches) are only checked for scope.
+func (t *WidgetToken) Permits(scope, origin string) bool {
+	if t.RevokedAt != nil || !slices.Contains(t.Scopes, scope) {
+		return false
+	}
+	return origin == "" || len(t.AllowedOrigins) == 0 || slices.Contains(t.AllowedOrigins, origin)
+}
+
+// WidgetTokenRepository stores widget tokens
+type WidgetTokenRepository interface {
+	Create(ctx context.Context, token *WidgetToken) error
+	// FindByHash returns ErrNotFound for unknown hashes; revoked tokens are returned
+	FindByHash(ctx context.Context, hash string) (*WidgetToken, error)
+	ListByTenant(ctx context.Context, tenantID string) ([]*WidgetToken, error)
+	// Revoke returns ErrNotFound when the token isn't one of the tenant's active tokens
+	Revoke(ctx context.Context, tenantID, id string, at time.Time) error
+}
+
+// WidgetCoach is the public profile of a coach
+type WidgetCoach struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	AvatarURL string   `json:"avatar_url,omitempty"`
+	BranchIDs []string `json:"branch_ids"`
+}
+
+// WidgetSlot is a weekly window in which a coach takes bookings
+type WidgetSlot struct {
+	Weekday   time.Weekday `json:"weekday"` // 0 = Sunday
+	Start     string       `json:"start"`
+	End       string       `json:"end"`
+	CoachID   string       `json:"coach_id"`
+	CoachName string       `json:"coach_name"`
+}
+
+// WidgetBranchTimetable is the weekly timetable of one branch
+type WidgetBranchTimetable struct {
+	BranchID string       `json:"branch_id"`
+	Name     string       `json:"name"`
+	Slots    []WidgetSlot `json:"slots"`
+}
+
+// WidgetPackage is a PT package as shown in a public catalog
+type WidgetPackage struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	BranchID      string `json:"branch_id"`
+	BranchName    string `json:"branch_name"`
+	TotalSessions int    `json:"total_sessions"`
+	Price         Money  `json:"price"`
+
+	// Prices at branches that charge differently for a package sold at every branch
+	BranchPrices []BranchPrice `json:"branch_prices,omitempty"`
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Workout event types, in the order a session usually produces them
+const (
+	WorkoutEventSessionInitialized = "session.initialized"
+	WorkoutEventExerciseAdded      = "exercise.added"
+	WorkoutEventSetLogged          = "set.logged"
+	WorkoutEventSessionCompleted   = "session.completed"
+)
+
+// WorkoutEvent is an immutable entry in the workout event log.
+// Derived data (daily volumes, personal bests) is computed from these events and can be
+// rebuilt by replaying them.
+type WorkoutEvent struct {
+	ID         string                 `json:"id" bson:"_id,omitempty"`
+	Type       string                 `json:"type" bson:"type"`
+	TenantID   string                 `json:"tenant_id" bson:"tenant_id"`
+	ScheduleID string                 `json:"schedule_id" bson:"schedule_id"`
+	MemberID   string                 `json:"member_id" bson:"member_id"`
+	ActorID    string                 `json:"actor_id,omitempty" bson:"actor_id,omitempty"` // Coach who caused the event, when known
+	Data       map[string]interface{} `json:"data,omitempty" bson:"data,omitempty"`         // Type-specific details (exercise_id, weight, reps, ...)
+	OccurredAt time.Time              `json:"occurred_at" bson:"occurred_at"`
+}
+
+// WorkoutEventRepository is append-only: events are never updated or deleted
+type WorkoutEventRepository interface {
+	Append(ctx context.Context, event *WorkoutEvent) error
+	// ListBySchedule returns a schedule's events in the order they occurred
+	ListBySchedule(ctx context.Context, scheduleID string) ([]*WorkoutEvent, error)
+	// ListScheduleIDs returns every schedule with at least one event of the given type
+	ListScheduleIDs(ctx context.Context, eventType string) ([]string, error)
+}
@@ -7,8 +7,11 @@ import (
 )
 
 var (
-	ErrSessionNotFound      = errors.New("workout session not found")
-	ErrExerciseULIDNotFound = errors.New("exercise ULID not found in session")
+	ErrSessionNotFound       = errors.New("workout session not found")
+	ErrExerciseULIDNotFound  = errors.New("exercise ULID not found in session")
+	ErrSelfWorkoutInProgress = errors.New("finish your current workout before starting another")
+	ErrSelfWorkoutClosed     = errors.New("workout is already completed")
+	ErrSelfWorkoutExpired    = errors.New("workout was left unfinished too long; start a new one")
 )
 
 type SetLog struct {
@@ -46,6 +49,13 @@ type WorkoutSession struct {
 	UpdatedAt        time.Time          `json:"updated_at" bson:"updated_at"`
 }
 
+// SelfWorkout is a member's self-logged workout with everything logged so far
+type SelfWorkout struct {
+	Schedule  *Schedule          `json:"schedule"`
+	Exercises []*PlannedExercise `json:"exercises"`
+	Sets      []*SetLogDocument  `json:"sets"`
+}
+
 type WorkoutSessionRepository interface {
 	Create(ctx context.Context, session *WorkoutSession) error
 	GetByID(ctx context.Context, id string) (*WorkoutSession, error)
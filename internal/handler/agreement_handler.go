@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+type AgreementHandler struct {
+	agreementService *service.AgreementService
+	tenantRepo       domain.TenantRepository
+	maxUploadMB      int64
+}
+
+func NewAgreementHandler(agreementService *service.AgreementService, tenantRepo domain.TenantRepository, maxUploadMB int64) *AgreementHandler {
+	return &AgreementHandler{
+		agreementService: agreementService,
+		tenantRepo:       tenantRepo,
+		maxUploadMB:      maxUploadMB,
+	}
+}
+
+// GetMyAgreement GET /v1/me/contracts/:id/agreement
+func (h *AgreementHandler) GetMyAgreement(c *fiber.Ctx) error {
+	memberID, ok := c.Locals("userID").(string)
+	if !ok || memberID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing user context")
+	}
+
+	agreement, err := h.agreementService.GetForMember(c.UserContext(), c.Params("id"), memberID)
+	if err != nil {
+		return agreementError(c, err)
+	}
+	return response.OK(c, agreement)
+}
+
+// SignMyAgreement POST /v1/me/contracts/:id/agreement/sign
+// Accepts JSON {"typed_name": "...", "consent": true} or multipart with a "signature" image
+func (h *AgreementHandler) SignMyAgreement(c *fiber.Ctx) error {
+	memberID, ok := c.Locals("userID").(string)
+	if !ok || memberID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing user context")
+	}
+
+	input := service.SignatureInput{
+		IPAddress: c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	}
+
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		input.TypedName = c.FormValue("typed_name")
+		if fileHeader, err := c.FormFile("signature"); err == nil {
+			if fileHeader.Size > h.maxUploadMB*1024*1024 {
+				return response.Error(c, fiber.StatusBadRequest, "Signature image is too large")
+			}
+			file, err := fileHeader.Open()
+			if err != nil {
+				return response.Error(c, fiber.StatusBadRequest, "Failed to read signature image")
+			}
+			defer file.Close()
+			input.Image, err = io.ReadAll(file)
+			if err != nil {
+				return response.Error(c, fiber.StatusBadRequest, "Failed to read signature image")
+			}
+		}
+	} else {
+		var req struct {
+			TypedName string `json:"typed_name"`
+			Consent   bool   `json:"consent"`
+		}
+		if err := c.BodyParser(&req); err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+		}
+		if !req.Consent {
+			return response.Error(c, fiber.StatusBadRequest, "Consent must be given to sign the agreement")
+		}
+		input.TypedName = req.TypedName
+	}
+
+	agreement, err := h.agreementService.Sign(c.UserContext(), c.Params("id"), memberID, input)
+	if err != nil {
+		return agreementError(c, err)
+	}
+	return response.OK(c, agreement)
+}
+
+// GetContractTemplate GET /v1/tenant-admin/contract-template
+func (h *AgreementHandler) GetContractTemplate(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+
+	tenant, err := h.tenantRepo.GetByID(c.UserContext(), tenantID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return response.Error(c, fiber.StatusNotFound, "Tenant not found")
+		}
+		return response.Fail(c, err)
+	}
+
+	return response.OK(c, fiber.Map{
+		"template":   tenant.ContractTemplate,
+		"default":    domain.DefaultContractTemplate,
+		"is_default": strings.TrimSpace(tenant.ContractTemplate) == "",
+	})
+}
+
+// UpdateContractTemplate PUT /v1/tenant-admin/contract-template
+// Applies to contracts created after the change; existing agreements keep their rendered text
+func (h *AgreementHandler) UpdateContractTemplate(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+
+	var req struct {
+		Template string `json:"template"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	tenant, err := h.tenantRepo.GetByID(c.UserContext(), tenantID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return response.Error(c, fiber.StatusNotFound, "Tenant not found")
+		}
+		return response.Fail(c, err)
+	}
+
+	tenant.ContractTemplate = req.Template
+	if err := h.tenantRepo.Update(c.UserContext(), tenant); err != nil {
+		return response.Fail(c, err)
+	}
+
+	return response.OK(c, fiber.Map{"template": tenant.ContractTemplate})
+}
+
+func agreementError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrContractNotFound, domain.ErrInvalidID:
+		return response.Error(c, fiber.StatusNotFound, "Contract not found")
+	case domain.ErrForbidden:
+		return response.Error(c, fiber.StatusForbidden, "Contract does not belong to you")
+	case domain.ErrAgreementAlreadySigned:
+		return response.FailAs(c, fiber.StatusConflict, err)
+	case domain.ErrSignatureRequired, domain.ErrInvalidSignatureImage, domain.ErrSignatureImageNotKept:
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	return response.Fail(c, err)
+}
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/mansoorceksport/metamorph/internal/middleware"
+	"github.com/mansoorceksport/metamorph/internal/response"
 	"github.com/mansoorceksport/metamorph/internal/service"
 )
 
@@ -12,24 +14,25 @@ import (
 type AnalyticsHandler struct {
 	analyticsService *service.AnalyticsService
 	trendService     *service.TrendService
+	ptService        *service.PTService // For the metrics the member's coach hides from them
 }
 
 // NewAnalyticsHandler creates a new analytics handler
-func NewAnalyticsHandler(analyticsService *service.AnalyticsService, trendService *service.TrendService) *AnalyticsHandler {
+func NewAnalyticsHandler(analyticsService *service.AnalyticsService, trendService *service.TrendService, ptService *service.PTService) *AnalyticsHandler {
 	return &AnalyticsHandler{
 		analyticsService: analyticsService,
 		trendService:     trendService,
+		ptService:        ptService,
 	}
 }
 
 // GetHistory handles GET /v1/analytics/history
+// Served from cache; "freshness" says when it was computed and whether a newer scan is
+// still being folded in.
 func (h *AnalyticsHandler) GetHistory(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
 	if userID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"error":   "user not authenticated",
-		})
+		return response.Error(c, fiber.StatusUnauthorized, "user not authenticated")
 	}
 
 	// Parse limit query parameter (default: 10)
@@ -44,17 +47,15 @@ func (h *AnalyticsHandler) GetHistory(c *fiber.Ctx) error {
 		limit = 100
 	}
 
-	history, err := h.analyticsService.GetHistory(c.UserContext(), userID, limit)
+	history, freshness, err := h.analyticsService.GetCachedHistory(c.UserContext(), userID, limit)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "failed to retrieve analytics history: " + err.Error(),
-		})
+		return response.Fail(c, fmt.Errorf("failed to retrieve analytics history: %w", err))
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"data":    history,
+	visibility := memberVisibility(c, h.ptService, userID)
+	return response.WithMeta(c, fiber.StatusOK, visibility.History(history), fiber.Map{
+		"freshness":      freshness,
+		"hidden_metrics": visibility.HiddenMetrics(),
 	})
 }
 
@@ -62,22 +63,13 @@ func (h *AnalyticsHandler) GetHistory(c *fiber.Ctx) error {
 func (h *AnalyticsHandler) GetRecap(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
 	if userID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"error":   "user not authenticated",
-		})
+		return response.Error(c, fiber.StatusUnauthorized, "user not authenticated")
 	}
 
 	recap, err := h.trendService.GenerateTrendRecap(c.UserContext(), userID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "failed to generate trend recap: " + err.Error(),
-		})
+		return response.Fail(c, fmt.Errorf("failed to generate trend recap: %w", err))
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"data":    recap,
-	})
+	return response.OK(c, recap)
 }
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// AssessmentHandler records fitness assessments and serves their history
+type AssessmentHandler struct {
+	assessmentService *service.AssessmentService
+	userRepo          domain.UserRepository
+}
+
+func NewAssessmentHandler(assessmentService *service.AssessmentService, userRepo domain.UserRepository) *AssessmentHandler {
+	return &AssessmentHandler{assessmentService: assessmentService, userRepo: userRepo}
+}
+
+// RecordAssessmentRequest is the body of POST /v1/pro/members/:id/assessments. Leave out
+// the tests the member didn't take.
+type RecordAssessmentRequest struct {
+	ScheduleID    string    `json:"schedule_id"` // Optional: the assessment session
+	TakenAt       time.Time `json:"taken_at"`    // Optional: defaults to the session start, or now
+	PushUpMax     *int      `json:"push_up_max"`
+	PlankSeconds  *int      `json:"plank_seconds"`
+	SitAndReachCM *float64  `json:"sit_and_reach_cm"`
+	RestingHR     *int      `json:"resting_hr"`
+	Notes         string    `json:"notes"`
+}
+
+// RecordAssessment POST /v1/pro/members/:id/assessments
+func (h *AssessmentHandler) RecordAssessment(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	coachID, _ := c.Locals("userID").(string)
+	memberID := c.Params("id")
+
+	var req RecordAssessmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	member, err := h.userRepo.GetByID(c.UserContext(), memberID)
+	if err != nil {
+		return assessmentError(c, err)
+	}
+	if _, err := member.ScopedTo(tenantID); err != nil {
+		return response.Fail(c, domain.ErrTenantScopeViolation)
+	}
+
+	assessment := &domain.Assessment{
+		TenantID:      tenantID,
+		MemberID:      memberID,
+		ScheduleID:    req.ScheduleID,
+		TakenAt:       req.TakenAt,
+		PushUpMax:     req.PushUpMax,
+		PlankSeconds:  req.PlankSeconds,
+		SitAndReachCM: req.SitAndReachCM,
+		RestingHR:     req.RestingHR,
+		Notes:         req.Notes,
+	}
+	if err := h.assessmentService.Record(c.UserContext(), coachID, assessment); err != nil {
+		return assessmentError(c, err)
+	}
+	return response.Created(c, assessment)
+}
+
+// GetMemberAssessments GET /v1/pro/members/:id/assessments
+func (h *AssessmentHandler) GetMemberAssessments(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	return h.history(c, tenantID, c.Params("id"))
+}
+
+// GetMyAssessments GET /v1/me/assessments
+func (h *AssessmentHandler) GetMyAssessments(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("userID").(string)
+	return h.history(c, tenantID, userID)
+}
+
+func (h *AssessmentHandler) history(c *fiber.Ctx, tenantID, memberID string) error {
+	assessments, trends, err := h.assessmentService.History(c.UserContext(), tenantID, memberID)
+	if err != nil {
+		return assessmentError(c, err)
+	}
+	return response.WithMeta(c, fiber.StatusOK, assessments, fiber.Map{"trends": trends})
+}
+
+func assessmentError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return response.Error(c, fiber.StatusNotFound, "Member not found")
+	case errors.Is(err, domain.ErrScheduleNotFound), errors.Is(err, domain.ErrInvalidID):
+		return response.Error(c, fiber.StatusNotFound, "Schedule not found")
+	case errors.Is(err, domain.ErrForbidden):
+		return response.Error(c, fiber.StatusForbidden, "The session belongs to another coach or member")
+	case errors.Is(err, domain.ErrInvalidAssessment):
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	return response.Fail(c, err)
+}
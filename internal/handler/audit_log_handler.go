@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+)
+
+// AuditLogHandler serves the audit log of mutating requests to tenant admins and the platform
+type AuditLogHandler struct {
+	repo domain.AuditLogRepository
+}
+
+func NewAuditLogHandler(repo domain.AuditLogRepository) *AuditLogHandler {
+	return &AuditLogHandler{repo: repo}
+}
+
+// ListTenantLogs GET /v1/tenant-admin/audit-logs
+// Query: actor_id, entity_type, entity_id, from, to, limit, cursor
+func (h *AuditLogHandler) ListTenantLogs(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	if tenantID == "" {
+		return response.Error(c, fiber.StatusForbidden, "No tenant selected")
+	}
+	return h.list(c, tenantID)
+}
+
+// ListPlatformLogs GET /v1/platform/audit-logs
+// Query: tenant_id (all tenants if empty), actor_id, entity_type, entity_id, from, to, limit, cursor
+func (h *AuditLogHandler) ListPlatformLogs(c *fiber.Ctx) error {
+	return h.list(c, c.Query("tenant_id"))
+}
+
+func (h *AuditLogHandler) list(c *fiber.Ctx, tenantID string) error {
+	page, _ := pageQuery(c)
+	q := domain.AuditLogQuery{
+		TenantID:   tenantID,
+		ActorID:    c.Query("actor_id"),
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+		Page:       page,
+	}
+
+	var ok bool
+	if q.From, ok = auditTime(c.Query("from"), false); !ok {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid from, use RFC3339 or YYYY-MM-DD")
+	}
+	if q.To, ok = auditTime(c.Query("to"), true); !ok {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid to, use RFC3339 or YYYY-MM-DD")
+	}
+
+	logs, err := h.repo.List(c.UserContext(), q)
+	if err != nil {
+		if err == domain.ErrInvalidAuditLogQuery {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		}
+		return pageError(c, err)
+	}
+	return response.OK(c, logs)
+}
+
+// auditTime parses a from or to bound given as RFC3339 or a date. A date as the to bound
+// includes that whole day (UTC). ok is false if raw is set but invalid.
+func auditTime(raw string, endOfDay bool) (t *time.Time, ok bool) {
+	if raw == "" {
+		return nil, true
+	}
+	if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &parsed, true
+	}
+	parsed, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return nil, false
+	}
+	if endOfDay {
+		parsed = parsed.AddDate(0, 0, 1)
+	}
+	return &parsed, true
+}
@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
 	"github.com/mansoorceksport/metamorph/internal/service"
 )
 
@@ -21,14 +24,39 @@ func NewAuthHandler(authService *service.AuthService, tokenService *service.Toke
 	}
 }
 
+// TenantRequest picks the tenant a token is scoped to
+type TenantRequest struct {
+	TenantID string `json:"tenant_id"`
+}
+
+// TokenUser is who an access token was issued to
+type TokenUser struct {
+	ID        string   `json:"id"`
+	Roles     []string `json:"roles"`
+	TenantID  string   `json:"tenant_id"`
+	TenantIDs []string `json:"tenant_ids,omitempty"` // Every tenant the user may switch to; on login
+}
+
+// TokenResponse carries an access token. The refresh token is set as an httpOnly cookie.
+type TokenResponse struct {
+	Token     string     `json:"token"`
+	ExpiresIn int64      `json:"expires_in"` // Seconds
+	User      *TokenUser `json:"user,omitempty"`
+}
+
+// LoginResponse is the body of POST /v1/auth/login
+type LoginResponse struct {
+	TokenResponse
+	IsNewUser bool   `json:"is_new_user"`
+	Message   string `json:"message"`
+}
+
 // LoginOrRegister handles POST /v1/auth/login
 func (h *AuthHandler) LoginOrRegister(c *fiber.Ctx) error {
 	// Get Firebase token from Authorization header
 	authHeader := c.Get("Authorization")
 	if authHeader == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Missing Authorization header",
-		})
+		return response.Error(c, fiber.StatusUnauthorized, "Missing Authorization header")
 	}
 
 	// Extract token (format: "Bearer <token>")
@@ -37,25 +65,37 @@ func (h *AuthHandler) LoginOrRegister(c *fiber.Ctx) error {
 		token = authHeader[7:]
 	}
 
+	// Users in several tenants may pick the one to work in; the primary tenant otherwise
+	var req TenantRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
 	// Call auth service to login/register
 	resp, err := h.authService.LoginOrRegister(c.Context(), service.LoginOrRegisterRequest{
 		FirebaseToken: token,
 	})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return response.Fail(c, err)
+	}
+
+	user, err := resp.User.ScopedTo(req.TenantID)
+	if err != nil {
+		return response.Error(c, fiber.StatusForbidden, "You are not a member of this tenant")
 	}
 
 	// Generate token pair (access + refresh)
 	userAgent := c.Get("User-Agent")
 	ipAddress := c.IP()
 
-	tokenPair, err := h.tokenService.GenerateTokenPair(c.Context(), resp.User, userAgent, ipAddress)
+	tokenPair, err := h.tokenService.GenerateScopedTokenPair(c.Context(), resp.User, req.TenantID, userAgent, ipAddress)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to generate tokens: " + err.Error(),
-		})
+		if err == domain.ErrTenantDeactivated {
+			return response.FailAs(c, fiber.StatusForbidden, err)
+		}
+		return response.Fail(c, fmt.Errorf("failed to generate tokens: %w", err))
 	}
 
 	// Set refresh token as httpOnly cookie
@@ -70,16 +110,67 @@ func (h *AuthHandler) LoginOrRegister(c *fiber.Ctx) error {
 	})
 
 	// Return response with access token
-	return c.JSON(fiber.Map{
-		"token":       tokenPair.AccessToken,
-		"expires_in":  tokenPair.ExpiresIn,
-		"is_new_user": resp.IsNewUser,
-		"message":     h.getWelcomeMessage(resp),
-		"user": fiber.Map{
-			"id":        resp.User.ID,
-			"roles":     resp.User.Roles,
-			"tenant_id": resp.User.TenantID,
+	return response.OK(c, LoginResponse{
+		TokenResponse: TokenResponse{
+			Token:     tokenPair.AccessToken,
+			ExpiresIn: tokenPair.ExpiresIn,
+			User: &TokenUser{
+				ID:        user.ID,
+				Roles:     user.Roles,
+				TenantID:  user.TenantID,
+				TenantIDs: resp.User.TenantIDs(),
+			},
 		},
+		IsNewUser: resp.IsNewUser,
+		Message:   h.getWelcomeMessage(resp),
+	})
+}
+
+// SwitchTenant handles POST /v1/auth/switch-tenant
+// Issues a new token pair scoped to another tenant the user belongs to
+func (h *AuthHandler) SwitchTenant(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req TenantRequest
+	if err := c.BodyParser(&req); err != nil || req.TenantID == "" {
+		return response.Error(c, fiber.StatusBadRequest, "tenant_id is required")
+	}
+
+	tokenPair, user, err := h.tokenService.SwitchTenant(c.Context(), userID, req.TenantID, c.Get("User-Agent"), c.IP())
+	if err != nil {
+		if err == domain.ErrNotTenantMember {
+			return response.Error(c, fiber.StatusForbidden, "You are not a member of this tenant")
+		}
+		if err == domain.ErrTenantDeactivated {
+			return response.FailAs(c, fiber.StatusForbidden, err)
+		}
+		if err == domain.ErrNotFound {
+			return response.Error(c, fiber.StatusNotFound, "User not found")
+		}
+		return response.Fail(c, err)
+	}
+
+	// The old session is replaced by the one for the new tenant
+	if oldToken := c.Cookies("metamorph-refresh-token"); oldToken != "" {
+		_ = h.tokenService.RevokeRefreshToken(c.Context(), oldToken)
+	}
+	c.Cookie(&fiber.Cookie{
+		Name:     "metamorph-refresh-token",
+		Value:    tokenPair.RefreshToken,
+		Expires:  time.Now().Add(7 * 24 * time.Hour),
+		HTTPOnly: true,
+		Secure:   false,
+		SameSite: "Lax",
+		Path:     "/",
+	})
+
+	return response.OK(c, TokenResponse{
+		Token:     tokenPair.AccessToken,
+		ExpiresIn: tokenPair.ExpiresIn,
+		User:      &TokenUser{ID: user.ID, Roles: user.Roles, TenantID: user.TenantID},
 	})
 }
 
@@ -88,9 +179,7 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 	// Get refresh token from httpOnly cookie
 	refreshToken := c.Cookies("metamorph-refresh-token")
 	if refreshToken == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "No refresh token provided",
-		})
+		return response.Error(c, fiber.StatusUnauthorized, "No refresh token provided")
 	}
 
 	userAgent := c.Get("User-Agent")
@@ -110,9 +199,7 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 			Path:     "/",
 		})
 
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Invalid or expired refresh token",
-		})
+		return response.Error(c, fiber.StatusUnauthorized, "Invalid or expired refresh token")
 	}
 
 	// Set new refresh token cookie
@@ -127,10 +214,7 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 	})
 
 	// Return new access token
-	return c.JSON(fiber.Map{
-		"token":      tokenPair.AccessToken,
-		"expires_in": tokenPair.ExpiresIn,
-	})
+	return response.OK(c, TokenResponse{Token: tokenPair.AccessToken, ExpiresIn: tokenPair.ExpiresIn})
 }
 
 // Logout handles POST /v1/auth/logout
@@ -153,9 +237,7 @@ func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 		Path:     "/",
 	})
 
-	return c.JSON(fiber.Map{
-		"message": "Logged out successfully",
-	})
+	return response.OK(c, MessageResponse{Message: "Logged out successfully"})
 }
 
 func (h *AuthHandler) getWelcomeMessage(resp *service.LoginOrRegisterResponse) string {
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// CustomFieldHandler serves the fields a tenant tracks on its members and contracts.
+// Member values are set through the user and member endpoints (custom_fields).
+type CustomFieldHandler struct {
+	customFields *service.CustomFieldService
+}
+
+func NewCustomFieldHandler(customFields *service.CustomFieldService) *CustomFieldHandler {
+	return &CustomFieldHandler{customFields: customFields}
+}
+
+// CustomFieldSchemaBody is the tenant's custom fields, as read and replaced
+type CustomFieldSchemaBody struct {
+	Fields domain.CustomFieldSchema `json:"fields"`
+}
+
+// CustomFieldsRequest changes a record's custom field values, by key
+type CustomFieldsRequest struct {
+	CustomFields map[string]interface{} `json:"custom_fields"`
+}
+
+// GetSchema GET /v1/tenant-admin/custom-fields
+func (h *CustomFieldHandler) GetSchema(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+
+	schema, err := h.customFields.Schema(c.UserContext(), tenantID)
+	if err != nil {
+		return customFieldError(c, err)
+	}
+	if schema == nil {
+		schema = domain.CustomFieldSchema{}
+	}
+	return response.OK(c, CustomFieldSchemaBody{Fields: schema})
+}
+
+// UpdateSchema PUT /v1/tenant-admin/custom-fields
+// Body: {"fields": [{"key": "locker_number", "label": "Locker", "entity": "member",
+// "type": "text"}, ...]} replacing every field. Types: text (max_length), number (min, max),
+// date (YYYY-MM-DD), boolean and select (options).
+func (h *CustomFieldHandler) UpdateSchema(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+
+	var req CustomFieldSchemaBody
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	schema, err := h.customFields.SetSchema(c.UserContext(), tenantID, req.Fields)
+	if err != nil {
+		return customFieldError(c, err)
+	}
+	if schema == nil {
+		schema = domain.CustomFieldSchema{}
+	}
+	return response.OK(c, CustomFieldSchemaBody{Fields: schema})
+}
+
+// UpdateContractFields PUT /v1/tenant-admin/contracts/:id/custom-fields
+// Body: {"custom_fields": {"insurance_id": "AX-1234", "referral": null}}. Fields left out
+// keep their value; null clears one.
+func (h *CustomFieldHandler) UpdateContractFields(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+
+	var req CustomFieldsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	contract, err := h.customFields.SetContractFields(c.UserContext(), tenantID, c.Params("id"), req.CustomFields)
+	if err != nil {
+		return customFieldError(c, err)
+	}
+	return response.OK(c, contract)
+}
+
+func customFieldError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidCustomFieldSchema), errors.Is(err, domain.ErrInvalidCustomFields):
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	case errors.Is(err, domain.ErrContractNotFound), errors.Is(err, domain.ErrInvalidID):
+		return response.Error(c, fiber.StatusNotFound, "Contract not found")
+	case errors.Is(err, domain.ErrNotFound):
+		return response.Error(c, fiber.StatusNotFound, "Tenant not found")
+	}
+	return response.Fail(c, err)
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+type DemoHandler struct {
+	demoService *service.DemoService
+}
+
+func NewDemoHandler(demoService *service.DemoService) *DemoHandler {
+	return &DemoHandler{demoService: demoService}
+}
+
+// SeedDemo POST /v1/platform/tenants/:id/seed-demo
+func (h *DemoHandler) SeedDemo(c *fiber.Ctx) error {
+	summary, err := h.demoService.SeedDemo(c.UserContext(), c.Params("id"))
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return response.Error(c, fiber.StatusNotFound, "Tenant not found")
+		}
+		if err == domain.ErrDemoDataExists {
+			return response.Error(c, fiber.StatusConflict, "Tenant already has demo data; delete it first")
+		}
+		return response.Fail(c, err)
+	}
+	return response.Created(c, summary)
+}
+
+// DeleteDemo DELETE /v1/platform/tenants/:id/demo-data
+// Removes only records flagged as demo data
+func (h *DemoHandler) DeleteDemo(c *fiber.Ctx) error {
+	summary, err := h.demoService.DeleteDemo(c.UserContext(), c.Params("id"))
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return response.Error(c, fiber.StatusNotFound, "Tenant not found")
+		}
+		return response.Fail(c, err)
+	}
+	return response.OK(c, summary)
+}
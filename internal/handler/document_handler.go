@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+type DocumentHandler struct {
+	documentService *service.DocumentService
+	maxUploadMB     int64
+}
+
+func NewDocumentHandler(documentService *service.DocumentService, maxUploadMB int64) *DocumentHandler {
+	return &DocumentHandler{
+		documentService: documentService,
+		maxUploadMB:     maxUploadMB,
+	}
+}
+
+// --- Tenant Admin ---
+
+// CreateDocument POST /v1/tenant-admin/documents (multipart: title, description, required, blocks_booking, file)
+func (h *DocumentHandler) CreateDocument(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+
+	title := c.FormValue("title")
+	if title == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Document title is required")
+	}
+
+	file, filename, contentType, err := h.readFile(c)
+	if err != nil {
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+
+	doc := &domain.Document{
+		TenantID:      tenantID,
+		Title:         title,
+		Description:   c.FormValue("description"),
+		Required:      formBool(c, "required", true),
+		BlocksBooking: formBool(c, "blocks_booking", false),
+	}
+
+	if err := h.documentService.CreateDocument(c.UserContext(), doc, file, filename, contentType); err != nil {
+		return response.Fail(c, err)
+	}
+
+	return response.Created(c, doc)
+}
+
+// ListDocuments GET /v1/tenant-admin/documents
+func (h *DocumentHandler) ListDocuments(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+
+	docs, err := h.documentService.ListDocuments(c.UserContext(), tenantID)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, docs)
+}
+
+// UpdateDocument PUT /v1/tenant-admin/documents/:id (multipart, all fields optional)
+func (h *DocumentHandler) UpdateDocument(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+
+	doc, err := h.getTenantDocument(c, tenantID)
+	if err != nil {
+		if err == domain.ErrDocumentNotFound {
+			return response.Error(c, fiber.StatusNotFound, "Document not found")
+		}
+		return response.Fail(c, err)
+	}
+
+	if title := c.FormValue("title"); title != "" {
+		doc.Title = title
+	}
+	if description := c.FormValue("description"); description != "" {
+		doc.Description = description
+	}
+	doc.Required = formBool(c, "required", doc.Required)
+	doc.BlocksBooking = formBool(c, "blocks_booking", doc.BlocksBooking)
+	doc.Active = formBool(c, "active", doc.Active)
+
+	file, filename, contentType, err := h.readFile(c)
+	if err != nil {
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+
+	if err := h.documentService.UpdateDocument(c.UserContext(), doc, file, filename, contentType); err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, doc)
+}
+
+// DeleteDocument DELETE /v1/tenant-admin/documents/:id
+// Deactivates the document so existing acceptances remain on record
+func (h *DocumentHandler) DeleteDocument(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+
+	doc, err := h.getTenantDocument(c, tenantID)
+	if err != nil {
+		if err == domain.ErrDocumentNotFound {
+			return response.Error(c, fiber.StatusNotFound, "Document not found")
+		}
+		return response.Fail(c, err)
+	}
+
+	doc.Active = false
+	if err := h.documentService.UpdateDocument(c.UserContext(), doc, nil, "", ""); err != nil {
+		return response.Fail(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// --- Member ---
+
+// GetMyDocuments GET /v1/me/documents
+func (h *DocumentHandler) GetMyDocuments(c *fiber.Ctx) error {
+	memberID, ok := c.Locals("userID").(string)
+	if !ok || memberID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing user context")
+	}
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	compliance, err := h.documentService.GetCompliance(c.UserContext(), tenantID, memberID)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, compliance)
+}
+
+// AcceptMyDocument POST /v1/me/documents/:id/accept
+func (h *DocumentHandler) AcceptMyDocument(c *fiber.Ctx) error {
+	memberID, ok := c.Locals("userID").(string)
+	if !ok || memberID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing user context")
+	}
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	var req struct {
+		TypedName string `json:"typed_name"`
+		Consent   bool   `json:"consent"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if !req.Consent {
+		return response.Error(c, fiber.StatusBadRequest, "Consent must be given to accept the document")
+	}
+
+	acceptance := &domain.DocumentAcceptance{
+		TenantID:   tenantID,
+		DocumentID: c.Params("id"),
+		MemberID:   memberID,
+		TypedName:  req.TypedName,
+		IPAddress:  c.IP(),
+		UserAgent:  c.Get(fiber.HeaderUserAgent),
+	}
+
+	if err := h.documentService.AcceptDocument(c.UserContext(), acceptance); err != nil {
+		if err == domain.ErrDocumentNotFound || err == domain.ErrInvalidID {
+			return response.Error(c, fiber.StatusNotFound, "Document not found")
+		}
+		return response.Fail(c, err)
+	}
+	return response.OK(c, acceptance)
+}
+
+// --- Helpers ---
+
+func (h *DocumentHandler) getTenantDocument(c *fiber.Ctx, tenantID string) (*domain.Document, error) {
+	doc, err := h.documentService.GetDocument(c.UserContext(), c.Params("id"))
+	if err != nil {
+		if err == domain.ErrInvalidID {
+			return nil, domain.ErrDocumentNotFound
+		}
+		return nil, err
+	}
+	if doc.TenantID != tenantID {
+		return nil, domain.ErrDocumentNotFound
+	}
+	return doc, nil
+}
+
+// readFile returns the optional "file" form field
+func (h *DocumentHandler) readFile(c *fiber.Ctx) ([]byte, string, string, error) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return nil, "", "", nil
+	}
+	if fileHeader.Size > h.maxUploadMB*1024*1024 {
+		return nil, "", "", fmt.Errorf("file size exceeds maximum of %dMB", h.maxUploadMB)
+	}
+
+	f, err := fileHeader.Open()
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to read file")
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to read file")
+	}
+	return data, fileHeader.Filename, fileHeader.Header.Get("Content-Type"), nil
+}
+
+func formBool(c *fiber.Ctx, key string, fallback bool) bool {
+	v := c.FormValue(key)
+	if v == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fallback
+	}
+	return b
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+type EquipmentHandler struct {
+	equipmentService *service.EquipmentService
+}
+
+func NewEquipmentHandler(equipmentService *service.EquipmentService) *EquipmentHandler {
+	return &EquipmentHandler{equipmentService: equipmentService}
+}
+
+type equipmentRequest struct {
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+	Available *bool  `json:"available"` // Defaults to true
+}
+
+// ListBranchEquipment GET /v1/tenant-admin/branches/:id/equipment
+func (h *EquipmentHandler) ListBranchEquipment(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	items, err := h.equipmentService.ListBranchEquipment(c.UserContext(), tenantID, c.Params("id"))
+	if err != nil {
+		return equipmentError(c, err)
+	}
+	return response.OK(c, items)
+}
+
+// AddBranchEquipment POST /v1/tenant-admin/branches/:id/equipment
+func (h *EquipmentHandler) AddBranchEquipment(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	var req equipmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	item := &domain.Equipment{
+		BranchID:  c.Params("id"),
+		Name:      req.Name,
+		Quantity:  req.Quantity,
+		Available: req.Available == nil || *req.Available,
+	}
+	if err := h.equipmentService.AddEquipment(c.UserContext(), tenantID, item); err != nil {
+		return equipmentError(c, err)
+	}
+	return response.Created(c, item)
+}
+
+// UpdateEquipment PUT /v1/tenant-admin/equipment/:id
+func (h *EquipmentHandler) UpdateEquipment(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	var req equipmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	item, err := h.equipmentService.UpdateEquipment(c.UserContext(), tenantID, &domain.Equipment{
+		ID:        c.Params("id"),
+		Name:      req.Name,
+		Quantity:  req.Quantity,
+		Available: req.Available == nil || *req.Available,
+	})
+	if err != nil {
+		return equipmentError(c, err)
+	}
+	return response.OK(c, item)
+}
+
+// DeleteEquipment DELETE /v1/tenant-admin/equipment/:id
+func (h *EquipmentHandler) DeleteEquipment(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	if err := h.equipmentService.DeleteEquipment(c.UserContext(), tenantID, c.Params("id")); err != nil {
+		return equipmentError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+type maintenanceRequest struct {
+	Kind  string `json:"kind"`  // out_of_order, repaired or serviced
+	Units int    `json:"units"` // Defaults to 1 for breakdowns and repairs
+	Note  string `json:"note"`
+}
+
+// LogMaintenance POST /v1/tenant-admin/equipment/:id/maintenance
+func (h *EquipmentHandler) LogMaintenance(c *fiber.Ctx) error {
+	var req maintenanceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	return h.logMaintenance(c, &domain.EquipmentMaintenance{Kind: req.Kind, Units: req.Units, Note: req.Note})
+}
+
+// ReportOutOfOrder POST /v1/pro/equipment/:id/out-of-order
+// Coaches flag broken kit on the floor; repairs are logged by the tenant admin
+func (h *EquipmentHandler) ReportOutOfOrder(c *fiber.Ctx) error {
+	var req maintenanceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	return h.logMaintenance(c, &domain.EquipmentMaintenance{Kind: domain.MaintenanceOutOfOrder, Units: req.Units, Note: req.Note})
+}
+
+func (h *EquipmentHandler) logMaintenance(c *fiber.Ctx, entry *domain.EquipmentMaintenance) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("userID").(string)
+	entry.EquipmentID = c.Params("id")
+
+	item, err := h.equipmentService.LogMaintenance(c.UserContext(), tenantID, userID, entry)
+	if err != nil {
+		return equipmentError(c, err)
+	}
+	return response.Created(c, fiber.Map{"entry": entry, "equipment": item})
+}
+
+// GetMaintenanceLog GET /v1/tenant-admin/equipment/:id/maintenance
+func (h *EquipmentHandler) GetMaintenanceLog(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	entries, err := h.equipmentService.MaintenanceLog(c.UserContext(), tenantID, c.Params("id"))
+	if err != nil {
+		return equipmentError(c, err)
+	}
+	return response.OK(c, entries)
+}
+
+// GetMaintenanceReport GET /v1/tenant-admin/reports/equipment-maintenance?from=&to=&branch_id=
+// Equipment out of order now and the maintenance logged in the requested days, the last
+// 28 by default
+func (h *EquipmentHandler) GetMaintenanceReport(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -28)
+	if s := c.Query("from"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid 'from' date format, use YYYY-MM-DD")
+		}
+		from = d
+	}
+	if s := c.Query("to"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid 'to' date format, use YYYY-MM-DD")
+		}
+		to = d.AddDate(0, 0, 1) // Include the whole day
+	}
+	if !from.Before(to) {
+		return response.Error(c, fiber.StatusBadRequest, "'from' must not be after 'to'")
+	}
+
+	report, err := h.equipmentService.MaintenanceReport(c.UserContext(), tenantID, c.Query("branch_id"), from, to)
+	if err != nil {
+		return equipmentError(c, err)
+	}
+	return response.OK(c, report)
+}
+
+// GetSubstitutes GET /v1/pro/exercises/:id/substitutes?branch_id=
+// Lists same-muscle-group exercises the branch has the equipment for
+func (h *EquipmentHandler) GetSubstitutes(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	branchID := c.Query("branch_id")
+	if branchID == "" {
+		return response.Error(c, fiber.StatusBadRequest, "branch_id is required")
+	}
+
+	supported, substitutes, err := h.equipmentService.Substitutes(c.UserContext(), tenantID, c.Params("id"), branchID)
+	if err != nil {
+		return equipmentError(c, err)
+	}
+	return response.OK(c, fiber.Map{
+		"exercise_id":         c.Params("id"),
+		"branch_id":           branchID,
+		"equipment_available": supported,
+		"substitutes":         substitutes,
+	})
+}
+
+func equipmentError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrNotFound, domain.ErrInvalidID:
+		return response.Error(c, fiber.StatusNotFound, "Branch not found")
+	case domain.ErrEquipmentNotFound, domain.ErrExerciseNotFound:
+		return response.FailAs(c, fiber.StatusNotFound, err)
+	case domain.ErrInvalidEquipment:
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	case domain.ErrDuplicateEquipment:
+		return response.FailAs(c, fiber.StatusConflict, err)
+	}
+	if errors.Is(err, domain.ErrInvalidMaintenance) {
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	return response.Fail(c, err)
+}
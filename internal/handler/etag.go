@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ifMatchVersion parses the record version from an If-Match header ("3", "\"3\"" or "W/\"3\"").
+// ok is false when the header is absent.
+func ifMatchVersion(c *fiber.Ctx) (version int64, ok bool, err error) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" {
+		return 0, false, nil
+	}
+	header = strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	version, err = strconv.ParseInt(header, 10, 64)
+	if err != nil || version < 0 {
+		return 0, false, fmt.Errorf("invalid If-Match header")
+	}
+	return version, true, nil
+}
+
+// setETag exposes the record version so clients can send it back in If-Match
+func setETag(c *fiber.Ctx, version int64) {
+	c.Set(fiber.HeaderETag, fmt.Sprintf(`"%d"`, version))
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+type ExerciseMergeHandler struct {
+	mergeService *service.ExerciseMergeService
+}
+
+func NewExerciseMergeHandler(mergeService *service.ExerciseMergeService) *ExerciseMergeHandler {
+	return &ExerciseMergeHandler{mergeService: mergeService}
+}
+
+// ListDuplicates GET /v1/platform/exercises/duplicates?threshold=0.8
+// Pairs of catalog exercises with similar names, most similar first
+func (h *ExerciseMergeHandler) ListDuplicates(c *fiber.Ctx) error {
+	threshold := 0.0
+	if raw := c.Query("threshold"); raw != "" {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || v <= 0 || v > 1 {
+			return response.Error(c, fiber.StatusBadRequest, "threshold must be between 0 and 1")
+		}
+		threshold = v
+	}
+
+	pairs, err := h.mergeService.Duplicates(c.UserContext(), threshold)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, pairs)
+}
+
+// MergeExercises POST /v1/platform/exercises/merge
+// Body: canonical_id, duplicate_ids. The duplicates are deleted once everything pointing
+// at them has moved to the canonical exercise.
+func (h *ExerciseMergeHandler) MergeExercises(c *fiber.Ctx) error {
+	actorID, _ := c.Locals("userID").(string)
+
+	var req struct {
+		CanonicalID  string   `json:"canonical_id"`
+		DuplicateIDs []string `json:"duplicate_ids"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	merge, err := h.mergeService.Merge(c.UserContext(), actorID, req.CanonicalID, req.DuplicateIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidExerciseMerge):
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		case errors.Is(err, domain.ErrExerciseNotFound), errors.Is(err, domain.ErrInvalidID):
+			return response.FailAs(c, fiber.StatusNotFound, err)
+		}
+		return response.Fail(c, err)
+	}
+	return response.OK(c, merge)
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// ExerciseVideoHandler serves the demonstration clips hosted for exercises
+type ExerciseVideoHandler struct {
+	videoService *service.ExerciseVideoService
+}
+
+func NewExerciseVideoHandler(videoService *service.ExerciseVideoService) *ExerciseVideoHandler {
+	return &ExerciseVideoHandler{videoService: videoService}
+}
+
+// UploadVideo POST /v1/exercises/:id/video (multipart, field "video")
+func (h *ExerciseVideoHandler) UploadVideo(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	fileHeader, err := c.FormFile("video")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "video file is required")
+	}
+	f, err := fileHeader.Open()
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "failed to read file")
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "failed to read file")
+	}
+
+	ex, err := h.videoService.Upload(c.UserContext(), userID, c.Params("id"), data, fileHeader.Header.Get("Content-Type"))
+	if err != nil {
+		return exerciseVideoError(c, err)
+	}
+	return response.Created(c, ex)
+}
+
+// DeleteVideo DELETE /v1/exercises/:id/video
+// The exercise falls back to its video_url.
+func (h *ExerciseVideoHandler) DeleteVideo(c *fiber.Ctx) error {
+	ex, err := h.videoService.Remove(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return exerciseVideoError(c, err)
+	}
+	return response.OK(c, ex)
+}
+
+// PlayVideo GET /v1/exercises/:id/video
+// Redirects to a signed URL of the hosted clip, or to the exercise's video_url.
+func (h *ExerciseVideoHandler) PlayVideo(c *fiber.Ctx) error {
+	url, err := h.videoService.PlaybackURL(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return exerciseVideoError(c, err)
+	}
+	return c.Redirect(url, fiber.StatusFound)
+}
+
+func exerciseVideoError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrExerciseNotFound, domain.ErrSetVideoNotFound, domain.ErrInvalidID:
+		return response.FailAs(c, fiber.StatusNotFound, err)
+	case domain.ErrVideoTooLarge:
+		return response.FailAs(c, fiber.StatusRequestEntityTooLarge, err)
+	case domain.ErrVideoTooLong, domain.ErrUnsupportedVideo, domain.ErrVideoResolution:
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	return response.Fail(c, err)
+}
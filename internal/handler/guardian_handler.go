@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// GuardianHandler serves members' age restrictions, the guardian consents gyms record for
+// minors, and the tenant's minor age
+type GuardianHandler struct {
+	guardians *service.GuardianService
+}
+
+func NewGuardianHandler(guardians *service.GuardianService) *GuardianHandler {
+	return &GuardianHandler{guardians: guardians}
+}
+
+// --- Coach ---
+
+// GetMinorStatus GET /v1/pro/members/:id/minor
+func (h *GuardianHandler) GetMinorStatus(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	status, err := h.guardians.Status(c.UserContext(), tenantID, c.Params("id"))
+	if err != nil {
+		return guardianError(c, err)
+	}
+	return response.OK(c, status)
+}
+
+// RecordConsent POST /v1/pro/members/:id/guardian-consent
+// Body: {"guardian_name", "relationship", "email", "phone", "note"}
+func (h *GuardianHandler) RecordConsent(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("userID").(string)
+	var req struct {
+		GuardianName string `json:"guardian_name"`
+		Relationship string `json:"relationship"`
+		Email        string `json:"email"`
+		Phone        string `json:"phone"`
+		Note         string `json:"note"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	status, err := h.guardians.RecordConsent(c.UserContext(), &domain.GuardianConsent{
+		TenantID:     tenantID,
+		MemberID:     c.Params("id"),
+		GuardianName: req.GuardianName,
+		Relationship: req.Relationship,
+		Email:        req.Email,
+		Phone:        req.Phone,
+		Note:         req.Note,
+		RecordedBy:   userID,
+	})
+	if err != nil {
+		return guardianError(c, err)
+	}
+	return response.Created(c, status)
+}
+
+// RevokeConsent DELETE /v1/pro/members/:id/guardian-consent
+// Records the guardian withdrawing consent; a minor can't be booked again until a new one
+func (h *GuardianHandler) RevokeConsent(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("userID").(string)
+	status, err := h.guardians.Revoke(c.UserContext(), tenantID, c.Params("id"), userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return response.Error(c, fiber.StatusNotFound, "No guardian consent to revoke")
+		}
+		return guardianError(c, err)
+	}
+	return response.OK(c, status)
+}
+
+// --- Tenant admin ---
+
+// GetMinorPolicy GET /v1/tenant-admin/minor-policy
+func (h *GuardianHandler) GetMinorPolicy(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	age, err := h.guardians.MinorAge(c.UserContext(), tenantID)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, fiber.Map{"minor_age": age})
+}
+
+// UpdateMinorPolicy PUT /v1/tenant-admin/minor-policy
+// Body: {"minor_age": 16}; 0 restores the default of 18
+func (h *GuardianHandler) UpdateMinorPolicy(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	var req struct {
+		MinorAge int `json:"minor_age"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	tenant, err := h.guardians.SetMinorAge(c.UserContext(), tenantID, req.MinorAge)
+	if err != nil {
+		return guardianError(c, err)
+	}
+	return response.OK(c, fiber.Map{"minor_age": tenant.MinorAgeLimit()})
+}
+
+func guardianError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrInvalidGuardianConsent, domain.ErrInvalidMinorAge:
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	case domain.ErrNotFound:
+		return response.Error(c, fiber.StatusNotFound, "Member not found")
+	case domain.ErrNotTenantMember:
+		return response.Fail(c, domain.ErrTenantScopeViolation)
+	}
+	return response.Fail(c, err)
+}
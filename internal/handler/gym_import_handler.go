@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+type GymImportHandler struct {
+	importService *service.GymImportService
+	maxUploadMB   int64
+}
+
+func NewGymImportHandler(importService *service.GymImportService, maxUploadMB int64) *GymImportHandler {
+	return &GymImportHandler{importService: importService, maxUploadMB: maxUploadMB}
+}
+
+// CreateImport POST /v1/tenant-admin/imports
+// Multipart form: source ("glofox" or "mindbody"), coach_id, optional branch_id and
+// timezone (of the exported dates, UTC by default), and the export files as members,
+// memberships and bookings. Nothing is imported yet: the response is a preview to start.
+func (h *GymImportHandler) CreateImport(c *fiber.Ctx) error {
+	req := service.GymImportRequest{
+		TenantID: c.Locals("tenant_id").(string),
+		ActorID:  c.Locals("userID").(string),
+		Source:   c.FormValue("source"),
+		CoachID:  c.FormValue("coach_id"),
+		BranchID: c.FormValue("branch_id"),
+		Location: time.UTC,
+		Files:    make(map[string][]byte),
+	}
+	if req.CoachID == "" {
+		return response.Error(c, fiber.StatusBadRequest, "coach_id is required")
+	}
+	if tz := c.FormValue("timezone"); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid timezone")
+		}
+		req.Location = loc
+	}
+
+	for _, name := range []string{domain.ImportFileMembers, domain.ImportFileMemberships, domain.ImportFileBookings} {
+		file, err := c.FormFile(name)
+		if err != nil {
+			continue // Only members is required, which the service checks
+		}
+		if file.Size > h.maxUploadMB*1024*1024 {
+			return response.Error(c, fiber.StatusBadRequest, fmt.Sprintf("%s file exceeds maximum of %dMB", name, h.maxUploadMB))
+		}
+		f, err := file.Open()
+		if err != nil {
+			return response.Error(c, fiber.StatusInternalServerError, "Failed to open uploaded file")
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return response.Error(c, fiber.StatusInternalServerError, "Failed to read uploaded file")
+		}
+		req.Files[name] = data
+	}
+
+	imp, err := h.importService.Preview(c.UserContext(), req)
+	if err != nil {
+		return gymImportError(c, err)
+	}
+	return response.Created(c, imp)
+}
+
+// ListImports GET /v1/tenant-admin/imports
+func (h *GymImportHandler) ListImports(c *fiber.Ctx) error {
+	imports, err := h.importService.List(c.UserContext(), c.Locals("tenant_id").(string))
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, imports)
+}
+
+// GetImport GET /v1/tenant-admin/imports/:id
+// Poll this to follow a running import
+func (h *GymImportHandler) GetImport(c *fiber.Ctx) error {
+	imp, err := h.importService.Get(c.UserContext(), c.Locals("tenant_id").(string), c.Params("id"))
+	if err != nil {
+		return gymImportError(c, err)
+	}
+	return response.OK(c, imp)
+}
+
+// StartImport POST /v1/tenant-admin/imports/:id/start
+func (h *GymImportHandler) StartImport(c *fiber.Ctx) error {
+	imp, err := h.importService.Start(c.UserContext(), c.Locals("tenant_id").(string), c.Params("id"))
+	if err != nil {
+		return gymImportError(c, err)
+	}
+	return response.JSON(c, fiber.StatusAccepted, imp)
+}
+
+func gymImportError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrInvalidID):
+		return response.Error(c, fiber.StatusNotFound, "Import not found")
+	case errors.Is(err, domain.ErrImportStarted):
+		return response.FailAs(c, fiber.StatusConflict, err)
+	case errors.Is(err, domain.ErrUnknownImportSource), errors.Is(err, domain.ErrInvalidImportFile),
+		errors.Is(err, domain.ErrInvalidImportCoach), errors.Is(err, domain.ErrBranchNotAllowed),
+		errors.Is(err, domain.ErrNoWorkingBranch):
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	return response.Fail(c, err)
+}
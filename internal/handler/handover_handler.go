@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// HandoverHandler serves the briefs coaches get on clients they take over, the notes those
+// briefs draw on, and moving a contract to another coach
+type HandoverHandler struct {
+	handovers *service.HandoverService
+	userRepo  domain.UserRepository
+}
+
+func NewHandoverHandler(handovers *service.HandoverService, userRepo domain.UserRepository) *HandoverHandler {
+	return &HandoverHandler{handovers: handovers, userRepo: userRepo}
+}
+
+// ListMyHandovers GET /v1/pro/handovers?pending=true
+// Handovers to the coach, newest first; pending ones aren't acknowledged yet
+func (h *HandoverHandler) ListMyHandovers(c *fiber.Ctx) error {
+	coachID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+	handovers, err := h.handovers.List(c.UserContext(), tenantID, coachID, c.QueryBool("pending"))
+	if err != nil {
+		return handoverError(c, err)
+	}
+	return response.OK(c, handovers)
+}
+
+// GetHandover GET /v1/pro/handovers/:id
+func (h *HandoverHandler) GetHandover(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+	viewer, err := h.userRepo.GetByID(c.UserContext(), userID)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, "Failed to fetch user profile")
+	}
+	if scoped, err := viewer.ScopedTo(tenantID); err == nil {
+		viewer = scoped
+	}
+	handover, err := h.handovers.Get(c.UserContext(), viewer, tenantID, c.Params("id"))
+	if err != nil {
+		return handoverError(c, err)
+	}
+	return response.OK(c, handover)
+}
+
+// AcknowledgeHandover POST /v1/pro/handovers/:id/acknowledge
+// The receiving coach confirms they read the handover
+func (h *HandoverHandler) AcknowledgeHandover(c *fiber.Ctx) error {
+	coachID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+	handover, err := h.handovers.Acknowledge(c.UserContext(), tenantID, coachID, c.Params("id"))
+	if err != nil {
+		return handoverError(c, err)
+	}
+	return response.OK(c, handover)
+}
+
+// GetClientNotes GET /v1/pro/clients/:id/notes
+func (h *HandoverHandler) GetClientNotes(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	if err := h.checkMember(c, tenantID); err != nil {
+		return handoverError(c, err)
+	}
+	notes, err := h.handovers.Notes(c.UserContext(), tenantID, c.Params("id"))
+	if err != nil {
+		return handoverError(c, err)
+	}
+	return response.OK(c, notes)
+}
+
+// UpdateClientNotes PUT /v1/pro/clients/:id/notes
+// Body: injuries and preferences, each a list that replaces the saved one
+func (h *HandoverHandler) UpdateClientNotes(c *fiber.Ctx) error {
+	coachID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+	if err := h.checkMember(c, tenantID); err != nil {
+		return handoverError(c, err)
+	}
+
+	var req struct {
+		Injuries    []string `json:"injuries"`
+		Preferences []string `json:"preferences"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	notes, err := h.handovers.UpdateNotes(c.UserContext(), coachID, tenantID, c.Params("id"), req.Injuries, req.Preferences)
+	if err != nil {
+		return handoverError(c, err)
+	}
+	return response.OK(c, notes)
+}
+
+// TransferContract POST /v1/tenant-admin/contracts/:id/transfer
+// Body: coach_id of the new coach, and a note for them. Moves the contract and its upcoming
+// sessions, and briefs the new coach.
+func (h *HandoverHandler) TransferContract(c *fiber.Ctx) error {
+	actorID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	var req struct {
+		CoachID string `json:"coach_id"`
+		Note    string `json:"note"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.CoachID == "" {
+		return response.Error(c, fiber.StatusBadRequest, "coach_id is required")
+	}
+	if len(req.Note) > domain.MaxClientNoteLength {
+		return response.Error(c, fiber.StatusBadRequest, "note is too long")
+	}
+
+	handover, err := h.handovers.TransferContract(c.UserContext(), actorID, tenantID, c.Params("id"), req.CoachID, req.Note)
+	if err != nil {
+		return handoverError(c, err)
+	}
+	return response.OK(c, handover)
+}
+
+// checkMember fails unless the :id member belongs to the tenant
+func (h *HandoverHandler) checkMember(c *fiber.Ctx, tenantID string) error {
+	member, err := h.userRepo.GetByID(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+	_, err = member.ScopedTo(tenantID)
+	return err
+}
+
+func handoverError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrHandoverNotFound), errors.Is(err, domain.ErrContractNotFound),
+		errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrInvalidID):
+		return response.FailAs(c, fiber.StatusNotFound, err)
+	case errors.Is(err, domain.ErrInvalidClientNotes):
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	case errors.Is(err, domain.ErrBranchNotAllowed), errors.Is(err, domain.ErrNotTenantMember):
+		return response.FailAs(c, fiber.StatusForbidden, err)
+	case errors.Is(err, domain.ErrInvalidContractHandoff):
+		return response.FailAs(c, fiber.StatusConflict, err)
+	}
+	return response.Fail(c, err)
+}
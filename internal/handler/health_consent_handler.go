@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// HealthConsentHandler serves members' consent to the processing of their InBody scans,
+// its status for their coaches, and the policy it is given to
+type HealthConsentHandler struct {
+	consent *service.HealthConsentService
+}
+
+func NewHealthConsentHandler(consent *service.HealthConsentService) *HealthConsentHandler {
+	return &HealthConsentHandler{consent: consent}
+}
+
+// --- Member ---
+
+// GetMyConsent GET /v1/me/health-consent
+// The member's status and the current policy; the app asks for consent unless current is true
+func (h *HealthConsentHandler) GetMyConsent(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing user context")
+	}
+	status, err := h.consent.Status(c.UserContext(), userID)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, status)
+}
+
+// GrantMyConsent POST /v1/me/health-consent
+// Body: {"policy_version": 2, "consent": true}, the version the member was shown
+func (h *HealthConsentHandler) GrantMyConsent(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing user context")
+	}
+	var req struct {
+		PolicyVersion int  `json:"policy_version"`
+		Consent       bool `json:"consent"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if !req.Consent {
+		return response.Error(c, fiber.StatusBadRequest, "Consent must be given explicitly")
+	}
+
+	status, err := h.consent.Grant(c.UserContext(), h.consentRecord(c, userID, req.PolicyVersion))
+	if err != nil {
+		if errors.Is(err, domain.ErrHealthConsentOutdated) {
+			return response.FailAs(c, fiber.StatusConflict, err)
+		}
+		return response.Fail(c, err)
+	}
+	return response.OK(c, status)
+}
+
+// WithdrawMyConsent DELETE /v1/me/health-consent
+func (h *HealthConsentHandler) WithdrawMyConsent(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing user context")
+	}
+	status, err := h.consent.Withdraw(c.UserContext(), h.consentRecord(c, userID, 0))
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, status)
+}
+
+// --- Coach ---
+
+// GetMemberConsent GET /v1/pro/members/:id/health-consent
+func (h *HealthConsentHandler) GetMemberConsent(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	status, err := h.consent.MemberStatus(c.UserContext(), tenantID, c.Params("id"))
+	if err != nil {
+		return memberConsentError(c, err)
+	}
+	return response.OK(c, status)
+}
+
+// PromptMember POST /v1/pro/members/:id/health-consent/prompt
+// Notifies a member without current consent to review the policy
+func (h *HealthConsentHandler) PromptMember(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	status, err := h.consent.Prompt(c.UserContext(), tenantID, c.Params("id"))
+	if err != nil {
+		return memberConsentError(c, err)
+	}
+	return response.OK(c, status)
+}
+
+// --- Platform ---
+
+// GetPolicy GET /v1/platform/health-data-policy
+func (h *HealthConsentHandler) GetPolicy(c *fiber.Ctx) error {
+	policy, err := h.consent.CurrentPolicy(c.UserContext())
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, policy)
+}
+
+// PublishPolicy PUT /v1/platform/health-data-policy
+// Body: {"text": "..."}. Publishes a new version, which every member must consent to again.
+func (h *HealthConsentHandler) PublishPolicy(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	var req struct {
+		Text string `json:"text"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	policy, err := h.consent.PublishPolicy(c.UserContext(), req.Text, userID)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidHealthDataPolicy:
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		case domain.ErrVersionConflict:
+			return response.FailAs(c, fiber.StatusConflict, err)
+		}
+		return response.Fail(c, err)
+	}
+	return response.Created(c, policy)
+}
+
+// --- Helpers ---
+
+func (h *HealthConsentHandler) consentRecord(c *fiber.Ctx, userID string, policyVersion int) *domain.HealthConsent {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	return &domain.HealthConsent{
+		UserID:        userID,
+		TenantID:      tenantID,
+		PolicyVersion: policyVersion,
+		IPAddress:     c.IP(),
+		UserAgent:     c.Get(fiber.HeaderUserAgent),
+	}
+}
+
+func memberConsentError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrNotFound:
+		return response.Error(c, fiber.StatusNotFound, "Member not found")
+	case domain.ErrNotTenantMember:
+		return response.Fail(c, domain.ErrTenantScopeViolation)
+	}
+	return response.Fail(c, err)
+}
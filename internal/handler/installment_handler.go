@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// InstallmentHandler serves installment plans for PT contracts: tenant admins set them up,
+// members pay them one VA at a time
+type InstallmentHandler struct {
+	installments *service.InstallmentService
+}
+
+func NewInstallmentHandler(installments *service.InstallmentService) *InstallmentHandler {
+	return &InstallmentHandler{installments: installments}
+}
+
+// CreatePlan POST /v1/tenant-admin/contracts/:id/installments
+// Body: installments [{amount, due_date}] adding up to the contract price, optional grace_days
+func (h *InstallmentHandler) CreatePlan(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	var req struct {
+		Installments []struct {
+			Amount  int64     `json:"amount"`
+			DueDate time.Time `json:"due_date"`
+		} `json:"installments"`
+		GraceDays int `json:"grace_days"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	installments := make([]domain.Installment, 0, len(req.Installments))
+	for _, inst := range req.Installments {
+		installments = append(installments, domain.Installment{Amount: inst.Amount, DueDate: inst.DueDate})
+	}
+
+	plan, err := h.installments.CreatePlan(c.UserContext(), tenantID, c.Params("id"), installments, req.GraceDays)
+	if err != nil {
+		return installmentError(c, err)
+	}
+	return response.Created(c, plan)
+}
+
+// GetPlan GET /v1/tenant-admin/contracts/:id/installments
+func (h *InstallmentHandler) GetPlan(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	plan, err := h.installments.GetPlan(c.UserContext(), tenantID, c.Params("id"))
+	if err != nil {
+		return installmentError(c, err)
+	}
+	return response.OK(c, plan)
+}
+
+// ListMyPlans GET /v1/me/payments/installments
+func (h *InstallmentHandler) ListMyPlans(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	plans, err := h.installments.MyPlans(c.UserContext(), userID)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, plans)
+}
+
+// PayNext POST /v1/me/payments/installments/:id/pay
+// Body: payment_method (BCA, Mandiri, BNI). Returns the plan with the VA for the next installment.
+func (h *InstallmentHandler) PayNext(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	var req struct {
+		PaymentMethod string `json:"payment_method"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if req.PaymentMethod != "BCA" && req.PaymentMethod != "Mandiri" && req.PaymentMethod != "BNI" {
+		return response.Error(c, fiber.StatusBadRequest, "invalid payment_method, must be BCA, Mandiri, or BNI")
+	}
+
+	plan, err := h.installments.Pay(c.UserContext(), userID, c.Params("id"), req.PaymentMethod)
+	if err != nil {
+		return installmentError(c, err)
+	}
+	return response.OK(c, plan)
+}
+
+func installmentError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrNotFound, domain.ErrContractNotFound, domain.ErrInvalidID:
+		return response.Error(c, fiber.StatusNotFound, "Installment plan not found")
+	case domain.ErrForbidden:
+		return response.FailAs(c, fiber.StatusForbidden, err)
+	case domain.ErrInvalidInstallments:
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	case domain.ErrInstallmentPlanExists, domain.ErrInstallmentPlanPaid:
+		return response.FailAs(c, fiber.StatusConflict, err)
+	}
+	return response.Fail(c, err)
+}
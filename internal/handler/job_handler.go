@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/jobs"
+	"github.com/mansoorceksport/metamorph/internal/response"
+)
+
+type JobHandler struct {
+	jobRunRepo domain.JobRunRepository
+	runner     *jobs.Runner
+}
+
+func NewJobHandler(jobRunRepo domain.JobRunRepository, runner *jobs.Runner) *JobHandler {
+	return &JobHandler{jobRunRepo: jobRunRepo, runner: runner}
+}
+
+// ListJobRuns GET /v1/platform/jobs?type=&status=&tenant_id=
+// Job history is unbounded, so this endpoint is always paginated
+func (h *JobHandler) ListJobRuns(c *fiber.Ctx) error {
+	filter := domain.JobRunFilter{
+		Type:     c.Query("type"),
+		Status:   c.Query("status"),
+		TenantID: c.Query("tenant_id"),
+	}
+	q, _ := pageQuery(c)
+
+	page, err := h.jobRunRepo.List(c.UserContext(), filter, q)
+	if err != nil {
+		return pageError(c, err)
+	}
+	return response.OK(c, page)
+}
+
+// RetryJobRun POST /v1/platform/jobs/:id/retry
+// The retry runs in the background; its run is returned so it can be followed in the listing
+func (h *JobHandler) RetryJobRun(c *fiber.Ctx) error {
+	run, err := h.runner.Retry(c.UserContext(), c.Params("id"))
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return response.Error(c, fiber.StatusNotFound, "Job run not found")
+		}
+		if err == domain.ErrJobNotRetryable {
+			return response.Error(c, fiber.StatusConflict, "Only failed runs of a known job type can be retried")
+		}
+		return response.Fail(c, err)
+	}
+	return response.JSON(c, fiber.StatusAccepted, run)
+}
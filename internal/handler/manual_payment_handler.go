@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// ManualPaymentHandler lets the front desk record cash and bank transfer payments and
+// reconcile them with provider payments
+type ManualPaymentHandler struct {
+	payments *service.ManualPaymentService
+}
+
+func NewManualPaymentHandler(payments *service.ManualPaymentService) *ManualPaymentHandler {
+	return &ManualPaymentHandler{payments: payments}
+}
+
+// Record POST /v1/tenant-admin/payments/manual
+// Body: channel (cash, bank_transfer), optional receipt and note, and either invoice_id with
+// an optional amount, or contract {package_id, member_id, coach_id, branch_id} to sell a
+// contract paid in full
+func (h *ManualPaymentHandler) Record(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("userID").(string)
+
+	var req struct {
+		InvoiceID string `json:"invoice_id"`
+		Contract  *struct {
+			PackageID string `json:"package_id"`
+			MemberID  string `json:"member_id"`
+			CoachID   string `json:"coach_id"`
+			BranchID  string `json:"branch_id"`
+		} `json:"contract"`
+		Amount  int64  `json:"amount"`
+		Channel string `json:"channel"`
+		Receipt string `json:"receipt"`
+		Note    string `json:"note"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if (req.InvoiceID == "") == (req.Contract == nil) {
+		return response.Error(c, fiber.StatusBadRequest, "Provide either invoice_id or contract")
+	}
+	payment := domain.InvoicePayment{Amount: req.Amount, Channel: req.Channel, Receipt: req.Receipt, Note: req.Note}
+
+	if req.Contract != nil {
+		contract := &domain.PTContract{
+			PackageID: req.Contract.PackageID,
+			MemberID:  req.Contract.MemberID,
+			CoachID:   req.Contract.CoachID,
+			BranchID:  req.Contract.BranchID,
+		}
+		invoice, err := h.payments.SellContract(c.UserContext(), tenantID, userID, contract, payment)
+		if err != nil {
+			return manualPaymentError(c, err)
+		}
+		return response.Created(c, fiber.Map{"invoice": invoice, "contract": contract})
+	}
+
+	invoice, err := h.payments.PayInvoice(c.UserContext(), tenantID, userID, req.InvoiceID, payment)
+	if err != nil {
+		return manualPaymentError(c, err)
+	}
+	return response.Created(c, fiber.Map{"invoice": invoice})
+}
+
+// Reconcile GET /v1/tenant-admin/payments/reconciliation
+// Optional: from, to (YYYY-MM-DD, inclusive; defaults to the last 30 days)
+func (h *ManualPaymentHandler) Reconcile(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -30)
+	if s := c.Query("from"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid 'from' date format, use YYYY-MM-DD")
+		}
+		from = d
+	}
+	if s := c.Query("to"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid 'to' date format, use YYYY-MM-DD")
+		}
+		to = d.AddDate(0, 0, 1) // Include the whole day
+	}
+	if !from.Before(to) {
+		return response.Error(c, fiber.StatusBadRequest, "'from' must not be after 'to'")
+	}
+
+	report, err := h.payments.Reconcile(c.UserContext(), tenantID, from, to)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, report)
+}
+
+func manualPaymentError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrNotFound, domain.ErrInvalidID:
+		return response.Error(c, fiber.StatusNotFound, "Invoice not found")
+	case domain.ErrInvalidPaymentChannel, domain.ErrInvalidManualPayment, domain.ErrNotContractInvoice, domain.ErrBranchMismatch:
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	case domain.ErrInvoiceAlreadyPaid:
+		return response.FailAs(c, fiber.StatusConflict, err)
+	}
+	return response.Fail(c, err)
+}
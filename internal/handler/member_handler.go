@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"log"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
 	"github.com/mansoorceksport/metamorph/internal/service"
 )
 
@@ -26,6 +28,9 @@ type MemberHandler struct {
 	exerciseRepo   domain.ExerciseRepository
 	userRepo       domain.UserRepository
 	authService    *service.AuthService
+	videoService   *service.SetVideoService
+	progressScores *service.ProgressScoreService
+	photoService   *service.SessionPhotoService
 }
 
 // NewMemberHandler creates a new MemberHandler
@@ -39,6 +44,9 @@ func NewMemberHandler(
 	exerciseRepo domain.ExerciseRepository,
 	userRepo domain.UserRepository,
 	authService *service.AuthService,
+	videoService *service.SetVideoService,
+	progressScores *service.ProgressScoreService,
+	photoService *service.SessionPhotoService,
 ) *MemberHandler {
 	return &MemberHandler{
 		pbRepo:         pbRepo,
@@ -50,6 +58,9 @@ func NewMemberHandler(
 		exerciseRepo:   exerciseRepo,
 		userRepo:       userRepo,
 		authService:    authService,
+		videoService:   videoService,
+		progressScores: progressScores,
+		photoService:   photoService,
 	}
 }
 
@@ -74,13 +85,17 @@ func (h *MemberHandler) GetMyPBs(c *fiber.Ctx) error {
 		limit = 20
 	}
 
+	if memberVisibility(c, h.ptService, memberID).HidesPersonalBests() {
+		return response.OK(c, []PBWithExerciseName{})
+	}
+
 	pbs, err := h.pbRepo.GetByMember(c.UserContext(), memberID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	if pbs == nil || len(pbs) == 0 {
-		return c.JSON([]PBWithExerciseName{})
+		return response.OK(c, []PBWithExerciseName{})
 	}
 
 	// Limit the results
@@ -120,7 +135,7 @@ func (h *MemberHandler) GetMyPBs(c *fiber.Ctx) error {
 		}
 	}
 
-	return c.JSON(result)
+	return response.OK(c, result)
 }
 
 // GetMyVolumeHistory handles GET /v1/me/volume-history
@@ -133,7 +148,7 @@ func (h *MemberHandler) GetMyVolumeHistory(c *fiber.Ctx) error {
 
 	volumes, err := h.workoutService.GetMemberVolumeHistory(c.Context(), memberID, limit, "")
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	// Build response
@@ -146,9 +161,9 @@ func (h *MemberHandler) GetMyVolumeHistory(c *fiber.Ctx) error {
 		ExerciseCount int     `json:"exercise_count"`
 	}
 
-	response := make([]VolumePoint, len(volumes))
+	points := make([]VolumePoint, len(volumes))
 	for i, v := range volumes {
-		response[i] = VolumePoint{
+		points[i] = VolumePoint{
 			Date:          v.Date.Format("2006-01-02"),
 			TotalVolume:   v.TotalVolume,
 			TotalSets:     v.TotalSets,
@@ -158,19 +173,20 @@ func (h *MemberHandler) GetMyVolumeHistory(c *fiber.Ctx) error {
 		}
 	}
 
-	return c.JSON(fiber.Map{"volumes": response})
+	return response.OK(c, fiber.Map{"volumes": points})
 }
 
 // GetMySchedules handles GET /v1/me/schedules
-// Returns upcoming schedules for the authenticated member
+// Returns upcoming schedules for the authenticated member. Optional: tag
 func (h *MemberHandler) GetMySchedules(c *fiber.Ctx) error {
 	memberID := c.Locals("userID").(string)
+	tag := c.Query("tag")
 
-	// Try cache first
-	if h.cacheRepo != nil {
+	// Try cache first; only the unfiltered list is cached
+	if h.cacheRepo != nil && tag == "" {
 		var cached map[string]interface{}
 		if err := h.cacheRepo.GetMemberSchedules(c.UserContext(), memberID, &cached); err == nil {
-			return c.JSON(cached)
+			return response.OK(c, cached)
 		}
 	}
 
@@ -180,17 +196,20 @@ func (h *MemberHandler) GetMySchedules(c *fiber.Ctx) error {
 
 	schedules, err := h.scheduleRepo.GetByMember(c.UserContext(), memberID, from, to)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	response := fiber.Map{"schedules": schedules}
+	if tag != "" {
+		return response.OK(c, fiber.Map{"schedules": filterSchedulesByTag(schedules, tag)})
+	}
+	body := fiber.Map{"schedules": schedules}
 
 	// Cache the result (10 minutes TTL)
 	if h.cacheRepo != nil {
-		_ = h.cacheRepo.SetMemberSchedules(c.UserContext(), memberID, response, 10*time.Minute)
+		_ = h.cacheRepo.SetMemberSchedules(c.UserContext(), memberID, body, 10*time.Minute)
 	}
 
-	return c.JSON(response)
+	return response.OK(c, body)
 }
 
 // WorkoutHistoryItem represents a completed workout session for the member history view
@@ -235,10 +254,7 @@ func (h *MemberHandler) GetMyWorkoutHistory(c *fiber.Ctx) error {
 
 	schedules, err := h.scheduleRepo.GetByMember(c.UserContext(), memberID, from, to)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   err.Error(),
-		})
+		return response.Fail(c, err)
 	}
 
 	// Filter to only completed sessions
@@ -321,14 +337,11 @@ func (h *MemberHandler) GetMyWorkoutHistory(c *fiber.Ctx) error {
 		nextCursor = paginatedSchedules[len(paginatedSchedules)-1].ID
 	}
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data": WorkoutHistoryResponse{
-			Workouts:   history,
-			Total:      len(completedSchedules),
-			HasMore:    hasMore,
-			NextCursor: nextCursor,
-		},
+	return response.OK(c, WorkoutHistoryResponse{
+		Workouts:   history,
+		Total:      len(completedSchedules),
+		HasMore:    hasMore,
+		NextCursor: nextCursor,
 	})
 }
 
@@ -350,16 +363,19 @@ func (h *MemberHandler) GetMyDashboard(c *fiber.Ctx) error {
 		if err := h.cacheRepo.GetMemberDashboard(c.UserContext(), memberID, &cached); err == nil {
 			// Inject fresh access_status into cached response
 			cached["access_status"] = accessStatus
-			return c.JSON(cached)
+			return response.OK(c, cached)
 		}
 	}
 
 	// Get contracts to calculate remaining sessions
 	contracts, err := h.ptService.GetActiveContractsByMember(c.UserContext(), memberID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
+	// What the member's coaches let them see of their metrics
+	visibility := domain.MemberMetricVisibility(contracts)
+
 	// Calculate total remaining sessions
 	totalRemaining := 0
 	totalSessions := 0
@@ -373,7 +389,7 @@ func (h *MemberHandler) GetMyDashboard(c *fiber.Ctx) error {
 	to := from.AddDate(0, 0, 30)
 	schedules, err := h.scheduleRepo.GetByMember(c.UserContext(), memberID, from, to)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	var nextSchedule *domain.Schedule
@@ -387,7 +403,7 @@ func (h *MemberHandler) GetMyDashboard(c *fiber.Ctx) error {
 	// Get latest scan for AI recap
 	scans, err := h.scanRepo.FindAllByUserID(c.UserContext(), memberID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	var latestScan *domain.InBodyRecord
@@ -398,13 +414,16 @@ func (h *MemberHandler) GetMyDashboard(c *fiber.Ctx) error {
 	// Get top PBs (limit to 5)
 	pbs, err := h.pbRepo.GetByMember(c.UserContext(), memberID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	topPBs := pbs
 	if len(topPBs) > 5 {
 		topPBs = topPBs[:5]
 	}
+	if visibility.HidesPersonalBests() {
+		topPBs = []*domain.PersonalBest{}
+	}
 
 	// Get user's first_login_at for trial calculation (legacy - keeping for backward compat)
 	var firstLoginAt *time.Time
@@ -414,23 +433,34 @@ func (h *MemberHandler) GetMyDashboard(c *fiber.Ctx) error {
 		}
 	}
 
-	response := fiber.Map{
+	// Latest weekly progress score; nil until the member has been scored
+	var progressScore *domain.ProgressScore
+	if h.progressScores != nil {
+		tenantID, _ := c.Locals("tenant_id").(string)
+		if scores, err := h.progressScores.MemberScores(c.UserContext(), tenantID, memberID, 1); err == nil && len(scores) > 0 {
+			progressScore = scores[0]
+		}
+	}
+
+	dashboard := fiber.Map{
 		"remaining_sessions": totalRemaining,
 		"total_sessions":     totalSessions,
 		"next_schedule":      nextSchedule,
-		"latest_scan":        latestScan,
+		"latest_scan":        visibility.Scan(latestScan),
 		"top_pbs":            topPBs,
 		"contracts":          contracts,
 		"first_login_at":     firstLoginAt,
 		"access_status":      accessStatus,
+		"progress_score":     progressScore,
+		"hidden_metrics":     visibility.HiddenMetrics(),
 	}
 
 	// Cache the result (5 minutes TTL)
 	if h.cacheRepo != nil {
-		_ = h.cacheRepo.SetMemberDashboard(c.UserContext(), memberID, response, 5*time.Minute)
+		_ = h.cacheRepo.SetMemberDashboard(c.UserContext(), memberID, dashboard, 5*time.Minute)
 	}
 
-	return c.JSON(response)
+	return response.OK(c, dashboard)
 }
 
 // GetMyScans handles GET /v1/me/scans
@@ -465,16 +495,11 @@ func (h *MemberHandler) GetMyScans(c *fiber.Ctx) error {
 
 	result, err := h.scanRepo.FindPaginatedByUserID(c.UserContext(), memberID, query)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   err.Error(),
-		})
+		return response.Fail(c, err)
 	}
+	visibility := memberVisibility(c, h.ptService, memberID)
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    result,
-	})
+	return response.WithMeta(c, fiber.StatusOK, visibility.ScanList(result), fiber.Map{"hidden_metrics": visibility.HiddenMetrics()})
 }
 
 // GetMyScan handles GET /v1/me/scans/:id
@@ -484,22 +509,18 @@ func (h *MemberHandler) GetMyScan(c *fiber.Ctx) error {
 	scanID := c.Params("id")
 
 	if scanID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "scan ID is required",
-		})
+		return response.Error(c, fiber.StatusBadRequest, "scan ID is required")
 	}
 
-	// Try cache first
+	visibility := memberVisibility(c, h.ptService, memberID)
+
+	// Try cache first. The cached scan is complete; hidden metrics are removed per response.
 	if h.cacheRepo != nil {
 		cached, err := h.cacheRepo.GetScanByID(c.UserContext(), scanID)
 		if err == nil && cached != nil {
 			// Verify ownership
-			if cached.UserID.Hex() == memberID {
-				return c.JSON(fiber.Map{
-					"success": true,
-					"data":    cached,
-				})
+			if cached.UserID == memberID {
+				return response.WithMeta(c, fiber.StatusOK, visibility.Scan(cached), fiber.Map{"hidden_metrics": visibility.HiddenMetrics()})
 			}
 		}
 	}
@@ -508,23 +529,14 @@ func (h *MemberHandler) GetMyScan(c *fiber.Ctx) error {
 	scan, err := h.scanRepo.FindByID(c.UserContext(), scanID)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"success": false,
-				"error":   "scan not found",
-			})
+			return response.Error(c, fiber.StatusNotFound, "scan not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   err.Error(),
-		})
+		return response.Fail(c, err)
 	}
 
 	// Verify ownership
-	if scan.UserID.Hex() != memberID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"success": false,
-			"error":   "you don't have access to this scan",
-		})
+	if scan.UserID != memberID {
+		return response.Error(c, fiber.StatusForbidden, "you don't have access to this scan")
 	}
 
 	// Cache the result
@@ -532,10 +544,7 @@ func (h *MemberHandler) GetMyScan(c *fiber.Ctx) error {
 		_ = h.cacheRepo.SetScanByID(c.UserContext(), scanID, scan, ScanCacheTTL)
 	}
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    scan,
-	})
+	return response.WithMeta(c, fiber.StatusOK, visibility.Scan(scan), fiber.Map{"hidden_metrics": visibility.HiddenMetrics()})
 }
 
 // ExerciseWithSets represents an exercise with its sets for workout detail
@@ -548,21 +557,24 @@ type ExerciseWithSets struct {
 
 // SetDetail represents a single set in the workout detail
 type SetDetail struct {
-	SetIndex  int     `json:"set_index"`
-	Weight    float64 `json:"weight"`
-	Reps      int     `json:"reps"`
-	Completed bool    `json:"completed"`
+	ID        string             `json:"id"`
+	SetIndex  int                `json:"set_index"`
+	Weight    float64            `json:"weight"`
+	Reps      int                `json:"reps"`
+	Completed bool               `json:"completed"`
+	Videos    []*domain.SetVideo `json:"videos,omitempty"` // Form-check clips with coach comments
 }
 
 // WorkoutDetailResponse represents the full workout detail
 type WorkoutDetailResponse struct {
-	ID            string             `json:"id"`
-	Date          time.Time          `json:"date"`
-	SessionGoal   string             `json:"session_goal"`
-	TotalVolume   float64            `json:"total_volume"`
-	TotalSets     int                `json:"total_sets"`
-	ExerciseCount int                `json:"exercise_count"`
-	Exercises     []ExerciseWithSets `json:"exercises"`
+	ID            string                 `json:"id"`
+	Date          time.Time              `json:"date"`
+	SessionGoal   string                 `json:"session_goal"`
+	TotalVolume   float64                `json:"total_volume"`
+	TotalSets     int                    `json:"total_sets"`
+	ExerciseCount int                    `json:"exercise_count"`
+	Exercises     []ExerciseWithSets     `json:"exercises"`
+	Photos        []*domain.SessionPhoto `json:"photos"` // Taken by the coach during the session
 }
 
 // GetMyWorkoutDetail handles GET /v1/me/workouts/:id
@@ -572,7 +584,7 @@ func (h *MemberHandler) GetMyWorkoutDetail(c *fiber.Ctx) error {
 	scheduleID := c.Params("id")
 
 	if scheduleID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "workout ID is required"})
+		return response.Error(c, fiber.StatusBadRequest, "workout ID is required")
 	}
 
 	// Get the schedule
@@ -581,19 +593,19 @@ func (h *MemberHandler) GetMyWorkoutDetail(c *fiber.Ctx) error {
 		// Try by client_id
 		schedule, err = h.scheduleRepo.GetByClientID(c.UserContext(), scheduleID)
 		if err != nil {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "workout not found"})
+			return response.Error(c, fiber.StatusNotFound, "workout not found")
 		}
 	}
 
 	// Verify ownership
 	if schedule.MemberID != memberID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "you don't have access to this workout"})
+		return response.Error(c, fiber.StatusForbidden, "you don't have access to this workout")
 	}
 
 	// Get set logs for this schedule
 	setLogs, err := h.workoutService.GetSetsBySchedule(c.UserContext(), schedule.ID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	// Get member's PBs to mark exercises with PRs
@@ -603,6 +615,17 @@ func (h *MemberHandler) GetMyWorkoutDetail(c *fiber.Ctx) error {
 		pbScheduleMap[pb.ExerciseID] = pb.ScheduleID
 	}
 
+	// Form-check videos are extras; the workout still shows without them
+	videos, err := h.videoService.VideosBySet(c.UserContext(), schedule.ID)
+	if err != nil {
+		log.Printf("Warning: failed to load videos for workout %s: %v", schedule.ID, err)
+	}
+	photos, err := h.photoService.SchedulePhotos(c.UserContext(), schedule.ID)
+	if err != nil {
+		log.Printf("Warning: failed to load photos for workout %s: %v", schedule.ID, err)
+		photos = []*domain.SessionPhoto{}
+	}
+
 	// Group sets by exercise
 	exerciseMap := make(map[string]*ExerciseWithSets)
 	exerciseOrder := []string{}
@@ -622,10 +645,12 @@ func (h *MemberHandler) GetMyWorkoutDetail(c *fiber.Ctx) error {
 		}
 
 		exerciseMap[log.ExerciseID].Sets = append(exerciseMap[log.ExerciseID].Sets, SetDetail{
+			ID:        log.ID,
 			SetIndex:  log.SetIndex,
 			Weight:    log.Weight,
 			Reps:      log.Reps,
 			Completed: log.Completed,
+			Videos:    videos[log.ID],
 		})
 	}
 
@@ -659,16 +684,14 @@ func (h *MemberHandler) GetMyWorkoutDetail(c *fiber.Ctx) error {
 		}
 	}
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data": WorkoutDetailResponse{
-			ID:            schedule.ID,
-			Date:          schedule.StartTime,
-			SessionGoal:   schedule.SessionGoal,
-			TotalVolume:   totalVolume,
-			TotalSets:     totalSets,
-			ExerciseCount: len(exerciseList),
-			Exercises:     exerciseList,
-		},
+	return response.OK(c, WorkoutDetailResponse{
+		ID:            schedule.ID,
+		Date:          schedule.StartTime,
+		SessionGoal:   schedule.SessionGoal,
+		TotalVolume:   totalVolume,
+		TotalSets:     totalSets,
+		ExerciseCount: len(exerciseList),
+		Exercises:     exerciseList,
+		Photos:        photos,
 	})
 }
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+type MemberInviteHandler struct {
+	invites      *service.MemberInviteService
+	tokenService *service.TokenService
+}
+
+func NewMemberInviteHandler(invites *service.MemberInviteService, tokenService *service.TokenService) *MemberInviteHandler {
+	return &MemberInviteHandler{invites: invites, tokenService: tokenService}
+}
+
+// Invite handles POST /v1/pro/members/invite
+// Invites a member by email; the response has the link for the coach to text them
+func (h *MemberInviteHandler) Invite(c *fiber.Ctx) error {
+	coachID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+	if tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "TenantID not found in token")
+	}
+
+	var req struct {
+		Email       string `json:"email"`
+		Name        string `json:"name"`
+		PackageID   string `json:"package_id"`    // Optional: sold when the invite is accepted
+		DateOfBirth string `json:"date_of_birth"` // Optional: YYYY-MM-DD
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	dob, err := domain.ParseDateOfBirth(req.DateOfBirth, time.Now())
+	if err != nil {
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+
+	sent, err := h.invites.Invite(c.UserContext(), tenantID, coachID, &domain.MemberInvite{
+		Email:       req.Email,
+		Name:        req.Name,
+		PackageID:   req.PackageID,
+		DateOfBirth: dob,
+	})
+	if err != nil {
+		return inviteError(c, err)
+	}
+	return response.Created(c, sent)
+}
+
+// ListInvites handles GET /v1/pro/members/invites
+// The coach's invites that haven't been accepted, revoked or expired
+func (h *MemberInviteHandler) ListInvites(c *fiber.Ctx) error {
+	coachID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+	invites, err := h.invites.ListPending(c.UserContext(), tenantID, coachID)
+	if err != nil {
+		return inviteError(c, err)
+	}
+	return response.OK(c, invites)
+}
+
+// RevokeInvite handles DELETE /v1/pro/members/invites/:id
+func (h *MemberInviteHandler) RevokeInvite(c *fiber.Ctx) error {
+	coachID, _ := c.Locals("userID").(string)
+	if err := h.invites.Revoke(c.UserContext(), coachID, c.Params("id")); err != nil {
+		return inviteError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// AcceptInvite handles POST /v1/auth/accept-invite
+// Public like login: the Firebase token of the invited email in the Authorization header
+// and the invite token in the body. Answers like login, scoped to the invite's gym.
+func (h *MemberInviteHandler) AcceptInvite(c *fiber.Ctx) error {
+	firebaseToken := strings.TrimPrefix(c.Get("Authorization"), "Bearer ")
+	if firebaseToken == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing Authorization header")
+	}
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.Token == "" {
+		return response.Error(c, fiber.StatusBadRequest, "token is required")
+	}
+
+	accepted, err := h.invites.Accept(c.UserContext(), firebaseToken, req.Token)
+	if err != nil {
+		return inviteError(c, err)
+	}
+
+	tokenPair, err := h.tokenService.GenerateScopedTokenPair(c.Context(), accepted.User, accepted.Invite.TenantID, c.Get("User-Agent"), c.IP())
+	if err != nil {
+		if err == domain.ErrTenantDeactivated {
+			return response.FailAs(c, fiber.StatusForbidden, err)
+		}
+		return response.Fail(c, fmt.Errorf("failed to generate tokens: %w", err))
+	}
+	c.Cookie(&fiber.Cookie{
+		Name:     "metamorph-refresh-token",
+		Value:    tokenPair.RefreshToken,
+		Expires:  time.Now().Add(7 * 24 * time.Hour),
+		HTTPOnly: true,
+		Secure:   false,
+		SameSite: "Lax",
+		Path:     "/",
+	})
+
+	res := fiber.Map{
+		"token":      tokenPair.AccessToken,
+		"expires_in": tokenPair.ExpiresIn,
+		"user": fiber.Map{
+			"id":         accepted.User.ID,
+			"roles":      []string{domain.RoleMember},
+			"tenant_id":  accepted.Invite.TenantID,
+			"tenant_ids": accepted.User.TenantIDs(),
+		},
+		"contract": accepted.Contract,
+	}
+	if accepted.Warning != "" {
+		res["warning"] = accepted.Warning
+	}
+	return response.OK(c, res)
+}
+
+func inviteError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInvite):
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	case errors.Is(err, domain.ErrInviteNotFound), errors.Is(err, domain.ErrPackageTemplateNotFound), errors.Is(err, domain.ErrNotFound):
+		return response.FailAs(c, fiber.StatusNotFound, err)
+	case errors.Is(err, domain.ErrInviteEmailMismatch), errors.Is(err, domain.ErrInviteEmailUnverified):
+		return response.FailAs(c, fiber.StatusForbidden, err)
+	case errors.Is(err, domain.ErrInviteMemberExists), errors.Is(err, domain.ErrInviteAccountLinked):
+		return response.FailAs(c, fiber.StatusConflict, err)
+	}
+	return response.Fail(c, err)
+}
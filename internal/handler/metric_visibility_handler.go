@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// MetricVisibilityHandler lets coaches choose which metrics their clients see of themselves
+type MetricVisibilityHandler struct {
+	ptService *service.PTService
+	cacheRepo domain.CacheRepository
+}
+
+func NewMetricVisibilityHandler(ptService *service.PTService, cacheRepo domain.CacheRepository) *MetricVisibilityHandler {
+	return &MetricVisibilityHandler{ptService: ptService, cacheRepo: cacheRepo}
+}
+
+// GetVisibility GET /v1/pro/contracts/:id/metric-visibility
+func (h *MetricVisibilityHandler) GetVisibility(c *fiber.Ctx) error {
+	coachID, _ := c.Locals("userID").(string)
+	contract, err := h.ptService.GetContract(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return metricVisibilityError(c, err)
+	}
+	if contract.CoachID != coachID {
+		return metricVisibilityError(c, domain.ErrForbidden)
+	}
+
+	var visibility domain.MetricVisibility
+	if contract.MetricVisibility != nil {
+		visibility = *contract.MetricVisibility
+	}
+	return response.OK(c, fiber.Map{
+		"contract_id":    contract.ID,
+		"visibility":     visibility,
+		"hidden_metrics": visibility.HiddenMetrics(),
+	})
+}
+
+// UpdateVisibility PUT /v1/pro/contracts/:id/metric-visibility
+// Body: {"hide_body_fat": true, "hide_weight": false, "volume_only": false}. All false shows
+// the member everything again. Applies to the member's scan, analytics and dashboard
+// responses; a metric hidden by any of their active contracts stays hidden.
+func (h *MetricVisibilityHandler) UpdateVisibility(c *fiber.Ctx) error {
+	coachID, _ := c.Locals("userID").(string)
+	var req domain.MetricVisibility
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	contract, err := h.ptService.SetMetricVisibility(c.UserContext(), coachID, c.Params("id"), &req)
+	if err != nil {
+		return metricVisibilityError(c, err)
+	}
+	// The cached dashboard was shaped by the old settings
+	if h.cacheRepo != nil {
+		_ = h.cacheRepo.InvalidateMemberDashboard(c.UserContext(), contract.MemberID)
+	}
+	return response.OK(c, fiber.Map{
+		"contract_id":    contract.ID,
+		"visibility":     req,
+		"hidden_metrics": req.HiddenMetrics(),
+	})
+}
+
+func metricVisibilityError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrContractNotFound), errors.Is(err, domain.ErrInvalidID):
+		return response.Error(c, fiber.StatusNotFound, "Contract not found")
+	case errors.Is(err, domain.ErrForbidden):
+		return response.Error(c, fiber.StatusForbidden, "You can only change the visibility of your own clients' contracts")
+	}
+	return response.Fail(c, err)
+}
@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+	prefs               domain.NotificationPreferencesRepository
+}
+
+func NewNotificationHandler(notificationService *service.NotificationService, prefs domain.NotificationPreferencesRepository) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService, prefs: prefs}
+}
+
+// InboxResponse is a page of the caller's notifications and how many are unread
+type InboxResponse struct {
+	*domain.Page[*domain.InboxItem]
+	UnreadCount int64 `json:"unread_count"`
+}
+
+// GetMyNotifications GET /v1/me/notifications?unread=true&limit=&cursor=
+// Always paginated, newest first, with the unread count for the bell badge
+func (h *NotificationHandler) GetMyNotifications(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	q, _ := pageQuery(c)
+
+	page, unread, err := h.notificationService.Inbox(c.UserContext(), userID, c.QueryBool("unread"), q)
+	if err != nil {
+		return pageError(c, err)
+	}
+	return response.OK(c, InboxResponse{Page: page, UnreadCount: unread})
+}
+
+// GetMyUnreadCount GET /v1/me/notifications/unread-count
+func (h *NotificationHandler) GetMyUnreadCount(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	unread, err := h.notificationService.UnreadCount(c.UserContext(), userID)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, fiber.Map{"unread_count": unread})
+}
+
+// MarkMyNotificationRead POST /v1/me/notifications/:id/read
+func (h *NotificationHandler) MarkMyNotificationRead(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	if err := h.notificationService.MarkRead(c.UserContext(), userID, c.Params("id")); err != nil {
+		if err == domain.ErrNotFound {
+			return response.Error(c, fiber.StatusNotFound, "Notification not found")
+		}
+		return response.Fail(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// MarkAllMyNotificationsRead POST /v1/me/notifications/read-all
+func (h *NotificationHandler) MarkAllMyNotificationsRead(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	marked, err := h.notificationService.MarkAllRead(c.UserContext(), userID)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, fiber.Map{"marked": marked})
+}
+
+// DeviceRequest is the body of POST and DELETE /v1/devices
+type DeviceRequest struct {
+	Token    string `json:"token"`    // FCM registration token
+	Platform string `json:"platform"` // "android", "ios" or "web"; not needed to unregister
+}
+
+// RegisterMyDevice POST /v1/devices
+// Members and staff register each device they want pushes on
+func (h *NotificationHandler) RegisterMyDevice(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	var req DeviceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	device, err := h.notificationService.RegisterDevice(c.UserContext(), userID, req.Token, req.Platform)
+	if err != nil {
+		return deviceError(c, err)
+	}
+	return response.OK(c, device)
+}
+
+// UnregisterMyDevice DELETE /v1/devices
+// The token goes in the body: FCM tokens contain characters that don't belong in a path
+func (h *NotificationHandler) UnregisterMyDevice(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	var req DeviceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if err := h.notificationService.UnregisterDevice(c.UserContext(), userID, req.Token); err != nil {
+		return deviceError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func deviceError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, domain.ErrInvalidDeviceToken) {
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	return response.Fail(c, err)
+}
+
+// GetMyPreferences GET /v1/me/notification-preferences and /v1/pro/notification-preferences
+func (h *NotificationHandler) GetMyPreferences(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	prefs, err := h.prefs.GetUser(c.UserContext(), userID)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, prefs)
+}
+
+// UpdateMyPreferences PUT /v1/me/notification-preferences and /v1/pro/notification-preferences
+func (h *NotificationHandler) UpdateMyPreferences(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	var req struct {
+		RemindersOptOut *bool               `json:"reminders_opt_out"`
+		QuietHours      *domain.QuietHours  `json:"quiet_hours"` // Empty start and end clear them
+		Channels        map[string][]string `json:"channels"`    // Replaces the saved choices when present
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	prefs, err := h.prefs.GetUser(c.UserContext(), userID)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	if req.RemindersOptOut != nil {
+		prefs.RemindersOptOut = *req.RemindersOptOut
+	}
+	if req.QuietHours != nil {
+		if req.QuietHours.Start == "" && req.QuietHours.End == "" {
+			prefs.QuietHours = nil
+		} else if err := req.QuietHours.Validate(); err != nil {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		} else {
+			prefs.QuietHours = req.QuietHours
+		}
+	}
+	if req.Channels != nil {
+		if err := domain.ValidateChannels(req.Channels); err != nil {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		}
+		prefs.Channels = req.Channels
+	}
+	if err := h.prefs.UpsertUser(c.UserContext(), prefs); err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, prefs)
+}
+
+// GetTenantSettings GET /v1/tenant-admin/notification-settings
+func (h *NotificationHandler) GetTenantSettings(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	settings, err := h.prefs.GetTenant(c.UserContext(), tenantID)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, settings)
+}
+
+// UpdateTenantSettings PUT /v1/tenant-admin/notification-settings
+// reminder_lead_minutes lists when members are reminded of a session, e.g. [1440, 60];
+// an empty list turns reminders off for the tenant. confirmation_alert_minutes is when
+// coaches hear about sessions the member hasn't confirmed; 0 turns the alerts off.
+func (h *NotificationHandler) UpdateTenantSettings(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	var req struct {
+		ReminderLeadMinutes      *[]int `json:"reminder_lead_minutes"`
+		ConfirmationAlertMinutes *int   `json:"confirmation_alert_minutes"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	settings, err := h.prefs.GetTenant(c.UserContext(), tenantID)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	if req.ReminderLeadMinutes != nil {
+		if err := domain.ValidateReminderLeadMinutes(*req.ReminderLeadMinutes); err != nil {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		}
+		settings.ReminderLeadMinutes = *req.ReminderLeadMinutes
+	}
+	if req.ConfirmationAlertMinutes != nil {
+		if m := *req.ConfirmationAlertMinutes; m < 0 || m > domain.MaxReminderLeadMinutes {
+			return response.Fail(c, domain.ErrInvalidConfirmationAlert)
+		}
+		settings.ConfirmationAlertMinutes = req.ConfirmationAlertMinutes
+	}
+	if err := h.prefs.UpsertTenant(c.UserContext(), settings); err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, settings)
+}
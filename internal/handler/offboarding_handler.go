@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// OffboardingHandler lets the platform delete a tenant in stages: export, deactivation with
+// a grace period, anonymization and purge
+type OffboardingHandler struct {
+	offboarding *service.TenantOffboardingService
+}
+
+func NewOffboardingHandler(offboarding *service.TenantOffboardingService) *OffboardingHandler {
+	return &OffboardingHandler{offboarding: offboarding}
+}
+
+// Start POST /v1/platform/tenants/:id/offboarding
+// Body: {"confirm_name": "<tenant name>", "grace_days": 30}. The export runs in the
+// background; follow it with GET.
+func (h *OffboardingHandler) Start(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	var req struct {
+		ConfirmName string `json:"confirm_name"`
+		GraceDays   int    `json:"grace_days"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	o, err := h.offboarding.Start(c.UserContext(), c.Params("id"), userID, req.ConfirmName, req.GraceDays)
+	if err != nil {
+		return offboardingError(c, err)
+	}
+	return response.JSON(c, fiber.StatusAccepted, o)
+}
+
+// Get GET /v1/platform/tenants/:id/offboarding
+// The latest off-boarding with its progress, and a link to the export archive once it exists
+func (h *OffboardingHandler) Get(c *fiber.Ctx) error {
+	o, archiveURL, err := h.offboarding.Get(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return offboardingError(c, err)
+	}
+	return response.OK(c, fiber.Map{"offboarding": o, "archive_url": archiveURL})
+}
+
+// Cancel DELETE /v1/platform/tenants/:id/offboarding
+// Restores the tenant during the grace period
+func (h *OffboardingHandler) Cancel(c *fiber.Ctx) error {
+	o, err := h.offboarding.Cancel(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return offboardingError(c, err)
+	}
+	return response.OK(c, o)
+}
+
+func offboardingError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrNotFound:
+		return response.Error(c, fiber.StatusNotFound, "Not found")
+	case domain.ErrOffboardingConfirmation, domain.ErrInvalidOffboardingGrace:
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	case domain.ErrOffboardingStarted, domain.ErrOffboardingNotCancellable:
+		return response.FailAs(c, fiber.StatusConflict, err)
+	}
+	return response.Fail(c, err)
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/openapi"
+)
+
+// swaggerUI browses the document at /v1/openapi.json with Swagger UI from a CDN
+const swaggerUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Metamorph API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: "/v1/openapi.json", dom_id: "#swagger-ui", persistAuthorization: true});
+  </script>
+</body>
+</html>`
+
+// OpenAPIHandler serves the generated OpenAPI document of the API
+type OpenAPIHandler struct {
+	doc *openapi.Document
+}
+
+func NewOpenAPIHandler(doc *openapi.Document) *OpenAPIHandler {
+	return &OpenAPIHandler{doc: doc}
+}
+
+// GetSpec GET /v1/openapi.json
+// The document as is, not in an envelope, for OpenAPI tools to read
+func (h *OpenAPIHandler) GetSpec(c *fiber.Ctx) error {
+	return c.JSON(h.doc)
+}
+
+// GetDocs GET /v1/docs
+// Swagger UI over the document
+func (h *OpenAPIHandler) GetDocs(c *fiber.Ctx) error {
+	c.Type("html", "utf-8")
+	return c.SendString(swaggerUI)
+}
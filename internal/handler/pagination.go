@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+)
+
+// pageQuery reads ?limit= and ?cursor= for cursor-paginated list endpoints.
+// ok is false when neither is set, so clients that don't paginate keep getting a plain array.
+func pageQuery(c *fiber.Ctx) (q domain.PageQuery, ok bool) {
+	cursor := c.Query("cursor")
+	limit := c.QueryInt("limit")
+	if cursor == "" && limit == 0 {
+		return domain.PageQuery{}, false
+	}
+	return domain.PageQuery{Limit: limit, Cursor: cursor}, true
+}
+
+// userListQuery reads a user list's filters, sort and page. ok is false when none were
+// given, for clients that still expect the full list.
+func userListQuery(c *fiber.Ctx) (q domain.UserListQuery, ok bool) {
+	page, paged := pageQuery(c)
+	q = domain.UserListQuery{Role: c.Query("role"), Search: c.Query("search"), Sort: c.Query("sort"), Page: page}
+	return q, paged || q.Role != "" || q.Search != "" || q.Sort != ""
+}
+
+// pageError maps list errors, treating a bad cursor as a client error
+func pageError(c *fiber.Ctx, err error) error {
+	if err == domain.ErrInvalidCursor {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid cursor")
+	}
+	return response.Fail(c, err)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
 	"github.com/mansoorceksport/metamorph/internal/service"
 )
 
@@ -14,6 +15,9 @@ type PaymentHandler struct {
 	invoiceRepo     domain.InvoiceRepository
 	packageRepo     domain.PackageRepository
 	paymentProvider service.PaymentProvider
+	funnel          *service.SalesFunnelService
+	sandbox         *service.SandboxService
+	invoiceExpiry   *service.InvoiceExpiryService
 }
 
 // NewPaymentHandler creates a new PaymentHandler
@@ -21,11 +25,17 @@ func NewPaymentHandler(
 	invoiceRepo domain.InvoiceRepository,
 	packageRepo domain.PackageRepository,
 	paymentProvider service.PaymentProvider,
+	funnel *service.SalesFunnelService,
+	sandbox *service.SandboxService,
+	invoiceExpiry *service.InvoiceExpiryService,
 ) *PaymentHandler {
 	return &PaymentHandler{
 		invoiceRepo:     invoiceRepo,
 		packageRepo:     packageRepo,
 		paymentProvider: paymentProvider,
+		funnel:          funnel,
+		sandbox:         sandbox,
+		invoiceExpiry:   invoiceExpiry,
 	}
 }
 
@@ -37,48 +47,48 @@ type CheckoutRequest struct {
 
 // CheckoutResponse represents the checkout response with invoice details
 type CheckoutResponse struct {
-	ID            string `json:"id"`
-	VANumber      string `json:"va_number"`
-	Amount        int64  `json:"amount"`
-	PaymentMethod string `json:"payment_method"`
-	ExpiryDate    string `json:"expiry_date"` // ISO 8601 format
-	Status        string `json:"status"`
+	ID            string       `json:"id"`
+	VANumber      string       `json:"va_number"`
+	Amount        domain.Money `json:"amount"`
+	PaymentMethod string       `json:"payment_method"`
+	ExpiryDate    string       `json:"expiry_date"` // ISO 8601 format
+	Status        string       `json:"status"`
+}
+
+func checkoutResponse(invoice *domain.Invoice) CheckoutResponse {
+	return CheckoutResponse{
+		ID:            invoice.ID,
+		VANumber:      invoice.VANumber,
+		Amount:        invoice.Amount,
+		PaymentMethod: invoice.PaymentMethod,
+		ExpiryDate:    invoice.ExpiryDate.Format("2006-01-02T15:04:05Z07:00"), // ISO 8601
+		Status:        invoice.Status,
+	}
 }
 
 // Checkout handles POST /api/member/payments/checkout
-// Creates or returns existing pending invoice with VA number
+// Creates or returns existing pending invoice with VA number. An invoice whose VA expired
+// unpaid is reopened with a new VA instead.
 func (h *PaymentHandler) Checkout(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(string)
 	if !ok || userID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"error":   "unauthorized",
-		})
+		return response.Error(c, fiber.StatusUnauthorized, "unauthorized")
 	}
 
 	var req CheckoutRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "invalid request body",
-		})
+		return response.Error(c, fiber.StatusBadRequest, "invalid request body")
 	}
 
 	// Validate package_id
 	if req.PackageID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "package_id is required",
-		})
+		return response.Error(c, fiber.StatusBadRequest, "package_id is required")
 	}
 
 	// Validate payment_method
 	validMethods := map[string]bool{"BCA": true, "Mandiri": true, "BNI": true}
 	if !validMethods[req.PaymentMethod] {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "invalid payment_method, must be BCA, Mandiri, or BNI",
-		})
+		return response.Error(c, fiber.StatusBadRequest, "invalid payment_method, must be BCA, Mandiri, or BNI")
 	}
 
 	ctx := c.UserContext()
@@ -87,65 +97,59 @@ func (h *PaymentHandler) Checkout(c *fiber.Ctx) error {
 	pkg, err := h.packageRepo.GetByID(ctx, req.PackageID)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"success": false,
-				"error":   "package not found",
-			})
+			return response.Error(c, fiber.StatusNotFound, "package not found")
 		}
 		log.Printf("[Checkout] Error fetching package: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "failed to fetch package",
-		})
+		return response.Error(c, fiber.StatusInternalServerError, "failed to fetch package")
 	}
 
 	if !pkg.IsActive {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "package is not active",
-		})
+		return response.Error(c, fiber.StatusBadRequest, "package is not active")
 	}
 
+	tenantID, _ := c.Locals("tenant_id").(string)
+	h.funnel.Track(ctx, tenantID, userID, pkg.ID, domain.SalesStageCheckoutStarted, "")
+
 	// Check for existing pending invoice (Active Session logic)
 	existingInvoice, err := h.invoiceRepo.GetPendingByUserAndPackage(ctx, userID, req.PackageID)
 	if err == nil && existingInvoice != nil {
 		// Return existing invoice - no need to create new one
-		return c.JSON(fiber.Map{
-			"success": true,
-			"data": CheckoutResponse{
-				ID:            existingInvoice.ID,
-				VANumber:      existingInvoice.VANumber,
-				Amount:        existingInvoice.Amount,
-				PaymentMethod: existingInvoice.PaymentMethod,
-				ExpiryDate:    existingInvoice.ExpiryDate.Format("2006-01-02T15:04:05Z07:00"),
-				Status:        existingInvoice.Status,
-			},
-		})
+		return response.OK(c, checkoutResponse(existingInvoice))
 	}
 
 	// If error is not "not found", it's a real error
 	if err != nil && !errors.Is(err, domain.ErrNotFound) {
 		log.Printf("[Checkout] Error checking existing invoice: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "failed to check existing invoices",
-		})
+		return response.Error(c, fiber.StatusInternalServerError, "failed to check existing invoices")
+	}
+
+	// A checkout retried after its VA expired continues the lapsed invoice
+	reopened, err := h.invoiceExpiry.Reopen(ctx, tenantID, userID, pkg, req.PaymentMethod)
+	if err != nil {
+		log.Printf("[Checkout] Error reopening invoice: %v", err)
+		return response.Error(c, fiber.StatusInternalServerError, "payment service unavailable, please try again later")
+	}
+	if reopened != nil {
+		return response.OK(c, checkoutResponse(reopened))
 	}
 
 	// No existing pending invoice - create new one
-	// Step 1: Generate VA from payment provider
-	vaResponse, err := h.paymentProvider.GenerateVA(ctx, req.PaymentMethod, pkg.Price, userID)
+	// Step 1: Generate VA from payment provider; sandbox tenants never reach the real one
+	provider, err := h.sandbox.PaymentProvider(ctx, tenantID, h.paymentProvider)
+	if err != nil {
+		log.Printf("[Checkout] Error resolving payment provider: %v", err)
+		return response.Error(c, fiber.StatusInternalServerError, "payment service unavailable, please try again later")
+	}
+	vaResponse, err := provider.GenerateVA(ctx, req.PaymentMethod, pkg.Price.Minor, userID)
 	if err != nil {
 		log.Printf("[Checkout] Error generating VA: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "payment service unavailable, please try again later",
-		})
+		return response.Error(c, fiber.StatusInternalServerError, "payment service unavailable, please try again later")
 	}
 
 	// Step 2: Create invoice with VA details
 	invoice := &domain.Invoice{
 		UserID:           userID,
+		TenantID:         tenantID,
 		PackageID:        req.PackageID,
 		Amount:           pkg.Price,
 		Status:           domain.InvoiceStatusPending,
@@ -157,23 +161,11 @@ func (h *PaymentHandler) Checkout(c *fiber.Ctx) error {
 
 	if err := h.invoiceRepo.Create(ctx, invoice); err != nil {
 		log.Printf("[Checkout] Error creating invoice: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "failed to create invoice",
-		})
+		return response.Error(c, fiber.StatusInternalServerError, "failed to create invoice")
 	}
+	h.funnel.Track(ctx, tenantID, userID, pkg.ID, domain.SalesStageInvoiceCreated, invoice.ID)
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"success": true,
-		"data": CheckoutResponse{
-			ID:            invoice.ID,
-			VANumber:      invoice.VANumber,
-			Amount:        invoice.Amount,
-			PaymentMethod: invoice.PaymentMethod,
-			ExpiryDate:    invoice.ExpiryDate.Format("2006-01-02T15:04:05Z07:00"),
-			Status:        invoice.Status,
-		},
-	})
+	return response.Created(c, checkoutResponse(invoice))
 }
 
 // GetInvoiceStatus handles GET /api/member/payments/status/:id
@@ -181,18 +173,12 @@ func (h *PaymentHandler) Checkout(c *fiber.Ctx) error {
 func (h *PaymentHandler) GetInvoiceStatus(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(string)
 	if !ok || userID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"error":   "unauthorized",
-		})
+		return response.Error(c, fiber.StatusUnauthorized, "unauthorized")
 	}
 
 	invoiceID := c.Params("id")
 	if invoiceID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "invoice ID is required",
-		})
+		return response.Error(c, fiber.StatusBadRequest, "invoice ID is required")
 	}
 
 	ctx := c.UserContext()
@@ -200,46 +186,50 @@ func (h *PaymentHandler) GetInvoiceStatus(c *fiber.Ctx) error {
 	invoice, err := h.invoiceRepo.GetByID(ctx, invoiceID)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"success": false,
-				"error":   "invoice not found",
-			})
+			return response.Error(c, fiber.StatusNotFound, "invoice not found")
 		}
 		log.Printf("[GetInvoiceStatus] Error fetching invoice: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "failed to fetch invoice",
-		})
+		return response.Error(c, fiber.StatusInternalServerError, "failed to fetch invoice")
 	}
 
 	// Verify ownership
 	if invoice.UserID != userID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"success": false,
-			"error":   "access denied",
-		})
+		return response.Error(c, fiber.StatusForbidden, "access denied")
+	}
+
+	return response.OK(c, checkoutResponse(invoice))
+}
+
+// TrackPackageView handles POST /v1/me/payments/packages/:id/view
+// The client reports each time a member opens a package's page
+func (h *PaymentHandler) TrackPackageView(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "unauthorized")
 	}
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data": CheckoutResponse{
-			ID:            invoice.ID,
-			VANumber:      invoice.VANumber,
-			Amount:        invoice.Amount,
-			PaymentMethod: invoice.PaymentMethod,
-			ExpiryDate:    invoice.ExpiryDate.Format("2006-01-02T15:04:05Z07:00"),
-			Status:        invoice.Status,
-		},
-	})
+	ctx := c.UserContext()
+	pkg, err := h.packageRepo.GetByID(ctx, c.Params("id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return response.Error(c, fiber.StatusNotFound, "package not found")
+		}
+		log.Printf("[TrackPackageView] Error fetching package: %v", err)
+		return response.Error(c, fiber.StatusInternalServerError, "failed to fetch package")
+	}
+
+	tenantID, _ := c.Locals("tenant_id").(string)
+	h.funnel.Track(ctx, tenantID, userID, pkg.ID, domain.SalesStagePageView, "")
+	return c.SendStatus(fiber.StatusNoContent)
 }
 
 // PackageResponse represents a payment package for the frontend
 type PackageResponse struct {
-	ID             string `json:"id"`
-	Name           string `json:"name"`
-	Description    string `json:"description"`
-	Price          int64  `json:"price"`
-	DurationMonths int    `json:"duration_months"`
+	ID             string       `json:"id"`
+	Name           string       `json:"name"`
+	Description    string       `json:"description"`
+	Price          domain.Money `json:"price"`
+	DurationMonths int          `json:"duration_months"`
 }
 
 // ListPackages handles GET /api/member/payments/packages
@@ -250,16 +240,13 @@ func (h *PaymentHandler) ListPackages(c *fiber.Ctx) error {
 	packages, err := h.packageRepo.GetActivePackages(ctx)
 	if err != nil {
 		log.Printf("[ListPackages] Error fetching packages: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "failed to fetch packages",
-		})
+		return response.Error(c, fiber.StatusInternalServerError, "failed to fetch packages")
 	}
 
 	// Map to response format
-	var response []PackageResponse
+	var list []PackageResponse
 	for _, pkg := range packages {
-		response = append(response, PackageResponse{
+		list = append(list, PackageResponse{
 			ID:             pkg.ID,
 			Name:           pkg.Name,
 			Description:    pkg.Description,
@@ -268,8 +255,5 @@ func (h *PaymentHandler) ListPackages(c *fiber.Ctx) error {
 		})
 	}
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    response,
-	})
+	return response.OK(c, list)
 }
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// PBRulesHandler serves the tenant's rules for which sets count as personal bests
+type PBRulesHandler struct {
+	detector   *service.PersonalBestDetector
+	tenantRepo domain.TenantRepository
+}
+
+func NewPBRulesHandler(detector *service.PersonalBestDetector, tenantRepo domain.TenantRepository) *PBRulesHandler {
+	return &PBRulesHandler{detector: detector, tenantRepo: tenantRepo}
+}
+
+// GetRules GET /v1/tenant-admin/pb-rules
+func (h *PBRulesHandler) GetRules(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+
+	tenant, err := h.tenantRepo.GetByID(c.UserContext(), tenantID)
+	if err != nil {
+		return pbRulesError(c, err)
+	}
+	return response.OK(c, fiber.Map{
+		"rules":      tenant.PersonalBestRules(),
+		"default":    domain.DefaultPBRules,
+		"is_default": tenant.PBRules == nil,
+	})
+}
+
+// UpdateRules PUT /v1/tenant-admin/pb-rules
+// Body: the rules, or {"reset": true} for the defaults. Every member's PBs are rebuilt
+// under the new rules in the background; progress shows in the job history.
+func (h *PBRulesHandler) UpdateRules(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+
+	var req struct {
+		domain.PBRules
+		Reset bool `json:"reset"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	rules := &req.PBRules
+	if req.Reset {
+		rules = nil
+	}
+
+	saved, err := h.detector.SetRules(c.UserContext(), tenantID, rules)
+	if err != nil {
+		return pbRulesError(c, err)
+	}
+	return response.JSON(c, fiber.StatusAccepted, fiber.Map{
+		"rules":      saved,
+		"is_default": rules == nil,
+	})
+}
+
+// Rebuild POST /v1/tenant-admin/pb-rules/rebuild
+// Recomputes every member's PBs under the current rules, e.g. after set logs were corrected
+func (h *PBRulesHandler) Rebuild(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+
+	h.detector.RebuildInBackground(tenantID)
+	return response.JSON(c, fiber.StatusAccepted, fiber.Map{"message": "PB rebuild started"})
+}
+
+func pbRulesError(c *fiber.Ctx, err error) error {
+	switch {
+	case err == domain.ErrNotFound:
+		return response.Error(c, fiber.StatusNotFound, "Tenant not found")
+	case errors.Is(err, domain.ErrInvalidPBRules):
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	return response.Fail(c, err)
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/config"
+	"github.com/mansoorceksport/metamorph/internal/jobs"
+	"github.com/mansoorceksport/metamorph/internal/response"
+)
+
+// PlatformConfigHandler lets operators check what a deployment is actually running
+type PlatformConfigHandler struct {
+	cfg       *config.Config
+	scheduler *jobs.Scheduler
+	startedAt time.Time
+}
+
+func NewPlatformConfigHandler(cfg *config.Config, scheduler *jobs.Scheduler) *PlatformConfigHandler {
+	return &PlatformConfigHandler{cfg: cfg, scheduler: scheduler, startedAt: time.Now().UTC()}
+}
+
+// GetConfig GET /v1/platform/config
+// Returns build info, the background jobs this instance runs and the effective
+// configuration without secrets
+func (h *PlatformConfigHandler) GetConfig(c *fiber.Ctx) error {
+	return response.OK(c, fiber.Map{
+		"build":      config.Build(),
+		"started_at": h.startedAt,
+		"jobs":       h.scheduler.Names(),
+		"config":     h.cfg.Public(),
+	})
+}
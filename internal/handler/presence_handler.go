@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// PresenceHandler takes the coach app's heartbeats and serves the tenant's presence board
+type PresenceHandler struct {
+	presenceService *service.PresenceService
+}
+
+func NewPresenceHandler(presenceService *service.PresenceService) *PresenceHandler {
+	return &PresenceHandler{presenceService: presenceService}
+}
+
+// Heartbeat POST /v1/pro/presence
+// Sent by the coach app every 30 seconds while open; a coach silent for two minutes is offline
+func (h *PresenceHandler) Heartbeat(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("userID").(string)
+
+	if err := h.presenceService.Heartbeat(c.UserContext(), tenantID, userID); err != nil {
+		return response.Fail(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetBoard GET /v1/tenant-admin/dashboard/presence
+// Which coaches are in session, online or offline, with the session each has running now
+func (h *PresenceHandler) GetBoard(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	board, err := h.presenceService.Board(c.UserContext(), tenantID)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, board)
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// presentUser serializes a user with the fields the caller's role may see of them (see
+// domain.UserFieldPolicy). Callers see all of their own record.
+func presentUser(c *fiber.Ctx, user *domain.User) fiber.Map {
+	policy := domain.UserFieldPolicy
+	if userID, _ := c.Locals("userID").(string); userID != "" && userID == user.ID {
+		policy = domain.FieldPolicy{domain.ViewerRole(callerRoles(c)): nil}
+	}
+	fields, err := policy.Filter(user, callerRoles(c))
+	if err != nil {
+		log.Printf("Warning: failed to serialize user %s: %v", user.ID, err)
+		return fiber.Map{"id": user.ID}
+	}
+	return fields
+}
+
+// presentUsers serializes each user with presentUser
+func presentUsers(c *fiber.Ctx, users []*domain.User) []fiber.Map {
+	out := make([]fiber.Map, 0, len(users))
+	for _, user := range users {
+		out = append(out, presentUser(c, user))
+	}
+	return out
+}
+
+// presentUserPage serializes a page of users with presentUser
+func presentUserPage(c *fiber.Ctx, page *domain.Page[*domain.User]) *domain.Page[fiber.Map] {
+	return &domain.Page[fiber.Map]{Items: presentUsers(c, page.Items), HasMore: page.HasMore, NextCursor: page.NextCursor}
+}
+
+// callerRoles returns the roles of the caller's token
+func callerRoles(c *fiber.Ctx) []string {
+	roles, _ := c.Locals("roles").([]string)
+	return roles
+}
+
+// memberVisibility returns which of their metrics the member may see (see
+// domain.MetricVisibility). Should that be unknown, everything a coach could hide is hidden.
+func memberVisibility(c *fiber.Ctx, ptService *service.PTService, memberID string) domain.MetricVisibility {
+	visibility, err := ptService.MemberMetricVisibility(c.UserContext(), memberID)
+	if err != nil {
+		log.Printf("Warning: failed to resolve metric visibility of member %s: %v", memberID, err)
+		return domain.MetricVisibility{VolumeOnly: true}
+	}
+	return visibility
+}
@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
 	"github.com/mansoorceksport/metamorph/internal/service"
 )
 
@@ -20,6 +23,10 @@ type ProHandler struct {
 	inbodyRepo       domain.InBodyRepository       // For fetching scan records
 	workoutService   *service.WorkoutService       // For volume history
 	schedRepo        domain.ScheduleRepository     // For hydration
+	documentService  *service.DocumentService      // For waiver compliance on client profiles
+	assessments      *service.AssessmentService    // For the latest fitness test on client profiles
+	guardians        *service.GuardianService      // For flagging minors
+	customFields     *service.CustomFieldService   // For the tenant's member and contract fields
 	maxUploadMB      int64
 }
 
@@ -33,6 +40,10 @@ func NewProHandler(
 	inbodyRepo domain.InBodyRepository,
 	workoutService *service.WorkoutService,
 	schedRepo domain.ScheduleRepository,
+	documentService *service.DocumentService,
+	assessments *service.AssessmentService,
+	guardians *service.GuardianService,
+	customFields *service.CustomFieldService,
 	maxUploadMB int64,
 ) *ProHandler {
 	return &ProHandler{
@@ -45,6 +56,10 @@ func NewProHandler(
 		inbodyRepo:       inbodyRepo,
 		workoutService:   workoutService,
 		schedRepo:        schedRepo,
+		documentService:  documentService,
+		assessments:      assessments,
+		guardians:        guardians,
+		customFields:     customFields,
 		maxUploadMB:      maxUploadMB,
 	}
 }
@@ -61,6 +76,7 @@ type ClientResponse struct {
 	AttendanceTrend   string `json:"attendance_trend"`
 	LastSessionDate   string `json:"last_session_date,omitempty"`
 	TotalSessions     int    `json:"total_sessions"`
+	Minor             bool   `json:"minor,omitempty"`
 }
 
 // SimpleClientResponse is a lightweight response for the /members list page
@@ -70,6 +86,22 @@ type SimpleClientResponse struct {
 	Name              string `json:"name"`
 	RemainingSessions int    `json:"remaining_sessions"`
 	TotalSessions     int    `json:"total_sessions"`
+	Minor             bool   `json:"minor,omitempty"`
+}
+
+// minorCheck tells which members are minors in the caller's tenant. Flags are left off
+// when that can't be worked out, rather than failing the list.
+func (h *ProHandler) minorCheck(c *fiber.Ctx) func(*domain.User) bool {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	if h.guardians == nil || tenantID == "" {
+		return func(*domain.User) bool { return false }
+	}
+	isMinor, err := h.guardians.MinorCheck(c.UserContext(), tenantID)
+	if err != nil {
+		fmt.Printf("Warning: Failed to check minors: %v\n", err)
+		return func(*domain.User) bool { return false }
+	}
+	return isMinor
 }
 
 // GetClients handles GET /v1/pro/clients
@@ -80,7 +112,7 @@ func (h *ProHandler) GetClients(c *fiber.Ctx) error {
 	// Query 1: Get contracts with member info (aggregation)
 	contractsWithMembers, err := h.ptService.GetActiveContractsWithMembers(c.UserContext(), coachID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	// Collect all contract IDs for batch query
@@ -100,6 +132,7 @@ func (h *ProHandler) GetClients(c *fiber.Ctx) error {
 	}
 
 	// Deduplicate by member (a member may have multiple contracts)
+	isMinor := h.minorCheck(c)
 	memberMap := make(map[string]*ClientResponse)
 	for _, cwm := range contractsWithMembers {
 		if cwm.Member == nil || cwm.Contract == nil {
@@ -127,6 +160,7 @@ func (h *ProHandler) GetClients(c *fiber.Ctx) error {
 				ChurnScore:        50, // TODO: Compute from attendance patterns
 				AttendanceTrend:   "stable",
 				TotalSessions:     cwm.Contract.TotalSessions,
+				Minor:             isMinor(cwm.Member),
 			}
 		} else {
 			// Add remaining sessions from additional contracts
@@ -145,7 +179,7 @@ func (h *ProHandler) GetClients(c *fiber.Ctx) error {
 		clients = append(clients, client)
 	}
 
-	return c.JSON(clients)
+	return response.OK(c, clients)
 }
 
 // GetClientsSimple handles GET /v1/pro/clients/simple
@@ -157,10 +191,11 @@ func (h *ProHandler) GetClientsSimple(c *fiber.Ctx) error {
 	// Use the same aggregation but skip expensive schedule count computation
 	contractsWithMembers, err := h.ptService.GetActiveContractsWithMembers(c.UserContext(), coachID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	// Deduplicate by member, return simple response
+	isMinor := h.minorCheck(c)
 	memberMap := make(map[string]*SimpleClientResponse)
 	for _, cwm := range contractsWithMembers {
 		if cwm.Member == nil || cwm.Contract == nil {
@@ -176,6 +211,7 @@ func (h *ProHandler) GetClientsSimple(c *fiber.Ctx) error {
 				Name:              cwm.Member.Name,
 				RemainingSessions: cwm.Contract.RemainingSessions,
 				TotalSessions:     cwm.Contract.TotalSessions,
+				Minor:             isMinor(cwm.Member),
 			}
 		} else {
 			// Aggregate across multiple contracts
@@ -190,7 +226,7 @@ func (h *ProHandler) GetClientsSimple(c *fiber.Ctx) error {
 		clients = append(clients, client)
 	}
 
-	return c.JSON(clients)
+	return response.OK(c, clients)
 }
 
 // GetClientHistory handles GET /v1/pro/clients/:id/history
@@ -202,7 +238,7 @@ func (h *ProHandler) GetClientHistory(c *fiber.Ctx) error {
 	// Verify access: Does coach have ANY active contract with this member?
 	contracts, err := h.ptService.GetActiveContractsByCoach(c.UserContext(), coachID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	hasAccess := false
@@ -214,7 +250,7 @@ func (h *ProHandler) GetClientHistory(c *fiber.Ctx) error {
 	}
 
 	if !hasAccess {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Not authorized to view this client"})
+		return response.Error(c, fiber.StatusForbidden, "Not authorized to view this client")
 	}
 
 	// Get History
@@ -226,10 +262,10 @@ func (h *ProHandler) GetClientHistory(c *fiber.Ctx) error {
 
 	history, err := h.analyticsService.GetHistory(c.UserContext(), clientID, limit)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.JSON(history)
+	return response.OK(c, history)
 }
 
 // GetDashboardSummary handles GET /v1/pro/dashboard/summary
@@ -239,20 +275,21 @@ func (h *ProHandler) GetDashboardSummary(c *fiber.Ctx) error {
 
 	summary, err := h.dashboardService.GetCoachSummary(c.UserContext(), coachID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.JSON(summary)
+	return response.OK(c, summary)
 }
 
 // ScheduleWithMemberName represents a schedule with denormalized member name
 type ScheduleWithMemberName struct {
 	*domain.Schedule
-	MemberName string `json:"member_name"`
+	MemberName         string `json:"member_name"`
+	ConfirmationStatus string `json:"confirmation_status,omitempty"` // confirmed, declined or unconfirmed
 }
 
 // GetMySchedules handles GET /v1/pro/schedules
-// Returns coach's schedules for a date range, with member names
+// Returns coach's schedules for a date range, with member names. Optional: tag
 func (h *ProHandler) GetMySchedules(c *fiber.Ctx) error {
 	coachID := c.Locals("userID").(string)
 
@@ -266,7 +303,7 @@ func (h *ProHandler) GetMySchedules(c *fiber.Ctx) error {
 	if fromStr != "" {
 		from, err = time.Parse("2006-01-02", fromStr)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid 'from' date format, use YYYY-MM-DD"})
+			return response.Error(c, fiber.StatusBadRequest, "Invalid 'from' date format, use YYYY-MM-DD")
 		}
 	} else {
 		// Default to start of current day
@@ -277,7 +314,7 @@ func (h *ProHandler) GetMySchedules(c *fiber.Ctx) error {
 	if toStr != "" {
 		to, err = time.Parse("2006-01-02", toStr)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid 'to' date format, use YYYY-MM-DD"})
+			return response.Error(c, fiber.StatusBadRequest, "Invalid 'to' date format, use YYYY-MM-DD")
 		}
 		// End of that day
 		to = to.Add(24*time.Hour - time.Second)
@@ -288,8 +325,9 @@ func (h *ProHandler) GetMySchedules(c *fiber.Ctx) error {
 
 	schedules, err := h.ptService.GetSchedules(c.UserContext(), "coach", coachID, from, to)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
+	schedules = filterSchedulesByTag(schedules, c.Query("tag"))
 
 	// Fetch member names for each schedule
 	result := make([]*ScheduleWithMemberName, 0, len(schedules))
@@ -308,12 +346,13 @@ func (h *ProHandler) GetMySchedules(c *fiber.Ctx) error {
 		}
 
 		result = append(result, &ScheduleWithMemberName{
-			Schedule:   schedule,
-			MemberName: memberName,
+			Schedule:           schedule,
+			MemberName:         memberName,
+			ConfirmationStatus: schedule.ConfirmationStatus(),
 		})
 	}
 
-	return c.JSON(result)
+	return response.OK(c, result)
 }
 
 // HydrateSchedules handles GET /v1/pro/schedules/hydrate
@@ -331,7 +370,7 @@ func (h *ProHandler) HydrateSchedules(c *fiber.Ctx) error {
 	if fromStr != "" {
 		from, err = time.Parse("2006-01-02", fromStr)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid 'from' date format, use YYYY-MM-DD"})
+			return response.Error(c, fiber.StatusBadRequest, "Invalid 'from' date format, use YYYY-MM-DD")
 		}
 	} else {
 		// Default to 10 days ago
@@ -342,7 +381,7 @@ func (h *ProHandler) HydrateSchedules(c *fiber.Ctx) error {
 	if toStr != "" {
 		to, err = time.Parse("2006-01-02", toStr)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid 'to' date format, use YYYY-MM-DD"})
+			return response.Error(c, fiber.StatusBadRequest, "Invalid 'to' date format, use YYYY-MM-DD")
 		}
 		// End of that day
 		to = to.Add(24*time.Hour - time.Second)
@@ -355,7 +394,7 @@ func (h *ProHandler) HydrateSchedules(c *fiber.Ctx) error {
 	// Use schedRepo directly to get ALL statuses (including cancelled)
 	schedules, err := h.schedRepo.GetByCoachAllStatuses(c.UserContext(), coachID, from, to)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	// Fetch member names for each schedule
@@ -375,12 +414,13 @@ func (h *ProHandler) HydrateSchedules(c *fiber.Ctx) error {
 		}
 
 		result = append(result, &ScheduleWithMemberName{
-			Schedule:   schedule,
-			MemberName: memberName,
+			Schedule:           schedule,
+			MemberName:         memberName,
+			ConfirmationStatus: schedule.ConfirmationStatus(),
 		})
 	}
 
-	return c.JSON(result)
+	return response.OK(c, result)
 }
 
 // GetMemberPBs handles GET /v1/pro/members/:member_id/pbs
@@ -392,7 +432,7 @@ func (h *ProHandler) GetMemberPBs(c *fiber.Ctx) error {
 	// Verify access: Coach must have an active contract with this member
 	contracts, err := h.ptService.GetActiveContractsByCoach(c.UserContext(), coachID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	hasAccess := false
@@ -404,13 +444,13 @@ func (h *ProHandler) GetMemberPBs(c *fiber.Ctx) error {
 	}
 
 	if !hasAccess {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Not authorized to view this member's PBs"})
+		return response.Error(c, fiber.StatusForbidden, "Not authorized to view this member's PBs")
 	}
 
 	// Fetch PBs
 	pbs, err := h.pbRepo.GetByMember(c.UserContext(), memberID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	// Return empty array if no PBs
@@ -418,7 +458,7 @@ func (h *ProHandler) GetMemberPBs(c *fiber.Ctx) error {
 		pbs = []*domain.PersonalBest{}
 	}
 
-	return c.JSON(pbs)
+	return response.OK(c, pbs)
 }
 
 // CreateMember handles POST /v1/pro/members
@@ -428,42 +468,64 @@ func (h *ProHandler) CreateMember(c *fiber.Ctx) error {
 	coachID := c.Locals("userID").(string)
 
 	var req struct {
-		Email     string `json:"email"`
-		Name      string `json:"name"`
-		PackageID string `json:"package_id"` // Optional: if provided, creates contract
+		Email       string `json:"email"`
+		Name        string `json:"name"`
+		PackageID   string `json:"package_id"`    // Optional: if provided, creates contract
+		DateOfBirth string `json:"date_of_birth"` // Optional: YYYY-MM-DD
+
+		CustomFields         map[string]interface{} `json:"custom_fields"`          // The tenant's member fields, by key
+		ContractCustomFields map[string]interface{} `json:"contract_custom_fields"` // And contract fields, with package_id
 	}
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
 	if req.Email == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Email is required"})
+		return response.Error(c, fiber.StatusBadRequest, "Email is required")
 	}
 	if req.Name == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Name is required"})
+		return response.Error(c, fiber.StatusBadRequest, "Name is required")
+	}
+	dob, err := domain.ParseDateOfBirth(req.DateOfBirth, time.Now())
+	if err != nil {
+		return response.FailAs(c, fiber.StatusBadRequest, err)
 	}
 
 	// Get Coach's TenantID from JWT context
 	tenantID := c.Locals("tenant_id")
 	if tenantID == nil || tenantID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "TenantID not found in token"})
+		return response.Error(c, fiber.StatusUnauthorized, "TenantID not found in token")
 	}
 	tID := tenantID.(string)
 
+	customFields, err := h.customFields.Apply(c.UserContext(), tID, domain.CustomFieldEntityMember, nil, req.CustomFields)
+	if err != nil {
+		return customFieldError(c, err)
+	}
+	var contractFields domain.CustomFieldValues
+	if req.PackageID != "" {
+		contractFields, err = h.customFields.Apply(c.UserContext(), tID, domain.CustomFieldEntityContract, nil, req.ContractCustomFields)
+		if err != nil {
+			return customFieldError(c, err)
+		}
+	}
+
 	// Create user with strictly 'member' role
 	user := &domain.User{
-		Email:    req.Email,
-		Name:     req.Name,
-		Roles:    []string{domain.RoleMember},
-		TenantID: tID,
+		Email:        req.Email,
+		Name:         req.Name,
+		Roles:        []string{domain.RoleMember},
+		TenantID:     tID,
+		DateOfBirth:  dob,
+		CustomFields: customFields,
 	}
 
 	if err := h.userRepo.Create(c.UserContext(), user); err != nil {
 		// Check for duplicate key error (email already exists)
 		if strings.Contains(err.Error(), "E11000") || strings.Contains(err.Error(), "duplicate key") {
-			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "A member with this email already exists"})
+			return response.Error(c, fiber.StatusConflict, "A member with this email already exists")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	// If package_id provided, create contract
@@ -473,37 +535,38 @@ func (h *ProHandler) CreateMember(c *fiber.Ctx) error {
 		pkg, err := h.ptService.GetPackageTemplate(c.UserContext(), req.PackageID)
 		if err != nil {
 			// Member created but package not found - return member with warning
-			return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-				"member":  user,
+			return response.Created(c, fiber.Map{
+				"member":  presentUser(c, user),
 				"warning": "Package not found, member created without contract",
 			})
 		}
 		if pkg.TenantID != tID {
-			return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-				"member":  user,
+			return response.Created(c, fiber.Map{
+				"member":  presentUser(c, user),
 				"warning": "Package does not belong to your tenant, member created without contract",
 			})
 		}
 
 		// Create contract
 		contract = &domain.PTContract{
-			PackageID: req.PackageID,
-			MemberID:  user.ID,
-			CoachID:   coachID,
-			BranchID:  pkg.BranchID,
-			TenantID:  tID,
+			PackageID:    req.PackageID,
+			MemberID:     user.ID,
+			CoachID:      coachID,
+			BranchID:     pkg.BranchID,
+			TenantID:     tID,
+			CustomFields: contractFields,
 		}
 
 		if err := h.ptService.CreateContract(c.UserContext(), contract); err != nil {
-			return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-				"member":  user,
+			return response.Created(c, fiber.Map{
+				"member":  presentUser(c, user),
 				"warning": fmt.Sprintf("Failed to create contract: %s", err.Error()),
 			})
 		}
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"member":   user,
+	return response.Created(c, fiber.Map{
+		"member":   presentUser(c, user),
 		"contract": contract,
 	})
 }
@@ -513,38 +576,38 @@ func (h *ProHandler) CreateMember(c *fiber.Ctx) error {
 func (h *ProHandler) DigitizeMemberScan(c *fiber.Ctx) error {
 	memberID := c.Params("id")
 	if memberID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Member ID is required"})
+		return response.Error(c, fiber.StatusBadRequest, "Member ID is required")
 	}
 
 	// Verify member exists and belongs to same tenant
 	tenantID := c.Locals("tenant_id")
 	if tenantID == nil || tenantID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "TenantID not found in token"})
+		return response.Error(c, fiber.StatusUnauthorized, "TenantID not found in token")
 	}
 	tID := tenantID.(string)
 
 	member, err := h.userRepo.GetByID(c.UserContext(), memberID)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Member not found"})
+			return response.Error(c, fiber.StatusNotFound, "Member not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	if member.TenantID != tID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Member does not belong to your tenant"})
+		return response.Fail(c, domain.ErrTenantScopeViolation)
 	}
 
 	// Parse multipart form
 	form, err := c.MultipartForm()
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid multipart form: " + err.Error()})
+		return response.FailAs(c, fiber.StatusBadRequest, fmt.Errorf("Invalid multipart form: %w", err))
 	}
 
 	// Get image file
 	files := form.File["image"]
 	if len(files) == 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Missing 'image' field in form data"})
+		return response.Error(c, fiber.StatusBadRequest, "Missing 'image' field in form data")
 	}
 
 	imageFile := files[0]
@@ -552,25 +615,25 @@ func (h *ProHandler) DigitizeMemberScan(c *fiber.Ctx) error {
 	// Validate file size
 	maxBytes := h.maxUploadMB * 1024 * 1024
 	if imageFile.Size > maxBytes {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("File size exceeds maximum of %dMB", h.maxUploadMB)})
+		return response.Error(c, fiber.StatusBadRequest, fmt.Sprintf("File size exceeds maximum of %dMB", h.maxUploadMB))
 	}
 
 	// Validate MIME type
 	if !isValidImageType(imageFile) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid file type, only JPEG, PNG, and HEIC images are allowed"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid file type, only JPEG, PNG, and HEIC images are allowed")
 	}
 
 	// Read file contents
 	fileHandle, err := imageFile.Open()
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to open uploaded file"})
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to open uploaded file")
 	}
 	defer fileHandle.Close()
 
 	imageData := make([]byte, imageFile.Size)
 	_, err = fileHandle.Read(imageData)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to read uploaded file"})
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to read uploaded file")
 	}
 
 	imageURL := imageFile.Filename
@@ -578,13 +641,78 @@ func (h *ProHandler) DigitizeMemberScan(c *fiber.Ctx) error {
 	// Process the scan for the MEMBER (not the coach)
 	record, err := h.scanService.ProcessScan(c.UserContext(), memberID, imageData, imageURL)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to process scan: " + err.Error()})
+		if errors.Is(err, domain.ErrHealthConsentRequired) {
+			return response.FailAs(c, fiber.StatusForbidden, err)
+		}
+		return response.Fail(c, fmt.Errorf("Failed to process scan: %w", err))
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"data":    record,
-	})
+	return response.OK(c, record)
+}
+
+// ImportMemberScansCSV handles POST /v1/pro/members/:id/scans/import-csv
+// Imports a member's scan history from a LookinBody CSV export. Test dates are read in
+// the optional "timezone" form field, UTC by default.
+func (h *ProHandler) ImportMemberScansCSV(c *fiber.Ctx) error {
+	memberID := c.Params("id")
+	if memberID == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Member ID is required")
+	}
+
+	tenantID := c.Locals("tenant_id")
+	if tenantID == nil || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "TenantID not found in token")
+	}
+
+	member, err := h.userRepo.GetByID(c.UserContext(), memberID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return response.Error(c, fiber.StatusNotFound, "Member not found")
+		}
+		return response.Fail(c, err)
+	}
+	if member.TenantID != tenantID.(string) {
+		return response.Fail(c, domain.ErrTenantScopeViolation)
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Missing 'file' field in form data")
+	}
+	if file.Size > h.maxUploadMB*1024*1024 {
+		return response.Error(c, fiber.StatusBadRequest, fmt.Sprintf("File size exceeds maximum of %dMB", h.maxUploadMB))
+	}
+
+	loc := time.UTC
+	if tz := c.FormValue("timezone"); tz != "" {
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid timezone")
+		}
+	}
+
+	fileHandle, err := file.Open()
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to open uploaded file")
+	}
+	defer fileHandle.Close()
+
+	data, err := io.ReadAll(fileHandle)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to read uploaded file")
+	}
+
+	report, err := h.scanService.ImportCSV(c.UserContext(), memberID, data, loc)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidScanCSV) {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		}
+		if errors.Is(err, domain.ErrHealthConsentRequired) {
+			return response.FailAs(c, fiber.StatusForbidden, err)
+		}
+		return response.Fail(c, fmt.Errorf("Failed to import scans: %w", err))
+	}
+
+	return response.OK(c, report)
 }
 
 // GetMember handles GET /v1/pro/members/:id
@@ -592,13 +720,13 @@ func (h *ProHandler) DigitizeMemberScan(c *fiber.Ctx) error {
 func (h *ProHandler) GetMember(c *fiber.Ctx) error {
 	memberID := c.Params("id")
 	if memberID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Member ID is required"})
+		return response.Error(c, fiber.StatusBadRequest, "Member ID is required")
 	}
 
 	// Get tenant from JWT
 	tenantID := c.Locals("tenant_id")
 	if tenantID == nil || tenantID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "TenantID not found in token"})
+		return response.Error(c, fiber.StatusUnauthorized, "TenantID not found in token")
 	}
 	tID := tenantID.(string)
 
@@ -606,14 +734,14 @@ func (h *ProHandler) GetMember(c *fiber.Ctx) error {
 	member, err := h.userRepo.GetByID(c.Context(), memberID)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Member not found"})
+			return response.Error(c, fiber.StatusNotFound, "Member not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	// Validate tenant
 	if member.TenantID != tID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Member does not belong to your tenant"})
+		return response.Fail(c, domain.ErrTenantScopeViolation)
 	}
 
 	// Get contracts for this member with the coach
@@ -636,18 +764,51 @@ func (h *ProHandler) GetMember(c *fiber.Ctx) error {
 		fmt.Printf("Warning: Failed to get member schedule stats: %v\n", statsErr)
 	}
 
-	return c.JSON(fiber.Map{
-		"id":                 member.ID,
-		"name":               member.Name,
-		"email":              member.Email,
-		"contracts":          contracts,
-		"remaining_sessions": totalRemaining,
-		"schedule_stats": fiber.Map{
-			"completed": completed,
-			"cancelled": cancelled,
-			"no_show":   noShow,
-		},
-	})
+	// Waiver compliance so coaches know whether the member can be booked
+	var documents *domain.DocumentCompliance
+	if h.documentService != nil {
+		documents, err = h.documentService.GetCompliance(c.Context(), tID, memberID)
+		if err != nil {
+			fmt.Printf("Warning: Failed to get member document compliance: %v\n", err)
+		}
+	}
+
+	// Latest fitness test and body composition, shown side by side on the profile
+	var latestAssessment *domain.Assessment
+	if h.assessments != nil {
+		latestAssessment, err = h.assessments.Latest(c.Context(), tID, memberID)
+		if err != nil {
+			fmt.Printf("Warning: Failed to get member's latest assessment: %v\n", err)
+		}
+	}
+	// Age restrictions, so coaches know a guardian's consent is needed before booking
+	var minor *domain.MinorStatus
+	if h.guardians != nil {
+		minor, err = h.guardians.Status(c.Context(), tID, memberID)
+		if err != nil {
+			fmt.Printf("Warning: Failed to get member's minor status: %v\n", err)
+		}
+	}
+	var latestScan *domain.InBodyRecord
+	if scans, err := h.inbodyRepo.GetByUserID(c.Context(), memberID, 1); err != nil {
+		fmt.Printf("Warning: Failed to get member's latest scan: %v\n", err)
+	} else if len(scans) > 0 {
+		latestScan = scans[0]
+	}
+
+	detail := presentUser(c, member)
+	detail["contracts"] = contracts
+	detail["remaining_sessions"] = totalRemaining
+	detail["schedule_stats"] = fiber.Map{
+		"completed": completed,
+		"cancelled": cancelled,
+		"no_show":   noShow,
+	}
+	detail["documents"] = documents
+	detail["latest_assessment"] = latestAssessment
+	detail["latest_scan"] = latestScan
+	detail["minor"] = minor
+	return response.OK(c, detail)
 }
 
 // ListPackages handles GET /v1/pro/packages
@@ -655,16 +816,16 @@ func (h *ProHandler) GetMember(c *fiber.Ctx) error {
 func (h *ProHandler) ListPackages(c *fiber.Ctx) error {
 	tenantID := c.Locals("tenant_id")
 	if tenantID == nil || tenantID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "TenantID not found in token"})
+		return response.Error(c, fiber.StatusUnauthorized, "TenantID not found in token")
 	}
 	tID := tenantID.(string)
 
 	packages, err := h.ptService.GetPackageTemplatesByTenant(c.Context(), tID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.JSON(packages)
+	return response.OK(c, packages)
 }
 
 // CreateContract handles POST /v1/pro/contracts
@@ -673,64 +834,71 @@ func (h *ProHandler) CreateContract(c *fiber.Ctx) error {
 	coachID := c.Locals("userID").(string)
 	tenantID := c.Locals("tenant_id")
 	if tenantID == nil || tenantID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "TenantID not found in token"})
+		return response.Error(c, fiber.StatusUnauthorized, "TenantID not found in token")
 	}
 	tID := tenantID.(string)
 
 	var req struct {
-		MemberID  string `json:"member_id"`
-		PackageID string `json:"package_id"`
+		MemberID     string                 `json:"member_id"`
+		PackageID    string                 `json:"package_id"`
+		CustomFields map[string]interface{} `json:"custom_fields"` // The tenant's contract fields, by key
 	}
 
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
 	if req.MemberID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Member ID is required"})
+		return response.Error(c, fiber.StatusBadRequest, "Member ID is required")
 	}
 	if req.PackageID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Package ID is required"})
+		return response.Error(c, fiber.StatusBadRequest, "Package ID is required")
 	}
 
 	// Validate member belongs to tenant
 	member, err := h.userRepo.GetByID(c.Context(), req.MemberID)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Member not found"})
+			return response.Error(c, fiber.StatusNotFound, "Member not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 	if member.TenantID != tID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Member does not belong to your tenant"})
+		return response.Fail(c, domain.ErrTenantScopeViolation)
 	}
 
 	// Validate package belongs to tenant
 	pkg, err := h.ptService.GetPackageTemplate(c.Context(), req.PackageID)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Package not found"})
+			return response.Error(c, fiber.StatusNotFound, "Package not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 	if pkg.TenantID != tID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Package does not belong to your tenant"})
+		return response.Fail(c, domain.ErrTenantScopeViolation)
+	}
+
+	customFields, err := h.customFields.Apply(c.Context(), tID, domain.CustomFieldEntityContract, nil, req.CustomFields)
+	if err != nil {
+		return customFieldError(c, err)
 	}
 
 	// Create contract
 	contract := &domain.PTContract{
-		PackageID: req.PackageID,
-		MemberID:  req.MemberID,
-		CoachID:   coachID,
-		BranchID:  pkg.BranchID, // Use package's branch
-		TenantID:  tID,
+		PackageID:    req.PackageID,
+		MemberID:     req.MemberID,
+		CoachID:      coachID,
+		BranchID:     pkg.BranchID, // Use package's branch
+		TenantID:     tID,
+		CustomFields: customFields,
 	}
 
 	if err := h.ptService.CreateContract(c.Context(), contract); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(contract)
+	return response.Created(c, contract)
 }
 
 // GetMemberScans handles GET /v1/pro/members/:id/scans
@@ -738,13 +906,13 @@ func (h *ProHandler) CreateContract(c *fiber.Ctx) error {
 func (h *ProHandler) GetMemberScans(c *fiber.Ctx) error {
 	memberID := c.Params("id")
 	if memberID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Member ID is required"})
+		return response.Error(c, fiber.StatusBadRequest, "Member ID is required")
 	}
 
 	// Get tenant from JWT
 	tenantID := c.Locals("tenant_id")
 	if tenantID == nil || tenantID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "TenantID not found in token"})
+		return response.Error(c, fiber.StatusUnauthorized, "TenantID not found in token")
 	}
 	tID := tenantID.(string)
 
@@ -752,21 +920,21 @@ func (h *ProHandler) GetMemberScans(c *fiber.Ctx) error {
 	member, err := h.userRepo.GetByID(c.Context(), memberID)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Member not found"})
+			return response.Error(c, fiber.StatusNotFound, "Member not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 	if member.TenantID != tID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Member does not belong to your tenant"})
+		return response.Fail(c, domain.ErrTenantScopeViolation)
 	}
 
 	// Fetch scans for member
 	scans, err := h.inbodyRepo.GetByUserID(c.Context(), memberID, 50) // Limit to 50 scans
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.JSON(scans)
+	return response.OK(c, scans)
 }
 
 // GetScan handles GET /v1/pro/scans/:id
@@ -774,28 +942,29 @@ func (h *ProHandler) GetMemberScans(c *fiber.Ctx) error {
 func (h *ProHandler) GetScan(c *fiber.Ctx) error {
 	scanID := c.Params("id")
 	if scanID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Scan ID is required"})
+		return response.Error(c, fiber.StatusBadRequest, "Scan ID is required")
 	}
 
 	// Fetch scan
 	scan, err := h.inbodyRepo.FindByID(c.Context(), scanID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Scan not found"})
+		return response.Error(c, fiber.StatusNotFound, "Scan not found")
 	}
 
 	// Get tenant from JWT and validate member belongs to tenant
 	tenantID := c.Locals("tenant_id")
 	if tenantID == nil || tenantID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "TenantID not found in token"})
+		return response.Error(c, fiber.StatusUnauthorized, "TenantID not found in token")
 	}
 	tID := tenantID.(string)
 
-	member, err := h.userRepo.GetByID(c.Context(), scan.UserID.Hex())
+	member, err := h.userRepo.GetByID(c.Context(), scan.UserID)
 	if err != nil || member.TenantID != tID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Access denied"})
+		return response.Error(c, fiber.StatusForbidden, "Access denied")
 	}
 
-	return c.JSON(scan)
+	setETag(c, scan.Version)
+	return response.OK(c, scan)
 }
 
 // UpdateScan handles PUT /v1/pro/scans/:id
@@ -803,32 +972,48 @@ func (h *ProHandler) GetScan(c *fiber.Ctx) error {
 func (h *ProHandler) UpdateScan(c *fiber.Ctx) error {
 	scanID := c.Params("id")
 	if scanID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Scan ID is required"})
+		return response.Error(c, fiber.StatusBadRequest, "Scan ID is required")
 	}
 
 	// Fetch existing scan
 	scan, err := h.inbodyRepo.FindByID(c.Context(), scanID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Scan not found"})
+		return response.Error(c, fiber.StatusNotFound, "Scan not found")
 	}
 
 	// Validate tenant ownership
 	tenantID := c.Locals("tenant_id")
 	if tenantID == nil || tenantID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "TenantID not found in token"})
+		return response.Error(c, fiber.StatusUnauthorized, "TenantID not found in token")
 	}
 	tID := tenantID.(string)
 
-	member, err := h.userRepo.GetByID(c.Context(), scan.UserID.Hex())
+	member, err := h.userRepo.GetByID(c.Context(), scan.UserID)
 	if err != nil || member.TenantID != tID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Access denied"})
+		return response.Error(c, fiber.StatusForbidden, "Access denied")
 	}
 
 	// Parse update request - allow partial updates
-	var req domain.InBodyRecord
+	var req struct {
+		domain.InBodyRecord
+		Version *int64 `json:"version"` // Alternative to the If-Match header; 0 is a version like any other
+	}
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Optimistic concurrency: If-Match (or a body "version") must match the stored version
+	version, hasVersion, err := ifMatchVersion(c)
+	if err != nil {
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	if !hasVersion && req.Version != nil {
+		version, hasVersion = *req.Version, true
+	}
+	if hasVersion && version != scan.Version {
+		return response.Fail(c, domain.ErrVersionConflict)
 	}
+	original := *scan
 
 	// Update only non-zero values from request
 	// Core metrics
@@ -885,12 +1070,17 @@ func (h *ProHandler) UpdateScan(c *fiber.Ctx) error {
 		scan.SegmentalFat = req.SegmentalFat
 	}
 
-	// Save updates
-	if err := h.inbodyRepo.Update(c.Context(), scanID, scan); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	// Save updates; the values they replace are kept as a revision
+	coachID, _ := c.Locals("userID").(string)
+	if err := h.scanService.SaveCorrection(c.UserContext(), &original, scan, coachID); err != nil {
+		if err == domain.ErrVersionConflict {
+			return response.FailAs(c, fiber.StatusConflict, err)
+		}
+		return response.Fail(c, err)
 	}
 
-	return c.JSON(scan)
+	setETag(c, scan.Version)
+	return response.OK(c, scan)
 }
 
 // DeleteScan handles DELETE /v1/pro/scans/:id
@@ -898,33 +1088,133 @@ func (h *ProHandler) UpdateScan(c *fiber.Ctx) error {
 func (h *ProHandler) DeleteScan(c *fiber.Ctx) error {
 	scanID := c.Params("id")
 	if scanID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Scan ID is required"})
+		return response.Error(c, fiber.StatusBadRequest, "Scan ID is required")
 	}
 
 	// Fetch scan to validate ownership
 	scan, err := h.inbodyRepo.FindByID(c.Context(), scanID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Scan not found"})
+		return response.Error(c, fiber.StatusNotFound, "Scan not found")
 	}
 
 	// Validate tenant ownership
 	tenantID := c.Locals("tenant_id")
 	if tenantID == nil || tenantID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "TenantID not found in token"})
+		return response.Error(c, fiber.StatusUnauthorized, "TenantID not found in token")
 	}
 	tID := tenantID.(string)
 
-	member, err := h.userRepo.GetByID(c.Context(), scan.UserID.Hex())
+	member, err := h.userRepo.GetByID(c.Context(), scan.UserID)
 	if err != nil || member.TenantID != tID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Access denied"})
+		return response.Error(c, fiber.StatusForbidden, "Access denied")
 	}
 
 	// Delete scan
 	if err := h.inbodyRepo.Delete(c.Context(), scanID); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
+	}
+
+	return response.OK(c, fiber.Map{"message": "Scan deleted"})
+}
+
+// ReExtractScan handles POST /v1/pro/scans/:id/re-extract
+// Runs the stored image through the current AI prompt and model again. The values it
+// replaces, including coach corrections, stay available as a revision.
+func (h *ProHandler) ReExtractScan(c *fiber.Ctx) error {
+	scanID := c.Params("id")
+	if scanID == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Scan ID is required")
+	}
+
+	scan, err := h.inbodyRepo.FindByID(c.Context(), scanID)
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, "Scan not found")
+	}
+
+	tenantID := c.Locals("tenant_id")
+	if tenantID == nil || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "TenantID not found in token")
+	}
+	member, err := h.userRepo.GetByID(c.Context(), scan.UserID)
+	if err != nil || member.TenantID != tenantID.(string) {
+		return response.Error(c, fiber.StatusForbidden, "Access denied")
+	}
+
+	coachID, _ := c.Locals("userID").(string)
+	record, err := h.scanService.ReExtract(c.UserContext(), scanID, coachID)
+	if err != nil {
+		switch err {
+		case domain.ErrScanImageUnavailable:
+			return response.FailAs(c, fiber.StatusConflict, err)
+		case domain.ErrHealthConsentRequired:
+			return response.FailAs(c, fiber.StatusForbidden, err)
+		case domain.ErrVersionConflict:
+			return response.Error(c, fiber.StatusConflict, "Scan was edited during re-extraction, try again")
+		}
+		return response.Fail(c, fmt.Errorf("Failed to re-extract scan: %w", err))
+	}
+
+	setETag(c, record.Version)
+	return response.OK(c, record)
+}
+
+// GetScanRevisions handles GET /v1/pro/scans/:id/revisions
+// Lists who changed the scan's values, when, and what they were before
+func (h *ProHandler) GetScanRevisions(c *fiber.Ctx) error {
+	scanID := c.Params("id")
+	scan, err := h.inbodyRepo.FindByID(c.Context(), scanID)
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, "Scan not found")
+	}
+
+	tenantID := c.Locals("tenant_id")
+	if tenantID == nil || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "TenantID not found in token")
+	}
+	member, err := h.userRepo.GetByID(c.Context(), scan.UserID)
+	if err != nil || member.TenantID != tenantID.(string) {
+		return response.Error(c, fiber.StatusForbidden, "Access denied")
+	}
+
+	revisions, err := h.scanService.ListRevisions(c.UserContext(), scanID)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, revisions)
+}
+
+// RevertScan handles POST /v1/pro/scans/:id/revisions/:revision_id/revert
+// Restores the values kept in a revision; the values it replaces become a new revision
+func (h *ProHandler) RevertScan(c *fiber.Ctx) error {
+	scanID := c.Params("id")
+	scan, err := h.inbodyRepo.FindByID(c.Context(), scanID)
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, "Scan not found")
+	}
+
+	tenantID := c.Locals("tenant_id")
+	if tenantID == nil || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "TenantID not found in token")
+	}
+	member, err := h.userRepo.GetByID(c.Context(), scan.UserID)
+	if err != nil || member.TenantID != tenantID.(string) {
+		return response.Error(c, fiber.StatusForbidden, "Access denied")
+	}
+
+	coachID, _ := c.Locals("userID").(string)
+	record, err := h.scanService.RevertScan(c.UserContext(), scanID, c.Params("revision_id"), coachID)
+	if err != nil {
+		switch err {
+		case domain.ErrScanRevisionNotFound:
+			return response.FailAs(c, fiber.StatusNotFound, err)
+		case domain.ErrVersionConflict:
+			return response.FailAs(c, fiber.StatusConflict, err)
+		}
+		return response.Fail(c, err)
 	}
 
-	return c.JSON(fiber.Map{"success": true, "message": "Scan deleted"})
+	setETag(c, record.Version)
+	return response.OK(c, record)
 }
 
 // GetMemberVolumeHistory handles GET /v1/pro/members/:id/volume-history
@@ -932,7 +1222,7 @@ func (h *ProHandler) DeleteScan(c *fiber.Ctx) error {
 func (h *ProHandler) GetMemberVolumeHistory(c *fiber.Ctx) error {
 	memberID := c.Params("id")
 	if memberID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Member ID required"})
+		return response.Error(c, fiber.StatusBadRequest, "Member ID required")
 	}
 
 	// Get query params
@@ -942,7 +1232,7 @@ func (h *ProHandler) GetMemberVolumeHistory(c *fiber.Ctx) error {
 	// Get volume history (optionally filtered by focus area)
 	volumes, err := h.workoutService.GetMemberVolumeHistory(c.Context(), memberID, limit, focusArea)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	// Build response
@@ -955,9 +1245,9 @@ func (h *ProHandler) GetMemberVolumeHistory(c *fiber.Ctx) error {
 		ExerciseCount int     `json:"exercise_count"`
 	}
 
-	response := make([]VolumePoint, len(volumes))
+	points := make([]VolumePoint, len(volumes))
 	for i, v := range volumes {
-		response[i] = VolumePoint{
+		points[i] = VolumePoint{
 			Date:          v.Date.Format("2006-01-02"),
 			TotalVolume:   v.TotalVolume,
 			TotalSets:     v.TotalSets,
@@ -967,5 +1257,5 @@ func (h *ProHandler) GetMemberVolumeHistory(c *fiber.Ctx) error {
 		}
 	}
 
-	return c.JSON(fiber.Map{"volumes": response})
+	return response.OK(c, fiber.Map{"volumes": points})
 }
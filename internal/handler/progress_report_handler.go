@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// ProgressReportHandler generates client progress reports for coaches
+type ProgressReportHandler struct {
+	reportService *service.ProgressReportService
+}
+
+func NewProgressReportHandler(reportService *service.ProgressReportService) *ProgressReportHandler {
+	return &ProgressReportHandler{reportService: reportService}
+}
+
+// ProgressReportRequest picks the report's period. Dates are YYYY-MM-DD; to defaults to today.
+type ProgressReportRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// GenerateReport POST /v1/pro/members/:id/report
+// Returns the report's data and a signed link to the PDF the coach can share with the member
+func (h *ProgressReportHandler) GenerateReport(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	var req ProgressReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	from, err := time.Parse("2006-01-02", req.From)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "from must be a date like 2025-06-01")
+	}
+	to := time.Now().UTC()
+	if req.To != "" {
+		if to, err = time.Parse("2006-01-02", req.To); err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "to must be a date like 2025-06-30")
+		}
+	}
+
+	report, err := h.reportService.Generate(c.UserContext(), tenantID, c.Params("id"), from, to)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidReportPeriod):
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrInvalidID):
+			return response.Error(c, fiber.StatusNotFound, "Member not found")
+		case errors.Is(err, domain.ErrNotTenantMember):
+			return response.Fail(c, domain.ErrTenantScopeViolation)
+		}
+		return response.Fail(c, err)
+	}
+	return response.Created(c, report)
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+const (
+	progressHistoryWeeks   = 12
+	maxLeaderboardSize     = 50
+	defaultLeaderboardSize = 10
+)
+
+// ProgressScoreHandler serves the weekly progress scores and their tenant weighting
+type ProgressScoreHandler struct {
+	scoreService *service.ProgressScoreService
+	tenantRepo   domain.TenantRepository
+}
+
+func NewProgressScoreHandler(scoreService *service.ProgressScoreService, tenantRepo domain.TenantRepository) *ProgressScoreHandler {
+	return &ProgressScoreHandler{scoreService: scoreService, tenantRepo: tenantRepo}
+}
+
+// GetMyProgress GET /v1/me/progress-score
+func (h *ProgressScoreHandler) GetMyProgress(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+	return h.memberScores(c, tenantID, userID)
+}
+
+// GetMemberProgress GET /v1/pro/members/:id/progress-score
+func (h *ProgressScoreHandler) GetMemberProgress(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	return h.memberScores(c, tenantID, c.Params("id"))
+}
+
+func (h *ProgressScoreHandler) memberScores(c *fiber.Ctx, tenantID, memberID string) error {
+	scores, err := h.scoreService.MemberScores(c.UserContext(), tenantID, memberID, progressHistoryWeeks)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	var latest *domain.ProgressScore
+	if len(scores) > 0 {
+		latest = scores[0]
+	}
+	return response.OK(c, fiber.Map{
+		"latest":  latest,
+		"history": scores,
+	})
+}
+
+// GetLeaderboard GET /v1/pro/progress-scores/leaderboard?limit=10
+// Ranks the tenant's members by last week's score
+func (h *ProgressScoreHandler) GetLeaderboard(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	limit := c.QueryInt("limit", defaultLeaderboardSize)
+	if limit < 1 || limit > maxLeaderboardSize {
+		limit = defaultLeaderboardSize
+	}
+
+	entries, err := h.scoreService.Leaderboard(c.UserContext(), tenantID, limit)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, entries)
+}
+
+// GetWeights GET /v1/tenant-admin/progress-score-weights
+func (h *ProgressScoreHandler) GetWeights(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+
+	tenant, err := h.tenantRepo.GetByID(c.UserContext(), tenantID)
+	if err != nil {
+		return progressScoreError(c, err)
+	}
+	return response.OK(c, fiber.Map{
+		"weights":    tenant.ProgressScoreWeights(),
+		"default":    domain.DefaultProgressWeights,
+		"is_default": tenant.ProgressWeights == nil,
+	})
+}
+
+// UpdateWeights PUT /v1/tenant-admin/progress-score-weights
+// Body: the four weights, or {"reset": true} for the defaults. Takes effect from the next
+// scoring run; past weeks keep the weights they were scored with.
+func (h *ProgressScoreHandler) UpdateWeights(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+
+	var req struct {
+		domain.ProgressWeights
+		Reset bool `json:"reset"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	weights := &req.ProgressWeights
+	if req.Reset {
+		weights = nil
+	}
+
+	saved, err := h.scoreService.SetWeights(c.UserContext(), tenantID, weights)
+	if err != nil {
+		return progressScoreError(c, err)
+	}
+	return response.OK(c, fiber.Map{
+		"weights":    saved,
+		"is_default": weights == nil,
+	})
+}
+
+func progressScoreError(c *fiber.Ctx, err error) error {
+	switch {
+	case err == domain.ErrNotFound:
+		return response.Error(c, fiber.StatusNotFound, "Tenant not found")
+	case errors.Is(err, domain.ErrInvalidProgressWeights):
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	return response.Fail(c, err)
+}
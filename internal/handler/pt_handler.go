@@ -1,10 +1,15 @@
 package handler
 
 import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
 	"github.com/mansoorceksport/metamorph/internal/service"
 )
 
@@ -13,14 +18,16 @@ type PTHandler struct {
 	branchRepo     domain.BranchRepository
 	userRepo       domain.UserRepository
 	workoutService *service.WorkoutService // For volume aggregation on completion
+	customFields   *service.CustomFieldService
 }
 
-func NewPTHandler(ptService *service.PTService, branchRepo domain.BranchRepository, userRepo domain.UserRepository, workoutService *service.WorkoutService) *PTHandler {
+func NewPTHandler(ptService *service.PTService, branchRepo domain.BranchRepository, userRepo domain.UserRepository, workoutService *service.WorkoutService, customFields *service.CustomFieldService) *PTHandler {
 	return &PTHandler{
 		ptService:      ptService,
 		branchRepo:     branchRepo,
 		userRepo:       userRepo,
 		workoutService: workoutService,
+		customFields:   customFields,
 	}
 }
 
@@ -30,35 +37,36 @@ func NewPTHandler(ptService *service.PTService, branchRepo domain.BranchReposito
 func (h *PTHandler) CreatePackageTemplate(c *fiber.Ctx) error {
 	tenantID, ok := c.Locals("tenant_id").(string)
 	if !ok || tenantID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Missing tenant context"})
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
 	}
 
 	var req struct {
-		Name          string  `json:"name"`
-		TotalSessions int     `json:"total_sessions"`
-		Price         float64 `json:"price"`
-		BranchID      string  `json:"branch_id"` // Optional? Or required? Usually required for packages.
+		Name          string       `json:"name"`
+		TotalSessions int          `json:"total_sessions"`
+		Price         domain.Money `json:"price"`
+		BranchID      string       `json:"branch_id"` // Optional? Or required? Usually required for packages.
+
+		BranchPrices   []domain.BranchPrice `json:"branch_prices"`   // Optional: prices at branches that charge differently
+		ValidityMonths int                  `json:"validity_months"` // Optional: unused sessions expire this long after purchase
 	}
 
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
 	if req.Name == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Package name is required"})
+		return response.Error(c, fiber.StatusBadRequest, "Package name is required")
 	}
 
 	// Validate Branch (if provided)
 	if req.BranchID != "" {
-		branch, err := h.branchRepo.GetByID(c.UserContext(), req.BranchID)
-		if err != nil {
-			if err == domain.ErrNotFound {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Branch not found"})
-			}
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to validate branch"})
+		if status, msg := h.tenantBranchError(c.UserContext(), tenantID, req.BranchID); status != 0 {
+			return response.Error(c, status, msg)
 		}
-		if branch.TenantID != tenantID {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Branch does not belong to this tenant"})
+	}
+	for _, bp := range req.BranchPrices {
+		if status, msg := h.tenantBranchError(c.UserContext(), tenantID, bp.BranchID); status != 0 {
+			return response.Error(c, status, msg)
 		}
 	}
 
@@ -68,31 +76,34 @@ func (h *PTHandler) CreatePackageTemplate(c *fiber.Ctx) error {
 		BranchID:      req.BranchID,
 		TotalSessions: req.TotalSessions,
 		Price:         req.Price,
+		BranchPrices:  req.BranchPrices,
+
+		ValidityMonths: req.ValidityMonths,
 	}
 
 	if err := h.ptService.CreatePackageTemplate(c.UserContext(), pkg); err != nil {
-		if err == domain.ErrInvalidSessionAmount {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		if err == domain.ErrInvalidSessionAmount || err == domain.ErrInvalidBranchPrice || err == domain.ErrInvalidPackageValidity {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(pkg)
+	return response.Created(c, pkg)
 }
 
 // ListPackageTemplates GET /v1/tenant-admin/packages
 func (h *PTHandler) ListPackageTemplates(c *fiber.Ctx) error {
 	tenantID, ok := c.Locals("tenant_id").(string)
 	if !ok || tenantID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Missing tenant context"})
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
 	}
 
 	packages, err := h.ptService.GetPackageTemplatesByTenant(c.UserContext(), tenantID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.JSON(packages)
+	return response.OK(c, packages)
 }
 
 // GetPackageTemplate GET /v1/tenant-admin/packages/:id
@@ -101,11 +112,11 @@ func (h *PTHandler) GetPackageTemplate(c *fiber.Ctx) error {
 	pkg, err := h.ptService.GetPackageTemplate(c.UserContext(), id)
 	if err != nil {
 		if err == domain.ErrPackageTemplateNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Package not found"})
+			return response.Error(c, fiber.StatusNotFound, "Package not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
-	return c.JSON(pkg)
+	return response.OK(c, pkg)
 }
 
 // UpdatePackageTemplate PUT /v1/tenant-admin/packages/:id
@@ -113,81 +124,125 @@ func (h *PTHandler) UpdatePackageTemplate(c *fiber.Ctx) error {
 	id := c.Params("id")
 	var req domain.PTPackage
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	tenantID, _ := c.Locals("tenant_id").(string)
+	for _, bp := range req.BranchPrices {
+		if status, msg := h.tenantBranchError(c.UserContext(), tenantID, bp.BranchID); status != 0 {
+			return response.Error(c, status, msg)
+		}
 	}
 
 	req.ID = id
 	if err := h.ptService.UpdatePackageTemplate(c.UserContext(), &req); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		if err == domain.ErrInvalidSessionAmount || err == domain.ErrInvalidBranchPrice || err == domain.ErrInvalidPackageValidity {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		}
+		return response.Fail(c, err)
 	}
 
-	return c.JSON(req)
+	return response.OK(c, req)
+}
+
+// tenantBranchError returns the status and message to answer with unless the branch
+// belongs to the tenant, and zero if it does
+func (h *PTHandler) tenantBranchError(ctx context.Context, tenantID, branchID string) (int, string) {
+	branch, err := h.branchRepo.GetByID(ctx, branchID)
+	if err != nil {
+		if err == domain.ErrNotFound || err == domain.ErrInvalidID {
+			return fiber.StatusBadRequest, "Branch not found"
+		}
+		return fiber.StatusInternalServerError, "Failed to validate branch"
+	}
+	if branch.TenantID != tenantID {
+		return fiber.StatusBadRequest, "Branch does not belong to this tenant"
+	}
+	return 0, ""
 }
 
 // --- Tenant Admin: Contracts (Assignment) ---
 
+// CreateContractRequest assigns a package to a member
+type CreateContractRequest struct {
+	PackageID string `json:"package_id"`
+	MemberID  string `json:"member_id"`
+	CoachID   string `json:"coach_id"`
+	BranchID  string `json:"branch_id"`
+
+	CustomFields map[string]interface{} `json:"custom_fields"` // The tenant's contract fields, by key
+}
+
 // CreateContract POST /v1/tenant-admin/contracts
 func (h *PTHandler) CreateContract(c *fiber.Ctx) error {
 	tenantID, ok := c.Locals("tenant_id").(string)
 	if !ok || tenantID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Missing tenant context"})
-	}
-
-	var req struct {
-		PackageID string `json:"package_id"`
-		MemberID  string `json:"member_id"`
-		CoachID   string `json:"coach_id"`
-		BranchID  string `json:"branch_id"`
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
 	}
 
+	var req CreateContractRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	customFields, err := h.customFields.Apply(c.UserContext(), tenantID, domain.CustomFieldEntityContract, nil, req.CustomFields)
+	if err != nil {
+		return customFieldError(c, err)
 	}
 
 	contract := &domain.PTContract{
-		PackageID: req.PackageID,
-		MemberID:  req.MemberID,
-		CoachID:   req.CoachID,
-		BranchID:  req.BranchID,
-		TenantID:  tenantID,
+		PackageID:    req.PackageID,
+		MemberID:     req.MemberID,
+		CoachID:      req.CoachID,
+		BranchID:     req.BranchID,
+		TenantID:     tenantID,
+		CustomFields: customFields,
 	}
 
 	if err := h.ptService.CreateContract(c.UserContext(), contract); err != nil {
 		if err == domain.ErrBranchMismatch {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+			return response.FailAs(c, fiber.StatusBadRequest, err)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(contract)
+	return response.Created(c, contract)
 }
 
 // ListContracts GET /v1/tenant-admin/contracts
+// Optional: limit, cursor (returns a page instead of the full list)
 func (h *PTHandler) ListContracts(c *fiber.Ctx) error {
 	tenantID, ok := c.Locals("tenant_id").(string)
 	if !ok || tenantID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Missing tenant context"})
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
 	}
+	if q, ok := pageQuery(c); ok {
+		page, err := h.ptService.ListContractsPage(c.UserContext(), tenantID, q)
+		if err != nil {
+			return pageError(c, err)
+		}
+		return response.OK(c, page)
+	}
+
 	// Future: Filters from query params
 	contracts, err := h.ptService.GetContractsByTenant(c.UserContext(), tenantID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
-	return c.JSON(contracts)
+	return response.OK(c, contracts)
 }
 
 // GetMyContracts GET /v1/me/contracts
 func (h *PTHandler) GetMyContracts(c *fiber.Ctx) error {
 	memberID, ok := c.Locals("userID").(string)
 	if !ok || memberID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Missing user context"})
+		return response.Error(c, fiber.StatusUnauthorized, "Missing user context")
 	}
 
 	contracts, err := h.ptService.GetActiveContractsByMember(c.UserContext(), memberID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
-	return c.JSON(contracts)
+	return response.OK(c, contracts)
 }
 
 // GetContract GET /v1/contracts/:id (Admin/Coach/Member)
@@ -196,12 +251,95 @@ func (h *PTHandler) GetContract(c *fiber.Ctx) error {
 	contract, err := h.ptService.GetContract(c.UserContext(), id)
 	if err != nil {
 		if err == domain.ErrContractNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Contract not found"})
+			return response.Error(c, fiber.StatusNotFound, "Contract not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 	// Todo: Auth check ownership?
-	return c.JSON(contract)
+	return response.OK(c, contract)
+}
+
+// GetMyContractStatement GET /v1/me/contracts/:id/statement
+// Itemized ledger of every credit movement on the member's contract
+func (h *PTHandler) GetMyContractStatement(c *fiber.Ctx) error {
+	memberID, ok := c.Locals("userID").(string)
+	if !ok || memberID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing user context")
+	}
+
+	statement, err := h.ptService.GetContractStatement(c.UserContext(), c.Params("id"))
+	if err != nil {
+		if err == domain.ErrContractNotFound || err == domain.ErrInvalidID {
+			return response.Error(c, fiber.StatusNotFound, "Contract not found")
+		}
+		return response.Fail(c, err)
+	}
+	if statement.Contract.MemberID != memberID {
+		return response.Error(c, fiber.StatusNotFound, "Contract not found")
+	}
+
+	return response.OK(c, statement)
+}
+
+// GetContractStatement GET /v1/tenant-admin/contracts/:id/statement
+func (h *PTHandler) GetContractStatement(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+
+	statement, err := h.ptService.GetContractStatement(c.UserContext(), c.Params("id"))
+	if err != nil {
+		if err == domain.ErrContractNotFound || err == domain.ErrInvalidID {
+			return response.Error(c, fiber.StatusNotFound, "Contract not found")
+		}
+		return response.Fail(c, err)
+	}
+	if statement.Contract.TenantID != tenantID {
+		return response.Error(c, fiber.StatusNotFound, "Contract not found")
+	}
+
+	return response.OK(c, statement)
+}
+
+// AdjustContractCredits POST /v1/tenant-admin/contracts/:id/credits
+// Records a refund, freeze/unfreeze, expiry, or manual adjustment
+func (h *PTHandler) AdjustContractCredits(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+	actorID, _ := c.Locals("userID").(string)
+
+	var req struct {
+		Type   string `json:"type"`   // refunded, frozen, unfrozen, expired, adjusted
+		Amount int    `json:"amount"` // Positive; signed only for "adjusted"
+		Note   string `json:"note"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	contract, err := h.ptService.GetContract(c.UserContext(), c.Params("id"))
+	if err != nil || contract.TenantID != tenantID {
+		if err == nil || err == domain.ErrContractNotFound || err == domain.ErrInvalidID {
+			return response.Error(c, fiber.StatusNotFound, "Contract not found")
+		}
+		return response.Fail(c, err)
+	}
+
+	txn, err := h.ptService.AdjustCredits(c.UserContext(), contract.ID, req.Type, req.Amount, req.Note, actorID)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidCreditType, domain.ErrInvalidCreditAmount, domain.ErrInsufficientCredits:
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		case domain.ErrLockNotAcquired:
+			return response.FailAs(c, fiber.StatusConflict, err)
+		}
+		return response.Fail(c, err)
+	}
+
+	return response.Created(c, txn)
 }
 
 // --- Pro/Member: Schedules ---
@@ -217,25 +355,26 @@ func (h *PTHandler) CreateSchedule(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(string)
 	if !ok || userID == "" {
 		println("[DEBUG] CreateSchedule - Missing userID")
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Missing user context"})
+		return response.Error(c, fiber.StatusUnauthorized, "Missing user context")
 	}
 	println("[DEBUG] CreateSchedule - userID:", userID)
 
 	tenantID, _ := c.Locals("tenant_id").(string)
 	println("[DEBUG] CreateSchedule - tenantID:", tenantID)
 
-	// Fetch user to get current HomeBranchID (dynamic lookup)
+	// Fetch user to get current branches (dynamic lookup), as held in the token's tenant
 	user, err := h.userRepo.GetByID(c.UserContext(), userID)
 	if err != nil {
 		println("[DEBUG] CreateSchedule - Failed to fetch user:", err.Error())
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Failed to fetch user profile"})
+		return response.Error(c, fiber.StatusUnauthorized, "Failed to fetch user profile")
+	}
+	if scoped, err := user.ScopedTo(tenantID); err == nil {
+		user = scoped
 	}
-	homeBranchID := user.HomeBranchID
-	println("[DEBUG] CreateSchedule - homeBranchID:", homeBranchID)
 
-	if homeBranchID == "" {
+	if len(user.CoachBranchIDs()) == 0 {
 		println("[DEBUG] CreateSchedule - No HomeBranchID")
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Coach must be assigned to a Home Branch"})
+		return response.Error(c, fiber.StatusForbidden, "Coach must be assigned to a Home Branch")
 	}
 
 	var req struct {
@@ -247,11 +386,17 @@ func (h *PTHandler) CreateSchedule(c *fiber.Ctx) error {
 		SessionGoal string    `json:"session_goal"` // e.g., "Leg Day - Hypertrophy Focus"
 		FocusArea   string    `json:"focus_area"`   // LEG_DAY, UPPER_BODY, etc.
 		Remarks     string    `json:"remarks"`      // Optional coach notes
+		BranchID    string    `json:"branch_id"`    // Optional: one of the coach's branches; defaults to the contract's
+		Tags        []string  `json:"tags"`         // Optional: "assessment", "trial", ...
+		Label       string    `json:"label"`        // Optional calendar caption
+		Color       string    `json:"color"`        // Optional calendar color, "#RRGGBB"
+		Modality    string    `json:"modality"`     // Optional: in_person (default) or online
+		MeetingURL  string    `json:"meeting_url"`  // Optional for online sessions; generated when a provider is configured
 	}
 
 	if err := c.BodyParser(&req); err != nil {
 		println("[DEBUG] CreateSchedule - BodyParser error:", err.Error())
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
 	println("[DEBUG] CreateSchedule - Parsed request:")
@@ -263,11 +408,11 @@ func (h *PTHandler) CreateSchedule(c *fiber.Ctx) error {
 	// Validate required fields
 	if req.MemberID == "" {
 		println("[DEBUG] CreateSchedule - MemberID is empty")
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "member_id is required"})
+		return response.Error(c, fiber.StatusBadRequest, "member_id is required")
 	}
 	if req.StartTime.IsZero() {
 		println("[DEBUG] CreateSchedule - StartTime is zero")
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "start_time is required"})
+		return response.Error(c, fiber.StatusBadRequest, "start_time is required")
 	}
 
 	// Validate focus_area if provided
@@ -280,26 +425,33 @@ func (h *PTHandler) CreateSchedule(c *fiber.Ctx) error {
 			}
 		}
 		if !validFocus {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Invalid focus_area. Must be one of: LEG_DAY, UPPER_BODY, BACK_DAY, CHEST_DAY, FULL_BODY, FUNCTIONAL, CORE, OTHER",
-			})
+			return response.Error(c, fiber.StatusBadRequest, "Invalid focus_area. Must be one of: LEG_DAY, UPPER_BODY, BACK_DAY, CHEST_DAY, FULL_BODY, FUNCTIONAL, CORE, OTHER")
 		}
 	}
 
 	// Auto-resolve contract_id if not provided
 	contractID := req.ContractID
+	contractBranchID := ""
 	if contractID == "" {
 		println("[DEBUG] CreateSchedule - Resolving contract for coach:", userID, "member:", req.MemberID)
 		contract, err := h.ptService.GetFirstActiveContractByCoachAndMember(c.UserContext(), userID, req.MemberID)
 		if err != nil {
 			println("[DEBUG] CreateSchedule - Contract resolution failed:", err.Error())
 			if err == domain.ErrContractNotFound {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "No active contract found for this member"})
+				return response.Error(c, fiber.StatusBadRequest, "No active contract found for this member")
 			}
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to resolve contract: " + err.Error()})
+			return response.Fail(c, fmt.Errorf("Failed to resolve contract: %w", err))
 		}
 		contractID = contract.ID
+		contractBranchID = contract.BranchID
 		println("[DEBUG] CreateSchedule - Resolved contractID:", contractID)
+	} else if contract, err := h.ptService.GetContract(c.UserContext(), contractID); err == nil {
+		contractBranchID = contract.BranchID
+	}
+
+	branchID, err := user.ScheduleBranch(req.BranchID, contractBranchID)
+	if err != nil {
+		return response.FailAs(c, fiber.StatusForbidden, err)
 	}
 
 	// Default end time to +1 hour if not provided
@@ -314,32 +466,47 @@ func (h *PTHandler) CreateSchedule(c *fiber.Ctx) error {
 		CoachID:     userID, // The creator (Pro) is the coach
 		MemberID:    req.MemberID,
 		TenantID:    tenantID,
-		BranchID:    homeBranchID,
+		BranchID:    branchID,
 		StartTime:   req.StartTime,
 		EndTime:     endTime,
 		SessionGoal: req.SessionGoal,
 		FocusArea:   req.FocusArea,
 		Remarks:     req.Remarks,
+		Tags:        req.Tags,
+		Label:       req.Label,
+		Color:       req.Color,
+		Modality:    req.Modality,
+		MeetingURL:  req.MeetingURL,
 	}
 
 	if err := h.ptService.CreateSchedule(c.UserContext(), schedule); err != nil {
 		println("[DEBUG] CreateSchedule - ptService.CreateSchedule failed:", err.Error())
 		if err == domain.ErrPackageDepleted {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+			return response.FailAs(c, fiber.StatusBadRequest, err)
 		}
 		if err == domain.ErrBranchMismatch {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+			return response.FailAs(c, fiber.StatusBadRequest, err)
 		}
-		if err == domain.ErrContractNotFound {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		if err == domain.ErrOutsideAvailability {
+			return response.FailAs(c, fiber.StatusConflict, err)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		if err == domain.ErrContractNotFound || err == domain.ErrInvalidScheduleTags || err == domain.ErrInvalidScheduleLabel ||
+			err == domain.ErrInvalidModality || err == domain.ErrInvalidMeetingURL {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		}
+		if err == domain.ErrRequiredDocumentsUnsigned || err == domain.ErrGuardianConsentRequired {
+			return response.FailAs(c, fiber.StatusPreconditionFailed, err)
+		}
+		if err == domain.ErrContractSuspended {
+			return response.FailAs(c, fiber.StatusPaymentRequired, err)
+		}
+		return response.Fail(c, err)
 	}
 
 	println("[DEBUG] CreateSchedule - Success! ID:", schedule.ID)
 
 	// Return schedule with client_id for dual-identity handshake
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+	return response.Created(c, fiber.Map{
 		"id":           schedule.ID,
 		"client_id":    req.ClientID,
 		"contract_id":  schedule.ContractID,
@@ -352,15 +519,123 @@ func (h *PTHandler) CreateSchedule(c *fiber.Ctx) error {
 		"session_goal": schedule.SessionGoal,
 		"focus_area":   schedule.FocusArea,
 		"remarks":      schedule.Remarks,
+		"tags":         schedule.Tags,
+		"label":        schedule.Label,
+		"color":        schedule.Color,
+		"modality":     schedule.Modality,
+		"meeting_url":  schedule.MeetingURL,
 		"status":       schedule.Status,
 	})
 }
 
+// CreateScheduleBatch POST /v1/pro/schedules/bulk
+// Books every slot of a program at once. The fields outside slots apply to each session;
+// contract_id can be resolved from member_id as in CreateSchedule. Slots that can't be
+// booked are listed under skipped with the reason.
+func (h *PTHandler) CreateScheduleBatch(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing user context")
+	}
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	user, err := h.userRepo.GetByID(c.UserContext(), userID)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, "Failed to fetch user profile")
+	}
+	if scoped, err := user.ScopedTo(tenantID); err == nil {
+		user = scoped
+	}
+	if len(user.CoachBranchIDs()) == 0 {
+		return response.Error(c, fiber.StatusForbidden, "Coach must be assigned to a Home Branch")
+	}
+
+	var req struct {
+		ContractID  string                `json:"contract_id"` // Optional if member_id provided
+		MemberID    string                `json:"member_id"`   // Required
+		SessionGoal string                `json:"session_goal"`
+		FocusArea   string                `json:"focus_area"`
+		Remarks     string                `json:"remarks"`
+		BranchID    string                `json:"branch_id"`
+		Tags        []string              `json:"tags"`
+		Label       string                `json:"label"`
+		Color       string                `json:"color"`
+		Modality    string                `json:"modality"`
+		Slots       []domain.ScheduleSlot `json:"slots"` // start_time, optional end_time (+1 hour), client_id, session_goal, focus_area
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if req.MemberID == "" {
+		return response.Error(c, fiber.StatusBadRequest, "member_id is required")
+	}
+	for _, focus := range append([]string{req.FocusArea}, slotFocusAreas(req.Slots)...) {
+		if focus != "" && !slices.Contains(domain.ValidFocusAreas, focus) {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid focus_area: "+focus)
+		}
+	}
+
+	contractID, contractBranchID := req.ContractID, ""
+	if contractID == "" {
+		contract, err := h.ptService.GetFirstActiveContractByCoachAndMember(c.UserContext(), userID, req.MemberID)
+		if err == domain.ErrContractNotFound {
+			return response.Error(c, fiber.StatusBadRequest, "No active contract found for this member")
+		}
+		if err != nil {
+			return response.Fail(c, fmt.Errorf("Failed to resolve contract: %w", err))
+		}
+		contractID, contractBranchID = contract.ID, contract.BranchID
+	} else if contract, err := h.ptService.GetContract(c.UserContext(), contractID); err == nil {
+		contractBranchID = contract.BranchID
+	}
+	branchID, err := user.ScheduleBranch(req.BranchID, contractBranchID)
+	if err != nil {
+		return response.FailAs(c, fiber.StatusForbidden, err)
+	}
+
+	base := &domain.Schedule{
+		ContractID:  contractID,
+		CoachID:     userID,
+		MemberID:    req.MemberID,
+		TenantID:    tenantID,
+		BranchID:    branchID,
+		SessionGoal: req.SessionGoal,
+		FocusArea:   req.FocusArea,
+		Remarks:     req.Remarks,
+		Tags:        req.Tags,
+		Label:       req.Label,
+		Color:       req.Color,
+		Modality:    req.Modality,
+	}
+	result, err := h.ptService.CreateScheduleBatch(c.UserContext(), base, req.Slots)
+	if err != nil {
+		switch err {
+		case domain.ErrPackageDepleted, domain.ErrBranchMismatch, domain.ErrContractNotFound, domain.ErrInvalidScheduleBatch,
+			domain.ErrInvalidScheduleTags, domain.ErrInvalidScheduleLabel, domain.ErrInvalidModality:
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		case domain.ErrRequiredDocumentsUnsigned, domain.ErrGuardianConsentRequired:
+			return response.FailAs(c, fiber.StatusPreconditionFailed, err)
+		case domain.ErrContractSuspended:
+			return response.FailAs(c, fiber.StatusPaymentRequired, err)
+		}
+		return response.Fail(c, err)
+	}
+	return response.Created(c, result)
+}
+
+func slotFocusAreas(slots []domain.ScheduleSlot) []string {
+	areas := make([]string, len(slots))
+	for i, slot := range slots {
+		areas[i] = slot.FocusArea
+	}
+	return areas
+}
+
 // RescheduleSession PATCH /v1/schedules/:id/reschedule
 func (h *PTHandler) RescheduleSession(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(string)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+		return response.Error(c, fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Determine role
@@ -390,25 +665,25 @@ func (h *PTHandler) RescheduleSession(c *fiber.Ctx) error {
 		EndTime   time.Time `json:"end_time"`
 	}
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid body")
 	}
 
 	err := h.ptService.RescheduleSession(c.UserContext(), scheduleID, req.StartTime, req.EndTime, actorRole, userID)
 	if err != nil {
 		if err == domain.ErrUnauthorizedReschedule {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+			return response.FailAs(c, fiber.StatusForbidden, err)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.JSON(fiber.Map{"message": "Reschedule processed", "status": "updated"})
+	return response.OK(c, fiber.Map{"message": "Reschedule processed", "status": "updated"})
 }
 
 // CompleteSession POST /v1/pro/schedules/:id/complete
 func (h *PTHandler) CompleteSession(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(string)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+		return response.Error(c, fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	scheduleID := c.Params("id")
@@ -417,38 +692,39 @@ func (h *PTHandler) CompleteSession(c *fiber.Ctx) error {
 	schedule, err := h.ptService.GetSchedule(c.UserContext(), scheduleID)
 	if err != nil {
 		if err == domain.ErrScheduleNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Schedule not found"})
+			return response.Error(c, fiber.StatusNotFound, "Schedule not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	// Complete the session
 	if err := h.ptService.CompleteSession(c.Context(), scheduleID, userID); err != nil {
 		if err == domain.ErrScheduleNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Schedule not found"})
+			return response.Error(c, fiber.StatusNotFound, "Schedule not found")
+		}
+		if err == domain.ErrPackageDepleted {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		if err == domain.ErrLockNotAcquired {
+			return response.FailAs(c, fiber.StatusConflict, err)
+		}
+		return response.Fail(c, err)
 	}
 
-	// Trigger volume aggregation
+	// Volume and PBs are derived from the session.completed event
 	if h.workoutService != nil {
-		volume, err := h.workoutService.AggregateSessionVolume(c.Context(), schedule.ID, schedule.MemberID, schedule.TenantID)
-		if err != nil {
-			// Log but don't fail
-			c.Context().Logger().Printf("Failed to aggregate volume for schedule %s: %v", schedule.ID, err)
-		} else if volume != nil {
-			c.Context().Logger().Printf("Aggregated volume for schedule %s: %.0f kg", schedule.ID, volume.TotalVolume)
-		}
+		h.workoutService.RecordSessionCompleted(c.Context(), schedule, userID)
 	}
 
-	return c.JSON(fiber.Map{"message": "Session completed"})
+	return response.OK(c, fiber.Map{"message": "Session completed"})
 }
 
 // ListSchedules GET /v1/schedules
+// Optional: member_id, coach_id, tag; limit, cursor (returns a page instead of the full list)
 func (h *PTHandler) ListSchedules(c *fiber.Ctx) error {
 	tenantID, ok := c.Locals("tenant_id").(string)
 	if !ok || tenantID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Missing tenant context"})
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
 	}
 
 	// Parse Filters
@@ -459,13 +735,24 @@ func (h *PTHandler) ListSchedules(c *fiber.Ctx) error {
 	if coachID := c.Query("coach_id"); coachID != "" {
 		filters["coach_id"] = coachID
 	}
+	if tag := strings.ToLower(strings.TrimSpace(c.Query("tag"))); tag != "" {
+		filters["tags"] = tag
+	}
 	// Add more filters if needed (from, to)
 
+	if q, ok := pageQuery(c); ok {
+		page, err := h.ptService.ListSchedulesPage(c.Context(), tenantID, filters, q)
+		if err != nil {
+			return pageError(c, err)
+		}
+		return response.OK(c, page)
+	}
+
 	schedules, err := h.ptService.ListSchedules(c.Context(), tenantID, filters)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
-	return c.JSON(schedules)
+	return response.OK(c, schedules)
 }
 
 // GetSchedule GET /v1/schedules/:id
@@ -474,18 +761,18 @@ func (h *PTHandler) GetSchedule(c *fiber.Ctx) error {
 	schedule, err := h.ptService.GetSchedule(c.Context(), id)
 	if err != nil {
 		if err == domain.ErrScheduleNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Schedule not found"})
+			return response.Error(c, fiber.StatusNotFound, "Schedule not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
-	return c.JSON(schedule)
+	return response.OK(c, schedule)
 }
 
 // DeleteSchedule DELETE /v1/pro/schedules/:id
 func (h *PTHandler) DeleteSchedule(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(string)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+		return response.Error(c, fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	scheduleID := c.Params("id")
@@ -494,32 +781,32 @@ func (h *PTHandler) DeleteSchedule(c *fiber.Ctx) error {
 	schedule, err := h.ptService.GetSchedule(c.Context(), scheduleID)
 	if err != nil {
 		if err == domain.ErrScheduleNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Schedule not found"})
+			return response.Error(c, fiber.StatusNotFound, "Schedule not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	if schedule.CoachID != userID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "You can only delete your own schedules"})
+		return response.Error(c, fiber.StatusForbidden, "You can only delete your own schedules")
 	}
 
 	// Only allow deleting scheduled (not started) sessions
 	if schedule.Status != domain.ScheduleStatusScheduled {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Can only delete scheduled sessions, not started or completed ones"})
+		return response.Error(c, fiber.StatusBadRequest, "Can only delete scheduled sessions, not started or completed ones")
 	}
 
 	if err := h.ptService.DeleteSchedule(c.Context(), scheduleID); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.JSON(fiber.Map{"message": "Schedule deleted successfully"})
+	return response.OK(c, fiber.Map{"message": "Schedule deleted successfully"})
 }
 
 // UpdateScheduleStatus PUT /v1/pro/schedules/:id/status
 func (h *PTHandler) UpdateScheduleStatus(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(string)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+		return response.Error(c, fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	scheduleID := c.Params("id")
@@ -528,11 +815,11 @@ func (h *PTHandler) UpdateScheduleStatus(c *fiber.Ctx) error {
 		Status string `json:"status"`
 	}
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
 	if req.Status == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Status is required"})
+		return response.Error(c, fiber.StatusBadRequest, "Status is required")
 	}
 
 	// Validate status value
@@ -549,38 +836,265 @@ func (h *PTHandler) UpdateScheduleStatus(c *fiber.Ctx) error {
 		"no-show":                                true, // Frontend uses lowercase
 	}
 	if !validStatuses[req.Status] {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid status value"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid status value")
 	}
 
 	// Verify the coach owns this schedule
 	schedule, err := h.ptService.GetSchedule(c.Context(), scheduleID)
 	if err != nil {
 		if err == domain.ErrScheduleNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Schedule not found"})
+			return response.Error(c, fiber.StatusNotFound, "Schedule not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	if schedule.CoachID != userID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "You can only update your own schedules"})
+		return response.Error(c, fiber.StatusForbidden, "You can only update your own schedules")
 	}
 
 	// Update status
 	if err := h.ptService.UpdateScheduleStatus(c.Context(), scheduleID, req.Status); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	// Trigger volume aggregation when session is completed
+	// Derive volume and PBs when session is completed
 	if req.Status == domain.ScheduleStatusCompleted && h.workoutService != nil {
-		_, err := h.workoutService.AggregateSessionVolume(c.Context(), scheduleID, schedule.MemberID, schedule.TenantID)
-		if err != nil {
-			// Log but don't fail the status update
-			c.Context().Logger().Printf("Failed to aggregate volume for schedule %s: %v", scheduleID, err)
-		}
+		h.workoutService.RecordSessionCompleted(c.Context(), schedule, userID)
 	}
 
-	return c.JSON(fiber.Map{
+	return response.OK(c, fiber.Map{
 		"id":     scheduleID,
 		"status": req.Status,
 	})
 }
+
+// --- Pro/Tenant Admin: Coach branches ---
+
+// GetMyAvailability GET /v1/pro/availability
+func (h *PTHandler) GetMyAvailability(c *fiber.Ctx) error {
+	coachID, _ := c.Locals("userID").(string)
+	availability, err := h.ptService.GetCoachAvailability(c.UserContext(), coachID)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, availability)
+}
+
+// SetMyAvailability PUT /v1/pro/availability
+// Replaces the coach's weekly working hours per branch
+func (h *PTHandler) SetMyAvailability(c *fiber.Ctx) error {
+	coachID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	var req struct {
+		Windows []domain.AvailabilityWindow `json:"windows"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	coach, err := h.userRepo.GetByID(c.UserContext(), coachID)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, "Failed to fetch user profile")
+	}
+	if scoped, err := coach.ScopedTo(tenantID); err == nil {
+		coach = scoped
+	}
+
+	availability, err := h.ptService.SetCoachAvailability(c.UserContext(), coach, req.Windows)
+	if err != nil {
+		if err == domain.ErrInvalidAvailability || err == domain.ErrBranchNotAllowed {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		}
+		return response.Fail(c, err)
+	}
+	return response.OK(c, availability)
+}
+
+// GetMyUtilization GET /v1/pro/utilization?from=YYYY-MM-DD&to=YYYY-MM-DD
+func (h *PTHandler) GetMyUtilization(c *fiber.Ctx) error {
+	coachID, _ := c.Locals("userID").(string)
+	return h.coachUtilization(c, coachID)
+}
+
+// GetCoachUtilization GET /v1/tenant-admin/coaches/:id/utilization?from=YYYY-MM-DD&to=YYYY-MM-DD
+func (h *PTHandler) GetCoachUtilization(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	coach, err := h.userRepo.GetByID(c.UserContext(), c.Params("id"))
+	if err != nil || !coach.HasRole(domain.RoleCoach) {
+		return response.Error(c, fiber.StatusNotFound, "Coach not found")
+	}
+	if _, err := coach.ScopedTo(tenantID); err != nil {
+		return response.Error(c, fiber.StatusNotFound, "Coach not found")
+	}
+	return h.coachUtilization(c, coach.ID)
+}
+
+// coachUtilization answers with the per-branch utilization over the requested days,
+// the last 28 days by default
+func (h *PTHandler) coachUtilization(c *fiber.Ctx, coachID string) error {
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -28)
+
+	if s := c.Query("from"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid 'from' date format, use YYYY-MM-DD")
+		}
+		from = d
+	}
+	if s := c.Query("to"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid 'to' date format, use YYYY-MM-DD")
+		}
+		to = d.AddDate(0, 0, 1) // Include the whole day
+	}
+	if !from.Before(to) {
+		return response.Error(c, fiber.StatusBadRequest, "'from' must not be after 'to'")
+	}
+
+	branches, err := h.ptService.GetCoachUtilization(c.UserContext(), coachID, from, to)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, fiber.Map{
+		"coach_id": coachID,
+		"from":     from,
+		"to":       to,
+		"branches": branches,
+	})
+}
+
+// TagSchedule PUT /v1/pro/schedules/:id/tags
+// Replaces the session's tags, label and color
+func (h *PTHandler) TagSchedule(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return response.Error(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req struct {
+		Tags  []string `json:"tags"`
+		Label string   `json:"label"`
+		Color string   `json:"color"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	schedule, err := h.ptService.TagSchedule(c.UserContext(), userID, c.Params("id"), req.Tags, req.Label, req.Color)
+	if err != nil {
+		switch err {
+		case domain.ErrScheduleNotFound, domain.ErrInvalidID:
+			return response.Error(c, fiber.StatusNotFound, "Schedule not found")
+		case domain.ErrForbidden:
+			return response.Error(c, fiber.StatusForbidden, "You can only tag your own schedules")
+		case domain.ErrInvalidScheduleTags, domain.ErrInvalidScheduleLabel:
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		}
+		return response.Fail(c, err)
+	}
+	return response.OK(c, schedule)
+}
+
+// SetSessionModality PUT /v1/pro/schedules/:id/modality
+// Body: {"modality": "online", "meeting_url": "https://..."}; meeting_url is optional
+func (h *PTHandler) SetSessionModality(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return response.Error(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req struct {
+		Modality   string `json:"modality"`
+		MeetingURL string `json:"meeting_url"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if req.Modality == "" {
+		return response.Error(c, fiber.StatusBadRequest, "modality is required")
+	}
+
+	schedule, err := h.ptService.SetSessionModality(c.UserContext(), userID, c.Params("id"), req.Modality, strings.TrimSpace(req.MeetingURL))
+	if err != nil {
+		switch err {
+		case domain.ErrScheduleNotFound, domain.ErrInvalidID:
+			return response.Error(c, fiber.StatusNotFound, "Schedule not found")
+		case domain.ErrForbidden:
+			return response.Error(c, fiber.StatusForbidden, "You can only change your own schedules")
+		case domain.ErrInvalidModality, domain.ErrInvalidMeetingURL:
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		case domain.ErrOutsideAvailability:
+			return response.FailAs(c, fiber.StatusConflict, err)
+		}
+		return response.Fail(c, err)
+	}
+	return response.OK(c, schedule)
+}
+
+// filterSchedulesByTag keeps the schedules carrying tag; an empty tag keeps them all
+func filterSchedulesByTag(schedules []*domain.Schedule, tag string) []*domain.Schedule {
+	if strings.TrimSpace(tag) == "" {
+		return schedules
+	}
+	tagged := make([]*domain.Schedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		if schedule.HasTag(tag) {
+			tagged = append(tagged, schedule)
+		}
+	}
+	return tagged
+}
+
+// GetMyTagReport GET /v1/pro/schedules/tag-report?from=YYYY-MM-DD&to=YYYY-MM-DD
+func (h *PTHandler) GetMyTagReport(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("userID").(string)
+	return h.scheduleTagReport(c, tenantID, userID)
+}
+
+// GetScheduleTagReport GET /v1/tenant-admin/reports/schedule-tags?from=&to=&coach_id=
+func (h *PTHandler) GetScheduleTagReport(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	return h.scheduleTagReport(c, tenantID, c.Query("coach_id"))
+}
+
+// scheduleTagReport answers with the tag counts of sessions starting in the requested
+// days, the last 28 days by default
+func (h *PTHandler) scheduleTagReport(c *fiber.Ctx, tenantID, coachID string) error {
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -28)
+
+	if s := c.Query("from"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid 'from' date format, use YYYY-MM-DD")
+		}
+		from = d
+	}
+	if s := c.Query("to"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid 'to' date format, use YYYY-MM-DD")
+		}
+		to = d.AddDate(0, 0, 1) // Include the whole day
+	}
+	if !from.Before(to) {
+		return response.Error(c, fiber.StatusBadRequest, "'from' must not be after 'to'")
+	}
+
+	tags, err := h.ptService.ScheduleTagReport(c.UserContext(), tenantID, coachID, from, to)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, fiber.Map{
+		"coach_id": coachID,
+		"from":     from,
+		"to":       to,
+		"tags":     tags,
+	})
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// RenewalHandler lets members opt their PT contracts in to automatic renewal
+type RenewalHandler struct {
+	renewals *service.RenewalService
+}
+
+func NewRenewalHandler(renewals *service.RenewalService) *RenewalHandler {
+	return &RenewalHandler{renewals: renewals}
+}
+
+// SetMyAutoRenew PUT /v1/me/contracts/:id/auto-renew
+// Body: enabled, payment_method (BCA, Mandiri, BNI; required when enabled). When the
+// sessions run out or expire, the package is bought again with an invoice paid by VA at
+// that bank. A contract already used up renews right away.
+func (h *RenewalHandler) SetMyAutoRenew(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	var req struct {
+		Enabled       bool   `json:"enabled"`
+		PaymentMethod string `json:"payment_method"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	contract, err := h.renewals.SetAutoRenew(c.UserContext(), userID, c.Params("id"), req.Enabled, req.PaymentMethod)
+	if err != nil {
+		switch err {
+		case domain.ErrContractNotFound, domain.ErrInvalidID:
+			return response.Error(c, fiber.StatusNotFound, "Contract not found")
+		case domain.ErrInvalidPaymentMethod:
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		case domain.ErrContractRenewed:
+			return response.FailAs(c, fiber.StatusConflict, err)
+		}
+		return response.Fail(c, err)
+	}
+	return response.OK(c, contract)
+}
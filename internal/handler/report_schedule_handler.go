@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// ReportScheduleHandler manages the tenant's scheduled reports
+type ReportScheduleHandler struct {
+	scheduleService *service.ReportScheduleService
+}
+
+func NewReportScheduleHandler(scheduleService *service.ReportScheduleService) *ReportScheduleHandler {
+	return &ReportScheduleHandler{scheduleService: scheduleService}
+}
+
+// ReportScheduleRequest is the body of creating or updating a report schedule
+type ReportScheduleRequest struct {
+	Report     string   `json:"report"`    // revenue, attendance or coach_utilization
+	Frequency  string   `json:"frequency"` // weekly or monthly
+	Delivery   string   `json:"delivery"`  // email or webhook
+	Recipients []string `json:"recipients"`
+	WebhookURL string   `json:"webhook_url"`
+	Paused     bool     `json:"paused"`
+}
+
+func (r *ReportScheduleRequest) schedule() *domain.ReportSchedule {
+	return &domain.ReportSchedule{
+		Report:     r.Report,
+		Frequency:  r.Frequency,
+		Delivery:   r.Delivery,
+		Recipients: r.Recipients,
+		WebhookURL: r.WebhookURL,
+		Paused:     r.Paused,
+	}
+}
+
+// ReportScheduleResponse is a schedule and, when webhook delivery was just set up, its
+// signing secret, shown only this once
+type ReportScheduleResponse struct {
+	Schedule      *domain.ReportSchedule `json:"schedule"`
+	WebhookSecret string                 `json:"webhook_secret,omitempty"`
+}
+
+// CreateSchedule POST /v1/tenant-admin/report-schedules
+// Email recipients default to the admin creating the schedule. For webhook delivery the
+// response is the only time the signing secret is shown.
+func (h *ReportScheduleHandler) CreateSchedule(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("userID").(string)
+
+	var req ReportScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	schedule := req.schedule()
+	schedule.TenantID, schedule.CreatedBy = tenantID, userID
+	if schedule.Delivery == domain.ReportDeliveryEmail && len(schedule.Recipients) == 0 {
+		schedule.Recipients = []string{userID}
+	}
+
+	secret, err := h.scheduleService.Create(c.UserContext(), schedule)
+	if err != nil {
+		return reportScheduleError(c, err)
+	}
+	return response.Created(c, ReportScheduleResponse{Schedule: schedule, WebhookSecret: secret})
+}
+
+// ListSchedules GET /v1/tenant-admin/report-schedules
+func (h *ReportScheduleHandler) ListSchedules(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	schedules, err := h.scheduleService.List(c.UserContext(), tenantID)
+	if err != nil {
+		return reportScheduleError(c, err)
+	}
+	return response.OK(c, schedules)
+}
+
+// GetSchedule GET /v1/tenant-admin/report-schedules/:id
+func (h *ReportScheduleHandler) GetSchedule(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	schedule, err := h.scheduleService.Get(c.UserContext(), tenantID, c.Params("id"))
+	if err != nil {
+		return reportScheduleError(c, err)
+	}
+	return response.OK(c, schedule)
+}
+
+// UpdateSchedule PUT /v1/tenant-admin/report-schedules/:id
+// Replaces the schedule's settings. A switch to webhook delivery returns its new secret.
+func (h *ReportScheduleHandler) UpdateSchedule(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	var req ReportScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	schedule, secret, err := h.scheduleService.Update(c.UserContext(), tenantID, c.Params("id"), req.schedule())
+	if err != nil {
+		return reportScheduleError(c, err)
+	}
+	return response.OK(c, ReportScheduleResponse{Schedule: schedule, WebhookSecret: secret})
+}
+
+// DeleteSchedule DELETE /v1/tenant-admin/report-schedules/:id
+func (h *ReportScheduleHandler) DeleteSchedule(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	if err := h.scheduleService.Delete(c.UserContext(), tenantID, c.Params("id")); err != nil {
+		return reportScheduleError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func reportScheduleError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return response.Error(c, fiber.StatusNotFound, "Report schedule not found")
+	case errors.Is(err, domain.ErrInvalidReportSchedule):
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	return response.Fail(c, err)
+}
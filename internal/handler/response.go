@@ -0,0 +1,12 @@
+package handler
+
+// MessageResponse is the body of requests that only report they were done
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// HealthResponse is the body of GET /health
+type HealthResponse struct {
+	Status  string `json:"status"`
+	Service string `json:"service"`
+}
@@ -2,31 +2,51 @@ package handler
 
 import (
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"math/big"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/audit"
 	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
 )
 
 type SaaSHandler struct {
 	tenantRepo domain.TenantRepository
 	userRepo   domain.UserRepository
 	branchRepo domain.BranchRepository
+	joinGuard  *service.JoinCodeGuard // Optional: throttles join-code guessing
+	digitizer  *service.OpenRouterDigitizer
+	deletions  *service.DeletedRecordService
+	invites    *service.MemberAppNotifier
+	fields     *service.CustomFieldService
 }
 
 func NewSaaSHandler(
 	tenantRepo domain.TenantRepository,
 	userRepo domain.UserRepository,
 	branchRepo domain.BranchRepository,
+	joinGuard *service.JoinCodeGuard,
+	digitizer *service.OpenRouterDigitizer,
+	deletions *service.DeletedRecordService,
+	invites *service.MemberAppNotifier,
+	fields *service.CustomFieldService,
 ) *SaaSHandler {
 	return &SaaSHandler{
 		tenantRepo: tenantRepo,
 		userRepo:   userRepo,
 		branchRepo: branchRepo,
+		joinGuard:  joinGuard,
+		digitizer:  digitizer,
+		deletions:  deletions,
+		invites:    invites,
+		fields:     fields,
 	}
 }
 
@@ -34,11 +54,11 @@ func NewSaaSHandler(
 func (h *SaaSHandler) CreateTenant(c *fiber.Ctx) error {
 	var tenant domain.Tenant
 	if err := c.BodyParser(&tenant); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
 	if tenant.Name == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Tenant name is required"})
+		return response.Error(c, fiber.StatusBadRequest, "Tenant name is required")
 	}
 
 	if tenant.JoinCode == "" {
@@ -47,14 +67,20 @@ func (h *SaaSHandler) CreateTenant(c *fiber.Ctx) error {
 		// Let's require it for now to be explicit, or generate simple one.
 		// User didn't specify auto-generation requirements, but simpler to require or default.
 		// Let's require it for simplicity as per "Add a JoinCode field".
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "join_code is required"})
+		return response.Error(c, fiber.StatusBadRequest, "join_code is required")
+	}
+	if err := domain.ValidateJoinCode(tenant.JoinCode); err != nil {
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	if err := tenant.AISettings.Validate(); err != nil {
+		return response.FailAs(c, fiber.StatusBadRequest, err)
 	}
 
 	if err := h.tenantRepo.Create(c.UserContext(), &tenant); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(tenant)
+	return response.Created(c, tenant)
 }
 
 // GetTenant handles GET /v1/tenants/:id
@@ -63,12 +89,83 @@ func (h *SaaSHandler) GetTenant(c *fiber.Ctx) error {
 	tenant, err := h.tenantRepo.GetByID(c.UserContext(), id)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Tenant not found"})
+			return response.Error(c, fiber.StatusNotFound, "Tenant not found")
+		}
+		return response.Fail(c, err)
+	}
+
+	return response.OK(c, tenant)
+}
+
+// DeleteTenant handles DELETE /v1/platform/tenants/:id
+// Soft-deletes the tenant and signs everyone out; it can be restored until purged
+func (h *SaaSHandler) DeleteTenant(c *fiber.Ctx) error {
+	if err := h.deletions.DeleteTenant(c.UserContext(), c.Params("id")); err != nil {
+		if err == domain.ErrNotFound {
+			return response.Error(c, fiber.StatusNotFound, "Tenant not found")
+		}
+		return response.Fail(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListDeletedTenants handles GET /v1/platform/tenants/deleted
+func (h *SaaSHandler) ListDeletedTenants(c *fiber.Ctx) error {
+	tenants, err := h.deletions.ListDeletedTenants(c.UserContext())
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, tenants)
+}
+
+// RestoreTenant handles POST /v1/platform/tenants/:id/restore
+func (h *SaaSHandler) RestoreTenant(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if err := h.deletions.RestoreTenant(c.UserContext(), id); err != nil {
+		if err == domain.ErrNotFound {
+			return response.Error(c, fiber.StatusNotFound, "Deleted tenant not found")
+		}
+		return response.Fail(c, err)
+	}
+	tenant, err := h.tenantRepo.GetByID(c.UserContext(), id)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, tenant)
+}
+
+// PreviewAIPrompt handles POST /v1/platform/tenants/:id/ai-prompt/preview
+// Renders the scan prompts with the ai_settings in the body, or the tenant's saved
+// settings when the body has none, without saving anything
+func (h *SaaSHandler) PreviewAIPrompt(c *fiber.Ctx) error {
+	var req struct {
+		AISettings *domain.AISettings `json:"ai_settings"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
+	tenant, err := h.tenantRepo.GetByID(c.UserContext(), c.Params("id"))
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return response.Error(c, fiber.StatusNotFound, "Tenant not found")
+		}
+		return response.Fail(c, err)
+	}
+	if req.AISettings != nil {
+		if err := req.AISettings.Validate(); err != nil {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		tenant.AISettings = *req.AISettings
 	}
 
-	return c.JSON(tenant)
+	preview, err := h.digitizer.PreviewPrompts(tenant)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, preview)
 }
 
 // UpdateTenant handles PUT /v1/tenants/:id
@@ -77,25 +174,53 @@ func (h *SaaSHandler) UpdateTenant(c *fiber.Ctx) error {
 
 	// Use pointers to detect missing fields
 	var req struct {
-		Name       *string            `json:"name"`
-		JoinCode   *string            `json:"join_code"`
-		LogoURL    *string            `json:"logo_url"`
-		AISettings *domain.AISettings `json:"ai_settings"`
+		Name             *string               `json:"name"`
+		JoinCode         *string               `json:"join_code"`
+		LogoURL          *string               `json:"logo_url"`
+		AISettings       *domain.AISettings    `json:"ai_settings"`
+		EmailBranding    *domain.EmailBranding `json:"email_branding"`
+		ContractTemplate *string               `json:"contract_template"`
+		WarehouseExport  *string               `json:"warehouse_export"`
+		Sandbox          *bool                 `json:"sandbox"`
 	}
 
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if req.WarehouseExport != nil {
+		switch *req.WarehouseExport {
+		case domain.WarehouseExportOff, domain.WarehouseExportAnonymized, domain.WarehouseExportFull:
+		default:
+			return response.Error(c, fiber.StatusBadRequest, "warehouse_export must be empty, 'anonymized' or 'full'")
+		}
+	}
+	if req.JoinCode != nil {
+		if err := domain.ValidateJoinCode(*req.JoinCode); err != nil {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		}
+	}
+	if req.AISettings != nil {
+		if err := req.AISettings.Validate(); err != nil {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		}
+	}
+	if req.EmailBranding != nil {
+		if err := req.EmailBranding.Validate(); err != nil {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		}
 	}
 
 	// Fetch existing tenant
 	existing, err := h.tenantRepo.GetByID(c.UserContext(), id)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Tenant not found"})
+			return response.Error(c, fiber.StatusNotFound, "Tenant not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
+	audit.Before(c, existing)
+
 	// Apply partial updates
 	updated := false
 	if req.Name != nil {
@@ -114,14 +239,115 @@ func (h *SaaSHandler) UpdateTenant(c *fiber.Ctx) error {
 		existing.AISettings = *req.AISettings
 		updated = true
 	}
+	if req.EmailBranding != nil {
+		existing.EmailBranding = *req.EmailBranding
+		updated = true
+	}
+	if req.ContractTemplate != nil {
+		existing.ContractTemplate = *req.ContractTemplate
+		updated = true
+	}
+	if req.WarehouseExport != nil {
+		existing.WarehouseExport = *req.WarehouseExport
+		updated = true
+	}
+	if req.Sandbox != nil {
+		existing.Sandbox = *req.Sandbox
+		updated = true
+	}
 
 	if updated {
 		if err := h.tenantRepo.Update(c.UserContext(), existing); err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			return response.Fail(c, err)
 		}
 	}
 
-	return c.JSON(existing)
+	return response.OK(c, existing)
+}
+
+// GetEmailBranding handles GET /v1/tenant-admin/email-branding
+func (h *SaaSHandler) GetEmailBranding(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	tenant, err := h.tenantRepo.GetByID(c.UserContext(), tenantID)
+	if err != nil {
+		return tenantError(c, err)
+	}
+	return response.OK(c, tenant.EmailBranding)
+}
+
+// UpdateEmailBranding handles PUT /v1/tenant-admin/email-branding
+// Body: the whole branding, replacing the saved one; see domain.EmailBranding
+func (h *SaaSHandler) UpdateEmailBranding(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	var branding domain.EmailBranding
+	if err := c.BodyParser(&branding); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if err := branding.Validate(); err != nil {
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	tenant, err := h.tenantRepo.GetByID(c.UserContext(), tenantID)
+	if err != nil {
+		return tenantError(c, err)
+	}
+	audit.Before(c, tenant.EmailBranding)
+	tenant.EmailBranding = branding
+	if err := h.tenantRepo.Update(c.UserContext(), tenant); err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, tenant.EmailBranding)
+}
+
+// PreviewEmail handles POST /v1/tenant-admin/email-branding/preview
+// Body: {"type": "account.invited", "email_branding": {...}}. Renders a sample email of the
+// type, to the admin, with the branding in the body or else the saved one.
+func (h *SaaSHandler) PreviewEmail(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+	var req struct {
+		Type          string                `json:"type"`
+		EmailBranding *domain.EmailBranding `json:"email_branding"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if req.Type == "" {
+		req.Type = domain.NotificationInvitation
+	}
+	tenant, err := h.tenantRepo.GetByID(c.UserContext(), tenantID)
+	if err != nil {
+		return tenantError(c, err)
+	}
+	if req.EmailBranding != nil {
+		if err := req.EmailBranding.Validate(); err != nil {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		}
+		tenant.EmailBranding = *req.EmailBranding
+	}
+	admin, err := h.userRepo.GetByID(c.UserContext(), userID)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, "Failed to fetch user profile")
+	}
+
+	msg, err := domain.RenderEmail(tenant, admin, &domain.Notification{
+		UserID:   admin.ID,
+		TenantID: tenantID,
+		Type:     req.Type,
+		Title:    "A sample notification",
+		Body:     "This is how your members see emails of this kind.",
+		Data:     map[string]string{},
+	})
+	if err != nil {
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	return response.OK(c, fiber.Map{"from_name": msg.FromName, "reply_to": msg.ReplyTo, "subject": msg.Subject, "html": msg.HTML, "text": msg.Text})
+}
+
+func tenantError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return response.Error(c, fiber.StatusNotFound, "Tenant not found")
+	}
+	return response.Fail(c, err)
 }
 
 // AuthSync handles POST /v1/auth/sync
@@ -130,7 +356,7 @@ func (h *SaaSHandler) AuthSync(c *fiber.Ctx) error {
 	// Middleware should set "uid" (Firebase UID)
 	firebaseUID, ok := c.Locals("uid").(string)
 	if !ok || firebaseUID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+		return response.Error(c, fiber.StatusUnauthorized, "Unauthorized")
 	}
 	// "email" and "name" might also be available if middleware parsed token claims
 	// For now, let's assume we can pass some info in body or just create minimal record.
@@ -158,10 +384,10 @@ func (h *SaaSHandler) AuthSync(c *fiber.Ctx) error {
 	}
 
 	if err := h.userRepo.UpsertByFirebaseUID(c.UserContext(), user); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.JSON(user)
+	return response.OK(c, presentUser(c, user))
 }
 
 // CreateTenantAdmin handles POST /v1/platform/tenant-admins
@@ -175,22 +401,22 @@ func (h *SaaSHandler) CreateTenantAdmin(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
 	if req.Email == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Email is required"})
+		return response.Error(c, fiber.StatusBadRequest, "Email is required")
 	}
 	if req.TenantID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "TenantID is required"})
+		return response.Error(c, fiber.StatusBadRequest, "TenantID is required")
 	}
 
 	// Validate Tenant Exists
 	if _, err := h.tenantRepo.GetByID(c.UserContext(), req.TenantID); err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Tenant not found"})
+			return response.Error(c, fiber.StatusNotFound, "Tenant not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to validate tenant"})
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to validate tenant")
 	}
 
 	// Strict enforcement: Role is ALWAYS tenant_admin
@@ -203,31 +429,38 @@ func (h *SaaSHandler) CreateTenantAdmin(c *fiber.Ctx) error {
 	}
 
 	if err := h.userRepo.Create(c.UserContext(), user); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
-
-	return c.Status(fiber.StatusCreated).JSON(user)
+	h.invites.Invite(c.UserContext(), user)
+	return response.Created(c, presentUser(c, user))
 }
 
 // ListTenantAdmins handles GET /v1/platform/tenant-admins
+// Optional: tenant_id; search, sort, limit, cursor (return a page instead of the full list)
 func (h *SaaSHandler) ListTenantAdmins(c *fiber.Ctx) error {
 	// Optional filter by tenant_id
 	tenantID := c.Query("tenant_id")
 
+	if q, ok := userListQuery(c); ok {
+		q.TenantID = tenantID
+		q.Role = domain.RoleTenantAdmin
+		return h.listUserPage(c, q)
+	}
+
 	if tenantID != "" {
 		users, err := h.userRepo.GetByTenantAndRole(c.UserContext(), tenantID, domain.RoleTenantAdmin)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			return response.Fail(c, err)
 		}
-		return c.JSON(users)
+		return response.OK(c, presentUsers(c, users))
 	}
 
 	// Get all tenant admins
 	users, err := h.userRepo.GetByRole(c.UserContext(), domain.RoleTenantAdmin)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
-	return c.JSON(users)
+	return response.OK(c, presentUsers(c, users))
 }
 
 // CreateUser handles POST /v1/users (Tenant Member Creation)
@@ -237,22 +470,34 @@ func (h *SaaSHandler) CreateUser(c *fiber.Ctx) error {
 		Email        string   `json:"email"`
 		Name         string   `json:"name"`
 		BranchAccess []string `json:"branch_access"`
+		DateOfBirth  string   `json:"date_of_birth"` // Optional: YYYY-MM-DD
+
+		CustomFields map[string]interface{} `json:"custom_fields"` // The tenant's member fields, by key
 	}
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
 	if req.Email == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Email is required"})
+		return response.Error(c, fiber.StatusBadRequest, "Email is required")
+	}
+	dob, err := domain.ParseDateOfBirth(req.DateOfBirth, time.Now())
+	if err != nil {
+		return response.FailAs(c, fiber.StatusBadRequest, err)
 	}
 
 	// Auto-assign TenantID from token
 	tenantID := c.Locals("tenant_id")
 	if tenantID == nil || tenantID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "TenantID not found in token"})
+		return response.Error(c, fiber.StatusUnauthorized, "TenantID not found in token")
 	}
 	tID := tenantID.(string)
 
+	customFields, err := h.fields.Apply(c.UserContext(), tID, domain.CustomFieldEntityMember, nil, req.CustomFields)
+	if err != nil {
+		return customFieldError(c, err)
+	}
+
 	// Validate Branch Access (if provided)
 	validBranches := []string{}
 	if len(req.BranchAccess) > 0 {
@@ -278,12 +523,15 @@ func (h *SaaSHandler) CreateUser(c *fiber.Ctx) error {
 		Roles:        []string{domain.RoleMember}, // STRICTLY MEMBER
 		TenantID:     tID,
 		BranchAccess: validBranches,
+		DateOfBirth:  dob,
+		CustomFields: customFields,
 	}
 
 	if err := h.userRepo.Create(c.UserContext(), user); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
-	return c.Status(fiber.StatusCreated).JSON(user)
+	h.invites.Invite(c.UserContext(), user)
+	return response.Created(c, presentUser(c, user))
 }
 
 // GetUser handles GET /v1/users/:id
@@ -292,20 +540,21 @@ func (h *SaaSHandler) GetUser(c *fiber.Ctx) error {
 	user, err := h.userRepo.GetByID(c.UserContext(), id)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+			return response.Error(c, fiber.StatusNotFound, "User not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	// Strict Tenant Scope Check for tenant_admin
 	tenantID := c.Locals("tenant_id")
 	if tenantID != nil && tenantID != "" {
 		if user.TenantID != tenantID.(string) {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"}) // Mask existence
+			return response.Error(c, fiber.StatusNotFound, "User not found") // Mask existence
 		}
 	}
 
-	return c.JSON(user)
+	setETag(c, user.Version)
+	return response.OK(c, presentUser(c, user))
 }
 
 // UpdateUser handles PUT /v1/users/:id
@@ -317,27 +566,46 @@ func (h *SaaSHandler) UpdateUser(c *fiber.Ctx) error {
 		Name         *string   `json:"name"`
 		BranchAccess *[]string `json:"branch_access"`
 		Roles        *[]string `json:"roles"`
+		DateOfBirth  *string   `json:"date_of_birth"` // YYYY-MM-DD; empty clears it
+		Version      *int64    `json:"version"`       // Alternative to the If-Match header
+
+		// Changed member fields only; null clears one
+		CustomFields map[string]interface{} `json:"custom_fields"`
 	}
 
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	version, hasVersion, err := ifMatchVersion(c)
+	if err != nil {
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	if !hasVersion && req.Version != nil {
+		version, hasVersion = *req.Version, true
 	}
 
 	// Security: Fetch existing user and verify tenant BEFORE updating
 	existing, err := h.userRepo.GetByID(c.UserContext(), id)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+			return response.Error(c, fiber.StatusNotFound, "User not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	tenantID := c.Locals("tenant_id")
 	if tenantID != nil && tenantID != "" {
 		if existing.TenantID != tenantID.(string) {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+			return response.Error(c, fiber.StatusNotFound, "User not found")
 		}
 	}
+	audit.Before(c, presentUser(c, existing))
+
+	// Optimistic concurrency: reject edits made against a stale copy of the user
+	if hasVersion && version != existing.Version {
+		return response.Fail(c, domain.ErrVersionConflict)
+	}
 
 	// Apply Partial Updates
 	updated := false
@@ -353,6 +621,22 @@ func (h *SaaSHandler) UpdateUser(c *fiber.Ctx) error {
 		existing.BranchAccess = *req.BranchAccess
 		updated = true
 	}
+	if req.DateOfBirth != nil {
+		dob, err := domain.ParseDateOfBirth(*req.DateOfBirth, time.Now())
+		if err != nil {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		}
+		existing.DateOfBirth = dob
+		updated = true
+	}
+	if req.CustomFields != nil {
+		values, err := h.fields.Apply(c.UserContext(), existing.TenantID, domain.CustomFieldEntityMember, existing.CustomFields, req.CustomFields)
+		if err != nil {
+			return customFieldError(c, err)
+		}
+		existing.CustomFields = values
+		updated = true
+	}
 	if req.Roles != nil {
 		// Prevent role escalation. Remove any admin roles.
 		newRoles := []string{}
@@ -372,13 +656,17 @@ func (h *SaaSHandler) UpdateUser(c *fiber.Ctx) error {
 		existing.UpdatedAt = time.Now()
 		if err := h.userRepo.Update(c.UserContext(), existing); err != nil {
 			if err == domain.ErrNotFound {
-				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+				return response.Error(c, fiber.StatusNotFound, "User not found")
 			}
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			if err == domain.ErrVersionConflict {
+				return response.FailAs(c, fiber.StatusConflict, err)
+			}
+			return response.Fail(c, err)
 		}
 	}
 
-	return c.JSON(existing)
+	setETag(c, existing.Version)
+	return response.OK(c, presentUser(c, existing))
 }
 
 // DeleteUser handles DELETE /v1/users/:id
@@ -389,37 +677,179 @@ func (h *SaaSHandler) DeleteUser(c *fiber.Ctx) error {
 	user, err := h.userRepo.GetByID(c.UserContext(), id)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+			return response.Error(c, fiber.StatusNotFound, "User not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	tenantID := c.Locals("tenant_id")
 	if tenantID != nil && tenantID != "" {
 		if user.TenantID != tenantID.(string) {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+			return response.Error(c, fiber.StatusNotFound, "User not found")
+		}
+	}
+	audit.Before(c, presentUser(c, user))
+
+	if err := h.deletions.DeleteUser(c.UserContext(), id); err != nil {
+		return response.Fail(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListDeletedUsers handles GET /v1/users/deleted
+// Users of the caller's tenant (every tenant for super admins) that can still be restored
+func (h *SaaSHandler) ListDeletedUsers(c *fiber.Ctx) error {
+	return h.listDeletedUsers(c, "")
+}
+
+// RestoreUser handles POST /v1/users/:id/restore
+func (h *SaaSHandler) RestoreUser(c *fiber.Ctx) error {
+	id := c.Params("id")
+	tenantID, _ := c.Locals("tenant_id").(string)
+	if err := h.deletions.RestoreUser(c.UserContext(), id, tenantID); err != nil {
+		if err == domain.ErrNotFound {
+			return response.Error(c, fiber.StatusNotFound, "Deleted user not found")
+		}
+		return response.Fail(c, err)
+	}
+	user, err := h.userRepo.GetByID(c.UserContext(), id)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, presentUser(c, user))
+}
+
+// listDeletedUsers lists the deleted users of the caller's tenant, only those with role if set
+func (h *SaaSHandler) listDeletedUsers(c *fiber.Ctx, role string) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	users, err := h.userRepo.ListDeleted(c.UserContext(), tenantID)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	if role != "" {
+		filtered := make([]*domain.User, 0, len(users))
+		for _, u := range users {
+			if u.HasRole(role) {
+				filtered = append(filtered, u)
+			}
+		}
+		users = filtered
+	}
+	return response.OK(c, presentUsers(c, users))
+}
+
+// SetMembership handles PUT /v1/platform/users/:id/memberships/:tenant_id
+// Gives the user roles in another tenant, e.g. a coach working at several franchise gyms
+func (h *SaaSHandler) SetMembership(c *fiber.Ctx) error {
+	tenantID := c.Params("tenant_id")
+
+	var req struct {
+		Roles            []string `json:"roles"`
+		HomeBranchID     string   `json:"home_branch_id"`
+		BranchAccess     []string `json:"branch_access"`
+		WorkingBranchIDs []string `json:"working_branch_ids"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if len(req.Roles) == 0 {
+		return response.Error(c, fiber.StatusBadRequest, "roles is required")
+	}
+	for _, r := range req.Roles {
+		if r == domain.RoleSuperAdmin {
+			return response.Error(c, fiber.StatusBadRequest, "super_admin cannot be granted per tenant")
+		}
+	}
+
+	user, err := h.userRepo.GetByID(c.UserContext(), c.Params("id"))
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return response.Error(c, fiber.StatusNotFound, "User not found")
+		}
+		return response.Fail(c, err)
+	}
+	if tenantID == user.TenantID {
+		return response.Error(c, fiber.StatusBadRequest, "This is the user's primary tenant; update the user instead")
+	}
+	if _, err := h.tenantRepo.GetByID(c.UserContext(), tenantID); err != nil {
+		if err == domain.ErrNotFound {
+			return response.Error(c, fiber.StatusNotFound, "Tenant not found")
+		}
+		return response.Fail(c, err)
+	}
+
+	user.SetMembership(domain.TenantMembership{
+		TenantID:         tenantID,
+		Roles:            req.Roles,
+		HomeBranchID:     req.HomeBranchID,
+		BranchAccess:     req.BranchAccess,
+		WorkingBranchIDs: req.WorkingBranchIDs,
+	})
+	user.UpdatedAt = time.Now()
+	if err := h.userRepo.Update(c.UserContext(), user); err != nil {
+		if err == domain.ErrVersionConflict {
+			return response.FailAs(c, fiber.StatusConflict, err)
+		}
+		return response.Fail(c, err)
+	}
+	return response.OK(c, presentUser(c, user))
+}
+
+// RemoveMembership handles DELETE /v1/platform/users/:id/memberships/:tenant_id
+// Tokens already scoped to the tenant stay valid until they expire; refreshing them fails.
+func (h *SaaSHandler) RemoveMembership(c *fiber.Ctx) error {
+	user, err := h.userRepo.GetByID(c.UserContext(), c.Params("id"))
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return response.Error(c, fiber.StatusNotFound, "User not found")
 		}
+		return response.Fail(c, err)
+	}
+	if !user.RemoveMembership(c.Params("tenant_id")) {
+		return response.Error(c, fiber.StatusNotFound, "Membership not found")
 	}
 
-	if err := h.userRepo.Delete(c.UserContext(), id); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	user.UpdatedAt = time.Now()
+	if err := h.userRepo.Update(c.UserContext(), user); err != nil {
+		if err == domain.ErrVersionConflict {
+			return response.FailAs(c, fiber.StatusConflict, err)
+		}
+		return response.Fail(c, err)
 	}
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
 // ListUsers handles GET /v1/users
+// Optional: search, role, sort, limit, cursor (return a page instead of the full list)
 func (h *SaaSHandler) ListUsers(c *fiber.Ctx) error {
 	// Auto-assign TenantID from token
 	tenantID := c.Locals("tenant_id")
 	if tenantID == nil || tenantID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "TenantID not found in token"})
+		return response.Error(c, fiber.StatusUnauthorized, "TenantID not found in token")
+	}
+
+	if q, ok := userListQuery(c); ok {
+		q.TenantID = tenantID.(string)
+		return h.listUserPage(c, q)
 	}
 
 	users, err := h.userRepo.GetByTenant(c.UserContext(), tenantID.(string))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
+	}
+	return response.OK(c, presentUsers(c, users))
+}
+
+// listUserPage responds with one page of the users matching q
+func (h *SaaSHandler) listUserPage(c *fiber.Ctx, q domain.UserListQuery) error {
+	page, err := h.userRepo.ListUsers(c.UserContext(), q)
+	if err != nil {
+		if err == domain.ErrInvalidUserQuery {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		}
+		return pageError(c, err)
 	}
-	return c.JSON(users)
+	return response.OK(c, presentUserPage(c, page))
 }
 
 // JoinTenant handles POST /v1/me/join-tenant
@@ -428,32 +858,37 @@ func (h *SaaSHandler) JoinTenant(c *fiber.Ctx) error {
 		JoinCode string `json:"join_code"`
 	}
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
 	if req.JoinCode == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "join_code is required"})
+		return response.Error(c, fiber.StatusBadRequest, "join_code is required")
+	}
+
+	// UserID should be set by JWT middleware
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+	if h.joinThrottled(c, userID) {
+		return nil
 	}
 
 	// 1. Find Tenant by Code
 	tenant, err := h.tenantRepo.GetByJoinCode(c.UserContext(), req.JoinCode)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Invalid join code"})
+			h.joinFailed(c, userID, "tenant")
+			return response.Error(c, fiber.StatusNotFound, "Invalid join code")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to verify join code"})
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to verify join code")
 	}
+	h.joinSucceeded(c, userID)
 
 	// 2. Get Authenticated User
-	// UserID should be set by JWT middleware
-	userID, ok := c.Locals("userID").(string)
-	if !ok || userID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
-	}
-
 	user, err := h.userRepo.GetByID(c.UserContext(), userID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch user profile"})
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch user profile")
 	}
 
 	// 3. Update User's TenantID
@@ -464,11 +899,10 @@ func (h *SaaSHandler) JoinTenant(c *fiber.Ctx) error {
 	user.TenantID = tenant.ID
 
 	if err := h.userRepo.Update(c.UserContext(), user); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to join tenant"})
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to join tenant")
 	}
 
-	return c.JSON(fiber.Map{
-		"success":   true,
+	return response.OK(c, fiber.Map{
 		"message":   "Successfully joined tenant",
 		"tenant":    tenant,
 		"tenant_id": tenant.ID,
@@ -481,31 +915,36 @@ func (h *SaaSHandler) JoinBranch(c *fiber.Ctx) error {
 		JoinCode string `json:"join_code"`
 	}
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
 	if req.JoinCode == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "join_code is required"})
+		return response.Error(c, fiber.StatusBadRequest, "join_code is required")
+	}
+
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+	if h.joinThrottled(c, userID) {
+		return nil
 	}
 
 	// 1. Find Branch by Code
 	branch, err := h.branchRepo.GetByJoinCode(c.UserContext(), req.JoinCode)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Invalid join code"})
+			h.joinFailed(c, userID, "branch")
+			return response.Error(c, fiber.StatusNotFound, "Invalid join code")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to verify join code"})
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to verify join code")
 	}
+	h.joinSucceeded(c, userID)
 
 	// 2. Get Authenticated User
-	userID, ok := c.Locals("userID").(string)
-	if !ok || userID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
-	}
-
 	user, err := h.userRepo.GetByID(c.UserContext(), userID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch user profile"})
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch user profile")
 	}
 
 	// 3. Update User's BranchAccess and cleanup TenantID
@@ -513,9 +952,7 @@ func (h *SaaSHandler) JoinBranch(c *fiber.Ctx) error {
 	if user.TenantID == "" {
 		user.TenantID = branch.TenantID
 	} else if user.TenantID != branch.TenantID {
-		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
-			"error": "Joined branch belongs to a different tenant. Cross-tenant Access not allowed.",
-		})
+		return response.Error(c, fiber.StatusConflict, "Joined branch belongs to a different tenant. Cross-tenant Access not allowed.")
 	}
 
 	// Check if already in branch
@@ -530,12 +967,11 @@ func (h *SaaSHandler) JoinBranch(c *fiber.Ctx) error {
 	if !alreadyJoined {
 		user.BranchAccess = append(user.BranchAccess, branch.ID)
 		if err := h.userRepo.Update(c.UserContext(), user); err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to join branch"})
+			return response.Error(c, fiber.StatusInternalServerError, "Failed to join branch")
 		}
 	}
 
-	return c.JSON(fiber.Map{
-		"success":   true,
+	return response.OK(c, fiber.Map{
 		"message":   "Successfully joined branch",
 		"branch":    branch,
 		"branch_id": branch.ID,
@@ -548,23 +984,24 @@ func (h *SaaSHandler) JoinBranch(c *fiber.Ctx) error {
 // CreateCoach handles POST /v1/coaches
 func (h *SaaSHandler) CreateCoach(c *fiber.Ctx) error {
 	var req struct {
-		FirebaseUID  string `json:"firebase_uid"`
-		Email        string `json:"email"`
-		Name         string `json:"name"`
-		HomeBranchID string `json:"home_branch_id"`
+		FirebaseUID      string   `json:"firebase_uid"`
+		Email            string   `json:"email"`
+		Name             string   `json:"name"`
+		HomeBranchID     string   `json:"home_branch_id"`
+		WorkingBranchIDs []string `json:"working_branch_ids"` // Other branches the coach works at
 	}
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
 	if req.Email == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Email is required"})
+		return response.Error(c, fiber.StatusBadRequest, "Email is required")
 	}
 
 	// Auto-assign TenantID from token for tenant_admin
 	tenantID := c.Locals("tenant_id")
 	if tenantID == nil || tenantID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "TenantID not found in token"})
+		return response.Error(c, fiber.StatusUnauthorized, "TenantID not found in token")
 	}
 	tID := tenantID.(string)
 
@@ -573,46 +1010,57 @@ func (h *SaaSHandler) CreateCoach(c *fiber.Ctx) error {
 		branch, err := h.branchRepo.GetByID(c.UserContext(), req.HomeBranchID)
 		if err != nil {
 			if err == domain.ErrNotFound {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Home Branch not found"})
+				return response.Error(c, fiber.StatusBadRequest, "Home Branch not found")
 			}
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to validate branch"})
+			return response.Error(c, fiber.StatusInternalServerError, "Failed to validate branch")
 		}
 		if branch.TenantID != tID {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Home Branch does not belong to this tenant"})
+			return response.Error(c, fiber.StatusBadRequest, "Home Branch does not belong to this tenant")
 		}
 	}
+	if h.branchesRejected(c, tID, req.WorkingBranchIDs) {
+		return nil
+	}
 
 	// Create user with coach role
 	user := &domain.User{
-		FirebaseUID:  req.FirebaseUID, // Optional (will link on login)
-		Email:        req.Email,
-		Name:         req.Name,
-		Roles:        []string{domain.RoleCoach},
-		TenantID:     tID,
-		HomeBranchID: req.HomeBranchID, // Optional
+		FirebaseUID:      req.FirebaseUID, // Optional (will link on login)
+		Email:            req.Email,
+		Name:             req.Name,
+		Roles:            []string{domain.RoleCoach},
+		TenantID:         tID,
+		HomeBranchID:     req.HomeBranchID, // Optional
+		WorkingBranchIDs: req.WorkingBranchIDs,
 	}
 
 	if err := h.userRepo.Create(c.UserContext(), user); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
-	return c.Status(fiber.StatusCreated).JSON(user)
+	h.invites.Invite(c.UserContext(), user)
+	return response.Created(c, presentUser(c, user))
 }
 
-// ListCoaches handles GET /v1/coaches (optional ?tenant_id=xxx)
-// ListCoaches handles GET /v1/coaches (optional ?tenant_id=xxx)
+// ListCoaches handles GET /v1/coaches
+// Optional: search, sort, limit, cursor (return a page instead of the full list)
 func (h *SaaSHandler) ListCoaches(c *fiber.Ctx) error {
 	// Auto-assign TenantID from token
 	tenantID := c.Locals("tenant_id")
 	if tenantID == nil || tenantID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "TenantID not found in token"})
+		return response.Error(c, fiber.StatusUnauthorized, "TenantID not found in token")
 	}
 	tID := tenantID.(string)
 
+	if q, ok := userListQuery(c); ok {
+		q.TenantID = tID
+		q.Role = domain.RoleCoach
+		return h.listUserPage(c, q)
+	}
+
 	coaches, err := h.userRepo.GetByTenantAndRole(c.UserContext(), tID, domain.RoleCoach)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
-	return c.JSON(coaches)
+	return response.OK(c, presentUsers(c, coaches))
 }
 
 // GetCoach handles GET /v1/coaches/:id
@@ -621,25 +1069,25 @@ func (h *SaaSHandler) GetCoach(c *fiber.Ctx) error {
 	coach, err := h.userRepo.GetByID(c.UserContext(), id)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Coach not found"})
+			return response.Error(c, fiber.StatusNotFound, "Coach not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	// Verify user has coach role
 	if !coach.HasRole(domain.RoleCoach) {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User is not a coach"})
+		return response.Error(c, fiber.StatusNotFound, "User is not a coach")
 	}
 
 	// Strict Tenant Scope Check
 	tenantID := c.Locals("tenant_id")
 	if tenantID != nil && tenantID != "" {
 		if coach.TenantID != tenantID.(string) {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Coach not found"})
+			return response.Error(c, fiber.StatusNotFound, "Coach not found")
 		}
 	}
 
-	return c.JSON(coach)
+	return response.OK(c, presentUser(c, coach))
 }
 
 // UpdateCoach handles PUT /v1/coaches/:id
@@ -648,35 +1096,37 @@ func (h *SaaSHandler) UpdateCoach(c *fiber.Ctx) error {
 
 	// Create struct for partial updates (allow everything but ignore uneditable fields)
 	var req struct {
-		Name         string `json:"name"`
-		HomeBranchID string `json:"home_branch_id"`
+		Name             string    `json:"name"`
+		HomeBranchID     string    `json:"home_branch_id"`
+		WorkingBranchIDs *[]string `json:"working_branch_ids"` // Replaces the list; [] clears it
 		// Ignored fields: id, email, firebase_uid, tenant_id, branch_access, roles
 	}
 
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
 	// Fetch existing user to verify they're a coach
 	existing, err := h.userRepo.GetByID(c.UserContext(), id)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Coach not found"})
+			return response.Error(c, fiber.StatusNotFound, "Coach not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	if !existing.HasRole(domain.RoleCoach) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "User is not a coach"})
+		return response.Error(c, fiber.StatusBadRequest, "User is not a coach")
 	}
 
 	// Strict Tenant Scope Check
 	tenantID := c.Locals("tenant_id")
 	if tenantID != nil && tenantID != "" {
 		if existing.TenantID != tenantID.(string) {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Coach not found"})
+			return response.Error(c, fiber.StatusNotFound, "Coach not found")
 		}
 	}
+	audit.Before(c, presentUser(c, existing))
 
 	// Apply partial updates if provided
 	if req.Name != "" {
@@ -685,17 +1135,26 @@ func (h *SaaSHandler) UpdateCoach(c *fiber.Ctx) error {
 	if req.HomeBranchID != "" {
 		existing.HomeBranchID = req.HomeBranchID
 	}
+	if req.WorkingBranchIDs != nil {
+		if h.branchesRejected(c, existing.TenantID, *req.WorkingBranchIDs) {
+			return nil
+		}
+		existing.WorkingBranchIDs = *req.WorkingBranchIDs
+	}
 
 	existing.UpdatedAt = time.Now()
 
 	if err := h.userRepo.Update(c.UserContext(), existing); err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Coach not found"})
+			return response.Error(c, fiber.StatusNotFound, "Coach not found")
+		}
+		if err == domain.ErrVersionConflict {
+			return response.FailAs(c, fiber.StatusConflict, err)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.JSON(existing)
+	return response.OK(c, presentUser(c, existing))
 }
 
 // DeleteCoach handles DELETE /v1/coaches/:id
@@ -706,29 +1165,40 @@ func (h *SaaSHandler) DeleteCoach(c *fiber.Ctx) error {
 	user, err := h.userRepo.GetByID(c.Context(), id)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Coach not found"})
+			return response.Error(c, fiber.StatusNotFound, "Coach not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	if !user.HasRole(domain.RoleCoach) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "User is not a coach"})
+		return response.Error(c, fiber.StatusBadRequest, "User is not a coach")
 	}
 
 	// Strict Tenant Scope Check
 	tenantID := c.Locals("tenant_id")
 	if tenantID != nil && tenantID != "" {
 		if user.TenantID != tenantID.(string) {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Coach not found"})
+			return response.Error(c, fiber.StatusNotFound, "Coach not found")
 		}
 	}
+	audit.Before(c, presentUser(c, user))
 
-	if err := h.userRepo.Delete(c.Context(), id); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	if err := h.deletions.DeleteUser(c.Context(), id); err != nil {
+		return response.Fail(c, err)
 	}
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
+// ListDeletedCoaches handles GET /v1/tenant-admin/coaches/deleted
+func (h *SaaSHandler) ListDeletedCoaches(c *fiber.Ctx) error {
+	return h.listDeletedUsers(c, domain.RoleCoach)
+}
+
+// ListDeletedTenantAdmins handles GET /v1/platform/tenant-admins/deleted
+func (h *SaaSHandler) ListDeletedTenantAdmins(c *fiber.Ctx) error {
+	return h.listDeletedUsers(c, domain.RoleTenantAdmin)
+}
+
 // ============================================
 // Branch CRUD Handlers
 // ============================================
@@ -737,11 +1207,11 @@ func (h *SaaSHandler) DeleteCoach(c *fiber.Ctx) error {
 func (h *SaaSHandler) CreateBranch(c *fiber.Ctx) error {
 	var branch domain.Branch
 	if err := c.BodyParser(&branch); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
 	if branch.Name == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Name is required"})
+		return response.Error(c, fiber.StatusBadRequest, "Name is required")
 	}
 
 	// Auto-assign TenantID from token for tenant_admin
@@ -751,15 +1221,15 @@ func (h *SaaSHandler) CreateBranch(c *fiber.Ctx) error {
 	}
 
 	if branch.TenantID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "TenantID is required"})
+		return response.Error(c, fiber.StatusBadRequest, "TenantID is required")
 	}
 
 	// Validate Tenant Exists
 	if _, err := h.tenantRepo.GetByID(c.Context(), branch.TenantID); err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Tenant not found"})
+			return response.Error(c, fiber.StatusNotFound, "Tenant not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to validate tenant"})
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to validate tenant")
 	}
 
 	// Auto-generate JoinCode if not provided
@@ -772,18 +1242,16 @@ func (h *SaaSHandler) CreateBranch(c *fiber.Ctx) error {
 			prefix = prefix[:4]
 		}
 
-		// Generate random 4-digit suffix
-		randNum, _ := rand.Int(rand.Reader, big.NewInt(9000))
-		suffix := randNum.Int64() + 1000
-
-		branch.JoinCode = fmt.Sprintf("%s-%d", prefix, suffix)
+		branch.JoinCode = generateJoinCode(prefix)
+	} else if err := domain.ValidateJoinCode(branch.JoinCode); err != nil {
+		return response.FailAs(c, fiber.StatusBadRequest, err)
 	}
 
 	if err := h.branchRepo.Create(c.Context(), &branch); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(branch)
+	return response.Created(c, branch)
 }
 
 // ListBranches handles GET /branches
@@ -809,16 +1277,16 @@ func (h *SaaSHandler) ListBranches(c *fiber.Ctx) error {
 	} else {
 		// Tenant admin sees only their tenant's branches
 		if tenantID == nil || tenantID == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "TenantID not found in token"})
+			return response.Error(c, fiber.StatusUnauthorized, "TenantID not found in token")
 		}
 		branches, err = h.branchRepo.GetByTenantID(c.Context(), tenantID.(string))
 	}
 
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.JSON(branches)
+	return response.OK(c, branches)
 }
 
 // GetBranch handles GET /branches/:id
@@ -827,9 +1295,9 @@ func (h *SaaSHandler) GetBranch(c *fiber.Ctx) error {
 	branch, err := h.branchRepo.GetByID(c.Context(), id)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Branch not found"})
+			return response.Error(c, fiber.StatusNotFound, "Branch not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	// Check tenant scope for tenant_admin
@@ -846,11 +1314,11 @@ func (h *SaaSHandler) GetBranch(c *fiber.Ctx) error {
 
 	if !isSuperAdmin && tenantID != nil {
 		if branch.TenantID != tenantID.(string) {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Cannot access branch from different tenant"})
+			return response.Fail(c, domain.ErrTenantScopeViolation)
 		}
 	}
 
-	return c.JSON(branch)
+	return response.OK(c, branch)
 }
 
 // UpdateBranch handles PUT /branches/:id
@@ -861,9 +1329,9 @@ func (h *SaaSHandler) UpdateBranch(c *fiber.Ctx) error {
 	branch, err := h.branchRepo.GetByID(c.Context(), id)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Branch not found"})
+			return response.Error(c, fiber.StatusNotFound, "Branch not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	// Check tenant scope for tenant_admin
@@ -880,27 +1348,31 @@ func (h *SaaSHandler) UpdateBranch(c *fiber.Ctx) error {
 
 	if !isSuperAdmin && tenantID != nil {
 		if branch.TenantID != tenantID.(string) {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Cannot update branch from different tenant"})
+			return response.Fail(c, domain.ErrTenantScopeViolation)
 		}
 	}
+	audit.Before(c, branch)
 
 	// Parse update data
 	var updates domain.Branch
 	if err := c.BodyParser(&updates); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
 	// Update fields
 	branch.Name = updates.Name
 	if updates.JoinCode != "" {
+		if err := domain.ValidateJoinCode(updates.JoinCode); err != nil {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		}
 		branch.JoinCode = updates.JoinCode
 	}
 
 	if err := h.branchRepo.Update(c.Context(), branch); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.JSON(branch)
+	return response.OK(c, branch)
 }
 
 // DeleteBranch handles DELETE /branches/:id
@@ -911,9 +1383,9 @@ func (h *SaaSHandler) DeleteBranch(c *fiber.Ctx) error {
 	branch, err := h.branchRepo.GetByID(c.Context(), id)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Branch not found"})
+			return response.Error(c, fiber.StatusNotFound, "Branch not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	// Check tenant scope for tenant_admin
@@ -930,13 +1402,122 @@ func (h *SaaSHandler) DeleteBranch(c *fiber.Ctx) error {
 
 	if !isSuperAdmin && tenantID != nil {
 		if branch.TenantID != tenantID.(string) {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Cannot delete branch from different tenant"})
+			return response.Fail(c, domain.ErrTenantScopeViolation)
 		}
 	}
+	audit.Before(c, branch)
 
-	if err := h.branchRepo.Delete(c.Context(), id); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	if err := h.deletions.DeleteBranch(c.Context(), id); err != nil {
+		return response.Fail(c, err)
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+// ListDeletedBranches handles GET /branches/deleted
+func (h *SaaSHandler) ListDeletedBranches(c *fiber.Ctx) error {
+	branches, err := h.branchRepo.ListDeleted(c.Context(), branchScope(c))
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, branches)
+}
+
+// RestoreBranch handles POST /branches/:id/restore
+func (h *SaaSHandler) RestoreBranch(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if err := h.deletions.RestoreBranch(c.Context(), id, branchScope(c)); err != nil {
+		if err == domain.ErrNotFound {
+			return response.Error(c, fiber.StatusNotFound, "Deleted branch not found")
+		}
+		return response.Fail(c, err)
+	}
+	branch, err := h.branchRepo.GetByID(c.Context(), id)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, branch)
+}
+
+// branchScope is the tenant whose branches the caller manages; "" for super admins
+func branchScope(c *fiber.Ctx) string {
+	roles, _ := c.Locals("roles").([]string)
+	for _, role := range roles {
+		if role == domain.RoleSuperAdmin {
+			return ""
+		}
+	}
+	tenantID, _ := c.Locals("tenant_id").(string)
+	return tenantID
+}
+
+// --- Join code helpers ---
+
+// joinCodeAlphabet leaves out characters that are easily confused when read aloud or typed
+const joinCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// generateJoinCode returns prefix plus 8 random characters (about 40 bits), always mixing
+// letters and digits so it passes domain.ValidateJoinCode
+func generateJoinCode(prefix string) string {
+	for {
+		suffix := make([]byte, 8)
+		for i := range suffix {
+			n, _ := rand.Int(rand.Reader, big.NewInt(int64(len(joinCodeAlphabet))))
+			suffix[i] = joinCodeAlphabet[n.Int64()]
+		}
+		code := string(suffix)
+		if prefix != "" {
+			code = prefix + "-" + code
+		}
+		if domain.ValidateJoinCode(code) == nil {
+			return code
+		}
+	}
+}
+
+// branchesRejected answers 400 (or 500) and returns true unless every branch exists and
+// belongs to the tenant
+func (h *SaaSHandler) branchesRejected(c *fiber.Ctx, tenantID string, branchIDs []string) bool {
+	for _, id := range branchIDs {
+		branch, err := h.branchRepo.GetByID(c.UserContext(), id)
+		if err != nil && err != domain.ErrNotFound {
+			_ = response.Error(c, fiber.StatusInternalServerError, "Failed to validate branch")
+			return true
+		}
+		if err == domain.ErrNotFound || branch.TenantID != tenantID {
+			_ = response.Error(c, fiber.StatusBadRequest, "Branch "+id+" not found in this tenant")
+			return true
+		}
+	}
+	return false
+}
+
+// joinThrottled answers 429 with Retry-After when the caller must wait before guessing again.
+// Throttling fails open: a Redis outage shouldn't stop members from joining.
+func (h *SaaSHandler) joinThrottled(c *fiber.Ctx, userID string) bool {
+	if h.joinGuard == nil {
+		return false
+	}
+	wait, err := h.joinGuard.Check(c.UserContext(), userID, c.IP())
+	if err != domain.ErrTooManyAttempts {
+		if err != nil {
+			fmt.Printf("Warning: join throttle check failed: %v\n", err)
+		}
+		return false
+	}
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(wait.Seconds())+1))
+	_ = response.Error(c, fiber.StatusTooManyRequests, "Too many invalid join codes, try again later")
+	return true
+}
+
+func (h *SaaSHandler) joinFailed(c *fiber.Ctx, userID, scope string) {
+	if h.joinGuard != nil {
+		h.joinGuard.Failed(c.UserContext(), userID, c.IP(), scope)
+	}
+}
+
+func (h *SaaSHandler) joinSucceeded(c *fiber.Ctx, userID string) {
+	if h.joinGuard != nil {
+		h.joinGuard.Succeeded(c.UserContext(), userID)
+	}
+}
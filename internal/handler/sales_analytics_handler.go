@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+const defaultSalesFunnelDays = 30
+
+// SalesAnalyticsHandler serves package conversion funnels to tenant admins
+type SalesAnalyticsHandler struct {
+	funnel *service.SalesFunnelService
+}
+
+func NewSalesAnalyticsHandler(funnel *service.SalesFunnelService) *SalesAnalyticsHandler {
+	return &SalesAnalyticsHandler{funnel: funnel}
+}
+
+// GetSalesFunnel GET /v1/tenant-admin/analytics/sales?from=2025-06-01&to=2025-06-30
+// Both dates are inclusive; the default is the last 30 days
+func (h *SalesAnalyticsHandler) GetSalesFunnel(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if s := c.Query("to"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid 'to' date format, use YYYY-MM-DD")
+		}
+		to = d
+	}
+	from := to.AddDate(0, 0, -(defaultSalesFunnelDays - 1))
+	if s := c.Query("from"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid 'from' date format, use YYYY-MM-DD")
+		}
+		from = d
+	}
+	if from.After(to) {
+		return response.Error(c, fiber.StatusBadRequest, "'from' must not be after 'to'")
+	}
+
+	funnel, err := h.funnel.Funnel(c.UserContext(), tenantID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, funnel)
+}
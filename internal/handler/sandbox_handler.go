@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// SandboxHandler serves the tools integration partners use on sandbox tenants. A tenant
+// is made a sandbox with "sandbox": true on PUT /v1/platform/tenants/:id.
+type SandboxHandler struct {
+	sandbox *service.SandboxService
+}
+
+func NewSandboxHandler(sandbox *service.SandboxService) *SandboxHandler {
+	return &SandboxHandler{sandbox: sandbox}
+}
+
+// ListNotifications GET /v1/platform/tenants/:id/sandbox/notifications?limit=&cursor=
+// Notifications the sandbox tenant would have sent, newest first
+func (h *SandboxHandler) ListNotifications(c *fiber.Ctx) error {
+	return h.listNotifications(c, c.Params("id"))
+}
+
+// ListMyNotifications GET /v1/tenant-admin/sandbox/notifications?limit=&cursor=
+func (h *SandboxHandler) ListMyNotifications(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	return h.listNotifications(c, tenantID)
+}
+
+func (h *SandboxHandler) listNotifications(c *fiber.Ctx, tenantID string) error {
+	q, _ := pageQuery(c)
+	page, err := h.sandbox.Notifications(c.UserContext(), tenantID, q)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return response.Error(c, fiber.StatusNotFound, "Tenant not found")
+		}
+		return pageError(c, err)
+	}
+	return response.OK(c, page)
+}
+
+// Reset POST /v1/platform/tenants/:id/sandbox/reset
+// Wipes the sandbox tenant's members, coaches and their activity; set-up data stays
+func (h *SandboxHandler) Reset(c *fiber.Ctx) error {
+	reset, err := h.sandbox.Reset(c.UserContext(), c.Params("id"))
+	if err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			return response.Error(c, fiber.StatusNotFound, "Tenant not found")
+		case domain.ErrNotSandbox:
+			return response.Error(c, fiber.StatusConflict, "Tenant is not a sandbox")
+		}
+		return response.Fail(c, err)
+	}
+	return response.OK(c, reset)
+}
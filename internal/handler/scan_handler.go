@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"mime/multipart"
 	"path/filepath"
@@ -9,18 +10,22 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/mansoorceksport/metamorph/internal/domain"
 	"github.com/mansoorceksport/metamorph/internal/middleware"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
 )
 
 // ScanHandler handles HTTP requests for scan operations
 type ScanHandler struct {
 	scanService domain.ScanService
+	ptService   *service.PTService // For the metrics the member's coach hides from them
 	maxUploadMB int64
 }
 
 // NewScanHandler creates a new scan handler
-func NewScanHandler(scanService domain.ScanService, maxUploadMB int64) *ScanHandler {
+func NewScanHandler(scanService domain.ScanService, ptService *service.PTService, maxUploadMB int64) *ScanHandler {
 	return &ScanHandler{
 		scanService: scanService,
+		ptService:   ptService,
 		maxUploadMB: maxUploadMB,
 	}
 }
@@ -30,28 +35,19 @@ func (h *ScanHandler) DigitizeScan(c *fiber.Ctx) error {
 	// Get user ID from context (set by FirebaseAuth middleware)
 	userID := middleware.GetUserID(c)
 	if userID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"error":   "user not authenticated",
-		})
+		return response.Error(c, fiber.StatusUnauthorized, "user not authenticated")
 	}
 
 	// Parse multipart form
 	form, err := c.MultipartForm()
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "invalid multipart form: " + err.Error(),
-		})
+		return response.FailAs(c, fiber.StatusBadRequest, fmt.Errorf("invalid multipart form: %w", err))
 	}
 
 	// Get image file
 	files := form.File["image"]
 	if len(files) == 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "missing 'image' field in form data",
-		})
+		return response.Error(c, fiber.StatusBadRequest, "missing 'image' field in form data")
 	}
 
 	imageFile := files[0]
@@ -59,37 +55,25 @@ func (h *ScanHandler) DigitizeScan(c *fiber.Ctx) error {
 	// Validate file size
 	maxBytes := h.maxUploadMB * 1024 * 1024
 	if imageFile.Size > maxBytes {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   fmt.Sprintf("file size exceeds maximum of %dMB", h.maxUploadMB),
-		})
+		return response.Error(c, fiber.StatusBadRequest, fmt.Sprintf("file size exceeds maximum of %dMB", h.maxUploadMB))
 	}
 
 	// Validate MIME type
 	if !isValidImageType(imageFile) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "invalid file type, only JPEG, PNG, and HEIC images are allowed",
-		})
+		return response.Error(c, fiber.StatusBadRequest, "invalid file type, only JPEG, PNG, and HEIC images are allowed")
 	}
 
 	// Read file contents
 	fileHandle, err := imageFile.Open()
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "failed to open uploaded file",
-		})
+		return response.Error(c, fiber.StatusInternalServerError, "failed to open uploaded file")
 	}
 	defer fileHandle.Close()
 
 	imageData := make([]byte, imageFile.Size)
 	_, err = fileHandle.Read(imageData)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "failed to read uploaded file",
-		})
+		return response.Error(c, fiber.StatusInternalServerError, "failed to read uploaded file")
 	}
 
 	// For now, we'll use the filename as imageURL
@@ -99,17 +83,15 @@ func (h *ScanHandler) DigitizeScan(c *fiber.Ctx) error {
 	// Process the scan
 	record, err := h.scanService.ProcessScan(c.UserContext(), userID, imageData, imageURL)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "failed to process scan: " + err.Error(),
-		})
+		if errors.Is(err, domain.ErrHealthConsentRequired) {
+			return response.FailAs(c, fiber.StatusForbidden, err)
+		}
+		return response.Fail(c, fmt.Errorf("failed to process scan: %w", err))
 	}
 
 	// Return success response
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"data":    record,
-	})
+	visibility := memberVisibility(c, h.ptService, userID)
+	return response.WithMeta(c, fiber.StatusOK, visibility.Scan(record), fiber.Map{"hidden_metrics": visibility.HiddenMetrics()})
 }
 
 // isValidImageType checks if the uploaded file is a valid image type
@@ -133,163 +115,117 @@ func isValidImageType(file *multipart.FileHeader) bool {
 func (h *ScanHandler) ListScans(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
 	if userID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"error":   "user not authenticated",
-		})
+		return response.Error(c, fiber.StatusUnauthorized, "user not authenticated")
 	}
 
 	records, err := h.scanService.GetAllScans(c.UserContext(), userID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "failed to retrieve scans: " + err.Error(),
-		})
+		return response.Fail(c, fmt.Errorf("failed to retrieve scans: %w", err))
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"data":    records,
-	})
+	visibility := memberVisibility(c, h.ptService, userID)
+	for i, record := range records {
+		records[i] = visibility.Scan(record)
+	}
+	return response.WithMeta(c, fiber.StatusOK, records, fiber.Map{"hidden_metrics": visibility.HiddenMetrics()})
 }
 
 // GetScan handles GET /v1/scans/:id
 func (h *ScanHandler) GetScan(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
 	if userID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"error":   "user not authenticated",
-		})
+		return response.Error(c, fiber.StatusUnauthorized, "user not authenticated")
 	}
 
 	scanID := c.Params("id")
 	if scanID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "scan id is required",
-		})
+		return response.Error(c, fiber.StatusBadRequest, "scan id is required")
 	}
 
 	record, err := h.scanService.GetScanByID(c.UserContext(), userID, scanID)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"success": false,
-				"error":   "scan not found",
-			})
+			return response.Error(c, fiber.StatusNotFound, "scan not found")
 		}
 		if err == domain.ErrForbidden {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"success": false,
-				"error":   "you don't have access to this scan",
-			})
+			return response.Error(c, fiber.StatusForbidden, "you don't have access to this scan")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "failed to retrieve scan: " + err.Error(),
-		})
+		return response.Fail(c, fmt.Errorf("failed to retrieve scan: %w", err))
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"data":    record,
-	})
+	setETag(c, record.Version)
+	visibility := memberVisibility(c, h.ptService, userID)
+	return response.WithMeta(c, fiber.StatusOK, visibility.Scan(record), fiber.Map{"hidden_metrics": visibility.HiddenMetrics()})
 }
 
 // UpdateScan handles PATCH /v1/scans/:id
 func (h *ScanHandler) UpdateScan(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
 	if userID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"error":   "user not authenticated",
-		})
+		return response.Error(c, fiber.StatusUnauthorized, "user not authenticated")
 	}
 
 	scanID := c.Params("id")
 	if scanID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "scan id is required",
-		})
+		return response.Error(c, fiber.StatusBadRequest, "scan id is required")
 	}
 
 	// Parse request body
 	var updates map[string]interface{}
 	if err := c.BodyParser(&updates); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "invalid request body: " + err.Error(),
-		})
+		return response.FailAs(c, fiber.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+	}
+
+	// If-Match takes precedence over a "version" field in the body
+	version, ok, err := ifMatchVersion(c)
+	if err != nil {
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	if ok {
+		updates["version"] = float64(version)
 	}
 
 	record, err := h.scanService.UpdateScan(c.UserContext(), userID, scanID, updates)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"success": false,
-				"error":   "scan not found",
-			})
+			return response.Error(c, fiber.StatusNotFound, "scan not found")
 		}
 		if err == domain.ErrForbidden {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"success": false,
-				"error":   "you don't have access to this scan",
-			})
+			return response.Error(c, fiber.StatusForbidden, "you don't have access to this scan")
+		}
+		if err == domain.ErrVersionConflict {
+			return response.FailAs(c, fiber.StatusConflict, err)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "failed to update scan: " + err.Error(),
-		})
+		return response.Fail(c, fmt.Errorf("failed to update scan: %w", err))
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"data":    record,
-	})
+	setETag(c, record.Version)
+	visibility := memberVisibility(c, h.ptService, userID)
+	return response.WithMeta(c, fiber.StatusOK, visibility.Scan(record), fiber.Map{"hidden_metrics": visibility.HiddenMetrics()})
 }
 
 // DeleteScan handles DELETE /v1/scans/:id
 func (h *ScanHandler) DeleteScan(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
 	if userID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"error":   "user not authenticated",
-		})
+		return response.Error(c, fiber.StatusUnauthorized, "user not authenticated")
 	}
 
 	scanID := c.Params("id")
 	if scanID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "scan id is required",
-		})
+		return response.Error(c, fiber.StatusBadRequest, "scan id is required")
 	}
 
 	err := h.scanService.DeleteScan(c.UserContext(), userID, scanID)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"success": false,
-				"error":   "scan not found",
-			})
+			return response.Error(c, fiber.StatusNotFound, "scan not found")
 		}
 		if err == domain.ErrForbidden {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"success": false,
-				"error":   "you don't have access to this scan",
-			})
+			return response.Error(c, fiber.StatusForbidden, "you don't have access to this scan")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "failed to delete scan: " + err.Error(),
-		})
+		return response.Fail(c, fmt.Errorf("failed to delete scan: %w", err))
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"message": "scan deleted successfully",
-	})
+	return response.OK(c, fiber.Map{"message": "scan deleted successfully"})
 }
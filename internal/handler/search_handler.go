@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// SearchHandler serves the coach app's global search
+type SearchHandler struct {
+	searchService *service.SearchService
+}
+
+func NewSearchHandler(searchService *service.SearchService) *SearchHandler {
+	return &SearchHandler{searchService: searchService}
+}
+
+// Search GET /v1/pro/search?q=leg&limit=5
+// Finds the coach's clients, sessions and client scans, and exercises, most relevant first;
+// limit caps each kind
+func (h *SearchHandler) Search(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("userID").(string)
+
+	results, err := h.searchService.Search(c.UserContext(), tenantID, userID, c.Query("q"), c.QueryInt("limit"))
+	if err != nil {
+		return searchError(c, err)
+	}
+	return response.OK(c, results)
+}
+
+func searchError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, domain.ErrInvalidSearchQuery) {
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	return response.Fail(c, err)
+}
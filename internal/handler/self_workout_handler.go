@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// SelfWorkoutHandler serves /v1/me/workouts, where members log training without a coach
+type SelfWorkoutHandler struct {
+	workoutService *service.WorkoutService
+	userRepo       domain.UserRepository
+}
+
+func NewSelfWorkoutHandler(workoutService *service.WorkoutService, userRepo domain.UserRepository) *SelfWorkoutHandler {
+	return &SelfWorkoutHandler{workoutService: workoutService, userRepo: userRepo}
+}
+
+// StartWorkout POST /v1/me/workouts
+// Body: optional template_id, branch_id (defaults to the member's home branch), session_goal, focus_area
+func (h *SelfWorkoutHandler) StartWorkout(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	var req struct {
+		TemplateID  string `json:"template_id"`
+		BranchID    string `json:"branch_id"`
+		SessionGoal string `json:"session_goal"`
+		FocusArea   string `json:"focus_area"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	member, err := h.userRepo.GetByID(c.UserContext(), userID)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	member, err = member.ScopedTo(tenantID)
+	if err != nil {
+		return response.FailAs(c, fiber.StatusForbidden, err)
+	}
+
+	workout, err := h.workoutService.StartSelfWorkout(c.UserContext(), member, req.BranchID, req.TemplateID, req.SessionGoal, req.FocusArea)
+	if err != nil {
+		return selfWorkoutError(c, err)
+	}
+	return response.Created(c, workout)
+}
+
+// GetCurrentWorkout GET /v1/me/workouts/current
+// The member's unfinished self-logged workout; 404 when there is none
+func (h *SelfWorkoutHandler) GetCurrentWorkout(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	workout, err := h.workoutService.CurrentSelfWorkout(c.UserContext(), userID)
+	if err != nil {
+		return selfWorkoutError(c, err)
+	}
+	return response.OK(c, workout)
+}
+
+// AddExercise POST /v1/me/workouts/:id/exercises
+func (h *SelfWorkoutHandler) AddExercise(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	var req struct {
+		ClientID    string `json:"client_id"`
+		ExerciseID  string `json:"exercise_id"`
+		TargetSets  int    `json:"target_sets"`
+		TargetReps  int    `json:"target_reps"`
+		RestSeconds int    `json:"rest_seconds"`
+		Notes       string `json:"notes"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	planned, err := h.workoutService.AddSelfWorkoutExercise(c.UserContext(), userID, c.Params("id"), req.ExerciseID, req.ClientID, req.TargetSets, req.TargetReps, req.RestSeconds, req.Notes)
+	if err != nil {
+		return selfWorkoutError(c, err)
+	}
+	return response.Created(c, planned)
+}
+
+type selfWorkoutSetRequest struct {
+	ExerciseID string  `json:"exercise_id"` // Planned exercise ID or client_id; only used when logging a new set
+	ClientID   string  `json:"client_id"`
+	Weight     float64 `json:"weight"`
+	Reps       int     `json:"reps"`
+	Remarks    string  `json:"remarks"`
+	Completed  bool    `json:"completed"`
+}
+
+// LogSet POST /v1/me/workouts/:id/sets
+func (h *SelfWorkoutHandler) LogSet(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	var req selfWorkoutSetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	set, err := h.workoutService.LogSelfWorkoutSet(c.UserContext(), userID, c.Params("id"), req.ExerciseID, req.ClientID, req.Weight, req.Reps, req.Remarks, req.Completed)
+	if err != nil {
+		return selfWorkoutError(c, err)
+	}
+	return response.Created(c, set)
+}
+
+// UpdateSet PUT /v1/me/workouts/:id/sets/:set_id
+func (h *SelfWorkoutHandler) UpdateSet(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	var req selfWorkoutSetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := h.workoutService.UpdateSelfWorkoutSet(c.UserContext(), userID, c.Params("id"), c.Params("set_id"), req.Weight, req.Reps, req.Remarks, req.Completed); err != nil {
+		return selfWorkoutError(c, err)
+	}
+	return response.OK(c, fiber.Map{"message": "updated"})
+}
+
+// CompleteWorkout POST /v1/me/workouts/:id/complete
+// Volume and PBs are computed as for a PT session; no contract credit is used
+func (h *SelfWorkoutHandler) CompleteWorkout(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	schedule, err := h.workoutService.CompleteSelfWorkout(c.UserContext(), userID, c.Params("id"))
+	if err != nil {
+		return selfWorkoutError(c, err)
+	}
+	return response.OK(c, schedule)
+}
+
+func selfWorkoutError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrScheduleNotFound, domain.ErrInvalidID:
+		return response.Error(c, fiber.StatusNotFound, "Workout not found")
+	case domain.ErrSessionNotFound, domain.ErrExerciseULIDNotFound, domain.ErrExerciseNotFound, domain.ErrTemplateNotFound:
+		return response.FailAs(c, fiber.StatusNotFound, err)
+	case domain.ErrForbidden:
+		return response.Error(c, fiber.StatusForbidden, "You don't have access to this branch")
+	case domain.ErrSelfWorkoutInProgress, domain.ErrSelfWorkoutClosed, domain.ErrSelfWorkoutExpired:
+		return response.FailAs(c, fiber.StatusConflict, err)
+	}
+	return response.Fail(c, err)
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// SessionConfirmationHandler takes members' answers to session reminders
+type SessionConfirmationHandler struct {
+	confirmations *service.SessionConfirmationService
+}
+
+func NewSessionConfirmationHandler(confirmations *service.SessionConfirmationService) *SessionConfirmationHandler {
+	return &SessionConfirmationHandler{confirmations: confirmations}
+}
+
+// ConfirmMySession POST /v1/me/schedules/:id/confirm
+// Body: status ("confirmed", the default, or "declined") and an optional note for the coach
+func (h *SessionConfirmationHandler) ConfirmMySession(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	req := struct {
+		Status string `json:"status"`
+		Note   string `json:"note"`
+	}{Status: domain.ConfirmationConfirmed}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
+	schedule, err := h.confirmations.Confirm(c.UserContext(), userID, c.Params("id"), req.Status, req.Note)
+	if err != nil {
+		switch err {
+		case domain.ErrScheduleNotFound, domain.ErrInvalidID:
+			return response.Error(c, fiber.StatusNotFound, "Schedule not found")
+		case domain.ErrInvalidConfirmation:
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		case domain.ErrSessionNotConfirmable:
+			return response.FailAs(c, fiber.StatusConflict, err)
+		}
+		return response.Fail(c, err)
+	}
+	return response.OK(c, schedule)
+}
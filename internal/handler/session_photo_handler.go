@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// SessionPhotoHandler serves session photos and members' consent to them. The /v1/me
+// routes act as the member, the /v1/pro routes as tenant staff.
+type SessionPhotoHandler struct {
+	photoService *service.SessionPhotoService
+	userRepo     domain.UserRepository
+}
+
+func NewSessionPhotoHandler(photoService *service.SessionPhotoService, userRepo domain.UserRepository) *SessionPhotoHandler {
+	return &SessionPhotoHandler{photoService: photoService, userRepo: userRepo}
+}
+
+// UploadPhoto POST /v1/pro/schedules/:id/photos (multipart, field "photo", optional "caption")
+func (h *SessionPhotoHandler) UploadPhoto(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	fileHeader, err := c.FormFile("photo")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "photo file is required")
+	}
+	f, err := fileHeader.Open()
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "failed to read file")
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "failed to read file")
+	}
+
+	photo, err := h.photoService.UploadPhoto(c.UserContext(), userID, tenantID, c.Params("id"), data,
+		fileHeader.Header.Get("Content-Type"), c.FormValue("caption"))
+	if err != nil {
+		return sessionPhotoError(c, err)
+	}
+	return response.Created(c, photo)
+}
+
+// ListSchedulePhotos GET /v1/pro/schedules/:id/photos
+func (h *SessionPhotoHandler) ListSchedulePhotos(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+	photos, err := h.photoService.ListSchedulePhotos(c.UserContext(), userID, tenantID, true, c.Params("id"))
+	if err != nil {
+		return sessionPhotoError(c, err)
+	}
+	return response.OK(c, photos)
+}
+
+// DeleteMyPhoto DELETE /v1/me/photos/:id
+func (h *SessionPhotoHandler) DeleteMyPhoto(c *fiber.Ctx) error {
+	return h.delete(c, false)
+}
+
+// DeletePhoto DELETE /v1/pro/photos/:id
+func (h *SessionPhotoHandler) DeletePhoto(c *fiber.Ctx) error {
+	return h.delete(c, true)
+}
+
+func (h *SessionPhotoHandler) delete(c *fiber.Ctx, staff bool) error {
+	userID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+	if err := h.photoService.DeletePhoto(c.UserContext(), userID, tenantID, staff, c.Params("id")); err != nil {
+		return sessionPhotoError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetMyConsent GET /v1/me/photo-consent
+func (h *SessionPhotoHandler) GetMyConsent(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+	consent, err := h.photoService.Consent(c.UserContext(), tenantID, userID)
+	if err != nil {
+		return sessionPhotoError(c, err)
+	}
+	return response.OK(c, consent)
+}
+
+// UpdateMyConsent PUT /v1/me/photo-consent
+// Body: capture (coaches may photograph my sessions), reports (include photos in my progress reports)
+func (h *SessionPhotoHandler) UpdateMyConsent(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	var req struct {
+		Capture bool `json:"capture"`
+		Reports bool `json:"reports"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	consent, err := h.photoService.SetConsent(c.UserContext(), tenantID, userID, req.Capture, req.Reports)
+	if err != nil {
+		return sessionPhotoError(c, err)
+	}
+	return response.OK(c, consent)
+}
+
+// GetClientConsent GET /v1/pro/clients/:id/photo-consent
+// Whether the coach may photograph the client's sessions
+func (h *SessionPhotoHandler) GetClientConsent(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	memberID := c.Params("id")
+
+	member, err := h.userRepo.GetByID(c.UserContext(), memberID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return response.Error(c, fiber.StatusNotFound, "Member not found")
+		}
+		return response.Fail(c, err)
+	}
+	if _, err := member.ScopedTo(tenantID); err != nil {
+		return response.Fail(c, domain.ErrTenantScopeViolation)
+	}
+
+	consent, err := h.photoService.Consent(c.UserContext(), tenantID, memberID)
+	if err != nil {
+		return sessionPhotoError(c, err)
+	}
+	return response.OK(c, consent)
+}
+
+func sessionPhotoError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrScheduleNotFound, domain.ErrSessionPhotoNotFound, domain.ErrInvalidID:
+		return response.FailAs(c, fiber.StatusNotFound, err)
+	case domain.ErrForbidden, domain.ErrPhotoConsentRequired:
+		return response.FailAs(c, fiber.StatusForbidden, err)
+	case domain.ErrPhotoTooLarge:
+		return response.FailAs(c, fiber.StatusRequestEntityTooLarge, err)
+	case domain.ErrPhotoSessionNotCompleted:
+		return response.FailAs(c, fiber.StatusConflict, err)
+	case domain.ErrUnsupportedPhoto:
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	return response.Fail(c, err)
+}
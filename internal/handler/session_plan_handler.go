@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// SessionPlanHandler serves the pre-session brief: coaches write it, members read it
+type SessionPlanHandler struct {
+	planService *service.SessionPlanService
+}
+
+func NewSessionPlanHandler(planService *service.SessionPlanService) *SessionPlanHandler {
+	return &SessionPlanHandler{planService: planService}
+}
+
+// PlanSession PUT /v1/pro/schedules/:id/plan
+// Body: optional template_id (only for a session without exercises), notes for the member,
+// and notify to send the member a notification
+func (h *SessionPlanHandler) PlanSession(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	var req struct {
+		TemplateID string `json:"template_id"`
+		Notes      string `json:"notes"`
+		Notify     bool   `json:"notify"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	plan, err := h.planService.PlanSession(c.UserContext(), userID, c.Params("id"), req.TemplateID, req.Notes, req.Notify)
+	if err != nil {
+		return sessionPlanError(c, err)
+	}
+	return response.OK(c, plan)
+}
+
+// GetMyPlan GET /v1/me/schedules/:id/plan
+func (h *SessionPlanHandler) GetMyPlan(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	plan, err := h.planService.MemberPlan(c.UserContext(), userID, c.Params("id"))
+	if err != nil {
+		return sessionPlanError(c, err)
+	}
+	return response.OK(c, plan)
+}
+
+func sessionPlanError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrScheduleNotFound, domain.ErrInvalidID:
+		return response.Error(c, fiber.StatusNotFound, "Schedule not found")
+	case domain.ErrSessionPlanNotFound, domain.ErrTemplateNotFound:
+		return response.FailAs(c, fiber.StatusNotFound, err)
+	case domain.ErrForbidden:
+		return response.Error(c, fiber.StatusForbidden, "You can only plan your own sessions")
+	case domain.ErrSessionAlreadyHeld, domain.ErrSessionAlreadyPlanned:
+		return response.FailAs(c, fiber.StatusConflict, err)
+	}
+	return response.Fail(c, err)
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// SetVideoHandler serves form-check videos. The /v1/me routes act as the member, the
+// /v1/pro routes as tenant staff.
+type SetVideoHandler struct {
+	videoService *service.SetVideoService
+}
+
+func NewSetVideoHandler(videoService *service.SetVideoService) *SetVideoHandler {
+	return &SetVideoHandler{videoService: videoService}
+}
+
+// UploadMySetVideo POST /v1/me/sets/:id/videos (multipart, field "video")
+func (h *SetVideoHandler) UploadMySetVideo(c *fiber.Ctx) error {
+	return h.upload(c, false)
+}
+
+// UploadSetVideo POST /v1/pro/sets/:id/videos (multipart, field "video")
+func (h *SetVideoHandler) UploadSetVideo(c *fiber.Ctx) error {
+	return h.upload(c, true)
+}
+
+func (h *SetVideoHandler) upload(c *fiber.Ctx, staff bool) error {
+	userID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	fileHeader, err := c.FormFile("video")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "video file is required")
+	}
+	f, err := fileHeader.Open()
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "failed to read file")
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "failed to read file")
+	}
+
+	video, err := h.videoService.UploadVideo(c.UserContext(), userID, tenantID, staff, c.Params("id"), data, fileHeader.Header.Get("Content-Type"))
+	if err != nil {
+		return setVideoError(c, err)
+	}
+	return response.Created(c, video)
+}
+
+// ListScheduleVideos GET /v1/pro/schedules/:schedule_id/videos
+func (h *SetVideoHandler) ListScheduleVideos(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+	videos, err := h.videoService.ListScheduleVideos(c.UserContext(), userID, tenantID, true, c.Params("schedule_id"))
+	if err != nil {
+		return setVideoError(c, err)
+	}
+	return response.OK(c, videos)
+}
+
+// AddComment POST /v1/pro/videos/:id/comments
+// Body: text, optional at_seconds to pin the comment to a moment in the clip
+func (h *SetVideoHandler) AddComment(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	var req struct {
+		Text      string   `json:"text"`
+		AtSeconds *float64 `json:"at_seconds"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	comment, err := h.videoService.AddComment(c.UserContext(), userID, tenantID, c.Params("id"), req.Text, req.AtSeconds)
+	if err != nil {
+		return setVideoError(c, err)
+	}
+	return response.Created(c, comment)
+}
+
+// DeleteMyVideo DELETE /v1/me/videos/:id
+func (h *SetVideoHandler) DeleteMyVideo(c *fiber.Ctx) error {
+	return h.delete(c, false)
+}
+
+// DeleteVideo DELETE /v1/pro/videos/:id
+func (h *SetVideoHandler) DeleteVideo(c *fiber.Ctx) error {
+	return h.delete(c, true)
+}
+
+func (h *SetVideoHandler) delete(c *fiber.Ctx, staff bool) error {
+	userID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+	if err := h.videoService.DeleteVideo(c.UserContext(), userID, tenantID, staff, c.Params("id")); err != nil {
+		return setVideoError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func setVideoError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrSessionNotFound, domain.ErrScheduleNotFound, domain.ErrSetVideoNotFound, domain.ErrInvalidID:
+		return response.FailAs(c, fiber.StatusNotFound, err)
+	case domain.ErrForbidden:
+		return response.FailAs(c, fiber.StatusForbidden, err)
+	case domain.ErrVideoTooLarge:
+		return response.FailAs(c, fiber.StatusRequestEntityTooLarge, err)
+	case domain.ErrVideoTooLong, domain.ErrUnsupportedVideo, domain.ErrInvalidComment:
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	return response.Fail(c, err)
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// SettlementHandler reconciles the payment provider's settlement files for tenant admins
+type SettlementHandler struct {
+	settlements *service.SettlementService
+	maxUploadMB int64
+}
+
+func NewSettlementHandler(settlements *service.SettlementService, maxUploadMB int64) *SettlementHandler {
+	return &SettlementHandler{settlements: settlements, maxUploadMB: maxUploadMB}
+}
+
+// Reconcile POST /v1/tenant-admin/finance/reconciliation
+// Multipart form: file (CSV with sid, trx_id, amount, fee and settled_at columns) and an
+// optional period_from and period_to (YYYY-MM-DD, inclusive) to also flag recorded payments
+// missing from the file
+func (h *SettlementHandler) Reconcile(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("userID").(string)
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Missing 'file' field in form data")
+	}
+	if file.Size > h.maxUploadMB*1024*1024 {
+		return response.Error(c, fiber.StatusBadRequest, fmt.Sprintf("File size exceeds maximum of %dMB", h.maxUploadMB))
+	}
+
+	var from, to *time.Time
+	if s := c.FormValue("period_from"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid 'period_from' date format, use YYYY-MM-DD")
+		}
+		from = &d
+	}
+	if s := c.FormValue("period_to"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid 'period_to' date format, use YYYY-MM-DD")
+		}
+		d = d.AddDate(0, 0, 1) // Include the whole day
+		to = &d
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to open uploaded file")
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to read uploaded file")
+	}
+
+	rec, err := h.settlements.Reconcile(c.UserContext(), tenantID, userID, file.Filename, data, from, to)
+	if err != nil {
+		return settlementError(c, err)
+	}
+	return response.Created(c, rec)
+}
+
+// List GET /v1/tenant-admin/finance/reconciliation
+// Optional: limit, cursor. Items are left out; fetch a reconciliation for them.
+func (h *SettlementHandler) List(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	q, _ := pageQuery(c)
+	page, err := h.settlements.List(c.UserContext(), tenantID, q)
+	if err != nil {
+		return pageError(c, err)
+	}
+	return response.OK(c, page)
+}
+
+// Get GET /v1/tenant-admin/finance/reconciliation/:id
+func (h *SettlementHandler) Get(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	rec, err := h.settlements.Get(c.UserContext(), tenantID, c.Params("id"))
+	if err != nil {
+		return settlementError(c, err)
+	}
+	return response.OK(c, rec)
+}
+
+func settlementError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return response.Error(c, fiber.StatusNotFound, "Reconciliation not found")
+	case errors.Is(err, domain.ErrInvalidSettlementFile):
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	return response.Fail(c, err)
+}
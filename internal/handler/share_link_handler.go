@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"math"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// sharePage is the public page of a share link: the card, and the Open Graph tags social
+// networks build their link previews from
+var sharePage = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Title}} | {{.Gym}}</title>
+<meta property="og:type" content="website">
+<meta property="og:site_name" content="{{.Gym}}">
+<meta property="og:title" content="{{if .FirstName}}{{.FirstName}}: {{end}}{{.Title}}">
+<meta property="og:description" content="{{.Headline}}">
+{{if .LogoURL}}<meta property="og:image" content="{{.LogoURL}}">{{end}}
+</head>
+<body>
+<main>
+{{if .LogoURL}}<img src="{{.LogoURL}}" alt="{{.Gym}}" height="64">{{end}}
+<p>{{.Gym}}</p>
+<h1>{{.Title}}</h1>
+<h2>{{.Headline}}</h2>
+{{if .Stats}}<dl>{{range .Stats}}<dt>{{.Label}}</dt><dd>{{.Value}}</dd>{{end}}</dl>{{end}}
+<p>{{if .FirstName}}{{.FirstName}}, {{end}}{{.Date.Format "2 January 2006"}}</p>
+</main>
+</body>
+</html>
+`))
+
+// ShareLinkHandler lets members share achievement cards and serves the cards publicly
+type ShareLinkHandler struct {
+	shareService *service.ShareLinkService
+}
+
+func NewShareLinkHandler(shareService *service.ShareLinkService) *ShareLinkHandler {
+	return &ShareLinkHandler{shareService: shareService}
+}
+
+// CreateShareLinkRequest is the body of POST /v1/me/share-links
+type CreateShareLinkRequest struct {
+	Kind    string `json:"kind"`    // "personal_best" or "month"
+	Subject string `json:"subject"` // The PB's ID, or the month as YYYY-MM
+}
+
+// CreateMyLink POST /v1/me/share-links
+// The response is the only time the link's token is shown
+func (h *ShareLinkHandler) CreateMyLink(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	var req CreateShareLinkRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	link, secret, err := h.shareService.CreateLink(c.UserContext(), tenantID, userID, req.Kind, req.Subject)
+	if err != nil {
+		return shareLinkError(c, err)
+	}
+	return response.Created(c, fiber.Map{
+		"token":      secret,
+		"path":       "/v1/public/share/" + secret + "/page",
+		"share_link": link,
+	})
+}
+
+// ListMyLinks GET /v1/me/share-links
+func (h *ShareLinkHandler) ListMyLinks(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+	links, err := h.shareService.ListLinks(c.UserContext(), tenantID, userID)
+	if err != nil {
+		return shareLinkError(c, err)
+	}
+	return response.OK(c, links)
+}
+
+// RevokeMyLink DELETE /v1/me/share-links/:id
+func (h *ShareLinkHandler) RevokeMyLink(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	if err := h.shareService.RevokeLink(c.UserContext(), userID, c.Params("id")); err != nil {
+		return shareLinkError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetSharedCard GET /v1/public/share/:token
+// The card as JSON, for the app and the gym's site to render
+func (h *ShareLinkHandler) GetSharedCard(c *fiber.Ctx) error {
+	card, err := h.view(c)
+	if err != nil {
+		return shareLinkError(c, err)
+	}
+	return response.OK(c, card)
+}
+
+// GetSharedPage GET /v1/public/share/:token/page
+// The card as a web page with link preview tags; this is the URL members post
+func (h *ShareLinkHandler) GetSharedPage(c *fiber.Ctx) error {
+	card, err := h.view(c)
+	if err != nil {
+		return shareLinkError(c, err)
+	}
+	var page bytes.Buffer
+	if err := sharePage.Execute(&page, card); err != nil {
+		return shareLinkError(c, err)
+	}
+	c.Type("html", "utf-8")
+	return c.Send(page.Bytes())
+}
+
+func (h *ShareLinkHandler) view(c *fiber.Ctx) (*domain.SharedAchievement, error) {
+	// A revoked card must stop showing at once, so neither browsers nor CDNs may keep a copy
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	card, retryAfter, err := h.shareService.View(c.UserContext(), c.Params("token"), c.IP())
+	if errors.Is(err, domain.ErrRateLimited) {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+	return card, err
+}
+
+func shareLinkError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrShareLinkNotFound):
+		return response.Fail(c, domain.ErrShareLinkNotFound)
+	case errors.Is(err, domain.ErrInvalidShareLink):
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	case errors.Is(err, domain.ErrShareLinkMinor):
+		return response.FailAs(c, fiber.StatusForbidden, err)
+	case errors.Is(err, domain.ErrRateLimited):
+		return response.FailAs(c, fiber.StatusTooManyRequests, err)
+	}
+	return response.Fail(c, err)
+}
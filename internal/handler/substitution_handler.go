@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// SubstitutionHandler serves the coach substitution board: coaches offer the sessions they
+// can't make, colleagues at the branch claim them, and tenant admins oversee it all
+type SubstitutionHandler struct {
+	substitutions *service.SubstitutionService
+	userRepo      domain.UserRepository
+}
+
+func NewSubstitutionHandler(substitutions *service.SubstitutionService, userRepo domain.UserRepository) *SubstitutionHandler {
+	return &SubstitutionHandler{substitutions: substitutions, userRepo: userRepo}
+}
+
+// MarkUnavailable POST /v1/pro/unavailability
+// Body: from, to (RFC 3339), reason. Offers the coach's sessions in the period for cover.
+func (h *SubstitutionHandler) MarkUnavailable(c *fiber.Ctx) error {
+	coachID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	var req struct {
+		From   time.Time `json:"from"`
+		To     time.Time `json:"to"`
+		Reason string    `json:"reason"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	offers, err := h.substitutions.MarkUnavailable(c.UserContext(), coachID, tenantID, req.From, req.To, req.Reason)
+	if err != nil {
+		return substitutionError(c, err)
+	}
+	return response.Created(c, fiber.Map{"offers": offers})
+}
+
+// ListOpen GET /v1/pro/substitutions
+// Sessions at the coach's branches that are waiting for a substitute, soonest first
+func (h *SubstitutionHandler) ListOpen(c *fiber.Ctx) error {
+	coach, err := h.coach(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, "Failed to fetch user profile")
+	}
+	offers, err := h.substitutions.ListOpen(c.UserContext(), coach)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, offers)
+}
+
+// Claim POST /v1/pro/substitutions/:id/claim
+func (h *SubstitutionHandler) Claim(c *fiber.Ctx) error {
+	coach, err := h.coach(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, "Failed to fetch user profile")
+	}
+	offer, err := h.substitutions.Claim(c.UserContext(), coach, c.Params("id"))
+	if err != nil {
+		return substitutionError(c, err)
+	}
+	return response.OK(c, offer)
+}
+
+// List GET /v1/tenant-admin/substitutions?status=&limit=&cursor=
+func (h *SubstitutionHandler) List(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	q, _ := pageQuery(c)
+	page, err := h.substitutions.List(c.UserContext(), tenantID, c.Query("status"), q)
+	if err != nil {
+		return pageError(c, err)
+	}
+	return response.OK(c, page)
+}
+
+// Assign POST /v1/tenant-admin/substitutions/:id/assign
+// Body: coach_id of the substitute
+func (h *SubstitutionHandler) Assign(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	actorID, _ := c.Locals("userID").(string)
+
+	var req struct {
+		CoachID string `json:"coach_id"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.CoachID == "" {
+		return response.Error(c, fiber.StatusBadRequest, "coach_id is required")
+	}
+
+	offer, err := h.substitutions.Assign(c.UserContext(), actorID, tenantID, c.Params("id"), req.CoachID)
+	if err != nil {
+		return substitutionError(c, err)
+	}
+	return response.OK(c, offer)
+}
+
+// Cancel POST /v1/tenant-admin/substitutions/:id/cancel
+// Withdraws the offer; the session stays with its coach
+func (h *SubstitutionHandler) Cancel(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	actorID, _ := c.Locals("userID").(string)
+
+	offer, err := h.substitutions.Cancel(c.UserContext(), actorID, tenantID, c.Params("id"))
+	if err != nil {
+		return substitutionError(c, err)
+	}
+	return response.OK(c, offer)
+}
+
+// coach loads the calling coach with their branches in the token's tenant
+func (h *SubstitutionHandler) coach(c *fiber.Ctx) (*domain.User, error) {
+	coachID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals("tenant_id").(string)
+	coach, err := h.userRepo.GetByID(c.UserContext(), coachID)
+	if err != nil {
+		return nil, err
+	}
+	if scoped, err := coach.ScopedTo(tenantID); err == nil {
+		coach = scoped
+	}
+	return coach, nil
+}
+
+func substitutionError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrNotFound, domain.ErrInvalidID, domain.ErrScheduleNotFound:
+		return response.FailAs(c, fiber.StatusNotFound, err)
+	case domain.ErrInvalidUnavailability:
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	case domain.ErrBranchNotAllowed:
+		return response.FailAs(c, fiber.StatusForbidden, err)
+	case domain.ErrSubstitutionClosed, domain.ErrCannotCover:
+		return response.FailAs(c, fiber.StatusConflict, err)
+	}
+	return response.Fail(c, err)
+}
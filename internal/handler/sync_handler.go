@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// SyncHandler is the coach app's offline sync endpoint
+type SyncHandler struct {
+	syncService *service.SyncService
+}
+
+func NewSyncHandler(syncService *service.SyncService) *SyncHandler {
+	return &SyncHandler{syncService: syncService}
+}
+
+// Sync POST /v1/pro/sync
+// Body: {"sync_token": "...", "mutations": [{"id": "01J...", "type": "set_log.upsert", "set_log": {...}}]}
+// Applies the mutations queued offline and returns their results, the server changes since
+// sync_token (omit it on first sync) and the token for the next sync. Resending a batch is
+// safe: mutations already applied come back as "duplicate".
+func (h *SyncHandler) Sync(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	coachID, _ := c.Locals("userID").(string)
+
+	var req struct {
+		SyncToken string                `json:"sync_token"`
+		Mutations []domain.SyncMutation `json:"mutations"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	outcome, err := h.syncService.Sync(c.UserContext(), tenantID, coachID, req.SyncToken, req.Mutations)
+	if err != nil {
+		return syncError(c, err)
+	}
+	return response.OK(c, fiber.Map{
+		"results":    outcome.Results,
+		"sync_token": outcome.SyncToken,
+		"delta": fiber.Map{
+			"schedules": outcome.Delta.Schedules,
+			"set_logs":  outcome.Delta.SetLogs,
+			"members":   presentUsers(c, outcome.Delta.Members),
+		},
+	})
+}
+
+func syncError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidSyncBatch):
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	case errors.Is(err, domain.ErrInvalidSyncToken):
+		return response.Error(c, fiber.StatusBadRequest, "Invalid sync token; sync again without one")
+	}
+	return response.Fail(c, err)
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// TenantDashboardHandler serves the tenant-admin dashboard and each admin's widget layout
+type TenantDashboardHandler struct {
+	dashboardService *service.TenantDashboardService
+}
+
+func NewTenantDashboardHandler(dashboardService *service.TenantDashboardService) *TenantDashboardHandler {
+	return &TenantDashboardHandler{dashboardService: dashboardService}
+}
+
+// GetDashboard GET /v1/tenant-admin/dashboard
+// Returns the admin's layout with each widget's value, in display order
+func (h *TenantDashboardHandler) GetDashboard(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("userID").(string)
+
+	dashboard, err := h.dashboardService.Dashboard(c.UserContext(), tenantID, userID)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, dashboard)
+}
+
+// GetLayout GET /v1/tenant-admin/dashboard/layout
+func (h *TenantDashboardHandler) GetLayout(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("userID").(string)
+
+	layout, err := h.dashboardService.Layout(c.UserContext(), tenantID, userID)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, fiber.Map{"layout": layout, "widget_types": domain.DashboardWidgetTypes})
+}
+
+// UpdateLayout PUT /v1/tenant-admin/dashboard/layout
+// Body: {"widgets": [{"type": "revenue", "threshold": {"low": 1000000}}, ...]} in display order
+func (h *TenantDashboardHandler) UpdateLayout(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("userID").(string)
+
+	var req struct {
+		Widgets []domain.DashboardWidget `json:"widgets"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	layout, err := h.dashboardService.SaveLayout(c.UserContext(), &domain.DashboardLayout{TenantID: tenantID, UserID: userID, Widgets: req.Widgets})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidDashboardLayout) {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		}
+		return response.Fail(c, err)
+	}
+	return response.OK(c, layout)
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// TenantWebhookHandler manages the webhooks a tenant receives domain events on
+type TenantWebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+func NewTenantWebhookHandler(webhookService *service.WebhookService) *TenantWebhookHandler {
+	return &TenantWebhookHandler{webhookService: webhookService}
+}
+
+// TenantWebhookRequest is the body of creating or updating a webhook
+type TenantWebhookRequest struct {
+	URL         string   `json:"url"`    // Must be https
+	Events      []string `json:"events"` // schedule.completed, contract.created, scan.digitized, pb.achieved
+	Description string   `json:"description"`
+	Active      *bool    `json:"active"` // Updates only; defaults to true
+}
+
+func (r *TenantWebhookRequest) webhook() *domain.Webhook {
+	webhook := &domain.Webhook{URL: r.URL, Events: r.Events, Description: r.Description, Active: true}
+	if r.Active != nil {
+		webhook.Active = *r.Active
+	}
+	return webhook
+}
+
+// CreatedWebhookResponse is a new webhook with its signing secret
+type CreatedWebhookResponse struct {
+	Webhook *domain.Webhook `json:"webhook"`
+	Secret  string          `json:"secret"`
+}
+
+// WebhookListResponse is the tenant's webhooks and the events they may subscribe to
+type WebhookListResponse struct {
+	Data   []*domain.Webhook `json:"data"`
+	Events []string          `json:"events"`
+}
+
+// CreateWebhook POST /v1/tenant-admin/webhooks
+// The response is the only time the signing secret is shown.
+func (h *TenantWebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("userID").(string)
+
+	var req TenantWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	webhook := req.webhook()
+	webhook.TenantID, webhook.CreatedBy = tenantID, userID
+
+	secret, err := h.webhookService.Create(c.UserContext(), webhook)
+	if err != nil {
+		return tenantWebhookError(c, err)
+	}
+	return response.Created(c, CreatedWebhookResponse{Webhook: webhook, Secret: secret})
+}
+
+// ListWebhooks GET /v1/tenant-admin/webhooks
+func (h *TenantWebhookHandler) ListWebhooks(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	webhooks, err := h.webhookService.List(c.UserContext(), tenantID)
+	if err != nil {
+		return tenantWebhookError(c, err)
+	}
+	return response.OK(c, WebhookListResponse{Data: webhooks, Events: domain.WebhookEvents})
+}
+
+// GetWebhook GET /v1/tenant-admin/webhooks/:id
+func (h *TenantWebhookHandler) GetWebhook(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	webhook, err := h.webhookService.Get(c.UserContext(), tenantID, c.Params("id"))
+	if err != nil {
+		return tenantWebhookError(c, err)
+	}
+	return response.OK(c, webhook)
+}
+
+// UpdateWebhook PUT /v1/tenant-admin/webhooks/:id
+func (h *TenantWebhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	var req TenantWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	webhook, err := h.webhookService.Update(c.UserContext(), tenantID, c.Params("id"), req.webhook())
+	if err != nil {
+		return tenantWebhookError(c, err)
+	}
+	return response.OK(c, webhook)
+}
+
+// RotateSecret POST /v1/tenant-admin/webhooks/:id/rotate-secret
+func (h *TenantWebhookHandler) RotateSecret(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	secret, err := h.webhookService.RotateSecret(c.UserContext(), tenantID, c.Params("id"))
+	if err != nil {
+		return tenantWebhookError(c, err)
+	}
+	return response.OK(c, fiber.Map{"secret": secret})
+}
+
+// DeleteWebhook DELETE /v1/tenant-admin/webhooks/:id
+func (h *TenantWebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+
+	if err := h.webhookService.Delete(c.UserContext(), tenantID, c.Params("id")); err != nil {
+		return tenantWebhookError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListDeliveries GET /v1/tenant-admin/webhooks/:id/deliveries?limit=&cursor=
+// The webhook's delivery log for the last 30 days, newest first
+func (h *TenantWebhookHandler) ListDeliveries(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	q, _ := pageQuery(c)
+
+	page, err := h.webhookService.Deliveries(c.UserContext(), tenantID, c.Params("id"), q)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		return pageError(c, err)
+	}
+	if err != nil {
+		return tenantWebhookError(c, err)
+	}
+	return response.OK(c, page)
+}
+
+func tenantWebhookError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return response.Error(c, fiber.StatusNotFound, "Webhook not found")
+	case errors.Is(err, domain.ErrInvalidWebhook):
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
+	return response.Fail(c, err)
+}
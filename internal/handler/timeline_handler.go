@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// TimelineHandler serves members' activity feeds
+type TimelineHandler struct {
+	timeline *service.TimelineService
+	userRepo domain.UserRepository
+}
+
+func NewTimelineHandler(timeline *service.TimelineService, userRepo domain.UserRepository) *TimelineHandler {
+	return &TimelineHandler{timeline: timeline, userRepo: userRepo}
+}
+
+// GetMyTimeline GET /v1/me/timeline?limit=&cursor=
+// Sessions completed, PBs, scans, goals reached and contract changes, most recent first
+func (h *TimelineHandler) GetMyTimeline(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	return h.timelinePage(c, "", userID)
+}
+
+// GetClientTimeline GET /v1/pro/clients/:id/timeline?limit=&cursor=
+// A client's activity at the coach's gym
+func (h *TimelineHandler) GetClientTimeline(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	memberID := c.Params("id")
+
+	member, err := h.userRepo.GetByID(c.UserContext(), memberID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return response.Error(c, fiber.StatusNotFound, "Member not found")
+		}
+		return response.Fail(c, err)
+	}
+	if _, err := member.ScopedTo(tenantID); err != nil {
+		return response.Fail(c, domain.ErrTenantScopeViolation)
+	}
+	return h.timelinePage(c, tenantID, memberID)
+}
+
+func (h *TimelineHandler) timelinePage(c *fiber.Ctx, tenantID, memberID string) error {
+	q, _ := pageQuery(c)
+	page, err := h.timeline.MemberTimeline(c.UserContext(), tenantID, memberID, q)
+	if err != nil {
+		return pageError(c, err)
+	}
+	return response.OK(c, page)
+}
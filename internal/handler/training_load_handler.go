@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// TrainingLoadHandler records session effort and serves training load and weekly reviews
+type TrainingLoadHandler struct {
+	loadService *service.TrainingLoadService
+	userRepo    domain.UserRepository
+}
+
+func NewTrainingLoadHandler(loadService *service.TrainingLoadService, userRepo domain.UserRepository) *TrainingLoadHandler {
+	return &TrainingLoadHandler{loadService: loadService, userRepo: userRepo}
+}
+
+// SessionEffortRequest is the body of the effort endpoints. Send only what was measured.
+type SessionEffortRequest struct {
+	AvgHR  *int   `json:"avg_hr"`
+	MaxHR  *int   `json:"max_hr"`
+	RPE    *int   `json:"rpe"`
+	Source string `json:"source"` // Members only: manual (default) or wearable
+	Device string `json:"device"`
+}
+
+// RecordEffort PUT /v1/pro/schedules/:id/effort
+// Coaches enter effort by hand
+func (h *TrainingLoadHandler) RecordEffort(c *fiber.Ctx) error {
+	return h.recordEffort(c, domain.EffortSourceManual)
+}
+
+// RecordMyEffort PUT /v1/me/schedules/:id/effort
+// Used by the app both for the member's own RPE and for heart rate synced from a wearable
+func (h *TrainingLoadHandler) RecordMyEffort(c *fiber.Ctx) error {
+	return h.recordEffort(c, "")
+}
+
+func (h *TrainingLoadHandler) recordEffort(c *fiber.Ctx, source string) error {
+	userID, _ := c.Locals("userID").(string)
+
+	var req SessionEffortRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if source == "" {
+		source = req.Source
+		if source == "" {
+			source = domain.EffortSourceManual
+		}
+	}
+
+	schedule, err := h.loadService.RecordEffort(c.UserContext(), userID, c.Params("id"), domain.SessionEffort{
+		AvgHR:  req.AvgHR,
+		MaxHR:  req.MaxHR,
+		RPE:    req.RPE,
+		Source: source,
+		Device: req.Device,
+	})
+	if err != nil {
+		return trainingLoadError(c, err)
+	}
+	return response.OK(c, schedule)
+}
+
+// GetMyTrainingLoad GET /v1/me/training-load?weeks=8
+func (h *TrainingLoadHandler) GetMyTrainingLoad(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	return h.memberLoad(c, userID)
+}
+
+// GetMemberTrainingLoad GET /v1/pro/members/:id/training-load?weeks=8
+func (h *TrainingLoadHandler) GetMemberTrainingLoad(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	memberID := c.Params("id")
+
+	member, err := h.userRepo.GetByID(c.UserContext(), memberID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return response.Error(c, fiber.StatusNotFound, "Member not found")
+		}
+		return response.Fail(c, err)
+	}
+	if _, err := member.ScopedTo(tenantID); err != nil {
+		return response.Fail(c, domain.ErrTenantScopeViolation)
+	}
+	return h.memberLoad(c, memberID)
+}
+
+func (h *TrainingLoadHandler) memberLoad(c *fiber.Ctx, memberID string) error {
+	load, err := h.loadService.MemberLoad(c.UserContext(), memberID, c.QueryInt("weeks", 8))
+	if err != nil {
+		return trainingLoadError(c, err)
+	}
+	return response.OK(c, load)
+}
+
+// GetWeeklyReview GET /v1/pro/weekly-review?week=YYYY-MM-DD
+// Any date selects its Monday-to-Sunday week; the default is last week
+func (h *TrainingLoadHandler) GetWeeklyReview(c *fiber.Ctx) error {
+	coachID, _ := c.Locals("userID").(string)
+
+	week := time.Now().UTC().AddDate(0, 0, -7)
+	if raw := c.Query("week"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "week must be a date like 2025-06-16")
+		}
+		week = parsed
+	}
+
+	review, err := h.loadService.WeeklyReview(c.UserContext(), coachID, week)
+	if err != nil {
+		return trainingLoadError(c, err)
+	}
+	return response.OK(c, review)
+}
+
+func trainingLoadError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrScheduleNotFound), errors.Is(err, domain.ErrInvalidID):
+		return response.Error(c, fiber.StatusNotFound, "Schedule not found")
+	case errors.Is(err, domain.ErrForbidden):
+		return response.Error(c, fiber.StatusForbidden, "You can only record effort for your own sessions")
+	case errors.Is(err, domain.ErrInvalidSessionEffort):
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	case errors.Is(err, domain.ErrSessionNotCompleted):
+		return response.FailAs(c, fiber.StatusConflict, err)
+	}
+	return response.Fail(c, err)
+}
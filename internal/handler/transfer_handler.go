@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+type TransferHandler struct {
+	transferService *service.MemberTransferService
+}
+
+func NewTransferHandler(transferService *service.MemberTransferService) *TransferHandler {
+	return &TransferHandler{transferService: transferService}
+}
+
+// TransferBranch POST /v1/tenant-admin/users/:id/transfer-branch
+// Body: to_branch_id, optional from_branch_id (defaults to all of the member's branches), reason
+func (h *TransferHandler) TransferBranch(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	actorID, _ := c.Locals("userID").(string)
+
+	var req struct {
+		FromBranchID string `json:"from_branch_id"`
+		ToBranchID   string `json:"to_branch_id"`
+		Reason       string `json:"reason"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	transfer, err := h.transferService.TransferBranch(c.UserContext(), actorID, tenantID, c.Params("id"), req.FromBranchID, req.ToBranchID, req.Reason)
+	if err != nil {
+		return transferError(c, err)
+	}
+	return response.Created(c, transfer)
+}
+
+// TransferTenant POST /v1/platform/users/:id/transfer-tenant
+// Body: to_tenant_id, to_branch_id, policy ("move" or "copy"), reason
+func (h *TransferHandler) TransferTenant(c *fiber.Ctx) error {
+	actorID, _ := c.Locals("userID").(string)
+
+	var req struct {
+		ToTenantID string `json:"to_tenant_id"`
+		ToBranchID string `json:"to_branch_id"`
+		Policy     string `json:"policy"`
+		Reason     string `json:"reason"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	transfer, err := h.transferService.TransferTenant(c.UserContext(), actorID, c.Params("id"), req.ToTenantID, req.ToBranchID, req.Policy, req.Reason)
+	if err != nil {
+		return transferError(c, err)
+	}
+	return response.Created(c, transfer)
+}
+
+// ListTransfers GET /v1/platform/users/:id/transfers
+func (h *TransferHandler) ListTransfers(c *fiber.Ctx) error {
+	transfers, err := h.transferService.ListTransfers(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	return response.OK(c, transfers)
+}
+
+func transferError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrNotFound, domain.ErrInvalidID:
+		return response.Error(c, fiber.StatusNotFound, "Member not found")
+	case domain.ErrInvalidTransferPolicy, domain.ErrInvalidTransferTarget:
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	case domain.ErrVersionConflict:
+		return response.FailAs(c, fiber.StatusConflict, err)
+	}
+	return response.Fail(c, err)
+}
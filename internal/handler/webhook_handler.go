@@ -4,11 +4,16 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"log"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/clock"
 	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
 )
 
 // WebhookHandler handles external payment webhooks
@@ -17,8 +22,12 @@ type WebhookHandler struct {
 	packageRepo      domain.PackageRepository
 	subscriptionRepo domain.SubscriptionRepository
 	userRepo         domain.UserRepository
+	funnel           *service.SalesFunnelService
+	installments     *service.InstallmentService
+	ptService        *service.PTService
 	apiKey           string
 	vaNumber         string
+	clock            domain.Clock
 }
 
 // NewWebhookHandler creates a new WebhookHandler
@@ -27,15 +36,23 @@ func NewWebhookHandler(
 	packageRepo domain.PackageRepository,
 	subscriptionRepo domain.SubscriptionRepository,
 	userRepo domain.UserRepository,
+	funnel *service.SalesFunnelService,
+	installments *service.InstallmentService,
+	ptService *service.PTService,
 	apiKey, vaNumber string,
+	clk domain.Clock,
 ) *WebhookHandler {
 	return &WebhookHandler{
 		invoiceRepo:      invoiceRepo,
 		packageRepo:      packageRepo,
 		subscriptionRepo: subscriptionRepo,
 		userRepo:         userRepo,
+		funnel:           funnel,
+		installments:     installments,
+		ptService:        ptService,
 		apiKey:           apiKey,
 		vaNumber:         vaNumber,
+		clock:            clock.OrReal(clk),
 	}
 }
 
@@ -50,18 +67,20 @@ type IPAYMUWebhookRequest struct {
 	Signature   string `json:"signature"`    // HMAC signature for verification
 }
 
-// IPAYMUWebhook handles POST /api/payments/webhook/ipaymu
-// This is a public endpoint - no authentication required
+// IPAYMUWebhook handles POST /api/payments/webhook/ipaymu and POST /v1/payments/webhook
+// This is a public endpoint - no authentication required, the callback is signed instead.
+// A settled VA is applied to the PT contract its invoice is for, which credits the sessions
+// of a renewal waiting for payment (see InstallmentService.RecordPayment), or else marks the
+// invoice paid and extends the member's subscription, crediting the sessions of the PT
+// package the paid package maps to (see Package.PTPackageID). Such an invoice must still be
+// pending and be paid in full; callbacks for another VA than ours are refused.
 func (h *WebhookHandler) IPAYMUWebhook(c *fiber.Ctx) error {
 	ctx := c.UserContext()
 
 	var req IPAYMUWebhookRequest
 	if err := c.BodyParser(&req); err != nil {
 		log.Printf("[Webhook] Failed to parse body: %v", err)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "invalid request body",
-		})
+		return response.Error(c, fiber.StatusBadRequest, "invalid request body")
 	}
 
 	log.Printf("[Webhook] Received callback: sid=%s, status=%s, va=%s, amount=%d",
@@ -70,66 +89,98 @@ func (h *WebhookHandler) IPAYMUWebhook(c *fiber.Ctx) error {
 	// Verify signature
 	if !h.verifySignature(req.VA, req.SID, req.Status, req.Signature) {
 		log.Printf("[Webhook] Signature verification failed for sid=%s", req.SID)
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"error":   "invalid signature",
-		})
+		return response.Error(c, fiber.StatusUnauthorized, "invalid signature")
+	}
+	if h.vaNumber != "" && req.VA != h.vaNumber {
+		log.Printf("[Webhook] Callback for VA %s instead of ours, sid=%s", req.VA, req.SID)
+		return response.Error(c, fiber.StatusBadRequest, "unknown virtual account")
 	}
 
 	// Find invoice by payment session ID
 	invoice, err := h.invoiceRepo.GetByPaymentSessionID(ctx, req.SID)
 	if err != nil {
 		log.Printf("[Webhook] Invoice not found for sid=%s: %v", req.SID, err)
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"error":   "invoice not found",
-		})
+		return response.Error(c, fiber.StatusNotFound, "invoice not found")
 	}
 
 	// Only process successful payments
 	if req.Status != "berhasil" {
 		log.Printf("[Webhook] Payment not successful: status=%s, sid=%s", req.Status, req.SID)
-		return c.JSON(fiber.Map{
-			"success": true,
-			"message": "status acknowledged",
-		})
+		return response.OK(c, fiber.Map{"message": "status acknowledged"})
+	}
+
+	// Installments may arrive in parts; each payment is applied to the contract's plan.
+	// Contract invoices paid in one go, like automatic renewals, are recorded the same way.
+	if invoice.IsInstallmentPlan() || invoice.ContractID != "" {
+		if err := h.installments.RecordPayment(ctx, invoice, req.SID, req.TrxID, req.Amount); err != nil {
+			log.Printf("[Webhook] Failed to record installment payment: %v", err)
+			return response.Error(c, fiber.StatusInternalServerError, "failed to record payment")
+		}
+		return response.OK(c, fiber.Map{"message": "payment recorded"})
 	}
 
 	// Prevent duplicate processing
 	if invoice.Status == domain.InvoiceStatusPaid {
 		log.Printf("[Webhook] Invoice already paid: id=%s", invoice.ID)
-		return c.JSON(fiber.Map{
-			"success": true,
-			"message": "already processed",
-		})
+		return response.OK(c, fiber.Map{"message": "already processed"})
 	}
-
-	// Update invoice status to paid
-	if err := h.invoiceRepo.UpdateStatus(ctx, invoice.ID, domain.InvoiceStatusPaid); err != nil {
-		log.Printf("[Webhook] Failed to update invoice status: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "failed to update invoice",
-		})
+	if invoice.Status != domain.InvoiceStatusPending {
+		log.Printf("[Webhook] Payment for %s invoice %s, sid=%s; refund or reopen it by hand", invoice.Status, invoice.ID, req.SID)
+		return response.Error(c, fiber.StatusConflict, "invoice is not pending")
+	}
+	if req.Amount != invoice.Amount.Minor {
+		log.Printf("[Webhook] Payment of %d for invoice %s of %d, sid=%s", req.Amount, invoice.ID, invoice.Amount.Minor, req.SID)
+		return response.Error(c, fiber.StatusBadRequest, "amount does not match invoice")
 	}
 
 	// Get package to determine subscription duration
 	pkg, err := h.packageRepo.GetByID(ctx, invoice.PackageID)
 	if err != nil {
 		log.Printf("[Webhook] Failed to get package: %v", err)
-		// Continue - invoice is already marked as paid
+		// Continue - the payment is still recorded
+	}
+
+	// Credit the sessions before marking the invoice paid, so a failure is retried by the
+	// gateway; the credit itself is only recorded once per invoice
+	if pkg != nil && pkg.PTPackageID != "" {
+		contract, err := h.ptService.CreditPaidPackage(ctx, invoice.UserID, pkg.PTPackageID, invoice.ID)
+		switch {
+		case errors.Is(err, domain.ErrContractNotFound):
+			log.Printf("[Webhook] No contract on PT package %s for user %s; invoice=%s sessions must be credited by hand",
+				pkg.PTPackageID, invoice.UserID, invoice.ID)
+		case err != nil:
+			log.Printf("[Webhook] Failed to credit PT sessions: %v", err)
+			return response.Error(c, fiber.StatusInternalServerError, "failed to credit sessions")
+		default:
+			log.Printf("[Webhook] Credited PT package %s to contract %s", pkg.PTPackageID, contract.ID)
+		}
+	}
+
+	now := h.clock.Now().UTC()
+	paid, err := h.invoiceRepo.MarkPaid(ctx, invoice.ID, now)
+	if err != nil {
+		log.Printf("[Webhook] Failed to update invoice status: %v", err)
+		return response.Error(c, fiber.StatusInternalServerError, "failed to update invoice")
+	}
+	if !paid {
+		log.Printf("[Webhook] Invoice %s stopped being pending while its payment was processed, sid=%s", invoice.ID, req.SID)
+		return response.Error(c, fiber.StatusConflict, "invoice is not pending")
 	}
 
 	// Get user to calculate new subscription end date
 	user, err := h.userRepo.GetByID(ctx, invoice.UserID)
 	if err != nil {
 		log.Printf("[Webhook] Failed to get user: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "failed to get user",
-		})
+		return response.Error(c, fiber.StatusInternalServerError, "failed to get user")
 	}
 
+	// Invoices from before tenants were stored on them count towards the member's primary tenant
+	tenantID := invoice.TenantID
+	if tenantID == "" {
+		tenantID = user.TenantID
+	}
+	h.funnel.Track(ctx, tenantID, invoice.UserID, invoice.PackageID, domain.SalesStagePaymentCompleted, invoice.ID)
+
 	// Calculate new subscription end date (stacking logic)
 	durationMonths := 1 // Default to 1 month if package lookup failed
 	if pkg != nil {
@@ -138,7 +189,6 @@ func (h *WebhookHandler) IPAYMUWebhook(c *fiber.Ctx) error {
 	newEndDate := domain.CalculateNewEndDate(user.SubscriptionEndDate, durationMonths)
 
 	// Create subscription record
-	now := time.Now().UTC()
 	subscription := &domain.Subscription{
 		UserID:    invoice.UserID,
 		InvoiceID: invoice.ID,
@@ -161,11 +211,15 @@ func (h *WebhookHandler) IPAYMUWebhook(c *fiber.Ctx) error {
 
 	log.Printf("[Webhook] Payment processed successfully: invoice=%s, user=%s, newEndDate=%s",
 		invoice.ID, invoice.UserID, newEndDate.Format(time.RFC3339))
-
-	return c.JSON(fiber.Map{
-		"success": true,
-		"message": "payment processed",
+	invoice.Status = domain.InvoiceStatusPaid
+	h.installments.Receipt(ctx, invoice, domain.InvoicePayment{
+		Reference: fmt.Sprintf("%s:%d", req.SID, req.TrxID),
+		Amount:    req.Amount,
+		PaidAt:    now,
+		Channel:   domain.PaymentChannelProvider,
 	})
+
+	return response.OK(c, fiber.Map{"message": "payment processed"})
 }
 
 // verifySignature validates the HMAC-SHA256 signature from iPaymu
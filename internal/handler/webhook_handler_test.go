@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/mansoorceksport/metamorph/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testWebhookKey = "ipaymu-key"
+	testWebhookVA  = "8808"
+)
+
+var testWebhookNow = time.Date(2025, 6, 16, 10, 0, 0, 0, time.UTC)
+
+type webhookMocks struct {
+	invoices      *mocks.InvoiceRepository
+	packages      *mocks.PackageRepository
+	subscriptions *mocks.SubscriptionRepository
+	users         *mocks.UserRepository
+	ptPackages    *mocks.PTPackageRepository
+	contracts     *mocks.PTContractRepository
+	credits       *mocks.CreditTransactionRepository
+}
+
+func newTestWebhookApp(t *testing.T) (*fiber.App, *webhookMocks) {
+	m := &webhookMocks{
+		invoices:      mocks.NewInvoiceRepository(t),
+		packages:      mocks.NewPackageRepository(t),
+		subscriptions: mocks.NewSubscriptionRepository(t),
+		users:         mocks.NewUserRepository(t),
+		ptPackages:    mocks.NewPTPackageRepository(t),
+		contracts:     mocks.NewPTContractRepository(t),
+		credits:       mocks.NewCreditTransactionRepository(t),
+	}
+	ptService := service.NewPTService(m.ptPackages, m.contracts, nil, nil, nil, m.credits, nil, nil, nil, nil, nil, nil, nil)
+	installments := service.NewInstallmentService(m.invoices, m.contracts, nil, nil, 0, nil)
+	h := NewWebhookHandler(m.invoices, m.packages, m.subscriptions, m.users, nil, installments, ptService, testWebhookKey, testWebhookVA, clock.NewFake(testWebhookNow))
+
+	app := fiber.New()
+	app.Post("/v1/payments/webhook", h.IPAYMUWebhook)
+	return app, m
+}
+
+// settle posts a signed "berhasil" callback of amount to va for the payment session sid
+func settle(t *testing.T, app *fiber.App, va, sid string, amount int64) int {
+	t.Helper()
+	mac := hmac.New(sha256.New, []byte(testWebhookKey))
+	mac.Write([]byte(va + "." + sid + ".berhasil"))
+	body, err := json.Marshal(IPAYMUWebhookRequest{SID: sid, VA: va, Status: "berhasil", TrxID: 42, Amount: amount,
+		Signature: hex.EncodeToString(mac.Sum(nil))})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(fiber.MethodPost, "/v1/payments/webhook", strings.NewReader(string(body)))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	return resp.StatusCode
+}
+
+func TestWebhookHandler_IPAYMUWebhook_CreditsPTPackage(t *testing.T) {
+	pending := func() *domain.Invoice {
+		return &domain.Invoice{ID: "inv-1", UserID: "member-1", TenantID: "gym", PackageID: "pkg-pt-10",
+			Amount: domain.NewMoney(1500000, "IDR"), Status: domain.InvoiceStatusPending}
+	}
+
+	t.Run("credits the sessions, then marks the invoice paid", func(t *testing.T) {
+		app, m := newTestWebhookApp(t)
+		m.invoices.On("GetByPaymentSessionID", mock.Anything, "sid-1").Return(pending(), nil)
+		m.packages.On("GetByID", mock.Anything, "pkg-pt-10").Return(&domain.Package{ID: "pkg-pt-10", DurationMonths: 1, PTPackageID: "pt-10"}, nil)
+		m.ptPackages.On("GetByID", mock.Anything, "pt-10").Return(&domain.PTPackage{ID: "pt-10", TotalSessions: 10}, nil)
+		m.contracts.On("GetByMemberAndPackage", mock.Anything, "member-1", "pt-10").Return([]*domain.PTContract{
+			{ID: "k1", MemberID: "member-1", PackageID: "pt-10", Status: domain.PackageStatusDepleted},
+		}, nil)
+		m.credits.On("GetLatest", mock.Anything, "k1").Return(&domain.CreditTransaction{Sequence: 3}, nil)
+		m.credits.On("Append", mock.Anything, mock.MatchedBy(func(txn *domain.CreditTransaction) bool {
+			return txn.Type == domain.CreditTypePurchased && txn.Amount == 10 && txn.IdempotencyKey == "purchased:invoice:inv-1"
+		})).Run(func(args mock.Arguments) {
+			txn := args.Get(1).(*domain.CreditTransaction)
+			txn.BalanceAfter, txn.Sequence = 10, 4
+		}).Return(nil)
+		m.contracts.On("SyncBalance", mock.Anything, "k1", 10, int64(4)).Return(nil)
+		m.invoices.On("MarkPaid", mock.Anything, "inv-1", mock.AnythingOfType("time.Time")).Return(true, nil)
+		m.users.On("GetByID", mock.Anything, "member-1").Return(&domain.User{ID: "member-1", TenantID: "gym"}, nil)
+		m.subscriptions.On("Create", mock.Anything, mock.MatchedBy(func(sub *domain.Subscription) bool {
+			return sub.StartDate.Equal(testWebhookNow)
+		})).Return(nil)
+		m.users.On("Update", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil)
+
+		assert.Equal(t, fiber.StatusOK, settle(t, app, testWebhookVA, "sid-1", 1500000))
+	})
+
+	t.Run("a failed credit leaves the invoice for the gateway to retry", func(t *testing.T) {
+		app, m := newTestWebhookApp(t)
+		m.invoices.On("GetByPaymentSessionID", mock.Anything, "sid-1").Return(pending(), nil)
+		m.packages.On("GetByID", mock.Anything, "pkg-pt-10").Return(&domain.Package{ID: "pkg-pt-10", PTPackageID: "pt-10"}, nil)
+		m.ptPackages.On("GetByID", mock.Anything, "pt-10").Return(&domain.PTPackage{ID: "pt-10", TotalSessions: 10}, nil)
+		m.contracts.On("GetByMemberAndPackage", mock.Anything, "member-1", "pt-10").Return(nil, assert.AnError)
+
+		assert.Equal(t, fiber.StatusInternalServerError, settle(t, app, testWebhookVA, "sid-1", 1500000))
+		m.invoices.AssertNotCalled(t, "MarkPaid", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestWebhookHandler_IPAYMUWebhook_RefusesMismatches(t *testing.T) {
+	invoice := func(status string) *domain.Invoice {
+		return &domain.Invoice{ID: "inv-1", UserID: "member-1", PackageID: "pkg-1", Amount: domain.NewMoney(1500000, "IDR"), Status: status}
+	}
+
+	t.Run("another VA", func(t *testing.T) {
+		app, m := newTestWebhookApp(t)
+
+		assert.Equal(t, fiber.StatusBadRequest, settle(t, app, "9999", "sid-1", 1500000))
+		m.invoices.AssertNotCalled(t, "GetByPaymentSessionID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("less than the invoice", func(t *testing.T) {
+		app, m := newTestWebhookApp(t)
+		m.invoices.On("GetByPaymentSessionID", mock.Anything, "sid-1").Return(invoice(domain.InvoiceStatusPending), nil)
+
+		assert.Equal(t, fiber.StatusBadRequest, settle(t, app, testWebhookVA, "sid-1", 1000))
+		m.invoices.AssertNotCalled(t, "MarkPaid", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("an expired invoice", func(t *testing.T) {
+		app, m := newTestWebhookApp(t)
+		m.invoices.On("GetByPaymentSessionID", mock.Anything, "sid-1").Return(invoice(domain.InvoiceStatusExpired), nil)
+
+		assert.Equal(t, fiber.StatusConflict, settle(t, app, testWebhookVA, "sid-1", 1500000))
+		m.invoices.AssertNotCalled(t, "MarkPaid", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("an invoice that expires meanwhile", func(t *testing.T) {
+		app, m := newTestWebhookApp(t)
+		m.invoices.On("GetByPaymentSessionID", mock.Anything, "sid-1").Return(invoice(domain.InvoiceStatusPending), nil)
+		m.packages.On("GetByID", mock.Anything, "pkg-1").Return(&domain.Package{ID: "pkg-1", DurationMonths: 1}, nil)
+		m.invoices.On("MarkPaid", mock.Anything, "inv-1", mock.AnythingOfType("time.Time")).Return(false, nil)
+
+		assert.Equal(t, fiber.StatusConflict, settle(t, app, testWebhookVA, "sid-1", 1500000))
+		m.subscriptions.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+	"github.com/mansoorceksport/metamorph/internal/service"
+)
+
+// WidgetTokenHeader carries a widget token; embeds that can't set headers use ?token=
+const WidgetTokenHeader = "X-Widget-Token"
+
+// WidgetHandler serves the public widget API and lets tenant admins manage widget tokens
+type WidgetHandler struct {
+	widgetService *service.WidgetService
+}
+
+func NewWidgetHandler(widgetService *service.WidgetService) *WidgetHandler {
+	return &WidgetHandler{widgetService: widgetService}
+}
+
+// CreateWidgetTokenRequest is the body of POST /v1/tenant-admin/widget-tokens
+type CreateWidgetTokenRequest struct {
+	Name           string   `json:"name"`
+	Scopes         []string `json:"scopes"`
+	AllowedOrigins []string `json:"allowed_origins"`
+	RateLimit      int      `json:"rate_limit"`
+}
+
+// CreateToken POST /v1/tenant-admin/widget-tokens
+// The response is the only time the token itself is shown
+func (h *WidgetHandler) CreateToken(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+	userID, _ := c.Locals("userID").(string)
+
+	var req CreateWidgetTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	token := &domain.WidgetToken{
+		TenantID:       tenantID,
+		Name:           req.Name,
+		Scopes:         req.Scopes,
+		AllowedOrigins: req.AllowedOrigins,
+		RateLimit:      req.RateLimit,
+		CreatedBy:      userID,
+	}
+	secret, err := h.widgetService.CreateToken(c.UserContext(), token)
+	if err != nil {
+		return widgetError(c, err)
+	}
+	return response.Created(c, fiber.Map{
+		"token":        secret,
+		"widget_token": token,
+	})
+}
+
+// ListTokens GET /v1/tenant-admin/widget-tokens
+func (h *WidgetHandler) ListTokens(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+	tokens, err := h.widgetService.ListTokens(c.UserContext(), tenantID)
+	if err != nil {
+		return widgetError(c, err)
+	}
+	return response.OK(c, tokens)
+}
+
+// RevokeToken DELETE /v1/tenant-admin/widget-tokens/:id
+func (h *WidgetHandler) RevokeToken(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals("tenant_id").(string)
+	if !ok || tenantID == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "Missing tenant context")
+	}
+	if err := h.widgetService.RevokeToken(c.UserContext(), tenantID, c.Params("id")); err != nil {
+		return widgetError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Authorize returns middleware admitting requests whose widget token allows scope, and
+// scoping them to the token's tenant
+func (h *WidgetHandler) Authorize(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		secret := c.Get(WidgetTokenHeader)
+		if secret == "" {
+			secret = c.Query("token")
+		}
+		token, retryAfter, err := h.widgetService.Authenticate(c.UserContext(), secret, scope, c.Get(fiber.HeaderOrigin), c.IP())
+		if err != nil {
+			if errors.Is(err, domain.ErrRateLimited) {
+				c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			}
+			return widgetError(c, err)
+		}
+		c.Locals("tenant_id", token.TenantID)
+		return c.Next()
+	}
+}
+
+// GetCoaches GET /v1/public/widgets/coaches
+func (h *WidgetHandler) GetCoaches(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	coaches, err := h.widgetService.Coaches(c.UserContext(), tenantID)
+	if err != nil {
+		return widgetError(c, err)
+	}
+	return response.OK(c, coaches)
+}
+
+// GetTimetable GET /v1/public/widgets/timetable
+func (h *WidgetHandler) GetTimetable(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	timetable, err := h.widgetService.Timetable(c.UserContext(), tenantID)
+	if err != nil {
+		return widgetError(c, err)
+	}
+	return response.OK(c, timetable)
+}
+
+// GetPackages GET /v1/public/widgets/packages
+func (h *WidgetHandler) GetPackages(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	packages, err := h.widgetService.Packages(c.UserContext(), tenantID)
+	if err != nil {
+		return widgetError(c, err)
+	}
+	return response.OK(c, packages)
+}
+
+func widgetError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return response.Error(c, fiber.StatusNotFound, "Widget token not found")
+	case errors.Is(err, domain.ErrInvalidWidgetToken):
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	case errors.Is(err, domain.ErrWidgetUnauthorized):
+		return response.FailAs(c, fiber.StatusUnauthorized, err)
+	case errors.Is(err, domain.ErrWidgetScopeDenied):
+		return response.FailAs(c, fiber.StatusForbidden, err)
+	case errors.Is(err, domain.ErrRateLimited):
+		return response.FailAs(c, fiber.StatusTooManyRequests, err)
+	}
+	return response.Fail(c, err)
+}
@@ -1,15 +1,24 @@
 package handler
 
 import (
+	"errors"
+	"log"
+	"mime"
+	"slices"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
 	"github.com/mansoorceksport/metamorph/internal/service"
 )
 
 type WorkoutHandler struct {
-	workoutService *service.WorkoutService
-	exerciseRepo   domain.ExerciseRepository // Exposed for simple CRUD
-	templateRepo   domain.TemplateRepository // Exposed for simple CRUD
+	workoutService   *service.WorkoutService
+	equipmentService *service.EquipmentService
+	exerciseVideos   *service.ExerciseVideoService
+	catalog          *service.SeedCatalogService
+	exerciseRepo     domain.ExerciseRepository // Exposed for simple CRUD
+	templateRepo     domain.TemplateRepository // Exposed for simple CRUD
 	// In strict layered arch, these CRUDs should go through service too.
 	// But for scaffolding valid simple persistence, direct repo is acceptable for now.
 }
@@ -18,30 +27,53 @@ func NewWorkoutHandler(
 	workoutService *service.WorkoutService,
 	exerciseRepo domain.ExerciseRepository,
 	templateRepo domain.TemplateRepository,
+	equipmentService *service.EquipmentService,
+	exerciseVideos *service.ExerciseVideoService,
+	catalog *service.SeedCatalogService,
 ) *WorkoutHandler {
 	return &WorkoutHandler{
-		workoutService: workoutService,
-		exerciseRepo:   exerciseRepo,
-		templateRepo:   templateRepo,
+		workoutService:   workoutService,
+		equipmentService: equipmentService,
+		exerciseVideos:   exerciseVideos,
+		catalog:          catalog,
+		exerciseRepo:     exerciseRepo,
+		templateRepo:     templateRepo,
 	}
 }
 
 // --- Exercises CRUD ---
 
+// ListExercises GET /v1/exercises?name=&scope=all|global|tenant
+// Public. Signed-in tenant users also see their tenant's private exercises, which replace
+// global ones of the same name unless scope=global.
 func (h *WorkoutHandler) ListExercises(c *fiber.Ctx) error {
 	nameFilter := c.Query("name")
+	scope := c.Query("scope")
+	if err := domain.ValidateExerciseScope(scope); err != nil {
+		return response.FailAs(c, fiber.StatusBadRequest, err)
+	}
 	filter := make(map[string]interface{})
 	if nameFilter != "" {
 		filter["name"] = nameFilter
 	}
+	filter["scope"] = scope
+	if tenantID, _ := c.Locals("tenant_id").(string); tenantID != "" {
+		filter["tenant_id"] = tenantID
+	}
 	// public
 	exs, err := h.exerciseRepo.List(c.UserContext(), filter)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
-	return c.JSON(exs)
+	if scope == "" || scope == domain.ExerciseScopeAll {
+		exs = domain.MergeExerciseLibraries(exs)
+	}
+	h.exerciseVideos.Present(c.UserContext(), exs...)
+	return response.OK(c, exs)
 }
 
+// CreateExercise POST /v1/exercises
+// "scope": "tenant" adds the exercise to the caller's tenant library instead of the global one
 func (h *WorkoutHandler) CreateExercise(c *fiber.Ctx) error {
 	// Admin Only (Middleware check outside)
 	var req struct {
@@ -51,9 +83,10 @@ func (h *WorkoutHandler) CreateExercise(c *fiber.Ctx) error {
 		Equipment    string `json:"equipment"`
 		VideoURL     string `json:"video_url"`
 		ReferenceURL string `json:"reference_url"`
+		Scope        string `json:"scope"` // "global" (default) or "tenant"
 	}
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid body")
 	}
 
 	ex := &domain.Exercise{
@@ -64,15 +97,30 @@ func (h *WorkoutHandler) CreateExercise(c *fiber.Ctx) error {
 		VideoURL:     req.VideoURL,
 		ReferenceURL: req.ReferenceURL,
 	}
+	switch req.Scope {
+	case "", domain.ExerciseScopeGlobal:
+	case domain.ExerciseScopeTenant:
+		tenantID, _ := c.Locals("tenant_id").(string)
+		if tenantID == "" {
+			return response.Error(c, fiber.StatusBadRequest, "Tenant exercises need a tenant; sign in to one")
+		}
+		ex.TenantID = tenantID
+	default:
+		return response.Error(c, fiber.StatusBadRequest, "scope must be 'global' or 'tenant'")
+	}
 
 	if err := h.exerciseRepo.Create(c.UserContext(), ex); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		if err == domain.ErrDuplicateExercise {
+			return response.FailAs(c, fiber.StatusConflict, err)
+		}
+		return response.Fail(c, err)
 	}
 
 	// Return exercise with client_id for dual-identity handshake
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+	return response.Created(c, fiber.Map{
 		"id":            ex.ID,
 		"client_id":     ex.ClientID,
+		"tenant_id":     ex.TenantID,
 		"name":          ex.Name,
 		"muscle_group":  ex.MuscleGroup,
 		"equipment":     ex.Equipment,
@@ -87,21 +135,99 @@ func (h *WorkoutHandler) UpdateExercise(c *fiber.Ctx) error {
 	id := c.Params("id")
 	var req domain.Exercise
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid body")
+	}
+	existing, ok := h.editableExercise(c, id)
+	if !ok {
+		return nil
 	}
 	req.ID = id
+	req.TenantID = existing.TenantID
 	if err := h.exerciseRepo.Update(c.UserContext(), &req); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
-	return c.JSON(req)
+	return response.OK(c, req)
 }
 
 func (h *WorkoutHandler) DeleteExercise(c *fiber.Ctx) error {
 	id := c.Params("id")
+	if _, ok := h.editableExercise(c, id); !ok {
+		return nil
+	}
 	if err := h.exerciseRepo.Delete(c.UserContext(), id); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
+	}
+	return response.OK(c, fiber.Map{"message": "deleted"})
+}
+
+// editableExercise loads an exercise the caller may change, writing the error response
+// (ok false) if it's missing or another tenant's. Super admins may change any.
+func (h *WorkoutHandler) editableExercise(c *fiber.Ctx, id string) (*domain.Exercise, bool) {
+	ex, err := h.exerciseRepo.GetByID(c.UserContext(), id)
+	if err != nil {
+		if err == domain.ErrExerciseNotFound || err == domain.ErrInvalidID {
+			response.Error(c, fiber.StatusNotFound, "Exercise not found")
+			return nil, false
+		}
+		response.Fail(c, err)
+		return nil, false
+	}
+	tenantID, _ := c.Locals("tenant_id").(string)
+	if !ex.VisibleTo(tenantID) && !slices.Contains(callerRoles(c), domain.RoleSuperAdmin) {
+		response.Error(c, fiber.StatusNotFound, "Exercise not found")
+		return nil, false
+	}
+	return ex, true
+}
+
+// ImportExercises POST /v1/exercises/import
+// Body: CSV with a header row (name, muscle_group, equipment, video_url, reference_url) as
+// text/csv, or JSON as a list of exercises or {"exercises": [...]}. Exercises are matched to
+// the library by name: new ones are created and changed ones updated. ?dry_run=true only
+// reports what would happen.
+func (h *WorkoutHandler) ImportExercises(c *fiber.Ctx) error {
+	format := service.ExerciseFormatJSON
+	if mediaType, _, _ := mime.ParseMediaType(c.Get(fiber.HeaderContentType)); mediaType == "text/csv" {
+		format = service.ExerciseFormatCSV
+	}
+
+	entries, issues, err := service.ParseExerciseImport(format, c.Body())
+	if err != nil {
+		return response.FailAs(c, fiber.StatusBadRequest, err)
 	}
-	return c.JSON(fiber.Map{"message": "deleted"})
+	result, err := h.catalog.ImportExercises(c.UserContext(), entries, c.QueryBool("dry_run"))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidExerciseImport) {
+			return response.FailAs(c, fiber.StatusBadRequest, err)
+		}
+		return response.Fail(c, err)
+	}
+	return response.OK(c, fiber.Map{
+		"dry_run":   c.QueryBool("dry_run"),
+		"exercises": result.Exercises,
+		"issues":    issues,
+	})
+}
+
+// ExportExercises GET /v1/exercises/export?format=csv|json
+// Returns the library in the format ImportExercises reads, JSON by default
+func (h *WorkoutHandler) ExportExercises(c *fiber.Ctx) error {
+	format := c.Query("format", service.ExerciseFormatJSON)
+	if format != service.ExerciseFormatCSV && format != service.ExerciseFormatJSON {
+		return response.Error(c, fiber.StatusBadRequest, "format must be csv or json")
+	}
+
+	data, err := h.catalog.ExportExercises(c.UserContext(), format)
+	if err != nil {
+		return response.Fail(c, err)
+	}
+	contentType := fiber.MIMEApplicationJSON
+	if format == service.ExerciseFormatCSV {
+		contentType = "text/csv"
+	}
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="exercises.`+format+`"`)
+	return c.Send(data)
 }
 
 // --- Templates CRUD ---
@@ -109,41 +235,41 @@ func (h *WorkoutHandler) DeleteExercise(c *fiber.Ctx) error {
 func (h *WorkoutHandler) ListTemplates(c *fiber.Ctx) error {
 	tmps, err := h.templateRepo.List(c.UserContext())
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
-	return c.JSON(tmps)
+	return response.OK(c, tmps)
 }
 
 func (h *WorkoutHandler) CreateTemplate(c *fiber.Ctx) error {
 	var req domain.WorkoutTemplate
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid body")
 	}
 	if err := h.templateRepo.Create(c.UserContext(), &req); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
-	return c.Status(fiber.StatusCreated).JSON(req)
+	return response.Created(c, req)
 }
 
 func (h *WorkoutHandler) UpdateTemplate(c *fiber.Ctx) error {
 	id := c.Params("id")
 	var req domain.WorkoutTemplate
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid body")
 	}
 	req.ID = id
 	if err := h.templateRepo.Update(c.UserContext(), &req); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
-	return c.JSON(req)
+	return response.OK(c, req)
 }
 
 func (h *WorkoutHandler) DeleteTemplate(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if err := h.templateRepo.Delete(c.UserContext(), id); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
-	return c.JSON(fiber.Map{"message": "deleted"})
+	return response.OK(c, fiber.Map{"message": "deleted"})
 }
 
 // --- Session Management ---
@@ -158,14 +284,14 @@ func (h *WorkoutHandler) InitializeSession(c *fiber.Ctx) error {
 		TemplateID string `json:"template_id"`
 	}
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid body")
 	}
 
 	session, err := h.workoutService.InitializeSession(c.UserContext(), req.ScheduleID, req.TemplateID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
-	return c.Status(fiber.StatusCreated).JSON(session)
+	return response.Created(c, session)
 }
 
 // ListScheduleSets GET /v1/pro/schedules/:schedule_id/sets - List all set logs for a schedule
@@ -174,10 +300,27 @@ func (h *WorkoutHandler) ListScheduleSets(c *fiber.Ctx) error {
 
 	sets, err := h.workoutService.GetSetsBySchedule(c.UserContext(), scheduleID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.JSON(sets)
+	return response.OK(c, sets)
+}
+
+// GetLastPerformance GET /v1/pro/schedules/:schedule_id/exercises/:exercise_id/last-performance
+// The member's sets of a library exercise from their previous session with it; sets is
+// empty when they have never done it
+func (h *WorkoutHandler) GetLastPerformance(c *fiber.Ctx) error {
+	tenantID, _ := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("user_id").(string)
+	last, err := h.workoutService.LastPerformance(c.UserContext(), tenantID, userID, domain.ViewerRole(callerRoles(c)),
+		c.Params("schedule_id"), c.Params("exercise_id"))
+	if err != nil {
+		if err == domain.ErrScheduleNotFound || err == domain.ErrInvalidID {
+			return response.Error(c, fiber.StatusNotFound, "Schedule not found")
+		}
+		return response.Fail(c, err)
+	}
+	return response.OK(c, last)
 }
 
 // ListScheduleExercises GET /v1/pro/schedules/:schedule_id/exercises - List all planned exercises for a schedule
@@ -187,13 +330,13 @@ func (h *WorkoutHandler) ListScheduleExercises(c *fiber.Ctx) error {
 	exercises, err := h.workoutService.GetExercisesBySchedule(c.UserContext(), scheduleID)
 	if err != nil {
 		if err == domain.ErrSessionNotFound {
-			return c.JSON([]interface{}{})
+			return response.OK(c, []interface{}{})
 		}
 		// If schedule itself is not found, resolveScheduleID returns error
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.JSON(exercises)
+	return response.OK(c, exercises)
 }
 
 // AddExercise POST /v1/pro/schedules/:schedule_id/exercises
@@ -209,16 +352,16 @@ func (h *WorkoutHandler) AddExercise(c *fiber.Ctx) error {
 		Order       int    `json:"order"`
 	}
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid body")
 	}
 
 	planned, err := h.workoutService.AddExerciseToSession(c.UserContext(), scheduleID, req.ExerciseID, req.ClientID, req.TargetSets, req.TargetReps, req.RestSeconds, req.Notes, req.Order)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	// Return planned exercise with client_id for dual-identity handshake
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+	resp := fiber.Map{
 		"id":           planned.ID,
 		"client_id":    req.ClientID,
 		"schedule_id":  planned.ScheduleID,
@@ -229,16 +372,27 @@ func (h *WorkoutHandler) AddExercise(c *fiber.Ctx) error {
 		"rest_seconds": planned.RestSeconds,
 		"notes":        planned.Notes,
 		"order":        planned.Order,
-	})
+	}
+
+	// The exercise is added either way; if the branch lacks its equipment the coach gets
+	// alternatives to swap in. Suggestions are best-effort and never fail the request.
+	substitutes, err := h.equipmentService.SubstitutesForSession(c.UserContext(), planned.ScheduleID, planned.ExerciseID)
+	if err != nil {
+		log.Printf("Warning: no substitutes for exercise %s: %v", planned.ExerciseID, err)
+	} else if len(substitutes) > 0 {
+		resp["equipment_unavailable"] = true
+		resp["substitutes"] = substitutes
+	}
+	return response.Created(c, resp)
 }
 
 // RemoveExercise DELETE /v1/pro/exercises/:id
 func (h *WorkoutHandler) RemoveExercise(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if err := h.workoutService.RemovePlannedExercise(c.UserContext(), id); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
-	return c.JSON(fiber.Map{"message": "deleted"})
+	return response.OK(c, fiber.Map{"message": "deleted"})
 }
 
 // UpdatePlannedExercise PUT /v1/pro/exercises/:id
@@ -252,7 +406,7 @@ func (h *WorkoutHandler) UpdatePlannedExercise(c *fiber.Ctx) error {
 		Order       int    `json:"order"`
 	}
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid body")
 	}
 
 	ex := &domain.PlannedExercise{
@@ -265,9 +419,9 @@ func (h *WorkoutHandler) UpdatePlannedExercise(c *fiber.Ctx) error {
 	}
 
 	if err := h.workoutService.UpdatePlannedExercise(c.UserContext(), ex); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
-	return c.JSON(fiber.Map{"message": "updated"})
+	return response.OK(c, fiber.Map{"message": "updated"})
 }
 
 // LogSessionSetByULID PATCH /v1/pro/sessions/:id/log-ulid
@@ -287,7 +441,7 @@ func (h *WorkoutHandler) LogSessionSetByULID(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid body")
 	}
 
 	setLog := &domain.SetLog{
@@ -302,12 +456,12 @@ func (h *WorkoutHandler) LogSessionSetByULID(c *fiber.Ctx) error {
 	// req.ExerciseID matches "exercise_ulid" json tag which frontend sends safely
 	if err := h.workoutService.LogSetByULID(c.UserContext(), sessionID, req.ExerciseID, setLog); err != nil {
 		if err == domain.ErrExerciseULIDNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+			return response.FailAs(c, fiber.StatusNotFound, err)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.JSON(fiber.Map{"message": "logged", "set_ulid": setLog.ULID})
+	return response.OK(c, fiber.Map{"message": "logged", "set_ulid": setLog.ULID})
 }
 
 // UpdateSetLog PUT /v1/pro/sets/:id - Atomic update of a set log document
@@ -321,7 +475,7 @@ func (h *WorkoutHandler) UpdateSetLog(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
 	// For partial updates, we might need a Get then Update pattern, but service handles it?
@@ -352,9 +506,9 @@ func (h *WorkoutHandler) UpdateSetLog(c *fiber.Ctx) error {
 	err := h.workoutService.UpdateSetLog(c.UserContext(), id, weight, reps, remarks, completed)
 	if err != nil {
 		if err == domain.ErrSessionNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Set log not found"})
+			return response.Error(c, fiber.StatusNotFound, "Set log not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	return c.SendStatus(fiber.StatusOK)
@@ -364,12 +518,12 @@ func (h *WorkoutHandler) UpdateSetLog(c *fiber.Ctx) error {
 func (h *WorkoutHandler) DeleteSetLog(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Set log ID is required"})
+		return response.Error(c, fiber.StatusBadRequest, "Set log ID is required")
 	}
 
 	err := h.workoutService.DeleteSetLog(c.UserContext(), id)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
 	return c.SendStatus(fiber.StatusOK)
@@ -384,15 +538,15 @@ func (h *WorkoutHandler) AddSetToExercise(c *fiber.Ctx) error {
 		SetIndex int    `json:"set_index"`
 	}
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid body"})
+		return response.Error(c, fiber.StatusBadRequest, "Invalid body")
 	}
 
 	setLog, err := h.workoutService.AddSetToExercise(c.UserContext(), exerciseID, req.ClientID, req.SetIndex)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return response.Fail(c, err)
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+	return response.Created(c, fiber.Map{
 		"id":        setLog.ID,
 		"client_id": setLog.ClientID,
 		"set_index": setLog.SetIndex,
@@ -0,0 +1,70 @@
+// Package ffmpeg extracts still frames from videos with the ffmpeg binary.
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// FrameExtractor implements domain.FrameExtractor. ffmpeg reads the video straight from
+// its URL, seeking to each frame, so the clip is never downloaded in full.
+type FrameExtractor struct {
+	binary string
+}
+
+// NewFrameExtractor returns an extractor using the ffmpeg at binary, which may be a bare
+// name looked up on PATH
+func NewFrameExtractor(binary string) (*FrameExtractor, error) {
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg not found: %w", err)
+	}
+	return &FrameExtractor{binary: path}, nil
+}
+
+// ExtractFrames grabs count JPEG stills spread evenly over the video, skipping the very
+// start and end where people are usually setting up or walking to the phone
+func (e *FrameExtractor) ExtractFrames(ctx context.Context, videoURL string, duration time.Duration, count int) ([]domain.VideoFrame, error) {
+	dir, err := os.MkdirTemp("", "frames-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create frame directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	step := duration.Seconds() / float64(count+1)
+	frames := make([]domain.VideoFrame, 0, count)
+	for i := 1; i <= count; i++ {
+		at := step * float64(i)
+		out := filepath.Join(dir, fmt.Sprintf("%02d.jpg", i))
+
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, e.binary,
+			"-nostdin", "-loglevel", "error",
+			"-ss", strconv.FormatFloat(at, 'f', 3, 64),
+			"-i", videoURL,
+			"-frames:v", "1",
+			"-vf", "scale=720:-2", // plenty for pose, and keeps the model request small
+			"-q:v", "4",
+			out,
+		)
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("ffmpeg failed at %.1fs: %w: %s", at, err, stderr.String())
+		}
+
+		image, err := os.ReadFile(out)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read frame: %w", err)
+		}
+		frames = append(frames, domain.VideoFrame{AtSeconds: at, Image: image})
+	}
+	return frames, nil
+}
@@ -0,0 +1,338 @@
+// Package gymimport reads the CSV exports of other gym management systems for tenants
+// migrating to Metamorph.
+package gymimport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// maxRows bounds one export file. The parsed rows are kept on the import until it runs,
+// so a longer booking history has to be exported in date ranges.
+const maxRows = 20000
+
+const defaultSessionLength = time.Hour
+
+// columns maps a field to the headers it's exported under, normalized (see normalize)
+type columns map[string][]string
+
+// format describes one system's exports
+type format struct {
+	source      string
+	files       map[string]columns
+	dateLayouts []string          // Dates, with or without a time of day
+	timeLayouts []string          // Times of day in separate columns
+	statuses    map[string]string // Normalized booking status → Schedule status
+}
+
+// Adapter reads exports in one system's format
+type Adapter struct {
+	format format
+}
+
+func (a *Adapter) Source() string {
+	return a.format.source
+}
+
+func (a *Adapter) Parse(file string, data []byte, loc *time.Location) (*domain.GymImportData, []domain.GymImportIssue, error) {
+	cols, ok := a.format.files[file]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: unknown file %q", domain.ErrInvalidImportFile, file)
+	}
+	t, err := readTable(data, cols)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s export: %v", domain.ErrInvalidImportFile, file, err)
+	}
+	if missing := t.missing("id"); missing != "" {
+		return nil, nil, fmt.Errorf("%w: %s export has no %s column", domain.ErrInvalidImportFile, file, missing)
+	}
+
+	p := &parser{format: a.format, file: file, loc: loc}
+	out := &domain.GymImportData{}
+	for _, row := range t.rows {
+		switch file {
+		case domain.ImportFileMembers:
+			if m, ok := p.member(row); ok {
+				out.Members = append(out.Members, m)
+			}
+		case domain.ImportFileMemberships:
+			if m, ok := p.membership(row); ok {
+				out.Memberships = append(out.Memberships, m)
+			}
+		case domain.ImportFileBookings:
+			if b, ok := p.booking(row); ok {
+				out.Bookings = append(out.Bookings, b)
+			}
+		}
+	}
+	return out, p.issues, nil
+}
+
+var nonAlnum = regexp.MustCompile(`[^a-z0-9]+`)
+
+// normalize reduces a header or status to lowercase letters and digits: "No-Show" → "noshow"
+func normalize(s string) string {
+	return nonAlnum.ReplaceAllString(strings.ToLower(s), "")
+}
+
+// table is a CSV file with its columns resolved to fields
+type table struct {
+	index map[string]int // Field → column
+	rows  []row
+}
+
+type row struct {
+	line  int
+	cells []string
+	index map[string]int
+}
+
+func (r row) get(field string) string {
+	i, ok := r.index[field]
+	if !ok || i >= len(r.cells) {
+		return ""
+	}
+	return strings.TrimSpace(r.cells[i])
+}
+
+func (t *table) missing(fields ...string) string {
+	for _, f := range fields {
+		if _, ok := t.index[f]; !ok {
+			return f
+		}
+	}
+	return ""
+}
+
+func readTable(data []byte, cols columns) (*table, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	byHeader := make(map[string]int, len(header))
+	for i, h := range header {
+		if _, dup := byHeader[normalize(h)]; !dup {
+			byHeader[normalize(h)] = i
+		}
+	}
+	t := &table{index: make(map[string]int)}
+	for field, aliases := range cols {
+		for _, alias := range aliases {
+			if i, ok := byHeader[alias]; ok {
+				t.index[field] = i
+				break
+			}
+		}
+	}
+
+	for {
+		cells, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(strings.Join(cells, "")) == "" {
+			continue
+		}
+		if len(t.rows) == maxRows {
+			return nil, fmt.Errorf("more than %d rows; export a shorter date range", maxRows)
+		}
+		line, _ := reader.FieldPos(0)
+		t.rows = append(t.rows, row{line: line, cells: cells, index: t.index})
+	}
+	return t, nil
+}
+
+// parser turns rows into import records, collecting the rows it can't read
+type parser struct {
+	format
+	file   string
+	loc    *time.Location
+	issues []domain.GymImportIssue
+}
+
+func (p *parser) skip(r row, format string, args ...interface{}) bool {
+	p.issues = append(p.issues, domain.GymImportIssue{
+		File:       p.file,
+		Row:        r.line,
+		ExternalID: r.get("id"),
+		Message:    fmt.Sprintf(format, args...),
+	})
+	return false
+}
+
+func (p *parser) member(r row) (m domain.ImportedMember, ok bool) {
+	m = domain.ImportedMember{
+		ExternalID: r.get("id"),
+		Name:       r.get("name"),
+		Email:      strings.ToLower(r.get("email")),
+		Phone:      r.get("phone"),
+	}
+	if m.Name == "" {
+		m.Name = strings.TrimSpace(r.get("first_name") + " " + r.get("last_name"))
+	}
+	switch {
+	case m.ExternalID == "":
+		return m, p.skip(r, "missing member ID")
+	case m.Email == "":
+		return m, p.skip(r, "missing email")
+	case m.Name == "":
+		m.Name = m.Email
+	}
+	return m, true
+}
+
+func (p *parser) membership(r row) (m domain.ImportedMembership, ok bool) {
+	m = domain.ImportedMembership{
+		ExternalID:       r.get("id"),
+		MemberExternalID: r.get("member_id"),
+		Name:             r.get("name"),
+	}
+	if m.ExternalID == "" || m.MemberExternalID == "" {
+		return m, p.skip(r, "missing membership or member ID")
+	}
+
+	var err error
+	if m.TotalSessions, err = p.count(r.get("total")); err != nil {
+		return m, p.skip(r, "total sessions: %v", err)
+	}
+	if m.RemainingSessions, err = p.count(r.get("remaining")); err != nil {
+		return m, p.skip(r, "remaining sessions: %v", err)
+	}
+	if m.TotalSessions < m.RemainingSessions {
+		m.TotalSessions = m.RemainingSessions
+	}
+	if m.TotalSessions == 0 {
+		return m, p.skip(r, "membership has no sessions")
+	}
+	if m.Price, err = p.amount(r.get("price")); err != nil {
+		return m, p.skip(r, "price: %v", err)
+	}
+	if m.StartDate, err = p.date(r.get("start")); err != nil {
+		return m, p.skip(r, "start date: %v", err)
+	}
+	if raw := r.get("end"); raw != "" {
+		end, err := p.date(raw)
+		if err != nil {
+			return m, p.skip(r, "end date: %v", err)
+		}
+		m.EndDate = &end
+	}
+	if m.Name == "" {
+		m.Name = fmt.Sprintf("%d sessions", m.TotalSessions)
+	}
+	return m, true
+}
+
+func (p *parser) booking(r row) (b domain.ImportedBooking, ok bool) {
+	b = domain.ImportedBooking{
+		ExternalID:       r.get("id"),
+		MemberExternalID: r.get("member_id"),
+		Coach:            r.get("coach"),
+	}
+	if b.ExternalID == "" || b.MemberExternalID == "" {
+		return b, p.skip(r, "missing booking or member ID")
+	}
+
+	status, known := p.statuses[normalize(r.get("status"))]
+	if !known {
+		return b, p.skip(r, "unknown booking status %q", r.get("status"))
+	}
+	b.Status = status
+
+	var err error
+	if b.StartTime, err = p.dateTime(r.get("date"), r.get("start")); err != nil {
+		return b, p.skip(r, "start: %v", err)
+	}
+	switch {
+	case r.get("end") != "":
+		if b.EndTime, err = p.dateTime(r.get("date"), r.get("end")); err != nil {
+			return b, p.skip(r, "end: %v", err)
+		}
+	case r.get("duration") != "":
+		minutes, err := strconv.Atoi(strings.Fields(r.get("duration"))[0])
+		if err != nil || minutes <= 0 {
+			return b, p.skip(r, "duration %q is not a number of minutes", r.get("duration"))
+		}
+		b.EndTime = b.StartTime.Add(time.Duration(minutes) * time.Minute)
+	default:
+		b.EndTime = b.StartTime.Add(defaultSessionLength)
+	}
+	if !b.EndTime.After(b.StartTime) {
+		return b, p.skip(r, "session ends before it starts")
+	}
+	return b, true
+}
+
+// date parses a date, or a date and time, in the import's time zone
+func (p *parser) date(raw string) (time.Time, error) {
+	for _, layout := range p.dateLayouts {
+		if t, err := time.ParseInLocation(layout, raw, p.loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised date %q", raw)
+}
+
+// dateTime combines a date with a time of day from its own column, when there is one
+func (p *parser) dateTime(date, clock string) (time.Time, error) {
+	day, err := p.date(date)
+	if err != nil || clock == "" {
+		return day, err
+	}
+	for _, layout := range p.timeLayouts {
+		if t, err := time.Parse(layout, strings.ToUpper(clock)); err == nil {
+			return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, p.loc), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised time %q", clock)
+}
+
+func (p *parser) count(raw string) (int, error) {
+	if raw == "" || strings.EqualFold(raw, "unlimited") {
+		return 0, nil
+	}
+	n, err := strconv.ParseFloat(raw, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%q is not a session count", raw)
+	}
+	return int(n), nil
+}
+
+// amount reads a price, ignoring currency symbols and thousands separators
+func (p *parser) amount(raw string) (float64, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '.' || r == '-' {
+			return r
+		}
+		return -1
+	}, raw)
+	if cleaned == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not an amount", raw)
+	}
+	return v, nil
+}
@@ -0,0 +1,60 @@
+package gymimport
+
+import "github.com/mansoorceksport/metamorph/internal/domain"
+
+// NewGlofox reads the Members, Memberships and Bookings reports of the Glofox dashboard.
+// Glofox writes day-first dates.
+func NewGlofox() *Adapter {
+	return &Adapter{format: format{
+		source: domain.ImportSourceGlofox,
+		files: map[string]columns{
+			domain.ImportFileMembers: {
+				"id":         {"memberid", "userid", "id"},
+				"name":       {"fullname", "name"},
+				"first_name": {"firstname"},
+				"last_name":  {"lastname", "surname"},
+				"email":      {"email", "emailaddress"},
+				"phone":      {"phone", "phonenumber", "mobile"},
+			},
+			domain.ImportFileMemberships: {
+				"id":        {"membershipid", "purchaseid", "id"},
+				"member_id": {"memberid", "userid"},
+				"name":      {"membershipname", "planname", "membership", "plan"},
+				"total":     {"totalcredits", "credits", "sessions"},
+				"remaining": {"creditsremaining", "remainingcredits", "creditsleft"},
+				"price":     {"price", "amount", "amountpaid"},
+				"start":     {"startdate", "purchasedate"},
+				"end":       {"expirydate", "enddate", "expiry"},
+			},
+			domain.ImportFileBookings: {
+				"id":        {"bookingid", "id"},
+				"member_id": {"memberid", "userid"},
+				"coach":     {"trainer", "trainername", "instructor", "staff"},
+				"date":      {"date", "bookingdate", "classdate", "eventdate"},
+				"start":     {"starttime", "time"},
+				"end":       {"endtime"},
+				"duration":  {"duration", "durationmins"},
+				"status":    {"status", "bookingstatus"},
+			},
+		},
+		dateLayouts: []string{
+			"02/01/2006 15:04",
+			"02/01/2006",
+			"2006-01-02 15:04:05",
+			"2006-01-02 15:04",
+			"2006-01-02",
+			"02-01-2006",
+		},
+		timeLayouts: []string{"15:04", "15:04:05", "3:04 PM", "3:04PM"},
+		statuses: map[string]string{
+			"attended":      domain.ScheduleStatusCompleted,
+			"checkedin":     domain.ScheduleStatusCompleted,
+			"booked":        domain.ScheduleStatusScheduled,
+			"reserved":      domain.ScheduleStatusScheduled,
+			"cancelled":     domain.ScheduleStatusCancelled,
+			"canceled":      domain.ScheduleStatusCancelled,
+			"latecancelled": domain.ScheduleStatusCancelled,
+			"noshow":        domain.ScheduleStatusNoShow,
+		},
+	}}
+}
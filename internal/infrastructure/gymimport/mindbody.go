@@ -0,0 +1,62 @@
+package gymimport
+
+import "github.com/mansoorceksport/metamorph/internal/domain"
+
+// NewMindbody reads Mindbody's Client Export, Client Pricing Options and Visit History
+// reports. Mindbody calls members clients and bookings visits, and writes US dates.
+func NewMindbody() *Adapter {
+	return &Adapter{format: format{
+		source: domain.ImportSourceMindbody,
+		files: map[string]columns{
+			domain.ImportFileMembers: {
+				"id":         {"clientid", "id"},
+				"name":       {"clientname", "name"},
+				"first_name": {"firstname"},
+				"last_name":  {"lastname"},
+				"email":      {"email", "emailaddress"},
+				"phone":      {"mobilephone", "cellphone", "homephone", "phone"},
+			},
+			domain.ImportFileMemberships: {
+				"id":        {"pricingoptionid", "saleid", "id"},
+				"member_id": {"clientid"},
+				"name":      {"pricingoption", "series", "description", "name"},
+				"total":     {"count", "totalvisits", "sessions"},
+				"remaining": {"remaining", "remainingvisits", "visitsremaining"},
+				"price":     {"price", "amountpaid", "amount"},
+				"start":     {"activationdate", "saledate", "startdate"},
+				"end":       {"expirationdate", "expdate", "enddate"},
+			},
+			domain.ImportFileBookings: {
+				"id":        {"visitid", "id"},
+				"member_id": {"clientid"},
+				"coach":     {"staff", "staffname", "teacher", "instructor"},
+				"date":      {"visitdate", "classdate", "date"},
+				"start":     {"starttime", "time"},
+				"end":       {"endtime"},
+				"duration":  {"duration"},
+				"status":    {"status", "visitstatus"},
+			},
+		},
+		dateLayouts: []string{
+			"1/2/2006 3:04:05 PM",
+			"1/2/2006 3:04 PM",
+			"1/2/2006",
+			"2006-01-02 15:04:05",
+			"2006-01-02",
+		},
+		timeLayouts: []string{"3:04 PM", "3:04PM", "3:04:05 PM", "15:04"},
+		statuses: map[string]string{
+			"signedin":    domain.ScheduleStatusCompleted,
+			"checkedin":   domain.ScheduleStatusCompleted,
+			"completed":   domain.ScheduleStatusCompleted,
+			"booked":      domain.ScheduleStatusScheduled,
+			"confirmed":   domain.ScheduleStatusScheduled,
+			"reserved":    domain.ScheduleStatusScheduled,
+			"latecancel":  domain.ScheduleStatusCancelled,
+			"earlycancel": domain.ScheduleStatusCancelled,
+			"cancelled":   domain.ScheduleStatusCancelled,
+			"noshow":      domain.ScheduleStatusNoShow,
+			"missed":      domain.ScheduleStatusNoShow,
+		},
+	}}
+}
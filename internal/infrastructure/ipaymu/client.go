@@ -182,12 +182,8 @@ func (c *Client) CreateDirectVA(ctx context.Context, invoiceID string, amount in
 		return nil, fmt.Errorf("iPaymu API error: %s", apiResp.Message)
 	}
 
-	// Parse expiry date
+	// Parse expiry date; left zero when iPaymu sends none we can read
 	expiresAt, _ := time.Parse(time.RFC3339, apiResp.Data.Expired)
-	if expiresAt.IsZero() {
-		// Fallback to 24 hours from now
-		expiresAt = time.Now().UTC().Add(24 * time.Hour)
-	}
 
 	return &VAResponse{
 		VANumber:  apiResp.Data.PaymentNo,
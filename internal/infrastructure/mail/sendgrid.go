@@ -0,0 +1,77 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+const sendGridURL = "https://api.sendgrid.com/v3/mail/send"
+
+// SendGrid sends emails through the SendGrid v3 API
+type SendGrid struct {
+	apiKey     string
+	from       string // A verified sender of the SendGrid account
+	httpClient *http.Client
+}
+
+func NewSendGrid(apiKey, from string) *SendGrid {
+	return &SendGrid{
+		apiKey:     apiKey,
+		from:       from,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+func (s *SendGrid) Send(ctx context.Context, msg *domain.EmailMessage) error {
+	payload := map[string]interface{}{
+		"personalizations": []map[string]interface{}{
+			{"to": []sendGridAddress{{Email: msg.To, Name: msg.ToName}}},
+		},
+		"from":    sendGridAddress{Email: s.from, Name: msg.FromName},
+		"subject": msg.Subject,
+		// SendGrid wants text/plain before text/html
+		"content": []sendGridContent{{Type: "text/plain", Value: msg.Text}, {Type: "text/html", Value: msg.HTML}},
+	}
+	if msg.ReplyTo != "" {
+		payload["reply_to"] = sendGridAddress{Email: msg.ReplyTo}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sendGridURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sendgrid: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("sendgrid returned %d: %s", resp.StatusCode, string(detail))
+	}
+	return nil
+}
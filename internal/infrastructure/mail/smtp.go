@@ -0,0 +1,100 @@
+// Package mail provides domain.EmailSender implementations.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// SMTPConfig holds the mail server and the address emails are sent from
+type SMTPConfig struct {
+	Host     string
+	Port     string // 587 with STARTTLS in most setups
+	Username string // Empty for servers without authentication
+	Password string
+	From     string // e.g. "no-reply@metamorph.app"
+}
+
+// SMTP sends emails through a mail server
+type SMTP struct {
+	config SMTPConfig
+}
+
+func NewSMTP(config SMTPConfig) *SMTP {
+	return &SMTP{config: config}
+}
+
+// Send delivers msg as a text and HTML alternative. The server upgrades to TLS when it
+// offers STARTTLS; net/smtp only sends credentials over TLS or to localhost.
+func (s *SMTP) Send(ctx context.Context, msg *domain.EmailMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	body, err := s.compose(msg)
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+	addr := net.JoinHostPort(s.config.Host, s.config.Port)
+	if err := smtp.SendMail(addr, auth, s.config.From, []string{msg.To}, body); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return nil
+}
+
+// compose renders msg as a multipart/alternative message with its headers
+func (s *SMTP) compose(msg *domain.EmailMessage) ([]byte, error) {
+	var parts bytes.Buffer
+	w := multipart.NewWriter(&parts)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", msg.Text},
+		{"text/html; charset=UTF-8", msg.HTML},
+	} {
+		pw, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	header := func(key, value string) { fmt.Fprintf(&out, "%s: %s\r\n", key, value) }
+	header("From", (&mail.Address{Name: msg.FromName, Address: s.config.From}).String())
+	header("To", (&mail.Address{Name: msg.ToName, Address: msg.To}).String())
+	if msg.ReplyTo != "" {
+		header("Reply-To", msg.ReplyTo)
+	}
+	header("Subject", mime.QEncoding.Encode("UTF-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "multipart/alternative; boundary="+w.Boundary())
+	out.WriteString("\r\n")
+	out.Write(parts.Bytes())
+	return out.Bytes(), nil
+}
@@ -0,0 +1,29 @@
+package meeting
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// Room links each online session to its own room on a server that opens rooms on first
+// visit, such as Jitsi Meet. No account or API call is needed; the unguessable room name
+// is what keeps sessions private.
+type Room struct {
+	baseURL string
+}
+
+func NewRoom(baseURL string) *Room {
+	return &Room{baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+func (r *Room) CreateMeeting(_ context.Context, _ *domain.Schedule) (string, error) {
+	name := make([]byte, 12)
+	if _, err := rand.Read(name); err != nil {
+		return "", err
+	}
+	return r.baseURL + "/metamorph-" + hex.EncodeToString(name), nil
+}
@@ -0,0 +1,130 @@
+// Package meeting provides domain.MeetingLinkGenerator implementations.
+package meeting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+const (
+	zoomTokenURL    = "https://zoom.us/oauth/token"
+	zoomMeetingsURL = "https://api.zoom.us/v2/users/me/meetings"
+)
+
+// ZoomConfig holds the credentials of a Zoom Server-to-Server OAuth app with the
+// meeting:write scope. Meetings are created on the account owner's calendar.
+type ZoomConfig struct {
+	AccountID    string
+	ClientID     string
+	ClientSecret string
+}
+
+// Zoom schedules a Zoom meeting per online session
+type Zoom struct {
+	config     ZoomConfig
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+func NewZoom(config ZoomConfig) *Zoom {
+	return &Zoom{
+		config:     config,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (z *Zoom) CreateMeeting(ctx context.Context, schedule *domain.Schedule) (string, error) {
+	token, err := z.token(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	topic := "PT session"
+	if schedule.SessionGoal != "" {
+		topic += ": " + schedule.SessionGoal
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"topic":      topic,
+		"type":       2, // Scheduled meeting
+		"start_time": schedule.StartTime.UTC().Format("2006-01-02T15:04:05Z"),
+		"duration":   int(schedule.EndTime.Sub(schedule.StartTime).Minutes()),
+		"settings": map[string]interface{}{
+			"join_before_host": false,
+			"waiting_room":     true,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, zoomMeetingsURL, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	var meeting struct {
+		JoinURL string `json:"join_url"`
+	}
+	if err := z.do(req, http.StatusCreated, &meeting); err != nil {
+		return "", fmt.Errorf("failed to create zoom meeting: %w", err)
+	}
+	return meeting.JoinURL, nil
+}
+
+// token returns a cached access token, fetching a new one shortly before it expires
+func (z *Zoom) token(ctx context.Context) (string, error) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	if z.accessToken != "" && time.Now().Before(z.expiresAt) {
+		return z.accessToken, nil
+	}
+
+	form := url.Values{"grant_type": {"account_credentials"}, "account_id": {z.config.AccountID}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, zoomTokenURL+"?"+form.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(z.config.ClientID, z.config.ClientSecret)
+
+	var grant struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := z.do(req, http.StatusOK, &grant); err != nil {
+		return "", fmt.Errorf("failed to get zoom access token: %w", err)
+	}
+	z.accessToken = grant.AccessToken
+	z.expiresAt = time.Now().Add(time.Duration(grant.ExpiresIn)*time.Second - time.Minute)
+	return z.accessToken, nil
+}
+
+func (z *Zoom) do(req *http.Request, wantStatus int, out interface{}) error {
+	resp, err := z.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != wantStatus {
+		return fmt.Errorf("zoom returned %d: %s", resp.StatusCode, string(body))
+	}
+	return json.Unmarshal(body, out)
+}
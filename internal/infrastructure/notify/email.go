@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// EmailSender delivers notifications by email, in the branding of the notification's
+// tenant. Users without an email address are skipped.
+type EmailSender struct {
+	mailer  domain.EmailSender
+	users   domain.UserRepository
+	tenants domain.TenantRepository
+}
+
+func NewEmailSender(mailer domain.EmailSender, users domain.UserRepository, tenants domain.TenantRepository) *EmailSender {
+	return &EmailSender{mailer: mailer, users: users, tenants: tenants}
+}
+
+func (s *EmailSender) Channel() string {
+	return domain.ChannelEmail
+}
+
+func (s *EmailSender) Send(ctx context.Context, n *domain.Notification) error {
+	user, err := s.users.GetByID(ctx, n.UserID)
+	if err != nil {
+		return err
+	}
+	if user.Email == "" {
+		return nil
+	}
+	var tenant *domain.Tenant
+	if n.TenantID != "" {
+		tenant, err = s.tenants.GetByID(ctx, n.TenantID)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+	}
+	msg, err := domain.RenderEmail(tenant, user, n)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, msg)
+}
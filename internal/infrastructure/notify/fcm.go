@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"firebase.google.com/go/v4/messaging"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// FCMClient is the part of the Firebase messaging client FCMSender uses
+type FCMClient interface {
+	SendEachForMulticast(ctx context.Context, message *messaging.MulticastMessage) (*messaging.BatchResponse, error)
+}
+
+// FCMSender delivers push notifications through Firebase Cloud Messaging to every device
+// the user registered. Tokens FCM reports as unregistered, e.g. after the app was
+// uninstalled, are forgotten.
+type FCMSender struct {
+	client FCMClient
+	tokens domain.DeviceTokenRepository
+}
+
+func NewFCMSender(client FCMClient, tokens domain.DeviceTokenRepository) *FCMSender {
+	return &FCMSender{client: client, tokens: tokens}
+}
+
+func (s *FCMSender) Channel() string {
+	return domain.ChannelPush
+}
+
+// Send pushes n to the user's devices. A user without devices is not an error; failing on
+// every device is.
+func (s *FCMSender) Send(ctx context.Context, n *domain.Notification) error {
+	devices, err := s.tokens.ListByUser(ctx, n.UserID)
+	if err != nil {
+		return err
+	}
+	if len(devices) == 0 {
+		return nil
+	}
+	tokens := make([]string, len(devices))
+	for i, d := range devices {
+		tokens[i] = d.Token
+	}
+
+	resp, err := s.client.SendEachForMulticast(ctx, &messaging.MulticastMessage{
+		Tokens:       tokens,
+		Data:         n.Payload(),
+		Notification: &messaging.Notification{Title: n.Title, Body: n.Body},
+		Android:      &messaging.AndroidConfig{Priority: "high"},
+	})
+	if err != nil {
+		return fmt.Errorf("fcm: %w", err)
+	}
+
+	var stale []string
+	var lastErr error
+	for i, r := range resp.Responses {
+		if r.Success {
+			continue
+		}
+		if messaging.IsUnregistered(r.Error) {
+			stale = append(stale, tokens[i])
+			continue
+		}
+		lastErr = r.Error
+	}
+	if len(stale) > 0 {
+		if err := s.tokens.Delete(ctx, stale); err != nil {
+			log.Printf("Warning: failed to forget %d stale device tokens of user %s: %v", len(stale), n.UserID, err)
+		}
+	}
+	if resp.SuccessCount == 0 && lastErr != nil {
+		return fmt.Errorf("fcm: no device of user %s took the push: %w", n.UserID, lastErr)
+	}
+	return nil
+}
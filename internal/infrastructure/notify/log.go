@@ -0,0 +1,28 @@
+// Package notify provides domain.NotificationSender implementations.
+package notify
+
+import (
+	"context"
+	"log"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// LogSender writes notifications to the log instead of delivering them. It stands in for
+// a channel whose provider isn't configured, e.g. in development.
+type LogSender struct {
+	channel string
+}
+
+func NewLogSender(channel string) *LogSender {
+	return &LogSender{channel: channel}
+}
+
+func (s *LogSender) Channel() string {
+	return s.channel
+}
+
+func (s *LogSender) Send(_ context.Context, n *domain.Notification) error {
+	log.Printf("[Notify] %s to user %s: %s - %s %v", s.channel, n.UserID, n.Title, n.Body, n.Payload())
+	return nil
+}
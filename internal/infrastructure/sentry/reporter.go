@@ -0,0 +1,180 @@
+// Package sentry reports errors to Sentry, or any service speaking its envelope protocol
+// (GlitchTip, self-hosted Sentry), without pulling in the full SDK.
+package sentry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+const queueSize = 100
+
+// Config holds the error tracker settings
+type Config struct {
+	DSN         string // e.g. https://<key>@o123.ingest.sentry.io/<project>
+	Environment string
+	Release     string
+}
+
+// Reporter implements domain.ErrorReporter. Reports are sent by a single background
+// worker; when the queue is full new reports are dropped so a failing tracker can't
+// slow down requests.
+type Reporter struct {
+	config     Config
+	endpoint   string
+	authHeader string
+	httpClient *http.Client
+	queue      chan *domain.ErrorReport
+}
+
+func NewReporter(cfg Config) (*Reporter, error) {
+	u, err := url.Parse(cfg.DSN)
+	if err != nil || u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("invalid Sentry DSN")
+	}
+	project := path.Base(u.Path)
+	if project == "" || project == "/" || project == "." {
+		return nil, fmt.Errorf("invalid Sentry DSN: missing project ID")
+	}
+	prefix := strings.TrimSuffix(path.Dir(u.Path), "/")
+
+	r := &Reporter{
+		config:     cfg,
+		endpoint:   fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, prefix, project),
+		authHeader: fmt.Sprintf("Sentry sentry_version=7, sentry_client=metamorph/1.0, sentry_key=%s", u.User.Username()),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		queue:      make(chan *domain.ErrorReport, queueSize),
+	}
+	go r.run()
+	return r, nil
+}
+
+// Report queues the report for sending
+func (r *Reporter) Report(_ context.Context, report *domain.ErrorReport) {
+	select {
+	case r.queue <- report:
+	default:
+		log.Printf("Warning: error report queue full, dropping report: %v", report.Err)
+	}
+}
+
+func (r *Reporter) run() {
+	for report := range r.queue {
+		if err := r.send(report); err != nil {
+			log.Printf("Warning: failed to send error report: %v", err)
+		}
+	}
+}
+
+// event is the subset of the Sentry event payload we fill in
+type event struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Platform    string            `json:"platform"`
+	Level       string            `json:"level"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Transaction string            `json:"transaction,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Request     map[string]string `json:"request,omitempty"`
+	Exception   struct {
+		Values []exception `json:"values"`
+	} `json:"exception"`
+	Extra map[string]interface{} `json:"extra,omitempty"`
+}
+
+type exception struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+func (r *Reporter) send(report *domain.ErrorReport) error {
+	ev := event{
+		EventID:     newEventID(),
+		Timestamp:   report.OccurredAt.UTC().Format(time.RFC3339),
+		Platform:    "go",
+		Level:       "error",
+		Environment: r.config.Environment,
+		Release:     r.config.Release,
+		Transaction: report.Tags["route"],
+		Tags:        report.Tags,
+		Request:     map[string]string{"method": report.Method, "url": report.Path},
+		Extra:       map[string]interface{}{"status_code": report.StatusCode},
+	}
+
+	excType := fmt.Sprintf("%T", report.Err)
+	if report.Panic {
+		ev.Level = "fatal"
+		excType = "panic"
+		ev.Extra["stack"] = report.Stack
+	}
+	message := "unknown error"
+	if report.Err != nil {
+		message = report.Err.Error()
+	}
+	ev.Exception.Values = []exception{{Type: excType, Value: message}}
+
+	body, err := r.envelope(&ev)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", r.authHeader)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("sentry returned %d: %s", resp.StatusCode, msg)
+	}
+	return nil
+}
+
+// envelope wraps the event in Sentry's newline-delimited envelope format
+func (r *Reporter) envelope(ev *event) ([]byte, error) {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+	header, _ := json.Marshal(map[string]string{
+		"event_id": ev.EventID,
+		"sent_at":  time.Now().UTC().Format(time.RFC3339),
+	})
+	itemHeader, _ := json.Marshal(map[string]interface{}{"type": "event", "length": len(payload)})
+
+	var buf bytes.Buffer
+	buf.Write(header)
+	buf.WriteByte('\n')
+	buf.Write(itemHeader)
+	buf.WriteByte('\n')
+	buf.Write(payload)
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,111 @@
+// Package warehouse provides domain.WarehouseSink implementations.
+package warehouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// ClickHouseConfig holds the ClickHouse HTTP interface settings.
+// The table is expected to look like:
+//
+//	CREATE TABLE metamorph_events (
+//	    schema_version UInt16,
+//	    entity         LowCardinality(String),
+//	    op             LowCardinality(String),
+//	    tenant_id      String,
+//	    entity_id      String,
+//	    occurred_at    DateTime64(3),
+//	    anonymized     Bool,
+//	    data           String  -- JSON, shape depends on entity and schema_version
+//	) ENGINE = MergeTree ORDER BY (tenant_id, entity, occurred_at)
+type ClickHouseConfig struct {
+	URL      string // e.g. https://clickhouse.example.com:8443
+	Database string
+	Table    string
+	User     string
+	Password string
+}
+
+// ClickHouseSink inserts rows through the ClickHouse HTTP interface as JSONEachRow
+type ClickHouseSink struct {
+	config     ClickHouseConfig
+	httpClient *http.Client
+}
+
+func NewClickHouseSink(config ClickHouseConfig) *ClickHouseSink {
+	return &ClickHouseSink{
+		config:     config,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// clickHouseRow flattens WarehouseRow; data is stored as a JSON string so the table
+// schema doesn't change when an entity gains fields
+type clickHouseRow struct {
+	SchemaVersion int    `json:"schema_version"`
+	Entity        string `json:"entity"`
+	Op            string `json:"op"`
+	TenantID      string `json:"tenant_id"`
+	EntityID      string `json:"entity_id"`
+	OccurredAt    string `json:"occurred_at"`
+	Anonymized    bool   `json:"anonymized"`
+	Data          string `json:"data"`
+}
+
+func (s *ClickHouseSink) Write(ctx context.Context, rows []*domain.WarehouseRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, row := range rows {
+		data, err := json.Marshal(row.Data)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s %s: %w", row.Entity, row.EntityID, err)
+		}
+		err = enc.Encode(clickHouseRow{
+			SchemaVersion: row.SchemaVersion,
+			Entity:        row.Entity,
+			Op:            row.Op,
+			TenantID:      row.TenantID,
+			EntityID:      row.EntityID,
+			OccurredAt:    row.OccurredAt.UTC().Format("2006-01-02 15:04:05.000"),
+			Anonymized:    row.Anonymized,
+			Data:          string(data),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	query := url.Values{}
+	query.Set("query", fmt.Sprintf("INSERT INTO %s.%s FORMAT JSONEachRow", s.config.Database, s.config.Table))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.URL+"/?"+query.Encode(), &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-ClickHouse-User", s.config.User)
+	req.Header.Set("X-ClickHouse-Key", s.config.Password)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("clickhouse insert failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("clickhouse insert failed: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package warehouse
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// JSONLinesSink writes one JSON row per line, e.g. to stdout for piping into `bq load`
+// or another warehouse's bulk loader
+type JSONLinesSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func NewJSONLinesSink(w io.Writer) *JSONLinesSink {
+	return &JSONLinesSink{enc: json.NewEncoder(w)}
+}
+
+func (s *JSONLinesSink) Write(_ context.Context, rows []*domain.WarehouseRow) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, row := range rows {
+		if err := s.enc.Encode(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
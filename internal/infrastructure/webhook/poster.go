@@ -0,0 +1,116 @@
+// Package webhook delivers payloads to tenants' own HTTPS endpoints.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+const (
+	// SignatureHeader carries the hex HMAC-SHA256 of the timestamp, a dot and the body under
+	// the endpoint's secret, so the receiver can tell the request came from us
+	SignatureHeader = "X-Metamorph-Signature"
+	// TimestampHeader carries the Unix seconds the request was signed at, so the receiver
+	// can refuse old requests replayed at it
+	TimestampHeader = "X-Metamorph-Timestamp"
+)
+
+var errNonPublicAddress = errors.New("webhook host is not a public address")
+
+// Poster implements domain.ReportWebhook and domain.WebhookPoster
+type Poster struct {
+	httpClient *http.Client
+	resolver   *net.Resolver
+	clock      domain.Clock
+}
+
+func NewPoster(clk domain.Clock) *Poster {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, Control: dialPublicOnly}
+	// No proxy: it would be the one dialing, past dialPublicOnly
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	return &Poster{
+		httpClient: &http.Client{Timeout: 15 * time.Second, Transport: transport},
+		resolver:   net.DefaultResolver,
+		clock:      clock.OrReal(clk),
+	}
+}
+
+// dialPublicOnly refuses connections to addresses domain.PublicIP rejects. It sees the
+// address actually dialed, so a host that resolved to a public address when registered
+// but to an internal one now, or a redirect inward, is refused too.
+func dialPublicOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !domain.PublicIP(ip) {
+		return errNonPublicAddress
+	}
+	return nil
+}
+
+// Sign returns the signature of body sent at timestamp under secret, as sent in SignatureHeader
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (p *Poster) Post(ctx context.Context, url, secret string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := p.clock.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(secret, timestamp, body))
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	// Only the status is kept: the body is the endpoint's to show, not ours
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (p *Poster) CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("%w: url must be an https URL", domain.ErrInvalidWebhook)
+	}
+	addrs, err := p.resolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: %s does not resolve", domain.ErrInvalidWebhook, u.Hostname())
+	}
+	for _, addr := range addrs {
+		if !domain.PublicIP(addr.IP) {
+			return fmt.Errorf("%w: url must point to a public host", domain.ErrInvalidWebhook)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+const archiveBatchSize = 500
+
+// ArchiveWorkouts moves the workout detail of schedules older than months to cold storage, daily
+func ArchiveWorkouts(repo domain.WorkoutArchiveRepository, clk domain.Clock, months int) Job {
+	return Job{
+		Name:     "archive-workouts",
+		Interval: 24 * time.Hour,
+		Run: func(ctx context.Context) error {
+			cutoff := clk.Now().AddDate(0, -months, 0)
+			total := 0
+			for {
+				summary, err := repo.ArchiveBefore(ctx, cutoff, archiveBatchSize)
+				if summary != nil {
+					total += summary.Schedules
+				}
+				if err != nil {
+					return err
+				}
+				if summary.Schedules < archiveBatchSize {
+					break
+				}
+			}
+			log.Printf("Archived workout detail of %d schedules", total)
+			return nil
+		},
+	}
+}
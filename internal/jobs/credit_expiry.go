@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// CreditExpirer expires the unused sessions of contracts past their validity and warns
+// about the ones expiring soon
+type CreditExpirer interface {
+	ExpireDue(ctx context.Context) (warned, expired int, err error)
+}
+
+// CreditExpiry checks contract validity nightly; expiry is by the day, so nothing finer is needed
+func CreditExpiry(expirer CreditExpirer) Job {
+	return Job{
+		Name:     "credit-expiry",
+		Interval: 24 * time.Hour,
+		Run: func(ctx context.Context) error {
+			warned, expired, err := expirer.ExpireDue(ctx)
+			if warned > 0 || expired > 0 {
+				log.Printf("Expired %d contracts, warned %d about expiring sessions", expired, warned)
+			}
+			return err
+		},
+	}
+}
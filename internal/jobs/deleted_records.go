@@ -0,0 +1,30 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// DeletedRecordPurger permanently removes records soft-deleted before a time
+type DeletedRecordPurger interface {
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+}
+
+// PurgeDeletedRecords removes the users, branches and tenants deleted more than days ago,
+// daily. Until then they can be restored.
+func PurgeDeletedRecords(purger DeletedRecordPurger, clk domain.Clock, days int) Job {
+	return Job{
+		Name:     "purge-deleted-records",
+		Interval: 24 * time.Hour,
+		Run: func(ctx context.Context) error {
+			purged, err := purger.PurgeDeleted(ctx, clk.Now().AddDate(0, 0, -days))
+			if purged > 0 {
+				log.Printf("Purged %d deleted records", purged)
+			}
+			return err
+		},
+	}
+}
@@ -0,0 +1,30 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+const formFeedbackBatchSize = 20
+
+// FormAnalyzer reviews queued form-check videos
+type FormAnalyzer interface {
+	AnalyzePending(ctx context.Context, limit int) (int, error)
+}
+
+// FormFeedback works through the videos waiting for AI form feedback every minute. A
+// batch is kept small since each video costs a model call and a few ffmpeg runs.
+func FormFeedback(analyzer FormAnalyzer) Job {
+	return Job{
+		Name:     "form-feedback",
+		Interval: time.Minute,
+		Run: func(ctx context.Context) error {
+			analyzed, err := analyzer.AnalyzePending(ctx, formFeedbackBatchSize)
+			if analyzed > 0 {
+				log.Printf("Analyzed form in %d videos", analyzed)
+			}
+			return err
+		},
+	}
+}
@@ -0,0 +1,29 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// InstallmentEnforcer suspends contracts with overdue installments and reinstates the
+// ones that caught up
+type InstallmentEnforcer interface {
+	EnforceDue(ctx context.Context) (suspended, reinstated int, err error)
+}
+
+// OverdueInstallments checks installment plans hourly, so a contract is suspended soon
+// after its grace period ends
+func OverdueInstallments(enforcer InstallmentEnforcer) Job {
+	return Job{
+		Name:     "overdue-installments",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			suspended, reinstated, err := enforcer.EnforceDue(ctx)
+			if suspended > 0 || reinstated > 0 {
+				log.Printf("Suspended %d contracts for overdue installments, reinstated %d", suspended, reinstated)
+			}
+			return err
+		},
+	}
+}
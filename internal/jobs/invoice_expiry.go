@@ -0,0 +1,29 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// InvoiceExpirer expires checkout invoices whose VA expired unpaid and reminds members of
+// the ones expiring soon
+type InvoiceExpirer interface {
+	ExpireDue(ctx context.Context) (reminded, expired int, err error)
+}
+
+// InvoiceExpiry checks pending checkouts hourly. Checkouts retried before it runs reopen
+// their lapsed invoice anyway, so nothing finer is needed.
+func InvoiceExpiry(expirer InvoiceExpirer) Job {
+	return Job{
+		Name:     "invoice-expiry",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			reminded, expired, err := expirer.ExpireDue(ctx)
+			if reminded > 0 || expired > 0 {
+				log.Printf("Expired %d invoices, reminded %d members to pay", expired, reminded)
+			}
+			return err
+		},
+	}
+}
@@ -0,0 +1,21 @@
+package jobs
+
+import (
+	"context"
+	"time"
+)
+
+// OffboardingAdvancer moves tenant off-boardings past their grace period and on from failures
+type OffboardingAdvancer interface {
+	Advance(ctx context.Context) error
+}
+
+// TenantOffboarding advances off-boardings hourly; a tenant is anonymized and purged within
+// an hour of its grace period ending
+func TenantOffboarding(advancer OffboardingAdvancer) Job {
+	return Job{
+		Name:     "tenant-offboarding",
+		Interval: time.Hour,
+		Run:      advancer.Advance,
+	}
+}
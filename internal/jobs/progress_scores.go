@@ -0,0 +1,26 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// ProgressScorer scores members for the last full week
+type ProgressScorer interface {
+	ScoreLastWeek(ctx context.Context) (int, error)
+}
+
+// ProgressScores scores last week daily rather than once on Monday: a score is replaced
+// rather than added, and sessions logged late still make it in.
+func ProgressScores(scorer ProgressScorer) Job {
+	return Job{
+		Name:     "progress-scores",
+		Interval: 24 * time.Hour,
+		Run: func(ctx context.Context) error {
+			scored, err := scorer.ScoreLastWeek(ctx)
+			log.Printf("Computed %d progress scores", scored)
+			return err
+		},
+	}
+}
@@ -0,0 +1,52 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// ReminderSender sends the reminders due in a window of time
+type ReminderSender interface {
+	SendReminders(ctx context.Context, from, to time.Time) (int, error)
+}
+
+// SessionReminders sends the reminders falling in the current slot, every interval. The
+// slot boundaries match the scheduler's, so consecutive runs cover time without gaps.
+func SessionReminders(sender ReminderSender, clk domain.Clock, interval time.Duration) Job {
+	return Job{
+		Name:     "session-reminders",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			from := clk.Now().UTC().Truncate(interval)
+			sent, err := sender.SendReminders(ctx, from, from.Add(interval))
+			if sent > 0 {
+				log.Printf("Sent %d session reminders", sent)
+			}
+			return err
+		},
+	}
+}
+
+// UnconfirmedAlerter alerts coaches about sessions the member hasn't confirmed
+type UnconfirmedAlerter interface {
+	AlertUnconfirmed(ctx context.Context) (int, error)
+}
+
+// UnconfirmedSessions checks for unconfirmed sessions every interval. Each session is only
+// alerted once, so runs may overlap in what they look at.
+func UnconfirmedSessions(alerter UnconfirmedAlerter, interval time.Duration) Job {
+	return Job{
+		Name:     "unconfirmed-sessions",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			alerted, err := alerter.AlertUnconfirmed(ctx)
+			if alerted > 0 {
+				log.Printf("Alerted coaches about %d unconfirmed sessions", alerted)
+			}
+			return err
+		},
+	}
+}
@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// ContractRenewer renews the contracts set to auto-renew whose sessions ran out or expired
+type ContractRenewer interface {
+	RenewDue(ctx context.Context) (int, error)
+}
+
+// ContractRenewals looks for contracts due to renew hourly. Most renew when their last
+// session is completed; this catches expiries, which happen overnight, and retries failures.
+func ContractRenewals(renewer ContractRenewer) Job {
+	return Job{
+		Name:     "contract-renewals",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			renewed, err := renewer.RenewDue(ctx)
+			if renewed > 0 {
+				log.Printf("Renewed %d contracts", renewed)
+			}
+			return err
+		},
+	}
+}
@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// ReportDeliverer generates and delivers the scheduled reports that are due
+type ReportDeliverer interface {
+	RunDue(ctx context.Context) (delivered int, err error)
+}
+
+// ReportSchedules checks for due reports hourly, so a report arrives within an hour of its
+// period closing
+func ReportSchedules(deliverer ReportDeliverer) Job {
+	return Job{
+		Name:     "report-schedules",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			delivered, err := deliverer.RunDue(ctx)
+			if delivered > 0 {
+				log.Printf("Delivered %d scheduled reports", delivered)
+			}
+			return err
+		},
+	}
+}
@@ -0,0 +1,112 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// RetryHandler re-executes a run of its job type from the run's params
+type RetryHandler func(ctx context.Context, params map[string]interface{}) error
+
+// Runner records job executions in the job-runs collection and retries failed ones
+type Runner struct {
+	repo  domain.JobRunRepository
+	clock domain.Clock
+
+	mu       sync.RWMutex
+	handlers map[string]RetryHandler
+}
+
+func NewRunner(repo domain.JobRunRepository, clk domain.Clock) *Runner {
+	return &Runner{
+		repo:     repo,
+		clock:    clock.OrReal(clk),
+		handlers: make(map[string]RetryHandler),
+	}
+}
+
+// Handle makes runs of jobType retryable
+func (r *Runner) Handle(jobType string, handler RetryHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[jobType] = handler
+}
+
+// Record runs fn and records it as a run of jobType. fn's error is returned unchanged;
+// failing to record the run is only logged, so bookkeeping never blocks the work itself.
+func (r *Runner) Record(ctx context.Context, jobType, tenantID string, params map[string]interface{}, fn func(ctx context.Context) error) error {
+	run := r.start(ctx, &domain.JobRun{Type: jobType, TenantID: tenantID, Params: params})
+	err := fn(ctx)
+	r.finish(ctx, run, err)
+	return err
+}
+
+// Retry starts a new run of a failed run in the background and returns it
+func (r *Runner) Retry(ctx context.Context, id string) (*domain.JobRun, error) {
+	prev, err := r.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	handler, ok := r.handlers[prev.Type]
+	r.mu.RUnlock()
+	if !ok || prev.Status != domain.JobRunStatusFailed {
+		return nil, domain.ErrJobNotRetryable
+	}
+
+	run := &domain.JobRun{
+		Type:      prev.Type,
+		TenantID:  prev.TenantID,
+		Params:    prev.Params,
+		RetryOf:   prev.ID,
+		Status:    domain.JobRunStatusRunning,
+		CreatedAt: r.clock.Now(),
+	}
+	if err := r.repo.Create(ctx, run); err != nil {
+		return nil, err
+	}
+
+	// The request that asked for the retry shouldn't wait for (or cancel) the job
+	go func() {
+		bg := context.Background()
+		r.finish(bg, run, handler(bg, run.Params))
+	}()
+	return run, nil
+}
+
+func (r *Runner) start(ctx context.Context, run *domain.JobRun) *domain.JobRun {
+	run.Status = domain.JobRunStatusRunning
+	run.CreatedAt = r.clock.Now()
+	if err := r.repo.Create(ctx, run); err != nil {
+		log.Printf("Warning: failed to record %s run: %v", run.Type, err)
+		return nil
+	}
+	return run
+}
+
+func (r *Runner) finish(ctx context.Context, run *domain.JobRun, err error) {
+	if run == nil {
+		return
+	}
+	finishedAt := r.clock.Now()
+	run.FinishedAt = &finishedAt
+	run.DurationMS = finishedAt.Sub(run.CreatedAt).Milliseconds()
+	run.Status = domain.JobRunStatusSucceeded
+	if err != nil {
+		run.Status = domain.JobRunStatusFailed
+		run.Error = err.Error()
+	}
+
+	// The job's context may have been cancelled, which is often why it failed
+	finishCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := r.repo.Finish(finishCtx, run); err != nil {
+		log.Printf("Warning: failed to record %s run %s outcome: %v", run.Type, run.ID, err)
+	}
+}
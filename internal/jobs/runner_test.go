@@ -0,0 +1,91 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunner_Record(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 16, 10, 0, 0, 0, time.UTC)
+	params := map[string]interface{}{"schedule_id": "s1"}
+
+	repo := mocks.NewJobRunRepository(t)
+	repo.On("Create", ctx, mock.Anything).Return(nil)
+	var finished *domain.JobRun
+	repo.On("Finish", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		finished = args.Get(1).(*domain.JobRun)
+	}).Return(nil)
+
+	err := NewRunner(repo, clock.NewFake(now)).Record(ctx, "volume", "tenant-1", params, func(context.Context) error {
+		return errors.New("mongo timeout")
+	})
+
+	assert.EqualError(t, err, "mongo timeout")
+	require.NotNil(t, finished)
+	assert.Equal(t, domain.JobRunStatusFailed, finished.Status)
+	assert.Equal(t, "mongo timeout", finished.Error)
+	assert.Equal(t, "tenant-1", finished.TenantID)
+	assert.Equal(t, params, finished.Params)
+}
+
+func TestRunner_Retry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 16, 10, 0, 0, 0, time.UTC)
+	failed := &domain.JobRun{ID: "run-1", Type: "volume", Status: domain.JobRunStatusFailed, Params: map[string]interface{}{"schedule_id": "s1"}}
+
+	t.Run("re-runs a failed run with its params", func(t *testing.T) {
+		repo := mocks.NewJobRunRepository(t)
+		repo.On("GetByID", ctx, "run-1").Return(failed, nil)
+		repo.On("Create", ctx, mock.Anything).Return(nil)
+		done := make(chan *domain.JobRun, 1)
+		repo.On("Finish", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			done <- args.Get(1).(*domain.JobRun)
+		}).Return(nil)
+
+		runner := NewRunner(repo, clock.NewFake(now))
+		var gotParams map[string]interface{}
+		runner.Handle("volume", func(_ context.Context, params map[string]interface{}) error {
+			gotParams = params
+			return nil
+		})
+
+		run, err := runner.Retry(ctx, "run-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "run-1", run.RetryOf)
+		finished := <-done
+		assert.Equal(t, domain.JobRunStatusSucceeded, finished.Status)
+		assert.Equal(t, failed.Params, gotParams)
+	})
+
+	t.Run("refuses runs that did not fail", func(t *testing.T) {
+		repo := mocks.NewJobRunRepository(t)
+		repo.On("GetByID", ctx, "run-2").Return(&domain.JobRun{ID: "run-2", Type: "volume", Status: domain.JobRunStatusSucceeded}, nil)
+
+		runner := NewRunner(repo, clock.NewFake(now))
+		runner.Handle("volume", func(context.Context, map[string]interface{}) error { return nil })
+
+		_, err := runner.Retry(ctx, "run-2")
+
+		assert.ErrorIs(t, err, domain.ErrJobNotRetryable)
+	})
+
+	t.Run("refuses unknown job types", func(t *testing.T) {
+		repo := mocks.NewJobRunRepository(t)
+		repo.On("GetByID", ctx, "run-1").Return(failed, nil)
+
+		_, err := NewRunner(repo, clock.NewFake(now)).Retry(ctx, "run-1")
+
+		assert.ErrorIs(t, err, domain.ErrJobNotRetryable)
+	})
+}
@@ -0,0 +1,41 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+const scanImageBatchSize = 100
+
+// ScanImageArchiver downscales scan images past their retention period
+type ScanImageArchiver interface {
+	ArchiveImages(ctx context.Context, before time.Time, limit int) (int, error)
+}
+
+// ArchiveScanImages downscales the original images of scans older than days, daily. It
+// stops after a short batch, so images that keep failing don't hold up the run.
+func ArchiveScanImages(archiver ScanImageArchiver, clk domain.Clock, days int) Job {
+	return Job{
+		Name:     "archive-scan-images",
+		Interval: 24 * time.Hour,
+		Run: func(ctx context.Context) error {
+			cutoff := clk.Now().AddDate(0, 0, -days)
+			total := 0
+			for {
+				archived, err := archiver.ArchiveImages(ctx, cutoff, scanImageBatchSize)
+				total += archived
+				if err != nil {
+					return err
+				}
+				if archived < scanImageBatchSize {
+					break
+				}
+			}
+			log.Printf("Archived %d scan images", total)
+			return nil
+		},
+	}
+}
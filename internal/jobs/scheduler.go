@@ -0,0 +1,112 @@
+// Package jobs runs periodic background work. Every API replica runs the same scheduler;
+// a distributed lock per time slot makes sure each job runs once per slot across the fleet.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// Job is a unit of periodic work
+type Job struct {
+	Name     string
+	Interval time.Duration // The job runs once per slot of this length, aligned to UTC
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler ticks registered jobs and elects one runner per slot through the locker
+type Scheduler struct {
+	locker domain.Locker
+	clock  domain.Clock
+	poll   time.Duration // How often replicas check for a new slot
+	runs   *Runner       // Optional: records each run in the job history
+
+	mu   sync.Mutex
+	jobs []Job
+}
+
+func NewScheduler(locker domain.Locker, clk domain.Clock, runs *Runner) *Scheduler {
+	return &Scheduler{
+		locker: locker,
+		clock:  clock.OrReal(clk),
+		poll:   time.Minute,
+		runs:   runs,
+	}
+}
+
+// Register adds a job; it must be called before Start. Failed runs of a registered
+// job can be retried from the job history.
+func (s *Scheduler) Register(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, job)
+	if s.runs != nil {
+		s.runs.Handle(job.Name, func(ctx context.Context, _ map[string]interface{}) error {
+			return job.Run(ctx)
+		})
+	}
+}
+
+// Names lists the registered jobs in registration order
+func (s *Scheduler) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, len(s.jobs))
+	for i, job := range s.jobs {
+		names[i] = job.Name
+	}
+	return names
+}
+
+// Start runs every job in its own goroutine until ctx is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, job := range s.jobs {
+		go s.loop(ctx, job)
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(min(s.poll, job.Interval))
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunSlot(ctx, job); err != nil && ctx.Err() == nil {
+			log.Printf("Warning: job %s failed: %v", job.Name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunSlot runs the job for the current slot unless another replica already has.
+// It reports whether this replica ran it.
+func (s *Scheduler) RunSlot(ctx context.Context, job Job) (bool, error) {
+	slot := s.clock.Now().UTC().Truncate(job.Interval)
+
+	// The lock is never released: it expires with the slot, so a replica that polls
+	// later in the same slot still finds it taken
+	key := fmt.Sprintf("job:%s:%d", job.Name, slot.Unix())
+	if _, err := s.locker.Acquire(ctx, key, job.Interval, 0); err != nil {
+		if errors.Is(err, domain.ErrLockNotAcquired) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if s.runs == nil {
+		return true, job.Run(ctx)
+	}
+	return true, s.runs.Record(ctx, job.Name, "", nil, job.Run)
+}
@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_RunSlot(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 16, 10, 42, 0, 0, time.UTC)
+	slot := time.Date(2025, 6, 16, 10, 0, 0, 0, time.UTC) // Start of the hour-long slot
+
+	runs := 0
+	job := Job{Name: "digest", Interval: time.Hour, Run: func(context.Context) error {
+		runs++
+		return nil
+	}}
+
+	t.Run("runs when this replica wins the slot", func(t *testing.T) {
+		locker := mocks.NewLocker(t)
+		locker.On("Acquire", ctx, fmt.Sprintf("job:digest:%d", slot.Unix()), time.Hour, time.Duration(0)).Return(func() {}, nil)
+
+		ran, err := NewScheduler(locker, clock.NewFake(now), nil).RunSlot(ctx, job)
+
+		require.NoError(t, err)
+		assert.True(t, ran)
+		assert.Equal(t, 1, runs)
+	})
+
+	t.Run("skips a slot another replica took", func(t *testing.T) {
+		locker := mocks.NewLocker(t)
+		locker.On("Acquire", ctx, fmt.Sprintf("job:digest:%d", slot.Unix()), time.Hour, time.Duration(0)).Return(nil, domain.ErrLockNotAcquired)
+
+		ran, err := NewScheduler(locker, clock.NewFake(now), nil).RunSlot(ctx, job)
+
+		require.NoError(t, err)
+		assert.False(t, ran)
+		assert.Equal(t, 1, runs)
+	})
+}
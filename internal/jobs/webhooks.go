@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// WebhookDeliverer posts the webhook deliveries that are due
+type WebhookDeliverer interface {
+	DeliverDue(ctx context.Context) (delivered int, err error)
+}
+
+// WebhookDeliveries posts new and retried deliveries every minute
+func WebhookDeliveries(deliverer WebhookDeliverer) Job {
+	return Job{
+		Name:     "webhook-deliveries",
+		Interval: time.Minute,
+		Run: func(ctx context.Context) error {
+			delivered, err := deliverer.DeliverDue(ctx)
+			if delivered > 0 {
+				log.Printf("Delivered %d webhook events", delivered)
+			}
+			return err
+		},
+	}
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"mime"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/response"
+)
+
+// BodyRule sets the body limit and accepted content types of one route.
+// Path uses Fiber syntax; ":param" segments match any single segment.
+type BodyRule struct {
+	Method       string
+	Path         string
+	MaxBytes     int
+	ContentTypes []string
+}
+
+// BodyGuardConfig configures BodyGuard
+type BodyGuardConfig struct {
+	// Defaults for every route without a rule: a small limit and JSON only
+	MaxBytes     int
+	ContentTypes []string
+	Rules        []BodyRule
+}
+
+// BodyGuard enforces per-route body limits and content types. The server-wide BodyLimit has
+// to fit the largest upload; this keeps ordinary API routes from accepting bodies that size.
+// It runs before routing, so rules are matched on the request path.
+func BodyGuard(cfg BodyGuardConfig) fiber.Handler {
+	if len(cfg.ContentTypes) == 0 {
+		cfg.ContentTypes = []string{fiber.MIMEApplicationJSON}
+	}
+	rules := make([]compiledBodyRule, len(cfg.Rules))
+	for i, r := range cfg.Rules {
+		rules[i] = compiledBodyRule{BodyRule: r, segments: splitPath(r.Path)}
+	}
+
+	return func(c *fiber.Ctx) error {
+		maxBytes, types := cfg.MaxBytes, cfg.ContentTypes
+		segments := splitPath(c.Path())
+		for _, r := range rules {
+			if r.Method == c.Method() && r.matches(segments) {
+				maxBytes, types = r.MaxBytes, r.ContentTypes
+				break
+			}
+		}
+
+		size := max(c.Request().Header.ContentLength(), len(c.Request().Body()))
+		if size <= 0 {
+			return c.Next()
+		}
+		if maxBytes > 0 && size > maxBytes {
+			return response.Error(c, fiber.StatusRequestEntityTooLarge, "Request body is too large")
+		}
+
+		mediaType, _, err := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+		if err != nil || !containsFold(types, mediaType) {
+			return response.Error(c, fiber.StatusUnsupportedMediaType, "Unsupported content type, expected "+strings.Join(types, " or "))
+		}
+		return c.Next()
+	}
+}
+
+type compiledBodyRule struct {
+	BodyRule
+	segments []string
+}
+
+func (r compiledBodyRule) matches(path []string) bool {
+	if len(path) != len(r.segments) {
+		return false
+	}
+	for i, seg := range r.segments {
+		if !strings.HasPrefix(seg, ":") && seg != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func splitPath(p string) []string {
+	return strings.Split(strings.Trim(p, "/"), "/")
+}
+
+func containsFold(values []string, v string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, v) {
+			return true
+		}
+	}
+	return false
+}
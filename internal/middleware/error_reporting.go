@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"errors"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+const panicStackKey = "panic_stack"
+
+// CapturePanicStack is a recover.Config StackTraceHandler that keeps the panicking
+// goroutine's stack for ReportErrors
+func CapturePanicStack(c *fiber.Ctx, _ interface{}) {
+	c.Locals(panicStackKey, string(debug.Stack()))
+}
+
+// ReportErrors sends 5xx responses and recovered panics to the error reporter, tagged with
+// the correlation ID, route, tenant and role. It must be registered before recover so the
+// panic has been turned into an error by the time it gets here.
+func ReportErrors(reporter domain.ErrorReporter) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			// The app's error handler hasn't written the response yet
+			status = fiber.StatusInternalServerError
+			var fe *fiber.Error
+			if errors.As(err, &fe) {
+				status = fe.Code
+			} else if entry, ok := domain.LookupError(err); ok {
+				status = entry.Status
+			}
+		}
+		if status < fiber.StatusInternalServerError {
+			return err
+		}
+
+		report := &domain.ErrorReport{
+			Err:        err,
+			Method:     c.Method(),
+			Path:       c.Path(),
+			StatusCode: status,
+			Tags:       errorTags(c),
+			OccurredAt: time.Now(),
+		}
+		if stack, ok := c.Locals(panicStackKey).(string); ok {
+			report.Panic = true
+			report.Stack = stack
+		}
+		if report.Err == nil {
+			// Handlers usually answer 5xx themselves; the body carries the message
+			report.Err = errors.New(string(c.Response().Body()))
+		}
+		reporter.Report(c.UserContext(), report)
+		return err
+	}
+}
+
+func errorTags(c *fiber.Ctx) map[string]string {
+	tags := map[string]string{"route": c.Route().Path}
+	if id := c.Get("X-Correlation-ID"); id != "" {
+		tags["correlation_id"] = id
+	}
+	if tenantID, ok := c.Locals(TenantIDKey).(string); ok && tenantID != "" {
+		tags["tenant_id"] = tenantID
+	}
+	if roles, ok := c.Locals(RolesKey).([]string); ok && len(roles) > 0 {
+		tags["role"] = strings.Join(roles, ",")
+	}
+	return tags
+}
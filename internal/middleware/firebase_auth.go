@@ -8,6 +8,7 @@ import (
 
 	firebase "firebase.google.com/go/v4"
 	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/response"
 	"google.golang.org/api/option"
 )
 
@@ -27,19 +28,13 @@ func FirebaseAuth(firebaseApp *firebase.App) fiber.Handler {
 		// Extract token from Authorization header
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"success": false,
-				"error":   "missing authorization header",
-			})
+			return response.Error(c, fiber.StatusUnauthorized, "missing authorization header")
 		}
 
 		// Extract Bearer token
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"success": false,
-				"error":   "invalid authorization header format, expected 'Bearer <token>'",
-			})
+			return response.Error(c, fiber.StatusUnauthorized, "invalid authorization header format, expected 'Bearer <token>'")
 		}
 
 		token := parts[1]
@@ -49,15 +44,9 @@ func FirebaseAuth(firebaseApp *firebase.App) fiber.Handler {
 		if err != nil {
 			// Check for specific error types
 			if strings.Contains(err.Error(), "expired") {
-				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-					"success": false,
-					"error":   "token expired",
-				})
+				return response.Error(c, fiber.StatusUnauthorized, "token expired")
 			}
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"success": false,
-				"error":   "invalid token",
-			})
+			return response.Error(c, fiber.StatusUnauthorized, "invalid token")
 		}
 
 		// Extract UID and attach to context
@@ -2,59 +2,78 @@ package middleware
 
 import (
 	"context"
-	"fmt"
+	"log"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/redis/go-redis/v9"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
 )
 
-// IdempotencyMiddleware provides idempotency for POST/PATCH requests using X-Correlation-ID
-// If the same correlation ID is received within the TTL, it returns the cached response
-func IdempotencyMiddleware(redisClient *redis.Client, ttl time.Duration) fiber.Handler {
+// idempotencyLease is how long a key stays reserved for a request still running: more than
+// the slowest request takes, bounded by the 60s AI call, with a margin. A replica that dies
+// mid-request leaves the key in use only this long rather than for the whole ttl.
+const idempotencyLease = 2 * time.Minute
+
+// Idempotency makes a request sent with an Idempotency-Key safe to retry: the first one is
+// carried out and its successful response stored for ttl; retries with the same key get
+// that response back, marked with an Idempotent-Replayed header. Keys are per user, so it
+// goes after the auth middleware. A retry while the first request is still running gets a
+// 409, and reusing a key for a different request a 422. Failed requests free their key.
+// Requests without the header are passed through, as are all of them while the store is down.
+func Idempotency(store domain.IdempotencyStore, ttl time.Duration) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// Only apply to mutating methods
-		if c.Method() != "POST" && c.Method() != "PATCH" && c.Method() != "PUT" {
+		key := c.Get(domain.IdempotencyKeyHeader)
+		if key == "" {
 			return c.Next()
 		}
-
-		correlationID := c.Get("X-Correlation-ID")
-		if correlationID == "" {
-			// No correlation ID = no idempotency check
-			return c.Next()
+		if !domain.ValidIdempotencyKey(key) {
+			return response.Fail(c, domain.ErrInvalidIdempotencyKey)
 		}
 
-		key := fmt.Sprintf("idempotency:%s", correlationID)
-		ctx := context.Background()
+		userID, _ := c.Locals("userID").(string)
+		key = userID + ":" + key
+		fingerprint := domain.RequestFingerprint(c.Method(), c.Path(), c.Body())
 
-		// Check if we have a cached response
-		cached, err := redisClient.Get(ctx, key).Bytes()
-		if err == nil && len(cached) > 0 {
-			// Return cached response
-			c.Set("X-Idempotent-Replay", "true")
-			c.Set("Content-Type", "application/json")
-			return c.Send(cached)
+		prior, err := store.Begin(c.UserContext(), key, fingerprint, idempotencyLease)
+		if err != nil {
+			log.Printf("Warning: idempotency store unavailable, %s %s not protected: %v", c.Method(), c.Path(), err)
+			return c.Next()
 		}
-
-		// Process the request
-		if err := c.Next(); err != nil {
-			return err
+		if prior != nil {
+			switch {
+			case prior.Fingerprint != fingerprint:
+				return response.Fail(c, domain.ErrIdempotencyKeyReused)
+			case !prior.Completed:
+				return response.Fail(c, domain.ErrIdempotencyKeyInUse)
+			}
+			c.Set("Idempotent-Replayed", "true")
+			c.Set(fiber.HeaderContentType, prior.ContentType)
+			return c.Status(prior.Status).Send(prior.Body)
 		}
 
-		// Cache successful responses (2xx status codes)
-		statusCode := c.Response().StatusCode()
-		if statusCode >= 200 && statusCode < 300 {
-			body := c.Response().Body()
-			if len(body) > 0 {
-				// Cache with TTL (fire and forget)
-				go func() {
-					bgCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-					defer cancel()
-					redisClient.Set(bgCtx, key, body, ttl)
-				}()
+		err = c.Next()
+		// A fresh context: the response is stored even if the client already gave up
+		storeCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		status := c.Response().StatusCode()
+		if err != nil || status < 200 || status >= 300 {
+			if releaseErr := store.Release(storeCtx, key); releaseErr != nil {
+				log.Printf("Warning: failed to release idempotency key after %s %s: %v", c.Method(), c.Path(), releaseErr)
 			}
+			return err
+		}
+		response := &domain.IdempotentResponse{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			Status:      status,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+		}
+		if err := store.Complete(storeCtx, key, response, ttl); err != nil {
+			log.Printf("Warning: failed to store idempotent response of %s %s: %v", c.Method(), c.Path(), err)
 		}
-
 		return nil
 	}
 }
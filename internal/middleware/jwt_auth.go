@@ -1,9 +1,12 @@
 package middleware
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
 )
 
 // Context keys for storing user info
@@ -22,49 +25,67 @@ func VerifyMetamorphToken(jwtSecret string) fiber.Handler {
 		// Get token from Authorization header
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Missing authorization token",
-			})
+			return response.Error(c, fiber.StatusUnauthorized, "Missing authorization token")
 		}
 
-		// Extract token (format: "Bearer <token>")
-		tokenString := authHeader
-		if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-			tokenString = authHeader[7:]
+		claims, err := parseMetamorphToken(authHeader, jwtSecret)
+		if err != nil {
+			return response.FailAs(c, fiber.StatusUnauthorized, err)
 		}
 
-		// Parse and validate token
-		token, err := jwt.ParseWithClaims(tokenString, &domain.MetamorphClaims{}, func(token *jwt.Token) (interface{}, error) {
-			// Verify signing method
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid signing method")
-			}
-			return []byte(jwtSecret), nil
-		})
+		storeClaims(c, claims)
+		return c.Next()
+	}
+}
 
-		if err != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Invalid or expired token",
-			})
+// OptionalMetamorphToken stores the caller's claims when a valid token is sent, for public
+// endpoints that show signed-in users more. Missing or invalid tokens are served anonymously.
+func OptionalMetamorphToken(jwtSecret string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if authHeader := c.Get("Authorization"); authHeader != "" {
+			if claims, err := parseMetamorphToken(authHeader, jwtSecret); err == nil {
+				storeClaims(c, claims)
+			}
 		}
+		return c.Next()
+	}
+}
 
-		// Extract claims
-		claims, ok := token.Claims.(*domain.MetamorphClaims)
-		if !ok || !token.Valid {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Invalid token claims",
-			})
-		}
+// parseMetamorphToken validates an Authorization header's token and returns its claims
+func parseMetamorphToken(authHeader, jwtSecret string) (*domain.MetamorphClaims, error) {
+	// Extract token (format: "Bearer <token>")
+	tokenString := authHeader
+	if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
+		tokenString = authHeader[7:]
+	}
 
-		// Store claims in context
-		c.Locals(UserIDKey, claims.UserID)
-		c.Locals(RolesKey, claims.Roles)
-		c.Locals(TenantIDKey, claims.TenantID)
-		c.Locals(HomeBranchIDKey, claims.HomeBranchID)
-		c.Locals(BranchAccessKey, claims.BranchAccess)
+	// Parse and validate token
+	token, err := jwt.ParseWithClaims(tokenString, &domain.MetamorphClaims{}, func(token *jwt.Token) (interface{}, error) {
+		// Verify signing method
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid signing method")
+		}
+		return []byte(jwtSecret), nil
+	})
+	if err != nil {
+		return nil, errors.New("Invalid or expired token")
+	}
 
-		return c.Next()
+	// Extract claims
+	claims, ok := token.Claims.(*domain.MetamorphClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("Invalid token claims")
 	}
+	return claims, nil
+}
+
+// storeClaims puts the token's claims in the request context
+func storeClaims(c *fiber.Ctx, claims *domain.MetamorphClaims) {
+	c.Locals(UserIDKey, claims.UserID)
+	c.Locals(RolesKey, claims.Roles)
+	c.Locals(TenantIDKey, claims.TenantID)
+	c.Locals(HomeBranchIDKey, claims.HomeBranchID)
+	c.Locals(BranchAccessKey, claims.BranchAccess)
 }
 
 // AuthorizeRole checks if user has at least one of the required roles
@@ -73,16 +94,12 @@ func AuthorizeRole(allowedRoles ...string) fiber.Handler {
 		// Get roles from context
 		rolesInterface := c.Locals(RolesKey)
 		if rolesInterface == nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "No roles found in token",
-			})
+			return response.Error(c, fiber.StatusUnauthorized, "No roles found in token")
 		}
 
 		userRoles, ok := rolesInterface.([]string)
 		if !ok {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Invalid roles format",
-			})
+			return response.Error(c, fiber.StatusInternalServerError, "Invalid roles format")
 		}
 
 		// Check if user has any of the allowed roles
@@ -94,10 +111,7 @@ func AuthorizeRole(allowedRoles ...string) fiber.Handler {
 			}
 		}
 
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error":          "Insufficient permissions",
-			"required_roles": allowedRoles,
-		})
+		return response.ErrorDetails(c, fiber.StatusForbidden, "Insufficient permissions", fiber.Map{"required_roles": allowedRoles})
 	}
 }
 
@@ -109,9 +123,7 @@ func TenantScope() fiber.Handler {
 		// Verify user_id exists (required for all)
 		userID := c.Locals(UserIDKey)
 		if userID == nil || userID == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Missing user context",
-			})
+			return response.Error(c, fiber.StatusUnauthorized, "Missing user context")
 		}
 
 		// Get roles to determine tenant_id requirement
@@ -148,9 +160,7 @@ func TenantScope() fiber.Handler {
 						if isCoach {
 							roleType = "Coach"
 						}
-						return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-							"error": roleType + " must belong to a tenant",
-						})
+						return response.Error(c, fiber.StatusUnauthorized, roleType+" must belong to a tenant")
 					}
 
 					// Coach Specific: Must have HomeBranchID
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/response"
+)
+
+// RateLimitConfig is the limits of one route group. Zero limits aren't enforced.
+type RateLimitConfig struct {
+	Group   string // Keeps the group's buckets apart from other groups', e.g. "auth"
+	PerIP   domain.RateLimit
+	PerUser domain.RateLimit // Goes after the auth middleware; requests without a user only count per IP
+}
+
+// RateLimit answers 429 with a Retry-After header once the client's IP or user has used up
+// the group's requests. Requests are let through while the bucket store is down, so a
+// Redis outage doesn't take the API down with it.
+func RateLimit(bucket domain.TokenBucket, cfg RateLimitConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		buckets := []domain.Bucket{{Key: cfg.Group + ":ip:" + c.IP(), Limit: cfg.PerIP}}
+		if userID, _ := c.Locals("userID").(string); userID != "" {
+			buckets = append(buckets, domain.Bucket{Key: cfg.Group + ":user:" + userID, Limit: cfg.PerUser})
+		}
+
+		// Both buckets are taken from together, so a user refused their own requests doesn't
+		// also drain the IP's bucket for everyone behind the same NAT
+		allowed, retryAfter, err := bucket.Take(c.UserContext(), buckets)
+		if err != nil {
+			log.Printf("Warning: rate limiter unavailable, %s %s not limited: %v", c.Method(), c.Path(), err)
+			return c.Next()
+		}
+		if !allowed {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			return response.Fail(c, domain.ErrRateLimited)
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,4 @@
+// Package mocks holds testify mocks for the interfaces in internal/domain, so services can be
+// unit tested without Mongo or Redis. The files are generated; run `mockery` from the repo root
+// (configured by .mockery.yaml) after changing a domain interface.
+package mocks
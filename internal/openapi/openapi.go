@@ -0,0 +1,292 @@
+// Package openapi generates the OpenAPI 3.0 document of the API. Paths come from the routes
+// registered with Fiber, so every endpoint is listed; operations described with the Go types
+// their handlers read and write get schemas reflected from those types.
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Security schemes operations may require
+const (
+	SecurityBearer   = "bearerAuth"   // Access token issued by /v1/auth/login
+	SecurityFirebase = "firebaseAuth" // Firebase ID token, exchanged for an access token
+	SecurityNone     = "none"         // Public, on a path that otherwise isn't
+)
+
+// Document is an OpenAPI 3.0 document
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Tags       []Tag                `json:"tags,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem holds a path's operations by method
+type PathItem struct {
+	Get     *Operation `json:"get,omitempty"`
+	Put     *Operation `json:"put,omitempty"`
+	Post    *Operation `json:"post,omitempty"`
+	Delete  *Operation `json:"delete,omitempty"`
+	Patch   *Operation `json:"patch,omitempty"`
+	Options *Operation `json:"options,omitempty"`
+}
+
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	OperationID string                `json:"operationId,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // path or query
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// Endpoint describes a route with the types its handler reads and writes. Request and
+// Response are values of those types, e.g. handler.CheckoutRequest{}; nil leaves the body
+// undocumented.
+type Endpoint struct {
+	Method   string // As registered, e.g. fiber.MethodPost
+	Path     string // As registered, e.g. "/v1/tenant-admin/contracts/:id/custom-fields"
+	Summary  string
+	Query    []string // Query parameters it reads
+	Request  interface{}
+	Response interface{}
+	Status   int    // Of the response; 200 if zero
+	Security string // Scheme it requires instead of the default; see Config.Public
+}
+
+// Config is what the document is generated from besides the routes
+type Config struct {
+	Title       string
+	Description string
+	Version     string
+	Public      []string // Path prefixes reachable without an access token
+	Endpoints   []Endpoint
+	Error       interface{} // Body of error responses
+	Envelope    interface{} // Body successful responses wrap their data in, under "data"; nil for none
+}
+
+// Generate builds the document of the routes. Middleware and HEAD routes are left out.
+func Generate(cfg Config, routes []fiber.Route) *Document {
+	schemas := newSchemaSet()
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: cfg.Title, Description: cfg.Description, Version: cfg.Version},
+		Paths:   map[string]*PathItem{},
+		Components: Components{
+			Schemas: schemas.defs,
+			SecuritySchemes: map[string]SecurityScheme{
+				SecurityBearer: {Type: "http", Scheme: "bearer", BearerFormat: "JWT",
+					Description: "Access token from POST /v1/auth/login"},
+				SecurityFirebase: {Type: "http", Scheme: "bearer", BearerFormat: "JWT",
+					Description: "Firebase ID token of the signed-in user"},
+			},
+		},
+	}
+
+	endpoints := make(map[string]Endpoint, len(cfg.Endpoints))
+	for _, e := range cfg.Endpoints {
+		endpoints[e.Method+" "+normalizePath(e.Path)] = e
+	}
+	var errorSchema *Schema
+	if cfg.Error != nil {
+		errorSchema = schemas.of(reflect.TypeOf(cfg.Error))
+	}
+
+	tags := map[string]bool{}
+	for _, route := range routes {
+		path := normalizePath(route.Path)
+		slot := doc.operation(route.Method, path)
+		if slot == nil || *slot != nil {
+			continue // A method that isn't documented, or a route registered twice
+		}
+		endpoint := endpoints[route.Method+" "+path]
+		op := &Operation{
+			Tags:        []string{tagOf(path)},
+			Summary:     endpoint.Summary,
+			OperationID: operationID(route.Method, path),
+			Responses:   map[string]Response{},
+		}
+		tags[op.Tags[0]] = true
+
+		for _, name := range pathParams(path) {
+			op.Parameters = append(op.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+		for _, name := range endpoint.Query {
+			op.Parameters = append(op.Parameters, Parameter{Name: name, In: "query", Schema: &Schema{Type: "string"}})
+		}
+		if endpoint.Request != nil {
+			op.RequestBody = &RequestBody{Content: jsonContent(schemas.of(reflect.TypeOf(endpoint.Request)))}
+		}
+
+		status := http.StatusOK
+		if endpoint.Status != 0 {
+			status = endpoint.Status
+		}
+		response := Response{Description: http.StatusText(status)}
+		if endpoint.Response != nil {
+			schema := schemas.of(reflect.TypeOf(endpoint.Response))
+			if cfg.Envelope != nil {
+				schema = schemas.wrap(reflect.TypeOf(cfg.Envelope), schema)
+			}
+			response.Content = jsonContent(schema)
+		}
+		op.Responses[strconv.Itoa(status)] = response
+		if errorSchema != nil {
+			op.Responses["default"] = Response{Description: "Error", Content: jsonContent(errorSchema)}
+		}
+
+		security := endpoint.Security
+		if security == "" && !isPublic(path, cfg.Public) {
+			security = SecurityBearer
+		}
+		if security != "" && security != SecurityNone {
+			op.Security = []map[string][]string{{security: {}}}
+		}
+		*slot = op
+	}
+
+	for name := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: name})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+	return doc
+}
+
+// operation returns where the operation of the method goes on the path, or nil for methods
+// that aren't documented
+func (d *Document) operation(method, path string) **Operation {
+	item, ok := d.Paths[path]
+	if !ok {
+		item = &PathItem{}
+	}
+	var op **Operation
+	switch method {
+	case fiber.MethodGet:
+		op = &item.Get
+	case fiber.MethodPut:
+		op = &item.Put
+	case fiber.MethodPost:
+		op = &item.Post
+	case fiber.MethodDelete:
+		op = &item.Delete
+	case fiber.MethodPatch:
+		op = &item.Patch
+	case fiber.MethodOptions:
+		op = &item.Options
+	default:
+		return nil
+	}
+	d.Paths[path] = item
+	return op
+}
+
+// normalizePath turns a Fiber route path into an OpenAPI one: "/contracts/:id/" becomes
+// "/contracts/{id}"
+func normalizePath(path string) string {
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			name := strings.TrimSuffix(strings.TrimPrefix(segment, ":"), "?")
+			if j := strings.IndexByte(name, '<'); j >= 0 {
+				name = name[:j] // Constraint, e.g. :id<int>
+			}
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func pathParams(path string) []string {
+	var names []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") {
+			names = append(names, strings.Trim(segment, "{}"))
+		}
+	}
+	return names
+}
+
+// tagOf groups paths by the API they belong to: the segment after the version, e.g.
+// "tenant-admin" for "/v1/tenant-admin/contracts"
+func tagOf(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) > 1 && (segments[0] == "v1" || segments[0] == "api") {
+		return segments[1]
+	}
+	return segments[0]
+}
+
+// operationID names an operation after its method and path, e.g. "post_v1_me_payments_checkout"
+func operationID(method, path string) string {
+	id := strings.NewReplacer("/", "_", "-", "_", "{", "", "}", "").Replace(strings.Trim(path, "/"))
+	return strings.ToLower(method) + "_" + id
+}
+
+func isPublic(path string, public []string) bool {
+	for _, prefix := range public {
+		if path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{fiber.MIMEApplicationJSON: {Schema: schema}}
+}
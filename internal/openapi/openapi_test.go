@@ -0,0 +1,112 @@
+package openapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testMoney struct {
+	Minor    int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type testBase struct {
+	ID string `json:"id"`
+}
+
+type testContract struct {
+	testBase
+	Price     testMoney              `json:"price"`
+	Notes     *string                `json:"notes,omitempty"`
+	ExpiresAt *time.Time             `json:"expires_at,omitempty"`
+	Tags      []string               `json:"tags"`
+	Fields    map[string]interface{} `json:"custom_fields,omitempty"`
+	Sequence  int64                  `json:"-"`
+	Renewal   *testContract          `json:"renewal,omitempty"`
+	internal  string
+}
+
+type testPage[T any] struct {
+	Items []T `json:"items"`
+}
+
+type testEnvelope[T any] struct {
+	Success bool `json:"success"`
+	Data    T    `json:"data"`
+}
+
+func TestGenerate(t *testing.T) {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error { return c.Next() })
+	noop := func(c *fiber.Ctx) error { return nil }
+	app.Get("/health", noop)
+	app.Get("/v1/contracts/", noop)
+	app.Post("/v1/contracts/:id/renew", noop)
+	app.Get("/v1/auth/me", noop)
+
+	doc := Generate(Config{
+		Title:    "Test",
+		Public:   []string{"/health"},
+		Envelope: testEnvelope[interface{}]{},
+		Endpoints: []Endpoint{
+			{Method: fiber.MethodGet, Path: "/v1/contracts", Summary: "Contracts", Query: []string{"cursor"},
+				Response: testPage[*testContract]{}},
+			{Method: fiber.MethodPost, Path: "/v1/contracts/:id/renew", Request: testContract{}, Status: fiber.StatusCreated},
+		},
+	}, app.GetRoutes(true))
+
+	t.Run("lists every route once, with OpenAPI paths", func(t *testing.T) {
+		assert.Len(t, doc.Paths, 4)
+		require.Contains(t, doc.Paths, "/v1/contracts/{id}/renew")
+		assert.Nil(t, doc.Paths["/v1/contracts"].Post)
+	})
+
+	t.Run("describes the documented endpoints", func(t *testing.T) {
+		list := doc.Paths["/v1/contracts"].Get
+		require.NotNil(t, list)
+		assert.Equal(t, "Contracts", list.Summary)
+		assert.Equal(t, "get_v1_contracts", list.OperationID)
+		body := list.Responses["200"].Content[fiber.MIMEApplicationJSON].Schema
+		assert.Equal(t, &Schema{Type: "boolean"}, body.Properties["success"])
+		assert.Equal(t, "#/components/schemas/testPageOfTestContract", body.Properties["data"].Ref)
+		assert.Equal(t, []Parameter{{Name: "cursor", In: "query", Schema: &Schema{Type: "string"}}}, list.Parameters)
+
+		renew := doc.Paths["/v1/contracts/{id}/renew"].Post
+		assert.Equal(t, []Parameter{{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}}}, renew.Parameters)
+		assert.Contains(t, renew.Responses, "201")
+		assert.Nil(t, renew.Responses["201"].Content, "no body documented")
+		assert.Equal(t, "#/components/schemas/testContract", renew.RequestBody.Content[fiber.MIMEApplicationJSON].Schema.Ref)
+	})
+
+	t.Run("requires a token outside the public paths", func(t *testing.T) {
+		assert.Empty(t, doc.Paths["/health"].Get.Security)
+		assert.Equal(t, []map[string][]string{{SecurityBearer: {}}}, doc.Paths["/v1/auth/me"].Get.Security)
+	})
+
+	t.Run("reflects schemas as encoding/json writes them", func(t *testing.T) {
+		contract := doc.Components.Schemas["testContract"]
+		require.NotNil(t, contract)
+		assert.ElementsMatch(t, []string{"id", "price", "tags"}, contract.Required)
+		assert.Equal(t, &Schema{Type: "string"}, contract.Properties["id"])
+		assert.Equal(t, &Schema{Ref: "#/components/schemas/testMoney"}, contract.Properties["price"])
+		assert.Equal(t, &Schema{Type: "string", Nullable: true}, contract.Properties["notes"])
+		assert.Equal(t, &Schema{Type: "string", Format: "date-time", Nullable: true}, contract.Properties["expires_at"])
+		assert.Equal(t, &Schema{Type: "array", Items: &Schema{Type: "string"}}, contract.Properties["tags"])
+		assert.Equal(t, &Schema{Type: "object", AdditionalProperties: &Schema{}}, contract.Properties["custom_fields"])
+		assert.Equal(t, &Schema{Ref: "#/components/schemas/testContract"}, contract.Properties["renewal"])
+		assert.NotContains(t, contract.Properties, "Sequence")
+		assert.NotContains(t, contract.Properties, "internal")
+	})
+}
+
+func TestNormalizePath(t *testing.T) {
+	assert.Equal(t, "/v1/tenant-admin/users", normalizePath("/v1/tenant-admin/users/"))
+	assert.Equal(t, "/v1/schedules/{schedule_id}/exercises/{exercise_id}",
+		normalizePath("/v1/schedules/:schedule_id/exercises/:exercise_id"))
+	assert.Equal(t, "/v1/items/{id}", normalizePath("/v1/items/:id<int>"))
+	assert.Equal(t, "/", normalizePath("/"))
+}
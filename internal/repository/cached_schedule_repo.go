@@ -157,6 +157,47 @@ func (r *CachedScheduleRepository) SoftDelete(ctx context.Context, id string) er
 	return nil
 }
 
+// SetConfirmation saves the member's answer and invalidates caches
+func (r *CachedScheduleRepository) SetConfirmation(ctx context.Context, id string, confirmation *domain.SessionConfirmation) error {
+	if err := r.mongo.SetConfirmation(ctx, id, confirmation); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id)
+	return nil
+}
+
+// MarkCoachAlerted records the coach alert and invalidates caches
+func (r *CachedScheduleRepository) MarkCoachAlerted(ctx context.Context, id string, at time.Time) (bool, error) {
+	marked, err := r.mongo.MarkCoachAlerted(ctx, id, at)
+	if err != nil || !marked {
+		return marked, err
+	}
+	r.invalidate(ctx, id)
+	return true, nil
+}
+
+// Reassign hands the session to another coach and invalidates both coaches' caches
+func (r *CachedScheduleRepository) Reassign(ctx context.Context, id, fromCoachID, toCoachID, coveredFor string) (bool, error) {
+	moved, err := r.mongo.Reassign(ctx, id, fromCoachID, toCoachID, coveredFor)
+	if err != nil || !moved {
+		return moved, err
+	}
+	r.invalidate(ctx, id)
+	_ = r.cache.DeleteByPattern(ctx, fmt.Sprintf("schedule:coach:%s:*", fromCoachID))
+	return true, nil
+}
+
+// invalidate drops the cached copies of a schedule changed by a partial update
+func (r *CachedScheduleRepository) invalidate(ctx context.Context, id string) {
+	_ = r.cache.Delete(ctx, scheduleByIDKeyPrefix+id)
+	if schedule, _ := r.mongo.GetByID(ctx, id); schedule != nil {
+		if schedule.ClientID != "" {
+			_ = r.cache.Delete(ctx, scheduleByClientIDKeyPrefix+schedule.ClientID)
+		}
+		_ = r.cache.DeleteByPattern(ctx, fmt.Sprintf("schedule:coach:%s:*", schedule.CoachID))
+	}
+}
+
 // === Pass-through methods (no caching) ===
 
 func (r *CachedScheduleRepository) GetByCoach(ctx context.Context, coachID string, from, to time.Time) ([]*domain.Schedule, error) {
@@ -171,10 +212,18 @@ func (r *CachedScheduleRepository) GetByMember(ctx context.Context, memberID str
 	return r.mongo.GetByMember(ctx, memberID, from, to)
 }
 
+func (r *CachedScheduleRepository) ListStartingBetween(ctx context.Context, from, to time.Time, statuses []string) ([]*domain.Schedule, error) {
+	return r.mongo.ListStartingBetween(ctx, from, to, statuses)
+}
+
 func (r *CachedScheduleRepository) List(ctx context.Context, tenantID string, filterOpts map[string]interface{}) ([]*domain.Schedule, error) {
 	return r.mongo.List(ctx, tenantID, filterOpts)
 }
 
+func (r *CachedScheduleRepository) ListPage(ctx context.Context, tenantID string, filterOpts map[string]interface{}, page domain.PageQuery) (*domain.Page[*domain.Schedule], error) {
+	return r.mongo.ListPage(ctx, tenantID, filterOpts, page)
+}
+
 func (r *CachedScheduleRepository) CountByContractAndStatus(ctx context.Context, contractID string, statuses []string) (int64, error) {
 	return r.mongo.CountByContractAndStatus(ctx, contractID, statuses)
 }
@@ -190,3 +239,11 @@ func (r *CachedScheduleRepository) GetAttendanceByCoach(ctx context.Context, coa
 func (r *CachedScheduleRepository) GetMemberScheduleStats(ctx context.Context, memberID string) (completed int, cancelled int, noShow int, err error) {
 	return r.mongo.GetMemberScheduleStats(ctx, memberID)
 }
+
+func (r *CachedScheduleRepository) CountByTag(ctx context.Context, tenantID, coachID string, from, to time.Time) ([]domain.ScheduleTagCount, error) {
+	return r.mongo.CountByTag(ctx, tenantID, coachID, from, to)
+}
+
+func (r *CachedScheduleRepository) ListChangedByCoach(ctx context.Context, coachID string, since time.Time) ([]*domain.Schedule, error) {
+	return r.mongo.ListChangedByCoach(ctx, coachID, since)
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/oklog/ulid/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newID returns the _id for a new document.
+// New entities use ULIDs: they sort by creation time and can be generated offline by clients.
+func newID() string {
+	return ulid.MustNew(ulid.Timestamp(time.Now()), rand.Reader).String()
+}
+
+// idValue converts an API ID into the value stored in Mongo.
+// Documents created before the ULID switch keep their ObjectID _id, so hex IDs still resolve.
+func idValue(id string) (interface{}, error) {
+	if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+		return oid, nil
+	}
+	if _, err := ulid.ParseStrict(id); err != nil {
+		return nil, domain.ErrInvalidID
+	}
+	return id, nil
+}
+
+// idValues converts a list of IDs for an $in filter, skipping invalid ones
+func idValues(ids []string) []interface{} {
+	values := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		if v, err := idValue(id); err == nil {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// idString is the inverse of idValue for documents decoded into bson.M
+func idString(v interface{}) string {
+	switch id := v.(type) {
+	case primitive.ObjectID:
+		return id.Hex()
+	case string:
+		return id
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// moneyCollection opens collections whose documents decode into domain.Money, so amounts
+// stored as bare numbers before `metamorph migrate money` still read
+var moneyCollection = options.Collection().SetRegistry(newMoneyRegistry())
+
+func newMoneyRegistry() *bsoncodec.Registry {
+	reg := bson.NewRegistry()
+	reg.RegisterTypeDecoder(reflect.TypeOf(domain.Money{}), bsoncodec.ValueDecoderFunc(decodeMoney))
+	return reg
+}
+
+// decodeMoney reads a {amount, currency} document, or a legacy number in major units of
+// domain.DefaultCurrency
+func decodeMoney(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	var m domain.Money
+	switch vr.Type() {
+	case bsontype.Double:
+		f, err := vr.ReadDouble()
+		if err != nil {
+			return err
+		}
+		m = domain.MoneyFromMajor(f, domain.DefaultCurrency)
+	case bsontype.Int32:
+		i, err := vr.ReadInt32()
+		if err != nil {
+			return err
+		}
+		m = domain.MoneyFromMajor(float64(i), domain.DefaultCurrency)
+	case bsontype.Int64:
+		i, err := vr.ReadInt64()
+		if err != nil {
+			return err
+		}
+		m = domain.MoneyFromMajor(float64(i), domain.DefaultCurrency)
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+	case bsontype.EmbeddedDocument:
+		type money domain.Money // Without this decoder
+		dec, err := dc.LookupDecoder(reflect.TypeOf(money{}))
+		if err != nil {
+			return err
+		}
+		var v money
+		if err := dec.DecodeValue(dc, vr, reflect.ValueOf(&v).Elem()); err != nil {
+			return err
+		}
+		m = domain.NewMoney(v.Minor, v.Currency)
+	default:
+		return fmt.Errorf("cannot decode %s into an amount", vr.Type())
+	}
+	val.Set(reflect.ValueOf(m))
+	return nil
+}
+
+// moneyValue reads an amount from a document decoded into bson.M, as decodeMoney does
+func moneyValue(raw any) domain.Money {
+	switch v := raw.(type) {
+	case float64:
+		return domain.MoneyFromMajor(v, domain.DefaultCurrency)
+	case int32:
+		return domain.MoneyFromMajor(float64(v), domain.DefaultCurrency)
+	case int64:
+		return domain.MoneyFromMajor(float64(v), domain.DefaultCurrency)
+	case primitive.M:
+		currency, _ := v["currency"].(string)
+		switch minor := v["amount"].(type) {
+		case int64:
+			return domain.NewMoney(minor, currency)
+		case int32:
+			return domain.NewMoney(int64(minor), currency)
+		}
+		return domain.NewMoney(0, currency)
+	case primitive.D:
+		m := primitive.M{}
+		for _, e := range v {
+			m[e.Key] = e.Value
+		}
+		return moneyValue(m)
+	}
+	return domain.Money{}
+}
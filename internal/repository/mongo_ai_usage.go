@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoAIUsageRepository keeps one counter document per tenant and month
+type MongoAIUsageRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoAIUsageRepository(db *mongo.Database) *MongoAIUsageRepository {
+	return &MongoAIUsageRepository{collection: db.Collection("ai_usage")}
+}
+
+// Consume increments the month's counter only while it is below limit. When the counter
+// is already full the filter misses and the upsert collides with the existing document,
+// which is how an exhausted quota shows up.
+func (r *MongoAIUsageRepository) Consume(ctx context.Context, tenantID string, at time.Time, limit int) error {
+	month := at.UTC().Format("2006-01")
+	filter := bson.M{"_id": tenantID + ":" + month}
+	if limit > 0 {
+		filter["count"] = bson.M{"$lt": limit}
+	}
+	update := bson.M{
+		"$inc":         bson.M{"count": 1},
+		"$set":         bson.M{"updated_at": time.Now()},
+		"$setOnInsert": bson.M{"tenant_id": tenantID, "month": month},
+	}
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrAIQuotaExceeded
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record AI usage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoAssessmentRepository implements domain.AssessmentRepository
+type MongoAssessmentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoAssessmentRepository(db *mongo.Database) *MongoAssessmentRepository {
+	coll := db.Collection("assessments")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "member_id", Value: 1}, {Key: "taken_at", Value: -1}}},
+		{
+			// One assessment per session; ad-hoc assessments have no schedule
+			Keys:    bson.D{{Key: "schedule_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"schedule_id": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create assessments indexes: %v\n", err)
+	}
+
+	return &MongoAssessmentRepository{collection: coll}
+}
+
+func (r *MongoAssessmentRepository) Create(ctx context.Context, a *domain.Assessment) error {
+	a.ID = newID()
+	if _, err := r.collection.InsertOne(ctx, a); err != nil {
+		return fmt.Errorf("failed to create assessment: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoAssessmentRepository) Update(ctx context.Context, a *domain.Assessment) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": a.ID, "tenant_id": a.TenantID}, a)
+	if err != nil {
+		return fmt.Errorf("failed to update assessment: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoAssessmentRepository) GetBySchedule(ctx context.Context, tenantID, scheduleID string) (*domain.Assessment, error) {
+	var a domain.Assessment
+	err := r.collection.FindOne(ctx, bson.M{"tenant_id": tenantID, "schedule_id": scheduleID}).Decode(&a)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find assessment: %w", err)
+	}
+	return &a, nil
+}
+
+func (r *MongoAssessmentRepository) ListByMember(ctx context.Context, tenantID, memberID string, limit int) ([]*domain.Assessment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "taken_at", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, bson.M{"tenant_id": tenantID, "member_id": memberID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list assessments: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	assessments := []*domain.Assessment{}
+	if err := cursor.All(ctx, &assessments); err != nil {
+		return nil, err
+	}
+	return assessments, nil
+}
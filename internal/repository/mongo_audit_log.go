@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoAuditLogRepository implements domain.AuditLogRepository
+type MongoAuditLogRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoAuditLogRepository(db *mongo.Database) *MongoAuditLogRepository {
+	coll := db.Collection("audit_logs")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "actor_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "entity_type", Value: 1}, {Key: "entity_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create audit_logs indexes: %v\n", err)
+	}
+
+	return &MongoAuditLogRepository{collection: coll}
+}
+
+func (r *MongoAuditLogRepository) Record(ctx context.Context, log *domain.AuditLog) error {
+	log.ID = newID()
+	if _, err := r.collection.InsertOne(ctx, log); err != nil {
+		return fmt.Errorf("failed to record audit log: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoAuditLogRepository) List(ctx context.Context, q domain.AuditLogQuery) (*domain.Page[*domain.AuditLog], error) {
+	q, err := q.Normalized()
+	if err != nil {
+		return nil, err
+	}
+
+	base := bson.M{}
+	if q.TenantID != "" {
+		base["tenant_id"] = q.TenantID
+	}
+	if q.ActorID != "" {
+		base["actor_id"] = q.ActorID
+	}
+	if q.EntityType != "" {
+		base["entity_type"] = q.EntityType
+	}
+	if q.EntityID != "" {
+		base["entity_id"] = q.EntityID
+	}
+	if q.From != nil || q.To != nil {
+		createdAt := bson.M{}
+		if q.From != nil {
+			createdAt["$gte"] = *q.From
+		}
+		if q.To != nil {
+			createdAt["$lt"] = *q.To
+		}
+		base["created_at"] = createdAt
+	}
+
+	filter, err := pageFilter(base, q.Page.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := r.collection.Find(ctx, filter, pageOptions(q.Page))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var logs []*domain.AuditLog
+	if err := cursor.All(ctx, &logs); err != nil {
+		return nil, err
+	}
+	return newPage(logs, q.Page, func(l *domain.AuditLog) (time.Time, string) { return l.CreatedAt, l.ID }), nil
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const changeFeedMaxBatch = 500
+
+// MongoChangeFeed implements domain.ChangeFeed with a database change stream.
+// The resume token of the last handled batch is stored in change_stream_checkpoints under
+// the feed's name, so each consumer resumes where it stopped. Requires a replica set.
+type MongoChangeFeed struct {
+	db          *mongo.Database
+	name        string
+	checkpoints *mongo.Collection
+}
+
+func NewMongoChangeFeed(db *mongo.Database, name string) *MongoChangeFeed {
+	return &MongoChangeFeed{
+		db:          db,
+		name:        name,
+		checkpoints: db.Collection("change_stream_checkpoints"),
+	}
+}
+
+type changeStreamEvent struct {
+	OperationType string `bson:"operationType"`
+	NS            struct {
+		Coll string `bson:"coll"`
+	} `bson:"ns"`
+	DocumentKey struct {
+		ID interface{} `bson:"_id"`
+	} `bson:"documentKey"`
+	FullDocument bson.M              `bson:"fullDocument"`
+	ClusterTime  primitive.Timestamp `bson:"clusterTime"`
+}
+
+func (f *MongoChangeFeed) Watch(ctx context.Context, collections []string, handle func(ctx context.Context, batch []*domain.ChangeEvent) error) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"ns.coll":       bson.M{"$in": collections},
+			"operationType": bson.M{"$in": []string{"insert", "update", "replace", "delete"}},
+		}}},
+	}
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+
+	token, err := f.loadResumeToken(ctx)
+	if err != nil {
+		return err
+	}
+	if token != nil {
+		opts.SetResumeAfter(token)
+	}
+
+	stream, err := f.db.Watch(ctx, pipeline, opts)
+	if err != nil {
+		return fmt.Errorf("failed to open change stream: %w", err)
+	}
+	defer stream.Close(ctx)
+
+	for stream.Next(ctx) {
+		var batch []*domain.ChangeEvent
+		for {
+			var raw changeStreamEvent
+			if err := stream.Decode(&raw); err != nil {
+				return fmt.Errorf("failed to decode change event: %w", err)
+			}
+			batch = append(batch, toChangeEvent(&raw))
+
+			// Drain what the server already sent, without waiting for more
+			if len(batch) >= changeFeedMaxBatch || stream.RemainingBatchLength() == 0 || !stream.Next(ctx) {
+				break
+			}
+		}
+
+		if err := handle(ctx, batch); err != nil {
+			return err
+		}
+		if err := f.saveResumeToken(ctx, stream.ResumeToken()); err != nil {
+			return err
+		}
+	}
+	if err := stream.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("change stream failed: %w", err)
+	}
+	return ctx.Err()
+}
+
+func (f *MongoChangeFeed) loadResumeToken(ctx context.Context) (bson.Raw, error) {
+	var doc struct {
+		Token bson.Raw `bson:"token"`
+	}
+	err := f.checkpoints.FindOne(ctx, bson.M{"_id": f.name}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load change stream checkpoint: %w", err)
+	}
+	return doc.Token, nil
+}
+
+func (f *MongoChangeFeed) saveResumeToken(ctx context.Context, token bson.Raw) error {
+	_, err := f.checkpoints.UpdateOne(ctx,
+		bson.M{"_id": f.name},
+		bson.M{"$set": bson.M{"token": token, "updated_at": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save change stream checkpoint: %w", err)
+	}
+	return nil
+}
+
+func toChangeEvent(raw *changeStreamEvent) *domain.ChangeEvent {
+	event := &domain.ChangeEvent{
+		Collection: raw.NS.Coll,
+		DocumentID: idString(raw.DocumentKey.ID),
+		OccurredAt: time.Unix(int64(raw.ClusterTime.T), 0),
+	}
+	switch raw.OperationType {
+	case "insert":
+		event.Op = domain.ChangeOpInsert
+	case "delete":
+		event.Op = domain.ChangeOpDelete
+	default:
+		event.Op = domain.ChangeOpUpdate
+	}
+	if raw.FullDocument != nil {
+		event.Document = plainValue(raw.FullDocument).(map[string]interface{})
+	}
+	return event
+}
+
+// plainValue converts decoded BSON into plain Go values (strings, numbers, time.Time,
+// maps and slices) that any sink can serialize
+func plainValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case bson.M:
+		m := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			m[k] = plainValue(item)
+		}
+		return m
+	case bson.D:
+		m := make(map[string]interface{}, len(val))
+		for _, e := range val {
+			m[e.Key] = plainValue(e.Value)
+		}
+		return m
+	case bson.A:
+		s := make([]interface{}, len(val))
+		for i, item := range val {
+			s[i] = plainValue(item)
+		}
+		return s
+	case primitive.ObjectID:
+		return val.Hex()
+	case primitive.DateTime:
+		return val.Time().UTC()
+	case primitive.Decimal128:
+		return val.String()
+	case primitive.Timestamp:
+		return time.Unix(int64(val.T), 0).UTC()
+	}
+	return v
+}
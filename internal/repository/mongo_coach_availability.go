@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoCoachAvailabilityRepository stores one document per coach, keyed by the coach ID
+type MongoCoachAvailabilityRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoCoachAvailabilityRepository(db *mongo.Database) *MongoCoachAvailabilityRepository {
+	return &MongoCoachAvailabilityRepository{
+		collection: db.Collection("coach_availability"),
+	}
+}
+
+func (r *MongoCoachAvailabilityRepository) Get(ctx context.Context, coachID string) (*domain.CoachAvailability, error) {
+	var availability domain.CoachAvailability
+	err := r.collection.FindOne(ctx, bson.M{"_id": coachID}).Decode(&availability)
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get coach availability: %w", err)
+	}
+	return &availability, nil
+}
+
+func (r *MongoCoachAvailabilityRepository) Upsert(ctx context.Context, availability *domain.CoachAvailability) error {
+	availability.UpdatedAt = time.Now()
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": availability.CoachID}, availability, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save coach availability: %w", err)
+	}
+	return nil
+}
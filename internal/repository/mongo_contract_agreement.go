@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoContractAgreementRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoContractAgreementRepository(db *mongo.Database) *MongoContractAgreementRepository {
+	collection := db.Collection("contract_agreements")
+
+	// One agreement per contract
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "contract_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create index on contract_agreements.contract_id: %v\n", err)
+	}
+
+	return &MongoContractAgreementRepository{
+		collection: collection,
+	}
+}
+
+func (r *MongoContractAgreementRepository) Create(ctx context.Context, agreement *domain.ContractAgreement) error {
+	agreement.CreatedAt = time.Now()
+	agreement.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, agreement)
+	if err != nil {
+		return fmt.Errorf("failed to create contract agreement: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		agreement.ID = oid.Hex()
+	}
+	return nil
+}
+
+func (r *MongoContractAgreementRepository) GetByContractID(ctx context.Context, contractID string) (*domain.ContractAgreement, error) {
+	var agreement domain.ContractAgreement
+	err := r.collection.FindOne(ctx, bson.M{"contract_id": contractID}).Decode(&agreement)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrAgreementNotFound
+		}
+		return nil, err
+	}
+	return &agreement, nil
+}
+
+func (r *MongoContractAgreementRepository) MarkSigned(ctx context.Context, agreement *domain.ContractAgreement) error {
+	oid, err := primitive.ObjectIDFromHex(agreement.ID)
+	if err != nil {
+		return domain.ErrInvalidID
+	}
+	agreement.UpdatedAt = time.Now()
+
+	// Only a pending agreement is signed, so of two signatures sent at once the first is kept
+	update := bson.M{
+		"$set": bson.M{
+			"signed_document_url": agreement.SignedDocumentURL,
+			"status":              domain.AgreementStatusSigned,
+			"signature":           agreement.Signature,
+			"updated_at":          agreement.UpdatedAt,
+		},
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid, "status": domain.AgreementStatusPending}, update)
+	if err != nil {
+		return fmt.Errorf("failed to sign contract agreement: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrAgreementAlreadySigned
+	}
+	agreement.Status = domain.AgreementStatusSigned
+	return nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxLedgerAppendAttempts bounds retries when concurrent appends race for the same sequence
+const maxLedgerAppendAttempts = 5
+
+type MongoCreditTransactionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoCreditTransactionRepository(db *mongo.Database) *MongoCreditTransactionRepository {
+	collection := db.Collection("credit_transactions")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// Serializes appends per contract: two writers can't claim the same sequence
+			Keys:    bson.D{{Key: "contract_id", Value: 1}, {Key: "sequence", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Prevents double-consumption of the same schedule (e.g. double-tapped "Complete")
+			Keys: bson.D{{Key: "idempotency_key", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+				"idempotency_key": bson.M{"$exists": true},
+			}),
+		},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create indexes on credit_transactions: %v\n", err)
+	}
+
+	return &MongoCreditTransactionRepository{
+		collection: collection,
+	}
+}
+
+func (r *MongoCreditTransactionRepository) Append(ctx context.Context, txn *domain.CreditTransaction) error {
+	if txn.CreatedAt.IsZero() {
+		txn.CreatedAt = time.Now()
+	}
+
+	for attempt := 0; attempt < maxLedgerAppendAttempts; attempt++ {
+		latest, err := r.GetLatest(ctx, txn.ContractID)
+		if err != nil {
+			return err
+		}
+
+		var balance int
+		var sequence int64
+		if latest != nil {
+			balance = latest.BalanceAfter
+			sequence = latest.Sequence
+		}
+		if balance+txn.Amount < 0 {
+			return domain.ErrInsufficientCredits
+		}
+
+		txn.Sequence = sequence + 1
+		txn.BalanceAfter = balance + txn.Amount
+
+		result, err := r.collection.InsertOne(ctx, txn)
+		if err == nil {
+			if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+				txn.ID = oid.Hex()
+			}
+			return nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("failed to append credit transaction: %w", err)
+		}
+
+		// Either the idempotency key was already used, or another writer took this sequence
+		if txn.IdempotencyKey != "" {
+			count, countErr := r.collection.CountDocuments(ctx, bson.M{"idempotency_key": txn.IdempotencyKey})
+			if countErr == nil && count > 0 {
+				return domain.ErrDuplicateCredit
+			}
+		}
+	}
+
+	return fmt.Errorf("failed to append credit transaction for contract %s: too many concurrent writes", txn.ContractID)
+}
+
+func (r *MongoCreditTransactionRepository) GetLatest(ctx context.Context, contractID string) (*domain.CreditTransaction, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "sequence", Value: -1}})
+
+	var txn domain.CreditTransaction
+	err := r.collection.FindOne(ctx, bson.M{"contract_id": contractID}, opts).Decode(&txn)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &txn, nil
+}
+
+func (r *MongoCreditTransactionRepository) GetByContract(ctx context.Context, contractID string) ([]*domain.CreditTransaction, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "sequence", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"contract_id": contractID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var txns []*domain.CreditTransaction
+	if err := cursor.All(ctx, &txns); err != nil {
+		return nil, err
+	}
+	return txns, nil
+}
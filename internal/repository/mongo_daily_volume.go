@@ -9,18 +9,28 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 type MongoDailyVolumeRepository struct {
 	collection *mongo.Collection
+	analytics  *mongo.Collection
 }
 
 func NewMongoDailyVolumeRepository(db *mongo.Database) *MongoDailyVolumeRepository {
+	coll := db.Collection("daily_volumes")
 	return &MongoDailyVolumeRepository{
-		collection: db.Collection("daily_volumes"),
+		collection: coll,
+		analytics:  coll,
 	}
 }
 
+// RouteAnalyticsReads sends the member volume history queries to pref.
+// GetByScheduleID stays on the primary because aggregation reads it right after writing.
+func (r *MongoDailyVolumeRepository) RouteAnalyticsReads(pref *readpref.ReadPref) {
+	r.analytics = withReadPreference(r.collection, pref)
+}
+
 func (r *MongoDailyVolumeRepository) Create(ctx context.Context, volume *domain.DailyVolume) error {
 	volume.CreatedAt = time.Now()
 	result, err := r.collection.InsertOne(ctx, volume)
@@ -51,7 +61,7 @@ func (r *MongoDailyVolumeRepository) GetByMemberID(ctx context.Context, memberID
 		opts.SetLimit(int64(limit))
 	}
 
-	cursor, err := r.collection.Find(ctx, bson.M{"member_id": memberID}, opts)
+	cursor, err := r.analytics.Find(ctx, bson.M{"member_id": memberID}, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +84,7 @@ func (r *MongoDailyVolumeRepository) GetByMemberIDAndDateRange(ctx context.Conte
 	}
 	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}})
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	cursor, err := r.analytics.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +109,7 @@ func (r *MongoDailyVolumeRepository) GetByMemberIDAndFocusArea(ctx context.Conte
 		opts.SetLimit(int64(limit))
 	}
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	cursor, err := r.analytics.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoDashboardLayoutRepository implements domain.DashboardLayoutRepository. One document
+// per tenant and user, keyed by both, so an admin of several tenants keeps a layout in each.
+type MongoDashboardLayoutRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoDashboardLayoutRepository(db *mongo.Database) *MongoDashboardLayoutRepository {
+	return &MongoDashboardLayoutRepository{collection: db.Collection("dashboard_layouts")}
+}
+
+func dashboardLayoutID(tenantID, userID string) string {
+	return tenantID + ":" + userID
+}
+
+func (r *MongoDashboardLayoutRepository) Get(ctx context.Context, tenantID, userID string) (*domain.DashboardLayout, error) {
+	var layout domain.DashboardLayout
+	err := r.collection.FindOne(ctx, bson.M{"_id": dashboardLayoutID(tenantID, userID)}).Decode(&layout)
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dashboard layout: %w", err)
+	}
+	return &layout, nil
+}
+
+func (r *MongoDashboardLayoutRepository) Upsert(ctx context.Context, layout *domain.DashboardLayout) error {
+	layout.ID = dashboardLayoutID(layout.TenantID, layout.UserID)
+	layout.UpdatedAt = time.Now()
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": layout.ID}, layout, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save dashboard layout: %w", err)
+	}
+	return nil
+}
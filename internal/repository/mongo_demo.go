@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const demoInsertBatchSize = 1000
+
+// demoCollections lists every collection the demo generator writes to
+var demoCollections = []string{
+	"branches",
+	"users",
+	"pt_packages",
+	"pt_contracts",
+	"credit_transactions",
+	"schedules",
+	"workout_sessions",
+	"planned_exercises",
+	"set_logs",
+	"daily_volumes",
+	"personal_bests",
+	collectionName, // inbody_records
+	"invoices",
+}
+
+// MongoDemoDataRepository implements domain.DemoDataRepository. Every document it writes
+// carries demo: true and demo_tenant_id, which is also what DeleteAll matches on.
+type MongoDemoDataRepository struct {
+	db *mongo.Database
+}
+
+func NewMongoDemoDataRepository(db *mongo.Database) *MongoDemoDataRepository {
+	return &MongoDemoDataRepository{
+		db: db,
+	}
+}
+
+func (r *MongoDemoDataRepository) Save(ctx context.Context, tenantID string, dataset *domain.DemoDataset) error {
+	writes := []struct {
+		collection string
+		docs       interface{}
+	}{
+		{"branches", dataset.Branches},
+		{"users", dataset.Users},
+		{"pt_packages", dataset.Packages},
+		{"pt_contracts", dataset.Contracts},
+		{"credit_transactions", dataset.Credits},
+		{"schedules", dataset.Schedules},
+		{"workout_sessions", dataset.WorkoutSessions},
+		{"planned_exercises", dataset.PlannedExercises},
+		{"set_logs", dataset.SetLogs},
+		{"daily_volumes", dataset.DailyVolumes},
+		{"personal_bests", dataset.PersonalBests},
+		{collectionName, dataset.Scans},
+		{"invoices", dataset.Invoices},
+	}
+
+	for _, w := range writes {
+		if err := r.insert(ctx, tenantID, w.collection, w.docs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *MongoDemoDataRepository) HasDemoData(ctx context.Context, tenantID string) (bool, error) {
+	count, err := r.db.Collection("users").CountDocuments(ctx, bson.M{"demo_tenant_id": tenantID}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *MongoDemoDataRepository) DeleteAll(ctx context.Context, tenantID string) (int64, error) {
+	var deleted int64
+	for _, name := range demoCollections {
+		result, err := r.db.Collection(name).DeleteMany(ctx, bson.M{"demo_tenant_id": tenantID})
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete demo data from %s: %w", name, err)
+		}
+		deleted += result.DeletedCount
+	}
+	return deleted, nil
+}
+
+// insert tags each record (a slice of domain pointers) and writes it in batches
+func (r *MongoDemoDataRepository) insert(ctx context.Context, tenantID, collection string, records interface{}) error {
+	v := reflect.ValueOf(records)
+	if v.Len() == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		doc, err := demoDocument(v.Index(i).Interface(), tenantID)
+		if err != nil {
+			return fmt.Errorf("failed to encode demo %s: %w", collection, err)
+		}
+		docs = append(docs, doc)
+	}
+
+	col := r.db.Collection(collection)
+	for start := 0; start < len(docs); start += demoInsertBatchSize {
+		end := start + demoInsertBatchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+		if _, err := col.InsertMany(ctx, docs[start:end], options.InsertMany().SetOrdered(false)); err != nil {
+			return fmt.Errorf("failed to insert demo %s: %w", collection, err)
+		}
+	}
+	return nil
+}
+
+// demoDocument encodes a record with the same bson tags the regular repositories use,
+// stores its pre-assigned ID the way the regular repositories would and adds the demo markers.
+func demoDocument(record interface{}, tenantID string) (bson.D, error) {
+	raw, err := bson.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	out := make(bson.D, 0, len(doc)+2)
+	for _, e := range doc {
+		switch e.Key {
+		case "demo", "demo_tenant_id":
+			continue
+		case "_id":
+			if id, ok := e.Value.(string); ok {
+				v, err := idValue(id)
+				if err != nil {
+					return nil, fmt.Errorf("invalid demo id %q: %w", id, err)
+				}
+				e.Value = v
+			}
+		}
+		out = append(out, e)
+	}
+	return append(out, bson.E{Key: "demo", Value: true}, bson.E{Key: "demo_tenant_id", Value: tenantID}), nil
+}
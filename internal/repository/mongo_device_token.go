@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoDeviceTokenRepository implements domain.DeviceTokenRepository, keyed by the token
+// itself so a device is only ever registered to one user
+type MongoDeviceTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoDeviceTokenRepository(db *mongo.Database) *MongoDeviceTokenRepository {
+	coll := db.Collection("device_tokens")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "last_seen_at", Value: -1}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create device_tokens indexes: %v\n", err)
+	}
+
+	return &MongoDeviceTokenRepository{collection: coll}
+}
+
+func (r *MongoDeviceTokenRepository) Register(ctx context.Context, token *domain.DeviceToken) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": token.Token},
+		bson.M{
+			"$set":         bson.M{"user_id": token.UserID, "platform": token.Platform, "last_seen_at": token.LastSeenAt},
+			"$setOnInsert": bson.M{"created_at": token.CreatedAt},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register device token: %w", err)
+	}
+
+	// Drop the tokens of devices beyond the most recent few
+	opts := options.Find().SetSort(bson.D{{Key: "last_seen_at", Value: -1}}).
+		SetSkip(domain.MaxDeviceTokensPerUser).SetProjection(bson.M{"_id": 1})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": token.UserID}, opts)
+	if err != nil {
+		return fmt.Errorf("failed to list device tokens: %w", err)
+	}
+	var stale []struct {
+		Token string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &stale); err != nil {
+		return err
+	}
+	tokens := make([]string, len(stale))
+	for i, s := range stale {
+		tokens[i] = s.Token
+	}
+	return r.Delete(ctx, tokens)
+}
+
+func (r *MongoDeviceTokenRepository) Unregister(ctx context.Context, userID, token string) error {
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"_id": token, "user_id": userID}); err != nil {
+		return fmt.Errorf("failed to unregister device token: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoDeviceTokenRepository) ListByUser(ctx context.Context, userID string) ([]*domain.DeviceToken, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "last_seen_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list device tokens: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	tokens := []*domain.DeviceToken{}
+	if err := cursor.All(ctx, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (r *MongoDeviceTokenRepository) Delete(ctx context.Context, tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": tokens}}); err != nil {
+		return fmt.Errorf("failed to delete device tokens: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoDocumentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoDocumentRepository(db *mongo.Database) *MongoDocumentRepository {
+	return &MongoDocumentRepository{
+		collection: db.Collection("documents"),
+	}
+}
+
+func (r *MongoDocumentRepository) Create(ctx context.Context, doc *domain.Document) error {
+	doc.CreatedAt = time.Now()
+	doc.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to create document: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		doc.ID = oid.Hex()
+	}
+	return nil
+}
+
+func (r *MongoDocumentRepository) GetByID(ctx context.Context, id string) (*domain.Document, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrInvalidID
+	}
+
+	var doc domain.Document
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrDocumentNotFound
+		}
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (r *MongoDocumentRepository) GetByTenant(ctx context.Context, tenantID string, activeOnly bool) ([]*domain.Document, error) {
+	filter := bson.M{"tenant_id": tenantID}
+	if activeOnly {
+		filter["active"] = true
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []*domain.Document
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func (r *MongoDocumentRepository) Update(ctx context.Context, doc *domain.Document) error {
+	oid, err := primitive.ObjectIDFromHex(doc.ID)
+	if err != nil {
+		return domain.ErrInvalidID
+	}
+	doc.UpdatedAt = time.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"title":          doc.Title,
+			"description":    doc.Description,
+			"file_url":       doc.FileURL,
+			"required":       doc.Required,
+			"blocks_booking": doc.BlocksBooking,
+			"active":         doc.Active,
+			"updated_at":     doc.UpdatedAt,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update document: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrDocumentNotFound
+	}
+	return nil
+}
+
+type MongoDocumentAcceptanceRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoDocumentAcceptanceRepository(db *mongo.Database) *MongoDocumentAcceptanceRepository {
+	collection := db.Collection("document_acceptances")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "member_id", Value: 1}, {Key: "document_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create index on document_acceptances: %v\n", err)
+	}
+
+	return &MongoDocumentAcceptanceRepository{
+		collection: collection,
+	}
+}
+
+func (r *MongoDocumentAcceptanceRepository) Upsert(ctx context.Context, acceptance *domain.DocumentAcceptance) error {
+	filter := bson.M{
+		"member_id":   acceptance.MemberID,
+		"document_id": acceptance.DocumentID,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"tenant_id":   acceptance.TenantID,
+			"typed_name":  acceptance.TypedName,
+			"ip_address":  acceptance.IPAddress,
+			"user_agent":  acceptance.UserAgent,
+			"accepted_at": acceptance.AcceptedAt,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to record document acceptance: %w", err)
+	}
+	if oid, ok := result.UpsertedID.(primitive.ObjectID); ok {
+		acceptance.ID = oid.Hex()
+	}
+	return nil
+}
+
+func (r *MongoDocumentAcceptanceRepository) GetByMember(ctx context.Context, memberID string) ([]*domain.DocumentAcceptance, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"member_id": memberID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var acceptances []*domain.DocumentAcceptance
+	if err := cursor.All(ctx, &acceptances); err != nil {
+		return nil, err
+	}
+	return acceptances, nil
+}
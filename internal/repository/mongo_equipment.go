@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoEquipmentRepository implements domain.EquipmentRepository
+type MongoEquipmentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoEquipmentRepository(db *mongo.Database) *MongoEquipmentRepository {
+	coll := db.Collection("equipment")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// One entry per kind of kit per branch; "barbell" and "Barbell" are the same kind
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "branch_id", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetCollation(&options.Collation{Locale: "en", Strength: 2}),
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create equipment indexes: %v\n", err)
+	}
+
+	return &MongoEquipmentRepository{collection: coll}
+}
+
+func (r *MongoEquipmentRepository) Create(ctx context.Context, item *domain.Equipment) error {
+	item.ID = newID()
+	item.CreatedAt = time.Now()
+	item.UpdatedAt = item.CreatedAt
+	if _, err := r.collection.InsertOne(ctx, item); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrDuplicateEquipment
+		}
+		return fmt.Errorf("failed to create equipment: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoEquipmentRepository) GetByID(ctx context.Context, id string) (*domain.Equipment, error) {
+	var item domain.Equipment
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&item)
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrEquipmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get equipment: %w", err)
+	}
+	return &item, nil
+}
+
+func (r *MongoEquipmentRepository) ListByBranch(ctx context.Context, branchID string) ([]*domain.Equipment, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"branch_id": branchID}, options.Find().SetSort(bson.M{"name": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list equipment: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	items := []*domain.Equipment{}
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *MongoEquipmentRepository) Update(ctx context.Context, item *domain.Equipment) error {
+	item.UpdatedAt = time.Now()
+	update := bson.M{"$set": bson.M{
+		"name":       item.Name,
+		"quantity":   item.Quantity,
+		"available":  item.Available,
+		"updated_at": item.UpdatedAt,
+
+		"out_of_order":       item.OutOfOrder,
+		"out_of_order_since": item.OutOfOrderSince,
+		"last_serviced_at":   item.LastServicedAt,
+	}}
+	result, err := r.collection.UpdateByID(ctx, item.ID, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrDuplicateEquipment
+		}
+		return fmt.Errorf("failed to update equipment: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrEquipmentNotFound
+	}
+	return nil
+}
+
+func (r *MongoEquipmentRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete equipment: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrEquipmentNotFound
+	}
+	return nil
+}
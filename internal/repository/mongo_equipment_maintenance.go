@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoEquipmentMaintenanceRepository implements domain.EquipmentMaintenanceRepository
+type MongoEquipmentMaintenanceRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoEquipmentMaintenanceRepository(db *mongo.Database) *MongoEquipmentMaintenanceRepository {
+	coll := db.Collection("equipment_maintenance")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "equipment_id", Value: 1}, {Key: "logged_at", Value: -1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "logged_at", Value: 1}}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create equipment maintenance indexes: %v\n", err)
+	}
+
+	return &MongoEquipmentMaintenanceRepository{collection: coll}
+}
+
+func (r *MongoEquipmentMaintenanceRepository) Create(ctx context.Context, entry *domain.EquipmentMaintenance) error {
+	entry.ID = newID()
+	if _, err := r.collection.InsertOne(ctx, entry); err != nil {
+		return fmt.Errorf("failed to log equipment maintenance: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoEquipmentMaintenanceRepository) ListByEquipment(ctx context.Context, equipmentID string, limit int) ([]*domain.EquipmentMaintenance, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "logged_at", Value: -1}}).SetLimit(int64(limit))
+	return r.find(ctx, bson.M{"equipment_id": equipmentID}, opts)
+}
+
+func (r *MongoEquipmentMaintenanceRepository) ListByTenant(ctx context.Context, tenantID, branchID string, from, to time.Time) ([]*domain.EquipmentMaintenance, error) {
+	filter := bson.M{"tenant_id": tenantID, "logged_at": bson.M{"$gte": from, "$lt": to}}
+	if branchID != "" {
+		filter["branch_id"] = branchID
+	}
+	return r.find(ctx, filter, options.Find().SetSort(bson.D{{Key: "logged_at", Value: 1}}))
+}
+
+func (r *MongoEquipmentMaintenanceRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*domain.EquipmentMaintenance, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list equipment maintenance: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	entries := []*domain.EquipmentMaintenance{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
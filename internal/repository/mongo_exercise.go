@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/mansoorceksport/metamorph/internal/domain"
@@ -23,9 +24,11 @@ func NewMongoExerciseRepository(db *mongo.Database) *MongoExerciseRepository {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	// Unique index on name
+	// Names are unique per library: among global exercises (no tenant_id) and within each tenant.
+	// This replaces the unique index on name alone, which would stop tenants overriding.
+	coll.Indexes().DropOne(ctx, "name_1")
 	nameMod := mongo.IndexModel{
-		Keys:    bson.M{"name": 1},
+		Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "name", Value: 1}},
 		Options: options.Index().SetUnique(true),
 	}
 	coll.Indexes().CreateOne(ctx, nameMod)
@@ -131,6 +134,23 @@ func (r *MongoExerciseRepository) List(ctx context.Context, filter map[string]in
 	if name, ok := filter["name"].(string); ok && name != "" {
 		query["name"] = bson.M{"$regex": name, "$options": "i"}
 	}
+	if group, ok := filter["muscle_group"].(string); ok && group != "" {
+		query["muscle_group"] = bson.M{"$regex": "^" + regexp.QuoteMeta(group) + "$", "$options": "i"}
+	}
+
+	global := bson.M{"tenant_id": bson.M{"$exists": false}}
+	tenantID, _ := filter["tenant_id"].(string)
+	scope, _ := filter["scope"].(string)
+	switch {
+	case tenantID == "" && scope == domain.ExerciseScopeTenant:
+		return []*domain.Exercise{}, nil
+	case tenantID == "" || scope == domain.ExerciseScopeGlobal:
+		query["tenant_id"] = global["tenant_id"]
+	case scope == domain.ExerciseScopeTenant:
+		query["tenant_id"] = tenantID
+	default:
+		query["$or"] = bson.A{global, bson.M{"tenant_id": tenantID}}
+	}
 
 	cursor, err := r.collection.Find(ctx, query)
 	if err != nil {
@@ -167,6 +187,25 @@ func (r *MongoExerciseRepository) Update(ctx context.Context, ex *domain.Exercis
 	return err
 }
 
+func (r *MongoExerciseRepository) SetVideo(ctx context.Context, id string, video *domain.ExerciseVideo) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrInvalidID
+	}
+	update := bson.M{"$set": bson.M{"video": video, "updated_at": time.Now()}}
+	if video == nil {
+		update = bson.M{"$unset": bson.M{"video": ""}, "$set": bson.M{"updated_at": time.Now()}}
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid}, update)
+	if err != nil {
+		return fmt.Errorf("failed to set exercise video: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrExerciseNotFound
+	}
+	return nil
+}
+
 func (r *MongoExerciseRepository) Delete(ctx context.Context, id string) error {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoExerciseMergeRepository implements domain.ExerciseMergeRepository. Like the member
+// transfer rewrites, it writes straight to the collections of other repositories.
+type MongoExerciseMergeRepository struct {
+	db     *mongo.Database
+	merges *mongo.Collection
+}
+
+func NewMongoExerciseMergeRepository(db *mongo.Database) *MongoExerciseMergeRepository {
+	return &MongoExerciseMergeRepository{db: db, merges: db.Collection("exercise_merges")}
+}
+
+// repoint sets field from any of the duplicates to the canonical ID in each collection
+func (r *MongoExerciseMergeRepository) repoint(ctx context.Context, field string, duplicateIDs []string, canonicalID string, collections ...string) (int64, error) {
+	var total int64
+	for _, name := range collections {
+		result, err := r.db.Collection(name).UpdateMany(ctx,
+			bson.M{field: bson.M{"$in": duplicateIDs}},
+			bson.M{"$set": bson.M{field: canonicalID}},
+		)
+		if err != nil {
+			return total, fmt.Errorf("failed to re-point %s: %w", name, err)
+		}
+		total += result.ModifiedCount
+	}
+	return total, nil
+}
+
+func (r *MongoExerciseMergeRepository) RepointSetLogs(ctx context.Context, duplicateIDs []string, canonicalID string) (int64, error) {
+	return r.repoint(ctx, "exercise_id", duplicateIDs, canonicalID, "set_logs", setLogsArchiveCollection)
+}
+
+func (r *MongoExerciseMergeRepository) RepointPlannedExercises(ctx context.Context, duplicateIDs []string, canonicalID string) (int64, error) {
+	return r.repoint(ctx, "exercise_id", duplicateIDs, canonicalID, "planned_exercises", plannedExercisesArchiveCollection)
+}
+
+func (r *MongoExerciseMergeRepository) RepointWorkoutEvents(ctx context.Context, duplicateIDs []string, canonicalID string) (int64, error) {
+	return r.repoint(ctx, "data.exercise_id", duplicateIDs, canonicalID, "workout_events")
+}
+
+func (r *MongoExerciseMergeRepository) RepointTemplates(ctx context.Context, duplicateIDs []string, canonicalID string) (int64, error) {
+	// Map duplicates to the canonical ID, then drop repeats in order
+	pipeline := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"exercise_ids": bson.M{"$reduce": bson.M{
+			"input": bson.M{"$map": bson.M{
+				"input": "$exercise_ids",
+				"as":    "id",
+				"in":    bson.M{"$cond": bson.A{bson.M{"$in": bson.A{"$$id", duplicateIDs}}, canonicalID, "$$id"}},
+			}},
+			"initialValue": bson.A{},
+			"in": bson.M{"$cond": bson.A{
+				bson.M{"$in": bson.A{"$$this", "$$value"}},
+				"$$value",
+				bson.M{"$concatArrays": bson.A{"$$value", bson.A{"$$this"}}},
+			}},
+		}},
+		"updated_at": time.Now(),
+	}}}}
+
+	result, err := r.db.Collection("workout_templates").UpdateMany(ctx, bson.M{"exercise_ids": bson.M{"$in": duplicateIDs}}, pipeline)
+	if err != nil {
+		return 0, fmt.Errorf("failed to re-point templates: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *MongoExerciseMergeRepository) MergePersonalBests(ctx context.Context, duplicateIDs []string, canonicalID string) (int64, error) {
+	coll := r.db.Collection("personal_bests")
+	exerciseIDs := append([]string{canonicalID}, duplicateIDs...)
+	cursor, err := coll.Find(ctx, bson.M{"exercise_id": bson.M{"$in": exerciseIDs}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read personal bests: %w", err)
+	}
+	var pbs []*domain.PersonalBest
+	if err := cursor.All(ctx, &pbs); err != nil {
+		return 0, fmt.Errorf("failed to read personal bests: %w", err)
+	}
+
+	// A member keeps one PB per exercise: the heaviest, or the earliest of equal lifts
+	best := make(map[string]*domain.PersonalBest)
+	for _, pb := range pbs {
+		cur, ok := best[pb.MemberID]
+		if !ok || pb.Weight > cur.Weight || (pb.Weight == cur.Weight && pb.AchievedAt.Before(cur.AchievedAt)) {
+			best[pb.MemberID] = pb
+		}
+	}
+
+	var changed int64
+	for _, pb := range pbs {
+		if pb.ExerciseID == canonicalID && best[pb.MemberID] == pb {
+			continue
+		}
+		id, err := idValue(pb.ID)
+		if err != nil {
+			return changed, err
+		}
+		if best[pb.MemberID] == pb {
+			_, err = coll.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"exercise_id": canonicalID, "updated_at": time.Now()}})
+		} else {
+			_, err = coll.DeleteOne(ctx, bson.M{"_id": id})
+		}
+		if err != nil {
+			return changed, fmt.Errorf("failed to merge personal bests: %w", err)
+		}
+		changed++
+	}
+	return changed, nil
+}
+
+func (r *MongoExerciseMergeRepository) Record(ctx context.Context, merge *domain.ExerciseMerge) error {
+	merge.ID = newID()
+	if merge.CreatedAt.IsZero() {
+		merge.CreatedAt = time.Now()
+	}
+	if _, err := r.merges.InsertOne(ctx, merge); err != nil {
+		return fmt.Errorf("failed to record exercise merge: %w", err)
+	}
+	return nil
+}
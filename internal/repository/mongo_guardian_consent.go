@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoGuardianConsentRepository implements domain.GuardianConsentRepository
+type MongoGuardianConsentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoGuardianConsentRepository(db *mongo.Database) *MongoGuardianConsentRepository {
+	coll := db.Collection("guardian_consents")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "member_id", Value: 1}, {Key: "recorded_at", Value: -1}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create guardian_consents indexes: %v\n", err)
+	}
+
+	return &MongoGuardianConsentRepository{collection: coll}
+}
+
+func (r *MongoGuardianConsentRepository) Record(ctx context.Context, consent *domain.GuardianConsent) error {
+	consent.ID = newID()
+	if _, err := r.collection.InsertOne(ctx, consent); err != nil {
+		return fmt.Errorf("failed to record guardian consent: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoGuardianConsentRepository) GetLatest(ctx context.Context, tenantID, memberID string) (*domain.GuardianConsent, error) {
+	var consent domain.GuardianConsent
+	opts := options.FindOne().SetSort(bson.D{{Key: "recorded_at", Value: -1}, {Key: "_id", Value: -1}})
+	err := r.collection.FindOne(ctx, bson.M{"tenant_id": tenantID, "member_id": memberID}, opts).Decode(&consent)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find guardian consent: %w", err)
+	}
+	return &consent, nil
+}
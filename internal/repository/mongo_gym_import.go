@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoGymImportRepository implements domain.GymImportRepository
+type MongoGymImportRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoGymImportRepository(db *mongo.Database) *MongoGymImportRepository {
+	coll := db.Collection("gym_imports")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create gym_imports indexes: %v\n", err)
+	}
+
+	return &MongoGymImportRepository{collection: coll}
+}
+
+func (r *MongoGymImportRepository) Create(ctx context.Context, imp *domain.GymImport) error {
+	imp.ID = newID()
+	if _, err := r.collection.InsertOne(ctx, imp); err != nil {
+		return fmt.Errorf("failed to create gym import: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoGymImportRepository) GetByID(ctx context.Context, tenantID, id string) (*domain.GymImport, error) {
+	var imp domain.GymImport
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "tenant_id": tenantID}).Decode(&imp)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find gym import: %w", err)
+	}
+	return &imp, nil
+}
+
+func (r *MongoGymImportRepository) Start(ctx context.Context, tenantID, id string, at time.Time) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "tenant_id": tenantID, "status": domain.GymImportStatusPreview},
+		bson.M{"$set": bson.M{"status": domain.GymImportStatusRunning, "started_at": at}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to start gym import: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		if _, err := r.GetByID(ctx, tenantID, id); err != nil {
+			return err
+		}
+		return domain.ErrImportStarted
+	}
+	return nil
+}
+
+func (r *MongoGymImportRepository) Update(ctx context.Context, imp *domain.GymImport) error {
+	set := bson.M{
+		"status":      imp.Status,
+		"progress":    imp.Progress,
+		"result":      imp.Result,
+		"issues":      imp.Issues,
+		"error":       imp.Error,
+		"finished_at": imp.FinishedAt,
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": imp.ID, "tenant_id": imp.TenantID}, bson.M{"$set": set})
+	if err != nil {
+		return fmt.Errorf("failed to update gym import: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoGymImportRepository) ListByTenant(ctx context.Context, tenantID string, limit int) ([]*domain.GymImport, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"data": 0, "progress": 0})
+	cursor, err := r.collection.Find(ctx, bson.M{"tenant_id": tenantID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list gym imports: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	imports := []*domain.GymImport{}
+	if err := cursor.All(ctx, &imports); err != nil {
+		return nil, err
+	}
+	return imports, nil
+}
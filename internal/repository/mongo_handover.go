@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoHandoverRepository implements domain.HandoverRepository: the handovers, and the
+// client notes they draw on
+type MongoHandoverRepository struct {
+	handovers *mongo.Collection
+	notes     *mongo.Collection
+}
+
+func NewMongoHandoverRepository(db *mongo.Database) *MongoHandoverRepository {
+	handovers := db.Collection("handovers")
+	notes := db.Collection("client_notes")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := handovers.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "to_coach_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create handovers indexes: %v\n", err)
+	}
+	_, err = notes.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "member_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create client_notes indexes: %v\n", err)
+	}
+
+	return &MongoHandoverRepository{handovers: handovers, notes: notes}
+}
+
+func (r *MongoHandoverRepository) Create(ctx context.Context, handover *domain.Handover) error {
+	handover.ID = newID()
+	if handover.CreatedAt.IsZero() {
+		handover.CreatedAt = time.Now()
+	}
+	if _, err := r.handovers.InsertOne(ctx, handover); err != nil {
+		return fmt.Errorf("failed to create handover: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoHandoverRepository) GetByID(ctx context.Context, id string) (*domain.Handover, error) {
+	var handover domain.Handover
+	err := r.handovers.FindOne(ctx, bson.M{"_id": id}).Decode(&handover)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrHandoverNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get handover: %w", err)
+	}
+	return &handover, nil
+}
+
+func (r *MongoHandoverRepository) ListByCoach(ctx context.Context, tenantID, coachID string, pendingOnly bool) ([]*domain.Handover, error) {
+	filter := bson.M{"tenant_id": tenantID, "to_coach_id": coachID}
+	if pendingOnly {
+		filter["acknowledged_at"] = bson.M{"$exists": false}
+	}
+	cursor, err := r.handovers.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list handovers: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	handovers := []*domain.Handover{}
+	if err := cursor.All(ctx, &handovers); err != nil {
+		return nil, err
+	}
+	return handovers, nil
+}
+
+func (r *MongoHandoverRepository) Acknowledge(ctx context.Context, id, coachID string, at time.Time) error {
+	result, err := r.handovers.UpdateOne(ctx, bson.M{"_id": id, "to_coach_id": coachID}, []bson.M{
+		{"$set": bson.M{"acknowledged_at": bson.M{"$ifNull": bson.A{"$acknowledged_at", at}}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge handover: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrHandoverNotFound
+	}
+	return nil
+}
+
+func (r *MongoHandoverRepository) GetNotes(ctx context.Context, tenantID, memberID string) (*domain.ClientNotes, error) {
+	notes := domain.ClientNotes{TenantID: tenantID, MemberID: memberID, Injuries: []string{}, Preferences: []string{}}
+	err := r.notes.FindOne(ctx, bson.M{"tenant_id": tenantID, "member_id": memberID},
+		options.FindOne().SetProjection(bson.M{"_id": 0})).Decode(&notes)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("failed to get client notes: %w", err)
+	}
+	return &notes, nil
+}
+
+func (r *MongoHandoverRepository) SaveNotes(ctx context.Context, notes *domain.ClientNotes) error {
+	_, err := r.notes.UpdateOne(ctx,
+		bson.M{"tenant_id": notes.TenantID, "member_id": notes.MemberID},
+		bson.M{"$set": notes},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save client notes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoHealthConsentRepository implements domain.HealthConsentRepository
+type MongoHealthConsentRepository struct {
+	policies *mongo.Collection
+	consents *mongo.Collection
+}
+
+func NewMongoHealthConsentRepository(db *mongo.Database) *MongoHealthConsentRepository {
+	policies := db.Collection("health_data_policies")
+	consents := db.Collection("health_consents")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := policies.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "version", Value: -1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create health_data_policies indexes: %v\n", err)
+	}
+	_, err = consents.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "recorded_at", Value: -1}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create health_consents indexes: %v\n", err)
+	}
+
+	return &MongoHealthConsentRepository{policies: policies, consents: consents}
+}
+
+func (r *MongoHealthConsentRepository) GetCurrentPolicy(ctx context.Context) (*domain.HealthDataPolicy, error) {
+	var policy domain.HealthDataPolicy
+	opts := options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}})
+	err := r.policies.FindOne(ctx, bson.M{}, opts).Decode(&policy)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find health data policy: %w", err)
+	}
+	return &policy, nil
+}
+
+func (r *MongoHealthConsentRepository) CreatePolicy(ctx context.Context, policy *domain.HealthDataPolicy) error {
+	policy.ID = newID()
+	if _, err := r.policies.InsertOne(ctx, policy); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrVersionConflict
+		}
+		return fmt.Errorf("failed to create health data policy: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoHealthConsentRepository) Record(ctx context.Context, consent *domain.HealthConsent) error {
+	consent.ID = newID()
+	if _, err := r.consents.InsertOne(ctx, consent); err != nil {
+		return fmt.Errorf("failed to record health consent: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoHealthConsentRepository) GetLatest(ctx context.Context, userID string) (*domain.HealthConsent, error) {
+	var consent domain.HealthConsent
+	opts := options.FindOne().SetSort(bson.D{{Key: "recorded_at", Value: -1}, {Key: "_id", Value: -1}})
+	err := r.consents.FindOne(ctx, bson.M{"user_id": userID}, opts).Decode(&consent)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find health consent: %w", err)
+	}
+	return &consent, nil
+}
@@ -10,6 +10,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 const (
@@ -20,6 +21,7 @@ const (
 type MongoInBodyRepository struct {
 	collection             *mongo.Collection
 	trendSummaryCollection *mongo.Collection
+	analytics              *mongo.Collection // Reads for trends and dashboards; see RouteAnalyticsReads
 }
 
 // NewMongoInBodyRepository creates a new MongoDB repository
@@ -40,6 +42,13 @@ func NewMongoInBodyRepository(db *mongo.Database) *MongoInBodyRepository {
 	}
 	_, _ = collection.Indexes().CreateOne(ctx, indexModel)
 
+	// Scans whose original image is still kept, for the retention job
+	retentionIndexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "metadata.processed_at", Value: 1}},
+		Options: options.Index().SetPartialFilterExpression(bson.M{"metadata.image_archived_at": bson.M{"$exists": false}}),
+	}
+	_, _ = collection.Indexes().CreateOne(ctx, retentionIndexModel)
+
 	// Index on user_id and last_generated_at for trend summaries
 	trendIndexModel := mongo.IndexModel{
 		Keys: bson.D{
@@ -52,21 +61,71 @@ func NewMongoInBodyRepository(db *mongo.Database) *MongoInBodyRepository {
 	return &MongoInBodyRepository{
 		collection:             collection,
 		trendSummaryCollection: trendSummaryCollection,
+		analytics:              collection,
 	}
 }
 
+// RouteAnalyticsReads sends trend history and dashboard scan queries to pref (e.g. secondaries).
+// A scan that is a few seconds late in a trend chart is harmless; everything else reads from the primary.
+func (r *MongoInBodyRepository) RouteAnalyticsReads(pref *readpref.ReadPref) {
+	r.analytics = withReadPreference(r.collection, pref)
+}
+
 // Create saves a new InBodyRecord to MongoDB
 func (r *MongoInBodyRepository) Create(ctx context.Context, record *domain.InBodyRecord) error {
+	doc, err := newRecordDocument(record)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.InsertOne(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to insert inbody record: %w", err)
+	}
+
+	return nil
+}
+
+// CreateMany inserts records in one round trip, e.g. a scan history imported from a file.
+// Nothing is inserted if any record is invalid.
+func (r *MongoInBodyRepository) CreateMany(ctx context.Context, records []*domain.InBodyRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, 0, len(records))
+	for _, record := range records {
+		doc, err := newRecordDocument(record)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, doc)
+	}
+
+	if _, err := r.collection.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("failed to insert inbody records: %w", err)
+	}
+	return nil
+}
+
+// newRecordDocument builds the document for a new record and sets the record's ID and
+// processed time to the ones stored
+func newRecordDocument(record *domain.InBodyRecord) (bson.M, error) {
 	// Generate new ObjectID
 	objectID := primitive.NewObjectID()
 
+	// user_id follows the type of the user's _id so per-user queries match
+	uid, err := idValue(record.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+
 	// Set processed timestamp
 	processedAt := time.Now()
 
 	// Create BSON document with proper ObjectID type for _id
 	doc := bson.M{
 		"_id":                        objectID, // Store as ObjectID, not string
-		"user_id":                    record.UserID,
+		"user_id":                    uid,
 		"test_date_time":             record.TestDateTime,
 		"weight":                     record.Weight,
 		"smm":                        record.SMM,
@@ -87,8 +146,12 @@ func (r *MongoInBodyRepository) Create(ctx context.Context, record *domain.InBod
 		"metadata": bson.M{
 			"image_url":    record.Metadata.ImageURL,
 			"processed_at": processedAt,
+			"model":        record.Metadata.Model,
 		},
 	}
+	if record.Metadata.Source != "" {
+		doc["metadata"].(bson.M)["source"] = record.Metadata.Source
+	}
 
 	// Add V2 fields if present (backward compatibility)
 	if record.SegmentalLean != nil {
@@ -101,25 +164,20 @@ func (r *MongoInBodyRepository) Create(ctx context.Context, record *domain.InBod
 		doc["analysis"] = record.Analysis
 	}
 
-	_, err := r.collection.InsertOne(ctx, doc)
-	if err != nil {
-		return fmt.Errorf("failed to insert inbody record: %w", err)
-	}
-
 	// Set the ID in the record (as hex string for domain model)
 	record.ID = objectID.Hex()
 	record.Metadata.ProcessedAt = processedAt
 
-	return nil
+	return doc, nil
 }
 
 // GetLatestByUserID retrieves the most recent scan for a user
 func (r *MongoInBodyRepository) GetLatestByUserID(ctx context.Context, userID string) (*domain.InBodyRecord, error) {
-	oid, err := primitive.ObjectIDFromHex(userID)
+	uid, err := idValue(userID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid user id: %w", err)
 	}
-	filter := bson.M{"user_id": oid}
+	filter := bson.M{"user_id": uid}
 	opts := options.FindOne().SetSort(bson.D{{Key: "test_date_time", Value: -1}})
 
 	var record domain.InBodyRecord
@@ -136,11 +194,11 @@ func (r *MongoInBodyRepository) GetLatestByUserID(ctx context.Context, userID st
 
 // GetByUserID retrieves multiple scans for a user, limited by count
 func (r *MongoInBodyRepository) GetByUserID(ctx context.Context, userID string, limit int) ([]*domain.InBodyRecord, error) {
-	oid, err := primitive.ObjectIDFromHex(userID)
+	uid, err := idValue(userID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid user id: %w", err)
 	}
-	filter := bson.M{"user_id": oid}
+	filter := bson.M{"user_id": uid}
 	opts := options.Find().
 		SetSort(bson.D{{Key: "test_date_time", Value: -1}}).
 		SetLimit(int64(limit))
@@ -161,11 +219,11 @@ func (r *MongoInBodyRepository) GetByUserID(ctx context.Context, userID string,
 
 // FindAllByUserID retrieves all scans for a user, sorted by test_date_time DESC
 func (r *MongoInBodyRepository) FindAllByUserID(ctx context.Context, userID string) ([]*domain.InBodyRecord, error) {
-	oid, err := primitive.ObjectIDFromHex(userID)
+	uid, err := idValue(userID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid user id: %w", err)
 	}
-	filter := bson.M{"user_id": oid}
+	filter := bson.M{"user_id": uid}
 	opts := options.Find().SetSort(bson.D{{Key: "test_date_time", Value: -1}})
 
 	cursor, err := r.collection.Find(ctx, filter, opts)
@@ -210,37 +268,51 @@ func (r *MongoInBodyRepository) Update(ctx context.Context, id string, record *d
 		return domain.ErrNotFound
 	}
 
-	filter := bson.M{"_id": objectID}
+	set := bson.M{
+		"weight":                     record.Weight,
+		"smm":                        record.SMM,
+		"body_fat_mass":              record.BodyFatMass,
+		"bmi":                        record.BMI,
+		"pbf":                        record.PBF,
+		"bmr":                        record.BMR,
+		"visceral_fat":               record.VisceralFatLevel,
+		"whr":                        record.WaistHipRatio,
+		"inbody_score":               record.InBodyScore,
+		"obesity_degree":             record.ObesityDegree,
+		"fat_free_mass":              record.FatFreeMass,
+		"recommended_calorie_intake": record.RecommendedCalorieIntake,
+		"target_weight":              record.TargetWeight,
+		"weight_control":             record.WeightControl,
+		"fat_control":                record.FatControl,
+		"muscle_control":             record.MuscleControl,
+		"metadata.processed_at":      record.Metadata.ProcessedAt,
+		"metadata.model":             record.Metadata.Model,
+	}
+	// V2 fields are only ever replaced by a new extraction, never cleared
+	if record.SegmentalLean != nil {
+		set["segmental_lean"] = record.SegmentalLean
+	}
+	if record.SegmentalFat != nil {
+		set["segmental_fat"] = record.SegmentalFat
+	}
+	if record.Analysis != nil {
+		set["analysis"] = record.Analysis
+	}
 	update := bson.M{
-		"$set": bson.M{
-			"weight":                     record.Weight,
-			"smm":                        record.SMM,
-			"body_fat_mass":              record.BodyFatMass,
-			"bmi":                        record.BMI,
-			"pbf":                        record.PBF,
-			"bmr":                        record.BMR,
-			"visceral_fat":               record.VisceralFatLevel,
-			"whr":                        record.WaistHipRatio,
-			"inbody_score":               record.InBodyScore,
-			"obesity_degree":             record.ObesityDegree,
-			"fat_free_mass":              record.FatFreeMass,
-			"recommended_calorie_intake": record.RecommendedCalorieIntake,
-			"target_weight":              record.TargetWeight,
-			"weight_control":             record.WeightControl,
-			"fat_control":                record.FatControl,
-			"muscle_control":             record.MuscleControl,
-		},
+		"$set": set,
+		"$inc": bson.M{"version": 1},
 	}
 
-	result, err := r.collection.UpdateOne(ctx, filter, update)
+	result, err := r.collection.UpdateOne(ctx, versionFilter(objectID, record.Version), update)
 	if err != nil {
 		return fmt.Errorf("failed to update record: %w", err)
 	}
 
 	if result.MatchedCount == 0 {
-		return domain.ErrNotFound
+		return notFoundOrConflict(ctx, r.collection, objectID)
 	}
 
+	record.Version++
 	return nil
 }
 
@@ -265,14 +337,61 @@ func (r *MongoInBodyRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// ListOriginalImages retrieves scans processed before the cutoff that still keep their original image
+func (r *MongoInBodyRepository) ListOriginalImages(ctx context.Context, before time.Time, limit int) ([]*domain.InBodyRecord, error) {
+	filter := bson.M{
+		"metadata.processed_at":      bson.M{"$lt": before},
+		"metadata.image_archived_at": bson.M{"$exists": false},
+		"metadata.image_url":         bson.M{"$ne": ""},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "metadata.processed_at", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find records: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []*domain.InBodyRecord
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode records: %w", err)
+	}
+
+	return records, nil
+}
+
+// SetImageArchived records that a scan's image was archived. The version is left alone so
+// a coach editing the scan at the same time doesn't get a conflict.
+func (r *MongoInBodyRepository) SetImageArchived(ctx context.Context, id string, imageURL string, at time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrNotFound
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": bson.M{
+		"metadata.image_url":         imageURL,
+		"metadata.image_archived_at": at,
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to update record: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
 // GetTrendHistory retrieves N scans for analytics, sorted ascending by test_date_time
 // Uses projection to only return necessary fields for charting
 func (r *MongoInBodyRepository) GetTrendHistory(ctx context.Context, userID string, limit int) ([]*domain.InBodyRecord, error) {
-	oid, err := primitive.ObjectIDFromHex(userID)
+	uid, err := idValue(userID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid user id: %w", err)
 	}
-	filter := bson.M{"user_id": oid}
+	filter := bson.M{"user_id": uid}
 
 	// Sort ascending (oldest first) for left-to-right chart plotting
 	opts := options.Find().
@@ -295,7 +414,7 @@ func (r *MongoInBodyRepository) GetTrendHistory(ctx context.Context, userID stri
 			"segmental_fat":              1,
 		})
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	cursor, err := r.analytics.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find trend history: %w", err)
 	}
@@ -314,16 +433,21 @@ func (r *MongoInBodyRepository) SaveTrendSummary(ctx context.Context, summary *d
 	// Generate new ObjectID
 	objectID := primitive.NewObjectID()
 
+	uid, err := idValue(summary.UserID)
+	if err != nil {
+		return fmt.Errorf("invalid user id: %w", err)
+	}
+
 	// Create BSON document
 	doc := bson.M{
 		"_id":               objectID,
-		"user_id":           summary.UserID,
+		"user_id":           uid,
 		"summary_text":      summary.SummaryText,
 		"last_generated_at": summary.LastGeneratedAt,
 		"included_scan_ids": summary.IncludedScanIDs,
 	}
 
-	_, err := r.trendSummaryCollection.InsertOne(ctx, doc)
+	_, err = r.trendSummaryCollection.InsertOne(ctx, doc)
 	if err != nil {
 		return fmt.Errorf("failed to insert trend summary: %w", err)
 	}
@@ -336,11 +460,11 @@ func (r *MongoInBodyRepository) SaveTrendSummary(ctx context.Context, summary *d
 
 // GetLatestTrendSummary retrieves the most recent trend summary for a user
 func (r *MongoInBodyRepository) GetLatestTrendSummary(ctx context.Context, userID string) (*domain.TrendSummary, error) {
-	oid, err := primitive.ObjectIDFromHex(userID)
+	uid, err := idValue(userID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid user id: %w", err)
 	}
-	filter := bson.M{"user_id": oid}
+	filter := bson.M{"user_id": uid}
 	opts := options.FindOne().SetSort(bson.D{{Key: "last_generated_at", Value: -1}})
 
 	var summary domain.TrendSummary
@@ -362,17 +486,9 @@ func (r *MongoInBodyRepository) GetRecentScansByMembers(ctx context.Context, mem
 		return make(map[string][]*domain.InBodyRecord), nil
 	}
 
-	// Convert string IDs to ObjectIDs
-	oids := make([]primitive.ObjectID, 0, len(memberIDs))
-	for _, id := range memberIDs {
-		oid, err := primitive.ObjectIDFromHex(id)
-		if err != nil {
-			continue
-		}
-		oids = append(oids, oid)
-	}
-
-	if len(oids) == 0 {
+	// Convert string IDs to their stored form
+	uids := idValues(memberIDs)
+	if len(uids) == 0 {
 		return make(map[string][]*domain.InBodyRecord), nil
 	}
 
@@ -381,7 +497,7 @@ func (r *MongoInBodyRepository) GetRecentScansByMembers(ctx context.Context, mem
 	// 2. Sort by test_date_time descending
 	// 3. Group by user_id and take first N documents
 	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: bson.M{"user_id": bson.M{"$in": oids}}}},
+		{{Key: "$match", Value: bson.M{"user_id": bson.M{"$in": uids}}}},
 		{{Key: "$sort", Value: bson.D{{Key: "test_date_time", Value: -1}}}},
 		{{Key: "$group", Value: bson.M{
 			"_id":   "$user_id",
@@ -393,7 +509,7 @@ func (r *MongoInBodyRepository) GetRecentScansByMembers(ctx context.Context, mem
 		}}},
 	}
 
-	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	cursor, err := r.analytics.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, fmt.Errorf("failed to aggregate scans: %w", err)
 	}
@@ -403,13 +519,13 @@ func (r *MongoInBodyRepository) GetRecentScansByMembers(ctx context.Context, mem
 	result := make(map[string][]*domain.InBodyRecord)
 	for cursor.Next(ctx) {
 		var doc struct {
-			ID    primitive.ObjectID     `bson:"_id"`
+			ID    interface{}            `bson:"_id"`
 			Scans []*domain.InBodyRecord `bson:"scans"`
 		}
 		if err := cursor.Decode(&doc); err != nil {
 			continue
 		}
-		result[doc.ID.Hex()] = doc.Scans
+		result[idString(doc.ID)] = doc.Scans
 	}
 
 	return result, nil
@@ -418,13 +534,13 @@ func (r *MongoInBodyRepository) GetRecentScansByMembers(ctx context.Context, mem
 // FindPaginatedByUserID retrieves scans with cursor-based pagination and date filtering
 // Returns lightweight ScanListItem records for efficient list rendering
 func (r *MongoInBodyRepository) FindPaginatedByUserID(ctx context.Context, userID string, query *domain.ScanListQuery) (*domain.ScanListResult, error) {
-	oid, err := primitive.ObjectIDFromHex(userID)
+	uid, err := idValue(userID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid user id: %w", err)
 	}
 
 	// Build filter
-	filter := bson.M{"user_id": oid}
+	filter := bson.M{"user_id": uid}
 
 	// Add date range filter if provided
 	if !query.From.IsZero() || !query.To.IsZero() {
@@ -472,7 +588,7 @@ func (r *MongoInBodyRepository) FindPaginatedByUserID(ctx context.Context, userI
 	}
 
 	// Get total count for the user (without cursor/pagination)
-	countFilter := bson.M{"user_id": oid}
+	countFilter := bson.M{"user_id": uid}
 	if !query.From.IsZero() || !query.To.IsZero() {
 		dateFilter := bson.M{}
 		if !query.From.IsZero() {
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// inboxRetention is how long notifications stay in the inbox, read or not
+const inboxRetention = 90 * 24 * time.Hour
+
+// MongoInboxRepository implements domain.InboxRepository
+type MongoInboxRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoInboxRepository(db *mongo.Database) *MongoInboxRepository {
+	coll := db.Collection("notifications")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "read_at", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(inboxRetention.Seconds())),
+		},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create notifications indexes: %v\n", err)
+	}
+
+	return &MongoInboxRepository{collection: coll}
+}
+
+func (r *MongoInboxRepository) Create(ctx context.Context, item *domain.InboxItem) error {
+	item.ID = newID()
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = time.Now()
+	}
+	if _, err := r.collection.InsertOne(ctx, item); err != nil {
+		return fmt.Errorf("failed to store notification: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoInboxRepository) List(ctx context.Context, userID string, unreadOnly bool, q domain.PageQuery) (*domain.Page[*domain.InboxItem], error) {
+	q = q.Normalized()
+
+	match := bson.M{"user_id": userID}
+	if unreadOnly {
+		match["read_at"] = nil
+	}
+	filter, err := pageFilter(match, q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, pageOptions(q))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var items []*domain.InboxItem
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return newPage(items, q, func(i *domain.InboxItem) (time.Time, string) { return i.CreatedAt, i.ID }), nil
+}
+
+func (r *MongoInboxRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"user_id": userID, "read_at": nil})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+func (r *MongoInboxRepository) MarkRead(ctx context.Context, userID, id string, at time.Time) error {
+	// Marking twice keeps the first read time
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "user_id": userID},
+		bson.A{bson.M{"$set": bson.M{"read_at": bson.M{"$ifNull": bson.A{"$read_at", at}}}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoInboxRepository) MarkAllRead(ctx context.Context, userID string, at time.Time) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"user_id": userID, "read_at": nil},
+		bson.M{"$set": bson.M{"read_at": at}},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
@@ -9,6 +9,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // MongoInvoiceRepository implements domain.InvoiceRepository
@@ -36,6 +37,7 @@ func (r *MongoInvoiceRepository) Create(ctx context.Context, invoice *domain.Inv
 	doc := bson.M{
 		"_id":                objID,
 		"user_id":            invoice.UserID,
+		"tenant_id":          invoice.TenantID,
 		"package_id":         invoice.PackageID,
 		"amount":             invoice.Amount,
 		"status":             invoice.Status,
@@ -46,6 +48,21 @@ func (r *MongoInvoiceRepository) Create(ctx context.Context, invoice *domain.Inv
 		"created_at":         invoice.CreatedAt,
 		"updated_at":         invoice.UpdatedAt,
 	}
+	if invoice.ContractID != "" {
+		doc["contract_id"] = invoice.ContractID
+	}
+	if invoice.BranchID != "" {
+		doc["branch_id"] = invoice.BranchID
+		doc["branch_priced"] = invoice.BranchPriced
+	}
+	if invoice.IsInstallmentPlan() {
+		doc["installments"] = invoice.Installments
+		doc["grace_days"] = invoice.GraceDays
+	}
+	if len(invoice.Payments) > 0 {
+		doc["payments"] = invoice.Payments
+		doc["paid_amount"] = invoice.PaidAmount
+	}
 
 	_, err := r.collection.InsertOne(ctx, doc)
 	if err != nil {
@@ -109,27 +126,19 @@ func (r *MongoInvoiceRepository) GetPendingByUserAndPackage(ctx context.Context,
 	return mapBsonToInvoice(raw), nil
 }
 
-func (r *MongoInvoiceRepository) UpdateStatus(ctx context.Context, id string, status string) error {
+func (r *MongoInvoiceRepository) MarkPaid(ctx context.Context, id string, at time.Time) (bool, error) {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return fmt.Errorf("invalid invoice id: %w", err)
+		return false, fmt.Errorf("invalid invoice id: %w", err)
 	}
-
-	update := bson.M{
-		"$set": bson.M{
-			"status":     status,
-			"updated_at": time.Now().UTC(),
-		},
-	}
-
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": objID, "status": domain.InvoiceStatusPending},
+		bson.M{"$set": bson.M{"status": domain.InvoiceStatusPaid, "updated_at": at}},
+	)
 	if err != nil {
-		return fmt.Errorf("failed to update invoice status: %w", err)
+		return false, fmt.Errorf("failed to mark invoice paid: %w", err)
 	}
-	if result.MatchedCount == 0 {
-		return domain.ErrNotFound
-	}
-	return nil
+	return result.ModifiedCount > 0, nil
 }
 
 // Update updates an invoice with all fields
@@ -151,6 +160,9 @@ func (r *MongoInvoiceRepository) Update(ctx context.Context, invoice *domain.Inv
 			"updated_at":         invoice.UpdatedAt,
 		},
 	}
+	if invoice.IsInstallmentPlan() {
+		update["$set"].(bson.M)["installments"] = invoice.Installments // Carries the VA issued per installment
+	}
 
 	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
 	if err != nil {
@@ -162,10 +174,15 @@ func (r *MongoInvoiceRepository) Update(ctx context.Context, invoice *domain.Inv
 	return nil
 }
 
-// GetByPaymentSessionID finds an invoice by its payment session ID
+// GetByPaymentSessionID finds an invoice by its payment session ID, including the VAs
+// issued for earlier installments
 func (r *MongoInvoiceRepository) GetByPaymentSessionID(ctx context.Context, sessionID string) (*domain.Invoice, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"payment_session_id": sessionID},
+		bson.M{"installments.payment_session_id": sessionID},
+	}}
 	var raw bson.M
-	if err := r.collection.FindOne(ctx, bson.M{"payment_session_id": sessionID}).Decode(&raw); err != nil {
+	if err := r.collection.FindOne(ctx, filter).Decode(&raw); err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrNotFound
 		}
@@ -174,6 +191,236 @@ func (r *MongoInvoiceRepository) GetByPaymentSessionID(ctx context.Context, sess
 	return mapBsonToInvoice(raw), nil
 }
 
+// GetByContractID returns the contract's latest installment plan
+func (r *MongoInvoiceRepository) GetByContractID(ctx context.Context, contractID string) (*domain.Invoice, error) {
+	opts := options.FindOne().SetSort(bson.M{"created_at": -1})
+	var raw bson.M
+	if err := r.collection.FindOne(ctx, bson.M{"contract_id": contractID}, opts).Decode(&raw); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get invoice by contract: %w", err)
+	}
+	return mapBsonToInvoice(raw), nil
+}
+
+func (r *MongoInvoiceRepository) ListInstallmentPlansDue(ctx context.Context, before time.Time) ([]*domain.Invoice, error) {
+	filter := bson.M{
+		"contract_id": bson.M{"$exists": true},
+		"$or": bson.A{
+			bson.M{
+				"status":       bson.M{"$in": []string{domain.InvoiceStatusPending, domain.InvoiceStatusPartiallyPaid}},
+				"installments": bson.M{"$elemMatch": bson.M{"due_date": bson.M{"$lt": before}, "paid_at": nil}},
+			},
+			bson.M{"suspended_at": bson.M{"$ne": nil}},
+		},
+	}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list installment plans: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var invoices []*domain.Invoice
+	for cursor.Next(ctx) {
+		var raw bson.M
+		if err := cursor.Decode(&raw); err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, mapBsonToInvoice(raw))
+	}
+	return invoices, cursor.Err()
+}
+
+func (r *MongoInvoiceRepository) RecordPayment(ctx context.Context, invoice *domain.Invoice, payment domain.InvoicePayment) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(invoice.ID)
+	if err != nil {
+		return false, fmt.Errorf("invalid invoice id: %w", err)
+	}
+
+	invoice.UpdatedAt = time.Now().UTC()
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": objID, "payments.reference": bson.M{"$ne": payment.Reference}},
+		bson.M{
+			"$set": bson.M{
+				"installments": invoice.Installments,
+				"paid_amount":  invoice.PaidAmount,
+				"status":       invoice.Status,
+				"updated_at":   invoice.UpdatedAt,
+			},
+			"$push": bson.M{"payments": payment},
+		},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to record payment: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (r *MongoInvoiceRepository) SetSuspended(ctx context.Context, id string, at *time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid invoice id: %w", err)
+	}
+
+	update := bson.M{"$set": bson.M{"suspended_at": at, "updated_at": time.Now().UTC()}}
+	if at == nil {
+		update = bson.M{"$unset": bson.M{"suspended_at": ""}, "$set": bson.M{"updated_at": time.Now().UTC()}}
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update invoice suspension: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoInvoiceRepository) ListPaidBetween(ctx context.Context, tenantID string, from, to time.Time) ([]*domain.Invoice, error) {
+	filter := bson.M{
+		"tenant_id": tenantID,
+		"$or": bson.A{
+			bson.M{"payments": bson.M{"$elemMatch": bson.M{"paid_at": bson.M{"$gte": from, "$lt": to}}}},
+			bson.M{
+				"status":     domain.InvoiceStatusPaid,
+				"payments":   bson.M{"$exists": false},
+				"updated_at": bson.M{"$gte": from, "$lt": to},
+			},
+		},
+	}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list paid invoices: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var invoices []*domain.Invoice
+	for cursor.Next(ctx) {
+		var raw bson.M
+		if err := cursor.Decode(&raw); err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, mapBsonToInvoice(raw))
+	}
+	return invoices, cursor.Err()
+}
+
+func (r *MongoInvoiceRepository) ListCheckoutsExpiringBefore(ctx context.Context, now, before time.Time) ([]*domain.Invoice, error) {
+	filter := bson.M{
+		"status":      domain.InvoiceStatusPending,
+		"contract_id": bson.M{"$exists": false},
+		"expiry_date": bson.M{"$lt": before},
+		"$or": bson.A{
+			bson.M{"expiry_date": bson.M{"$lte": now}},
+			bson.M{"reminded_at": nil},
+		},
+	}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expiring invoices: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var invoices []*domain.Invoice
+	for cursor.Next(ctx) {
+		var raw bson.M
+		if err := cursor.Decode(&raw); err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, mapBsonToInvoice(raw))
+	}
+	return invoices, cursor.Err()
+}
+
+func (r *MongoInvoiceRepository) Expire(ctx context.Context, id string, now time.Time) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid invoice id: %w", err)
+	}
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": objID, "status": domain.InvoiceStatusPending, "expiry_date": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"status": domain.InvoiceStatusExpired, "updated_at": time.Now().UTC()}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to expire invoice: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (r *MongoInvoiceRepository) MarkReminded(ctx context.Context, id string, at time.Time) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid invoice id: %w", err)
+	}
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": objID, "status": domain.InvoiceStatusPending, "reminded_at": nil},
+		bson.M{"$set": bson.M{"reminded_at": at}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark invoice reminded: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// lapsedCheckoutFilter matches checkout invoices whose VA expired unpaid by now
+func lapsedCheckoutFilter(now time.Time) bson.M {
+	return bson.M{
+		"contract_id": bson.M{"$exists": false},
+		"$or": bson.A{
+			bson.M{"status": domain.InvoiceStatusExpired},
+			bson.M{"status": domain.InvoiceStatusPending, "expiry_date": bson.M{"$lte": now}},
+		},
+	}
+}
+
+func (r *MongoInvoiceRepository) GetLapsedCheckout(ctx context.Context, userID, packageID string, now time.Time) (*domain.Invoice, error) {
+	filter := lapsedCheckoutFilter(now)
+	filter["user_id"] = userID
+	filter["package_id"] = packageID
+
+	var raw bson.M
+	opts := options.FindOne().SetSort(bson.M{"created_at": -1})
+	if err := r.collection.FindOne(ctx, filter, opts).Decode(&raw); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get lapsed invoice: %w", err)
+	}
+	return mapBsonToInvoice(raw), nil
+}
+
+func (r *MongoInvoiceRepository) Reopen(ctx context.Context, invoice *domain.Invoice, previousSessionID string) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(invoice.ID)
+	if err != nil {
+		return false, fmt.Errorf("invalid invoice id: %w", err)
+	}
+
+	invoice.Status = domain.InvoiceStatusPending
+	invoice.RemindedAt = nil
+	invoice.UpdatedAt = time.Now().UTC()
+	filter := lapsedCheckoutFilter(invoice.UpdatedAt)
+	filter["_id"] = objID
+	filter["payment_session_id"] = previousSessionID
+
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{
+			"amount":             invoice.Amount,
+			"va_number":          invoice.VANumber,
+			"payment_method":     invoice.PaymentMethod,
+			"payment_session_id": invoice.PaymentSessionID,
+			"expiry_date":        invoice.ExpiryDate,
+			"status":             invoice.Status,
+			"updated_at":         invoice.UpdatedAt,
+		},
+		"$unset": bson.M{"reminded_at": ""},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to reopen invoice: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
 func mapBsonToInvoice(raw bson.M) *domain.Invoice {
 	invoice := &domain.Invoice{}
 
@@ -183,14 +430,13 @@ func mapBsonToInvoice(raw bson.M) *domain.Invoice {
 	if userID, ok := raw["user_id"].(string); ok {
 		invoice.UserID = userID
 	}
+	if tenantID, ok := raw["tenant_id"].(string); ok {
+		invoice.TenantID = tenantID
+	}
 	if pkgID, ok := raw["package_id"].(string); ok {
 		invoice.PackageID = pkgID
 	}
-	if amount, ok := raw["amount"].(int64); ok {
-		invoice.Amount = amount
-	} else if amount, ok := raw["amount"].(int32); ok {
-		invoice.Amount = int64(amount)
-	}
+	invoice.Amount = moneyValue(raw["amount"])
 	if status, ok := raw["status"].(string); ok {
 		invoice.Status = status
 	}
@@ -212,6 +458,53 @@ func mapBsonToInvoice(raw bson.M) *domain.Invoice {
 	if updated, ok := raw["updated_at"].(primitive.DateTime); ok {
 		invoice.UpdatedAt = updated.Time()
 	}
+	if reminded, ok := raw["reminded_at"].(primitive.DateTime); ok {
+		at := reminded.Time()
+		invoice.RemindedAt = &at
+	}
+
+	// Installment plans
+	if contractID, ok := raw["contract_id"].(string); ok {
+		invoice.ContractID = contractID
+	}
+	if branchID, ok := raw["branch_id"].(string); ok {
+		invoice.BranchID = branchID
+	}
+	invoice.BranchPriced, _ = raw["branch_priced"].(bool)
+	decodeInvoiceField(raw, "installments", &invoice.Installments)
+	decodeInvoiceField(raw, "payments", &invoice.Payments)
+	if paid, ok := raw["paid_amount"].(int64); ok {
+		invoice.PaidAmount = paid
+	} else if paid, ok := raw["paid_amount"].(int32); ok {
+		invoice.PaidAmount = int64(paid)
+	}
+	if grace, ok := raw["grace_days"].(int32); ok {
+		invoice.GraceDays = int(grace)
+	} else if grace, ok := raw["grace_days"].(int64); ok {
+		invoice.GraceDays = int(grace)
+	}
+	if suspended, ok := raw["suspended_at"].(primitive.DateTime); ok {
+		at := suspended.Time()
+		invoice.SuspendedAt = &at
+	}
 
 	return invoice
 }
+
+// decodeInvoiceField decodes a nested value of the raw document into out
+func decodeInvoiceField[T any](raw bson.M, key string, out *T) {
+	value, ok := raw[key]
+	if !ok || value == nil {
+		return
+	}
+	data, err := bson.Marshal(bson.M{"v": value})
+	if err != nil {
+		return
+	}
+	var doc struct {
+		V T `bson:"v"`
+	}
+	if bson.Unmarshal(data, &doc) == nil {
+		*out = doc.V
+	}
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoJobRunRepository implements domain.JobRunRepository
+type MongoJobRunRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoJobRunRepository(db *mongo.Database) *MongoJobRunRepository {
+	coll := db.Collection("job_runs")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "type", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+		// Runs are operational history, not records: keep 30 days
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(30 * 24 * 60 * 60),
+		},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create job_runs indexes: %v\n", err)
+	}
+
+	return &MongoJobRunRepository{collection: coll}
+}
+
+func (r *MongoJobRunRepository) Create(ctx context.Context, run *domain.JobRun) error {
+	run.ID = newID()
+	if run.CreatedAt.IsZero() {
+		run.CreatedAt = time.Now()
+	}
+	if _, err := r.collection.InsertOne(ctx, run); err != nil {
+		return fmt.Errorf("failed to create job run: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoJobRunRepository) Finish(ctx context.Context, run *domain.JobRun) error {
+	update := bson.M{"$set": bson.M{
+		"status":      run.Status,
+		"error":       run.Error,
+		"duration_ms": run.DurationMS,
+		"finished_at": run.FinishedAt,
+	}}
+	result, err := r.collection.UpdateByID(ctx, run.ID, update)
+	if err != nil {
+		return fmt.Errorf("failed to finish job run: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoJobRunRepository) GetByID(ctx context.Context, id string) (*domain.JobRun, error) {
+	var run domain.JobRun
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&run)
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job run: %w", err)
+	}
+	return &run, nil
+}
+
+func (r *MongoJobRunRepository) List(ctx context.Context, f domain.JobRunFilter, q domain.PageQuery) (*domain.Page[*domain.JobRun], error) {
+	q = q.Normalized()
+
+	match := bson.M{}
+	if f.Type != "" {
+		match["type"] = f.Type
+	}
+	if f.Status != "" {
+		match["status"] = f.Status
+	}
+	if f.TenantID != "" {
+		match["tenant_id"] = f.TenantID
+	}
+	filter, err := pageFilter(match, q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, pageOptions(q))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job runs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var runs []*domain.JobRun
+	if err := cursor.All(ctx, &runs); err != nil {
+		return nil, err
+	}
+	return newPage(runs, q, func(r *domain.JobRun) (time.Time, string) { return r.CreatedAt, r.ID }), nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoMemberInviteRepository implements domain.MemberInviteRepository. Used invites are
+// kept as the record of who invited whom.
+type MongoMemberInviteRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoMemberInviteRepository(db *mongo.Database) *MongoMemberInviteRepository {
+	coll := db.Collection("member_invites")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "coach_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create member_invites indexes: %v\n", err)
+	}
+
+	return &MongoMemberInviteRepository{collection: coll}
+}
+
+func (r *MongoMemberInviteRepository) Create(ctx context.Context, invite *domain.MemberInvite) error {
+	invite.ID = newID()
+	if _, err := r.collection.InsertOne(ctx, invite); err != nil {
+		return fmt.Errorf("failed to create member invite: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoMemberInviteRepository) FindByHash(ctx context.Context, hash string) (*domain.MemberInvite, error) {
+	var invite domain.MemberInvite
+	if err := r.collection.FindOne(ctx, bson.M{"token_hash": hash}).Decode(&invite); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find member invite: %w", err)
+	}
+	return &invite, nil
+}
+
+func (r *MongoMemberInviteRepository) ListPending(ctx context.Context, tenantID, coachID string, now time.Time) ([]*domain.MemberInvite, error) {
+	filter := pendingInvite(bson.M{"tenant_id": tenantID, "coach_id": coachID}, now)
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list member invites: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	invites := []*domain.MemberInvite{}
+	if err := cursor.All(ctx, &invites); err != nil {
+		return nil, err
+	}
+	return invites, nil
+}
+
+func (r *MongoMemberInviteRepository) Accept(ctx context.Context, id, memberID string, at time.Time) error {
+	result, err := r.collection.UpdateOne(ctx,
+		pendingInvite(bson.M{"_id": id}, at),
+		bson.M{"$set": bson.M{"accepted_at": at, "member_id": memberID}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to accept member invite: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoMemberInviteRepository) Revoke(ctx context.Context, coachID, id string, at time.Time) error {
+	result, err := r.collection.UpdateOne(ctx,
+		pendingInvite(bson.M{"_id": id, "coach_id": coachID}, at),
+		bson.M{"$set": bson.M{"revoked_at": at}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke member invite: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// pendingInvite narrows filter to invites that can still be accepted at now
+func pendingInvite(filter bson.M, now time.Time) bson.M {
+	filter["accepted_at"] = bson.M{"$exists": false}
+	filter["revoked_at"] = bson.M{"$exists": false}
+	filter["expires_at"] = bson.M{"$gt": now}
+	return filter
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoMemberTransferRepository implements domain.MemberTransferRepository. The bulk
+// rewrites go straight to the collections, so run them inside a transaction.
+type MongoMemberTransferRepository struct {
+	db        *mongo.Database
+	transfers *mongo.Collection
+}
+
+func NewMongoMemberTransferRepository(db *mongo.Database) *MongoMemberTransferRepository {
+	coll := db.Collection("member_transfers")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "member_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create member_transfers index: %v\n", err)
+	}
+
+	return &MongoMemberTransferRepository{db: db, transfers: coll}
+}
+
+func (r *MongoMemberTransferRepository) MoveBranchContracts(ctx context.Context, memberID, tenantID, fromBranchID, toBranchID string) (int64, error) {
+	filter := bson.M{
+		"member_id": memberID,
+		"tenant_id": tenantID,
+		"status":    domain.PackageStatusActive,
+		"branch_id": bson.M{"$ne": toBranchID},
+	}
+	if fromBranchID != "" {
+		filter["branch_id"] = fromBranchID
+	}
+
+	result, err := r.db.Collection("pt_contracts").UpdateMany(ctx, filter, bson.M{"$set": bson.M{
+		"branch_id":  toBranchID,
+		"updated_at": time.Now(),
+	}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to move contracts: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *MongoMemberTransferRepository) MoveHistory(ctx context.Context, memberID, fromTenantID, toTenantID, toBranchID string) (int64, int64, error) {
+	filter := bson.M{"member_id": memberID, "tenant_id": fromTenantID}
+	set := bson.M{"$set": bson.M{
+		"tenant_id":  toTenantID,
+		"branch_id":  toBranchID,
+		"updated_at": time.Now(),
+	}}
+
+	contracts, err := r.db.Collection("pt_contracts").UpdateMany(ctx, filter, set)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to move contracts: %w", err)
+	}
+	schedules, err := r.db.Collection("schedules").UpdateMany(ctx, filter, set)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to move schedules: %w", err)
+	}
+	if _, err := r.db.Collection("workout_sessions").UpdateMany(ctx, filter, set); err != nil {
+		return 0, 0, fmt.Errorf("failed to move workout sessions: %w", err)
+	}
+	return contracts.ModifiedCount, schedules.ModifiedCount, nil
+}
+
+func (r *MongoMemberTransferRepository) CopyHistory(ctx context.Context, memberID, fromTenantID, toTenantID, toBranchID string) (int64, error) {
+	coll := r.db.Collection("schedules")
+	cursor, err := coll.Find(ctx, bson.M{
+		"member_id":  memberID,
+		"tenant_id":  fromTenantID,
+		"status":     bson.M{"$in": []string{domain.ScheduleStatusCompleted, domain.ScheduleStatusNoShow}},
+		"deleted_at": bson.M{"$exists": false},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schedules: %w", err)
+	}
+
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return 0, fmt.Errorf("failed to read schedules: %w", err)
+	}
+	if len(docs) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	copies := make([]interface{}, 0, len(docs))
+	for _, doc := range docs {
+		doc["copied_from"] = idString(doc["_id"])
+		doc["_id"] = newID()
+		doc["tenant_id"] = toTenantID
+		doc["branch_id"] = toBranchID
+		doc["updated_at"] = now
+		// The source contract and frontend ID stay with the source tenant
+		delete(doc, "contract_id")
+		delete(doc, "client_id")
+		copies = append(copies, doc)
+	}
+
+	result, err := coll.InsertMany(ctx, copies)
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy schedules: %w", err)
+	}
+	return int64(len(result.InsertedIDs)), nil
+}
+
+func (r *MongoMemberTransferRepository) Record(ctx context.Context, transfer *domain.MemberTransfer) error {
+	transfer.ID = newID()
+	if transfer.CreatedAt.IsZero() {
+		transfer.CreatedAt = time.Now()
+	}
+	if _, err := r.transfers.InsertOne(ctx, transfer); err != nil {
+		return fmt.Errorf("failed to record member transfer: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoMemberTransferRepository) ListByMember(ctx context.Context, memberID string) ([]*domain.MemberTransfer, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.transfers.Find(ctx, bson.M{"member_id": memberID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list member transfers: %w", err)
+	}
+	transfers := []*domain.MemberTransfer{}
+	if err := cursor.All(ctx, &transfers); err != nil {
+		return nil, fmt.Errorf("failed to list member transfers: %w", err)
+	}
+	return transfers, nil
+}
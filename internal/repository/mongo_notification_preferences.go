@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoNotificationPreferencesRepository keeps user preferences and tenant settings in two
+// collections, each keyed by the user or tenant ID
+type MongoNotificationPreferencesRepository struct {
+	users   *mongo.Collection
+	tenants *mongo.Collection
+}
+
+func NewMongoNotificationPreferencesRepository(db *mongo.Database) *MongoNotificationPreferencesRepository {
+	return &MongoNotificationPreferencesRepository{
+		users:   db.Collection("notification_preferences"),
+		tenants: db.Collection("tenant_notification_settings"),
+	}
+}
+
+func (r *MongoNotificationPreferencesRepository) GetUser(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+	var prefs domain.NotificationPreferences
+	err := r.users.FindOne(ctx, bson.M{"_id": userID}).Decode(&prefs)
+	if err == mongo.ErrNoDocuments {
+		return &domain.NotificationPreferences{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	return &prefs, nil
+}
+
+func (r *MongoNotificationPreferencesRepository) UpsertUser(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	prefs.UpdatedAt = time.Now()
+	_, err := r.users.ReplaceOne(ctx, bson.M{"_id": prefs.UserID}, prefs, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoNotificationPreferencesRepository) GetTenant(ctx context.Context, tenantID string) (*domain.TenantNotificationSettings, error) {
+	var settings domain.TenantNotificationSettings
+	err := r.tenants.FindOne(ctx, bson.M{"_id": tenantID}).Decode(&settings)
+	if err == mongo.ErrNoDocuments {
+		return &domain.TenantNotificationSettings{
+			TenantID:            tenantID,
+			ReminderLeadMinutes: slices.Clone(domain.DefaultReminderLeadMinutes),
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant notification settings: %w", err)
+	}
+	return &settings, nil
+}
+
+func (r *MongoNotificationPreferencesRepository) UpsertTenant(ctx context.Context, settings *domain.TenantNotificationSettings) error {
+	settings.UpdatedAt = time.Now()
+	_, err := r.tenants.ReplaceOne(ctx, bson.M{"_id": settings.TenantID}, settings, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save tenant notification settings: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoNotificationPreferencesRepository) ListTenants(ctx context.Context) ([]*domain.TenantNotificationSettings, error) {
+	cursor, err := r.tenants.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenant notification settings: %w", err)
+	}
+	var settings []*domain.TenantNotificationSettings
+	if err := cursor.All(ctx, &settings); err != nil {
+		return nil, fmt.Errorf("failed to list tenant notification settings: %w", err)
+	}
+	return settings, nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoOutboxRepository implements domain.OutboxRepository
+type MongoOutboxRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoOutboxRepository(db *mongo.Database) *MongoOutboxRepository {
+	coll := db.Collection("outbox")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		// Delivered messages are only kept for a week of debugging
+		{
+			Keys:    bson.D{{Key: "delivered_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(7 * 24 * 60 * 60),
+		},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create outbox indexes: %v\n", err)
+	}
+
+	return &MongoOutboxRepository{collection: coll}
+}
+
+func (r *MongoOutboxRepository) Add(ctx context.Context, msg *domain.OutboxMessage) error {
+	msg.ID = newID()
+	msg.Status = domain.OutboxStatusPending
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = time.Now()
+	}
+	if msg.NextAttemptAt.IsZero() {
+		msg.NextAttemptAt = msg.CreatedAt
+	}
+
+	if _, err := r.collection.InsertOne(ctx, msg); err != nil {
+		return fmt.Errorf("failed to add outbox message: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoOutboxRepository) Claim(ctx context.Context, now time.Time, lease time.Duration) (*domain.OutboxMessage, error) {
+	filter := bson.M{
+		"status":          domain.OutboxStatusPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{"next_attempt_at": now.Add(lease)},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var msg domain.OutboxMessage
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&msg)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox message: %w", err)
+	}
+	return &msg, nil
+}
+
+func (r *MongoOutboxRepository) MarkDelivered(ctx context.Context, id string, at time.Time) error {
+	update := bson.M{
+		"$set":   bson.M{"status": domain.OutboxStatusDelivered, "delivered_at": at},
+		"$unset": bson.M{"last_error": ""},
+	}
+	if _, err := r.collection.UpdateByID(ctx, id, update); err != nil {
+		return fmt.Errorf("failed to mark outbox message delivered: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoOutboxRepository) MarkFailed(ctx context.Context, id string, lastError string, nextAttemptAt time.Time, dead bool) error {
+	set := bson.M{"last_error": lastError, "next_attempt_at": nextAttemptAt}
+	if dead {
+		set["status"] = domain.OutboxStatusDead
+	}
+	if _, err := r.collection.UpdateByID(ctx, id, bson.M{"$set": set}); err != nil {
+		return fmt.Errorf("failed to mark outbox message failed: %w", err)
+	}
+	return nil
+}
@@ -37,6 +37,7 @@ func (r *MongoPackageRepository) Create(ctx context.Context, pkg *domain.Package
 		"price":           pkg.Price,
 		"duration_months": pkg.DurationMonths,
 		"is_active":       pkg.IsActive,
+		"pt_package_id":   pkg.PTPackageID,
 		"created_at":      pkg.CreatedAt,
 		"updated_at":      pkg.UpdatedAt,
 	}
@@ -87,6 +88,7 @@ func (r *MongoPackageRepository) Update(ctx context.Context, pkg *domain.Package
 			"price":           pkg.Price,
 			"duration_months": pkg.DurationMonths,
 			"is_active":       pkg.IsActive,
+			"pt_package_id":   pkg.PTPackageID,
 			"updated_at":      pkg.UpdatedAt,
 		},
 	}
@@ -123,7 +125,7 @@ func (r *MongoPackageRepository) SeedDefaultPackages(ctx context.Context) error
 		ID:             pkgID,
 		Name:           "Annual Pro Transformation",
 		Description:    "12 months of Pro access (10-for-12 deal)",
-		Price:          490000, // IDR 490,000
+		Price:          domain.NewMoney(490000, "IDR"),
 		DurationMonths: 12,
 		IsActive:       true,
 	}
@@ -132,7 +134,7 @@ func (r *MongoPackageRepository) SeedDefaultPackages(ctx context.Context) error
 		return fmt.Errorf("failed to seed package: %w", err)
 	}
 
-	log.Printf("[Seed] Created package: %s (%s) - Price: %d, Duration: %d months",
+	log.Printf("[Seed] Created package: %s (%s) - Price: %s, Duration: %d months",
 		pkg.ID, pkg.Name, pkg.Price, pkg.DurationMonths)
 
 	return nil
@@ -150,11 +152,7 @@ func mapBsonToPackage(raw bson.M) *domain.Package {
 	if desc, ok := raw["description"].(string); ok {
 		pkg.Description = desc
 	}
-	if price, ok := raw["price"].(int64); ok {
-		pkg.Price = price
-	} else if price, ok := raw["price"].(int32); ok {
-		pkg.Price = int64(price)
-	}
+	pkg.Price = moneyValue(raw["price"])
 	if duration, ok := raw["duration_months"].(int32); ok {
 		pkg.DurationMonths = int(duration)
 	} else if duration, ok := raw["duration_months"].(int64); ok {
@@ -163,6 +161,9 @@ func mapBsonToPackage(raw bson.M) *domain.Package {
 	if isActive, ok := raw["is_active"].(bool); ok {
 		pkg.IsActive = isActive
 	}
+	if ptPackageID, ok := raw["pt_package_id"].(string); ok {
+		pkg.PTPackageID = ptPackageID
+	}
 	if created, ok := raw["created_at"].(interface{ Time() time.Time }); ok {
 		pkg.CreatedAt = created.Time()
 	}
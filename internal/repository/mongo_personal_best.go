@@ -104,6 +104,29 @@ func (r *MongoPersonalBestRepository) GetByMember(ctx context.Context, memberID
 	return pbs, nil
 }
 
+// ReplaceForMember swaps all of a member's PBs for pbs, as recomputed from their sessions
+func (r *MongoPersonalBestRepository) ReplaceForMember(ctx context.Context, memberID string, pbs []*domain.PersonalBest) error {
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"member_id": memberID}); err != nil {
+		return err
+	}
+	if len(pbs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, len(pbs))
+	for i, pb := range pbs {
+		pb.ID = ""
+		pb.CreatedAt, pb.UpdatedAt = now, now
+		if pb.AchievedAt.IsZero() {
+			pb.AchievedAt = now
+		}
+		docs[i] = pb
+	}
+	_, err := r.collection.InsertMany(ctx, docs)
+	return err
+}
+
 // GetRecentPBsByMembers retrieves PBs achieved by members since a given date
 // Optimized with index {member_id: 1, achieved_at: -1}
 func (r *MongoPersonalBestRepository) GetRecentPBsByMembers(ctx context.Context, memberIDs []string, since time.Time) ([]*domain.PersonalBest, error) {
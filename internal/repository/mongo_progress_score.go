@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoProgressScoreRepository implements domain.ProgressScoreRepository. One document per
+// member and week; rescoring a week overwrites it.
+type MongoProgressScoreRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoProgressScoreRepository(db *mongo.Database) *MongoProgressScoreRepository {
+	coll := db.Collection("progress_scores")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "member_id", Value: 1}, {Key: "week_start", Value: -1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "week_start", Value: 1}, {Key: "score", Value: -1}}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create progress_scores indexes: %v\n", err)
+	}
+
+	return &MongoProgressScoreRepository{collection: coll}
+}
+
+func (r *MongoProgressScoreRepository) Upsert(ctx context.Context, score *domain.ProgressScore) error {
+	score.ID = fmt.Sprintf("%s:%s", score.MemberID, score.WeekStart.UTC().Format("2006-01-02"))
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": score.ID}, score, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save progress score: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoProgressScoreRepository) ListByMember(ctx context.Context, tenantID, memberID string, limit int) ([]*domain.ProgressScore, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "week_start", Value: -1}}).SetLimit(int64(limit))
+	return r.find(ctx, bson.M{"tenant_id": tenantID, "member_id": memberID}, opts)
+}
+
+func (r *MongoProgressScoreRepository) Leaderboard(ctx context.Context, tenantID string, weekStart time.Time, limit int) ([]*domain.ProgressScore, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "score", Value: -1}, {Key: "member_id", Value: 1}}).SetLimit(int64(limit))
+	return r.find(ctx, bson.M{"tenant_id": tenantID, "week_start": weekStart}, opts)
+}
+
+func (r *MongoProgressScoreRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*domain.ProgressScore, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list progress scores: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	scores := []*domain.ProgressScore{}
+	if err := cursor.All(ctx, &scores); err != nil {
+		return nil, err
+	}
+	return scores, nil
+}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/mansoorceksport/metamorph/internal/domain"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -18,33 +17,30 @@ type MongoPTContractRepository struct {
 
 func NewMongoPTContractRepository(db *mongo.Database) *MongoPTContractRepository {
 	return &MongoPTContractRepository{
-		collection: db.Collection("pt_contracts"),
+		collection: db.Collection("pt_contracts", moneyCollection),
 	}
 }
 
 func (r *MongoPTContractRepository) Create(ctx context.Context, contract *domain.PTContract) error {
+	contract.ID = newID()
 	contract.CreatedAt = time.Now()
 	contract.UpdatedAt = time.Now()
 
-	result, err := r.collection.InsertOne(ctx, contract)
+	_, err := r.collection.InsertOne(ctx, contract)
 	if err != nil {
 		return fmt.Errorf("failed to create pt contract: %w", err)
 	}
-
-	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
-		contract.ID = oid.Hex()
-	}
 	return nil
 }
 
 func (r *MongoPTContractRepository) GetByID(ctx context.Context, id string) (*domain.PTContract, error) {
-	oid, err := primitive.ObjectIDFromHex(id)
+	docID, err := idValue(id)
 	if err != nil {
 		return nil, domain.ErrInvalidID
 	}
 
 	var contract domain.PTContract
-	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&contract)
+	err = r.collection.FindOne(ctx, bson.M{"_id": docID}).Decode(&contract)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrContractNotFound
@@ -90,6 +86,27 @@ func (r *MongoPTContractRepository) GetByTenant(ctx context.Context, tenantID st
 	return contracts, nil
 }
 
+// ListByTenant returns one page of a tenant's contracts, newest first
+func (r *MongoPTContractRepository) ListByTenant(ctx context.Context, tenantID string, q domain.PageQuery) (*domain.Page[*domain.PTContract], error) {
+	q = q.Normalized()
+	filter, err := pageFilter(bson.M{"tenant_id": tenantID}, q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, pageOptions(q))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contracts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var contracts []*domain.PTContract
+	if err := cursor.All(ctx, &contracts); err != nil {
+		return nil, err
+	}
+	return newPage(contracts, q, func(c *domain.PTContract) (time.Time, string) { return c.CreatedAt, c.ID }), nil
+}
+
 func (r *MongoPTContractRepository) GetActiveByCoach(ctx context.Context, coachID string) ([]*domain.PTContract, error) {
 	filter := bson.M{
 		"coach_id": coachID,
@@ -111,54 +128,49 @@ func (r *MongoPTContractRepository) GetActiveByCoach(ctx context.Context, coachI
 	return contracts, nil
 }
 
-// DecrementSession atomically decrements remaining_sessions and updates status if needed
-func (r *MongoPTContractRepository) DecrementSession(ctx context.Context, contractID string) error {
-	oid, err := primitive.ObjectIDFromHex(contractID)
+// SyncBalance writes the ledger balance onto the contract's remaining_sessions projection.
+// Writes carrying an older ledger sequence are ignored so out-of-order syncs can't regress it.
+// The status moves between Active and Depleted as the balance crosses zero.
+func (r *MongoPTContractRepository) SyncBalance(ctx context.Context, contractID string, remaining int, sequence int64) error {
+	docID, err := idValue(contractID)
 	if err != nil {
 		return domain.ErrInvalidID
 	}
 
-	// 1. Atomically decrement if > 0
-	filter := bson.M{
-		"_id":                oid,
-		"remaining_sessions": bson.M{"$gt": 0},
-	}
-	update := bson.M{
-		"$inc": bson.M{"remaining_sessions": -1},
-		"$set": bson.M{"updated_at": time.Now()},
+	status := bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", domain.PackageStatusDepleted}}, domain.PackageStatusActive, "$status"}}
+	if remaining == 0 {
+		status = bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", domain.PackageStatusActive}}, domain.PackageStatusDepleted, "$status"}}
 	}
 
-	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
-	var updatedContract domain.PTContract
-
-	err = r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updatedContract)
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return domain.ErrPackageDepleted // Effectively depleted or not found
-		}
-		return fmt.Errorf("failed to decrement session: %w", err)
+	filter := bson.M{
+		"_id": docID,
+		"$or": bson.A{
+			bson.M{"ledger_sequence": bson.M{"$lt": sequence}},
+			bson.M{"ledger_sequence": bson.M{"$exists": false}},
+		},
 	}
-
-	// 2. If reached 0, update status to Depleted
-	if updatedContract.RemainingSessions == 0 {
-		_, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{
-			"$set": bson.M{"status": domain.PackageStatusDepleted},
-		})
-		if err != nil {
-			fmt.Printf("Warning: Failed to update status to Depleted for contract %s: %v\n", contractID, err)
-		}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"remaining_sessions": remaining,
+			"ledger_sequence":    sequence,
+			"status":             status,
+			"updated_at":         time.Now(),
+		}}},
 	}
 
+	if _, err := r.collection.UpdateOne(ctx, filter, update); err != nil {
+		return fmt.Errorf("failed to sync contract balance: %w", err)
+	}
 	return nil
 }
 
 func (r *MongoPTContractRepository) UpdateStatus(ctx context.Context, contractID string, status string) error {
-	oid, err := primitive.ObjectIDFromHex(contractID)
+	docID, err := idValue(contractID)
 	if err != nil {
 		return domain.ErrInvalidID
 	}
 
-	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": docID}, bson.M{
 		"$set": bson.M{
 			"status":     status,
 			"updated_at": time.Now(),
@@ -231,6 +243,8 @@ func (r *MongoPTContractRepository) GetActiveContractsWithMembers(ctx context.Co
 			"path":                       "$member_combined",
 			"preserveNullAndEmptyArrays": true,
 		}}},
+		// Leave out soft-deleted members
+		{{Key: "$match", Value: bson.M{"member_combined.deleted_at": bson.M{"$exists": false}}}},
 		// Project final shape
 		{{Key: "$project", Value: bson.M{
 			"contract": bson.M{
@@ -312,3 +326,245 @@ func (r *MongoPTContractRepository) GetByMemberAndCoach(ctx context.Context, mem
 
 	return contracts, nil
 }
+
+// GetByMemberAndPackage returns the member's contracts on a PT package, newest first
+func (r *MongoPTContractRepository) GetByMemberAndPackage(ctx context.Context, memberID, packageID string) ([]*domain.PTContract, error) {
+	filter := bson.M{
+		"member_id":  memberID,
+		"package_id": packageID,
+	}
+	opts := options.Find().SetSort(bson.M{"created_at": -1})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find contracts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var contracts []*domain.PTContract
+	if err := cursor.All(ctx, &contracts); err != nil {
+		return nil, fmt.Errorf("failed to decode contracts: %w", err)
+	}
+	return contracts, nil
+}
+
+// AddCoverCoach records a substitute on the contract, once per coach
+func (r *MongoPTContractRepository) AddCoverCoach(ctx context.Context, contractID, coachID string) error {
+	docID, err := idValue(contractID)
+	if err != nil {
+		return domain.ErrInvalidID
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": docID}, bson.M{
+		"$addToSet": bson.M{"cover_coach_ids": coachID},
+		"$set":      bson.M{"updated_at": time.Now()},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record cover coach: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoPTContractRepository) ReassignCoach(ctx context.Context, contractID, fromCoachID, toCoachID string) (bool, error) {
+	docID, err := idValue(contractID)
+	if err != nil {
+		return false, domain.ErrInvalidID
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": docID, "coach_id": fromCoachID}, bson.M{
+		"$set": bson.M{"coach_id": toCoachID, "updated_at": time.Now()},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to reassign contract: %w", err)
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *MongoPTContractRepository) ListExpiringBefore(ctx context.Context, before time.Time) ([]*domain.PTContract, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"expires_at": bson.M{"$lt": before},
+		"status":     bson.M{"$in": []string{domain.PackageStatusActive, domain.PackageStatusSuspended}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expiring contracts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var contracts []*domain.PTContract
+	if err := cursor.All(ctx, &contracts); err != nil {
+		return nil, err
+	}
+	return contracts, nil
+}
+
+func (r *MongoPTContractRepository) MarkExpiryWarned(ctx context.Context, contractID string, at time.Time) (bool, error) {
+	docID, err := idValue(contractID)
+	if err != nil {
+		return false, domain.ErrInvalidID
+	}
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": docID, "expiry_warned_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"expiry_warned_at": at, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark expiry warned: %w", err)
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *MongoPTContractRepository) SetMetricVisibility(ctx context.Context, contractID string, visibility *domain.MetricVisibility) error {
+	docID, err := idValue(contractID)
+	if err != nil {
+		return domain.ErrInvalidID
+	}
+
+	update := bson.M{"$set": bson.M{"metric_visibility": visibility, "updated_at": time.Now()}}
+	if visibility == nil {
+		update = bson.M{"$unset": bson.M{"metric_visibility": ""}, "$set": bson.M{"updated_at": time.Now()}}
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": docID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to set metric visibility: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrContractNotFound
+	}
+	return nil
+}
+
+func (r *MongoPTContractRepository) SetAutoRenew(ctx context.Context, contractID string, renewal *domain.AutoRenewal) error {
+	docID, err := idValue(contractID)
+	if err != nil {
+		return domain.ErrInvalidID
+	}
+
+	update := bson.M{"$set": bson.M{"auto_renew": renewal, "updated_at": time.Now()}}
+	if renewal == nil {
+		update = bson.M{"$unset": bson.M{"auto_renew": ""}, "$set": bson.M{"updated_at": time.Now()}}
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": docID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to set auto-renewal: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrContractNotFound
+	}
+	return nil
+}
+
+func (r *MongoPTContractRepository) SetCustomFields(ctx context.Context, contractID string, values domain.CustomFieldValues) error {
+	docID, err := idValue(contractID)
+	if err != nil {
+		return domain.ErrInvalidID
+	}
+
+	update := bson.M{"$set": bson.M{"custom_fields": values, "updated_at": time.Now()}}
+	if values == nil {
+		update = bson.M{"$unset": bson.M{"custom_fields": ""}, "$set": bson.M{"updated_at": time.Now()}}
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": docID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to set custom fields: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrContractNotFound
+	}
+	return nil
+}
+
+func (r *MongoPTContractRepository) ClaimRenewal(ctx context.Context, contractID string, at time.Time) (bool, error) {
+	docID, err := idValue(contractID)
+	if err != nil {
+		return false, domain.ErrInvalidID
+	}
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": docID, "renewed_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"renewed_at": at, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim renewal: %w", err)
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *MongoPTContractRepository) ReleaseRenewal(ctx context.Context, contractID string) error {
+	docID, err := idValue(contractID)
+	if err != nil {
+		return domain.ErrInvalidID
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": docID},
+		bson.M{"$unset": bson.M{"renewed_at": ""}, "$set": bson.M{"updated_at": time.Now()}})
+	if err != nil {
+		return fmt.Errorf("failed to release renewal: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoPTContractRepository) ListRenewalsDue(ctx context.Context, now time.Time) ([]*domain.PTContract, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"auto_renew": bson.M{"$exists": true},
+		"renewed_at": bson.M{"$exists": false},
+		"status":     bson.M{"$in": []string{domain.PackageStatusActive, domain.PackageStatusDepleted, domain.PackageStatusExpired}},
+		"$or": bson.A{
+			bson.M{"remaining_sessions": bson.M{"$lte": 0}},
+			bson.M{"expires_at": bson.M{"$lte": now}},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list renewals due: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var contracts []*domain.PTContract
+	if err := cursor.All(ctx, &contracts); err != nil {
+		return nil, err
+	}
+	return contracts, nil
+}
+
+func (r *MongoPTContractRepository) ListRenewalsByCoach(ctx context.Context, coachID string) ([]*domain.PTContract, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"coach_id": coachID,
+		"$or": bson.A{
+			bson.M{
+				"auto_renew": bson.M{"$exists": true},
+				"renewed_at": bson.M{"$exists": false},
+				"status":     bson.M{"$in": []string{domain.PackageStatusActive, domain.PackageStatusSuspended}},
+			},
+			bson.M{"status": domain.PackageStatusPendingPayment},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list renewals: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var contracts []*domain.PTContract
+	if err := cursor.All(ctx, &contracts); err != nil {
+		return nil, err
+	}
+	return contracts, nil
+}
+
+func (r *MongoPTContractRepository) ActivatePending(ctx context.Context, contractID string, expiresAt *time.Time) (bool, error) {
+	docID, err := idValue(contractID)
+	if err != nil {
+		return false, domain.ErrInvalidID
+	}
+
+	set := bson.M{"status": domain.PackageStatusActive, "updated_at": time.Now()}
+	if expiresAt != nil {
+		set["expires_at"] = expiresAt
+	}
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": docID, "status": domain.PackageStatusPendingPayment},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to activate contract: %w", err)
+	}
+	return result.ModifiedCount == 1, nil
+}
@@ -17,7 +17,7 @@ type MongoPTPackageRepository struct {
 
 func NewMongoPTPackageRepository(db *mongo.Database) *MongoPTPackageRepository {
 	return &MongoPTPackageRepository{
-		collection: db.Collection("pt_packages"), // Templates
+		collection: db.Collection("pt_packages", moneyCollection), // Templates
 	}
 }
 
@@ -80,11 +80,13 @@ func (r *MongoPTPackageRepository) Update(ctx context.Context, pkg *domain.PTPac
 
 	update := bson.M{
 		"$set": bson.M{
-			"name":           pkg.Name,
-			"total_sessions": pkg.TotalSessions,
-			"price":          pkg.Price,
-			"active":         pkg.Active,
-			"updated_at":     pkg.UpdatedAt,
+			"name":            pkg.Name,
+			"total_sessions":  pkg.TotalSessions,
+			"price":           pkg.Price,
+			"branch_prices":   pkg.BranchPrices,
+			"validity_months": pkg.ValidityMonths,
+			"active":          pkg.Active,
+			"updated_at":      pkg.UpdatedAt,
 		},
 	}
 
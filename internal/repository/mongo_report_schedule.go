@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoReportScheduleRepository implements domain.ReportScheduleRepository
+type MongoReportScheduleRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoReportScheduleRepository(db *mongo.Database) *MongoReportScheduleRepository {
+	coll := db.Collection("report_schedules")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "paused", Value: 1}, {Key: "next_run_at", Value: 1}}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create report_schedules indexes: %v\n", err)
+	}
+
+	return &MongoReportScheduleRepository{collection: coll}
+}
+
+func (r *MongoReportScheduleRepository) Create(ctx context.Context, schedule *domain.ReportSchedule) error {
+	schedule.ID = newID()
+	now := time.Now()
+	schedule.CreatedAt, schedule.UpdatedAt = now, now
+	if _, err := r.collection.InsertOne(ctx, schedule); err != nil {
+		return fmt.Errorf("failed to create report schedule: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoReportScheduleRepository) GetByID(ctx context.Context, id string) (*domain.ReportSchedule, error) {
+	var schedule domain.ReportSchedule
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&schedule)
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report schedule: %w", err)
+	}
+	return &schedule, nil
+}
+
+func (r *MongoReportScheduleRepository) ListByTenant(ctx context.Context, tenantID string) ([]*domain.ReportSchedule, error) {
+	return r.find(ctx, bson.M{"tenant_id": tenantID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+}
+
+func (r *MongoReportScheduleRepository) ListDue(ctx context.Context, now time.Time) ([]*domain.ReportSchedule, error) {
+	return r.find(ctx, bson.M{"paused": false, "next_run_at": bson.M{"$lte": now}}, options.Find().SetSort(bson.D{{Key: "next_run_at", Value: 1}}))
+}
+
+func (r *MongoReportScheduleRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*domain.ReportSchedule, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list report schedules: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	schedules := []*domain.ReportSchedule{}
+	if err := cursor.All(ctx, &schedules); err != nil {
+		return nil, fmt.Errorf("failed to decode report schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+func (r *MongoReportScheduleRepository) Update(ctx context.Context, schedule *domain.ReportSchedule) error {
+	schedule.UpdatedAt = time.Now()
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": schedule.ID}, schedule)
+	if err != nil {
+		return fmt.Errorf("failed to update report schedule: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoReportScheduleRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete report schedule: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoReportScheduleRepository) Claim(ctx context.Context, id string, due, next time.Time) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "next_run_at": due},
+		bson.M{"$set": bson.M{"next_run_at": next}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim report schedule: %w", err)
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *MongoReportScheduleRepository) RecordRun(ctx context.Context, id string, ranAt time.Time, runErr string) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"last_run_at": ranAt, "last_error": runErr}})
+	if err != nil {
+		return fmt.Errorf("failed to record report run: %w", err)
+	}
+	return nil
+}
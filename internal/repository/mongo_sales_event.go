@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoSalesEventRepository implements domain.SalesEventRepository. Events are append-only.
+type MongoSalesEventRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoSalesEventRepository(db *mongo.Database) *MongoSalesEventRepository {
+	coll := db.Collection("sales_events")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "occurred_at", Value: 1}}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create sales_events indexes: %v\n", err)
+	}
+
+	return &MongoSalesEventRepository{collection: coll}
+}
+
+func (r *MongoSalesEventRepository) Record(ctx context.Context, event *domain.SalesEvent) error {
+	event.ID = newID()
+	if _, err := r.collection.InsertOne(ctx, event); err != nil {
+		return fmt.Errorf("failed to record sales event: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoSalesEventRepository) CountByStage(ctx context.Context, tenantID string, from, to time.Time) ([]domain.SalesStageCount, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"tenant_id":   tenantID,
+			"occurred_at": bson.M{"$gte": from, "$lt": to},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     bson.M{"package_id": "$package_id", "stage": "$stage"},
+			"events":  bson.M{"$sum": 1},
+			"members": bson.M{"$addToSet": "$user_id"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":        0,
+			"package_id": "$_id.package_id",
+			"stage":      "$_id.stage",
+			"events":     1,
+			"members":    bson.M{"$size": "$members"},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate sales events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	counts := []domain.SalesStageCount{}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, fmt.Errorf("failed to decode sales event counts: %w", err)
+	}
+	return counts, nil
+}
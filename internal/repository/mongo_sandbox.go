@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// sandboxTenantCollections hold activity with a tenant_id; a reset empties them for the tenant.
+// Set-up data (branches, packages, equipment, documents, widget tokens, settings) is kept.
+var sandboxTenantCollections = []string{
+	"pt_contracts",
+	"credit_transactions",
+	"contract_agreements",
+	"document_acceptances",
+	"guardian_consents",
+	"schedules",
+	"workout_sessions",
+	"workout_events",
+	"daily_volumes",
+	"assessments",
+	"progress_scores",
+	"set_videos",
+	"coach_availability",
+	"coach_assignments",
+	"substitution_offers",
+	"invoices",
+	"settlement_reconciliations",
+	"sales_events",
+	"gym_imports",
+	"notifications",
+	"member_timeline",
+	"session_photos",
+	"session_photo_consents",
+	"share_links",
+	"handovers",
+	"client_notes",
+	"member_invites",
+}
+
+// sandboxMemberCollections are keyed by user instead of tenant: collection -> user field
+var sandboxMemberCollections = []struct {
+	collection string
+	field      string
+}{
+	{collectionName, "user_id"}, // inbody_records
+	{"scan_revisions", "user_id"},
+	{"trend_summaries", "user_id"},
+	{"set_logs", "member_id"},
+	{"personal_bests", "member_id"},
+	{"refresh_tokens", "user_id"},
+	{"notification_preferences", "_id"},
+	{"device_tokens", "user_id"},
+}
+
+// MongoSandboxRepository implements domain.SandboxRepository
+type MongoSandboxRepository struct {
+	db       *mongo.Database
+	captured *mongo.Collection
+}
+
+func NewMongoSandboxRepository(db *mongo.Database) *MongoSandboxRepository {
+	coll := db.Collection("sandbox_notifications")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create sandbox_notifications indexes: %v\n", err)
+	}
+
+	return &MongoSandboxRepository{db: db, captured: coll}
+}
+
+func (r *MongoSandboxRepository) CaptureNotification(ctx context.Context, n *domain.CapturedNotification) error {
+	n.ID = newID()
+	if n.CreatedAt.IsZero() {
+		n.CreatedAt = time.Now()
+	}
+	if _, err := r.captured.InsertOne(ctx, n); err != nil {
+		return fmt.Errorf("failed to capture notification: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoSandboxRepository) ListNotifications(ctx context.Context, tenantID string, q domain.PageQuery) (*domain.Page[*domain.CapturedNotification], error) {
+	q = q.Normalized()
+
+	filter, err := pageFilter(bson.M{"tenant_id": tenantID}, q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := r.captured.Find(ctx, filter, pageOptions(q))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list captured notifications: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var items []*domain.CapturedNotification
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return newPage(items, q, func(n *domain.CapturedNotification) (time.Time, string) { return n.CreatedAt, n.ID }), nil
+}
+
+func (r *MongoSandboxRepository) Reset(ctx context.Context, tenantID string) (*domain.SandboxReset, error) {
+	users := r.db.Collection("users")
+	userFilter := bson.M{"tenant_id": tenantID, "roles": bson.M{"$ne": domain.RoleTenantAdmin}}
+
+	cursor, err := users.Find(ctx, userFilter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sandbox users: %w", err)
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	// Member-keyed records store the user ID either as it is in _id or as its string form
+	userKeys := make([]interface{}, 0, 2*len(docs))
+	for _, doc := range docs {
+		userKeys = append(userKeys, doc["_id"])
+		if id := idString(doc["_id"]); id != "" {
+			userKeys = append(userKeys, id)
+		}
+	}
+
+	// Planned exercises only know their schedule
+	scheduleIDs, err := r.db.Collection("schedules").Distinct(ctx, "_id", bson.M{"tenant_id": tenantID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sandbox schedules: %w", err)
+	}
+	scheduleKeys := make([]interface{}, 0, len(scheduleIDs))
+	for _, id := range scheduleIDs {
+		if s := idString(id); s != "" {
+			scheduleKeys = append(scheduleKeys, s)
+		}
+	}
+
+	reset := &domain.SandboxReset{}
+	deleteMany := func(collection string, filter bson.M) error {
+		result, err := r.db.Collection(collection).DeleteMany(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to reset %s: %w", collection, err)
+		}
+		reset.Records += result.DeletedCount
+		return nil
+	}
+
+	if len(scheduleKeys) > 0 {
+		if err := deleteMany("planned_exercises", bson.M{"schedule_id": bson.M{"$in": scheduleKeys}}); err != nil {
+			return reset, err
+		}
+	}
+	if len(userKeys) > 0 {
+		for _, c := range sandboxMemberCollections {
+			if err := deleteMany(c.collection, bson.M{c.field: bson.M{"$in": userKeys}}); err != nil {
+				return reset, err
+			}
+		}
+	}
+	for _, name := range sandboxTenantCollections {
+		if err := deleteMany(name, bson.M{"tenant_id": tenantID}); err != nil {
+			return reset, err
+		}
+	}
+
+	result, err := users.DeleteMany(ctx, userFilter)
+	if err != nil {
+		return reset, fmt.Errorf("failed to reset users: %w", err)
+	}
+	reset.Users = result.DeletedCount
+
+	result, err = r.captured.DeleteMany(ctx, bson.M{"tenant_id": tenantID})
+	if err != nil {
+		return reset, fmt.Errorf("failed to reset captured notifications: %w", err)
+	}
+	reset.Notifications = result.DeletedCount
+	return reset, nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoScanRevisionRepository implements domain.ScanRevisionRepository
+type MongoScanRevisionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoScanRevisionRepository(db *mongo.Database) *MongoScanRevisionRepository {
+	coll := db.Collection("scan_revisions")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "scan_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create scan_revisions indexes: %v\n", err)
+	}
+
+	return &MongoScanRevisionRepository{collection: coll}
+}
+
+func (r *MongoScanRevisionRepository) Create(ctx context.Context, revision *domain.ScanRevision) error {
+	revision.ID = newID()
+	if revision.CreatedAt.IsZero() {
+		revision.CreatedAt = time.Now()
+	}
+	if _, err := r.collection.InsertOne(ctx, revision); err != nil {
+		return fmt.Errorf("failed to create scan revision: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoScanRevisionRepository) GetByID(ctx context.Context, id string) (*domain.ScanRevision, error) {
+	var revision domain.ScanRevision
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&revision)
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrScanRevisionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scan revision: %w", err)
+	}
+	return &revision, nil
+}
+
+func (r *MongoScanRevisionRepository) ListByScanID(ctx context.Context, scanID string) ([]*domain.ScanRevision, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"scan_id": scanID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scan revisions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	revisions := []*domain.ScanRevision{}
+	if err := cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
@@ -3,47 +3,54 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mansoorceksport/metamorph/internal/domain"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 type MongoScheduleRepository struct {
 	collection *mongo.Collection
+	analytics  *mongo.Collection
 }
 
 func NewMongoScheduleRepository(db *mongo.Database) *MongoScheduleRepository {
+	coll := db.Collection("schedules")
 	return &MongoScheduleRepository{
-		collection: db.Collection("schedules"),
+		collection: coll,
+		analytics:  coll,
 	}
 }
 
+// RouteAnalyticsReads sends the attendance and schedule stats aggregations to pref.
+// Schedule lookups used for completing or rescheduling sessions stay on the primary.
+func (r *MongoScheduleRepository) RouteAnalyticsReads(pref *readpref.ReadPref) {
+	r.analytics = withReadPreference(r.collection, pref)
+}
+
 func (r *MongoScheduleRepository) Create(ctx context.Context, schedule *domain.Schedule) error {
+	schedule.ID = newID()
 	schedule.CreatedAt = time.Now()
 	schedule.UpdatedAt = time.Now()
 
-	result, err := r.collection.InsertOne(ctx, schedule)
+	_, err := r.collection.InsertOne(ctx, schedule)
 	if err != nil {
 		return fmt.Errorf("failed to create schedule: %w", err)
 	}
-
-	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
-		schedule.ID = oid.Hex()
-	}
 	return nil
 }
 
 func (r *MongoScheduleRepository) GetByID(ctx context.Context, id string) (*domain.Schedule, error) {
-	oid, err := primitive.ObjectIDFromHex(id)
+	docID, err := idValue(id)
 	if err != nil {
 		return nil, domain.ErrInvalidID
 	}
 
 	var schedule domain.Schedule
-	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&schedule)
+	err = r.collection.FindOne(ctx, bson.M{"_id": docID}).Decode(&schedule)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrScheduleNotFound
@@ -139,6 +146,26 @@ func (r *MongoScheduleRepository) GetByMember(ctx context.Context, memberID stri
 	return schedules, nil
 }
 
+func (r *MongoScheduleRepository) ListStartingBetween(ctx context.Context, from, to time.Time, statuses []string) ([]*domain.Schedule, error) {
+	filter := bson.M{
+		"start_time": bson.M{"$gte": from, "$lt": to},
+		"status":     bson.M{"$in": statuses},
+		"deleted_at": bson.M{"$exists": false},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var schedules []*domain.Schedule
+	if err := cursor.All(ctx, &schedules); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
 func (r *MongoScheduleRepository) List(ctx context.Context, tenantID string, filterOpts map[string]interface{}) ([]*domain.Schedule, error) {
 	filter := bson.M{"tenant_id": tenantID}
 	for k, v := range filterOpts {
@@ -161,8 +188,33 @@ func (r *MongoScheduleRepository) List(ctx context.Context, tenantID string, fil
 	return schedules, nil
 }
 
+// ListPage is the cursor-paginated form of List, newest first
+func (r *MongoScheduleRepository) ListPage(ctx context.Context, tenantID string, filterOpts map[string]interface{}, q domain.PageQuery) (*domain.Page[*domain.Schedule], error) {
+	q = q.Normalized()
+	base := bson.M{"tenant_id": tenantID}
+	for k, v := range filterOpts {
+		base[k] = v
+	}
+	filter, err := pageFilter(base, q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, pageOptions(q))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var schedules []*domain.Schedule
+	if err := cursor.All(ctx, &schedules); err != nil {
+		return nil, err
+	}
+	return newPage(schedules, q, func(s *domain.Schedule) (time.Time, string) { return s.CreatedAt, s.ID }), nil
+}
+
 func (r *MongoScheduleRepository) Update(ctx context.Context, schedule *domain.Schedule) error {
-	oid, err := primitive.ObjectIDFromHex(schedule.ID)
+	docID, err := idValue(schedule.ID)
 	if err != nil {
 		return domain.ErrInvalidID
 	}
@@ -170,26 +222,36 @@ func (r *MongoScheduleRepository) Update(ctx context.Context, schedule *domain.S
 
 	update := bson.M{
 		"$set": bson.M{
-			"start_time": schedule.StartTime,
-			"end_time":   schedule.EndTime,
-			"status":     schedule.Status,
-			"remarks":    schedule.Remarks,
-			"focus_area": schedule.FocusArea,
-			"updated_at": schedule.UpdatedAt,
+			"start_time":  schedule.StartTime,
+			"end_time":    schedule.EndTime,
+			"status":      schedule.Status,
+			"remarks":     schedule.Remarks,
+			"focus_area":  schedule.FocusArea,
+			"plan_notes":  schedule.PlanNotes,
+			"tags":        schedule.Tags,
+			"label":       schedule.Label,
+			"color":       schedule.Color,
+			"modality":    schedule.Modality,
+			"meeting_url": schedule.MeetingURL,
+			"effort":      schedule.Effort,
+			"updated_at":  schedule.UpdatedAt,
 		},
 	}
+	if schedule.PlanShared != nil {
+		update["$set"].(bson.M)["plan_shared_at"] = schedule.PlanShared
+	}
 
-	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": oid}, update)
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": docID}, update)
 	return err
 }
 
 func (r *MongoScheduleRepository) UpdateStatus(ctx context.Context, id string, status string) error {
-	oid, err := primitive.ObjectIDFromHex(id)
+	docID, err := idValue(id)
 	if err != nil {
 		return domain.ErrInvalidID
 	}
 
-	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": docID}, bson.M{
 		"$set": bson.M{
 			"status":     status,
 			"updated_at": time.Now(),
@@ -198,13 +260,77 @@ func (r *MongoScheduleRepository) UpdateStatus(ctx context.Context, id string, s
 	return err
 }
 
+func (r *MongoScheduleRepository) SetConfirmation(ctx context.Context, id string, confirmation *domain.SessionConfirmation) error {
+	docID, err := idValue(id)
+	if err != nil {
+		return domain.ErrInvalidID
+	}
+
+	update := bson.M{"$set": bson.M{"confirmation": confirmation, "updated_at": time.Now()}}
+	if confirmation == nil {
+		update = bson.M{
+			"$set":   bson.M{"updated_at": time.Now()},
+			"$unset": bson.M{"confirmation": "", "coach_alerted_at": ""},
+		}
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": docID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to save confirmation: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrScheduleNotFound
+	}
+	return nil
+}
+
+func (r *MongoScheduleRepository) MarkCoachAlerted(ctx context.Context, id string, at time.Time) (bool, error) {
+	docID, err := idValue(id)
+	if err != nil {
+		return false, domain.ErrInvalidID
+	}
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": docID, "confirmation": nil, "coach_alerted_at": nil},
+		bson.M{"$set": bson.M{"coach_alerted_at": at}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark coach alerted: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (r *MongoScheduleRepository) Reassign(ctx context.Context, id, fromCoachID, toCoachID, coveredFor string) (bool, error) {
+	docID, err := idValue(id)
+	if err != nil {
+		return false, domain.ErrInvalidID
+	}
+
+	update := bson.M{"$set": bson.M{"coach_id": toCoachID, "covered_for": coveredFor, "updated_at": time.Now()}}
+	if coveredFor == "" {
+		update = bson.M{
+			"$set":   bson.M{"coach_id": toCoachID, "updated_at": time.Now()},
+			"$unset": bson.M{"covered_for": ""},
+		}
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{
+		"_id":        docID,
+		"coach_id":   fromCoachID,
+		"status":     bson.M{"$in": []string{domain.ScheduleStatusScheduled, domain.ScheduleStatusPendingConfirmation}},
+		"deleted_at": nil,
+	}, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to reassign schedule: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
 func (r *MongoScheduleRepository) Delete(ctx context.Context, id string) error {
-	oid, err := primitive.ObjectIDFromHex(id)
+	docID, err := idValue(id)
 	if err != nil {
 		return domain.ErrInvalidID
 	}
 
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": oid})
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": docID})
 	if err != nil {
 		return fmt.Errorf("failed to delete schedule: %w", err)
 	}
@@ -271,7 +397,7 @@ func (r *MongoScheduleRepository) GetAttendanceByCoach(ctx context.Context, coac
 		"start_time": bson.M{"$gte": since},
 	}
 
-	cursor, err := r.collection.Find(ctx, filter)
+	cursor, err := r.analytics.Find(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -284,15 +410,32 @@ func (r *MongoScheduleRepository) GetAttendanceByCoach(ctx context.Context, coac
 	return schedules, nil
 }
 
+func (r *MongoScheduleRepository) ListChangedByCoach(ctx context.Context, coachID string, since time.Time) ([]*domain.Schedule, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"coach_id":   coachID,
+		"updated_at": bson.M{"$gt": since},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed schedules: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var schedules []*domain.Schedule
+	if err := cursor.All(ctx, &schedules); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
 // SoftDelete sets the deleted_at timestamp instead of removing the document
 func (r *MongoScheduleRepository) SoftDelete(ctx context.Context, id string) error {
-	oid, err := primitive.ObjectIDFromHex(id)
+	docID, err := idValue(id)
 	if err != nil {
 		return domain.ErrInvalidID
 	}
 
 	now := time.Now()
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": docID}, bson.M{
 		"$set": bson.M{
 			"deleted_at": now,
 			"updated_at": now,
@@ -307,12 +450,14 @@ func (r *MongoScheduleRepository) SoftDelete(ctx context.Context, id string) err
 	return nil
 }
 
-// GetMemberScheduleStats returns schedule status counts for a member
+// GetMemberScheduleStats returns PT session status counts for a member; self-logged
+// workouts aren't sessions with a coach and don't count
 func (r *MongoScheduleRepository) GetMemberScheduleStats(ctx context.Context, memberID string) (completed int, cancelled int, noShow int, err error) {
 	pipeline := mongo.Pipeline{
 		{{Key: "$match", Value: bson.M{
-			"member_id":  memberID,
-			"deleted_at": bson.M{"$exists": false}, // Exclude soft-deleted
+			"member_id":   memberID,
+			"deleted_at":  bson.M{"$exists": false}, // Exclude soft-deleted
+			"self_logged": bson.M{"$ne": true},
 		}}},
 		{{Key: "$group", Value: bson.M{
 			"_id":   "$status",
@@ -320,7 +465,7 @@ func (r *MongoScheduleRepository) GetMemberScheduleStats(ctx context.Context, me
 		}}},
 	}
 
-	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	cursor, err := r.analytics.Aggregate(ctx, pipeline)
 	if err != nil {
 		return 0, 0, 0, fmt.Errorf("failed to aggregate member stats: %w", err)
 	}
@@ -349,3 +494,52 @@ func (r *MongoScheduleRepository) GetMemberScheduleStats(ctx context.Context, me
 
 	return completed, cancelled, noShow, nil
 }
+
+// CountByTag unwinds the tags of the tenant's PT sessions in the period and counts each
+// tag's sessions by status
+func (r *MongoScheduleRepository) CountByTag(ctx context.Context, tenantID, coachID string, from, to time.Time) ([]domain.ScheduleTagCount, error) {
+	match := bson.M{
+		"tenant_id":   tenantID,
+		"start_time":  bson.M{"$gte": from, "$lt": to},
+		"tags.0":      bson.M{"$exists": true},
+		"deleted_at":  bson.M{"$exists": false},
+		"self_logged": bson.M{"$ne": true},
+	}
+	if coachID != "" {
+		match["coach_id"] = coachID
+	}
+	// The coach app writes some statuses in lowercase, so they're compared case-insensitively
+	countStatus := func(statuses ...string) bson.M {
+		lower := make(bson.A, len(statuses))
+		for i, status := range statuses {
+			lower[i] = strings.ToLower(status)
+		}
+		return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$in": bson.A{bson.M{"$toLower": "$status"}, lower}}, 1, 0}}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       "$tags",
+			"total":     bson.M{"$sum": 1},
+			"scheduled": countStatus(domain.ScheduleStatusScheduled, domain.ScheduleStatusPendingConfirmation),
+			"completed": countStatus(domain.ScheduleStatusCompleted),
+			"cancelled": countStatus(domain.ScheduleStatusCancelled),
+			"no_show":   countStatus(domain.ScheduleStatusNoShow),
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "total", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := r.analytics.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate schedule tags: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	counts := []domain.ScheduleTagCount{}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, fmt.Errorf("failed to decode schedule tag counts: %w", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoSearchRepository implements domain.SearchRepository with a text index on each
+// collection searched. A collection has at most one, so these are the only ones.
+type MongoSearchRepository struct {
+	users     *mongo.Collection
+	schedules *mongo.Collection
+	exercises *mongo.Collection
+	scans     *mongo.Collection
+}
+
+func NewMongoSearchRepository(db *mongo.Database) *MongoSearchRepository {
+	r := &MongoSearchRepository{
+		users:     db.Collection("users"),
+		schedules: db.Collection("schedules"),
+		exercises: db.Collection("exercises"),
+		scans:     db.Collection(collectionName),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Weights rank a match in a name or title above one in notes
+	indexes := map[*mongo.Collection]mongo.IndexModel{
+		r.users: {
+			Keys:    bson.D{{Key: "name", Value: "text"}, {Key: "email", Value: "text"}},
+			Options: options.Index().SetName("search_text").SetWeights(bson.D{{Key: "name", Value: 10}, {Key: "email", Value: 5}}),
+		},
+		r.schedules: {
+			Keys: bson.D{
+				{Key: "session_goal", Value: "text"}, {Key: "label", Value: "text"}, {Key: "tags", Value: "text"},
+				{Key: "focus_area", Value: "text"}, {Key: "remarks", Value: "text"}, {Key: "plan_notes", Value: "text"},
+			},
+			Options: options.Index().SetName("search_text").SetWeights(bson.D{
+				{Key: "session_goal", Value: 10}, {Key: "label", Value: 10}, {Key: "tags", Value: 5}, {Key: "focus_area", Value: 5},
+			}),
+		},
+		r.exercises: {
+			Keys:    bson.D{{Key: "name", Value: "text"}, {Key: "muscle_group", Value: "text"}, {Key: "equipment", Value: "text"}},
+			Options: options.Index().SetName("search_text").SetWeights(bson.D{{Key: "name", Value: 10}, {Key: "muscle_group", Value: 3}}),
+		},
+		r.scans: {
+			Keys: bson.D{
+				{Key: "analysis.summary", Value: "text"}, {Key: "analysis.improvements", Value: "text"},
+				{Key: "analysis.advice", Value: "text"}, {Key: "analysis.positive_feedback", Value: "text"},
+			},
+			Options: options.Index().SetName("search_text"),
+		},
+	}
+	for coll, index := range indexes {
+		if _, err := coll.Indexes().CreateOne(ctx, index); err != nil {
+			fmt.Printf("Warning: failed to create %s text index: %v\n", coll.Name(), err)
+		}
+	}
+
+	return r
+}
+
+// textSearch finds the documents matching filter and text, most relevant first, with
+// their relevance in "score"
+func textSearch(ctx context.Context, coll *mongo.Collection, filter bson.M, text string, limit int, results interface{}) error {
+	filter["$text"] = bson.M{"$search": text}
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}}).
+		SetLimit(int64(limit))
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("failed to search %s: %w", coll.Name(), err)
+	}
+	if err := cursor.All(ctx, results); err != nil {
+		return fmt.Errorf("failed to decode %s search results: %w", coll.Name(), err)
+	}
+	return nil
+}
+
+func (r *MongoSearchRepository) SearchMembers(ctx context.Context, memberIDs []string, text string, limit int) ([]domain.Scored[*domain.User], error) {
+	if len(memberIDs) == 0 {
+		return nil, nil
+	}
+	var raws []bson.M
+	if err := textSearch(ctx, r.users, live(bson.M{"_id": bson.M{"$in": idValues(memberIDs)}}), text, limit, &raws); err != nil {
+		return nil, err
+	}
+	hits := make([]domain.Scored[*domain.User], 0, len(raws))
+	for _, raw := range raws {
+		score, _ := raw["score"].(float64)
+		hits = append(hits, domain.Scored[*domain.User]{Item: mapBsonToUser(raw), Score: score})
+	}
+	return hits, nil
+}
+
+func (r *MongoSearchRepository) SearchSchedules(ctx context.Context, tenantID, coachID, text string, limit int) ([]domain.Scored[*domain.Schedule], error) {
+	var docs []struct {
+		domain.Schedule `bson:",inline"`
+		Score           float64 `bson:"score"`
+	}
+	filter := live(bson.M{"tenant_id": tenantID, "coach_id": coachID})
+	if err := textSearch(ctx, r.schedules, filter, text, limit, &docs); err != nil {
+		return nil, err
+	}
+	hits := make([]domain.Scored[*domain.Schedule], 0, len(docs))
+	for i := range docs {
+		hits = append(hits, domain.Scored[*domain.Schedule]{Item: &docs[i].Schedule, Score: docs[i].Score})
+	}
+	return hits, nil
+}
+
+func (r *MongoSearchRepository) SearchExercises(ctx context.Context, tenantID, text string, limit int) ([]domain.Scored[*domain.Exercise], error) {
+	var docs []struct {
+		domain.Exercise `bson:",inline"`
+		Score           float64 `bson:"score"`
+	}
+	filter := bson.M{"$or": bson.A{bson.M{"tenant_id": bson.M{"$exists": false}}, bson.M{"tenant_id": tenantID}}}
+	if err := textSearch(ctx, r.exercises, filter, text, limit, &docs); err != nil {
+		return nil, err
+	}
+	hits := make([]domain.Scored[*domain.Exercise], 0, len(docs))
+	for i := range docs {
+		hits = append(hits, domain.Scored[*domain.Exercise]{Item: &docs[i].Exercise, Score: docs[i].Score})
+	}
+	return hits, nil
+}
+
+func (r *MongoSearchRepository) SearchScans(ctx context.Context, memberIDs []string, text string, limit int) ([]domain.Scored[*domain.InBodyRecord], error) {
+	if len(memberIDs) == 0 {
+		return nil, nil
+	}
+	var docs []struct {
+		domain.InBodyRecord `bson:",inline"`
+		Score               float64 `bson:"score"`
+	}
+	filter := bson.M{"user_id": bson.M{"$in": idValues(memberIDs)}}
+	if err := textSearch(ctx, r.scans, filter, text, limit, &docs); err != nil {
+		return nil, err
+	}
+	hits := make([]domain.Scored[*domain.InBodyRecord], 0, len(docs))
+	for i := range docs {
+		hits = append(hits, domain.Scored[*domain.InBodyRecord]{Item: &docs[i].InBodyRecord, Score: docs[i].Score})
+	}
+	return hits, nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoSessionPhotoRepository implements domain.SessionPhotoRepository. The images live in
+// file storage; this keeps their metadata and members' consent.
+type MongoSessionPhotoRepository struct {
+	photos   *mongo.Collection
+	consents *mongo.Collection
+}
+
+func NewMongoSessionPhotoRepository(db *mongo.Database) *MongoSessionPhotoRepository {
+	photos := db.Collection("session_photos")
+	consents := db.Collection("session_photo_consents")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := photos.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "schedule_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "member_id", Value: 1}, {Key: "taken_at", Value: 1}}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create session_photos indexes: %v\n", err)
+	}
+	_, err = consents.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "member_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create session_photo_consents indexes: %v\n", err)
+	}
+
+	return &MongoSessionPhotoRepository{photos: photos, consents: consents}
+}
+
+func (r *MongoSessionPhotoRepository) Create(ctx context.Context, photo *domain.SessionPhoto) error {
+	photo.ID = newID()
+	if photo.CreatedAt.IsZero() {
+		photo.CreatedAt = time.Now()
+	}
+	if _, err := r.photos.InsertOne(ctx, photo); err != nil {
+		return fmt.Errorf("failed to create session photo: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoSessionPhotoRepository) GetByID(ctx context.Context, id string) (*domain.SessionPhoto, error) {
+	var photo domain.SessionPhoto
+	err := r.photos.FindOne(ctx, bson.M{"_id": id}).Decode(&photo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrSessionPhotoNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session photo: %w", err)
+	}
+	return &photo, nil
+}
+
+func (r *MongoSessionPhotoRepository) ListBySchedule(ctx context.Context, scheduleID string) ([]*domain.SessionPhoto, error) {
+	return r.find(ctx, bson.M{"schedule_id": scheduleID}, bson.M{"created_at": 1})
+}
+
+func (r *MongoSessionPhotoRepository) ListByMember(ctx context.Context, tenantID, memberID string, from, to time.Time) ([]*domain.SessionPhoto, error) {
+	return r.find(ctx, bson.M{
+		"tenant_id": tenantID,
+		"member_id": memberID,
+		"taken_at":  bson.M{"$gte": from, "$lt": to},
+	}, bson.D{{Key: "taken_at", Value: 1}, {Key: "created_at", Value: 1}})
+}
+
+func (r *MongoSessionPhotoRepository) find(ctx context.Context, filter, sort interface{}) ([]*domain.SessionPhoto, error) {
+	cursor, err := r.photos.Find(ctx, filter, options.Find().SetSort(sort))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session photos: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	photos := []*domain.SessionPhoto{}
+	if err := cursor.All(ctx, &photos); err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+func (r *MongoSessionPhotoRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.photos.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete session photo: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrSessionPhotoNotFound
+	}
+	return nil
+}
+
+func (r *MongoSessionPhotoRepository) GetConsent(ctx context.Context, tenantID, memberID string) (*domain.PhotoConsent, error) {
+	consent := domain.PhotoConsent{TenantID: tenantID, MemberID: memberID}
+	err := r.consents.FindOne(ctx, bson.M{"tenant_id": tenantID, "member_id": memberID},
+		options.FindOne().SetProjection(bson.M{"_id": 0})).Decode(&consent)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("failed to get photo consent: %w", err)
+	}
+	return &consent, nil
+}
+
+func (r *MongoSessionPhotoRepository) SaveConsent(ctx context.Context, consent *domain.PhotoConsent) error {
+	_, err := r.consents.UpdateOne(ctx,
+		bson.M{"tenant_id": consent.TenantID, "member_id": consent.MemberID},
+		bson.M{"$set": consent},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save photo consent: %w", err)
+	}
+	return nil
+}
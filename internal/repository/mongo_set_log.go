@@ -9,15 +9,31 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type MongoSetLogRepository struct {
 	collection *mongo.Collection
+	archive    *mongo.Collection // Set logs of archived schedules, see MongoWorkoutArchiveRepository
 }
 
 func NewMongoSetLogRepository(db *mongo.Database) *MongoSetLogRepository {
+	coll := db.Collection("set_logs")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Backs GetLastPerformance, which runs every time a coach adds an exercise
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "member_id", Value: 1}, {Key: "exercise_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create set_logs indexes: %v\n", err)
+	}
+
 	return &MongoSetLogRepository{
-		collection: db.Collection("set_logs"),
+		collection: coll,
+		archive:    db.Collection(setLogsArchiveCollection),
 	}
 }
 
@@ -80,17 +96,7 @@ func (r *MongoSetLogRepository) GetByPlannedExerciseID(ctx context.Context, plan
 }
 
 func (r *MongoSetLogRepository) GetByScheduleID(ctx context.Context, scheduleID string) ([]*domain.SetLogDocument, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{"schedule_id": scheduleID})
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var setLogs []*domain.SetLogDocument
-	if err := cursor.All(ctx, &setLogs); err != nil {
-		return nil, err
-	}
-	return setLogs, nil
+	return findWithArchiveFallback[*domain.SetLogDocument](ctx, r.collection, r.archive, bson.M{"schedule_id": scheduleID})
 }
 
 func (r *MongoSetLogRepository) Update(ctx context.Context, setLog *domain.SetLogDocument) error {
@@ -164,3 +170,59 @@ func (r *MongoSetLogRepository) SoftDelete(ctx context.Context, id string) error
 	}
 	return nil
 }
+
+func (r *MongoSetLogRepository) GetLastPerformance(ctx context.Context, memberID, exerciseID, excludeScheduleID string) ([]*domain.SetLogDocument, error) {
+	// Find the latest completed set first, then load its whole session's sets. Sessions
+	// old enough to be archived are only consulted when nothing recent exists.
+	for _, coll := range []*mongo.Collection{r.collection, r.archive} {
+		var latest domain.SetLogDocument
+		err := coll.FindOne(ctx, bson.M{
+			"member_id":   memberID,
+			"exercise_id": exerciseID,
+			"schedule_id": bson.M{"$ne": excludeScheduleID},
+			"completed":   true,
+			"deleted_at":  nil,
+		}, options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})).Decode(&latest)
+		if err == mongo.ErrNoDocuments {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to find last performance: %w", err)
+		}
+
+		cursor, err := coll.Find(ctx, bson.M{
+			"schedule_id": latest.ScheduleID,
+			"exercise_id": exerciseID,
+			"deleted_at":  nil,
+		}, options.Find().SetSort(bson.D{{Key: "set_index", Value: 1}}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load last performance: %w", err)
+		}
+		var sets []*domain.SetLogDocument
+		if err := cursor.All(ctx, &sets); err != nil {
+			return nil, err
+		}
+		return sets, nil
+	}
+	return nil, nil
+}
+
+func (r *MongoSetLogRepository) ListChangedSince(ctx context.Context, scheduleIDs []string, since time.Time) ([]*domain.SetLogDocument, error) {
+	if len(scheduleIDs) == 0 {
+		return nil, nil
+	}
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"schedule_id": bson.M{"$in": scheduleIDs},
+		"updated_at":  bson.M{"$gt": since},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed set logs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var setLogs []*domain.SetLogDocument
+	if err := cursor.All(ctx, &setLogs); err != nil {
+		return nil, err
+	}
+	return setLogs, nil
+}
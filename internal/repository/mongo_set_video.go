@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoSetVideoRepository implements domain.SetVideoRepository. The clips themselves live
+// in file storage; this keeps their metadata and coach comments.
+type MongoSetVideoRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoSetVideoRepository(db *mongo.Database) *MongoSetVideoRepository {
+	coll := db.Collection("set_videos")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "schedule_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		// Only videos awaiting feedback are indexed for the analysis job
+		{
+			Keys:    bson.D{{Key: "form_feedback.status", Value: 1}, {Key: "form_feedback.requested_at", Value: 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"form_feedback.status": domain.FormFeedbackPending}),
+		},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create set_videos indexes: %v\n", err)
+	}
+
+	return &MongoSetVideoRepository{collection: coll}
+}
+
+func (r *MongoSetVideoRepository) Create(ctx context.Context, video *domain.SetVideo) error {
+	video.ID = newID()
+	if video.CreatedAt.IsZero() {
+		video.CreatedAt = time.Now()
+	}
+	if video.Comments == nil {
+		video.Comments = []domain.VideoComment{}
+	}
+	if _, err := r.collection.InsertOne(ctx, video); err != nil {
+		return fmt.Errorf("failed to create set video: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoSetVideoRepository) GetByID(ctx context.Context, id string) (*domain.SetVideo, error) {
+	var video domain.SetVideo
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&video)
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrSetVideoNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get set video: %w", err)
+	}
+	return &video, nil
+}
+
+func (r *MongoSetVideoRepository) ListBySchedule(ctx context.Context, scheduleID string) ([]*domain.SetVideo, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"schedule_id": scheduleID}, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list set videos: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	videos := []*domain.SetVideo{}
+	if err := cursor.All(ctx, &videos); err != nil {
+		return nil, err
+	}
+	return videos, nil
+}
+
+func (r *MongoSetVideoRepository) AddComment(ctx context.Context, videoID string, comment *domain.VideoComment) error {
+	comment.ID = newID()
+	result, err := r.collection.UpdateByID(ctx, videoID, bson.M{"$push": bson.M{"comments": comment}})
+	if err != nil {
+		return fmt.Errorf("failed to add video comment: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrSetVideoNotFound
+	}
+	return nil
+}
+
+func (r *MongoSetVideoRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete set video: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrSetVideoNotFound
+	}
+	return nil
+}
+
+func (r *MongoSetVideoRepository) SetFormFeedback(ctx context.Context, videoID string, feedback *domain.FormFeedback) error {
+	result, err := r.collection.UpdateByID(ctx, videoID, bson.M{"$set": bson.M{"form_feedback": feedback}})
+	if err != nil {
+		return fmt.Errorf("failed to save form feedback: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrSetVideoNotFound
+	}
+	return nil
+}
+
+func (r *MongoSetVideoRepository) ListPendingFeedback(ctx context.Context, limit int) ([]*domain.SetVideo, error) {
+	opts := options.Find().SetSort(bson.M{"form_feedback.requested_at": 1}).SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, bson.M{"form_feedback.status": domain.FormFeedbackPending}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list videos awaiting feedback: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	videos := []*domain.SetVideo{}
+	if err := cursor.All(ctx, &videos); err != nil {
+		return nil, err
+	}
+	return videos, nil
+}
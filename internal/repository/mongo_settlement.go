@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoSettlementRepository implements domain.SettlementRepository
+type MongoSettlementRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoSettlementRepository(db *mongo.Database) *MongoSettlementRepository {
+	coll := db.Collection("settlement_reconciliations")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create settlement_reconciliations indexes: %v\n", err)
+	}
+
+	return &MongoSettlementRepository{collection: coll}
+}
+
+func (r *MongoSettlementRepository) Create(ctx context.Context, rec *domain.SettlementReconciliation) error {
+	rec.ID = newID()
+	if rec.CreatedAt.IsZero() {
+		rec.CreatedAt = time.Now()
+	}
+	if _, err := r.collection.InsertOne(ctx, rec); err != nil {
+		return fmt.Errorf("failed to create settlement reconciliation: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoSettlementRepository) GetByID(ctx context.Context, id string) (*domain.SettlementReconciliation, error) {
+	var rec domain.SettlementReconciliation
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&rec)
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settlement reconciliation: %w", err)
+	}
+	return &rec, nil
+}
+
+func (r *MongoSettlementRepository) ListByTenant(ctx context.Context, tenantID string, q domain.PageQuery) (*domain.Page[*domain.SettlementReconciliation], error) {
+	q = q.Normalized()
+
+	filter, err := pageFilter(bson.M{"tenant_id": tenantID}, q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := r.collection.Find(ctx, filter, pageOptions(q).SetProjection(bson.M{"items": 0}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list settlement reconciliations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var items []*domain.SettlementReconciliation
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return newPage(items, q, func(rec *domain.SettlementReconciliation) (time.Time, string) { return rec.CreatedAt, rec.ID }), nil
+}
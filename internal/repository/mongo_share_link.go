@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoShareLinkRepository implements domain.ShareLinkRepository. Revoked links are kept so
+// members can see what they once shared.
+type MongoShareLinkRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoShareLinkRepository(db *mongo.Database) *MongoShareLinkRepository {
+	coll := db.Collection("share_links")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "member_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create share_links indexes: %v\n", err)
+	}
+
+	return &MongoShareLinkRepository{collection: coll}
+}
+
+func (r *MongoShareLinkRepository) Create(ctx context.Context, link *domain.ShareLink) error {
+	link.ID = newID()
+	if _, err := r.collection.InsertOne(ctx, link); err != nil {
+		return fmt.Errorf("failed to create share link: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoShareLinkRepository) FindByHash(ctx context.Context, hash string) (*domain.ShareLink, error) {
+	var link domain.ShareLink
+	if err := r.collection.FindOne(ctx, bson.M{"token_hash": hash}).Decode(&link); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find share link: %w", err)
+	}
+	return &link, nil
+}
+
+func (r *MongoShareLinkRepository) ListByMember(ctx context.Context, tenantID, memberID string) ([]*domain.ShareLink, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"tenant_id": tenantID, "member_id": memberID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share links: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	links := []*domain.ShareLink{}
+	if err := cursor.All(ctx, &links); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+func (r *MongoShareLinkRepository) Revoke(ctx context.Context, memberID, id string, at time.Time) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "member_id": memberID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": at}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke share link: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoSubstitutionRepository implements domain.SubstitutionRepository
+type MongoSubstitutionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoSubstitutionRepository(db *mongo.Database) *MongoSubstitutionRepository {
+	coll := db.Collection("substitution_offers")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// One open offer per session
+			Keys:    bson.D{{Key: "schedule_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"status": domain.SubstitutionOpen}),
+		},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "status", Value: 1}, {Key: "start_time", Value: 1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create substitution_offers indexes: %v\n", err)
+	}
+
+	return &MongoSubstitutionRepository{collection: coll}
+}
+
+func (r *MongoSubstitutionRepository) Create(ctx context.Context, offer *domain.SubstitutionOffer) error {
+	offer.ID = newID()
+	if offer.CreatedAt.IsZero() {
+		offer.CreatedAt = time.Now()
+	}
+	if _, err := r.collection.InsertOne(ctx, offer); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrSubstitutionExists
+		}
+		return fmt.Errorf("failed to create substitution offer: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoSubstitutionRepository) GetByID(ctx context.Context, id string) (*domain.SubstitutionOffer, error) {
+	var offer domain.SubstitutionOffer
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&offer)
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get substitution offer: %w", err)
+	}
+	return &offer, nil
+}
+
+func (r *MongoSubstitutionRepository) ListOpen(ctx context.Context, tenantID string, branchIDs []string, from time.Time) ([]*domain.SubstitutionOffer, error) {
+	filter := bson.M{
+		"tenant_id":  tenantID,
+		"status":     domain.SubstitutionOpen,
+		"branch_id":  bson.M{"$in": branchIDs},
+		"start_time": bson.M{"$gt": from},
+	}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "start_time", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open substitution offers: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	offers := []*domain.SubstitutionOffer{}
+	if err := cursor.All(ctx, &offers); err != nil {
+		return nil, err
+	}
+	return offers, nil
+}
+
+func (r *MongoSubstitutionRepository) ListByTenant(ctx context.Context, tenantID, status string, q domain.PageQuery) (*domain.Page[*domain.SubstitutionOffer], error) {
+	q = q.Normalized()
+
+	base := bson.M{"tenant_id": tenantID}
+	if status != "" {
+		base["status"] = status
+	}
+	filter, err := pageFilter(base, q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := r.collection.Find(ctx, filter, pageOptions(q))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list substitution offers: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var items []*domain.SubstitutionOffer
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return newPage(items, q, func(o *domain.SubstitutionOffer) (time.Time, string) { return o.CreatedAt, o.ID }), nil
+}
+
+func (r *MongoSubstitutionRepository) Close(ctx context.Context, id, status, claimedBy, actorID string, at time.Time) (bool, error) {
+	set := bson.M{"status": status, "closed_by": actorID, "closed_at": at}
+	if claimedBy != "" {
+		set["claimed_by"] = claimedBy
+	}
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": domain.SubstitutionOpen},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to close substitution offer: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
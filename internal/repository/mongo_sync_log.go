@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// syncLogRetention is how long a mutation's result is remembered; the coach app gives up on
+// unsent mutations well before
+const syncLogRetention = 30 * 24 * time.Hour
+
+// MongoSyncLogRepository implements domain.SyncLogRepository
+type MongoSyncLogRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoSyncLogRepository(db *mongo.Database) *MongoSyncLogRepository {
+	coll := db.Collection("sync_log")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "applied_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(syncLogRetention.Seconds())),
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create sync_log indexes: %v\n", err)
+	}
+
+	return &MongoSyncLogRepository{collection: coll}
+}
+
+func (r *MongoSyncLogRepository) GetByIDs(ctx context.Context, coachID string, mutationIDs []string) (map[string]*domain.SyncLogEntry, error) {
+	entries := make(map[string]*domain.SyncLogEntry)
+	if len(mutationIDs) == 0 {
+		return entries, nil
+	}
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": mutationIDs}, "coach_id": coachID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync log: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var list []*domain.SyncLogEntry
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	for _, entry := range list {
+		entries[entry.MutationID] = entry
+	}
+	return entries, nil
+}
+
+func (r *MongoSyncLogRepository) Record(ctx context.Context, entry *domain.SyncLogEntry) error {
+	if _, err := r.collection.InsertOne(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record sync mutation %s: %w", entry.MutationID, err)
+	}
+	return nil
+}
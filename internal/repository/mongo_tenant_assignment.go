@@ -46,12 +46,19 @@ func (r *MongoTenantRepository) Create(ctx context.Context, tenant *domain.Tenan
 
 	// Create a BSON document for insertion to ensure _id is an ObjectID
 	doc := bson.M{
-		"_id":         objID,
-		"name":        tenant.Name,
-		"join_code":   tenant.JoinCode,
-		"logo_url":    tenant.LogoURL,
-		"ai_settings": tenant.AISettings,
-		"created_at":  tenant.CreatedAt,
+		"_id":            objID,
+		"name":           tenant.Name,
+		"join_code":      tenant.JoinCode,
+		"logo_url":       tenant.LogoURL,
+		"ai_settings":    tenant.AISettings,
+		"email_branding": tenant.EmailBranding,
+		"created_at":     tenant.CreatedAt,
+	}
+	if tenant.ContractTemplate != "" {
+		doc["contract_template"] = tenant.ContractTemplate
+	}
+	if len(tenant.CustomFields) > 0 {
+		doc["custom_fields"] = tenant.CustomFields
 	}
 
 	_, err := r.collection.InsertOne(ctx, doc)
@@ -69,7 +76,7 @@ func (r *MongoTenantRepository) GetByID(ctx context.Context, id string) (*domain
 
 	// Fetch raw document
 	var raw bson.M
-	if err := r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&raw); err != nil {
+	if err := r.collection.FindOne(ctx, live(bson.M{"_id": objID})).Decode(&raw); err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrNotFound
 		}
@@ -82,7 +89,7 @@ func (r *MongoTenantRepository) GetByID(ctx context.Context, id string) (*domain
 
 func (r *MongoTenantRepository) GetByJoinCode(ctx context.Context, code string) (*domain.Tenant, error) {
 	var raw bson.M
-	if err := r.collection.FindOne(ctx, bson.M{"join_code": code}).Decode(&raw); err != nil {
+	if err := r.collection.FindOne(ctx, live(bson.M{"join_code": code})).Decode(&raw); err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrNotFound
 		}
@@ -92,7 +99,7 @@ func (r *MongoTenantRepository) GetByJoinCode(ctx context.Context, code string)
 }
 
 func (r *MongoTenantRepository) GetAll(ctx context.Context) ([]*domain.Tenant, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	cursor, err := r.collection.Find(ctx, live(bson.M{}))
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tenants: %w", err)
 	}
@@ -121,9 +128,17 @@ func (r *MongoTenantRepository) Update(ctx context.Context, tenant *domain.Tenan
 
 	update := bson.M{
 		"$set": bson.M{
-			"name":        tenant.Name,
-			"logo_url":    tenant.LogoURL,
-			"ai_settings": tenant.AISettings,
+			"name":              tenant.Name,
+			"logo_url":          tenant.LogoURL,
+			"ai_settings":       tenant.AISettings,
+			"email_branding":    tenant.EmailBranding,
+			"contract_template": tenant.ContractTemplate,
+			"warehouse_export":  tenant.WarehouseExport,
+			"progress_weights":  tenant.ProgressWeights,
+			"pb_rules":          tenant.PBRules,
+			"custom_fields":     tenant.CustomFields,
+			"sandbox":           tenant.Sandbox,
+			"minor_age":         tenant.MinorAge,
 		},
 	}
 
@@ -137,6 +152,65 @@ func (r *MongoTenantRepository) Update(ctx context.Context, tenant *domain.Tenan
 	return nil
 }
 
+func (r *MongoTenantRepository) SetDeactivated(ctx context.Context, id string, at *time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid id format: %w", err)
+	}
+
+	update := bson.M{"$unset": bson.M{"deactivated_at": ""}}
+	if at != nil {
+		update = bson.M{"$set": bson.M{"deactivated_at": *at}}
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update tenant: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoTenantRepository) Delete(ctx context.Context, id string, at time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid id format: %w", err)
+	}
+	return softDelete(ctx, r.collection, bson.M{"_id": objID}, at)
+}
+
+func (r *MongoTenantRepository) Restore(ctx context.Context, id string, at time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid id format: %w", err)
+	}
+	return restoreDeleted(ctx, r.collection, bson.M{"_id": objID}, at)
+}
+
+func (r *MongoTenantRepository) ListDeleted(ctx context.Context, before time.Time) ([]*domain.Tenant, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"deleted_at": bson.M{"$lt": before}},
+		options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted tenants: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	tenants := []*domain.Tenant{}
+	for cursor.Next(ctx) {
+		var raw bson.M
+		if err := cursor.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("failed to decode tenant: %w", err)
+		}
+		tenant, err := mapBsonToTenant(raw)
+		if err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, tenant)
+	}
+	return tenants, nil
+}
+
 // Helper to map BSON to Tenant
 func mapBsonToTenant(raw bson.M) (*domain.Tenant, error) {
 	tenant := &domain.Tenant{}
@@ -153,9 +227,28 @@ func mapBsonToTenant(raw bson.M) (*domain.Tenant, error) {
 	if logo, ok := raw["logo_url"].(string); ok {
 		tenant.LogoURL = logo
 	}
+	if tpl, ok := raw["contract_template"].(string); ok {
+		tenant.ContractTemplate = tpl
+	}
+	if export, ok := raw["warehouse_export"].(string); ok {
+		tenant.WarehouseExport = export
+	}
 	if created, ok := raw["created_at"].(primitive.DateTime); ok {
 		tenant.CreatedAt = created.Time()
 	}
+	if minorAge, ok := raw["minor_age"].(int32); ok {
+		tenant.MinorAge = int(minorAge)
+	} else if minorAge, ok := raw["minor_age"].(int64); ok {
+		tenant.MinorAge = int(minorAge)
+	}
+	if deactivated, ok := raw["deactivated_at"].(primitive.DateTime); ok {
+		at := deactivated.Time()
+		tenant.DeactivatedAt = &at
+	}
+	if deleted, ok := raw["deleted_at"].(primitive.DateTime); ok {
+		at := deleted.Time()
+		tenant.DeletedAt = &at
+	}
 
 	// Handle AISettings
 	if aiSettingsRaw, ok := raw["ai_settings"]; ok {
@@ -163,6 +256,24 @@ func mapBsonToTenant(raw bson.M) (*domain.Tenant, error) {
 		data, _ := bson.Marshal(aiSettingsRaw)
 		bson.Unmarshal(data, &tenant.AISettings)
 	}
+	if brandingRaw, ok := raw["email_branding"]; ok && brandingRaw != nil {
+		data, _ := bson.Marshal(brandingRaw)
+		bson.Unmarshal(data, &tenant.EmailBranding)
+	}
+	if rulesRaw, ok := raw["pb_rules"]; ok && rulesRaw != nil {
+		data, _ := bson.Marshal(rulesRaw)
+		tenant.PBRules = &domain.PBRules{}
+		bson.Unmarshal(data, tenant.PBRules)
+	}
+	if fieldsRaw, ok := raw["custom_fields"]; ok && fieldsRaw != nil {
+		// An array can't be unmarshaled on its own, so wrap it in a document
+		data, _ := bson.Marshal(bson.M{"fields": fieldsRaw})
+		var wrapped struct {
+			Fields domain.CustomFieldSchema `bson:"fields"`
+		}
+		bson.Unmarshal(data, &wrapped)
+		tenant.CustomFields = wrapped.Fields
+	}
 	return tenant, nil
 }
 
@@ -284,25 +395,21 @@ func (r *MongoAssignmentRepository) FindMembersByCoach(ctx context.Context, coac
 		return []*domain.User{}, nil
 	}
 
-	// 2. Extract Member IDs (these are MongoDB ObjectIDs)
-	memberIDs := make([]primitive.ObjectID, 0, len(assignments))
+	// 2. Extract Member IDs (legacy ObjectIDs or ULIDs; invalid IDs are skipped)
+	ids := make([]string, 0, len(assignments))
 	for _, a := range assignments {
-		objID, err := primitive.ObjectIDFromHex(a.MemberID)
-		if err != nil {
-			// Skip invalid IDs
-			continue
-		}
-		memberIDs = append(memberIDs, objID)
+		ids = append(ids, a.MemberID)
 	}
+	memberIDs := idValues(ids)
 
 	if len(memberIDs) == 0 {
 		return []*domain.User{}, nil
 	}
 
 	// 3. Find Users where _id IN (memberIDs)
-	filter := bson.M{
+	filter := live(bson.M{
 		"_id": bson.M{"$in": memberIDs},
-	}
+	})
 
 	cursor, err := r.userCollection.Find(ctx, filter)
 	if err != nil {
@@ -373,7 +480,7 @@ func (r *MongoBranchRepository) GetByID(ctx context.Context, id string) (*domain
 	}
 
 	var raw bson.M
-	if err := r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&raw); err != nil {
+	if err := r.collection.FindOne(ctx, live(bson.M{"_id": objID})).Decode(&raw); err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrNotFound
 		}
@@ -384,7 +491,7 @@ func (r *MongoBranchRepository) GetByID(ctx context.Context, id string) (*domain
 
 func (r *MongoBranchRepository) GetByJoinCode(ctx context.Context, code string) (*domain.Branch, error) {
 	var raw bson.M
-	if err := r.collection.FindOne(ctx, bson.M{"join_code": code}).Decode(&raw); err != nil {
+	if err := r.collection.FindOne(ctx, live(bson.M{"join_code": code})).Decode(&raw); err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrNotFound
 		}
@@ -394,7 +501,7 @@ func (r *MongoBranchRepository) GetByJoinCode(ctx context.Context, code string)
 }
 
 func (r *MongoBranchRepository) GetByTenantID(ctx context.Context, tenantID string) ([]*domain.Branch, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{"tenant_id": tenantID})
+	cursor, err := r.collection.Find(ctx, live(bson.M{"tenant_id": tenantID}))
 	if err != nil {
 		return nil, err
 	}
@@ -431,6 +538,10 @@ func mapBsonToBranch(raw bson.M) *domain.Branch {
 	if updated, ok := raw["updated_at"].(primitive.DateTime); ok {
 		branch.UpdatedAt = updated.Time()
 	}
+	if deleted, ok := raw["deleted_at"].(primitive.DateTime); ok {
+		at := deleted.Time()
+		branch.DeletedAt = &at
+	}
 	return branch
 }
 
@@ -460,26 +571,45 @@ func (r *MongoBranchRepository) Update(ctx context.Context, branch *domain.Branc
 	return nil
 }
 
-// Delete deletes a branch by ID
-func (r *MongoBranchRepository) Delete(ctx context.Context, id string) error {
+// Delete soft-deletes a branch by ID
+func (r *MongoBranchRepository) Delete(ctx context.Context, id string, at time.Time) error {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return fmt.Errorf("invalid branch id: %w", err)
 	}
+	return softDelete(ctx, r.collection, bson.M{"_id": objID}, at)
+}
 
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
+// Restore brings back a soft-deleted branch
+func (r *MongoBranchRepository) Restore(ctx context.Context, id, tenantID string, at time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return fmt.Errorf("failed to delete branch: %w", err)
+		return fmt.Errorf("invalid branch id: %w", err)
 	}
-	if result.DeletedCount == 0 {
-		return domain.ErrNotFound
+	filter := bson.M{"_id": objID}
+	if tenantID != "" {
+		filter["tenant_id"] = tenantID
 	}
-	return nil
+	return restoreDeleted(ctx, r.collection, filter, at)
+}
+
+// ListDeleted retrieves the soft-deleted branches of a tenant, or of all for ""
+func (r *MongoBranchRepository) ListDeleted(ctx context.Context, tenantID string) ([]*domain.Branch, error) {
+	filter := bson.M{}
+	if tenantID != "" {
+		filter["tenant_id"] = tenantID
+	}
+	return findDeleted(ctx, r.collection, filter, mapBsonToBranch)
+}
+
+// PurgeDeleted permanently removes branches soft-deleted before the time given
+func (r *MongoBranchRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return purgeDeleted(ctx, r.collection, before)
 }
 
 // GetAll retrieves all branches (for super_admin)
 func (r *MongoBranchRepository) GetAll(ctx context.Context) ([]*domain.Branch, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	cursor, err := r.collection.Find(ctx, live(bson.M{}))
 	if err != nil {
 		return nil, fmt.Errorf("failed to list branches: %w", err)
 	}
@@ -0,0 +1,401 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// tenantOwnedCollections hold records with a tenant_id: the activity a sandbox reset empties
+// and the tenant's set-up
+var tenantOwnedCollections = append(slices.Clone(sandboxTenantCollections),
+	"branches",
+	"pt_packages",
+	"equipment",
+	"equipment_maintenance",
+	"documents",
+	"widget_tokens",
+	"dashboard_layouts",
+	"webhooks",
+	"webhook_deliveries",
+	"report_schedules",
+	"ai_usage",
+	"audit_logs",
+	"exercises", // Private exercises only; the global library has no tenant_id
+)
+
+// Users by how they belong to a tenant. Exclusive users go with the tenant; the others keep
+// their account and lose the membership.
+func exclusiveUsersFilter(tenantID string) bson.M {
+	return bson.M{"tenant_id": tenantID, "memberships.0": bson.M{"$exists": false}}
+}
+
+func sharedPrimaryUsersFilter(tenantID string) bson.M {
+	return bson.M{"tenant_id": tenantID, "memberships.0": bson.M{"$exists": true}}
+}
+
+func tenantUsersFilter(tenantID string) bson.M {
+	return bson.M{"$or": bson.A{bson.M{"tenant_id": tenantID}, bson.M{"memberships.tenant_id": tenantID}}}
+}
+
+// MongoTenantDataRepository implements domain.TenantDataRepository
+type MongoTenantDataRepository struct {
+	db *mongo.Database
+}
+
+func NewMongoTenantDataRepository(db *mongo.Database) *MongoTenantDataRepository {
+	return &MongoTenantDataRepository{db: db}
+}
+
+// userIDs returns the _id of up to limit users matching filter (all for 0)
+func (r *MongoTenantDataRepository) userIDs(ctx context.Context, filter bson.M, limit int) ([]interface{}, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+	cursor, err := r.db.Collection("users").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tenant users: %w", err)
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	ids := make([]interface{}, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc["_id"])
+	}
+	return ids, nil
+}
+
+// userKeys returns user IDs in both forms member-keyed records store them in: as in _id and
+// as its string form
+func userKeys(ids []interface{}) []interface{} {
+	keys := make([]interface{}, 0, 2*len(ids))
+	for _, id := range ids {
+		keys = append(keys, id)
+		if _, isString := id.(string); !isString {
+			if s := idString(id); s != "" {
+				keys = append(keys, s)
+			}
+		}
+	}
+	return keys
+}
+
+// scheduleKeys returns the IDs of the tenant's schedules, which planned exercises refer to
+func (r *MongoTenantDataRepository) scheduleKeys(ctx context.Context, tenantID string) ([]interface{}, error) {
+	ids, err := r.db.Collection("schedules").Distinct(ctx, "_id", bson.M{"tenant_id": tenantID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tenant schedules: %w", err)
+	}
+	keys := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		if s := idString(id); s != "" {
+			keys = append(keys, s)
+		}
+	}
+	return keys, nil
+}
+
+func (r *MongoTenantDataRepository) Export(ctx context.Context, tenantID string, write func(dataset string, record []byte) error) (map[string]int, error) {
+	counts := make(map[string]int)
+	export := func(dataset, collection string, filter bson.M) error {
+		cursor, err := r.db.Collection(collection).Find(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to export %s: %w", dataset, err)
+		}
+		defer cursor.Close(ctx)
+		for cursor.Next(ctx) {
+			record, err := bson.MarshalExtJSON(cursor.Current, false, false)
+			if err != nil {
+				return fmt.Errorf("failed to export %s: %w", dataset, err)
+			}
+			if err := write(dataset, record); err != nil {
+				return err
+			}
+			counts[dataset]++
+		}
+		return cursor.Err()
+	}
+
+	tenantKey, err := idValue(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := export("tenant", "tenants", bson.M{"_id": tenantKey}); err != nil {
+		return counts, err
+	}
+	if err := export("notification_settings", "tenant_notification_settings", bson.M{"_id": tenantID}); err != nil {
+		return counts, err
+	}
+	if err := export("users", "users", tenantUsersFilter(tenantID)); err != nil {
+		return counts, err
+	}
+	for _, name := range tenantOwnedCollections {
+		if err := export(name, name, bson.M{"tenant_id": tenantID}); err != nil {
+			return counts, err
+		}
+	}
+
+	scheduleKeys, err := r.scheduleKeys(ctx, tenantID)
+	if err != nil {
+		return counts, err
+	}
+	if len(scheduleKeys) > 0 {
+		if err := export("planned_exercises", "planned_exercises", bson.M{"schedule_id": bson.M{"$in": scheduleKeys}}); err != nil {
+			return counts, err
+		}
+	}
+
+	// Personal records of the users who leave with the tenant; sessions aren't data
+	ids, err := r.userIDs(ctx, exclusiveUsersFilter(tenantID), 0)
+	if err != nil {
+		return counts, err
+	}
+	if keys := userKeys(ids); len(keys) > 0 {
+		for _, c := range sandboxMemberCollections {
+			if c.collection == "refresh_tokens" || c.collection == "device_tokens" {
+				continue
+			}
+			if err := export(c.collection, c.collection, bson.M{c.field: bson.M{"$in": keys}}); err != nil {
+				return counts, err
+			}
+		}
+	}
+	return counts, nil
+}
+
+func (r *MongoTenantDataRepository) RevokeSessions(ctx context.Context, tenantID string) error {
+	ids, err := r.userIDs(ctx, bson.M{"tenant_id": tenantID}, 0)
+	if err != nil {
+		return err
+	}
+	// Sessions scoped to the tenant, and sessions on the primary tenant of its users
+	filter := bson.M{"revoked": false, "$or": bson.A{
+		bson.M{"tenant_id": tenantID},
+		bson.M{"user_id": bson.M{"$in": userKeys(ids)}, "tenant_id": bson.M{"$in": bson.A{"", nil}}},
+	}}
+	if _, err := r.db.Collection("refresh_tokens").UpdateMany(ctx, filter, bson.M{"$set": bson.M{"revoked": true}}); err != nil {
+		return fmt.Errorf("failed to revoke tenant sessions: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoTenantDataRepository) AnonymizeMembers(ctx context.Context, tenantID string, limit int) (*domain.AnonymizedBatch, error) {
+	filter := exclusiveUsersFilter(tenantID)
+	filter["roles"] = domain.RoleMember
+	filter["anonymized_at"] = bson.M{"$exists": false}
+	ids, err := r.userIDs(ctx, filter, limit)
+	if err != nil {
+		return nil, err
+	}
+	batch := &domain.AnonymizedBatch{}
+	if len(ids) == 0 {
+		return batch, nil
+	}
+	inKeys := bson.M{"$in": userKeys(ids)}
+
+	// Stored scan images and drawn signatures show the member; their references go first
+	files, err := r.fileURLs(ctx, collectionName, bson.M{"user_id": inKeys}, "metadata.image_url")
+	if err != nil {
+		return nil, err
+	}
+	batch.Files = append(batch.Files, files...)
+	if _, err := r.db.Collection(collectionName).UpdateMany(ctx,
+		bson.M{"user_id": inKeys},
+		bson.M{"$set": bson.M{"metadata.image_url": ""}},
+	); err != nil {
+		return nil, fmt.Errorf("failed to anonymize scans: %w", err)
+	}
+
+	files, err = r.fileURLs(ctx, "contract_agreements", bson.M{"member_id": inKeys}, "signature.image_url")
+	if err != nil {
+		return nil, err
+	}
+	batch.Files = append(batch.Files, files...)
+	if _, err := r.db.Collection("contract_agreements").UpdateMany(ctx,
+		bson.M{"member_id": inKeys, "signature": bson.M{"$ne": nil}},
+		bson.M{"$unset": bson.M{"signature.typed_name": "", "signature.image_url": "", "signature.ip_address": "", "signature.user_agent": ""}},
+	); err != nil {
+		return nil, fmt.Errorf("failed to anonymize agreements: %w", err)
+	}
+	if _, err := r.db.Collection("document_acceptances").UpdateMany(ctx,
+		bson.M{"member_id": inKeys},
+		bson.M{"$unset": bson.M{"typed_name": "", "ip_address": "", "user_agent": ""}},
+	); err != nil {
+		return nil, fmt.Errorf("failed to anonymize document acceptances: %w", err)
+	}
+	for _, name := range []string{"refresh_tokens", "device_tokens", "notification_preferences"} {
+		field := "user_id"
+		if name == "notification_preferences" {
+			field = "_id"
+		}
+		if _, err := r.db.Collection(name).DeleteMany(ctx, bson.M{field: inKeys}); err != nil {
+			return nil, fmt.Errorf("failed to anonymize %s: %w", name, err)
+		}
+	}
+	// Injuries are health data; handovers keep a copy of them and the member's name
+	if _, err := r.db.Collection("client_notes").DeleteMany(ctx, bson.M{"member_id": inKeys}); err != nil {
+		return nil, fmt.Errorf("failed to anonymize client notes: %w", err)
+	}
+	if _, err := r.db.Collection("handovers").UpdateMany(ctx,
+		bson.M{"member_id": inKeys},
+		bson.M{"$set": bson.M{"member_name": "Former member", "injuries": bson.A{}, "preferences": bson.A{}}},
+	); err != nil {
+		return nil, fmt.Errorf("failed to anonymize handovers: %w", err)
+	}
+	// Imports keep the source system's member rows, emails included
+	if _, err := r.db.Collection("gym_imports").UpdateMany(ctx,
+		bson.M{"tenant_id": tenantID, "data": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"data": ""}},
+	); err != nil {
+		return nil, fmt.Errorf("failed to anonymize gym imports: %w", err)
+	}
+
+	users := r.db.Collection("users")
+	now := time.Now()
+	for _, rawID := range ids {
+		id := idString(rawID)
+		_, err := users.UpdateOne(ctx, bson.M{"_id": rawID}, bson.M{
+			"$set": bson.M{
+				"name":          "Former member",
+				"email":         "anonymized-" + id + "@invalid",
+				"anonymized_at": now,
+				"updated_at":    now,
+			},
+			"$unset": bson.M{"firebase_uid": "", "avatar_url": "", "date_of_birth": "", "first_login_at": "", "last_login_at": ""},
+			"$inc":   bson.M{"version": 1},
+		})
+		if err != nil {
+			return batch, fmt.Errorf("failed to anonymize user %s: %w", id, err)
+		}
+		batch.Members++
+	}
+	return batch, nil
+}
+
+// fileURLs returns the non-empty values of a stored file's URL field in the matching records
+func (r *MongoTenantDataRepository) fileURLs(ctx context.Context, collection string, filter bson.M, field string) ([]string, error) {
+	values, err := r.db.Collection(collection).Distinct(ctx, field, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find files in %s: %w", collection, err)
+	}
+	var urls []string
+	for _, v := range values {
+		if url, ok := v.(string); ok && url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls, nil
+}
+
+func (r *MongoTenantDataRepository) Purge(ctx context.Context, tenantID string) (int64, error) {
+	var purged int64
+	deleteMany := func(collection string, filter bson.M) error {
+		result, err := r.db.Collection(collection).DeleteMany(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to purge %s: %w", collection, err)
+		}
+		purged += result.DeletedCount
+		return nil
+	}
+
+	scheduleKeys, err := r.scheduleKeys(ctx, tenantID)
+	if err != nil {
+		return purged, err
+	}
+	if len(scheduleKeys) > 0 {
+		if err := deleteMany("planned_exercises", bson.M{"schedule_id": bson.M{"$in": scheduleKeys}}); err != nil {
+			return purged, err
+		}
+	}
+	ids, err := r.userIDs(ctx, exclusiveUsersFilter(tenantID), 0)
+	if err != nil {
+		return purged, err
+	}
+	if keys := userKeys(ids); len(keys) > 0 {
+		for _, c := range sandboxMemberCollections {
+			if err := deleteMany(c.collection, bson.M{c.field: bson.M{"$in": keys}}); err != nil {
+				return purged, err
+			}
+		}
+	}
+	for _, name := range tenantOwnedCollections {
+		if err := deleteMany(name, bson.M{"tenant_id": tenantID}); err != nil {
+			return purged, err
+		}
+	}
+	if err := deleteMany("sandbox_notifications", bson.M{"tenant_id": tenantID}); err != nil {
+		return purged, err
+	}
+	if err := deleteMany("tenant_notification_settings", bson.M{"_id": tenantID}); err != nil {
+		return purged, err
+	}
+
+	if err := r.releaseSharedUsers(ctx, tenantID); err != nil {
+		return purged, err
+	}
+	if err := deleteMany("users", exclusiveUsersFilter(tenantID)); err != nil {
+		return purged, err
+	}
+
+	tenantKey, err := idValue(tenantID)
+	if err != nil {
+		return purged, err
+	}
+	if err := deleteMany("tenants", bson.M{"_id": tenantKey}); err != nil {
+		return purged, err
+	}
+	return purged, nil
+}
+
+// releaseSharedUsers takes the tenant off users who also belong to another: its membership
+// is dropped, and users whose primary tenant it was move to their first other membership
+func (r *MongoTenantDataRepository) releaseSharedUsers(ctx context.Context, tenantID string) error {
+	users := r.db.Collection("users")
+	if _, err := users.UpdateMany(ctx,
+		bson.M{"memberships.tenant_id": tenantID},
+		bson.M{"$pull": bson.M{"memberships": bson.M{"tenant_id": tenantID}}},
+	); err != nil {
+		return fmt.Errorf("failed to remove tenant memberships: %w", err)
+	}
+
+	cursor, err := users.Find(ctx, sharedPrimaryUsersFilter(tenantID))
+	if err != nil {
+		return fmt.Errorf("failed to find shared users: %w", err)
+	}
+	var shared []*domain.User
+	if err := cursor.All(ctx, &shared); err != nil {
+		return err
+	}
+	for _, user := range shared {
+		next := user.Memberships[0]
+		key, err := idValue(user.ID)
+		if err != nil {
+			return err
+		}
+		_, err = users.UpdateOne(ctx, bson.M{"_id": key, "tenant_id": tenantID}, bson.M{
+			"$set": bson.M{
+				"tenant_id":          next.TenantID,
+				"roles":              next.Roles,
+				"home_branch_id":     next.HomeBranchID,
+				"branch_access":      next.BranchAccess,
+				"working_branch_ids": next.WorkingBranchIDs,
+				"memberships":        user.Memberships[1:],
+				"updated_at":         time.Now(),
+			},
+			"$inc": bson.M{"version": 1},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to move user %s to tenant %s: %w", user.ID, next.TenantID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// finishedOffboardingStages are the stages ListActive leaves out
+var finishedOffboardingStages = []string{domain.OffboardingStageCompleted, domain.OffboardingStageCancelled}
+
+// MongoTenantOffboardingRepository implements domain.TenantOffboardingRepository
+type MongoTenantOffboardingRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoTenantOffboardingRepository(db *mongo.Database) *MongoTenantOffboardingRepository {
+	coll := db.Collection("tenant_offboardings")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "stage", Value: 1}}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create tenant_offboardings indexes: %v\n", err)
+	}
+
+	return &MongoTenantOffboardingRepository{collection: coll}
+}
+
+func (r *MongoTenantOffboardingRepository) Create(ctx context.Context, o *domain.TenantOffboarding) error {
+	err := r.collection.FindOne(ctx, bson.M{
+		"tenant_id": o.TenantID,
+		"stage":     bson.M{"$nin": finishedOffboardingStages},
+	}).Err()
+	if err == nil {
+		return domain.ErrOffboardingStarted
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("failed to check tenant offboardings: %w", err)
+	}
+
+	o.ID = newID()
+	if _, err := r.collection.InsertOne(ctx, o); err != nil {
+		return fmt.Errorf("failed to create tenant offboarding: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoTenantOffboardingRepository) GetLatest(ctx context.Context, tenantID string) (*domain.TenantOffboarding, error) {
+	var o domain.TenantOffboarding
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	err := r.collection.FindOne(ctx, bson.M{"tenant_id": tenantID}, opts).Decode(&o)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tenant offboarding: %w", err)
+	}
+	return &o, nil
+}
+
+func (r *MongoTenantOffboardingRepository) Update(ctx context.Context, o *domain.TenantOffboarding) error {
+	o.UpdatedAt = time.Now()
+	set := bson.M{
+		"stage":          o.Stage,
+		"progress":       o.Progress,
+		"archive_url":    o.ArchiveURL,
+		"grace_until":    o.GraceUntil,
+		"error":          o.Error,
+		"deactivated_at": o.DeactivatedAt,
+		"finished_at":    o.FinishedAt,
+		"updated_at":     o.UpdatedAt,
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": o.ID}, bson.M{"$set": set})
+	if err != nil {
+		return fmt.Errorf("failed to update tenant offboarding: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoTenantOffboardingRepository) ListActive(ctx context.Context) ([]*domain.TenantOffboarding, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"stage": bson.M{"$nin": finishedOffboardingStages}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenant offboardings: %w", err)
+	}
+	var list []*domain.TenantOffboarding
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
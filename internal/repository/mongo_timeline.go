@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoTimelineRepository implements domain.TimelineRepository
+type MongoTimelineRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoTimelineRepository(db *mongo.Database) *MongoTimelineRepository {
+	coll := db.Collection("member_timeline")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "member_id", Value: 1}, {Key: "occurred_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "member_id", Value: 1}, {Key: "occurred_at", Value: -1}, {Key: "_id", Value: -1}}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create member_timeline indexes: %v\n", err)
+	}
+
+	return &MongoTimelineRepository{collection: coll}
+}
+
+func (r *MongoTimelineRepository) Record(ctx context.Context, entry *domain.TimelineEntry) error {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	id := newID()
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"key": entry.Key},
+		bson.M{"$setOnInsert": bson.M{
+			"_id":         id,
+			"tenant_id":   entry.TenantID,
+			"member_id":   entry.MemberID,
+			"type":        entry.Type,
+			"title":       entry.Title,
+			"data":        entry.Data,
+			"occurred_at": entry.OccurredAt,
+			"created_at":  entry.CreatedAt,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil // Recorded concurrently
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record timeline entry: %w", err)
+	}
+	if result.UpsertedCount > 0 {
+		entry.ID = id
+	}
+	return nil
+}
+
+func (r *MongoTimelineRepository) List(ctx context.Context, tenantID, memberID string, q domain.PageQuery) (*domain.Page[*domain.TimelineEntry], error) {
+	q = q.Normalized()
+
+	match := bson.M{"member_id": memberID}
+	if tenantID != "" {
+		match["tenant_id"] = tenantID
+	}
+	filter, err := pageFilterBy("occurred_at", match, q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, pageOptionsBy("occurred_at", q))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list timeline: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*domain.TimelineEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return newPage(entries, q, func(e *domain.TimelineEntry) (time.Time, string) { return e.OccurredAt, e.ID }), nil
+}
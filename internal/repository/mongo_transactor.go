@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// errCodeIllegalOperation is returned by standalone servers, which don't support transactions
+const errCodeIllegalOperation = 20
+
+// MongoTransactor implements domain.Transactor with multi-document transactions.
+// Transactions need a replica set; against a standalone server (local dev, some test setups)
+// fn runs without one after a one-time warning.
+type MongoTransactor struct {
+	client      *mongo.Client
+	unsupported atomic.Bool
+}
+
+func NewMongoTransactor(db *mongo.Database) *MongoTransactor {
+	return &MongoTransactor{client: db.Client()}
+}
+
+func (t *MongoTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if t.unsupported.Load() {
+		return fn(ctx)
+	}
+
+	session, err := t.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == errCodeIllegalOperation {
+		if t.unsupported.CompareAndSwap(false, true) {
+			log.Printf("Warning: MongoDB does not support transactions, running without them: %v", err)
+		}
+		return fn(ctx)
+	}
+	return err
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/mansoorceksport/metamorph/internal/domain"
@@ -36,6 +38,14 @@ func NewMongoUserRepository(db *mongo.Database) *MongoUserRepository {
 		},
 		{Keys: bson.D{{Key: "tenant_id", Value: 1}}},
 		{Keys: bson.D{{Key: "roles", Value: 1}}},
+		// Support ListUsers keyset pagination by date and by name
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{
+			Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "name", Value: 1}, {Key: "_id", Value: 1}},
+			Options: options.Index().SetCollation(userNameCollation),
+		},
+		// Find users to purge
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}, Options: options.Index().SetSparse(true)},
 	})
 
 	return &MongoUserRepository{
@@ -46,11 +56,10 @@ func NewMongoUserRepository(db *mongo.Database) *MongoUserRepository {
 func (r *MongoUserRepository) Create(ctx context.Context, user *domain.User) error {
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
-	objID := primitive.NewObjectID()
-	user.ID = objID.Hex()
+	user.ID = newID()
 
 	doc := bson.M{
-		"_id":            objID,
+		"_id":            user.ID,
 		"email":          user.Email,
 		"name":           user.Name,
 		"roles":          user.Roles,
@@ -64,22 +73,51 @@ func (r *MongoUserRepository) Create(ctx context.Context, user *domain.User) err
 	if user.FirebaseUID != "" {
 		doc["firebase_uid"] = user.FirebaseUID
 	}
+	if len(user.WorkingBranchIDs) > 0 {
+		doc["working_branch_ids"] = user.WorkingBranchIDs
+	}
+	if user.DateOfBirth != nil {
+		doc["date_of_birth"] = user.DateOfBirth
+	}
+	if len(user.CustomFields) > 0 {
+		doc["custom_fields"] = user.CustomFields
+	}
 
 	_, err := r.collection.InsertOne(ctx, doc)
+	if r.collidesWithDeleted(ctx, err, user) {
+		return domain.ErrUserDeleted
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 	return nil
 }
 
+// collidesWithDeleted reports whether err is inserting user failing on the unique email
+// or Firebase UID of a deleted account
+func (r *MongoUserRepository) collidesWithDeleted(ctx context.Context, err error, user *domain.User) bool {
+	if !mongo.IsDuplicateKeyError(err) {
+		return false
+	}
+	same := bson.A{bson.M{"email": user.Email}}
+	if user.FirebaseUID != "" {
+		same = append(same, bson.M{"firebase_uid": user.FirebaseUID})
+	}
+	deleted, countErr := r.collection.CountDocuments(ctx, bson.M{
+		"deleted_at": bson.M{"$exists": true},
+		"$or":        same,
+	})
+	return countErr == nil && deleted > 0
+}
+
 func (r *MongoUserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
-	objID, err := primitive.ObjectIDFromHex(id)
+	docID, err := idValue(id)
 	if err != nil {
-		return nil, fmt.Errorf("invalid id: %w", err)
+		return nil, err
 	}
 
 	var raw bson.M
-	if err := r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&raw); err != nil {
+	if err := r.collection.FindOne(ctx, live(bson.M{"_id": docID})).Decode(&raw); err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrNotFound
 		}
@@ -90,7 +128,7 @@ func (r *MongoUserRepository) GetByID(ctx context.Context, id string) (*domain.U
 
 func (r *MongoUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var raw bson.M
-	if err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&raw); err != nil {
+	if err := r.collection.FindOne(ctx, live(bson.M{"email": email})).Decode(&raw); err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrNotFound
 		}
@@ -101,7 +139,7 @@ func (r *MongoUserRepository) GetByEmail(ctx context.Context, email string) (*do
 
 func (r *MongoUserRepository) GetByFirebaseUID(ctx context.Context, uid string) (*domain.User, error) {
 	var raw bson.M
-	if err := r.collection.FindOne(ctx, bson.M{"firebase_uid": uid}).Decode(&raw); err != nil {
+	if err := r.collection.FindOne(ctx, live(bson.M{"firebase_uid": uid})).Decode(&raw); err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrNotFound
 		}
@@ -111,22 +149,26 @@ func (r *MongoUserRepository) GetByFirebaseUID(ctx context.Context, uid string)
 }
 
 func (r *MongoUserRepository) Update(ctx context.Context, user *domain.User) error {
-	objID, err := primitive.ObjectIDFromHex(user.ID)
+	docID, err := idValue(user.ID)
 	if err != nil {
-		return fmt.Errorf("invalid id: %w", err)
+		return err
 	}
 
 	user.UpdatedAt = time.Now()
 	// Update method
 	update := bson.M{
 		"$set": bson.M{
-			"name":           user.Name,
-			"email":          user.Email,
-			"roles":          user.Roles,
-			"tenant_id":      user.TenantID,
-			"branch_access":  user.BranchAccess,
-			"home_branch_id": user.HomeBranchID,
-			"updated_at":     user.UpdatedAt,
+			"name":               user.Name,
+			"email":              user.Email,
+			"roles":              user.Roles,
+			"tenant_id":          user.TenantID,
+			"branch_access":      user.BranchAccess,
+			"home_branch_id":     user.HomeBranchID,
+			"working_branch_ids": user.WorkingBranchIDs,
+			"memberships":        user.Memberships,
+			"date_of_birth":      user.DateOfBirth,
+			"custom_fields":      user.CustomFields,
+			"updated_at":         user.UpdatedAt,
 		},
 	}
 
@@ -145,35 +187,62 @@ func (r *MongoUserRepository) Update(ctx context.Context, user *domain.User) err
 		update["$set"].(bson.M)["first_login_at"] = user.FirstLoginAt
 	}
 
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	update["$inc"] = bson.M{"version": 1}
+
+	result, err := r.collection.UpdateOne(ctx, versionFilter(docID, user.Version), update)
 	if err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 	if result.MatchedCount == 0 {
-		return domain.ErrNotFound
+		return notFoundOrConflict(ctx, r.collection, docID)
 	}
+	user.Version++
 	return nil
 }
 
-func (r *MongoUserRepository) Delete(ctx context.Context, id string) error {
-	objID, err := primitive.ObjectIDFromHex(id)
+func (r *MongoUserRepository) Delete(ctx context.Context, id string, at time.Time) error {
+	docID, err := idValue(id)
 	if err != nil {
-		return fmt.Errorf("invalid id: %w", err)
+		return err
+	}
+	return softDelete(ctx, r.collection, bson.M{"_id": docID}, at)
+}
+
+func (r *MongoUserRepository) Restore(ctx context.Context, id, tenantID string, at time.Time) error {
+	docID, err := idValue(id)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"_id": docID}
+	if tenantID != "" {
+		filter["tenant_id"] = tenantID
+	}
+	return restoreDeleted(ctx, r.collection, filter, at)
+}
+
+func (r *MongoUserRepository) ListDeleted(ctx context.Context, tenantID string) ([]*domain.User, error) {
+	filter := bson.M{}
+	if tenantID != "" {
+		filter["tenant_id"] = tenantID
 	}
-	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objID})
-	return err
+	return findDeleted(ctx, r.collection, filter, mapBsonToUser)
+}
+
+func (r *MongoUserRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return purgeDeleted(ctx, r.collection, before)
 }
 
 func (r *MongoUserRepository) UpsertByFirebaseUID(ctx context.Context, user *domain.User) error {
-	filter := bson.M{"firebase_uid": user.FirebaseUID}
+	// A deleted user doesn't match, so the upsert inserts and collides with it
+	filter := live(bson.M{"firebase_uid": user.FirebaseUID})
 
-	// Generate ObjectID for potential insert
-	objID := primitive.NewObjectID()
+	// Generate an ID for potential insert
+	docID := newID()
 	now := time.Now()
 
 	update := bson.M{
 		"$setOnInsert": bson.M{
-			"_id":          objID,
+			"_id":          docID,
 			"firebase_uid": user.FirebaseUID,
 			"created_at":   now,
 		},
@@ -183,6 +252,7 @@ func (r *MongoUserRepository) UpsertByFirebaseUID(ctx context.Context, user *dom
 			"roles":      user.Roles,
 			"updated_at": now,
 		},
+		"$inc": bson.M{"version": 1},
 	}
 
 	// Only set tenant_id if provided
@@ -192,37 +262,42 @@ func (r *MongoUserRepository) UpsertByFirebaseUID(ctx context.Context, user *dom
 
 	opts := options.Update().SetUpsert(true)
 	result, err := r.collection.UpdateOne(ctx, filter, update, opts)
+	if r.collidesWithDeleted(ctx, err, user) {
+		return domain.ErrUserDeleted
+	}
 	if err != nil {
 		return fmt.Errorf("failed to upsert user: %w", err)
 	}
 
 	if result.UpsertedID != nil {
-		user.ID = objID.Hex()
+		user.ID = docID
 	} else {
 		// Fetch to get current state
 		existing, err := r.GetByFirebaseUID(ctx, user.FirebaseUID)
-		if err == nil {
-			user.ID = existing.ID
-			user.TenantID = existing.TenantID
+		if err != nil {
+			return err
 		}
+		user.ID = existing.ID
+		user.TenantID = existing.TenantID
 	}
 
 	return nil
 }
 
 func (r *MongoUserRepository) AddRole(ctx context.Context, userID string, role string) error {
-	objID, err := primitive.ObjectIDFromHex(userID)
+	docID, err := idValue(userID)
 	if err != nil {
-		return fmt.Errorf("invalid id: %w", err)
+		return err
 	}
 
 	// Use $addToSet to prevent duplicate roles
 	update := bson.M{
 		"$addToSet": bson.M{"roles": role},
 		"$set":      bson.M{"updated_at": time.Now()},
+		"$inc":      bson.M{"version": 1},
 	}
 
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": docID}, update)
 	if err != nil {
 		return fmt.Errorf("failed to add role: %w", err)
 	}
@@ -233,9 +308,9 @@ func (r *MongoUserRepository) AddRole(ctx context.Context, userID string, role s
 }
 
 func (r *MongoUserRepository) UpdateFirebaseUID(ctx context.Context, userID string, firebaseUID string) error {
-	objID, err := primitive.ObjectIDFromHex(userID)
+	docID, err := idValue(userID)
 	if err != nil {
-		return fmt.Errorf("invalid id: %w", err)
+		return err
 	}
 
 	update := bson.M{
@@ -245,7 +320,7 @@ func (r *MongoUserRepository) UpdateFirebaseUID(ctx context.Context, userID stri
 		},
 	}
 
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": docID}, update)
 	if err != nil {
 		return fmt.Errorf("failed to update firebase uid: %w", err)
 	}
@@ -256,17 +331,18 @@ func (r *MongoUserRepository) UpdateFirebaseUID(ctx context.Context, userID stri
 }
 
 func (r *MongoUserRepository) RemoveRole(ctx context.Context, userID string, role string) error {
-	objID, err := primitive.ObjectIDFromHex(userID)
+	docID, err := idValue(userID)
 	if err != nil {
-		return fmt.Errorf("invalid id: %w", err)
+		return err
 	}
 
 	update := bson.M{
 		"$pull": bson.M{"roles": role},
 		"$set":  bson.M{"updated_at": time.Now()},
+		"$inc":  bson.M{"version": 1},
 	}
 
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": docID}, update)
 	if err != nil {
 		return fmt.Errorf("failed to remove role: %w", err)
 	}
@@ -278,9 +354,9 @@ func (r *MongoUserRepository) RemoveRole(ctx context.Context, userID string, rol
 
 // RecordLogin updates first_login_at (only if not set), last_login_at, and increments login_count
 func (r *MongoUserRepository) RecordLogin(ctx context.Context, userID string) error {
-	objID, err := primitive.ObjectIDFromHex(userID)
+	docID, err := idValue(userID)
 	if err != nil {
-		return fmt.Errorf("invalid id: %w", err)
+		return err
 	}
 
 	now := time.Now()
@@ -296,7 +372,7 @@ func (r *MongoUserRepository) RecordLogin(ctx context.Context, userID string) er
 	}
 
 	// First update: always update last_login_at and increment count
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": docID}, update)
 	if err != nil {
 		return fmt.Errorf("failed to record login: %w", err)
 	}
@@ -306,7 +382,7 @@ func (r *MongoUserRepository) RecordLogin(ctx context.Context, userID string) er
 
 	// Second update: set first_login_at only if it doesn't exist
 	_, _ = r.collection.UpdateOne(ctx, bson.M{
-		"_id":            objID,
+		"_id":            docID,
 		"first_login_at": bson.M{"$exists": false},
 	}, bson.M{
 		"$set": bson.M{"first_login_at": now},
@@ -316,7 +392,7 @@ func (r *MongoUserRepository) RecordLogin(ctx context.Context, userID string) er
 }
 
 func (r *MongoUserRepository) GetAll(ctx context.Context) ([]*domain.User, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	cursor, err := r.collection.Find(ctx, live(bson.M{}))
 	if err != nil {
 		return nil, fmt.Errorf("failed to list users: %w", err)
 	}
@@ -334,7 +410,7 @@ func (r *MongoUserRepository) GetAll(ctx context.Context) ([]*domain.User, error
 }
 
 func (r *MongoUserRepository) GetByRole(ctx context.Context, role string) ([]*domain.User, error) {
-	filter := bson.M{"roles": role}
+	filter := live(bson.M{"roles": role})
 	cursor, err := r.collection.Find(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list users by role: %w", err)
@@ -353,7 +429,7 @@ func (r *MongoUserRepository) GetByRole(ctx context.Context, role string) ([]*do
 }
 
 func (r *MongoUserRepository) GetByTenant(ctx context.Context, tenantID string) ([]*domain.User, error) {
-	filter := bson.M{"tenant_id": tenantID}
+	filter := live(bson.M{"tenant_id": tenantID})
 	cursor, err := r.collection.Find(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list users by tenant: %w", err)
@@ -371,11 +447,116 @@ func (r *MongoUserRepository) GetByTenant(ctx context.Context, tenantID string)
 	return users, nil
 }
 
+// userNameCollation compares names ignoring case and accents
+var userNameCollation = &options.Collation{Locale: "en", Strength: 1}
+
+// ListUsers returns one page of users matching q. The cursor is the sort field's value and
+// the _id of the page's last user, so each sort order has its own cursors.
+func (r *MongoUserRepository) ListUsers(ctx context.Context, q domain.UserListQuery) (*domain.Page[*domain.User], error) {
+	q, err := q.Normalized()
+	if err != nil {
+		return nil, err
+	}
+
+	filter := live(bson.M{})
+	if q.TenantID != "" {
+		filter["tenant_id"] = q.TenantID
+	}
+	if q.Role != "" {
+		filter["roles"] = q.Role
+	}
+	if q.Search != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(strings.TrimSpace(q.Search)), Options: "i"}
+		filter["$or"] = bson.A{bson.M{"name": pattern}, bson.M{"email": pattern}}
+	}
+
+	field, order := "created_at", -1
+	switch q.Sort {
+	case domain.UserSortOldest:
+		order = 1
+	case domain.UserSortName:
+		field, order = "name", 1
+	}
+	if q.Page.Cursor != "" {
+		after, err := userCursorFilter(q.Page.Cursor, field, order)
+		if err != nil {
+			return nil, err
+		}
+		filter = bson.M{"$and": bson.A{filter, after}}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: field, Value: order}, {Key: "_id", Value: order}}).
+		SetLimit(int64(q.Page.Limit + 1))
+	if field == "name" {
+		opts.SetCollation(userNameCollation)
+	}
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var users []*domain.User
+	for cursor.Next(ctx) {
+		var raw bson.M
+		if err := cursor.Decode(&raw); err != nil {
+			return nil, err
+		}
+		users = append(users, mapBsonToUser(raw))
+	}
+
+	page := &domain.Page[*domain.User]{Items: users}
+	if page.Items == nil {
+		page.Items = []*domain.User{}
+	}
+	if len(users) > q.Page.Limit {
+		page.Items = users[:q.Page.Limit]
+		page.HasMore = true
+		last := page.Items[q.Page.Limit-1]
+		value := last.CreatedAt.UTC().Format(time.RFC3339Nano)
+		if field == "name" {
+			value = last.Name
+		}
+		page.NextCursor = fmt.Sprintf("%s_%s", value, last.ID)
+	}
+	return page, nil
+}
+
+// userCursorFilter matches the users after a "value_id" cursor in the order of field
+func userCursorFilter(cursor, field string, order int) (bson.M, error) {
+	parts := splitCursor(cursor)
+	if len(parts) != 2 {
+		return nil, domain.ErrInvalidCursor
+	}
+	var value interface{} = parts[0]
+	if field == "created_at" {
+		createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+		if err != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+		value = createdAt
+	}
+	id, err := idValue(parts[1])
+	if err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+
+	op := "$gt"
+	if order < 0 {
+		op = "$lt"
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{field: bson.M{op: value}},
+		bson.M{field: value, "_id": bson.M{op: id}},
+	}}, nil
+}
+
 func (r *MongoUserRepository) GetByTenantAndRole(ctx context.Context, tenantID string, role string) ([]*domain.User, error) {
-	filter := bson.M{
+	filter := live(bson.M{
 		"tenant_id": tenantID,
 		"roles":     role,
-	}
+	})
 	cursor, err := r.collection.Find(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list users by tenant and role: %w", err)
@@ -395,9 +576,7 @@ func (r *MongoUserRepository) GetByTenantAndRole(ctx context.Context, tenantID s
 
 func mapBsonToUser(raw bson.M) *domain.User {
 	user := &domain.User{}
-	if oid, ok := raw["_id"].(primitive.ObjectID); ok {
-		user.ID = oid.Hex()
-	}
+	user.ID = idString(raw["_id"])
 	if uid, ok := raw["firebase_uid"].(string); ok {
 		user.FirebaseUID = uid
 	}
@@ -413,6 +592,16 @@ func mapBsonToUser(raw bson.M) *domain.User {
 	if hbid, ok := raw["home_branch_id"].(string); ok {
 		user.HomeBranchID = hbid
 	}
+	if avatar, ok := raw["avatar_url"].(string); ok {
+		user.AvatarURL = avatar
+	}
+	if demo, ok := raw["demo"].(bool); ok {
+		user.Demo = demo
+	}
+	if dob, ok := raw["date_of_birth"].(primitive.DateTime); ok {
+		t := dob.Time().UTC()
+		user.DateOfBirth = &t
+	}
 	if ba, ok := raw["branch_access"].(primitive.A); ok {
 		user.BranchAccess = make([]string, 0, len(ba))
 		for _, b := range ba {
@@ -421,12 +610,31 @@ func mapBsonToUser(raw bson.M) *domain.User {
 			}
 		}
 	}
+	if wb, ok := raw["working_branch_ids"].(primitive.A); ok {
+		for _, b := range wb {
+			if id, ok := b.(string); ok {
+				user.WorkingBranchIDs = append(user.WorkingBranchIDs, id)
+			}
+		}
+	}
+	if memberships, ok := raw["memberships"]; ok {
+		user.Memberships = decodeMemberships(memberships)
+	}
 	if created, ok := raw["created_at"].(primitive.DateTime); ok {
 		user.CreatedAt = created.Time()
 	}
 	if updated, ok := raw["updated_at"].(primitive.DateTime); ok {
 		user.UpdatedAt = updated.Time()
 	}
+	if deleted, ok := raw["deleted_at"].(primitive.DateTime); ok {
+		t := deleted.Time()
+		user.DeletedAt = &t
+	}
+	if version, ok := raw["version"].(int32); ok {
+		user.Version = int64(version)
+	} else if version, ok := raw["version"].(int64); ok {
+		user.Version = version
+	}
 
 	// Handle roles array
 	if rolesRaw, ok := raw["roles"]; ok {
@@ -467,3 +675,18 @@ func mapBsonToUser(raw bson.M) *domain.User {
 
 	return user
 }
+
+// decodeMemberships round-trips the raw array through BSON so the struct tags apply
+func decodeMemberships(raw interface{}) []domain.TenantMembership {
+	data, err := bson.Marshal(bson.M{"m": raw})
+	if err != nil {
+		return nil
+	}
+	var wrapper struct {
+		M []domain.TenantMembership `bson:"m"`
+	}
+	if err := bson.Unmarshal(data, &wrapper); err != nil {
+		return nil
+	}
+	return wrapper.M
+}
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoWebhookRepository implements domain.WebhookRepository
+type MongoWebhookRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoWebhookRepository(db *mongo.Database) *MongoWebhookRepository {
+	coll := db.Collection("webhooks")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "events", Value: 1}, {Key: "active", Value: 1}}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create webhooks indexes: %v\n", err)
+	}
+
+	return &MongoWebhookRepository{collection: coll}
+}
+
+func (r *MongoWebhookRepository) Create(ctx context.Context, webhook *domain.Webhook) error {
+	webhook.ID = newID()
+	now := time.Now()
+	webhook.CreatedAt, webhook.UpdatedAt = now, now
+	if _, err := r.collection.InsertOne(ctx, webhook); err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoWebhookRepository) GetByID(ctx context.Context, id string) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&webhook)
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return &webhook, nil
+}
+
+func (r *MongoWebhookRepository) ListByTenant(ctx context.Context, tenantID string) ([]*domain.Webhook, error) {
+	return r.find(ctx, bson.M{"tenant_id": tenantID})
+}
+
+func (r *MongoWebhookRepository) ListSubscribed(ctx context.Context, tenantID, event string) ([]*domain.Webhook, error) {
+	return r.find(ctx, bson.M{"tenant_id": tenantID, "events": event, "active": true})
+}
+
+func (r *MongoWebhookRepository) find(ctx context.Context, filter bson.M) ([]*domain.Webhook, error) {
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := []*domain.Webhook{}
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, fmt.Errorf("failed to decode webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+func (r *MongoWebhookRepository) Update(ctx context.Context, webhook *domain.Webhook) error {
+	webhook.UpdatedAt = time.Now()
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": webhook.ID}, webhook)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoWebhookRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// MongoWebhookDeliveryRepository implements domain.WebhookDeliveryRepository
+type MongoWebhookDeliveryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoWebhookDeliveryRepository(db *mongo.Database) *MongoWebhookDeliveryRepository {
+	coll := db.Collection("webhook_deliveries")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "webhook_id", Value: 1}, {Key: "event_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		// The delivery log covers the last 30 days
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(30 * 24 * 60 * 60),
+		},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create webhook_deliveries indexes: %v\n", err)
+	}
+
+	return &MongoWebhookDeliveryRepository{collection: coll}
+}
+
+func (r *MongoWebhookDeliveryRepository) Create(ctx context.Context, delivery *domain.WebhookDelivery) (bool, error) {
+	delivery.ID = newID()
+	delivery.Status = domain.WebhookDeliveryPending
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+	if delivery.NextAttemptAt.IsZero() {
+		delivery.NextAttemptAt = delivery.CreatedAt
+	}
+
+	_, err := r.collection.InsertOne(ctx, delivery)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	return true, nil
+}
+
+func (r *MongoWebhookDeliveryRepository) Claim(ctx context.Context, now time.Time, lease time.Duration) (*domain.WebhookDelivery, error) {
+	filter := bson.M{
+		"status":          domain.WebhookDeliveryPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{"next_attempt_at": now.Add(lease)},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var delivery domain.WebhookDelivery
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook delivery: %w", err)
+	}
+	return &delivery, nil
+}
+
+func (r *MongoWebhookDeliveryRepository) MarkDelivered(ctx context.Context, id string, at time.Time) error {
+	update := bson.M{
+		"$set":   bson.M{"status": domain.WebhookDeliveryDelivered, "delivered_at": at, "last_attempt_at": at},
+		"$unset": bson.M{"last_error": ""},
+	}
+	if _, err := r.collection.UpdateByID(ctx, id, update); err != nil {
+		return fmt.Errorf("failed to mark webhook delivery delivered: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoWebhookDeliveryRepository) MarkFailed(ctx context.Context, id string, lastError string, at, nextAttemptAt time.Time, failed bool) error {
+	set := bson.M{"last_error": lastError, "last_attempt_at": at, "next_attempt_at": nextAttemptAt}
+	if failed {
+		set["status"] = domain.WebhookDeliveryFailed
+	}
+	if _, err := r.collection.UpdateByID(ctx, id, bson.M{"$set": set}); err != nil {
+		return fmt.Errorf("failed to mark webhook delivery failed: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoWebhookDeliveryRepository) ListByWebhook(ctx context.Context, webhookID string, q domain.PageQuery) (*domain.Page[*domain.WebhookDelivery], error) {
+	q = q.Normalized()
+
+	filter, err := pageFilter(bson.M{"webhook_id": webhookID}, q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := r.collection.Find(ctx, filter, pageOptions(q))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var items []*domain.WebhookDelivery
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return newPage(items, q, func(d *domain.WebhookDelivery) (time.Time, string) { return d.CreatedAt, d.ID }), nil
+}
+
+func (r *MongoWebhookDeliveryRepository) DeleteByWebhook(ctx context.Context, webhookID string) error {
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"webhook_id": webhookID}); err != nil {
+		return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+	return nil
+}
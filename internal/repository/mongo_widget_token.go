@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoWidgetTokenRepository implements domain.WidgetTokenRepository. Revoked tokens are
+// kept so admins can see what was once embedded.
+type MongoWidgetTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoWidgetTokenRepository(db *mongo.Database) *MongoWidgetTokenRepository {
+	coll := db.Collection("widget_tokens")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create widget_tokens indexes: %v\n", err)
+	}
+
+	return &MongoWidgetTokenRepository{collection: coll}
+}
+
+func (r *MongoWidgetTokenRepository) Create(ctx context.Context, token *domain.WidgetToken) error {
+	token.ID = newID()
+	if _, err := r.collection.InsertOne(ctx, token); err != nil {
+		return fmt.Errorf("failed to create widget token: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoWidgetTokenRepository) FindByHash(ctx context.Context, hash string) (*domain.WidgetToken, error) {
+	var token domain.WidgetToken
+	if err := r.collection.FindOne(ctx, bson.M{"token_hash": hash}).Decode(&token); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find widget token: %w", err)
+	}
+	return &token, nil
+}
+
+func (r *MongoWidgetTokenRepository) ListByTenant(ctx context.Context, tenantID string) ([]*domain.WidgetToken, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"tenant_id": tenantID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list widget tokens: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	tokens := []*domain.WidgetToken{}
+	if err := cursor.All(ctx, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (r *MongoWidgetTokenRepository) Revoke(ctx context.Context, tenantID, id string, at time.Time) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "tenant_id": tenantID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": at}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke widget token: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
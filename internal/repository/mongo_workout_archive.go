@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	setLogsArchiveCollection          = "set_logs_archive"
+	plannedExercisesArchiveCollection = "planned_exercises_archive"
+
+	errCodeNamespaceExists = 48
+)
+
+// MongoWorkoutArchiveRepository implements domain.WorkoutArchiveRepository
+type MongoWorkoutArchiveRepository struct {
+	schedules        *mongo.Collection
+	setLogs          *mongo.Collection
+	plannedExercises *mongo.Collection
+	setLogsArchive   *mongo.Collection
+	plannedArchive   *mongo.Collection
+}
+
+func NewMongoWorkoutArchiveRepository(db *mongo.Database) *MongoWorkoutArchiveRepository {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, name := range []string{setLogsArchiveCollection, plannedExercisesArchiveCollection} {
+		if err := createCompressedCollection(ctx, db, name); err != nil {
+			fmt.Printf("Warning: failed to create %s: %v\n", name, err)
+		}
+		_, err := db.Collection(name).Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "schedule_id", Value: 1}}})
+		if err != nil {
+			fmt.Printf("Warning: failed to create %s index: %v\n", name, err)
+		}
+	}
+
+	return &MongoWorkoutArchiveRepository{
+		schedules:        db.Collection("schedules"),
+		setLogs:          db.Collection("set_logs"),
+		plannedExercises: db.Collection("planned_exercises"),
+		setLogsArchive:   db.Collection(setLogsArchiveCollection),
+		plannedArchive:   db.Collection(plannedExercisesArchiveCollection),
+	}
+}
+
+// createCompressedCollection creates a zstd-compressed collection; archives are rarely read
+// so trading CPU for disk is worth it
+func createCompressedCollection(ctx context.Context, db *mongo.Database, name string) error {
+	opts := options.CreateCollection().SetStorageEngine(bson.M{
+		"wiredTiger": bson.M{"configString": "block_compressor=zstd"},
+	})
+	err := db.CreateCollection(ctx, name, opts)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == errCodeNamespaceExists {
+		return nil
+	}
+	return err
+}
+
+func (r *MongoWorkoutArchiveRepository) ArchiveBefore(ctx context.Context, cutoff time.Time, limit int) (*domain.ArchiveSummary, error) {
+	filter := bson.M{
+		"status": bson.M{"$in": []string{
+			domain.ScheduleStatusCompleted,
+			domain.ScheduleStatusCancelled,
+			domain.ScheduleStatusNoShow,
+		}},
+		"start_time":  bson.M{"$lt": cutoff},
+		"archived_at": bson.M{"$exists": false},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "start_time", Value: 1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"_id": 1})
+
+	cursor, err := r.schedules.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find archivable schedules: %w", err)
+	}
+	var docs []struct {
+		ID interface{} `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode archivable schedules: %w", err)
+	}
+
+	summary := &domain.ArchiveSummary{}
+	for _, doc := range docs {
+		setLogs, planned, err := r.archiveSchedule(ctx, doc.ID)
+		if err != nil {
+			return summary, err
+		}
+		summary.Schedules++
+		summary.SetLogs += setLogs
+		summary.PlannedExercises += planned
+	}
+	return summary, nil
+}
+
+// archiveSchedule copies the schedule's detail to the archive, removes the hot copies and
+// then marks the schedule. Every step can be re-run, so a crash midway is fixed by the next run.
+func (r *MongoWorkoutArchiveRepository) archiveSchedule(ctx context.Context, docID interface{}) (setLogs, planned int, err error) {
+	scheduleID := idString(docID)
+	filter := bson.M{"schedule_id": scheduleID}
+
+	if setLogs, err = moveDocuments(ctx, r.setLogs, r.setLogsArchive, filter); err != nil {
+		return 0, 0, fmt.Errorf("failed to archive set logs of schedule %s: %w", scheduleID, err)
+	}
+	if planned, err = moveDocuments(ctx, r.plannedExercises, r.plannedArchive, filter); err != nil {
+		return 0, 0, fmt.Errorf("failed to archive planned exercises of schedule %s: %w", scheduleID, err)
+	}
+
+	_, err = r.schedules.UpdateOne(ctx, bson.M{"_id": docID}, bson.M{"$set": bson.M{"archived_at": time.Now()}})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to mark schedule %s archived: %w", scheduleID, err)
+	}
+	return setLogs, planned, nil
+}
+
+// moveDocuments copies the matching documents from src to dst, then deletes them from src.
+// Documents already in dst (from an interrupted run) are skipped.
+func moveDocuments(ctx context.Context, src, dst *mongo.Collection, filter bson.M) (int, error) {
+	cursor, err := src.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	var docs []bson.Raw
+	if err := cursor.All(ctx, &docs); err != nil {
+		return 0, err
+	}
+	if len(docs) == 0 {
+		return 0, nil
+	}
+
+	batch := make([]interface{}, len(docs))
+	ids := make([]interface{}, len(docs))
+	for i, doc := range docs {
+		batch[i] = doc
+		ids[i] = doc.Lookup("_id")
+	}
+
+	_, err = dst.InsertMany(ctx, batch, options.InsertMany().SetOrdered(false))
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return 0, err
+	}
+	if _, err := src.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// findWithArchiveFallback reads from hot, and from archive when hot has no matches.
+// Workout detail for archived schedules is only requested occasionally, so the extra
+// query on empty results is cheap.
+func findWithArchiveFallback[T any](ctx context.Context, hot, archive *mongo.Collection, filter bson.M, opts ...*options.FindOptions) ([]T, error) {
+	var results []T
+	for _, coll := range []*mongo.Collection{hot, archive} {
+		cursor, err := coll.Find(ctx, filter, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if err := cursor.All(ctx, &results); err != nil {
+			return nil, err
+		}
+		if len(results) > 0 {
+			break
+		}
+	}
+	return results, nil
+}
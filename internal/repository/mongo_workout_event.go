@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoWorkoutEventRepository implements domain.WorkoutEventRepository
+type MongoWorkoutEventRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoWorkoutEventRepository(db *mongo.Database) *MongoWorkoutEventRepository {
+	coll := db.Collection("workout_events")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "schedule_id", Value: 1}, {Key: "occurred_at", Value: 1}}},
+		{Keys: bson.D{{Key: "type", Value: 1}}},
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to create workout_events indexes: %v\n", err)
+	}
+
+	return &MongoWorkoutEventRepository{collection: coll}
+}
+
+func (r *MongoWorkoutEventRepository) Append(ctx context.Context, event *domain.WorkoutEvent) error {
+	event.ID = newID()
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	if _, err := r.collection.InsertOne(ctx, event); err != nil {
+		return fmt.Errorf("failed to append workout event: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoWorkoutEventRepository) ListBySchedule(ctx context.Context, scheduleID string) ([]*domain.WorkoutEvent, error) {
+	// ULIDs break ties between events recorded in the same millisecond
+	opts := options.Find().SetSort(bson.D{{Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"schedule_id": scheduleID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workout events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var events []*domain.WorkoutEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, fmt.Errorf("failed to decode workout events: %w", err)
+	}
+	return events, nil
+}
+
+func (r *MongoWorkoutEventRepository) ListScheduleIDs(ctx context.Context, eventType string) ([]string, error) {
+	values, err := r.collection.Distinct(ctx, "schedule_id", bson.M{"type": eventType})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules with workout events: %w", err)
+	}
+
+	ids := make([]string, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
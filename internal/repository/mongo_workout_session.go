@@ -10,20 +10,31 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 type MongoWorkoutSessionRepository struct {
 	collection     *mongo.Collection
 	planCollection *mongo.Collection
+	planArchive    *mongo.Collection
+	analytics      *mongo.Collection
 }
 
 func NewMongoWorkoutSessionRepository(db *mongo.Database) *MongoWorkoutSessionRepository {
+	coll := db.Collection("workout_sessions")
 	return &MongoWorkoutSessionRepository{
-		collection:     db.Collection("workout_sessions"),
+		collection:     coll,
 		planCollection: db.Collection("planned_exercises"),
+		planArchive:    db.Collection(plannedExercisesArchiveCollection),
+		analytics:      coll,
 	}
 }
 
+// RouteAnalyticsReads sends the coach dashboard's session range queries to pref
+func (r *MongoWorkoutSessionRepository) RouteAnalyticsReads(pref *readpref.ReadPref) {
+	r.analytics = withReadPreference(r.collection, pref)
+}
+
 func (r *MongoWorkoutSessionRepository) Create(ctx context.Context, session *domain.WorkoutSession) error {
 	session.CreatedAt = time.Now()
 	session.UpdatedAt = time.Now()
@@ -86,16 +97,10 @@ func (r *MongoWorkoutSessionRepository) GetByScheduleID(ctx context.Context, sch
 }
 
 func (r *MongoWorkoutSessionRepository) loadPlannedExercises(ctx context.Context, session *domain.WorkoutSession) error {
-	cursor, err := r.planCollection.Find(ctx, bson.M{"schedule_id": session.ScheduleID}, options.Find().SetSort(bson.M{"order": 1}))
+	exercises, err := r.GetPlannedExercisesByScheduleID(ctx, session.ScheduleID)
 	if err != nil {
 		return err
 	}
-	defer cursor.Close(ctx)
-
-	var exercises []*domain.PlannedExercise
-	if err := cursor.All(ctx, &exercises); err != nil {
-		return err
-	}
 	session.PlannedExercises = exercises
 	return nil
 }
@@ -120,17 +125,8 @@ func (r *MongoWorkoutSessionRepository) Update(ctx context.Context, session *dom
 
 // GetPlannedExercisesByScheduleID retrieves planned exercises directly (ignoring session doc existence)
 func (r *MongoWorkoutSessionRepository) GetPlannedExercisesByScheduleID(ctx context.Context, scheduleID string) ([]*domain.PlannedExercise, error) {
-	cursor, err := r.planCollection.Find(ctx, bson.M{"schedule_id": scheduleID}, options.Find().SetSort(bson.M{"order": 1}))
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var exercises []*domain.PlannedExercise
-	if err := cursor.All(ctx, &exercises); err != nil {
-		return nil, err
-	}
-	return exercises, nil
+	opts := options.Find().SetSort(bson.M{"order": 1})
+	return findWithArchiveFallback[*domain.PlannedExercise](ctx, r.planCollection, r.planArchive, bson.M{"schedule_id": scheduleID}, opts)
 }
 
 // GetSessionsByCoachAndDateRange retrieves all workout sessions for a coach within a date range
@@ -140,7 +136,7 @@ func (r *MongoWorkoutSessionRepository) GetSessionsByCoachAndDateRange(ctx conte
 		"created_at": bson.M{"$gte": from, "$lte": to},
 	}
 
-	cursor, err := r.collection.Find(ctx, filter)
+	cursor, err := r.analytics.Find(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
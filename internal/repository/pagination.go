@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Paginated lists are ordered newest first by created_at (or the field given to the *By
+// variants), with _id as the tie-breaker.
+// Cursor format: "created_at_id" (e.g., "2025-12-20T09:45:00.123Z_01JFA3Q6Z8D1H4XK2M9T0V7W5C")
+
+// pageFilter narrows filter to the records after the cursor
+func pageFilter(filter bson.M, cursor string) (bson.M, error) {
+	return pageFilterBy("created_at", filter, cursor)
+}
+
+// pageFilterBy is pageFilter for lists ordered by another time field
+func pageFilterBy(field string, filter bson.M, cursor string) (bson.M, error) {
+	if cursor == "" {
+		return filter, nil
+	}
+
+	parts := splitCursor(cursor)
+	if len(parts) != 2 {
+		return nil, domain.ErrInvalidCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+	id, err := idValue(parts[1])
+	if err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+
+	return bson.M{"$and": bson.A{
+		filter,
+		bson.M{"$or": bson.A{
+			bson.M{field: bson.M{"$lt": createdAt}},
+			bson.M{field: createdAt, "_id": bson.M{"$lt": id}},
+		}},
+	}}, nil
+}
+
+// pageOptions sorts newest first and fetches one extra record to detect a next page
+func pageOptions(q domain.PageQuery) *options.FindOptions {
+	return pageOptionsBy("created_at", q)
+}
+
+// pageOptionsBy is pageOptions for lists ordered by another time field
+func pageOptionsBy(field string, q domain.PageQuery) *options.FindOptions {
+	return options.Find().
+		SetSort(bson.D{
+			{Key: field, Value: -1},
+			{Key: "_id", Value: -1},
+		}).
+		SetLimit(int64(q.Limit + 1))
+}
+
+// newPage trims the extra record fetched by pageOptions and builds the next cursor from the last item
+func newPage[T any](items []T, q domain.PageQuery, key func(T) (time.Time, string)) *domain.Page[T] {
+	page := &domain.Page[T]{Items: items}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if len(items) > q.Limit {
+		page.Items = items[:q.Limit]
+		page.HasMore = true
+		createdAt, id := key(page.Items[q.Limit-1])
+		page.NextCursor = fmt.Sprintf("%s_%s", createdAt.UTC().Format(time.RFC3339Nano), id)
+	}
+	return page
+}
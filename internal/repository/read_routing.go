@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// ParseReadPreference parses a read preference mode such as "secondaryPreferred".
+// An empty mode returns nil, which keeps reads on the client's default (the primary).
+func ParseReadPreference(mode string) (*readpref.ReadPref, error) {
+	if mode == "" {
+		return nil, nil
+	}
+	m, err := readpref.ModeFromString(mode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid read preference %q: %w", mode, err)
+	}
+	return readpref.New(m)
+}
+
+// withReadPreference returns a handle on coll whose reads use pref.
+// Writes and read-after-write lookups must keep using the original handle.
+func withReadPreference(coll *mongo.Collection, pref *readpref.ReadPref) *mongo.Collection {
+	if pref == nil {
+		return coll
+	}
+	return coll.Database().Collection(coll.Name(), options.Collection().SetReadPreference(pref))
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+const attemptsKeyPrefix = "attempts:"
+
+// RedisAttemptTracker implements domain.AttemptTracker with one hash per key
+type RedisAttemptTracker struct {
+	client *redis.Client
+}
+
+func NewRedisAttemptTracker(client *redis.Client) *RedisAttemptTracker {
+	return &RedisAttemptTracker{client: client}
+}
+
+func (t *RedisAttemptTracker) Get(ctx context.Context, key string) (*domain.AttemptState, error) {
+	values, err := t.client.HGetAll(ctx, attemptsKeyPrefix+key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attempts: %w", err)
+	}
+	return parseAttemptState(values), nil
+}
+
+func (t *RedisAttemptTracker) RecordFailure(ctx context.Context, key string, at time.Time, window time.Duration) (*domain.AttemptState, error) {
+	redisKey := attemptsKeyPrefix + key
+	pipe := t.client.TxPipeline()
+	failures := pipe.HIncrBy(ctx, redisKey, "failures", 1)
+	pipe.HSet(ctx, redisKey, "last_failure_at", at.UnixMilli())
+	pipe.Expire(ctx, redisKey, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to record attempt: %w", err)
+	}
+	return &domain.AttemptState{Failures: int(failures.Val()), LastFailureAt: at}, nil
+}
+
+func (t *RedisAttemptTracker) Reset(ctx context.Context, key string) error {
+	return t.client.Del(ctx, attemptsKeyPrefix+key).Err()
+}
+
+func parseAttemptState(values map[string]string) *domain.AttemptState {
+	state := &domain.AttemptState{}
+	state.Failures, _ = strconv.Atoi(values["failures"])
+	if ms, err := strconv.ParseInt(values["last_failure_at"], 10, 64); err == nil {
+		state.LastFailureAt = time.UnixMilli(ms)
+	}
+	return state
+}
@@ -18,6 +18,9 @@ const (
 	trendRecapKeyPrefix = "trend_recap:"
 	scanDetailKeyPrefix = "scan:detail:" // Cache for individual scan details
 
+	analyticsHistoryKeyPrefix = "analytics:history:"  // Followed by user ID and limit
+	scansChangedKeyPrefix     = "user:scans_changed:" // When the user's scans last changed
+
 	// Member endpoint caching prefixes
 	memberDashboardKeyPrefix = "member:dashboard:"
 	memberSchedulesKeyPrefix = "member:schedules:"
@@ -187,6 +190,44 @@ func (r *RedisCacheRepository) InvalidateScan(ctx context.Context, scanID string
 }
 
 // =============================================================================
+// SetAnalyticsHistory caches a user's analytics history for one limit with TTL
+func (r *RedisCacheRepository) SetAnalyticsHistory(ctx context.Context, userID string, limit int, entry *domain.CachedAnalyticsHistory, ttl time.Duration) error {
+	return r.Set(ctx, fmt.Sprintf("%s%s:%d", analyticsHistoryKeyPrefix, userID, limit), entry, ttl)
+}
+
+// GetAnalyticsHistory retrieves a cached analytics history
+func (r *RedisCacheRepository) GetAnalyticsHistory(ctx context.Context, userID string, limit int) (*domain.CachedAnalyticsHistory, error) {
+	var entry domain.CachedAnalyticsHistory
+	err := r.Get(ctx, fmt.Sprintf("%s%s:%d", analyticsHistoryKeyPrefix, userID, limit), &entry)
+	if err == ErrCacheMiss {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// MarkScansChanged records when a user's scans last changed
+func (r *RedisCacheRepository) MarkScansChanged(ctx context.Context, userID string, at time.Time, ttl time.Duration) error {
+	if err := r.client.Set(ctx, scansChangedKeyPrefix+userID, at.UTC().Format(time.RFC3339Nano), ttl).Err(); err != nil {
+		return fmt.Errorf("failed to mark scans changed: %w", err)
+	}
+	return nil
+}
+
+// GetScansChangedAt returns when a user's scans last changed, or zero if not recorded
+func (r *RedisCacheRepository) GetScansChangedAt(ctx context.Context, userID string) (time.Time, error) {
+	value, err := r.client.Get(ctx, scansChangedKeyPrefix+userID).Result()
+	if err == redis.Nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get scans changed time: %w", err)
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
+
 // Generic Cache Operations with OpenTelemetry Tracing
 // =============================================================================
 
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	lockKeyPrefix     = "lock:"
+	lockRetryInterval = 50 * time.Millisecond
+)
+
+// releaseLockScript deletes the lock only if it is still held by the caller's token,
+// so a holder whose lock expired can't release a lock since taken by someone else.
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// RedisLocker implements domain.Locker with SET NX PX and a token-checked release
+type RedisLocker struct {
+	client *redis.Client
+}
+
+// NewRedisLocker creates a new Redis-backed distributed locker
+func NewRedisLocker(client *redis.Client) *RedisLocker {
+	return &RedisLocker{
+		client: client,
+	}
+}
+
+// Acquire takes the lock for key, retrying until wait elapses
+func (l *RedisLocker) Acquire(ctx context.Context, key string, ttl, wait time.Duration) (func(), error) {
+	tokenBytes := make([]byte, 16)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	token := hex.EncodeToString(tokenBytes)
+	lockKey := lockKeyPrefix + key
+
+	deadline := time.Now().Add(wait)
+	for {
+		acquired, err := l.client.SetNX(ctx, lockKey, token, ttl).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire lock: %w", err)
+		}
+		if acquired {
+			break
+		}
+		if time.Now().After(deadline) {
+			return nil, domain.ErrLockNotAcquired
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			// Use a fresh context: the request context may already be cancelled
+			releaseCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			if err := releaseLockScript.Run(releaseCtx, l.client, []string{lockKey}, token).Err(); err != nil {
+				fmt.Printf("Warning: failed to release lock %s: %v\n", lockKey, err)
+			}
+		})
+	}
+	return release, nil
+}
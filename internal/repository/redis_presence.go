@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+const presenceKeyPrefix = "presence:"
+
+// RedisPresenceRepository implements domain.PresenceRepository with one sorted set per
+// tenant, scored by each user's last heartbeat in milliseconds
+type RedisPresenceRepository struct {
+	client *redis.Client
+}
+
+func NewRedisPresenceRepository(client *redis.Client) *RedisPresenceRepository {
+	return &RedisPresenceRepository{client: client}
+}
+
+func (r *RedisPresenceRepository) Heartbeat(ctx context.Context, tenantID, userID string, at time.Time) error {
+	key := presenceKeyPrefix + tenantID
+	pipe := r.client.TxPipeline()
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(at.UnixMilli()), Member: userID})
+	// Heartbeats past the TTL are dropped here, and the whole set once the tenant goes quiet
+	pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(at.Add(-domain.PresenceTTL).UnixMilli(), 10))
+	pipe.Expire(ctx, key, domain.PresenceTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record heartbeat: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisPresenceRepository) SeenSince(ctx context.Context, tenantID string, since time.Time) (map[string]time.Time, error) {
+	beats, err := r.client.ZRangeByScoreWithScores(ctx, presenceKeyPrefix+tenantID, &redis.ZRangeBy{
+		Min: strconv.FormatInt(since.UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read presence: %w", err)
+	}
+	seen := make(map[string]time.Time, len(beats))
+	for _, beat := range beats {
+		if userID, ok := beat.Member.(string); ok {
+			seen[userID] = time.UnixMilli(int64(beat.Score))
+		}
+	}
+	return seen, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+const tokenBucketKeyPrefix = "tokenbucket:"
+
+// takeTokens refills each bucket for the time since it was last refilled, one token per
+// interval, then takes a token from every bucket if each has one, or from none. It returns
+// 0, or the milliseconds until the emptiest bucket has a token again. Time is read from the
+// Redis server so replicas with skewed clocks agree. A full bucket expires, as it's the same
+// as none.
+//
+// KEYS buckets; ARGV capacity and refill interval (ms) of each bucket, in turn
+var takeTokens = redis.NewScript(`
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) * 1000 + math.floor(tonumber(clock[2]) / 1000)
+local tokens, at = {}, {}
+local wait = 0
+for i = 1, #KEYS do
+	local capacity = tonumber(ARGV[2 * i - 1])
+	local interval = tonumber(ARGV[2 * i])
+	local state = redis.call('HMGET', KEYS[i], 'tokens', 'at')
+	local t = tonumber(state[1])
+	local a = tonumber(state[2])
+	if t == nil or a == nil then
+		t = capacity
+		a = now
+	elseif a > now then
+		a = now
+	end
+	local refills = math.floor((now - a) / interval)
+	if refills > 0 then
+		t = math.min(capacity, t + refills)
+		a = a + refills * interval
+	end
+	if t >= capacity then
+		a = now
+	end
+	if t < 1 then
+		wait = math.max(wait, interval - (now - a))
+	end
+	tokens[i], at[i] = t, a
+end
+if wait > 0 then
+	return wait
+end
+for i = 1, #KEYS do
+	local capacity = tonumber(ARGV[2 * i - 1])
+	local interval = tonumber(ARGV[2 * i])
+	redis.call('HSET', KEYS[i], 'tokens', tokens[i] - 1, 'at', at[i])
+	redis.call('PEXPIRE', KEYS[i], (capacity - tokens[i] + 1) * interval + interval)
+end
+return 0
+`)
+
+// RedisTokenBucket implements domain.TokenBucket, keeping each bucket in a Redis hash so
+// every replica draws from the same one
+type RedisTokenBucket struct {
+	client *redis.Client
+}
+
+func NewRedisTokenBucket(client *redis.Client) *RedisTokenBucket {
+	return &RedisTokenBucket{client: client}
+}
+
+func (b *RedisTokenBucket) Take(ctx context.Context, buckets []domain.Bucket) (bool, time.Duration, error) {
+	keys := make([]string, 0, len(buckets))
+	args := make([]interface{}, 0, 2*len(buckets))
+	for _, bucket := range buckets {
+		if !bucket.Limit.Enabled() {
+			continue
+		}
+		interval := (bucket.Limit.Per / time.Duration(bucket.Limit.Requests)).Milliseconds()
+		if interval < 1 {
+			interval = 1
+		}
+		keys = append(keys, tokenBucketKeyPrefix+bucket.Key)
+		args = append(args, bucket.Limit.Requests, interval)
+	}
+	if len(keys) == 0 {
+		return true, 0, nil
+	}
+
+	wait, err := takeTokens.Run(ctx, b.client, keys, args...).Int64()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to take a token: %w", err)
+	}
+	if wait > 0 {
+		return false, time.Duration(wait) * time.Millisecond, nil
+	}
+	return true, 0, nil
+}
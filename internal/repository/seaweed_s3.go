@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -15,6 +17,7 @@ import (
 // SeaweedS3Repository implements domain.FileRepository using AWS SDK v2
 type SeaweedS3Repository struct {
 	client    *s3.Client
+	presign   *s3.PresignClient // Signs for the public URL, since that's where clients fetch from
 	bucket    string
 	publicURL string
 }
@@ -40,7 +43,12 @@ func NewSeaweedS3Repository(ctx context.Context, cfg appConfig.S3Config) (*Seawe
 	})
 
 	repo := &SeaweedS3Repository{
-		client:    client,
+		client: client,
+		presign: s3.NewPresignClient(client, func(o *s3.PresignOptions) {
+			o.ClientOptions = append(o.ClientOptions, func(o *s3.Options) {
+				o.BaseEndpoint = aws.String(cfg.PublicURL)
+			})
+		}),
 		bucket:    cfg.Bucket,
 		publicURL: cfg.PublicURL, // Use public URL for serving files externally
 	}
@@ -93,21 +101,33 @@ func (r *SeaweedS3Repository) ensureBucket(ctx context.Context) error {
 	return nil
 }
 
-// Delete removes a file from S3 storage
-func (r *SeaweedS3Repository) Delete(ctx context.Context, fileURL string) error {
-	// Parse URL to extract key
-	// Format: {Endpoint}/{Bucket}/{Key}
-	// We need to extract the key from the URL
-	// Simple approach: remove the prefix "endpoint/bucket/"
+// Download reads a file back from S3
+func (r *SeaweedS3Repository) Download(ctx context.Context, fileURL string) ([]byte, error) {
+	key, err := r.keyFromURL(fileURL)
+	if err != nil {
+		return nil, err
+	}
 
-	prefix := fmt.Sprintf("%s/%s/", r.publicURL, r.bucket)
-	if len(fileURL) <= len(prefix) {
-		return fmt.Errorf("invalid file URL format")
+	out, err := r.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(r.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file from S3: %w", err)
 	}
+	defer out.Body.Close()
 
-	key := fileURL[len(prefix):]
+	return io.ReadAll(out.Body)
+}
 
-	_, err := r.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+// Delete removes a file from S3 storage
+func (r *SeaweedS3Repository) Delete(ctx context.Context, fileURL string) error {
+	key, err := r.keyFromURL(fileURL)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(r.bucket),
 		Key:    aws.String(key),
 	})
@@ -117,3 +137,31 @@ func (r *SeaweedS3Repository) Delete(ctx context.Context, fileURL string) error
 
 	return nil
 }
+
+// SignedURL presigns a GET of the file, valid for ttl
+func (r *SeaweedS3Repository) SignedURL(ctx context.Context, fileURL string, ttl time.Duration) (string, error) {
+	key, err := r.keyFromURL(fileURL)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := r.presign.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(r.bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign file URL: %w", err)
+	}
+	return req.URL, nil
+}
+
+// keyFromURL extracts the object key from a URL returned by Upload
+func (r *SeaweedS3Repository) keyFromURL(fileURL string) (string, error) {
+	// Format: {Endpoint}/{Bucket}/{Key}
+	// Simple approach: remove the prefix "endpoint/bucket/"
+	prefix := fmt.Sprintf("%s/%s/", r.publicURL, r.bucket)
+	if len(fileURL) <= len(prefix) {
+		return "", fmt.Errorf("invalid file URL format")
+	}
+	return fileURL[len(prefix):], nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Users, branches and tenants are soft-deleted: deleted_at is set, every query skips them,
+// and they can be restored until the purge job removes them for good.
+
+// live adds to filter that the document isn't soft-deleted, and returns it
+func live(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": false}
+	return filter
+}
+
+// softDelete marks the live document matching filter deleted at the time given, or returns ErrNotFound
+func softDelete(ctx context.Context, collection *mongo.Collection, filter bson.M, at time.Time) error {
+	result, err := collection.UpdateOne(ctx, live(filter), bson.M{
+		"$set": bson.M{"deleted_at": at, "updated_at": at},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete from %s: %w", collection.Name(), err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// restoreDeleted brings back the soft-deleted document matching filter at the time given,
+// or returns ErrNotFound
+func restoreDeleted(ctx context.Context, collection *mongo.Collection, filter bson.M, at time.Time) error {
+	filter["deleted_at"] = bson.M{"$exists": true}
+	result, err := collection.UpdateOne(ctx, filter, bson.M{
+		"$unset": bson.M{"deleted_at": ""},
+		"$set":   bson.M{"updated_at": at},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore in %s: %w", collection.Name(), err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// purgeDeleted removes the documents soft-deleted before the time given
+func purgeDeleted(ctx context.Context, collection *mongo.Collection, before time.Time) (int64, error) {
+	result, err := collection.DeleteMany(ctx, bson.M{"deleted_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge %s: %w", collection.Name(), err)
+	}
+	return result.DeletedCount, nil
+}
+
+// findDeleted decodes the soft-deleted documents matching filter, latest first, with decode
+func findDeleted[T any](ctx context.Context, collection *mongo.Collection, filter bson.M, decode func(bson.M) T) ([]T, error) {
+	filter["deleted_at"] = bson.M{"$exists": true}
+	cursor, err := collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted %s: %w", collection.Name(), err)
+	}
+	defer cursor.Close(ctx)
+
+	items := []T{}
+	for cursor.Next(ctx) {
+		var raw bson.M
+		if err := cursor.Decode(&raw); err != nil {
+			return nil, err
+		}
+		items = append(items, decode(raw))
+	}
+	return items, cursor.Err()
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// versionFilter matches a document only if it is still at the expected version.
+// Documents written before versioning have no version field and count as version 0.
+func versionFilter(id interface{}, version int64) bson.M {
+	if version == 0 {
+		return bson.M{
+			"_id": id,
+			"$or": bson.A{
+				bson.M{"version": 0},
+				bson.M{"version": bson.M{"$exists": false}},
+			},
+		}
+	}
+	return bson.M{"_id": id, "version": version}
+}
+
+// notFoundOrConflict explains why a versioned update matched nothing
+func notFoundOrConflict(ctx context.Context, collection *mongo.Collection, id interface{}) error {
+	count, err := collection.CountDocuments(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return domain.ErrNotFound
+	}
+	return domain.ErrVersionConflict
+}
@@ -0,0 +1,101 @@
+// Package response writes the JSON bodies of the API. Every body is an envelope:
+//
+//	{"success": true, "data": ..., "meta": {...}}
+//	{"success": false, "error": "message", "code": "PACKAGE_DEPLETED", "details": {...}}
+//
+// so clients read the payload from one place and branch on the code, never the message.
+package response
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// Envelope is the body of successful responses
+type Envelope[T any] struct {
+	Success bool                   `json:"success"` // Always true
+	Data    T                      `json:"data"`
+	Meta    map[string]interface{} `json:"meta,omitempty"` // About the data, e.g. the metrics hidden from it
+}
+
+// ErrorBody is the body of error responses
+type ErrorBody struct {
+	Success bool                   `json:"success"` // Always false
+	Error   string                 `json:"error"`
+	Code    domain.ErrorCode       `json:"code"`
+	Details map[string]interface{} `json:"details,omitempty"` // e.g. the roles a forbidden route requires
+}
+
+// OK answers 200 with data
+func OK(c *fiber.Ctx, data interface{}) error {
+	return JSON(c, fiber.StatusOK, data)
+}
+
+// Created answers 201 with what was created
+func Created(c *fiber.Ctx, data interface{}) error {
+	return JSON(c, fiber.StatusCreated, data)
+}
+
+// JSON answers a successful status with data
+func JSON(c *fiber.Ctx, status int, data interface{}) error {
+	return WithMeta(c, status, data, nil)
+}
+
+// WithMeta answers a successful status with data and what the client should know about it
+func WithMeta(c *fiber.Ctx, status int, data interface{}, meta fiber.Map) error {
+	return c.Status(status).JSON(Envelope[interface{}]{Success: true, Data: data, Meta: meta})
+}
+
+// Error answers an error status with message and the status's code. Errors from the domain
+// are answered with Fail or FailAs instead, so they carry their own code.
+func Error(c *fiber.Ctx, status int, message string) error {
+	return ErrorDetails(c, status, message, nil)
+}
+
+// ErrorDetails is Error with more about what went wrong
+func ErrorDetails(c *fiber.Ctx, status int, message string, details fiber.Map) error {
+	return Coded(c, status, domain.StatusErrorCode(status), message, details)
+}
+
+// Fail answers err with the status and code it's registered with, or 500
+func Fail(c *fiber.Ctx, err error) error {
+	return FailAs(c, fiber.StatusInternalServerError, err)
+}
+
+// FailAs answers err with the status and code it's registered with. Other errors are
+// answered status, with the status's code, so the two never disagree.
+func FailAs(c *fiber.Ctx, status int, err error) error {
+	code := domain.StatusErrorCode(status)
+	if registered, registeredCode, ok := classify(err); ok {
+		status, code = registered, registeredCode
+	}
+	return Coded(c, status, code, err.Error(), nil)
+}
+
+// Coded answers an error status with an explicit code
+func Coded(c *fiber.Ctx, status int, code domain.ErrorCode, message string, details fiber.Map) error {
+	return c.Status(status).JSON(ErrorBody{Error: message, Code: code, Details: details})
+}
+
+// Classify is the status and code err is answered with: a fiber error's status, or a
+// registered domain error's status and code; anything else is an internal error
+func Classify(err error) (int, domain.ErrorCode) {
+	if status, code, ok := classify(err); ok {
+		return status, code
+	}
+	return fiber.StatusInternalServerError, domain.CodeInternal
+}
+
+// classify is the status and code of a fiber error or a registered domain error
+func classify(err error) (int, domain.ErrorCode, bool) {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code, domain.StatusErrorCode(fiberErr.Code), true
+	}
+	if entry, ok := domain.LookupError(err); ok {
+		return entry.Status, entry.Code, true
+	}
+	return 0, "", false
+}
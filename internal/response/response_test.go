@@ -0,0 +1,105 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// respond serves one request with handler and returns the status and the decoded body
+func respond(t *testing.T, handler fiber.Handler) (int, map[string]interface{}) {
+	t.Helper()
+	app := fiber.New()
+	app.Get("/", handler)
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(raw, &body), string(raw))
+	return resp.StatusCode, body
+}
+
+func TestSuccess(t *testing.T) {
+	t.Run("wraps the data", func(t *testing.T) {
+		status, body := respond(t, func(c *fiber.Ctx) error { return OK(c, fiber.Map{"id": "c-1"}) })
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, map[string]interface{}{"success": true, "data": map[string]interface{}{"id": "c-1"}}, body)
+	})
+
+	t.Run("keeps the status", func(t *testing.T) {
+		status, body := respond(t, func(c *fiber.Ctx) error { return Created(c, []string{"a"}) })
+		assert.Equal(t, fiber.StatusCreated, status)
+		assert.Equal(t, []interface{}{"a"}, body["data"])
+	})
+
+	t.Run("puts meta next to the data", func(t *testing.T) {
+		_, body := respond(t, func(c *fiber.Ctx) error {
+			return WithMeta(c, fiber.StatusOK, nil, fiber.Map{"hidden_metrics": []string{"pbf"}})
+		})
+		assert.Equal(t, map[string]interface{}{"hidden_metrics": []interface{}{"pbf"}}, body["meta"])
+		assert.Contains(t, body, "data")
+	})
+}
+
+func TestErrors(t *testing.T) {
+	t.Run("codes a message by its status, whatever it says", func(t *testing.T) {
+		status, body := respond(t, func(c *fiber.Ctx) error {
+			return Error(c, fiber.StatusBadRequest, "Cannot book: "+domain.ErrPackageDepleted.Error())
+		})
+		assert.Equal(t, fiber.StatusBadRequest, status)
+		assert.Equal(t, false, body["success"])
+		assert.Equal(t, string(domain.CodeBadRequest), body["code"])
+		assert.NotContains(t, body, "details")
+	})
+
+	t.Run("codes other messages by the status", func(t *testing.T) {
+		_, body := respond(t, func(c *fiber.Ctx) error {
+			return ErrorDetails(c, fiber.StatusForbidden, "Insufficient permissions", fiber.Map{"required_roles": []string{"coach"}})
+		})
+		assert.Equal(t, string(domain.CodeForbidden), body["code"])
+		assert.Equal(t, map[string]interface{}{"required_roles": []interface{}{"coach"}}, body["details"])
+	})
+
+	t.Run("answers a domain error with its status and code", func(t *testing.T) {
+		status, body := respond(t, func(c *fiber.Ctx) error {
+			return Fail(c, fmt.Errorf("member m-1: %w", domain.ErrTenantScopeViolation))
+		})
+		assert.Equal(t, fiber.StatusForbidden, status)
+		assert.Equal(t, "TENANT_SCOPE_VIOLATION", body["code"])
+		assert.Equal(t, "member m-1: resource belongs to another tenant", body["error"])
+	})
+
+	t.Run("answers a registered error with its own status, not the one asked for", func(t *testing.T) {
+		status, body := respond(t, func(c *fiber.Ctx) error {
+			return FailAs(c, fiber.StatusInternalServerError, fmt.Errorf("Failed to import scans: %w", domain.ErrInvalidScanCSV))
+		})
+		assert.Equal(t, fiber.StatusBadRequest, status)
+		assert.Equal(t, "INVALID_SCAN_CSV", body["code"])
+	})
+
+	t.Run("answers other errors with the status asked for", func(t *testing.T) {
+		status, body := respond(t, func(c *fiber.Ctx) error {
+			return FailAs(c, fiber.StatusBadRequest, fmt.Errorf("unexpected end of JSON input"))
+		})
+		assert.Equal(t, fiber.StatusBadRequest, status)
+		assert.Equal(t, string(domain.CodeBadRequest), body["code"])
+	})
+}
+
+func TestClassify(t *testing.T) {
+	status, code := Classify(fiber.NewError(fiber.StatusNotFound, "Cannot GET /nope"))
+	assert.Equal(t, fiber.StatusNotFound, status)
+	assert.Equal(t, domain.CodeNotFound, code)
+
+	status, code = Classify(fmt.Errorf("boom"))
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+	assert.Equal(t, domain.CodeInternal, code)
+}
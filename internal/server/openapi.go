@@ -0,0 +1,154 @@
+package server
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mansoorceksport/metamorph/internal/config"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/handler"
+	"github.com/mansoorceksport/metamorph/internal/openapi"
+	"github.com/mansoorceksport/metamorph/internal/response"
+)
+
+// publicPaths are reachable without an access token
+var publicPaths = []string{
+	"/health",
+	"/v1/error-codes",
+	"/v1/auth",
+	"/v1/public",
+	"/v1/payments/webhook",
+	"/api/payments/webhook",
+}
+
+// apiEndpoints describes routes with the types their handlers read and write. Routes left
+// out are still in the document, without body schemas; describe a route here once its
+// handler answers with a type rather than a fiber.Map.
+var apiEndpoints = []openapi.Endpoint{
+	// Platform
+	{Method: fiber.MethodGet, Path: "/health", Summary: "Service health", Response: handler.HealthResponse{}},
+	{Method: fiber.MethodGet, Path: "/v1/error-codes", Summary: "Codes of error responses",
+		Response: []domain.ErrorCodeEntry{}},
+
+	// Auth
+	{Method: fiber.MethodPost, Path: "/v1/auth/login", Summary: "Sign in with a Firebase ID token, registering new users",
+		Request: handler.TenantRequest{}, Response: handler.LoginResponse{}, Security: openapi.SecurityFirebase},
+	{Method: fiber.MethodPost, Path: "/v1/auth/refresh", Summary: "New access token from the refresh token cookie",
+		Response: handler.TokenResponse{}},
+	{Method: fiber.MethodPost, Path: "/v1/auth/logout", Summary: "Revoke the refresh token", Response: handler.MessageResponse{}},
+	{Method: fiber.MethodPost, Path: "/v1/auth/switch-tenant", Summary: "Access token scoped to another of the user's tenants",
+		Request: handler.TenantRequest{}, Response: handler.TokenResponse{}, Security: openapi.SecurityBearer},
+	{Method: fiber.MethodPost, Path: "/v1/auth/accept-invite", Summary: "Join the tenant that invited the signed-in email",
+		Security: openapi.SecurityFirebase},
+
+	// Members
+	{Method: fiber.MethodGet, Path: "/v1/me/contracts", Summary: "My active contracts", Response: []*domain.PTContract{}},
+	{Method: fiber.MethodGet, Path: "/v1/me/contracts/:id/statement", Summary: "Credit statement of my contract",
+		Response: domain.ContractStatement{}},
+	{Method: fiber.MethodGet, Path: "/v1/me/workouts/history", Summary: "My completed workouts", Query: []string{"limit", "cursor"},
+		Response: handler.WorkoutHistoryResponse{}},
+	{Method: fiber.MethodGet, Path: "/v1/me/workouts/:id", Summary: "One of my workouts",
+		Response: handler.WorkoutDetailResponse{}},
+	{Method: fiber.MethodGet, Path: "/v1/me/payments/packages", Summary: "Packages on sale",
+		Response: []handler.PackageResponse{}},
+	{Method: fiber.MethodPost, Path: "/v1/me/payments/checkout", Summary: "Invoice with a virtual account to pay a package into",
+		Request: handler.CheckoutRequest{}, Response: handler.CheckoutResponse{}, Status: fiber.StatusCreated},
+	{Method: fiber.MethodGet, Path: "/v1/me/payments/status/:id", Summary: "Status of my invoice",
+		Response: handler.CheckoutResponse{}},
+	{Method: fiber.MethodGet, Path: "/v1/me/notifications", Summary: "My notifications, newest first",
+		Query: []string{"unread", "limit", "cursor"}, Response: handler.InboxResponse{}},
+	{Method: fiber.MethodGet, Path: "/v1/me/notification-preferences", Summary: "Which notifications I get",
+		Response: domain.NotificationPreferences{}},
+	{Method: fiber.MethodPost, Path: "/v1/me/share-links", Summary: "Share an achievement card",
+		Request: handler.CreateShareLinkRequest{}, Status: fiber.StatusCreated},
+	{Method: fiber.MethodGet, Path: "/v1/me/share-links", Summary: "My share links",
+		Response: []*domain.ShareLink{}},
+	{Method: fiber.MethodPut, Path: "/v1/me/schedules/:id/effort", Summary: "Rate the effort of my session",
+		Request: handler.SessionEffortRequest{}, Response: domain.Schedule{}},
+	{Method: fiber.MethodPost, Path: "/v1/devices", Summary: "Register a device for pushes",
+		Request: handler.DeviceRequest{}, Response: domain.DeviceToken{}},
+	{Method: fiber.MethodDelete, Path: "/v1/devices", Summary: "Unregister a device", Request: handler.DeviceRequest{}},
+
+	// Coaches
+	{Method: fiber.MethodGet, Path: "/v1/pro/clients", Summary: "My clients with their contracts",
+		Response: []*handler.ClientResponse{}},
+	{Method: fiber.MethodGet, Path: "/v1/pro/clients/simple", Summary: "My clients, without their stats",
+		Response: []*handler.SimpleClientResponse{}},
+	{Method: fiber.MethodGet, Path: "/v1/pro/members/invites", Summary: "My pending member invites",
+		Response: []*domain.MemberInvite{}},
+	{Method: fiber.MethodPost, Path: "/v1/pro/members/:id/assessments", Summary: "Record an assessment",
+		Request: handler.RecordAssessmentRequest{}, Response: domain.Assessment{}, Status: fiber.StatusCreated},
+	{Method: fiber.MethodPost, Path: "/v1/pro/members/:id/report", Summary: "Generate a progress report",
+		Request: handler.ProgressReportRequest{}, Response: domain.ProgressReport{}, Status: fiber.StatusCreated},
+	{Method: fiber.MethodPut, Path: "/v1/pro/schedules/:id/effort", Summary: "Rate the effort of a session",
+		Request: handler.SessionEffortRequest{}, Response: domain.Schedule{}},
+	{Method: fiber.MethodGet, Path: "/v1/pro/schedules/:id/photos", Summary: "Photos of a session",
+		Response: []*domain.SessionPhoto{}},
+	{Method: fiber.MethodGet, Path: "/v1/pro/search", Summary: "Search my clients, sessions, exercises and scans, most relevant first",
+		Query: []string{"q", "limit"}, Response: domain.SearchResults{}},
+	{Method: fiber.MethodPost, Path: "/v1/pro/presence", Summary: "Heartbeat of the coach app, every 30 seconds",
+		Status: fiber.StatusNoContent},
+
+	// Tenant admins
+	{Method: fiber.MethodGet, Path: "/v1/tenant-admin/users", Summary: "Users of the tenant, with the fields the caller may see",
+		Query: []string{"role", "search", "sort", "limit", "cursor"}, Response: []*domain.User{}},
+	{Method: fiber.MethodGet, Path: "/v1/tenant-admin/users/:id", Summary: "A user, with the fields the caller may see",
+		Response: domain.User{}},
+	{Method: fiber.MethodGet, Path: "/v1/tenant-admin/branches", Summary: "Branches of the tenant", Response: []*domain.Branch{}},
+	{Method: fiber.MethodGet, Path: "/v1/tenant-admin/branches/:id", Summary: "A branch", Response: domain.Branch{}},
+	{Method: fiber.MethodGet, Path: "/v1/tenant-admin/packages", Summary: "Package templates", Response: []*domain.PTPackage{}},
+	{Method: fiber.MethodGet, Path: "/v1/tenant-admin/packages/:id", Summary: "A package template", Response: domain.PTPackage{}},
+	{Method: fiber.MethodPut, Path: "/v1/tenant-admin/packages/:id", Summary: "Update a package template",
+		Request: domain.PTPackage{}, Response: domain.PTPackage{}},
+	{Method: fiber.MethodPost, Path: "/v1/tenant-admin/contracts", Summary: "Assign a package to a member",
+		Request: handler.CreateContractRequest{}, Response: domain.PTContract{}, Status: fiber.StatusCreated},
+	{Method: fiber.MethodGet, Path: "/v1/tenant-admin/contracts", Summary: "Contracts of the tenant; paginated with limit or cursor",
+		Query: []string{"limit", "cursor"}, Response: []*domain.PTContract{}},
+	{Method: fiber.MethodGet, Path: "/v1/tenant-admin/contracts/:id/statement", Summary: "Credit statement of a contract",
+		Response: domain.ContractStatement{}},
+	{Method: fiber.MethodPut, Path: "/v1/tenant-admin/contracts/:id/custom-fields", Summary: "Set a contract's custom fields; null clears one",
+		Request: handler.CustomFieldsRequest{}, Response: domain.PTContract{}},
+	{Method: fiber.MethodGet, Path: "/v1/tenant-admin/custom-fields", Summary: "Custom fields of members and contracts",
+		Response: handler.CustomFieldSchemaBody{}},
+	{Method: fiber.MethodPut, Path: "/v1/tenant-admin/custom-fields", Summary: "Replace the custom fields",
+		Request: handler.CustomFieldSchemaBody{}, Response: handler.CustomFieldSchemaBody{}},
+	{Method: fiber.MethodGet, Path: "/v1/tenant-admin/dashboard/presence", Summary: "Coaches in session, online and offline",
+		Response: domain.PresenceBoard{}},
+	{Method: fiber.MethodPost, Path: "/v1/tenant-admin/report-schedules", Summary: "Schedule a report",
+		Request: handler.ReportScheduleRequest{}, Response: handler.ReportScheduleResponse{}, Status: fiber.StatusCreated},
+	{Method: fiber.MethodGet, Path: "/v1/tenant-admin/report-schedules", Summary: "Scheduled reports",
+		Response: []*domain.ReportSchedule{}},
+	{Method: fiber.MethodGet, Path: "/v1/tenant-admin/report-schedules/:id", Summary: "A scheduled report",
+		Response: domain.ReportSchedule{}},
+	{Method: fiber.MethodPut, Path: "/v1/tenant-admin/report-schedules/:id", Summary: "Update a scheduled report",
+		Request: handler.ReportScheduleRequest{}, Response: handler.ReportScheduleResponse{}},
+	{Method: fiber.MethodPost, Path: "/v1/tenant-admin/webhooks", Summary: "Subscribe a URL to events; the secret is shown once",
+		Request: handler.TenantWebhookRequest{}, Response: handler.CreatedWebhookResponse{}, Status: fiber.StatusCreated},
+	{Method: fiber.MethodGet, Path: "/v1/tenant-admin/webhooks", Summary: "Webhooks and the events they may subscribe to",
+		Response: handler.WebhookListResponse{}},
+	{Method: fiber.MethodPost, Path: "/v1/tenant-admin/widget-tokens", Summary: "Token for the public widget API",
+		Request: handler.CreateWidgetTokenRequest{}, Status: fiber.StatusCreated},
+	{Method: fiber.MethodGet, Path: "/v1/tenant-admin/widget-tokens", Summary: "Widget tokens",
+		Response: []*domain.WidgetToken{}},
+
+	// Shared
+	{Method: fiber.MethodGet, Path: "/v1/contracts/:id", Summary: "A contract", Response: domain.PTContract{}},
+	{Method: fiber.MethodGet, Path: "/v1/schedules/:id", Summary: "A session", Response: domain.Schedule{}},
+	{Method: fiber.MethodGet, Path: "/v1/exercises", Summary: "Exercise library; signed-in users also get their tenant's",
+		Response: []*domain.Exercise{}, Security: openapi.SecurityNone},
+	{Method: fiber.MethodGet, Path: "/v1/exercises/:id/video", Summary: "Play an exercise's demo video",
+		Security: openapi.SecurityNone},
+	{Method: fiber.MethodGet, Path: "/v1/templates", Summary: "Workout templates", Response: []*domain.WorkoutTemplate{},
+		Security: openapi.SecurityNone},
+}
+
+// apiDocument generates the OpenAPI document of the registered routes
+func apiDocument(routes []fiber.Route) *openapi.Document {
+	return openapi.Generate(openapi.Config{
+		Title:       "Metamorph API",
+		Description: "Gym management and body composition tracking for coaches, members and tenant admins",
+		Version:     config.Build().Version,
+		Public:      publicPaths,
+		Endpoints:   apiEndpoints,
+		Error:       response.ErrorBody{},
+		Envelope:    response.Envelope[interface{}]{},
+	}, routes)
+}
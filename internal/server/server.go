@@ -4,16 +4,29 @@ import (
 	"context"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/mansoorceksport/metamorph/internal/audit"
+	"github.com/mansoorceksport/metamorph/internal/clock"
 	"github.com/mansoorceksport/metamorph/internal/config"
 	"github.com/mansoorceksport/metamorph/internal/domain"
 	"github.com/mansoorceksport/metamorph/internal/handler"
+	"github.com/mansoorceksport/metamorph/internal/infrastructure/ffmpeg"
+	"github.com/mansoorceksport/metamorph/internal/infrastructure/gymimport"
+	"github.com/mansoorceksport/metamorph/internal/infrastructure/mail"
+	"github.com/mansoorceksport/metamorph/internal/infrastructure/meeting"
+	"github.com/mansoorceksport/metamorph/internal/infrastructure/notify"
+	"github.com/mansoorceksport/metamorph/internal/infrastructure/sentry"
+	"github.com/mansoorceksport/metamorph/internal/infrastructure/webhook"
+	"github.com/mansoorceksport/metamorph/internal/jobs"
 	"github.com/mansoorceksport/metamorph/internal/middleware"
 	"github.com/mansoorceksport/metamorph/internal/repository"
+	"github.com/mansoorceksport/metamorph/internal/response"
 	"github.com/mansoorceksport/metamorph/internal/service"
 	"github.com/mansoorceksport/metamorph/internal/telemetry"
 	"github.com/redis/go-redis/v9"
@@ -26,6 +39,8 @@ type AppDependencies struct {
 	MongoDB     *mongo.Database
 	RedisClient *redis.Client
 	AuthClient  service.FirebaseAuthClient
+	PushClient  notify.FCMClient // Optional: without it pushes are only logged
+	Clock       domain.Clock     // Optional: defaults to the system clock
 }
 
 // NewApp creates and configures the Fiber application with the given dependencies
@@ -33,6 +48,7 @@ func NewApp(deps AppDependencies) *fiber.App {
 	// Initialize repositories
 	mongoRepo := repository.NewMongoInBodyRepository(deps.MongoDB)
 	redisRepo := repository.NewRedisCacheRepository(deps.RedisClient)
+	locker := repository.NewRedisLocker(deps.RedisClient)
 	tenantRepo := repository.NewMongoTenantRepository(deps.MongoDB)
 	userRepo := repository.NewMongoUserRepository(deps.MongoDB)
 	branchRepo := repository.NewMongoBranchRepository(deps.MongoDB)
@@ -40,13 +56,34 @@ func NewApp(deps AppDependencies) *fiber.App {
 	contractRepo := repository.NewMongoPTContractRepository(deps.MongoDB)
 	schedMongoRepo := repository.NewMongoScheduleRepository(deps.MongoDB)
 	schedRepo := repository.NewCachedScheduleRepository(schedMongoRepo, redisRepo)
+	coachAvailabilityRepo := repository.NewMongoCoachAvailabilityRepository(deps.MongoDB)
 	exerciseRepo := repository.NewMongoExerciseRepository(deps.MongoDB)
 	templateRepo := repository.NewMongoTemplateRepository(deps.MongoDB)
 	workoutSessionRepo := repository.NewMongoWorkoutSessionRepository(deps.MongoDB)
 	setLogRepo := repository.NewMongoSetLogRepository(deps.MongoDB)
 	pbRepo := repository.NewMongoPersonalBestRepository(deps.MongoDB)
 	dailyVolumeRepo := repository.NewMongoDailyVolumeRepository(deps.MongoDB)
+	workoutEventRepo := repository.NewMongoWorkoutEventRepository(deps.MongoDB)
+	jobRunRepo := repository.NewMongoJobRunRepository(deps.MongoDB)
 	refreshTokenRepo := repository.NewMongoRefreshTokenRepository(deps.MongoDB)
+	agreementRepo := repository.NewMongoContractAgreementRepository(deps.MongoDB)
+	documentRepo := repository.NewMongoDocumentRepository(deps.MongoDB)
+	documentAcceptanceRepo := repository.NewMongoDocumentAcceptanceRepository(deps.MongoDB)
+	creditRepo := repository.NewMongoCreditTransactionRepository(deps.MongoDB)
+	demoRepo := repository.NewMongoDemoDataRepository(deps.MongoDB)
+	outboxRepo := repository.NewMongoOutboxRepository(deps.MongoDB)
+	sandboxRepo := repository.NewMongoSandboxRepository(deps.MongoDB)
+	transactor := repository.NewMongoTransactor(deps.MongoDB)
+
+	// Dashboards, trends and volume history can be served by secondaries
+	analyticsReadPref, err := repository.ParseReadPreference(deps.Config.MongoDB.AnalyticsReadPreference)
+	if err != nil {
+		log.Printf("Warning: %v; analytics reads stay on the primary", err)
+	}
+	mongoRepo.RouteAnalyticsReads(analyticsReadPref)
+	schedMongoRepo.RouteAnalyticsReads(analyticsReadPref)
+	workoutSessionRepo.RouteAnalyticsReads(analyticsReadPref)
+	dailyVolumeRepo.RouteAnalyticsReads(analyticsReadPref)
 
 	// Payment-related repositories
 	pkgPaymentRepo := repository.NewMongoPackageRepository(deps.MongoDB)
@@ -62,6 +99,20 @@ func NewApp(deps AppDependencies) *fiber.App {
 	if err != nil {
 		log.Printf("Warning: Failed to initialize S3 repository: %v", err)
 	}
+	var fileRepo domain.FileRepository
+	if s3Repo != nil {
+		fileRepo = s3Repo
+	}
+
+	clk := clock.OrReal(deps.Clock)
+
+	// Side effects (cache invalidation, later webhooks and pushes) are written to the outbox
+	// with the change that caused them and delivered by the relay
+	outboxRelay := service.NewOutboxRelay(outboxRepo, clk)
+	outbox := service.NewOutbox(outboxRepo, transactor, outboxRelay)
+
+	// Sandbox tenants get mock payments and digitizing, and have their notifications captured
+	sandboxService := service.NewSandboxService(tenantRepo, userRepo, sandboxRepo, clk)
 
 	// Initialize services
 	digitizerService := service.NewOpenRouterDigitizer(
@@ -72,55 +123,326 @@ func NewApp(deps AppDependencies) *fiber.App {
 	)
 
 	scanService := service.NewScanService(
-		digitizerService,
+		sandboxService.Digitizer(digitizerService),
 		mongoRepo,
 		redisRepo,
-		s3Repo,
+		fileRepo,
+		repository.NewMongoScanRevisionRepository(deps.MongoDB),
+		outbox,
+		clk,
 	)
+	outboxRelay.Handle(domain.OutboxTopicScanChanged, scanService.HandleScanChanged)
+
+	// Tenants' webhooks are fed from the outbox and the workout event log, and posted by a job
+	webhookService := service.NewWebhookService(repository.NewMongoWebhookRepository(deps.MongoDB),
+		repository.NewMongoWebhookDeliveryRepository(deps.MongoDB), contractRepo, userRepo, webhook.NewPoster(clk), clk)
+	outboxRelay.Handle(domain.OutboxTopicScanDigitized, webhookService.HandleScanDigitized)
+	outboxRelay.Handle(domain.OutboxTopicContractCreated, webhookService.HandleContractCreated)
 
 	// Initialize analytics service
-	analyticsService := service.NewAnalyticsService(mongoRepo)
+	analyticsService := service.NewAnalyticsService(mongoRepo, redisRepo, clk)
 
 	// Initialize trend service
-	trendService := service.NewTrendService(mongoRepo, redisRepo)
+	trendService := service.NewTrendService(mongoRepo, redisRepo, clk)
 
 	// Initialize auth service
-	authService := service.NewAuthService(userRepo, tenantRepo, deps.AuthClient, deps.Config.JWT.Secret)
-	tokenService := service.NewTokenService(deps.Config.JWT, refreshTokenRepo, userRepo)
-	ptService := service.NewPTService(pkgRepo, contractRepo, schedRepo, workoutSessionRepo, setLogRepo, pbRepo)
-	workoutService := service.NewWorkoutService(exerciseRepo, templateRepo, workoutSessionRepo, schedRepo, setLogRepo, pbRepo, dailyVolumeRepo)
+	authService := service.NewAuthService(userRepo, tenantRepo, deps.AuthClient, deps.Config.JWT.Secret, clk)
+	tokenService := service.NewTokenService(deps.Config.JWT, refreshTokenRepo, userRepo, clk)
+	tokenService.RefuseDeactivatedTenants(tenantRepo)
+	agreementService := service.NewAgreementService(agreementRepo, contractRepo, pkgRepo, tenantRepo, userRepo, fileRepo, clk)
+	documentService := service.NewDocumentService(documentRepo, documentAcceptanceRepo, fileRepo, clk)
+
+	// Online sessions booked without a video link get one from the configured provider
+	var meetings domain.MeetingLinkGenerator
+	switch deps.Config.Meeting.Provider {
+	case "zoom":
+		meetings = meeting.NewZoom(meeting.ZoomConfig{
+			AccountID:    deps.Config.Meeting.ZoomAccountID,
+			ClientID:     deps.Config.Meeting.ZoomClientID,
+			ClientSecret: deps.Config.Meeting.ZoomClientSecret,
+		})
+	case "room":
+		meetings = meeting.NewRoom(deps.Config.Meeting.RoomBaseURL)
+	}
+	ptService := service.NewPTService(pkgRepo, contractRepo, schedRepo, workoutSessionRepo, setLogRepo, creditRepo, agreementService, documentService, locker, coachAvailabilityRepo, meetings, outbox, clk)
+
+	// Background job executions are recorded so platform admins can inspect and retry them
+	jobRunner := jobs.NewRunner(jobRunRepo, clk)
+
+	// Daily volumes and personal bests are derived from the workout event log
+	workoutEvents := service.NewWorkoutEventLog(workoutEventRepo, clk)
+	workoutEvents.TrackRuns(jobRunner)
+	workoutService := service.NewWorkoutService(exerciseRepo, templateRepo, workoutSessionRepo, schedRepo, setLogRepo, pbRepo, dailyVolumeRepo, workoutEvents, clk)
+	workoutEvents.Subscribe("volume aggregator", workoutService)
+	pbDetector := service.NewPersonalBestDetector(setLogRepo, pbRepo, schedRepo, exerciseRepo, tenantRepo)
+	pbDetector.TrackRuns(jobRunner)
+	pbDetector.OnNewPB(webhookService)
+	workoutEvents.Subscribe("PB detector", pbDetector)
+	workoutEvents.Subscribe("webhooks", webhookService)
+	// Members' activity feeds are projected from the workout event log, new PBs and the outbox
+	timelineService := service.NewTimelineService(repository.NewMongoTimelineRepository(deps.MongoDB), mongoRepo, contractRepo, userRepo, exerciseRepo, clk)
+	workoutEvents.Subscribe("timeline", timelineService)
+	pbDetector.OnNewPB(timelineService)
+	outboxRelay.Handle(domain.OutboxTopicScanDigitized, timelineService.HandleScanDigitized)
+	outboxRelay.Handle(domain.OutboxTopicContractCreated, timelineService.HandleContractCreated)
+	exerciseMergeService := service.NewExerciseMergeService(exerciseRepo, repository.NewMongoExerciseMergeRepository(deps.MongoDB), transactor, clk)
+	demoService := service.NewDemoService(demoRepo, tenantRepo, branchRepo, exerciseRepo, clk)
+	transferService := service.NewMemberTransferService(userRepo, branchRepo, schedRepo, repository.NewMongoMemberTransferRepository(deps.MongoDB), transactor, clk)
+	gymImportService := service.NewGymImportService(repository.NewMongoGymImportRepository(deps.MongoDB), userRepo, pkgRepo, schedRepo, ptService, jobRunner, clk,
+		gymimport.NewGlofox(), gymimport.NewMindbody())
+	tenantDataRepo := repository.NewMongoTenantDataRepository(deps.MongoDB)
+	offboardingService := service.NewTenantOffboardingService(repository.NewMongoTenantOffboardingRepository(deps.MongoDB),
+		tenantDataRepo, tenantRepo, fileRepo, jobRunner, clk)
+	deletedRecordService := service.NewDeletedRecordService(userRepo, branchRepo, tenantRepo, tenantDataRepo, clk)
+	auditLogRepo := repository.NewMongoAuditLogRepository(deps.MongoDB)
+
+	// Notifications are only logged until their providers are configured
+	notificationPrefsRepo := repository.NewMongoNotificationPreferencesRepository(deps.MongoDB)
+	deviceTokenRepo := repository.NewMongoDeviceTokenRepository(deps.MongoDB)
+	var pushSender domain.NotificationSender = notify.NewLogSender(domain.ChannelPush)
+	if deps.PushClient != nil {
+		pushSender = notify.NewFCMSender(deps.PushClient, deviceTokenRepo)
+	}
+	var mailer domain.EmailSender
+	switch email := deps.Config.Email; email.Provider {
+	case "smtp":
+		mailer = mail.NewSMTP(mail.SMTPConfig{
+			Host:     email.SMTPHost,
+			Port:     email.SMTPPort,
+			Username: email.SMTPUsername,
+			Password: email.SMTPPassword,
+			From:     email.From,
+		})
+	case "sendgrid":
+		mailer = mail.NewSendGrid(email.SendGridAPIKey, email.From)
+	}
+	var emailSender domain.NotificationSender = notify.NewLogSender(domain.ChannelEmail)
+	if mailer != nil {
+		emailSender = notify.NewEmailSender(mailer, userRepo, tenantRepo)
+	}
+	notificationService := service.NewNotificationService(notificationPrefsRepo, repository.NewMongoInboxRepository(deps.MongoDB), clk,
+		pushSender,
+		emailSender,
+		notify.NewLogSender(domain.ChannelWhatsApp),
+	)
+	notificationService.CaptureSandbox(sandboxService)
+	notificationService.TrackDevices(deviceTokenRepo)
+	// Scans are only processed for members who consented to the health data policy
+	healthConsentService := service.NewHealthConsentService(repository.NewMongoHealthConsentRepository(deps.MongoDB), userRepo, notificationService, clk)
+	scanService.RequireHealthConsent(healthConsentService)
+	guardianService := service.NewGuardianService(repository.NewMongoGuardianConsentRepository(deps.MongoDB), userRepo, tenantRepo, clk)
+	ptService.RequireGuardianConsent(guardianService)
+	reminderService := service.NewReminderService(schedRepo, notificationPrefsRepo, notificationService)
+	confirmationService := service.NewSessionConfirmationService(schedRepo, notificationPrefsRepo, notificationService, clk)
+	sessionPlanService := service.NewSessionPlanService(schedRepo, workoutSessionRepo, workoutService, notificationService, clk)
+	substitutionService := service.NewSubstitutionService(userRepo, schedRepo, contractRepo, repository.NewMongoSubstitutionRepository(deps.MongoDB), notificationService, transactor, clk)
+	handoverService := service.NewHandoverService(repository.NewMongoHandoverRepository(deps.MongoDB), userRepo, contractRepo,
+		schedRepo, setLogRepo, exerciseRepo, notificationService, transactor, clk)
+	substitutionService.BriefSubstitutes(handoverService)
+	syncService := service.NewSyncService(schedRepo, setLogRepo, workoutSessionRepo, userRepo, contractRepo, repository.NewMongoSyncLogRepository(deps.MongoDB), transactor, workoutService, clk)
+	// Bookings and packages set up for members are pushed with a link to the app screen
+	memberAppNotifier := service.NewMemberAppNotifier(schedRepo, contractRepo, notificationService)
+	outboxRelay.Handle(domain.OutboxTopicScheduleChanged, memberAppNotifier.HandleScheduleChanged)
+	outboxRelay.Handle(domain.OutboxTopicContractCreated, memberAppNotifier.HandleContractCreated)
+	outboxRelay.Handle(domain.OutboxTopicContractLowSessions, memberAppNotifier.HandleLowSessions)
+	// Cancellations, members' moves, completed sessions and new PBs are pushed to the other side
+	sessionEventNotifier := service.NewSessionEventNotifier(schedRepo, exerciseRepo, notificationService, clk)
+	outboxRelay.Handle(domain.OutboxTopicScheduleChanged, sessionEventNotifier.HandleScheduleChanged)
+	workoutEvents.Subscribe("session pushes", sessionEventNotifier)
+	pbDetector.OnNewPB(sessionEventNotifier)
+
+	// AI form review needs ffmpeg on the host; without it videos simply get no feedback
+	setVideoRepo := repository.NewMongoSetVideoRepository(deps.MongoDB)
+	var formFeedbackService *service.FormFeedbackService
+	if deps.Config.Form.Enabled {
+		extractor, err := ffmpeg.NewFrameExtractor(deps.Config.Form.FFmpegPath)
+		if err != nil {
+			log.Printf("Warning: AI form feedback disabled: %v", err)
+		} else {
+			formFeedbackService = service.NewFormFeedbackService(setVideoRepo, setLogRepo, exerciseRepo, tenantRepo,
+				repository.NewMongoAIUsageRepository(deps.MongoDB), extractor,
+				service.NewOpenRouterFormAnalyzer(deps.Config.OpenRouter.APIKey, deps.Config.Form.Model), clk)
+			formFeedbackService.RestrictMinors(guardianService)
+		}
+	}
+	setVideoService := service.NewSetVideoService(setVideoRepo, setLogRepo, schedRepo, fileRepo, formFeedbackService, service.VideoLimits{
+		MaxBytes:    deps.Config.Server.MaxVideoSizeMB * 1024 * 1024,
+		MaxDuration: time.Duration(deps.Config.Server.MaxVideoSeconds) * time.Second,
+	}, clk)
 
 	// Initialize payment service
-	paymentProvider := service.NewPaymentProvider()
+	paymentProvider := service.NewPaymentProvider(clk)
+	installmentService := service.NewInstallmentService(invoiceRepo, contractRepo, paymentProvider, sandboxService, int(deps.Config.Jobs.InstallmentGraceDays), clk)
+	manualPaymentService := service.NewManualPaymentService(invoiceRepo, ptService, installmentService, clk)
+	// Members who opt in get the next package with an invoice when the current one runs out
+	renewalService := service.NewRenewalService(contractRepo, invoiceRepo, ptService, paymentProvider, sandboxService, notificationService, clk)
+	ptService.RenewOnDepletion(renewalService)
+	installmentService.ActivateRenewals(renewalService)
+	installmentService.SendReceipts(notificationService)
+	renewalService.ShowOnTimeline(timelineService)
+	// Unpaid checkouts are reminded, expired, and reopened when the member tries again
+	invoiceExpiryService := service.NewInvoiceExpiryService(invoiceRepo, paymentProvider, sandboxService, notificationService, clk)
+	settlementService := service.NewSettlementService(invoiceRepo, repository.NewMongoSettlementRepository(deps.MongoDB), clk)
 
 	// Initialize dashboard service
-	dashboardService := service.NewDashboardService(contractRepo, schedRepo, mongoRepo, workoutSessionRepo, userRepo, pbRepo)
+	dashboardService := service.NewDashboardService(contractRepo, schedRepo, mongoRepo, workoutSessionRepo, userRepo, pbRepo, clk)
+	trainingLoadService := service.NewTrainingLoadService(schedRepo, userRepo, clk)
+	assessmentService := service.NewAssessmentService(repository.NewMongoAssessmentRepository(deps.MongoDB), schedRepo, clk)
+	progressScoreService := service.NewProgressScoreService(tenantRepo, userRepo, schedRepo, dailyVolumeRepo, mongoRepo, pbRepo, repository.NewMongoProgressScoreRepository(deps.MongoDB), clk)
+	progressReportService := service.NewProgressReportService(tenantRepo, userRepo, mongoRepo, dailyVolumeRepo, pbRepo, exerciseRepo, schedRepo,
+		repository.NewMongoAIUsageRepository(deps.MongoDB), service.NewOpenRouterProgressSummarizer(deps.Config.OpenRouter.APIKey, deps.Config.OpenRouter.Model), fileRepo, clk)
+	// Coaches' session photos, shown with the workout and, if the member agrees, in reports
+	sessionPhotoService := service.NewSessionPhotoService(repository.NewMongoSessionPhotoRepository(deps.MongoDB), schedRepo, fileRepo,
+		deps.Config.Server.MaxUploadSizeMB*1024*1024, clk)
+	progressReportService.IncludePhotos(sessionPhotoService)
 
 	// Initialize handlers
-	scanHandler := handler.NewScanHandler(scanService, deps.Config.Server.MaxUploadSizeMB)
-	analyticsHandler := handler.NewAnalyticsHandler(analyticsService, trendService)
+	scanHandler := handler.NewScanHandler(scanService, ptService, deps.Config.Server.MaxUploadSizeMB)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService, trendService, ptService)
 	authHandler := handler.NewAuthHandler(authService, tokenService)
-	saasHandler := handler.NewSaaSHandler(tenantRepo, userRepo, branchRepo)
-	proHandler := handler.NewProHandler(ptService, userRepo, analyticsService, dashboardService, pbRepo, scanService, mongoRepo, workoutService, schedRepo, deps.Config.Server.MaxUploadSizeMB)
-	ptHandler := handler.NewPTHandler(ptService, branchRepo, userRepo, workoutService)
-	workoutHandler := handler.NewWorkoutHandler(workoutService, exerciseRepo, templateRepo)
-	memberHandler := handler.NewMemberHandler(pbRepo, workoutService, ptService, schedRepo, mongoRepo, redisRepo, exerciseRepo, userRepo, authService)
-	paymentHandler := handler.NewPaymentHandler(invoiceRepo, pkgPaymentRepo, paymentProvider)
+	memberInviteService := service.NewMemberInviteService(repository.NewMongoMemberInviteRepository(deps.MongoDB), userRepo, tenantRepo, ptService, deps.AuthClient, clk)
+	if mailer != nil {
+		memberInviteService.EmailInvites(mailer)
+	}
+	memberInviteHandler := handler.NewMemberInviteHandler(memberInviteService, tokenService)
+	joinGuard := service.NewJoinCodeGuard(repository.NewRedisAttemptTracker(deps.RedisClient), clk)
+	customFieldService := service.NewCustomFieldService(tenantRepo, contractRepo)
+	customFieldHandler := handler.NewCustomFieldHandler(customFieldService)
+	searchHandler := handler.NewSearchHandler(service.NewSearchService(repository.NewMongoSearchRepository(deps.MongoDB), contractRepo))
+	saasHandler := handler.NewSaaSHandler(tenantRepo, userRepo, branchRepo, joinGuard, digitizerService, deletedRecordService, memberAppNotifier, customFieldService)
+	proHandler := handler.NewProHandler(ptService, userRepo, analyticsService, dashboardService, pbRepo, scanService, mongoRepo, workoutService, schedRepo, documentService, assessmentService, guardianService, customFieldService, deps.Config.Server.MaxUploadSizeMB)
+	ptHandler := handler.NewPTHandler(ptService, branchRepo, userRepo, workoutService, customFieldService)
+	equipmentRepo := repository.NewMongoEquipmentRepository(deps.MongoDB)
+	equipmentMaintenanceRepo := repository.NewMongoEquipmentMaintenanceRepository(deps.MongoDB)
+	equipmentService := service.NewEquipmentService(equipmentRepo, equipmentMaintenanceRepo, branchRepo, exerciseRepo, schedRepo, clk)
+	exerciseVideoService := service.NewExerciseVideoService(exerciseRepo, fileRepo, nil, service.VideoLimits{
+		MaxBytes:    deps.Config.Server.MaxDemoVideoSizeMB * 1024 * 1024,
+		MaxDuration: time.Duration(deps.Config.Server.MaxDemoVideoSeconds) * time.Second,
+	}, clk)
+	catalogService := service.NewSeedCatalogService(exerciseRepo, templateRepo, pkgRepo, equipmentRepo, branchRepo)
+	workoutHandler := handler.NewWorkoutHandler(workoutService, exerciseRepo, templateRepo, equipmentService, exerciseVideoService, catalogService)
+	exerciseVideoHandler := handler.NewExerciseVideoHandler(exerciseVideoService)
+	equipmentHandler := handler.NewEquipmentHandler(equipmentService)
+	selfWorkoutHandler := handler.NewSelfWorkoutHandler(workoutService, userRepo)
+	setVideoHandler := handler.NewSetVideoHandler(setVideoService)
+	sessionPhotoHandler := handler.NewSessionPhotoHandler(sessionPhotoService, userRepo)
+	memberHandler := handler.NewMemberHandler(pbRepo, workoutService, ptService, schedRepo, mongoRepo, redisRepo, exerciseRepo, userRepo, authService, setVideoService, progressScoreService, sessionPhotoService)
+	salesFunnelService := service.NewSalesFunnelService(repository.NewMongoSalesEventRepository(deps.MongoDB), pkgPaymentRepo, clk)
+	paymentHandler := handler.NewPaymentHandler(invoiceRepo, pkgPaymentRepo, paymentProvider, salesFunnelService, sandboxService, invoiceExpiryService)
+	salesAnalyticsHandler := handler.NewSalesAnalyticsHandler(salesFunnelService)
+	presenceService := service.NewPresenceService(repository.NewRedisPresenceRepository(deps.RedisClient), userRepo, schedRepo, clk)
+	presenceHandler := handler.NewPresenceHandler(presenceService)
+	tenantDashboardHandler := handler.NewTenantDashboardHandler(service.NewTenantDashboardService(
+		repository.NewMongoDashboardLayoutRepository(deps.MongoDB), contractRepo, manualPaymentService, salesFunnelService, substitutionService, progressScoreService,
+		presenceService, clk))
+	reportScheduleService := service.NewReportScheduleService(repository.NewMongoReportScheduleRepository(deps.MongoDB), userRepo, tenantRepo,
+		manualPaymentService, ptService, fileRepo, notificationService, webhook.NewPoster(clk), clk)
+	reportScheduleHandler := handler.NewReportScheduleHandler(reportScheduleService)
+	tenantWebhookHandler := handler.NewTenantWebhookHandler(webhookService)
+	buckets := repository.NewRedisTokenBucket(deps.RedisClient)
+	widgetService := service.NewWidgetService(repository.NewMongoWidgetTokenRepository(deps.MongoDB), buckets,
+		userRepo, branchRepo, coachAvailabilityRepo, pkgRepo, clk)
+	widgetHandler := handler.NewWidgetHandler(widgetService)
+	shareLinkHandler := handler.NewShareLinkHandler(service.NewShareLinkService(repository.NewMongoShareLinkRepository(deps.MongoDB),
+		buckets, tenantRepo, userRepo, pbRepo, exerciseRepo, dailyVolumeRepo, clk))
+	agreementHandler := handler.NewAgreementHandler(agreementService, tenantRepo, deps.Config.Server.MaxUploadSizeMB)
+	documentHandler := handler.NewDocumentHandler(documentService, deps.Config.Server.MaxUploadSizeMB)
+	demoHandler := handler.NewDemoHandler(demoService)
+	jobHandler := handler.NewJobHandler(jobRunRepo, jobRunner)
+	transferHandler := handler.NewTransferHandler(transferService)
+	exerciseMergeHandler := handler.NewExerciseMergeHandler(exerciseMergeService)
+	gymImportHandler := handler.NewGymImportHandler(gymImportService, deps.Config.Server.MaxUploadSizeMB)
+	notificationHandler := handler.NewNotificationHandler(notificationService, notificationPrefsRepo)
+	sessionPlanHandler := handler.NewSessionPlanHandler(sessionPlanService)
+	confirmationHandler := handler.NewSessionConfirmationHandler(confirmationService)
+	progressScoreHandler := handler.NewProgressScoreHandler(progressScoreService, tenantRepo)
+	pbRulesHandler := handler.NewPBRulesHandler(pbDetector, tenantRepo)
+	metricVisibilityHandler := handler.NewMetricVisibilityHandler(ptService, redisRepo)
+	syncHandler := handler.NewSyncHandler(syncService)
+	progressReportHandler := handler.NewProgressReportHandler(progressReportService)
+	assessmentHandler := handler.NewAssessmentHandler(assessmentService, userRepo)
+	trainingLoadHandler := handler.NewTrainingLoadHandler(trainingLoadService, userRepo)
+	sandboxHandler := handler.NewSandboxHandler(sandboxService)
+	healthConsentHandler := handler.NewHealthConsentHandler(healthConsentService)
+	guardianHandler := handler.NewGuardianHandler(guardianService)
+	offboardingHandler := handler.NewOffboardingHandler(offboardingService)
+	substitutionHandler := handler.NewSubstitutionHandler(substitutionService, userRepo)
+	handoverHandler := handler.NewHandoverHandler(handoverService, userRepo)
+	installmentHandler := handler.NewInstallmentHandler(installmentService)
+	manualPaymentHandler := handler.NewManualPaymentHandler(manualPaymentService)
+	settlementHandler := handler.NewSettlementHandler(settlementService, deps.Config.Server.MaxUploadSizeMB)
+	auditLogHandler := handler.NewAuditLogHandler(auditLogRepo)
+	renewalHandler := handler.NewRenewalHandler(renewalService)
+	timelineHandler := handler.NewTimelineHandler(timelineService, userRepo)
 
 	// Webhook handler (for payment callbacks)
 	ipaymuAPIKey := os.Getenv("IPAYMU_API_KEY")
 	ipaymuVA := os.Getenv("IPAYMU_VA")
-	webhookHandler := handler.NewWebhookHandler(invoiceRepo, pkgPaymentRepo, subscriptionRepo, userRepo, ipaymuAPIKey, ipaymuVA)
+	webhookHandler := handler.NewWebhookHandler(invoiceRepo, pkgPaymentRepo, subscriptionRepo, userRepo, salesFunnelService, installmentService, ptService, ipaymuAPIKey, ipaymuVA, clk)
 
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
 		AppName:      "HOM Gym Digitizer API",
-		BodyLimit:    int(deps.Config.Server.MaxUploadSizeMB * 1024 * 1024),
+		BodyLimit:    int(max(deps.Config.Server.MaxUploadSizeMB, deps.Config.Server.MaxVideoSizeMB, deps.Config.Server.MaxDemoVideoSizeMB) * 1024 * 1024), // Largest upload; see BodyGuard below
 		ErrorHandler: customErrorHandler,
 	})
 
-	// Global middleware
-	app.Use(recover.New())
+	// Background work runs on every replica: the relay claims messages one by one and the
+	// scheduler takes a lock per job slot, so nothing is processed twice
+	jobScheduler := jobs.NewScheduler(locker, clk, jobRunner)
+	if months := deps.Config.Jobs.ArchiveAfterMonths; months > 0 {
+		archiveRepo := repository.NewMongoWorkoutArchiveRepository(deps.MongoDB)
+		jobScheduler.Register(jobs.ArchiveWorkouts(archiveRepo, clk, int(months)))
+	}
+	if minutes := deps.Config.Jobs.ReminderMinutes; minutes > 0 {
+		jobScheduler.Register(jobs.SessionReminders(reminderService, clk, time.Duration(minutes)*time.Minute))
+		jobScheduler.Register(jobs.UnconfirmedSessions(confirmationService, time.Duration(minutes)*time.Minute))
+	}
+	if formFeedbackService != nil {
+		jobScheduler.Register(jobs.FormFeedback(formFeedbackService))
+	}
+	jobScheduler.Register(jobs.ProgressScores(progressScoreService))
+	jobScheduler.Register(jobs.OverdueInstallments(installmentService))
+	jobScheduler.Register(jobs.CreditExpiry(service.NewCreditExpiryService(contractRepo, ptService, notificationService, clk)))
+	jobScheduler.Register(jobs.ContractRenewals(renewalService))
+	jobScheduler.Register(jobs.InvoiceExpiry(invoiceExpiryService))
+	jobScheduler.Register(jobs.ReportSchedules(reportScheduleService))
+	jobScheduler.Register(jobs.WebhookDeliveries(webhookService))
+	jobScheduler.Register(jobs.TenantOffboarding(offboardingService))
+	if days := deps.Config.Jobs.ScanImageDays; days > 0 {
+		jobScheduler.Register(jobs.ArchiveScanImages(scanService, clk, int(days)))
+	}
+	if days := deps.Config.Jobs.DeletedRetentionDays; days > 0 {
+		jobScheduler.Register(jobs.PurgeDeletedRecords(deletedRecordService, clk, int(days)))
+	}
+
+	platformConfigHandler := handler.NewPlatformConfigHandler(deps.Config, jobScheduler)
+
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	go outboxRelay.Run(backgroundCtx, time.Second)
+	jobScheduler.Start(backgroundCtx)
+	app.Hooks().OnShutdown(func() error {
+		stopBackground()
+		return nil
+	})
+
+	// Global middleware. The error reporter wraps recover so it sees panics as errors.
+	if deps.Config.Errors.Enabled {
+		reporter, err := sentry.NewReporter(sentry.Config{
+			DSN:         deps.Config.Errors.DSN,
+			Environment: deps.Config.Errors.Environment,
+			Release:     deps.Config.Errors.Release,
+		})
+		if err != nil {
+			log.Printf("Warning: error reporting disabled: %v", err)
+		} else {
+			app.Use(middleware.ReportErrors(reporter))
+		}
+	}
+	app.Use(recover.New(recover.Config{
+		EnableStackTrace:  true,
+		StackTraceHandler: middleware.CapturePanicStack,
+	}))
 	app.Use(logger.New())
 
 	// OpenTelemetry tracing middleware (before other middleware)
@@ -128,19 +450,52 @@ func NewApp(deps AppDependencies) *fiber.App {
 		app.Use(telemetry.FiberMiddleware())
 	}
 
+	// Widgets are embedded on the tenants' own sites and get their own CORS policy below
 	app.Use(cors.New(cors.Config{
+		Next:             func(c *fiber.Ctx) bool { return strings.HasPrefix(c.Path(), "/v1/public/") },
 		AllowOrigins:     "http://localhost:3000, http://localhost:3001, http://192.168.1.10:3000, https://pt.cek-sport.com",
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-Correlation-ID",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-Correlation-ID, " + domain.IdempotencyKeyHeader,
 		AllowMethods:     "GET, POST, PUT, PATCH, DELETE, OPTIONS",
-		AllowCredentials: true, // Required for httpOnly cookie refresh tokens
+		ExposeHeaders:    fiber.HeaderRetryAfter, // Sent with 429s; see middleware.RateLimit
+		AllowCredentials: true,                   // Required for httpOnly cookie refresh tokens
+	}))
+
+	// Only upload routes accept large or multipart bodies; everything else is JSON
+	uploadBytes := int(deps.Config.Server.MaxUploadSizeMB * 1024 * 1024)
+	videoBytes := int(deps.Config.Server.MaxVideoSizeMB * 1024 * 1024)
+	demoVideoBytes := int(deps.Config.Server.MaxDemoVideoSizeMB * 1024 * 1024)
+	multipart := []string{fiber.MIMEMultipartForm}
+	app.Use(middleware.BodyGuard(middleware.BodyGuardConfig{
+		MaxBytes: int(deps.Config.Server.MaxJSONBodyKB * 1024),
+		Rules: []middleware.BodyRule{
+			{Method: fiber.MethodPost, Path: "/v1/me/scans/digitize", MaxBytes: uploadBytes, ContentTypes: multipart},
+			{Method: fiber.MethodPost, Path: "/v1/pro/members/:id/scans", MaxBytes: uploadBytes, ContentTypes: multipart},
+			{Method: fiber.MethodPost, Path: "/v1/me/contracts/:id/agreement/sign", MaxBytes: uploadBytes,
+				ContentTypes: []string{fiber.MIMEMultipartForm, fiber.MIMEApplicationJSON}},
+			{Method: fiber.MethodPost, Path: "/v1/tenant-admin/documents", MaxBytes: uploadBytes, ContentTypes: multipart},
+			{Method: fiber.MethodPut, Path: "/v1/tenant-admin/documents/:id", MaxBytes: uploadBytes, ContentTypes: multipart},
+			{Method: fiber.MethodPost, Path: "/v1/me/sets/:id/videos", MaxBytes: videoBytes, ContentTypes: multipart},
+			{Method: fiber.MethodPost, Path: "/v1/pro/sets/:id/videos", MaxBytes: videoBytes, ContentTypes: multipart},
+			{Method: fiber.MethodPost, Path: "/v1/pro/schedules/:id/photos", MaxBytes: uploadBytes, ContentTypes: multipart},
+			{Method: fiber.MethodPost, Path: "/v1/exercises/:id/video", MaxBytes: demoVideoBytes, ContentTypes: multipart},
+			{Method: fiber.MethodPost, Path: "/v1/exercises/import", MaxBytes: uploadBytes,
+				ContentTypes: []string{fiber.MIMEApplicationJSON, "text/csv"}},
+			// iPaymu may post its callback form-encoded
+			{Method: fiber.MethodPost, Path: "/api/payments/webhook/ipaymu", MaxBytes: int(deps.Config.Server.MaxJSONBodyKB * 1024),
+				ContentTypes: []string{fiber.MIMEApplicationJSON, fiber.MIMEApplicationForm}},
+			{Method: fiber.MethodPost, Path: "/v1/payments/webhook", MaxBytes: int(deps.Config.Server.MaxJSONBodyKB * 1024),
+				ContentTypes: []string{fiber.MIMEApplicationJSON, fiber.MIMEApplicationForm}},
+		},
 	}))
 
 	// Health check endpoint
 	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"status":  "healthy",
-			"service": "hom-gym-digitizer",
-		})
+		return response.OK(c, handler.HealthResponse{Status: "healthy", Service: "hom-gym-digitizer"})
+	})
+
+	// Stable codes of error responses, for clients to branch on
+	app.Get("/v1/error-codes", func(c *fiber.Ctx) error {
+		return response.OK(c, domain.ErrorCatalog())
 	})
 
 	// Public API endpoints (no auth required)
@@ -150,11 +505,60 @@ func NewApp(deps AppDependencies) *fiber.App {
 	// API v1 routes
 	v1 := app.Group("/v1")
 
+	// The gateway's settlement callbacks, also under /v1; public, signed by the gateway
+	v1.Post("/payments/webhook", webhookHandler.IPAYMUWebhook)
+
+	// Mutations through the staff APIs are audited, including those refused by role checks
+	auditTrail := audit.Middleware(auditLogRepo, clk)
+
+	// Purchases and bookings from flaky mobile connections are safe to retry with an
+	// Idempotency-Key; responses are kept for a day, well past any client's retries
+	idempotent := middleware.Idempotency(repository.NewRedisIdempotencyStore(deps.RedisClient), 24*time.Hour)
+
+	// Per-IP and per-user limits where requests are expensive or worth guessing at, drawn
+	// from token buckets in Redis shared by every replica
+	limits := deps.Config.RateLimits
+	rateLimited := func(group, perIP, perUser string) fiber.Handler {
+		cfg := middleware.RateLimitConfig{Group: group}
+		if limits.Enabled {
+			cfg.PerIP, _ = domain.ParseRateLimit(perIP) // Checked by config.Validate
+			cfg.PerUser, _ = domain.ParseRateLimit(perUser)
+		}
+		return middleware.RateLimit(buckets, cfg)
+	}
+	authLimited := rateLimited("auth", limits.AuthPerIP, "")
+	aiLimited := rateLimited("digitize", limits.DigitizePerIP, limits.DigitizePerUser) // AI calls
+	paymentLimited := rateLimited("payments", limits.PaymentsPerIP, limits.PaymentsPerUser)
+
+	// Public widget API, authorized by a tenant's widget token instead of a user
+	widgets := v1.Group("/public/widgets")
+	widgets.Use(cors.New(cors.Config{
+		AllowOrigins: "*",
+		AllowHeaders: "Origin, Accept, " + handler.WidgetTokenHeader,
+		AllowMethods: "GET, OPTIONS",
+	}))
+	widgets.Get("/coaches", widgetHandler.Authorize(domain.WidgetScopeCoaches), widgetHandler.GetCoaches)
+	widgets.Get("/timetable", widgetHandler.Authorize(domain.WidgetScopeTimetable), widgetHandler.GetTimetable)
+	widgets.Get("/packages", widgetHandler.Authorize(domain.WidgetScopePackages), widgetHandler.GetPackages)
+
+	// Members' achievement cards, public to anyone with the link
+	shared := v1.Group("/public/share")
+	shared.Use(cors.New(cors.Config{
+		AllowOrigins: "*",
+		AllowHeaders: "Origin, Accept",
+		AllowMethods: "GET, OPTIONS",
+	}))
+	shared.Get("/:token", shareLinkHandler.GetSharedCard)
+	shared.Get("/:token/page", shareLinkHandler.GetSharedPage)
+
 	// Auth endpoints (public)
 	auth := v1.Group("/auth")
+	auth.Use(authLimited)
 	auth.Post("/login", authHandler.LoginOrRegister)
 	auth.Post("/refresh", authHandler.RefreshToken)
 	auth.Post("/logout", authHandler.Logout)
+	auth.Post("/accept-invite", memberInviteHandler.AcceptInvite) // Firebase token of the invited email, like login
+	auth.Post("/switch-tenant", middleware.VerifyMetamorphToken(deps.Config.JWT.Secret), authHandler.SwitchTenant)
 
 	// ===========================================
 	// MEMBER API - /v1/me/* (requires 'member' role)
@@ -169,27 +573,75 @@ func NewApp(deps AppDependencies) *fiber.App {
 	me.Get("/pbs", memberHandler.GetMyPBs)
 	me.Get("/volume-history", memberHandler.GetMyVolumeHistory)
 	me.Get("/schedules", memberHandler.GetMySchedules)
+	me.Get("/schedules/:id/plan", sessionPlanHandler.GetMyPlan)
+	me.Post("/schedules/:id/confirm", confirmationHandler.ConfirmMySession) // Answer a reminder: confirmed or declined
+	me.Get("/progress-score", progressScoreHandler.GetMyProgress)
+	me.Get("/assessments", assessmentHandler.GetMyAssessments)
+	me.Put("/schedules/:id/effort", trainingLoadHandler.RecordMyEffort)
+	me.Get("/training-load", trainingLoadHandler.GetMyTrainingLoad)
+	me.Get("/timeline", timelineHandler.GetMyTimeline) // Sessions, PBs, scans, goals and contracts, most recent first
+
+	// Public links to cards of a PB or a month of training
+	me.Post("/share-links", shareLinkHandler.CreateMyLink)
+	me.Get("/share-links", shareLinkHandler.ListMyLinks)
+	me.Delete("/share-links/:id", shareLinkHandler.RevokeMyLink)
 
 	// Workouts hub endpoints
 	meWorkouts := me.Group("/workouts")
 	meWorkouts.Get("/history", memberHandler.GetMyWorkoutHistory)
+	meWorkouts.Get("/current", selfWorkoutHandler.GetCurrentWorkout)
 	meWorkouts.Get("/:id", memberHandler.GetMyWorkoutDetail)
 
+	// Self-logged workouts, for training without a coach
+	meWorkouts.Post("/", selfWorkoutHandler.StartWorkout)
+	meWorkouts.Post("/:id/exercises", selfWorkoutHandler.AddExercise)
+	meWorkouts.Post("/:id/sets", selfWorkoutHandler.LogSet)
+	meWorkouts.Put("/:id/sets/:set_id", selfWorkoutHandler.UpdateSet)
+	meWorkouts.Post("/:id/complete", selfWorkoutHandler.CompleteWorkout)
+
+	// Form-check videos
+	me.Post("/sets/:id/videos", setVideoHandler.UploadMySetVideo)
+	me.Delete("/videos/:id", setVideoHandler.DeleteMyVideo)
+
+	// Session photos; the workout detail lists them
+	me.Delete("/photos/:id", sessionPhotoHandler.DeleteMyPhoto)
+	me.Get("/photo-consent", sessionPhotoHandler.GetMyConsent)
+	me.Put("/photo-consent", sessionPhotoHandler.UpdateMyConsent)
+
 	meScans := me.Group("/scans")
-	meScans.Post("/digitize", scanHandler.DigitizeScan)
+	meScans.Post("/digitize", aiLimited, scanHandler.DigitizeScan)
 	meScans.Get("/", memberHandler.GetMyScans)   // Optimized: paginated, lightweight list
 	meScans.Get("/:id", memberHandler.GetMyScan) // Optimized: cached detail
 	meScans.Patch("/:id", scanHandler.UpdateScan)
 	meScans.Delete("/:id", scanHandler.DeleteScan)
 
 	me.Post("/join-tenant", saasHandler.JoinTenant)
+	me.Post("/join-branch", saasHandler.JoinBranch)
 	me.Get("/contracts", ptHandler.GetMyContracts)
+	me.Get("/contracts/:id/statement", ptHandler.GetMyContractStatement)
+	me.Put("/contracts/:id/auto-renew", renewalHandler.SetMyAutoRenew)
+	me.Get("/contracts/:id/agreement", agreementHandler.GetMyAgreement)
+	me.Post("/contracts/:id/agreement/sign", agreementHandler.SignMyAgreement)
+	me.Get("/documents", documentHandler.GetMyDocuments)
+	me.Post("/documents/:id/accept", documentHandler.AcceptMyDocument)
+	me.Get("/health-consent", healthConsentHandler.GetMyConsent)
+	me.Post("/health-consent", healthConsentHandler.GrantMyConsent)
+	me.Delete("/health-consent", healthConsentHandler.WithdrawMyConsent)
+	me.Get("/notification-preferences", notificationHandler.GetMyPreferences)
+	me.Put("/notification-preferences", notificationHandler.UpdateMyPreferences)
+	me.Get("/notifications", notificationHandler.GetMyNotifications)
+	me.Get("/notifications/unread-count", notificationHandler.GetMyUnreadCount)
+	me.Post("/notifications/read-all", notificationHandler.MarkAllMyNotificationsRead)
+	me.Post("/notifications/:id/read", notificationHandler.MarkMyNotificationRead)
 
 	// Payment endpoints
 	mePayments := me.Group("/payments")
 	mePayments.Get("/packages", paymentHandler.ListPackages)
-	mePayments.Post("/checkout", paymentHandler.Checkout)
+	mePayments.Post("/packages/:id/view", paymentHandler.TrackPackageView)
+	mePayments.Post("/checkout", paymentLimited, idempotent, paymentHandler.Checkout)
 	mePayments.Get("/status/:id", paymentHandler.GetInvoiceStatus)
+	mePayments.Get("/installments", installmentHandler.ListMyPlans)
+	mePayments.Post("/installments/:id/pay", paymentLimited, installmentHandler.PayNext) // VA for the next installment
 
 	meAnalytics := me.Group("/analytics")
 	meAnalytics.Get("/history", analyticsHandler.GetHistory)
@@ -201,11 +653,22 @@ func NewApp(deps AppDependencies) *fiber.App {
 	pro := v1.Group("/pro")
 	pro.Use(middleware.VerifyMetamorphToken(deps.Config.JWT.Secret))
 	pro.Use(middleware.TenantScope())
+	pro.Use(auditTrail)
 	pro.Use(middleware.AuthorizeRole(domain.RoleCoach, domain.RoleTenantAdmin))
 
 	pro.Get("/clients", proHandler.GetClients)
 	pro.Get("/clients/simple", proHandler.GetClientsSimple) // Lightweight for /members list
 	pro.Get("/clients/:id/history", proHandler.GetClientHistory)
+	pro.Get("/clients/:id/timeline", timelineHandler.GetClientTimeline)
+	pro.Get("/clients/:id/notes", handoverHandler.GetClientNotes)
+	pro.Put("/clients/:id/notes", handoverHandler.UpdateClientNotes)
+	pro.Get("/handovers", handoverHandler.ListMyHandovers)
+	pro.Get("/handovers/:id", handoverHandler.GetHandover)
+	pro.Post("/handovers/:id/acknowledge", handoverHandler.AcknowledgeHandover)
+	// Invites are preferred over creating accounts: members sign up from the link
+	pro.Post("/members/invite", memberInviteHandler.Invite)
+	pro.Get("/members/invites", memberInviteHandler.ListInvites) // Before /members/:id
+	pro.Delete("/members/invites/:id", memberInviteHandler.RevokeInvite)
 	pro.Get("/dashboard/summary", proHandler.GetDashboardSummary)
 	pro.Get("/schedules", proHandler.GetMySchedules)                          // Get coach's schedules for date range
 	pro.Get("/schedules/hydrate", proHandler.HydrateSchedules)                // Login hydration - all statuses including cancelled
@@ -216,15 +679,52 @@ func NewApp(deps AppDependencies) *fiber.App {
 	pro.Get("/packages", proHandler.ListPackages)                             // List available packages
 	pro.Get("/scans/:id", proHandler.GetScan)                                 // Get single scan by ID
 	pro.Post("/members", proHandler.CreateMember)                             // Coach creates new member
-	pro.Post("/members/:id/scans", proHandler.DigitizeMemberScan)             // Coach uploads scan for member
-	pro.Post("/contracts", proHandler.CreateContract)                         // Coach creates contract for member
+	pro.Post("/members/:id/scans", aiLimited, proHandler.DigitizeMemberScan)  // Coach uploads scan for member
+	pro.Post("/contracts", idempotent, proHandler.CreateContract)             // Coach creates contract for member
 	pro.Put("/scans/:id", proHandler.UpdateScan)                              // Update scan data
 	pro.Delete("/scans/:id", proHandler.DeleteScan)                           // Delete scan
+	pro.Post("/scans/:id/re-extract", aiLimited, proHandler.ReExtractScan)
+	pro.Post("/members/:id/scans/import-csv", proHandler.ImportMemberScansCSV)
+	pro.Get("/members/:id/health-consent", healthConsentHandler.GetMemberConsent)
+	pro.Post("/members/:id/health-consent/prompt", healthConsentHandler.PromptMember) // Ask the member to (re-)consent
+	pro.Get("/members/:id/minor", guardianHandler.GetMinorStatus)
+	pro.Post("/members/:id/guardian-consent", guardianHandler.RecordConsent)
+	pro.Delete("/members/:id/guardian-consent", guardianHandler.RevokeConsent)
+	pro.Get("/scans/:id/revisions", proHandler.GetScanRevisions)
+	pro.Post("/scans/:id/revisions/:revision_id/revert", proHandler.RevertScan)
+	pro.Get("/contracts/:id/metric-visibility", metricVisibilityHandler.GetVisibility)
+	pro.Put("/contracts/:id/metric-visibility", metricVisibilityHandler.UpdateVisibility)
+	pro.Post("/sync", syncHandler.Sync)
+	pro.Get("/members/:id/progress-score", progressScoreHandler.GetMemberProgress)
+	pro.Post("/members/:id/report", progressReportHandler.GenerateReport)
+	pro.Get("/progress-scores/leaderboard", progressScoreHandler.GetLeaderboard)
+	pro.Get("/members/:id/assessments", assessmentHandler.GetMemberAssessments)
+	pro.Post("/members/:id/assessments", assessmentHandler.RecordAssessment)
+	pro.Get("/members/:id/training-load", trainingLoadHandler.GetMemberTrainingLoad)
+	pro.Put("/schedules/:id/effort", trainingLoadHandler.RecordEffort)
+	pro.Get("/weekly-review", trainingLoadHandler.GetWeeklyReview)
+	pro.Get("/search", searchHandler.Search) // Clients, sessions, exercises and scans in one call
+	pro.Post("/presence", presenceHandler.Heartbeat)
 
-	pro.Post("/schedules", ptHandler.CreateSchedule)
+	pro.Post("/schedules", idempotent, ptHandler.CreateSchedule)
+	pro.Post("/schedules/bulk", ptHandler.CreateScheduleBatch) // Book a whole program; reports the slots it skipped
 	pro.Post("/schedules/:id/complete", ptHandler.CompleteSession)
 	pro.Put("/schedules/:id/status", ptHandler.UpdateScheduleStatus)
+	pro.Put("/schedules/:id/tags", ptHandler.TagSchedule)
+	pro.Put("/schedules/:id/modality", ptHandler.SetSessionModality)
+	pro.Get("/schedules/tag-report", ptHandler.GetMyTagReport)
+	pro.Put("/schedules/:id/plan", sessionPlanHandler.PlanSession) // Brief the member before the session
 	pro.Delete("/schedules/:id", ptHandler.DeleteSchedule)
+	pro.Get("/availability", ptHandler.GetMyAvailability) // Weekly working hours per branch
+	pro.Put("/availability", ptHandler.SetMyAvailability)
+	pro.Get("/notification-preferences", notificationHandler.GetMyPreferences) // Which pushes staff get, e.g. members' moves and PBs
+	pro.Put("/notification-preferences", notificationHandler.UpdateMyPreferences)
+	pro.Get("/utilization", ptHandler.GetMyUtilization) // Booked vs available time per branch
+
+	// Cover while a coach is out: their sessions go to colleagues at the branch
+	pro.Post("/unavailability", substitutionHandler.MarkUnavailable)
+	pro.Get("/substitutions", substitutionHandler.ListOpen) // Sessions at my branches needing a substitute
+	pro.Post("/substitutions/:id/claim", substitutionHandler.Claim)
 
 	// ===========================================
 	// PLATFORM API - /v1/platform/* (requires 'super_admin' role)
@@ -232,12 +732,26 @@ func NewApp(deps AppDependencies) *fiber.App {
 	platform := v1.Group("/platform")
 	platform.Use(middleware.VerifyMetamorphToken(deps.Config.JWT.Secret))
 	platform.Use(middleware.TenantScope())
+	platform.Use(auditTrail)
 	platform.Use(middleware.AuthorizeRole(domain.RoleSuperAdmin))
 
+	platform.Get("/audit-logs", auditLogHandler.ListPlatformLogs) // ?tenant_id=&actor_id=&entity_type=&entity_id=&from=&to=
+
 	platformTenants := platform.Group("/tenants")
 	platformTenants.Post("/", saasHandler.CreateTenant)
+	platformTenants.Get("/deleted", saasHandler.ListDeletedTenants) // Restorable until purged
 	platformTenants.Get("/:id", saasHandler.GetTenant)
 	platformTenants.Put("/:id", saasHandler.UpdateTenant)
+	platformTenants.Delete("/:id", saasHandler.DeleteTenant)
+	platformTenants.Post("/:id/restore", saasHandler.RestoreTenant)
+	platformTenants.Post("/:id/ai-prompt/preview", saasHandler.PreviewAIPrompt)
+	platformTenants.Post("/:id/seed-demo", demoHandler.SeedDemo)     // Populate sales-demo data
+	platformTenants.Delete("/:id/demo-data", demoHandler.DeleteDemo) // Remove all demo data
+	platformTenants.Get("/:id/sandbox/notifications", sandboxHandler.ListNotifications)
+	platformTenants.Post("/:id/sandbox/reset", sandboxHandler.Reset)   // Wipe a sandbox tenant's test data
+	platformTenants.Post("/:id/offboarding", offboardingHandler.Start) // Export, deactivate, then anonymize and purge
+	platformTenants.Get("/:id/offboarding", offboardingHandler.Get)
+	platformTenants.Delete("/:id/offboarding", offboardingHandler.Cancel) // Restore during the grace period
 
 	// Deprecated: Assignments replaced by Contracts
 	// platformAssignments := platform.Group("/assignments")
@@ -247,16 +761,36 @@ func NewApp(deps AppDependencies) *fiber.App {
 	platformTenantAdmins := platform.Group("/tenant-admins")
 	platformTenantAdmins.Post("/", saasHandler.CreateTenantAdmin)
 	platformTenantAdmins.Get("/", saasHandler.ListTenantAdmins)
+	platformTenantAdmins.Get("/deleted", saasHandler.ListDeletedTenantAdmins)
 	platformTenantAdmins.Get("/:id", saasHandler.GetUser)
 	platformTenantAdmins.Put("/:id", saasHandler.UpdateUser)
 	platformTenantAdmins.Delete("/:id", saasHandler.DeleteUser)
+	platformTenantAdmins.Post("/:id/restore", saasHandler.RestoreUser)
+
+	platformUsers := platform.Group("/users")
+	platformUsers.Put("/:id/memberships/:tenant_id", saasHandler.SetMembership)
+	platformUsers.Delete("/:id/memberships/:tenant_id", saasHandler.RemoveMembership)
+	platformUsers.Post("/:id/transfer-tenant", transferHandler.TransferTenant) // Move a member to another tenant
+	platformUsers.Get("/:id/transfers", transferHandler.ListTransfers)
 
 	platformBranches := platform.Group("/branches")
 	platformBranches.Post("/", saasHandler.CreateBranch)
 	platformBranches.Get("/", saasHandler.ListBranches)
+	platformBranches.Get("/deleted", saasHandler.ListDeletedBranches)
 	platformBranches.Get("/:id", saasHandler.GetBranch)
 	platformBranches.Put("/:id", saasHandler.UpdateBranch)
 	platformBranches.Delete("/:id", saasHandler.DeleteBranch)
+	platformBranches.Post("/:id/restore", saasHandler.RestoreBranch)
+
+	platform.Get("/jobs", jobHandler.ListJobRuns)            // Background job history
+	platform.Post("/jobs/:id/retry", jobHandler.RetryJobRun) // Re-run a failed job
+	platform.Get("/config", platformConfigHandler.GetConfig)
+	platform.Get("/health-data-policy", healthConsentHandler.GetPolicy)
+	platform.Put("/health-data-policy", healthConsentHandler.PublishPolicy) // New version; members consent again
+
+	platformExercises := platform.Group("/exercises")
+	platformExercises.Get("/duplicates", exerciseMergeHandler.ListDuplicates)
+	platformExercises.Post("/merge", exerciseMergeHandler.MergeExercises)
 
 	// ===========================================
 	// TENANT-ADMIN API - /v1/tenant-admin/* (requires 'tenant_admin' role)
@@ -264,6 +798,7 @@ func NewApp(deps AppDependencies) *fiber.App {
 	tenantAdmin := v1.Group("/tenant-admin")
 	tenantAdmin.Use(middleware.VerifyMetamorphToken(deps.Config.JWT.Secret))
 	tenantAdmin.Use(middleware.TenantScope())
+	tenantAdmin.Use(auditTrail)
 	tenantAdmin.Use(middleware.AuthorizeRole(domain.RoleTenantAdmin))
 
 	// Deprecated: Assignments replaced by Contracts
@@ -271,26 +806,44 @@ func NewApp(deps AppDependencies) *fiber.App {
 	// tenantAssignments.Post("/", saasHandler.AssignCoach)
 	// tenantAssignments.Delete("/:id", saasHandler.RemoveAssignment)
 
+	tenantAdmin.Get("/audit-logs", auditLogHandler.ListTenantLogs) // ?actor_id=&entity_type=&entity_id=&from=&to=
+
 	tenantAdminUsers := tenantAdmin.Group("/users")
 	tenantAdminUsers.Get("/", saasHandler.ListUsers)
 	tenantAdminUsers.Post("/", saasHandler.CreateUser)
+	tenantAdminUsers.Get("/deleted", saasHandler.ListDeletedUsers)
 	tenantAdminUsers.Get("/:id", saasHandler.GetUser)
 	tenantAdminUsers.Put("/:id", saasHandler.UpdateUser)
 	tenantAdminUsers.Delete("/:id", saasHandler.DeleteUser)
+	tenantAdminUsers.Post("/:id/restore", saasHandler.RestoreUser)
+	tenantAdminUsers.Post("/:id/transfer-branch", transferHandler.TransferBranch)
 
 	tenantAdminCoaches := tenantAdmin.Group("/coaches")
 	tenantAdminCoaches.Get("/", saasHandler.ListCoaches)
 	tenantAdminCoaches.Post("/", saasHandler.CreateCoach)
+	tenantAdminCoaches.Get("/deleted", saasHandler.ListDeletedCoaches)
 	tenantAdminCoaches.Get("/:id", saasHandler.GetCoach)
 	tenantAdminCoaches.Put("/:id", saasHandler.UpdateCoach)
 	tenantAdminCoaches.Delete("/:id", saasHandler.DeleteCoach)
+	tenantAdminCoaches.Post("/:id/restore", saasHandler.RestoreUser)
+	tenantAdminCoaches.Get("/:id/utilization", ptHandler.GetCoachUtilization)
 
 	tenantAdminBranches := tenantAdmin.Group("/branches")
 	tenantAdminBranches.Post("/", saasHandler.CreateBranch)
 	tenantAdminBranches.Get("/", saasHandler.ListBranches)
+	tenantAdminBranches.Get("/deleted", saasHandler.ListDeletedBranches)
 	tenantAdminBranches.Get("/:id", saasHandler.GetBranch)
 	tenantAdminBranches.Put("/:id", saasHandler.UpdateBranch)
 	tenantAdminBranches.Delete("/:id", saasHandler.DeleteBranch)
+	tenantAdminBranches.Post("/:id/restore", saasHandler.RestoreBranch)
+	tenantAdminBranches.Get("/:id/equipment", equipmentHandler.ListBranchEquipment)
+	tenantAdminBranches.Post("/:id/equipment", equipmentHandler.AddBranchEquipment)
+
+	tenantAdminEquipment := tenantAdmin.Group("/equipment")
+	tenantAdminEquipment.Put("/:id", equipmentHandler.UpdateEquipment)
+	tenantAdminEquipment.Delete("/:id", equipmentHandler.DeleteEquipment)
+	tenantAdminEquipment.Get("/:id/maintenance", equipmentHandler.GetMaintenanceLog)
+	tenantAdminEquipment.Post("/:id/maintenance", equipmentHandler.LogMaintenance) // Breakdowns, repairs and services
 
 	tenantAdminPackages := tenantAdmin.Group("/packages")
 	tenantAdminPackages.Post("/", ptHandler.CreatePackageTemplate)
@@ -301,6 +854,82 @@ func NewApp(deps AppDependencies) *fiber.App {
 	tenantAdminContracts := tenantAdmin.Group("/contracts")
 	tenantAdminContracts.Post("/", ptHandler.CreateContract)
 	tenantAdminContracts.Get("/", ptHandler.ListContracts)
+	tenantAdminContracts.Get("/:id/statement", ptHandler.GetContractStatement)
+	tenantAdminContracts.Post("/:id/credits", ptHandler.AdjustContractCredits)
+	tenantAdminContracts.Post("/:id/transfer", handoverHandler.TransferContract)
+	tenantAdminContracts.Post("/:id/installments", installmentHandler.CreatePlan)
+	tenantAdminContracts.Get("/:id/installments", installmentHandler.GetPlan)
+	tenantAdminContracts.Put("/:id/custom-fields", customFieldHandler.UpdateContractFields)
+
+	tenantAdminPayments := tenantAdmin.Group("/payments")
+	tenantAdminPayments.Post("/manual", manualPaymentHandler.Record)
+	tenantAdminPayments.Get("/reconciliation", manualPaymentHandler.Reconcile)
+
+	tenantAdminFinance := tenantAdmin.Group("/finance")
+	tenantAdminFinance.Post("/reconciliation", settlementHandler.Reconcile)
+	tenantAdminFinance.Get("/reconciliation", settlementHandler.List)
+	tenantAdminFinance.Get("/reconciliation/:id", settlementHandler.Get)
+
+	tenantAdmin.Get("/contract-template", agreementHandler.GetContractTemplate)
+	tenantAdmin.Put("/contract-template", agreementHandler.UpdateContractTemplate)
+	tenantAdmin.Get("/notification-settings", notificationHandler.GetTenantSettings)
+	tenantAdmin.Put("/notification-settings", notificationHandler.UpdateTenantSettings)
+	tenantAdmin.Get("/progress-score-weights", progressScoreHandler.GetWeights)
+	tenantAdmin.Put("/progress-score-weights", progressScoreHandler.UpdateWeights)
+	tenantAdmin.Get("/pb-rules", pbRulesHandler.GetRules)
+	tenantAdmin.Put("/pb-rules", pbRulesHandler.UpdateRules)
+	tenantAdmin.Get("/minor-policy", guardianHandler.GetMinorPolicy)
+	tenantAdmin.Put("/minor-policy", guardianHandler.UpdateMinorPolicy)
+	tenantAdmin.Get("/email-branding", saasHandler.GetEmailBranding)
+	tenantAdmin.Put("/email-branding", saasHandler.UpdateEmailBranding)
+	tenantAdmin.Post("/email-branding/preview", saasHandler.PreviewEmail)
+	tenantAdmin.Get("/custom-fields", customFieldHandler.GetSchema)
+	tenantAdmin.Put("/custom-fields", customFieldHandler.UpdateSchema)
+	tenantAdmin.Post("/pb-rules/rebuild", pbRulesHandler.Rebuild)
+	tenantAdmin.Get("/dashboard", tenantDashboardHandler.GetDashboard)
+	tenantAdmin.Get("/dashboard/presence", presenceHandler.GetBoard) // Coaches in session, online and offline
+	tenantAdmin.Get("/dashboard/layout", tenantDashboardHandler.GetLayout)
+	tenantAdmin.Put("/dashboard/layout", tenantDashboardHandler.UpdateLayout)
+
+	tenantAdminReportSchedules := tenantAdmin.Group("/report-schedules")
+	tenantAdminReportSchedules.Post("/", reportScheduleHandler.CreateSchedule)
+	tenantAdminReportSchedules.Get("/", reportScheduleHandler.ListSchedules)
+	tenantAdminReportSchedules.Get("/:id", reportScheduleHandler.GetSchedule)
+	tenantAdminReportSchedules.Put("/:id", reportScheduleHandler.UpdateSchedule)
+	tenantAdminReportSchedules.Delete("/:id", reportScheduleHandler.DeleteSchedule)
+
+	tenantAdminWebhooks := tenantAdmin.Group("/webhooks")
+	tenantAdminWebhooks.Post("/", tenantWebhookHandler.CreateWebhook)
+	tenantAdminWebhooks.Get("/", tenantWebhookHandler.ListWebhooks)
+	tenantAdminWebhooks.Get("/:id", tenantWebhookHandler.GetWebhook)
+	tenantAdminWebhooks.Put("/:id", tenantWebhookHandler.UpdateWebhook)
+	tenantAdminWebhooks.Delete("/:id", tenantWebhookHandler.DeleteWebhook)
+	tenantAdminWebhooks.Post("/:id/rotate-secret", tenantWebhookHandler.RotateSecret)
+	tenantAdminWebhooks.Get("/:id/deliveries", tenantWebhookHandler.ListDeliveries)
+
+	tenantAdmin.Get("/analytics/sales", salesAnalyticsHandler.GetSalesFunnel)
+	tenantAdmin.Get("/reports/schedule-tags", ptHandler.GetScheduleTagReport)
+	tenantAdmin.Get("/reports/equipment-maintenance", equipmentHandler.GetMaintenanceReport)
+	tenantAdmin.Get("/widget-tokens", widgetHandler.ListTokens)
+	tenantAdmin.Post("/widget-tokens", widgetHandler.CreateToken)
+	tenantAdmin.Delete("/widget-tokens/:id", widgetHandler.RevokeToken)
+	tenantAdmin.Get("/sandbox/notifications", sandboxHandler.ListMyNotifications)
+	tenantAdmin.Get("/substitutions", substitutionHandler.List)
+	tenantAdmin.Post("/substitutions/:id/assign", substitutionHandler.Assign) // Pick the substitute directly
+	tenantAdmin.Post("/substitutions/:id/cancel", substitutionHandler.Cancel)
+
+	// Migrations from other gym systems: upload exports for a preview, then start it
+	tenantAdminImports := tenantAdmin.Group("/imports")
+	tenantAdminImports.Post("/", gymImportHandler.CreateImport)
+	tenantAdminImports.Get("/", gymImportHandler.ListImports)
+	tenantAdminImports.Get("/:id", gymImportHandler.GetImport)
+	tenantAdminImports.Post("/:id/start", gymImportHandler.StartImport)
+
+	tenantAdminDocuments := tenantAdmin.Group("/documents")
+	tenantAdminDocuments.Post("/", documentHandler.CreateDocument)
+	tenantAdminDocuments.Get("/", documentHandler.ListDocuments)
+	tenantAdminDocuments.Put("/:id", documentHandler.UpdateDocument)
+	tenantAdminDocuments.Delete("/:id", documentHandler.DeleteDocument)
 
 	// ===========================================
 	// SHARED /schedules & /contracts API (Coach & Member & Admin)
@@ -313,6 +942,13 @@ func NewApp(deps AppDependencies) *fiber.App {
 	// Reschedule: Coach or Member
 	schedules.Patch("/:id/reschedule", middleware.AuthorizeRole(domain.RoleCoach, domain.RoleMember), ptHandler.RescheduleSession)
 
+	// Push notification devices of any signed-in user
+	devices := v1.Group("/devices")
+	devices.Use(middleware.VerifyMetamorphToken(deps.Config.JWT.Secret))
+	devices.Use(middleware.TenantScope())
+	devices.Post("/", notificationHandler.RegisterMyDevice)
+	devices.Delete("/", notificationHandler.UnregisterMyDevice)
+
 	// Shared Contracts details (for getting by ID)
 	contracts := v1.Group("/contracts")
 	contracts.Use(middleware.VerifyMetamorphToken(deps.Config.JWT.Secret))
@@ -325,22 +961,29 @@ func NewApp(deps AppDependencies) *fiber.App {
 	// Public Read, Admin Write
 
 	// Exercises
-	v1.Get("/exercises", workoutHandler.ListExercises)
+	v1.Get("/exercises", middleware.OptionalMetamorphToken(deps.Config.JWT.Secret), workoutHandler.ListExercises) // Signed-in users also get their tenant's exercises
+	v1.Get("/exercises/:id/video", exerciseVideoHandler.PlayVideo)
 	// Exercise CRUD (Coach and Admin can create/update/delete)
 	adminEx := v1.Group("/exercises")
 	adminEx.Use(middleware.VerifyMetamorphToken(deps.Config.JWT.Secret))
 	adminEx.Use(middleware.TenantScope())
+	adminEx.Use(auditTrail)
 	// Allow Coach to manage exercises (will restrict to SuperAdmin later via Metamorph Dashboard)
 	adminEx.Use(middleware.AuthorizeRole(domain.RoleSuperAdmin, domain.RoleCoach, domain.RoleTenantAdmin))
 	adminEx.Post("/", workoutHandler.CreateExercise)
+	adminEx.Post("/import", middleware.AuthorizeRole(domain.RoleSuperAdmin, domain.RoleTenantAdmin), workoutHandler.ImportExercises)
+	adminEx.Get("/export", workoutHandler.ExportExercises)
 	adminEx.Put("/:id", workoutHandler.UpdateExercise)
 	adminEx.Delete("/:id", workoutHandler.DeleteExercise)
+	adminEx.Post("/:id/video", exerciseVideoHandler.UploadVideo)
+	adminEx.Delete("/:id/video", exerciseVideoHandler.DeleteVideo)
 
 	// Templates
 	v1.Get("/templates", workoutHandler.ListTemplates)
 	adminTpl := v1.Group("/templates")
 	adminTpl.Use(middleware.VerifyMetamorphToken(deps.Config.JWT.Secret))
 	adminTpl.Use(middleware.TenantScope())
+	adminTpl.Use(auditTrail)
 	adminTpl.Use(middleware.AuthorizeRole(domain.RoleSuperAdmin))
 	adminTpl.Post("/", workoutHandler.CreateTemplate)
 	adminTpl.Put("/:id", workoutHandler.UpdateTemplate)
@@ -356,6 +999,9 @@ func NewApp(deps AppDependencies) *fiber.App {
 	pro.Post("/schedules/:schedule_id/exercises", workoutHandler.AddExercise)
 	pro.Delete("/exercises/:id", workoutHandler.RemoveExercise)
 	pro.Put("/exercises/:id", workoutHandler.UpdatePlannedExercise)
+	pro.Get("/exercises/:id/substitutes", equipmentHandler.GetSubstitutes) // :id is a library exercise here
+	pro.Get("/branches/:id/equipment", equipmentHandler.ListBranchEquipment)
+	pro.Post("/equipment/:id/out-of-order", equipmentHandler.ReportOutOfOrder)
 
 	// Atomic set operations (new set_logs collection)
 	pro.Put("/sets/:id", workoutHandler.UpdateSetLog)
@@ -363,17 +1009,31 @@ func NewApp(deps AppDependencies) *fiber.App {
 	pro.Post("/exercises/:id/sets", workoutHandler.AddSetToExercise)
 	pro.Get("/schedules/:schedule_id/sets", workoutHandler.ListScheduleSets)
 	pro.Get("/schedules/:schedule_id/exercises", workoutHandler.ListScheduleExercises)
+	pro.Get("/schedules/:schedule_id/exercises/:exercise_id/last-performance", workoutHandler.GetLastPerformance)
+
+	// Form-check videos
+	pro.Post("/sets/:id/videos", setVideoHandler.UploadSetVideo)
+	pro.Get("/schedules/:schedule_id/videos", setVideoHandler.ListScheduleVideos)
+	pro.Post("/videos/:id/comments", setVideoHandler.AddComment)
+	pro.Delete("/videos/:id", setVideoHandler.DeleteVideo)
+
+	// Session photos
+	pro.Post("/schedules/:id/photos", sessionPhotoHandler.UploadPhoto)
+	pro.Get("/schedules/:id/photos", sessionPhotoHandler.ListSchedulePhotos)
+	pro.Delete("/photos/:id", sessionPhotoHandler.DeletePhoto)
+	pro.Get("/clients/:id/photo-consent", sessionPhotoHandler.GetClientConsent)
+
+	// The API's OpenAPI document, generated from the routes above, and Swagger UI over it
+	docsHandler := handler.NewOpenAPIHandler(apiDocument(app.GetRoutes(true)))
+	app.Get("/v1/openapi.json", docsHandler.GetSpec)
+	app.Get("/v1/docs", docsHandler.GetDocs)
 
 	return app
 }
 
+// customErrorHandler answers errors returned from handlers with their status and a stable
+// error code; see response.Classify
 func customErrorHandler(c *fiber.Ctx, err error) error {
-	code := fiber.StatusInternalServerError
-	if e, ok := err.(*fiber.Error); ok {
-		code = e.Code
-	}
 	log.Printf("Error: %v", err)
-	return c.Status(code).JSON(fiber.Map{
-		"error": err.Error(),
-	})
+	return response.Fail(c, err)
 }
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/oklog/ulid/v2"
+)
+
+// AgreementService generates contract agreement PDFs and captures member e-signatures
+type AgreementService struct {
+	agreementRepo domain.ContractAgreementRepository
+	contractRepo  domain.PTContractRepository
+	pkgRepo       domain.PTPackageRepository
+	tenantRepo    domain.TenantRepository
+	userRepo      domain.UserRepository
+	fileRepo      domain.FileRepository // Optional: documents are not stored, nor drawn signatures taken, when nil
+	clock         domain.Clock          // Stamps signature times
+}
+
+func NewAgreementService(
+	agreementRepo domain.ContractAgreementRepository,
+	contractRepo domain.PTContractRepository,
+	pkgRepo domain.PTPackageRepository,
+	tenantRepo domain.TenantRepository,
+	userRepo domain.UserRepository,
+	fileRepo domain.FileRepository,
+	clk domain.Clock,
+) *AgreementService {
+	return &AgreementService{
+		agreementRepo: agreementRepo,
+		contractRepo:  contractRepo,
+		pkgRepo:       pkgRepo,
+		tenantRepo:    tenantRepo,
+		userRepo:      userRepo,
+		fileRepo:      fileRepo,
+		clock:         clock.OrReal(clk),
+	}
+}
+
+// SignatureInput holds the member's signature and request metadata
+type SignatureInput struct {
+	TypedName string // Typed consent
+	Image     []byte // Drawn signature (PNG or JPEG)
+	IPAddress string
+	UserAgent string
+}
+
+// GenerateForContract renders the tenant's template for a contract and stores the unsigned PDF
+func (s *AgreementService) GenerateForContract(ctx context.Context, contract *domain.PTContract) (*domain.ContractAgreement, error) {
+	tenant, err := s.tenantRepo.GetByID(ctx, contract.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tenant: %w", err)
+	}
+	member, err := s.userRepo.GetByID(ctx, contract.MemberID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load member: %w", err)
+	}
+
+	packageName := ""
+	if pkg, err := s.pkgRepo.GetByID(ctx, contract.PackageID); err == nil {
+		packageName = pkg.Name
+	}
+
+	tpl := tenant.ContractTemplate
+	if strings.TrimSpace(tpl) == "" {
+		tpl = domain.DefaultContractTemplate
+	}
+	body := strings.NewReplacer(
+		"{{tenant_name}}", tenant.Name,
+		"{{member_name}}", member.Name,
+		"{{member_email}}", member.Email,
+		"{{package_name}}", packageName,
+		"{{total_sessions}}", strconv.Itoa(contract.TotalSessions),
+		"{{price}}", contract.Price.String(),
+		"{{contract_id}}", contract.ID,
+		"{{date}}", contract.CreatedAt.Format("2006-01-02"),
+	).Replace(tpl)
+
+	agreement := &domain.ContractAgreement{
+		TenantID:   contract.TenantID,
+		ContractID: contract.ID,
+		MemberID:   contract.MemberID,
+		Body:       body,
+		Status:     domain.AgreementStatusPending,
+	}
+
+	if s.fileRepo != nil {
+		doc := newPDFDocument()
+		doc.Paragraph(body, 11)
+		filename := fmt.Sprintf("agreements/%s/%s.pdf", contract.TenantID, contract.ID)
+		url, err := s.fileRepo.Upload(ctx, doc.Bytes(), filename, "application/pdf")
+		if err != nil {
+			return nil, err
+		}
+		agreement.DocumentURL = url
+	}
+
+	if err := s.agreementRepo.Create(ctx, agreement); err != nil {
+		return nil, err
+	}
+	return agreement, nil
+}
+
+// GetForMember returns the agreement for a member's contract, generating it for contracts
+// created before agreements existed
+func (s *AgreementService) GetForMember(ctx context.Context, contractID, memberID string) (*domain.ContractAgreement, error) {
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	if contract.MemberID != memberID {
+		return nil, domain.ErrForbidden
+	}
+
+	agreement, err := s.agreementRepo.GetByContractID(ctx, contractID)
+	if err == domain.ErrAgreementNotFound {
+		return s.GenerateForContract(ctx, contract)
+	}
+	return agreement, err
+}
+
+// Sign records the member's signature and stores a signed copy of the agreement. A drawn
+// signature needs the file store to be kept; without one, members sign by typing their name.
+func (s *AgreementService) Sign(ctx context.Context, contractID, memberID string, input SignatureInput) (*domain.ContractAgreement, error) {
+	agreement, err := s.GetForMember(ctx, contractID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if agreement.Status == domain.AgreementStatusSigned {
+		return nil, domain.ErrAgreementAlreadySigned
+	}
+
+	typedName := strings.TrimSpace(input.TypedName)
+	if typedName == "" && len(input.Image) == 0 {
+		return nil, domain.ErrSignatureRequired
+	}
+	if len(input.Image) > 0 && s.fileRepo == nil {
+		return nil, domain.ErrSignatureImageNotKept
+	}
+
+	now := s.clock.Now()
+	signature := &domain.Signature{
+		Method:    domain.SignatureMethodTyped,
+		TypedName: typedName,
+		IPAddress: input.IPAddress,
+		UserAgent: input.UserAgent,
+		SignedAt:  now,
+	}
+	if len(input.Image) > 0 {
+		signature.Method = domain.SignatureMethodImage
+	}
+
+	// Files are named per attempt, so one that loses to a concurrent signature can't
+	// overwrite the winner's
+	attempt := ulid.Make().String()
+	if s.fileRepo != nil {
+		doc := newPDFDocument()
+		doc.Paragraph(agreement.Body, 11)
+		doc.Space(24)
+		doc.Heading("Signature", 12)
+		if len(input.Image) > 0 {
+			if err := doc.Image(input.Image, 180); err != nil {
+				return nil, domain.ErrInvalidSignatureImage
+			}
+			contentType := http.DetectContentType(input.Image)
+			ext := ".jpg"
+			if contentType == "image/png" {
+				ext = ".png"
+			}
+			filename := fmt.Sprintf("agreements/%s/%s-signature-%s%s", agreement.TenantID, agreement.ContractID, attempt, ext)
+			imageURL, err := s.fileRepo.Upload(ctx, input.Image, filename, contentType)
+			if err != nil {
+				return nil, err
+			}
+			signature.ImageURL = imageURL
+		}
+		if typedName != "" {
+			doc.Paragraph("Typed name: "+typedName, 11)
+		}
+		doc.Paragraph(fmt.Sprintf("Signed electronically on %s from IP %s", now.UTC().Format(time.RFC3339), input.IPAddress), 9)
+
+		filename := fmt.Sprintf("agreements/%s/%s-signed-%s.pdf", agreement.TenantID, agreement.ContractID, attempt)
+		url, err := s.fileRepo.Upload(ctx, doc.Bytes(), filename, "application/pdf")
+		if err != nil {
+			return nil, err
+		}
+		agreement.SignedDocumentURL = url
+	}
+
+	agreement.Signature = signature
+	if err := s.agreementRepo.MarkSigned(ctx, agreement); err != nil {
+		s.discardSignatureFiles(ctx, signature.ImageURL, agreement.SignedDocumentURL)
+		return nil, err
+	}
+	return agreement, nil
+}
+
+// discardSignatureFiles removes the files of a signature that wasn't kept
+func (s *AgreementService) discardSignatureFiles(ctx context.Context, urls ...string) {
+	for _, url := range urls {
+		if url == "" {
+			continue
+		}
+		if err := s.fileRepo.Delete(ctx, url); err != nil {
+			log.Printf("Warning: failed to delete unkept signature file %s: %v", url, err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgreementService_Sign(t *testing.T) {
+	ctx := context.Background()
+	pending := func() *domain.ContractAgreement {
+		return &domain.ContractAgreement{ID: "ag-1", TenantID: "gym", ContractID: "k1", MemberID: "m1",
+			Body: "Terms", Status: domain.AgreementStatusPending}
+	}
+	newService := func(t *testing.T, withFiles bool) (*AgreementService, *mocks.ContractAgreementRepository, *mocks.FileRepository) {
+		agreements, contracts := mocks.NewContractAgreementRepository(t), mocks.NewPTContractRepository(t)
+		contracts.On("GetByID", ctx, "k1").Return(&domain.PTContract{ID: "k1", MemberID: "m1"}, nil)
+		var files *mocks.FileRepository
+		var fileRepo domain.FileRepository
+		if withFiles {
+			files = mocks.NewFileRepository(t)
+			fileRepo = files
+		}
+		return NewAgreementService(agreements, contracts, nil, nil, nil, fileRepo, clock.NewFake(testNow)), agreements, files
+	}
+
+	t.Run("signs a pending agreement", func(t *testing.T) {
+		svc, agreements, files := newService(t, true)
+		agreements.On("GetByContractID", ctx, "k1").Return(pending(), nil)
+		files.On("Upload", ctx, mock.Anything, mock.MatchedBy(func(name string) bool {
+			return strings.HasPrefix(name, "agreements/gym/k1-signed-")
+		}), "application/pdf").Return("https://files/signed.pdf", nil)
+		agreements.On("MarkSigned", ctx, mock.MatchedBy(func(a *domain.ContractAgreement) bool {
+			return a.Signature.TypedName == "Rina" && a.Signature.SignedAt.Equal(testNow) && a.SignedDocumentURL == "https://files/signed.pdf"
+		})).Return(nil)
+
+		_, err := svc.Sign(ctx, "k1", "m1", SignatureInput{TypedName: " Rina ", IPAddress: "10.0.0.1"})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("a concurrent signature wins and this one's files are removed", func(t *testing.T) {
+		svc, agreements, files := newService(t, true)
+		agreements.On("GetByContractID", ctx, "k1").Return(pending(), nil)
+		files.On("Upload", ctx, mock.Anything, mock.Anything, "application/pdf").Return("https://files/signed-2.pdf", nil)
+		agreements.On("MarkSigned", ctx, mock.Anything).Return(domain.ErrAgreementAlreadySigned)
+		files.On("Delete", ctx, "https://files/signed-2.pdf").Return(nil)
+
+		_, err := svc.Sign(ctx, "k1", "m1", SignatureInput{TypedName: "Rina"})
+
+		assert.ErrorIs(t, err, domain.ErrAgreementAlreadySigned)
+	})
+
+	t.Run("a drawn signature needs the file store", func(t *testing.T) {
+		svc, agreements, _ := newService(t, false)
+		agreements.On("GetByContractID", ctx, "k1").Return(pending(), nil)
+
+		_, err := svc.Sign(ctx, "k1", "m1", SignatureInput{Image: []byte("\x89PNG")})
+
+		assert.ErrorIs(t, err, domain.ErrSignatureImageNotKept)
+		agreements.AssertNotCalled(t, "MarkSigned", mock.Anything, mock.Anything)
+	})
+}
@@ -3,20 +3,98 @@ package service
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
+	"github.com/mansoorceksport/metamorph/internal/clock"
 	"github.com/mansoorceksport/metamorph/internal/domain"
 )
 
+const (
+	analyticsHistoryTTL     = 7 * 24 * time.Hour
+	analyticsRefreshTimeout = 30 * time.Second
+)
+
 // AnalyticsService handles analytics and trend data
 type AnalyticsService struct {
 	repository domain.InBodyRepository
+	cache      domain.CacheRepository
+	clock      domain.Clock
+
+	refreshing sync.Map       // Cache keys being refreshed in the background
+	refreshes  sync.WaitGroup // Lets tests wait for background refreshes
 }
 
 // NewAnalyticsService creates a new analytics service
-func NewAnalyticsService(repository domain.InBodyRepository) *AnalyticsService {
+func NewAnalyticsService(repository domain.InBodyRepository, cache domain.CacheRepository, clk domain.Clock) *AnalyticsService {
 	return &AnalyticsService{
 		repository: repository,
+		cache:      cache,
+		clock:      clock.OrReal(clk),
+	}
+}
+
+// GetCachedHistory serves the history from cache with stale-while-revalidate: a cached
+// history is returned at once, and when the member's scans changed after it was computed
+// it is also refreshed in the background for the next call. Only a cache miss computes
+// the history in the request.
+func (s *AnalyticsService) GetCachedHistory(ctx context.Context, userID string, limit int) (*domain.AnalyticsHistory, *domain.HistoryFreshness, error) {
+	entry, err := s.cache.GetAnalyticsHistory(ctx, userID, limit)
+	if err != nil {
+		fmt.Printf("Warning: failed to read cached analytics history: %v\n", err)
+	}
+	if entry == nil || entry.History == nil {
+		entry, err = s.refreshHistory(ctx, userID, limit)
+		if err != nil {
+			return nil, nil, err
+		}
+		return entry.History, &domain.HistoryFreshness{ComputedAt: entry.ComputedAt}, nil
+	}
+
+	freshness := &domain.HistoryFreshness{Cached: true, ComputedAt: entry.ComputedAt}
+	changedAt, err := s.cache.GetScansChangedAt(ctx, userID)
+	if err != nil {
+		fmt.Printf("Warning: failed to check when scans changed: %v\n", err)
 	}
+	if entry.ComputedAt.Before(changedAt) {
+		freshness.Stale = true
+		s.refreshInBackground(ctx, userID, limit)
+	}
+	return entry.History, freshness, nil
+}
+
+// refreshHistory computes the history and caches it. ComputedAt is taken before the scans
+// are read, so a scan saved while computing leaves the entry stale rather than fresh.
+func (s *AnalyticsService) refreshHistory(ctx context.Context, userID string, limit int) (*domain.CachedAnalyticsHistory, error) {
+	computedAt := s.clock.Now()
+	history, err := s.GetHistory(ctx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	entry := &domain.CachedAnalyticsHistory{History: history, ComputedAt: computedAt}
+	if err := s.cache.SetAnalyticsHistory(ctx, userID, limit, entry, analyticsHistoryTTL); err != nil {
+		fmt.Printf("Warning: failed to cache analytics history: %v\n", err)
+	}
+	return entry, nil
+}
+
+// refreshInBackground refreshes a cached history unless a refresh of it is already running
+func (s *AnalyticsService) refreshInBackground(ctx context.Context, userID string, limit int) {
+	key := fmt.Sprintf("%s:%d", userID, limit)
+	if _, running := s.refreshing.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+	s.refreshes.Add(1)
+	go func() {
+		defer s.refreshes.Done()
+		defer s.refreshing.Delete(key)
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), analyticsRefreshTimeout)
+		defer cancel()
+		if _, err := s.refreshHistory(ctx, userID, limit); err != nil {
+			fmt.Printf("Warning: failed to refresh analytics history for user %s: %v\n", userID, err)
+		}
+	}()
 }
 
 // GetHistory retrieves analytics history with progress calculation
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyticsService_GetCachedHistory(t *testing.T) {
+	ctx := context.Background()
+	scans := []*domain.InBodyRecord{
+		{ID: "scan-1", TestDateTime: testNow.AddDate(0, -1, 0), Weight: 80},
+		{ID: "scan-2", TestDateTime: testNow, Weight: 78},
+	}
+	cached := &domain.CachedAnalyticsHistory{
+		History:    &domain.AnalyticsHistory{Progress: domain.ProgressSummary{TotalScans: 1}},
+		ComputedAt: testNow.Add(-time.Hour),
+	}
+	isFresh := func(entry *domain.CachedAnalyticsHistory) bool {
+		return entry.ComputedAt.Equal(testNow) && entry.History.Progress.TotalScans == 2
+	}
+
+	t.Run("computes and caches on a miss", func(t *testing.T) {
+		records, cache := mocks.NewInBodyRepository(t), mocks.NewCacheRepository(t)
+		svc := NewAnalyticsService(records, cache, clock.NewFake(testNow))
+		cache.On("GetAnalyticsHistory", ctx, "member-1", 10).Return(nil, nil)
+		records.On("GetTrendHistory", ctx, "member-1", 10).Return(scans, nil)
+		cache.On("SetAnalyticsHistory", ctx, "member-1", 10, mock.MatchedBy(isFresh), analyticsHistoryTTL).Return(nil)
+
+		history, freshness, err := svc.GetCachedHistory(ctx, "member-1", 10)
+
+		require.NoError(t, err)
+		assert.Equal(t, -2.0, history.Progress.WeightChange)
+		assert.Equal(t, &domain.HistoryFreshness{ComputedAt: testNow}, freshness)
+	})
+
+	t.Run("serves a fresh entry without reading scans", func(t *testing.T) {
+		records, cache := mocks.NewInBodyRepository(t), mocks.NewCacheRepository(t)
+		svc := NewAnalyticsService(records, cache, clock.NewFake(testNow))
+		cache.On("GetAnalyticsHistory", ctx, "member-1", 10).Return(cached, nil)
+		cache.On("GetScansChangedAt", ctx, "member-1").Return(testNow.Add(-2*time.Hour), nil)
+
+		history, freshness, err := svc.GetCachedHistory(ctx, "member-1", 10)
+
+		require.NoError(t, err)
+		assert.Same(t, cached.History, history)
+		assert.Equal(t, &domain.HistoryFreshness{Cached: true, ComputedAt: cached.ComputedAt}, freshness)
+	})
+
+	t.Run("serves a stale entry and refreshes it in the background", func(t *testing.T) {
+		records, cache := mocks.NewInBodyRepository(t), mocks.NewCacheRepository(t)
+		svc := NewAnalyticsService(records, cache, clock.NewFake(testNow))
+		cache.On("GetAnalyticsHistory", ctx, "member-1", 10).Return(cached, nil)
+		cache.On("GetScansChangedAt", ctx, "member-1").Return(testNow.Add(-time.Minute), nil)
+		records.On("GetTrendHistory", mock.Anything, "member-1", 10).Return(scans, nil).Once()
+		cache.On("SetAnalyticsHistory", mock.Anything, "member-1", 10, mock.MatchedBy(isFresh), analyticsHistoryTTL).Return(nil).Once()
+
+		history, freshness, err := svc.GetCachedHistory(ctx, "member-1", 10)
+		svc.refreshes.Wait()
+
+		require.NoError(t, err)
+		assert.Same(t, cached.History, history)
+		assert.True(t, freshness.Stale)
+	})
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// assessmentHistoryLimit caps how many assessments a member's history and trends cover
+const assessmentHistoryLimit = 100
+
+// AssessmentService records fitness tests and follows their results over time
+type AssessmentService struct {
+	repo      domain.AssessmentRepository
+	schedRepo domain.ScheduleRepository
+	clock     domain.Clock
+}
+
+func NewAssessmentService(repo domain.AssessmentRepository, schedRepo domain.ScheduleRepository, clk domain.Clock) *AssessmentService {
+	return &AssessmentService{
+		repo:      repo,
+		schedRepo: schedRepo,
+		clock:     clock.OrReal(clk),
+	}
+}
+
+// Record saves an assessment taken by coachID. Given a schedule, the results belong to that
+// session: recording again replaces them, and the session is tagged as an assessment.
+func (s *AssessmentService) Record(ctx context.Context, coachID string, a *domain.Assessment) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+	now := s.clock.Now().UTC()
+	a.CoachID = coachID
+	a.CreatedAt, a.UpdatedAt = now, now
+
+	if a.ScheduleID == "" {
+		if a.TakenAt.IsZero() {
+			a.TakenAt = now
+		}
+		return s.repo.Create(ctx, a)
+	}
+
+	schedule, err := s.schedRepo.GetByID(ctx, a.ScheduleID)
+	if err != nil {
+		return err
+	}
+	if schedule.DeletedAt != nil || schedule.TenantID != a.TenantID {
+		return domain.ErrScheduleNotFound
+	}
+	if schedule.CoachID != coachID || schedule.MemberID != a.MemberID {
+		return domain.ErrForbidden
+	}
+	if a.TakenAt.IsZero() {
+		a.TakenAt = schedule.StartTime
+	}
+
+	if !schedule.HasTag(domain.ScheduleTagAssessment) {
+		schedule.Tags = append(schedule.Tags, domain.ScheduleTagAssessment)
+		if err := s.schedRepo.Update(ctx, schedule); err != nil {
+			return err
+		}
+	}
+
+	existing, err := s.repo.GetBySchedule(ctx, a.TenantID, a.ScheduleID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return s.repo.Create(ctx, a)
+	}
+	if err != nil {
+		return err
+	}
+	a.ID, a.CreatedAt = existing.ID, existing.CreatedAt
+	return s.repo.Update(ctx, a)
+}
+
+// History returns the member's assessments, newest first, with a trend per metric
+func (s *AssessmentService) History(ctx context.Context, tenantID, memberID string) ([]*domain.Assessment, []domain.AssessmentTrend, error) {
+	assessments, err := s.repo.ListByMember(ctx, tenantID, memberID, assessmentHistoryLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return assessments, assessmentTrends(assessments), nil
+}
+
+// Latest returns the member's most recent assessment, or nil if they never took one
+func (s *AssessmentService) Latest(ctx context.Context, tenantID, memberID string) (*domain.Assessment, error) {
+	assessments, err := s.repo.ListByMember(ctx, tenantID, memberID, 1)
+	if err != nil || len(assessments) == 0 {
+		return nil, err
+	}
+	return assessments[0], nil
+}
+
+// assessmentTrends turns newest-first assessments into one trend per metric that was
+// tested at least once, in a fixed metric order
+func assessmentTrends(assessments []*domain.Assessment) []domain.AssessmentTrend {
+	metrics := []string{domain.AssessmentPushUps, domain.AssessmentPlank, domain.AssessmentSitAndReach, domain.AssessmentRestingHR}
+	trends := []domain.AssessmentTrend{}
+	for _, metric := range metrics {
+		var points []domain.AssessmentPoint
+		for _, a := range slices.Backward(assessments) {
+			if v, ok := a.Results()[metric]; ok {
+				points = append(points, domain.AssessmentPoint{Date: a.TakenAt, Value: v})
+			}
+		}
+		if len(points) == 0 {
+			continue
+		}
+		first, latest := points[0].Value, points[len(points)-1].Value
+		change := math.Round((latest-first)*10) / 10
+		improved := change > 0
+		if metric == domain.AssessmentRestingHR {
+			improved = change < 0
+		}
+		trends = append(trends, domain.AssessmentTrend{
+			Metric:   metric,
+			Points:   points,
+			First:    first,
+			Latest:   latest,
+			Change:   change,
+			Improved: improved,
+		})
+	}
+	return trends
+}
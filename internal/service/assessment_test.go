@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAssessmentService(t *testing.T) (*AssessmentService, *mocks.AssessmentRepository, *mocks.ScheduleRepository) {
+	repo := mocks.NewAssessmentRepository(t)
+	schedRepo := mocks.NewScheduleRepository(t)
+	return NewAssessmentService(repo, schedRepo, clock.NewFake(testNow)), repo, schedRepo
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestAssessmentService_Record(t *testing.T) {
+	ctx := context.Background()
+	sessionStart := testNow.Add(-2 * time.Hour)
+
+	t.Run("in a session tags it and takes its start time", func(t *testing.T) {
+		svc, repo, schedRepo := newTestAssessmentService(t)
+		schedRepo.On("GetByID", ctx, "sched-1").Return(&domain.Schedule{ID: "sched-1", TenantID: "tenant-1", CoachID: "coach-1", MemberID: "member-1", StartTime: sessionStart, Tags: []string{"trial"}}, nil)
+		schedRepo.On("Update", ctx, mock.MatchedBy(func(s *domain.Schedule) bool {
+			return s.HasTag(domain.ScheduleTagAssessment) && s.HasTag("trial")
+		})).Return(nil)
+		repo.On("GetBySchedule", ctx, "tenant-1", "sched-1").Return(nil, domain.ErrNotFound)
+		repo.On("Create", ctx, mock.AnythingOfType("*domain.Assessment")).Return(nil)
+
+		a := &domain.Assessment{TenantID: "tenant-1", MemberID: "member-1", ScheduleID: "sched-1", PushUpMax: intPtr(25)}
+		require.NoError(t, svc.Record(ctx, "coach-1", a))
+
+		assert.Equal(t, sessionStart, a.TakenAt)
+		assert.Equal(t, "coach-1", a.CoachID)
+	})
+
+	t.Run("recording a session again replaces its results", func(t *testing.T) {
+		svc, repo, schedRepo := newTestAssessmentService(t)
+		schedRepo.On("GetByID", ctx, "sched-1").Return(&domain.Schedule{ID: "sched-1", TenantID: "tenant-1", CoachID: "coach-1", MemberID: "member-1", StartTime: sessionStart, Tags: []string{domain.ScheduleTagAssessment}}, nil)
+		repo.On("GetBySchedule", ctx, "tenant-1", "sched-1").Return(&domain.Assessment{ID: "asm-1", CreatedAt: sessionStart}, nil)
+		repo.On("Update", ctx, mock.MatchedBy(func(a *domain.Assessment) bool {
+			return a.ID == "asm-1" && a.CreatedAt.Equal(sessionStart) && *a.RestingHR == 62
+		})).Return(nil)
+
+		require.NoError(t, svc.Record(ctx, "coach-1", &domain.Assessment{TenantID: "tenant-1", MemberID: "member-1", ScheduleID: "sched-1", RestingHR: intPtr(62)}))
+	})
+
+	t.Run("another member's session", func(t *testing.T) {
+		svc, _, schedRepo := newTestAssessmentService(t)
+		schedRepo.On("GetByID", ctx, "sched-1").Return(&domain.Schedule{ID: "sched-1", TenantID: "tenant-1", CoachID: "coach-1", MemberID: "member-2"}, nil)
+
+		err := svc.Record(ctx, "coach-1", &domain.Assessment{TenantID: "tenant-1", MemberID: "member-1", ScheduleID: "sched-1", PushUpMax: intPtr(25)})
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("no results", func(t *testing.T) {
+		svc, _, _ := newTestAssessmentService(t)
+
+		err := svc.Record(ctx, "coach-1", &domain.Assessment{TenantID: "tenant-1", MemberID: "member-1"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidAssessment)
+	})
+}
+
+func TestAssessmentService_History(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newTestAssessmentService(t)
+	reach := 4.5
+	repo.On("ListByMember", ctx, "tenant-1", "member-1", assessmentHistoryLimit).Return([]*domain.Assessment{
+		{TakenAt: testNow, PushUpMax: intPtr(30), RestingHR: intPtr(64), SitAndReachCM: &reach},
+		{TakenAt: testNow.AddDate(0, -1, 0), PushUpMax: intPtr(26)},
+		{TakenAt: testNow.AddDate(0, -2, 0), PushUpMax: intPtr(20), RestingHR: intPtr(72)},
+	}, nil)
+
+	_, trends, err := svc.History(ctx, "tenant-1", "member-1")
+
+	require.NoError(t, err)
+	require.Len(t, trends, 3, "plank was never tested")
+
+	pushUps := trends[0]
+	assert.Equal(t, domain.AssessmentPushUps, pushUps.Metric)
+	assert.Equal(t, []float64{20, 26, 30}, []float64{pushUps.Points[0].Value, pushUps.Points[1].Value, pushUps.Points[2].Value}, "oldest first")
+	assert.Equal(t, 10.0, pushUps.Change)
+	assert.True(t, pushUps.Improved)
+
+	restingHR := trends[2]
+	assert.Equal(t, domain.AssessmentRestingHR, restingHR.Metric)
+	assert.Equal(t, -8.0, restingHR.Change)
+	assert.True(t, restingHR.Improved, "a lower resting heart rate is better")
+}
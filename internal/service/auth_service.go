@@ -3,11 +3,13 @@ package service
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"time"
 
 	"firebase.google.com/go/v4/auth"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/mansoorceksport/metamorph/internal/clock"
 	"github.com/mansoorceksport/metamorph/internal/domain"
 )
 
@@ -23,6 +25,7 @@ type AuthService struct {
 	tenantRepo domain.TenantRepository
 	authClient FirebaseAuthClient
 	jwtSecret  string
+	clock      domain.Clock // Trial and subscription expiry are evaluated against it
 }
 
 // NewAuthService creates a new auth service
@@ -31,12 +34,14 @@ func NewAuthService(
 	tenantRepo domain.TenantRepository,
 	authClient FirebaseAuthClient,
 	jwtSecret string,
+	clk domain.Clock,
 ) *AuthService {
 	return &AuthService{
 		userRepo:   userRepo,
 		tenantRepo: tenantRepo,
 		authClient: authClient,
 		jwtSecret:  jwtSecret,
+		clock:      clock.OrReal(clk),
 	}
 }
 
@@ -128,8 +133,10 @@ func (s *AuthService) LoginOrRegister(ctx context.Context, req LoginOrRegisterRe
 			// TenantID is empty for generic members until they join a gym/tenant
 		}
 
-		// Create the user
-		if err := s.userRepo.Create(ctx, newUser); err != nil {
+		// Create the user. A deleted account with this UID or email still holds them.
+		if err := s.userRepo.Create(ctx, newUser); errors.Is(err, domain.ErrUserDeleted) {
+			return nil, err
+		} else if err != nil {
 			return nil, fmt.Errorf("failed to create user: %w", err)
 		}
 
@@ -201,6 +208,7 @@ func generateJoinCode() (string, error) {
 // GenerateMetamorphToken creates a JWT token with custom claims
 func (s *AuthService) GenerateMetamorphToken(user *domain.User) (string, error) {
 	// Create claims with user data
+	now := s.clock.Now()
 	claims := domain.MetamorphClaims{
 		UserID:       user.ID,
 		Roles:        user.Roles, // Multi-role support - includes all roles
@@ -208,9 +216,9 @@ func (s *AuthService) GenerateMetamorphToken(user *domain.User) (string, error)
 		HomeBranchID: user.HomeBranchID,
 		BranchAccess: user.BranchAccess,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(24 * time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
@@ -235,7 +243,7 @@ func (s *AuthService) GetAccessStatus(ctx context.Context, userID string) (*doma
 		return nil, fmt.Errorf("failed to fetch user: %w", err)
 	}
 
-	now := time.Now().UTC()
+	now := s.clock.Now().UTC()
 	needsUpdate := false
 
 	// Priority Check: Only initialize TrialEndDate if it's nil (respect manual overrides)
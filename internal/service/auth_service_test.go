@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthService_GetAccessStatus(t *testing.T) {
+	ctx := context.Background()
+	day := 24 * time.Hour
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	t.Run("ghost user starts a 14 day trial", func(t *testing.T) {
+		userRepo := mocks.NewUserRepository(t)
+		svc := NewAuthService(userRepo, mocks.NewTenantRepository(t), nil, "secret", clock.NewFake(testNow))
+		user := &domain.User{ID: "user-1"}
+		userRepo.On("GetByID", ctx, "user-1").Return(user, nil)
+		userRepo.On("Update", ctx, user).Return(nil).Once()
+
+		status, err := svc.GetAccessStatus(ctx, "user-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "trial", status.AccessType)
+		assert.Equal(t, 14, status.DaysRemaining)
+		assert.Equal(t, testNow.Add(14*day), *user.TrialEndDate)
+	})
+
+	t.Run("trial expires when the clock passes its end", func(t *testing.T) {
+		userRepo := mocks.NewUserRepository(t)
+		clk := clock.NewFake(testNow)
+		svc := NewAuthService(userRepo, mocks.NewTenantRepository(t), nil, "secret", clk)
+		userRepo.On("GetByID", ctx, "user-1").Return(&domain.User{
+			ID:           "user-1",
+			FirstLoginAt: ptr(testNow.Add(-13 * day)),
+			TrialEndDate: ptr(testNow.Add(day)),
+		}, nil)
+
+		status, err := svc.GetAccessStatus(ctx, "user-1")
+		require.NoError(t, err)
+		assert.True(t, status.IsPro)
+		assert.False(t, status.IsManualExtend)
+
+		clk.Advance(day + time.Minute)
+		status, err = svc.GetAccessStatus(ctx, "user-1")
+		require.NoError(t, err)
+		assert.False(t, status.IsPro)
+		assert.Equal(t, "none", status.AccessType)
+	})
+
+	t.Run("manually extended trial", func(t *testing.T) {
+		userRepo := mocks.NewUserRepository(t)
+		svc := NewAuthService(userRepo, mocks.NewTenantRepository(t), nil, "secret", clock.NewFake(testNow))
+		userRepo.On("GetByID", ctx, "user-1").Return(&domain.User{
+			ID:           "user-1",
+			FirstLoginAt: ptr(testNow.Add(-20 * day)),
+			TrialEndDate: ptr(testNow.Add(10 * day)),
+		}, nil)
+
+		status, err := svc.GetAccessStatus(ctx, "user-1")
+
+		require.NoError(t, err)
+		assert.True(t, status.IsManualExtend)
+		assert.Equal(t, 10, status.DaysRemaining)
+	})
+
+	t.Run("paid subscription wins over trial", func(t *testing.T) {
+		userRepo := mocks.NewUserRepository(t)
+		svc := NewAuthService(userRepo, mocks.NewTenantRepository(t), nil, "secret", clock.NewFake(testNow))
+		userRepo.On("GetByID", ctx, "user-1").Return(&domain.User{
+			ID:                  "user-1",
+			TrialEndDate:        ptr(testNow.Add(5 * day)),
+			SubscriptionEndDate: ptr(testNow.Add(30 * day)),
+		}, nil)
+
+		status, err := svc.GetAccessStatus(ctx, "user-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "paid", status.AccessType)
+		assert.Equal(t, 30, status.DaysRemaining)
+		userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthService_LoginOrRegister(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("refuses a deleted account", func(t *testing.T) {
+		userRepo := mocks.NewUserRepository(t)
+		signedIn := firebaseStub{uid: "fb-1", email: "budi@example.com"}
+		svc := NewAuthService(userRepo, mocks.NewTenantRepository(t), signedIn, "secret", clock.NewFake(testNow))
+		// Lookups skip deleted users, so only creating the account again finds it
+		userRepo.On("GetByFirebaseUID", ctx, "fb-1").Return(nil, domain.ErrNotFound)
+		userRepo.On("GetByEmail", ctx, "budi@example.com").Return(nil, domain.ErrNotFound)
+		userRepo.On("Create", ctx, mock.Anything).Return(domain.ErrUserDeleted).Once()
+
+		_, err := svc.LoginOrRegister(ctx, LoginOrRegisterRequest{FirebaseToken: "firebase-token"})
+
+		assert.Equal(t, domain.ErrUserDeleted, err)
+	})
+}
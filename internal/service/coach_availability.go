@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// checkAvailability rejects a booking outside the coach's working hours at the schedule's
+// branch, or at any of their branches for an online session. Coaches who never set their
+// hours can be booked at any time.
+func (s *PTService) checkAvailability(ctx context.Context, schedule *domain.Schedule) error {
+	availability, err := s.coachAvailability(ctx, schedule.CoachID)
+	if err != nil {
+		return err
+	}
+	if !availabilityCovers(availability, schedule) {
+		return domain.ErrOutsideAvailability
+	}
+	return nil
+}
+
+// coachAvailability returns the coach's weekly hours, nil when their bookings aren't restricted
+func (s *PTService) coachAvailability(ctx context.Context, coachID string) (*domain.CoachAvailability, error) {
+	if s.availability == nil {
+		return nil, nil
+	}
+	availability, err := s.availability.Get(ctx, coachID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	return availability, err
+}
+
+// availabilityCovers reports whether the session falls in the hours. Online sessions may
+// be in any window, whatever its branch.
+func availabilityCovers(availability *domain.CoachAvailability, schedule *domain.Schedule) bool {
+	if availability == nil {
+		return true
+	}
+	branchID := schedule.BranchID
+	if schedule.IsOnline() {
+		branchID = ""
+	}
+	return availability.Covers(branchID, schedule.StartTime, schedule.EndTime)
+}
+
+// GetCoachAvailability returns the coach's weekly hours, empty if none were set
+func (s *PTService) GetCoachAvailability(ctx context.Context, coachID string) (*domain.CoachAvailability, error) {
+	empty := &domain.CoachAvailability{CoachID: coachID, Windows: []domain.AvailabilityWindow{}}
+	if s.availability == nil {
+		return empty, nil
+	}
+	availability, err := s.availability.Get(ctx, coachID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return empty, nil
+	}
+	return availability, err
+}
+
+// SetCoachAvailability replaces the coach's weekly hours. Every window must be at one of
+// the branches the coach works at; an empty list removes the restriction.
+func (s *PTService) SetCoachAvailability(ctx context.Context, coach *domain.User, windows []domain.AvailabilityWindow) (*domain.CoachAvailability, error) {
+	if s.availability == nil {
+		return nil, errors.New("coach availability is not configured")
+	}
+	for _, w := range windows {
+		if _, _, err := w.Minutes(); err != nil {
+			return nil, err
+		}
+		if !coach.WorksAt(w.BranchID) {
+			return nil, domain.ErrBranchNotAllowed
+		}
+	}
+	if windows == nil {
+		windows = []domain.AvailabilityWindow{}
+	}
+
+	availability := &domain.CoachAvailability{
+		CoachID:  coach.ID,
+		TenantID: coach.TenantID,
+		Windows:  windows,
+	}
+	if err := s.availability.Upsert(ctx, availability); err != nil {
+		return nil, err
+	}
+	return availability, nil
+}
+
+// GetCoachUtilization reports, per branch, the coach's sessions between from and to and how
+// much of their working time there they fill. Available time is counted per calendar day in
+// from's time zone.
+func (s *PTService) GetCoachUtilization(ctx context.Context, coachID string, from, to time.Time) ([]*domain.BranchUtilization, error) {
+	schedules, err := s.schedRepo.GetByCoachAllStatuses(ctx, coachID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	availability, err := s.GetCoachAvailability(ctx, coachID)
+	if err != nil {
+		return nil, err
+	}
+
+	byBranch := make(map[string]*domain.BranchUtilization)
+	branch := func(id string) *domain.BranchUtilization {
+		u, ok := byBranch[id]
+		if !ok {
+			u = &domain.BranchUtilization{BranchID: id}
+			byBranch[id] = u
+		}
+		return u
+	}
+
+	for _, sched := range schedules {
+		if sched.DeletedAt != nil {
+			continue
+		}
+		u := branch(sched.BranchID)
+		if sched.Status == domain.ScheduleStatusCancelled {
+			u.Cancelled++
+			continue
+		}
+		u.Sessions++
+		u.BookedMinutes += int(sched.EndTime.Sub(sched.StartTime).Minutes())
+		switch sched.Status {
+		case domain.ScheduleStatusCompleted:
+			u.Completed++
+		case domain.ScheduleStatusNoShow:
+			u.NoShow++
+		}
+	}
+
+	// Walk the period day by day so partial weeks only count the days they include
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, w := range availability.Windows {
+			if w.Weekday != day.Weekday() {
+				continue
+			}
+			if start, end, err := w.Minutes(); err == nil {
+				branch(w.BranchID).AvailableMinutes += end - start
+			}
+		}
+	}
+
+	result := make([]*domain.BranchUtilization, 0, len(byBranch))
+	for _, u := range byBranch {
+		if u.AvailableMinutes > 0 {
+			u.Utilization = float64(u.BookedMinutes) / float64(u.AvailableMinutes)
+		}
+		result = append(result, u)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].BranchID < result[j].BranchID })
+	return result, nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Mornings at the north branch on Mondays, afternoons at the east branch on Mondays and Tuesdays
+var testCoachHours = &domain.CoachAvailability{
+	CoachID: "coach-1",
+	Windows: []domain.AvailabilityWindow{
+		{BranchID: "br-north", Weekday: time.Monday, Start: "07:00", End: "12:00"},
+		{BranchID: "br-east", Weekday: time.Monday, Start: "14:00", End: "18:00"},
+		{BranchID: "br-east", Weekday: time.Tuesday, Start: "14:00", End: "18:00"},
+	},
+}
+
+func TestPTService_CreateSchedule_Availability(t *testing.T) {
+	ctx := context.Background()
+	contract := &domain.PTContract{ID: "contract-1", MemberID: "member-1", BranchID: "br-east", Status: domain.PackageStatusActive, RemainingSessions: 5}
+
+	newService := func(t *testing.T) (*PTService, *ptServiceMocks) {
+		_, m := newTestPTService(t)
+		availability := mocks.NewCoachAvailabilityRepository(t)
+		availability.On("Get", anyCtx, "coach-1").Return(testCoachHours, nil)
+		m.contractRepo.On("GetByID", anyCtx, "contract-1").Return(contract, nil)
+		m.schedRepo.On("CountByContractAndStatus", anyCtx, "contract-1", []string{domain.ScheduleStatusScheduled, domain.ScheduleStatusPendingConfirmation}).Return(int64(0), nil)
+		svc := NewPTService(m.pkgRepo, m.contractRepo, m.schedRepo, mocks.NewWorkoutSessionRepository(t), m.setLogRepo, m.creditRepo, nil, nil, m.locker, availability, nil, nil, clock.NewFake(testNow))
+		return svc, m
+	}
+	schedule := func(start time.Time) *domain.Schedule {
+		return &domain.Schedule{ContractID: "contract-1", CoachID: "coach-1", MemberID: "member-1", BranchID: "br-east", StartTime: start, EndTime: start.Add(time.Hour)}
+	}
+
+	t.Run("inside the branch's hours", func(t *testing.T) {
+		svc, m := newService(t)
+		sched := schedule(testNow.Add(5 * time.Hour)) // Monday 15:00
+		m.schedRepo.On("Create", anyCtx, sched).Return(nil)
+
+		require.NoError(t, svc.CreateSchedule(ctx, sched))
+	})
+
+	t.Run("hours at another branch don't count", func(t *testing.T) {
+		svc, _ := newService(t)
+
+		err := svc.CreateSchedule(ctx, schedule(testNow)) // Monday 10:00, only north is open
+
+		assert.ErrorIs(t, err, domain.ErrOutsideAvailability)
+	})
+
+	t.Run("online sessions fit hours at any branch", func(t *testing.T) {
+		svc, m := newService(t)
+		sched := schedule(testNow) // Monday 10:00, only north is open
+		sched.Modality = domain.SessionModalityOnline
+		m.schedRepo.On("Create", anyCtx, sched).Return(nil)
+
+		require.NoError(t, svc.CreateSchedule(ctx, sched))
+	})
+}
+
+func TestPTService_GetCoachUtilization(t *testing.T) {
+	_, m := newTestPTService(t)
+	availability := mocks.NewCoachAvailabilityRepository(t)
+	svc := NewPTService(m.pkgRepo, m.contractRepo, m.schedRepo, mocks.NewWorkoutSessionRepository(t), m.setLogRepo, m.creditRepo, nil, nil, m.locker, availability, nil, nil, clock.NewFake(testNow))
+
+	// Monday and Tuesday of the test week
+	from := time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 2)
+	availability.On("Get", anyCtx, "coach-1").Return(testCoachHours, nil)
+	m.schedRepo.On("GetByCoachAllStatuses", anyCtx, "coach-1", from, to).Return([]*domain.Schedule{
+		{BranchID: "br-north", Status: domain.ScheduleStatusCompleted, StartTime: testNow, EndTime: testNow.Add(time.Hour)},
+		{BranchID: "br-north", Status: domain.ScheduleStatusNoShow, StartTime: testNow.Add(time.Hour), EndTime: testNow.Add(90 * time.Minute)},
+		{BranchID: "br-east", Status: domain.ScheduleStatusCancelled, StartTime: testNow, EndTime: testNow.Add(time.Hour)},
+		{BranchID: "br-east", Status: domain.ScheduleStatusScheduled, StartTime: testNow, EndTime: testNow.Add(2 * time.Hour)},
+	}, nil)
+
+	branches, err := svc.GetCoachUtilization(context.Background(), "coach-1", from, to)
+
+	require.NoError(t, err)
+	require.Len(t, branches, 2)
+
+	east, north := branches[0], branches[1]
+	assert.Equal(t, "br-east", east.BranchID)
+	assert.Equal(t, 1, east.Sessions)
+	assert.Equal(t, 1, east.Cancelled)
+	assert.Equal(t, 480, east.AvailableMinutes, "four hours on both days")
+	assert.InDelta(t, 0.25, east.Utilization, 0.001)
+
+	assert.Equal(t, "br-north", north.BranchID)
+	assert.Equal(t, 2, north.Sessions)
+	assert.Equal(t, 1, north.Completed)
+	assert.Equal(t, 1, north.NoShow)
+	assert.Equal(t, 90, north.BookedMinutes)
+	assert.Equal(t, 300, north.AvailableMinutes)
+	assert.InDelta(t, 0.3, north.Utilization, 0.001)
+}
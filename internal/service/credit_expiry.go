@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// creditExpiryWarning is how long before a contract's sessions expire the member and coach
+// are told, leaving time to book what's left
+const creditExpiryWarning = 14 * 24 * time.Hour
+
+// CreditExpiryService expires the unused sessions of contracts whose package has a validity,
+// warning the member and coach beforehand
+type CreditExpiryService struct {
+	contractRepo domain.PTContractRepository
+	ptService    *PTService
+	notifier     *NotificationService
+	clock        domain.Clock
+}
+
+func NewCreditExpiryService(contractRepo domain.PTContractRepository, ptService *PTService, notifier *NotificationService, clk domain.Clock) *CreditExpiryService {
+	return &CreditExpiryService{
+		contractRepo: contractRepo,
+		ptService:    ptService,
+		notifier:     notifier,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// ExpireDue expires the contracts past their expiry and warns about the ones expiring
+// within two weeks. Each contract is warned once.
+func (s *CreditExpiryService) ExpireDue(ctx context.Context) (warned, expired int, err error) {
+	now := s.clock.Now()
+	contracts, err := s.contractRepo.ListExpiringBefore(ctx, now.Add(creditExpiryWarning))
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, contract := range contracts {
+		if contract.ExpiresAt == nil {
+			continue
+		}
+		if !contract.ExpiresAt.After(now) {
+			sessions, err := s.ptService.ExpireContract(ctx, contract)
+			if err != nil {
+				return warned, expired, fmt.Errorf("failed to expire contract %s: %w", contract.ID, err)
+			}
+			expired++
+			if sessions > 0 {
+				s.notify(ctx, contract, domain.NotificationCreditsExpired, "Sessions expired",
+					fmt.Sprintf("%d unused sessions of the PT package expired on %s", sessions, contract.ExpiresAt.UTC().Format("2 Jan 2006")))
+			}
+			continue
+		}
+
+		remaining := contract.RemainingSessions
+		if remaining <= 0 || contract.ExpiryWarnedAt != nil {
+			continue
+		}
+		marked, err := s.contractRepo.MarkExpiryWarned(ctx, contract.ID, now)
+		if err != nil {
+			return warned, expired, err
+		}
+		if !marked {
+			continue
+		}
+		warned++
+		s.notify(ctx, contract, domain.NotificationCreditsExpiring, "Sessions expiring soon",
+			fmt.Sprintf("%d sessions of the PT package expire on %s. Book them before then.", remaining, contract.ExpiresAt.UTC().Format("2 Jan 2006")))
+	}
+	return warned, expired, nil
+}
+
+// notify tells the member and the coach, logging failures: the contract is already marked,
+// so failing the run wouldn't send it again
+func (s *CreditExpiryService) notify(ctx context.Context, contract *domain.PTContract, kind, title, body string) {
+	for _, userID := range []string{contract.MemberID, contract.CoachID} {
+		if userID == "" {
+			continue
+		}
+		err := s.notifier.Notify(ctx, &domain.Notification{
+			UserID:   userID,
+			TenantID: contract.TenantID,
+			Type:     kind,
+			Title:    title,
+			Body:     body,
+			Data:     map[string]string{"contract_id": contract.ID, "member_id": contract.MemberID},
+			Link:     &domain.DeepLink{Screen: domain.ScreenContract, ContractID: contract.ID},
+		})
+		if err != nil {
+			log.Printf("Warning: %s notification for contract %s to %s not sent: %v", kind, contract.ID, userID, err)
+		}
+	}
+}
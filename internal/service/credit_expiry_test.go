@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreditExpiryService_ExpireDue(t *testing.T) {
+	ctx := context.Background()
+	at := func(days int) *time.Time {
+		ts := testNow.AddDate(0, 0, days)
+		return &ts
+	}
+
+	newService := func(t *testing.T) (*CreditExpiryService, *ptServiceMocks, *mocks.NotificationSender) {
+		ptService, m := newTestPTService(t)
+		prefs := mocks.NewNotificationPreferencesRepository(t)
+		prefs.On("GetUser", ctx, mock.Anything).Return(&domain.NotificationPreferences{}, nil).Maybe()
+		push := mocks.NewNotificationSender(t)
+		push.On("Channel").Return(domain.ChannelPush)
+		notifications := NewNotificationService(prefs, nil, clock.NewFake(testNow), push)
+		return NewCreditExpiryService(m.contractRepo, ptService, notifications, clock.NewFake(testNow)), m, push
+	}
+
+	t.Run("expires the remaining sessions and tells both sides", func(t *testing.T) {
+		svc, m, push := newService(t)
+		contract := &domain.PTContract{ID: "contract-1", TenantID: "gym", MemberID: "member-1", CoachID: "coach-1",
+			RemainingSessions: 3, Status: domain.PackageStatusActive, ExpiresAt: at(-1)}
+		m.contractRepo.On("ListExpiringBefore", ctx, testNow.Add(creditExpiryWarning)).Return([]*domain.PTContract{contract}, nil)
+		m.creditRepo.On("GetLatest", anyCtx, "contract-1").Return(&domain.CreditTransaction{BalanceAfter: 3}, nil)
+		m.expectLock("contract:contract-1")
+		m.creditRepo.On("Append", anyCtx, mock.MatchedBy(func(txn *domain.CreditTransaction) bool {
+			return txn.Type == domain.CreditTypeExpired && txn.Amount == -3 && txn.IdempotencyKey == "expired:contract-1"
+		})).Run(func(args mock.Arguments) {
+			txn := args.Get(1).(*domain.CreditTransaction)
+			txn.BalanceAfter, txn.Sequence = 0, 5
+		}).Return(nil)
+		m.contractRepo.On("SyncBalance", anyCtx, "contract-1", 0, int64(5)).Return(nil)
+		m.contractRepo.On("UpdateStatus", anyCtx, "contract-1", domain.PackageStatusExpired).Return(nil)
+		push.On("Send", ctx, mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.Type == domain.NotificationCreditsExpired && n.Link.ContractID == "contract-1"
+		})).Return(nil).Twice()
+
+		warned, expired, err := svc.ExpireDue(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{0, 1}, []int{warned, expired})
+		assert.Equal(t, domain.PackageStatusExpired, contract.Status)
+	})
+
+	t.Run("warns once about sessions expiring soon", func(t *testing.T) {
+		svc, m, push := newService(t)
+		m.contractRepo.On("ListExpiringBefore", ctx, testNow.Add(creditExpiryWarning)).Return([]*domain.PTContract{
+			{ID: "contract-1", MemberID: "member-1", RemainingSessions: 4, ExpiresAt: at(10)},
+			{ID: "contract-2", MemberID: "member-2", RemainingSessions: 4, ExpiresAt: at(10), ExpiryWarnedAt: at(-1)},
+			{ID: "contract-3", MemberID: "member-3", ExpiresAt: at(10)}, // Nothing left to lose
+			{ID: "contract-4", MemberID: "member-4", RemainingSessions: 2, ExpiresAt: at(12)},
+		}, nil)
+		m.contractRepo.On("MarkExpiryWarned", ctx, "contract-1", testNow).Return(true, nil)
+		m.contractRepo.On("MarkExpiryWarned", ctx, "contract-4", testNow).Return(false, nil) // Another run got there first
+		push.On("Send", ctx, mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.Type == domain.NotificationCreditsExpiring && n.UserID == "member-1" &&
+				n.Body == "4 sessions of the PT package expire on 26 Jun 2025. Book them before then."
+		})).Return(nil).Once()
+
+		warned, expired, err := svc.ExpireDue(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 0}, []int{warned, expired})
+	})
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// CustomFieldService manages the fields a tenant defines on its members and contracts, and
+// checks the values set through the member and contract endpoints against them
+type CustomFieldService struct {
+	tenantRepo   domain.TenantRepository
+	contractRepo domain.PTContractRepository
+}
+
+func NewCustomFieldService(tenantRepo domain.TenantRepository, contractRepo domain.PTContractRepository) *CustomFieldService {
+	return &CustomFieldService{tenantRepo: tenantRepo, contractRepo: contractRepo}
+}
+
+// Schema returns the tenant's custom fields
+func (s *CustomFieldService) Schema(ctx context.Context, tenantID string) (domain.CustomFieldSchema, error) {
+	tenant, err := s.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch custom fields: %w", err)
+	}
+	return tenant.CustomFields, nil
+}
+
+// SetSchema replaces the tenant's custom fields. Values of removed fields stay on their
+// members and contracts but are no longer shown or exported, and are dropped when next
+// edited; a field added back under the same key finds them again.
+func (s *CustomFieldService) SetSchema(ctx context.Context, tenantID string, schema domain.CustomFieldSchema) (domain.CustomFieldSchema, error) {
+	if err := schema.Validate(); err != nil {
+		return nil, err
+	}
+	tenant, err := s.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	tenant.CustomFields = schema
+	if err := s.tenantRepo.Update(ctx, tenant); err != nil {
+		return nil, err
+	}
+	return tenant.CustomFields, nil
+}
+
+// Apply checks the changes against the tenant's fields for the entity and returns the
+// values after them (see domain.CustomFieldSchema.Apply). New members and contracts pass
+// nil current values, so required fields must be among the changes.
+func (s *CustomFieldService) Apply(ctx context.Context, tenantID, entity string, current domain.CustomFieldValues, changes map[string]interface{}) (domain.CustomFieldValues, error) {
+	schema, err := s.Schema(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return schema.Apply(entity, current, changes)
+}
+
+// SetContractFields changes the custom field values of one of the tenant's contracts
+func (s *CustomFieldService) SetContractFields(ctx context.Context, tenantID, contractID string, changes map[string]interface{}) (*domain.PTContract, error) {
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	if contract.TenantID != tenantID {
+		return nil, domain.ErrContractNotFound
+	}
+	values, err := s.Apply(ctx, tenantID, domain.CustomFieldEntityContract, contract.CustomFields, changes)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.contractRepo.SetCustomFields(ctx, contractID, values); err != nil {
+		return nil, err
+	}
+	contract.CustomFields = values
+	return contract, nil
+}
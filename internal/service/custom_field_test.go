@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomFieldService_SetSchema(t *testing.T) {
+	ctx := context.Background()
+	schema := domain.CustomFieldSchema{{Key: "locker", Label: "Locker", Entity: domain.CustomFieldEntityMember, Type: domain.CustomFieldText}}
+
+	t.Run("saves a valid schema", func(t *testing.T) {
+		tenants := mocks.NewTenantRepository(t)
+		svc := NewCustomFieldService(tenants, nil)
+		tenants.On("GetByID", ctx, "gym").Return(&domain.Tenant{ID: "gym", Name: "Gym"}, nil)
+		tenants.On("Update", ctx, mock.MatchedBy(func(tenant *domain.Tenant) bool {
+			return tenant.Name == "Gym" && len(tenant.CustomFields) == 1
+		})).Return(nil)
+
+		saved, err := svc.SetSchema(ctx, "gym", schema)
+
+		require.NoError(t, err)
+		assert.Equal(t, schema, saved)
+	})
+
+	t.Run("rejects an invalid schema", func(t *testing.T) {
+		svc := NewCustomFieldService(mocks.NewTenantRepository(t), nil)
+
+		_, err := svc.SetSchema(ctx, "gym", append(schema, schema[0]))
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCustomFieldSchema)
+	})
+}
+
+func TestCustomFieldService_SetContractFields(t *testing.T) {
+	ctx := context.Background()
+	tenant := &domain.Tenant{ID: "gym", CustomFields: domain.CustomFieldSchema{
+		{Key: "insurance_id", Label: "Insurance ID", Entity: domain.CustomFieldEntityContract, Type: domain.CustomFieldText},
+	}}
+
+	t.Run("merges the changes into the contract's values", func(t *testing.T) {
+		tenants, contracts := mocks.NewTenantRepository(t), mocks.NewPTContractRepository(t)
+		svc := NewCustomFieldService(tenants, contracts)
+		contracts.On("GetByID", ctx, "contract-1").Return(&domain.PTContract{ID: "contract-1", TenantID: "gym",
+			CustomFields: domain.CustomFieldValues{"retired": "x"}}, nil)
+		tenants.On("GetByID", ctx, "gym").Return(tenant, nil)
+		contracts.On("SetCustomFields", ctx, "contract-1", domain.CustomFieldValues{"insurance_id": "AX-1"}).Return(nil)
+
+		contract, err := svc.SetContractFields(ctx, "gym", "contract-1", map[string]interface{}{"insurance_id": "AX-1"})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.CustomFieldValues{"insurance_id": "AX-1"}, contract.CustomFields)
+	})
+
+	t.Run("hides other tenants' contracts", func(t *testing.T) {
+		contracts := mocks.NewPTContractRepository(t)
+		svc := NewCustomFieldService(mocks.NewTenantRepository(t), contracts)
+		contracts.On("GetByID", ctx, "contract-1").Return(&domain.PTContract{ID: "contract-1", TenantID: "other"}, nil)
+
+		_, err := svc.SetContractFields(ctx, "gym", "contract-1", map[string]interface{}{"insurance_id": "AX-1"})
+
+		assert.ErrorIs(t, err, domain.ErrContractNotFound)
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		tenants, contracts := mocks.NewTenantRepository(t), mocks.NewPTContractRepository(t)
+		svc := NewCustomFieldService(tenants, contracts)
+		contracts.On("GetByID", ctx, "contract-1").Return(&domain.PTContract{ID: "contract-1", TenantID: "gym"}, nil)
+		tenants.On("GetByID", ctx, "gym").Return(tenant, nil)
+
+		_, err := svc.SetContractFields(ctx, "gym", "contract-1", map[string]interface{}{"locker": "12"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCustomFields)
+	})
+}
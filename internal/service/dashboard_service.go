@@ -6,6 +6,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/mansoorceksport/metamorph/internal/clock"
 	"github.com/mansoorceksport/metamorph/internal/domain"
 	"golang.org/x/sync/errgroup"
 )
@@ -18,6 +19,7 @@ type DashboardService struct {
 	sessionRepo  domain.WorkoutSessionRepository
 	userRepo     domain.UserRepository
 	pbRepo       domain.PersonalBestRepository
+	clock        domain.Clock
 }
 
 // NewDashboardService creates a new DashboardService instance
@@ -28,6 +30,7 @@ func NewDashboardService(
 	sessionRepo domain.WorkoutSessionRepository,
 	userRepo domain.UserRepository,
 	pbRepo domain.PersonalBestRepository,
+	clk domain.Clock,
 ) *DashboardService {
 	return &DashboardService{
 		contractRepo: contractRepo,
@@ -36,6 +39,7 @@ func NewDashboardService(
 		sessionRepo:  sessionRepo,
 		userRepo:     userRepo,
 		pbRepo:       pbRepo,
+		clock:        clock.OrReal(clk),
 	}
 }
 
@@ -73,6 +77,7 @@ func (s *DashboardService) GetCoachSummary(ctx context.Context, coachID string)
 		StrengthWins:       []domain.MemberAnalytics{},
 		PackageHealth:      []domain.MemberAnalytics{},
 		Consistent:         []domain.MemberAnalytics{},
+		UpcomingRenewals:   []domain.UpcomingRenewal{},
 	}
 
 	// Use errgroup for concurrent fetching
@@ -138,6 +143,16 @@ func (s *DashboardService) GetCoachSummary(ctx context.Context, coachID string)
 		return nil
 	})
 
+	// Upcoming Renewals (Auto-Renewal Opt-Ins)
+	g.Go(func() error {
+		renewals, err := s.calculateUpcomingRenewals(gCtx, coachID, users)
+		if err != nil {
+			return err
+		}
+		summary.UpcomingRenewals = renewals
+		return nil
+	})
+
 	if err := g.Wait(); err != nil {
 		return nil, err
 	}
@@ -228,7 +243,7 @@ func (s *DashboardService) calculateChurnRisk(ctx context.Context, coachID strin
 		return nil, err
 	}
 
-	now := time.Now()
+	now := s.clock.Now()
 	sevenDaysAgo := now.AddDate(0, 0, -7)
 
 	// Track attendance per member
@@ -294,7 +309,7 @@ func (s *DashboardService) calculateChurnRisk(ctx context.Context, coachID strin
 // calculateStrengthWins detects personal records from session logs
 func (s *DashboardService) calculateStrengthWins(ctx context.Context, coachID string, users map[string]*domain.User) ([]domain.MemberAnalytics, error) {
 	// Get sessions from last 7 days
-	now := time.Now()
+	now := s.clock.Now()
 	sevenDaysAgo := now.AddDate(0, 0, -7)
 	thirtyDaysAgo := now.AddDate(0, 0, -30)
 
@@ -382,8 +397,7 @@ func (s *DashboardService) calculateStrengthWins(ctx context.Context, coachID st
 
 // calculatePackageHealth finds contracts with low remaining sessions
 func (s *DashboardService) calculatePackageHealth(ctx context.Context, coachID string, users map[string]*domain.User) ([]domain.MemberAnalytics, error) {
-	// Get contracts with < 3 remaining sessions
-	contracts, err := s.contractRepo.GetLowSessionsByCoach(ctx, coachID, 3)
+	contracts, err := s.contractRepo.GetLowSessionsByCoach(ctx, coachID, domain.LowSessionsThreshold)
 	if err != nil {
 		return nil, err
 	}
@@ -423,6 +437,60 @@ func (s *DashboardService) calculatePackageHealth(ctx context.Context, coachID s
 	return result, nil
 }
 
+// Contracts set to auto-renew count as upcoming once they are down to this many sessions or
+// this close to expiring
+const (
+	renewalSoonSessions = 3
+	renewalSoonExpiry   = 14 * 24 * time.Hour
+)
+
+// calculateUpcomingRenewals lists renewals waiting for payment, then contracts set to renew
+// that are nearly used up or about to expire, fewest sessions first
+func (s *DashboardService) calculateUpcomingRenewals(ctx context.Context, coachID string, users map[string]*domain.User) ([]domain.UpcomingRenewal, error) {
+	contracts, err := s.contractRepo.ListRenewalsByCoach(ctx, coachID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	result := make([]domain.UpcomingRenewal, 0, len(contracts))
+	for _, contract := range contracts {
+		status := domain.RenewalStatusUpcoming
+		if contract.Status == domain.PackageStatusPendingPayment {
+			status = domain.RenewalStatusPendingPayment
+		} else if contract.RemainingSessions > renewalSoonSessions &&
+			(contract.ExpiresAt == nil || contract.ExpiresAt.Sub(now) > renewalSoonExpiry) {
+			continue
+		}
+
+		// Members waiting on a renewal may have no active contract to have been loaded with
+		name := contract.MemberID
+		if user, ok := users[contract.MemberID]; ok {
+			name = user.Name
+		} else if user, err := s.userRepo.GetByID(ctx, contract.MemberID); err == nil {
+			name = user.Name
+		}
+		result = append(result, domain.UpcomingRenewal{
+			ContractID:        contract.ID,
+			MemberID:          contract.MemberID,
+			Name:              name,
+			Status:            status,
+			RemainingSessions: contract.RemainingSessions,
+			ExpiresAt:         contract.ExpiresAt,
+			RenewalOf:         contract.RenewalOf,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		pi, pj := result[i].Status == domain.RenewalStatusPendingPayment, result[j].Status == domain.RenewalStatusPendingPayment
+		if pi != pj {
+			return pi
+		}
+		return result[i].RemainingSessions < result[j].RemainingSessions
+	})
+	return result, nil
+}
+
 // calculateConsistent finds members with 100% attendance over last 30 days
 func (s *DashboardService) calculateConsistent(ctx context.Context, coachID string, memberIDs []string, users map[string]*domain.User) ([]domain.MemberAnalytics, error) {
 	// Get attendance for last 30 days
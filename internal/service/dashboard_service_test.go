@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testNow is the frozen "now" for time-dependent service tests
+var testNow = time.Date(2025, 6, 16, 10, 0, 0, 0, time.UTC)
+
+type dashboardServiceMocks struct {
+	contractRepo *mocks.PTContractRepository
+	schedRepo    *mocks.ScheduleRepository
+	inbodyRepo   *mocks.InBodyRepository
+	sessionRepo  *mocks.WorkoutSessionRepository
+	userRepo     *mocks.UserRepository
+}
+
+func newTestDashboardService(t *testing.T) (*DashboardService, *dashboardServiceMocks) {
+	m := &dashboardServiceMocks{
+		contractRepo: mocks.NewPTContractRepository(t),
+		schedRepo:    mocks.NewScheduleRepository(t),
+		inbodyRepo:   mocks.NewInBodyRepository(t),
+		sessionRepo:  mocks.NewWorkoutSessionRepository(t),
+		userRepo:     mocks.NewUserRepository(t),
+	}
+	svc := NewDashboardService(m.contractRepo, m.schedRepo, m.inbodyRepo, m.sessionRepo, m.userRepo, mocks.NewPersonalBestRepository(t), clock.NewFake(testNow))
+	return svc, m
+}
+
+func TestDashboardService_GetCoachSummary(t *testing.T) {
+	// The summary sections are computed concurrently on a derived context
+	anyCtx := mock.Anything
+	daysAgo := func(d int) time.Time { return testNow.AddDate(0, 0, -d) }
+
+	svc, m := newTestDashboardService(t)
+	m.contractRepo.On("GetActiveByCoach", anyCtx, "coach-1").Return([]*domain.PTContract{
+		{ID: "c-1", MemberID: "alice"},
+		{ID: "c-2", MemberID: "bob"},
+		{ID: "c-3", MemberID: "alice"}, // Second contract for the same member
+	}, nil)
+	m.userRepo.On("GetByID", anyCtx, "alice").Return(&domain.User{ID: "alice", Name: "Alice"}, nil)
+	m.userRepo.On("GetByID", anyCtx, "bob").Return(nil, domain.ErrNotFound)
+
+	// Alice: gained muscle and lost fat. Bob: only one scan
+	m.inbodyRepo.On("GetRecentScansByMembers", anyCtx, []string{"alice", "bob"}, 2).Return(map[string][]*domain.InBodyRecord{
+		"alice": {{SMM: 31.5, PBF: 20}, {SMM: 30, PBF: 22}},
+		"bob":   {{SMM: 35, PBF: 18}},
+	}, nil)
+
+	// Alice attended every session; Bob trained weekly but skipped the last two
+	m.schedRepo.On("GetAttendanceByCoach", anyCtx, "coach-1", 30).Return([]*domain.Schedule{
+		{MemberID: "alice", Status: domain.ScheduleStatusCompleted, StartTime: daysAgo(2)},
+		{MemberID: "alice", Status: domain.ScheduleStatusCompleted, StartTime: daysAgo(9)},
+		{MemberID: "bob", Status: domain.ScheduleStatusCompleted, StartTime: daysAgo(12)},
+		{MemberID: "bob", Status: domain.ScheduleStatusCompleted, StartTime: daysAgo(19)},
+		{MemberID: "bob", Status: domain.ScheduleStatusCompleted, StartTime: daysAgo(26)},
+		{MemberID: "bob", Status: domain.ScheduleStatusNoShow, StartTime: daysAgo(3)},
+		{MemberID: "bob", Status: domain.ScheduleStatusNoShow, StartTime: daysAgo(5)},
+	}, nil)
+
+	// Alice squatted 100kg this week against a 90kg best
+	m.sessionRepo.On("GetSessionsByCoachAndDateRange", anyCtx, "coach-1", mock.Anything, mock.Anything).Return(func(_ context.Context, _ string, from, _ time.Time) []*domain.WorkoutSession {
+		if from.Before(daysAgo(20)) {
+			return []*domain.WorkoutSession{{MemberID: "alice", PlannedExercises: []*domain.PlannedExercise{
+				{ExerciseID: "squat", Name: "Squat", Sets: []*domain.SetLog{{Weight: 90, Completed: true}}},
+			}}}
+		}
+		return []*domain.WorkoutSession{{MemberID: "alice", PlannedExercises: []*domain.PlannedExercise{
+			{ExerciseID: "squat", Name: "Squat", Sets: []*domain.SetLog{{Weight: 100, Completed: true}}},
+		}}}
+	}, nil)
+
+	m.contractRepo.On("GetLowSessionsByCoach", anyCtx, "coach-1", 3).Return([]*domain.PTContract{
+		{MemberID: "bob", RemainingSessions: 1},
+		{MemberID: "alice", RemainingSessions: 2},
+	}, nil)
+
+	// Alice renews soon; Carol's renewal waits for payment; Bob's contract has plenty left
+	m.contractRepo.On("ListRenewalsByCoach", anyCtx, "coach-1").Return([]*domain.PTContract{
+		{ID: "c-1", MemberID: "alice", Status: domain.PackageStatusActive, RemainingSessions: 2},
+		{ID: "c-2", MemberID: "bob", Status: domain.PackageStatusActive, RemainingSessions: 8},
+		{ID: "c-4", MemberID: "carol", Status: domain.PackageStatusPendingPayment, RenewalOf: "c-0"},
+	}, nil)
+	m.userRepo.On("GetByID", anyCtx, "carol").Return(&domain.User{ID: "carol", Name: "Carol"}, nil)
+
+	summary, err := svc.GetCoachSummary(context.Background(), "coach-1")
+	require.NoError(t, err)
+
+	require.Len(t, summary.RisingStars, 1)
+	assert.Equal(t, "Alice", summary.RisingStars[0].Name)
+	assert.InDelta(t, 3.5, summary.RisingStars[0].Value, 0.001)
+
+	require.Len(t, summary.ChurnRisk, 1)
+	assert.Equal(t, "bob", summary.ChurnRisk[0].Name, "falls back to the member ID when the user can't be loaded")
+
+	require.Len(t, summary.StrengthWins, 1)
+	assert.Equal(t, "100.0kg Squat (+10.0kg PR)", summary.StrengthWins[0].Label)
+
+	require.Len(t, summary.PackageHealth, 2)
+	assert.Equal(t, "Last session!", summary.PackageHealth[0].Label)
+	assert.Equal(t, "2 sessions left", summary.PackageHealth[1].Label)
+
+	require.Len(t, summary.Consistent, 1)
+	assert.Equal(t, "alice", summary.Consistent[0].MemberID)
+
+	require.Len(t, summary.InterventionNeeded, 1)
+	assert.Equal(t, "2 Skipped Sessions", summary.InterventionNeeded[0].Label)
+
+	require.Len(t, summary.UpcomingRenewals, 2)
+	assert.Equal(t, domain.UpcomingRenewal{ContractID: "c-4", MemberID: "carol", Name: "Carol",
+		Status: domain.RenewalStatusPendingPayment, RenewalOf: "c-0"}, summary.UpcomingRenewals[0])
+	assert.Equal(t, "Alice", summary.UpcomingRenewals[1].Name)
+	assert.Equal(t, domain.RenewalStatusUpcoming, summary.UpcomingRenewals[1].Status)
+}
+
+func TestDashboardService_GetCoachSummary_NoMembers(t *testing.T) {
+	anyCtx := mock.Anything
+	svc, m := newTestDashboardService(t)
+	m.contractRepo.On("GetActiveByCoach", anyCtx, "coach-1").Return([]*domain.PTContract{}, nil)
+	m.schedRepo.On("GetAttendanceByCoach", anyCtx, "coach-1", 30).Return([]*domain.Schedule{}, nil)
+	m.sessionRepo.On("GetSessionsByCoachAndDateRange", anyCtx, "coach-1", mock.Anything, mock.Anything).Return([]*domain.WorkoutSession{}, nil)
+	m.contractRepo.On("GetLowSessionsByCoach", anyCtx, "coach-1", 3).Return([]*domain.PTContract{}, nil)
+	m.contractRepo.On("ListRenewalsByCoach", anyCtx, "coach-1").Return([]*domain.PTContract{}, nil)
+
+	summary, err := svc.GetCoachSummary(context.Background(), "coach-1")
+
+	require.NoError(t, err)
+	assert.Empty(t, summary.RisingStars)
+	assert.Empty(t, summary.InterventionNeeded)
+	assert.NotNil(t, summary.UpcomingRenewals)
+}
+
+func TestDashboardService_GetCoachSummary_PropagatesErrors(t *testing.T) {
+	svc, m := newTestDashboardService(t)
+	m.contractRepo.On("GetActiveByCoach", mock.Anything, "coach-1").Return(nil, errors.New("mongo unavailable"))
+
+	_, err := svc.GetCoachSummary(context.Background(), "coach-1")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// DeletedRecordService soft-deletes and restores users, branches and tenants, and purges
+// those soft-deleted for longer than the retention period.
+type DeletedRecordService struct {
+	userRepo   domain.UserRepository
+	branchRepo domain.BranchRepository
+	tenantRepo domain.TenantRepository
+	data       domain.TenantDataRepository
+	clock      domain.Clock
+}
+
+func NewDeletedRecordService(
+	userRepo domain.UserRepository,
+	branchRepo domain.BranchRepository,
+	tenantRepo domain.TenantRepository,
+	data domain.TenantDataRepository,
+	clk domain.Clock,
+) *DeletedRecordService {
+	return &DeletedRecordService{
+		userRepo:   userRepo,
+		branchRepo: branchRepo,
+		tenantRepo: tenantRepo,
+		data:       data,
+		clock:      clock.OrReal(clk),
+	}
+}
+
+// DeleteTenant soft-deletes a tenant and signs everyone out of it. Its data is kept until
+// the tenant is purged.
+func (s *DeletedRecordService) DeleteTenant(ctx context.Context, tenantID string) error {
+	if err := s.tenantRepo.Delete(ctx, tenantID, s.clock.Now()); err != nil {
+		return err
+	}
+	if err := s.data.RevokeSessions(ctx, tenantID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return nil
+}
+
+// RestoreTenant brings back a soft-deleted tenant; its members sign in again as before
+func (s *DeletedRecordService) RestoreTenant(ctx context.Context, tenantID string) error {
+	return s.tenantRepo.Restore(ctx, tenantID, s.clock.Now())
+}
+
+// DeleteUser soft-deletes a user
+func (s *DeletedRecordService) DeleteUser(ctx context.Context, id string) error {
+	return s.userRepo.Delete(ctx, id, s.clock.Now())
+}
+
+// RestoreUser brings back a soft-deleted user of tenantID ("" for any)
+func (s *DeletedRecordService) RestoreUser(ctx context.Context, id, tenantID string) error {
+	return s.userRepo.Restore(ctx, id, tenantID, s.clock.Now())
+}
+
+// DeleteBranch soft-deletes a branch
+func (s *DeletedRecordService) DeleteBranch(ctx context.Context, id string) error {
+	return s.branchRepo.Delete(ctx, id, s.clock.Now())
+}
+
+// RestoreBranch brings back a soft-deleted branch of tenantID ("" for any)
+func (s *DeletedRecordService) RestoreBranch(ctx context.Context, id, tenantID string) error {
+	return s.branchRepo.Restore(ctx, id, tenantID, s.clock.Now())
+}
+
+// ListDeletedTenants returns the tenants that can still be restored
+func (s *DeletedRecordService) ListDeletedTenants(ctx context.Context) ([]*domain.Tenant, error) {
+	return s.tenantRepo.ListDeleted(ctx, s.clock.Now())
+}
+
+// PurgeDeleted permanently removes what was soft-deleted before the time given: tenants
+// with all of their data, then users and branches. It returns how many records went.
+func (s *DeletedRecordService) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	tenants, err := s.tenantRepo.ListDeleted(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+	var errs []error
+	for _, tenant := range tenants {
+		n, err := s.data.Purge(ctx, tenant.ID)
+		purged += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", tenant.ID, err))
+		}
+	}
+
+	users, err := s.userRepo.PurgeDeleted(ctx, before)
+	purged += users
+	if err != nil {
+		errs = append(errs, err)
+	}
+	branches, err := s.branchRepo.PurgeDeleted(ctx, before)
+	purged += branches
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return purged, errors.Join(errs...)
+}
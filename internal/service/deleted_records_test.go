@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeletedRecordService(t *testing.T) {
+	ctx := context.Background()
+	cutoff := testNow.AddDate(0, 0, -30)
+
+	newService := func(t *testing.T) (*DeletedRecordService, *mocks.UserRepository, *mocks.BranchRepository, *mocks.TenantRepository, *mocks.TenantDataRepository) {
+		users, branches := mocks.NewUserRepository(t), mocks.NewBranchRepository(t)
+		tenants, data := mocks.NewTenantRepository(t), mocks.NewTenantDataRepository(t)
+		return NewDeletedRecordService(users, branches, tenants, data, clock.NewFake(testNow)), users, branches, tenants, data
+	}
+
+	t.Run("deleting a tenant signs everyone out", func(t *testing.T) {
+		svc, _, _, tenants, data := newService(t)
+		tenants.On("Delete", ctx, "t1", testNow).Return(nil)
+		data.On("RevokeSessions", ctx, "t1").Return(nil)
+
+		require.NoError(t, svc.DeleteTenant(ctx, "t1"))
+	})
+
+	t.Run("users and branches are deleted and restored now", func(t *testing.T) {
+		svc, users, branches, _, _ := newService(t)
+		users.On("Delete", ctx, "u1", testNow).Return(nil)
+		users.On("Restore", ctx, "u1", "gym", testNow).Return(nil)
+		branches.On("Delete", ctx, "b1", testNow).Return(nil)
+		branches.On("Restore", ctx, "b1", "", testNow).Return(nil)
+
+		require.NoError(t, svc.DeleteUser(ctx, "u1"))
+		require.NoError(t, svc.RestoreUser(ctx, "u1", "gym"))
+		require.NoError(t, svc.DeleteBranch(ctx, "b1"))
+		require.NoError(t, svc.RestoreBranch(ctx, "b1", ""))
+	})
+
+	t.Run("deleted tenants are listed until now", func(t *testing.T) {
+		svc, _, _, tenants, _ := newService(t)
+		tenants.On("ListDeleted", ctx, testNow).Return([]*domain.Tenant{{ID: "t1"}}, nil)
+
+		list, err := svc.ListDeletedTenants(ctx)
+		require.NoError(t, err)
+		assert.Len(t, list, 1)
+	})
+
+	t.Run("purging removes tenants with their data, then users and branches", func(t *testing.T) {
+		svc, users, branches, tenants, data := newService(t)
+		tenants.On("ListDeleted", ctx, cutoff).Return([]*domain.Tenant{{ID: "t1"}, {ID: "t2"}}, nil)
+		data.On("Purge", ctx, "t1").Return(int64(40), nil)
+		data.On("Purge", ctx, "t2").Return(int64(3), errors.New("purge failed"))
+		users.On("PurgeDeleted", ctx, cutoff).Return(int64(2), nil)
+		branches.On("PurgeDeleted", ctx, cutoff).Return(int64(1), nil)
+
+		purged, err := svc.PurgeDeleted(ctx, cutoff)
+		assert.ErrorContains(t, err, "tenant t2")
+		assert.Equal(t, int64(46), purged)
+	})
+}
@@ -0,0 +1,482 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// Demo tenant shape: enough to look like a busy boutique gym without slowing the seed down
+const (
+	demoCoaches         = 4
+	demoMembers         = 24
+	demoHistoryMonths   = 6
+	demoSessionsPerWeek = 2
+	demoScanEveryDays   = 28
+	demoEmailDomain     = "demo.metamorph.app"
+)
+
+var demoFirstNames = []string{
+	"Aisha", "Budi", "Citra", "Dimas", "Eka", "Fajar", "Gita", "Hendra", "Indah", "Joko",
+	"Kartika", "Lukas", "Maya", "Nadia", "Oscar", "Putri", "Rizky", "Sari", "Taufik", "Vina",
+	"Wulan", "Yoga", "Zahra", "Adi", "Bella", "Dewi", "Galih", "Laras",
+}
+
+var demoLastNames = []string{
+	"Santoso", "Wijaya", "Pratama", "Halim", "Kusuma", "Saputra", "Lestari", "Gunawan",
+	"Hartono", "Nugroho", "Permata", "Siregar", "Tanjung", "Utomo",
+}
+
+var demoFocusAreas = []string{
+	domain.FocusAreaLegDay, domain.FocusAreaUpperBody, domain.FocusAreaBackDay,
+	domain.FocusAreaChestDay, domain.FocusAreaFullBody, domain.FocusAreaCore,
+}
+
+var demoSessionGoals = map[string]string{
+	domain.FocusAreaLegDay:    "Leg Day - Strength",
+	domain.FocusAreaUpperBody: "Upper Body - Hypertrophy",
+	domain.FocusAreaBackDay:   "Back & Posture",
+	domain.FocusAreaChestDay:  "Chest & Triceps",
+	domain.FocusAreaFullBody:  "Full Body Circuit",
+	domain.FocusAreaCore:      "Core Stability",
+}
+
+// DemoService generates and removes believable demo data for sales demos
+type DemoService struct {
+	demoRepo     domain.DemoDataRepository
+	tenantRepo   domain.TenantRepository
+	branchRepo   domain.BranchRepository
+	exerciseRepo domain.ExerciseRepository
+	clock        domain.Clock
+}
+
+func NewDemoService(
+	demoRepo domain.DemoDataRepository,
+	tenantRepo domain.TenantRepository,
+	branchRepo domain.BranchRepository,
+	exerciseRepo domain.ExerciseRepository,
+	clk domain.Clock,
+) *DemoService {
+	return &DemoService{
+		demoRepo:     demoRepo,
+		tenantRepo:   tenantRepo,
+		branchRepo:   branchRepo,
+		exerciseRepo: exerciseRepo,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// SeedDemo populates a tenant with coaches, members, six months of sessions, scans and invoices.
+// Everything is tagged as demo data; a tenant can hold only one demo dataset at a time.
+func (s *DemoService) SeedDemo(ctx context.Context, tenantID string) (*domain.DemoSummary, error) {
+	tenant, err := s.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.demoRepo.HasDemoData(ctx, tenant.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing demo data: %w", err)
+	}
+	if exists {
+		return nil, domain.ErrDemoDataExists
+	}
+
+	branches, err := s.branchRepo.GetByTenantID(ctx, tenant.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load branches: %w", err)
+	}
+	exercises, err := s.exerciseRepo.List(ctx, map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load exercises: %w", err)
+	}
+
+	g := &demoGenerator{
+		tenant:    tenant,
+		exercises: exercises,
+		rnd:       rand.New(rand.NewSource(s.clock.Now().UnixNano())),
+		now:       s.clock.Now(),
+		dataset:   &domain.DemoDataset{},
+	}
+	g.generate(branches)
+
+	if err := s.demoRepo.Save(ctx, tenant.ID, g.dataset); err != nil {
+		return nil, err
+	}
+	return g.summary(), nil
+}
+
+// DeleteDemo removes all demo data for a tenant, leaving real records untouched
+func (s *DemoService) DeleteDemo(ctx context.Context, tenantID string) (*domain.DemoSummary, error) {
+	if _, err := s.tenantRepo.GetByID(ctx, tenantID); err != nil {
+		return nil, err
+	}
+	deleted, err := s.demoRepo.DeleteAll(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.DemoSummary{Deleted: deleted}, nil
+}
+
+// demoGenerator builds a DemoDataset in memory; IDs are assigned up front so records can link
+type demoGenerator struct {
+	tenant    *domain.Tenant
+	exercises []*domain.Exercise
+	rnd       *rand.Rand
+	now       time.Time
+	dataset   *domain.DemoDataset
+	branchID  string
+	packages  []*domain.PTPackage
+	nameIndex int
+}
+
+func (g *demoGenerator) generate(branches []*domain.Branch) {
+	start := g.now.AddDate(0, -demoHistoryMonths, 0)
+
+	if len(branches) > 0 {
+		g.branchID = branches[0].ID
+	} else {
+		branch := &domain.Branch{
+			ID:        generateULID(),
+			TenantID:  g.tenant.ID,
+			Name:      g.tenant.Name + " Demo Studio",
+			JoinCode:  "DEMO-" + strings.ToUpper(generateULID()[18:]),
+			CreatedAt: start,
+			UpdatedAt: start,
+		}
+		g.dataset.Branches = append(g.dataset.Branches, branch)
+		g.branchID = branch.ID
+	}
+
+	for _, size := range []int{10, 20} {
+		pkg := &domain.PTPackage{
+			ID:            generateULID(),
+			TenantID:      g.tenant.ID,
+			BranchID:      g.branchID,
+			Name:          fmt.Sprintf("Demo %d Session Pack", size),
+			TotalSessions: size,
+			Price:         domain.NewMoney(int64(size)*250000, domain.DefaultCurrency),
+			Active:        true,
+			CreatedAt:     start,
+			UpdatedAt:     start,
+		}
+		g.packages = append(g.packages, pkg)
+		g.dataset.Packages = append(g.dataset.Packages, pkg)
+	}
+
+	coaches := make([]*domain.User, 0, demoCoaches)
+	for i := 0; i < demoCoaches; i++ {
+		coach := g.user(domain.RoleCoach, start)
+		coach.HomeBranchID = g.branchID
+		coaches = append(coaches, coach)
+	}
+
+	for i := 0; i < demoMembers; i++ {
+		member := g.user(domain.RoleMember, start.AddDate(0, 0, g.rnd.Intn(21)))
+		member.BranchAccess = []string{g.branchID}
+		g.memberHistory(member, coaches[i%len(coaches)])
+	}
+}
+
+func (g *demoGenerator) user(role string, createdAt time.Time) *domain.User {
+	first := demoFirstNames[g.nameIndex%len(demoFirstNames)]
+	last := demoLastNames[(g.nameIndex*7+3)%len(demoLastNames)]
+	g.nameIndex++
+	name := first + " " + last
+
+	user := &domain.User{
+		ID:        generateULID(),
+		Email:     fmt.Sprintf("%s.%s.%d@%s", strings.ToLower(first), strings.ToLower(last), g.nameIndex, demoEmailDomain),
+		Name:      name,
+		Roles:     []string{role},
+		TenantID:  g.tenant.ID,
+		AvatarURL: "https://api.dicebear.com/7.x/avataaars/svg?seed=" + url.QueryEscape(name),
+		Demo:      true,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	g.dataset.Users = append(g.dataset.Users, user)
+	return user
+}
+
+// memberHistory simulates a member training with their coach since they joined:
+// back-to-back contracts (each paid by an invoice), sessions with logged sets, PBs and scans
+func (g *demoGenerator) memberHistory(member, coach *domain.User) {
+	var (
+		contract *domain.PTContract
+		sequence int64
+		bests    = make(map[string]*domain.PersonalBest)
+		weights  = make(map[string]float64)
+		session  int
+	)
+
+	buy := func(at time.Time) {
+		pkg := g.packages[g.rnd.Intn(len(g.packages))]
+		contract = &domain.PTContract{
+			ID:                generateULID(),
+			TenantID:          g.tenant.ID,
+			BranchID:          g.branchID,
+			PackageID:         pkg.ID,
+			MemberID:          member.ID,
+			CoachID:           coach.ID,
+			TotalSessions:     pkg.TotalSessions,
+			RemainingSessions: pkg.TotalSessions,
+			Price:             pkg.Price,
+			Status:            domain.PackageStatusActive,
+			CreatedAt:         at,
+			UpdatedAt:         at,
+		}
+		g.dataset.Contracts = append(g.dataset.Contracts, contract)
+		sequence = 1
+		g.credit(contract, sequence, domain.CreditTypePurchased, pkg.TotalSessions, "", at)
+		g.dataset.Invoices = append(g.dataset.Invoices, &domain.Invoice{
+			ID:            generateULID(),
+			UserID:        member.ID,
+			PackageID:     pkg.ID,
+			Amount:        pkg.Price,
+			Status:        domain.InvoiceStatusPaid,
+			PaymentMethod: []string{"BCA", "Mandiri", "BNI"}[g.rnd.Intn(3)],
+			ExpiryDate:    at.Add(24 * time.Hour),
+			CreatedAt:     at,
+			UpdatedAt:     at,
+		})
+	}
+	buy(member.CreatedAt)
+
+	// Past sessions, then a couple of upcoming ones so calendars aren't empty
+	upcoming := 0
+	for week := member.CreatedAt; week.Before(g.now.AddDate(0, 0, 14)); week = week.AddDate(0, 0, 7) {
+		for i := 0; i < demoSessionsPerWeek; i++ {
+			startTime := time.Date(week.Year(), week.Month(), week.Day()+i*3, 7+g.rnd.Intn(12), 0, 0, 0, time.Local)
+			future := startTime.After(g.now)
+			if future && (upcoming >= contract.RemainingSessions || upcoming >= 2) {
+				continue
+			}
+			if !future && contract.RemainingSessions == 0 {
+				buy(startTime.AddDate(0, 0, -1))
+			}
+
+			status := domain.ScheduleStatusCompleted
+			switch r := g.rnd.Float64(); {
+			case future:
+				status = domain.ScheduleStatusScheduled
+				upcoming++
+			case r < 0.05:
+				status = domain.ScheduleStatusNoShow
+			case r < 0.1:
+				status = domain.ScheduleStatusCancelled
+			}
+
+			focus := demoFocusAreas[g.rnd.Intn(len(demoFocusAreas))]
+			schedule := &domain.Schedule{
+				ID:          generateULID(),
+				TenantID:    g.tenant.ID,
+				BranchID:    g.branchID,
+				ContractID:  contract.ID,
+				CoachID:     coach.ID,
+				MemberID:    member.ID,
+				StartTime:   startTime,
+				EndTime:     startTime.Add(time.Hour),
+				Status:      status,
+				SessionGoal: demoSessionGoals[focus],
+				FocusArea:   focus,
+				CreatedAt:   startTime.AddDate(0, 0, -3),
+				UpdatedAt:   startTime.Add(time.Hour),
+			}
+			g.dataset.Schedules = append(g.dataset.Schedules, schedule)
+
+			if status != domain.ScheduleStatusCompleted {
+				continue
+			}
+			contract.RemainingSessions--
+			contract.UpdatedAt = schedule.EndTime
+			if contract.RemainingSessions == 0 {
+				contract.Status = domain.PackageStatusDepleted
+			}
+			sequence++
+			g.credit(contract, sequence, domain.CreditTypeConsumed, -1, schedule.ID, schedule.EndTime)
+			g.workout(schedule, session, weights, bests)
+			session++
+		}
+	}
+
+	for _, pb := range bests {
+		g.dataset.PersonalBests = append(g.dataset.PersonalBests, pb)
+	}
+	g.scans(member)
+}
+
+func (g *demoGenerator) credit(contract *domain.PTContract, sequence int64, creditType string, amount int, scheduleID string, at time.Time) {
+	balance := contract.TotalSessions
+	if creditType == domain.CreditTypeConsumed {
+		balance = contract.RemainingSessions
+	}
+	contract.LedgerSequence = sequence
+	g.dataset.Credits = append(g.dataset.Credits, &domain.CreditTransaction{
+		ID:             generateULID(),
+		TenantID:       contract.TenantID,
+		ContractID:     contract.ID,
+		MemberID:       contract.MemberID,
+		Sequence:       sequence,
+		Type:           creditType,
+		Amount:         amount,
+		BalanceAfter:   balance,
+		ScheduleID:     scheduleID,
+		IdempotencyKey: fmt.Sprintf("%s:%s", creditType, contract.ID+scheduleID),
+		CreatedAt:      at,
+	})
+}
+
+// workout logs 3-4 exercises for a completed session; loads creep up ~1% per session
+func (g *demoGenerator) workout(schedule *domain.Schedule, session int, weights map[string]float64, bests map[string]*domain.PersonalBest) {
+	g.dataset.WorkoutSessions = append(g.dataset.WorkoutSessions, &domain.WorkoutSession{
+		ID:         generateULID(),
+		TenantID:   schedule.TenantID,
+		BranchID:   schedule.BranchID,
+		ScheduleID: schedule.ID,
+		CoachID:    schedule.CoachID,
+		MemberID:   schedule.MemberID,
+		CreatedAt:  schedule.StartTime,
+		UpdatedAt:  schedule.EndTime,
+	})
+	if len(g.exercises) == 0 {
+		return
+	}
+
+	volume := &domain.DailyVolume{
+		ID:         generateULID(),
+		TenantID:   schedule.TenantID,
+		MemberID:   schedule.MemberID,
+		ScheduleID: schedule.ID,
+		FocusArea:  schedule.FocusArea,
+		Date:       time.Date(schedule.StartTime.Year(), schedule.StartTime.Month(), schedule.StartTime.Day(), 0, 0, 0, 0, time.UTC),
+		CreatedAt:  schedule.EndTime,
+	}
+
+	count := 3 + g.rnd.Intn(2)
+	for order := 0; order < count; order++ {
+		ex := g.exercises[g.rnd.Intn(len(g.exercises))]
+		if _, ok := weights[ex.ID]; !ok {
+			weights[ex.ID] = float64(10 + 5*g.rnd.Intn(10))
+		}
+		weight := math.Round(weights[ex.ID]*(1+0.01*float64(session))/2.5) * 2.5
+
+		planned := &domain.PlannedExercise{
+			ID:          generateULID(),
+			ScheduleID:  schedule.ID,
+			ExerciseID:  ex.ID,
+			Name:        ex.Name,
+			TargetSets:  3,
+			TargetReps:  10,
+			RestSeconds: 90,
+			Order:       order,
+		}
+		g.dataset.PlannedExercises = append(g.dataset.PlannedExercises, planned)
+
+		for set := 1; set <= 3; set++ {
+			reps := 8 + g.rnd.Intn(5)
+			g.dataset.SetLogs = append(g.dataset.SetLogs, &domain.SetLogDocument{
+				ID:                generateULID(),
+				PlannedExerciseID: planned.ID,
+				ScheduleID:        schedule.ID,
+				MemberID:          schedule.MemberID,
+				ExerciseID:        ex.ID,
+				SetIndex:          set,
+				Weight:            weight,
+				Reps:              reps,
+				Completed:         true,
+				CreatedAt:         schedule.StartTime,
+				UpdatedAt:         schedule.StartTime,
+			})
+			volume.TotalVolume += weight * float64(reps)
+			volume.TotalSets++
+			volume.TotalReps += reps
+			volume.TotalWeight += weight
+
+			if pb, ok := bests[ex.ID]; !ok || weight > pb.Weight {
+				bests[ex.ID] = &domain.PersonalBest{
+					ID:         generateULID(),
+					MemberID:   schedule.MemberID,
+					ExerciseID: ex.ID,
+					Weight:     weight,
+					Reps:       reps,
+					AchievedAt: schedule.EndTime,
+					ScheduleID: schedule.ID,
+					CreatedAt:  schedule.EndTime,
+					UpdatedAt:  schedule.EndTime,
+				}
+			}
+		}
+		volume.ExerciseCount++
+	}
+	g.dataset.DailyVolumes = append(g.dataset.DailyVolumes, volume)
+}
+
+// scans adds a monthly InBody scan; members either cut fat or build muscle, with some noise
+func (g *demoGenerator) scans(member *domain.User) {
+	height := 1.55 + g.rnd.Float64()*0.35
+	weight := 55 + g.rnd.Float64()*40
+	pbf := 18 + g.rnd.Float64()*17
+	cutting := pbf > 25
+
+	for at := member.CreatedAt; at.Before(g.now); at = at.AddDate(0, 0, demoScanEveryDays) {
+		noise := (g.rnd.Float64() - 0.5) * 0.6
+		if cutting {
+			weight -= 0.8 + noise
+			pbf -= 0.7 + noise/2
+		} else {
+			weight += 0.4 + noise
+			pbf -= 0.3 + noise/3
+		}
+
+		fatMass := round1(weight * pbf / 100)
+		fatFree := round1(weight - fatMass)
+		record := &domain.InBodyRecord{
+			ID:                       generateULID(),
+			UserID:                   member.ID,
+			TestDateTime:             at.Add(8 * time.Hour),
+			Weight:                   round1(weight),
+			SMM:                      round1(fatFree * 0.56),
+			BodyFatMass:              fatMass,
+			BMI:                      round1(weight / (height * height)),
+			PBF:                      round1(pbf),
+			BMR:                      int(370 + 21.6*fatFree),
+			VisceralFatLevel:         int(math.Max(1, math.Round(pbf/3))),
+			WaistHipRatio:            math.Round((0.8+pbf/200)*100) / 100,
+			InBodyScore:              math.Round(90 - pbf/2 + fatFree/10),
+			FatFreeMass:              fatFree,
+			RecommendedCalorieIntake: int(math.Round((370+21.6*fatFree)*1.4/10) * 10),
+		}
+		record.Metadata.ProcessedAt = record.TestDateTime
+		g.dataset.Scans = append(g.dataset.Scans, record)
+	}
+}
+
+func (g *demoGenerator) summary() *domain.DemoSummary {
+	summary := &domain.DemoSummary{
+		Contracts: len(g.dataset.Contracts),
+		Schedules: len(g.dataset.Schedules),
+		SetLogs:   len(g.dataset.SetLogs),
+		Scans:     len(g.dataset.Scans),
+		Invoices:  len(g.dataset.Invoices),
+	}
+	for _, u := range g.dataset.Users {
+		if u.Roles[0] == domain.RoleCoach {
+			summary.Coaches++
+		} else {
+			summary.Members++
+		}
+	}
+	return summary
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -3,20 +3,15 @@ package service
 import (
 	"bytes"
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+	"strings"
 	"text/template"
-	"time"
 
 	"github.com/mansoorceksport/metamorph/internal/domain"
 )
 
 const (
-	openRouterAPIURL = "https://openrouter.ai/api/v1/chat/completions"
-
 	// Default values if no tenant context is found
 	defaultGymName = "House of Metamorfit (HOM)"
 	defaultTone    = "Encouraging, empathetic"
@@ -24,7 +19,7 @@ const (
 	defaultPersona = "Supportive personal trainer"
 
 	// System Prompt Template
-	systemPromptTmplStr = `You are an expert at extracting data from InBody 270 body composition scans AND a {{.Persona}} from '{{.GymName}}'. Your tone should be {{.Tone}}. Your advice should be {{.Style}}. Extract metrics accurately and provide coaching advice. Return only valid JSON.`
+	systemPromptTmplStr = `You are an expert at extracting data from InBody 270 body composition scans AND a {{.Persona}} from '{{.GymName}}'. Your tone should be {{.Tone}}. Your advice should be {{.Style}}. Extract metrics accurately and provide coaching advice.{{if .Language}} Write all analysis text in {{.Language}}; keep the JSON keys and numbers as specified.{{end}} Return only valid JSON.`
 
 	// User Prompt: Analysis Section Template
 	analysisPromptTmplStr = `
@@ -53,22 +48,81 @@ You are a {{.Persona}} at {{.GymName}}. Provide:
    - **For Asymmetries**: Recommend unilateral exercises
    - **For Visceral Fat**: Suggest cardio (Zone 2/HIIT)
    - **For Muscle**: Progressive overload
+{{- if .Instructions}}
+
+**GYM INSTRUCTIONS** (follow these unless they conflict with the tasks above or the JSON format):
+{{.Instructions}}
+{{- end}}
+{{- if .BannedTopics}}
+
+**NEVER MENTION:** {{.BannedTopics}}
+{{- end}}
 `
 )
 
 // PromptContext holds data for the templates
 type PromptContext struct {
-	GymName string
-	Tone    string
-	Style   string
-	Persona string
+	GymName      string
+	Tone         string
+	Style        string
+	Persona      string
+	Instructions string
+	Language     string
+	BannedTopics string // Joined list
+}
+
+// PromptPreview is the prompt the digitizer would send for a tenant, minus the image
+type PromptPreview struct {
+	SystemPrompt string `json:"system_prompt"`
+	UserPrompt   string `json:"user_prompt"`
+}
+
+// promptText makes tenant-supplied text safe to put in a prompt. Template data is never
+// parsed, but the text still ends up next to the JSON skeleton the model has to fill in,
+// so braces are turned into parentheses to keep it from opening or closing JSON, and
+// stray control characters are dropped.
+var promptText = strings.NewReplacer("{", "(", "}", ")", "\r", "", "\t", " ", "\x00", "")
+
+// newPromptContext fills the templates' data from the tenant's AI settings, falling back
+// to the defaults for anything unset. tenant may be nil.
+func newPromptContext(tenant *domain.Tenant) PromptContext {
+	pc := PromptContext{
+		GymName: defaultGymName,
+		Tone:    defaultTone,
+		Style:   defaultStyle,
+		Persona: defaultPersona,
+	}
+	if tenant == nil {
+		return pc
+	}
+
+	clean := func(s string) string { return strings.TrimSpace(promptText.Replace(s)) }
+	ai := tenant.AISettings
+	pc.GymName = clean(tenant.Name)
+	if ai.Tone != "" {
+		pc.Tone = clean(ai.Tone)
+	}
+	if ai.Style != "" {
+		pc.Style = clean(ai.Style)
+	}
+	if ai.Persona != "" {
+		pc.Persona = clean(ai.Persona)
+	}
+	pc.Instructions = clean(ai.Instructions)
+	pc.Language = clean(ai.Language)
+	topics := make([]string, 0, len(ai.BannedTopics))
+	for _, t := range ai.BannedTopics {
+		if t = clean(t); t != "" {
+			topics = append(topics, t)
+		}
+	}
+	pc.BannedTopics = strings.Join(topics, "; ")
+	return pc
 }
 
 // OpenRouterDigitizer implements domain.DigitizerService using OpenRouter API
 type OpenRouterDigitizer struct {
-	apiKey       string
-	model        string
-	httpClient   *http.Client
+	client       *openRouterClient
 	userRepo     domain.UserRepository
 	tenantRepo   domain.TenantRepository
 	systemTmpl   *template.Template
@@ -86,9 +140,7 @@ func NewOpenRouterDigitizer(
 	anaTmpl, _ := template.New("analysis").Parse(analysisPromptTmplStr)
 
 	return &OpenRouterDigitizer{
-		apiKey:       apiKey,
-		model:        model,
-		httpClient:   &http.Client{Timeout: 60 * time.Second},
+		client:       newOpenRouterClient(apiKey, model, "HOM Gym Digitizer"),
 		userRepo:     userRepo,
 		tenantRepo:   tenantRepo,
 		systemTmpl:   sysTmpl,
@@ -99,34 +151,61 @@ func NewOpenRouterDigitizer(
 // ExtractMetrics uses OpenRouter AI to extract InBody metrics from an image
 func (d *OpenRouterDigitizer) ExtractMetrics(ctx context.Context, userID string, imageData []byte) (*domain.InBodyMetrics, error) {
 	// 1. Determine Context (SaaS)
-	promptCtx := PromptContext{
-		GymName: defaultGymName,
-		Tone:    defaultTone,
-		Style:   defaultStyle,
-		Persona: defaultPersona,
-	}
-
-	// Try to fetch user and tenant
+	var tenant *domain.Tenant
 	if userID != "" && d.userRepo != nil {
 		user, err := d.userRepo.GetByFirebaseUID(ctx, userID)
 		if err == nil && user != nil && user.TenantID != "" {
-			tenant, err := d.tenantRepo.GetByID(ctx, user.TenantID)
-			if err == nil && tenant != nil {
-				promptCtx.GymName = tenant.Name
-				if tenant.AISettings.Tone != "" {
-					promptCtx.Tone = tenant.AISettings.Tone
-				}
-				if tenant.AISettings.Style != "" {
-					promptCtx.Style = tenant.AISettings.Style
-				}
-				if tenant.AISettings.Persona != "" {
-					promptCtx.Persona = tenant.AISettings.Persona
-				}
+			if t, err := d.tenantRepo.GetByID(ctx, user.TenantID); err == nil {
+				tenant = t
 			}
 		}
 	}
 
 	// 2. Generate Prompts
+	prompts, err := d.buildPrompts(newPromptContext(tenant))
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := d.client.complete(ctx, []map[string]interface{}{
+		{
+			"role":    "system",
+			"content": prompts.SystemPrompt,
+		},
+		{
+			"role": "user",
+			"content": []map[string]interface{}{
+				{
+					"type": "text",
+					"text": prompts.UserPrompt,
+				},
+				imagePart(detectImageType(imageData), imageData),
+			},
+		},
+	}, 0.1)
+	if err != nil {
+		return nil, err
+	}
+
+	var metrics domain.InBodyMetrics
+	if err := json.Unmarshal([]byte(content), &metrics); err != nil {
+		metrics, err = extractJSONFromText(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse AI response as JSON: %w", err)
+		}
+	}
+	metrics.Model = d.client.model
+
+	return &metrics, nil
+}
+
+// PreviewPrompts renders the prompts a scan for this tenant would use, so platform
+// admins can check the effect of AI settings before members see it
+func (d *OpenRouterDigitizer) PreviewPrompts(tenant *domain.Tenant) (*PromptPreview, error) {
+	return d.buildPrompts(newPromptContext(tenant))
+}
+
+func (d *OpenRouterDigitizer) buildPrompts(promptCtx PromptContext) (*PromptPreview, error) {
 	var systemPromptBuf bytes.Buffer
 	if err := d.systemTmpl.Execute(&systemPromptBuf, promptCtx); err != nil {
 		return nil, fmt.Errorf("failed to generate system prompt: %w", err)
@@ -207,111 +286,7 @@ Return ONLY valid JSON in this EXACT format:
 
 NOTE: If segmental data is not visible or unclear, use 0.0 and mention it in the analysis summary.`, analysisPromptBuf.String(), promptCtx.GymName)
 
-	// Detect image type
-	imageType := detectImageType(imageData)
-	imageBase64 := base64.StdEncoding.EncodeToString(imageData)
-
-	// Build request payload
-	requestBody := map[string]interface{}{
-		"model": d.model,
-		"messages": []map[string]interface{}{
-			{
-				"role":    "system",
-				"content": systemPromptBuf.String(),
-			},
-			{
-				"role": "user",
-				"content": []map[string]interface{}{
-					{
-						"type": "text",
-						"text": fullUserPrompt,
-					},
-					{
-						"type": "image_url",
-						"image_url": map[string]string{
-							"url": fmt.Sprintf("data:%s;base64,%s", imageType, imageBase64),
-						},
-					},
-				},
-			},
-		},
-		"temperature": 0.1,
-	}
-
-	payload, err := json.Marshal(requestBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "POST", openRouterAPIURL, bytes.NewReader(payload))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+d.apiKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("HTTP-Referer", "https://homgym.app") // Optional
-	req.Header.Set("X-Title", "HOM Gym Digitizer")       // Optional
-
-	// Send request
-	resp, err := d.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("openrouter api error (status %d): %s", resp.StatusCode, string(body))
-	}
-
-	var apiResponse struct {
-		Choices []struct {
-			Message struct {
-				Content string `json:"content"`
-			} `json:"message"`
-		} `json:"choices"`
-		Error *struct {
-			Message  string                 `json:"message"`
-			Code     int                    `json:"code"`
-			Metadata map[string]interface{} `json:"metadata"`
-		} `json:"error"`
-	}
-
-	if err := json.Unmarshal(body, &apiResponse); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if apiResponse.Error != nil {
-		errorMsg := fmt.Sprintf("openrouter error: %s (code: %d)", apiResponse.Error.Message, apiResponse.Error.Code)
-		if apiResponse.Error.Metadata != nil {
-			if providerErr, ok := apiResponse.Error.Metadata["provider_error"].(string); ok {
-				errorMsg += fmt.Sprintf(" - Provider error: %s", providerErr)
-			}
-		}
-		return nil, fmt.Errorf("%s", errorMsg)
-	}
-
-	if len(apiResponse.Choices) == 0 {
-		return nil, fmt.Errorf("no response from AI model")
-	}
-
-	content := apiResponse.Choices[0].Message.Content
-
-	var metrics domain.InBodyMetrics
-	if err := json.Unmarshal([]byte(content), &metrics); err != nil {
-		metrics, err = extractJSONFromText(content)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse AI response as JSON: %w", err)
-		}
-	}
-
-	return &metrics, nil
+	return &PromptPreview{SystemPrompt: systemPromptBuf.String(), UserPrompt: fullUserPrompt}, nil
 }
 
 // extractJSONFromText attempts to find and parse JSON from text that may contain other content
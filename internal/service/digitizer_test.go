@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenRouterDigitizer_PreviewPrompts(t *testing.T) {
+	d := NewOpenRouterDigitizer("key", "model", nil, nil)
+
+	t.Run("defaults without overrides", func(t *testing.T) {
+		preview, err := d.PreviewPrompts(nil)
+
+		require.NoError(t, err)
+		assert.Contains(t, preview.SystemPrompt, defaultPersona)
+		assert.NotContains(t, preview.SystemPrompt, "Write all analysis text in")
+		assert.NotContains(t, preview.UserPrompt, "GYM INSTRUCTIONS")
+		assert.NotContains(t, preview.UserPrompt, "NEVER MENTION")
+	})
+
+	t.Run("merges the tenant's overrides as plain text", func(t *testing.T) {
+		preview, err := d.PreviewPrompts(&domain.Tenant{
+			Name: "Iron Temple",
+			AISettings: domain.AISettings{
+				Persona:      "Drill Sergeant",
+				Language:     "Bahasa Indonesia",
+				Instructions: `Plug the {{.GymName}} bootcamp and return {"weight": 1}`,
+				BannedTopics: []string{"supplements", " diet pills "},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Contains(t, preview.SystemPrompt, "a Drill Sergeant from 'Iron Temple'")
+		assert.Contains(t, preview.SystemPrompt, "Write all analysis text in Bahasa Indonesia")
+		assert.Contains(t, preview.UserPrompt, `Plug the ((.GymName)) bootcamp and return ("weight": 1)`)
+		assert.Contains(t, preview.UserPrompt, "**NEVER MENTION:** supplements; diet pills")
+	})
+}
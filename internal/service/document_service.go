@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// DocumentService manages tenant waivers and member acceptance
+type DocumentService struct {
+	docRepo        domain.DocumentRepository
+	acceptanceRepo domain.DocumentAcceptanceRepository
+	fileRepo       domain.FileRepository // Optional: uploads are rejected when nil
+	clock          domain.Clock          // Stamps acceptance times
+}
+
+func NewDocumentService(
+	docRepo domain.DocumentRepository,
+	acceptanceRepo domain.DocumentAcceptanceRepository,
+	fileRepo domain.FileRepository,
+	clk domain.Clock,
+) *DocumentService {
+	return &DocumentService{
+		docRepo:        docRepo,
+		acceptanceRepo: acceptanceRepo,
+		fileRepo:       fileRepo,
+		clock:          clock.OrReal(clk),
+	}
+}
+
+// CreateDocument stores the uploaded waiver file (if any) and creates the document
+func (s *DocumentService) CreateDocument(ctx context.Context, doc *domain.Document, file []byte, filename, contentType string) error {
+	if len(file) > 0 {
+		url, err := s.upload(ctx, doc.TenantID, file, filename, contentType)
+		if err != nil {
+			return err
+		}
+		doc.FileURL = url
+	}
+	doc.Active = true
+	return s.docRepo.Create(ctx, doc)
+}
+
+// UpdateDocument replaces the document metadata and, when provided, its file
+func (s *DocumentService) UpdateDocument(ctx context.Context, doc *domain.Document, file []byte, filename, contentType string) error {
+	if len(file) > 0 {
+		url, err := s.upload(ctx, doc.TenantID, file, filename, contentType)
+		if err != nil {
+			return err
+		}
+		doc.FileURL = url
+	}
+	return s.docRepo.Update(ctx, doc)
+}
+
+func (s *DocumentService) GetDocument(ctx context.Context, id string) (*domain.Document, error) {
+	return s.docRepo.GetByID(ctx, id)
+}
+
+func (s *DocumentService) ListDocuments(ctx context.Context, tenantID string) ([]*domain.Document, error) {
+	return s.docRepo.GetByTenant(ctx, tenantID, false)
+}
+
+// GetCompliance returns the member's status against the tenant's active documents
+func (s *DocumentService) GetCompliance(ctx context.Context, tenantID, memberID string) (*domain.DocumentCompliance, error) {
+	docs, err := s.docRepo.GetByTenant(ctx, tenantID, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load documents: %w", err)
+	}
+	acceptances, err := s.acceptanceRepo.GetByMember(ctx, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load acceptances: %w", err)
+	}
+	return domain.BuildDocumentCompliance(docs, acceptances), nil
+}
+
+// AcceptDocument records a member accepting an active document of their tenant
+func (s *DocumentService) AcceptDocument(ctx context.Context, acceptance *domain.DocumentAcceptance) error {
+	doc, err := s.docRepo.GetByID(ctx, acceptance.DocumentID)
+	if err != nil {
+		return err
+	}
+	if doc.TenantID != acceptance.TenantID || !doc.Active {
+		return domain.ErrDocumentNotFound
+	}
+
+	acceptance.AcceptedAt = s.clock.Now()
+	return s.acceptanceRepo.Upsert(ctx, acceptance)
+}
+
+// CheckBookingAllowed returns ErrRequiredDocumentsUnsigned if a booking-blocking document is outstanding
+func (s *DocumentService) CheckBookingAllowed(ctx context.Context, tenantID, memberID string) error {
+	compliance, err := s.GetCompliance(ctx, tenantID, memberID)
+	if err != nil {
+		return err
+	}
+	if compliance.BookingBlocked {
+		return domain.ErrRequiredDocumentsUnsigned
+	}
+	return nil
+}
+
+func (s *DocumentService) upload(ctx context.Context, tenantID string, file []byte, filename, contentType string) (string, error) {
+	if s.fileRepo == nil {
+		return "", fmt.Errorf("file storage is not configured")
+	}
+	key := fmt.Sprintf("documents/%s/%d%s", tenantID, s.clock.Now().UnixNano(), filepath.Ext(filename))
+	return s.fileRepo.Upload(ctx, file, key, contentType)
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// maxSubstitutes caps how many alternatives are suggested for one exercise
+const maxSubstitutes = 10
+
+// maintenanceLogLimit caps the maintenance history returned for one piece of equipment
+const maintenanceLogLimit = 100
+
+// EquipmentService manages branch equipment inventories and their maintenance, and
+// suggests exercise substitutes when a branch lacks the kit an exercise needs
+type EquipmentService struct {
+	equipmentRepo   domain.EquipmentRepository
+	maintenanceRepo domain.EquipmentMaintenanceRepository
+	branchRepo      domain.BranchRepository
+	exerciseRepo    domain.ExerciseRepository
+	schedRepo       domain.ScheduleRepository
+	clock           domain.Clock
+}
+
+func NewEquipmentService(equipmentRepo domain.EquipmentRepository, maintenanceRepo domain.EquipmentMaintenanceRepository, branchRepo domain.BranchRepository, exerciseRepo domain.ExerciseRepository, schedRepo domain.ScheduleRepository, clk domain.Clock) *EquipmentService {
+	return &EquipmentService{
+		equipmentRepo:   equipmentRepo,
+		maintenanceRepo: maintenanceRepo,
+		branchRepo:      branchRepo,
+		exerciseRepo:    exerciseRepo,
+		schedRepo:       schedRepo,
+		clock:           clock.OrReal(clk),
+	}
+}
+
+// ListBranchEquipment returns a branch's inventory, checking it belongs to the tenant
+func (s *EquipmentService) ListBranchEquipment(ctx context.Context, tenantID, branchID string) ([]*domain.Equipment, error) {
+	if err := s.checkBranch(ctx, tenantID, branchID); err != nil {
+		return nil, err
+	}
+	return s.equipmentRepo.ListByBranch(ctx, branchID)
+}
+
+func (s *EquipmentService) AddEquipment(ctx context.Context, tenantID string, item *domain.Equipment) error {
+	item.Name = strings.TrimSpace(item.Name)
+	if item.Name == "" || item.Quantity < 0 {
+		return domain.ErrInvalidEquipment
+	}
+	if err := s.checkBranch(ctx, tenantID, item.BranchID); err != nil {
+		return err
+	}
+	item.TenantID = tenantID
+	return s.equipmentRepo.Create(ctx, item)
+}
+
+// UpdateEquipment changes the name, quantity or availability of a tenant's equipment
+func (s *EquipmentService) UpdateEquipment(ctx context.Context, tenantID string, changes *domain.Equipment) (*domain.Equipment, error) {
+	item, err := s.tenantEquipment(ctx, tenantID, changes.ID)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(changes.Name)
+	if name == "" || changes.Quantity < 0 {
+		return nil, domain.ErrInvalidEquipment
+	}
+	item.Name, item.Quantity, item.Available = name, changes.Quantity, changes.Available
+	if item.OutOfOrder > item.Quantity {
+		// Units written off were the broken ones
+		item.OutOfOrder = item.Quantity
+	}
+	if item.OutOfOrder == 0 {
+		item.OutOfOrderSince = nil
+	}
+	if err := s.equipmentRepo.Update(ctx, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (s *EquipmentService) DeleteEquipment(ctx context.Context, tenantID, id string) error {
+	if _, err := s.tenantEquipment(ctx, tenantID, id); err != nil {
+		return err
+	}
+	return s.equipmentRepo.Delete(ctx, id)
+}
+
+// LogMaintenance records a breakdown, repair or service of a tenant's equipment by userID.
+// Units reported out of order stop counting towards the branch's inventory, so exercise
+// substitutes steer around them until they are repaired.
+func (s *EquipmentService) LogMaintenance(ctx context.Context, tenantID, userID string, entry *domain.EquipmentMaintenance) (*domain.Equipment, error) {
+	item, err := s.tenantEquipment(ctx, tenantID, entry.EquipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := item.ApplyMaintenance(entry, s.clock.Now()); err != nil {
+		return nil, err
+	}
+	entry.LoggedBy = userID
+	if err := s.equipmentRepo.Update(ctx, item); err != nil {
+		return nil, err
+	}
+	if err := s.maintenanceRepo.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+	log.Printf("[Audit] %s logged %s of %d %s at branch %s", userID, entry.Kind, entry.Units, item.Name, item.BranchID)
+	return item, nil
+}
+
+// MaintenanceLog returns the latest maintenance entries of a tenant's equipment
+func (s *EquipmentService) MaintenanceLog(ctx context.Context, tenantID, id string) ([]*domain.EquipmentMaintenance, error) {
+	if _, err := s.tenantEquipment(ctx, tenantID, id); err != nil {
+		return nil, err
+	}
+	return s.maintenanceRepo.ListByEquipment(ctx, id, maintenanceLogLimit)
+}
+
+// MaintenanceReport lists, per branch of the tenant or just branchID, the equipment out of
+// order now and the maintenance logged in [from, to)
+func (s *EquipmentService) MaintenanceReport(ctx context.Context, tenantID, branchID string, from, to time.Time) (*domain.MaintenanceReport, error) {
+	var branches []*domain.Branch
+	if branchID != "" {
+		branch, err := s.branchRepo.GetByID(ctx, branchID)
+		if err != nil {
+			return nil, err
+		}
+		if branch.TenantID != tenantID {
+			return nil, domain.ErrNotFound
+		}
+		branches = []*domain.Branch{branch}
+	} else {
+		var err error
+		if branches, err = s.branchRepo.GetByTenantID(ctx, tenantID); err != nil {
+			return nil, err
+		}
+	}
+	entries, err := s.maintenanceRepo.ListByTenant(ctx, tenantID, branchID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	report := &domain.MaintenanceReport{From: from, To: to, Branches: make([]domain.BranchMaintenance, 0, len(branches))}
+	index := make(map[string]int, len(branches))
+	for _, branch := range branches {
+		items, err := s.equipmentRepo.ListByBranch(ctx, branch.ID)
+		if err != nil {
+			return nil, err
+		}
+		row := domain.BranchMaintenance{BranchID: branch.ID, BranchName: branch.Name, Equipment: len(items), OutOfOrder: []domain.OutOfOrderEquipment{}}
+		for _, item := range items {
+			if item.InService() == item.Quantity {
+				continue
+			}
+			out := domain.OutOfOrderEquipment{EquipmentID: item.ID, Name: item.Name, Quantity: item.Quantity, OutOfOrder: item.OutOfOrder, Since: item.OutOfOrderSince}
+			if !item.Available {
+				// Taken out of service as a whole
+				out.OutOfOrder = item.Quantity
+			}
+			if out.Since != nil {
+				out.DaysOut = int(now.Sub(*out.Since).Hours() / 24)
+			}
+			row.OutOfOrder = append(row.OutOfOrder, out)
+		}
+		index[branch.ID] = len(report.Branches)
+		report.Branches = append(report.Branches, row)
+	}
+	for _, entry := range entries {
+		i, ok := index[entry.BranchID]
+		if !ok {
+			continue // A deleted branch
+		}
+		switch entry.Kind {
+		case domain.MaintenanceOutOfOrder:
+			report.Branches[i].Reported += entry.Units
+		case domain.MaintenanceRepaired:
+			report.Branches[i].Repaired += entry.Units
+		case domain.MaintenanceServiced:
+			report.Branches[i].Serviced++
+		}
+	}
+	return report, nil
+}
+
+// Substitutes returns exercises for the same muscle group that the branch has equipment
+// for, and whether the branch can run the original exercise as is
+func (s *EquipmentService) Substitutes(ctx context.Context, tenantID, exerciseID, branchID string) (supported bool, substitutes []*domain.Exercise, err error) {
+	if err := s.checkBranch(ctx, tenantID, branchID); err != nil {
+		return false, nil, err
+	}
+	exercise, err := s.exerciseRepo.GetByID(ctx, exerciseID)
+	if err != nil {
+		return false, nil, err
+	}
+	inv, err := s.inventory(ctx, branchID)
+	if err != nil {
+		return false, nil, err
+	}
+	substitutes, err = s.substitutesFor(ctx, exercise, inv)
+	if err != nil {
+		return false, nil, err
+	}
+	return inv.Supports(exercise), substitutes, nil
+}
+
+// SubstitutesForSession returns alternatives when the session's branch can't run the
+// exercise, and nothing when it can
+func (s *EquipmentService) SubstitutesForSession(ctx context.Context, scheduleID, exerciseID string) ([]*domain.Exercise, error) {
+	schedule, err := s.schedRepo.GetByID(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.BranchID == "" {
+		return nil, nil
+	}
+	exercise, err := s.exerciseRepo.GetByID(ctx, exerciseID)
+	if err != nil {
+		return nil, err
+	}
+	inv, err := s.inventory(ctx, schedule.BranchID)
+	if err != nil {
+		return nil, err
+	}
+	if inv.Supports(exercise) {
+		return nil, nil
+	}
+	return s.substitutesFor(ctx, exercise, inv)
+}
+
+func (s *EquipmentService) substitutesFor(ctx context.Context, exercise *domain.Exercise, inv domain.EquipmentInventory) ([]*domain.Exercise, error) {
+	if exercise.MuscleGroup == "" {
+		return []*domain.Exercise{}, nil
+	}
+	candidates, err := s.exerciseRepo.List(ctx, map[string]interface{}{"muscle_group": exercise.MuscleGroup})
+	if err != nil {
+		return nil, err
+	}
+
+	substitutes := []*domain.Exercise{}
+	for _, c := range candidates {
+		if c.ID != exercise.ID && inv.Supports(c) {
+			substitutes = append(substitutes, c)
+		}
+	}
+	// Same equipment first as the closest swap, then by name
+	slices.SortStableFunc(substitutes, func(a, b *domain.Exercise) int {
+		aSame, bSame := strings.EqualFold(a.Equipment, exercise.Equipment), strings.EqualFold(b.Equipment, exercise.Equipment)
+		if aSame != bSame {
+			if aSame {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	if len(substitutes) > maxSubstitutes {
+		substitutes = substitutes[:maxSubstitutes]
+	}
+	return substitutes, nil
+}
+
+func (s *EquipmentService) inventory(ctx context.Context, branchID string) (domain.EquipmentInventory, error) {
+	items, err := s.equipmentRepo.ListByBranch(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewEquipmentInventory(items), nil
+}
+
+func (s *EquipmentService) checkBranch(ctx context.Context, tenantID, branchID string) error {
+	branch, err := s.branchRepo.GetByID(ctx, branchID)
+	if err != nil {
+		return err
+	}
+	if branch.TenantID != tenantID {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (s *EquipmentService) tenantEquipment(ctx context.Context, tenantID, id string) (*domain.Equipment, error) {
+	item, err := s.equipmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if item.TenantID != tenantID {
+		return nil, domain.ErrEquipmentNotFound
+	}
+	return item, nil
+}
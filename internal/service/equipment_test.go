@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEquipmentService_SubstitutesForSession(t *testing.T) {
+	ctx := context.Background()
+	equipment := mocks.NewEquipmentRepository(t)
+	exercises := mocks.NewExerciseRepository(t)
+	schedRepo := mocks.NewScheduleRepository(t)
+	svc := NewEquipmentService(equipment, mocks.NewEquipmentMaintenanceRepository(t), mocks.NewBranchRepository(t), exercises, schedRepo, clock.NewFake(testNow))
+
+	squat := &domain.Exercise{ID: "squat", Name: "Back Squat", MuscleGroup: "Legs", Equipment: "Barbell"}
+	schedRepo.On("GetByID", ctx, "sched-1").Return(&domain.Schedule{ID: "sched-1", BranchID: "north"}, nil)
+	exercises.On("GetByID", ctx, "squat").Return(squat, nil)
+	equipment.On("ListByBranch", ctx, "north").Return([]*domain.Equipment{
+		{Name: "Barbell", Quantity: 2, Available: false},
+		{Name: "Dumbbell", Quantity: 10, Available: true},
+		{Name: "Leg Press", Quantity: 1, Available: true},
+	}, nil)
+	exercises.On("List", ctx, map[string]interface{}{"muscle_group": "Legs"}).Return([]*domain.Exercise{
+		squat,
+		{ID: "front", Name: "Front Squat", MuscleGroup: "Legs", Equipment: "Barbell"},
+		{ID: "press", Name: "Leg Press", MuscleGroup: "Legs", Equipment: "Leg Press"},
+		{ID: "goblet", Name: "Goblet Squat", MuscleGroup: "Legs", Equipment: "Dumbbell"},
+		{ID: "lunge", Name: "Walking Lunge", MuscleGroup: "Legs", Equipment: "Bodyweight"},
+	}, nil)
+
+	substitutes, err := svc.SubstitutesForSession(ctx, "sched-1", "squat")
+
+	require.NoError(t, err)
+	var ids []string
+	for _, ex := range substitutes {
+		ids = append(ids, ex.ID)
+	}
+	assert.Equal(t, []string{"goblet", "press", "lunge"}, ids, "no barbell lifts while the barbells are out of service")
+}
+
+func TestEquipmentService_SubstitutesForSession_Available(t *testing.T) {
+	ctx := context.Background()
+	equipment := mocks.NewEquipmentRepository(t)
+	exercises := mocks.NewExerciseRepository(t)
+	schedRepo := mocks.NewScheduleRepository(t)
+	svc := NewEquipmentService(equipment, mocks.NewEquipmentMaintenanceRepository(t), mocks.NewBranchRepository(t), exercises, schedRepo, clock.NewFake(testNow))
+
+	schedRepo.On("GetByID", ctx, "sched-1").Return(&domain.Schedule{ID: "sched-1", BranchID: "north"}, nil)
+	exercises.On("GetByID", ctx, "squat").Return(&domain.Exercise{ID: "squat", MuscleGroup: "Legs", Equipment: "Barbell"}, nil)
+	equipment.On("ListByBranch", ctx, "north").Return([]*domain.Equipment{{Name: "Barbell", Quantity: 2, Available: true}}, nil)
+
+	substitutes, err := svc.SubstitutesForSession(ctx, "sched-1", "squat")
+
+	require.NoError(t, err)
+	assert.Empty(t, substitutes)
+}
+
+func TestEquipmentService_LogMaintenance(t *testing.T) {
+	ctx := context.Background()
+	equipment, maintenance := mocks.NewEquipmentRepository(t), mocks.NewEquipmentMaintenanceRepository(t)
+	svc := NewEquipmentService(equipment, maintenance, mocks.NewBranchRepository(t), mocks.NewExerciseRepository(t), mocks.NewScheduleRepository(t), clock.NewFake(testNow))
+
+	equipment.On("GetByID", ctx, "eq-1").Return(&domain.Equipment{ID: "eq-1", TenantID: "gym", BranchID: "north", Name: "Barbell", Quantity: 4, Available: true}, nil)
+	equipment.On("Update", ctx, mock.MatchedBy(func(item *domain.Equipment) bool {
+		return item.OutOfOrder == 2 && item.OutOfOrderSince.Equal(testNow)
+	})).Return(nil).Once()
+	maintenance.On("Create", ctx, mock.MatchedBy(func(entry *domain.EquipmentMaintenance) bool {
+		return entry.TenantID == "gym" && entry.BranchID == "north" && entry.LoggedBy == "coach-1" && entry.Units == 2
+	})).Return(nil).Once()
+
+	item, err := svc.LogMaintenance(ctx, "gym", "coach-1", &domain.EquipmentMaintenance{EquipmentID: "eq-1", Kind: domain.MaintenanceOutOfOrder, Units: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 2, item.InService())
+
+	_, err = svc.LogMaintenance(ctx, "other-gym", "coach-1", &domain.EquipmentMaintenance{EquipmentID: "eq-1", Kind: domain.MaintenanceOutOfOrder})
+	assert.ErrorIs(t, err, domain.ErrEquipmentNotFound)
+}
+
+func TestEquipmentService_MaintenanceReport(t *testing.T) {
+	ctx := context.Background()
+	equipment, maintenance, branches := mocks.NewEquipmentRepository(t), mocks.NewEquipmentMaintenanceRepository(t), mocks.NewBranchRepository(t)
+	svc := NewEquipmentService(equipment, maintenance, branches, mocks.NewExerciseRepository(t), mocks.NewScheduleRepository(t), clock.NewFake(testNow))
+	from, to := testNow.AddDate(0, 0, -28), testNow
+	since := testNow.Add(-72 * time.Hour)
+
+	branches.On("GetByTenantID", ctx, "gym").Return([]*domain.Branch{{ID: "north", TenantID: "gym", Name: "North"}, {ID: "south", TenantID: "gym", Name: "South"}}, nil)
+	maintenance.On("ListByTenant", ctx, "gym", "", from, to).Return([]*domain.EquipmentMaintenance{
+		{BranchID: "north", Kind: domain.MaintenanceOutOfOrder, Units: 2},
+		{BranchID: "north", Kind: domain.MaintenanceRepaired, Units: 1},
+		{BranchID: "south", Kind: domain.MaintenanceServiced},
+		{BranchID: "closed", Kind: domain.MaintenanceServiced},
+	}, nil)
+	equipment.On("ListByBranch", ctx, "north").Return([]*domain.Equipment{
+		{ID: "eq-1", Name: "Treadmill", Quantity: 3, Available: true, OutOfOrder: 1, OutOfOrderSince: &since},
+		{ID: "eq-2", Name: "Cable Machine", Quantity: 1, Available: false},
+		{ID: "eq-3", Name: "Barbell", Quantity: 4, Available: true},
+	}, nil)
+	equipment.On("ListByBranch", ctx, "south").Return([]*domain.Equipment{}, nil)
+
+	report, err := svc.MaintenanceReport(ctx, "gym", "", from, to)
+
+	require.NoError(t, err)
+	require.Len(t, report.Branches, 2)
+	north := report.Branches[0]
+	assert.Equal(t, 3, north.Equipment)
+	assert.Equal(t, []domain.OutOfOrderEquipment{
+		{EquipmentID: "eq-1", Name: "Treadmill", Quantity: 3, OutOfOrder: 1, Since: &since, DaysOut: 3},
+		{EquipmentID: "eq-2", Name: "Cable Machine", Quantity: 1, OutOfOrder: 1},
+	}, north.OutOfOrder)
+	assert.Equal(t, 2, north.Reported)
+	assert.Equal(t, 1, north.Repaired)
+	assert.Equal(t, 1, report.Branches[1].Serviced)
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// ExerciseMergeService finds near-duplicate exercises in the global catalog and folds
+// duplicates into a canonical exercise, keeping members' history and PBs intact
+type ExerciseMergeService struct {
+	exerciseRepo domain.ExerciseRepository
+	merges       domain.ExerciseMergeRepository
+	tx           domain.Transactor
+	clock        domain.Clock
+}
+
+func NewExerciseMergeService(exerciseRepo domain.ExerciseRepository, merges domain.ExerciseMergeRepository, tx domain.Transactor, clk domain.Clock) *ExerciseMergeService {
+	return &ExerciseMergeService{
+		exerciseRepo: exerciseRepo,
+		merges:       merges,
+		tx:           tx,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// Duplicates reports the catalog's likely duplicate pairs. A threshold of 0 uses the default.
+func (s *ExerciseMergeService) Duplicates(ctx context.Context, threshold float64) ([]domain.ExerciseDuplicate, error) {
+	if threshold <= 0 {
+		threshold = domain.DefaultDuplicateThreshold
+	}
+	exercises, err := s.exerciseRepo.List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return domain.FindDuplicateExercises(exercises, threshold), nil
+}
+
+// Merge re-points set logs, planned exercises, templates, PBs and workout events from the
+// duplicates to canonicalID and deletes the duplicates, all or nothing
+func (s *ExerciseMergeService) Merge(ctx context.Context, actorID, canonicalID string, duplicateIDs []string) (*domain.ExerciseMerge, error) {
+	if canonicalID == "" || len(duplicateIDs) == 0 || slices.Contains(duplicateIDs, canonicalID) {
+		return nil, domain.ErrInvalidExerciseMerge
+	}
+	canonical, err := s.exerciseRepo.GetByID(ctx, canonicalID)
+	if err != nil {
+		return nil, err
+	}
+
+	merge := &domain.ExerciseMerge{
+		CanonicalID: canonicalID,
+		ActorID:     actorID,
+		CreatedAt:   s.clock.Now(),
+	}
+	for _, id := range duplicateIDs {
+		if slices.Contains(merge.DuplicateIDs, id) {
+			continue
+		}
+		duplicate, err := s.exerciseRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("exercise %s: %w", id, err)
+		}
+		// Other tenants' history can't be pointed at a tenant's private exercise
+		if canonical.TenantID != "" && duplicate.TenantID != canonical.TenantID {
+			return nil, domain.ErrInvalidExerciseMerge
+		}
+		merge.DuplicateIDs = append(merge.DuplicateIDs, id)
+		merge.DuplicateNames = append(merge.DuplicateNames, duplicate.Name)
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if merge.SetLogs, err = s.merges.RepointSetLogs(ctx, merge.DuplicateIDs, canonicalID); err != nil {
+			return err
+		}
+		if merge.PlannedExercises, err = s.merges.RepointPlannedExercises(ctx, merge.DuplicateIDs, canonicalID); err != nil {
+			return err
+		}
+		if merge.Templates, err = s.merges.RepointTemplates(ctx, merge.DuplicateIDs, canonicalID); err != nil {
+			return err
+		}
+		if merge.PersonalBests, err = s.merges.MergePersonalBests(ctx, merge.DuplicateIDs, canonicalID); err != nil {
+			return err
+		}
+		if _, err = s.merges.RepointWorkoutEvents(ctx, merge.DuplicateIDs, canonicalID); err != nil {
+			return err
+		}
+		for _, id := range merge.DuplicateIDs {
+			if err := s.exerciseRepo.Delete(ctx, id); err != nil {
+				return err
+			}
+		}
+		return s.merges.Record(ctx, merge)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return merge, nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestExerciseMergeService(t *testing.T) (*ExerciseMergeService, *mocks.ExerciseRepository, *mocks.ExerciseMergeRepository) {
+	exercises := mocks.NewExerciseRepository(t)
+	merges := mocks.NewExerciseMergeRepository(t)
+	tx := mocks.NewTransactor(t)
+	tx.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInline).Maybe()
+	return NewExerciseMergeService(exercises, merges, tx, clock.NewFake(testNow)), exercises, merges
+}
+
+func TestExerciseMergeService_Merge(t *testing.T) {
+	ctx := context.Background()
+	duplicates := []string{"ex-2", "ex-3"}
+
+	t.Run("re-points references and deletes the duplicates", func(t *testing.T) {
+		svc, exercises, merges := newTestExerciseMergeService(t)
+		exercises.On("GetByID", anyCtx, "ex-1").Return(&domain.Exercise{ID: "ex-1", Name: "Bench Press"}, nil)
+		exercises.On("GetByID", anyCtx, "ex-2").Return(&domain.Exercise{ID: "ex-2", Name: "Barbell Bench Press"}, nil)
+		exercises.On("GetByID", anyCtx, "ex-3").Return(&domain.Exercise{ID: "ex-3", Name: "Bench press (BB)"}, nil)
+		merges.On("RepointSetLogs", anyCtx, duplicates, "ex-1").Return(int64(42), nil)
+		merges.On("RepointPlannedExercises", anyCtx, duplicates, "ex-1").Return(int64(9), nil)
+		merges.On("RepointTemplates", anyCtx, duplicates, "ex-1").Return(int64(2), nil)
+		merges.On("MergePersonalBests", anyCtx, duplicates, "ex-1").Return(int64(3), nil)
+		merges.On("RepointWorkoutEvents", anyCtx, duplicates, "ex-1").Return(int64(12), nil)
+		exercises.On("Delete", anyCtx, "ex-2").Return(nil)
+		exercises.On("Delete", anyCtx, "ex-3").Return(nil)
+		merges.On("Record", anyCtx, mock.AnythingOfType("*domain.ExerciseMerge")).Return(nil)
+
+		merge, err := svc.Merge(ctx, "admin-1", "ex-1", []string{"ex-2", "ex-3", "ex-2"})
+		require.NoError(t, err)
+
+		assert.Equal(t, duplicates, merge.DuplicateIDs)
+		assert.Equal(t, []string{"Barbell Bench Press", "Bench press (BB)"}, merge.DuplicateNames)
+		assert.Equal(t, int64(42), merge.SetLogs)
+		assert.Equal(t, int64(9), merge.PlannedExercises)
+		assert.Equal(t, int64(2), merge.Templates)
+		assert.Equal(t, int64(3), merge.PersonalBests)
+		assert.Equal(t, testNow, merge.CreatedAt)
+	})
+
+	t.Run("rejects merging an exercise into itself", func(t *testing.T) {
+		svc, _, _ := newTestExerciseMergeService(t)
+
+		_, err := svc.Merge(ctx, "admin-1", "ex-1", []string{"ex-1"})
+		assert.ErrorIs(t, err, domain.ErrInvalidExerciseMerge)
+	})
+
+	t.Run("rejects merging global exercises into a tenant's private one", func(t *testing.T) {
+		svc, exercises, _ := newTestExerciseMergeService(t)
+		exercises.On("GetByID", anyCtx, "ex-1").Return(&domain.Exercise{ID: "ex-1", TenantID: "tenant-1"}, nil)
+		exercises.On("GetByID", anyCtx, "ex-2").Return(&domain.Exercise{ID: "ex-2"}, nil)
+
+		_, err := svc.Merge(ctx, "admin-1", "ex-1", []string{"ex-2"})
+		assert.ErrorIs(t, err, domain.ErrInvalidExerciseMerge)
+	})
+
+	t.Run("nothing is deleted when a rewrite fails", func(t *testing.T) {
+		svc, exercises, merges := newTestExerciseMergeService(t)
+		exercises.On("GetByID", anyCtx, "ex-1").Return(&domain.Exercise{ID: "ex-1"}, nil)
+		exercises.On("GetByID", anyCtx, "ex-2").Return(&domain.Exercise{ID: "ex-2"}, nil)
+		merges.On("RepointSetLogs", anyCtx, []string{"ex-2"}, "ex-1").Return(int64(0), errors.New("write conflict"))
+
+		_, err := svc.Merge(ctx, "admin-1", "ex-1", []string{"ex-2"})
+		assert.EqualError(t, err, "write conflict")
+	})
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// Exercise import and export formats
+const (
+	ExerciseFormatCSV  = "csv"
+	ExerciseFormatJSON = "json"
+)
+
+// exerciseColumns are the CSV columns of an exercise, in export order
+var exerciseColumns = []string{"name", "muscle_group", "equipment", "video_url", "reference_url"}
+
+// ParseExerciseImport reads exercises from CSV with a header row, or from JSON as a list
+// or a catalog {"exercises": [...]}. CSV headers are matched case-insensitively with spaces
+// for underscores, and unknown columns are ignored. A name repeated in the file keeps its
+// first entry; the others are returned as issues.
+func ParseExerciseImport(format string, data []byte) ([]domain.CatalogExercise, []string, error) {
+	var entries []domain.CatalogExercise
+	switch format {
+	case ExerciseFormatJSON:
+		trimmed := bytes.TrimSpace(data)
+		if bytes.HasPrefix(trimmed, []byte("[")) {
+			if err := json.Unmarshal(trimmed, &entries); err != nil {
+				return nil, nil, fmt.Errorf("%w: %v", domain.ErrInvalidExerciseImport, err)
+			}
+			break
+		}
+		var catalog domain.SeedCatalog
+		if err := json.Unmarshal(trimmed, &catalog); err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", domain.ErrInvalidExerciseImport, err)
+		}
+		entries = catalog.Exercises
+	case ExerciseFormatCSV:
+		var err error
+		if entries, err = parseExerciseCSV(data); err != nil {
+			return nil, nil, err
+		}
+	default:
+		return nil, nil, fmt.Errorf("%w: use CSV or JSON", domain.ErrInvalidExerciseImport)
+	}
+	if len(entries) == 0 {
+		return nil, nil, fmt.Errorf("%w: no exercises in the file", domain.ErrInvalidExerciseImport)
+	}
+
+	var issues []string
+	seen := make(map[string]bool, len(entries))
+	unique := entries[:0]
+	for i, entry := range entries {
+		key := strings.ToLower(strings.TrimSpace(entry.Name))
+		if key != "" && seen[key] {
+			issues = append(issues, fmt.Sprintf("exercises[%d]: %q repeats an earlier entry and was skipped", i, entry.Name))
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, entry)
+	}
+	return unique, issues, nil
+}
+
+func parseExerciseCSV(data []byte) ([]domain.CatalogExercise, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing header row", domain.ErrInvalidExerciseImport)
+	}
+	cols := make(map[string]int, len(header))
+	for i, h := range header {
+		cols[strings.ReplaceAll(strings.ToLower(strings.TrimSpace(h)), " ", "_")] = i
+	}
+	if _, ok := cols["name"]; !ok {
+		return nil, fmt.Errorf("%w: the header has no name column", domain.ErrInvalidExerciseImport)
+	}
+
+	var entries []domain.CatalogExercise
+	for {
+		cells, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidExerciseImport, err)
+		}
+		if strings.TrimSpace(strings.Join(cells, "")) == "" {
+			continue // Blank line
+		}
+		cell := func(column string) string {
+			if i, ok := cols[column]; ok && i < len(cells) {
+				return strings.TrimSpace(cells[i])
+			}
+			return ""
+		}
+		entries = append(entries, domain.CatalogExercise{
+			Name:         cell("name"),
+			MuscleGroup:  cell("muscle_group"),
+			Equipment:    cell("equipment"),
+			VideoURL:     cell("video_url"),
+			ReferenceURL: cell("reference_url"),
+		})
+	}
+	return entries, nil
+}
+
+// ImportExercises validates the exercises and upserts them into the library by name, as
+// the seed command does. With dryRun nothing is written.
+func (s *SeedCatalogService) ImportExercises(ctx context.Context, entries []domain.CatalogExercise, dryRun bool) (*domain.SeedCatalogResult, error) {
+	catalog := &domain.SeedCatalog{Exercises: entries}
+	if problems := catalog.Validate(false); len(problems) > 0 {
+		return nil, fmt.Errorf("%w:\n  %s", domain.ErrInvalidExerciseImport, strings.Join(problems, "\n  "))
+	}
+	result := &domain.SeedCatalogResult{}
+	if _, err := s.applyExercises(ctx, entries, dryRun, result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// ExportExercises returns the whole library sorted by name, in the import's format, so an
+// export can be edited and imported again. JSON is a catalog the seed command also reads.
+func (s *SeedCatalogService) ExportExercises(ctx context.Context, format string) ([]byte, error) {
+	library, err := s.exerciseRepo.List(ctx, map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load exercises: %w", err)
+	}
+	sort.Slice(library, func(i, j int) bool { return strings.ToLower(library[i].Name) < strings.ToLower(library[j].Name) })
+
+	entries := make([]domain.CatalogExercise, len(library))
+	for i, ex := range library {
+		entries[i] = domain.CatalogExercise{Name: ex.Name, MuscleGroup: ex.MuscleGroup, Equipment: ex.Equipment,
+			VideoURL: ex.VideoURL, ReferenceURL: ex.ReferenceURL}
+	}
+
+	if format == ExerciseFormatJSON {
+		return json.MarshalIndent(domain.SeedCatalog{Exercises: entries}, "", "  ")
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	rows := [][]string{exerciseColumns}
+	for _, e := range entries {
+		rows = append(rows, []string{e.Name, e.MuscleGroup, e.Equipment, e.VideoURL, e.ReferenceURL})
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
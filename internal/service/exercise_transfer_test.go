@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExerciseImport(t *testing.T) {
+	t.Run("reads CSV by header, skipping repeated names", func(t *testing.T) {
+		data := []byte("\xef\xbb\xbfName,Muscle Group,Equipment,Notes\n" +
+			"Goblet Squat,Legs,Dumbbell,keep chest up\n" +
+			"\n" +
+			"goblet squat ,Legs,Kettlebell,\n" +
+			"Plank,Core\n")
+
+		entries, issues, err := ParseExerciseImport(ExerciseFormatCSV, data)
+
+		require.NoError(t, err)
+		assert.Equal(t, []domain.CatalogExercise{
+			{Name: "Goblet Squat", MuscleGroup: "Legs", Equipment: "Dumbbell"},
+			{Name: "Plank", MuscleGroup: "Core"},
+		}, entries)
+		require.Len(t, issues, 1)
+		assert.Contains(t, issues[0], "exercises[1]")
+	})
+
+	t.Run("reads a JSON list or catalog", func(t *testing.T) {
+		for _, data := range []string{
+			`[{"name": "Plank", "muscle_group": "Core"}]`,
+			`{"exercises": [{"name": "Plank", "muscle_group": "Core"}]}`,
+		} {
+			entries, issues, err := ParseExerciseImport(ExerciseFormatJSON, []byte(data))
+			require.NoError(t, err, data)
+			assert.Empty(t, issues)
+			assert.Equal(t, []domain.CatalogExercise{{Name: "Plank", MuscleGroup: "Core"}}, entries)
+		}
+	})
+
+	t.Run("rejects unreadable or empty files", func(t *testing.T) {
+		for format, data := range map[string]string{
+			ExerciseFormatCSV:  "muscle_group,equipment\nLegs,Barbell\n",
+			ExerciseFormatJSON: `{"exercises": []}`,
+			"xml":              "<exercises/>",
+		} {
+			_, _, err := ParseExerciseImport(format, []byte(data))
+			assert.ErrorIs(t, err, domain.ErrInvalidExerciseImport, format)
+		}
+	})
+}
+
+func TestSeedCatalogService_ImportExercises(t *testing.T) {
+	ctx := context.Background()
+	exercises := mocks.NewExerciseRepository(t)
+	svc := NewSeedCatalogService(exercises, nil, nil, nil, nil)
+
+	_, err := svc.ImportExercises(ctx, []domain.CatalogExercise{{Name: "Plank"}}, false)
+	assert.ErrorIs(t, err, domain.ErrInvalidExerciseImport, "muscle group is required")
+
+	exercises.On("List", ctx, map[string]interface{}{}).Return([]*domain.Exercise{
+		{ID: "squat", Name: "Goblet Squat", MuscleGroup: "Legs", Equipment: "Kettlebell"},
+	}, nil)
+	exercises.On("Create", ctx, mock.MatchedBy(func(ex *domain.Exercise) bool { return ex.Name == "Plank" })).Return(nil)
+	exercises.On("Update", ctx, mock.MatchedBy(func(ex *domain.Exercise) bool {
+		return ex.ID == "squat" && ex.Equipment == "Dumbbell"
+	})).Return(nil)
+
+	result, err := svc.ImportExercises(ctx, []domain.CatalogExercise{
+		{Name: "goblet squat", MuscleGroup: "Legs", Equipment: "Dumbbell"},
+		{Name: "Plank", MuscleGroup: "Core"},
+	}, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.SeedCatalogCounts{Created: 1, Updated: 1}, result.Exercises)
+}
+
+func TestSeedCatalogService_ExportExercises(t *testing.T) {
+	ctx := context.Background()
+	exercises := mocks.NewExerciseRepository(t)
+	svc := NewSeedCatalogService(exercises, nil, nil, nil, nil)
+	exercises.On("List", ctx, map[string]interface{}{}).Return([]*domain.Exercise{
+		{ID: "squat", Name: "Goblet Squat", MuscleGroup: "Legs", Equipment: "Dumbbell"},
+		{ID: "curl", Name: "bicep curl", MuscleGroup: "Arms", Equipment: "Dumbbell, EZ bar", VideoURL: "https://v/curl"},
+	}, nil)
+
+	data, err := svc.ExportExercises(ctx, ExerciseFormatCSV)
+
+	require.NoError(t, err)
+	assert.Equal(t, "name,muscle_group,equipment,video_url,reference_url\n"+
+		"bicep curl,Arms,\"Dumbbell, EZ bar\",https://v/curl,\n"+
+		"Goblet Squat,Legs,Dumbbell,,\n", string(data))
+
+	entries, _, err := ParseExerciseImport(ExerciseFormatCSV, data)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "an export imports back")
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// exerciseVideoURLTTL is how long a signed playback URL stays valid. Long enough to watch a
+// demo after opening the exercise, short enough that shared links die.
+const exerciseVideoURLTTL = time.Hour
+
+// ExerciseVideoService hosts exercise demonstration clips in file storage, so exercises
+// don't depend on YouTube links that break or are blocked in some regions
+type ExerciseVideoService struct {
+	exerciseRepo domain.ExerciseRepository
+	fileRepo     domain.FileRepository  // Optional: uploads are rejected and VideoURL is served when nil
+	transcoder   domain.VideoTranscoder // Optional: without it uploads must already be sized for streaming
+	limits       VideoLimits
+	clock        domain.Clock
+}
+
+func NewExerciseVideoService(
+	exerciseRepo domain.ExerciseRepository,
+	fileRepo domain.FileRepository,
+	transcoder domain.VideoTranscoder,
+	limits VideoLimits,
+	clk domain.Clock,
+) *ExerciseVideoService {
+	return &ExerciseVideoService{
+		exerciseRepo: exerciseRepo,
+		fileRepo:     fileRepo,
+		transcoder:   transcoder,
+		limits:       limits,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// Upload replaces the exercise's demonstration clip. Clips go through the transcoder when
+// one is configured; otherwise they must be 1080p or smaller.
+func (s *ExerciseVideoService) Upload(ctx context.Context, uploaderID, exerciseID string, file []byte, contentType string) (*domain.Exercise, error) {
+	if s.fileRepo == nil {
+		return nil, fmt.Errorf("file storage is not configured")
+	}
+	ex, err := s.exerciseRepo.GetByID(ctx, exerciseID)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType = strings.ToLower(contentType)
+	if _, ok := videoExtensions[contentType]; !ok {
+		return nil, domain.ErrUnsupportedVideo
+	}
+	if s.limits.MaxBytes > 0 && int64(len(file)) > s.limits.MaxBytes {
+		return nil, domain.ErrVideoTooLarge
+	}
+	duration, err := mp4Duration(file)
+	if err != nil {
+		return nil, err
+	}
+	if s.limits.MaxDuration > 0 && duration > s.limits.MaxDuration {
+		return nil, domain.ErrVideoTooLong
+	}
+
+	if s.transcoder != nil {
+		if file, contentType, err = s.transcoder.Transcode(ctx, file, contentType); err != nil {
+			return nil, fmt.Errorf("failed to transcode video: %w", err)
+		}
+	} else {
+		width, height, err := mp4Dimensions(file)
+		if err != nil {
+			return nil, err
+		}
+		if max(width, height) > domain.MaxDemoVideoLongSide || min(width, height) > domain.MaxDemoVideoShortSide {
+			return nil, domain.ErrVideoResolution
+		}
+	}
+
+	ext, ok := videoExtensions[contentType]
+	if !ok {
+		ext = ".mp4"
+	}
+	now := s.clock.Now()
+	url, err := s.fileRepo.Upload(ctx, file, fmt.Sprintf("exercises/%s/%d%s", ex.ID, now.UnixNano(), ext), contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := ex.Video
+	ex.Video = &domain.ExerciseVideo{
+		URL:             url,
+		ContentType:     contentType,
+		SizeBytes:       int64(len(file)),
+		DurationSeconds: duration.Seconds(),
+		Transcoded:      s.transcoder != nil,
+		UploadedBy:      uploaderID,
+		UploadedAt:      now,
+	}
+	if err := s.exerciseRepo.SetVideo(ctx, ex.ID, ex.Video); err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		s.deleteFile(ctx, ex.ID, previous)
+	}
+	s.Present(ctx, ex)
+	return ex, nil
+}
+
+// Remove deletes the exercise's hosted clip, leaving its VideoURL to be served
+func (s *ExerciseVideoService) Remove(ctx context.Context, exerciseID string) (*domain.Exercise, error) {
+	ex, err := s.exerciseRepo.GetByID(ctx, exerciseID)
+	if err != nil {
+		return nil, err
+	}
+	if ex.Video == nil {
+		return nil, domain.ErrSetVideoNotFound
+	}
+	if err := s.exerciseRepo.SetVideo(ctx, ex.ID, nil); err != nil {
+		return nil, err
+	}
+	s.deleteFile(ctx, ex.ID, ex.Video)
+	ex.Video = nil
+	s.Present(ctx, ex)
+	return ex, nil
+}
+
+// Present sets the PlaybackURL of each exercise: a signed URL of its hosted clip, or its
+// VideoURL when it has none or signing fails
+func (s *ExerciseVideoService) Present(ctx context.Context, exercises ...*domain.Exercise) {
+	for _, ex := range exercises {
+		ex.PlaybackURL = ex.VideoURL
+		if ex.Video == nil || s.fileRepo == nil {
+			continue
+		}
+		url, err := s.fileRepo.SignedURL(ctx, ex.Video.URL, exerciseVideoURLTTL)
+		if err != nil {
+			log.Printf("Warning: failed to sign video of exercise %s, serving its video URL: %v", ex.ID, err)
+			continue
+		}
+		ex.PlaybackURL = url
+	}
+}
+
+// PlaybackURL returns where to play the exercise's demo, or ErrSetVideoNotFound if it has none
+func (s *ExerciseVideoService) PlaybackURL(ctx context.Context, exerciseID string) (string, error) {
+	ex, err := s.exerciseRepo.GetByID(ctx, exerciseID)
+	if err != nil {
+		return "", err
+	}
+	s.Present(ctx, ex)
+	if ex.PlaybackURL == "" {
+		return "", domain.ErrSetVideoNotFound
+	}
+	return ex.PlaybackURL, nil
+}
+
+func (s *ExerciseVideoService) deleteFile(ctx context.Context, exerciseID string, video *domain.ExerciseVideo) {
+	if err := s.fileRepo.Delete(ctx, video.URL); err != nil {
+		log.Printf("Warning: video of exercise %s replaced but its file remains: %v", exerciseID, err)
+	}
+}
+
+// mp4Dimensions returns the largest picture size in the track headers (moov/trak/tkhd) of
+// an MP4 or QuickTime file. Audio tracks report 0x0.
+func mp4Dimensions(data []byte) (width, height int, err error) {
+	moov, ok := findBox(data, "moov")
+	if !ok {
+		return 0, 0, domain.ErrUnsupportedVideo
+	}
+	for rest := moov; ; {
+		var trak []byte
+		if trak, rest, ok = nextBox(rest, "trak"); !ok {
+			break
+		}
+		tkhd, ok := findBox(trak, "tkhd")
+		if !ok || len(tkhd) < 84 {
+			return 0, 0, domain.ErrUnsupportedVideo
+		}
+		// Width and height close the header as 16.16 fixed-point numbers
+		w := int(binary.BigEndian.Uint32(tkhd[len(tkhd)-8:]) >> 16)
+		h := int(binary.BigEndian.Uint32(tkhd[len(tkhd)-4:]) >> 16)
+		width, height = max(width, w), max(height, h)
+	}
+	if width == 0 || height == 0 {
+		return 0, 0, domain.ErrUnsupportedVideo
+	}
+	return width, height, nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testSizedMP4 is testMP4 with a video track of the given size
+func testSizedMP4(seconds uint32, width, height uint32) []byte {
+	mvhd := make([]byte, 20)
+	binary.BigEndian.PutUint32(mvhd[12:16], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:20], seconds*1000)
+	tkhd := make([]byte, 84) // Version 0 header
+	binary.BigEndian.PutUint32(tkhd[76:80], width<<16)
+	binary.BigEndian.PutUint32(tkhd[80:84], height<<16)
+	audio := box("trak", box("tkhd", make([]byte, 84)))
+	return append(box("ftyp", []byte("isom")), box("moov", box("mvhd", mvhd), audio, box("trak", box("tkhd", tkhd)))...)
+}
+
+func TestMP4Dimensions(t *testing.T) {
+	w, h, err := mp4Dimensions(testSizedMP4(10, 1080, 1920))
+	require.NoError(t, err)
+	assert.Equal(t, []int{1080, 1920}, []int{w, h})
+
+	_, _, err = mp4Dimensions(testMP4(10))
+	assert.ErrorIs(t, err, domain.ErrUnsupportedVideo)
+}
+
+func TestExerciseVideoService_Upload(t *testing.T) {
+	ctx := context.Background()
+	limits := VideoLimits{MaxBytes: 1 << 20, MaxDuration: time.Minute}
+
+	newService := func(t *testing.T, transcoder domain.VideoTranscoder) (*ExerciseVideoService, *mocks.ExerciseRepository, *mocks.FileRepository) {
+		exercises, files := mocks.NewExerciseRepository(t), mocks.NewFileRepository(t)
+		return NewExerciseVideoService(exercises, files, transcoder, limits, clock.NewFake(testNow)), exercises, files
+	}
+
+	t.Run("stores the clip and replaces the previous one", func(t *testing.T) {
+		svc, exercises, files := newService(t, nil)
+		exercises.On("GetByID", ctx, "ex-1").Return(&domain.Exercise{ID: "ex-1", VideoURL: "https://youtu.be/x",
+			Video: &domain.ExerciseVideo{URL: "https://files/old.mp4"}}, nil)
+		files.On("Upload", ctx, mock.Anything, "exercises/ex-1/1750068000000000000.mp4", "video/mp4").Return("https://files/new.mp4", nil)
+		exercises.On("SetVideo", ctx, "ex-1", mock.MatchedBy(func(v *domain.ExerciseVideo) bool {
+			return v.URL == "https://files/new.mp4" && v.DurationSeconds == 20 && !v.Transcoded && v.UploadedBy == "coach-1"
+		})).Return(nil)
+		files.On("Delete", ctx, "https://files/old.mp4").Return(errors.New("gone")) // Only logged
+		files.On("SignedURL", ctx, "https://files/new.mp4", exerciseVideoURLTTL).Return("https://files/new.mp4?sig", nil)
+
+		ex, err := svc.Upload(ctx, "coach-1", "ex-1", testSizedMP4(20, 1920, 1080), "video/mp4")
+
+		require.NoError(t, err)
+		assert.Equal(t, "https://files/new.mp4?sig", ex.PlaybackURL)
+	})
+
+	t.Run("rejects clips over 1080p without a transcoder", func(t *testing.T) {
+		svc, exercises, _ := newService(t, nil)
+		exercises.On("GetByID", ctx, "ex-1").Return(&domain.Exercise{ID: "ex-1"}, nil)
+
+		_, err := svc.Upload(ctx, "coach-1", "ex-1", testSizedMP4(20, 3840, 2160), "video/mp4")
+
+		assert.ErrorIs(t, err, domain.ErrVideoResolution)
+	})
+
+	t.Run("stores what the transcoder returns", func(t *testing.T) {
+		transcoder := mocks.NewVideoTranscoder(t)
+		svc, exercises, files := newService(t, transcoder)
+		exercises.On("GetByID", ctx, "ex-1").Return(&domain.Exercise{ID: "ex-1"}, nil)
+		transcoder.On("Transcode", ctx, mock.Anything, "video/quicktime").Return([]byte("h264"), "video/mp4", nil)
+		files.On("Upload", ctx, []byte("h264"), "exercises/ex-1/1750068000000000000.mp4", "video/mp4").Return("https://files/new.mp4", nil)
+		exercises.On("SetVideo", ctx, "ex-1", mock.MatchedBy(func(v *domain.ExerciseVideo) bool {
+			return v.Transcoded && v.SizeBytes == 4 && v.ContentType == "video/mp4"
+		})).Return(nil)
+		files.On("SignedURL", ctx, "https://files/new.mp4", exerciseVideoURLTTL).Return("https://files/new.mp4?sig", nil)
+
+		_, err := svc.Upload(ctx, "coach-1", "ex-1", testSizedMP4(20, 3840, 2160), "video/quicktime")
+
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects clips over the duration limit", func(t *testing.T) {
+		svc, exercises, _ := newService(t, nil)
+		exercises.On("GetByID", ctx, "ex-1").Return(&domain.Exercise{ID: "ex-1"}, nil)
+
+		_, err := svc.Upload(ctx, "coach-1", "ex-1", testSizedMP4(90, 1280, 720), "video/mp4")
+
+		assert.ErrorIs(t, err, domain.ErrVideoTooLong)
+	})
+}
+
+func TestExerciseVideoService_Present(t *testing.T) {
+	ctx := context.Background()
+	files := mocks.NewFileRepository(t)
+	svc := NewExerciseVideoService(mocks.NewExerciseRepository(t), files, nil, VideoLimits{}, clock.NewFake(testNow))
+
+	hosted := &domain.Exercise{ID: "ex-1", VideoURL: "https://youtu.be/a", Video: &domain.ExerciseVideo{URL: "https://files/a.mp4"}}
+	unsigned := &domain.Exercise{ID: "ex-2", VideoURL: "https://youtu.be/b", Video: &domain.ExerciseVideo{URL: "https://files/b.mp4"}}
+	linked := &domain.Exercise{ID: "ex-3", VideoURL: "https://youtu.be/c"}
+	files.On("SignedURL", ctx, "https://files/a.mp4", exerciseVideoURLTTL).Return("https://files/a.mp4?sig", nil)
+	files.On("SignedURL", ctx, "https://files/b.mp4", exerciseVideoURLTTL).Return("", errors.New("s3 down"))
+
+	svc.Present(ctx, hosted, unsigned, linked)
+
+	assert.Equal(t, "https://files/a.mp4?sig", hosted.PlaybackURL)
+	// Falls back to the video URL when signing fails or nothing is hosted
+	assert.Equal(t, "https://youtu.be/b", unsigned.PlaybackURL)
+	assert.Equal(t, "https://youtu.be/c", linked.PlaybackURL)
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+const formSystemPrompt = `You are an experienced strength coach reviewing a member's exercise technique from still frames of a short video. Only comment on what is visible in the frames; if the view is unclear, say so instead of guessing. Return only valid JSON.`
+
+const formUserPrompt = `These %d frames are taken in order from one set of %s. Each frame is labelled with its time in the video.
+
+Review the technique and return JSON in this EXACT format:
+{
+  "summary": "1-2 sentences on the overall quality of the set",
+  "observations": [
+    {"issue": "what you see, e.g. knees caving in at the bottom", "cue": "a short coaching cue to fix it", "severity": "info|minor|major", "at_seconds": 0.0}
+  ]
+}
+
+Use "major" only for faults that risk injury. List at most 5 observations, most important first. If the form looks good, say so in the summary and return an empty observations list.`
+
+// OpenRouterFormAnalyzer implements domain.FormAnalyzer with a vision model on OpenRouter
+type OpenRouterFormAnalyzer struct {
+	client *openRouterClient
+}
+
+func NewOpenRouterFormAnalyzer(apiKey, model string) *OpenRouterFormAnalyzer {
+	return &OpenRouterFormAnalyzer{client: newOpenRouterClient(apiKey, model, "HOM Gym Form Check")}
+}
+
+func (a *OpenRouterFormAnalyzer) AnalyzeForm(ctx context.Context, exercise string, frames []domain.VideoFrame) (*domain.FormFeedback, error) {
+	if exercise == "" {
+		exercise = "an exercise"
+	}
+	content := []map[string]interface{}{
+		{"type": "text", "text": fmt.Sprintf(formUserPrompt, len(frames), exercise)},
+	}
+	for _, f := range frames {
+		content = append(content,
+			map[string]interface{}{"type": "text", "text": fmt.Sprintf("Frame at %.1fs:", f.AtSeconds)},
+			imagePart("image/jpeg", f.Image),
+		)
+	}
+
+	reply, err := a.client.complete(ctx, []map[string]interface{}{
+		{"role": "system", "content": formSystemPrompt},
+		{"role": "user", "content": content},
+	}, 0.2)
+	if err != nil {
+		return nil, err
+	}
+
+	feedback, err := parseFormFeedback(reply)
+	if err != nil {
+		return nil, err
+	}
+	feedback.Model = a.client.model
+	return feedback, nil
+}
+
+// parseFormFeedback reads the model's JSON, tolerating prose or code fences around it
+func parseFormFeedback(reply string) (*domain.FormFeedback, error) {
+	start, end := strings.IndexByte(reply, '{'), strings.LastIndexByte(reply, '}')
+	if start == -1 || end <= start {
+		return nil, fmt.Errorf("no JSON object found in AI response")
+	}
+	var feedback domain.FormFeedback
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &feedback); err != nil {
+		return nil, fmt.Errorf("failed to parse AI response as JSON: %w", err)
+	}
+	for i := range feedback.Observations {
+		switch feedback.Observations[i].Severity {
+		case "info", "minor", "major":
+		default:
+			feedback.Observations[i].Severity = "info"
+		}
+	}
+	return &feedback, nil
+}
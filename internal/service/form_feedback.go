@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// formFeedbackFrames is how many stills of a clip the model sees. A rep of most lifts
+// takes a few seconds, so six frames over a short clip catch each phase at least once.
+const formFeedbackFrames = 6
+
+// FormFeedbackService queues form-check videos for AI review and runs the reviews. It is
+// only wired up when the platform has frame extraction available; tenants then opt in
+// through their AI settings and each review counts against their monthly AI quota.
+type FormFeedbackService struct {
+	videoRepo    domain.SetVideoRepository
+	setLogRepo   domain.SetLogRepository
+	exerciseRepo domain.ExerciseRepository
+	tenantRepo   domain.TenantRepository
+	usageRepo    domain.AIUsageRepository
+	extractor    domain.FrameExtractor
+	analyzer     domain.FormAnalyzer
+	guardians    *GuardianService // Optional: see RestrictMinors
+	clock        domain.Clock
+}
+
+func NewFormFeedbackService(
+	videoRepo domain.SetVideoRepository,
+	setLogRepo domain.SetLogRepository,
+	exerciseRepo domain.ExerciseRepository,
+	tenantRepo domain.TenantRepository,
+	usageRepo domain.AIUsageRepository,
+	extractor domain.FrameExtractor,
+	analyzer domain.FormAnalyzer,
+	clk domain.Clock,
+) *FormFeedbackService {
+	return &FormFeedbackService{
+		videoRepo:    videoRepo,
+		setLogRepo:   setLogRepo,
+		exerciseRepo: exerciseRepo,
+		tenantRepo:   tenantRepo,
+		usageRepo:    usageRepo,
+		extractor:    extractor,
+		analyzer:     analyzer,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// RestrictMinors keeps videos of minors from being sent for AI review
+func (s *FormFeedbackService) RestrictMinors(guardians *GuardianService) {
+	s.guardians = guardians
+}
+
+// Request queues a review of a freshly uploaded video if its tenant has the feature and
+// quota left. It never fails the upload: a video without feedback is still useful.
+func (s *FormFeedbackService) Request(ctx context.Context, video *domain.SetVideo) {
+	tenant, err := s.tenantRepo.GetByID(ctx, video.TenantID)
+	if err != nil {
+		log.Printf("Warning: form feedback skipped for video %s: %v", video.ID, err)
+		return
+	}
+	if !tenant.AISettings.FormFeedback {
+		return
+	}
+	if s.guardians != nil {
+		minor, err := s.guardians.IsMinor(ctx, tenant, video.MemberID)
+		if err != nil {
+			log.Printf("Warning: form feedback skipped for video %s: %v", video.ID, err)
+			return
+		}
+		if minor {
+			return
+		}
+	}
+
+	now := s.clock.Now()
+	if err := s.usageRepo.Consume(ctx, tenant.ID, now, tenant.AISettings.MonthlyQuota); err != nil {
+		if errors.Is(err, domain.ErrAIQuotaExceeded) {
+			log.Printf("Tenant %s is out of AI quota, no form feedback for video %s", tenant.ID, video.ID)
+		} else {
+			log.Printf("Warning: form feedback skipped for video %s: %v", video.ID, err)
+		}
+		return
+	}
+
+	feedback := &domain.FormFeedback{Status: domain.FormFeedbackPending, RequestedAt: now}
+	if err := s.videoRepo.SetFormFeedback(ctx, video.ID, feedback); err != nil {
+		log.Printf("Warning: failed to queue form feedback for video %s: %v", video.ID, err)
+		return
+	}
+	video.FormFeedback = feedback
+}
+
+// AnalyzePending reviews up to limit queued videos and returns how many got feedback. A
+// video that can't be analyzed is marked failed rather than retried forever; its quota
+// is already spent.
+func (s *FormFeedbackService) AnalyzePending(ctx context.Context, limit int) (int, error) {
+	videos, err := s.videoRepo.ListPendingFeedback(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	analyzed := 0
+	for _, video := range videos {
+		if ctx.Err() != nil {
+			return analyzed, ctx.Err()
+		}
+		feedback, err := s.analyze(ctx, video)
+		if err != nil {
+			log.Printf("Warning: form feedback for video %s failed: %v", video.ID, err)
+			feedback = &domain.FormFeedback{Status: domain.FormFeedbackFailed, Error: "The video could not be analyzed"}
+		} else {
+			feedback.Status = domain.FormFeedbackDone
+			analyzed++
+		}
+		now := s.clock.Now()
+		feedback.RequestedAt = video.FormFeedback.RequestedAt
+		feedback.AnalyzedAt = &now
+		if err := s.videoRepo.SetFormFeedback(ctx, video.ID, feedback); err != nil {
+			return analyzed, err
+		}
+	}
+	return analyzed, nil
+}
+
+func (s *FormFeedbackService) analyze(ctx context.Context, video *domain.SetVideo) (*domain.FormFeedback, error) {
+	exercise := ""
+	if setLog, err := s.setLogRepo.GetByID(ctx, video.SetLogID); err == nil {
+		if ex, err := s.exerciseRepo.GetByID(ctx, setLog.ExerciseID); err == nil {
+			exercise = ex.Name
+		}
+	}
+
+	frames, err := s.extractor.ExtractFrames(ctx, video.URL, time.Duration(video.DurationSeconds*float64(time.Second)), formFeedbackFrames)
+	if err != nil {
+		return nil, err
+	}
+	return s.analyzer.AnalyzeForm(ctx, exercise, frames)
+}
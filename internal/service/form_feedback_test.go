@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type formFeedbackMocks struct {
+	videos    *mocks.SetVideoRepository
+	setLogs   *mocks.SetLogRepository
+	exercises *mocks.ExerciseRepository
+	tenants   *mocks.TenantRepository
+	usage     *mocks.AIUsageRepository
+	extractor *mocks.FrameExtractor
+	analyzer  *mocks.FormAnalyzer
+}
+
+func newTestFormFeedbackService(t *testing.T) (*FormFeedbackService, formFeedbackMocks) {
+	m := formFeedbackMocks{
+		videos:    mocks.NewSetVideoRepository(t),
+		setLogs:   mocks.NewSetLogRepository(t),
+		exercises: mocks.NewExerciseRepository(t),
+		tenants:   mocks.NewTenantRepository(t),
+		usage:     mocks.NewAIUsageRepository(t),
+		extractor: mocks.NewFrameExtractor(t),
+		analyzer:  mocks.NewFormAnalyzer(t),
+	}
+	svc := NewFormFeedbackService(m.videos, m.setLogs, m.exercises, m.tenants, m.usage, m.extractor, m.analyzer, clock.NewFake(testNow))
+	return svc, m
+}
+
+func TestFormFeedbackService_Request(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("tenant without the feature gets nothing queued", func(t *testing.T) {
+		svc, m := newTestFormFeedbackService(t)
+		m.tenants.On("GetByID", ctx, "gym").Return(&domain.Tenant{ID: "gym"}, nil)
+
+		video := &domain.SetVideo{ID: "v1", TenantID: "gym"}
+		svc.Request(ctx, video)
+
+		assert.Nil(t, video.FormFeedback)
+	})
+
+	t.Run("exhausted quota skips the review", func(t *testing.T) {
+		svc, m := newTestFormFeedbackService(t)
+		m.tenants.On("GetByID", ctx, "gym").Return(&domain.Tenant{ID: "gym", AISettings: domain.AISettings{FormFeedback: true, MonthlyQuota: 10}}, nil)
+		m.usage.On("Consume", ctx, "gym", testNow, 10).Return(domain.ErrAIQuotaExceeded)
+
+		video := &domain.SetVideo{ID: "v1", TenantID: "gym"}
+		svc.Request(ctx, video)
+
+		assert.Nil(t, video.FormFeedback)
+	})
+
+	t.Run("queues the review and spends quota", func(t *testing.T) {
+		svc, m := newTestFormFeedbackService(t)
+		m.tenants.On("GetByID", ctx, "gym").Return(&domain.Tenant{ID: "gym", AISettings: domain.AISettings{FormFeedback: true}}, nil)
+		m.usage.On("Consume", ctx, "gym", testNow, 0).Return(nil)
+		m.videos.On("SetFormFeedback", ctx, "v1", &domain.FormFeedback{Status: domain.FormFeedbackPending, RequestedAt: testNow}).Return(nil)
+
+		video := &domain.SetVideo{ID: "v1", TenantID: "gym"}
+		svc.Request(ctx, video)
+
+		require.NotNil(t, video.FormFeedback)
+		assert.Equal(t, domain.FormFeedbackPending, video.FormFeedback.Status)
+	})
+}
+
+func TestFormFeedbackService_AnalyzePending(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestFormFeedbackService(t)
+	requested := testNow.Add(-time.Minute)
+
+	m.videos.On("ListPendingFeedback", ctx, 5).Return([]*domain.SetVideo{
+		{ID: "good", SetLogID: "set-1", URL: "https://files/good.mp4", DurationSeconds: 7, FormFeedback: &domain.FormFeedback{Status: domain.FormFeedbackPending, RequestedAt: requested}},
+		{ID: "broken", SetLogID: "set-2", URL: "https://files/broken.mp4", DurationSeconds: 3, FormFeedback: &domain.FormFeedback{Status: domain.FormFeedbackPending, RequestedAt: requested}},
+	}, nil)
+	m.setLogs.On("GetByID", ctx, "set-1").Return(&domain.SetLogDocument{ID: "set-1", ExerciseID: "squat"}, nil)
+	m.setLogs.On("GetByID", ctx, "set-2").Return(nil, domain.ErrSessionNotFound)
+	m.exercises.On("GetByID", ctx, "squat").Return(&domain.Exercise{ID: "squat", Name: "Back Squat"}, nil)
+
+	frames := []domain.VideoFrame{{AtSeconds: 1, Image: []byte{0xFF, 0xD8}}}
+	m.extractor.On("ExtractFrames", ctx, "https://files/good.mp4", 7*time.Second, formFeedbackFrames).Return(frames, nil)
+	m.extractor.On("ExtractFrames", ctx, "https://files/broken.mp4", 3*time.Second, formFeedbackFrames).Return(nil, errors.New("moov atom not found"))
+	m.analyzer.On("AnalyzeForm", ctx, "Back Squat", frames).Return(&domain.FormFeedback{
+		Summary:      "Solid depth",
+		Observations: []domain.FormObservation{{Issue: "Heels lift", Cue: "Sit back", Severity: "minor"}},
+	}, nil)
+
+	saved := map[string]*domain.FormFeedback{}
+	m.videos.On("SetFormFeedback", ctx, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		saved[args.String(1)] = args.Get(2).(*domain.FormFeedback)
+	}).Return(nil)
+
+	analyzed, err := svc.AnalyzePending(ctx, 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, analyzed)
+	assert.Equal(t, domain.FormFeedbackDone, saved["good"].Status)
+	assert.Equal(t, "Solid depth", saved["good"].Summary)
+	assert.Equal(t, requested, saved["good"].RequestedAt)
+	assert.Equal(t, domain.FormFeedbackFailed, saved["broken"].Status)
+	assert.NotNil(t, saved["broken"].AnalyzedAt)
+}
+
+func TestParseFormFeedback(t *testing.T) {
+	reply := "Here is the review:\n```json\n{\"summary\": \"Good\", \"observations\": [{\"issue\": \"Rounded back\", \"cue\": \"Chest up\", \"severity\": \"critical\"}]}\n```"
+
+	feedback, err := parseFormFeedback(reply)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Good", feedback.Summary)
+	require.Len(t, feedback.Observations, 1)
+	assert.Equal(t, "info", feedback.Observations[0].Severity, "unknown severities fall back to info")
+
+	_, err = parseFormFeedback("I can't see the lifter")
+	assert.Error(t, err)
+}
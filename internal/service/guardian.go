@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// GuardianService applies a tenant's age restrictions. Members under its minor age (going by
+// their date of birth) can't be booked until the gym records a guardian's consent, and their
+// data is kept away from AI features.
+type GuardianService struct {
+	repo       domain.GuardianConsentRepository
+	userRepo   domain.UserRepository
+	tenantRepo domain.TenantRepository
+	clock      domain.Clock
+}
+
+func NewGuardianService(repo domain.GuardianConsentRepository, userRepo domain.UserRepository, tenantRepo domain.TenantRepository, clk domain.Clock) *GuardianService {
+	return &GuardianService{
+		repo:       repo,
+		userRepo:   userRepo,
+		tenantRepo: tenantRepo,
+		clock:      clock.OrReal(clk),
+	}
+}
+
+// Status returns the member's age restrictions in tenantID
+func (s *GuardianService) Status(ctx context.Context, tenantID, memberID string) (*domain.MinorStatus, error) {
+	member, tenant, err := s.load(ctx, tenantID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := s.latest(ctx, tenantID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.BuildMinorStatus(member, tenant, latest, s.clock.Now()), nil
+}
+
+// RecordConsent records a guardian's consent for consent.MemberID in consent.TenantID.
+// Consent can be recorded before a date of birth is, so it needn't be asked again later.
+func (s *GuardianService) RecordConsent(ctx context.Context, consent *domain.GuardianConsent) (*domain.MinorStatus, error) {
+	if err := consent.Validate(); err != nil {
+		return nil, err
+	}
+	member, tenant, err := s.load(ctx, consent.TenantID, consent.MemberID)
+	if err != nil {
+		return nil, err
+	}
+	consent.Granted = true
+	consent.RecordedAt = s.clock.Now()
+	if err := s.repo.Record(ctx, consent); err != nil {
+		return nil, err
+	}
+	return domain.BuildMinorStatus(member, tenant, consent, consent.RecordedAt), nil
+}
+
+// Revoke records the guardian withdrawing their consent, with the guardian's details
+// carried over from the consent being revoked. ErrNotFound if none was recorded.
+func (s *GuardianService) Revoke(ctx context.Context, tenantID, memberID, actorID string) (*domain.MinorStatus, error) {
+	member, tenant, err := s.load(ctx, tenantID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := s.repo.GetLatest(ctx, tenantID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	revocation := *latest
+	revocation.ID = ""
+	revocation.Granted = false
+	revocation.Note = ""
+	revocation.RecordedBy = actorID
+	revocation.RecordedAt = s.clock.Now()
+	if err := s.repo.Record(ctx, &revocation); err != nil {
+		return nil, err
+	}
+	return domain.BuildMinorStatus(member, tenant, &revocation, revocation.RecordedAt), nil
+}
+
+// CheckBookingAllowed returns ErrGuardianConsentRequired if the member is a minor in
+// tenantID without a guardian's consent
+func (s *GuardianService) CheckBookingAllowed(ctx context.Context, tenantID, memberID string) error {
+	status, err := s.Status(ctx, tenantID, memberID)
+	if err != nil {
+		return fmt.Errorf("failed to check guardian consent: %w", err)
+	}
+	if status.ConsentRequired {
+		return domain.ErrGuardianConsentRequired
+	}
+	return nil
+}
+
+// IsMinor reports whether the member is a minor in tenant, whose data AI features must skip
+func (s *GuardianService) IsMinor(ctx context.Context, tenant *domain.Tenant, memberID string) (bool, error) {
+	member, err := s.userRepo.GetByID(ctx, memberID)
+	if err != nil {
+		return false, err
+	}
+	return member.IsMinorIn(tenant, s.clock.Now()), nil
+}
+
+// MinorCheck returns a function telling which users are minors in tenantID, for flagging
+// members in lists without loading the tenant for each
+func (s *GuardianService) MinorCheck(ctx context.Context, tenantID string) (func(*domain.User) bool, error) {
+	tenant, err := s.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.clock.Now()
+	return func(u *domain.User) bool { return u.IsMinorIn(tenant, now) }, nil
+}
+
+// MinorAge returns the age below which the tenant's members are minors
+func (s *GuardianService) MinorAge(ctx context.Context, tenantID string) (int, error) {
+	tenant, err := s.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+	return tenant.MinorAgeLimit(), nil
+}
+
+// SetMinorAge sets the age below which the tenant's members are minors; 0 restores the default
+func (s *GuardianService) SetMinorAge(ctx context.Context, tenantID string, age int) (*domain.Tenant, error) {
+	if err := domain.ValidateMinorAge(age); err != nil {
+		return nil, err
+	}
+	tenant, err := s.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	tenant.MinorAge = age
+	if err := s.tenantRepo.Update(ctx, tenant); err != nil {
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// load returns the member and tenant, or ErrNotTenantMember unless the member belongs to it
+func (s *GuardianService) load(ctx context.Context, tenantID, memberID string) (*domain.User, *domain.Tenant, error) {
+	member, err := s.userRepo.GetByID(ctx, memberID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tenantID == "" {
+		return nil, nil, domain.ErrNotTenantMember
+	}
+	if _, err := member.ScopedTo(tenantID); err != nil {
+		return nil, nil, err
+	}
+	tenant, err := s.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return member, tenant, nil
+}
+
+// latest returns the member's latest consent record, or nil if none
+func (s *GuardianService) latest(ctx context.Context, tenantID, memberID string) (*domain.GuardianConsent, error) {
+	latest, err := s.repo.GetLatest(ctx, tenantID, memberID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	return latest, err
+}
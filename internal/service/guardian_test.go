@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuardianService(t *testing.T) {
+	ctx := context.Background()
+	dob := testNow.AddDate(-15, 0, 0)
+	minor := &domain.User{ID: "m1", TenantID: "t1", DateOfBirth: &dob}
+	tenant := &domain.Tenant{ID: "t1"}
+
+	newService := func(t *testing.T) (*GuardianService, *mocks.GuardianConsentRepository, *mocks.UserRepository, *mocks.TenantRepository) {
+		repo, users, tenants := mocks.NewGuardianConsentRepository(t), mocks.NewUserRepository(t), mocks.NewTenantRepository(t)
+		return NewGuardianService(repo, users, tenants, clock.NewFake(testNow)), repo, users, tenants
+	}
+
+	t.Run("minors can't be booked without a guardian's consent", func(t *testing.T) {
+		svc, repo, users, tenants := newService(t)
+		users.On("GetByID", ctx, "m1").Return(minor, nil)
+		tenants.On("GetByID", ctx, "t1").Return(tenant, nil)
+		repo.On("GetLatest", ctx, "t1", "m1").Return(nil, domain.ErrNotFound).Once()
+
+		assert.ErrorIs(t, svc.CheckBookingAllowed(ctx, "t1", "m1"), domain.ErrGuardianConsentRequired)
+
+		repo.On("GetLatest", ctx, "t1", "m1").Return(&domain.GuardianConsent{Granted: true}, nil).Once()
+		assert.NoError(t, svc.CheckBookingAllowed(ctx, "t1", "m1"))
+	})
+
+	t.Run("adults and members without a date of birth can be booked", func(t *testing.T) {
+		svc, repo, users, tenants := newService(t)
+		users.On("GetByID", ctx, "a1").Return(&domain.User{ID: "a1", TenantID: "t1"}, nil)
+		tenants.On("GetByID", ctx, "t1").Return(tenant, nil)
+		repo.On("GetLatest", ctx, "t1", "a1").Return(nil, domain.ErrNotFound)
+
+		assert.NoError(t, svc.CheckBookingAllowed(ctx, "t1", "a1"))
+	})
+
+	t.Run("consent is validated and recorded for members of the tenant", func(t *testing.T) {
+		svc, repo, users, tenants := newService(t)
+		_, err := svc.RecordConsent(ctx, &domain.GuardianConsent{TenantID: "t1", MemberID: "m1", GuardianName: "Jane"})
+		assert.ErrorIs(t, err, domain.ErrInvalidGuardianConsent)
+
+		users.On("GetByID", ctx, "m1").Return(minor, nil)
+		_, err = svc.RecordConsent(ctx, &domain.GuardianConsent{TenantID: "t2", MemberID: "m1", GuardianName: "Jane", Relationship: "mother", Phone: "0812"})
+		assert.ErrorIs(t, err, domain.ErrNotTenantMember)
+
+		tenants.On("GetByID", ctx, "t1").Return(tenant, nil)
+		repo.On("Record", ctx, mock.MatchedBy(func(c *domain.GuardianConsent) bool {
+			return c.Granted && c.RecordedBy == "coach1" && c.RecordedAt.Equal(testNow)
+		})).Return(nil)
+		status, err := svc.RecordConsent(ctx, &domain.GuardianConsent{
+			TenantID: "t1", MemberID: "m1", GuardianName: "Jane", Relationship: "mother", Phone: "0812", RecordedBy: "coach1",
+		})
+		require.NoError(t, err)
+		assert.True(t, status.Minor)
+		assert.False(t, status.ConsentRequired)
+	})
+
+	t.Run("revoking keeps the guardian's details", func(t *testing.T) {
+		svc, repo, users, tenants := newService(t)
+		users.On("GetByID", ctx, "m1").Return(minor, nil)
+		tenants.On("GetByID", ctx, "t1").Return(tenant, nil)
+		repo.On("GetLatest", ctx, "t1", "m1").Return(&domain.GuardianConsent{
+			ID: "g1", TenantID: "t1", MemberID: "m1", GuardianName: "Jane", Relationship: "mother", Granted: true,
+			RecordedAt: testNow.Add(-24 * time.Hour),
+		}, nil)
+		repo.On("Record", ctx, mock.MatchedBy(func(c *domain.GuardianConsent) bool {
+			return !c.Granted && c.ID == "" && c.GuardianName == "Jane" && c.RecordedBy == "admin1"
+		})).Return(nil)
+
+		status, err := svc.Revoke(ctx, "t1", "m1", "admin1")
+		require.NoError(t, err)
+		assert.True(t, status.ConsentRequired)
+	})
+
+	t.Run("the minor age is validated", func(t *testing.T) {
+		svc, _, _, _ := newService(t)
+		_, err := svc.SetMinorAge(ctx, "t1", 25)
+		assert.ErrorIs(t, err, domain.ErrInvalidMinorAge)
+	})
+}
@@ -0,0 +1,515 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/jobs"
+)
+
+const (
+	gymImportJobType      = "gym-import"
+	gymImportPreviewRows  = 5
+	gymImportListLimit    = 50
+	gymImportSaveInterval = 200 // Schedules between progress saves
+)
+
+// GymImportRequest is an upload of another system's export files
+type GymImportRequest struct {
+	TenantID string
+	BranchID string // Defaults to the coach's home branch
+	CoachID  string
+	ActorID  string
+	Source   string
+	Files    map[string][]byte // By domain.ImportFile*
+	Location *time.Location    // Zone of the exported dates
+}
+
+// GymImportService migrates a tenant's members, memberships and bookings from another gym
+// management system. Uploading the exports only validates them into a preview; the
+// import runs in the background once the preview is started.
+type GymImportService struct {
+	repo      domain.GymImportRepository
+	userRepo  domain.UserRepository
+	pkgRepo   domain.PTPackageRepository
+	schedRepo domain.ScheduleRepository
+	pt        *PTService
+	runs      *jobs.Runner // Optional: records runs in the job history and retries failed ones
+	adapters  map[string]domain.GymImportAdapter
+	clock     domain.Clock
+}
+
+func NewGymImportService(
+	repo domain.GymImportRepository,
+	userRepo domain.UserRepository,
+	pkgRepo domain.PTPackageRepository,
+	schedRepo domain.ScheduleRepository,
+	pt *PTService,
+	runs *jobs.Runner,
+	clk domain.Clock,
+	adapters ...domain.GymImportAdapter,
+) *GymImportService {
+	s := &GymImportService{
+		repo:      repo,
+		userRepo:  userRepo,
+		pkgRepo:   pkgRepo,
+		schedRepo: schedRepo,
+		pt:        pt,
+		runs:      runs,
+		adapters:  make(map[string]domain.GymImportAdapter, len(adapters)),
+		clock:     clock.OrReal(clk),
+	}
+	for _, a := range adapters {
+		s.adapters[a.Source()] = a
+	}
+	if runs != nil {
+		// Progress is kept per row, so a retry continues where the failed run stopped
+		runs.Handle(gymImportJobType, func(ctx context.Context, params map[string]interface{}) error {
+			tenantID, _ := params["tenant_id"].(string)
+			id, _ := params["import_id"].(string)
+			imp, err := s.repo.GetByID(ctx, tenantID, id)
+			if err != nil {
+				return err
+			}
+			return s.run(ctx, imp)
+		})
+	}
+	return s
+}
+
+// Preview parses and validates the exports and saves them as an import to review
+func (s *GymImportService) Preview(ctx context.Context, req GymImportRequest) (*domain.GymImport, error) {
+	adapter, ok := s.adapters[strings.ToLower(req.Source)]
+	if !ok {
+		return nil, domain.ErrUnknownImportSource
+	}
+	if len(req.Files[domain.ImportFileMembers]) == 0 {
+		return nil, fmt.Errorf("%w: the members export is required", domain.ErrInvalidImportFile)
+	}
+
+	coach, err := s.importCoach(ctx, req.TenantID, req.CoachID)
+	if err != nil {
+		return nil, err
+	}
+	branchID, err := coach.ScheduleBranch(req.BranchID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	loc := req.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	data := &domain.GymImportData{}
+	var issues []domain.GymImportIssue
+	for _, file := range []string{domain.ImportFileMembers, domain.ImportFileMemberships, domain.ImportFileBookings} {
+		content := req.Files[file]
+		if len(content) == 0 {
+			continue
+		}
+		parsed, fileIssues, err := adapter.Parse(file, content, loc)
+		if err != nil {
+			return nil, err
+		}
+		data.Members = append(data.Members, parsed.Members...)
+		data.Memberships = append(data.Memberships, parsed.Memberships...)
+		data.Bookings = append(data.Bookings, parsed.Bookings...)
+		issues = append(issues, fileIssues...)
+	}
+
+	imp := &domain.GymImport{
+		TenantID:  req.TenantID,
+		BranchID:  branchID,
+		CoachID:   coach.ID,
+		Source:    adapter.Source(),
+		Status:    domain.GymImportStatusPreview,
+		CreatedBy: req.ActorID,
+		CreatedAt: s.clock.Now(),
+	}
+	if err := s.validate(ctx, imp, data, issues); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, imp); err != nil {
+		return nil, err
+	}
+	imp.Preview = previewRows(imp.Data)
+	return imp, nil
+}
+
+func (s *GymImportService) importCoach(ctx context.Context, tenantID, coachID string) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, coachID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrInvalidImportCoach
+	}
+	if err != nil {
+		return nil, err
+	}
+	coach, err := user.ScopedTo(tenantID)
+	if err != nil || !coach.HasRole(domain.RoleCoach) {
+		return nil, domain.ErrInvalidImportCoach
+	}
+	return coach, nil
+}
+
+// validate keeps the rows that can be imported, in imp.Data, and records the rest as
+// issues. Memberships and bookings must belong to an imported member, and a booking's
+// member needs a membership for the session to count against.
+func (s *GymImportService) validate(ctx context.Context, imp *domain.GymImport, data *domain.GymImportData, issues []domain.GymImportIssue) error {
+	valid := &domain.GymImportData{}
+	skip := func(file, externalID, msg string) {
+		issues = append(issues, domain.GymImportIssue{File: file, ExternalID: externalID, Message: msg})
+	}
+
+	members := make(map[string]bool)
+	emails := make(map[string]bool)
+	for _, m := range data.Members {
+		switch {
+		case members[m.ExternalID]:
+			skip(domain.ImportFileMembers, m.ExternalID, "duplicate member ID")
+			continue
+		case emails[m.Email]:
+			skip(domain.ImportFileMembers, m.ExternalID, "email "+m.Email+" is used by another member in the export")
+			continue
+		}
+
+		existing, err := s.userRepo.GetByEmail(ctx, m.Email)
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			imp.Summary.NewMembers++
+		case err != nil:
+			return err
+		default:
+			if _, err := existing.ScopedTo(imp.TenantID); err != nil {
+				skip(domain.ImportFileMembers, m.ExternalID, "email "+m.Email+" belongs to a user of another gym")
+				continue
+			}
+			imp.Summary.ExistingMembers++
+		}
+		members[m.ExternalID] = true
+		emails[m.Email] = true
+		valid.Members = append(valid.Members, m)
+	}
+
+	withMembership := make(map[string]bool)
+	memberships := make(map[string]bool)
+	for _, m := range data.Memberships {
+		switch {
+		case !members[m.MemberExternalID]:
+			skip(domain.ImportFileMemberships, m.ExternalID, "member "+m.MemberExternalID+" is not in the members export")
+		case memberships[m.ExternalID]:
+			skip(domain.ImportFileMemberships, m.ExternalID, "duplicate membership ID")
+		default:
+			memberships[m.ExternalID] = true
+			withMembership[m.MemberExternalID] = true
+			valid.Memberships = append(valid.Memberships, m)
+		}
+	}
+
+	bookings := make(map[string]bool)
+	for _, b := range data.Bookings {
+		switch {
+		case !members[b.MemberExternalID]:
+			skip(domain.ImportFileBookings, b.ExternalID, "member "+b.MemberExternalID+" is not in the members export")
+		case !withMembership[b.MemberExternalID]:
+			skip(domain.ImportFileBookings, b.ExternalID, "member "+b.MemberExternalID+" has no membership to book against")
+		case bookings[b.ExternalID]:
+			skip(domain.ImportFileBookings, b.ExternalID, "duplicate booking ID")
+		default:
+			bookings[b.ExternalID] = true
+			valid.Bookings = append(valid.Bookings, b)
+		}
+	}
+
+	imp.Data = valid
+	imp.Issues = issues
+	imp.Summary.Memberships = len(valid.Memberships)
+	imp.Summary.Bookings = len(valid.Bookings)
+	imp.Summary.Skipped = len(issues)
+	return nil
+}
+
+func previewRows(data *domain.GymImportData) *domain.GymImportData {
+	return &domain.GymImportData{
+		Members:     data.Members[:min(len(data.Members), gymImportPreviewRows)],
+		Memberships: data.Memberships[:min(len(data.Memberships), gymImportPreviewRows)],
+		Bookings:    data.Bookings[:min(len(data.Bookings), gymImportPreviewRows)],
+	}
+}
+
+// Start runs a previewed import in the background and returns it as running
+func (s *GymImportService) Start(ctx context.Context, tenantID, id string) (*domain.GymImport, error) {
+	now := s.clock.Now()
+	if err := s.repo.Start(ctx, tenantID, id, now); err != nil {
+		return nil, err
+	}
+	imp, err := s.repo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The upload request shouldn't wait for (or cancel) a migration of the whole history
+	go func() {
+		bg := context.Background()
+		if s.runs == nil {
+			_ = s.run(bg, imp)
+			return
+		}
+		params := map[string]interface{}{"tenant_id": tenantID, "import_id": id}
+		_ = s.runs.Record(bg, gymImportJobType, tenantID, params, func(ctx context.Context) error {
+			return s.run(ctx, imp)
+		})
+	}()
+	return imp, nil
+}
+
+// Get returns an import with its first rows for review
+func (s *GymImportService) Get(ctx context.Context, tenantID, id string) (*domain.GymImport, error) {
+	imp, err := s.repo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if imp.Data != nil {
+		imp.Preview = previewRows(imp.Data)
+	}
+	return imp, nil
+}
+
+func (s *GymImportService) List(ctx context.Context, tenantID string) ([]*domain.GymImport, error) {
+	return s.repo.ListByTenant(ctx, tenantID, gymImportListLimit)
+}
+
+// run imports the data and saves the outcome. Whatever was created before a failure
+// stays recorded in the progress.
+func (s *GymImportService) run(ctx context.Context, imp *domain.GymImport) error {
+	imp.Status = domain.GymImportStatusRunning
+	imp.Error = ""
+	err := s.execute(ctx, imp)
+
+	finishedAt := s.clock.Now()
+	imp.FinishedAt = &finishedAt
+	imp.Status = domain.GymImportStatusCompleted
+	if err != nil {
+		imp.Status = domain.GymImportStatusFailed
+		imp.Error = err.Error()
+	}
+	if saveErr := s.save(ctx, imp); saveErr != nil {
+		log.Printf("Warning: failed to save gym import %s outcome: %v", imp.ID, saveErr)
+	}
+	return err
+}
+
+// save stores progress even when the run's context has been cancelled
+func (s *GymImportService) save(ctx context.Context, imp *domain.GymImport) error {
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	return s.repo.Update(saveCtx, imp)
+}
+
+func (s *GymImportService) execute(ctx context.Context, imp *domain.GymImport) error {
+	if imp.Data == nil {
+		return errors.New("import has no data")
+	}
+	progress := &imp.Progress
+	if progress.Members == nil {
+		progress.Members = make(map[string]string)
+	}
+	if progress.Contracts == nil {
+		progress.Contracts = make(map[string]string)
+	}
+	if progress.Schedules == nil {
+		progress.Schedules = make(map[string]string)
+	}
+
+	if err := s.importMembers(ctx, imp); err != nil {
+		return err
+	}
+	if err := s.save(ctx, imp); err != nil {
+		return err
+	}
+	if err := s.importContracts(ctx, imp); err != nil {
+		return err
+	}
+	if err := s.save(ctx, imp); err != nil {
+		return err
+	}
+	return s.importSchedules(ctx, imp)
+}
+
+// importMembers creates members, reusing the tenant's users with the same email
+func (s *GymImportService) importMembers(ctx context.Context, imp *domain.GymImport) error {
+	for _, m := range imp.Data.Members {
+		if _, done := imp.Progress.Members[m.ExternalID]; done {
+			continue
+		}
+
+		existing, err := s.userRepo.GetByEmail(ctx, m.Email)
+		if err == nil {
+			imp.Progress.Members[m.ExternalID] = existing.ID
+			imp.Result.MembersMatched++
+			continue
+		}
+		if !errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("member %s: %w", m.ExternalID, err)
+		}
+
+		user := &domain.User{
+			Email:        m.Email,
+			Name:         m.Name,
+			Roles:        []string{domain.RoleMember},
+			TenantID:     imp.TenantID,
+			BranchAccess: []string{imp.BranchID},
+		}
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			return fmt.Errorf("member %s: %w", m.ExternalID, err)
+		}
+		imp.Progress.Members[m.ExternalID] = user.ID
+		imp.Result.MembersCreated++
+	}
+	return nil
+}
+
+// importContracts turns memberships into contracts with the import coach. Each distinct
+// membership name becomes a package, created inactive so it isn't sold before an admin
+// has reviewed its price.
+func (s *GymImportService) importContracts(ctx context.Context, imp *domain.GymImport) error {
+	existing, err := s.pkgRepo.GetByTenant(ctx, imp.TenantID)
+	if err != nil {
+		return err
+	}
+	packages := make(map[string]*domain.PTPackage, len(existing))
+	for _, pkg := range existing {
+		packages[strings.ToLower(pkg.Name)] = pkg
+	}
+
+	now := s.clock.Now()
+	for _, m := range imp.Data.Memberships {
+		if _, done := imp.Progress.Contracts[m.ExternalID]; done {
+			continue
+		}
+
+		pkg, ok := packages[strings.ToLower(m.Name)]
+		if !ok {
+			pkg = &domain.PTPackage{
+				TenantID:      imp.TenantID,
+				BranchID:      imp.BranchID,
+				Name:          m.Name,
+				TotalSessions: m.TotalSessions,
+				Price:         domain.MoneyFromMajor(m.Price, domain.DefaultCurrency),
+			}
+			if err := s.pkgRepo.Create(ctx, pkg); err != nil {
+				return fmt.Errorf("package %q: %w", m.Name, err)
+			}
+			packages[strings.ToLower(m.Name)] = pkg
+			imp.Result.PackagesCreated++
+		}
+
+		status := domain.PackageStatusActive
+		switch {
+		case m.EndDate != nil && m.EndDate.Before(now):
+			status = domain.PackageStatusExpired
+		case m.RemainingSessions == 0:
+			status = domain.PackageStatusDepleted
+		}
+		contract := &domain.PTContract{
+			TenantID:          imp.TenantID,
+			BranchID:          imp.BranchID,
+			PackageID:         pkg.ID,
+			MemberID:          imp.Progress.Members[m.MemberExternalID],
+			CoachID:           imp.CoachID,
+			TotalSessions:     m.TotalSessions,
+			RemainingSessions: m.RemainingSessions,
+			Price:             domain.MoneyFromMajor(m.Price, domain.DefaultCurrency),
+			Status:            status,
+		}
+		err := s.pt.ImportContract(ctx, contract, "Balance imported from "+imp.Source)
+		if contract.ID != "" {
+			imp.Progress.Contracts[m.ExternalID] = contract.ID
+			imp.Result.ContractsCreated++
+		}
+		if err != nil {
+			return fmt.Errorf("membership %s: %w", m.ExternalID, err)
+		}
+	}
+	return nil
+}
+
+// importSchedules books each session against the member's membership that covers its
+// date, or else their most recent one started before it. Trainers are matched to the
+// tenant's coaches by email or name; the rest go to the import coach.
+func (s *GymImportService) importSchedules(ctx context.Context, imp *domain.GymImport) error {
+	coaches, err := s.userRepo.GetByTenantAndRole(ctx, imp.TenantID, domain.RoleCoach)
+	if err != nil {
+		return err
+	}
+	coachIDs := make(map[string]string, 2*len(coaches))
+	for _, c := range coaches {
+		coachIDs[strings.ToLower(c.Email)] = c.ID
+		coachIDs[strings.ToLower(c.Name)] = c.ID
+	}
+
+	byMember := make(map[string][]domain.ImportedMembership)
+	for _, m := range imp.Data.Memberships {
+		byMember[m.MemberExternalID] = append(byMember[m.MemberExternalID], m)
+	}
+
+	sinceSave := 0
+	for _, b := range imp.Data.Bookings {
+		if _, done := imp.Progress.Schedules[b.ExternalID]; done {
+			continue
+		}
+
+		membership := bookingMembership(byMember[b.MemberExternalID], b.StartTime)
+		coachID, ok := coachIDs[strings.ToLower(b.Coach)]
+		if !ok || b.Coach == "" {
+			coachID = imp.CoachID
+		}
+		schedule := &domain.Schedule{
+			TenantID:   imp.TenantID,
+			BranchID:   imp.BranchID,
+			ContractID: imp.Progress.Contracts[membership.ExternalID],
+			CoachID:    coachID,
+			MemberID:   imp.Progress.Members[b.MemberExternalID],
+			StartTime:  b.StartTime,
+			EndTime:    b.EndTime,
+			Status:     b.Status,
+			Remarks:    fmt.Sprintf("Imported from %s booking %s", imp.Source, b.ExternalID),
+		}
+		if err := s.schedRepo.Create(ctx, schedule); err != nil {
+			return fmt.Errorf("booking %s: %w", b.ExternalID, err)
+		}
+		imp.Progress.Schedules[b.ExternalID] = schedule.ID
+		imp.Result.SchedulesCreated++
+
+		if sinceSave++; sinceSave == gymImportSaveInterval {
+			if err := s.save(ctx, imp); err != nil {
+				return err
+			}
+			sinceSave = 0
+		}
+	}
+	return nil
+}
+
+// bookingMembership picks the membership a session at start counted against. Validation
+// guarantees the member has at least one.
+func bookingMembership(memberships []domain.ImportedMembership, start time.Time) domain.ImportedMembership {
+	best := memberships[0]
+	for _, m := range memberships {
+		if m.StartDate.After(start) {
+			continue
+		}
+		if m.EndDate == nil || !m.EndDate.Before(start) {
+			return m
+		}
+		if best.StartDate.After(start) || m.StartDate.After(best.StartDate) {
+			best = m
+		}
+	}
+	return best
+}
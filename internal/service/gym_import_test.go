@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/infrastructure/gymimport"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	glofoxMembers = "Member ID,First Name,Last Name,Email,Phone\n" +
+		"m1,Budi,Santoso,BUDI@example.com,0812000111\n" +
+		"m2,Sari,Dewi,sari@example.com,\n" +
+		"m3,Rina,Putri,rina@example.com,\n" +
+		"m4,No,Email,,\n"
+	glofoxMemberships = "Membership ID,Member ID,Membership Name,Credits,Credits Remaining,Price,Start Date,Expiry Date\n" +
+		"p1,m1,10 PT Sessions,10,4,\"Rp 3,500,000\",01/05/2025,31/12/2025\n" +
+		"p2,m9,10 PT Sessions,10,10,,01/05/2025,\n"
+	glofoxBookings = "Booking ID,Member ID,Trainer,Date,Start Time,End Time,Status\n" +
+		"b1,m1,Andi,02/06/2025,07:00,08:00,Attended\n" +
+		"b2,m1,,09/06/2025,07:00,,Booked\n" +
+		"b3,m2,,10/06/2025,07:00,08:00,Attended\n" +
+		"b4,m1,,11/06/2025,07:00,08:00,Waitlisted\n"
+)
+
+type gymImportMocks struct {
+	*ptServiceMocks
+	repo     *mocks.GymImportRepository
+	userRepo *mocks.UserRepository
+}
+
+func newTestGymImportService(t *testing.T) (*GymImportService, gymImportMocks) {
+	pt, ptMocks := newTestPTService(t)
+	m := gymImportMocks{
+		ptServiceMocks: ptMocks,
+		repo:           mocks.NewGymImportRepository(t),
+		userRepo:       mocks.NewUserRepository(t),
+	}
+	svc := NewGymImportService(m.repo, m.userRepo, m.pkgRepo, m.schedRepo, pt, nil, clock.NewFake(testNow), gymimport.NewGlofox())
+	return svc, m
+}
+
+func TestGymImportService_Preview(t *testing.T) {
+	ctx := context.Background()
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	svc, m := newTestGymImportService(t)
+	m.userRepo.On("GetByID", anyCtx, "coach-1").Return(&domain.User{ID: "coach-1", TenantID: "tenant-1", Roles: []string{domain.RoleCoach}, HomeBranchID: "branch-1"}, nil)
+	m.userRepo.On("GetByEmail", anyCtx, "budi@example.com").Return(nil, domain.ErrNotFound)
+	m.userRepo.On("GetByEmail", anyCtx, "sari@example.com").Return(&domain.User{ID: "user-sari", TenantID: "tenant-1"}, nil)
+	m.userRepo.On("GetByEmail", anyCtx, "rina@example.com").Return(&domain.User{ID: "user-rina", TenantID: "tenant-2"}, nil)
+	m.repo.On("Create", anyCtx, mock.AnythingOfType("*domain.GymImport")).Return(nil)
+
+	imp, err := svc.Preview(ctx, GymImportRequest{
+		TenantID: "tenant-1",
+		CoachID:  "coach-1",
+		ActorID:  "admin-1",
+		Source:   "Glofox",
+		Location: jakarta,
+		Files: map[string][]byte{
+			domain.ImportFileMembers:     []byte(glofoxMembers),
+			domain.ImportFileMemberships: []byte(glofoxMemberships),
+			domain.ImportFileBookings:    []byte(glofoxBookings),
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.GymImportStatusPreview, imp.Status)
+	assert.Equal(t, "branch-1", imp.BranchID)
+	assert.Equal(t, domain.GymImportSummary{NewMembers: 1, ExistingMembers: 1, Memberships: 1, Bookings: 2, Skipped: 5}, imp.Summary)
+
+	messages := make(map[string]string)
+	for _, issue := range imp.Issues {
+		messages[issue.File+":"+issue.ExternalID] = issue.Message
+	}
+	assert.Equal(t, "missing email", messages["members:m4"])
+	assert.Contains(t, messages["members:m3"], "another gym")
+	assert.Contains(t, messages["memberships:p2"], "not in the members export")
+	assert.Contains(t, messages["bookings:b3"], "no membership")
+	assert.Contains(t, messages["bookings:b4"], "unknown booking status")
+
+	membership := imp.Data.Memberships[0]
+	assert.Equal(t, 4, membership.RemainingSessions)
+	assert.Equal(t, 3500000.0, membership.Price)
+
+	booking := imp.Data.Bookings[0]
+	assert.Equal(t, time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), booking.StartTime.UTC())
+	assert.Equal(t, domain.ScheduleStatusCompleted, booking.Status)
+	assert.Equal(t, time.Hour, imp.Data.Bookings[1].EndTime.Sub(imp.Data.Bookings[1].StartTime))
+	require.NotNil(t, imp.Preview)
+	assert.Len(t, imp.Preview.Members, 2)
+
+	t.Run("rejects an unknown source", func(t *testing.T) {
+		_, err := svc.Preview(ctx, GymImportRequest{Source: "zenplanner", Files: map[string][]byte{domain.ImportFileMembers: []byte(glofoxMembers)}})
+		assert.ErrorIs(t, err, domain.ErrUnknownImportSource)
+	})
+}
+
+func TestGymImportService_Run(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	newImport := func() *domain.GymImport {
+		end := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+		return &domain.GymImport{
+			ID: "import-1", TenantID: "tenant-1", BranchID: "branch-1", CoachID: "coach-1", Source: domain.ImportSourceGlofox,
+			Status: domain.GymImportStatusRunning,
+			Data: &domain.GymImportData{
+				Members: []domain.ImportedMember{
+					{ExternalID: "m1", Name: "Budi Santoso", Email: "budi@example.com"},
+					{ExternalID: "m2", Name: "Sari Dewi", Email: "sari@example.com"},
+				},
+				Memberships: []domain.ImportedMembership{
+					{ExternalID: "p1", MemberExternalID: "m1", Name: "10 PT Sessions", TotalSessions: 10, RemainingSessions: 4, Price: 3500000, StartDate: start.AddDate(0, -1, 0), EndDate: &end},
+				},
+				Bookings: []domain.ImportedBooking{
+					{ExternalID: "b1", MemberExternalID: "m1", Coach: "Andi", StartTime: start, EndTime: start.Add(time.Hour), Status: domain.ScheduleStatusCompleted},
+					{ExternalID: "b2", MemberExternalID: "m1", StartTime: start.AddDate(0, 0, 7), EndTime: start.AddDate(0, 0, 7).Add(time.Hour), Status: domain.ScheduleStatusScheduled},
+				},
+			},
+		}
+	}
+
+	t.Run("creates members, contracts and schedules", func(t *testing.T) {
+		svc, m := newTestGymImportService(t)
+		m.repo.On("Update", anyCtx, mock.AnythingOfType("*domain.GymImport")).Return(nil)
+		m.userRepo.On("GetByEmail", anyCtx, "budi@example.com").Return(nil, domain.ErrNotFound)
+		m.userRepo.On("GetByEmail", anyCtx, "sari@example.com").Return(&domain.User{ID: "user-sari", TenantID: "tenant-1"}, nil)
+		m.userRepo.On("Create", anyCtx, mock.MatchedBy(func(u *domain.User) bool {
+			return u.Email == "budi@example.com" && u.TenantID == "tenant-1" && u.HasRole(domain.RoleMember)
+		})).Run(func(args mock.Arguments) { args.Get(1).(*domain.User).ID = "user-budi" }).Return(nil)
+
+		m.pkgRepo.On("GetByTenant", anyCtx, "tenant-1").Return([]*domain.PTPackage{}, nil)
+		m.pkgRepo.On("Create", anyCtx, mock.MatchedBy(func(p *domain.PTPackage) bool {
+			return p.Name == "10 PT Sessions" && !p.Active && p.Price == domain.NewMoney(3500000, "IDR")
+		})).Run(func(args mock.Arguments) { args.Get(1).(*domain.PTPackage).ID = "pkg-1" }).Return(nil)
+		m.contractRepo.On("Create", anyCtx, mock.MatchedBy(func(c *domain.PTContract) bool {
+			return c.MemberID == "user-budi" && c.PackageID == "pkg-1" && c.CoachID == "coach-1" && c.Status == domain.PackageStatusActive
+		})).Run(func(args mock.Arguments) { args.Get(1).(*domain.PTContract).ID = "contract-1" }).Return(nil)
+		m.expectLock("contract:contract-1")
+		m.expectAppend(domain.CreditTypeOpening, 4, 1)
+		m.contractRepo.On("SyncBalance", anyCtx, "contract-1", 4, int64(1)).Return(nil)
+
+		m.userRepo.On("GetByTenantAndRole", anyCtx, "tenant-1", domain.RoleCoach).Return([]*domain.User{
+			{ID: "coach-1", Name: "Default Coach"},
+			{ID: "coach-2", Name: "Andi", Email: "andi@example.com"},
+		}, nil)
+		var schedules []*domain.Schedule
+		m.schedRepo.On("Create", anyCtx, mock.AnythingOfType("*domain.Schedule")).Run(func(args mock.Arguments) {
+			s := args.Get(1).(*domain.Schedule)
+			s.ID = "schedule-" + s.CoachID
+			schedules = append(schedules, s)
+		}).Return(nil)
+
+		imp := newImport()
+		require.NoError(t, svc.run(ctx, imp))
+
+		assert.Equal(t, domain.GymImportStatusCompleted, imp.Status)
+		assert.Equal(t, domain.GymImportResult{MembersCreated: 1, MembersMatched: 1, PackagesCreated: 1, ContractsCreated: 1, SchedulesCreated: 2}, imp.Result)
+		require.Len(t, schedules, 2)
+		assert.Equal(t, "coach-2", schedules[0].CoachID) // Trainer matched by name
+		assert.Equal(t, "coach-1", schedules[1].CoachID)
+		assert.Equal(t, "contract-1", schedules[0].ContractID)
+		assert.Equal(t, "user-budi", schedules[0].MemberID)
+		assert.Equal(t, domain.ScheduleStatusCompleted, schedules[0].Status)
+	})
+
+	t.Run("a retry skips what the failed run imported", func(t *testing.T) {
+		svc, m := newTestGymImportService(t)
+		m.repo.On("Update", anyCtx, mock.AnythingOfType("*domain.GymImport")).Return(nil)
+		m.pkgRepo.On("GetByTenant", anyCtx, "tenant-1").Return([]*domain.PTPackage{}, nil)
+		m.userRepo.On("GetByTenantAndRole", anyCtx, "tenant-1", domain.RoleCoach).Return([]*domain.User{}, nil)
+		m.schedRepo.On("Create", anyCtx, mock.MatchedBy(func(s *domain.Schedule) bool {
+			return s.StartTime.Equal(start.AddDate(0, 0, 7)) && s.ContractID == "contract-1"
+		})).Return(nil).Once()
+
+		imp := newImport()
+		imp.Status = domain.GymImportStatusFailed
+		imp.Progress = domain.GymImportProgress{
+			Members:   map[string]string{"m1": "user-budi", "m2": "user-sari"},
+			Contracts: map[string]string{"p1": "contract-1"},
+			Schedules: map[string]string{"b1": "schedule-1"},
+		}
+		require.NoError(t, svc.run(ctx, imp))
+		assert.Equal(t, domain.GymImportStatusCompleted, imp.Status)
+		assert.Equal(t, 1, imp.Result.SchedulesCreated)
+	})
+}
@@ -0,0 +1,356 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// handoverLookback bounds how far back a handover looks for the member's recent sessions
+const handoverLookback = 180 * 24 * time.Hour
+
+// HandoverService briefs coaches on the clients they take over, whether for one covered
+// session or for good when a contract moves to them. The brief is assembled from the
+// member's recent sessions and the notes coaches keep on them, and the receiving coach
+// acknowledges it.
+type HandoverService struct {
+	handovers    domain.HandoverRepository
+	userRepo     domain.UserRepository
+	contractRepo domain.PTContractRepository
+	schedRepo    domain.ScheduleRepository
+	setLogRepo   domain.SetLogRepository
+	exerciseRepo domain.ExerciseRepository
+	notifier     *NotificationService
+	tx           domain.Transactor
+	clock        domain.Clock
+}
+
+func NewHandoverService(
+	handovers domain.HandoverRepository,
+	userRepo domain.UserRepository,
+	contractRepo domain.PTContractRepository,
+	schedRepo domain.ScheduleRepository,
+	setLogRepo domain.SetLogRepository,
+	exerciseRepo domain.ExerciseRepository,
+	notifier *NotificationService,
+	tx domain.Transactor,
+	clk domain.Clock,
+) *HandoverService {
+	return &HandoverService{
+		handovers:    handovers,
+		userRepo:     userRepo,
+		contractRepo: contractRepo,
+		schedRepo:    schedRepo,
+		setLogRepo:   setLogRepo,
+		exerciseRepo: exerciseRepo,
+		notifier:     notifier,
+		tx:           tx,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// BriefSubstitute hands the coach who claimed offer a brief on its member
+func (s *HandoverService) BriefSubstitute(ctx context.Context, offer *domain.SubstitutionOffer) (*domain.Handover, error) {
+	handover, err := s.assemble(ctx, &domain.Handover{
+		TenantID:    offer.TenantID,
+		MemberID:    offer.MemberID,
+		ContractID:  offer.ContractID,
+		ScheduleID:  offer.ScheduleID,
+		Reason:      domain.HandoverReasonCover,
+		FromCoachID: offer.CoachID,
+		ToCoachID:   offer.ClaimedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.handovers.Create(ctx, handover); err != nil {
+		return nil, err
+	}
+	s.notify(ctx, handover)
+	return handover, nil
+}
+
+// TransferContract moves a contract with sessions left, and its upcoming sessions, to
+// another coach at its branch, and briefs that coach. Sessions a substitute covers stay
+// with the substitute.
+func (s *HandoverService) TransferContract(ctx context.Context, actorID, tenantID, contractID, toCoachID, note string) (*domain.Handover, error) {
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	if contract.TenantID != tenantID {
+		return nil, domain.ErrContractNotFound
+	}
+	movable := []string{domain.PackageStatusActive, domain.PackageStatusSuspended, domain.PackageStatusPendingPayment}
+	if !slices.Contains(movable, contract.Status) || contract.CoachID == toCoachID {
+		return nil, domain.ErrInvalidContractHandoff
+	}
+	coach, err := s.userRepo.GetByID(ctx, toCoachID)
+	if err != nil {
+		return nil, err
+	}
+	coach, err = coach.ScopedTo(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if !coach.HasRole(domain.RoleCoach) || !coach.WorksAt(contract.BranchID) {
+		return nil, domain.ErrBranchNotAllowed
+	}
+
+	now := s.clock.Now()
+	booked, err := s.schedRepo.GetByMember(ctx, contract.MemberID, now, now.AddDate(2, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+	var upcoming []string
+	for _, sched := range booked {
+		if sched.ContractID == contract.ID && sched.CoachID == contract.CoachID && sched.DeletedAt == nil &&
+			(sched.Status == domain.ScheduleStatusScheduled || sched.Status == domain.ScheduleStatusPendingConfirmation) {
+			upcoming = append(upcoming, sched.ID)
+		}
+	}
+
+	handover, err := s.assemble(ctx, &domain.Handover{
+		TenantID:    tenantID,
+		MemberID:    contract.MemberID,
+		ContractID:  contract.ID,
+		Reason:      domain.HandoverReasonTransfer,
+		FromCoachID: contract.CoachID,
+		ToCoachID:   coach.ID,
+		Note:        note,
+	})
+	if err != nil {
+		return nil, err
+	}
+	moved := 0
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		reassigned, err := s.contractRepo.ReassignCoach(ctx, contract.ID, contract.CoachID, coach.ID)
+		if err != nil {
+			return err
+		}
+		if !reassigned {
+			return domain.ErrInvalidContractHandoff
+		}
+		for _, id := range upcoming {
+			// Sessions changed meanwhile keep their coach
+			ok, err := s.schedRepo.Reassign(ctx, id, contract.CoachID, coach.ID, "")
+			if err != nil {
+				return err
+			}
+			if ok {
+				moved++
+			}
+		}
+		return s.handovers.Create(ctx, handover)
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[Audit] contract %s moved from coach %s to %s by %s with %d upcoming sessions (handover %s)",
+		contract.ID, contract.CoachID, coach.ID, actorID, moved, handover.ID)
+	s.notify(ctx, handover)
+	return handover, nil
+}
+
+// List returns the handovers to the coach, newest first
+func (s *HandoverService) List(ctx context.Context, tenantID, coachID string, pendingOnly bool) ([]*domain.Handover, error) {
+	return s.handovers.ListByCoach(ctx, tenantID, coachID, pendingOnly)
+}
+
+// Get returns a handover to the viewer: its two coaches and the tenant's admins may read it
+func (s *HandoverService) Get(ctx context.Context, viewer *domain.User, tenantID, id string) (*domain.Handover, error) {
+	handover, err := s.handovers.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if handover.TenantID != tenantID {
+		return nil, domain.ErrHandoverNotFound
+	}
+	if viewer.ID != handover.ToCoachID && viewer.ID != handover.FromCoachID && !viewer.HasRole(domain.RoleTenantAdmin) {
+		return nil, domain.ErrHandoverNotFound
+	}
+	return handover, nil
+}
+
+// Acknowledge records that the receiving coach read the handover
+func (s *HandoverService) Acknowledge(ctx context.Context, tenantID, coachID, id string) (*domain.Handover, error) {
+	handover, err := s.handovers.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if handover.TenantID != tenantID || handover.ToCoachID != coachID {
+		return nil, domain.ErrHandoverNotFound
+	}
+	if handover.AcknowledgedAt == nil {
+		now := s.clock.Now()
+		if err := s.handovers.Acknowledge(ctx, id, coachID, now); err != nil {
+			return nil, err
+		}
+		handover.AcknowledgedAt = &now
+	}
+	return handover, nil
+}
+
+// Notes returns what coaches noted on the member at the tenant
+func (s *HandoverService) Notes(ctx context.Context, tenantID, memberID string) (*domain.ClientNotes, error) {
+	return s.handovers.GetNotes(ctx, tenantID, memberID)
+}
+
+// UpdateNotes replaces the notes on the member at the tenant
+func (s *HandoverService) UpdateNotes(ctx context.Context, coachID, tenantID, memberID string, injuries, preferences []string) (*domain.ClientNotes, error) {
+	if injuries == nil {
+		injuries = []string{}
+	}
+	if preferences == nil {
+		preferences = []string{}
+	}
+	notes := &domain.ClientNotes{
+		TenantID:    tenantID,
+		MemberID:    memberID,
+		Injuries:    injuries,
+		Preferences: preferences,
+		UpdatedBy:   coachID,
+		UpdatedAt:   s.clock.Now(),
+	}
+	if err := notes.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.handovers.SaveNotes(ctx, notes); err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// assemble fills in the handover from the member's notes and recent sessions at the tenant
+func (s *HandoverService) assemble(ctx context.Context, handover *domain.Handover) (*domain.Handover, error) {
+	member, err := s.userRepo.GetByID(ctx, handover.MemberID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	if member != nil {
+		handover.MemberName = member.Name
+	}
+	notes, err := s.handovers.GetNotes(ctx, handover.TenantID, handover.MemberID)
+	if err != nil {
+		return nil, err
+	}
+	handover.Injuries, handover.Preferences = notes.Injuries, notes.Preferences
+
+	now := s.clock.Now()
+	schedules, err := s.schedRepo.GetByMember(ctx, handover.MemberID, now.Add(-handoverLookback), now)
+	if err != nil {
+		return nil, err
+	}
+	var recent []*domain.Schedule
+	for _, sched := range schedules {
+		if sched.TenantID == handover.TenantID && sched.Status == domain.ScheduleStatusCompleted && sched.DeletedAt == nil {
+			recent = append(recent, sched)
+		}
+	}
+	slices.SortFunc(recent, func(a, b *domain.Schedule) int { return b.StartTime.Compare(a.StartTime) })
+	if len(recent) > domain.HandoverRecentSessions {
+		recent = recent[:domain.HandoverRecentSessions]
+	}
+
+	handover.Program = []domain.HandoverExercise{}
+	handover.RecentSessions = make([]domain.HandoverSession, 0, len(recent))
+	program := map[string]int{} // Exercise ID -> index in Program
+	sessionExercises := make([][]string, len(recent))
+	var exerciseIDs []string
+	for i, sched := range recent {
+		logs, err := s.setLogRepo.GetByScheduleID(ctx, sched.ID)
+		if err != nil {
+			return nil, err
+		}
+		session := domain.HandoverSession{
+			ScheduleID:  sched.ID,
+			Date:        sched.StartTime,
+			CoachID:     sched.CoachID,
+			SessionGoal: sched.SessionGoal,
+			Remarks:     sched.Remarks,
+		}
+		if sched.Effort != nil {
+			session.RPE = sched.Effort.RPE
+		}
+		for _, set := range logs {
+			if set.DeletedAt != nil || !set.Completed {
+				continue
+			}
+			session.Sets++
+			session.Volume += set.Weight * float64(set.Reps)
+			if !slices.Contains(sessionExercises[i], set.ExerciseID) {
+				sessionExercises[i] = append(sessionExercises[i], set.ExerciseID)
+			}
+			// The program shows each exercise's top set in the last session it was done
+			idx, seen := program[set.ExerciseID]
+			if !seen {
+				program[set.ExerciseID] = len(handover.Program)
+				handover.Program = append(handover.Program, domain.HandoverExercise{ExerciseID: set.ExerciseID, LastDone: sched.StartTime})
+				exerciseIDs = append(exerciseIDs, set.ExerciseID)
+				idx = len(handover.Program) - 1
+			}
+			top := &handover.Program[idx]
+			if top.LastDone.Equal(sched.StartTime) && (set.Weight > top.Weight || set.Weight == top.Weight && set.Reps > top.Reps) {
+				top.Weight, top.Reps = set.Weight, set.Reps
+			}
+		}
+		handover.RecentSessions = append(handover.RecentSessions, session)
+	}
+
+	names := map[string]string{}
+	if len(exerciseIDs) > 0 {
+		exercises, err := s.exerciseRepo.GetByIDs(ctx, exerciseIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, ex := range exercises {
+			names[ex.ID] = ex.Name
+		}
+	}
+	name := func(id string) string {
+		if names[id] != "" {
+			return names[id]
+		}
+		return "Exercise"
+	}
+	for i := range handover.Program {
+		handover.Program[i].Name = name(handover.Program[i].ExerciseID)
+	}
+	for i, ids := range sessionExercises {
+		handover.RecentSessions[i].Exercises = make([]string, len(ids))
+		for j, id := range ids {
+			handover.RecentSessions[i].Exercises[j] = name(id)
+		}
+	}
+	return handover, nil
+}
+
+// notify tells the receiving coach about the handover; failures are only logged since the
+// handover itself is saved
+func (s *HandoverService) notify(ctx context.Context, handover *domain.Handover) {
+	member := handover.MemberName
+	if member == "" {
+		member = "A member"
+	}
+	title, body := "Client handover", "You're covering a session of "+member+". Read their handover before you start"
+	if handover.Reason == domain.HandoverReasonTransfer {
+		body = member + " is now your client. Read their handover to pick up where their last coach left off"
+	}
+	n := &domain.Notification{
+		UserID:   handover.ToCoachID,
+		TenantID: handover.TenantID,
+		Type:     domain.NotificationHandover,
+		Title:    title,
+		Body:     body,
+		Data:     map[string]string{"handover_id": handover.ID, "member_id": handover.MemberID},
+		Link:     &domain.DeepLink{Screen: domain.ScreenHandover, HandoverID: handover.ID},
+	}
+	if err := s.notifier.Notify(ctx, n); err != nil {
+		log.Printf("Warning: handover %s notification to %s not sent: %v", handover.ID, handover.ToCoachID, err)
+	}
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type handoverMocks struct {
+	handovers *mocks.HandoverRepository
+	userRepo  *mocks.UserRepository
+	contracts *mocks.PTContractRepository
+	schedRepo *mocks.ScheduleRepository
+	setLogs   *mocks.SetLogRepository
+	exercises *mocks.ExerciseRepository
+	push      *mocks.NotificationSender
+}
+
+func newHandoverService(t *testing.T) (*HandoverService, *handoverMocks) {
+	m := &handoverMocks{
+		handovers: mocks.NewHandoverRepository(t),
+		userRepo:  mocks.NewUserRepository(t),
+		contracts: mocks.NewPTContractRepository(t),
+		schedRepo: mocks.NewScheduleRepository(t),
+		setLogs:   mocks.NewSetLogRepository(t),
+		exercises: mocks.NewExerciseRepository(t),
+		push:      mocks.NewNotificationSender(t),
+	}
+	prefs := mocks.NewNotificationPreferencesRepository(t)
+	prefs.On("GetUser", mock.Anything, mock.Anything).Return(&domain.NotificationPreferences{}, nil).Maybe()
+	m.push.On("Channel").Return(domain.ChannelPush).Maybe()
+	notifier := NewNotificationService(prefs, nil, clock.NewFake(testNow), m.push)
+
+	tx := mocks.NewTransactor(t)
+	tx.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInline).Maybe()
+	svc := NewHandoverService(m.handovers, m.userRepo, m.contracts, m.schedRepo, m.setLogs, m.exercises, notifier, tx, clock.NewFake(testNow))
+	return svc, m
+}
+
+// expectHistory sets up a member with notes and four completed sessions, the oldest of
+// which falls outside the handover
+func (m *handoverMocks) expectHistory(ctx context.Context) {
+	rpe := 8
+	m.userRepo.On("GetByID", ctx, "m1").Return(&domain.User{ID: "m1", Name: "Dina"}, nil)
+	m.handovers.On("GetNotes", ctx, "gym", "m1").Return(&domain.ClientNotes{
+		TenantID: "gym", MemberID: "m1", Injuries: []string{"Left knee: no deep squats"}, Preferences: []string{"Mornings"},
+	}, nil)
+	day := func(n int) time.Time { return testNow.AddDate(0, 0, -n) }
+	m.schedRepo.On("GetByMember", ctx, "m1", testNow.Add(-handoverLookback), testNow).Return([]*domain.Schedule{
+		{ID: "s0", TenantID: "gym", CoachID: "c1", Status: domain.ScheduleStatusCompleted, StartTime: day(20)},
+		{ID: "s1", TenantID: "gym", CoachID: "c1", Status: domain.ScheduleStatusCompleted, StartTime: day(7), SessionGoal: "Legs"},
+		{ID: "s2", TenantID: "gym", CoachID: "c1", Status: domain.ScheduleStatusCompleted, StartTime: day(3), Remarks: "Knee felt fine", Effort: &domain.SessionEffort{RPE: &rpe}},
+		{ID: "s3", TenantID: "gym", CoachID: "c1", Status: domain.ScheduleStatusCancelled, StartTime: day(2)},
+		{ID: "s4", TenantID: "other", CoachID: "x", Status: domain.ScheduleStatusCompleted, StartTime: day(2)},
+		{ID: "s5", TenantID: "gym", CoachID: "c3", Status: domain.ScheduleStatusCompleted, StartTime: day(1)},
+	}, nil)
+	m.setLogs.On("GetByScheduleID", ctx, "s5").Return([]*domain.SetLogDocument{
+		{ExerciseID: "bench", Weight: 60, Reps: 8, Completed: true},
+		{ExerciseID: "bench", Weight: 65, Reps: 5, Completed: true},
+		{ExerciseID: "bench", Weight: 90, Reps: 1, Completed: false}, // Failed attempt
+	}, nil)
+	m.setLogs.On("GetByScheduleID", ctx, "s2").Return([]*domain.SetLogDocument{
+		{ExerciseID: "squat", Weight: 80, Reps: 5, Completed: true},
+		{ExerciseID: "bench", Weight: 70, Reps: 5, Completed: true}, // Older than s5's top set
+	}, nil)
+	m.setLogs.On("GetByScheduleID", ctx, "s1").Return([]*domain.SetLogDocument{}, nil)
+	m.exercises.On("GetByIDs", ctx, []string{"bench", "squat"}).Return([]*domain.Exercise{{ID: "bench", Name: "Bench Press"}}, nil)
+}
+
+func TestHandoverService_BriefSubstitute(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newHandoverService(t)
+	m.expectHistory(ctx)
+
+	m.handovers.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Handover).ID = "h1"
+	}).Return(nil)
+	m.push.On("Send", ctx, mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == "c2" && n.Type == domain.NotificationHandover && n.Link.HandoverID == "h1"
+	})).Return(nil).Once()
+
+	handover, err := svc.BriefSubstitute(ctx, &domain.SubstitutionOffer{
+		TenantID: "gym", ScheduleID: "next", ContractID: "k1", MemberID: "m1", CoachID: "c1", ClaimedBy: "c2",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.HandoverReasonCover, handover.Reason)
+	assert.Equal(t, "Dina", handover.MemberName)
+	assert.Equal(t, "c1", handover.FromCoachID)
+	assert.Equal(t, "c2", handover.ToCoachID)
+	assert.Equal(t, []string{"Left knee: no deep squats"}, handover.Injuries)
+
+	require.Len(t, handover.RecentSessions, domain.HandoverRecentSessions)
+	assert.Equal(t, []string{"s5", "s2", "s1"}, []string{
+		handover.RecentSessions[0].ScheduleID, handover.RecentSessions[1].ScheduleID, handover.RecentSessions[2].ScheduleID,
+	})
+	assert.Equal(t, 2, handover.RecentSessions[0].Sets)
+	assert.Equal(t, 805.0, handover.RecentSessions[0].Volume)
+	assert.Equal(t, []string{"Exercise", "Bench Press"}, handover.RecentSessions[1].Exercises)
+	assert.Equal(t, 8, *handover.RecentSessions[1].RPE)
+	assert.Empty(t, handover.RecentSessions[2].Exercises)
+
+	require.Len(t, handover.Program, 2)
+	assert.Equal(t, domain.HandoverExercise{ExerciseID: "bench", Name: "Bench Press", Weight: 65, Reps: 5, LastDone: testNow.AddDate(0, 0, -1)}, handover.Program[0])
+	assert.Equal(t, domain.HandoverExercise{ExerciseID: "squat", Name: "Exercise", Weight: 80, Reps: 5, LastDone: testNow.AddDate(0, 0, -3)}, handover.Program[1])
+}
+
+func TestHandoverService_TransferContract(t *testing.T) {
+	ctx := context.Background()
+	contract := &domain.PTContract{ID: "k1", TenantID: "gym", BranchID: "b1", MemberID: "m1", CoachID: "c1", Status: domain.PackageStatusActive}
+
+	t.Run("moves the contract and its upcoming sessions", func(t *testing.T) {
+		svc, m := newHandoverService(t)
+		m.expectHistory(ctx)
+		m.contracts.On("GetByID", ctx, "k1").Return(contract, nil)
+		m.userRepo.On("GetByID", ctx, "c2").Return(&domain.User{ID: "c2", TenantID: "gym", Roles: []string{domain.RoleCoach}, HomeBranchID: "b1"}, nil)
+		m.schedRepo.On("GetByMember", ctx, "m1", testNow, testNow.AddDate(2, 0, 0)).Return([]*domain.Schedule{
+			{ID: "u1", ContractID: "k1", CoachID: "c1", Status: domain.ScheduleStatusScheduled},
+			{ID: "u2", ContractID: "k1", CoachID: "c1", Status: domain.ScheduleStatusPendingConfirmation},
+			{ID: "u3", ContractID: "k1", CoachID: "c3", Status: domain.ScheduleStatusScheduled}, // Covered by a substitute
+			{ID: "u4", ContractID: "k9", CoachID: "c1", Status: domain.ScheduleStatusScheduled},
+			{ID: "u5", ContractID: "k1", CoachID: "c1", Status: domain.ScheduleStatusCancelled},
+		}, nil)
+		m.contracts.On("ReassignCoach", ctx, "k1", "c1", "c2").Return(true, nil).Once()
+		m.schedRepo.On("Reassign", ctx, "u1", "c1", "c2", "").Return(true, nil).Once()
+		m.schedRepo.On("Reassign", ctx, "u2", "c1", "c2", "").Return(false, nil).Once()
+		m.handovers.On("Create", ctx, mock.MatchedBy(func(h *domain.Handover) bool {
+			return h.Reason == domain.HandoverReasonTransfer && h.ContractID == "k1" && h.Note == "Watch the knee"
+		})).Return(nil).Once()
+		m.push.On("Send", ctx, mock.MatchedBy(func(n *domain.Notification) bool { return n.UserID == "c2" })).Return(nil).Once()
+
+		handover, err := svc.TransferContract(ctx, "admin", "gym", "k1", "c2", "Watch the knee")
+		require.NoError(t, err)
+		assert.Equal(t, "c1", handover.FromCoachID)
+		assert.Equal(t, "c2", handover.ToCoachID)
+	})
+
+	t.Run("rejects", func(t *testing.T) {
+		svc, m := newHandoverService(t)
+		m.contracts.On("GetByID", ctx, "k1").Return(contract, nil)
+		m.contracts.On("GetByID", ctx, "done").Return(&domain.PTContract{ID: "done", TenantID: "gym", CoachID: "c1", Status: domain.PackageStatusDepleted}, nil)
+		m.userRepo.On("GetByID", ctx, "far").Return(&domain.User{ID: "far", TenantID: "gym", Roles: []string{domain.RoleCoach}, HomeBranchID: "b2"}, nil)
+		m.userRepo.On("GetByID", ctx, "stranger").Return(&domain.User{ID: "stranger", TenantID: "other", Roles: []string{domain.RoleCoach}}, nil)
+
+		_, err := svc.TransferContract(ctx, "admin", "other", "k1", "c2", "")
+		assert.ErrorIs(t, err, domain.ErrContractNotFound)
+		_, err = svc.TransferContract(ctx, "admin", "gym", "done", "c2", "")
+		assert.ErrorIs(t, err, domain.ErrInvalidContractHandoff)
+		_, err = svc.TransferContract(ctx, "admin", "gym", "k1", "c1", "")
+		assert.ErrorIs(t, err, domain.ErrInvalidContractHandoff)
+		_, err = svc.TransferContract(ctx, "admin", "gym", "k1", "far", "")
+		assert.ErrorIs(t, err, domain.ErrBranchNotAllowed)
+		_, err = svc.TransferContract(ctx, "admin", "gym", "k1", "stranger", "")
+		assert.ErrorIs(t, err, domain.ErrNotTenantMember)
+	})
+}
+
+func TestHandoverService_Acknowledge(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newHandoverService(t)
+	earlier := testNow.Add(-time.Hour)
+	m.handovers.On("GetByID", ctx, "h1").Return(&domain.Handover{ID: "h1", TenantID: "gym", ToCoachID: "c2"}, nil)
+	m.handovers.On("GetByID", ctx, "h2").Return(&domain.Handover{ID: "h2", TenantID: "gym", ToCoachID: "c2", AcknowledgedAt: &earlier}, nil)
+	m.handovers.On("Acknowledge", ctx, "h1", "c2", testNow).Return(nil).Once()
+
+	handover, err := svc.Acknowledge(ctx, "gym", "c2", "h1")
+	require.NoError(t, err)
+	assert.Equal(t, testNow, *handover.AcknowledgedAt)
+
+	handover, err = svc.Acknowledge(ctx, "gym", "c2", "h2")
+	require.NoError(t, err)
+	assert.Equal(t, earlier, *handover.AcknowledgedAt)
+
+	_, err = svc.Acknowledge(ctx, "gym", "c1", "h1")
+	assert.ErrorIs(t, err, domain.ErrHandoverNotFound)
+	_, err = svc.Acknowledge(ctx, "other", "c2", "h1")
+	assert.ErrorIs(t, err, domain.ErrHandoverNotFound)
+}
+
+func TestHandoverService_UpdateNotes(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newHandoverService(t)
+	m.handovers.On("SaveNotes", ctx, mock.MatchedBy(func(n *domain.ClientNotes) bool {
+		return n.UpdatedBy == "c1" && n.UpdatedAt.Equal(testNow) && len(n.Injuries) == 1 && n.Preferences != nil
+	})).Return(nil).Once()
+
+	notes, err := svc.UpdateNotes(ctx, "c1", "gym", "m1", []string{"Shoulder impingement"}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, notes.Preferences)
+
+	_, err = svc.UpdateNotes(ctx, "c1", "gym", "m1", []string{""}, nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidClientNotes)
+}
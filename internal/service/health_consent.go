@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// HealthConsentService records members' consent to the processing of their InBody scans.
+// Consent is given to a version of the health data policy; publishing a new version asks
+// everyone again, and scans are only digitized or imported with current consent.
+type HealthConsentService struct {
+	repo     domain.HealthConsentRepository
+	userRepo domain.UserRepository
+	notifier *NotificationService // Optional: without it coaches can't prompt members
+	clock    domain.Clock
+}
+
+func NewHealthConsentService(repo domain.HealthConsentRepository, userRepo domain.UserRepository, notifier *NotificationService, clk domain.Clock) *HealthConsentService {
+	return &HealthConsentService{
+		repo:     repo,
+		userRepo: userRepo,
+		notifier: notifier,
+		clock:    clock.OrReal(clk),
+	}
+}
+
+// CurrentPolicy returns the policy in force: the latest published, or the built-in one
+func (s *HealthConsentService) CurrentPolicy(ctx context.Context) (*domain.HealthDataPolicy, error) {
+	policy, err := s.repo.GetCurrentPolicy(ctx)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.DefaultHealthDataPolicy(), nil
+	}
+	return policy, err
+}
+
+// PublishPolicy makes text the next version of the policy. Members' consent to earlier
+// versions stops counting, so they are asked again.
+func (s *HealthConsentService) PublishPolicy(ctx context.Context, text, actorID string) (*domain.HealthDataPolicy, error) {
+	current, err := s.CurrentPolicy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := domain.NewHealthDataPolicy(current.Version+1, text)
+	if err != nil {
+		return nil, err
+	}
+	if policy.Digest == current.Digest {
+		return nil, domain.ErrInvalidHealthDataPolicy
+	}
+	policy.PublishedBy = actorID
+	policy.PublishedAt = s.clock.Now()
+	if err := s.repo.CreatePolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// Status returns where the user stands against the current policy
+func (s *HealthConsentService) Status(ctx context.Context, userID string) (*domain.HealthConsentStatus, error) {
+	policy, err := s.CurrentPolicy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := s.repo.GetLatest(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		latest = nil
+	} else if err != nil {
+		return nil, err
+	}
+	return domain.BuildHealthConsentStatus(policy, latest), nil
+}
+
+// Grant records the user's consent to policyVersion, the version they were shown. It
+// fails with ErrHealthConsentOutdated if a newer one was published in the meantime.
+func (s *HealthConsentService) Grant(ctx context.Context, consent *domain.HealthConsent) (*domain.HealthConsentStatus, error) {
+	policy, err := s.CurrentPolicy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if consent.PolicyVersion != policy.Version {
+		return nil, domain.ErrHealthConsentOutdated
+	}
+	consent.Granted = true
+	consent.PolicyDigest = policy.Digest
+	consent.RecordedAt = s.clock.Now()
+	if err := s.repo.Record(ctx, consent); err != nil {
+		return nil, err
+	}
+	return domain.BuildHealthConsentStatus(policy, consent), nil
+}
+
+// Withdraw records the user withdrawing consent; their scans are no longer processed
+func (s *HealthConsentService) Withdraw(ctx context.Context, consent *domain.HealthConsent) (*domain.HealthConsentStatus, error) {
+	policy, err := s.CurrentPolicy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	consent.Granted = false
+	consent.PolicyVersion = policy.Version
+	consent.PolicyDigest = policy.Digest
+	consent.RecordedAt = s.clock.Now()
+	if err := s.repo.Record(ctx, consent); err != nil {
+		return nil, err
+	}
+	return domain.BuildHealthConsentStatus(policy, consent), nil
+}
+
+// RequireConsent returns ErrHealthConsentRequired unless the user has consented to the
+// current policy
+func (s *HealthConsentService) RequireConsent(ctx context.Context, userID string) error {
+	status, err := s.Status(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check health consent: %w", err)
+	}
+	if !status.Current {
+		return domain.ErrHealthConsentRequired
+	}
+	return nil
+}
+
+// MemberStatus returns a member's status for a coach in tenantID
+func (s *HealthConsentService) MemberStatus(ctx context.Context, tenantID, memberID string) (*domain.HealthConsentStatus, error) {
+	if err := s.checkMember(ctx, tenantID, memberID); err != nil {
+		return nil, err
+	}
+	return s.Status(ctx, memberID)
+}
+
+// Prompt asks a member without current consent to review the policy, through a
+// notification that opens it in the app. Members who have consented aren't notified.
+func (s *HealthConsentService) Prompt(ctx context.Context, tenantID, memberID string) (*domain.HealthConsentStatus, error) {
+	if err := s.checkMember(ctx, tenantID, memberID); err != nil {
+		return nil, err
+	}
+	status, err := s.Status(ctx, memberID)
+	if err != nil || status.Current {
+		return status, err
+	}
+	if s.notifier == nil {
+		return nil, errors.New("notifications are not configured")
+	}
+
+	title := "Your consent is needed"
+	body := "Your coach can't process your InBody scans until you agree to the health data policy."
+	if status.Status == domain.HealthConsentOutdated {
+		title = "Our health data policy has changed"
+		body = "Please review the updated policy so your coach can keep processing your InBody scans."
+	}
+	err = s.notifier.Notify(ctx, &domain.Notification{
+		UserID:   memberID,
+		TenantID: tenantID,
+		Type:     domain.NotificationHealthConsent,
+		Title:    title,
+		Body:     body,
+		Data:     map[string]string{"policy_version": fmt.Sprint(status.Policy.Version)},
+		Link:     &domain.DeepLink{Screen: domain.ScreenHealthConsent},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to notify member: %w", err)
+	}
+	return status, nil
+}
+
+// checkMember returns ErrNotTenantMember unless memberID belongs to tenantID
+func (s *HealthConsentService) checkMember(ctx context.Context, tenantID, memberID string) error {
+	member, err := s.userRepo.GetByID(ctx, memberID)
+	if err != nil {
+		return err
+	}
+	if tenantID == "" {
+		return domain.ErrNotTenantMember
+	}
+	_, err = member.ScopedTo(tenantID)
+	return err
+}
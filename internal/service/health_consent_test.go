@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthConsentService(t *testing.T) {
+	ctx := context.Background()
+	v2, err := domain.NewHealthDataPolicy(2, "We read your scans to track your progress.")
+	require.NoError(t, err)
+
+	newService := func(t *testing.T) (*HealthConsentService, *mocks.HealthConsentRepository, *mocks.UserRepository, *mocks.NotificationSender) {
+		repo, users := mocks.NewHealthConsentRepository(t), mocks.NewUserRepository(t)
+		prefs := mocks.NewNotificationPreferencesRepository(t)
+		prefs.On("GetUser", ctx, mock.Anything).Return(&domain.NotificationPreferences{}, nil).Maybe()
+		push := mocks.NewNotificationSender(t)
+		push.On("Channel").Return(domain.ChannelPush)
+		notifications := NewNotificationService(prefs, nil, clock.NewFake(testNow), push)
+		return NewHealthConsentService(repo, users, notifications, clock.NewFake(testNow)), repo, users, push
+	}
+
+	t.Run("the built-in policy applies until one is published", func(t *testing.T) {
+		svc, repo, _, _ := newService(t)
+		repo.On("GetCurrentPolicy", ctx).Return(nil, domain.ErrNotFound)
+		repo.On("GetLatest", ctx, "m1").Return(nil, domain.ErrNotFound)
+
+		status, err := svc.Status(ctx, "m1")
+		require.NoError(t, err)
+		assert.Equal(t, domain.HealthConsentMissing, status.Status)
+		assert.Equal(t, 1, status.Policy.Version)
+		assert.ErrorIs(t, svc.RequireConsent(ctx, "m1"), domain.ErrHealthConsentRequired)
+	})
+
+	t.Run("consent is recorded against the version the member was shown", func(t *testing.T) {
+		svc, repo, _, _ := newService(t)
+		repo.On("GetCurrentPolicy", ctx).Return(v2, nil)
+
+		_, err := svc.Grant(ctx, &domain.HealthConsent{UserID: "m1", PolicyVersion: 1})
+		assert.ErrorIs(t, err, domain.ErrHealthConsentOutdated)
+
+		repo.On("Record", ctx, mock.MatchedBy(func(c *domain.HealthConsent) bool {
+			return c.Granted && c.PolicyVersion == 2 && c.PolicyDigest == v2.Digest && c.RecordedAt.Equal(testNow)
+		})).Return(nil)
+		status, err := svc.Grant(ctx, &domain.HealthConsent{UserID: "m1", PolicyVersion: 2})
+		require.NoError(t, err)
+		assert.True(t, status.Current)
+	})
+
+	t.Run("publishing a new version makes earlier consent outdated", func(t *testing.T) {
+		svc, repo, _, _ := newService(t)
+		repo.On("GetCurrentPolicy", ctx).Return(v2, nil).Once()
+
+		_, err := svc.PublishPolicy(ctx, "  "+v2.Text+"\n", "admin")
+		assert.ErrorIs(t, err, domain.ErrInvalidHealthDataPolicy)
+
+		repo.On("GetCurrentPolicy", ctx).Return(v2, nil).Once()
+		repo.On("CreatePolicy", ctx, mock.Anything).Return(nil)
+		v3, err := svc.PublishPolicy(ctx, "We read your scans and share them with your coach.", "admin")
+		require.NoError(t, err)
+		assert.Equal(t, 3, v3.Version)
+
+		status := domain.BuildHealthConsentStatus(v3, &domain.HealthConsent{Granted: true, PolicyVersion: 2, PolicyDigest: v2.Digest})
+		assert.Equal(t, domain.HealthConsentOutdated, status.Status)
+	})
+
+	t.Run("coaches prompt members without current consent", func(t *testing.T) {
+		svc, repo, users, push := newService(t)
+		users.On("GetByID", ctx, "m1").Return(&domain.User{ID: "m1", TenantID: "gym"}, nil)
+		repo.On("GetCurrentPolicy", ctx).Return(v2, nil)
+		repo.On("GetLatest", ctx, "m1").Return(&domain.HealthConsent{Granted: true, PolicyVersion: 1, PolicyDigest: "old"}, nil)
+		push.On("Send", ctx, mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.UserID == "m1" && n.Type == domain.NotificationHealthConsent && n.Link.Screen == domain.ScreenHealthConsent
+		})).Return(nil)
+
+		status, err := svc.Prompt(ctx, "gym", "m1")
+		require.NoError(t, err)
+		assert.Equal(t, domain.HealthConsentOutdated, status.Status)
+
+		_, err = svc.Prompt(ctx, "other-gym", "m1")
+		assert.ErrorIs(t, err, domain.ErrNotTenantMember)
+	})
+
+	t.Run("scans of members without consent are neither stored nor digitized", func(t *testing.T) {
+		consent, repo, _, _ := newService(t)
+		repo.On("GetCurrentPolicy", anyCtx).Return(v2, nil)
+		repo.On("GetLatest", anyCtx, "m1").Return(&domain.HealthConsent{Granted: false, PolicyVersion: 2, PolicyDigest: v2.Digest}, nil)
+		scans, m := newTestScanService(t)
+		scans.RequireHealthConsent(consent)
+
+		_, err := scans.ProcessScan(ctx, "m1", []byte("scan"), "scan.jpg")
+		assert.ErrorIs(t, err, domain.ErrHealthConsentRequired)
+		_, err = scans.ImportCSV(ctx, "m1", []byte("csv"), nil)
+		assert.ErrorIs(t, err, domain.ErrHealthConsentRequired)
+		m.files.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		m.digitizer.AssertNotCalled(t, "ExtractMetrics", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
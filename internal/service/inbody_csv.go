@@ -0,0 +1,313 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+const (
+	scanCSVMaxRows = 2000
+	scanCSVSource  = "lookinbody_csv"
+)
+
+// LookinBody numbers its export columns ("15. Weight") and puts units in parentheses
+var (
+	csvColumnNumber = regexp.MustCompile(`^\d+\.\s*`)
+	csvColumnUnit   = regexp.MustCompile(`\([^)]*\)`)
+	csvNonAlnum     = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Test dates come as LookinBody writes them in different versions and locales
+var scanCSVDateLayouts = []string{
+	"2006.01.02 15:04:05",
+	"2006.01.02 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"20060102150405",
+	"02/01/2006 15:04",
+	"2006.01.02",
+	"2006-01-02",
+}
+
+// scanCSVField sets one record field from a cell
+type scanCSVField func(r *domain.InBodyRecord, v float64)
+
+// scanCSVFields maps normalized column names to record fields. Several names are listed
+// where LookinBody versions differ.
+var scanCSVFields = map[string]scanCSVField{
+	"weight":                   func(r *domain.InBodyRecord, v float64) { r.Weight = v },
+	"skeletalmusclemass":       func(r *domain.InBodyRecord, v float64) { r.SMM = v },
+	"smm":                      func(r *domain.InBodyRecord, v float64) { r.SMM = v },
+	"bodyfatmass":              func(r *domain.InBodyRecord, v float64) { r.BodyFatMass = v },
+	"bfm":                      func(r *domain.InBodyRecord, v float64) { r.BodyFatMass = v },
+	"bmi":                      func(r *domain.InBodyRecord, v float64) { r.BMI = v },
+	"bodymassindex":            func(r *domain.InBodyRecord, v float64) { r.BMI = v },
+	"percentbodyfat":           func(r *domain.InBodyRecord, v float64) { r.PBF = v },
+	"percentbodyfatpercent":    func(r *domain.InBodyRecord, v float64) { r.PBF = v },
+	"pbf":                      func(r *domain.InBodyRecord, v float64) { r.PBF = v },
+	"basalmetabolicrate":       func(r *domain.InBodyRecord, v float64) { r.BMR = int(v) },
+	"bmr":                      func(r *domain.InBodyRecord, v float64) { r.BMR = int(v) },
+	"visceralfatlevel":         func(r *domain.InBodyRecord, v float64) { r.VisceralFatLevel = int(v) },
+	"vfl":                      func(r *domain.InBodyRecord, v float64) { r.VisceralFatLevel = int(v) },
+	"waisthipratio":            func(r *domain.InBodyRecord, v float64) { r.WaistHipRatio = v },
+	"whr":                      func(r *domain.InBodyRecord, v float64) { r.WaistHipRatio = v },
+	"inbodyscore":              func(r *domain.InBodyRecord, v float64) { r.InBodyScore = v },
+	"obesitydegree":            func(r *domain.InBodyRecord, v float64) { r.ObesityDegree = v },
+	"fatfreemass":              func(r *domain.InBodyRecord, v float64) { r.FatFreeMass = v },
+	"ffm":                      func(r *domain.InBodyRecord, v float64) { r.FatFreeMass = v },
+	"recommendedcalorieintake": func(r *domain.InBodyRecord, v float64) { r.RecommendedCalorieIntake = int(v) },
+	"targetweight":             func(r *domain.InBodyRecord, v float64) { r.TargetWeight = v },
+	"weightcontrol":            func(r *domain.InBodyRecord, v float64) { r.WeightControl = v },
+	"fatcontrol":               func(r *domain.InBodyRecord, v float64) { r.FatControl = v },
+	"musclecontrol":            func(r *domain.InBodyRecord, v float64) { r.MuscleControl = v },
+}
+
+var scanCSVDateColumns = []string{"testdatetime", "testdate", "datetime", "date"}
+
+func init() {
+	segments := map[string]func(d *domain.SegmentalData) *domain.SegmentMetrics{
+		"rightarm": func(d *domain.SegmentalData) *domain.SegmentMetrics { return &d.RightArm },
+		"leftarm":  func(d *domain.SegmentalData) *domain.SegmentMetrics { return &d.LeftArm },
+		"trunk":    func(d *domain.SegmentalData) *domain.SegmentMetrics { return &d.Trunk },
+		"rightleg": func(d *domain.SegmentalData) *domain.SegmentMetrics { return &d.RightLeg },
+		"leftleg":  func(d *domain.SegmentalData) *domain.SegmentMetrics { return &d.LeftLeg },
+	}
+	lean := func(r *domain.InBodyRecord) *domain.SegmentalData {
+		if r.SegmentalLean == nil {
+			r.SegmentalLean = &domain.SegmentalData{}
+		}
+		return r.SegmentalLean
+	}
+	fat := func(r *domain.InBodyRecord) *domain.SegmentalData {
+		if r.SegmentalFat == nil {
+			r.SegmentalFat = &domain.SegmentalData{}
+		}
+		return r.SegmentalFat
+	}
+	for name, segment := range segments {
+		segment := segment
+		for _, prefix := range []string{"leanmassof", "lmof"} {
+			scanCSVFields[prefix+name] = func(r *domain.InBodyRecord, v float64) { segment(lean(r)).Mass = v }
+		}
+		scanCSVFields["leanmasspercentof"+name] = func(r *domain.InBodyRecord, v float64) { segment(lean(r)).Percentage = v }
+		for _, prefix := range []string{"bodyfatmassof", "bfmof"} {
+			scanCSVFields[prefix+name] = func(r *domain.InBodyRecord, v float64) { segment(fat(r)).Mass = v }
+		}
+		for _, prefix := range []string{"bodyfatmasspercentof", "bfmpercentof"} {
+			scanCSVFields[prefix+name] = func(r *domain.InBodyRecord, v float64) { segment(fat(r)).Percentage = v }
+		}
+	}
+}
+
+// normalizeCSVColumn reduces a header such as "29. Lean Mass(%) of Right Arm" to
+// "leanmasspercentofrightarm"
+func normalizeCSVColumn(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = csvColumnNumber.ReplaceAllString(name, "")
+	name = strings.ReplaceAll(name, "(%)", "percent")
+	name = strings.ReplaceAll(name, "%", "percent")
+	name = csvColumnUnit.ReplaceAllString(name, "")
+	return csvNonAlnum.ReplaceAllString(name, "")
+}
+
+// scanCSVRow is a parsed data row, or the reason it couldn't be parsed
+type scanCSVRow struct {
+	line   int
+	record *domain.InBodyRecord
+	err    error
+}
+
+// parseScanCSV reads a LookinBody export. The header is the first row with a test date
+// and a weight column; title rows above it are skipped. Semicolon-separated exports,
+// which use decimal commas, are recognised too.
+func parseScanCSV(data []byte, loc *time.Location) ([]scanCSVRow, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	semicolons := bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(","))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if semicolons {
+		reader.Comma = ';'
+	}
+
+	dateCol, fields := -1, map[int]scanCSVField{}
+	var rows []scanCSVRow
+	for {
+		cells, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidScanCSV, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		if dateCol < 0 {
+			dateCol, fields = scanCSVHeader(cells)
+			continue
+		}
+		if blankRow(cells) {
+			continue
+		}
+		if len(rows) == scanCSVMaxRows {
+			return nil, fmt.Errorf("%w: more than %d scans", domain.ErrInvalidScanCSV, scanCSVMaxRows)
+		}
+		record, err := parseScanCSVRow(cells, dateCol, fields, loc, semicolons)
+		rows = append(rows, scanCSVRow{line: line, record: record, err: err})
+	}
+	if dateCol < 0 {
+		return nil, fmt.Errorf("%w: no header with test date and weight columns", domain.ErrInvalidScanCSV)
+	}
+	return rows, nil
+}
+
+// scanCSVHeader returns the test date column and the mapped value columns, or -1 if cells
+// isn't the header
+func scanCSVHeader(cells []string) (int, map[int]scanCSVField) {
+	dateCol, hasWeight := -1, false
+	fields := make(map[int]scanCSVField)
+	for i, cell := range cells {
+		name := normalizeCSVColumn(cell)
+		for _, dateName := range scanCSVDateColumns {
+			if name == dateName && dateCol < 0 {
+				dateCol = i
+			}
+		}
+		if field, ok := scanCSVFields[name]; ok {
+			fields[i] = field
+			hasWeight = hasWeight || name == "weight"
+		}
+	}
+	if dateCol < 0 || !hasWeight {
+		return -1, nil
+	}
+	return dateCol, fields
+}
+
+func parseScanCSVRow(cells []string, dateCol int, fields map[int]scanCSVField, loc *time.Location, decimalComma bool) (*domain.InBodyRecord, error) {
+	if dateCol >= len(cells) {
+		return nil, errors.New("missing test date")
+	}
+	testDate, err := parseScanCSVDate(cells[dateCol], loc)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &domain.InBodyRecord{TestDateTime: testDate}
+	for i, field := range fields {
+		if i >= len(cells) {
+			continue
+		}
+		raw := strings.TrimSpace(cells[i])
+		if raw == "" || raw == "-" {
+			continue // Not measured by this device
+		}
+		if decimalComma {
+			raw = strings.ReplaceAll(raw, ",", ".")
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("column %d: %q is not a number", i+1, cells[i])
+		}
+		field(record, v)
+	}
+	if record.Weight <= 0 {
+		return nil, errors.New("missing weight")
+	}
+	return record, nil
+}
+
+func parseScanCSVDate(raw string, loc *time.Location) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	for _, layout := range scanCSVDateLayouts {
+		if t, err := time.ParseInLocation(layout, raw, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised test date %q", raw)
+}
+
+func blankRow(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ImportCSV adds the scans of a LookinBody export to userID's history. A scan is a
+// duplicate when one with the same test time, to the minute, is already in the history
+// or earlier in the file. Valid scans are inserted together after all rows are checked.
+func (s *ScanServiceImpl) ImportCSV(ctx context.Context, userID string, data []byte, loc *time.Location) (*domain.ScanImportReport, error) {
+	if err := s.checkConsent(ctx, userID); err != nil {
+		return nil, err
+	}
+	rows, err := parseScanCSV(data, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repository.FindAllByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load existing scans: %w", err)
+	}
+	seen := make(map[time.Time]bool, len(existing)+len(rows))
+	for _, scan := range existing {
+		seen[scan.TestDateTime.UTC().Truncate(time.Minute)] = true
+	}
+
+	report := &domain.ScanImportReport{Rows: make([]domain.ScanImportRow, 0, len(rows))}
+	var records []*domain.InBodyRecord
+	var imported []int // Report rows of the records, in order
+	for _, row := range rows {
+		result := domain.ScanImportRow{Row: row.line}
+		switch {
+		case row.err != nil:
+			result.Status = domain.ScanImportInvalid
+			result.Error = row.err.Error()
+			report.Invalid++
+		case seen[row.record.TestDateTime.Truncate(time.Minute)]:
+			result.Status = domain.ScanImportDuplicate
+			result.TestDate = &row.record.TestDateTime
+			report.Duplicates++
+		default:
+			seen[row.record.TestDateTime.Truncate(time.Minute)] = true
+			row.record.UserID = userID
+			row.record.Metadata.Source = scanCSVSource
+			records = append(records, row.record)
+			imported = append(imported, len(report.Rows))
+			result.Status = domain.ScanImportImported
+			result.TestDate = &row.record.TestDateTime
+			report.Imported++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	if len(records) > 0 {
+		err := s.recordScanChange(ctx, userID, "", func(ctx context.Context) error {
+			return s.repository.CreateMany(ctx, records)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to save imported scans: %w", err)
+		}
+		for i, record := range records {
+			report.Rows[imported[i]].ScanID = record.ID
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const lookinBodyExport = "\xef\xbb\xbfLookinBody Export\n" +
+	"1. ID,2. Test Date / Time,3. Weight(kg),4. Skeletal Muscle Mass(kg),5. Body Fat Mass(kg),6. BMI(kg/m²),7. Percent Body Fat(%),8. Basal Metabolic Rate(kcal),9. Visceral Fat Level(Level),10. InBody Score,11. Lean Mass of Right Arm(kg),12. Lean Mass(%) of Right Arm,13. BFM of Trunk(kg)\n" +
+	"0001,2025.06.10 08:30:12,80.4,35.1,16.2,24.8,20.1,1720,7,78,3.4,102.5,8.1\n" +
+	"0001,2025.05.12 08:45:00,81.9,34.8,17.9,25.3,21.9,1705,8,75,3.3,99.8,8.9\n" +
+	"0001,2025.06.10 08:30:40,80.4,35.1,16.2,24.8,20.1,1720,7,78,3.4,102.5,8.1\n" +
+	"0001,yesterday,80.0,,,,,,,,,,\n" +
+	",,,,,,,,,,,,\n" +
+	"0001,2025.04.01 09:00:00,-,34.0,,,,,,,,,\n"
+
+func TestScanService_ImportCSV(t *testing.T) {
+	ctx := context.Background()
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	t.Run("imports new scans and reports duplicates and invalid rows", func(t *testing.T) {
+		svc, m := newTestScanService(t)
+		existing := &domain.InBodyRecord{ID: "scan-1", UserID: "member-1", TestDateTime: time.Date(2025, 5, 12, 1, 45, 30, 0, time.UTC)}
+		m.records.On("FindAllByUserID", anyCtx, "member-1").Return([]*domain.InBodyRecord{existing}, nil)
+
+		var saved []*domain.InBodyRecord
+		m.records.On("CreateMany", anyCtx, mock.AnythingOfType("[]*domain.InBodyRecord")).Run(func(args mock.Arguments) {
+			saved = args.Get(1).([]*domain.InBodyRecord)
+			saved[0].ID = "scan-2"
+		}).Return(nil)
+		m.cache.On("InvalidateUserCache", anyCtx, "member-1").Return(nil)
+		m.cache.On("InvalidateTrendRecap", anyCtx, "member-1").Return(nil)
+		m.cache.On("MarkScansChanged", anyCtx, "member-1", mock.Anything, scansChangedTTL).Return(nil)
+
+		report, err := svc.ImportCSV(ctx, "member-1", []byte(lookinBodyExport), jakarta)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, report.Imported)
+		assert.Equal(t, 2, report.Duplicates)
+		assert.Equal(t, 2, report.Invalid)
+		require.Len(t, report.Rows, 5)
+		assert.Equal(t, domain.ScanImportImported, report.Rows[0].Status)
+		assert.Equal(t, 3, report.Rows[0].Row)
+		assert.Equal(t, "scan-2", report.Rows[0].ScanID)
+		assert.Equal(t, domain.ScanImportDuplicate, report.Rows[1].Status) // Already in the history
+		assert.Equal(t, domain.ScanImportDuplicate, report.Rows[2].Status) // Same minute as row 3
+		assert.Equal(t, domain.ScanImportInvalid, report.Rows[3].Status)
+		assert.Contains(t, report.Rows[3].Error, "test date")
+		assert.Equal(t, "missing weight", report.Rows[4].Error)
+
+		require.Len(t, saved, 1)
+		scan := saved[0]
+		assert.Equal(t, "member-1", scan.UserID)
+		assert.Equal(t, time.Date(2025, 6, 10, 1, 30, 12, 0, time.UTC), scan.TestDateTime)
+		assert.Equal(t, 80.4, scan.Weight)
+		assert.Equal(t, 35.1, scan.SMM)
+		assert.Equal(t, 20.1, scan.PBF)
+		assert.Equal(t, 1720, scan.BMR)
+		assert.Equal(t, 7, scan.VisceralFatLevel)
+		assert.Equal(t, "lookinbody_csv", scan.Metadata.Source)
+		require.NotNil(t, scan.SegmentalLean)
+		assert.Equal(t, 3.4, scan.SegmentalLean.RightArm.Mass)
+		assert.Equal(t, 102.5, scan.SegmentalLean.RightArm.Percentage)
+		require.NotNil(t, scan.SegmentalFat)
+		assert.Equal(t, 8.1, scan.SegmentalFat.Trunk.Mass)
+	})
+
+	t.Run("reads semicolon exports with decimal commas", func(t *testing.T) {
+		svc, m := newTestScanService(t)
+		m.records.On("FindAllByUserID", anyCtx, "member-1").Return(nil, nil)
+		m.records.On("CreateMany", anyCtx, mock.MatchedBy(func(records []*domain.InBodyRecord) bool {
+			return len(records) == 1 && records[0].Weight == 72.3 && records[0].PBF == 18.5
+		})).Return(nil)
+		m.cache.On("InvalidateUserCache", anyCtx, "member-1").Return(nil)
+		m.cache.On("InvalidateTrendRecap", anyCtx, "member-1").Return(nil)
+		m.cache.On("MarkScansChanged", anyCtx, "member-1", mock.Anything, scansChangedTTL).Return(nil)
+
+		data := "Test Date / Time;Weight;PBF\n2025-06-10 08:30:00;72,3;18,5\n"
+		report, err := svc.ImportCSV(ctx, "member-1", []byte(data), time.UTC)
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Imported)
+	})
+
+	t.Run("rejects files without a scan header", func(t *testing.T) {
+		svc, _ := newTestScanService(t)
+
+		_, err := svc.ImportCSV(ctx, "member-1", []byte("name,email\nBudi,budi@example.com\n"), time.UTC)
+		assert.ErrorIs(t, err, domain.ErrInvalidScanCSV)
+	})
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// InstallmentService lets members pay a PT contract in installments. Each installment gets
+// its own VA; the payment webhook reports what came in, which may be part of an
+// installment. A contract whose installment stays unpaid past the grace period is
+// suspended until the member catches up.
+type InstallmentService struct {
+	invoiceRepo  domain.InvoiceRepository
+	contractRepo domain.PTContractRepository
+	provider     PaymentProvider
+	sandbox      *SandboxService
+	graceDays    int
+	renewals     *RenewalService      // Optional: see ActivateRenewals
+	receipts     *NotificationService // Optional: see SendReceipts
+	clock        domain.Clock
+}
+
+func NewInstallmentService(
+	invoiceRepo domain.InvoiceRepository,
+	contractRepo domain.PTContractRepository,
+	provider PaymentProvider,
+	sandbox *SandboxService,
+	graceDays int,
+	clk domain.Clock,
+) *InstallmentService {
+	return &InstallmentService{
+		invoiceRepo:  invoiceRepo,
+		contractRepo: contractRepo,
+		provider:     provider,
+		sandbox:      sandbox,
+		graceDays:    graceDays,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// ActivateRenewals credits the sessions of automatic renewals once their invoice is paid
+func (s *InstallmentService) ActivateRenewals(renewals *RenewalService) {
+	s.renewals = renewals
+}
+
+// SendReceipts tells members about every payment that comes in on their invoices
+func (s *InstallmentService) SendReceipts(notifier *NotificationService) {
+	s.receipts = notifier
+}
+
+// CreatePlan splits the contract's price into installments. graceDays of 0 uses the
+// configured default.
+func (s *InstallmentService) CreatePlan(ctx context.Context, tenantID, contractID string, installments []domain.Installment, graceDays int) (*domain.Invoice, error) {
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	if contract.TenantID != tenantID {
+		return nil, domain.ErrContractNotFound
+	}
+	if err := domain.ValidateInstallments(installments, contract.Price.Minor); err != nil {
+		return nil, err
+	}
+	if graceDays < 0 {
+		return nil, domain.ErrInvalidInstallments
+	}
+	if graceDays == 0 {
+		graceDays = s.graceDays
+	}
+
+	existing, err := s.invoiceRepo.GetByContractID(ctx, contract.ID)
+	if err == nil && existing.Status != domain.InvoiceStatusExpired && existing.Status != domain.InvoiceStatusFailed {
+		return nil, domain.ErrInstallmentPlanExists
+	}
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	invoice := &domain.Invoice{
+		UserID:       contract.MemberID,
+		TenantID:     contract.TenantID,
+		Amount:       contract.Price,
+		Status:       domain.InvoiceStatusPending,
+		ContractID:   contract.ID,
+		GraceDays:    graceDays,
+		BranchID:     contract.BranchID,
+		BranchPriced: contract.BranchPriced,
+	}
+	for i, inst := range installments {
+		invoice.Installments = append(invoice.Installments, domain.Installment{
+			Number:  i + 1,
+			Amount:  inst.Amount,
+			DueDate: inst.DueDate,
+		})
+	}
+	if err := s.invoiceRepo.Create(ctx, invoice); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// GetPlan returns the contract's installment plan for its tenant's admins
+func (s *InstallmentService) GetPlan(ctx context.Context, tenantID, contractID string) (*domain.Invoice, error) {
+	invoice, err := s.invoiceRepo.GetByContractID(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.TenantID != tenantID {
+		return nil, domain.ErrNotFound
+	}
+	return invoice, nil
+}
+
+// MyPlans lists the member's installment plans
+func (s *InstallmentService) MyPlans(ctx context.Context, userID string) ([]*domain.Invoice, error) {
+	invoices, err := s.invoiceRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	plans := []*domain.Invoice{}
+	for _, invoice := range invoices {
+		if invoice.IsInstallmentPlan() {
+			plans = append(plans, invoice)
+		}
+	}
+	return plans, nil
+}
+
+// Pay issues a VA for what is left of the member's next installment. A VA still valid for
+// the same installment and bank is returned again.
+func (s *InstallmentService) Pay(ctx context.Context, userID, invoiceID, method string) (*domain.Invoice, error) {
+	invoice, err := s.invoiceRepo.GetByID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.UserID != userID {
+		return nil, domain.ErrForbidden
+	}
+	if !invoice.IsInstallmentPlan() {
+		return nil, domain.ErrNotFound
+	}
+	next := invoice.NextInstallment()
+	if next == nil {
+		return nil, domain.ErrInstallmentPlanPaid
+	}
+
+	now := s.clock.Now()
+	if next.PaymentSessionID != "" && next.PaymentSessionID == invoice.PaymentSessionID &&
+		invoice.PaymentMethod == method && invoice.ExpiryDate.After(now) {
+		return invoice, nil
+	}
+
+	provider, err := s.sandbox.PaymentProvider(ctx, invoice.TenantID, s.provider)
+	if err != nil {
+		return nil, err
+	}
+	va, err := provider.GenerateVA(ctx, method, next.Outstanding(), userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate VA: %w", err)
+	}
+	invoice.VANumber = va.VANumber
+	invoice.PaymentMethod = method
+	invoice.PaymentSessionID = va.SessionID
+	invoice.ExpiryDate = va.ExpiresAt
+	next.PaymentSessionID = va.SessionID
+	if err := s.invoiceRepo.Update(ctx, invoice); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// RecordPayment applies a payment reported by the webhook for one of the plan's VAs, or for
+// the VA of a contract invoice paid in one go such as a renewal's. A
+// webhook retried for the same transaction is ignored. An amount of 0 (the provider didn't
+// say) counts as the installment the VA was issued for.
+func (s *InstallmentService) RecordPayment(ctx context.Context, invoice *domain.Invoice, sessionID string, trxID, amount int64) error {
+	if amount <= 0 {
+		for _, inst := range invoice.Installments {
+			if inst.PaymentSessionID == sessionID {
+				amount = inst.Outstanding()
+			}
+		}
+	}
+	if amount <= 0 {
+		if next := invoice.NextInstallment(); next != nil {
+			amount = next.Outstanding()
+		} else if !invoice.IsInstallmentPlan() {
+			amount = max(invoice.Amount.Minor-invoice.PaidAmount, 0)
+		}
+	}
+
+	payment := domain.InvoicePayment{
+		Reference: fmt.Sprintf("%s:%d", sessionID, trxID),
+		Amount:    amount,
+		PaidAt:    s.clock.Now(),
+		Channel:   domain.PaymentChannelProvider,
+	}
+	_, err := s.Apply(ctx, invoice, payment)
+	return err
+}
+
+// Apply records a payment against the invoice and reinstates its contract if that caught
+// the plan up, or activates it if it was a renewal waiting for the invoice to be paid. It
+// returns false, changing nothing, if the payment was already recorded.
+func (s *InstallmentService) Apply(ctx context.Context, invoice *domain.Invoice, payment domain.InvoicePayment) (bool, error) {
+	invoice.ApplyPayment(payment)
+	recorded, err := s.invoiceRepo.RecordPayment(ctx, invoice, payment)
+	if err != nil {
+		return false, err
+	}
+	if !recorded {
+		log.Printf("[Installments] Payment %s on invoice %s already recorded", payment.Reference, invoice.ID)
+		return false, nil
+	}
+	log.Printf("[Installments] Invoice %s received %d (%d of %d paid), status %s",
+		invoice.ID, payment.Amount, invoice.PaidAmount, invoice.Amount.Minor, invoice.Status)
+	s.Receipt(ctx, invoice, payment)
+
+	if s.renewals != nil && invoice.Status == domain.InvoiceStatusPaid {
+		if err := s.renewals.Activate(ctx, invoice); err != nil {
+			return true, err
+		}
+	}
+	if invoice.SuspendedAt != nil && !s.pastGrace(invoice) {
+		return true, s.reinstate(ctx, invoice)
+	}
+	return true, nil
+}
+
+// Receipt tells the member the payment on their invoice came in, and what is left to pay on
+// an installment plan. A failure is only logged: the payment is recorded either way.
+func (s *InstallmentService) Receipt(ctx context.Context, invoice *domain.Invoice, payment domain.InvoicePayment) {
+	if s.receipts == nil || invoice.UserID == "" {
+		return
+	}
+	amount := domain.NewMoney(payment.Amount, invoice.Amount.Currency).String()
+	body := "We received your payment of " + amount + ". Thank you!"
+	if outstanding := invoice.Amount.Minor - invoice.PaidAmount; invoice.IsInstallmentPlan() && outstanding > 0 {
+		body += " " + domain.NewMoney(outstanding, invoice.Amount.Currency).String() + " is left to pay on your plan"
+	}
+	reference := payment.Receipt
+	if reference == "" {
+		reference = payment.Reference
+	}
+	n := &domain.Notification{
+		UserID:   invoice.UserID,
+		TenantID: invoice.TenantID,
+		Type:     domain.NotificationInvoiceReceipt,
+		Title:    "Payment received",
+		Body:     body,
+		Data:     map[string]string{"invoice_id": invoice.ID, "amount": amount, "reference": reference},
+		Link:     &domain.DeepLink{Screen: domain.ScreenInvoice, InvoiceID: invoice.ID},
+	}
+	if err := s.receipts.Notify(ctx, n); err != nil {
+		log.Printf("Warning: receipt for invoice %s not sent: %v", invoice.ID, err)
+	}
+}
+
+// EnforceDue suspends the contracts of plans with an installment overdue beyond the grace
+// period, and reinstates suspended ones that caught up. It returns how many of each.
+func (s *InstallmentService) EnforceDue(ctx context.Context) (suspended, reinstated int, err error) {
+	plans, err := s.invoiceRepo.ListInstallmentPlansDue(ctx, s.clock.Now())
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, plan := range plans {
+		switch overdue := s.pastGrace(plan); {
+		case overdue && plan.SuspendedAt == nil:
+			done, err := s.suspend(ctx, plan)
+			if err != nil {
+				return suspended, reinstated, err
+			}
+			if done {
+				suspended++
+			}
+		case !overdue && plan.SuspendedAt != nil:
+			if err := s.reinstate(ctx, plan); err != nil {
+				return suspended, reinstated, err
+			}
+			reinstated++
+		}
+	}
+	return suspended, reinstated, nil
+}
+
+// pastGrace reports whether the plan's earliest unpaid installment is overdue by more
+// than its grace period
+func (s *InstallmentService) pastGrace(plan *domain.Invoice) bool {
+	due, overdue := plan.OverdueSince(s.clock.Now())
+	return overdue && s.clock.Now().After(due.AddDate(0, 0, plan.GraceDays))
+}
+
+// suspend stops bookings on an active contract. Contracts that are already depleted or
+// expired have nothing left to book and are left alone.
+func (s *InstallmentService) suspend(ctx context.Context, plan *domain.Invoice) (bool, error) {
+	contract, err := s.contractRepo.GetByID(ctx, plan.ContractID)
+	if err != nil {
+		return false, err
+	}
+	if contract.Status != domain.PackageStatusActive {
+		return false, nil
+	}
+	if err := s.contractRepo.UpdateStatus(ctx, contract.ID, domain.PackageStatusSuspended); err != nil {
+		return false, err
+	}
+	now := s.clock.Now()
+	if err := s.invoiceRepo.SetSuspended(ctx, plan.ID, &now); err != nil {
+		return false, err
+	}
+	plan.SuspendedAt = &now
+	log.Printf("[Installments] Contract %s suspended: installment of invoice %s overdue", contract.ID, plan.ID)
+	return true, nil
+}
+
+// reinstate reactivates a contract suspended over the plan
+func (s *InstallmentService) reinstate(ctx context.Context, plan *domain.Invoice) error {
+	contract, err := s.contractRepo.GetByID(ctx, plan.ContractID)
+	if err != nil {
+		return err
+	}
+	if contract.Status == domain.PackageStatusSuspended {
+		if err := s.contractRepo.UpdateStatus(ctx, contract.ID, domain.PackageStatusActive); err != nil {
+			return err
+		}
+	}
+	if err := s.invoiceRepo.SetSuspended(ctx, plan.ID, nil); err != nil {
+		return err
+	}
+	plan.SuspendedAt = nil
+	log.Printf("[Installments] Contract %s reinstated: invoice %s caught up", contract.ID, plan.ID)
+	return nil
+}
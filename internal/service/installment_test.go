@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newInstallmentService(t *testing.T) (*InstallmentService, *mocks.InvoiceRepository, *mocks.PTContractRepository) {
+	invoices, contracts := mocks.NewInvoiceRepository(t), mocks.NewPTContractRepository(t)
+	tenants := mocks.NewTenantRepository(t)
+	tenants.On("GetByID", mock.Anything, mock.Anything).Return(&domain.Tenant{}, nil).Maybe()
+	sandbox := NewSandboxService(tenants, nil, nil, clock.NewFake(testNow))
+	return NewInstallmentService(invoices, contracts, NewMockPaymentProvider(clock.NewFake(testNow)), sandbox, 7, clock.NewFake(testNow)), invoices, contracts
+}
+
+// threePart is a 3,000,000 plan with the first installment due at due
+func threePart(due time.Time) *domain.Invoice {
+	return &domain.Invoice{ID: "inv-1", UserID: "m1", TenantID: "gym", ContractID: "k1", Amount: domain.NewMoney(3_000_000, "IDR"), GraceDays: 7,
+		Status: domain.InvoiceStatusPending,
+		Installments: []domain.Installment{
+			{Number: 1, Amount: 1_000_000, DueDate: due},
+			{Number: 2, Amount: 1_000_000, DueDate: due.AddDate(0, 1, 0)},
+			{Number: 3, Amount: 1_000_000, DueDate: due.AddDate(0, 2, 0)},
+		}}
+}
+
+func TestInstallmentService_CreatePlan(t *testing.T) {
+	ctx := context.Background()
+	svc, invoices, contracts := newInstallmentService(t)
+	contracts.On("GetByID", ctx, "k1").Return(&domain.PTContract{ID: "k1", TenantID: "gym", MemberID: "m1", Price: domain.NewMoney(3_000_000, "IDR")}, nil)
+	parts := threePart(testNow).Installments
+
+	t.Run("splits the contract price", func(t *testing.T) {
+		invoices.On("GetByContractID", ctx, "k1").Return(nil, domain.ErrNotFound).Once()
+		invoices.On("Create", ctx, mock.Anything).Return(nil).Once()
+
+		plan, err := svc.CreatePlan(ctx, "gym", "k1", parts, 0)
+		require.NoError(t, err)
+		assert.Equal(t, "m1", plan.UserID)
+		assert.Equal(t, domain.NewMoney(3_000_000, "IDR"), plan.Amount)
+		assert.Equal(t, 7, plan.GraceDays)
+		assert.Equal(t, 3, plan.Installments[2].Number)
+	})
+
+	t.Run("one plan per contract", func(t *testing.T) {
+		invoices.On("GetByContractID", ctx, "k1").Return(threePart(testNow), nil).Once()
+
+		_, err := svc.CreatePlan(ctx, "gym", "k1", parts, 0)
+		assert.ErrorIs(t, err, domain.ErrInstallmentPlanExists)
+	})
+
+	t.Run("amounts must add up and the contract be the tenant's", func(t *testing.T) {
+		_, err := svc.CreatePlan(ctx, "gym", "k1", parts[:2], 0)
+		assert.ErrorIs(t, err, domain.ErrInvalidInstallments)
+		_, err = svc.CreatePlan(ctx, "other", "k1", parts, 0)
+		assert.ErrorIs(t, err, domain.ErrContractNotFound)
+	})
+}
+
+func TestInstallmentService_Pay(t *testing.T) {
+	ctx := context.Background()
+	svc, invoices, _ := newInstallmentService(t)
+	plan := threePart(testNow)
+	plan.Installments[0].PaidAmount = 400_000
+	invoices.On("GetByID", ctx, "inv-1").Return(plan, nil)
+	invoices.On("Update", ctx, plan).Return(nil).Once()
+
+	got, err := svc.Pay(ctx, "m1", "inv-1", "BCA")
+	require.NoError(t, err)
+	assert.NotEmpty(t, got.VANumber)
+	assert.Equal(t, got.PaymentSessionID, got.Installments[0].PaymentSessionID)
+
+	_, err = svc.Pay(ctx, "m2", "inv-1", "BCA")
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestInstallmentService_RecordPayment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("a partial payment is tracked", func(t *testing.T) {
+		svc, invoices, _ := newInstallmentService(t)
+		plan := threePart(testNow.AddDate(0, 0, 3))
+		invoices.On("RecordPayment", ctx, plan, domain.InvoicePayment{Reference: "sid-1:42", Amount: 500_000, PaidAt: testNow, Channel: domain.PaymentChannelProvider}).
+			Return(true, nil).Once()
+
+		require.NoError(t, svc.RecordPayment(ctx, plan, "sid-1", 42, 500_000))
+		assert.Equal(t, domain.InvoiceStatusPartiallyPaid, plan.Status)
+		assert.Equal(t, int64(500_000), plan.Installments[0].PaidAmount)
+	})
+
+	t.Run("catching up reinstates a suspended contract", func(t *testing.T) {
+		svc, invoices, contracts := newInstallmentService(t)
+		plan := threePart(testNow.AddDate(0, 0, -10))
+		suspendedAt := testNow.AddDate(0, 0, -2)
+		plan.SuspendedAt = &suspendedAt
+		plan.Installments[0].PaymentSessionID = "sid-2"
+		invoices.On("RecordPayment", ctx, plan, mock.Anything).Return(true, nil).Once()
+		contracts.On("GetByID", ctx, "k1").Return(&domain.PTContract{ID: "k1", Status: domain.PackageStatusSuspended}, nil)
+		contracts.On("UpdateStatus", ctx, "k1", domain.PackageStatusActive).Return(nil).Once()
+		invoices.On("SetSuspended", ctx, "inv-1", (*time.Time)(nil)).Return(nil).Once()
+
+		// No amount reported: the installment the VA was for
+		require.NoError(t, svc.RecordPayment(ctx, plan, "sid-2", 7, 0))
+		assert.NotNil(t, plan.Installments[0].PaidAt)
+		assert.Nil(t, plan.SuspendedAt)
+	})
+
+	t.Run("a retried webhook changes nothing", func(t *testing.T) {
+		svc, invoices, _ := newInstallmentService(t)
+		plan := threePart(testNow.AddDate(0, 0, -10))
+		suspendedAt := testNow
+		plan.SuspendedAt = &suspendedAt
+		invoices.On("RecordPayment", ctx, plan, mock.Anything).Return(false, nil).Once()
+
+		require.NoError(t, svc.RecordPayment(ctx, plan, "sid-1", 42, 1_000_000))
+	})
+}
+
+func TestInstallmentService_EnforceDue(t *testing.T) {
+	ctx := context.Background()
+	svc, invoices, contracts := newInstallmentService(t)
+
+	late := threePart(testNow.AddDate(0, 0, -8)) // Past the 7 day grace period
+	late.ID, late.ContractID = "late", "k-late"
+	graced := threePart(testNow.AddDate(0, 0, -3))
+	graced.ID, graced.ContractID = "graced", "k-graced"
+	depleted := threePart(testNow.AddDate(0, 0, -30))
+	depleted.ID, depleted.ContractID = "depleted", "k-depleted"
+	caughtUp := threePart(testNow.AddDate(0, 0, -20))
+	caughtUp.ID, caughtUp.ContractID = "caught-up", "k-caught-up"
+	caughtUp.Installments[0].PaidAmount = 1_000_000
+	suspendedAt := testNow.AddDate(0, 0, -5)
+	caughtUp.SuspendedAt = &suspendedAt
+
+	invoices.On("ListInstallmentPlansDue", ctx, testNow).Return([]*domain.Invoice{late, graced, depleted, caughtUp}, nil)
+	contracts.On("GetByID", ctx, "k-late").Return(&domain.PTContract{ID: "k-late", Status: domain.PackageStatusActive}, nil)
+	contracts.On("UpdateStatus", ctx, "k-late", domain.PackageStatusSuspended).Return(nil).Once()
+	invoices.On("SetSuspended", ctx, "late", &testNow).Return(nil).Once()
+	contracts.On("GetByID", ctx, "k-depleted").Return(&domain.PTContract{ID: "k-depleted", Status: domain.PackageStatusDepleted}, nil)
+	contracts.On("GetByID", ctx, "k-caught-up").Return(&domain.PTContract{ID: "k-caught-up", Status: domain.PackageStatusSuspended}, nil)
+	contracts.On("UpdateStatus", ctx, "k-caught-up", domain.PackageStatusActive).Return(nil).Once()
+	invoices.On("SetSuspended", ctx, "caught-up", (*time.Time)(nil)).Return(nil).Once()
+
+	suspended, reinstated, err := svc.EnforceDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, suspended)
+	assert.Equal(t, 1, reinstated)
+}
+
+func TestInstallmentService_Receipt(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newInstallmentService(t)
+	prefs := mocks.NewNotificationPreferencesRepository(t)
+	email := mocks.NewNotificationSender(t)
+	email.On("Channel").Return(domain.ChannelEmail).Maybe()
+	svc.SendReceipts(NewNotificationService(prefs, nil, clock.NewFake(testNow), email))
+
+	plan := threePart(testNow)
+	plan.PaidAmount = 1_000_000
+	prefs.On("GetUser", ctx, "m1").Return(&domain.NotificationPreferences{UserID: "m1"}, nil)
+	email.On("Send", ctx, mock.MatchedBy(func(sent *domain.Notification) bool {
+		return sent.Type == domain.NotificationInvoiceReceipt && sent.Data["reference"] == "sid-1:42" &&
+			sent.Link.InvoiceID == "inv-1" && strings.Contains(sent.Body, "left to pay")
+	})).Return(nil).Once()
+
+	svc.Receipt(ctx, plan, domain.InvoicePayment{Reference: "sid-1:42", Amount: 1_000_000, PaidAt: testNow})
+
+	// Invoices without a member have nobody to tell
+	svc.Receipt(ctx, &domain.Invoice{ID: "inv-2"}, domain.InvoicePayment{Amount: 1})
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// invoiceExpiryReminder is how long before a checkout's VA expires the member is reminded
+// to pay it
+const invoiceExpiryReminder = 24 * time.Hour
+
+// invoiceReminderMinAge spares members who just checked out, and still have the VA in front
+// of them, a reminder. VAs usually last a day, so most are reminded soon after.
+const invoiceReminderMinAge = time.Hour
+
+// InvoiceExpiryService closes out checkout invoices left unpaid: members are reminded before
+// the VA expires, the invoice is marked expired after, and a member who checks out the same
+// package again gets the invoice back with a new VA. Invoices of PT contracts are left to
+// InstallmentService, which issues their VAs per installment.
+type InvoiceExpiryService struct {
+	invoiceRepo domain.InvoiceRepository
+	provider    PaymentProvider
+	sandbox     *SandboxService
+	notifier    *NotificationService
+	clock       domain.Clock
+}
+
+func NewInvoiceExpiryService(
+	invoiceRepo domain.InvoiceRepository,
+	provider PaymentProvider,
+	sandbox *SandboxService,
+	notifier *NotificationService,
+	clk domain.Clock,
+) *InvoiceExpiryService {
+	return &InvoiceExpiryService{
+		invoiceRepo: invoiceRepo,
+		provider:    provider,
+		sandbox:     sandbox,
+		notifier:    notifier,
+		clock:       clock.OrReal(clk),
+	}
+}
+
+// ExpireDue expires the pending checkout invoices whose VA expired and reminds the members
+// whose VA expires within a day. Each invoice is reminded once per VA.
+func (s *InvoiceExpiryService) ExpireDue(ctx context.Context) (reminded, expired int, err error) {
+	now := s.clock.Now()
+	invoices, err := s.invoiceRepo.ListCheckoutsExpiringBefore(ctx, now, now.Add(invoiceExpiryReminder))
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, invoice := range invoices {
+		if !invoice.ExpiryDate.After(now) {
+			marked, err := s.invoiceRepo.Expire(ctx, invoice.ID, now)
+			if err != nil {
+				return reminded, expired, fmt.Errorf("failed to expire invoice %s: %w", invoice.ID, err)
+			}
+			if marked {
+				expired++
+			}
+			continue
+		}
+
+		if invoice.RemindedAt != nil || now.Sub(invoice.UpdatedAt) < invoiceReminderMinAge {
+			continue
+		}
+		marked, err := s.invoiceRepo.MarkReminded(ctx, invoice.ID, now)
+		if err != nil {
+			return reminded, expired, err
+		}
+		if !marked {
+			continue
+		}
+		reminded++
+		s.remind(ctx, invoice)
+	}
+	return reminded, expired, nil
+}
+
+// Reopen gives back the member's lapsed checkout of the package, due at the package's price
+// with a new VA at method, or nil if they have none. A checkout retried after its VA expired
+// thus continues the same invoice instead of leaving it behind.
+func (s *InvoiceExpiryService) Reopen(ctx context.Context, tenantID, userID string, pkg *domain.Package, method string) (*domain.Invoice, error) {
+	invoice, err := s.invoiceRepo.GetLapsedCheckout(ctx, userID, pkg.ID, s.clock.Now())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := s.sandbox.PaymentProvider(ctx, tenantID, s.provider)
+	if err != nil {
+		return nil, err
+	}
+	va, err := provider.GenerateVA(ctx, method, pkg.Price.Minor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	previousSessionID := invoice.PaymentSessionID
+	invoice.Amount = pkg.Price
+	invoice.PaymentMethod = method
+	invoice.VANumber = va.VANumber
+	invoice.PaymentSessionID = va.SessionID
+	invoice.ExpiryDate = va.ExpiresAt
+	reopened, err := s.invoiceRepo.Reopen(ctx, invoice, previousSessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !reopened {
+		return nil, nil // Paid or reopened by another request meanwhile
+	}
+	log.Printf("[Payments] Invoice %s reopened with a new %s VA", invoice.ID, method)
+	return invoice, nil
+}
+
+// remind tells the member to pay, logging failures: the invoice is already marked, so
+// failing the run wouldn't send it again
+func (s *InvoiceExpiryService) remind(ctx context.Context, invoice *domain.Invoice) {
+	err := s.notifier.Notify(ctx, &domain.Notification{
+		UserID:   invoice.UserID,
+		TenantID: invoice.TenantID,
+		Type:     domain.NotificationInvoiceExpiring,
+		Title:    "Payment pending",
+		Body: fmt.Sprintf("Pay %s to %s VA %s before %s UTC to complete your purchase.",
+			invoice.Amount, invoice.PaymentMethod, invoice.VANumber, invoice.ExpiryDate.UTC().Format("2 Jan 15:04")),
+		Data: map[string]string{"invoice_id": invoice.ID, "package_id": invoice.PackageID},
+		Link: &domain.DeepLink{Screen: domain.ScreenInvoice, InvoiceID: invoice.ID},
+	})
+	if err != nil {
+		log.Printf("Warning: payment reminder for invoice %s not sent: %v", invoice.ID, err)
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestInvoiceExpiryService(t *testing.T) (*InvoiceExpiryService, *mocks.InvoiceRepository, *mocks.NotificationSender) {
+	invoices, push := mocks.NewInvoiceRepository(t), mocks.NewNotificationSender(t)
+	tenants := mocks.NewTenantRepository(t)
+	tenants.On("GetByID", mock.Anything, mock.Anything).Return(&domain.Tenant{}, nil).Maybe()
+	prefs := mocks.NewNotificationPreferencesRepository(t)
+	prefs.On("GetUser", mock.Anything, mock.Anything).Return(&domain.NotificationPreferences{}, nil).Maybe()
+	push.On("Channel").Return(domain.ChannelPush).Maybe()
+
+	svc := NewInvoiceExpiryService(invoices, NewMockPaymentProvider(clock.NewFake(testNow)),
+		NewSandboxService(tenants, nil, nil, clock.NewFake(testNow)),
+		NewNotificationService(prefs, nil, clock.NewFake(testNow), push), clock.NewFake(testNow))
+	return svc, invoices, push
+}
+
+// checkout is a pending checkout of the monthly package by m1 whose VA was issued at issued
+// and expires a day later
+func checkout(id string, issued time.Time) *domain.Invoice {
+	return &domain.Invoice{ID: id, UserID: "m1", TenantID: "gym", PackageID: "monthly", Amount: domain.NewMoney(500_000, "IDR"),
+		Status: domain.InvoiceStatusPending, PaymentMethod: "BCA", VANumber: "8888-BCA-" + id, PaymentSessionID: "sid-" + id,
+		UpdatedAt: issued, ExpiryDate: issued.Add(24 * time.Hour)}
+}
+
+func TestInvoiceExpiryService_ExpireDue(t *testing.T) {
+	ctx := context.Background()
+	svc, invoices, push := newTestInvoiceExpiryService(t)
+
+	lapsed := checkout("inv-1", testNow.Add(-25*time.Hour))
+	expiring := checkout("inv-2", testNow.Add(-20*time.Hour))
+	reminded := checkout("inv-3", testNow.Add(-20*time.Hour))
+	reminded.RemindedAt = &testNow // Reminded while the list was read
+	fresh := checkout("inv-4", testNow.Add(-10*time.Minute))
+	invoices.On("ListCheckoutsExpiringBefore", ctx, testNow, testNow.Add(invoiceExpiryReminder)).
+		Return([]*domain.Invoice{lapsed, expiring, reminded, fresh}, nil)
+	invoices.On("Expire", ctx, "inv-1", testNow).Return(true, nil).Once()
+	invoices.On("MarkReminded", ctx, "inv-2", testNow).Return(true, nil).Once()
+	push.On("Send", anyCtx, mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.Type == domain.NotificationInvoiceExpiring && n.UserID == "m1" && n.Link.InvoiceID == "inv-2" &&
+			strings.HasPrefix(n.Body, "Pay IDR 500000 to BCA VA 8888-BCA-inv-2 before 16 Jun 14:00 UTC")
+	})).Return(nil).Once()
+
+	remindedCount, expired, err := svc.ExpireDue(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, remindedCount, "reminded once, and not right after checking out")
+	assert.Equal(t, 1, expired)
+}
+
+func TestInvoiceExpiryService_Reopen(t *testing.T) {
+	ctx := context.Background()
+	pkg := &domain.Package{ID: "monthly", Price: domain.NewMoney(550_000, "IDR"), IsActive: true}
+
+	t.Run("a lapsed checkout gets a new VA at the current price", func(t *testing.T) {
+		svc, invoices, _ := newTestInvoiceExpiryService(t)
+		lapsed := checkout("inv-1", testNow.Add(-48*time.Hour))
+		lapsed.Status = domain.InvoiceStatusExpired
+		invoices.On("GetLapsedCheckout", ctx, "m1", "monthly", testNow).Return(lapsed, nil)
+		invoices.On("Reopen", ctx, mock.MatchedBy(func(inv *domain.Invoice) bool {
+			return inv.ID == "inv-1" && inv.PaymentMethod == "Mandiri" && strings.HasPrefix(inv.VANumber, "8888-MOCK-MDR-") &&
+				inv.PaymentSessionID != "sid-inv-1" && inv.Amount.Minor == 550_000
+		}), "sid-inv-1").Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Invoice).Status = domain.InvoiceStatusPending
+		}).Return(true, nil).Once()
+
+		invoice, err := svc.Reopen(ctx, "gym", "m1", pkg, "Mandiri")
+
+		require.NoError(t, err)
+		require.NotNil(t, invoice)
+		assert.Equal(t, domain.InvoiceStatusPending, invoice.Status)
+	})
+
+	t.Run("nothing to reopen", func(t *testing.T) {
+		svc, invoices, _ := newTestInvoiceExpiryService(t)
+		invoices.On("GetLapsedCheckout", ctx, "m1", "monthly", testNow).Return(nil, domain.ErrNotFound)
+
+		invoice, err := svc.Reopen(ctx, "gym", "m1", pkg, "BCA")
+
+		require.NoError(t, err)
+		assert.Nil(t, invoice)
+	})
+
+	t.Run("an invoice paid meanwhile isn't reopened", func(t *testing.T) {
+		svc, invoices, _ := newTestInvoiceExpiryService(t)
+		invoices.On("GetLapsedCheckout", ctx, "m1", "monthly", testNow).Return(checkout("inv-1", testNow.Add(-48*time.Hour)), nil)
+		invoices.On("Reopen", ctx, mock.Anything, "sid-inv-1").Return(false, nil).Once()
+
+		invoice, err := svc.Reopen(ctx, "gym", "m1", pkg, "BCA")
+
+		require.NoError(t, err)
+		assert.Nil(t, invoice)
+	})
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// Join code throttling. Users get a few free mistakes; after that each failure doubles the
+// wait. IPs get more slack because a whole gym may share one.
+const (
+	joinFreeFailuresPerUser = 5
+	joinFreeFailuresPerIP   = 20
+	joinBaseDelay           = time.Second
+	joinMaxDelay            = 15 * time.Minute
+	joinFailureWindow       = 24 * time.Hour
+	joinAuditEvery          = 5 // Log an audit line every this many failures of a key
+)
+
+// JoinCodeGuard throttles join-code guessing per user and per IP
+type JoinCodeGuard struct {
+	attempts domain.AttemptTracker
+	clock    domain.Clock
+}
+
+func NewJoinCodeGuard(attempts domain.AttemptTracker, clk domain.Clock) *JoinCodeGuard {
+	return &JoinCodeGuard{attempts: attempts, clock: clock.OrReal(clk)}
+}
+
+// Check returns ErrTooManyAttempts and how long to wait while the user or IP is throttled
+func (g *JoinCodeGuard) Check(ctx context.Context, userID, ip string) (time.Duration, error) {
+	var wait time.Duration
+	for _, k := range g.keys(userID, ip) {
+		state, err := g.attempts.Get(ctx, k.key)
+		if err != nil {
+			return 0, err
+		}
+		wait = max(wait, joinDelay(state.Failures, k.free)-g.clock.Now().Sub(state.LastFailureAt))
+	}
+	if wait > 0 {
+		return wait, domain.ErrTooManyAttempts
+	}
+	return 0, nil
+}
+
+// Failed records a wrong code for the user and IP
+func (g *JoinCodeGuard) Failed(ctx context.Context, userID, ip, scope string) {
+	now := g.clock.Now()
+	for _, k := range g.keys(userID, ip) {
+		state, err := g.attempts.RecordFailure(ctx, k.key, now, joinFailureWindow)
+		if err != nil {
+			log.Printf("Warning: failed to record join attempt: %v", err)
+			continue
+		}
+		if state.Failures >= k.free && state.Failures%joinAuditEvery == 0 {
+			log.Printf("[Audit] repeated invalid %s join codes: %s failures=%d user=%s ip=%s", scope, k.key, state.Failures, userID, ip)
+		}
+	}
+}
+
+// Succeeded clears the user's failures. The IP's are kept: one member joining from the
+// gym's Wi-Fi shouldn't reset a guessing run from the same network.
+func (g *JoinCodeGuard) Succeeded(ctx context.Context, userID string) {
+	if err := g.attempts.Reset(ctx, "join:user:"+userID); err != nil {
+		log.Printf("Warning: failed to reset join attempts: %v", err)
+	}
+}
+
+type joinKey struct {
+	key  string
+	free int
+}
+
+func (g *JoinCodeGuard) keys(userID, ip string) []joinKey {
+	keys := []joinKey{{key: "join:user:" + userID, free: joinFreeFailuresPerUser}}
+	if ip != "" {
+		keys = append(keys, joinKey{key: "join:ip:" + ip, free: joinFreeFailuresPerIP})
+	}
+	return keys
+}
+
+// joinDelay is the wait imposed after the given number of failures
+func joinDelay(failures, free int) time.Duration {
+	if failures < free {
+		return 0
+	}
+	d := joinBaseDelay
+	for i := free; i < failures && d < joinMaxDelay; i++ {
+		d *= 2
+	}
+	return min(d, joinMaxDelay)
+}
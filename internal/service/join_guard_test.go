@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoinCodeGuard_Check(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("free failures are not throttled", func(t *testing.T) {
+		attempts := mocks.NewAttemptTracker(t)
+		attempts.On("Get", ctx, "join:user:u1").Return(&domain.AttemptState{Failures: 4, LastFailureAt: testNow}, nil)
+		attempts.On("Get", ctx, "join:ip:10.0.0.1").Return(&domain.AttemptState{}, nil)
+
+		_, err := NewJoinCodeGuard(attempts, clock.NewFake(testNow)).Check(ctx, "u1", "10.0.0.1")
+
+		require.NoError(t, err)
+	})
+
+	t.Run("waits double with each failure past the free ones", func(t *testing.T) {
+		attempts := mocks.NewAttemptTracker(t)
+		// 8 failures = 3 past the free 5: 8s, of which 3s have passed
+		attempts.On("Get", ctx, "join:user:u1").Return(&domain.AttemptState{Failures: 8, LastFailureAt: testNow.Add(-3 * time.Second)}, nil)
+		attempts.On("Get", ctx, "join:ip:10.0.0.1").Return(&domain.AttemptState{}, nil)
+
+		wait, err := NewJoinCodeGuard(attempts, clock.NewFake(testNow)).Check(ctx, "u1", "10.0.0.1")
+
+		assert.ErrorIs(t, err, domain.ErrTooManyAttempts)
+		assert.Equal(t, 5*time.Second, wait)
+	})
+
+	t.Run("an IP guessing across accounts is throttled", func(t *testing.T) {
+		attempts := mocks.NewAttemptTracker(t)
+		attempts.On("Get", ctx, "join:user:fresh").Return(&domain.AttemptState{}, nil)
+		attempts.On("Get", ctx, "join:ip:10.0.0.1").Return(&domain.AttemptState{Failures: 40, LastFailureAt: testNow}, nil)
+
+		wait, err := NewJoinCodeGuard(attempts, clock.NewFake(testNow)).Check(ctx, "fresh", "10.0.0.1")
+
+		assert.ErrorIs(t, err, domain.ErrTooManyAttempts)
+		assert.Equal(t, joinMaxDelay, wait)
+	})
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/oklog/ulid/v2"
+)
+
+// ManualPaymentService records payments the front desk takes in cash or by bank transfer,
+// either against a contract's invoice or by selling a contract paid on the spot, and
+// reconciles them with what came in through the payment provider.
+type ManualPaymentService struct {
+	invoiceRepo  domain.InvoiceRepository
+	ptService    *PTService
+	installments *InstallmentService
+	clock        domain.Clock
+}
+
+func NewManualPaymentService(
+	invoiceRepo domain.InvoiceRepository,
+	ptService *PTService,
+	installments *InstallmentService,
+	clk domain.Clock,
+) *ManualPaymentService {
+	return &ManualPaymentService{
+		invoiceRepo:  invoiceRepo,
+		ptService:    ptService,
+		installments: installments,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// PayInvoice records a manual payment against one of the tenant's contract invoices.
+// Installment plans take any amount up to what is outstanding, an amount of 0 paying the
+// next installment; other invoices are paid in full.
+func (s *ManualPaymentService) PayInvoice(ctx context.Context, tenantID, actorID, invoiceID string, payment domain.InvoicePayment) (*domain.Invoice, error) {
+	if !domain.IsManualPaymentChannel(payment.Channel) {
+		return nil, domain.ErrInvalidPaymentChannel
+	}
+	invoice, err := s.invoiceRepo.GetByID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.TenantID != tenantID {
+		return nil, domain.ErrNotFound
+	}
+	// Pro subscriptions are fulfilled by the payment webhook and are not the gym's to collect
+	if invoice.ContractID == "" {
+		return nil, domain.ErrNotContractInvoice
+	}
+
+	outstanding := max(invoice.Amount.Minor-invoice.PaidAmount, 0)
+	if invoice.IsInstallmentPlan() {
+		outstanding = 0
+		for _, inst := range invoice.Installments {
+			outstanding += inst.Outstanding()
+		}
+	}
+	if invoice.Status == domain.InvoiceStatusPaid || outstanding == 0 {
+		return nil, domain.ErrInvoiceAlreadyPaid
+	}
+
+	if payment.Amount == 0 {
+		payment.Amount = outstanding
+		if next := invoice.NextInstallment(); next != nil {
+			payment.Amount = next.Outstanding()
+		}
+	}
+	if payment.Amount < 0 || payment.Amount > outstanding ||
+		(!invoice.IsInstallmentPlan() && payment.Amount != outstanding) {
+		return nil, domain.ErrInvalidManualPayment
+	}
+
+	s.stamp(&payment, actorID)
+	if _, err := s.installments.Apply(ctx, invoice, payment); err != nil {
+		return nil, err
+	}
+	log.Printf("[Audit] %s payment of %d on invoice %s recorded by %s (receipt %q)",
+		payment.Channel, payment.Amount, invoice.ID, actorID, payment.Receipt)
+	return invoice, nil
+}
+
+// SellContract creates a contract from its package and an invoice for it paid in full at
+// the package price.
+func (s *ManualPaymentService) SellContract(ctx context.Context, tenantID, actorID string, contract *domain.PTContract, payment domain.InvoicePayment) (*domain.Invoice, error) {
+	if !domain.IsManualPaymentChannel(payment.Channel) {
+		return nil, domain.ErrInvalidPaymentChannel
+	}
+	contract.TenantID = tenantID
+	if err := s.ptService.CreateContract(ctx, contract); err != nil {
+		return nil, err
+	}
+
+	payment.Amount = contract.Price.Minor
+	invoice := &domain.Invoice{
+		UserID:       contract.MemberID,
+		TenantID:     tenantID,
+		Amount:       contract.Price,
+		Status:       domain.InvoiceStatusPending,
+		ContractID:   contract.ID,
+		BranchID:     contract.BranchID,
+		BranchPriced: contract.BranchPriced,
+	}
+	s.stamp(&payment, actorID)
+	invoice.ApplyPayment(payment)
+	if err := s.invoiceRepo.Create(ctx, invoice); err != nil {
+		return nil, err
+	}
+	log.Printf("[Audit] contract %s sold for %d (%s) by %s (receipt %q)",
+		contract.ID, payment.Amount, payment.Channel, actorID, payment.Receipt)
+	s.installments.Receipt(ctx, invoice, payment)
+	return invoice, nil
+}
+
+// Reconcile totals the tenant's payments in [from, to) by channel and by branch. Paid
+// invoices that predate payment tracking count as one provider payment at their last update.
+func (s *ManualPaymentService) Reconcile(ctx context.Context, tenantID string, from, to time.Time) (*domain.PaymentReconciliation, error) {
+	invoices, err := s.invoiceRepo.ListPaidBetween(ctx, tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.PaymentReconciliation{
+		Currency:  domain.DefaultCurrency,
+		From:      from,
+		To:        to,
+		ByChannel: map[string]domain.PaymentTotal{},
+		ByBranch:  map[string]domain.BranchRevenue{},
+		Payments:  []domain.ReconciledPayment{},
+	}
+	add := func(invoice *domain.Invoice, p domain.InvoicePayment) {
+		if p.PaidAt.Before(from) || !p.PaidAt.Before(to) {
+			return
+		}
+		channel := p.Channel
+		if channel == "" {
+			channel = domain.PaymentChannelProvider
+		}
+		if domain.IsManualPaymentChannel(channel) {
+			report.Manual.Add(p.Amount)
+		} else {
+			report.Provider.Add(p.Amount)
+		}
+		total := report.ByChannel[channel]
+		total.Add(p.Amount)
+		report.ByChannel[channel] = total
+
+		branchID := invoice.BranchID
+		if branchID == "" {
+			branchID = domain.UnattributedBranch
+		}
+		revenue := report.ByBranch[branchID]
+		if invoice.BranchPriced {
+			revenue.BranchPrice.Add(p.Amount)
+		} else {
+			revenue.BasePrice.Add(p.Amount)
+		}
+		report.ByBranch[branchID] = revenue
+
+		report.Payments = append(report.Payments, domain.ReconciledPayment{
+			InvoiceID:  invoice.ID,
+			ContractID: invoice.ContractID,
+			BranchID:   invoice.BranchID,
+			UserID:     invoice.UserID,
+			Channel:    channel,
+			Amount:     p.Amount,
+			PaidAt:     p.PaidAt,
+			RecordedBy: p.RecordedBy,
+			Receipt:    p.Receipt,
+		})
+	}
+	for _, invoice := range invoices {
+		if len(invoice.Payments) == 0 && invoice.Status == domain.InvoiceStatusPaid {
+			add(invoice, domain.InvoicePayment{Amount: invoice.Amount.Minor, PaidAt: invoice.UpdatedAt})
+			continue
+		}
+		for _, p := range invoice.Payments {
+			add(invoice, p)
+		}
+	}
+	sort.SliceStable(report.Payments, func(i, j int) bool {
+		return report.Payments[i].PaidAt.Before(report.Payments[j].PaidAt)
+	})
+	return report, nil
+}
+
+// stamp fills in what the service records for every manual payment
+func (s *ManualPaymentService) stamp(payment *domain.InvoicePayment, actorID string) {
+	payment.Reference = "manual:" + ulid.Make().String()
+	payment.PaidAt = s.clock.Now()
+	payment.RecordedBy = actorID
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManualPaymentService_PayInvoice(t *testing.T) {
+	ctx := context.Background()
+	cash := domain.InvoicePayment{Channel: domain.PaymentChannelCash, Receipt: "R-001"}
+
+	t.Run("pays the next installment by default", func(t *testing.T) {
+		installments, invoices, _ := newInstallmentService(t)
+		svc := NewManualPaymentService(invoices, nil, installments, clock.NewFake(testNow))
+		plan := threePart(testNow.AddDate(0, 0, 3))
+		invoices.On("GetByID", ctx, "inv-1").Return(plan, nil)
+		invoices.On("RecordPayment", ctx, plan, mock.MatchedBy(func(p domain.InvoicePayment) bool {
+			return strings.HasPrefix(p.Reference, "manual:") && p.Amount == 1_000_000 &&
+				p.RecordedBy == "desk-1" && p.Channel == domain.PaymentChannelCash && p.PaidAt.Equal(testNow)
+		})).Return(true, nil).Once()
+
+		got, err := svc.PayInvoice(ctx, "gym", "desk-1", "inv-1", cash)
+		require.NoError(t, err)
+		assert.NotNil(t, got.Installments[0].PaidAt)
+		assert.Equal(t, domain.InvoiceStatusPartiallyPaid, got.Status)
+	})
+
+	t.Run("rejects what the front desk cannot collect", func(t *testing.T) {
+		installments, invoices, _ := newInstallmentService(t)
+		svc := NewManualPaymentService(invoices, nil, installments, clock.NewFake(testNow))
+		invoices.On("GetByID", ctx, "inv-1").Return(threePart(testNow), nil)
+		invoices.On("GetByID", ctx, "pro").Return(&domain.Invoice{ID: "pro", TenantID: "gym", Amount: domain.NewMoney(99_000, "IDR"), Status: domain.InvoiceStatusPending}, nil)
+		invoices.On("GetByID", ctx, "sold").Return(&domain.Invoice{ID: "sold", TenantID: "gym", ContractID: "k2", Amount: domain.NewMoney(99_000, "IDR"), PaidAmount: 99_000, Status: domain.InvoiceStatusPaid}, nil)
+
+		_, err := svc.PayInvoice(ctx, "gym", "desk-1", "inv-1", domain.InvoicePayment{Channel: "card"})
+		assert.ErrorIs(t, err, domain.ErrInvalidPaymentChannel)
+		_, err = svc.PayInvoice(ctx, "other", "desk-1", "inv-1", cash)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		over := cash
+		over.Amount = 3_000_001
+		_, err = svc.PayInvoice(ctx, "gym", "desk-1", "inv-1", over)
+		assert.ErrorIs(t, err, domain.ErrInvalidManualPayment)
+		_, err = svc.PayInvoice(ctx, "gym", "desk-1", "pro", cash)
+		assert.ErrorIs(t, err, domain.ErrNotContractInvoice)
+		_, err = svc.PayInvoice(ctx, "gym", "desk-1", "sold", cash)
+		assert.ErrorIs(t, err, domain.ErrInvoiceAlreadyPaid)
+	})
+}
+
+func TestManualPaymentService_SellContract(t *testing.T) {
+	ctx := context.Background()
+	pt, m := newTestPTService(t)
+	installments, invoices, _ := newInstallmentService(t)
+	svc := NewManualPaymentService(invoices, pt, installments, clock.NewFake(testNow))
+
+	m.pkgRepo.On("GetByID", anyCtx, "pkg-1").Return(&domain.PTPackage{ID: "pkg-1", TotalSessions: 10, Price: domain.NewMoney(2500000, "IDR"), Active: true}, nil)
+	m.contractRepo.On("Create", anyCtx, mock.AnythingOfType("*domain.PTContract")).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.PTContract).ID = "contract-1"
+	}).Return(nil)
+	m.expectLock("contract:contract-1")
+	m.expectAppend(domain.CreditTypePurchased, 10, 1)
+	m.contractRepo.On("SyncBalance", anyCtx, "contract-1", 10, int64(1)).Return(nil)
+	invoices.On("Create", ctx, mock.AnythingOfType("*domain.Invoice")).Return(nil).Once()
+
+	contract := &domain.PTContract{PackageID: "pkg-1", MemberID: "member-1"}
+	invoice, err := svc.SellContract(ctx, "gym", "desk-1", contract, domain.InvoicePayment{Channel: domain.PaymentChannelBankTransfer, Receipt: "TRF-9"})
+	require.NoError(t, err)
+	assert.Equal(t, "gym", contract.TenantID)
+	assert.Equal(t, "contract-1", invoice.ContractID)
+	assert.Equal(t, domain.InvoiceStatusPaid, invoice.Status)
+	assert.Equal(t, int64(2500000), invoice.PaidAmount)
+	require.Len(t, invoice.Payments, 1)
+	assert.Equal(t, "TRF-9", invoice.Payments[0].Receipt)
+}
+
+func TestManualPaymentService_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	_, invoices, _ := newInstallmentService(t)
+	svc := NewManualPaymentService(invoices, nil, nil, clock.NewFake(testNow))
+	from, to := testNow.AddDate(0, 0, -7), testNow.AddDate(0, 0, 1)
+
+	plan := threePart(testNow)
+	plan.Payments = []domain.InvoicePayment{
+		{Reference: "sid-1:1", Amount: 1_000_000, PaidAt: testNow.AddDate(0, 0, -30)}, // Before the period
+		{Reference: "sid-2:2", Amount: 400_000, PaidAt: testNow.AddDate(0, 0, -2), Channel: domain.PaymentChannelProvider},
+		{Reference: "manual:x", Amount: 600_000, PaidAt: testNow.AddDate(0, 0, -1), Channel: domain.PaymentChannelCash, RecordedBy: "desk-1"},
+	}
+	legacy := &domain.Invoice{ID: "pro", UserID: "m2", TenantID: "gym", Amount: domain.NewMoney(99_000, "IDR"), Status: domain.InvoiceStatusPaid, UpdatedAt: testNow.AddDate(0, 0, -3)}
+	sold := &domain.Invoice{ID: "sold", UserID: "m3", TenantID: "gym", ContractID: "k3", Amount: domain.NewMoney(2_500_000, "IDR"), Status: domain.InvoiceStatusPaid,
+		BranchID: "br-1", BranchPriced: true,
+		Payments: []domain.InvoicePayment{{Reference: "manual:y", Amount: 2_500_000, PaidAt: testNow, Channel: domain.PaymentChannelBankTransfer}}}
+	invoices.On("ListPaidBetween", ctx, "gym", from, to).Return([]*domain.Invoice{plan, legacy, sold}, nil)
+
+	report, err := svc.Reconcile(ctx, "gym", from, to)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentTotal{Count: 2, Amount: 499_000}, report.Provider)
+	assert.Equal(t, domain.PaymentTotal{Count: 2, Amount: 3_100_000}, report.Manual)
+	assert.Equal(t, domain.PaymentTotal{Count: 1, Amount: 600_000}, report.ByChannel[domain.PaymentChannelCash])
+	assert.Equal(t, domain.BranchRevenue{BranchPrice: domain.PaymentTotal{Count: 1, Amount: 2_500_000}}, report.ByBranch["br-1"])
+	assert.Equal(t, domain.PaymentTotal{Count: 3, Amount: 1_099_000}, report.ByBranch[domain.UnattributedBranch].BasePrice)
+	require.Len(t, report.Payments, 4)
+	assert.Equal(t, "pro", report.Payments[0].InvoiceID)
+	assert.Equal(t, "sold", report.Payments[3].InvoiceID)
+}
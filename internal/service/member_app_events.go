@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// MemberAppNotifier tells members about accounts, sessions and packages set up for them. It
+// handles the outbox messages PTService writes, so a push only goes out for a change that
+// was saved, and links it to the app screen showing the change.
+type MemberAppNotifier struct {
+	schedRepo    domain.ScheduleRepository
+	contractRepo domain.PTContractRepository
+	notifier     *NotificationService
+}
+
+func NewMemberAppNotifier(schedRepo domain.ScheduleRepository, contractRepo domain.PTContractRepository, notifier *NotificationService) *MemberAppNotifier {
+	return &MemberAppNotifier{schedRepo: schedRepo, contractRepo: contractRepo, notifier: notifier}
+}
+
+// HandleScheduleChanged is the outbox handler telling the member their coach booked or
+// changed a session. Sessions deleted or cancelled since are skipped, as are cancellations
+// and members' own moves, which SessionEventNotifier handles.
+func (n *MemberAppNotifier) HandleScheduleChanged(ctx context.Context, msg *domain.OutboxMessage) error {
+	change, _ := msg.Payload["change"].(string)
+	if change == domain.ScheduleChangeCancelled || change == domain.ScheduleChangeMemberRescheduled {
+		return nil
+	}
+	sched, err := n.schedRepo.GetByID(ctx, msg.Key)
+	if errors.Is(err, domain.ErrScheduleNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if sched.DeletedAt != nil || sched.Status == domain.ScheduleStatusCancelled {
+		return nil
+	}
+	count, _ := msg.Payload["count"].(string)
+	n.send(ctx, scheduleChangeNotification(sched, change, count))
+	return nil
+}
+
+// HandleContractCreated is the outbox handler telling the member a PT package is ready
+func (n *MemberAppNotifier) HandleContractCreated(ctx context.Context, msg *domain.OutboxMessage) error {
+	contract, err := n.contractRepo.GetByID(ctx, msg.Key)
+	if errors.Is(err, domain.ErrContractNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	n.send(ctx, &domain.Notification{
+		UserID:   contract.MemberID,
+		TenantID: contract.TenantID,
+		Type:     domain.NotificationContractCreated,
+		Title:    "Your PT package is ready",
+		Body:     fmt.Sprintf("%d sessions are ready to book with your coach", contract.TotalSessions),
+		Data:     map[string]string{"contract_id": contract.ID},
+		Link:     &domain.DeepLink{Screen: domain.ScreenContract, ContractID: contract.ID},
+	})
+	return nil
+}
+
+// HandleLowSessions is the outbox handler warning the member their package is running low.
+// Packages topped up or used up since are skipped.
+func (n *MemberAppNotifier) HandleLowSessions(ctx context.Context, msg *domain.OutboxMessage) error {
+	contract, err := n.contractRepo.GetByID(ctx, msg.Key)
+	if errors.Is(err, domain.ErrContractNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	left := contract.RemainingSessions
+	if left <= 0 || left >= domain.LowSessionsThreshold {
+		return nil
+	}
+	body := fmt.Sprintf("%d sessions left in your PT package. Ask your coach about renewing", left)
+	if left == 1 {
+		body = "Only 1 session left in your PT package. Ask your coach about renewing"
+	}
+	n.send(ctx, &domain.Notification{
+		UserID:   contract.MemberID,
+		TenantID: contract.TenantID,
+		Type:     domain.NotificationLowSessions,
+		Title:    "Your package is running low",
+		Body:     body,
+		Data:     map[string]string{"contract_id": contract.ID, "remaining": strconv.Itoa(left)},
+		Link:     &domain.DeepLink{Screen: domain.ScreenContract, ContractID: contract.ID},
+	})
+	return nil
+}
+
+// Invite tells a user whose account was set up for them how to sign in
+func (n *MemberAppNotifier) Invite(ctx context.Context, user *domain.User) {
+	n.send(ctx, &domain.Notification{
+		UserID:   user.ID,
+		TenantID: user.TenantID,
+		Type:     domain.NotificationInvitation,
+		Title:    "Your account is ready",
+		Body:     "Download the app and sign in with " + user.Email + " to get started",
+		Data:     map[string]string{"email": user.Email},
+	})
+}
+
+// send notifies without failing the message: the inbox entry may already be saved, and a
+// retry would file it twice
+func (n *MemberAppNotifier) send(ctx context.Context, notification *domain.Notification) {
+	if err := n.notifier.Notify(ctx, notification); err != nil {
+		log.Printf("Warning: %s notification to %s not sent: %v", notification.Type, notification.UserID, err)
+	}
+}
+
+// scheduleChangeNotification tells the member about a change to their session, see
+// domain.ScheduleChangeBooked and the changes after it. count is the number of sessions
+// booked in a batch, of which sched is the first.
+func scheduleChangeNotification(sched *domain.Schedule, change, count string) *domain.Notification {
+	when := sched.StartTime.UTC().Format("Mon 2 Jan 15:04 MST")
+	kind, title, body := domain.NotificationScheduleChanged, "Session updated", "Your session on "+when+" changed"
+	switch change {
+	case domain.ScheduleChangeBooked:
+		kind, title, body = domain.NotificationScheduleBooked, "New session booked", "Your coach booked a session for "+when
+	case domain.ScheduleChangeBatchBooked:
+		kind, title, body = domain.NotificationScheduleBooked, "New sessions booked", "Your coach booked "+count+" sessions, starting "+when
+	case domain.ScheduleChangeRescheduled:
+		title, body = "Session moved", "Your coach moved your session to "+when
+	case domain.ScheduleChangeModality:
+		body = "Your session on " + when + " is now at the gym"
+	}
+
+	data := map[string]string{
+		"schedule_id": sched.ID,
+		"start_time":  sched.StartTime.UTC().Format(time.RFC3339),
+	}
+	if sched.IsOnline() {
+		if change == domain.ScheduleChangeModality {
+			body = "Your session on " + when + " is now online"
+		}
+		if sched.MeetingURL != "" {
+			data["meeting_url"] = sched.MeetingURL
+		}
+	}
+	return &domain.Notification{
+		UserID:   sched.MemberID,
+		TenantID: sched.TenantID,
+		Type:     kind,
+		Title:    title,
+		Body:     body,
+		Data:     data,
+		Link:     &domain.DeepLink{Screen: domain.ScreenSchedule, ScheduleID: sched.ID},
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPTService_CreateSchedule_RecordsBooking(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestPTService(t)
+	outboxRepo, tx := mocks.NewOutboxRepository(t), mocks.NewTransactor(t)
+	svc.outbox = NewOutbox(outboxRepo, tx, nil)
+
+	m.contractRepo.On("GetByID", anyCtx, "contract-1").Return(&domain.PTContract{ID: "contract-1", MemberID: "member-1", BranchID: "br-1",
+		RemainingSessions: 2, Status: domain.PackageStatusActive}, nil)
+	m.schedRepo.On("CountByContractAndStatus", anyCtx, "contract-1", mock.Anything).Return(int64(0), nil)
+	tx.On("WithinTransaction", anyCtx, mock.Anything).Return(runInline)
+	m.schedRepo.On("Create", anyCtx, mock.AnythingOfType("*domain.Schedule")).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Schedule).ID = "sched-1"
+	}).Return(nil)
+	outboxRepo.On("Add", anyCtx, mock.MatchedBy(func(msg *domain.OutboxMessage) bool {
+		return msg.Topic == domain.OutboxTopicScheduleChanged && msg.Key == "sched-1" && msg.TenantID == "gym" &&
+			msg.Payload["change"] == domain.ScheduleChangeBooked
+	})).Return(nil).Once()
+
+	err := svc.CreateSchedule(ctx, &domain.Schedule{TenantID: "gym", ContractID: "contract-1", MemberID: "member-1", BranchID: "br-1"})
+	require.NoError(t, err)
+}
+
+func TestPTService_RescheduleSession_RecordsChanges(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestPTService(t)
+	outboxRepo, tx := mocks.NewOutboxRepository(t), mocks.NewTransactor(t)
+	svc.outbox = NewOutbox(outboxRepo, tx, nil)
+	start := testNow.AddDate(0, 0, 2)
+
+	m.schedRepo.On("GetByID", ctx, "sched-1").Return(&domain.Schedule{ID: "sched-1", CoachID: "coach-1", MemberID: "member-1"}, nil)
+	m.schedRepo.On("Update", ctx, mock.AnythingOfType("*domain.Schedule")).Return(nil)
+	tx.On("WithinTransaction", ctx, mock.Anything).Return(runInline).Twice()
+	outboxRepo.On("Add", ctx, mock.MatchedBy(func(msg *domain.OutboxMessage) bool {
+		return msg.Key == "sched-1" && msg.Payload["change"] == domain.ScheduleChangeRescheduled
+	})).Return(nil).Once()
+	// The coach hears of members moving their session
+	outboxRepo.On("Add", ctx, mock.MatchedBy(func(msg *domain.OutboxMessage) bool {
+		return msg.Key == "sched-1" && msg.Payload["change"] == domain.ScheduleChangeMemberRescheduled
+	})).Return(nil).Once()
+
+	require.NoError(t, svc.RescheduleSession(ctx, "sched-1", start, start.Add(time.Hour), "coach", "coach-1"))
+	require.NoError(t, svc.RescheduleSession(ctx, "sched-1", start, start.Add(time.Hour), "member", "member-1"))
+}
+
+func TestPTService_UpdateScheduleStatus_RecordsCancellation(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestPTService(t)
+	outboxRepo, tx := mocks.NewOutboxRepository(t), mocks.NewTransactor(t)
+	svc.outbox = NewOutbox(outboxRepo, tx, nil)
+
+	m.schedRepo.On("GetByID", ctx, "sched-1").Return(&domain.Schedule{ID: "sched-1", TenantID: "gym", Status: domain.ScheduleStatusScheduled}, nil).Once()
+	m.schedRepo.On("UpdateStatus", ctx, "sched-1", mock.Anything).Return(nil)
+	tx.On("WithinTransaction", ctx, mock.Anything).Return(runInline).Once()
+	outboxRepo.On("Add", ctx, mock.MatchedBy(func(msg *domain.OutboxMessage) bool {
+		return msg.Key == "sched-1" && msg.TenantID == "gym" && msg.Payload["change"] == domain.ScheduleChangeCancelled
+	})).Return(nil).Once()
+
+	require.NoError(t, svc.UpdateScheduleStatus(ctx, "sched-1", "cancelled"))
+	// Other statuses tell nobody
+	require.NoError(t, svc.UpdateScheduleStatus(ctx, "sched-1", domain.ScheduleStatusNoShow))
+}
+
+func TestMemberAppNotifier_HandleScheduleChanged(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+
+	newNotifier := func(t *testing.T) (*MemberAppNotifier, *mocks.ScheduleRepository, *mocks.NotificationSender) {
+		schedRepo := mocks.NewScheduleRepository(t)
+		prefs := mocks.NewNotificationPreferencesRepository(t)
+		prefs.On("GetUser", ctx, "member-1").Return(&domain.NotificationPreferences{UserID: "member-1"}, nil).Maybe()
+		push := mocks.NewNotificationSender(t)
+		push.On("Channel").Return(domain.ChannelPush)
+		notifications := NewNotificationService(prefs, nil, clock.NewFake(testNow), push)
+		return NewMemberAppNotifier(schedRepo, mocks.NewPTContractRepository(t), notifications), schedRepo, push
+	}
+
+	t.Run("links the push to the session", func(t *testing.T) {
+		n, schedRepo, push := newNotifier(t)
+		schedRepo.On("GetByID", ctx, "sched-1").Return(&domain.Schedule{ID: "sched-1", TenantID: "gym", MemberID: "member-1",
+			StartTime: start, Modality: domain.SessionModalityOnline, MeetingURL: "https://meet.example/abc"}, nil)
+		var sent *domain.Notification
+		push.On("Send", ctx, mock.AnythingOfType("*domain.Notification")).Run(func(args mock.Arguments) {
+			sent = args.Get(1).(*domain.Notification)
+		}).Return(nil)
+
+		err := n.HandleScheduleChanged(ctx, &domain.OutboxMessage{Key: "sched-1", Payload: map[string]interface{}{"change": domain.ScheduleChangeModality}})
+
+		require.NoError(t, err)
+		require.NotNil(t, sent)
+		assert.Equal(t, domain.NotificationScheduleChanged, sent.Type)
+		assert.Equal(t, "Your session on Tue 20 Oct 09:00 UTC is now online", sent.Body)
+		assert.Equal(t, &domain.DeepLink{Screen: domain.ScreenSchedule, ScheduleID: "sched-1"}, sent.Link)
+		assert.Equal(t, "https://meet.example/abc", sent.Payload()["meeting_url"])
+	})
+
+	t.Run("leaves cancellations and members' moves to SessionEventNotifier", func(t *testing.T) {
+		n, _, _ := newNotifier(t)
+
+		assert.NoError(t, n.HandleScheduleChanged(ctx, &domain.OutboxMessage{Key: "sched-1", Payload: map[string]interface{}{"change": domain.ScheduleChangeCancelled}}))
+		assert.NoError(t, n.HandleScheduleChanged(ctx, &domain.OutboxMessage{Key: "sched-1", Payload: map[string]interface{}{"change": domain.ScheduleChangeMemberRescheduled}}))
+	})
+
+	t.Run("skips sessions cancelled since", func(t *testing.T) {
+		n, schedRepo, _ := newNotifier(t)
+		schedRepo.On("GetByID", ctx, "sched-1").Return(&domain.Schedule{ID: "sched-1", MemberID: "member-1", Status: domain.ScheduleStatusCancelled}, nil)
+
+		assert.NoError(t, n.HandleScheduleChanged(ctx, &domain.OutboxMessage{Key: "sched-1", Payload: map[string]interface{}{"change": domain.ScheduleChangeBooked}}))
+	})
+}
+
+func TestMemberAppNotifier_HandleContractCreated(t *testing.T) {
+	ctx := context.Background()
+	contracts := mocks.NewPTContractRepository(t)
+	prefs := mocks.NewNotificationPreferencesRepository(t)
+	push := mocks.NewNotificationSender(t)
+	push.On("Channel").Return(domain.ChannelPush)
+	n := NewMemberAppNotifier(mocks.NewScheduleRepository(t), contracts, NewNotificationService(prefs, nil, clock.NewFake(testNow), push))
+
+	contracts.On("GetByID", ctx, "contract-1").Return(&domain.PTContract{ID: "contract-1", TenantID: "gym", MemberID: "member-1", TotalSessions: 10}, nil)
+	prefs.On("GetUser", ctx, "member-1").Return(&domain.NotificationPreferences{UserID: "member-1"}, nil)
+	push.On("Send", ctx, mock.MatchedBy(func(sent *domain.Notification) bool {
+		return sent.Type == domain.NotificationContractCreated && sent.Link.Screen == domain.ScreenContract &&
+			sent.Payload()["link"] == "metamorph://contract?contract_id=contract-1"
+	})).Return(nil).Once()
+
+	require.NoError(t, n.HandleContractCreated(ctx, &domain.OutboxMessage{Key: "contract-1"}))
+}
+
+func TestMemberAppNotifier_HandleLowSessions(t *testing.T) {
+	ctx := context.Background()
+	contracts := mocks.NewPTContractRepository(t)
+	prefs := mocks.NewNotificationPreferencesRepository(t)
+	push := mocks.NewNotificationSender(t)
+	push.On("Channel").Return(domain.ChannelPush).Maybe()
+	n := NewMemberAppNotifier(mocks.NewScheduleRepository(t), contracts, NewNotificationService(prefs, nil, clock.NewFake(testNow), push))
+
+	contracts.On("GetByID", ctx, "contract-1").Return(&domain.PTContract{ID: "contract-1", TenantID: "gym", MemberID: "member-1", RemainingSessions: 1}, nil).Once()
+	prefs.On("GetUser", ctx, "member-1").Return(&domain.NotificationPreferences{UserID: "member-1"}, nil)
+	push.On("Send", ctx, mock.MatchedBy(func(sent *domain.Notification) bool {
+		return sent.Type == domain.NotificationLowSessions && sent.Data["remaining"] == "1" &&
+			strings.HasPrefix(sent.Body, "Only 1 session left")
+	})).Return(nil).Once()
+	require.NoError(t, n.HandleLowSessions(ctx, &domain.OutboxMessage{Key: "contract-1"}))
+
+	// A renewal since the message was written tells nobody
+	contracts.On("GetByID", ctx, "contract-1").Return(&domain.PTContract{ID: "contract-1", MemberID: "member-1", RemainingSessions: 11}, nil).Once()
+	require.NoError(t, n.HandleLowSessions(ctx, &domain.OutboxMessage{Key: "contract-1"}))
+
+	contracts.On("GetByID", ctx, "contract-2").Return(nil, domain.ErrContractNotFound)
+	require.NoError(t, n.HandleLowSessions(ctx, &domain.OutboxMessage{Key: "contract-2"}))
+}
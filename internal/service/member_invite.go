@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+const inviteTokenPrefix = "inv_"
+
+// MemberInviteService lets coaches invite members instead of creating their accounts. The
+// invite goes out by email and as a link the coach can text; the member accepts it by
+// signing in with the invited email, which links their account, makes them a member of the
+// gym and sells them the package the coach picked.
+type MemberInviteService struct {
+	invites    domain.MemberInviteRepository
+	userRepo   domain.UserRepository
+	tenantRepo domain.TenantRepository
+	ptService  *PTService
+	authClient FirebaseAuthClient
+	mailer     domain.EmailSender // Optional: see EmailInvites
+	clock      domain.Clock
+}
+
+func NewMemberInviteService(
+	invites domain.MemberInviteRepository,
+	userRepo domain.UserRepository,
+	tenantRepo domain.TenantRepository,
+	ptService *PTService,
+	authClient FirebaseAuthClient,
+	clk domain.Clock,
+) *MemberInviteService {
+	return &MemberInviteService{
+		invites:    invites,
+		userRepo:   userRepo,
+		tenantRepo: tenantRepo,
+		ptService:  ptService,
+		authClient: authClient,
+		clock:      clock.OrReal(clk),
+	}
+}
+
+// EmailInvites emails invites through mailer. Without it coaches share the link themselves.
+func (s *MemberInviteService) EmailInvites(mailer domain.EmailSender) {
+	s.mailer = mailer
+}
+
+// SentInvite is a new invite with its secret, which is only available now
+type SentInvite struct {
+	Invite  *domain.MemberInvite `json:"invite"`
+	Token   string               `json:"token"`
+	Link    string               `json:"link"`
+	SMS     string               `json:"sms"` // A text message with the link, for the coach to send
+	Emailed bool                 `json:"emailed"`
+}
+
+// Invite creates an invite from the coach to join the tenant, valid for
+// domain.MemberInviteTTL, and emails it. A package to sell on acceptance must be one of
+// the tenant's.
+func (s *MemberInviteService) Invite(ctx context.Context, tenantID, coachID string, invite *domain.MemberInvite) (*SentInvite, error) {
+	if err := invite.Normalize(); err != nil {
+		return nil, err
+	}
+	existing, err := s.userRepo.GetByEmail(ctx, invite.Email)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		if _, err := existing.ScopedTo(tenantID); err == nil {
+			return nil, domain.ErrInviteMemberExists
+		}
+	}
+	if invite.PackageID != "" {
+		pkg, err := s.ptService.GetPackageTemplate(ctx, invite.PackageID)
+		if err != nil {
+			return nil, err
+		}
+		if pkg.TenantID != tenantID {
+			return nil, domain.ErrPackageTemplateNotFound
+		}
+	}
+	tenant, err := s.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate invite token: %w", err)
+	}
+	secret := inviteTokenPrefix + hex.EncodeToString(raw)
+	now := s.clock.Now().UTC()
+	invite.ID = ""
+	invite.TenantID, invite.CoachID = tenantID, coachID
+	invite.TokenHash = hashToken(secret)
+	invite.CreatedAt, invite.ExpiresAt = now, now.Add(domain.MemberInviteTTL)
+	invite.AcceptedAt, invite.MemberID, invite.RevokedAt = nil, "", nil
+	if err := s.invites.Create(ctx, invite); err != nil {
+		return nil, err
+	}
+
+	link := domain.InviteLink(secret).URL()
+	sent := &SentInvite{
+		Invite: invite,
+		Token:  secret,
+		Link:   link,
+		SMS:    fmt.Sprintf("%s: you're invited to train with us. Join in the app: %s", tenant.Name, link),
+	}
+	sent.Emailed = s.email(ctx, tenant, invite, secret)
+	return sent, nil
+}
+
+// email sends the invite and reports whether it went out. Failures are only logged: the
+// coach still has the link to share.
+func (s *MemberInviteService) email(ctx context.Context, tenant *domain.Tenant, invite *domain.MemberInvite, secret string) bool {
+	if s.mailer == nil {
+		return false
+	}
+	msg, err := domain.RenderEmail(tenant, &domain.User{Email: invite.Email, Name: invite.Name}, &domain.Notification{
+		TenantID: tenant.ID,
+		Type:     domain.NotificationInvitation,
+		Title:    "You're invited to " + tenant.Name,
+		Body: fmt.Sprintf("Your coach set up your training at %s. Open this email on your phone and sign in with %s to join; the invite works until %s.",
+			tenant.Name, invite.Email, invite.ExpiresAt.Format("2 Jan 2006")),
+		Link: domain.InviteLink(secret),
+	})
+	if err == nil {
+		err = s.mailer.Send(ctx, msg)
+	}
+	if err != nil {
+		log.Printf("Warning: invite %s not emailed: %v", invite.ID, err)
+		return false
+	}
+	return true
+}
+
+// AcceptedInvite is the member who accepted an invite, with the contract for the invite's
+// package. Warning says why there is no contract when the package couldn't be sold.
+type AcceptedInvite struct {
+	User     *domain.User
+	Invite   *domain.MemberInvite
+	Contract *domain.PTContract
+	Warning  string
+}
+
+// Accept uses the invite behind secret for the Firebase account signed in with
+// firebaseToken, which must have the invited email, verified by Firebase. The account is created or linked,
+// joins the tenant as a member and gets the invite's package. Each invite works once.
+func (s *MemberInviteService) Accept(ctx context.Context, firebaseToken, secret string) (*AcceptedInvite, error) {
+	token, err := s.authClient.VerifyIDToken(ctx, firebaseToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	email, _ := token.Claims["email"].(string)
+	// Anyone can sign up with an email they don't own; only a verified one proves it
+	verified, _ := token.Claims["email_verified"].(bool)
+
+	invite, err := s.invites.FindByHash(ctx, hashToken(strings.TrimSpace(secret)))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrInviteNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := s.clock.Now().UTC()
+	if !invite.Pending(now) {
+		return nil, domain.ErrInviteNotFound
+	}
+	if !verified {
+		return nil, domain.ErrInviteEmailUnverified
+	}
+	if !strings.EqualFold(strings.TrimSpace(email), invite.Email) {
+		return nil, domain.ErrInviteEmailMismatch
+	}
+
+	user, err := s.account(ctx, token.UID, invite)
+	if err != nil {
+		return nil, err
+	}
+	// Claim the invite before joining, so a second use can't slip in
+	if err := s.invites.Accept(ctx, invite.ID, user.ID, now); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrInviteNotFound
+		}
+		return nil, err
+	}
+	invite.AcceptedAt, invite.MemberID = &now, user.ID
+
+	if _, err := user.ScopedTo(invite.TenantID); err != nil {
+		if user.TenantID == "" {
+			user.TenantID = invite.TenantID
+			if !user.HasRole(domain.RoleMember) {
+				user.Roles = append(user.Roles, domain.RoleMember)
+			}
+		} else {
+			user.SetMembership(domain.TenantMembership{TenantID: invite.TenantID, Roles: []string{domain.RoleMember}})
+		}
+		if user.DateOfBirth == nil {
+			user.DateOfBirth = invite.DateOfBirth
+		}
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, fmt.Errorf("failed to join tenant: %w", err)
+		}
+	}
+	log.Printf("[Audit] %s accepted invite %s from coach %s to tenant %s", user.ID, invite.ID, invite.CoachID, invite.TenantID)
+
+	accepted := &AcceptedInvite{User: user, Invite: invite}
+	if invite.PackageID != "" {
+		accepted.Contract, accepted.Warning = s.sellPackage(ctx, invite, user.ID)
+	}
+	return accepted, nil
+}
+
+// account finds the user signing in, linking their Firebase account to an account made for
+// them by email, or creates one under the invited name
+func (s *MemberInviteService) account(ctx context.Context, firebaseUID string, invite *domain.MemberInvite) (*domain.User, error) {
+	user, err := s.userRepo.GetByFirebaseUID(ctx, firebaseUID)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	user, err = s.userRepo.GetByEmail(ctx, invite.Email)
+	switch {
+	case err == nil && user.FirebaseUID != "":
+		return nil, domain.ErrInviteAccountLinked
+	case err == nil:
+		if err := s.userRepo.UpdateFirebaseUID(ctx, user.ID, firebaseUID); err != nil {
+			return nil, fmt.Errorf("failed to link firebase account: %w", err)
+		}
+		user.FirebaseUID = firebaseUID
+		return user, nil
+	case !errors.Is(err, domain.ErrNotFound):
+		return nil, err
+	}
+
+	user = &domain.User{
+		FirebaseUID: firebaseUID,
+		Email:       invite.Email,
+		Name:        invite.Name,
+		Roles:       []string{domain.RoleMember},
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	return user, nil
+}
+
+// sellPackage opens a contract for the invite's package with the inviting coach. A package
+// removed since the invite leaves the member without one, with a warning.
+func (s *MemberInviteService) sellPackage(ctx context.Context, invite *domain.MemberInvite, memberID string) (*domain.PTContract, string) {
+	pkg, err := s.ptService.GetPackageTemplate(ctx, invite.PackageID)
+	if err != nil || pkg.TenantID != invite.TenantID {
+		return nil, "Package not found, joined without a contract"
+	}
+	contract := &domain.PTContract{
+		PackageID: pkg.ID,
+		MemberID:  memberID,
+		CoachID:   invite.CoachID,
+		BranchID:  pkg.BranchID,
+		TenantID:  invite.TenantID,
+	}
+	if err := s.ptService.CreateContract(ctx, contract); err != nil {
+		log.Printf("Warning: contract for invite %s not created: %v", invite.ID, err)
+		return nil, "Failed to create contract: " + err.Error()
+	}
+	return contract, ""
+}
+
+// ListPending returns the coach's invites that can still be accepted
+func (s *MemberInviteService) ListPending(ctx context.Context, tenantID, coachID string) ([]*domain.MemberInvite, error) {
+	return s.invites.ListPending(ctx, tenantID, coachID, s.clock.Now().UTC())
+}
+
+// Revoke withdraws one of the coach's pending invites
+func (s *MemberInviteService) Revoke(ctx context.Context, coachID, id string) error {
+	err := s.invites.Revoke(ctx, coachID, id, s.clock.Now().UTC())
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.ErrInviteNotFound
+	}
+	return err
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"firebase.google.com/go/v4/auth"
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// firebaseStub verifies every ID token as the same account
+type firebaseStub struct {
+	uid, email string
+	unverified bool
+}
+
+func (f firebaseStub) VerifyIDToken(ctx context.Context, idToken string) (*auth.Token, error) {
+	return &auth.Token{UID: f.uid, Claims: map[string]interface{}{"email": f.email, "email_verified": !f.unverified}}, nil
+}
+
+type memberInviteMocks struct {
+	invites *mocks.MemberInviteRepository
+	users   *mocks.UserRepository
+	tenants *mocks.TenantRepository
+	pt      *ptServiceMocks
+}
+
+func newTestMemberInviteService(t *testing.T, signedIn firebaseStub) (*MemberInviteService, *memberInviteMocks) {
+	pt, ptMocks := newTestPTService(t)
+	m := &memberInviteMocks{
+		invites: mocks.NewMemberInviteRepository(t),
+		users:   mocks.NewUserRepository(t),
+		tenants: mocks.NewTenantRepository(t),
+		pt:      ptMocks,
+	}
+	return NewMemberInviteService(m.invites, m.users, m.tenants, pt, signedIn, clock.NewFake(testNow)), m
+}
+
+func TestMemberInviteService_Invite(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("emails the link", func(t *testing.T) {
+		svc, m := newTestMemberInviteService(t, firebaseStub{})
+		mailer := mocks.NewEmailSender(t)
+		svc.EmailInvites(mailer)
+		m.users.On("GetByEmail", ctx, "budi@example.com").Return(nil, domain.ErrNotFound)
+		m.pt.pkgRepo.On("GetByID", ctx, "pkg-1").Return(&domain.PTPackage{ID: "pkg-1", TenantID: "gym"}, nil)
+		m.tenants.On("GetByID", ctx, "gym").Return(&domain.Tenant{ID: "gym", Name: "Iron Gym"}, nil)
+		m.invites.On("Create", ctx, mock.MatchedBy(func(invite *domain.MemberInvite) bool {
+			return invite.TenantID == "gym" && invite.CoachID == "coach-1" && invite.ExpiresAt.Equal(testNow.Add(domain.MemberInviteTTL))
+		})).Return(nil).Once()
+		mailer.On("Send", ctx, mock.MatchedBy(func(msg *domain.EmailMessage) bool {
+			return msg.To == "budi@example.com" && msg.FromName == "Iron Gym" && strings.Contains(msg.Text, "metamorph://accept_invite?token=inv_")
+		})).Return(nil).Once()
+
+		sent, err := svc.Invite(ctx, "gym", "coach-1", &domain.MemberInvite{Email: "Budi@example.com", Name: "Budi", PackageID: "pkg-1"})
+
+		require.NoError(t, err)
+		assert.True(t, sent.Emailed)
+		assert.Equal(t, hashToken(sent.Token), sent.Invite.TokenHash)
+		assert.Contains(t, sent.SMS, sent.Link)
+	})
+
+	t.Run("rejects members of the gym", func(t *testing.T) {
+		svc, m := newTestMemberInviteService(t, firebaseStub{})
+		m.users.On("GetByEmail", ctx, "budi@example.com").Return(&domain.User{ID: "member-1", TenantID: "gym"}, nil)
+
+		_, err := svc.Invite(ctx, "gym", "coach-1", &domain.MemberInvite{Email: "budi@example.com", Name: "Budi"})
+		assert.ErrorIs(t, err, domain.ErrInviteMemberExists)
+	})
+
+	t.Run("rejects another gym's package", func(t *testing.T) {
+		svc, m := newTestMemberInviteService(t, firebaseStub{})
+		m.users.On("GetByEmail", ctx, "budi@example.com").Return(nil, domain.ErrNotFound)
+		m.pt.pkgRepo.On("GetByID", ctx, "pkg-1").Return(&domain.PTPackage{ID: "pkg-1", TenantID: "other-gym"}, nil)
+
+		_, err := svc.Invite(ctx, "gym", "coach-1", &domain.MemberInvite{Email: "budi@example.com", Name: "Budi", PackageID: "pkg-1"})
+		assert.ErrorIs(t, err, domain.ErrPackageTemplateNotFound)
+	})
+}
+
+func TestMemberInviteService_Accept(t *testing.T) {
+	ctx := context.Background()
+	pending := func() *domain.MemberInvite {
+		return &domain.MemberInvite{ID: "inv-1", TenantID: "gym", CoachID: "coach-1", Email: "budi@example.com", Name: "Budi",
+			ExpiresAt: testNow.Add(domain.MemberInviteTTL)}
+	}
+
+	t.Run("creates the member and sells the package", func(t *testing.T) {
+		svc, m := newTestMemberInviteService(t, firebaseStub{uid: "fb-1", email: "Budi@example.com"})
+		invite := pending()
+		invite.PackageID = "pkg-1"
+		m.invites.On("FindByHash", ctx, hashToken("inv_secret")).Return(invite, nil)
+		m.users.On("GetByFirebaseUID", ctx, "fb-1").Return(nil, domain.ErrNotFound)
+		m.users.On("GetByEmail", ctx, "budi@example.com").Return(nil, domain.ErrNotFound)
+		m.users.On("Create", ctx, mock.MatchedBy(func(u *domain.User) bool {
+			return u.FirebaseUID == "fb-1" && u.Name == "Budi"
+		})).Run(func(args mock.Arguments) { args.Get(1).(*domain.User).ID = "member-1" }).Return(nil)
+		m.invites.On("Accept", ctx, "inv-1", "member-1", testNow).Return(nil).Once()
+		m.users.On("Update", ctx, mock.MatchedBy(func(u *domain.User) bool { return u.TenantID == "gym" })).Return(nil).Once()
+		m.pt.pkgRepo.On("GetByID", anyCtx, "pkg-1").Return(&domain.PTPackage{ID: "pkg-1", TenantID: "gym", BranchID: "br-1", TotalSessions: 10,
+			Price: domain.NewMoney(2500000, "IDR"), Active: true}, nil)
+		m.pt.contractRepo.On("Create", anyCtx, mock.MatchedBy(func(c *domain.PTContract) bool {
+			return c.MemberID == "member-1" && c.CoachID == "coach-1"
+		})).Run(func(args mock.Arguments) { args.Get(1).(*domain.PTContract).ID = "contract-1" }).Return(nil)
+		m.pt.expectLock("contract:contract-1")
+		m.pt.expectAppend(domain.CreditTypePurchased, 10, 1)
+		m.pt.contractRepo.On("SyncBalance", anyCtx, "contract-1", 10, int64(1)).Return(nil)
+
+		accepted, err := svc.Accept(ctx, "firebase-token", " inv_secret ")
+
+		require.NoError(t, err)
+		assert.Equal(t, "gym", accepted.User.TenantID)
+		require.NotNil(t, accepted.Contract)
+		assert.Equal(t, "contract-1", accepted.Contract.ID)
+		assert.Empty(t, accepted.Warning)
+	})
+
+	t.Run("adds a membership to a member of another gym", func(t *testing.T) {
+		svc, m := newTestMemberInviteService(t, firebaseStub{uid: "fb-1", email: "budi@example.com"})
+		m.invites.On("FindByHash", ctx, hashToken("inv_secret")).Return(pending(), nil)
+		m.users.On("GetByFirebaseUID", ctx, "fb-1").Return(&domain.User{ID: "member-1", TenantID: "other-gym", Roles: []string{domain.RoleMember}}, nil)
+		m.invites.On("Accept", ctx, "inv-1", "member-1", testNow).Return(nil).Once()
+		m.users.On("Update", ctx, mock.MatchedBy(func(u *domain.User) bool {
+			return u.TenantID == "other-gym" && len(u.Memberships) == 1 && u.Memberships[0].TenantID == "gym"
+		})).Return(nil).Once()
+
+		_, err := svc.Accept(ctx, "firebase-token", "inv_secret")
+		require.NoError(t, err)
+	})
+
+	t.Run("only for the invited email", func(t *testing.T) {
+		svc, m := newTestMemberInviteService(t, firebaseStub{uid: "fb-2", email: "someone@example.com"})
+		m.invites.On("FindByHash", ctx, hashToken("inv_secret")).Return(pending(), nil)
+
+		_, err := svc.Accept(ctx, "firebase-token", "inv_secret")
+		assert.ErrorIs(t, err, domain.ErrInviteEmailMismatch)
+	})
+
+	t.Run("only once Firebase verified the email", func(t *testing.T) {
+		svc, m := newTestMemberInviteService(t, firebaseStub{uid: "fb-2", email: "budi@example.com", unverified: true})
+		m.invites.On("FindByHash", ctx, hashToken("inv_secret")).Return(pending(), nil)
+
+		_, err := svc.Accept(ctx, "firebase-token", "inv_secret")
+		assert.ErrorIs(t, err, domain.ErrInviteEmailUnverified)
+	})
+
+	t.Run("works once", func(t *testing.T) {
+		svc, m := newTestMemberInviteService(t, firebaseStub{uid: "fb-1", email: "budi@example.com"})
+		m.invites.On("FindByHash", ctx, hashToken("inv_secret")).Return(pending(), nil)
+		m.users.On("GetByFirebaseUID", ctx, "fb-1").Return(&domain.User{ID: "member-1"}, nil)
+		// Accepted by a concurrent request since it was read
+		m.invites.On("Accept", ctx, "inv-1", "member-1", testNow).Return(domain.ErrNotFound).Once()
+
+		_, err := svc.Accept(ctx, "firebase-token", "inv_secret")
+		assert.ErrorIs(t, err, domain.ErrInviteNotFound)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		svc, m := newTestMemberInviteService(t, firebaseStub{uid: "fb-1", email: "budi@example.com"})
+		expired := pending()
+		expired.ExpiresAt = testNow
+		m.invites.On("FindByHash", ctx, hashToken("inv_secret")).Return(expired, nil)
+
+		_, err := svc.Accept(ctx, "firebase-token", "inv_secret")
+		assert.ErrorIs(t, err, domain.ErrInviteNotFound)
+	})
+}
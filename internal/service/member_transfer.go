@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// upcomingHorizon bounds the lookup of sessions to cancel when a member moves
+const upcomingHorizon = 5 * 365 * 24 * time.Hour
+
+// MemberTransferService moves members between branches of a tenant and between tenants.
+// Upcoming sessions at the old location are cancelled, since the coach and times no
+// longer apply; the unused credits stay on the contract.
+type MemberTransferService struct {
+	userRepo   domain.UserRepository
+	branchRepo domain.BranchRepository
+	schedRepo  domain.ScheduleRepository
+	transfers  domain.MemberTransferRepository
+	tx         domain.Transactor
+	clock      domain.Clock
+}
+
+func NewMemberTransferService(
+	userRepo domain.UserRepository,
+	branchRepo domain.BranchRepository,
+	schedRepo domain.ScheduleRepository,
+	transfers domain.MemberTransferRepository,
+	tx domain.Transactor,
+	clk domain.Clock,
+) *MemberTransferService {
+	return &MemberTransferService{
+		userRepo:   userRepo,
+		branchRepo: branchRepo,
+		schedRepo:  schedRepo,
+		transfers:  transfers,
+		tx:         tx,
+		clock:      clock.OrReal(clk),
+	}
+}
+
+// TransferBranch moves a member of tenantID from fromBranchID (every other branch when empty,
+// or their only branch) to toBranchID, taking their active contracts along
+func (s *MemberTransferService) TransferBranch(ctx context.Context, actorID, tenantID, memberID, fromBranchID, toBranchID, reason string) (*domain.MemberTransfer, error) {
+	member, err := s.userRepo.GetByID(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member.TenantID != tenantID || !member.HasRole(domain.RoleMember) {
+		return nil, domain.ErrNotFound
+	}
+	if err := s.checkBranch(ctx, toBranchID, tenantID); err != nil {
+		return nil, err
+	}
+	if fromBranchID == "" && len(member.BranchAccess) == 1 {
+		fromBranchID = member.BranchAccess[0]
+	}
+	if fromBranchID == toBranchID {
+		return nil, domain.ErrInvalidTransferTarget
+	}
+
+	transfer := &domain.MemberTransfer{
+		MemberID:     memberID,
+		ActorID:      actorID,
+		Reason:       reason,
+		FromTenantID: tenantID,
+		ToTenantID:   tenantID,
+		FromBranchID: fromBranchID,
+		ToBranchID:   toBranchID,
+	}
+
+	transfer.SessionsCancelled, err = s.cancelUpcoming(ctx, member.ID, func(sched *domain.Schedule) bool {
+		if sched.TenantID != tenantID {
+			return false
+		}
+		if fromBranchID == "" {
+			return sched.BranchID != toBranchID
+		}
+		return sched.BranchID == fromBranchID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if fromBranchID == "" {
+		member.BranchAccess = []string{toBranchID}
+	} else {
+		member.BranchAccess = slices.DeleteFunc(member.BranchAccess, func(id string) bool { return id == fromBranchID })
+		if !slices.Contains(member.BranchAccess, toBranchID) {
+			member.BranchAccess = append(member.BranchAccess, toBranchID)
+		}
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		moved, err := s.transfers.MoveBranchContracts(ctx, member.ID, tenantID, fromBranchID, toBranchID)
+		if err != nil {
+			return err
+		}
+		transfer.ContractsMoved = moved
+		return s.finish(ctx, member, transfer)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// TransferTenant makes toTenantID the member's primary tenant at toBranchID. With the move
+// policy their history goes along; with copy the old tenant keeps it and the new one gets
+// copies of the finished sessions.
+func (s *MemberTransferService) TransferTenant(ctx context.Context, actorID, memberID, toTenantID, toBranchID, policy, reason string) (*domain.MemberTransfer, error) {
+	if policy != domain.TransferPolicyMove && policy != domain.TransferPolicyCopy {
+		return nil, domain.ErrInvalidTransferPolicy
+	}
+	member, err := s.userRepo.GetByID(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+	fromTenantID := member.TenantID
+	if fromTenantID == toTenantID {
+		return nil, domain.ErrInvalidTransferTarget
+	}
+	if err := s.checkBranch(ctx, toBranchID, toTenantID); err != nil {
+		return nil, err
+	}
+
+	transfer := &domain.MemberTransfer{
+		MemberID:     memberID,
+		ActorID:      actorID,
+		Reason:       reason,
+		FromTenantID: fromTenantID,
+		ToTenantID:   toTenantID,
+		ToBranchID:   toBranchID,
+		Policy:       policy,
+	}
+
+	transfer.SessionsCancelled, err = s.cancelUpcoming(ctx, member.ID, func(sched *domain.Schedule) bool {
+		return sched.TenantID == fromTenantID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	member.TenantID = toTenantID
+	member.BranchAccess = []string{toBranchID}
+	member.RemoveMembership(toTenantID) // Now the primary tenant
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if fromTenantID != "" {
+			var err error
+			if policy == domain.TransferPolicyMove {
+				transfer.ContractsMoved, transfer.SchedulesMoved, err = s.transfers.MoveHistory(ctx, member.ID, fromTenantID, toTenantID, toBranchID)
+			} else {
+				transfer.SchedulesCopied, err = s.transfers.CopyHistory(ctx, member.ID, fromTenantID, toTenantID, toBranchID)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return s.finish(ctx, member, transfer)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// ListTransfers returns the member's transfers, newest first
+func (s *MemberTransferService) ListTransfers(ctx context.Context, memberID string) ([]*domain.MemberTransfer, error) {
+	return s.transfers.ListByMember(ctx, memberID)
+}
+
+func (s *MemberTransferService) checkBranch(ctx context.Context, branchID, tenantID string) error {
+	if branchID == "" {
+		return domain.ErrInvalidTransferTarget
+	}
+	branch, err := s.branchRepo.GetByID(ctx, branchID)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && branch.TenantID != tenantID) {
+		return domain.ErrInvalidTransferTarget
+	}
+	return err
+}
+
+// cancelUpcoming cancels the member's scheduled and unconfirmed PT sessions that match
+func (s *MemberTransferService) cancelUpcoming(ctx context.Context, memberID string, match func(*domain.Schedule) bool) (int, error) {
+	now := s.clock.Now()
+	schedules, err := s.schedRepo.GetByMember(ctx, memberID, now, now.Add(upcomingHorizon))
+	if err != nil {
+		return 0, err
+	}
+	cancelled := 0
+	for _, sched := range schedules {
+		if sched.DeletedAt != nil || sched.SelfLogged || !match(sched) {
+			continue
+		}
+		if sched.Status != domain.ScheduleStatusScheduled && sched.Status != domain.ScheduleStatusPendingConfirmation {
+			continue
+		}
+		if err := s.schedRepo.UpdateStatus(ctx, sched.ID, domain.ScheduleStatusCancelled); err != nil {
+			return cancelled, err
+		}
+		cancelled++
+	}
+	return cancelled, nil
+}
+
+// finish saves the member and the audit record
+func (s *MemberTransferService) finish(ctx context.Context, member *domain.User, transfer *domain.MemberTransfer) error {
+	member.UpdatedAt = s.clock.Now()
+	if err := s.userRepo.Update(ctx, member); err != nil {
+		return err
+	}
+	transfer.CreatedAt = s.clock.Now()
+	if err := s.transfers.Record(ctx, transfer); err != nil {
+		return err
+	}
+	log.Printf("[Audit] member %s transferred by %s: tenant %s -> %s, branch %q -> %s, policy=%q contracts=%d schedules=%d copied=%d cancelled=%d",
+		transfer.MemberID, transfer.ActorID, transfer.FromTenantID, transfer.ToTenantID, transfer.FromBranchID, transfer.ToBranchID,
+		transfer.Policy, transfer.ContractsMoved, transfer.SchedulesMoved, transfer.SchedulesCopied, transfer.SessionsCancelled)
+	return nil
+}
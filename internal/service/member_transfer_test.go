@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type transferMocks struct {
+	userRepo   *mocks.UserRepository
+	branchRepo *mocks.BranchRepository
+	schedRepo  *mocks.ScheduleRepository
+	transfers  *mocks.MemberTransferRepository
+}
+
+func newTestTransferService(t *testing.T) (*MemberTransferService, *transferMocks) {
+	m := &transferMocks{
+		userRepo:   mocks.NewUserRepository(t),
+		branchRepo: mocks.NewBranchRepository(t),
+		schedRepo:  mocks.NewScheduleRepository(t),
+		transfers:  mocks.NewMemberTransferRepository(t),
+	}
+	tx := mocks.NewTransactor(t)
+	tx.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInline).Maybe()
+	svc := NewMemberTransferService(m.userRepo, m.branchRepo, m.schedRepo, m.transfers, tx, clock.NewFake(testNow))
+	return svc, m
+}
+
+func TestMemberTransferService_TransferBranch(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestTransferService(t)
+
+	member := &domain.User{ID: "member-1", TenantID: "gym", Roles: []string{domain.RoleMember}, BranchAccess: []string{"north"}}
+	m.userRepo.On("GetByID", ctx, "member-1").Return(member, nil)
+	m.branchRepo.On("GetByID", ctx, "east").Return(&domain.Branch{ID: "east", TenantID: "gym"}, nil)
+	m.schedRepo.On("GetByMember", ctx, "member-1", testNow, testNow.Add(upcomingHorizon)).Return([]*domain.Schedule{
+		{ID: "s1", TenantID: "gym", BranchID: "north", Status: domain.ScheduleStatusScheduled},
+		{ID: "s2", TenantID: "gym", BranchID: "north", Status: domain.ScheduleStatusCancelled},
+		{ID: "s3", TenantID: "gym", BranchID: "east", Status: domain.ScheduleStatusScheduled},
+		{ID: "s4", TenantID: "gym", BranchID: "north", Status: domain.ScheduleStatusScheduled, SelfLogged: true}, // Not a PT session
+	}, nil)
+	m.schedRepo.On("UpdateStatus", ctx, "s1", domain.ScheduleStatusCancelled).Return(nil).Once()
+	m.transfers.On("MoveBranchContracts", ctx, "member-1", "gym", "north", "east").Return(int64(2), nil)
+	m.userRepo.On("Update", ctx, mock.MatchedBy(func(u *domain.User) bool {
+		return assert.ObjectsAreEqual([]string{"east"}, u.BranchAccess)
+	})).Return(nil)
+	m.transfers.On("Record", ctx, mock.AnythingOfType("*domain.MemberTransfer")).Return(nil)
+
+	transfer, err := svc.TransferBranch(ctx, "admin-1", "gym", "member-1", "", "east", "moved house")
+
+	require.NoError(t, err)
+	assert.Equal(t, "north", transfer.FromBranchID, "the member's only branch")
+	assert.Equal(t, int64(2), transfer.ContractsMoved)
+	assert.Equal(t, 1, transfer.SessionsCancelled)
+	assert.Equal(t, testNow, transfer.CreatedAt)
+}
+
+func TestMemberTransferService_TransferTenant(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("copy leaves the old tenant's records in place", func(t *testing.T) {
+		svc, m := newTestTransferService(t)
+		member := &domain.User{
+			ID: "member-1", TenantID: "gym-a", Roles: []string{domain.RoleMember}, BranchAccess: []string{"a-north"},
+			Memberships: []domain.TenantMembership{{TenantID: "gym-b", Roles: []string{domain.RoleMember}}},
+		}
+		m.userRepo.On("GetByID", ctx, "member-1").Return(member, nil)
+		m.branchRepo.On("GetByID", ctx, "b-central").Return(&domain.Branch{ID: "b-central", TenantID: "gym-b"}, nil)
+		m.schedRepo.On("GetByMember", ctx, "member-1", testNow, testNow.Add(upcomingHorizon)).Return(nil, nil)
+		m.transfers.On("CopyHistory", ctx, "member-1", "gym-a", "gym-b", "b-central").Return(int64(12), nil)
+		m.userRepo.On("Update", ctx, mock.MatchedBy(func(u *domain.User) bool {
+			return u.TenantID == "gym-b" && len(u.Memberships) == 0
+		})).Return(nil)
+		m.transfers.On("Record", ctx, mock.AnythingOfType("*domain.MemberTransfer")).Return(nil)
+
+		transfer, err := svc.TransferTenant(ctx, "root", "member-1", "gym-b", "b-central", domain.TransferPolicyCopy, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(12), transfer.SchedulesCopied)
+		assert.Zero(t, transfer.SchedulesMoved)
+	})
+
+	t.Run("the branch must belong to the destination", func(t *testing.T) {
+		svc, m := newTestTransferService(t)
+		m.userRepo.On("GetByID", ctx, "member-1").Return(&domain.User{ID: "member-1", TenantID: "gym-a"}, nil)
+		m.branchRepo.On("GetByID", ctx, "a-north").Return(&domain.Branch{ID: "a-north", TenantID: "gym-a"}, nil)
+
+		_, err := svc.TransferTenant(ctx, "root", "member-1", "gym-b", "a-north", domain.TransferPolicyMove, "")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidTransferTarget)
+	})
+
+	t.Run("rejects unknown policies", func(t *testing.T) {
+		svc, _ := newTestTransferService(t)
+
+		_, err := svc.TransferTenant(ctx, "root", "member-1", "gym-b", "b-central", "merge", "")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidTransferPolicy)
+	})
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// NotificationService is the single entry point for sending notifications to users. It
+// applies each user's channel choices and quiet hours, so callers never have to.
+type NotificationService struct {
+	prefs   domain.NotificationPreferencesRepository
+	inbox   domain.InboxRepository
+	senders map[string]domain.NotificationSender
+	clock   domain.Clock
+	sandbox *SandboxService              // Optional: captures what sandbox tenants send
+	devices domain.DeviceTokenRepository // Optional: see TrackDevices
+}
+
+func NewNotificationService(prefs domain.NotificationPreferencesRepository, inbox domain.InboxRepository, clk domain.Clock, senders ...domain.NotificationSender) *NotificationService {
+	s := &NotificationService{
+		prefs:   prefs,
+		inbox:   inbox,
+		senders: make(map[string]domain.NotificationSender, len(senders)),
+		clock:   clock.OrReal(clk),
+	}
+	for _, sender := range senders {
+		s.senders[sender.Channel()] = sender
+	}
+	return s
+}
+
+// CaptureSandbox makes notifications of sandbox tenants go to sandbox instead of their channels
+func (s *NotificationService) CaptureSandbox(sandbox *SandboxService) {
+	s.sandbox = sandbox
+}
+
+// Notify stores n in the user's inbox and sends it on the channels they picked for its
+// type. During the user's quiet hours only email goes out; push and WhatsApp messages are
+// dropped rather than delayed, since most notifications are stale by morning and the inbox
+// still has them. A channel without a sender only logs. Sandbox tenants' notifications are
+// captured rather than sent.
+func (s *NotificationService) Notify(ctx context.Context, n *domain.Notification) error {
+	prefs, err := s.prefs.GetUser(ctx, n.UserID)
+	if err != nil {
+		return err
+	}
+	now := s.clock.Now()
+	quiet := prefs.QuietHours != nil && prefs.QuietHours.Contains(now)
+
+	var errs []error
+	if s.inbox != nil {
+		if err := s.inbox.Create(ctx, &domain.InboxItem{Notification: *n, CreatedAt: now}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, channel := range prefs.ChannelsFor(n.Type) {
+		if quiet && channel != domain.ChannelEmail {
+			log.Printf("Quiet hours for user %s, skipping %s %s notification", n.UserID, channel, n.Type)
+			continue
+		}
+		if s.sandbox != nil {
+			captured, err := s.sandbox.Capture(ctx, channel, n)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to capture %s notification by %s: %w", n.Type, channel, err))
+				continue
+			}
+			if captured {
+				continue
+			}
+		}
+		sender, ok := s.senders[channel]
+		if !ok {
+			log.Printf("Warning: no %s sender configured, dropping %s notification for %s", channel, n.Type, n.UserID)
+			continue
+		}
+		if err := sender.Send(ctx, n); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send %s notification by %s: %w", n.Type, channel, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Inbox returns a page of the user's notifications along with their unread count
+func (s *NotificationService) Inbox(ctx context.Context, userID string, unreadOnly bool, q domain.PageQuery) (*domain.Page[*domain.InboxItem], int64, error) {
+	page, err := s.inbox.List(ctx, userID, unreadOnly, q)
+	if err != nil {
+		return nil, 0, err
+	}
+	unread, err := s.inbox.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return page, unread, nil
+}
+
+// UnreadCount backs the app's bell badge
+func (s *NotificationService) UnreadCount(ctx context.Context, userID string) (int64, error) {
+	return s.inbox.CountUnread(ctx, userID)
+}
+
+// TrackDevices stores the devices users register for push notifications in devices
+func (s *NotificationService) TrackDevices(devices domain.DeviceTokenRepository) {
+	s.devices = devices
+}
+
+// RegisterDevice has pushes to the user go to the device with this FCM token. Apps
+// register on every start, which keeps the token fresh.
+func (s *NotificationService) RegisterDevice(ctx context.Context, userID, token, platform string) (*domain.DeviceToken, error) {
+	now := s.clock.Now()
+	device := &domain.DeviceToken{Token: token, UserID: userID, Platform: platform, CreatedAt: now, LastSeenAt: now}
+	if err := device.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.devices.Register(ctx, device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// UnregisterDevice stops pushes to the device, e.g. when the user signs out of it
+func (s *NotificationService) UnregisterDevice(ctx context.Context, userID, token string) error {
+	if token == "" {
+		return domain.ErrInvalidDeviceToken
+	}
+	return s.devices.Unregister(ctx, userID, token)
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id string) error {
+	return s.inbox.MarkRead(ctx, userID, id, s.clock.Now())
+}
+
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	return s.inbox.MarkAllRead(ctx, userID, s.clock.Now())
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationService_Notify(t *testing.T) {
+	ctx := context.Background()
+	n := &domain.Notification{UserID: "m1", Type: domain.NotificationScheduleReminder, Title: "Upcoming session"}
+
+	newService := func(t *testing.T, prefs *domain.NotificationPreferences, now time.Time) (*NotificationService, *mocks.NotificationSender, *mocks.NotificationSender) {
+		repo := mocks.NewNotificationPreferencesRepository(t)
+		repo.On("GetUser", ctx, "m1").Return(prefs, nil)
+		push, email := mocks.NewNotificationSender(t), mocks.NewNotificationSender(t)
+		push.On("Channel").Return(domain.ChannelPush)
+		email.On("Channel").Return(domain.ChannelEmail)
+		return NewNotificationService(repo, nil, clock.NewFake(now), push, email), push, email
+	}
+
+	t.Run("defaults to push", func(t *testing.T) {
+		svc, push, _ := newService(t, &domain.NotificationPreferences{UserID: "m1"}, testNow)
+		push.On("Send", ctx, n).Return(nil).Once()
+
+		assert.NoError(t, svc.Notify(ctx, n))
+	})
+
+	t.Run("quiet hours hold back push but not email", func(t *testing.T) {
+		prefs := &domain.NotificationPreferences{
+			UserID:     "m1",
+			QuietHours: &domain.QuietHours{Start: "22:00", End: "07:00", Timezone: "Asia/Jakarta"},
+			Channels:   map[string][]string{domain.NotificationScheduleReminder: {domain.ChannelPush, domain.ChannelEmail}},
+		}
+		threeAM := time.Date(2025, 6, 16, 20, 0, 0, 0, time.UTC) // 03:00 in Jakarta
+		svc, _, email := newService(t, prefs, threeAM)
+		email.On("Send", ctx, n).Return(nil).Once()
+
+		assert.NoError(t, svc.Notify(ctx, n))
+	})
+
+	t.Run("the inbox keeps notifications the user muted", func(t *testing.T) {
+		repo := mocks.NewNotificationPreferencesRepository(t)
+		repo.On("GetUser", ctx, "m1").Return(&domain.NotificationPreferences{
+			UserID:   "m1",
+			Channels: map[string][]string{domain.NotificationScheduleReminder: {}},
+		}, nil)
+		inbox := mocks.NewInboxRepository(t)
+		inbox.On("Create", ctx, &domain.InboxItem{Notification: *n, CreatedAt: testNow}).Return(nil).Once()
+		svc := NewNotificationService(repo, inbox, clock.NewFake(testNow))
+
+		assert.NoError(t, svc.Notify(ctx, n))
+	})
+
+	t.Run("a muted type goes nowhere", func(t *testing.T) {
+		prefs := &domain.NotificationPreferences{UserID: "m1", Channels: map[string][]string{domain.NotificationScheduleReminder: {}}}
+		svc, _, _ := newService(t, prefs, testNow)
+
+		assert.NoError(t, svc.Notify(ctx, n))
+	})
+}
+
+func TestNotificationService_RegisterDevice(t *testing.T) {
+	ctx := context.Background()
+	devices := mocks.NewDeviceTokenRepository(t)
+	svc := NewNotificationService(mocks.NewNotificationPreferencesRepository(t), nil, clock.NewFake(testNow))
+	svc.TrackDevices(devices)
+
+	want := &domain.DeviceToken{Token: "fcm:abc", UserID: "m1", Platform: domain.PlatformAndroid, CreatedAt: testNow, LastSeenAt: testNow}
+	devices.On("Register", ctx, want).Return(nil).Once()
+
+	device, err := svc.RegisterDevice(ctx, "m1", "fcm:abc", domain.PlatformAndroid)
+	assert.NoError(t, err)
+	assert.Equal(t, want, device)
+
+	_, err = svc.RegisterDevice(ctx, "m1", "fcm:abc", "blackberry")
+	assert.ErrorIs(t, err, domain.ErrInvalidDeviceToken)
+}
@@ -0,0 +1,324 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/jobs"
+)
+
+const (
+	offboardingJobType        = "tenant-offboarding-export"
+	offboardingAnonymizeBatch = 200
+	offboardingArchiveURLTTL  = 24 * time.Hour
+)
+
+// TenantOffboardingService deletes tenants in stages, so that nothing is lost by accident:
+// the tenant's data is exported to an archive, the tenant is deactivated for a grace period
+// in which it can be restored, and only then are its members anonymized and its records
+// purged. Everything after the request runs in the background; see Advance.
+type TenantOffboardingService struct {
+	repo       domain.TenantOffboardingRepository
+	data       domain.TenantDataRepository
+	tenantRepo domain.TenantRepository
+	files      domain.FileRepository
+	runs       *jobs.Runner // Optional: records exports in the job history and retries failed ones
+	clock      domain.Clock
+}
+
+func NewTenantOffboardingService(
+	repo domain.TenantOffboardingRepository,
+	data domain.TenantDataRepository,
+	tenantRepo domain.TenantRepository,
+	files domain.FileRepository,
+	runs *jobs.Runner,
+	clk domain.Clock,
+) *TenantOffboardingService {
+	s := &TenantOffboardingService{
+		repo:       repo,
+		data:       data,
+		tenantRepo: tenantRepo,
+		files:      files,
+		runs:       runs,
+		clock:      clock.OrReal(clk),
+	}
+	if runs != nil {
+		runs.Handle(offboardingJobType, func(ctx context.Context, params map[string]interface{}) error {
+			tenantID, _ := params["tenant_id"].(string)
+			o, err := s.repo.GetLatest(ctx, tenantID)
+			if err != nil {
+				return err
+			}
+			return s.advance(ctx, o)
+		})
+	}
+	return s
+}
+
+// Start begins off-boarding a tenant. confirmName must be the tenant's name, as a guard
+// against deleting the wrong one; graceDays 0 uses the default. The export runs in the
+// background and the tenant is deactivated once it has succeeded.
+func (s *TenantOffboardingService) Start(ctx context.Context, tenantID, actorID, confirmName string, graceDays int) (*domain.TenantOffboarding, error) {
+	tenant, err := s.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if confirmName != tenant.Name {
+		return nil, domain.ErrOffboardingConfirmation
+	}
+	if graceDays == 0 {
+		graceDays = domain.DefaultOffboardingGraceDays
+	}
+	if err := domain.ValidateOffboardingGrace(graceDays); err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	o := &domain.TenantOffboarding{
+		TenantID:    tenant.ID,
+		TenantName:  tenant.Name,
+		Stage:       domain.OffboardingStageExporting,
+		GraceDays:   graceDays,
+		RequestedBy: actorID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repo.Create(ctx, o); err != nil {
+		return nil, err
+	}
+
+	// The request shouldn't wait for (or cancel) an export of the tenant's whole history.
+	// The run works on its own copy, as o is returned to the caller.
+	run := *o
+	go func() {
+		o := &run
+		bg := context.Background()
+		if s.runs == nil {
+			_ = s.advance(bg, o)
+			return
+		}
+		params := map[string]interface{}{"tenant_id": o.TenantID, "offboarding_id": o.ID}
+		_ = s.runs.Record(bg, offboardingJobType, o.TenantID, params, func(ctx context.Context) error {
+			return s.advance(ctx, o)
+		})
+	}()
+	return o, nil
+}
+
+// Get returns the tenant's latest off-boarding and, once exported, a link to the archive
+func (s *TenantOffboardingService) Get(ctx context.Context, tenantID string) (*domain.TenantOffboarding, string, error) {
+	o, err := s.repo.GetLatest(ctx, tenantID)
+	if err != nil {
+		return nil, "", err
+	}
+	if o.ArchiveURL == "" || s.files == nil {
+		return o, "", nil
+	}
+	url, err := s.files.SignedURL(ctx, o.ArchiveURL, offboardingArchiveURLTTL)
+	if err != nil {
+		log.Printf("Warning: failed to sign archive of tenant %s: %v", tenantID, err)
+		return o, "", nil
+	}
+	return o, url, nil
+}
+
+// Cancel restores a tenant in its grace period (or whose export failed)
+func (s *TenantOffboardingService) Cancel(ctx context.Context, tenantID string) (*domain.TenantOffboarding, error) {
+	o, err := s.repo.GetLatest(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if !o.Cancellable() {
+		return nil, domain.ErrOffboardingNotCancellable
+	}
+
+	// A failed deactivation may have got as far as the tenant
+	if err := s.tenantRepo.SetDeactivated(ctx, tenantID, nil); err != nil {
+		return nil, fmt.Errorf("failed to reactivate tenant: %w", err)
+	}
+	now := s.clock.Now()
+	o.Stage = domain.OffboardingStageCancelled
+	o.Error = ""
+	o.FinishedAt = &now
+	if err := s.repo.Update(ctx, o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Advance moves every off-boarding on as far as it can go: past the grace period through
+// anonymization and purge, and on from a failed step. Exports still running are left alone.
+func (s *TenantOffboardingService) Advance(ctx context.Context) error {
+	list, err := s.repo.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, o := range list {
+		if o.Stage == domain.OffboardingStageExporting && o.Error == "" {
+			continue
+		}
+		if err := s.advance(ctx, o); err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", o.TenantID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// advance runs the stages from o's current one until it has to wait for the grace period
+// or a step fails; a failure is saved on o for the next attempt
+func (s *TenantOffboardingService) advance(ctx context.Context, o *domain.TenantOffboarding) error {
+	err := s.runStages(ctx, o)
+	o.Error = ""
+	if err != nil {
+		o.Error = err.Error()
+	}
+	if saveErr := s.save(ctx, o); saveErr != nil {
+		log.Printf("Warning: failed to save offboarding of tenant %s: %v", o.TenantID, saveErr)
+	}
+	return err
+}
+
+func (s *TenantOffboardingService) runStages(ctx context.Context, o *domain.TenantOffboarding) error {
+	for {
+		switch o.Stage {
+		case domain.OffboardingStageExporting:
+			if err := s.export(ctx, o); err != nil {
+				return fmt.Errorf("export failed: %w", err)
+			}
+			if err := s.deactivate(ctx, o); err != nil {
+				return fmt.Errorf("deactivation failed: %w", err)
+			}
+			o.Stage = domain.OffboardingStageGrace
+
+		case domain.OffboardingStageGrace:
+			if o.GraceUntil == nil || s.clock.Now().Before(*o.GraceUntil) {
+				return nil
+			}
+			o.Stage = domain.OffboardingStageAnonymizing
+
+		case domain.OffboardingStageAnonymizing:
+			if err := s.anonymize(ctx, o); err != nil {
+				return fmt.Errorf("anonymization failed: %w", err)
+			}
+			o.Stage = domain.OffboardingStagePurging
+
+		case domain.OffboardingStagePurging:
+			purged, err := s.data.Purge(ctx, o.TenantID)
+			o.Progress.RecordsPurged += purged
+			if err != nil {
+				return fmt.Errorf("purge failed: %w", err)
+			}
+			now := s.clock.Now()
+			o.Stage = domain.OffboardingStageCompleted
+			o.FinishedAt = &now
+
+		default:
+			return nil
+		}
+		// Each finished step is saved, so a crash doesn't repeat it
+		if err := s.save(ctx, o); err != nil {
+			return err
+		}
+	}
+}
+
+// export writes every record of the tenant to a zip archive with one JSON Lines file per
+// dataset and stores it
+func (s *TenantOffboardingService) export(ctx context.Context, o *domain.TenantOffboarding) error {
+	if s.files == nil {
+		return errors.New("file storage is not configured")
+	}
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	var (
+		current string
+		file    io.Writer
+	)
+	counts, err := s.data.Export(ctx, o.TenantID, func(dataset string, record []byte) error {
+		if dataset != current {
+			w, err := archive.Create(dataset + ".jsonl")
+			if err != nil {
+				return err
+			}
+			current, file = dataset, w
+		}
+		if _, err := file.Write(record); err != nil {
+			return err
+		}
+		_, err := file.Write([]byte("\n"))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("offboarding/%s/%s.zip", o.TenantID, o.ID)
+	url, err := s.files.Upload(ctx, buf.Bytes(), filename, "application/zip")
+	if err != nil {
+		return fmt.Errorf("failed to store archive: %w", err)
+	}
+	o.ArchiveURL = url
+	o.Progress.ExportedRecords = counts
+	o.Progress.ArchiveBytes = int64(buf.Len())
+	return nil
+}
+
+// deactivate locks everyone out of the tenant and starts the grace period
+func (s *TenantOffboardingService) deactivate(ctx context.Context, o *domain.TenantOffboarding) error {
+	now := s.clock.Now()
+	if err := s.tenantRepo.SetDeactivated(ctx, o.TenantID, &now); err != nil {
+		return err
+	}
+	if err := s.data.RevokeSessions(ctx, o.TenantID); err != nil {
+		return err
+	}
+	graceUntil := now.AddDate(0, 0, o.GraceDays)
+	o.DeactivatedAt = &now
+	o.GraceUntil = &graceUntil
+	return nil
+}
+
+// anonymize erases the members' personal data batch by batch, deleting the stored files
+// their records pointed to
+func (s *TenantOffboardingService) anonymize(ctx context.Context, o *domain.TenantOffboarding) error {
+	for {
+		batch, err := s.data.AnonymizeMembers(ctx, o.TenantID, offboardingAnonymizeBatch)
+		if err != nil {
+			return err
+		}
+		for _, url := range batch.Files {
+			if s.files == nil {
+				break
+			}
+			if err := s.files.Delete(ctx, url); err != nil {
+				log.Printf("Warning: failed to delete file %s of tenant %s: %v", url, o.TenantID, err)
+			}
+		}
+		if batch.Members == 0 {
+			return nil
+		}
+		o.Progress.MembersAnonymized += batch.Members
+		if err := s.save(ctx, o); err != nil {
+			return err
+		}
+	}
+}
+
+// save stores progress even when the run's context has been cancelled
+func (s *TenantOffboardingService) save(ctx context.Context, o *domain.TenantOffboarding) error {
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	return s.repo.Update(saveCtx, o)
+}
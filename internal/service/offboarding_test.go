@@ -0,0 +1,152 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantOffboardingService(t *testing.T) {
+	ctx := context.Background()
+	tenant := &domain.Tenant{ID: "t1", Name: "Iron Temple"}
+
+	type deps struct {
+		repo    *mocks.TenantOffboardingRepository
+		data    *mocks.TenantDataRepository
+		tenants *mocks.TenantRepository
+		files   *mocks.FileRepository
+		clk     *clock.Fake
+	}
+	newService := func(t *testing.T) (*TenantOffboardingService, deps) {
+		d := deps{
+			repo:    mocks.NewTenantOffboardingRepository(t),
+			data:    mocks.NewTenantDataRepository(t),
+			tenants: mocks.NewTenantRepository(t),
+			files:   mocks.NewFileRepository(t),
+			clk:     clock.NewFake(testNow),
+		}
+		return NewTenantOffboardingService(d.repo, d.data, d.tenants, d.files, nil, d.clk), d
+	}
+
+	t.Run("start needs the tenant's name and a valid grace period", func(t *testing.T) {
+		svc, d := newService(t)
+		d.tenants.On("GetByID", ctx, tenant.ID).Return(tenant, nil)
+
+		_, err := svc.Start(ctx, tenant.ID, "admin", "Iron temple", 0)
+		assert.ErrorIs(t, err, domain.ErrOffboardingConfirmation)
+
+		_, err = svc.Start(ctx, tenant.ID, "admin", tenant.Name, 3)
+		assert.ErrorIs(t, err, domain.ErrInvalidOffboardingGrace)
+		d.repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("a retried export archives the data, deactivates the tenant and waits out the grace period", func(t *testing.T) {
+		svc, d := newService(t)
+		o := &domain.TenantOffboarding{ID: "o1", TenantID: tenant.ID, Stage: domain.OffboardingStageExporting, GraceDays: 30, Error: "export failed: timeout"}
+		d.repo.On("ListActive", ctx).Return([]*domain.TenantOffboarding{o}, nil)
+		d.data.On("Export", ctx, tenant.ID, mock.Anything).Run(func(args mock.Arguments) {
+			write := args.Get(2).(func(string, []byte) error)
+			require.NoError(t, write("users", []byte(`{"_id":"u1"}`)))
+			require.NoError(t, write("users", []byte(`{"_id":"u2"}`)))
+			require.NoError(t, write("schedules", []byte(`{"_id":"s1"}`)))
+		}).Return(map[string]int{"users": 2, "schedules": 1}, nil)
+
+		var archive []byte
+		d.files.On("Upload", ctx, mock.Anything, "offboarding/t1/o1.zip", "application/zip").Run(func(args mock.Arguments) {
+			archive = args.Get(1).([]byte)
+		}).Return("https://files/offboarding/t1/o1.zip", nil)
+		d.tenants.On("SetDeactivated", ctx, tenant.ID, mock.MatchedBy(func(at *time.Time) bool {
+			return at != nil && at.Equal(testNow)
+		})).Return(nil)
+		d.data.On("RevokeSessions", ctx, tenant.ID).Return(nil)
+		d.repo.On("Update", mock.Anything, o).Return(nil)
+
+		require.NoError(t, svc.Advance(ctx))
+
+		assert.Equal(t, domain.OffboardingStageGrace, o.Stage)
+		assert.Empty(t, o.Error)
+		assert.Equal(t, testNow.AddDate(0, 0, 30), *o.GraceUntil)
+		assert.Equal(t, "https://files/offboarding/t1/o1.zip", o.ArchiveURL)
+		assert.Equal(t, map[string]int{"users": 2, "schedules": 1}, o.Progress.ExportedRecords)
+
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		require.NoError(t, err)
+		require.Len(t, zr.File, 2)
+		assert.Equal(t, "users.jsonl", zr.File[0].Name)
+		f, err := zr.File[0].Open()
+		require.NoError(t, err)
+		content, _ := io.ReadAll(f)
+		assert.Equal(t, "{\"_id\":\"u1\"}\n{\"_id\":\"u2\"}\n", string(content))
+		d.data.AssertNotCalled(t, "AnonymizeMembers", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("running exports are left alone", func(t *testing.T) {
+		svc, d := newService(t)
+		o := &domain.TenantOffboarding{ID: "o1", TenantID: tenant.ID, Stage: domain.OffboardingStageExporting}
+		d.repo.On("ListActive", ctx).Return([]*domain.TenantOffboarding{o}, nil)
+
+		require.NoError(t, svc.Advance(ctx))
+		d.data.AssertNotCalled(t, "Export", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("after the grace period members are anonymized and the tenant purged", func(t *testing.T) {
+		svc, d := newService(t)
+		graceUntil := testNow.Add(-time.Hour)
+		o := &domain.TenantOffboarding{ID: "o1", TenantID: tenant.ID, Stage: domain.OffboardingStageGrace, GraceUntil: &graceUntil}
+		d.repo.On("ListActive", ctx).Return([]*domain.TenantOffboarding{o}, nil)
+		d.data.On("AnonymizeMembers", ctx, tenant.ID, offboardingAnonymizeBatch).
+			Return(&domain.AnonymizedBatch{Members: 2, Files: []string{"https://files/scan.jpg"}}, nil).Once()
+		d.data.On("AnonymizeMembers", ctx, tenant.ID, offboardingAnonymizeBatch).Return(&domain.AnonymizedBatch{}, nil).Once()
+		d.files.On("Delete", ctx, "https://files/scan.jpg").Return(errors.New("already gone"))
+		d.data.On("Purge", ctx, tenant.ID).Return(int64(57), nil)
+		d.repo.On("Update", mock.Anything, o).Return(nil)
+
+		require.NoError(t, svc.Advance(ctx))
+
+		assert.Equal(t, domain.OffboardingStageCompleted, o.Stage)
+		assert.Equal(t, 2, o.Progress.MembersAnonymized)
+		assert.Equal(t, int64(57), o.Progress.RecordsPurged)
+		assert.Equal(t, testNow, *o.FinishedAt)
+	})
+
+	t.Run("a failed step is saved for the next run", func(t *testing.T) {
+		svc, d := newService(t)
+		o := &domain.TenantOffboarding{ID: "o1", TenantID: tenant.ID, Stage: domain.OffboardingStagePurging}
+		d.repo.On("ListActive", ctx).Return([]*domain.TenantOffboarding{o}, nil)
+		d.data.On("Purge", ctx, tenant.ID).Return(int64(10), errors.New("connection reset"))
+		d.repo.On("Update", mock.Anything, o).Return(nil)
+
+		assert.Error(t, svc.Advance(ctx))
+		assert.Equal(t, domain.OffboardingStagePurging, o.Stage)
+		assert.Equal(t, "purge failed: connection reset", o.Error)
+		assert.Equal(t, int64(10), o.Progress.RecordsPurged)
+	})
+
+	t.Run("cancel restores the tenant during the grace period only", func(t *testing.T) {
+		svc, d := newService(t)
+		graceUntil := testNow.AddDate(0, 0, 10)
+		o := &domain.TenantOffboarding{ID: "o1", TenantID: tenant.ID, Stage: domain.OffboardingStageGrace, GraceUntil: &graceUntil}
+		d.repo.On("GetLatest", ctx, tenant.ID).Return(o, nil).Once()
+		d.tenants.On("SetDeactivated", ctx, tenant.ID, (*time.Time)(nil)).Return(nil)
+		d.repo.On("Update", ctx, o).Return(nil)
+
+		cancelled, err := svc.Cancel(ctx, tenant.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.OffboardingStageCancelled, cancelled.Stage)
+
+		d.repo.On("GetLatest", ctx, tenant.ID).Return(&domain.TenantOffboarding{Stage: domain.OffboardingStageAnonymizing}, nil).Once()
+		_, err = svc.Cancel(ctx, tenant.ID)
+		assert.ErrorIs(t, err, domain.ErrOffboardingNotCancellable)
+	})
+}
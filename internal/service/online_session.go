@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// attachMeeting generates a video link for an online session that has none. Booking never
+// fails over it: the coach can still paste a link later.
+func (s *PTService) attachMeeting(ctx context.Context, schedule *domain.Schedule) {
+	if s.meetings == nil || !schedule.IsOnline() || schedule.MeetingURL != "" {
+		return
+	}
+	link, err := s.meetings.CreateMeeting(ctx, schedule)
+	if err != nil {
+		log.Printf("Warning: no meeting link generated for online session of member %s: %v", schedule.MemberID, err)
+		return
+	}
+	schedule.MeetingURL = link
+}
+
+// SetSessionModality switches the coach's session between in person and online. An online
+// session keeps meetingURL when given, otherwise its current link or a generated one;
+// moving a session back to the gym drops its link.
+func (s *PTService) SetSessionModality(ctx context.Context, coachID, scheduleID, modality, meetingURL string) (*domain.Schedule, error) {
+	schedule, err := s.GetSchedule(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.DeletedAt != nil {
+		return nil, domain.ErrScheduleNotFound
+	}
+	if schedule.CoachID != coachID {
+		return nil, domain.ErrForbidden
+	}
+
+	before := *schedule
+	schedule.Modality = modality
+	switch {
+	case meetingURL != "":
+		schedule.MeetingURL = meetingURL
+	case modality != domain.SessionModalityOnline:
+		schedule.MeetingURL = ""
+	}
+	if err := schedule.NormalizeModality(); err != nil {
+		return nil, err
+	}
+	// Moving online may take the session outside the coach's hours at the branch, and
+	// back in person the reverse; either way the new modality must fit
+	if err := s.checkAvailability(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	s.attachMeeting(ctx, schedule)
+	update := func(ctx context.Context) error {
+		return s.schedRepo.Update(ctx, schedule)
+	}
+	if schedule.Modality != before.Modality || schedule.MeetingURL != before.MeetingURL {
+		err = s.recordChange(ctx, update, scheduleChangedMessage(schedule, domain.ScheduleChangeModality))
+	} else {
+		err = update(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
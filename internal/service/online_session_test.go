@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOnlinePTService(t *testing.T) (*PTService, *ptServiceMocks, *mocks.MeetingLinkGenerator) {
+	_, m := newTestPTService(t)
+	meetings := mocks.NewMeetingLinkGenerator(t)
+	svc := NewPTService(m.pkgRepo, m.contractRepo, m.schedRepo, mocks.NewWorkoutSessionRepository(t), m.setLogRepo, m.creditRepo, nil, nil, m.locker, nil, meetings, nil, clock.NewFake(testNow))
+	return svc, m, meetings
+}
+
+func TestPTService_CreateSchedule_Online(t *testing.T) {
+	ctx := context.Background()
+	contract := &domain.PTContract{ID: "contract-1", MemberID: "member-1", BranchID: "br-1", RemainingSessions: 2, Status: domain.PackageStatusActive}
+	pending := []string{domain.ScheduleStatusScheduled, domain.ScheduleStatusPendingConfirmation}
+
+	book := func(t *testing.T, schedule *domain.Schedule) (*ptServiceMocks, *mocks.MeetingLinkGenerator, error) {
+		svc, m, meetings := newTestOnlinePTService(t)
+		m.contractRepo.On("GetByID", anyCtx, "contract-1").Return(contract, nil)
+		m.schedRepo.On("CountByContractAndStatus", anyCtx, "contract-1", pending).Return(int64(0), nil)
+		m.schedRepo.On("Create", anyCtx, schedule).Return(nil)
+		return m, meetings, svc.CreateSchedule(ctx, schedule)
+	}
+
+	t.Run("generates a link when none is given", func(t *testing.T) {
+		schedule := &domain.Schedule{ContractID: "contract-1", MemberID: "member-1", BranchID: "br-1", Modality: domain.SessionModalityOnline}
+		svc, m, meetings := newTestOnlinePTService(t)
+		meetings.On("CreateMeeting", anyCtx, schedule).Return("https://zoom.us/j/123", nil)
+		m.contractRepo.On("GetByID", anyCtx, "contract-1").Return(contract, nil)
+		m.schedRepo.On("CountByContractAndStatus", anyCtx, "contract-1", pending).Return(int64(0), nil)
+		m.schedRepo.On("Create", anyCtx, schedule).Return(nil)
+
+		require.NoError(t, svc.CreateSchedule(ctx, schedule))
+		assert.Equal(t, "https://zoom.us/j/123", schedule.MeetingURL)
+	})
+
+	t.Run("keeps the coach's own link", func(t *testing.T) {
+		schedule := &domain.Schedule{ContractID: "contract-1", MemberID: "member-1", BranchID: "br-1", Modality: domain.SessionModalityOnline, MeetingURL: "https://meet.google.com/abc-defg-hij"}
+
+		_, _, err := book(t, schedule)
+
+		require.NoError(t, err)
+		assert.Equal(t, "https://meet.google.com/abc-defg-hij", schedule.MeetingURL)
+	})
+
+	t.Run("books without a link when the provider fails", func(t *testing.T) {
+		schedule := &domain.Schedule{ContractID: "contract-1", MemberID: "member-1", BranchID: "br-1", Modality: domain.SessionModalityOnline}
+		svc, m, meetings := newTestOnlinePTService(t)
+		meetings.On("CreateMeeting", anyCtx, schedule).Return("", errors.New("zoom is down"))
+		m.contractRepo.On("GetByID", anyCtx, "contract-1").Return(contract, nil)
+		m.schedRepo.On("CountByContractAndStatus", anyCtx, "contract-1", pending).Return(int64(0), nil)
+		m.schedRepo.On("Create", anyCtx, schedule).Return(nil)
+
+		require.NoError(t, svc.CreateSchedule(ctx, schedule))
+		assert.Empty(t, schedule.MeetingURL)
+	})
+
+	t.Run("defaults to in person", func(t *testing.T) {
+		schedule := &domain.Schedule{ContractID: "contract-1", MemberID: "member-1", BranchID: "br-1"}
+
+		_, _, err := book(t, schedule)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.SessionModalityInPerson, schedule.Modality)
+	})
+
+	t.Run("rejects a link on an in person session", func(t *testing.T) {
+		svc, _, _ := newTestOnlinePTService(t)
+
+		err := svc.CreateSchedule(ctx, &domain.Schedule{ContractID: "contract-1", MeetingURL: "https://zoom.us/j/123"})
+
+		assert.Equal(t, domain.ErrInvalidMeetingURL, err)
+	})
+}
+
+func TestPTService_SetSessionModality(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("moving online generates a link", func(t *testing.T) {
+		svc, m, meetings := newTestOnlinePTService(t)
+		m.schedRepo.On("GetByID", anyCtx, "sched-1").Return(&domain.Schedule{ID: "sched-1", CoachID: "coach-1"}, nil)
+		meetings.On("CreateMeeting", anyCtx, mock.AnythingOfType("*domain.Schedule")).Return("https://meet.jit.si/metamorph-1", nil)
+		m.schedRepo.On("Update", anyCtx, mock.AnythingOfType("*domain.Schedule")).Return(nil)
+
+		schedule, err := svc.SetSessionModality(ctx, "coach-1", "sched-1", domain.SessionModalityOnline, "")
+
+		require.NoError(t, err)
+		assert.True(t, schedule.IsOnline())
+		assert.Equal(t, "https://meet.jit.si/metamorph-1", schedule.MeetingURL)
+	})
+
+	t.Run("moving back in person drops the link", func(t *testing.T) {
+		svc, m, _ := newTestOnlinePTService(t)
+		m.schedRepo.On("GetByID", anyCtx, "sched-1").Return(&domain.Schedule{ID: "sched-1", CoachID: "coach-1", Modality: domain.SessionModalityOnline, MeetingURL: "https://zoom.us/j/123"}, nil)
+		m.schedRepo.On("Update", anyCtx, mock.AnythingOfType("*domain.Schedule")).Return(nil)
+
+		schedule, err := svc.SetSessionModality(ctx, "coach-1", "sched-1", domain.SessionModalityInPerson, "")
+
+		require.NoError(t, err)
+		assert.Empty(t, schedule.MeetingURL)
+	})
+
+	t.Run("another coach's session", func(t *testing.T) {
+		svc, m, _ := newTestOnlinePTService(t)
+		m.schedRepo.On("GetByID", anyCtx, "sched-1").Return(&domain.Schedule{ID: "sched-1", CoachID: "coach-2"}, nil)
+
+		_, err := svc.SetSessionModality(ctx, "coach-1", "sched-1", domain.SessionModalityOnline, "")
+
+		assert.Equal(t, domain.ErrForbidden, err)
+	})
+}
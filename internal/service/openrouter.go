@@ -0,0 +1,116 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const openRouterAPIURL = "https://openrouter.ai/api/v1/chat/completions"
+
+// openRouterClient sends chat completions to OpenRouter. The digitizer and the form
+// analyzer share it so requests are built and errors surfaced the same way.
+type openRouterClient struct {
+	apiKey     string
+	model      string
+	title      string // X-Title header, shown in the OpenRouter dashboard
+	httpClient *http.Client
+}
+
+func newOpenRouterClient(apiKey, model, title string) *openRouterClient {
+	return &openRouterClient{
+		apiKey:     apiKey,
+		model:      model,
+		title:      title,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// imagePart is a user message content part carrying an inline image
+func imagePart(mimeType string, data []byte) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "image_url",
+		"image_url": map[string]string{
+			"url": fmt.Sprintf("data:%s;base64,%s", mimeType, base64.StdEncoding.EncodeToString(data)),
+		},
+	}
+}
+
+// complete sends the messages and returns the content of the first choice
+func (c *openRouterClient) complete(ctx context.Context, messages []map[string]interface{}, temperature float64) (string, error) {
+	requestBody := map[string]interface{}{
+		"model":       c.model,
+		"messages":    messages,
+		"temperature": temperature,
+	}
+
+	payload, err := json.Marshal(requestBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Create HTTP request
+	req, err := http.NewRequestWithContext(ctx, "POST", openRouterAPIURL, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("HTTP-Referer", "https://homgym.app") // Optional
+	req.Header.Set("X-Title", c.title)                   // Optional
+
+	// Send request
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("openrouter api error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var apiResponse struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Error *struct {
+			Message  string                 `json:"message"`
+			Code     int                    `json:"code"`
+			Metadata map[string]interface{} `json:"metadata"`
+		} `json:"error"`
+	}
+
+	if err := json.Unmarshal(body, &apiResponse); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if apiResponse.Error != nil {
+		errorMsg := fmt.Sprintf("openrouter error: %s (code: %d)", apiResponse.Error.Message, apiResponse.Error.Code)
+		if apiResponse.Error.Metadata != nil {
+			if providerErr, ok := apiResponse.Error.Metadata["provider_error"].(string); ok {
+				errorMsg += fmt.Sprintf(" - Provider error: %s", providerErr)
+			}
+		}
+		return "", fmt.Errorf("%s", errorMsg)
+	}
+
+	if len(apiResponse.Choices) == 0 {
+		return "", fmt.Errorf("no response from AI model")
+	}
+
+	return apiResponse.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+const (
+	outboxLease       = 30 * time.Second // How long a claimed message is hidden from other relays
+	outboxBaseBackoff = 10 * time.Second
+	outboxMaxBackoff  = time.Hour
+)
+
+// Outbox records domain changes together with the messages describing their side effects
+type Outbox struct {
+	repo  domain.OutboxRepository
+	tx    domain.Transactor
+	relay *OutboxRelay // Optional: notified after commit for prompt delivery
+}
+
+func NewOutbox(repo domain.OutboxRepository, tx domain.Transactor, relay *OutboxRelay) *Outbox {
+	return &Outbox{repo: repo, tx: tx, relay: relay}
+}
+
+// Write runs change and adds msgs in one transaction, so the side effects are delivered
+// if and only if the change is committed
+func (o *Outbox) Write(ctx context.Context, change func(ctx context.Context) error, msgs ...*domain.OutboxMessage) error {
+	err := o.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := change(ctx); err != nil {
+			return err
+		}
+		for _, msg := range msgs {
+			if err := o.repo.Add(ctx, msg); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil && o.relay != nil {
+		o.relay.Notify()
+	}
+	return err
+}
+
+// OutboxHandler delivers one outbox message. Delivery is at-least-once, and a message whose
+// delivery fails is retried through every handler of its topic, so handlers must be idempotent.
+type OutboxHandler func(ctx context.Context, msg *domain.OutboxMessage) error
+
+// OutboxRelay delivers outbox messages to the handlers registered for their topic
+type OutboxRelay struct {
+	repo  domain.OutboxRepository
+	clock domain.Clock
+
+	mu       sync.RWMutex
+	handlers map[string][]OutboxHandler
+
+	wake chan struct{}
+}
+
+func NewOutboxRelay(repo domain.OutboxRepository, clk domain.Clock) *OutboxRelay {
+	return &OutboxRelay{
+		repo:     repo,
+		clock:    clock.OrReal(clk),
+		handlers: make(map[string][]OutboxHandler),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Handle registers a handler for a topic
+func (r *OutboxRelay) Handle(topic string, handler OutboxHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[topic] = append(r.handlers[topic], handler)
+}
+
+// Notify wakes Run so freshly committed messages are delivered without waiting for the next poll
+func (r *OutboxRelay) Notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run delivers due messages every interval (or when notified) until ctx is cancelled
+func (r *OutboxRelay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RelayDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Warning: outbox relay failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.wake:
+		}
+	}
+}
+
+// RelayDue delivers every message that is currently due and returns how many were delivered
+func (r *OutboxRelay) RelayDue(ctx context.Context) (int, error) {
+	delivered := 0
+	for ctx.Err() == nil {
+		msg, err := r.repo.Claim(ctx, r.clock.Now(), outboxLease)
+		if err != nil {
+			return delivered, err
+		}
+		if msg == nil {
+			return delivered, nil
+		}
+
+		if err := r.deliver(ctx, msg); err != nil {
+			dead := msg.Attempts >= domain.MaxOutboxAttempts
+			if dead {
+				log.Printf("Warning: giving up on outbox message %s (%s) after %d attempts: %v", msg.ID, msg.Topic, msg.Attempts, err)
+			}
+			if markErr := r.repo.MarkFailed(ctx, msg.ID, err.Error(), r.clock.Now().Add(outboxBackoff(msg.Attempts)), dead); markErr != nil {
+				return delivered, markErr
+			}
+			continue
+		}
+
+		if err := r.repo.MarkDelivered(ctx, msg.ID, r.clock.Now()); err != nil {
+			return delivered, err
+		}
+		delivered++
+	}
+	return delivered, ctx.Err()
+}
+
+func (r *OutboxRelay) deliver(ctx context.Context, msg *domain.OutboxMessage) error {
+	r.mu.RLock()
+	handlers := r.handlers[msg.Topic]
+	r.mu.RUnlock()
+
+	if len(handlers) == 0 {
+		return fmt.Errorf("no handler registered for topic %s", msg.Topic)
+	}
+
+	var errs []error
+	for _, h := range handlers {
+		if err := h(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// outboxBackoff doubles the retry delay with each attempt, up to outboxMaxBackoff
+func outboxBackoff(attempts int) time.Duration {
+	d := outboxBaseBackoff
+	for i := 1; i < attempts && d < outboxMaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, outboxMaxBackoff)
+}
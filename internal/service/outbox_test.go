@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// runInline is a Transactor.WithinTransaction stand-in that just calls fn
+func runInline(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestOutbox_Write(t *testing.T) {
+	ctx := context.Background()
+	msg := &domain.OutboxMessage{Topic: domain.OutboxTopicScanChanged, Key: "user-1"}
+
+	t.Run("adds the messages after the change", func(t *testing.T) {
+		repo := mocks.NewOutboxRepository(t)
+		tx := mocks.NewTransactor(t)
+		tx.On("WithinTransaction", ctx, mock.Anything).Return(runInline)
+		repo.On("Add", ctx, msg).Return(nil)
+
+		changed := false
+		err := NewOutbox(repo, tx, nil).Write(ctx, func(context.Context) error {
+			changed = true
+			return nil
+		}, msg)
+
+		require.NoError(t, err)
+		assert.True(t, changed)
+	})
+
+	t.Run("a failed change adds nothing", func(t *testing.T) {
+		repo := mocks.NewOutboxRepository(t)
+		tx := mocks.NewTransactor(t)
+		tx.On("WithinTransaction", ctx, mock.Anything).Return(runInline)
+
+		err := NewOutbox(repo, tx, nil).Write(ctx, func(context.Context) error {
+			return domain.ErrNotFound
+		}, msg)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func TestOutboxRelay_RelayDue(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("delivers due messages to every handler of the topic", func(t *testing.T) {
+		repo := mocks.NewOutboxRepository(t)
+		msg := &domain.OutboxMessage{ID: "m1", Topic: domain.OutboxTopicScanChanged, Key: "user-1", Attempts: 1}
+		repo.On("Claim", ctx, testNow, outboxLease).Return(msg, nil).Once()
+		repo.On("Claim", ctx, testNow, outboxLease).Return(nil, nil).Once()
+		repo.On("MarkDelivered", ctx, "m1", testNow).Return(nil)
+
+		relay := NewOutboxRelay(repo, clock.NewFake(testNow))
+		var got []string
+		for _, name := range []string{"cache", "webhooks"} {
+			relay.Handle(domain.OutboxTopicScanChanged, func(_ context.Context, m *domain.OutboxMessage) error {
+				got = append(got, name+":"+m.Key)
+				return nil
+			})
+		}
+
+		n, err := relay.RelayDue(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, []string{"cache:user-1", "webhooks:user-1"}, got)
+	})
+
+	t.Run("a failed delivery is retried later", func(t *testing.T) {
+		repo := mocks.NewOutboxRepository(t)
+		msg := &domain.OutboxMessage{ID: "m1", Topic: domain.OutboxTopicScanChanged, Attempts: 3}
+		repo.On("Claim", ctx, testNow, outboxLease).Return(msg, nil).Once()
+		repo.On("Claim", ctx, testNow, outboxLease).Return(nil, nil).Once()
+		repo.On("MarkFailed", ctx, "m1", "redis unavailable", testNow.Add(40*time.Second), false).Return(nil)
+
+		relay := NewOutboxRelay(repo, clock.NewFake(testNow))
+		relay.Handle(domain.OutboxTopicScanChanged, func(context.Context, *domain.OutboxMessage) error {
+			return errors.New("redis unavailable")
+		})
+
+		n, err := relay.RelayDue(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		repo := mocks.NewOutboxRepository(t)
+		msg := &domain.OutboxMessage{ID: "m1", Topic: "unknown.topic", Attempts: domain.MaxOutboxAttempts}
+		repo.On("Claim", ctx, testNow, outboxLease).Return(msg, nil).Once()
+		repo.On("Claim", ctx, testNow, outboxLease).Return(nil, nil).Once()
+		repo.On("MarkFailed", ctx, "m1", mock.Anything, mock.Anything, true).Return(nil)
+
+		_, err := NewOutboxRelay(repo, clock.NewFake(testNow)).RelayDue(ctx)
+
+		require.NoError(t, err)
+	})
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	assert.Equal(t, 10*time.Second, outboxBackoff(1))
+	assert.Equal(t, 20*time.Second, outboxBackoff(2))
+	assert.Equal(t, 80*time.Second, outboxBackoff(4))
+	assert.Equal(t, time.Hour, outboxBackoff(20))
+}
@@ -7,6 +7,8 @@ import (
 	"os"
 	"time"
 
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
 	"github.com/mansoorceksport/metamorph/internal/infrastructure/ipaymu"
 	"github.com/oklog/ulid/v2"
 )
@@ -24,17 +26,27 @@ type PaymentProvider interface {
 	GenerateVA(ctx context.Context, bank string, amount int64, userID string) (*VAResponse, error)
 }
 
+// vaValidity is how long a virtual account stays payable when the provider doesn't say
+const vaValidity = 24 * time.Hour
+
 // MockIPaymuClient is a mock implementation of PaymentProvider for development
-type MockIPaymuClient struct{}
+type MockIPaymuClient struct {
+	clock domain.Clock
+}
+
+func NewMockPaymentProvider(clk domain.Clock) *MockIPaymuClient {
+	return &MockIPaymuClient{clock: clock.OrReal(clk)}
+}
 
 // IPaymuClientAdapter adapts the ipaymu.Client to PaymentProvider interface
 type IPaymuClientAdapter struct {
 	client *ipaymu.Client
+	clock  domain.Clock
 }
 
 // NewPaymentProvider returns the appropriate PaymentProvider based on environment config
 // If IPAYMU_API_KEY is empty, returns a mock client for development
-func NewPaymentProvider() PaymentProvider {
+func NewPaymentProvider(clk domain.Clock) PaymentProvider {
 	apiKey := os.Getenv("IPAYMU_API_KEY")
 	va := os.Getenv("IPAYMU_VA")
 	baseURL := os.Getenv("IPAYMU_BASE_URL")
@@ -42,7 +54,7 @@ func NewPaymentProvider() PaymentProvider {
 
 	if apiKey == "" || va == "" {
 		log.Println("[Payment] Using mock iPaymu client (no credentials configured)")
-		return &MockIPaymuClient{}
+		return NewMockPaymentProvider(clk)
 	}
 
 	if baseURL == "" {
@@ -63,7 +75,7 @@ func NewPaymentProvider() PaymentProvider {
 		NotifyURL: webhookURL,
 	})
 
-	return &IPaymuClientAdapter{client: client}
+	return &IPaymuClientAdapter{client: client, clock: clock.OrReal(clk)}
 }
 
 // GenerateVA generates a mock Virtual Account number
@@ -87,7 +99,7 @@ func (m *MockIPaymuClient) GenerateVA(ctx context.Context, bank string, amount i
 	return &VAResponse{
 		VANumber:  vaNumber,
 		SessionID: sessionID,
-		ExpiresAt: time.Now().UTC().Add(24 * time.Hour),
+		ExpiresAt: m.clock.Now().UTC().Add(vaValidity),
 	}, nil
 }
 
@@ -113,9 +125,13 @@ func (a *IPaymuClientAdapter) GenerateVA(ctx context.Context, bank string, amoun
 		return nil, fmt.Errorf("payment provider error: %w", err)
 	}
 
+	expiresAt := resp.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = a.clock.Now().UTC().Add(vaValidity)
+	}
 	return &VAResponse{
 		VANumber:  resp.VANumber,
 		SessionID: resp.SessionID,
-		ExpiresAt: resp.ExpiresAt,
+		ExpiresAt: expiresAt,
 	}, nil
 }
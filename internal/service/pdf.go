@@ -0,0 +1,247 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	_ "image/png" // Register PNG decoder for signature images
+	"math"
+	"strings"
+)
+
+// Minimal PDF writer (A4, Helvetica) so documents can be generated without an external library.
+// Supports wrapped text, embedded images and bar charts, which is all agreements and reports need.
+
+const (
+	pdfPageWidth   = 595.0
+	pdfPageHeight  = 842.0
+	pdfMargin      = 50.0
+	pdfLineSpacing = 1.4
+)
+
+type pdfImage struct {
+	data          []byte // JPEG encoded
+	width, height int
+}
+
+type pdfPage struct {
+	content bytes.Buffer
+	images  []int // indexes into pdfDocument.images
+}
+
+type pdfDocument struct {
+	pages  []*pdfPage
+	images []pdfImage
+	y      float64 // Current baseline from the bottom of the page
+}
+
+func newPDFDocument() *pdfDocument {
+	d := &pdfDocument{}
+	d.newPage()
+	return d
+}
+
+func (d *pdfDocument) newPage() {
+	d.pages = append(d.pages, &pdfPage{})
+	d.y = pdfPageHeight - pdfMargin
+}
+
+func (d *pdfDocument) page() *pdfPage {
+	return d.pages[len(d.pages)-1]
+}
+
+// ensureSpace starts a new page if the next block of the given height would not fit
+func (d *pdfDocument) ensureSpace(height float64) {
+	if d.y-height < pdfMargin {
+		d.newPage()
+	}
+}
+
+// Heading writes a single bold line
+func (d *pdfDocument) Heading(text string, size float64) {
+	d.writeLines([]string{text}, "F2", size)
+}
+
+// Paragraph writes text wrapped to the page width, honouring explicit line breaks
+func (d *pdfDocument) Paragraph(text string, size float64) {
+	var lines []string
+	for _, raw := range strings.Split(text, "\n") {
+		lines = append(lines, wrapPDFLine(raw, size)...)
+	}
+	d.writeLines(lines, "F1", size)
+}
+
+// Space adds vertical whitespace
+func (d *pdfDocument) Space(height float64) {
+	d.y -= height
+}
+
+// Image embeds an image scaled to the given width, preserving aspect ratio
+func (d *pdfDocument) Image(data []byte, width float64) error {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	// Flatten onto white so transparent PNG signatures don't render black
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(bounds)
+	draw.Draw(rgba, bounds, image.White, image.Point{}, draw.Src)
+	draw.Draw(rgba, bounds, img, bounds.Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, rgba, &jpeg.Options{Quality: 90}); err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	height := width * float64(bounds.Dy()) / float64(bounds.Dx())
+	d.ensureSpace(height)
+	d.y -= height
+
+	d.images = append(d.images, pdfImage{data: buf.Bytes(), width: bounds.Dx(), height: bounds.Dy()})
+	idx := len(d.images) - 1
+	p := d.page()
+	p.images = append(p.images, idx)
+	fmt.Fprintf(&p.content, "q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n", width, height, pdfMargin, d.y, idx)
+	return nil
+}
+
+// BarChart draws the values as bars across the page width, with labels underneath. Only
+// every few labels are drawn when there are too many to fit.
+func (d *pdfDocument) BarChart(labels []string, values []float64, height float64) {
+	if len(values) == 0 {
+		return
+	}
+	const labelSize = 7.0
+	d.ensureSpace(height + 2*labelSize*pdfLineSpacing)
+
+	peak := 0.0
+	for _, v := range values {
+		peak = math.Max(peak, v)
+	}
+	slot := (pdfPageWidth - 2*pdfMargin) / float64(len(values))
+	bottom := d.y - height
+	p := d.page()
+	fmt.Fprintf(&p.content, "q 0.25 0.45 0.75 rg\n")
+	for i, v := range values {
+		if peak <= 0 || v <= 0 {
+			continue
+		}
+		fmt.Fprintf(&p.content, "%.2f %.2f %.2f %.2f re f\n", pdfMargin+float64(i)*slot+slot*0.15, bottom, slot*0.7, height*v/peak)
+	}
+	fmt.Fprintf(&p.content, "Q\n")
+
+	d.y = bottom - labelSize*pdfLineSpacing
+	step := (len(labels) + 11) / 12
+	for i := 0; i < len(labels); i += step {
+		fmt.Fprintf(&p.content, "BT /F1 %.1f Tf %.2f %.2f Td (%s) Tj ET\n", labelSize, pdfMargin+float64(i)*slot, d.y, escapePDFText(labels[i]))
+	}
+}
+
+func (d *pdfDocument) writeLines(lines []string, font string, size float64) {
+	leading := size * pdfLineSpacing
+	for _, line := range lines {
+		d.ensureSpace(leading)
+		d.y -= leading
+		if line == "" {
+			continue
+		}
+		fmt.Fprintf(&d.page().content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, pdfMargin, d.y, escapePDFText(line))
+	}
+}
+
+// Bytes serializes the document
+func (d *pdfDocument) Bytes() []byte {
+	// Object layout: 1 catalog, 2 page tree, 3-4 fonts, then images, then page/content pairs
+	const fontBase = 3
+	imageBase := fontBase + 2
+	pageBase := imageBase + len(d.images)
+
+	var objects []string
+	objects = append(objects, "<< /Type /Catalog /Pages 2 0 R >>")
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", pageBase+i*2)
+	}
+	objects = append(objects, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for _, img := range d.images {
+		objects = append(objects, fmt.Sprintf(
+			"<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>\nstream\n%s\nendstream",
+			img.width, img.height, len(img.data), img.data,
+		))
+	}
+
+	for i, p := range d.pages {
+		var xobjects strings.Builder
+		for _, idx := range p.images {
+			fmt.Fprintf(&xobjects, "/Im%d %d 0 R ", idx, imageBase+idx)
+		}
+		objects = append(objects, fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> /XObject << %s>> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, fontBase, fontBase+1, xobjects.String(), pageBase+i*2+1,
+		))
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", p.content.Len(), p.content.String()))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+// wrapPDFLine breaks a line on word boundaries using an average Helvetica glyph width
+func wrapPDFLine(line string, size float64) []string {
+	maxChars := int((pdfPageWidth - 2*pdfMargin) / (size * 0.5))
+	words := strings.Fields(line)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	current := words[0]
+	for _, w := range words[1:] {
+		if len(current)+1+len(w) > maxChars {
+			lines = append(lines, current)
+			current = w
+			continue
+		}
+		current += " " + w
+	}
+	return append(lines, current)
+}
+
+// escapePDFText escapes string delimiters and drops characters outside WinAnsi's ASCII range
+func escapePDFText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteString("    ")
+		case r < 32 || r > 126:
+			b.WriteRune('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
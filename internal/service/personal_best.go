@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/jobs"
+)
+
+const pbRebuildJobType = "pb-rebuild"
+
+// PersonalBestListener is told about each personal best a session raised
+type PersonalBestListener interface {
+	HandleNewPB(ctx context.Context, pb *domain.PersonalBest) error
+}
+
+// PersonalBestDetector records personal bests from a completed session's set logs, counting
+// only the sets the tenant's PB rules allow
+type PersonalBestDetector struct {
+	setLogRepo   domain.SetLogRepository
+	pbRepo       domain.PersonalBestRepository
+	scheduleRepo domain.ScheduleRepository
+	exerciseRepo domain.ExerciseRepository
+	tenantRepo   domain.TenantRepository // Optional: without it the default rules apply
+	listeners    []PersonalBestListener  // Optional: see OnNewPB
+	runs         *jobs.Runner            // Optional: see TrackRuns
+}
+
+func NewPersonalBestDetector(
+	setLogRepo domain.SetLogRepository,
+	pbRepo domain.PersonalBestRepository,
+	scheduleRepo domain.ScheduleRepository,
+	exerciseRepo domain.ExerciseRepository,
+	tenantRepo domain.TenantRepository,
+) *PersonalBestDetector {
+	return &PersonalBestDetector{
+		setLogRepo:   setLogRepo,
+		pbRepo:       pbRepo,
+		scheduleRepo: scheduleRepo,
+		exerciseRepo: exerciseRepo,
+		tenantRepo:   tenantRepo,
+	}
+}
+
+// OnNewPB tells listener about every new personal best, after the listeners added before
+// it. Replaying a session raises none, so a listener hears of each PB once.
+func (d *PersonalBestDetector) OnNewPB(listener PersonalBestListener) {
+	d.listeners = append(d.listeners, listener)
+}
+
+// TrackRuns records rebuilds in the job history, where a failed one can be retried
+func (d *PersonalBestDetector) TrackRuns(runs *jobs.Runner) {
+	d.runs = runs
+	runs.Handle(pbRebuildJobType, func(ctx context.Context, params map[string]interface{}) error {
+		tenantID, _ := params["tenant_id"].(string)
+		_, err := d.Rebuild(ctx, tenantID)
+		return err
+	})
+}
+
+func (d *PersonalBestDetector) HandleWorkoutEvent(ctx context.Context, event *domain.WorkoutEvent) error {
+	if event.Type != domain.WorkoutEventSessionCompleted {
+		return nil
+	}
+	return d.Detect(ctx, event.ScheduleID)
+}
+
+type pbKey struct {
+	memberID   string
+	exerciseID string
+}
+
+// Detect upserts the heaviest counted set per (member, exercise) of a schedule.
+// Upsert only ever raises a PB, so running it again for the same schedule is harmless.
+func (d *PersonalBestDetector) Detect(ctx context.Context, scheduleID string) error {
+	schedule, err := d.scheduleRepo.GetByID(ctx, scheduleID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch schedule for PB update: %w", err)
+	}
+	rules, err := d.Rules(ctx, schedule.TenantID)
+	if err != nil {
+		return err
+	}
+
+	best := make(map[pbKey]*domain.PersonalBest)
+	if err := d.collectBest(ctx, rules, schedule, best); err != nil {
+		return err
+	}
+
+	for key, pb := range best {
+		isNewPB, err := d.pbRepo.Upsert(ctx, pb)
+		if err != nil {
+			fmt.Printf("Warning: Failed to upsert PB for member %s, exercise %s: %v\n", key.memberID, key.exerciseID, err)
+		} else if isNewPB {
+			fmt.Printf("🎉 New PB! Member %s, Exercise %s: %.1f kg\n", key.memberID, key.exerciseID, pb.Weight)
+			for _, listener := range d.listeners {
+				if err := listener.HandleNewPB(ctx, pb); err != nil {
+					fmt.Printf("Warning: Failed to announce PB for member %s, exercise %s: %v\n", key.memberID, key.exerciseID, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// collectBest raises best to the heaviest set of the schedule the rules count. Of equally
+// heavy sets the one already in best is kept, so the earliest session holds the PB.
+func (d *PersonalBestDetector) collectBest(ctx context.Context, rules domain.PBRules, schedule *domain.Schedule, best map[pbKey]*domain.PersonalBest) error {
+	setLogs, err := d.setLogRepo.GetByScheduleID(ctx, schedule.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch set logs for PB update: %w", err)
+	}
+	var sessionRPE *int
+	if schedule.Effort != nil {
+		sessionRPE = schedule.Effort.RPE
+	}
+
+	var counted []*domain.SetLogDocument
+	exerciseIDs := make(map[string]bool)
+	for _, log := range setLogs {
+		if rules.Counts(log, sessionRPE) {
+			counted = append(counted, log)
+			exerciseIDs[log.ExerciseID] = true
+		}
+	}
+	excluded, err := d.excludedExercises(ctx, rules, exerciseIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, log := range counted {
+		if excluded[log.ExerciseID] {
+			continue
+		}
+		key := pbKey{memberID: log.MemberID, exerciseID: log.ExerciseID}
+		if existing, ok := best[key]; ok && log.Weight <= existing.Weight {
+			continue
+		}
+		best[key] = &domain.PersonalBest{
+			MemberID:   log.MemberID,
+			ExerciseID: log.ExerciseID,
+			Weight:     log.Weight,
+			Reps:       log.Reps,
+			ScheduleID: schedule.ID,
+			AchievedAt: schedule.StartTime,
+		}
+	}
+	return nil
+}
+
+// excludedExercises returns which of the exercises the rules leave out by name
+func (d *PersonalBestDetector) excludedExercises(ctx context.Context, rules domain.PBRules, ids map[string]bool) (map[string]bool, error) {
+	if !rules.ExcludeAssisted || len(ids) == 0 || d.exerciseRepo == nil {
+		return nil, nil
+	}
+	list := make([]string, 0, len(ids))
+	for id := range ids {
+		list = append(list, id)
+	}
+	exercises, err := d.exerciseRepo.GetByIDs(ctx, list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch exercises for PB rules: %w", err)
+	}
+	excluded := make(map[string]bool)
+	for _, ex := range exercises {
+		if rules.ExcludesExercise(ex) {
+			excluded[ex.ID] = true
+		}
+	}
+	return excluded, nil
+}
+
+// Rules returns the tenant's PB rules, or the defaults
+func (d *PersonalBestDetector) Rules(ctx context.Context, tenantID string) (domain.PBRules, error) {
+	if d.tenantRepo == nil || tenantID == "" {
+		return domain.DefaultPBRules, nil
+	}
+	tenant, err := d.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return domain.PBRules{}, fmt.Errorf("failed to fetch PB rules: %w", err)
+	}
+	return tenant.PersonalBestRules(), nil
+}
+
+// SetRules changes the tenant's PB rules and rebuilds its members' PBs under them in the
+// background. nil goes back to the defaults.
+func (d *PersonalBestDetector) SetRules(ctx context.Context, tenantID string, rules *domain.PBRules) (domain.PBRules, error) {
+	if rules != nil {
+		if err := rules.Validate(); err != nil {
+			return domain.PBRules{}, err
+		}
+	}
+	tenant, err := d.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return domain.PBRules{}, err
+	}
+	tenant.PBRules = rules
+	if err := d.tenantRepo.Update(ctx, tenant); err != nil {
+		return domain.PBRules{}, err
+	}
+	d.RebuildInBackground(tenantID)
+	return tenant.PersonalBestRules(), nil
+}
+
+// RebuildInBackground starts a Rebuild that the request doesn't wait for (or cancel)
+func (d *PersonalBestDetector) RebuildInBackground(tenantID string) {
+	go func() {
+		bg := context.Background()
+		rebuild := func(ctx context.Context) error {
+			_, err := d.Rebuild(ctx, tenantID)
+			return err
+		}
+		if d.runs == nil {
+			if err := rebuild(bg); err != nil {
+				fmt.Printf("Warning: failed to rebuild PBs for tenant %s: %v\n", tenantID, err)
+			}
+			return
+		}
+		_ = d.runs.Record(bg, pbRebuildJobType, tenantID, map[string]interface{}{"tenant_id": tenantID}, rebuild)
+	}()
+}
+
+// Rebuild recomputes the PBs of every member with a completed session in the tenant from
+// all of those sessions, under the tenant's current rules, and returns how many members'
+// PBs were replaced. Nobody is told of new PBs: none were achieved.
+func (d *PersonalBestDetector) Rebuild(ctx context.Context, tenantID string) (int, error) {
+	rules, err := d.Rules(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+	schedules, err := d.scheduleRepo.List(ctx, tenantID, map[string]interface{}{"status": domain.ScheduleStatusCompleted})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list completed schedules: %w", err)
+	}
+	sort.SliceStable(schedules, func(i, j int) bool { return schedules[i].StartTime.Before(schedules[j].StartTime) })
+
+	best := make(map[pbKey]*domain.PersonalBest)
+	members := make(map[string][]*domain.PersonalBest)
+	for _, schedule := range schedules {
+		if schedule.DeletedAt != nil {
+			continue
+		}
+		if schedule.MemberID != "" {
+			members[schedule.MemberID] = nil
+		}
+		if err := d.collectBest(ctx, rules, schedule, best); err != nil {
+			return 0, err
+		}
+	}
+	for key, pb := range best {
+		members[key.memberID] = append(members[key.memberID], pb)
+	}
+
+	memberIDs := make([]string, 0, len(members))
+	for id := range members {
+		memberIDs = append(memberIDs, id)
+	}
+	sort.Strings(memberIDs)
+	for _, memberID := range memberIDs {
+		pbs := members[memberID]
+		sort.Slice(pbs, func(i, j int) bool { return pbs[i].ExerciseID < pbs[j].ExerciseID })
+		if err := d.pbRepo.ReplaceForMember(ctx, memberID, pbs); err != nil {
+			return 0, fmt.Errorf("failed to replace PBs of member %s: %w", memberID, err)
+		}
+	}
+	return len(memberIDs), nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type pbDetectorMocks struct {
+	setLogs   *mocks.SetLogRepository
+	pbs       *mocks.PersonalBestRepository
+	schedules *mocks.ScheduleRepository
+	exercises *mocks.ExerciseRepository
+	tenants   *mocks.TenantRepository
+}
+
+func newTestPBDetector(t *testing.T) (*PersonalBestDetector, pbDetectorMocks) {
+	m := pbDetectorMocks{
+		setLogs:   mocks.NewSetLogRepository(t),
+		pbs:       mocks.NewPersonalBestRepository(t),
+		schedules: mocks.NewScheduleRepository(t),
+		exercises: mocks.NewExerciseRepository(t),
+		tenants:   mocks.NewTenantRepository(t),
+	}
+	return NewPersonalBestDetector(m.setLogs, m.pbs, m.schedules, m.exercises, m.tenants), m
+}
+
+type recordingPBListener struct {
+	pbs []*domain.PersonalBest
+}
+
+func (l *recordingPBListener) HandleNewPB(_ context.Context, pb *domain.PersonalBest) error {
+	l.pbs = append(l.pbs, pb)
+	return nil
+}
+
+func TestPersonalBestDetector(t *testing.T) {
+	ctx := context.Background()
+	detector, m := newTestPBDetector(t)
+	listener := &recordingPBListener{}
+	detector.OnNewPB(listener)
+
+	m.schedules.On("GetByID", ctx, "sched-1").Return(&domain.Schedule{ID: "sched-1", TenantID: "gym"}, nil)
+	m.tenants.On("GetByID", ctx, "gym").Return(&domain.Tenant{ID: "gym"}, nil)
+	m.setLogs.On("GetByScheduleID", ctx, "sched-1").Return([]*domain.SetLogDocument{
+		{MemberID: "member-1", ExerciseID: "squat", Weight: 80, Reps: 8, Completed: true},
+		{MemberID: "member-1", ExerciseID: "squat", Weight: 90, Reps: 5, Completed: true},
+		{MemberID: "member-1", ExerciseID: "squat", Weight: 100, Reps: 1, Completed: false},
+	}, nil)
+	m.pbs.On("Upsert", ctx, mock.MatchedBy(func(pb *domain.PersonalBest) bool {
+		return pb.ExerciseID == "squat" && pb.Weight == 90 && pb.Reps == 5 && pb.ScheduleID == "sched-1"
+	})).Return(true, nil).Once()
+
+	// Other event types are ignored
+	require.NoError(t, detector.HandleWorkoutEvent(ctx, &domain.WorkoutEvent{Type: domain.WorkoutEventSetLogged, ScheduleID: "sched-1"}))
+	require.NoError(t, detector.HandleWorkoutEvent(ctx, &domain.WorkoutEvent{Type: domain.WorkoutEventSessionCompleted, ScheduleID: "sched-1"}))
+	require.Len(t, listener.pbs, 1)
+	assert.Equal(t, 90.0, listener.pbs[0].Weight)
+}
+
+func TestPersonalBestDetector_TenantRules(t *testing.T) {
+	ctx := context.Background()
+	detector, m := newTestPBDetector(t)
+	rpe := 10
+	m.schedules.On("GetByID", ctx, "sched-1").Return(&domain.Schedule{ID: "sched-1", TenantID: "gym"}, nil)
+	m.schedules.On("GetByID", ctx, "sched-2").Return(&domain.Schedule{ID: "sched-2", TenantID: "gym", Effort: &domain.SessionEffort{RPE: &rpe}}, nil)
+	m.tenants.On("GetByID", ctx, "gym").Return(&domain.Tenant{ID: "gym", PBRules: &domain.PBRules{
+		CompletedOnly: true, MinReps: 3, MaxRPE: 9, ExcludeAssisted: true,
+	}}, nil)
+	m.setLogs.On("GetByScheduleID", ctx, "sched-1").Return([]*domain.SetLogDocument{
+		{MemberID: "member-1", ExerciseID: "squat", Weight: 120, Reps: 1, Completed: true}, // Too few reps
+		{MemberID: "member-1", ExerciseID: "squat", Weight: 100, Reps: 3, Completed: true},
+		{MemberID: "member-1", ExerciseID: "pullup", Weight: 20, Reps: 8, Completed: true},
+	}, nil)
+	m.setLogs.On("GetByScheduleID", ctx, "sched-2").Return([]*domain.SetLogDocument{
+		{MemberID: "member-1", ExerciseID: "squat", Weight: 110, Reps: 5, Completed: true},
+	}, nil)
+	m.exercises.On("GetByIDs", ctx, mock.Anything).Return([]*domain.Exercise{
+		{ID: "squat", Name: "Back Squat"}, {ID: "pullup", Name: "Assisted Pull-up"},
+	}, nil).Once()
+	m.pbs.On("Upsert", ctx, mock.MatchedBy(func(pb *domain.PersonalBest) bool {
+		return pb.ExerciseID == "squat" && pb.Weight == 100
+	})).Return(true, nil).Once()
+
+	require.NoError(t, detector.Detect(ctx, "sched-1"))
+	require.NoError(t, detector.Detect(ctx, "sched-2"), "a session rated above max_rpe counts no sets")
+}
+
+func TestPersonalBestDetector_Rebuild(t *testing.T) {
+	ctx := context.Background()
+	detector, m := newTestPBDetector(t)
+	monday := time.Date(2025, 6, 9, 7, 0, 0, 0, time.UTC)
+	m.tenants.On("GetByID", ctx, "gym").Return(&domain.Tenant{ID: "gym", PBRules: &domain.PBRules{MaxReps: 5}}, nil)
+	m.schedules.On("List", ctx, "gym", map[string]interface{}{"status": domain.ScheduleStatusCompleted}).Return([]*domain.Schedule{
+		{ID: "later", MemberID: "member-1", StartTime: monday.AddDate(0, 0, 7)},
+		{ID: "earlier", MemberID: "member-1", StartTime: monday},
+		{ID: "deleted", MemberID: "member-1", StartTime: monday, DeletedAt: &monday},
+		{ID: "only-high-reps", MemberID: "member-2", StartTime: monday},
+	}, nil)
+	m.setLogs.On("GetByScheduleID", ctx, "earlier").Return([]*domain.SetLogDocument{
+		{MemberID: "member-1", ExerciseID: "squat", Weight: 100, Reps: 5},
+	}, nil)
+	m.setLogs.On("GetByScheduleID", ctx, "later").Return([]*domain.SetLogDocument{
+		{MemberID: "member-1", ExerciseID: "squat", Weight: 100, Reps: 4, Completed: true},
+		{MemberID: "member-1", ExerciseID: "bench", Weight: 80, Reps: 5, Completed: true},
+	}, nil)
+	m.setLogs.On("GetByScheduleID", ctx, "only-high-reps").Return([]*domain.SetLogDocument{
+		{MemberID: "member-2", ExerciseID: "squat", Weight: 60, Reps: 12, Completed: true},
+	}, nil)
+	m.pbs.On("ReplaceForMember", ctx, "member-1", []*domain.PersonalBest{
+		{MemberID: "member-1", ExerciseID: "bench", Weight: 80, Reps: 5, ScheduleID: "later", AchievedAt: monday.AddDate(0, 0, 7)},
+		{MemberID: "member-1", ExerciseID: "squat", Weight: 100, Reps: 5, ScheduleID: "earlier", AchievedAt: monday},
+	}).Return(nil)
+	m.pbs.On("ReplaceForMember", ctx, "member-2", []*domain.PersonalBest(nil)).Return(nil)
+
+	members, err := detector.Rebuild(ctx, "gym")
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, members)
+}
+
+func TestPBRules_Validate(t *testing.T) {
+	assert.NoError(t, domain.DefaultPBRules.Validate())
+	for _, rules := range []domain.PBRules{{MinReps: -1}, {MinReps: 5, MaxReps: 3}, {MaxRPE: 11}} {
+		assert.ErrorIs(t, rules.Validate(), domain.ErrInvalidPBRules)
+	}
+}
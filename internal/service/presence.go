@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// PresenceService tracks which coaches have the app open and puts them against their
+// schedule, so tenant admins can see who is on the floor
+type PresenceService struct {
+	presenceRepo domain.PresenceRepository
+	userRepo     domain.UserRepository
+	schedRepo    domain.ScheduleRepository
+	clock        domain.Clock
+}
+
+func NewPresenceService(presenceRepo domain.PresenceRepository, userRepo domain.UserRepository, schedRepo domain.ScheduleRepository, clk domain.Clock) *PresenceService {
+	return &PresenceService{presenceRepo: presenceRepo, userRepo: userRepo, schedRepo: schedRepo, clock: clock.OrReal(clk)}
+}
+
+// Heartbeat marks the user online in the tenant for domain.PresenceTTL
+func (s *PresenceService) Heartbeat(ctx context.Context, tenantID, userID string) error {
+	return s.presenceRepo.Heartbeat(ctx, tenantID, userID, s.clock.Now())
+}
+
+// Board lists the tenant's coaches with their presence. A coach is in session when online
+// during one of their sessions that is still booked; the session is shown when they are
+// offline too, which flags a coach who should be on the floor but isn't.
+func (s *PresenceService) Board(ctx context.Context, tenantID string) (*domain.PresenceBoard, error) {
+	now := s.clock.Now()
+	coaches, err := s.userRepo.GetByTenantAndRole(ctx, tenantID, domain.RoleCoach)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get coaches: %w", err)
+	}
+	seen, err := s.presenceRepo.SeenSince(ctx, tenantID, now.Add(-domain.PresenceTTL))
+	if err != nil {
+		return nil, err
+	}
+	running, err := s.schedRepo.List(ctx, tenantID, map[string]interface{}{
+		"start_time": map[string]interface{}{"$lte": now},
+		"end_time":   map[string]interface{}{"$gt": now},
+		"status":     map[string]interface{}{"$in": []string{domain.ScheduleStatusScheduled, domain.ScheduleStatusPendingConfirmation}},
+		"deleted_at": nil,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get running sessions: %w", err)
+	}
+	sessions := make(map[string]*domain.Schedule, len(running))
+	for _, schedule := range running {
+		sessions[schedule.CoachID] = schedule
+	}
+
+	board := &domain.PresenceBoard{Coaches: make([]*domain.CoachPresence, 0, len(coaches))}
+	for _, coach := range coaches {
+		presence := &domain.CoachPresence{CoachID: coach.ID, Name: coach.Name, Status: domain.PresenceOffline, Session: sessions[coach.ID]}
+		if at, ok := seen[coach.ID]; ok {
+			presence.LastSeenAt = &at
+			presence.Status = domain.PresenceOnline
+			board.Online++
+			if presence.Session != nil {
+				presence.Status = domain.PresenceInSession
+				board.InSession++
+			}
+		}
+		board.Coaches = append(board.Coaches, presence)
+	}
+
+	rank := map[string]int{domain.PresenceInSession: 0, domain.PresenceOnline: 1, domain.PresenceOffline: 2}
+	sort.SliceStable(board.Coaches, func(i, j int) bool {
+		a, b := board.Coaches[i], board.Coaches[j]
+		if rank[a.Status] != rank[b.Status] {
+			return rank[a.Status] < rank[b.Status]
+		}
+		return a.Name < b.Name
+	})
+	return board, nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresenceService_Heartbeat(t *testing.T) {
+	presence := mocks.NewPresenceRepository(t)
+	svc := NewPresenceService(presence, nil, nil, clock.NewFake(testNow))
+	presence.On("Heartbeat", context.Background(), "gym", "coach-1", testNow).Return(nil)
+
+	assert.NoError(t, svc.Heartbeat(context.Background(), "gym", "coach-1"))
+}
+
+func TestPresenceService_Board(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*PresenceService, *mocks.PresenceRepository, *mocks.UserRepository, *mocks.ScheduleRepository) {
+		presence, users, schedules := mocks.NewPresenceRepository(t), mocks.NewUserRepository(t), mocks.NewScheduleRepository(t)
+		return NewPresenceService(presence, users, schedules, clock.NewFake(testNow)), presence, users, schedules
+	}
+
+	t.Run("puts online coaches against their running sessions", func(t *testing.T) {
+		svc, presence, users, schedules := setup(t)
+		users.On("GetByTenantAndRole", ctx, "gym", domain.RoleCoach).Return([]*domain.User{
+			{ID: "zoe", Name: "Zoe"}, {ID: "adam", Name: "Adam"}, {ID: "bea", Name: "Bea"}, {ID: "carl", Name: "Carl"},
+		}, nil)
+		seenAt := testNow.Add(-30 * time.Second)
+		presence.On("SeenSince", ctx, "gym", testNow.Add(-domain.PresenceTTL)).Return(map[string]time.Time{"zoe": seenAt, "bea": seenAt}, nil)
+		zoeSession := &domain.Schedule{ID: "s-1", CoachID: "zoe"}
+		carlSession := &domain.Schedule{ID: "s-2", CoachID: "carl"}
+		schedules.On("List", ctx, "gym", mock.MatchedBy(func(filter map[string]interface{}) bool {
+			return filter["start_time"] != nil && filter["end_time"] != nil
+		})).Return([]*domain.Schedule{zoeSession, carlSession}, nil)
+
+		board, err := svc.Board(ctx, "gym")
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, board.InSession)
+		assert.Equal(t, 2, board.Online)
+		require.Len(t, board.Coaches, 4)
+		assert.Equal(t, &domain.CoachPresence{CoachID: "zoe", Name: "Zoe", Status: domain.PresenceInSession, LastSeenAt: &seenAt, Session: zoeSession}, board.Coaches[0])
+		assert.Equal(t, &domain.CoachPresence{CoachID: "bea", Name: "Bea", Status: domain.PresenceOnline, LastSeenAt: &seenAt}, board.Coaches[1])
+		// Offline, by name; Carl should be in a session
+		assert.Equal(t, &domain.CoachPresence{CoachID: "adam", Name: "Adam", Status: domain.PresenceOffline}, board.Coaches[2])
+		assert.Equal(t, &domain.CoachPresence{CoachID: "carl", Name: "Carl", Status: domain.PresenceOffline, Session: carlSession}, board.Coaches[3])
+	})
+
+	t.Run("fails when presence is unavailable", func(t *testing.T) {
+		svc, presence, users, _ := setup(t)
+		users.On("GetByTenantAndRole", ctx, "gym", domain.RoleCoach).Return([]*domain.User{{ID: "zoe"}}, nil)
+		presence.On("SeenSince", ctx, "gym", mock.Anything).Return(nil, errors.New("redis down"))
+
+		_, err := svc.Board(ctx, "gym")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// maxReportPhotos bounds the session photos in a report, most recent kept
+const maxReportPhotos = 6
+
+// progressReportLinkTTL is how long the link a coach shares with the member keeps working
+const progressReportLinkTTL = 7 * 24 * time.Hour
+
+// ProgressReportService builds client progress report PDFs: scan trends, training volume,
+// PBs and attendance over a period, with an AI-written summary when the tenant has quota
+type ProgressReportService struct {
+	tenantRepo   domain.TenantRepository
+	userRepo     domain.UserRepository
+	inbodyRepo   domain.InBodyRepository
+	volumeRepo   domain.DailyVolumeRepository
+	pbRepo       domain.PersonalBestRepository
+	exerciseRepo domain.ExerciseRepository
+	schedRepo    domain.ScheduleRepository
+	usageRepo    domain.AIUsageRepository
+	summarizer   domain.ProgressSummarizer // Optional: reports have no summary when nil
+	fileRepo     domain.FileRepository
+	photos       *SessionPhotoService // Optional: see IncludePhotos
+	clock        domain.Clock
+}
+
+func NewProgressReportService(
+	tenantRepo domain.TenantRepository,
+	userRepo domain.UserRepository,
+	inbodyRepo domain.InBodyRepository,
+	volumeRepo domain.DailyVolumeRepository,
+	pbRepo domain.PersonalBestRepository,
+	exerciseRepo domain.ExerciseRepository,
+	schedRepo domain.ScheduleRepository,
+	usageRepo domain.AIUsageRepository,
+	summarizer domain.ProgressSummarizer,
+	fileRepo domain.FileRepository,
+	clk domain.Clock,
+) *ProgressReportService {
+	return &ProgressReportService{
+		tenantRepo:   tenantRepo,
+		userRepo:     userRepo,
+		inbodyRepo:   inbodyRepo,
+		volumeRepo:   volumeRepo,
+		pbRepo:       pbRepo,
+		exerciseRepo: exerciseRepo,
+		schedRepo:    schedRepo,
+		usageRepo:    usageRepo,
+		summarizer:   summarizer,
+		fileRepo:     fileRepo,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// IncludePhotos adds the member's session photos of the period to reports, if they agreed
+// to it
+func (s *ProgressReportService) IncludePhotos(photos *SessionPhotoService) {
+	s.photos = photos
+}
+
+// Generate builds the member's report for the days from through to (inclusive), stores
+// the PDF and returns the report with a signed link to it
+func (s *ProgressReportService) Generate(ctx context.Context, tenantID, memberID string, from, to time.Time) (*domain.ProgressReport, error) {
+	now := s.clock.Now()
+	from, to = from.UTC().Truncate(24*time.Hour), to.UTC().Truncate(24*time.Hour)
+	end := to.AddDate(0, 0, 1)
+	if to.Before(from) || end.Sub(from) > domain.MaxReportDays*24*time.Hour || to.After(now) {
+		return nil, domain.ErrInvalidReportPeriod
+	}
+
+	member, err := s.userRepo.GetByID(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := member.ScopedTo(tenantID); err != nil {
+		return nil, err
+	}
+	tenant, err := s.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tenant: %w", err)
+	}
+
+	report := &domain.ProgressReport{MemberID: memberID, MemberName: member.Name, From: from, To: to, GeneratedAt: now}
+	if err := s.collect(ctx, report, end); err != nil {
+		return nil, err
+	}
+	if s.photos != nil {
+		photos, err := s.photos.ReportPhotos(ctx, tenantID, memberID, from, end)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load session photos: %w", err)
+		}
+		if len(photos) > maxReportPhotos {
+			photos = photos[len(photos)-maxReportPhotos:]
+		}
+		report.Photos = photos
+	}
+	// Minors' data isn't sent to the AI
+	if !member.IsMinorIn(tenant, now) {
+		report.Summary = s.summarize(ctx, tenant, report)
+	}
+
+	filename := fmt.Sprintf("reports/%s/%s/%s-%d.pdf", tenantID, memberID, to.Format("2006-01-02"), now.UnixNano())
+	url, err := s.fileRepo.Upload(ctx, s.render(ctx, tenant, report), filename, "application/pdf")
+	if err != nil {
+		return nil, err
+	}
+	report.DocumentURL = url
+	if report.ShareURL, err = s.fileRepo.SignedURL(ctx, url, progressReportLinkTTL); err != nil {
+		return nil, fmt.Errorf("failed to sign report link: %w", err)
+	}
+	report.ShareExpires = now.Add(progressReportLinkTTL)
+	return report, nil
+}
+
+// collect fills the report's data for the period ending before end
+func (s *ProgressReportService) collect(ctx context.Context, report *domain.ProgressReport, end time.Time) error {
+	inPeriod := func(t time.Time) bool { return !t.Before(report.From) && t.Before(end) }
+
+	scans, err := s.inbodyRepo.FindAllByUserID(ctx, report.MemberID)
+	if err != nil {
+		return fmt.Errorf("failed to load scans: %w", err)
+	}
+	report.Scans = []*domain.InBodyRecord{}
+	for i := len(scans) - 1; i >= 0; i-- { // Newest first from the repository
+		if inPeriod(scans[i].TestDateTime) {
+			report.Scans = append(report.Scans, scans[i])
+		}
+	}
+
+	volumes, err := s.volumeRepo.GetByMemberIDAndDateRange(ctx, report.MemberID, report.From, end)
+	if err != nil {
+		return fmt.Errorf("failed to load volume: %w", err)
+	}
+	report.Volume = []domain.WeeklyVolume{}
+	for week := progressWeekStart(report.From); week.Before(end); week = week.AddDate(0, 0, 7) {
+		report.Volume = append(report.Volume, domain.WeeklyVolume{WeekStart: week})
+	}
+	for _, v := range volumes {
+		if !inPeriod(v.Date) {
+			continue
+		}
+		week := &report.Volume[int(progressWeekStart(v.Date).Sub(report.Volume[0].WeekStart).Hours()/(24*7))]
+		week.Volume += v.TotalVolume
+		week.Sessions++
+	}
+
+	schedules, err := s.schedRepo.GetByMember(ctx, report.MemberID, report.From, end)
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+	for _, sched := range schedules {
+		if sched.DeletedAt != nil || !inPeriod(sched.StartTime) {
+			continue
+		}
+		switch sched.Status {
+		case domain.ScheduleStatusCompleted:
+			report.Attendance.Completed++
+		case domain.ScheduleStatusNoShow:
+			report.Attendance.NoShow++
+		case domain.ScheduleStatusCancelled:
+			report.Attendance.Cancelled++
+		}
+	}
+
+	pbs, err := s.pbRepo.GetByMember(ctx, report.MemberID)
+	if err != nil {
+		return fmt.Errorf("failed to load personal bests: %w", err)
+	}
+	report.PersonalBests = []domain.ReportPersonalBest{}
+	var exerciseIDs []string
+	for _, pb := range pbs {
+		if inPeriod(pb.AchievedAt) {
+			exerciseIDs = append(exerciseIDs, pb.ExerciseID)
+		}
+	}
+	if len(exerciseIDs) == 0 {
+		return nil
+	}
+	names := make(map[string]string, len(exerciseIDs))
+	exercises, err := s.exerciseRepo.GetByIDs(ctx, exerciseIDs)
+	if err != nil {
+		return fmt.Errorf("failed to load exercises: %w", err)
+	}
+	for _, ex := range exercises {
+		names[ex.ID] = ex.Name
+	}
+	for _, pb := range pbs {
+		if !inPeriod(pb.AchievedAt) {
+			continue
+		}
+		name := names[pb.ExerciseID]
+		if name == "" {
+			name = "Exercise"
+		}
+		report.PersonalBests = append(report.PersonalBests, domain.ReportPersonalBest{
+			Exercise: name, Weight: pb.Weight, Reps: pb.Reps, AchievedAt: pb.AchievedAt,
+		})
+	}
+	sort.Slice(report.PersonalBests, func(i, j int) bool {
+		return report.PersonalBests[i].AchievedAt.Before(report.PersonalBests[j].AchievedAt)
+	})
+	return nil
+}
+
+// summarize asks the AI for the report's narrative. The report is still worth having
+// without one, so failures and an exhausted quota only leave the summary out.
+func (s *ProgressReportService) summarize(ctx context.Context, tenant *domain.Tenant, report *domain.ProgressReport) string {
+	if s.summarizer == nil {
+		return ""
+	}
+	if err := s.usageRepo.Consume(ctx, tenant.ID, s.clock.Now(), tenant.AISettings.MonthlyQuota); err != nil {
+		if errors.Is(err, domain.ErrAIQuotaExceeded) {
+			log.Printf("Tenant %s is out of AI quota, no summary for the report of member %s", tenant.ID, report.MemberID)
+		} else {
+			log.Printf("Warning: report summary skipped for member %s: %v", report.MemberID, err)
+		}
+		return ""
+	}
+	summary, err := s.summarizer.SummarizeProgress(ctx, report, tenant)
+	if err != nil {
+		log.Printf("Warning: report summary failed for member %s: %v", report.MemberID, err)
+		return ""
+	}
+	return strings.TrimSpace(summary)
+}
+
+// render lays out the report under the tenant's name and logo
+func (s *ProgressReportService) render(ctx context.Context, tenant *domain.Tenant, report *domain.ProgressReport) []byte {
+	doc := newPDFDocument()
+	if tenant.LogoURL != "" {
+		logo, err := s.fileRepo.Download(ctx, tenant.LogoURL)
+		if err == nil {
+			err = doc.Image(logo, 120)
+		}
+		if err != nil {
+			log.Printf("Warning: progress report of member %s rendered without logo: %v", report.MemberID, err)
+		}
+		doc.Space(12)
+	}
+	doc.Heading(tenant.Name, 18)
+	doc.Heading("Progress report: "+report.MemberName, 14)
+	doc.Paragraph(fmt.Sprintf("%s - %s", report.From.Format("2 Jan 2006"), report.To.Format("2 Jan 2006")), 10)
+
+	if report.Summary != "" {
+		doc.Space(12)
+		doc.Heading("Coach's summary", 13)
+		doc.Paragraph(report.Summary, 10)
+	}
+
+	doc.Space(12)
+	doc.Heading("Body composition", 13)
+	if len(report.Scans) == 0 {
+		doc.Paragraph("No scans in this period.", 10)
+	}
+	for _, scan := range report.Scans {
+		doc.Paragraph(fmt.Sprintf("%s   Weight %.1f kg   Muscle %.1f kg   Body fat %.1f%%",
+			scan.TestDateTime.Format("2 Jan 2006"), scan.Weight, scan.SMM, scan.PBF), 10)
+	}
+	if n := len(report.Scans); n >= 2 {
+		first, last := report.Scans[0], report.Scans[n-1]
+		doc.Paragraph(fmt.Sprintf("Change: weight %+.1f kg, muscle %+.1f kg, body fat %+.1f points",
+			last.Weight-first.Weight, last.SMM-first.SMM, last.PBF-first.PBF), 10)
+	}
+
+	doc.Space(12)
+	doc.Heading("Training volume", 13)
+	labels := make([]string, len(report.Volume))
+	values := make([]float64, len(report.Volume))
+	total, sessions := 0.0, 0
+	for i, week := range report.Volume {
+		labels[i] = week.WeekStart.Format("2 Jan")
+		values[i] = week.Volume
+		total += week.Volume
+		sessions += week.Sessions
+	}
+	doc.BarChart(labels, values, 120)
+	doc.Paragraph(fmt.Sprintf("%.0f kg lifted over %d sessions, by week.", total, sessions), 10)
+
+	doc.Space(12)
+	doc.Heading("Personal bests", 13)
+	if len(report.PersonalBests) == 0 {
+		doc.Paragraph("No new personal bests in this period.", 10)
+	}
+	for _, pb := range report.PersonalBests {
+		doc.Paragraph(fmt.Sprintf("%s: %.1f kg x %d on %s", pb.Exercise, pb.Weight, pb.Reps, pb.AchievedAt.Format("2 Jan 2006")), 10)
+	}
+
+	doc.Space(12)
+	doc.Heading("Attendance", 13)
+	a := report.Attendance
+	doc.Paragraph(fmt.Sprintf("%d sessions completed, %d missed, %d cancelled (%d%% attendance).", a.Completed, a.NoShow, a.Cancelled, a.Rate()), 10)
+
+	if len(report.Photos) > 0 {
+		doc.Space(12)
+		doc.Heading("Session photos", 13)
+		for _, photo := range report.Photos {
+			// A photo that can't be shown is left out rather than failing the report
+			data, err := s.fileRepo.Download(ctx, photo.URL)
+			if err == nil {
+				err = doc.Image(data, 240)
+			}
+			if err != nil {
+				log.Printf("Warning: photo %s left out of the report of member %s: %v", photo.ID, report.MemberID, err)
+				continue
+			}
+			label := photo.TakenAt.Format("2 Jan 2006")
+			if photo.Caption != "" {
+				label += ": " + photo.Caption
+			}
+			doc.Paragraph(label, 9)
+			doc.Space(6)
+		}
+	}
+
+	doc.Space(24)
+	doc.Paragraph("Generated on "+report.GeneratedAt.UTC().Format("2 Jan 2006"), 8)
+	return doc.Bytes()
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressReportService_Generate(t *testing.T) {
+	ctx := context.Background()
+	from, to := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 14, 0, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 9, 0, 0, 0, time.UTC) }
+	end := to.AddDate(0, 0, 1)
+
+	type reportMocks struct {
+		usage      *mocks.AIUsageRepository
+		summarizer *mocks.ProgressSummarizer
+		files      *mocks.FileRepository
+	}
+	newService := func(t *testing.T) (*ProgressReportService, reportMocks) {
+		tenants, users := mocks.NewTenantRepository(t), mocks.NewUserRepository(t)
+		inbody, volumes, pbs := mocks.NewInBodyRepository(t), mocks.NewDailyVolumeRepository(t), mocks.NewPersonalBestRepository(t)
+		exercises, schedules := mocks.NewExerciseRepository(t), mocks.NewScheduleRepository(t)
+		m := reportMocks{mocks.NewAIUsageRepository(t), mocks.NewProgressSummarizer(t), mocks.NewFileRepository(t)}
+
+		users.On("GetByID", ctx, "member-1").Return(&domain.User{ID: "member-1", TenantID: "gym", Name: "Ana"}, nil)
+		tenants.On("GetByID", ctx, "gym").Return(&domain.Tenant{ID: "gym", Name: "House of Metamorfit", LogoURL: "https://files/logo.png",
+			AISettings: domain.AISettings{MonthlyQuota: 50}}, nil)
+		inbody.On("FindAllByUserID", ctx, "member-1").Return([]*domain.InBodyRecord{
+			{ID: "after", TestDateTime: day(20)},
+			{ID: "scan-2", TestDateTime: day(12), Weight: 78, SMM: 34, PBF: 20},
+			{ID: "scan-1", TestDateTime: day(2), Weight: 80, SMM: 33, PBF: 22},
+		}, nil)
+		volumes.On("GetByMemberIDAndDateRange", ctx, "member-1", from, end).Return([]*domain.DailyVolume{
+			{Date: day(3), TotalVolume: 4000},
+			{Date: day(5), TotalVolume: 5000},
+			{Date: day(10), TotalVolume: 6000},
+		}, nil)
+		schedules.On("GetByMember", ctx, "member-1", from, end).Return([]*domain.Schedule{
+			{StartTime: day(3), Status: domain.ScheduleStatusCompleted},
+			{StartTime: day(5), Status: domain.ScheduleStatusCompleted},
+			{StartTime: day(7), Status: domain.ScheduleStatusNoShow},
+			{StartTime: day(8), Status: domain.ScheduleStatusCancelled},
+			{StartTime: day(9), Status: domain.ScheduleStatusCompleted, DeletedAt: &testNow},
+			{StartTime: day(10), Status: domain.ScheduleStatusCompleted},
+		}, nil)
+		pbs.On("GetByMember", ctx, "member-1").Return([]*domain.PersonalBest{
+			{ExerciseID: "squat", Weight: 100, Reps: 5, AchievedAt: day(10)},
+			{ExerciseID: "bench", Weight: 70, Reps: 3, AchievedAt: day(5)},
+			{ExerciseID: "deadlift", Weight: 140, Reps: 1, AchievedAt: day(30).AddDate(0, -2, 0)}, // Before the period
+		}, nil)
+		exercises.On("GetByIDs", ctx, []string{"squat", "bench"}).Return([]*domain.Exercise{{ID: "squat", Name: "Back Squat"}, {ID: "bench", Name: "Bench Press"}}, nil)
+		m.files.On("Download", ctx, "https://files/logo.png").Return(nil, errors.New("gone")) // Rendered without the logo
+
+		return NewProgressReportService(tenants, users, inbody, volumes, pbs, exercises, schedules, m.usage, m.summarizer, m.files, clock.NewFake(testNow)), m
+	}
+	expectStored := func(m reportMocks) *[]byte {
+		var pdf []byte
+		m.files.On("Upload", ctx, mock.Anything, "reports/gym/member-1/2025-06-14-1750068000000000000.pdf", "application/pdf").Run(func(args mock.Arguments) {
+			pdf = args.Get(1).([]byte)
+		}).Return("https://files/report.pdf", nil)
+		m.files.On("SignedURL", ctx, "https://files/report.pdf", progressReportLinkTTL).Return("https://files/report.pdf?sig", nil)
+		return &pdf
+	}
+
+	t.Run("builds, stores and links the report", func(t *testing.T) {
+		svc, m := newService(t)
+		m.usage.On("Consume", ctx, "gym", testNow, 50).Return(nil)
+		m.summarizer.On("SummarizeProgress", ctx, mock.AnythingOfType("*domain.ProgressReport"), mock.AnythingOfType("*domain.Tenant")).
+			Return("  Great fortnight, Ana.  ", nil)
+		pdf := expectStored(m)
+
+		report, err := svc.Generate(ctx, "gym", "member-1", from, to)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Great fortnight, Ana.", report.Summary)
+		require.Len(t, report.Scans, 2)
+		assert.Equal(t, "scan-1", report.Scans[0].ID)
+		assert.Equal(t, []domain.WeeklyVolume{
+			{WeekStart: time.Date(2025, 5, 26, 0, 0, 0, 0, time.UTC)},
+			{WeekStart: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), Volume: 9000, Sessions: 2},
+			{WeekStart: time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC), Volume: 6000, Sessions: 1},
+		}, report.Volume)
+		assert.Equal(t, domain.ReportAttendance{Completed: 3, NoShow: 1, Cancelled: 1}, report.Attendance)
+		assert.Equal(t, 75, report.Attendance.Rate())
+		assert.Equal(t, []domain.ReportPersonalBest{
+			{Exercise: "Bench Press", Weight: 70, Reps: 3, AchievedAt: day(5)},
+			{Exercise: "Back Squat", Weight: 100, Reps: 5, AchievedAt: day(10)},
+		}, report.PersonalBests)
+		assert.Equal(t, "https://files/report.pdf?sig", report.ShareURL)
+		assert.Equal(t, testNow.Add(progressReportLinkTTL), report.ShareExpires)
+
+		assert.True(t, bytes.HasPrefix(*pdf, []byte("%PDF-")))
+		assert.Contains(t, string(*pdf), "(Progress report: Ana)")
+		assert.Contains(t, string(*pdf), "(Great fortnight, Ana.)")
+	})
+
+	t.Run("includes the session photos the member agreed to", func(t *testing.T) {
+		svc, m := newService(t)
+		m.usage.On("Consume", ctx, "gym", testNow, 50).Return(domain.ErrAIQuotaExceeded)
+		photos := mocks.NewSessionPhotoRepository(t)
+		photos.On("GetConsent", ctx, "gym", "member-1").Return(&domain.PhotoConsent{Capture: true, Reports: true}, nil)
+		photos.On("ListByMember", ctx, "gym", "member-1", from, end).Return([]*domain.SessionPhoto{
+			{ID: "photo-1", URL: "https://files/photo-1.png", Caption: "First pull-up", TakenAt: day(10)},
+			{ID: "photo-2", URL: "https://files/photo-2.png", TakenAt: day(12)},
+		}, nil)
+		svc.IncludePhotos(NewSessionPhotoService(photos, nil, m.files, 0, clock.NewFake(testNow)))
+		var photo bytes.Buffer
+		require.NoError(t, png.Encode(&photo, image.NewRGBA(image.Rect(0, 0, 4, 3))))
+		m.files.On("Download", ctx, "https://files/photo-1.png").Return(photo.Bytes(), nil)
+		m.files.On("Download", ctx, "https://files/photo-2.png").Return(nil, errors.New("gone"))
+		pdf := expectStored(m)
+
+		report, err := svc.Generate(ctx, "gym", "member-1", from, to)
+
+		require.NoError(t, err)
+		assert.Len(t, report.Photos, 2)
+		assert.Contains(t, string(*pdf), "(Session photos)")
+		assert.Contains(t, string(*pdf), "(10 Jun 2025: First pull-up)")
+		assert.NotContains(t, string(*pdf), "(12 Jun 2025)", "a photo that can't be downloaded is left out")
+	})
+
+	t.Run("leaves the summary out when the tenant is out of AI quota", func(t *testing.T) {
+		svc, m := newService(t)
+		m.usage.On("Consume", ctx, "gym", testNow, 50).Return(domain.ErrAIQuotaExceeded)
+		expectStored(m)
+
+		report, err := svc.Generate(ctx, "gym", "member-1", from, to)
+
+		require.NoError(t, err)
+		assert.Empty(t, report.Summary)
+	})
+}
+
+func TestProgressReportService_Generate_RejectsInvalidPeriods(t *testing.T) {
+	svc := NewProgressReportService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, clock.NewFake(testNow))
+	today := testNow.Truncate(24 * time.Hour)
+
+	for name, period := range map[string][2]time.Time{
+		"reversed":       {today, today.AddDate(0, 0, -1)},
+		"over a year":    {today.AddDate(-1, 0, -1), today},
+		"ends in future": {today, today.AddDate(0, 0, 1)},
+	} {
+		_, err := svc.Generate(context.Background(), "gym", "member-1", period[0], period[1])
+		assert.ErrorIs(t, err, domain.ErrInvalidReportPeriod, name)
+	}
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+const (
+	progressWindowDays     = 28 // Each week is scored on the four weeks up to its end
+	progressTargetSessions = 8  // Two sessions a week earn full attendance
+	progressTargetPBs      = 4  // PBs in the window for a full PB component
+)
+
+// ProgressScoreService computes the weekly 0-100 progress score of every member, combining
+// attendance, volume trend, body-composition change and PB frequency
+type ProgressScoreService struct {
+	tenantRepo domain.TenantRepository
+	userRepo   domain.UserRepository
+	schedRepo  domain.ScheduleRepository
+	volumeRepo domain.DailyVolumeRepository
+	inbodyRepo domain.InBodyRepository
+	pbRepo     domain.PersonalBestRepository
+	scoreRepo  domain.ProgressScoreRepository
+	clock      domain.Clock
+}
+
+func NewProgressScoreService(
+	tenantRepo domain.TenantRepository,
+	userRepo domain.UserRepository,
+	schedRepo domain.ScheduleRepository,
+	volumeRepo domain.DailyVolumeRepository,
+	inbodyRepo domain.InBodyRepository,
+	pbRepo domain.PersonalBestRepository,
+	scoreRepo domain.ProgressScoreRepository,
+	clk domain.Clock,
+) *ProgressScoreService {
+	return &ProgressScoreService{
+		tenantRepo: tenantRepo,
+		userRepo:   userRepo,
+		schedRepo:  schedRepo,
+		volumeRepo: volumeRepo,
+		inbodyRepo: inbodyRepo,
+		pbRepo:     pbRepo,
+		scoreRepo:  scoreRepo,
+		clock:      clock.OrReal(clk),
+	}
+}
+
+// ScoreLastWeek scores every member of every tenant for the last full week and returns
+// how many scores were saved
+func (s *ProgressScoreService) ScoreLastWeek(ctx context.Context) (int, error) {
+	tenants, err := s.tenantRepo.GetAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+	week := s.lastWeek()
+	scored := 0
+	for _, tenant := range tenants {
+		n, err := s.ScoreTenant(ctx, tenant, week)
+		scored += n
+		if err != nil {
+			return scored, fmt.Errorf("tenant %s: %w", tenant.ID, err)
+		}
+	}
+	return scored, nil
+}
+
+// ScoreTenant scores the tenant's members for the week starting at weekStart
+func (s *ProgressScoreService) ScoreTenant(ctx context.Context, tenant *domain.Tenant, weekStart time.Time) (int, error) {
+	members, err := s.userRepo.GetByTenantAndRole(ctx, tenant.ID, domain.RoleMember)
+	if err != nil || len(members) == 0 {
+		return 0, err
+	}
+	memberIDs := make([]string, 0, len(members))
+	for _, m := range members {
+		memberIDs = append(memberIDs, m.ID)
+	}
+	scans, err := s.inbodyRepo.GetRecentScansByMembers(ctx, memberIDs, 2)
+	if err != nil {
+		return 0, err
+	}
+
+	weights := tenant.ProgressScoreWeights()
+	end := weekStart.AddDate(0, 0, 7)
+	from := end.AddDate(0, 0, -progressWindowDays)
+	scored := 0
+	for _, memberID := range memberIDs {
+		components, err := s.components(ctx, memberID, from, end, scans[memberID])
+		if err != nil {
+			return scored, err
+		}
+		score := &domain.ProgressScore{
+			TenantID:   tenant.ID,
+			MemberID:   memberID,
+			WeekStart:  weekStart,
+			Score:      weightedScore(components, weights),
+			Components: components,
+			Weights:    weights,
+			ComputedAt: s.clock.Now(),
+		}
+		if err := s.scoreRepo.Upsert(ctx, score); err != nil {
+			return scored, err
+		}
+		scored++
+	}
+	return scored, nil
+}
+
+// MemberScores returns the member's recent weekly scores, newest first
+func (s *ProgressScoreService) MemberScores(ctx context.Context, tenantID, memberID string, limit int) ([]*domain.ProgressScore, error) {
+	return s.scoreRepo.ListByMember(ctx, tenantID, memberID, limit)
+}
+
+// Leaderboard ranks the tenant's members by their score for the last full week
+func (s *ProgressScoreService) Leaderboard(ctx context.Context, tenantID string, limit int) ([]*domain.LeaderboardEntry, error) {
+	scores, err := s.scoreRepo.Leaderboard(ctx, tenantID, s.lastWeek(), limit)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*domain.LeaderboardEntry, 0, len(scores))
+	for i, score := range scores {
+		name := score.MemberID
+		if user, err := s.userRepo.GetByID(ctx, score.MemberID); err == nil && user != nil {
+			name = user.Name
+		}
+		entries = append(entries, &domain.LeaderboardEntry{Rank: i + 1, Name: name, ProgressScore: score})
+	}
+	return entries, nil
+}
+
+// SetWeights changes the tenant's weighting from the next scoring run on. nil goes back
+// to the defaults.
+func (s *ProgressScoreService) SetWeights(ctx context.Context, tenantID string, weights *domain.ProgressWeights) (domain.ProgressWeights, error) {
+	if weights != nil {
+		if err := weights.Validate(); err != nil {
+			return domain.ProgressWeights{}, err
+		}
+	}
+	tenant, err := s.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return domain.ProgressWeights{}, err
+	}
+	tenant.ProgressWeights = weights
+	if err := s.tenantRepo.Update(ctx, tenant); err != nil {
+		return domain.ProgressWeights{}, err
+	}
+	return tenant.ProgressScoreWeights(), nil
+}
+
+func (s *ProgressScoreService) lastWeek() time.Time {
+	return progressWeekStart(s.clock.Now()).AddDate(0, 0, -7)
+}
+
+func (s *ProgressScoreService) components(ctx context.Context, memberID string, from, end time.Time, scans []*domain.InBodyRecord) (domain.ProgressComponents, error) {
+	var c domain.ProgressComponents
+
+	schedules, err := s.schedRepo.GetByMember(ctx, memberID, from, end)
+	if err != nil {
+		return c, err
+	}
+	completed, missed := 0, 0
+	for _, sched := range schedules {
+		if sched.DeletedAt != nil || !sched.StartTime.Before(end) {
+			continue
+		}
+		switch sched.Status {
+		case domain.ScheduleStatusCompleted:
+			completed++
+		case domain.ScheduleStatusNoShow:
+			missed++
+		}
+	}
+	c.Attendance = attendanceScore(completed, missed)
+
+	volumes, err := s.volumeRepo.GetByMemberIDAndDateRange(ctx, memberID, from.AddDate(0, 0, -progressWindowDays), end)
+	if err != nil {
+		return c, err
+	}
+	var current, previous float64
+	for _, v := range volumes {
+		switch {
+		case !v.Date.Before(end):
+		case !v.Date.Before(from):
+			current += v.TotalVolume
+		default:
+			previous += v.TotalVolume
+		}
+	}
+	c.Volume = volumeScore(current, previous)
+
+	c.BodyComposition = 50 // Neutral until there are two scans to compare
+	if len(scans) >= 2 {
+		c.BodyComposition = bodyCompositionScore(scans[0], scans[1])
+	}
+
+	pbs, err := s.pbRepo.GetByMember(ctx, memberID)
+	if err != nil {
+		return c, err
+	}
+	recent := 0
+	for _, pb := range pbs {
+		if !pb.AchievedAt.Before(from) && pb.AchievedAt.Before(end) {
+			recent++
+		}
+	}
+	c.PersonalBests = min(recent, progressTargetPBs) * 100 / progressTargetPBs
+	return c, nil
+}
+
+// attendanceScore is the show-up rate, scaled down below two sessions a week so a single
+// attended session doesn't score full marks
+func attendanceScore(completed, missed int) int {
+	if completed+missed == 0 {
+		return 0
+	}
+	rate := float64(completed) / float64(completed+missed)
+	return clampScore(100 * rate * min(1, float64(completed)/progressTargetSessions))
+}
+
+// volumeScore is 50 for the same volume as the four weeks before, moving 10 points per
+// 10% change. Starting to train from nothing counts as a strong trend.
+func volumeScore(current, previous float64) int {
+	switch {
+	case current == 0:
+		return 0
+	case previous == 0:
+		return 75
+	}
+	return clampScore(50 + 100*(current-previous)/previous)
+}
+
+// bodyCompositionScore rewards muscle gained plus body fat percentage lost between the
+// last two scans: 50 when flat, 100 at a combined +2
+func bodyCompositionScore(latest, previous *domain.InBodyRecord) int {
+	delta := (latest.SMM - previous.SMM) + (previous.PBF - latest.PBF)
+	return clampScore(50 + 25*delta)
+}
+
+func weightedScore(c domain.ProgressComponents, w domain.ProgressWeights) int {
+	total := w.Attendance + w.Volume + w.BodyComposition + w.PersonalBests
+	if total <= 0 {
+		return 0
+	}
+	sum := w.Attendance*float64(c.Attendance) +
+		w.Volume*float64(c.Volume) +
+		w.BodyComposition*float64(c.BodyComposition) +
+		w.PersonalBests*float64(c.PersonalBests)
+	return clampScore(sum / total)
+}
+
+func clampScore(v float64) int {
+	return int(math.Round(math.Max(0, math.Min(100, v))))
+}
+
+// progressWeekStart returns the Monday 00:00 UTC of t's week
+func progressWeekStart(t time.Time) time.Time {
+	day := t.UTC().Truncate(24 * time.Hour)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressScoreService_ScoreTenant(t *testing.T) {
+	ctx := context.Background()
+	tenants := mocks.NewTenantRepository(t)
+	users := mocks.NewUserRepository(t)
+	schedules := mocks.NewScheduleRepository(t)
+	volumes := mocks.NewDailyVolumeRepository(t)
+	scans := mocks.NewInBodyRepository(t)
+	pbs := mocks.NewPersonalBestRepository(t)
+	scores := mocks.NewProgressScoreRepository(t)
+	svc := NewProgressScoreService(tenants, users, schedules, volumes, scans, pbs, scores, clock.NewFake(testNow))
+
+	// testNow is Monday 16 June, so last week starts on the 9th and the window on 19 May
+	week := time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC)
+	end := week.AddDate(0, 0, 7)
+	from := end.AddDate(0, 0, -28)
+	assert.Equal(t, week, svc.lastWeek())
+
+	tenant := &domain.Tenant{ID: "gym", ProgressWeights: &domain.ProgressWeights{Attendance: 1, Volume: 1, BodyComposition: 1, PersonalBests: 1}}
+	users.On("GetByTenantAndRole", ctx, "gym", domain.RoleMember).Return([]*domain.User{{ID: "member-1"}}, nil)
+	scans.On("GetRecentScansByMembers", ctx, []string{"member-1"}, 2).Return(map[string][]*domain.InBodyRecord{
+		"member-1": {{SMM: 33, PBF: 20}, {SMM: 32.5, PBF: 20.5}},
+	}, nil)
+
+	var sessions []*domain.Schedule
+	for i := 0; i < 6; i++ {
+		sessions = append(sessions, &domain.Schedule{StartTime: from.AddDate(0, 0, 4*i), Status: domain.ScheduleStatusCompleted})
+	}
+	sessions = append(sessions,
+		&domain.Schedule{StartTime: from.AddDate(0, 0, 1), Status: domain.ScheduleStatusNoShow},
+		&domain.Schedule{StartTime: from.AddDate(0, 0, 2), Status: domain.ScheduleStatusNoShow},
+	)
+	schedules.On("GetByMember", ctx, "member-1", from, end).Return(sessions, nil)
+	volumes.On("GetByMemberIDAndDateRange", ctx, "member-1", from.AddDate(0, 0, -28), end).Return([]*domain.DailyVolume{
+		{Date: from.AddDate(0, 0, -10), TotalVolume: 10000},
+		{Date: from.AddDate(0, 0, 3), TotalVolume: 6000},
+		{Date: from.AddDate(0, 0, 20), TotalVolume: 6000},
+	}, nil)
+	pbs.On("GetByMember", ctx, "member-1").Return([]*domain.PersonalBest{
+		{AchievedAt: from.AddDate(0, 0, 5)},
+		{AchievedAt: from.AddDate(0, 0, -1)}, // Before the window
+	}, nil)
+
+	var saved *domain.ProgressScore
+	scores.On("Upsert", ctx, mock.AnythingOfType("*domain.ProgressScore")).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*domain.ProgressScore)
+	}).Return(nil)
+
+	scored, err := svc.ScoreTenant(ctx, tenant, week)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, scored)
+	require.NotNil(t, saved)
+	// 6 of 8 sessions attended, and 6 of the 8 a week-on-week regular would
+	assert.Equal(t, 56, saved.Components.Attendance)
+	// 12000 against 10000 the four weeks before
+	assert.Equal(t, 70, saved.Components.Volume)
+	// +0.5kg muscle, -0.5% fat
+	assert.Equal(t, 75, saved.Components.BodyComposition)
+	assert.Equal(t, 25, saved.Components.PersonalBests)
+	assert.Equal(t, 57, saved.Score)
+	assert.Equal(t, week, saved.WeekStart)
+}
+
+func TestProgressScoreService_SetWeights(t *testing.T) {
+	ctx := context.Background()
+	tenants := mocks.NewTenantRepository(t)
+	svc := NewProgressScoreService(tenants, nil, nil, nil, nil, nil, nil, clock.NewFake(testNow))
+
+	_, err := svc.SetWeights(ctx, "gym", &domain.ProgressWeights{Attendance: -1, Volume: 2})
+	assert.ErrorIs(t, err, domain.ErrInvalidProgressWeights)
+
+	tenants.On("GetByID", ctx, "gym").Return(&domain.Tenant{ID: "gym", ProgressWeights: &domain.ProgressWeights{Volume: 1}}, nil)
+	tenants.On("Update", ctx, mock.MatchedBy(func(tenant *domain.Tenant) bool { return tenant.ProgressWeights == nil })).Return(nil)
+
+	weights, err := svc.SetWeights(ctx, "gym", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.DefaultProgressWeights, weights)
+}
+
+func TestProgressComponentScores(t *testing.T) {
+	assert.Equal(t, 0, attendanceScore(0, 0))
+	assert.Equal(t, 100, attendanceScore(10, 0))
+	assert.Equal(t, 13, attendanceScore(1, 0), "one session isn't full attendance")
+
+	assert.Equal(t, 0, volumeScore(0, 5000))
+	assert.Equal(t, 75, volumeScore(5000, 0))
+	assert.Equal(t, 50, volumeScore(5000, 5000))
+	assert.Equal(t, 0, volumeScore(2000, 5000))
+
+	assert.Equal(t, time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC), progressWeekStart(time.Date(2025, 6, 22, 23, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+const progressSummaryPrompt = `Write the summary of %s's progress report for %s to %s, addressed to the member. In 3-5 sentences, point out what improved, what to work on next, and end with encouragement. Only use the numbers below; don't invent any. Reply with the summary text only, no headings or markdown.
+
+%s`
+
+// OpenRouterProgressSummarizer implements domain.ProgressSummarizer with a text model on OpenRouter
+type OpenRouterProgressSummarizer struct {
+	client *openRouterClient
+}
+
+func NewOpenRouterProgressSummarizer(apiKey, model string) *OpenRouterProgressSummarizer {
+	return &OpenRouterProgressSummarizer{client: newOpenRouterClient(apiKey, model, "HOM Gym Progress Report")}
+}
+
+func (a *OpenRouterProgressSummarizer) SummarizeProgress(ctx context.Context, report *domain.ProgressReport, tenant *domain.Tenant) (string, error) {
+	pc := newPromptContext(tenant)
+	system := fmt.Sprintf("You are a %s at %s. Your tone should be %s. Your writing should be %s.", pc.Persona, pc.GymName, pc.Tone, pc.Style)
+	if pc.Language != "" {
+		system += " Write in " + pc.Language + "."
+	}
+	if pc.Instructions != "" {
+		system += "\n\nGym instructions: " + pc.Instructions
+	}
+	if pc.BannedTopics != "" {
+		system += "\n\nNever mention: " + pc.BannedTopics
+	}
+
+	user := fmt.Sprintf(progressSummaryPrompt, promptText.Replace(report.MemberName),
+		report.From.Format("2 Jan 2006"), report.To.Format("2 Jan 2006"), progressFacts(report))
+	return a.client.complete(ctx, []map[string]interface{}{
+		{"role": "system", "content": system},
+		{"role": "user", "content": user},
+	}, 0.5)
+}
+
+// progressFacts lists the report's numbers for the prompt
+func progressFacts(report *domain.ProgressReport) string {
+	var b strings.Builder
+	if n := len(report.Scans); n > 0 {
+		first, last := report.Scans[0], report.Scans[n-1]
+		fmt.Fprintf(&b, "Body scans: %d. Latest: weight %.1f kg, skeletal muscle %.1f kg, body fat %.1f%%.\n", n, last.Weight, last.SMM, last.PBF)
+		if n > 1 {
+			fmt.Fprintf(&b, "Change since the first scan: weight %+.1f kg, muscle %+.1f kg, body fat %+.1f points.\n",
+				last.Weight-first.Weight, last.SMM-first.SMM, last.PBF-first.PBF)
+		}
+	} else {
+		b.WriteString("Body scans: none in this period.\n")
+	}
+
+	weekly := make([]string, len(report.Volume))
+	for i, week := range report.Volume {
+		weekly[i] = fmt.Sprintf("%.0f", week.Volume)
+	}
+	fmt.Fprintf(&b, "Weekly training volume in kg, oldest first: %s.\n", strings.Join(weekly, ", "))
+
+	for _, pb := range report.PersonalBests {
+		fmt.Fprintf(&b, "New personal best: %s %.1f kg x %d.\n", promptText.Replace(pb.Exercise), pb.Weight, pb.Reps)
+	}
+	a := report.Attendance
+	fmt.Fprintf(&b, "Sessions: %d completed, %d missed, %d cancelled.\n", a.Completed, a.NoShow, a.Cancelled)
+	return b.String()
+}
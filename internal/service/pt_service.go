@@ -4,18 +4,39 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/mansoorceksport/metamorph/internal/clock"
 	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Lock timings for session completion and credit movements. The TTL only matters if a
+// holder crashes; the wait lets a double-tapped request queue behind the first one.
+const (
+	ptLockTTL  = 15 * time.Second
+	ptLockWait = 5 * time.Second
 )
 
 type PTService struct {
 	pkgRepo      domain.PTPackageRepository
 	contractRepo domain.PTContractRepository
 	schedRepo    domain.ScheduleRepository
-	sessionRepo  domain.WorkoutSessionRepository // For cascade delete of planned exercises
-	setLogRepo   domain.SetLogRepository         // For cascade delete of set logs
-	pbRepo       domain.PersonalBestRepository   // For PB updates at session completion
+	sessionRepo  domain.WorkoutSessionRepository    // For cascade delete of planned exercises
+	setLogRepo   domain.SetLogRepository            // For cascade delete of set logs
+	creditRepo   domain.CreditTransactionRepository // Source of truth for session credits
+	agreements   *AgreementService                  // Optional: generates contract agreements on purchase
+	documents    *DocumentService                   // Optional: blocks booking until required waivers are signed
+	locker       domain.Locker                      // Optional: serializes completions and credit movements across instances
+	availability domain.CoachAvailabilityRepository // Optional: rejects bookings outside the coach's working hours
+	meetings     domain.MeetingLinkGenerator        // Optional: creates video links for online sessions booked without one
+	outbox       *Outbox                            // Optional: without it members aren't told about bookings and new packages
+	guardians    *GuardianService                   // Optional: see RequireGuardianConsent
+	renewals     *RenewalService                    // Optional: see RenewOnDepletion
+	clock        domain.Clock
 }
 
 func NewPTService(
@@ -24,7 +45,14 @@ func NewPTService(
 	schedRepo domain.ScheduleRepository,
 	sessionRepo domain.WorkoutSessionRepository,
 	setLogRepo domain.SetLogRepository,
-	pbRepo domain.PersonalBestRepository,
+	creditRepo domain.CreditTransactionRepository,
+	agreements *AgreementService,
+	documents *DocumentService,
+	locker domain.Locker,
+	availability domain.CoachAvailabilityRepository,
+	meetings domain.MeetingLinkGenerator,
+	outbox *Outbox,
+	clk domain.Clock,
 ) *PTService {
 	return &PTService{
 		pkgRepo:      pkgRepo,
@@ -32,19 +60,42 @@ func NewPTService(
 		schedRepo:    schedRepo,
 		sessionRepo:  sessionRepo,
 		setLogRepo:   setLogRepo,
-		pbRepo:       pbRepo,
+		creditRepo:   creditRepo,
+		agreements:   agreements,
+		documents:    documents,
+		locker:       locker,
+		availability: availability,
+		meetings:     meetings,
+		outbox:       outbox,
+		clock:        clock.OrReal(clk),
 	}
 }
 
+// RequireGuardianConsent stops minors from being booked until a guardian's consent is recorded
+func (s *PTService) RequireGuardianConsent(guardians *GuardianService) {
+	s.guardians = guardians
+}
+
+// RenewOnDepletion renews contracts set to auto-renew as soon as their last session is completed
+func (s *PTService) RenewOnDepletion(renewals *RenewalService) {
+	s.renewals = renewals
+}
+
 // --- Package (Template) Management ---
 
 func (s *PTService) CreatePackageTemplate(ctx context.Context, pkg *domain.PTPackage) error {
 	// Validate Sessions Tier
-	validTiers := map[int]bool{10: true, 20: true, 30: true, 40: true, 50: true}
-	if !validTiers[pkg.TotalSessions] {
+	if !domain.ValidSessionAmount(pkg.TotalSessions) {
 		return domain.ErrInvalidSessionAmount
 	}
+	if pkg.ValidityMonths < 0 {
+		return domain.ErrInvalidPackageValidity
+	}
 
+	pkg.Price = domain.NewMoney(pkg.Price.Minor, pkg.Price.Currency)
+	if err := pkg.NormalizeBranchPrices(); err != nil {
+		return err
+	}
 	pkg.Active = true
 	return s.pkgRepo.Create(ctx, pkg)
 }
@@ -59,46 +110,215 @@ func (s *PTService) GetPackageTemplate(ctx context.Context, id string) (*domain.
 
 func (s *PTService) UpdatePackageTemplate(ctx context.Context, pkg *domain.PTPackage) error {
 	// Optional: basic validation if fields present
-	if pkg.TotalSessions > 0 {
-		validTiers := map[int]bool{10: true, 20: true, 30: true, 40: true, 50: true}
-		if !validTiers[pkg.TotalSessions] {
-			return domain.ErrInvalidSessionAmount
+	if pkg.TotalSessions > 0 && !domain.ValidSessionAmount(pkg.TotalSessions) {
+		return domain.ErrInvalidSessionAmount
+	}
+	if pkg.ValidityMonths < 0 {
+		return domain.ErrInvalidPackageValidity
+	}
+	pkg.Price = domain.NewMoney(pkg.Price.Minor, pkg.Price.Currency)
+	if len(pkg.BranchPrices) > 0 && pkg.BranchID == "" {
+		// Updates don't move a package between branches, so check against where it is sold
+		current, err := s.pkgRepo.GetByID(ctx, pkg.ID)
+		if err != nil {
+			return err
 		}
+		pkg.BranchID = current.BranchID
+	}
+	if err := pkg.NormalizeBranchPrices(); err != nil {
+		return err
 	}
 	return s.pkgRepo.Update(ctx, pkg)
 }
 
 // --- Contract Management (Purchasing/Assigning) ---
 
-func (s *PTService) CreateContract(ctx context.Context, contractReq *domain.PTContract) error {
-	// 1. Fetch Template
-	template, err := s.pkgRepo.GetByID(ctx, contractReq.PackageID)
+func (s *PTService) CreateContract(ctx context.Context, contractReq *domain.PTContract) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "PTService.CreateContract",
+		telemetry.TenantID(contractReq.TenantID), telemetry.MemberID(contractReq.MemberID))
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	template, err := s.hydrateContract(ctx, contractReq)
 	if err != nil {
 		return err
 	}
+	contractReq.RemainingSessions = template.TotalSessions
+	contractReq.Status = domain.PackageStatusActive
+	if expiresAt := s.validityFrom(template); expiresAt != nil {
+		contractReq.ExpiresAt = expiresAt
+	}
+
+	msg := &domain.OutboxMessage{Topic: domain.OutboxTopicContractCreated, TenantID: contractReq.TenantID}
+	err = s.recordChange(ctx, func(ctx context.Context) error {
+		if err := s.contractRepo.Create(ctx, contractReq); err != nil {
+			return err
+		}
+		msg.Key = contractReq.ID
+		return nil
+	}, msg)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.applyCredit(ctx, contractReq, domain.CreditTypePurchased, contractReq.TotalSessions, "", "", "Package purchased", "purchased:"+contractReq.ID); err != nil {
+		return fmt.Errorf("contract created but failed to record purchased credits: %w", err)
+	}
+
+	// Generate the agreement for the member to sign (non-blocking for the purchase itself)
+	if s.agreements != nil {
+		if _, err := s.agreements.GenerateForContract(ctx, contractReq); err != nil {
+			fmt.Printf("Warning: failed to generate agreement for contract %s: %v\n", contractReq.ID, err)
+		}
+	}
+	return nil
+}
+
+// CreatePendingContract creates a contract from its package that waits for payment: no
+// sessions are credited and its validity doesn't start until ActivateContract.
+func (s *PTService) CreatePendingContract(ctx context.Context, contract *domain.PTContract) error {
+	if _, err := s.hydrateContract(ctx, contract); err != nil {
+		return err
+	}
+	contract.RemainingSessions = 0
+	contract.Status = domain.PackageStatusPendingPayment
+	contract.ExpiresAt = nil
+	return s.contractRepo.Create(ctx, contract)
+}
+
+// ActivateContract credits the sessions of a contract waiting for payment and makes it
+// active, its validity starting now. A contract that isn't waiting is returned unchanged.
+func (s *PTService) ActivateContract(ctx context.Context, contractID string) (*domain.PTContract, error) {
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	if contract.Status != domain.PackageStatusPendingPayment {
+		return contract, nil
+	}
+	template, err := s.pkgRepo.GetByID(ctx, contract.PackageID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Credit first: the ledger entry is only written once, so a retry after a failed
+	// activation doesn't credit the sessions twice
+	_, err = s.applyCredit(ctx, contract, domain.CreditTypePurchased, contract.TotalSessions, "", "", "Package renewed", "purchased:"+contract.ID)
+	if err != nil && err != domain.ErrDuplicateCredit {
+		return nil, fmt.Errorf("failed to record purchased credits: %w", err)
+	}
+	expiresAt := s.validityFrom(template)
+	activated, err := s.contractRepo.ActivatePending(ctx, contract.ID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if !activated {
+		return s.contractRepo.GetByID(ctx, contract.ID)
+	}
+	contract.Status = domain.PackageStatusActive
+	contract.ExpiresAt = expiresAt
+
+	if s.agreements != nil {
+		if _, err := s.agreements.GenerateForContract(ctx, contract); err != nil {
+			fmt.Printf("Warning: failed to generate agreement for contract %s: %v\n", contract.ID, err)
+		}
+	}
+	return contract, nil
+}
+
+// CreditPaidPackage credits the sessions of a PT package paid for through a checkout to the
+// member's newest active or depleted contract on it. The credit is keyed on the invoice, so
+// a retried settlement doesn't credit it twice. Returns ErrContractNotFound when the member
+// has no such contract: one needs a coach, so it isn't created here.
+func (s *PTService) CreditPaidPackage(ctx context.Context, memberID, ptPackageID, invoiceID string) (_ *domain.PTContract, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "PTService.CreditPaidPackage",
+		telemetry.MemberID(memberID), attribute.String("invoice_id", invoiceID))
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	template, err := s.pkgRepo.GetByID(ctx, ptPackageID)
+	if err != nil {
+		return nil, err
+	}
+	contracts, err := s.contractRepo.GetByMemberAndPackage(ctx, memberID, ptPackageID)
+	if err != nil {
+		return nil, err
+	}
+	var contract *domain.PTContract
+	for _, c := range contracts {
+		if c.Status == domain.PackageStatusActive || c.Status == domain.PackageStatusDepleted {
+			contract = c
+			break
+		}
+	}
+	if contract == nil {
+		return nil, domain.ErrContractNotFound
+	}
+
+	if err := s.ensureLedgerOpened(ctx, contract); err != nil {
+		return nil, err
+	}
+	_, err = s.applyCredit(ctx, contract, domain.CreditTypePurchased, template.TotalSessions, "", "", "Package paid", "purchased:invoice:"+invoiceID)
+	if err != nil && err != domain.ErrDuplicateCredit {
+		return nil, fmt.Errorf("failed to record purchased credits: %w", err)
+	}
+	return contract, nil
+}
+
+// hydrateContract copies the package's sessions and price onto the contract after checking
+// the package can be sold at its branch, and returns the package
+func (s *PTService) hydrateContract(ctx context.Context, contract *domain.PTContract) (*domain.PTPackage, error) {
+	template, err := s.pkgRepo.GetByID(ctx, contract.PackageID)
+	if err != nil {
+		return nil, err
+	}
 	if !template.Active {
-		return errors.New("cannot create contract from inactive package template")
+		return nil, errors.New("cannot create contract from inactive package template")
 	}
 
-	// 2. Validate Branch Consistency (Optional strict mode: Template Branch == Member Branch == Coach Branch)
+	// Optional strict mode would be Template Branch == Member Branch == Coach Branch.
 	// For now, simpler check: Template Branch must match assigned Branch if template has one.
-	if template.BranchID != "" && template.BranchID != contractReq.BranchID {
-		return domain.ErrBranchMismatch
+	if template.BranchID != "" && template.BranchID != contract.BranchID {
+		return nil, domain.ErrBranchMismatch
 	}
 
-	// 3. Hydrate Contract from Template
-	contractReq.TotalSessions = template.TotalSessions
-	contractReq.RemainingSessions = template.TotalSessions
-	contractReq.Price = template.Price
-	contractReq.Status = domain.PackageStatusActive
+	contract.TotalSessions = template.TotalSessions
+	contract.Price, contract.BranchPriced = template.PriceAt(contract.BranchID)
+	return template, nil
+}
 
-	return s.contractRepo.Create(ctx, contractReq)
+// validityFrom returns when sessions of the package bought now expire, nil if they don't
+func (s *PTService) validityFrom(template *domain.PTPackage) *time.Time {
+	if template.ValidityMonths <= 0 {
+		return nil
+	}
+	expiresAt := s.clock.Now().AddDate(0, template.ValidityMonths, 0)
+	return &expiresAt
+}
+
+// ImportContract saves a contract migrated from another gym system as it stood there. Its
+// remaining sessions open the credit ledger; sessions used before the move aren't replayed.
+func (s *PTService) ImportContract(ctx context.Context, contract *domain.PTContract, note string) error {
+	remaining := contract.RemainingSessions
+	if err := s.contractRepo.Create(ctx, contract); err != nil {
+		return err
+	}
+	if remaining == 0 {
+		return nil
+	}
+	if _, err := s.applyCredit(ctx, contract, domain.CreditTypeOpening, remaining, "", "", note, "opening:"+contract.ID); err != nil {
+		return fmt.Errorf("contract imported but failed to record its opening balance: %w", err)
+	}
+	return nil
 }
 
 func (s *PTService) GetContractsByTenant(ctx context.Context, tenantID string) ([]*domain.PTContract, error) {
 	return s.contractRepo.GetByTenant(ctx, tenantID)
 }
 
+// ListContractsPage returns one page of the tenant's contracts, newest first
+func (s *PTService) ListContractsPage(ctx context.Context, tenantID string, q domain.PageQuery) (*domain.Page[*domain.PTContract], error) {
+	return s.contractRepo.ListByTenant(ctx, tenantID, q)
+}
+
 func (s *PTService) GetActiveContractsByMember(ctx context.Context, memberID string) ([]*domain.PTContract, error) {
 	return s.contractRepo.GetActiveByMember(ctx, memberID)
 }
@@ -126,15 +346,200 @@ func (s *PTService) GetContractsByMemberAndCoach(ctx context.Context, memberID,
 	return s.contractRepo.GetByMemberAndCoach(ctx, memberID, coachID)
 }
 
+// SetMetricVisibility changes which metrics the member of one of the coach's contracts sees
+// of themselves; nil shows everything again
+func (s *PTService) SetMetricVisibility(ctx context.Context, coachID, contractID string, visibility *domain.MetricVisibility) (*domain.PTContract, error) {
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	if contract.CoachID != coachID {
+		return nil, domain.ErrForbidden
+	}
+	if visibility != nil && !visibility.Restricted() {
+		visibility = nil
+	}
+
+	if err := s.contractRepo.SetMetricVisibility(ctx, contractID, visibility); err != nil {
+		return nil, err
+	}
+	contract.MetricVisibility = visibility
+	return contract, nil
+}
+
+// MemberMetricVisibility returns which of their metrics a member may see, combined over their
+// active contracts
+func (s *PTService) MemberMetricVisibility(ctx context.Context, memberID string) (domain.MetricVisibility, error) {
+	contracts, err := s.contractRepo.GetActiveByMember(ctx, memberID)
+	if err != nil {
+		return domain.MetricVisibility{}, err
+	}
+	return domain.MemberMetricVisibility(contracts), nil
+}
+
+// --- Credits & Statements ---
+
+// AdjustCredits applies a manual credit movement (refund, freeze, expiry, correction) to a contract.
+// amount is always positive except for CreditTypeAdjusted, where the sign gives the direction.
+func (s *PTService) AdjustCredits(ctx context.Context, contractID, creditType string, amount int, note, actorID string) (tx *domain.CreditTransaction, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "PTService.AdjustCredits",
+		attribute.String("contract_id", contractID), attribute.String("credit.type", creditType))
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	sign, ok := domain.CreditTypeSign(creditType)
+	if !ok || creditType == domain.CreditTypePurchased || creditType == domain.CreditTypeConsumed {
+		return nil, domain.ErrInvalidCreditType
+	}
+	delta := amount
+	if sign != 0 {
+		if amount <= 0 {
+			return nil, domain.ErrInvalidCreditAmount
+		}
+		delta = sign * amount
+	} else if amount == 0 {
+		return nil, domain.ErrInvalidCreditAmount
+	}
+
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureLedgerOpened(ctx, contract); err != nil {
+		return nil, err
+	}
+	return s.applyCredit(ctx, contract, creditType, delta, "", actorID, note, "")
+}
+
+// ExpireContract records the loss of the contract's remaining sessions on its ledger and
+// marks it expired, returning how many sessions expired. The ledger entry is only written
+// once, so retrying after a failed status update doesn't expire anything twice.
+func (s *PTService) ExpireContract(ctx context.Context, contract *domain.PTContract) (int, error) {
+	if err := s.ensureLedgerOpened(ctx, contract); err != nil {
+		return 0, err
+	}
+	latest, err := s.creditRepo.GetLatest(ctx, contract.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load credit ledger: %w", err)
+	}
+	expired := 0
+	if latest != nil && latest.BalanceAfter > 0 {
+		expired = latest.BalanceAfter
+		_, err := s.applyCredit(ctx, contract, domain.CreditTypeExpired, -expired, "", "", "Sessions expired", "expired:"+contract.ID)
+		if err == domain.ErrDuplicateCredit {
+			expired = 0
+		} else if err != nil {
+			return 0, err
+		}
+	}
+	if err := s.contractRepo.UpdateStatus(ctx, contract.ID, domain.PackageStatusExpired); err != nil {
+		return 0, err
+	}
+	contract.Status = domain.PackageStatusExpired
+	return expired, nil
+}
+
+// GetContractStatement returns the itemized credit ledger for a contract
+func (s *PTService) GetContractStatement(ctx context.Context, contractID string) (*domain.ContractStatement, error) {
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureLedgerOpened(ctx, contract); err != nil {
+		return nil, err
+	}
+	txns, err := s.creditRepo.GetByContract(ctx, contract.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load credit transactions: %w", err)
+	}
+	return domain.BuildContractStatement(contract, txns), nil
+}
+
+// applyCredit appends an entry to the contract's ledger and projects the new balance onto
+// RemainingSessions. The ledger entry is authoritative; a failed projection is only logged
+// and will be corrected by the next movement on the contract.
+func (s *PTService) applyCredit(ctx context.Context, contract *domain.PTContract, creditType string, amount int, scheduleID, actorID, note, idempotencyKey string) (*domain.CreditTransaction, error) {
+	// Holding the contract lock keeps ledger appends and balance syncs in order
+	release, err := s.lock(ctx, "contract:"+contract.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	txn := &domain.CreditTransaction{
+		TenantID:       contract.TenantID,
+		ContractID:     contract.ID,
+		MemberID:       contract.MemberID,
+		Type:           creditType,
+		Amount:         amount,
+		ScheduleID:     scheduleID,
+		ActorID:        actorID,
+		Note:           note,
+		IdempotencyKey: idempotencyKey,
+	}
+	if err := s.creditRepo.Append(ctx, txn); err != nil {
+		return nil, err
+	}
+
+	if err := s.contractRepo.SyncBalance(ctx, contract.ID, txn.BalanceAfter, txn.Sequence); err != nil {
+		fmt.Printf("Warning: failed to sync balance for contract %s: %v\n", contract.ID, err)
+	} else {
+		contract.RemainingSessions = txn.BalanceAfter
+	}
+	return txn, nil
+}
+
+// lock acquires a distributed lock when a locker is configured, otherwise it's a no-op
+func (s *PTService) lock(ctx context.Context, key string) (func(), error) {
+	if s.locker == nil {
+		return func() {}, nil
+	}
+	return s.locker.Acquire(ctx, key, ptLockTTL, ptLockWait)
+}
+
+// ensureLedgerOpened carries over the legacy remaining_sessions counter for contracts created
+// before the ledger existed (and not yet migrated), so their first movement starts from it.
+func (s *PTService) ensureLedgerOpened(ctx context.Context, contract *domain.PTContract) error {
+	latest, err := s.creditRepo.GetLatest(ctx, contract.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load credit ledger: %w", err)
+	}
+	if latest != nil || contract.RemainingSessions <= 0 {
+		return nil
+	}
+
+	_, err = s.applyCredit(ctx, contract, domain.CreditTypeOpening, contract.RemainingSessions, "", "", "Opening balance", "opening:"+contract.ID)
+	if err != nil && err != domain.ErrDuplicateCredit {
+		return fmt.Errorf("failed to open credit ledger: %w", err)
+	}
+	return nil
+}
+
 // --- Scheduling ---
 
-func (s *PTService) CreateSchedule(ctx context.Context, schedule *domain.Schedule) error {
+func (s *PTService) CreateSchedule(ctx context.Context, schedule *domain.Schedule) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "PTService.CreateSchedule",
+		telemetry.TenantID(schedule.TenantID), telemetry.MemberID(schedule.MemberID))
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	if schedule.Tags, err = domain.NormalizeScheduleTags(schedule.Tags); err != nil {
+		return err
+	}
+	if err := domain.ValidateScheduleLabel(schedule.Label, schedule.Color); err != nil {
+		return err
+	}
+	if err := schedule.NormalizeModality(); err != nil {
+		return err
+	}
+
 	// 1. Verify Contract exists and has remaining sessions
 	contract, err := s.contractRepo.GetByID(ctx, schedule.ContractID)
 	if err != nil {
 		return err
 	}
 
+	if contract.Status == domain.PackageStatusSuspended {
+		return domain.ErrContractSuspended
+	}
 	if contract.Status != domain.PackageStatusActive || contract.RemainingSessions <= 0 {
 		return domain.ErrPackageDepleted
 	}
@@ -161,11 +566,35 @@ func (s *PTService) CreateSchedule(ctx context.Context, schedule *domain.Schedul
 		return domain.ErrBranchMismatch
 	}
 
+	if err := s.checkAvailability(ctx, schedule); err != nil {
+		return err
+	}
+
+	// Waivers marked as blocking must be signed before sessions can be booked
+	if s.documents != nil {
+		if err := s.documents.CheckBookingAllowed(ctx, contract.TenantID, contract.MemberID); err != nil {
+			return err
+		}
+	}
+	if s.guardians != nil {
+		if err := s.guardians.CheckBookingAllowed(ctx, contract.TenantID, contract.MemberID); err != nil {
+			return err
+		}
+	}
+
 	// 2. Set defaults
 	schedule.Status = domain.ScheduleStatusScheduled
+	s.attachMeeting(ctx, schedule)
 
 	// 3. Create
-	return s.schedRepo.Create(ctx, schedule)
+	msg := scheduleChangedMessage(schedule, domain.ScheduleChangeBooked)
+	return s.recordChange(ctx, func(ctx context.Context) error {
+		if err := s.schedRepo.Create(ctx, schedule); err != nil {
+			return err
+		}
+		msg.Key = schedule.ID
+		return nil
+	}, msg)
 }
 
 func (s *PTService) RescheduleSession(ctx context.Context, scheduleID string, newStart, newEnd time.Time, actorRole string, actorID string) error {
@@ -193,10 +622,48 @@ func (s *PTService) RescheduleSession(ctx context.Context, scheduleID string, ne
 		schedule.Status = domain.ScheduleStatusScheduled
 	}
 
-	return s.schedRepo.Update(ctx, schedule)
+	change := func(ctx context.Context) error {
+		if err := s.schedRepo.Update(ctx, schedule); err != nil {
+			return err
+		}
+		// An answer for the old time says nothing about the new one
+		if schedule.Confirmation != nil || schedule.CoachAlertedAt != nil {
+			return s.schedRepo.SetConfirmation(ctx, schedule.ID, nil)
+		}
+		return nil
+	}
+	// A member moving their own session tells the coach, who has to confirm the new time
+	if actorRole == "member" {
+		return s.recordChange(ctx, change, scheduleChangedMessage(schedule, domain.ScheduleChangeMemberRescheduled))
+	}
+	return s.recordChange(ctx, change, scheduleChangedMessage(schedule, domain.ScheduleChangeRescheduled))
 }
 
-func (s *PTService) CompleteSession(ctx context.Context, scheduleID string, coachID string) error {
+// recordChange saves a change the apps should hear about together with msg, which
+// MemberAppNotifier and SessionEventNotifier turn into pushes. Without an outbox the change
+// is saved alone.
+func (s *PTService) recordChange(ctx context.Context, change func(ctx context.Context) error, msg *domain.OutboxMessage) error {
+	if s.outbox == nil {
+		return change(ctx)
+	}
+	return s.outbox.Write(ctx, change, msg)
+}
+
+// scheduleChangedMessage reports a change to the session. Key is the schedule ID, which new
+// sessions only get on create.
+func scheduleChangedMessage(schedule *domain.Schedule, change string) *domain.OutboxMessage {
+	return &domain.OutboxMessage{
+		Topic:    domain.OutboxTopicScheduleChanged,
+		TenantID: schedule.TenantID,
+		Key:      schedule.ID,
+		Payload:  map[string]interface{}{"change": change},
+	}
+}
+
+func (s *PTService) CompleteSession(ctx context.Context, scheduleID string, coachID string) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "PTService.CompleteSession", attribute.String("schedule_id", scheduleID))
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	schedule, err := s.schedRepo.GetByID(ctx, scheduleID)
 	if err != nil {
 		// If not found or invalid ID, try looking up by ClientID (ULID)
@@ -215,73 +682,77 @@ func (s *PTService) CompleteSession(ctx context.Context, scheduleID string, coac
 		}
 	}
 
+	span.SetAttributes(telemetry.TenantID(schedule.TenantID), telemetry.MemberID(schedule.MemberID))
+
 	if schedule.CoachID != coachID {
 		return domain.ErrForbidden
 	}
 
+	// Serialize completions of the same schedule (e.g. "Complete" double-tapped on a flaky
+	// connection) and re-read it under the lock so the status check below is current.
+	release, err := s.lock(ctx, "schedule:"+scheduleID)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if s.locker != nil {
+		if schedule, err = s.schedRepo.GetByID(ctx, scheduleID); err != nil {
+			return err
+		}
+	}
+
 	if schedule.Status == domain.ScheduleStatusCompleted {
 		return errors.New("session already completed")
 	}
 
-	// 1. Mark Schedule as Completed
-	if err := s.schedRepo.UpdateStatus(ctx, scheduleID, domain.ScheduleStatusCompleted); err != nil {
-		return fmt.Errorf("failed to complete schedule: %w", err)
+	// 1. Consume a credit from the ledger. The idempotency key makes a retried or concurrent
+	// completion of the same schedule a no-op instead of a second deduction.
+	contract, err := s.contractRepo.GetByID(ctx, schedule.ContractID)
+	if err != nil {
+		return fmt.Errorf("failed to load contract: %w", err)
 	}
-
-	// 2. Atomically Decrement Contract
-	if err := s.contractRepo.DecrementSession(ctx, schedule.ContractID); err != nil {
-		return fmt.Errorf("session completed but failed to decrement contract: %w", err)
+	if err := s.ensureLedgerOpened(ctx, contract); err != nil {
+		return err
+	}
+	_, err = s.applyCredit(ctx, contract, domain.CreditTypeConsumed, -1, scheduleID, coachID, "", "consumed:"+scheduleID)
+	if err == domain.ErrInsufficientCredits {
+		return domain.ErrPackageDepleted
+	}
+	if err != nil && err != domain.ErrDuplicateCredit {
+		return fmt.Errorf("failed to consume session credit: %w", err)
 	}
+	consumed := err == nil
 
-	// 3. Update Personal Bests (batch processing at session completion)
-	if s.pbRepo != nil && s.setLogRepo != nil {
-		setLogs, err := s.setLogRepo.GetByScheduleID(ctx, scheduleID)
-		if err != nil {
-			// Log but don't fail the completion
-			fmt.Printf("Warning: Failed to fetch set logs for PB update: %v\n", err)
-		} else {
-			// Group by (member_id, exercise_id) and find max weight for completed sets
-			type pbKey struct {
-				memberID   string
-				exerciseID string
-			}
-			maxWeights := make(map[pbKey]struct {
-				weight float64
-				reps   int
-			})
-
-			for _, log := range setLogs {
-				if !log.Completed || log.Weight <= 0 {
-					continue
-				}
-				key := pbKey{memberID: log.MemberID, exerciseID: log.ExerciseID}
-				if existing, ok := maxWeights[key]; !ok || log.Weight > existing.weight {
-					maxWeights[key] = struct {
-						weight float64
-						reps   int
-					}{weight: log.Weight, reps: log.Reps}
-				}
-			}
+	// 2. Mark Schedule as Completed. A package running low warns the member, once per
+	// session consumed.
+	complete := func(ctx context.Context) error {
+		return s.schedRepo.UpdateStatus(ctx, scheduleID, domain.ScheduleStatusCompleted)
+	}
+	if consumed && contract.RemainingSessions > 0 && contract.RemainingSessions < domain.LowSessionsThreshold {
+		err = s.recordChange(ctx, complete, &domain.OutboxMessage{
+			Topic:    domain.OutboxTopicContractLowSessions,
+			TenantID: contract.TenantID,
+			Key:      contract.ID,
+			Payload:  map[string]interface{}{"remaining": strconv.Itoa(contract.RemainingSessions)},
+		})
+	} else {
+		err = complete(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("credit consumed but failed to complete schedule: %w", err)
+	}
 
-			// Upsert each PB
-			for key, val := range maxWeights {
-				pb := &domain.PersonalBest{
-					MemberID:   key.memberID,
-					ExerciseID: key.exerciseID,
-					Weight:     val.weight,
-					Reps:       val.reps,
-					ScheduleID: scheduleID,
-				}
-				isNewPB, err := s.pbRepo.Upsert(ctx, pb)
-				if err != nil {
-					fmt.Printf("Warning: Failed to upsert PB for member %s, exercise %s: %v\n", key.memberID, key.exerciseID, err)
-				} else if isNewPB {
-					fmt.Printf("🎉 New PB! Member %s, Exercise %s: %.1f kg\n", key.memberID, key.exerciseID, val.weight)
-				}
-			}
+	// 3. The last session renews the package for members who opted in. A failure here is left
+	// to the renewal job rather than failing the completion.
+	if s.renewals != nil && contract.RemainingSessions == 0 {
+		if _, err := s.renewals.Renew(ctx, contract); err != nil {
+			fmt.Printf("Warning: failed to renew contract %s: %v\n", contract.ID, err)
 		}
 	}
 
+	// Derived data (volumes, personal bests) is computed by the workout event consumers
+	// once the session.completed event is recorded; see WorkoutService.RecordSessionCompleted.
 	return nil
 }
 
@@ -298,6 +769,11 @@ func (s *PTService) ListSchedules(ctx context.Context, tenantID string, filter m
 	return s.schedRepo.List(ctx, tenantID, filter)
 }
 
+// ListSchedulesPage returns one page of the tenant's schedules, newest first
+func (s *PTService) ListSchedulesPage(ctx context.Context, tenantID string, filter map[string]interface{}, q domain.PageQuery) (*domain.Page[*domain.Schedule], error) {
+	return s.schedRepo.ListPage(ctx, tenantID, filter, q)
+}
+
 func (s *PTService) GetSchedule(ctx context.Context, id string) (*domain.Schedule, error) {
 	schedule, err := s.schedRepo.GetByID(ctx, id)
 	if err != nil {
@@ -313,6 +789,41 @@ func (s *PTService) GetSchedule(ctx context.Context, id string) (*domain.Schedul
 	return schedule, nil
 }
 
+// TagSchedule replaces the tags, label and color of one of the coach's sessions
+func (s *PTService) TagSchedule(ctx context.Context, coachID, scheduleID string, tags []string, label, color string) (*domain.Schedule, error) {
+	tags, err := domain.NormalizeScheduleTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	label = strings.TrimSpace(label)
+	if err := domain.ValidateScheduleLabel(label, color); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.GetSchedule(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.DeletedAt != nil {
+		return nil, domain.ErrScheduleNotFound
+	}
+	if schedule.CoachID != coachID {
+		return nil, domain.ErrForbidden
+	}
+
+	schedule.Tags, schedule.Label, schedule.Color = tags, label, color
+	if err := s.schedRepo.Update(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// ScheduleTagReport counts the tenant's tagged sessions in [from, to) per tag and outcome,
+// for one coach or, with an empty coachID, all of them
+func (s *PTService) ScheduleTagReport(ctx context.Context, tenantID, coachID string, from, to time.Time) ([]domain.ScheduleTagCount, error) {
+	return s.schedRepo.CountByTag(ctx, tenantID, coachID, from, to)
+}
+
 func (s *PTService) DeleteSchedule(ctx context.Context, id string) error {
 	// Soft delete: preserve data but mark as deleted
 	// Note: We don't cascade delete set_logs or planned_exercises
@@ -320,8 +831,22 @@ func (s *PTService) DeleteSchedule(ctx context.Context, id string) error {
 	return s.schedRepo.SoftDelete(ctx, id)
 }
 
+// UpdateScheduleStatus sets a session's status. Cancelling a session tells the member.
 func (s *PTService) UpdateScheduleStatus(ctx context.Context, id string, status string) error {
-	return s.schedRepo.UpdateStatus(ctx, id, status)
+	change := func(ctx context.Context) error {
+		return s.schedRepo.UpdateStatus(ctx, id, status)
+	}
+	if s.outbox == nil || !strings.EqualFold(status, domain.ScheduleStatusCancelled) {
+		return change(ctx)
+	}
+	schedule, err := s.schedRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(schedule.Status, domain.ScheduleStatusCancelled) {
+		return change(ctx)
+	}
+	return s.recordChange(ctx, change, scheduleChangedMessage(schedule, domain.ScheduleChangeCancelled))
 }
 
 func (s *PTService) GetActiveScheduleCount(ctx context.Context, contractID string) (int64, error) {
@@ -0,0 +1,460 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// anyCtx matches the context repositories receive: services start a tracing span, so it is
+// derived from the test's ctx rather than equal to it
+const anyCtx = mock.Anything
+
+type ptServiceMocks struct {
+	pkgRepo      *mocks.PTPackageRepository
+	contractRepo *mocks.PTContractRepository
+	schedRepo    *mocks.ScheduleRepository
+	setLogRepo   *mocks.SetLogRepository
+	creditRepo   *mocks.CreditTransactionRepository
+	locker       *mocks.Locker
+}
+
+func newTestPTService(t *testing.T) (*PTService, *ptServiceMocks) {
+	m := &ptServiceMocks{
+		pkgRepo:      mocks.NewPTPackageRepository(t),
+		contractRepo: mocks.NewPTContractRepository(t),
+		schedRepo:    mocks.NewScheduleRepository(t),
+		setLogRepo:   mocks.NewSetLogRepository(t),
+		creditRepo:   mocks.NewCreditTransactionRepository(t),
+		locker:       mocks.NewLocker(t),
+	}
+	svc := NewPTService(m.pkgRepo, m.contractRepo, m.schedRepo, mocks.NewWorkoutSessionRepository(t), m.setLogRepo, m.creditRepo, nil, nil, m.locker, nil, nil, nil, clock.NewFake(testNow))
+	return svc, m
+}
+
+// expectLock lets the service take and release a lock on key
+func (m *ptServiceMocks) expectLock(key string) {
+	m.locker.On("Acquire", mock.Anything, key, ptLockTTL, ptLockWait).Return(func() {}, nil).Once()
+}
+
+// expectAppend makes the ledger accept the entry and report the given balance
+func (m *ptServiceMocks) expectAppend(creditType string, balance int, sequence int64) {
+	m.creditRepo.On("Append", mock.Anything, mock.MatchedBy(func(txn *domain.CreditTransaction) bool {
+		return txn.Type == creditType
+	})).Run(func(args mock.Arguments) {
+		txn := args.Get(1).(*domain.CreditTransaction)
+		txn.BalanceAfter = balance
+		txn.Sequence = sequence
+	}).Return(nil).Once()
+}
+
+func TestPTService_CreatePackageTemplate_RejectsInvalidTier(t *testing.T) {
+	svc, _ := newTestPTService(t)
+
+	err := svc.CreatePackageTemplate(context.Background(), &domain.PTPackage{TotalSessions: 12})
+
+	assert.Equal(t, domain.ErrInvalidSessionAmount, err)
+}
+
+func TestPTService_CreateContract(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("hydrates from template and records purchased credits", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		m.pkgRepo.On("GetByID", anyCtx, "pkg-1").Return(&domain.PTPackage{ID: "pkg-1", BranchID: "br-1", TotalSessions: 10, Price: domain.NewMoney(2500000, "IDR"), Active: true,
+			ValidityMonths: 3}, nil)
+		m.contractRepo.On("Create", anyCtx, mock.AnythingOfType("*domain.PTContract")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.PTContract).ID = "contract-1"
+		}).Return(nil)
+		m.expectLock("contract:contract-1")
+		m.creditRepo.On("Append", anyCtx, mock.MatchedBy(func(txn *domain.CreditTransaction) bool {
+			return txn.Type == domain.CreditTypePurchased && txn.Amount == 10 && txn.IdempotencyKey == "purchased:contract-1"
+		})).Run(func(args mock.Arguments) {
+			txn := args.Get(1).(*domain.CreditTransaction)
+			txn.BalanceAfter, txn.Sequence = 10, 1
+		}).Return(nil)
+		m.contractRepo.On("SyncBalance", anyCtx, "contract-1", 10, int64(1)).Return(nil)
+
+		contract := &domain.PTContract{PackageID: "pkg-1", BranchID: "br-1", MemberID: "member-1"}
+		require.NoError(t, svc.CreateContract(ctx, contract))
+
+		assert.Equal(t, 10, contract.TotalSessions)
+		assert.Equal(t, 10, contract.RemainingSessions)
+		assert.Equal(t, domain.NewMoney(2500000, "IDR"), contract.Price)
+		assert.Equal(t, domain.PackageStatusActive, contract.Status)
+		require.NotNil(t, contract.ExpiresAt)
+		assert.Equal(t, testNow.AddDate(0, 3, 0), *contract.ExpiresAt)
+	})
+
+	t.Run("charges the branch's own price", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		m.pkgRepo.On("GetByID", anyCtx, "pkg-1").Return(&domain.PTPackage{ID: "pkg-1", TotalSessions: 10, Price: domain.NewMoney(2500000, "IDR"), Active: true,
+			BranchPrices: []domain.BranchPrice{{BranchID: "br-2", Price: domain.NewMoney(3000000, "IDR")}}}, nil)
+		m.contractRepo.On("Create", anyCtx, mock.MatchedBy(func(c *domain.PTContract) bool {
+			return c.Price == domain.NewMoney(3000000, "IDR") && c.BranchPriced
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.PTContract).ID = "contract-1"
+		}).Return(nil)
+		m.expectLock("contract:contract-1")
+		m.expectAppend(domain.CreditTypePurchased, 10, 1)
+		m.contractRepo.On("SyncBalance", anyCtx, "contract-1", 10, int64(1)).Return(nil)
+
+		require.NoError(t, svc.CreateContract(ctx, &domain.PTContract{PackageID: "pkg-1", BranchID: "br-2", MemberID: "member-1"}))
+	})
+
+	t.Run("rejects branch mismatch", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		m.pkgRepo.On("GetByID", anyCtx, "pkg-1").Return(&domain.PTPackage{ID: "pkg-1", BranchID: "br-1", TotalSessions: 10, Active: true}, nil)
+
+		err := svc.CreateContract(ctx, &domain.PTContract{PackageID: "pkg-1", BranchID: "br-2"})
+
+		assert.Equal(t, domain.ErrBranchMismatch, err)
+	})
+
+	t.Run("rejects inactive template", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		m.pkgRepo.On("GetByID", anyCtx, "pkg-1").Return(&domain.PTPackage{ID: "pkg-1", TotalSessions: 10, Active: false}, nil)
+
+		assert.Error(t, svc.CreateContract(ctx, &domain.PTContract{PackageID: "pkg-1"}))
+	})
+}
+
+func TestPTService_CreateSchedule(t *testing.T) {
+	ctx := context.Background()
+	contract := &domain.PTContract{ID: "contract-1", MemberID: "member-1", BranchID: "br-1", RemainingSessions: 2, Status: domain.PackageStatusActive}
+	pending := []string{domain.ScheduleStatusScheduled, domain.ScheduleStatusPendingConfirmation}
+
+	tests := []struct {
+		name     string
+		contract domain.PTContract
+		pending  int64
+		schedule domain.Schedule
+		wantErr  error
+		creates  bool
+	}{
+		{
+			name:     "books within remaining credits",
+			contract: *contract,
+			pending:  1,
+			schedule: domain.Schedule{ContractID: "contract-1", MemberID: "member-1", BranchID: "br-1"},
+			creates:  true,
+		},
+		{
+			name:     "depleted contract",
+			contract: domain.PTContract{ID: "contract-1", Status: domain.PackageStatusDepleted},
+			schedule: domain.Schedule{ContractID: "contract-1"},
+			wantErr:  domain.ErrPackageDepleted,
+		},
+		{
+			name:     "branch mismatch",
+			contract: *contract,
+			schedule: domain.Schedule{ContractID: "contract-1", MemberID: "member-1", BranchID: "br-2"},
+			wantErr:  domain.ErrBranchMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestPTService(t)
+			c := tt.contract
+			m.contractRepo.On("GetByID", anyCtx, "contract-1").Return(&c, nil)
+			if c.Status == domain.PackageStatusActive {
+				m.schedRepo.On("CountByContractAndStatus", anyCtx, "contract-1", pending).Return(tt.pending, nil)
+			}
+			if tt.creates {
+				m.schedRepo.On("Create", anyCtx, mock.AnythingOfType("*domain.Schedule")).Return(nil)
+			}
+
+			schedule := tt.schedule
+			err := svc.CreateSchedule(ctx, &schedule)
+
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, domain.ScheduleStatusScheduled, schedule.Status)
+		})
+	}
+
+	t.Run("pending sessions use all remaining credits", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		m.contractRepo.On("GetByID", anyCtx, "contract-1").Return(contract, nil)
+		m.schedRepo.On("CountByContractAndStatus", anyCtx, "contract-1", pending).Return(int64(2), nil)
+
+		err := svc.CreateSchedule(ctx, &domain.Schedule{ContractID: "contract-1", MemberID: "member-1", BranchID: "br-1"})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestPTService_CompleteSession(t *testing.T) {
+	ctx := context.Background()
+	scheduled := func() *domain.Schedule {
+		return &domain.Schedule{ID: "sched-1", ContractID: "contract-1", CoachID: "coach-1", Status: domain.ScheduleStatusScheduled}
+	}
+	contract := func() *domain.PTContract {
+		return &domain.PTContract{ID: "contract-1", MemberID: "member-1", RemainingSessions: 5, Status: domain.PackageStatusActive}
+	}
+
+	t.Run("consumes a credit and completes the schedule", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		m.schedRepo.On("GetByID", anyCtx, "sched-1").Return(scheduled(), nil).Twice()
+		m.expectLock("schedule:sched-1")
+		m.contractRepo.On("GetByID", anyCtx, "contract-1").Return(contract(), nil)
+		m.creditRepo.On("GetLatest", anyCtx, "contract-1").Return(&domain.CreditTransaction{Sequence: 1, BalanceAfter: 5}, nil)
+		m.expectLock("contract:contract-1")
+		m.expectAppend(domain.CreditTypeConsumed, 4, 2)
+		m.contractRepo.On("SyncBalance", anyCtx, "contract-1", 4, int64(2)).Return(nil)
+		m.schedRepo.On("UpdateStatus", anyCtx, "sched-1", domain.ScheduleStatusCompleted).Return(nil)
+
+		require.NoError(t, svc.CompleteSession(ctx, "sched-1", "coach-1"))
+	})
+
+	t.Run("warns the member when the package runs low", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		outboxRepo, tx := mocks.NewOutboxRepository(t), mocks.NewTransactor(t)
+		svc.outbox = NewOutbox(outboxRepo, tx, nil)
+		m.schedRepo.On("GetByID", anyCtx, "sched-1").Return(scheduled(), nil).Twice()
+		m.expectLock("schedule:sched-1")
+		low := contract()
+		low.RemainingSessions = 3
+		m.contractRepo.On("GetByID", anyCtx, "contract-1").Return(low, nil)
+		m.creditRepo.On("GetLatest", anyCtx, "contract-1").Return(&domain.CreditTransaction{Sequence: 1, BalanceAfter: 3}, nil)
+		m.expectLock("contract:contract-1")
+		m.expectAppend(domain.CreditTypeConsumed, 2, 2)
+		m.contractRepo.On("SyncBalance", anyCtx, "contract-1", 2, int64(2)).Return(nil)
+		tx.On("WithinTransaction", anyCtx, mock.Anything).Return(runInline).Once()
+		m.schedRepo.On("UpdateStatus", anyCtx, "sched-1", domain.ScheduleStatusCompleted).Return(nil)
+		outboxRepo.On("Add", anyCtx, mock.MatchedBy(func(msg *domain.OutboxMessage) bool {
+			return msg.Topic == domain.OutboxTopicContractLowSessions && msg.Key == "contract-1" && msg.Payload["remaining"] == "2"
+		})).Return(nil).Once()
+
+		require.NoError(t, svc.CompleteSession(ctx, "sched-1", "coach-1"))
+	})
+
+	t.Run("rejects another coach", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		m.schedRepo.On("GetByID", anyCtx, "sched-1").Return(scheduled(), nil).Once()
+
+		assert.Equal(t, domain.ErrForbidden, svc.CompleteSession(ctx, "sched-1", "coach-2"))
+	})
+
+	t.Run("already completed", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		done := scheduled()
+		done.Status = domain.ScheduleStatusCompleted
+		m.schedRepo.On("GetByID", anyCtx, "sched-1").Return(done, nil).Twice()
+		m.expectLock("schedule:sched-1")
+
+		assert.Error(t, svc.CompleteSession(ctx, "sched-1", "coach-1"))
+	})
+
+	t.Run("maps insufficient credits to depleted package", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		m.schedRepo.On("GetByID", anyCtx, "sched-1").Return(scheduled(), nil).Twice()
+		m.expectLock("schedule:sched-1")
+		m.contractRepo.On("GetByID", anyCtx, "contract-1").Return(contract(), nil)
+		m.creditRepo.On("GetLatest", anyCtx, "contract-1").Return(&domain.CreditTransaction{Sequence: 3, BalanceAfter: 0}, nil)
+		m.expectLock("contract:contract-1")
+		m.creditRepo.On("Append", anyCtx, mock.AnythingOfType("*domain.CreditTransaction")).Return(domain.ErrInsufficientCredits)
+
+		assert.Equal(t, domain.ErrPackageDepleted, svc.CompleteSession(ctx, "sched-1", "coach-1"))
+	})
+
+	t.Run("surfaces lock contention", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		m.schedRepo.On("GetByID", anyCtx, "sched-1").Return(scheduled(), nil).Once()
+		m.locker.On("Acquire", anyCtx, "schedule:sched-1", ptLockTTL, ptLockWait).Return(nil, domain.ErrLockNotAcquired)
+
+		assert.Equal(t, domain.ErrLockNotAcquired, svc.CompleteSession(ctx, "sched-1", "coach-1"))
+	})
+}
+
+func TestPTService_AdjustCredits(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("validates type and amount before touching the ledger", func(t *testing.T) {
+		svc, _ := newTestPTService(t)
+
+		_, err := svc.AdjustCredits(ctx, "contract-1", domain.CreditTypeConsumed, 1, "", "admin-1")
+		assert.Equal(t, domain.ErrInvalidCreditType, err)
+
+		_, err = svc.AdjustCredits(ctx, "contract-1", domain.CreditTypeRefunded, 0, "", "admin-1")
+		assert.Equal(t, domain.ErrInvalidCreditAmount, err)
+
+		_, err = svc.AdjustCredits(ctx, "contract-1", domain.CreditTypeAdjusted, 0, "", "admin-1")
+		assert.Equal(t, domain.ErrInvalidCreditAmount, err)
+	})
+
+	t.Run("opens the ledger for legacy contracts before applying the movement", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		m.contractRepo.On("GetByID", anyCtx, "contract-1").Return(&domain.PTContract{ID: "contract-1", RemainingSessions: 3}, nil)
+		m.creditRepo.On("GetLatest", anyCtx, "contract-1").Return(nil, nil)
+		m.locker.On("Acquire", anyCtx, "contract:contract-1", ptLockTTL, ptLockWait).Return(func() {}, nil).Twice()
+		m.expectAppend(domain.CreditTypeOpening, 3, 1)
+		m.contractRepo.On("SyncBalance", anyCtx, "contract-1", 3, int64(1)).Return(nil)
+		m.creditRepo.On("Append", anyCtx, mock.MatchedBy(func(txn *domain.CreditTransaction) bool {
+			return txn.Type == domain.CreditTypeFrozen && txn.Amount == -2 && txn.ActorID == "admin-1"
+		})).Run(func(args mock.Arguments) {
+			txn := args.Get(1).(*domain.CreditTransaction)
+			txn.BalanceAfter, txn.Sequence = 1, 2
+		}).Return(nil)
+		m.contractRepo.On("SyncBalance", anyCtx, "contract-1", 1, int64(2)).Return(errors.New("mongo unavailable"))
+
+		txn, err := svc.AdjustCredits(ctx, "contract-1", domain.CreditTypeFrozen, 2, "Holiday", "admin-1")
+
+		require.NoError(t, err, "a failed balance projection must not fail the ledger entry")
+		assert.Equal(t, 1, txn.BalanceAfter)
+	})
+}
+
+func TestPTService_CreditPaidPackage(t *testing.T) {
+	ctx := context.Background()
+	template := &domain.PTPackage{ID: "pt-10", TotalSessions: 10}
+
+	t.Run("tops up the member's newest usable contract on the package", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		m.pkgRepo.On("GetByID", anyCtx, "pt-10").Return(template, nil)
+		m.contractRepo.On("GetByMemberAndPackage", anyCtx, "member-1", "pt-10").Return([]*domain.PTContract{
+			{ID: "k3", PackageID: "pt-10", Status: domain.PackageStatusExpired},
+			{ID: "k2", PackageID: "pt-10", Status: domain.PackageStatusDepleted},
+			{ID: "k1", PackageID: "pt-10", Status: domain.PackageStatusActive, RemainingSessions: 2},
+		}, nil)
+		m.creditRepo.On("GetLatest", anyCtx, "k2").Return(&domain.CreditTransaction{BalanceAfter: 0, Sequence: 4}, nil)
+		m.expectLock("contract:k2")
+		m.creditRepo.On("Append", anyCtx, mock.MatchedBy(func(txn *domain.CreditTransaction) bool {
+			return txn.Type == domain.CreditTypePurchased && txn.Amount == 10 && txn.IdempotencyKey == "purchased:invoice:inv-1"
+		})).Run(func(args mock.Arguments) {
+			txn := args.Get(1).(*domain.CreditTransaction)
+			txn.BalanceAfter, txn.Sequence = 10, 5
+		}).Return(nil)
+		m.contractRepo.On("SyncBalance", anyCtx, "k2", 10, int64(5)).Return(nil)
+
+		contract, err := svc.CreditPaidPackage(ctx, "member-1", "pt-10", "inv-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "k2", contract.ID)
+		assert.Equal(t, 10, contract.RemainingSessions)
+	})
+
+	t.Run("a retried settlement credits nothing more", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		m.pkgRepo.On("GetByID", anyCtx, "pt-10").Return(template, nil)
+		m.contractRepo.On("GetByMemberAndPackage", anyCtx, "member-1", "pt-10").Return([]*domain.PTContract{
+			{ID: "k1", PackageID: "pt-10", Status: domain.PackageStatusActive, RemainingSessions: 12},
+		}, nil)
+		m.creditRepo.On("GetLatest", anyCtx, "k1").Return(&domain.CreditTransaction{BalanceAfter: 12, Sequence: 5}, nil)
+		m.expectLock("contract:k1")
+		m.creditRepo.On("Append", anyCtx, mock.Anything).Return(domain.ErrDuplicateCredit)
+
+		contract, err := svc.CreditPaidPackage(ctx, "member-1", "pt-10", "inv-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, 12, contract.RemainingSessions)
+	})
+
+	t.Run("without a contract to credit", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		m.pkgRepo.On("GetByID", anyCtx, "pt-10").Return(template, nil)
+		m.contractRepo.On("GetByMemberAndPackage", anyCtx, "member-1", "pt-10").Return([]*domain.PTContract{
+			{ID: "k1", PackageID: "pt-10", Status: domain.PackageStatusExpired},
+		}, nil)
+
+		_, err := svc.CreditPaidPackage(ctx, "member-1", "pt-10", "inv-1")
+
+		assert.ErrorIs(t, err, domain.ErrContractNotFound)
+	})
+}
+
+func TestPTService_TagSchedule(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("replaces tags, label and color", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		m.schedRepo.On("GetByID", anyCtx, "sched-1").Return(&domain.Schedule{ID: "sched-1", CoachID: "coach-1", Tags: []string{"online"}}, nil)
+		m.schedRepo.On("Update", anyCtx, mock.MatchedBy(func(s *domain.Schedule) bool {
+			return len(s.Tags) == 2 && s.Tags[0] == "assessment" && s.Color == "#F97316"
+		})).Return(nil)
+
+		schedule, err := svc.TagSchedule(ctx, "coach-1", "sched-1", []string{"Assessment", "trial"}, " First visit ", "#F97316")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"assessment", "trial"}, schedule.Tags)
+		assert.Equal(t, "First visit", schedule.Label)
+	})
+
+	t.Run("another coach's session", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		m.schedRepo.On("GetByID", anyCtx, "sched-1").Return(&domain.Schedule{ID: "sched-1", CoachID: "coach-2"}, nil)
+
+		_, err := svc.TagSchedule(ctx, "coach-1", "sched-1", []string{"trial"}, "", "")
+
+		assert.Equal(t, domain.ErrForbidden, err)
+	})
+
+	t.Run("invalid color", func(t *testing.T) {
+		svc, _ := newTestPTService(t)
+
+		_, err := svc.TagSchedule(ctx, "coach-1", "sched-1", nil, "", "orange")
+
+		assert.Equal(t, domain.ErrInvalidScheduleLabel, err)
+	})
+}
+
+func TestPTService_SetMetricVisibility(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("hides metrics on the coach's contract", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		m.contractRepo.On("GetByID", anyCtx, "contract-1").Return(&domain.PTContract{ID: "contract-1", CoachID: "coach-1"}, nil)
+		m.contractRepo.On("SetMetricVisibility", anyCtx, "contract-1", &domain.MetricVisibility{HideBodyFat: true}).Return(nil)
+
+		contract, err := svc.SetMetricVisibility(ctx, "coach-1", "contract-1", &domain.MetricVisibility{HideBodyFat: true})
+
+		require.NoError(t, err)
+		assert.True(t, contract.MetricVisibility.HideBodyFat)
+	})
+
+	t.Run("nothing hidden clears the setting", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		m.contractRepo.On("GetByID", anyCtx, "contract-1").Return(&domain.PTContract{ID: "contract-1", CoachID: "coach-1",
+			MetricVisibility: &domain.MetricVisibility{HideWeight: true}}, nil)
+		m.contractRepo.On("SetMetricVisibility", anyCtx, "contract-1", (*domain.MetricVisibility)(nil)).Return(nil)
+
+		contract, err := svc.SetMetricVisibility(ctx, "coach-1", "contract-1", &domain.MetricVisibility{})
+
+		require.NoError(t, err)
+		assert.Nil(t, contract.MetricVisibility)
+	})
+
+	t.Run("another coach's contract", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		m.contractRepo.On("GetByID", anyCtx, "contract-1").Return(&domain.PTContract{ID: "contract-1", CoachID: "coach-2"}, nil)
+
+		_, err := svc.SetMetricVisibility(ctx, "coach-1", "contract-1", &domain.MetricVisibility{VolumeOnly: true})
+
+		assert.Equal(t, domain.ErrForbidden, err)
+	})
+}
+
+func TestPTService_MemberMetricVisibility(t *testing.T) {
+	svc, m := newTestPTService(t)
+	m.contractRepo.On("GetActiveByMember", anyCtx, "member-1").Return([]*domain.PTContract{
+		{ID: "contract-1", MetricVisibility: &domain.MetricVisibility{HideBodyFat: true}},
+		{ID: "contract-2"},
+		{ID: "contract-3", MetricVisibility: &domain.MetricVisibility{HideWeight: true}},
+	}, nil)
+
+	visibility, err := svc.MemberMetricVisibility(context.Background(), "member-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.MetricVisibility{HideBodyFat: true, HideWeight: true}, visibility)
+}
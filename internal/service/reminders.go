@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// reminderStatuses are the sessions still worth reminding about
+var reminderStatuses = []string{domain.ScheduleStatusScheduled, domain.ScheduleStatusPendingConfirmation}
+
+// ReminderService sends session reminders at the lead times each tenant configured,
+// skipping members who opted out
+type ReminderService struct {
+	schedRepo domain.ScheduleRepository
+	prefs     domain.NotificationPreferencesRepository
+	notifier  *NotificationService
+}
+
+func NewReminderService(schedRepo domain.ScheduleRepository, prefs domain.NotificationPreferencesRepository, notifier *NotificationService) *ReminderService {
+	return &ReminderService{
+		schedRepo: schedRepo,
+		prefs:     prefs,
+		notifier:  notifier,
+	}
+}
+
+// SendReminders sends every reminder whose send time falls in [from, to) and returns how
+// many were sent. Consecutive windows never overlap, so each reminder goes out once.
+func (s *ReminderService) SendReminders(ctx context.Context, from, to time.Time) (int, error) {
+	configured, err := s.prefs.ListTenants(ctx)
+	if err != nil {
+		return 0, err
+	}
+	tenantLeads := make(map[string][]int, len(configured))
+	leads := slices.Clone(domain.DefaultReminderLeadMinutes)
+	for _, settings := range configured {
+		tenantLeads[settings.TenantID] = settings.ReminderLeadMinutes
+		for _, m := range settings.ReminderLeadMinutes {
+			if !slices.Contains(leads, m) {
+				leads = append(leads, m)
+			}
+		}
+	}
+
+	optedOut := make(map[string]bool)
+	sent := 0
+	for _, lead := range leads {
+		offset := time.Duration(lead) * time.Minute
+		schedules, err := s.schedRepo.ListStartingBetween(ctx, from.Add(offset), to.Add(offset), reminderStatuses)
+		if err != nil {
+			return sent, fmt.Errorf("failed to list sessions starting in %d minutes: %w", lead, err)
+		}
+
+		for _, sched := range schedules {
+			want, ok := tenantLeads[sched.TenantID]
+			if !ok {
+				want = domain.DefaultReminderLeadMinutes
+			}
+			if !slices.Contains(want, lead) {
+				continue
+			}
+
+			out, ok := optedOut[sched.MemberID]
+			if !ok {
+				prefs, err := s.prefs.GetUser(ctx, sched.MemberID)
+				if err != nil {
+					return sent, err
+				}
+				out = prefs.RemindersOptOut
+				optedOut[sched.MemberID] = out
+			}
+			if out {
+				continue
+			}
+
+			// A failed delivery shouldn't hold back everyone else's reminders
+			if err := s.notifier.Notify(ctx, reminderFor(sched, lead)); err != nil {
+				log.Printf("Warning: reminder for schedule %s not sent: %v", sched.ID, err)
+				continue
+			}
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+func reminderFor(sched *domain.Schedule, lead int) *domain.Notification {
+	body := "Your PT session starts in " + leadText(lead)
+	if sched.SessionGoal != "" {
+		body += ": " + sched.SessionGoal
+	}
+	data := map[string]string{
+		"schedule_id": sched.ID,
+		"start_time":  sched.StartTime.UTC().Format(time.RFC3339),
+	}
+	if sched.Confirmation == nil {
+		// The app shows confirm and decline buttons that post {"status": ...} here
+		data["actions"] = domain.ConfirmationConfirmed + "," + domain.ConfirmationDeclined
+		data["action_path"] = fmt.Sprintf(confirmPath, sched.ID)
+	}
+	if sched.IsOnline() {
+		body += ". It's online"
+		if sched.MeetingURL != "" {
+			body += ", join at " + sched.MeetingURL
+			data["meeting_url"] = sched.MeetingURL
+		}
+	}
+	return &domain.Notification{
+		UserID:   sched.MemberID,
+		TenantID: sched.TenantID,
+		Type:     domain.NotificationScheduleReminder,
+		Title:    "Upcoming session",
+		Body:     body,
+		Data:     data,
+		Link:     &domain.DeepLink{Screen: domain.ScreenSchedule, ScheduleID: sched.ID},
+	}
+}
+
+// leadText renders a lead time the way a person would say it, e.g. "1 hour" or "2 days"
+func leadText(minutes int) string {
+	unit, n := "minute", minutes
+	switch {
+	case minutes%(24*60) == 0:
+		unit, n = "day", minutes/(24*60)
+	case minutes%60 == 0:
+		unit, n = "hour", minutes/60
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s", n, unit)
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminderService_SendReminders(t *testing.T) {
+	ctx := context.Background()
+	from, to := testNow, testNow.Add(5*time.Minute)
+
+	schedRepo := mocks.NewScheduleRepository(t)
+	prefs := mocks.NewNotificationPreferencesRepository(t)
+	push := mocks.NewNotificationSender(t)
+	push.On("Channel").Return(domain.ChannelPush)
+	svc := NewReminderService(schedRepo, prefs, NewNotificationService(prefs, nil, clock.NewFake(testNow), push))
+
+	// gym-a only wants a 2-hour reminder; gym-b never configured any and gets the defaults
+	prefs.On("ListTenants", ctx).Return([]*domain.TenantNotificationSettings{
+		{TenantID: "gym-a", ReminderLeadMinutes: []int{120}},
+	}, nil)
+
+	inOneHour := []*domain.Schedule{
+		{ID: "a-1h", TenantID: "gym-a", MemberID: "m1", StartTime: from.Add(time.Hour)},
+		{ID: "b-1h", TenantID: "gym-b", MemberID: "m2", StartTime: from.Add(time.Hour)},
+		{ID: "b-1h-optout", TenantID: "gym-b", MemberID: "m3", StartTime: from.Add(time.Hour)},
+	}
+	inTwoHours := []*domain.Schedule{
+		{ID: "a-2h", TenantID: "gym-a", MemberID: "m1", StartTime: from.Add(2 * time.Hour)},
+		{ID: "b-2h", TenantID: "gym-b", MemberID: "m2", StartTime: from.Add(2 * time.Hour)},
+	}
+	schedRepo.On("ListStartingBetween", ctx, from.Add(24*time.Hour), to.Add(24*time.Hour), reminderStatuses).Return(nil, nil)
+	schedRepo.On("ListStartingBetween", ctx, from.Add(time.Hour), to.Add(time.Hour), reminderStatuses).Return(inOneHour, nil)
+	schedRepo.On("ListStartingBetween", ctx, from.Add(2*time.Hour), to.Add(2*time.Hour), reminderStatuses).Return(inTwoHours, nil)
+
+	prefs.On("GetUser", ctx, "m1").Return(&domain.NotificationPreferences{UserID: "m1"}, nil)
+	prefs.On("GetUser", ctx, "m2").Return(&domain.NotificationPreferences{UserID: "m2"}, nil)
+	prefs.On("GetUser", ctx, "m3").Return(&domain.NotificationPreferences{UserID: "m3", RemindersOptOut: true}, nil)
+
+	var reminded []string
+	push.On("Send", ctx, mock.AnythingOfType("*domain.Notification")).Run(func(args mock.Arguments) {
+		reminded = append(reminded, args.Get(1).(*domain.Notification).Data["schedule_id"])
+	}).Return(nil)
+
+	sent, err := svc.SendReminders(ctx, from, to)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.ElementsMatch(t, []string{"b-1h", "a-2h"}, reminded)
+}
+
+func TestLeadText(t *testing.T) {
+	assert.Equal(t, "1 day", leadText(24*60))
+	assert.Equal(t, "2 hours", leadText(120))
+	assert.Equal(t, "90 minutes", leadText(90))
+}
+
+func TestReminderFor_Online(t *testing.T) {
+	n := reminderFor(&domain.Schedule{ID: "s-1", MemberID: "m1", StartTime: testNow, Modality: domain.SessionModalityOnline, MeetingURL: "https://zoom.us/j/123"}, 60)
+
+	assert.Equal(t, "Your PT session starts in 1 hour. It's online, join at https://zoom.us/j/123", n.Body)
+	assert.Equal(t, "https://zoom.us/j/123", n.Data["meeting_url"])
+
+	n = reminderFor(&domain.Schedule{ID: "s-2", MemberID: "m1", StartTime: testNow}, 60)
+	assert.NotContains(t, n.Data, "meeting_url")
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// RenewalService renews the contracts of members who opted in to auto-renewal. When a
+// contract's sessions run out or expire, the same package is bought again as a contract
+// waiting for payment, with an invoice whose VA is issued at the member's chosen bank. Paying
+// the invoice credits the sessions; see InstallmentService.ActivateRenewals.
+type RenewalService struct {
+	contractRepo domain.PTContractRepository
+	invoiceRepo  domain.InvoiceRepository
+	ptService    *PTService
+	provider     PaymentProvider
+	sandbox      *SandboxService
+	notifier     *NotificationService
+	timeline     *TimelineService // Optional: see ShowOnTimeline
+	clock        domain.Clock
+}
+
+func NewRenewalService(
+	contractRepo domain.PTContractRepository,
+	invoiceRepo domain.InvoiceRepository,
+	ptService *PTService,
+	provider PaymentProvider,
+	sandbox *SandboxService,
+	notifier *NotificationService,
+	clk domain.Clock,
+) *RenewalService {
+	return &RenewalService{
+		contractRepo: contractRepo,
+		invoiceRepo:  invoiceRepo,
+		ptService:    ptService,
+		provider:     provider,
+		sandbox:      sandbox,
+		notifier:     notifier,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// ShowOnTimeline records every renewal on the member's timeline
+func (s *RenewalService) ShowOnTimeline(timeline *TimelineService) {
+	s.timeline = timeline
+}
+
+// SetAutoRenew turns auto-renewal of one of the member's contracts on, paying by VA at
+// method, or off. A contract that is already due renews right away.
+func (s *RenewalService) SetAutoRenew(ctx context.Context, memberID, contractID string, enabled bool, method string) (*domain.PTContract, error) {
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	if contract.MemberID != memberID {
+		return nil, domain.ErrContractNotFound
+	}
+	if contract.RenewedAt != nil || contract.Status == domain.PackageStatusPendingPayment {
+		return nil, domain.ErrContractRenewed
+	}
+
+	var renewal *domain.AutoRenewal
+	if enabled {
+		if !domain.ValidPaymentMethod(method) {
+			return nil, domain.ErrInvalidPaymentMethod
+		}
+		renewal = &domain.AutoRenewal{PaymentMethod: method, EnabledAt: s.clock.Now()}
+	}
+	if err := s.contractRepo.SetAutoRenew(ctx, contract.ID, renewal); err != nil {
+		return nil, err
+	}
+	contract.AutoRenew = renewal
+
+	if _, err := s.Renew(ctx, contract); err != nil {
+		log.Printf("Warning: contract %s set to renew but not renewed yet: %v", contract.ID, err)
+	}
+	return contract, nil
+}
+
+// Renew buys the contract's package again if the contract is due to renew, returning the
+// renewal waiting for payment, or nil if it wasn't due or another run renewed it. The
+// renewal carries the opt-in forward, so it renews in turn.
+func (s *RenewalService) Renew(ctx context.Context, contract *domain.PTContract) (*domain.PTContract, error) {
+	now := s.clock.Now()
+	reason := contract.RenewalDue(now)
+	if reason == "" {
+		return nil, nil
+	}
+	claimed, err := s.contractRepo.ClaimRenewal(ctx, contract.ID, now)
+	if err != nil || !claimed {
+		return nil, err
+	}
+
+	renewal := &domain.PTContract{
+		TenantID:     contract.TenantID,
+		BranchID:     contract.BranchID,
+		PackageID:    contract.PackageID,
+		MemberID:     contract.MemberID,
+		CoachID:      contract.CoachID,
+		AutoRenew:    contract.AutoRenew,
+		RenewalOf:    contract.ID,
+		CustomFields: contract.CustomFields,
+	}
+	if err := s.ptService.CreatePendingContract(ctx, renewal); err != nil {
+		if releaseErr := s.contractRepo.ReleaseRenewal(ctx, contract.ID); releaseErr != nil {
+			log.Printf("Warning: renewal of contract %s failed and stays claimed: %v", contract.ID, releaseErr)
+		}
+		return nil, err
+	}
+	contract.RenewedAt = &now
+
+	invoice := &domain.Invoice{
+		UserID:        renewal.MemberID,
+		TenantID:      renewal.TenantID,
+		Amount:        renewal.Price,
+		Status:        domain.InvoiceStatusPending,
+		PaymentMethod: contract.AutoRenew.PaymentMethod,
+		ContractID:    renewal.ID,
+		BranchID:      renewal.BranchID,
+		BranchPriced:  renewal.BranchPriced,
+	}
+	// Without a VA the invoice can still be paid at the front desk
+	if err := s.issueVA(ctx, invoice); err != nil {
+		log.Printf("Warning: no VA issued for the renewal of contract %s: %v", contract.ID, err)
+	}
+	if err := s.invoiceRepo.Create(ctx, invoice); err != nil {
+		return renewal, fmt.Errorf("contract %s renewed as %s but its invoice wasn't created: %w", contract.ID, renewal.ID, err)
+	}
+	log.Printf("[Renewals] Contract %s %s, renewed as %s (invoice %s)", contract.ID, reason, renewal.ID, invoice.ID)
+
+	s.notify(ctx, renewal, invoice, reason)
+	if s.timeline != nil {
+		if err := s.timeline.RecordRenewal(ctx, renewal); err != nil {
+			log.Printf("Warning: renewal of contract %s not recorded on the timeline: %v", contract.ID, err)
+		}
+	}
+	return renewal, nil
+}
+
+// RenewDue renews every contract set to auto-renew whose sessions ran out or expired and
+// returns how many renewed. Contracts emptied by a completed session usually renewed on the
+// spot; this picks up expiries and anything that failed then.
+func (s *RenewalService) RenewDue(ctx context.Context) (int, error) {
+	contracts, err := s.contractRepo.ListRenewalsDue(ctx, s.clock.Now())
+	if err != nil {
+		return 0, err
+	}
+	renewed := 0
+	for _, contract := range contracts {
+		renewal, err := s.Renew(ctx, contract)
+		if err != nil {
+			return renewed, fmt.Errorf("failed to renew contract %s: %w", contract.ID, err)
+		}
+		if renewal != nil {
+			renewed++
+		}
+	}
+	return renewed, nil
+}
+
+// Activate credits the sessions of the renewal a paid invoice is for. Invoices of other
+// contracts are left alone.
+func (s *RenewalService) Activate(ctx context.Context, invoice *domain.Invoice) error {
+	if invoice.ContractID == "" || invoice.Status != domain.InvoiceStatusPaid {
+		return nil
+	}
+	contract, err := s.ptService.ActivateContract(ctx, invoice.ContractID)
+	if err != nil {
+		return fmt.Errorf("failed to activate contract %s: %w", invoice.ContractID, err)
+	}
+	if contract.RenewalOf != "" {
+		log.Printf("[Renewals] Contract %s paid and active", contract.ID)
+	}
+	return nil
+}
+
+// issueVA asks the payment provider for a VA at the invoice's bank for its amount
+func (s *RenewalService) issueVA(ctx context.Context, invoice *domain.Invoice) error {
+	provider, err := s.sandbox.PaymentProvider(ctx, invoice.TenantID, s.provider)
+	if err != nil {
+		return err
+	}
+	va, err := provider.GenerateVA(ctx, invoice.PaymentMethod, invoice.Amount.Minor, invoice.UserID)
+	if err != nil {
+		return err
+	}
+	invoice.VANumber = va.VANumber
+	invoice.PaymentSessionID = va.SessionID
+	invoice.ExpiryDate = va.ExpiresAt
+	return nil
+}
+
+// notify tells the member the package renewed and how to pay, logging failures: the renewal
+// is already made, so failing it wouldn't send the notification again
+func (s *RenewalService) notify(ctx context.Context, renewal *domain.PTContract, invoice *domain.Invoice, reason string) {
+	why := "Your PT sessions are used up"
+	if reason == domain.RenewalReasonExpired {
+		why = "Your PT package expired"
+	}
+	body := fmt.Sprintf("%s, so it renewed for %d sessions. Pay %s to start booking them.", why, renewal.TotalSessions, renewal.Price)
+	if invoice.VANumber != "" {
+		body = fmt.Sprintf("%s, so it renewed for %d sessions. Pay %s to %s VA %s to start booking them.",
+			why, renewal.TotalSessions, renewal.Price, invoice.PaymentMethod, invoice.VANumber)
+	}
+	err := s.notifier.Notify(ctx, &domain.Notification{
+		UserID:   renewal.MemberID,
+		TenantID: renewal.TenantID,
+		Type:     domain.NotificationContractRenewed,
+		Title:    "PT package renewed",
+		Body:     body,
+		Data:     map[string]string{"contract_id": renewal.ID, "invoice_id": invoice.ID, "renewal_of": renewal.RenewalOf},
+		Link:     &domain.DeepLink{Screen: domain.ScreenContract, ContractID: renewal.ID},
+	})
+	if err != nil {
+		log.Printf("Warning: renewal notification for contract %s not sent: %v", renewal.ID, err)
+	}
+}
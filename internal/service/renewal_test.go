@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type renewalMocks struct {
+	*ptServiceMocks
+	invoices *mocks.InvoiceRepository
+	push     *mocks.NotificationSender
+}
+
+func newTestRenewalService(t *testing.T) (*RenewalService, *renewalMocks) {
+	ptService, pm := newTestPTService(t)
+	m := &renewalMocks{ptServiceMocks: pm, invoices: mocks.NewInvoiceRepository(t), push: mocks.NewNotificationSender(t)}
+
+	tenants := mocks.NewTenantRepository(t)
+	tenants.On("GetByID", mock.Anything, mock.Anything).Return(&domain.Tenant{}, nil).Maybe()
+	prefs := mocks.NewNotificationPreferencesRepository(t)
+	prefs.On("GetUser", mock.Anything, mock.Anything).Return(&domain.NotificationPreferences{}, nil).Maybe()
+	m.push.On("Channel").Return(domain.ChannelPush).Maybe()
+
+	svc := NewRenewalService(m.contractRepo, m.invoices, ptService, NewMockPaymentProvider(clock.NewFake(testNow)),
+		NewSandboxService(tenants, nil, nil, clock.NewFake(testNow)),
+		NewNotificationService(prefs, nil, clock.NewFake(testNow), m.push), clock.NewFake(testNow))
+	return svc, m
+}
+
+// renewable is a contract of member-1 set to renew by BCA VA, with the given sessions left
+func renewable(remaining int) *domain.PTContract {
+	return &domain.PTContract{ID: "k1", TenantID: "gym", BranchID: "b1", PackageID: "p1", MemberID: "member-1", CoachID: "coach-1",
+		Status: domain.PackageStatusActive, RemainingSessions: remaining,
+		AutoRenew: &domain.AutoRenewal{PaymentMethod: "BCA", EnabledAt: testNow.AddDate(0, -1, 0)}}
+}
+
+// expectRenewal lets k1 renew as k2: a pending contract of the 10-session package and an
+// invoice for it
+func (m *renewalMocks) expectRenewal() {
+	m.contractRepo.On("ClaimRenewal", anyCtx, "k1", testNow).Return(true, nil).Once()
+	m.pkgRepo.On("GetByID", anyCtx, "p1").Return(&domain.PTPackage{ID: "p1", TotalSessions: 10,
+		Price: domain.NewMoney(5_000_000, "IDR"), Active: true, ValidityMonths: 3}, nil).Once()
+	m.contractRepo.On("Create", anyCtx, mock.MatchedBy(func(c *domain.PTContract) bool {
+		return c.Status == domain.PackageStatusPendingPayment && c.RenewalOf == "k1" && c.RemainingSessions == 0 &&
+			c.TotalSessions == 10 && c.ExpiresAt == nil && c.AutoRenew != nil
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.PTContract).ID = "k2"
+	}).Return(nil).Once()
+	m.invoices.On("Create", anyCtx, mock.MatchedBy(func(inv *domain.Invoice) bool {
+		return inv.ContractID == "k2" && inv.UserID == "member-1" && inv.Status == domain.InvoiceStatusPending &&
+			inv.Amount.Minor == 5_000_000 && inv.PaymentMethod == "BCA" && strings.HasPrefix(inv.VANumber, "8888-MOCK-BCA-")
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Invoice).ID = "inv-2"
+	}).Return(nil).Once()
+}
+
+func TestRenewalService_Renew(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("a used up contract is bought again and the member told how to pay", func(t *testing.T) {
+		svc, m := newTestRenewalService(t)
+		m.expectRenewal()
+		m.push.On("Send", anyCtx, mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.Type == domain.NotificationContractRenewed && n.UserID == "member-1" && n.Link.ContractID == "k2" &&
+				strings.HasPrefix(n.Body, "Your PT sessions are used up, so it renewed for 10 sessions. Pay IDR 5000000 to BCA VA 8888-MOCK-BCA-")
+		})).Return(nil).Once()
+		contract := renewable(0)
+
+		renewal, err := svc.Renew(ctx, contract)
+
+		require.NoError(t, err)
+		require.NotNil(t, renewal)
+		assert.Equal(t, "k2", renewal.ID)
+		assert.Equal(t, &testNow, contract.RenewedAt)
+	})
+
+	t.Run("contracts not due are left alone", func(t *testing.T) {
+		svc, _ := newTestRenewalService(t)
+		optedOut := renewable(0)
+		optedOut.AutoRenew = nil
+		renewed := renewable(0)
+		renewed.RenewedAt = &testNow
+
+		for _, contract := range []*domain.PTContract{renewable(2), optedOut, renewed} {
+			renewal, err := svc.Renew(ctx, contract)
+			require.NoError(t, err)
+			assert.Nil(t, renewal)
+		}
+	})
+
+	t.Run("a contract another run renewed is skipped", func(t *testing.T) {
+		svc, m := newTestRenewalService(t)
+		m.contractRepo.On("ClaimRenewal", anyCtx, "k1", testNow).Return(false, nil).Once()
+
+		renewal, err := svc.Renew(ctx, renewable(0))
+
+		require.NoError(t, err)
+		assert.Nil(t, renewal)
+	})
+
+	t.Run("the claim is released when the package can't be sold again", func(t *testing.T) {
+		svc, m := newTestRenewalService(t)
+		m.contractRepo.On("ClaimRenewal", anyCtx, "k1", testNow).Return(true, nil).Once()
+		m.pkgRepo.On("GetByID", anyCtx, "p1").Return(&domain.PTPackage{ID: "p1", Active: false}, nil).Once()
+		m.contractRepo.On("ReleaseRenewal", anyCtx, "k1").Return(nil).Once()
+
+		_, err := svc.Renew(ctx, renewable(0))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestRenewalService_RenewDue(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestRenewalService(t)
+	expired := renewable(2)
+	expiredAt := testNow.AddDate(0, 0, -1)
+	expired.ExpiresAt, expired.Status = &expiredAt, domain.PackageStatusExpired
+	m.contractRepo.On("ListRenewalsDue", ctx, testNow).Return([]*domain.PTContract{expired}, nil)
+	m.expectRenewal()
+	m.push.On("Send", anyCtx, mock.MatchedBy(func(n *domain.Notification) bool {
+		return strings.HasPrefix(n.Body, "Your PT package expired, so it renewed")
+	})).Return(nil).Once()
+
+	renewed, err := svc.RenewDue(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, renewed)
+}
+
+func TestRenewalService_SetAutoRenew(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stores the opt-in with the bank", func(t *testing.T) {
+		svc, m := newTestRenewalService(t)
+		contract := renewable(5)
+		contract.AutoRenew = nil
+		m.contractRepo.On("GetByID", ctx, "k1").Return(contract, nil)
+		want := &domain.AutoRenewal{PaymentMethod: "Mandiri", EnabledAt: testNow}
+		m.contractRepo.On("SetAutoRenew", ctx, "k1", want).Return(nil).Once()
+
+		got, err := svc.SetAutoRenew(ctx, "member-1", "k1", true, "Mandiri")
+
+		require.NoError(t, err)
+		assert.Equal(t, want, got.AutoRenew)
+	})
+
+	t.Run("opting in with no sessions left renews at once", func(t *testing.T) {
+		svc, m := newTestRenewalService(t)
+		m.contractRepo.On("GetByID", ctx, "k1").Return(renewable(0), nil)
+		m.contractRepo.On("SetAutoRenew", ctx, "k1", mock.Anything).Return(nil).Once()
+		m.expectRenewal()
+		m.push.On("Send", anyCtx, mock.Anything).Return(nil).Once()
+
+		got, err := svc.SetAutoRenew(ctx, "member-1", "k1", true, "BCA")
+
+		require.NoError(t, err)
+		assert.NotNil(t, got.RenewedAt)
+	})
+
+	t.Run("rejects what can't be renewed", func(t *testing.T) {
+		svc, m := newTestRenewalService(t)
+		renewed := renewable(0)
+		renewed.ID, renewed.RenewedAt = "k3", &testNow
+		m.contractRepo.On("GetByID", ctx, "k1").Return(renewable(5), nil)
+		m.contractRepo.On("GetByID", ctx, "k3").Return(renewed, nil)
+
+		_, err := svc.SetAutoRenew(ctx, "member-2", "k1", true, "BCA")
+		assert.ErrorIs(t, err, domain.ErrContractNotFound, "another member's contract")
+		_, err = svc.SetAutoRenew(ctx, "member-1", "k1", true, "Paypal")
+		assert.ErrorIs(t, err, domain.ErrInvalidPaymentMethod)
+		_, err = svc.SetAutoRenew(ctx, "member-1", "k3", false, "")
+		assert.ErrorIs(t, err, domain.ErrContractRenewed)
+	})
+}
+
+func TestInstallmentService_Apply_ActivatesRenewal(t *testing.T) {
+	ctx := context.Background()
+	renewals, m := newTestRenewalService(t)
+	installments := NewInstallmentService(m.invoices, m.contractRepo, NewMockPaymentProvider(clock.NewFake(testNow)), renewals.sandbox, 7, clock.NewFake(testNow))
+	installments.ActivateRenewals(renewals)
+
+	invoice := &domain.Invoice{ID: "inv-2", UserID: "member-1", TenantID: "gym", ContractID: "k2",
+		Amount: domain.NewMoney(5_000_000, "IDR"), Status: domain.InvoiceStatusPending}
+	m.invoices.On("RecordPayment", ctx, invoice, mock.Anything).Return(true, nil).Once()
+	m.contractRepo.On("GetByID", ctx, "k2").Return(&domain.PTContract{ID: "k2", PackageID: "p1", TotalSessions: 10,
+		Status: domain.PackageStatusPendingPayment, RenewalOf: "k1"}, nil)
+	m.pkgRepo.On("GetByID", ctx, "p1").Return(&domain.PTPackage{ID: "p1", ValidityMonths: 3}, nil)
+	m.expectLock("contract:k2")
+	m.creditRepo.On("Append", anyCtx, mock.MatchedBy(func(txn *domain.CreditTransaction) bool {
+		return txn.Type == domain.CreditTypePurchased && txn.Amount == 10 && txn.IdempotencyKey == "purchased:k2"
+	})).Run(func(args mock.Arguments) {
+		txn := args.Get(1).(*domain.CreditTransaction)
+		txn.BalanceAfter, txn.Sequence = 10, 1
+	}).Return(nil).Once()
+	m.contractRepo.On("SyncBalance", anyCtx, "k2", 10, int64(1)).Return(nil)
+	expiresAt := testNow.AddDate(0, 3, 0)
+	m.contractRepo.On("ActivatePending", ctx, "k2", &expiresAt).Return(true, nil).Once()
+
+	// The provider didn't report the amount: the rest of the invoice
+	require.NoError(t, installments.RecordPayment(ctx, invoice, "sid-9", 1, 0))
+	assert.Equal(t, domain.InvoiceStatusPaid, invoice.Status)
+	assert.Equal(t, int64(5_000_000), invoice.PaidAmount)
+}
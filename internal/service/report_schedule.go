@@ -0,0 +1,439 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// scheduledReportLinkTTL is how long the link in a delivered report works
+const scheduledReportLinkTTL = 7 * 24 * time.Hour
+
+// ReportScheduleService manages the tenants' report schedules and, from the job runner,
+// generates each due report as CSV and delivers a link to it by email or webhook
+type ReportScheduleService struct {
+	scheduleRepo domain.ReportScheduleRepository
+	userRepo     domain.UserRepository
+	tenantRepo   domain.TenantRepository // For the custom field columns
+	payments     *ManualPaymentService
+	ptService    *PTService
+	fileRepo     domain.FileRepository
+	notifier     *NotificationService
+	webhook      domain.ReportWebhook
+	clock        domain.Clock
+}
+
+func NewReportScheduleService(
+	scheduleRepo domain.ReportScheduleRepository,
+	userRepo domain.UserRepository,
+	tenantRepo domain.TenantRepository,
+	payments *ManualPaymentService,
+	ptService *PTService,
+	fileRepo domain.FileRepository,
+	notifier *NotificationService,
+	webhook domain.ReportWebhook,
+	clk domain.Clock,
+) *ReportScheduleService {
+	return &ReportScheduleService{
+		scheduleRepo: scheduleRepo,
+		userRepo:     userRepo,
+		tenantRepo:   tenantRepo,
+		payments:     payments,
+		ptService:    ptService,
+		fileRepo:     fileRepo,
+		notifier:     notifier,
+		webhook:      webhook,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// Create stores a schedule whose first run is after the current period. A webhook schedule
+// gets a signing secret, returned only now.
+func (s *ReportScheduleService) Create(ctx context.Context, schedule *domain.ReportSchedule) (string, error) {
+	if err := s.validate(ctx, schedule); err != nil {
+		return "", err
+	}
+	if schedule.Delivery == domain.ReportDeliveryWebhook {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return "", err
+		}
+		schedule.Secret = secret
+	}
+	schedule.NextRunAt = schedule.NextBoundary(s.clock.Now())
+	if err := s.scheduleRepo.Create(ctx, schedule); err != nil {
+		return "", err
+	}
+	return schedule.Secret, nil
+}
+
+func newWebhookSecret() (string, error) {
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+// List returns the tenant's schedules, oldest first
+func (s *ReportScheduleService) List(ctx context.Context, tenantID string) ([]*domain.ReportSchedule, error) {
+	return s.scheduleRepo.ListByTenant(ctx, tenantID)
+}
+
+// Get returns one of the tenant's schedules, ErrNotFound for another tenant's
+func (s *ReportScheduleService) Get(ctx context.Context, tenantID, id string) (*domain.ReportSchedule, error) {
+	schedule, err := s.scheduleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.TenantID != tenantID {
+		return nil, domain.ErrNotFound
+	}
+	return schedule, nil
+}
+
+// Update replaces the report, frequency, delivery and pause of a schedule. A new frequency
+// restarts it from the current period; a switch to webhook delivery keeps or makes a secret,
+// returned only when new.
+func (s *ReportScheduleService) Update(ctx context.Context, tenantID, id string, changes *domain.ReportSchedule) (*domain.ReportSchedule, string, error) {
+	schedule, err := s.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, "", err
+	}
+	frequency := schedule.Frequency
+	schedule.Report, schedule.Frequency, schedule.Delivery = changes.Report, changes.Frequency, changes.Delivery
+	schedule.Recipients, schedule.WebhookURL, schedule.Paused = changes.Recipients, changes.WebhookURL, changes.Paused
+	if err := s.validate(ctx, schedule); err != nil {
+		return nil, "", err
+	}
+
+	var secret string
+	if schedule.Delivery == domain.ReportDeliveryWebhook && schedule.Secret == "" {
+		if secret, err = newWebhookSecret(); err != nil {
+			return nil, "", err
+		}
+		schedule.Secret = secret
+	}
+	if schedule.Frequency != frequency {
+		schedule.NextRunAt = schedule.NextBoundary(s.clock.Now())
+	}
+	if err := s.scheduleRepo.Update(ctx, schedule); err != nil {
+		return nil, "", err
+	}
+	return schedule, secret, nil
+}
+
+// Delete removes one of the tenant's schedules
+func (s *ReportScheduleService) Delete(ctx context.Context, tenantID, id string) error {
+	if _, err := s.Get(ctx, tenantID, id); err != nil {
+		return err
+	}
+	return s.scheduleRepo.Delete(ctx, id)
+}
+
+// validate checks the schedule and that every email recipient is an admin of its tenant,
+// as the reports hold revenue and member data
+func (s *ReportScheduleService) validate(ctx context.Context, schedule *domain.ReportSchedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	if schedule.Delivery != domain.ReportDeliveryEmail {
+		schedule.Recipients = nil
+		return nil
+	}
+	schedule.WebhookURL = ""
+	for _, userID := range schedule.Recipients {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.ErrInvalidReportSchedule
+		}
+		if err != nil {
+			return err
+		}
+		scoped, err := user.ScopedTo(schedule.TenantID)
+		if err != nil || !scoped.HasRole(domain.RoleTenantAdmin) {
+			return domain.ErrInvalidReportSchedule
+		}
+	}
+	return nil
+}
+
+// RunDue generates and delivers every due report, each for the period that ended at its
+// run. A schedule that missed runs catches up with its latest period only. A failing
+// schedule is recorded on it and doesn't stop the others.
+func (s *ReportScheduleService) RunDue(ctx context.Context) (delivered int, err error) {
+	now := s.clock.Now()
+	schedules, err := s.scheduleRepo.ListDue(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	for _, schedule := range schedules {
+		due := schedule.NextRunAt
+		claimed, err := s.scheduleRepo.Claim(ctx, schedule.ID, due, schedule.NextBoundary(now))
+		if err != nil {
+			return delivered, err
+		}
+		if !claimed {
+			continue
+		}
+
+		to := schedule.PeriodBoundary(now)
+		from := schedule.PeriodBoundary(to.Add(-time.Nanosecond))
+		var runErr string
+		if err := s.run(ctx, schedule, from, to); err != nil {
+			log.Printf("Warning: scheduled %s report %s of tenant %s failed: %v", schedule.Report, schedule.ID, schedule.TenantID, err)
+			runErr = err.Error()
+		} else {
+			delivered++
+		}
+		if err := s.scheduleRepo.RecordRun(ctx, schedule.ID, now, runErr); err != nil {
+			return delivered, err
+		}
+	}
+	return delivered, nil
+}
+
+func (s *ReportScheduleService) run(ctx context.Context, schedule *domain.ReportSchedule, from, to time.Time) error {
+	rows, err := s.generate(ctx, schedule, from, to)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := csv.NewWriter(&buf).WriteAll(rows); err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("reports/%s/scheduled/%s-%s-%d.csv", schedule.TenantID, schedule.Report, from.Format("2006-01-02"), s.clock.Now().UnixNano())
+	fileURL, err := s.fileRepo.Upload(ctx, buf.Bytes(), key, "text/csv")
+	if err != nil {
+		return fmt.Errorf("failed to store report: %w", err)
+	}
+	link, err := s.fileRepo.SignedURL(ctx, fileURL, scheduledReportLinkTTL)
+	if err != nil {
+		return fmt.Errorf("failed to link report: %w", err)
+	}
+	file := &domain.ReportFile{
+		ScheduleID: schedule.ID,
+		TenantID:   schedule.TenantID,
+		Report:     schedule.Report,
+		From:       from,
+		To:         to,
+		Rows:       len(rows) - 1,
+		URL:        link,
+		Expires:    s.clock.Now().Add(scheduledReportLinkTTL),
+	}
+
+	if schedule.Delivery == domain.ReportDeliveryWebhook {
+		body, err := json.Marshal(file)
+		if err != nil {
+			return err
+		}
+		return s.webhook.Post(ctx, schedule.WebhookURL, schedule.Secret, body)
+	}
+
+	title := strings.ToUpper(schedule.Report[:1]) + strings.ReplaceAll(schedule.Report[1:], "_", " ") + " report"
+	body := fmt.Sprintf("Your %s %s report for %s to %s is ready. The link works for 7 days.", schedule.Frequency,
+		strings.ReplaceAll(schedule.Report, "_", " "), from.Format("2 Jan 2006"), to.Add(-time.Nanosecond).Format("2 Jan 2006"))
+	var errs []error
+	for _, userID := range schedule.Recipients {
+		err := s.notifier.Notify(ctx, &domain.Notification{
+			UserID:   userID,
+			TenantID: schedule.TenantID,
+			Type:     domain.NotificationReportReady,
+			Title:    title,
+			Body:     body,
+			Data:     map[string]string{"report": schedule.Report, "schedule_id": schedule.ID, "url": link},
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// generate returns the report's CSV rows, header first
+func (s *ReportScheduleService) generate(ctx context.Context, schedule *domain.ReportSchedule, from, to time.Time) ([][]string, error) {
+	switch schedule.Report {
+	case domain.ScheduledReportRevenue:
+		return s.revenueRows(ctx, schedule.TenantID, from, to)
+	case domain.ScheduledReportAttendance:
+		return s.attendanceRows(ctx, schedule.TenantID, from, to)
+	case domain.ScheduledReportCoachUtilization:
+		return s.utilizationRows(ctx, schedule.TenantID, from, to)
+	}
+	return nil, fmt.Errorf("unknown report %q", schedule.Report)
+}
+
+func (s *ReportScheduleService) revenueRows(ctx context.Context, tenantID string, from, to time.Time) ([][]string, error) {
+	report, err := s.payments.Reconcile(ctx, tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := s.customFields(ctx, tenantID, domain.CustomFieldEntityContract)
+	if err != nil {
+		return nil, err
+	}
+	contracts := make(map[string]*domain.PTContract)
+	if len(fields) > 0 {
+		all, err := s.ptService.GetContractsByTenant(ctx, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range all {
+			contracts[c.ID] = c
+		}
+	}
+
+	rows := [][]string{customFieldHeader([]string{"paid_at", "invoice_id", "contract_id", "branch_id", "member_id", "channel", "amount", "currency"}, fields)}
+	for _, p := range report.Payments { // Oldest first
+		var values domain.CustomFieldValues
+		if c, ok := contracts[p.ContractID]; ok {
+			values = c.CustomFields
+		}
+		rows = append(rows, customFieldCells([]string{p.PaidAt.UTC().Format(time.RFC3339), p.InvoiceID, p.ContractID, p.BranchID, p.UserID,
+			p.Channel, strconv.FormatInt(p.Amount, 10), report.Currency}, fields, values))
+	}
+	return rows, nil
+}
+
+func (s *ReportScheduleService) attendanceRows(ctx context.Context, tenantID string, from, to time.Time) ([][]string, error) {
+	schedules, err := s.ptService.ListSchedules(ctx, tenantID, map[string]interface{}{
+		"start_time":  map[string]interface{}{"$gte": from, "$lt": to},
+		"self_logged": map[string]interface{}{"$ne": true}, // Attendance is of PT sessions
+	})
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.users(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(users))
+	for id, u := range users {
+		names[id] = u.Name
+	}
+	fields, err := s.customFields(ctx, tenantID, domain.CustomFieldEntityMember)
+	if err != nil {
+		return nil, err
+	}
+
+	byMember := make(map[string]*domain.ReportAttendance)
+	for _, sched := range schedules {
+		if sched.DeletedAt != nil {
+			continue
+		}
+		a, ok := byMember[sched.MemberID]
+		if !ok {
+			a = &domain.ReportAttendance{}
+			byMember[sched.MemberID] = a
+		}
+		switch sched.Status {
+		case domain.ScheduleStatusCompleted:
+			a.Completed++
+		case domain.ScheduleStatusNoShow:
+			a.NoShow++
+		case domain.ScheduleStatusCancelled:
+			a.Cancelled++
+		}
+	}
+	memberIDs := make([]string, 0, len(byMember))
+	for id := range byMember {
+		memberIDs = append(memberIDs, id)
+	}
+	sort.Slice(memberIDs, func(i, j int) bool {
+		if names[memberIDs[i]] != names[memberIDs[j]] {
+			return names[memberIDs[i]] < names[memberIDs[j]]
+		}
+		return memberIDs[i] < memberIDs[j]
+	})
+
+	rows := [][]string{customFieldHeader([]string{"member_id", "member", "completed", "no_show", "cancelled", "attendance_rate"}, fields)}
+	for _, id := range memberIDs {
+		a := byMember[id]
+		var values domain.CustomFieldValues
+		if u, ok := users[id]; ok {
+			values = u.CustomFieldsIn(tenantID)
+		}
+		rows = append(rows, customFieldCells([]string{id, names[id], strconv.Itoa(a.Completed), strconv.Itoa(a.NoShow),
+			strconv.Itoa(a.Cancelled), strconv.Itoa(a.Rate())}, fields, values))
+	}
+	return rows, nil
+}
+
+func (s *ReportScheduleService) utilizationRows(ctx context.Context, tenantID string, from, to time.Time) ([][]string, error) {
+	coaches, err := s.userRepo.GetByTenantAndRole(ctx, tenantID, domain.RoleCoach)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(coaches, func(i, j int) bool { return coaches[i].Name < coaches[j].Name })
+
+	rows := [][]string{{"coach_id", "coach", "branch_id", "sessions", "completed", "no_show", "cancelled",
+		"booked_minutes", "available_minutes", "utilization"}}
+	for _, coach := range coaches {
+		branches, err := s.ptService.GetCoachUtilization(ctx, coach.ID, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get utilization of coach %s: %w", coach.ID, err)
+		}
+		for _, u := range branches {
+			utilization := "" // Blank when the coach set no hours at the branch
+			if u.AvailableMinutes > 0 {
+				utilization = strconv.FormatFloat(u.Utilization*100, 'f', 1, 64)
+			}
+			rows = append(rows, []string{coach.ID, coach.Name, u.BranchID, strconv.Itoa(u.Sessions), strconv.Itoa(u.Completed),
+				strconv.Itoa(u.NoShow), strconv.Itoa(u.Cancelled), strconv.Itoa(u.BookedMinutes), strconv.Itoa(u.AvailableMinutes), utilization})
+		}
+	}
+	return rows, nil
+}
+
+// users maps the tenant's user IDs to the users
+func (s *ReportScheduleService) users(ctx context.Context, tenantID string) (map[string]*domain.User, error) {
+	users, err := s.userRepo.GetByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*domain.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+	return byID, nil
+}
+
+// customFields returns the tenant's custom fields on the entity, which reports add as
+// columns after their own
+func (s *ReportScheduleService) customFields(ctx context.Context, tenantID, entity string) ([]domain.CustomField, error) {
+	tenant, err := s.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch custom fields: %w", err)
+	}
+	return tenant.CustomFields.For(entity), nil
+}
+
+// customFieldHeader appends a column per custom field, named by its key
+func customFieldHeader(header []string, fields []domain.CustomField) []string {
+	for _, f := range fields {
+		header = append(header, f.Key)
+	}
+	return header
+}
+
+// customFieldCells appends the row's custom field values, blank where unset
+func customFieldCells(row []string, fields []domain.CustomField, values domain.CustomFieldValues) []string {
+	for _, f := range fields {
+		row = append(row, domain.FormatCustomField(values[f.Key]))
+	}
+	return row
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type reportScheduleMocks struct {
+	schedules *mocks.ReportScheduleRepository
+	users     *mocks.UserRepository
+	tenants   *mocks.TenantRepository
+	invoices  *mocks.InvoiceRepository
+	pt        *ptServiceMocks
+	files     *mocks.FileRepository
+	email     *mocks.NotificationSender
+	webhook   *mocks.ReportWebhook
+}
+
+func newTestReportScheduleService(t *testing.T) (*ReportScheduleService, reportScheduleMocks) {
+	ptService, pt := newTestPTService(t)
+	m := reportScheduleMocks{
+		schedules: mocks.NewReportScheduleRepository(t),
+		users:     mocks.NewUserRepository(t),
+		tenants:   mocks.NewTenantRepository(t),
+		invoices:  mocks.NewInvoiceRepository(t),
+		pt:        pt,
+		files:     mocks.NewFileRepository(t),
+		email:     mocks.NewNotificationSender(t),
+		webhook:   mocks.NewReportWebhook(t),
+	}
+	prefs := mocks.NewNotificationPreferencesRepository(t)
+	prefs.On("GetUser", mock.Anything, mock.Anything).Return(&domain.NotificationPreferences{}, nil).Maybe()
+	m.email.On("Channel").Return(domain.ChannelEmail)
+	notifications := NewNotificationService(prefs, nil, clock.NewFake(testNow), m.email)
+	payments := NewManualPaymentService(m.invoices, nil, nil, clock.NewFake(testNow))
+	return NewReportScheduleService(m.schedules, m.users, m.tenants, payments, ptService, m.files, notifications, m.webhook, clock.NewFake(testNow)), m
+}
+
+func TestReportScheduleService_Create(t *testing.T) {
+	ctx := context.Background()
+	nextMonday := time.Date(2025, 6, 23, 0, 0, 0, 0, time.UTC)
+
+	t.Run("emails the tenant's admins from next period", func(t *testing.T) {
+		svc, m := newTestReportScheduleService(t)
+		m.users.On("GetByID", ctx, "admin-1").Return(&domain.User{ID: "admin-1", TenantID: "gym", Roles: []string{domain.RoleTenantAdmin}}, nil)
+		m.schedules.On("Create", ctx, mock.AnythingOfType("*domain.ReportSchedule")).Return(nil)
+		schedule := &domain.ReportSchedule{TenantID: "gym", Report: domain.ScheduledReportRevenue, Frequency: domain.ReportWeekly,
+			Delivery: domain.ReportDeliveryEmail, Recipients: []string{"admin-1"}, WebhookURL: "https://ignored"}
+
+		secret, err := svc.Create(ctx, schedule)
+
+		require.NoError(t, err)
+		assert.Empty(t, secret)
+		assert.Empty(t, schedule.WebhookURL)
+		assert.Equal(t, nextMonday, schedule.NextRunAt)
+	})
+
+	t.Run("rejects recipients who don't administer the tenant", func(t *testing.T) {
+		svc, m := newTestReportScheduleService(t)
+		m.users.On("GetByID", ctx, "coach-1").Return(&domain.User{ID: "coach-1", TenantID: "gym", Roles: []string{domain.RoleCoach}}, nil)
+		m.users.On("GetByID", ctx, "other-admin").Return(&domain.User{ID: "other-admin", TenantID: "rival", Roles: []string{domain.RoleTenantAdmin}}, nil)
+
+		for _, recipient := range []string{"coach-1", "other-admin"} {
+			_, err := svc.Create(ctx, &domain.ReportSchedule{TenantID: "gym", Report: domain.ScheduledReportRevenue, Frequency: domain.ReportWeekly,
+				Delivery: domain.ReportDeliveryEmail, Recipients: []string{recipient}})
+			assert.ErrorIs(t, err, domain.ErrInvalidReportSchedule, recipient)
+		}
+	})
+
+	t.Run("gives a webhook a signing secret", func(t *testing.T) {
+		svc, m := newTestReportScheduleService(t)
+		m.schedules.On("Create", ctx, mock.AnythingOfType("*domain.ReportSchedule")).Return(nil)
+		schedule := &domain.ReportSchedule{TenantID: "gym", Report: domain.ScheduledReportAttendance, Frequency: domain.ReportMonthly,
+			Delivery: domain.ReportDeliveryWebhook, WebhookURL: "https://hooks.example.com/metamorph"}
+
+		secret, err := svc.Create(ctx, schedule)
+
+		require.NoError(t, err)
+		assert.Len(t, secret, 48)
+		assert.Equal(t, secret, schedule.Secret)
+		assert.Equal(t, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), schedule.NextRunAt)
+	})
+}
+
+func TestReportScheduleService_RunDue(t *testing.T) {
+	ctx := context.Background()
+	monday, nextMonday := time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 23, 0, 0, 0, 0, time.UTC)
+	lastMonday := time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC)
+	expectStored := func(m reportScheduleMocks, key string) *[]byte {
+		var csv []byte
+		m.files.On("Upload", ctx, mock.Anything, key, "text/csv").Run(func(args mock.Arguments) {
+			csv = args.Get(1).([]byte)
+		}).Return("https://files/report.csv", nil)
+		m.files.On("SignedURL", ctx, "https://files/report.csv", scheduledReportLinkTTL).Return("https://files/report.csv?sig", nil)
+		return &csv
+	}
+
+	t.Run("posts last week's revenue to the webhook", func(t *testing.T) {
+		svc, m := newTestReportScheduleService(t)
+		schedule := &domain.ReportSchedule{ID: "rs-1", TenantID: "gym", Report: domain.ScheduledReportRevenue, Frequency: domain.ReportWeekly,
+			Delivery: domain.ReportDeliveryWebhook, WebhookURL: "https://hooks.example.com/metamorph", Secret: "s3cret", NextRunAt: monday}
+		m.schedules.On("ListDue", ctx, testNow).Return([]*domain.ReportSchedule{schedule}, nil)
+		m.schedules.On("Claim", ctx, "rs-1", monday, nextMonday).Return(true, nil)
+		m.invoices.On("ListPaidBetween", ctx, "gym", lastMonday, monday).Return([]*domain.Invoice{{
+			ID: "inv-1", ContractID: "contract-1", UserID: "member-1", BranchID: "north",
+			Payments: []domain.InvoicePayment{{Amount: 500000, PaidAt: lastMonday.Add(30 * time.Hour), Channel: domain.PaymentChannelCash}},
+		}}, nil)
+		m.tenants.On("GetByID", ctx, "gym").Return(&domain.Tenant{ID: "gym", CustomFields: domain.CustomFieldSchema{
+			{Key: "insurance_id", Label: "Insurance ID", Entity: domain.CustomFieldEntityContract, Type: domain.CustomFieldText},
+			{Key: "locker", Label: "Locker", Entity: domain.CustomFieldEntityMember, Type: domain.CustomFieldNumber},
+		}}, nil)
+		m.pt.contractRepo.On("GetByTenant", ctx, "gym").Return([]*domain.PTContract{
+			{ID: "contract-1", CustomFields: domain.CustomFieldValues{"insurance_id": "AX-1"}},
+		}, nil)
+		csv := expectStored(m, "reports/gym/scheduled/revenue-2025-06-09-1750068000000000000.csv")
+		m.webhook.On("Post", ctx, "https://hooks.example.com/metamorph", "s3cret", mock.MatchedBy(func(body []byte) bool {
+			var file domain.ReportFile
+			return json.Unmarshal(body, &file) == nil && file.URL == "https://files/report.csv?sig" && file.Rows == 1 && file.From.Equal(lastMonday)
+		})).Return(nil)
+		m.schedules.On("RecordRun", ctx, "rs-1", testNow, "").Return(nil)
+
+		delivered, err := svc.RunDue(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+		assert.Equal(t, "paid_at,invoice_id,contract_id,branch_id,member_id,channel,amount,currency,insurance_id\n"+
+			"2025-06-10T06:00:00Z,inv-1,contract-1,north,member-1,cash,500000,IDR,AX-1\n", string(*csv))
+	})
+
+	t.Run("emails attendance and records failures without stopping", func(t *testing.T) {
+		svc, m := newTestReportScheduleService(t)
+		attendance := &domain.ReportSchedule{ID: "rs-1", TenantID: "gym", Report: domain.ScheduledReportAttendance, Frequency: domain.ReportWeekly,
+			Delivery: domain.ReportDeliveryEmail, Recipients: []string{"admin-1"}, NextRunAt: monday}
+		taken := &domain.ReportSchedule{ID: "rs-2", TenantID: "gym", Report: domain.ScheduledReportRevenue, Frequency: domain.ReportWeekly, NextRunAt: monday}
+		broken := &domain.ReportSchedule{ID: "rs-3", TenantID: "gym", Report: domain.ScheduledReportCoachUtilization, Frequency: domain.ReportWeekly,
+			Delivery: domain.ReportDeliveryEmail, Recipients: []string{"admin-1"}, NextRunAt: lastMonday} // Missed a week
+		m.schedules.On("ListDue", ctx, testNow).Return([]*domain.ReportSchedule{attendance, taken, broken}, nil)
+		m.schedules.On("Claim", ctx, "rs-1", monday, nextMonday).Return(true, nil)
+		m.schedules.On("Claim", ctx, "rs-2", monday, nextMonday).Return(false, nil)
+		m.schedules.On("Claim", ctx, "rs-3", lastMonday, nextMonday).Return(true, nil)
+
+		m.pt.schedRepo.On("List", ctx, "gym", map[string]interface{}{
+			"start_time":  map[string]interface{}{"$gte": lastMonday, "$lt": monday},
+			"self_logged": map[string]interface{}{"$ne": true},
+		}).Return([]*domain.Schedule{
+			{MemberID: "member-2", Status: domain.ScheduleStatusCompleted},
+			{MemberID: "member-1", Status: domain.ScheduleStatusCompleted},
+			{MemberID: "member-1", Status: domain.ScheduleStatusNoShow},
+			{MemberID: "member-1", Status: domain.ScheduleStatusCancelled},
+			{MemberID: "member-1", Status: domain.ScheduleStatusCompleted, DeletedAt: &testNow},
+		}, nil)
+		m.users.On("GetByTenant", ctx, "gym").Return([]*domain.User{
+			{ID: "member-1", Name: "Ana", TenantID: "gym", CustomFields: domain.CustomFieldValues{"locker": float64(12)}},
+			{ID: "member-2", Name: "Budi", TenantID: "gym"},
+		}, nil)
+		m.tenants.On("GetByID", ctx, "gym").Return(&domain.Tenant{ID: "gym", CustomFields: domain.CustomFieldSchema{
+			{Key: "locker", Label: "Locker", Entity: domain.CustomFieldEntityMember, Type: domain.CustomFieldNumber},
+		}}, nil)
+		csv := expectStored(m, "reports/gym/scheduled/attendance-2025-06-09-1750068000000000000.csv")
+		m.email.On("Send", ctx, mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.UserID == "admin-1" && n.Type == domain.NotificationReportReady && n.Data["url"] == "https://files/report.csv?sig"
+		})).Return(nil).Once()
+		m.schedules.On("RecordRun", ctx, "rs-1", testNow, "").Return(nil)
+
+		m.users.On("GetByTenantAndRole", ctx, "gym", domain.RoleCoach).Return(nil, errors.New("mongo down"))
+		m.schedules.On("RecordRun", ctx, "rs-3", testNow, "mongo down").Return(nil)
+
+		delivered, err := svc.RunDue(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+		assert.Equal(t, "member_id,member,completed,no_show,cancelled,attendance_rate,locker\n"+
+			"member-1,Ana,1,1,1,50,12\n"+
+			"member-2,Budi,1,0,0,100,\n", string(*csv))
+	})
+}
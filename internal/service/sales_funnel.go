@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// SalesFunnelService records how members move from viewing a package to paying for it and
+// turns that into per-package conversion funnels
+type SalesFunnelService struct {
+	events   domain.SalesEventRepository
+	packages domain.PackageRepository
+	clock    domain.Clock
+}
+
+func NewSalesFunnelService(events domain.SalesEventRepository, packages domain.PackageRepository, clk domain.Clock) *SalesFunnelService {
+	return &SalesFunnelService{events: events, packages: packages, clock: clock.OrReal(clk)}
+}
+
+// Track records that a member reached a funnel stage. Analytics never fail a purchase, so
+// errors are only logged.
+func (s *SalesFunnelService) Track(ctx context.Context, tenantID, userID, packageID, stage, invoiceID string) {
+	if s == nil || tenantID == "" || packageID == "" {
+		return
+	}
+	err := s.events.Record(ctx, &domain.SalesEvent{
+		TenantID:   tenantID,
+		PackageID:  packageID,
+		UserID:     userID,
+		Stage:      stage,
+		InvoiceID:  invoiceID,
+		OccurredAt: s.clock.Now().UTC(),
+	})
+	if err != nil {
+		log.Printf("Warning: failed to track %s of package %s: %v", stage, packageID, err)
+	}
+}
+
+// Funnel builds the tenant's funnel for every active package, plus any inactive package
+// that still had activity in [from, to)
+func (s *SalesFunnelService) Funnel(ctx context.Context, tenantID string, from, to time.Time) (*domain.SalesFunnel, error) {
+	counts, err := s.events.CountByStage(ctx, tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	active, err := s.packages.GetActivePackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string)
+	for _, pkg := range active {
+		names[pkg.ID] = pkg.Name
+	}
+	byPackage := make(map[string]map[string]domain.SalesStageCount)
+	for _, count := range counts {
+		if byPackage[count.PackageID] == nil {
+			byPackage[count.PackageID] = make(map[string]domain.SalesStageCount)
+		}
+		byPackage[count.PackageID][count.Stage] = count
+
+		if _, ok := names[count.PackageID]; !ok {
+			names[count.PackageID] = ""
+			if pkg, err := s.packages.GetByID(ctx, count.PackageID); err == nil {
+				names[count.PackageID] = pkg.Name
+			}
+		}
+	}
+
+	funnel := &domain.SalesFunnel{From: from, To: to, Packages: []domain.PackageFunnel{}}
+	for packageID, name := range names {
+		funnel.Packages = append(funnel.Packages, packageFunnel(packageID, name, byPackage[packageID]))
+	}
+	sort.Slice(funnel.Packages, func(i, j int) bool {
+		a, b := funnel.Packages[i], funnel.Packages[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.PackageID < b.PackageID
+	})
+	return funnel, nil
+}
+
+// packageFunnel lays a package's stage counts out in funnel order
+func packageFunnel(packageID, name string, stages map[string]domain.SalesStageCount) domain.PackageFunnel {
+	funnel := domain.PackageFunnel{PackageID: packageID, Name: name}
+	for i, stage := range domain.SalesStages {
+		count := stages[stage]
+		step := domain.FunnelStep{Stage: stage, Events: count.Events, Members: count.Members, Conversion: 1}
+		if i > 0 {
+			step.Conversion = conversionRate(count.Members, funnel.Steps[i-1].Members)
+		}
+		funnel.Steps = append(funnel.Steps, step)
+	}
+	funnel.Overall = conversionRate(funnel.Steps[len(funnel.Steps)-1].Members, funnel.Steps[0].Members)
+	return funnel
+}
+
+// conversionRate is n/d rounded to three decimals, or 0 when nobody reached the earlier stage
+func conversionRate(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return math.Round(float64(n)/float64(d)*1000) / 1000
+}
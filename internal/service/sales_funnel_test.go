@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSalesFunnelService_Funnel(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewSalesEventRepository(t)
+	packages := mocks.NewPackageRepository(t)
+	svc := NewSalesFunnelService(events, packages, clock.NewFake(testNow))
+
+	from, to := testNow.AddDate(0, 0, -30), testNow
+	events.On("CountByStage", ctx, "tenant-1", from, to).Return([]domain.SalesStageCount{
+		{PackageID: "pkg-monthly", Stage: domain.SalesStagePageView, Events: 40, Members: 20},
+		{PackageID: "pkg-monthly", Stage: domain.SalesStageCheckoutStarted, Events: 12, Members: 8},
+		{PackageID: "pkg-monthly", Stage: domain.SalesStageInvoiceCreated, Events: 6, Members: 6},
+		{PackageID: "pkg-monthly", Stage: domain.SalesStagePaymentCompleted, Events: 5, Members: 5},
+		{PackageID: "pkg-retired", Stage: domain.SalesStagePaymentCompleted, Events: 1, Members: 1},
+	}, nil)
+	packages.On("GetActivePackages", ctx).Return([]*domain.Package{
+		{ID: "pkg-monthly", Name: "Monthly"},
+		{ID: "pkg-annual", Name: "Annual"},
+	}, nil)
+	packages.On("GetByID", ctx, "pkg-retired").Return(&domain.Package{ID: "pkg-retired", Name: "Quarterly"}, nil)
+
+	funnel, err := svc.Funnel(ctx, "tenant-1", from, to)
+
+	require.NoError(t, err)
+	require.Len(t, funnel.Packages, 3)
+	assert.Equal(t, "Annual", funnel.Packages[0].Name, "active packages without activity are listed")
+	assert.Zero(t, funnel.Packages[0].Overall)
+
+	monthly := funnel.Packages[1]
+	assert.Equal(t, "pkg-monthly", monthly.PackageID)
+	require.Len(t, monthly.Steps, 4)
+	assert.Equal(t, 1.0, monthly.Steps[0].Conversion)
+	assert.Equal(t, 40, monthly.Steps[0].Events)
+	assert.Equal(t, 0.4, monthly.Steps[1].Conversion)
+	assert.Equal(t, 0.75, monthly.Steps[2].Conversion)
+	assert.Equal(t, 0.833, monthly.Steps[3].Conversion)
+	assert.Equal(t, 0.25, monthly.Overall)
+
+	retired := funnel.Packages[2]
+	assert.Equal(t, "Quarterly", retired.Name)
+	assert.Zero(t, retired.Steps[3].Conversion, "no conversion without the earlier stage")
+}
+
+func TestSalesFunnelService_Track(t *testing.T) {
+	ctx := context.Background()
+	events := mocks.NewSalesEventRepository(t)
+	svc := NewSalesFunnelService(events, nil, clock.NewFake(testNow))
+
+	events.On("Record", ctx, mock.MatchedBy(func(e *domain.SalesEvent) bool {
+		return e.TenantID == "tenant-1" && e.Stage == domain.SalesStageInvoiceCreated &&
+			e.InvoiceID == "inv-1" && e.OccurredAt.Equal(testNow)
+	})).Return(errors.New("mongo down"))
+
+	// A failed write is logged, never returned
+	svc.Track(ctx, "tenant-1", "member-1", "pkg-monthly", domain.SalesStageInvoiceCreated, "inv-1")
+
+	// Events without a tenant can't be attributed and aren't stored
+	svc.Track(ctx, "", "member-1", "pkg-monthly", domain.SalesStagePageView, "")
+
+	var disabled *SalesFunnelService
+	disabled.Track(ctx, "tenant-1", "member-1", "pkg-monthly", domain.SalesStagePageView, "")
+}
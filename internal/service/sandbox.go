@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"math"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// sandboxModel is recorded on scans digitized for sandbox tenants
+const sandboxModel = "sandbox"
+
+// SandboxService keeps sandbox tenants, which integration partners test against, away from
+// real money, AI spend and inboxes: checkouts get mock virtual accounts, scans are digitized
+// with made-up metrics and notifications are captured instead of sent.
+type SandboxService struct {
+	tenantRepo  domain.TenantRepository
+	userRepo    domain.UserRepository
+	sandboxRepo domain.SandboxRepository
+	payments    PaymentProvider
+	clock       domain.Clock
+}
+
+func NewSandboxService(tenantRepo domain.TenantRepository, userRepo domain.UserRepository, sandboxRepo domain.SandboxRepository, clk domain.Clock) *SandboxService {
+	return &SandboxService{
+		tenantRepo:  tenantRepo,
+		userRepo:    userRepo,
+		sandboxRepo: sandboxRepo,
+		payments:    NewMockPaymentProvider(clk),
+		clock:       clock.OrReal(clk),
+	}
+}
+
+// IsSandbox reports whether the tenant is flagged as a sandbox. Unknown tenants are not.
+func (s *SandboxService) IsSandbox(ctx context.Context, tenantID string) (bool, error) {
+	if tenantID == "" {
+		return false, nil
+	}
+	tenant, err := s.tenantRepo.GetByID(ctx, tenantID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return tenant.Sandbox, nil
+}
+
+// PaymentProvider returns the provider for a checkout in the tenant: live, or the mock
+// client for a sandbox. It fails rather than guess when the tenant can't be loaded.
+func (s *SandboxService) PaymentProvider(ctx context.Context, tenantID string, live PaymentProvider) (PaymentProvider, error) {
+	sandbox, err := s.IsSandbox(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if sandbox {
+		return s.payments, nil
+	}
+	return live, nil
+}
+
+// Digitizer wraps live so that scans of sandbox tenant members never reach the AI
+func (s *SandboxService) Digitizer(live domain.DigitizerService) domain.DigitizerService {
+	return &sandboxDigitizer{live: live, sandbox: s}
+}
+
+// Capture stores n as sent on channel if its tenant is a sandbox, and reports whether it did.
+// The caller sends the notification itself when it wasn't captured.
+func (s *SandboxService) Capture(ctx context.Context, channel string, n *domain.Notification) (bool, error) {
+	sandbox, err := s.IsSandbox(ctx, n.TenantID)
+	if err != nil || !sandbox {
+		return false, err
+	}
+	err = s.sandboxRepo.CaptureNotification(ctx, &domain.CapturedNotification{
+		Channel:      channel,
+		Notification: *n,
+		CreatedAt:    s.clock.Now(),
+	})
+	return err == nil, err
+}
+
+// Notifications returns a page of the notifications captured for the tenant
+func (s *SandboxService) Notifications(ctx context.Context, tenantID string, q domain.PageQuery) (*domain.Page[*domain.CapturedNotification], error) {
+	if _, err := s.tenantRepo.GetByID(ctx, tenantID); err != nil {
+		return nil, err
+	}
+	return s.sandboxRepo.ListNotifications(ctx, tenantID, q)
+}
+
+// Reset wipes a sandbox tenant's test data, see domain.SandboxRepository. Refuses tenants
+// that aren't flagged as sandbox so a typo can't empty a real gym.
+func (s *SandboxService) Reset(ctx context.Context, tenantID string) (*domain.SandboxReset, error) {
+	tenant, err := s.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if !tenant.Sandbox {
+		return nil, domain.ErrNotSandbox
+	}
+	return s.sandboxRepo.Reset(ctx, tenant.ID)
+}
+
+// sandboxDigitizer answers for members of sandbox tenants with plausible metrics derived
+// from the image, so the same upload always reads the same, and defers to live otherwise
+type sandboxDigitizer struct {
+	live    domain.DigitizerService
+	sandbox *SandboxService
+}
+
+func (d *sandboxDigitizer) ExtractMetrics(ctx context.Context, userID string, imageData []byte) (*domain.InBodyMetrics, error) {
+	user, err := d.sandbox.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return d.live.ExtractMetrics(ctx, userID, imageData)
+	}
+	sandbox, err := d.sandbox.IsSandbox(ctx, user.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if !sandbox {
+		return d.live.ExtractMetrics(ctx, userID, imageData)
+	}
+	return d.mockMetrics(imageData), nil
+}
+
+func (d *sandboxDigitizer) mockMetrics(imageData []byte) *domain.InBodyMetrics {
+	h := fnv.New32a()
+	h.Write(imageData)
+	seed := h.Sum32()
+
+	round := func(v float64) float64 { return math.Round(v*10) / 10 }
+	weight := 55 + float64(seed%400)/10    // 55.0 - 94.9 kg
+	pbf := 12 + float64((seed/400)%250)/10 // 12.0 - 36.9 %
+	height := 1.55 + float64((seed/100000)%40)/100
+	fatMass := weight * pbf / 100
+	fatFree := weight - fatMass
+
+	return &domain.InBodyMetrics{
+		Weight:                   round(weight),
+		SMM:                      round(fatFree * 0.55),
+		BodyFatMass:              round(fatMass),
+		PBF:                      round(pbf),
+		BMI:                      round(weight / (height * height)),
+		BMR:                      int(370 + 21.6*fatFree),
+		VisceralFatLevel:         int(pbf / 3),
+		WaistHipRatio:            round(0.75 + pbf/200),
+		TestDate:                 d.sandbox.clock.Now(),
+		InBodyScore:              round(100 - pbf),
+		FatFreeMass:              round(fatFree),
+		RecommendedCalorieIntake: int(1.4 * (370 + 21.6*fatFree)),
+		Model:                    sandboxModel,
+	}
+}
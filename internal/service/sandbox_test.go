@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandboxService(t *testing.T) {
+	ctx := context.Background()
+	sandboxTenant := &domain.Tenant{ID: "t-sandbox", Sandbox: true}
+	liveTenant := &domain.Tenant{ID: "t-live"}
+
+	newService := func(t *testing.T) (*SandboxService, *mocks.TenantRepository, *mocks.UserRepository, *mocks.SandboxRepository) {
+		tenants, users, repo := mocks.NewTenantRepository(t), mocks.NewUserRepository(t), mocks.NewSandboxRepository(t)
+		tenants.On("GetByID", mock.Anything, sandboxTenant.ID).Return(sandboxTenant, nil).Maybe()
+		tenants.On("GetByID", mock.Anything, liveTenant.ID).Return(liveTenant, nil).Maybe()
+		return NewSandboxService(tenants, users, repo, clock.NewFake(testNow)), tenants, users, repo
+	}
+
+	t.Run("sandbox checkouts use the mock provider", func(t *testing.T) {
+		svc, _, _, _ := newService(t)
+		live := &IPaymuClientAdapter{}
+
+		provider, err := svc.PaymentProvider(ctx, sandboxTenant.ID, live)
+		require.NoError(t, err)
+		assert.IsType(t, &MockIPaymuClient{}, provider)
+		va, err := provider.GenerateVA(ctx, "BCA", 500_000, "m1")
+		require.NoError(t, err)
+		assert.Equal(t, testNow.Add(24*time.Hour), va.ExpiresAt, "expires a day after the service's now")
+
+		provider, err = svc.PaymentProvider(ctx, liveTenant.ID, live)
+		require.NoError(t, err)
+		assert.Same(t, live, provider)
+	})
+
+	t.Run("sandbox scans never reach the AI", func(t *testing.T) {
+		svc, _, users, _ := newService(t)
+		live := mocks.NewDigitizerService(t)
+		users.On("GetByID", ctx, "m1").Return(&domain.User{ID: "m1", TenantID: sandboxTenant.ID}, nil)
+
+		digitizer := svc.Digitizer(live)
+		first, err := digitizer.ExtractMetrics(ctx, "m1", []byte("scan"))
+		require.NoError(t, err)
+		again, err := digitizer.ExtractMetrics(ctx, "m1", []byte("scan"))
+		require.NoError(t, err)
+
+		assert.Equal(t, first, again)
+		assert.Equal(t, sandboxModel, first.Model)
+		assert.InDelta(t, first.Weight, first.BodyFatMass+first.FatFreeMass, 0.11)
+		live.AssertNotCalled(t, "ExtractMetrics", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("other scans go to the live digitizer", func(t *testing.T) {
+		svc, _, users, _ := newService(t)
+		live := mocks.NewDigitizerService(t)
+		users.On("GetByID", ctx, "m2").Return(&domain.User{ID: "m2", TenantID: liveTenant.ID}, nil)
+		live.On("ExtractMetrics", ctx, "m2", []byte("scan")).Return(&domain.InBodyMetrics{Weight: 70}, nil).Once()
+
+		metrics, err := svc.Digitizer(live).ExtractMetrics(ctx, "m2", []byte("scan"))
+		require.NoError(t, err)
+		assert.Equal(t, 70.0, metrics.Weight)
+	})
+
+	t.Run("reset refuses live tenants", func(t *testing.T) {
+		svc, _, _, _ := newService(t)
+
+		_, err := svc.Reset(ctx, liveTenant.ID)
+		assert.ErrorIs(t, err, domain.ErrNotSandbox)
+	})
+
+	t.Run("reset wipes a sandbox tenant", func(t *testing.T) {
+		svc, _, _, repo := newService(t)
+		repo.On("Reset", ctx, sandboxTenant.ID).Return(&domain.SandboxReset{Users: 3, Records: 40}, nil).Once()
+
+		reset, err := svc.Reset(ctx, sandboxTenant.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), reset.Users)
+	})
+}
+
+func TestNotificationService_SandboxCapture(t *testing.T) {
+	ctx := context.Background()
+	tenants, repo := mocks.NewTenantRepository(t), mocks.NewSandboxRepository(t)
+	tenants.On("GetByID", ctx, "t-sandbox").Return(&domain.Tenant{ID: "t-sandbox", Sandbox: true}, nil)
+	tenants.On("GetByID", ctx, "t-live").Return(&domain.Tenant{ID: "t-live"}, nil)
+
+	prefs := mocks.NewNotificationPreferencesRepository(t)
+	prefs.On("GetUser", ctx, "m1").Return(&domain.NotificationPreferences{UserID: "m1"}, nil)
+	push := mocks.NewNotificationSender(t)
+	push.On("Channel").Return(domain.ChannelPush)
+
+	svc := NewNotificationService(prefs, nil, clock.NewFake(testNow), push)
+	svc.CaptureSandbox(NewSandboxService(tenants, nil, repo, clock.NewFake(testNow)))
+
+	sandboxed := &domain.Notification{UserID: "m1", TenantID: "t-sandbox", Type: domain.NotificationScheduleReminder}
+	repo.On("CaptureNotification", ctx, &domain.CapturedNotification{
+		Channel:      domain.ChannelPush,
+		Notification: *sandboxed,
+		CreatedAt:    testNow,
+	}).Return(nil).Once()
+	assert.NoError(t, svc.Notify(ctx, sandboxed))
+
+	live := &domain.Notification{UserID: "m1", TenantID: "t-live", Type: domain.NotificationScheduleReminder}
+	push.On("Send", ctx, live).Return(nil).Once()
+	assert.NoError(t, svc.Notify(ctx, live))
+}
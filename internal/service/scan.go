@@ -2,15 +2,19 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/mansoorceksport/metamorph/internal/clock"
 	"github.com/mansoorceksport/metamorph/internal/domain"
-	"go.mongodb.org/mongo-driver/bson/primitive"
+	"github.com/mansoorceksport/metamorph/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
 	cacheLatestScanTTL = 24 * time.Hour
+	scansChangedTTL    = analyticsHistoryTTL // Outlives every history cached before the change
 )
 
 // ScanServiceImpl implements domain.ScanService
@@ -19,6 +23,10 @@ type ScanServiceImpl struct {
 	repository     domain.InBodyRepository
 	cache          domain.CacheRepository
 	fileRepository domain.FileRepository
+	revisions      domain.ScanRevisionRepository
+	outbox         *Outbox               // Optional: without it caches are invalidated inline
+	consent        *HealthConsentService // Optional: see RequireHealthConsent
+	clock          domain.Clock
 }
 
 // NewScanService creates a new scan service
@@ -27,30 +35,61 @@ func NewScanService(
 	repository domain.InBodyRepository,
 	cache domain.CacheRepository,
 	fileRepository domain.FileRepository,
+	revisions domain.ScanRevisionRepository,
+	outbox *Outbox,
+	clk domain.Clock,
 ) *ScanServiceImpl {
 	return &ScanServiceImpl{
 		digitizer:      digitizer,
 		repository:     repository,
 		cache:          cache,
 		fileRepository: fileRepository,
+		revisions:      revisions,
+		outbox:         outbox,
+		clock:          clock.OrReal(clk),
 	}
 }
 
+// RequireHealthConsent stops scans of members without current health data consent from
+// being stored, digitized or imported
+func (s *ScanServiceImpl) RequireHealthConsent(consent *HealthConsentService) {
+	s.consent = consent
+}
+
+// checkConsent returns ErrHealthConsentRequired if userID's scans may not be processed
+func (s *ScanServiceImpl) checkConsent(ctx context.Context, userID string) error {
+	if s.consent == nil {
+		return nil
+	}
+	return s.consent.RequireConsent(ctx, userID)
+}
+
 // ProcessScan orchestrates the entire digitization workflow
-func (s *ScanServiceImpl) ProcessScan(ctx context.Context, userID string, imageData []byte, imageURL string) (*domain.InBodyRecord, error) {
+// Each step gets its own span so a slow digitization can be attributed to storage or the AI call.
+func (s *ScanServiceImpl) ProcessScan(ctx context.Context, userID string, imageData []byte, imageURL string) (record *domain.InBodyRecord, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "ScanService.ProcessScan", telemetry.MemberID(userID))
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	// Nothing of the scan is stored or sent to the AI without the member's consent
+	if err := s.checkConsent(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	// Step 0: Upload image to S3 (SeaweedFS) if fileRepository is available
 	// We generate a filename based on userID and timestamp
 	if s.fileRepository != nil {
-		filename := fmt.Sprintf("%s/%d.jpg", userID, time.Now().UnixNano()) // Simple path strategy
-		contentType := "image/jpeg"                                         // Default, ideally detect dynamically
+		filename := fmt.Sprintf("%s/%d.jpg", userID, s.clock.Now().UnixNano()) // Simple path strategy
+		contentType := "image/jpeg"                                            // Default, ideally detect dynamically
 
 		// Improve content type detection if possible (reusing logic from digitizer would be good, but keep simple for now)
 		if len(imageData) > 0 && imageData[0] == 0x89 && imageData[1] == 0x50 {
 			contentType = "image/png"
-			filename = fmt.Sprintf("%s/%d.png", userID, time.Now().UnixNano())
+			filename = fmt.Sprintf("%s/%d.png", userID, s.clock.Now().UnixNano())
 		}
 
-		uploadedURL, err := s.fileRepository.Upload(ctx, imageData, filename, contentType)
+		uploadCtx, uploadSpan := telemetry.StartSpan(ctx, "scan.upload", attribute.Int("scan.image_bytes", len(imageData)))
+		uploadedURL, err := s.fileRepository.Upload(uploadCtx, imageData, filename, contentType)
+		telemetry.EndSpan(uploadSpan, err)
 		if err != nil {
 			return nil, fmt.Errorf("failed to upload image: %w", err)
 		}
@@ -58,54 +97,36 @@ func (s *ScanServiceImpl) ProcessScan(ctx context.Context, userID string, imageD
 	}
 
 	// Step 1: Extract metrics using AI (analyzing current scan only)
-	metrics, err := s.digitizer.ExtractMetrics(ctx, userID, imageData)
+	aiCtx, aiSpan := telemetry.StartSpan(ctx, "scan.extract_metrics")
+	metrics, err := s.digitizer.ExtractMetrics(aiCtx, userID, imageData)
+	telemetry.EndSpan(aiSpan, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract metrics: %w", err)
 	}
 
-	userObjectID, err := primitive.ObjectIDFromHex(userID)
-	if err != nil {
-		return nil, fmt.Errorf("invalid user id: %w", err)
-	}
-
-	// Step 2: Build InBodyRecord with V1 fields
-	record := &domain.InBodyRecord{
-		UserID:                   userObjectID,
-		TestDateTime:             metrics.TestDate,
-		Weight:                   metrics.Weight,
-		SMM:                      metrics.SMM,
-		BodyFatMass:              metrics.BodyFatMass,
-		BMI:                      metrics.BMI,
-		PBF:                      metrics.PBF,
-		BMR:                      metrics.BMR,
-		VisceralFatLevel:         metrics.VisceralFatLevel,
-		WaistHipRatio:            metrics.WaistHipRatio,
-		InBodyScore:              metrics.InBodyScore,
-		ObesityDegree:            metrics.ObesityDegree,
-		FatFreeMass:              metrics.FatFreeMass,
-		RecommendedCalorieIntake: metrics.RecommendedCalorieIntake,
-		TargetWeight:             metrics.TargetWeight,
-		WeightControl:            metrics.WeightControl,
-		FatControl:               metrics.FatControl,
-		MuscleControl:            metrics.MuscleControl,
-	}
-
-	// Step 2.5: Map V2 fields if present (backward compatible)
-	if metrics.SegmentalLean != nil {
-		record.SegmentalLean = metrics.SegmentalLean
-	}
-	if metrics.SegmentalFat != nil {
-		record.SegmentalFat = metrics.SegmentalFat
-	}
-	if metrics.Analysis != nil {
-		record.Analysis = metrics.Analysis
+	// Step 2: Build InBodyRecord from the extracted metrics
+	record = &domain.InBodyRecord{
+		UserID:       userID,
+		TestDateTime: metrics.TestDate,
 	}
+	applyMetrics(record, metrics)
 
 	record.Metadata.ImageURL = imageURL
-	record.Metadata.ProcessedAt = time.Now()
+	record.Metadata.ProcessedAt = s.clock.Now()
 
 	// Step 3: Save to MongoDB
-	if err := s.repository.Create(ctx, record); err != nil {
+	persistCtx, persistSpan := telemetry.StartSpan(ctx, "scan.persist")
+	digitized := &domain.OutboxMessage{Topic: domain.OutboxTopicScanDigitized}
+	err = s.recordScanChange(persistCtx, userID, "", func(ctx context.Context) error {
+		if err := s.repository.Create(ctx, record); err != nil {
+			return err
+		}
+		digitized.Key = record.ID
+		digitized.Payload = map[string]interface{}{"user_id": userID, "test_date": record.TestDateTime}
+		return nil
+	}, digitized)
+	telemetry.EndSpan(persistSpan, err)
+	if err != nil {
 		return nil, fmt.Errorf("failed to save record: %w", err)
 	}
 
@@ -116,11 +137,6 @@ func (s *ScanServiceImpl) ProcessScan(ctx context.Context, userID string, imageD
 		fmt.Printf("Warning: failed to cache latest scan: %v\n", err)
 	}
 
-	// Invalidate trend recap cache
-	if err := s.cache.InvalidateTrendRecap(ctx, userID); err != nil {
-		fmt.Printf("Warning: failed to invalidate trend recap cache: %v\n", err)
-	}
-
 	return record, nil
 }
 
@@ -137,7 +153,7 @@ func (s *ScanServiceImpl) GetScanByID(ctx context.Context, userID string, scanID
 	}
 
 	// Verify ownership
-	if record.UserID.Hex() != userID {
+	if record.UserID != userID {
 		return nil, domain.ErrForbidden
 	}
 
@@ -153,10 +169,16 @@ func (s *ScanServiceImpl) UpdateScan(ctx context.Context, userID string, scanID
 	}
 
 	// Verify ownership
-	if record.UserID.Hex() != userID {
+	if record.UserID != userID {
 		return nil, domain.ErrForbidden
 	}
 
+	// Optimistic concurrency: the client's copy must still be current
+	if version, ok := updates["version"].(float64); ok && int64(version) != record.Version {
+		return nil, domain.ErrVersionConflict
+	}
+	original := *record
+
 	// Apply updates to allowed fields
 	if weight, ok := updates["weight"].(float64); ok {
 		record.Weight = weight
@@ -207,15 +229,11 @@ func (s *ScanServiceImpl) UpdateScan(ctx context.Context, userID string, scanID
 		record.MuscleControl = muscleCtrl
 	}
 
-	// Update in database
-	if err := s.repository.Update(ctx, scanID, record); err != nil {
+	// Update in database, keeping the replaced values as a revision
+	if err := s.saveRevised(ctx, &original, record, domain.ScanRevisionEdit, userID); err != nil {
 		return nil, err
 	}
 
-	// Invalidate cache for this user
-	_ = s.cache.InvalidateUserCache(ctx, userID)
-	_ = s.cache.InvalidateTrendRecap(ctx, userID)
-
 	// Return updated record
 	return s.repository.FindByID(ctx, scanID)
 }
@@ -229,7 +247,7 @@ func (s *ScanServiceImpl) DeleteScan(ctx context.Context, userID string, scanID
 	}
 
 	// Verify ownership
-	if record.UserID.Hex() != userID {
+	if record.UserID != userID {
 		return domain.ErrForbidden
 	}
 
@@ -242,13 +260,119 @@ func (s *ScanServiceImpl) DeleteScan(ctx context.Context, userID string, scanID
 	}
 
 	// Delete from database
-	if err := s.repository.Delete(ctx, scanID); err != nil {
-		return err
+	return s.recordScanChange(ctx, userID, scanID, func(ctx context.Context) error {
+		return s.repository.Delete(ctx, scanID)
+	})
+}
+
+// ReExtract reruns the AI on the scan's stored image, e.g. after a prompt or model upgrade.
+// The test date is kept, and the replaced values are saved as a revision.
+func (s *ScanServiceImpl) ReExtract(ctx context.Context, scanID string, changedBy string) (record *domain.InBodyRecord, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "ScanService.ReExtract")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	record, err = s.repository.FindByID(ctx, scanID)
+	if err != nil {
+		return nil, err
+	}
+	if s.fileRepository == nil || record.Metadata.ImageURL == "" {
+		return nil, domain.ErrScanImageUnavailable
+	}
+	if err := s.checkConsent(ctx, record.UserID); err != nil {
+		return nil, err
 	}
 
-	// Invalidate cache for this user
-	_ = s.cache.InvalidateUserCache(ctx, userID)
-	_ = s.cache.InvalidateTrendRecap(ctx, userID)
+	imageData, err := s.fileRepository.Download(ctx, record.Metadata.ImageURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download image: %w", err)
+	}
+
+	aiCtx, aiSpan := telemetry.StartSpan(ctx, "scan.extract_metrics")
+	metrics, err := s.digitizer.ExtractMetrics(aiCtx, record.UserID, imageData)
+	telemetry.EndSpan(aiSpan, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract metrics: %w", err)
+	}
 
+	original := *record
+	applyMetrics(record, metrics)
+	record.Metadata.ProcessedAt = s.clock.Now()
+
+	if err := s.saveRevised(ctx, &original, record, domain.ScanRevisionReExtract, changedBy); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// recordScanChange applies a change to a user's scans and makes sure their cached scans
+// and trend recap are invalidated afterwards. The also messages are written with it; they
+// have no inline fallback, so without an outbox they are dropped.
+func (s *ScanServiceImpl) recordScanChange(ctx context.Context, userID, scanID string, change func(ctx context.Context) error, also ...*domain.OutboxMessage) error {
+	msg := &domain.OutboxMessage{
+		Topic:   domain.OutboxTopicScanChanged,
+		Key:     userID,
+		Payload: map[string]interface{}{"scan_id": scanID},
+	}
+	if s.outbox != nil {
+		return s.outbox.Write(ctx, change, append([]*domain.OutboxMessage{msg}, also...)...)
+	}
+
+	if err := change(ctx); err != nil {
+		return err
+	}
+	if err := s.HandleScanChanged(ctx, msg); err != nil {
+		fmt.Printf("Warning: failed to invalidate scan caches: %v\n", err)
+	}
 	return nil
 }
+
+// HandleScanChanged is the outbox handler that invalidates a user's scan caches and marks
+// their cached analytics histories stale
+func (s *ScanServiceImpl) HandleScanChanged(ctx context.Context, msg *domain.OutboxMessage) error {
+	userID := msg.Key
+	changedAt := msg.CreatedAt
+	if changedAt.IsZero() {
+		changedAt = s.clock.Now()
+	}
+	errs := []error{
+		s.cache.InvalidateUserCache(ctx, userID),
+		s.cache.InvalidateTrendRecap(ctx, userID),
+		s.cache.MarkScansChanged(ctx, userID, changedAt, scansChangedTTL),
+	}
+	if scanID, _ := msg.Payload["scan_id"].(string); scanID != "" {
+		errs = append(errs, s.cache.InvalidateScan(ctx, scanID))
+	}
+	return errors.Join(errs...)
+}
+
+// applyMetrics copies the AI's output onto a record. The V2 fields are only set when the
+// model returned them, so older records keep theirs.
+func applyMetrics(record *domain.InBodyRecord, metrics *domain.InBodyMetrics) {
+	record.Weight = metrics.Weight
+	record.SMM = metrics.SMM
+	record.BodyFatMass = metrics.BodyFatMass
+	record.BMI = metrics.BMI
+	record.PBF = metrics.PBF
+	record.BMR = metrics.BMR
+	record.VisceralFatLevel = metrics.VisceralFatLevel
+	record.WaistHipRatio = metrics.WaistHipRatio
+	record.InBodyScore = metrics.InBodyScore
+	record.ObesityDegree = metrics.ObesityDegree
+	record.FatFreeMass = metrics.FatFreeMass
+	record.RecommendedCalorieIntake = metrics.RecommendedCalorieIntake
+	record.TargetWeight = metrics.TargetWeight
+	record.WeightControl = metrics.WeightControl
+	record.FatControl = metrics.FatControl
+	record.MuscleControl = metrics.MuscleControl
+
+	if metrics.SegmentalLean != nil {
+		record.SegmentalLean = metrics.SegmentalLean
+	}
+	if metrics.SegmentalFat != nil {
+		record.SegmentalFat = metrics.SegmentalFat
+	}
+	if metrics.Analysis != nil {
+		record.Analysis = metrics.Analysis
+	}
+	record.Metadata.Model = metrics.Model
+}
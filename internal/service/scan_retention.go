@@ -0,0 +1,104 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png" // Scans are uploaded as JPEG or PNG
+	"log"
+	"time"
+)
+
+const (
+	// Archived scans stay readable for a coach and good enough to re-extract from
+	archivedScanMaxSide = 1600
+	archivedScanQuality = 80
+)
+
+// ArchiveImages replaces the original image of up to limit scans processed before the
+// cutoff with a downscaled JPEG. Images that can't be decoded (HEIC) are kept as they are
+// but still marked archived so they aren't retried every day. Returns how many were done.
+func (s *ScanServiceImpl) ArchiveImages(ctx context.Context, before time.Time, limit int) (int, error) {
+	if s.fileRepository == nil {
+		return 0, nil
+	}
+	records, err := s.repository.ListOriginalImages(ctx, before, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	archived := 0
+	for _, record := range records {
+		original := record.Metadata.ImageURL
+		data, err := s.fileRepository.Download(ctx, original)
+		if err != nil {
+			log.Printf("Warning: scan %s: failed to download image for archiving: %v", record.ID, err)
+			continue
+		}
+
+		imageURL := original
+		if small, err := downscaleImage(data, archivedScanMaxSide, archivedScanQuality); err != nil {
+			log.Printf("Warning: scan %s: keeping original image: %v", record.ID, err)
+		} else if len(small) < len(data) {
+			filename := fmt.Sprintf("archive/%s/%s.jpg", record.UserID, record.ID)
+			if imageURL, err = s.fileRepository.Upload(ctx, small, filename, "image/jpeg"); err != nil {
+				log.Printf("Warning: scan %s: failed to upload archived image: %v", record.ID, err)
+				continue
+			}
+		}
+
+		if err := s.repository.SetImageArchived(ctx, record.ID, imageURL, s.clock.Now()); err != nil {
+			return archived, err
+		}
+		if imageURL != original {
+			if err := s.fileRepository.Delete(ctx, original); err != nil {
+				log.Printf("Warning: scan %s: failed to delete original image: %v", record.ID, err)
+			}
+		}
+		archived++
+	}
+	return archived, nil
+}
+
+// downscaleImage re-encodes an image as JPEG, shrinking it so its longest side is at most
+// maxSide. Each output pixel averages the block of input pixels it covers.
+func downscaleImage(data []byte, maxSide int, quality int) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if longest := max(w, h); longest > maxSide {
+		w, h = max(1, w*maxSide/longest), max(1, h*maxSide/longest)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/h
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/h)
+		for x := 0; x < w; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/w
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/w)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a, n = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa), n+1
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / n >> 8), G: uint8(g / n >> 8), B: uint8(b / n >> 8), A: uint8(a / n >> 8)})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// scanMetrics are the numeric values a revision tracks, by JSON field name
+var scanMetrics = []struct {
+	field string
+	value func(r *domain.InBodyRecord) float64
+}{
+	{"weight", func(r *domain.InBodyRecord) float64 { return r.Weight }},
+	{"smm", func(r *domain.InBodyRecord) float64 { return r.SMM }},
+	{"body_fat_mass", func(r *domain.InBodyRecord) float64 { return r.BodyFatMass }},
+	{"bmi", func(r *domain.InBodyRecord) float64 { return r.BMI }},
+	{"pbf", func(r *domain.InBodyRecord) float64 { return r.PBF }},
+	{"bmr", func(r *domain.InBodyRecord) float64 { return float64(r.BMR) }},
+	{"visceral_fat", func(r *domain.InBodyRecord) float64 { return float64(r.VisceralFatLevel) }},
+	{"whr", func(r *domain.InBodyRecord) float64 { return r.WaistHipRatio }},
+	{"inbody_score", func(r *domain.InBodyRecord) float64 { return r.InBodyScore }},
+	{"obesity_degree", func(r *domain.InBodyRecord) float64 { return r.ObesityDegree }},
+	{"fat_free_mass", func(r *domain.InBodyRecord) float64 { return r.FatFreeMass }},
+	{"recommended_calorie_intake", func(r *domain.InBodyRecord) float64 { return float64(r.RecommendedCalorieIntake) }},
+	{"target_weight", func(r *domain.InBodyRecord) float64 { return r.TargetWeight }},
+	{"weight_control", func(r *domain.InBodyRecord) float64 { return r.WeightControl }},
+	{"fat_control", func(r *domain.InBodyRecord) float64 { return r.FatControl }},
+	{"muscle_control", func(r *domain.InBodyRecord) float64 { return r.MuscleControl }},
+}
+
+// SaveCorrection saves a coach's or member's edit of a scan
+func (s *ScanServiceImpl) SaveCorrection(ctx context.Context, original *domain.InBodyRecord, corrected *domain.InBodyRecord, changedBy string) error {
+	return s.saveRevised(ctx, original, corrected, domain.ScanRevisionEdit, changedBy)
+}
+
+// ListRevisions returns who changed the scan's values and what they were before
+func (s *ScanServiceImpl) ListRevisions(ctx context.Context, scanID string) ([]*domain.ScanRevision, error) {
+	return s.revisions.ListByScanID(ctx, scanID)
+}
+
+// RevertScan puts the values of a revision back, along with the model that produced them
+func (s *ScanServiceImpl) RevertScan(ctx context.Context, scanID string, revisionID string, changedBy string) (*domain.InBodyRecord, error) {
+	revision, err := s.revisions.GetByID(ctx, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	if revision.ScanID != scanID {
+		return nil, domain.ErrScanRevisionNotFound
+	}
+	record, err := s.repository.FindByID(ctx, scanID)
+	if err != nil {
+		return nil, err
+	}
+
+	original := *record
+	restoreValues(record, &revision.Values)
+	if err := s.saveRevised(ctx, &original, record, domain.ScanRevisionRevert, changedBy); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// saveRevised updates the scan and, if any value changed, keeps original as a revision in
+// the same write
+func (s *ScanServiceImpl) saveRevised(ctx context.Context, original *domain.InBodyRecord, record *domain.InBodyRecord, reason, changedBy string) error {
+	changes := scanChanges(original, record)
+	return s.recordScanChange(ctx, record.UserID, record.ID, func(ctx context.Context) error {
+		if err := s.repository.Update(ctx, record.ID, record); err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		return s.revisions.Create(ctx, &domain.ScanRevision{
+			ScanID:    record.ID,
+			UserID:    record.UserID,
+			Version:   original.Version,
+			Reason:    reason,
+			ChangedBy: changedBy,
+			Values:    *original,
+			Changes:   changes,
+		})
+	})
+}
+
+// scanChanges lists the values that differ between two versions of a scan
+func scanChanges(before, after *domain.InBodyRecord) []domain.ScanFieldChange {
+	var changes []domain.ScanFieldChange
+	for _, m := range scanMetrics {
+		from, to := m.value(before), m.value(after)
+		if from != to {
+			changes = append(changes, domain.ScanFieldChange{Field: m.field, From: &from, To: &to})
+		}
+	}
+	if !reflect.DeepEqual(before.SegmentalLean, after.SegmentalLean) {
+		changes = append(changes, domain.ScanFieldChange{Field: "segmental_lean"})
+	}
+	if !reflect.DeepEqual(before.SegmentalFat, after.SegmentalFat) {
+		changes = append(changes, domain.ScanFieldChange{Field: "segmental_fat"})
+	}
+	if !reflect.DeepEqual(before.Analysis, after.Analysis) {
+		changes = append(changes, domain.ScanFieldChange{Field: "analysis"})
+	}
+	return changes
+}
+
+// restoreValues copies the values kept in a revision onto the current record, leaving its
+// identity, test date, image and version alone
+func restoreValues(record *domain.InBodyRecord, values *domain.InBodyRecord) {
+	record.Weight = values.Weight
+	record.SMM = values.SMM
+	record.BodyFatMass = values.BodyFatMass
+	record.BMI = values.BMI
+	record.PBF = values.PBF
+	record.BMR = values.BMR
+	record.VisceralFatLevel = values.VisceralFatLevel
+	record.WaistHipRatio = values.WaistHipRatio
+	record.InBodyScore = values.InBodyScore
+	record.ObesityDegree = values.ObesityDegree
+	record.FatFreeMass = values.FatFreeMass
+	record.RecommendedCalorieIntake = values.RecommendedCalorieIntake
+	record.TargetWeight = values.TargetWeight
+	record.WeightControl = values.WeightControl
+	record.FatControl = values.FatControl
+	record.MuscleControl = values.MuscleControl
+
+	// The repository never clears V2 fields, so a revision without them leaves the current ones
+	if values.SegmentalLean != nil {
+		record.SegmentalLean = values.SegmentalLean
+	}
+	if values.SegmentalFat != nil {
+		record.SegmentalFat = values.SegmentalFat
+	}
+	if values.Analysis != nil {
+		record.Analysis = values.Analysis
+	}
+	record.Metadata.ProcessedAt = values.Metadata.ProcessedAt
+	record.Metadata.Model = values.Metadata.Model
+}
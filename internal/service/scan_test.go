@@ -0,0 +1,215 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type scanMocks struct {
+	digitizer *mocks.DigitizerService
+	records   *mocks.InBodyRepository
+	cache     *mocks.CacheRepository
+	files     *mocks.FileRepository
+	revisions *mocks.ScanRevisionRepository
+}
+
+func newTestScanService(t *testing.T) (*ScanServiceImpl, scanMocks) {
+	m := scanMocks{
+		digitizer: mocks.NewDigitizerService(t),
+		records:   mocks.NewInBodyRepository(t),
+		cache:     mocks.NewCacheRepository(t),
+		files:     mocks.NewFileRepository(t),
+		revisions: mocks.NewScanRevisionRepository(t),
+	}
+	return NewScanService(m.digitizer, m.records, m.cache, m.files, m.revisions, nil, clock.NewFake(testNow)), m
+}
+
+func TestScanService_ReExtract(t *testing.T) {
+	ctx := context.Background()
+	const scanID = "6650f0a1c2d3e4f5a6b7c8d9"
+
+	t.Run("keeps the coach-corrected values as a revision", func(t *testing.T) {
+		svc, m := newTestScanService(t)
+		stored := &domain.InBodyRecord{ID: scanID, UserID: "member-1", TestDateTime: testNow, Weight: 81.5, PBF: 19, Version: 3}
+		stored.Metadata.ImageURL = "https://files/inbody-scans/member-1/1.jpg"
+		stored.Metadata.Model = "old-model"
+		m.records.On("FindByID", anyCtx, scanID).Return(stored, nil)
+		m.files.On("Download", anyCtx, stored.Metadata.ImageURL).Return([]byte{0xFF, 0xD8}, nil)
+		m.digitizer.On("ExtractMetrics", anyCtx, "member-1", []byte{0xFF, 0xD8}).Return(&domain.InBodyMetrics{
+			Weight:   81.2,
+			PBF:      18.4,
+			TestDate: testNow.AddDate(0, 0, -1),
+			Model:    "new-model",
+		}, nil)
+		m.records.On("Update", anyCtx, scanID, mock.MatchedBy(func(r *domain.InBodyRecord) bool {
+			return r.Weight == 81.2 && r.Version == 3
+		})).Return(nil)
+
+		var revision *domain.ScanRevision
+		m.revisions.On("Create", anyCtx, mock.AnythingOfType("*domain.ScanRevision")).Run(func(args mock.Arguments) {
+			revision = args.Get(1).(*domain.ScanRevision)
+		}).Return(nil)
+		m.cache.On("InvalidateUserCache", anyCtx, "member-1").Return(nil)
+		m.cache.On("InvalidateTrendRecap", anyCtx, "member-1").Return(nil)
+		m.cache.On("MarkScansChanged", anyCtx, "member-1", mock.Anything, scansChangedTTL).Return(nil)
+		m.cache.On("InvalidateScan", anyCtx, scanID).Return(nil)
+
+		record, err := svc.ReExtract(ctx, scanID, "coach-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, 18.4, record.PBF)
+		assert.Equal(t, "new-model", record.Metadata.Model)
+		assert.Equal(t, testNow, record.TestDateTime, "the test date isn't re-read")
+		require.NotNil(t, revision)
+		assert.Equal(t, domain.ScanRevisionReExtract, revision.Reason)
+		assert.Equal(t, "coach-1", revision.ChangedBy)
+		assert.Equal(t, int64(3), revision.Version)
+		assert.Equal(t, 81.5, revision.Values.Weight)
+		assert.Equal(t, "old-model", revision.Values.Metadata.Model)
+	})
+
+	t.Run("scan without a stored image", func(t *testing.T) {
+		svc, m := newTestScanService(t)
+		m.records.On("FindByID", anyCtx, scanID).Return(&domain.InBodyRecord{ID: scanID, UserID: "member-1"}, nil)
+
+		_, err := svc.ReExtract(ctx, scanID, "coach-1")
+
+		assert.ErrorIs(t, err, domain.ErrScanImageUnavailable)
+	})
+}
+
+func TestScanService_ArchiveImages(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestScanService(t)
+
+	large := testJPEG(t, 3200, 2400)
+	photo := &domain.InBodyRecord{ID: "scan-1", UserID: "member-1"}
+	photo.Metadata.ImageURL = "https://files/inbody-scans/member-1/1.jpg"
+	heic := &domain.InBodyRecord{ID: "scan-2", UserID: "member-1"}
+	heic.Metadata.ImageURL = "https://files/inbody-scans/member-1/2.heic"
+
+	m.records.On("ListOriginalImages", ctx, testNow, 10).Return([]*domain.InBodyRecord{photo, heic}, nil)
+	m.files.On("Download", ctx, photo.Metadata.ImageURL).Return(large, nil)
+	m.files.On("Download", ctx, heic.Metadata.ImageURL).Return([]byte("ftypheic"), nil)
+
+	var uploaded []byte
+	m.files.On("Upload", ctx, mock.Anything, "archive/member-1/scan-1.jpg", "image/jpeg").Run(func(args mock.Arguments) {
+		uploaded = args.Get(1).([]byte)
+	}).Return("https://files/inbody-scans/archive/member-1/scan-1.jpg", nil)
+	m.records.On("SetImageArchived", ctx, "scan-1", "https://files/inbody-scans/archive/member-1/scan-1.jpg", testNow).Return(nil)
+	m.files.On("Delete", ctx, photo.Metadata.ImageURL).Return(nil)
+	// Undecodable images stay where they are
+	m.records.On("SetImageArchived", ctx, "scan-2", heic.Metadata.ImageURL, testNow).Return(nil)
+
+	archived, err := svc.ArchiveImages(ctx, testNow, 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, archived)
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(uploaded))
+	require.NoError(t, err)
+	assert.Equal(t, archivedScanMaxSide, cfg.Width)
+	assert.Equal(t, 1200, cfg.Height)
+}
+
+func testJPEG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: uint8(x ^ y), A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}))
+	return buf.Bytes()
+}
+
+func TestScanService_UpdateScan_RecordsRevision(t *testing.T) {
+	ctx := context.Background()
+	const scanID = "6650f0a1c2d3e4f5a6b7c8d9"
+	svc, m := newTestScanService(t)
+
+	stored := &domain.InBodyRecord{ID: scanID, UserID: "member-1", Weight: 81.5, BMR: 1700, Version: 2}
+	updated := &domain.InBodyRecord{ID: scanID, UserID: "member-1", Weight: 80.9, BMR: 1700, Version: 3}
+	m.records.On("FindByID", ctx, scanID).Return(stored, nil).Once()
+	m.records.On("Update", ctx, scanID, mock.AnythingOfType("*domain.InBodyRecord")).Return(nil)
+	m.records.On("FindByID", ctx, scanID).Return(updated, nil).Once()
+
+	var revision *domain.ScanRevision
+	m.revisions.On("Create", ctx, mock.AnythingOfType("*domain.ScanRevision")).Run(func(args mock.Arguments) {
+		revision = args.Get(1).(*domain.ScanRevision)
+	}).Return(nil)
+	m.cache.On("InvalidateUserCache", ctx, "member-1").Return(nil)
+	m.cache.On("InvalidateTrendRecap", ctx, "member-1").Return(nil)
+	m.cache.On("MarkScansChanged", ctx, "member-1", mock.Anything, scansChangedTTL).Return(nil)
+	m.cache.On("InvalidateScan", ctx, scanID).Return(nil)
+
+	_, err := svc.UpdateScan(ctx, "member-1", scanID, map[string]interface{}{"weight": 80.9, "bmr": 1700.0})
+
+	require.NoError(t, err)
+	require.NotNil(t, revision)
+	assert.Equal(t, domain.ScanRevisionEdit, revision.Reason)
+	assert.Equal(t, "member-1", revision.ChangedBy)
+	assert.Equal(t, 81.5, revision.Values.Weight)
+	require.Len(t, revision.Changes, 1, "unchanged values aren't listed")
+	assert.Equal(t, "weight", revision.Changes[0].Field)
+	assert.Equal(t, 81.5, *revision.Changes[0].From)
+	assert.Equal(t, 80.9, *revision.Changes[0].To)
+}
+
+func TestScanService_RevertScan(t *testing.T) {
+	ctx := context.Background()
+	const scanID = "6650f0a1c2d3e4f5a6b7c8d9"
+
+	t.Run("restores the revision's values", func(t *testing.T) {
+		svc, m := newTestScanService(t)
+		kept := domain.InBodyRecord{ID: scanID, UserID: "member-1", Weight: 81.5, PBF: 19}
+		kept.Metadata.Model = "old-model"
+		current := &domain.InBodyRecord{ID: scanID, UserID: "member-1", TestDateTime: testNow, Weight: 81.2, PBF: 18.4, Version: 4}
+		current.Metadata.Model = "new-model"
+
+		m.revisions.On("GetByID", ctx, "rev-1").Return(&domain.ScanRevision{ID: "rev-1", ScanID: scanID, Values: kept}, nil)
+		m.records.On("FindByID", ctx, scanID).Return(current, nil)
+		m.records.On("Update", ctx, scanID, mock.AnythingOfType("*domain.InBodyRecord")).Return(nil)
+
+		var revision *domain.ScanRevision
+		m.revisions.On("Create", ctx, mock.AnythingOfType("*domain.ScanRevision")).Run(func(args mock.Arguments) {
+			revision = args.Get(1).(*domain.ScanRevision)
+		}).Return(nil)
+		m.cache.On("InvalidateUserCache", ctx, "member-1").Return(nil)
+		m.cache.On("InvalidateTrendRecap", ctx, "member-1").Return(nil)
+		m.cache.On("MarkScansChanged", ctx, "member-1", mock.Anything, scansChangedTTL).Return(nil)
+		m.cache.On("InvalidateScan", ctx, scanID).Return(nil)
+
+		record, err := svc.RevertScan(ctx, scanID, "rev-1", "coach-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, 81.5, record.Weight)
+		assert.Equal(t, "old-model", record.Metadata.Model)
+		assert.Equal(t, testNow, record.TestDateTime)
+		require.NotNil(t, revision)
+		assert.Equal(t, domain.ScanRevisionRevert, revision.Reason)
+		assert.Equal(t, 81.2, revision.Values.Weight, "the reverted values can be restored again")
+		assert.Equal(t, int64(4), revision.Version)
+	})
+
+	t.Run("revision of another scan", func(t *testing.T) {
+		svc, m := newTestScanService(t)
+		m.revisions.On("GetByID", ctx, "rev-1").Return(&domain.ScanRevision{ID: "rev-1", ScanID: "another-scan"}, nil)
+
+		_, err := svc.RevertScan(ctx, scanID, "rev-1", "coach-1")
+
+		assert.ErrorIs(t, err, domain.ErrScanRevisionNotFound)
+	})
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/telemetry"
+)
+
+// CreateScheduleBatch books a session for each slot on the base schedule's contract, as when
+// a coach assigns a whole program. The contract is checked once, as CreateSchedule would;
+// slots that overlap another session, fall outside the coach's hours or have no credit left
+// are skipped and reported. Earlier slots get the credits first. The rest are created in one
+// transaction with a single push to the member, so a failure books none of them.
+func (s *PTService) CreateScheduleBatch(ctx context.Context, base *domain.Schedule, slots []domain.ScheduleSlot) (result *domain.ScheduleBatchResult, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "PTService.CreateScheduleBatch",
+		telemetry.TenantID(base.TenantID), telemetry.MemberID(base.MemberID))
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	if len(slots) == 0 || len(slots) > domain.MaxScheduleBatch {
+		return nil, domain.ErrInvalidScheduleBatch
+	}
+	if base.Tags, err = domain.NormalizeScheduleTags(base.Tags); err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateScheduleLabel(base.Label, base.Color); err != nil {
+		return nil, err
+	}
+	if err := base.NormalizeModality(); err != nil {
+		return nil, err
+	}
+
+	contract, err := s.contractRepo.GetByID(ctx, base.ContractID)
+	if err != nil {
+		return nil, err
+	}
+	if contract.Status == domain.PackageStatusSuspended {
+		return nil, domain.ErrContractSuspended
+	}
+	if contract.Status != domain.PackageStatusActive || contract.RemainingSessions <= 0 {
+		return nil, domain.ErrPackageDepleted
+	}
+	if contract.MemberID != base.MemberID {
+		return nil, errors.New("contract does not belong to this member")
+	}
+	if contract.BranchID != base.BranchID {
+		return nil, domain.ErrBranchMismatch
+	}
+	if s.documents != nil {
+		if err := s.documents.CheckBookingAllowed(ctx, contract.TenantID, contract.MemberID); err != nil {
+			return nil, err
+		}
+	}
+
+	scheduledCount, err := s.schedRepo.CountByContractAndStatus(ctx, contract.ID, []string{domain.ScheduleStatusScheduled, domain.ScheduleStatusPendingConfirmation})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing schedules: %w", err)
+	}
+	credits := contract.RemainingSessions - int(scheduledCount)
+
+	availability, err := s.coachAvailability(ctx, base.CoachID)
+	if err != nil {
+		return nil, err
+	}
+
+	result = &domain.ScheduleBatchResult{Created: []*domain.Schedule{}, Skipped: []domain.SkippedSlot{}}
+	valid := make([]domain.ScheduleSlot, 0, len(slots))
+	for _, slot := range slots {
+		if slot.EndTime.IsZero() {
+			slot.EndTime = slot.StartTime.Add(time.Hour)
+		}
+		if slot.StartTime.IsZero() || !slot.EndTime.After(slot.StartTime) {
+			result.Skipped = append(result.Skipped, domain.SkippedSlot{ScheduleSlot: slot, Reason: domain.SkipReasonInvalidTime})
+			continue
+		}
+		valid = append(valid, slot)
+	}
+	if len(valid) == 0 {
+		return result, nil
+	}
+	sort.SliceStable(valid, func(i, j int) bool { return valid[i].StartTime.Before(valid[j].StartTime) })
+	booked, err := s.sessionsAround(ctx, base, valid)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, slot := range valid {
+		schedule := *base
+		schedule.ClientID = slot.ClientID
+		schedule.StartTime, schedule.EndTime = slot.StartTime, slot.EndTime
+		if slot.SessionGoal != "" {
+			schedule.SessionGoal = slot.SessionGoal
+		}
+		if slot.FocusArea != "" {
+			schedule.FocusArea = slot.FocusArea
+		}
+		schedule.Tags = append([]string(nil), base.Tags...)
+		schedule.Status = domain.ScheduleStatusScheduled
+
+		reason := ""
+		switch {
+		case overlapsAny(&schedule, booked):
+			reason = domain.SkipReasonConflict
+		case !availabilityCovers(availability, &schedule):
+			reason = domain.SkipReasonOutsideAvailability
+		case len(result.Created) >= credits:
+			reason = domain.SkipReasonNoCredits
+		}
+		if reason != "" {
+			result.Skipped = append(result.Skipped, domain.SkippedSlot{ScheduleSlot: slot, Reason: reason})
+			continue
+		}
+		booked = append(booked, &schedule)
+		result.Created = append(result.Created, &schedule)
+	}
+	if len(result.Created) == 0 {
+		return result, nil
+	}
+
+	for _, schedule := range result.Created {
+		s.attachMeeting(ctx, schedule)
+	}
+	msg := scheduleChangedMessage(base, domain.ScheduleChangeBatchBooked)
+	msg.Payload["count"] = strconv.Itoa(len(result.Created))
+	err = s.recordChange(ctx, func(ctx context.Context) error {
+		for _, schedule := range result.Created {
+			if err := s.schedRepo.Create(ctx, schedule); err != nil {
+				return err
+			}
+		}
+		msg.Key = result.Created[0].ID
+		return nil
+	}, msg)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// sessionsAround returns the live sessions of the coach and the member around the slots,
+// which must be sorted by start time
+func (s *PTService) sessionsAround(ctx context.Context, base *domain.Schedule, slots []domain.ScheduleSlot) ([]*domain.Schedule, error) {
+	// A session starting up to a day before the first slot may still run into it
+	from := slots[0].StartTime.AddDate(0, 0, -1)
+	to := slots[len(slots)-1].StartTime
+	for _, slot := range slots {
+		if slot.EndTime.After(to) {
+			to = slot.EndTime
+		}
+	}
+
+	coachSessions, err := s.schedRepo.GetByCoach(ctx, base.CoachID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load coach schedule: %w", err)
+	}
+	memberSessions, err := s.schedRepo.GetByMember(ctx, base.MemberID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load member schedule: %w", err)
+	}
+	// Unlike GetByCoach, GetByMember returns cancelled and deleted sessions too
+	for _, sched := range memberSessions {
+		if sched.DeletedAt == nil && sched.Status != domain.ScheduleStatusCancelled {
+			coachSessions = append(coachSessions, sched)
+		}
+	}
+	return coachSessions, nil
+}
+
+func overlapsAny(schedule *domain.Schedule, others []*domain.Schedule) bool {
+	for _, other := range others {
+		if schedule.StartTime.Before(other.EndTime) && other.StartTime.Before(schedule.EndTime) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPTService_CreateScheduleBatch(t *testing.T) {
+	ctx := context.Background()
+	monday := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	week := func(n int) time.Time { return monday.AddDate(0, 0, 7*n) }
+	base := func() *domain.Schedule {
+		return &domain.Schedule{TenantID: "gym", ContractID: "contract-1", CoachID: "coach-1", MemberID: "member-1", BranchID: "br-1", FocusArea: "FULL_BODY"}
+	}
+
+	t.Run("books what fits and reports the rest", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		outboxRepo, tx := mocks.NewOutboxRepository(t), mocks.NewTransactor(t)
+		svc.outbox = NewOutbox(outboxRepo, tx, nil)
+
+		m.contractRepo.On("GetByID", anyCtx, "contract-1").Return(&domain.PTContract{ID: "contract-1", MemberID: "member-1", BranchID: "br-1",
+			RemainingSessions: 4, Status: domain.PackageStatusActive}, nil)
+		m.schedRepo.On("CountByContractAndStatus", anyCtx, "contract-1", mock.Anything).Return(int64(1), nil)
+		m.schedRepo.On("GetByCoach", anyCtx, "coach-1", week(0).AddDate(0, 0, -1), week(4).Add(time.Hour)).Return([]*domain.Schedule{
+			{ID: "other", StartTime: week(1).Add(30 * time.Minute), EndTime: week(1).Add(90 * time.Minute)},
+		}, nil)
+		m.schedRepo.On("GetByMember", anyCtx, "member-1", mock.Anything, mock.Anything).Return([]*domain.Schedule{
+			{ID: "cancelled", StartTime: week(2), EndTime: week(2).Add(time.Hour), Status: domain.ScheduleStatusCancelled},
+		}, nil)
+		tx.On("WithinTransaction", anyCtx, mock.Anything).Return(runInline)
+		created := 0
+		m.schedRepo.On("Create", anyCtx, mock.AnythingOfType("*domain.Schedule")).Run(func(args mock.Arguments) {
+			created++
+			args.Get(1).(*domain.Schedule).ID = fmt.Sprintf("sched-%d", created)
+		}).Return(nil).Times(3)
+		outboxRepo.On("Add", anyCtx, mock.MatchedBy(func(msg *domain.OutboxMessage) bool {
+			return msg.Key == "sched-1" && msg.Payload["change"] == domain.ScheduleChangeBatchBooked && msg.Payload["count"] == "3"
+		})).Return(nil).Once()
+
+		result, err := svc.CreateScheduleBatch(ctx, base(), []domain.ScheduleSlot{
+			{StartTime: week(4)}, // Out of order: earlier slots get the credits
+			{StartTime: week(0), FocusArea: "LEG_DAY"},
+			{StartTime: week(1)},
+			{StartTime: week(2)},
+			{StartTime: week(3), EndTime: week(3).Add(-time.Hour)},
+			{StartTime: week(3).Add(time.Hour)},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, result.Created, 3)
+		assert.Equal(t, week(0), result.Created[0].StartTime)
+		assert.Equal(t, "LEG_DAY", result.Created[0].FocusArea)
+		assert.Equal(t, "FULL_BODY", result.Created[1].FocusArea)
+		assert.Equal(t, domain.ScheduleStatusScheduled, result.Created[2].Status)
+		reasons := map[time.Time]string{}
+		for _, skipped := range result.Skipped {
+			reasons[skipped.StartTime] = skipped.Reason
+		}
+		assert.Equal(t, map[time.Time]string{
+			week(3): domain.SkipReasonInvalidTime,
+			week(1): domain.SkipReasonConflict,
+			week(4): domain.SkipReasonNoCredits,
+		}, reasons)
+	})
+
+	t.Run("rejects a depleted contract outright", func(t *testing.T) {
+		svc, m := newTestPTService(t)
+		m.contractRepo.On("GetByID", anyCtx, "contract-1").Return(&domain.PTContract{ID: "contract-1", MemberID: "member-1", BranchID: "br-1",
+			Status: domain.PackageStatusActive}, nil)
+
+		_, err := svc.CreateScheduleBatch(ctx, base(), []domain.ScheduleSlot{{StartTime: week(0)}})
+
+		assert.ErrorIs(t, err, domain.ErrPackageDepleted)
+	})
+
+	t.Run("rejects an empty batch", func(t *testing.T) {
+		svc, _ := newTestPTService(t)
+
+		_, err := svc.CreateScheduleBatch(ctx, base(), nil)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidScheduleBatch)
+	})
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"golang.org/x/sync/errgroup"
+)
+
+// SearchService backs the coach app's search bar: one query over the coach's clients, their
+// sessions and scans, and the exercise library
+type SearchService struct {
+	searchRepo   domain.SearchRepository
+	contractRepo domain.PTContractRepository
+}
+
+func NewSearchService(searchRepo domain.SearchRepository, contractRepo domain.PTContractRepository) *SearchService {
+	return &SearchService{searchRepo: searchRepo, contractRepo: contractRepo}
+}
+
+// Search finds what the coach may see matching the text, most relevant first, with at
+// most limit results of each kind. Clients are the members of the coach's active contracts
+// in the tenant.
+func (s *SearchService) Search(ctx context.Context, tenantID, coachID, text string, limit int) (*domain.SearchResults, error) {
+	q, err := domain.SearchQuery{Text: text, TenantID: tenantID, CoachID: coachID, Limit: limit}.Normalized()
+	if err != nil {
+		return nil, err
+	}
+
+	contracts, err := s.contractRepo.GetActiveContractsWithMembers(ctx, coachID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clients: %w", err)
+	}
+	names := make(map[string]string)
+	for _, cwm := range contracts {
+		if cwm.Contract == nil || cwm.Contract.TenantID != tenantID {
+			continue
+		}
+		if _, ok := names[cwm.Contract.MemberID]; !ok {
+			q.MemberIDs = append(q.MemberIDs, cwm.Contract.MemberID)
+			names[cwm.Contract.MemberID] = ""
+		}
+		if cwm.Member != nil {
+			names[cwm.Contract.MemberID] = cwm.Member.Name
+		}
+	}
+
+	var (
+		members   []domain.Scored[*domain.User]
+		schedules []domain.Scored[*domain.Schedule]
+		exercises []domain.Scored[*domain.Exercise]
+		scans     []domain.Scored[*domain.InBodyRecord]
+	)
+	g, gCtx := errgroup.WithContext(ctx)
+	g.Go(func() (err error) {
+		members, err = s.searchRepo.SearchMembers(gCtx, q.MemberIDs, q.Text, q.Limit)
+		return err
+	})
+	g.Go(func() (err error) {
+		schedules, err = s.searchRepo.SearchSchedules(gCtx, q.TenantID, q.CoachID, q.Text, q.Limit)
+		return err
+	})
+	g.Go(func() (err error) {
+		exercises, err = s.searchRepo.SearchExercises(gCtx, q.TenantID, q.Text, q.Limit)
+		return err
+	})
+	g.Go(func() (err error) {
+		scans, err = s.searchRepo.SearchScans(gCtx, q.MemberIDs, q.Text, q.Limit)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	results := make([]*domain.SearchResult, 0, len(members)+len(schedules)+len(exercises)+len(scans))
+	for _, hit := range members {
+		results = append(results, &domain.SearchResult{
+			Kind: domain.SearchKindClient, ID: hit.Item.ID, Title: hit.Item.Name, Subtitle: hit.Item.Email,
+			MemberID: hit.Item.ID, Score: hit.Score,
+		})
+	}
+	for _, hit := range schedules {
+		start := hit.Item.StartTime
+		results = append(results, &domain.SearchResult{
+			Kind: domain.SearchKindSchedule, ID: hit.Item.ID, Title: scheduleTitle(hit.Item), Subtitle: names[hit.Item.MemberID],
+			MemberID: hit.Item.MemberID, Date: &start, Score: hit.Score,
+		})
+	}
+	for _, hit := range tenantExercisesFirst(exercises) {
+		results = append(results, &domain.SearchResult{
+			Kind: domain.SearchKindExercise, ID: hit.Item.ID, Title: hit.Item.Name,
+			Subtitle: strings.Trim(hit.Item.MuscleGroup+" · "+hit.Item.Equipment, " ·"), Score: hit.Score,
+		})
+	}
+	for _, hit := range scans {
+		tested := hit.Item.TestDateTime
+		results = append(results, &domain.SearchResult{
+			Kind: domain.SearchKindScan, ID: hit.Item.ID, Title: "Scan of " + names[hit.Item.UserID], Subtitle: scanSummary(hit.Item),
+			MemberID: hit.Item.UserID, Date: &tested, Score: hit.Score,
+		})
+	}
+
+	// Scores of different collections are comparable enough to interleave; ties keep
+	// clients, sessions, exercises and scans in that order
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return &domain.SearchResults{Query: q.Text, Results: results}, nil
+}
+
+// tenantExercisesFirst drops global exercises the tenant has its own version of, as the
+// exercise library does
+func tenantExercisesFirst(hits []domain.Scored[*domain.Exercise]) []domain.Scored[*domain.Exercise] {
+	exercises := make([]*domain.Exercise, 0, len(hits))
+	scores := make(map[*domain.Exercise]float64, len(hits))
+	for _, hit := range hits {
+		exercises = append(exercises, hit.Item)
+		scores[hit.Item] = hit.Score
+	}
+	merged := domain.MergeExerciseLibraries(exercises)
+	kept := make([]domain.Scored[*domain.Exercise], 0, len(merged))
+	for _, ex := range merged {
+		kept = append(kept, domain.Scored[*domain.Exercise]{Item: ex, Score: scores[ex]})
+	}
+	return kept
+}
+
+func scheduleTitle(schedule *domain.Schedule) string {
+	switch {
+	case schedule.SessionGoal != "":
+		return schedule.SessionGoal
+	case schedule.Label != "":
+		return schedule.Label
+	case schedule.FocusArea != "":
+		return schedule.FocusArea
+	}
+	return "Session"
+}
+
+func scanSummary(record *domain.InBodyRecord) string {
+	if record.Analysis == nil {
+		return ""
+	}
+	return record.Analysis.Summary
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchService_Search(t *testing.T) {
+	// The searches run concurrently on a derived context
+	anyCtx := mock.Anything
+
+	setup := func(t *testing.T) (*SearchService, *mocks.SearchRepository) {
+		searchRepo := mocks.NewSearchRepository(t)
+		contractRepo := mocks.NewPTContractRepository(t)
+		contractRepo.On("GetActiveContractsWithMembers", context.Background(), "coach-1").Return([]*domain.ContractWithMember{
+			{Contract: &domain.PTContract{ID: "c-1", TenantID: "t-1", MemberID: "alice"}, Member: &domain.User{ID: "alice", Name: "Alice"}},
+			{Contract: &domain.PTContract{ID: "c-2", TenantID: "t-1", MemberID: "alice"}, Member: &domain.User{ID: "alice", Name: "Alice"}},
+			{Contract: &domain.PTContract{ID: "c-3", TenantID: "t-2", MemberID: "bob"}, Member: &domain.User{ID: "bob", Name: "Bob"}},
+		}, nil).Maybe()
+		return NewSearchService(searchRepo, contractRepo), searchRepo
+	}
+
+	t.Run("merges every kind, most relevant first, scoped to the tenant's clients", func(t *testing.T) {
+		svc, searchRepo := setup(t)
+		searchRepo.On("SearchMembers", anyCtx, []string{"alice"}, "leg", domain.DefaultSearchLimit).
+			Return([]domain.Scored[*domain.User]{{Item: &domain.User{ID: "alice", Name: "Alice Legge", Email: "a@x.io"}, Score: 1.1}}, nil)
+		searchRepo.On("SearchSchedules", anyCtx, "t-1", "coach-1", "leg", domain.DefaultSearchLimit).
+			Return([]domain.Scored[*domain.Schedule]{{Item: &domain.Schedule{ID: "s-1", MemberID: "alice", FocusArea: "Leg day", StartTime: testNow}, Score: 2.5}}, nil)
+		searchRepo.On("SearchExercises", anyCtx, "t-1", "leg", domain.DefaultSearchLimit).
+			Return([]domain.Scored[*domain.Exercise]{
+				{Item: &domain.Exercise{ID: "e-1", Name: "Leg Press", MuscleGroup: "Legs", Equipment: "Machine"}, Score: 3},
+				{Item: &domain.Exercise{ID: "e-2", TenantID: "t-1", Name: "leg press", MuscleGroup: "Legs"}, Score: 2.9},
+			}, nil)
+		searchRepo.On("SearchScans", anyCtx, []string{"alice"}, "leg", domain.DefaultSearchLimit).
+			Return([]domain.Scored[*domain.InBodyRecord]{{
+				Item:  &domain.InBodyRecord{ID: "r-1", UserID: "alice", TestDateTime: testNow, Analysis: &domain.BodyAnalysis{Summary: "Leg lean mass up"}},
+				Score: 0.6,
+			}}, nil)
+
+		results, err := svc.Search(context.Background(), "t-1", "coach-1", "  leg ", 0)
+		require.NoError(t, err)
+		assert.Equal(t, "leg", results.Query)
+		require.Len(t, results.Results, 4)
+
+		exercise, schedule, client, scan := results.Results[0], results.Results[1], results.Results[2], results.Results[3]
+		// The tenant's own Leg Press replaces the global one
+		assert.Equal(t, &domain.SearchResult{Kind: domain.SearchKindExercise, ID: "e-2", Title: "leg press", Subtitle: "Legs", Score: 2.9}, exercise)
+		assert.Equal(t, domain.SearchKindSchedule, schedule.Kind)
+		assert.Equal(t, "Leg day", schedule.Title)
+		assert.Equal(t, "Alice", schedule.Subtitle)
+		assert.Equal(t, testNow, *schedule.Date)
+		assert.Equal(t, &domain.SearchResult{Kind: domain.SearchKindClient, ID: "alice", Title: "Alice Legge", Subtitle: "a@x.io", MemberID: "alice", Score: 1.1}, client)
+		assert.Equal(t, domain.SearchKindScan, scan.Kind)
+		assert.Equal(t, "Scan of Alice", scan.Title)
+		assert.Equal(t, "Leg lean mass up", scan.Subtitle)
+	})
+
+	t.Run("bounds the limit", func(t *testing.T) {
+		svc, searchRepo := setup(t)
+		searchRepo.On("SearchMembers", anyCtx, []string{"alice"}, "squat", domain.MaxSearchLimit).Return(nil, nil)
+		searchRepo.On("SearchSchedules", anyCtx, "t-1", "coach-1", "squat", domain.MaxSearchLimit).Return(nil, nil)
+		searchRepo.On("SearchExercises", anyCtx, "t-1", "squat", domain.MaxSearchLimit).Return(nil, nil)
+		searchRepo.On("SearchScans", anyCtx, []string{"alice"}, "squat", domain.MaxSearchLimit).Return(nil, nil)
+
+		results, err := svc.Search(context.Background(), "t-1", "coach-1", "squat", 500)
+		require.NoError(t, err)
+		assert.Empty(t, results.Results)
+	})
+
+	t.Run("fails when a search fails", func(t *testing.T) {
+		svc, searchRepo := setup(t)
+		searchRepo.On("SearchMembers", anyCtx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+		searchRepo.On("SearchSchedules", anyCtx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("boom"))
+		searchRepo.On("SearchExercises", anyCtx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+		searchRepo.On("SearchScans", anyCtx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+
+		_, err := svc.Search(context.Background(), "t-1", "coach-1", "squat", 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects text too short or too long", func(t *testing.T) {
+		svc, _ := setup(t)
+		for _, text := range []string{"", " a ", string(make([]rune, 101))} {
+			_, err := svc.Search(context.Background(), "t-1", "coach-1", text, 0)
+			assert.ErrorIs(t, err, domain.ErrInvalidSearchQuery)
+		}
+	})
+}
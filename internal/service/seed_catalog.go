@@ -0,0 +1,345 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// ParseSeedCatalog reads a catalog file, picking YAML or JSON by its extension. Unknown
+// fields are rejected so typos don't silently drop data. The catalog is not validated.
+func ParseSeedCatalog(filename string, data []byte) (*domain.SeedCatalog, error) {
+	var catalog domain.SeedCatalog
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&catalog); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: %s: %v", domain.ErrInvalidSeedCatalog, filename, err)
+		}
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&catalog); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", domain.ErrInvalidSeedCatalog, filename, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s: use a .yaml, .yml or .json file", domain.ErrInvalidSeedCatalog, filename)
+	}
+	return &catalog, nil
+}
+
+// SeedCatalogService upserts catalog entries by name: missing entries are created, entries
+// whose fields differ are updated, and nothing is ever deleted
+type SeedCatalogService struct {
+	exerciseRepo  domain.ExerciseRepository
+	templateRepo  domain.TemplateRepository
+	pkgRepo       domain.PTPackageRepository
+	equipmentRepo domain.EquipmentRepository
+	branchRepo    domain.BranchRepository
+}
+
+func NewSeedCatalogService(
+	exerciseRepo domain.ExerciseRepository,
+	templateRepo domain.TemplateRepository,
+	pkgRepo domain.PTPackageRepository,
+	equipmentRepo domain.EquipmentRepository,
+	branchRepo domain.BranchRepository,
+) *SeedCatalogService {
+	return &SeedCatalogService{
+		exerciseRepo:  exerciseRepo,
+		templateRepo:  templateRepo,
+		pkgRepo:       pkgRepo,
+		equipmentRepo: equipmentRepo,
+		branchRepo:    branchRepo,
+	}
+}
+
+// Apply validates the catalog and upserts it. tenantID is required for packages and
+// equipment. With dryRun nothing is written but the counts are what a real run would do.
+func (s *SeedCatalogService) Apply(ctx context.Context, catalog *domain.SeedCatalog, tenantID string, dryRun bool) (*domain.SeedCatalogResult, error) {
+	if problems := catalog.Validate(tenantID != ""); len(problems) > 0 {
+		return nil, fmt.Errorf("%w:\n  %s", domain.ErrInvalidSeedCatalog, strings.Join(problems, "\n  "))
+	}
+
+	result := &domain.SeedCatalogResult{}
+	exerciseIDs, err := s.applyExercises(ctx, catalog.Exercises, dryRun, result)
+	if err != nil {
+		return result, err
+	}
+	if err := s.applyTemplates(ctx, catalog.Templates, exerciseIDs, dryRun, result); err != nil {
+		return result, err
+	}
+	if len(catalog.Packages) == 0 && len(catalog.Equipment) == 0 {
+		return result, nil
+	}
+
+	branches, err := s.branchRepo.GetByTenantID(ctx, tenantID)
+	if err != nil {
+		return result, fmt.Errorf("failed to load branches: %w", err)
+	}
+	if err := s.applyPackages(ctx, catalog.Packages, tenantID, branches, dryRun, result); err != nil {
+		return result, err
+	}
+	if err := s.applyEquipment(ctx, catalog.Equipment, tenantID, branches, dryRun, result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// applyExercises returns the IDs of every library exercise by lower-cased name, including
+// the ones just created. In a dry run, new exercises map to an empty ID.
+func (s *SeedCatalogService) applyExercises(ctx context.Context, entries []domain.CatalogExercise, dryRun bool, result *domain.SeedCatalogResult) (map[string]string, error) {
+	// The whole library is loaded once: List matches names as substrings
+	library, err := s.exerciseRepo.List(ctx, map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load exercises: %w", err)
+	}
+	existing := make(map[string]*domain.Exercise, len(library))
+	ids := make(map[string]string, len(library))
+	for _, ex := range library {
+		key := strings.ToLower(ex.Name)
+		existing[key] = ex
+		ids[key] = ex.ID
+	}
+
+	for _, entry := range entries {
+		want := domain.Exercise{
+			Name:         strings.TrimSpace(entry.Name),
+			MuscleGroup:  strings.TrimSpace(entry.MuscleGroup),
+			Equipment:    strings.TrimSpace(entry.Equipment),
+			VideoURL:     entry.VideoURL,
+			ReferenceURL: entry.ReferenceURL,
+		}
+		key := strings.ToLower(want.Name)
+
+		current, ok := existing[key]
+		if !ok {
+			if !dryRun {
+				if err := s.exerciseRepo.Create(ctx, &want); err != nil {
+					return nil, fmt.Errorf("failed to create exercise %s: %w", want.Name, err)
+				}
+			}
+			ids[key] = want.ID
+			result.Exercises.Created++
+			continue
+		}
+
+		if current.MuscleGroup == want.MuscleGroup && current.Equipment == want.Equipment &&
+			current.VideoURL == want.VideoURL && current.ReferenceURL == want.ReferenceURL {
+			result.Exercises.Unchanged++
+			continue
+		}
+		current.MuscleGroup, current.Equipment = want.MuscleGroup, want.Equipment
+		current.VideoURL, current.ReferenceURL = want.VideoURL, want.ReferenceURL
+		if !dryRun {
+			if err := s.exerciseRepo.Update(ctx, current); err != nil {
+				return nil, fmt.Errorf("failed to update exercise %s: %w", current.Name, err)
+			}
+		}
+		result.Exercises.Updated++
+	}
+	return ids, nil
+}
+
+func (s *SeedCatalogService) applyTemplates(ctx context.Context, entries []domain.CatalogTemplate, exerciseIDs map[string]string, dryRun bool, result *domain.SeedCatalogResult) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	templates, err := s.templateRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load templates: %w", err)
+	}
+	existing := make(map[string]*domain.WorkoutTemplate, len(templates))
+	for _, tpl := range templates {
+		existing[strings.ToLower(tpl.Name)] = tpl
+	}
+
+entries:
+	for _, entry := range entries {
+		name := strings.TrimSpace(entry.Name)
+		gender := entry.Gender
+		if gender == "" {
+			gender = "All"
+		}
+		ids := make([]string, 0, len(entry.Exercises))
+		for _, exName := range entry.Exercises {
+			id, ok := exerciseIDs[strings.ToLower(strings.TrimSpace(exName))]
+			if !ok {
+				result.Issues = append(result.Issues, fmt.Sprintf("template %q skipped: exercise %q is not in the library or the catalog", name, exName))
+				continue entries
+			}
+			ids = append(ids, id)
+		}
+
+		current, ok := existing[strings.ToLower(name)]
+		if !ok {
+			if !dryRun {
+				if err := s.templateRepo.Create(ctx, &domain.WorkoutTemplate{Name: name, Gender: gender, ExerciseIDs: ids}); err != nil {
+					return fmt.Errorf("failed to create template %s: %w", name, err)
+				}
+			}
+			result.Templates.Created++
+			continue
+		}
+
+		if current.Gender == gender && slices.Equal(current.ExerciseIDs, ids) {
+			result.Templates.Unchanged++
+			continue
+		}
+		current.Gender, current.ExerciseIDs = gender, ids
+		if !dryRun {
+			if err := s.templateRepo.Update(ctx, current); err != nil {
+				return fmt.Errorf("failed to update template %s: %w", name, err)
+			}
+		}
+		result.Templates.Updated++
+	}
+	return nil
+}
+
+func (s *SeedCatalogService) applyPackages(ctx context.Context, entries []domain.CatalogPackage, tenantID string, branches []*domain.Branch, dryRun bool, result *domain.SeedCatalogResult) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	packages, err := s.pkgRepo.GetByTenant(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to load packages: %w", err)
+	}
+	existing := make(map[string]*domain.PTPackage, len(packages))
+	for _, pkg := range packages {
+		existing[pkg.BranchID+"/"+strings.ToLower(pkg.Name)] = pkg
+	}
+
+	for _, entry := range entries {
+		name := strings.TrimSpace(entry.Name)
+		branch, issue := catalogBranch(branches, entry.Branch)
+		if issue != "" {
+			result.Issues = append(result.Issues, fmt.Sprintf("package %q skipped: %s", name, issue))
+			continue
+		}
+		active := entry.Active == nil || *entry.Active
+		price := domain.MoneyFromMajor(entry.Price, domain.DefaultCurrency)
+
+		current, ok := existing[branch.ID+"/"+strings.ToLower(name)]
+		if !ok {
+			if !dryRun {
+				pkg := &domain.PTPackage{
+					TenantID:      tenantID,
+					BranchID:      branch.ID,
+					Name:          name,
+					TotalSessions: entry.TotalSessions,
+					Price:         price,
+				}
+				if err := s.pkgRepo.Create(ctx, pkg); err != nil {
+					return fmt.Errorf("failed to create package %s: %w", name, err)
+				}
+				// Packages are always created active
+				if !active {
+					pkg.Active = false
+					if err := s.pkgRepo.Update(ctx, pkg); err != nil {
+						return fmt.Errorf("failed to deactivate package %s: %w", name, err)
+					}
+				}
+			}
+			result.Packages.Created++
+			continue
+		}
+
+		if current.TotalSessions == entry.TotalSessions && current.Price == price && current.Active == active {
+			result.Packages.Unchanged++
+			continue
+		}
+		current.TotalSessions, current.Price, current.Active = entry.TotalSessions, price, active
+		if !dryRun {
+			if err := s.pkgRepo.Update(ctx, current); err != nil {
+				return fmt.Errorf("failed to update package %s: %w", name, err)
+			}
+		}
+		result.Packages.Updated++
+	}
+	return nil
+}
+
+func (s *SeedCatalogService) applyEquipment(ctx context.Context, entries []domain.CatalogEquipment, tenantID string, branches []*domain.Branch, dryRun bool, result *domain.SeedCatalogResult) error {
+	inventories := map[string][]*domain.Equipment{}
+	for _, entry := range entries {
+		name := strings.TrimSpace(entry.Name)
+		branch, issue := catalogBranch(branches, entry.Branch)
+		if issue != "" {
+			result.Issues = append(result.Issues, fmt.Sprintf("equipment %q skipped: %s", name, issue))
+			continue
+		}
+		available := entry.Available == nil || *entry.Available
+
+		inventory, ok := inventories[branch.ID]
+		if !ok {
+			var err error
+			if inventory, err = s.equipmentRepo.ListByBranch(ctx, branch.ID); err != nil {
+				return fmt.Errorf("failed to load equipment of branch %s: %w", branch.Name, err)
+			}
+			inventories[branch.ID] = inventory
+		}
+
+		i := slices.IndexFunc(inventory, func(item *domain.Equipment) bool {
+			return strings.EqualFold(strings.TrimSpace(item.Name), name)
+		})
+		if i < 0 {
+			if !dryRun {
+				item := &domain.Equipment{
+					TenantID:  tenantID,
+					BranchID:  branch.ID,
+					Name:      name,
+					Quantity:  entry.Quantity,
+					Available: available,
+				}
+				if err := s.equipmentRepo.Create(ctx, item); err != nil {
+					return fmt.Errorf("failed to create equipment %s: %w", name, err)
+				}
+			}
+			result.Equipment.Created++
+			continue
+		}
+
+		current := inventory[i]
+		if current.Quantity == entry.Quantity && current.Available == available {
+			result.Equipment.Unchanged++
+			continue
+		}
+		current.Quantity, current.Available = entry.Quantity, available
+		if !dryRun {
+			if err := s.equipmentRepo.Update(ctx, current); err != nil {
+				return fmt.Errorf("failed to update equipment %s: %w", name, err)
+			}
+		}
+		result.Equipment.Updated++
+	}
+	return nil
+}
+
+// catalogBranch finds a tenant branch by name. An empty name is fine when there is only
+// one branch. The returned issue explains why no branch matched.
+func catalogBranch(branches []*domain.Branch, name string) (*domain.Branch, string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		if len(branches) == 1 {
+			return branches[0], ""
+		}
+		return nil, fmt.Sprintf("the tenant has %d branches, so a branch name is required", len(branches))
+	}
+	for _, b := range branches {
+		if strings.EqualFold(b.Name, name) {
+			return b, ""
+		}
+	}
+	return nil, fmt.Sprintf("the tenant has no branch named %q", name)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSeedCatalog(t *testing.T) {
+	yamlCatalog := []byte(`
+exercises:
+  - name: Goblet Squat
+    muscle_group: Legs
+    equipment: Dumbbell
+templates:
+  - name: Legs
+    exercises: [Goblet Squat]
+`)
+	catalog, err := ParseSeedCatalog("gym.yaml", yamlCatalog)
+	require.NoError(t, err)
+	assert.Equal(t, "Dumbbell", catalog.Exercises[0].Equipment)
+	assert.Equal(t, []string{"Goblet Squat"}, catalog.Templates[0].Exercises)
+
+	catalog, err = ParseSeedCatalog("gym.json", []byte(`{"packages": [{"name": "10 Pack", "total_sessions": 10, "price": 100}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, 10, catalog.Packages[0].TotalSessions)
+
+	_, err = ParseSeedCatalog("gym.yaml", []byte("exercises:\n  - name: Plank\n    musclegroup: Core\n"))
+	assert.ErrorIs(t, err, domain.ErrInvalidSeedCatalog, "unknown fields are rejected")
+	_, err = ParseSeedCatalog("gym.json", []byte(`{"exercise": []}`))
+	assert.ErrorIs(t, err, domain.ErrInvalidSeedCatalog)
+	_, err = ParseSeedCatalog("gym.csv", nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidSeedCatalog)
+}
+
+func TestSeedCatalogService_Apply_Upserts(t *testing.T) {
+	ctx := context.Background()
+	exercises := mocks.NewExerciseRepository(t)
+	templates := mocks.NewTemplateRepository(t)
+	packages := mocks.NewPTPackageRepository(t)
+	equipment := mocks.NewEquipmentRepository(t)
+	branches := mocks.NewBranchRepository(t)
+	svc := NewSeedCatalogService(exercises, templates, packages, equipment, branches)
+
+	exercises.On("List", ctx, map[string]interface{}{}).Return([]*domain.Exercise{
+		{ID: "plank", Name: "Plank", MuscleGroup: "Core", Equipment: "Bodyweight"},
+		{ID: "squat", Name: "Goblet Squat", MuscleGroup: "Legs", Equipment: "Kettlebell"},
+	}, nil)
+	exercises.On("Create", ctx, mock.MatchedBy(func(ex *domain.Exercise) bool { return ex.Name == "Push Up" })).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Exercise).ID = "pushup" }).Return(nil)
+	exercises.On("Update", ctx, mock.MatchedBy(func(ex *domain.Exercise) bool {
+		return ex.ID == "squat" && ex.Equipment == "Dumbbell"
+	})).Return(nil)
+
+	templates.On("List", ctx).Return([]*domain.WorkoutTemplate{
+		{ID: "core", Name: "core", Gender: "All", ExerciseIDs: []string{"plank"}},
+	}, nil)
+	templates.On("Create", ctx, &domain.WorkoutTemplate{Name: "Full Body", Gender: "All", ExerciseIDs: []string{"squat", "pushup"}}).Return(nil)
+
+	branches.On("GetByTenantID", ctx, "t1").Return([]*domain.Branch{{ID: "b1", Name: "North"}}, nil)
+	packages.On("GetByTenant", ctx, "t1").Return([]*domain.PTPackage{
+		{ID: "p1", BranchID: "b1", Name: "10 Pack", TotalSessions: 10, Price: domain.NewMoney(100, "IDR"), Active: true},
+	}, nil)
+	packages.On("Update", ctx, mock.MatchedBy(func(p *domain.PTPackage) bool { return p.ID == "p1" && p.Price == domain.NewMoney(120, "IDR") })).Return(nil)
+	equipment.On("ListByBranch", ctx, "b1").Return([]*domain.Equipment{}, nil)
+	equipment.On("Create", ctx, &domain.Equipment{TenantID: "t1", BranchID: "b1", Name: "Dumbbell", Quantity: 10, Available: true}).Return(nil)
+
+	result, err := svc.Apply(ctx, &domain.SeedCatalog{
+		Exercises: []domain.CatalogExercise{
+			{Name: "Plank", MuscleGroup: "Core", Equipment: "Bodyweight"},
+			{Name: "Goblet Squat", MuscleGroup: "Legs", Equipment: "Dumbbell"},
+			{Name: "Push Up", MuscleGroup: "Chest", Equipment: "Bodyweight"},
+		},
+		Templates: []domain.CatalogTemplate{
+			{Name: "Core", Exercises: []string{"plank"}},
+			{Name: "Full Body", Exercises: []string{"Goblet Squat", "Push Up"}},
+			{Name: "Arms", Exercises: []string{"Barbell Curl"}},
+		},
+		Packages:  []domain.CatalogPackage{{Name: "10 pack", TotalSessions: 10, Price: 120}},
+		Equipment: []domain.CatalogEquipment{{Branch: "north", Name: "Dumbbell", Quantity: 10}},
+	}, "t1", false)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.SeedCatalogCounts{Created: 1, Updated: 1, Unchanged: 1}, result.Exercises)
+	assert.Equal(t, domain.SeedCatalogCounts{Created: 1, Unchanged: 1}, result.Templates)
+	assert.Equal(t, domain.SeedCatalogCounts{Updated: 1}, result.Packages)
+	assert.Equal(t, domain.SeedCatalogCounts{Created: 1}, result.Equipment)
+	assert.Equal(t, []string{`template "Arms" skipped: exercise "Barbell Curl" is not in the library or the catalog`}, result.Issues)
+}
+
+func TestSeedCatalogService_Apply_DryRunAndValidation(t *testing.T) {
+	ctx := context.Background()
+	exercises := mocks.NewExerciseRepository(t)
+	templates := mocks.NewTemplateRepository(t)
+	svc := NewSeedCatalogService(exercises, templates, mocks.NewPTPackageRepository(t), mocks.NewEquipmentRepository(t), mocks.NewBranchRepository(t))
+
+	_, err := svc.Apply(ctx, &domain.SeedCatalog{Packages: []domain.CatalogPackage{{Name: "Pack", TotalSessions: 10}}}, "", false)
+	assert.ErrorIs(t, err, domain.ErrInvalidSeedCatalog, "packages need a tenant")
+
+	// Mocks fail the test on Create or Update, so a dry run must only read
+	exercises.On("List", ctx, map[string]interface{}{}).Return([]*domain.Exercise{}, nil)
+	templates.On("List", ctx).Return([]*domain.WorkoutTemplate{}, nil)
+
+	result, err := svc.Apply(ctx, &domain.SeedCatalog{
+		Exercises: []domain.CatalogExercise{{Name: "Plank", MuscleGroup: "Core"}},
+		Templates: []domain.CatalogTemplate{{Name: "Core", Exercises: []string{"Plank"}}},
+	}, "", true)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Exercises.Created)
+	assert.Equal(t, 1, result.Templates.Created)
+	assert.Empty(t, result.Issues)
+}
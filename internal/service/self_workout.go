@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/telemetry"
+)
+
+// openSelfWorkoutWindow is how long an unfinished self-logged workout stays open; anything
+// older was abandoned and is cancelled the next time it's written to
+const openSelfWorkoutWindow = 12 * time.Hour
+
+// StartSelfWorkout starts a workout the member logs on their own. It is recorded as a
+// coachless schedule so sets, volume and PBs flow through the same pipelines as PT
+// sessions, but it never touches a contract. templateID is optional.
+func (s *WorkoutService) StartSelfWorkout(ctx context.Context, member *domain.User, branchID, templateID, goal, focusArea string) (_ *domain.SelfWorkout, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "WorkoutService.StartSelfWorkout",
+		telemetry.TenantID(member.TenantID), telemetry.MemberID(member.ID))
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	if branchID == "" {
+		branchID = member.HomeBranchID
+		if branchID == "" && len(member.BranchAccess) > 0 {
+			branchID = member.BranchAccess[0]
+		}
+	} else if branchID != member.HomeBranchID && !slices.Contains(member.BranchAccess, branchID) {
+		return nil, domain.ErrForbidden
+	}
+
+	// Check the template up front so a bad ID doesn't leave an empty workout open
+	if templateID != "" {
+		if _, err := s.templateRepo.GetByID(ctx, templateID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := s.clock.Now()
+	current, err := s.findOpenSelfWorkout(ctx, member.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		return nil, domain.ErrSelfWorkoutInProgress
+	}
+
+	schedule := &domain.Schedule{
+		TenantID:    member.TenantID,
+		BranchID:    branchID,
+		MemberID:    member.ID,
+		StartTime:   now,
+		EndTime:     now,
+		Status:      domain.ScheduleStatusScheduled,
+		SessionGoal: goal,
+		FocusArea:   focusArea,
+		SelfLogged:  true,
+	}
+	if err := s.scheduleRepo.Create(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	if templateID != "" {
+		if _, err := s.InitializeSession(ctx, schedule.ID, templateID); err != nil {
+			return nil, err
+		}
+	} else {
+		session := &domain.WorkoutSession{
+			ScheduleID: schedule.ID,
+			TenantID:   schedule.TenantID,
+			BranchID:   schedule.BranchID,
+			MemberID:   schedule.MemberID,
+		}
+		if err := s.sessionRepo.Create(ctx, session); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetSelfWorkout(ctx, member.ID, schedule.ID)
+}
+
+// CurrentSelfWorkout returns the member's unfinished self-logged workout, so the app can
+// resume it
+func (s *WorkoutService) CurrentSelfWorkout(ctx context.Context, memberID string) (*domain.SelfWorkout, error) {
+	current, err := s.findOpenSelfWorkout(ctx, memberID, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, domain.ErrScheduleNotFound
+	}
+	return s.GetSelfWorkout(ctx, memberID, current.ID)
+}
+
+// GetSelfWorkout returns one of the member's self-logged workouts
+func (s *WorkoutService) GetSelfWorkout(ctx context.Context, memberID, scheduleID string) (*domain.SelfWorkout, error) {
+	schedule, err := s.selfWorkout(ctx, memberID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	exercises, err := s.sessionRepo.GetPlannedExercisesByScheduleID(ctx, schedule.ID)
+	if err != nil {
+		return nil, err
+	}
+	sets, err := s.setLogRepo.GetByScheduleID(ctx, schedule.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exercises == nil {
+		exercises = []*domain.PlannedExercise{}
+	}
+	if sets == nil {
+		sets = []*domain.SetLogDocument{}
+	}
+	return &domain.SelfWorkout{Schedule: schedule, Exercises: exercises, Sets: sets}, nil
+}
+
+// AddSelfWorkoutExercise adds an exercise to an open self-logged workout
+func (s *WorkoutService) AddSelfWorkoutExercise(ctx context.Context, memberID, scheduleID, exerciseID, clientID string, targetSets, targetReps, restSeconds int, notes string) (*domain.PlannedExercise, error) {
+	schedule, err := s.openSelfWorkout(ctx, memberID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	return s.AddExerciseToSession(ctx, schedule.ID, exerciseID, clientID, targetSets, targetReps, restSeconds, notes, 0)
+}
+
+// LogSelfWorkoutSet records a new set for an exercise of an open self-logged workout
+func (s *WorkoutService) LogSelfWorkoutSet(ctx context.Context, memberID, scheduleID, plannedExerciseID, clientID string, weight float64, reps int, remarks string, completed bool) (*domain.SetLogDocument, error) {
+	schedule, err := s.openSelfWorkout(ctx, memberID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	planned, err := s.resolvePlannedExercise(ctx, plannedExerciseID)
+	if err != nil || planned.ScheduleID != schedule.ID {
+		return nil, domain.ErrExerciseULIDNotFound
+	}
+
+	set, err := s.AddSetToExercise(ctx, planned.ID, clientID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.UpdateSetLog(ctx, set.ID, weight, reps, remarks, completed); err != nil {
+		return nil, err
+	}
+	set.Weight, set.Reps, set.Remarks, set.Completed = weight, reps, remarks, completed
+	return set, nil
+}
+
+// UpdateSelfWorkoutSet changes a set already logged in an open self-logged workout
+func (s *WorkoutService) UpdateSelfWorkoutSet(ctx context.Context, memberID, scheduleID, setID string, weight float64, reps int, remarks string, completed bool) error {
+	schedule, err := s.openSelfWorkout(ctx, memberID, scheduleID)
+	if err != nil {
+		return err
+	}
+	set, err := s.resolveSetLog(ctx, setID)
+	if err != nil {
+		return err
+	}
+	if set.ScheduleID != schedule.ID {
+		return domain.ErrSessionNotFound
+	}
+	return s.UpdateSetLog(ctx, set.ID, weight, reps, remarks, completed)
+}
+
+// CompleteSelfWorkout closes the workout and hands it to the volume and PB consumers,
+// exactly as completing a PT session does, minus the credit deduction
+func (s *WorkoutService) CompleteSelfWorkout(ctx context.Context, memberID, scheduleID string) (*domain.Schedule, error) {
+	schedule, err := s.openSelfWorkout(ctx, memberID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	schedule.Status = domain.ScheduleStatusCompleted
+	schedule.EndTime = s.clock.Now()
+	if err := s.scheduleRepo.Update(ctx, schedule); err != nil {
+		return nil, err
+	}
+	s.RecordSessionCompleted(ctx, schedule, memberID)
+	return schedule, nil
+}
+
+// selfWorkout loads a self-logged workout, hiding other members' workouts and PT sessions
+func (s *WorkoutService) selfWorkout(ctx context.Context, memberID, scheduleID string) (*domain.Schedule, error) {
+	schedule, err := s.scheduleRepo.GetByID(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if !schedule.SelfLogged || schedule.MemberID != memberID || schedule.DeletedAt != nil {
+		return nil, domain.ErrScheduleNotFound
+	}
+	return schedule, nil
+}
+
+// findOpenSelfWorkout returns the member's recent unfinished self-logged workout, or nil
+func (s *WorkoutService) findOpenSelfWorkout(ctx context.Context, memberID string, now time.Time) (*domain.Schedule, error) {
+	recent, err := s.scheduleRepo.GetByMember(ctx, memberID, now.Add(-openSelfWorkoutWindow), now)
+	if err != nil {
+		return nil, err
+	}
+	for _, sched := range recent {
+		if sched.SelfLogged && sched.Status == domain.ScheduleStatusScheduled && sched.DeletedAt == nil {
+			return sched, nil
+		}
+	}
+	return nil, nil
+}
+
+// openSelfWorkout loads a self-logged workout that can still be written to. One left open
+// past the window is cancelled, so it doesn't linger as a scheduled session.
+func (s *WorkoutService) openSelfWorkout(ctx context.Context, memberID, scheduleID string) (*domain.Schedule, error) {
+	schedule, err := s.selfWorkout(ctx, memberID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Status != domain.ScheduleStatusScheduled {
+		return nil, domain.ErrSelfWorkoutClosed
+	}
+	if schedule.StartTime.Before(s.clock.Now().Add(-openSelfWorkoutWindow)) {
+		if err := s.scheduleRepo.UpdateStatus(ctx, schedule.ID, domain.ScheduleStatusCancelled); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrSelfWorkoutExpired
+	}
+	return schedule, nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkoutService_StartSelfWorkout(t *testing.T) {
+	ctx := context.Background()
+	member := &domain.User{ID: "member-1", TenantID: "gym", HomeBranchID: "north", BranchAccess: []string{"north"}}
+
+	t.Run("records a coachless schedule at the home branch", func(t *testing.T) {
+		svc, m := newTestWorkoutService(t)
+		m.scheduleRepo.On("GetByMember", anyCtx, "member-1", testNow.Add(-openSelfWorkoutWindow), testNow).Return(nil, nil)
+		m.scheduleRepo.On("Create", anyCtx, mock.MatchedBy(func(s *domain.Schedule) bool {
+			return s.SelfLogged && s.CoachID == "" && s.ContractID == "" && s.BranchID == "north" && s.StartTime.Equal(testNow)
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Schedule).ID = testScheduleID
+		}).Return(nil)
+		m.sessionRepo.On("Create", anyCtx, mock.AnythingOfType("*domain.WorkoutSession")).Return(nil)
+		m.scheduleRepo.On("GetByID", anyCtx, testScheduleID).Return(&domain.Schedule{
+			ID: testScheduleID, MemberID: "member-1", SelfLogged: true, Status: domain.ScheduleStatusScheduled,
+		}, nil)
+		m.sessionRepo.On("GetPlannedExercisesByScheduleID", anyCtx, testScheduleID).Return(nil, nil)
+		m.setLogRepo.On("GetByScheduleID", anyCtx, testScheduleID).Return(nil, nil)
+
+		workout, err := svc.StartSelfWorkout(ctx, member, "", "", "Upper body", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, testScheduleID, workout.Schedule.ID)
+		assert.Empty(t, workout.Exercises)
+	})
+
+	t.Run("one open workout at a time", func(t *testing.T) {
+		svc, m := newTestWorkoutService(t)
+		m.scheduleRepo.On("GetByMember", anyCtx, "member-1", mock.Anything, mock.Anything).Return([]*domain.Schedule{
+			{ID: "pt", MemberID: "member-1", Status: domain.ScheduleStatusScheduled},
+			{ID: "open", MemberID: "member-1", SelfLogged: true, Status: domain.ScheduleStatusScheduled},
+		}, nil)
+
+		_, err := svc.StartSelfWorkout(ctx, member, "", "", "", "")
+
+		assert.ErrorIs(t, err, domain.ErrSelfWorkoutInProgress)
+	})
+
+	t.Run("only branches the member can use", func(t *testing.T) {
+		svc, _ := newTestWorkoutService(t)
+
+		_, err := svc.StartSelfWorkout(ctx, member, "south", "", "", "")
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+}
+
+func TestWorkoutService_CompleteSelfWorkout(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("PT sessions can't be completed here", func(t *testing.T) {
+		svc, m := newTestWorkoutService(t)
+		m.scheduleRepo.On("GetByID", anyCtx, testScheduleID).Return(&domain.Schedule{
+			ID: testScheduleID, MemberID: "member-1", ContractID: "contract-1", Status: domain.ScheduleStatusScheduled,
+		}, nil)
+
+		_, err := svc.CompleteSelfWorkout(ctx, "member-1", testScheduleID)
+
+		assert.ErrorIs(t, err, domain.ErrScheduleNotFound)
+	})
+
+	t.Run("already completed", func(t *testing.T) {
+		svc, m := newTestWorkoutService(t)
+		m.scheduleRepo.On("GetByID", anyCtx, testScheduleID).Return(&domain.Schedule{
+			ID: testScheduleID, MemberID: "member-1", SelfLogged: true, Status: domain.ScheduleStatusCompleted,
+		}, nil)
+
+		_, err := svc.CompleteSelfWorkout(ctx, "member-1", testScheduleID)
+
+		assert.ErrorIs(t, err, domain.ErrSelfWorkoutClosed)
+	})
+
+	t.Run("a workout abandoned past the window is cancelled", func(t *testing.T) {
+		svc, m := newTestWorkoutService(t)
+		m.scheduleRepo.On("GetByID", anyCtx, testScheduleID).Return(&domain.Schedule{
+			ID: testScheduleID, MemberID: "member-1", SelfLogged: true, Status: domain.ScheduleStatusScheduled,
+			StartTime: testNow.Add(-openSelfWorkoutWindow - time.Minute),
+		}, nil)
+		m.scheduleRepo.On("UpdateStatus", anyCtx, testScheduleID, domain.ScheduleStatusCancelled).Return(nil)
+
+		_, err := svc.CompleteSelfWorkout(ctx, "member-1", testScheduleID)
+
+		assert.ErrorIs(t, err, domain.ErrSelfWorkoutExpired)
+	})
+}
+
+func TestWorkoutService_UpdateSelfWorkoutSet(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("still open at the end of the window", func(t *testing.T) {
+		svc, m := newTestWorkoutService(t)
+		m.scheduleRepo.On("GetByID", anyCtx, testScheduleID).Return(&domain.Schedule{
+			ID: testScheduleID, MemberID: "member-1", SelfLogged: true, Status: domain.ScheduleStatusScheduled,
+			StartTime: testNow.Add(-openSelfWorkoutWindow),
+		}, nil)
+		m.setLogRepo.On("GetByClientID", anyCtx, "set-1").Return(nil, nil)
+
+		err := svc.UpdateSelfWorkoutSet(ctx, "member-1", testScheduleID, "set-1", 60, 8, "", true)
+
+		assert.ErrorIs(t, err, domain.ErrSessionNotFound, "got past the window check to the set lookup")
+	})
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// confirmPath is where a reminder's confirm and decline actions post to
+const confirmPath = "/v1/me/schedules/%s/confirm"
+
+// SessionConfirmationService records whether members will attend their sessions and
+// alerts coaches about sessions nobody confirmed in time
+type SessionConfirmationService struct {
+	schedRepo domain.ScheduleRepository
+	prefs     domain.NotificationPreferencesRepository
+	notifier  *NotificationService
+	clock     domain.Clock
+}
+
+func NewSessionConfirmationService(schedRepo domain.ScheduleRepository, prefs domain.NotificationPreferencesRepository, notifier *NotificationService, clk domain.Clock) *SessionConfirmationService {
+	return &SessionConfirmationService{
+		schedRepo: schedRepo,
+		prefs:     prefs,
+		notifier:  notifier,
+		clock:     clock.OrReal(clk),
+	}
+}
+
+// Confirm records the member's answer for one of their upcoming sessions. The member can
+// change their mind until the session starts; a decline is passed on to the coach.
+func (s *SessionConfirmationService) Confirm(ctx context.Context, memberID, scheduleID, status, note string) (*domain.Schedule, error) {
+	schedule, err := s.schedRepo.GetByID(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.MemberID != memberID || schedule.DeletedAt != nil {
+		return nil, domain.ErrScheduleNotFound
+	}
+	now := s.clock.Now()
+	if !slices.Contains(reminderStatuses, schedule.Status) || !schedule.StartTime.After(now) {
+		return nil, domain.ErrSessionNotConfirmable
+	}
+
+	confirmation := &domain.SessionConfirmation{Status: status, Note: strings.TrimSpace(note), RespondedAt: now}
+	if err := confirmation.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.schedRepo.SetConfirmation(ctx, schedule.ID, confirmation); err != nil {
+		return nil, err
+	}
+	schedule.Confirmation = confirmation
+
+	if status == domain.ConfirmationDeclined && schedule.CoachID != "" {
+		// The answer is saved either way; the coach also sees it on their agenda
+		if err := s.notifier.Notify(ctx, coachNotification(schedule, domain.NotificationDeclined)); err != nil {
+			log.Printf("Warning: decline of schedule %s saved but coach not told: %v", schedule.ID, err)
+		}
+	}
+	return schedule, nil
+}
+
+// AlertUnconfirmed tells coaches about sessions starting within their tenant's alert lead
+// that the member hasn't confirmed, once per session, and returns how many alerts went out
+func (s *SessionConfirmationService) AlertUnconfirmed(ctx context.Context) (int, error) {
+	configured, err := s.prefs.ListTenants(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defaultLead := (&domain.TenantNotificationSettings{}).ConfirmationAlertLead()
+	tenantLeads := make(map[string]time.Duration, len(configured))
+	longest := defaultLead
+	for _, settings := range configured {
+		lead := settings.ConfirmationAlertLead()
+		tenantLeads[settings.TenantID] = lead
+		longest = max(longest, lead)
+	}
+	if longest == 0 {
+		return 0, nil
+	}
+
+	now := s.clock.Now()
+	schedules, err := s.schedRepo.ListStartingBetween(ctx, now, now.Add(longest), reminderStatuses)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list upcoming sessions: %w", err)
+	}
+
+	alerted := 0
+	for _, sched := range schedules {
+		if sched.Confirmation != nil || sched.CoachAlertedAt != nil || sched.CoachID == "" {
+			continue
+		}
+		lead, ok := tenantLeads[sched.TenantID]
+		if !ok {
+			lead = defaultLead
+		}
+		if sched.StartTime.Sub(now) > lead {
+			continue
+		}
+
+		// Claim the alert first so a member answering meanwhile, or another run, wins
+		marked, err := s.schedRepo.MarkCoachAlerted(ctx, sched.ID, now)
+		if err != nil {
+			return alerted, err
+		}
+		if !marked {
+			continue
+		}
+		if err := s.notifier.Notify(ctx, coachNotification(sched, domain.NotificationUnconfirmed)); err != nil {
+			log.Printf("Warning: unconfirmed alert for schedule %s not sent: %v", sched.ID, err)
+			continue
+		}
+		alerted++
+	}
+	return alerted, nil
+}
+
+func coachNotification(sched *domain.Schedule, kind string) *domain.Notification {
+	title, body := "Session not confirmed", "Your member hasn't confirmed the session starting at "
+	if kind == domain.NotificationDeclined {
+		title, body = "Session declined", "Your member can't make the session starting at "
+	}
+	body += sched.StartTime.UTC().Format("Mon 2 Jan 15:04 MST")
+	if sched.Confirmation != nil && sched.Confirmation.Note != "" {
+		body += ": " + sched.Confirmation.Note
+	}
+	return &domain.Notification{
+		UserID:   sched.CoachID,
+		TenantID: sched.TenantID,
+		Type:     kind,
+		Title:    title,
+		Body:     body,
+		Data: map[string]string{
+			"schedule_id": sched.ID,
+			"member_id":   sched.MemberID,
+			"start_time":  sched.StartTime.UTC().Format(time.RFC3339),
+		},
+		Link: &domain.DeepLink{Screen: domain.ScreenSchedule, ScheduleID: sched.ID},
+	}
+}
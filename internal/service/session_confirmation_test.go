@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionConfirmationService_Confirm(t *testing.T) {
+	ctx := context.Background()
+
+	newService := func(t *testing.T) (*SessionConfirmationService, *mocks.ScheduleRepository, *mocks.NotificationSender) {
+		schedRepo := mocks.NewScheduleRepository(t)
+		prefs := mocks.NewNotificationPreferencesRepository(t)
+		prefs.On("GetUser", ctx, mock.Anything).Return(&domain.NotificationPreferences{}, nil).Maybe()
+		push := mocks.NewNotificationSender(t)
+		push.On("Channel").Return(domain.ChannelPush)
+		notifier := NewNotificationService(prefs, nil, clock.NewFake(testNow), push)
+		return NewSessionConfirmationService(schedRepo, prefs, notifier, clock.NewFake(testNow)), schedRepo, push
+	}
+	upcoming := func() *domain.Schedule {
+		return &domain.Schedule{ID: "s1", MemberID: "m1", CoachID: "c1", Status: domain.ScheduleStatusScheduled, StartTime: testNow.Add(24 * time.Hour)}
+	}
+
+	t.Run("confirms an upcoming session", func(t *testing.T) {
+		svc, schedRepo, _ := newService(t)
+		schedRepo.On("GetByID", ctx, "s1").Return(upcoming(), nil)
+		want := &domain.SessionConfirmation{Status: domain.ConfirmationConfirmed, RespondedAt: testNow}
+		schedRepo.On("SetConfirmation", ctx, "s1", want).Return(nil).Once()
+
+		schedule, err := svc.Confirm(ctx, "m1", "s1", domain.ConfirmationConfirmed, "")
+		require.NoError(t, err)
+		assert.Equal(t, domain.ConfirmationConfirmed, schedule.ConfirmationStatus())
+	})
+
+	t.Run("a decline is passed on to the coach", func(t *testing.T) {
+		svc, schedRepo, push := newService(t)
+		schedRepo.On("GetByID", ctx, "s1").Return(upcoming(), nil)
+		schedRepo.On("SetConfirmation", ctx, "s1", mock.Anything).Return(nil).Once()
+		push.On("Send", ctx, mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.UserID == "c1" && n.Type == domain.NotificationDeclined && n.Body != "" && n.Data["member_id"] == "m1"
+		})).Return(nil).Once()
+
+		_, err := svc.Confirm(ctx, "m1", "s1", domain.ConfirmationDeclined, " sick ")
+		require.NoError(t, err)
+	})
+
+	t.Run("only the member's own upcoming sessions", func(t *testing.T) {
+		svc, schedRepo, _ := newService(t)
+		past := upcoming()
+		past.ID, past.StartTime = "s2", testNow.Add(-time.Hour)
+		done := upcoming()
+		done.ID, done.Status = "s3", domain.ScheduleStatusCompleted
+		schedRepo.On("GetByID", ctx, "s1").Return(upcoming(), nil)
+		schedRepo.On("GetByID", ctx, "s2").Return(past, nil)
+		schedRepo.On("GetByID", ctx, "s3").Return(done, nil)
+
+		_, err := svc.Confirm(ctx, "m2", "s1", domain.ConfirmationConfirmed, "")
+		assert.ErrorIs(t, err, domain.ErrScheduleNotFound)
+		_, err = svc.Confirm(ctx, "m1", "s2", domain.ConfirmationConfirmed, "")
+		assert.ErrorIs(t, err, domain.ErrSessionNotConfirmable)
+		_, err = svc.Confirm(ctx, "m1", "s3", domain.ConfirmationConfirmed, "")
+		assert.ErrorIs(t, err, domain.ErrSessionNotConfirmable)
+		_, err = svc.Confirm(ctx, "m1", "s1", "maybe", "")
+		assert.ErrorIs(t, err, domain.ErrInvalidConfirmation)
+	})
+}
+
+func TestSessionConfirmationService_AlertUnconfirmed(t *testing.T) {
+	ctx := context.Background()
+	schedRepo := mocks.NewScheduleRepository(t)
+	prefs := mocks.NewNotificationPreferencesRepository(t)
+	prefs.On("GetUser", ctx, "c1").Return(&domain.NotificationPreferences{UserID: "c1"}, nil)
+	push := mocks.NewNotificationSender(t)
+	push.On("Channel").Return(domain.ChannelPush)
+	svc := NewSessionConfirmationService(schedRepo, prefs, NewNotificationService(prefs, nil, clock.NewFake(testNow), push), clock.NewFake(testNow))
+
+	// gym-a alerts a day ahead, gym-b never configured it (3 hours) and gym-c turned it off
+	day, off := 24*60, 0
+	prefs.On("ListTenants", ctx).Return([]*domain.TenantNotificationSettings{
+		{TenantID: "gym-a", ConfirmationAlertMinutes: &day},
+		{TenantID: "gym-c", ConfirmationAlertMinutes: &off},
+	}, nil)
+	confirmed := &domain.SessionConfirmation{Status: domain.ConfirmationConfirmed}
+	schedRepo.On("ListStartingBetween", ctx, testNow, testNow.Add(24*time.Hour), reminderStatuses).Return([]*domain.Schedule{
+		{ID: "a-20h", TenantID: "gym-a", CoachID: "c1", StartTime: testNow.Add(20 * time.Hour)},
+		{ID: "a-confirmed", TenantID: "gym-a", CoachID: "c1", StartTime: testNow.Add(2 * time.Hour), Confirmation: confirmed},
+		{ID: "b-20h", TenantID: "gym-b", CoachID: "c1", StartTime: testNow.Add(20 * time.Hour)},
+		{ID: "b-2h", TenantID: "gym-b", CoachID: "c1", StartTime: testNow.Add(2 * time.Hour)},
+		{ID: "b-2h-raced", TenantID: "gym-b", CoachID: "c1", StartTime: testNow.Add(2 * time.Hour)},
+		{ID: "c-1h", TenantID: "gym-c", CoachID: "c1", StartTime: testNow.Add(time.Hour)},
+	}, nil)
+	schedRepo.On("MarkCoachAlerted", ctx, "a-20h", testNow).Return(true, nil).Once()
+	schedRepo.On("MarkCoachAlerted", ctx, "b-2h", testNow).Return(true, nil).Once()
+	schedRepo.On("MarkCoachAlerted", ctx, "b-2h-raced", testNow).Return(false, nil).Once()
+
+	var alerted []string
+	push.On("Send", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		n := args.Get(1).(*domain.Notification)
+		assert.Equal(t, domain.NotificationUnconfirmed, n.Type)
+		alerted = append(alerted, n.Data["schedule_id"])
+	}).Return(nil)
+
+	sent, err := svc.AlertUnconfirmed(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.Equal(t, []string{"a-20h", "b-2h"}, alerted)
+}
+
+func TestReminderFor_ConfirmActions(t *testing.T) {
+	n := reminderFor(&domain.Schedule{ID: "s-1", MemberID: "m1", StartTime: testNow}, 60)
+	assert.Equal(t, "confirmed,declined", n.Data["actions"])
+	assert.Equal(t, "/v1/me/schedules/s-1/confirm", n.Data["action_path"])
+
+	n = reminderFor(&domain.Schedule{ID: "s-2", MemberID: "m1", StartTime: testNow, Confirmation: &domain.SessionConfirmation{Status: domain.ConfirmationConfirmed}}, 60)
+	assert.NotContains(t, n.Data, "actions")
+}
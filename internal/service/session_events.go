@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// sessionCompletedPushWindow is how old a completion may be and still be pushed. Rebuilds
+// replay completions long after the fact, and those must not reach the member again.
+const sessionCompletedPushWindow = time.Hour
+
+// SessionEventNotifier pushes what happens to a session to the other side of it: members hear
+// of cancellations, completed sessions and new personal bests, and coaches of sessions their
+// members moved and of their members' personal bests. Bookings and coaches' moves are
+// MemberAppNotifier's.
+type SessionEventNotifier struct {
+	schedRepo    domain.ScheduleRepository
+	exerciseRepo domain.ExerciseRepository
+	notifier     *NotificationService
+	clock        domain.Clock
+}
+
+func NewSessionEventNotifier(schedRepo domain.ScheduleRepository, exerciseRepo domain.ExerciseRepository, notifier *NotificationService, clk domain.Clock) *SessionEventNotifier {
+	return &SessionEventNotifier{schedRepo: schedRepo, exerciseRepo: exerciseRepo, notifier: notifier, clock: clock.OrReal(clk)}
+}
+
+// HandleScheduleChanged is the outbox handler for cancelled sessions and sessions members
+// moved. Sessions deleted since, or no longer in the state the change left them in, are
+// skipped.
+func (n *SessionEventNotifier) HandleScheduleChanged(ctx context.Context, msg *domain.OutboxMessage) error {
+	change, _ := msg.Payload["change"].(string)
+	if change != domain.ScheduleChangeCancelled && change != domain.ScheduleChangeMemberRescheduled {
+		return nil
+	}
+	sched, err := n.schedRepo.GetByID(ctx, msg.Key)
+	if errors.Is(err, domain.ErrScheduleNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if sched.DeletedAt != nil {
+		return nil
+	}
+
+	when := sched.StartTime.UTC().Format("Mon 2 Jan 15:04 MST")
+	notification := &domain.Notification{
+		TenantID: sched.TenantID,
+		Data: map[string]string{
+			"schedule_id": sched.ID,
+			"start_time":  sched.StartTime.UTC().Format(time.RFC3339),
+		},
+		Link: &domain.DeepLink{Screen: domain.ScreenSchedule, ScheduleID: sched.ID},
+	}
+	if change == domain.ScheduleChangeCancelled {
+		// The handler accepts the frontend's lowercase status too
+		if !strings.EqualFold(sched.Status, domain.ScheduleStatusCancelled) {
+			return nil
+		}
+		notification.UserID = sched.MemberID
+		notification.Type = domain.NotificationScheduleCancelled
+		notification.Title = "Session cancelled"
+		notification.Body = "Your session on " + when + " was cancelled"
+	} else {
+		if sched.Status != domain.ScheduleStatusPendingConfirmation {
+			return nil
+		}
+		notification.UserID = sched.CoachID
+		notification.Type = domain.NotificationMemberRescheduled
+		notification.Title = "Session moved"
+		notification.Body = "Your member moved their session to " + when + ". Please confirm the new time"
+		notification.Data["member_id"] = sched.MemberID
+	}
+	n.send(ctx, notification)
+	return nil
+}
+
+// HandleWorkoutEvent tells the member their coach completed the session
+func (n *SessionEventNotifier) HandleWorkoutEvent(ctx context.Context, event *domain.WorkoutEvent) error {
+	if event.Type != domain.WorkoutEventSessionCompleted || event.ActorID == event.MemberID {
+		return nil
+	}
+	if n.clock.Now().Sub(event.OccurredAt) > sessionCompletedPushWindow {
+		return nil
+	}
+	n.send(ctx, &domain.Notification{
+		UserID:   event.MemberID,
+		TenantID: event.TenantID,
+		Type:     domain.NotificationSessionCompleted,
+		Title:    "Session complete",
+		Body:     "Great work! Your coach has logged today's session",
+		Data:     map[string]string{"schedule_id": event.ScheduleID},
+		Link:     &domain.DeepLink{Screen: domain.ScreenSchedule, ScheduleID: event.ScheduleID},
+	})
+	return nil
+}
+
+// HandleNewPB tells the member, and the coach of the session it was set in, about a new
+// personal best
+func (n *SessionEventNotifier) HandleNewPB(ctx context.Context, pb *domain.PersonalBest) error {
+	lift := "a lift"
+	if exercise, err := n.exerciseRepo.GetByID(ctx, pb.ExerciseID); err == nil {
+		lift = exercise.Name
+	}
+	var sched *domain.Schedule
+	if pb.ScheduleID != "" {
+		s, err := n.schedRepo.GetByID(ctx, pb.ScheduleID)
+		if err != nil && !errors.Is(err, domain.ErrScheduleNotFound) {
+			return err
+		}
+		sched = s
+	}
+
+	data := map[string]string{"exercise_id": pb.ExerciseID}
+	var link *domain.DeepLink
+	var tenantID string
+	if sched != nil {
+		data["schedule_id"] = sched.ID
+		link = &domain.DeepLink{Screen: domain.ScreenSchedule, ScheduleID: sched.ID}
+		tenantID = sched.TenantID
+	}
+	set := fmt.Sprintf("%g kg × %d", pb.Weight, pb.Reps)
+	n.send(ctx, &domain.Notification{
+		UserID:   pb.MemberID,
+		TenantID: tenantID,
+		Type:     domain.NotificationPersonalBest,
+		Title:    "New personal best!",
+		Body:     "You set a new " + lift + " personal best: " + set,
+		Data:     data,
+		Link:     link,
+	})
+	if sched != nil && sched.CoachID != "" {
+		coachData := maps.Clone(data)
+		coachData["member_id"] = pb.MemberID
+		n.send(ctx, &domain.Notification{
+			UserID:   sched.CoachID,
+			TenantID: tenantID,
+			Type:     domain.NotificationPersonalBest,
+			Title:    "New personal best",
+			Body:     "Your member set a new " + lift + " personal best: " + set,
+			Data:     coachData,
+			Link:     link,
+		})
+	}
+	return nil
+}
+
+// send notifies without failing the caller: a retry or replay would push twice
+func (n *SessionEventNotifier) send(ctx context.Context, notification *domain.Notification) {
+	if err := n.notifier.Notify(ctx, notification); err != nil {
+		log.Printf("Warning: %s notification to %s not sent: %v", notification.Type, notification.UserID, err)
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSessionEventNotifier(t *testing.T) (*SessionEventNotifier, *mocks.ScheduleRepository, *[]*domain.Notification) {
+	schedRepo := mocks.NewScheduleRepository(t)
+	exercises := mocks.NewExerciseRepository(t)
+	exercises.On("GetByID", anyCtx, "squat").Return(&domain.Exercise{ID: "squat", Name: "Back Squat"}, nil).Maybe()
+	prefs := mocks.NewNotificationPreferencesRepository(t)
+	prefs.On("GetUser", anyCtx, mock.Anything).Return(&domain.NotificationPreferences{}, nil).Maybe()
+	push := mocks.NewNotificationSender(t)
+	push.On("Channel").Return(domain.ChannelPush).Maybe()
+	var sent []*domain.Notification
+	push.On("Send", anyCtx, mock.AnythingOfType("*domain.Notification")).Run(func(args mock.Arguments) {
+		sent = append(sent, args.Get(1).(*domain.Notification))
+	}).Return(nil).Maybe()
+	notifications := NewNotificationService(prefs, nil, clock.NewFake(testNow), push)
+	return NewSessionEventNotifier(schedRepo, exercises, notifications, clock.NewFake(testNow)), schedRepo, &sent
+}
+
+func TestSessionEventNotifier_HandleScheduleChanged(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, 6, 20, 9, 0, 0, 0, time.UTC)
+	changed := func(change string) *domain.OutboxMessage {
+		return &domain.OutboxMessage{Key: "sched-1", Payload: map[string]interface{}{"change": change}}
+	}
+
+	t.Run("tells the member their session was cancelled", func(t *testing.T) {
+		n, schedRepo, sent := newTestSessionEventNotifier(t)
+		schedRepo.On("GetByID", ctx, "sched-1").Return(&domain.Schedule{ID: "sched-1", TenantID: "gym", MemberID: "member-1", CoachID: "coach-1",
+			StartTime: start, Status: "cancelled"}, nil)
+
+		require.NoError(t, n.HandleScheduleChanged(ctx, changed(domain.ScheduleChangeCancelled)))
+
+		require.Len(t, *sent, 1)
+		assert.Equal(t, "member-1", (*sent)[0].UserID)
+		assert.Equal(t, domain.NotificationScheduleCancelled, (*sent)[0].Type)
+		assert.Equal(t, "Your session on Fri 20 Jun 09:00 UTC was cancelled", (*sent)[0].Body)
+	})
+
+	t.Run("asks the coach to confirm a member's move", func(t *testing.T) {
+		n, schedRepo, sent := newTestSessionEventNotifier(t)
+		schedRepo.On("GetByID", ctx, "sched-1").Return(&domain.Schedule{ID: "sched-1", TenantID: "gym", MemberID: "member-1", CoachID: "coach-1",
+			StartTime: start, Status: domain.ScheduleStatusPendingConfirmation}, nil)
+
+		require.NoError(t, n.HandleScheduleChanged(ctx, changed(domain.ScheduleChangeMemberRescheduled)))
+
+		require.Len(t, *sent, 1)
+		assert.Equal(t, "coach-1", (*sent)[0].UserID)
+		assert.Equal(t, domain.NotificationMemberRescheduled, (*sent)[0].Type)
+		assert.Equal(t, "member-1", (*sent)[0].Data["member_id"])
+	})
+
+	t.Run("skips sessions reinstated since and coaches' changes", func(t *testing.T) {
+		n, schedRepo, sent := newTestSessionEventNotifier(t)
+		schedRepo.On("GetByID", ctx, "sched-1").Return(&domain.Schedule{ID: "sched-1", MemberID: "member-1", Status: domain.ScheduleStatusScheduled}, nil)
+
+		require.NoError(t, n.HandleScheduleChanged(ctx, changed(domain.ScheduleChangeCancelled)))
+		require.NoError(t, n.HandleScheduleChanged(ctx, changed(domain.ScheduleChangeBooked)))
+
+		assert.Empty(t, *sent)
+	})
+}
+
+func TestSessionEventNotifier_HandleWorkoutEvent(t *testing.T) {
+	ctx := context.Background()
+	completed := &domain.WorkoutEvent{Type: domain.WorkoutEventSessionCompleted, TenantID: "gym", ScheduleID: "sched-1",
+		MemberID: "member-1", ActorID: "coach-1", OccurredAt: testNow.Add(-time.Minute)}
+
+	t.Run("tells the member their session is logged", func(t *testing.T) {
+		n, _, sent := newTestSessionEventNotifier(t)
+
+		require.NoError(t, n.HandleWorkoutEvent(ctx, completed))
+
+		require.Len(t, *sent, 1)
+		assert.Equal(t, domain.NotificationSessionCompleted, (*sent)[0].Type)
+		assert.Equal(t, "member-1", (*sent)[0].UserID)
+	})
+
+	t.Run("replayed completions aren't pushed again", func(t *testing.T) {
+		n, _, sent := newTestSessionEventNotifier(t)
+		replayed := *completed
+		replayed.OccurredAt = testNow.AddDate(0, 0, -7)
+
+		require.NoError(t, n.HandleWorkoutEvent(ctx, &replayed))
+
+		assert.Empty(t, *sent)
+	})
+}
+
+func TestSessionEventNotifier_HandleNewPB(t *testing.T) {
+	ctx := context.Background()
+	n, schedRepo, sent := newTestSessionEventNotifier(t)
+	schedRepo.On("GetByID", ctx, "sched-1").Return(&domain.Schedule{ID: "sched-1", TenantID: "gym", MemberID: "member-1", CoachID: "coach-1"}, nil)
+
+	err := n.HandleNewPB(ctx, &domain.PersonalBest{MemberID: "member-1", ExerciseID: "squat", Weight: 100, Reps: 5, ScheduleID: "sched-1"})
+
+	require.NoError(t, err)
+	require.Len(t, *sent, 2)
+	assert.Equal(t, "member-1", (*sent)[0].UserID)
+	assert.Equal(t, "You set a new Back Squat personal best: 100 kg × 5", (*sent)[0].Body)
+	assert.Equal(t, "coach-1", (*sent)[1].UserID)
+	assert.Equal(t, "Your member set a new Back Squat personal best: 100 kg × 5", (*sent)[1].Body)
+	assert.Equal(t, "member-1", (*sent)[1].Data["member_id"])
+	assert.NotContains(t, (*sent)[0].Data, "member_id")
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// photoExtensions are the accepted photo formats, the ones PDF reports can embed
+var photoExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+// maxPhotoCaption bounds a photo's caption, in characters
+const maxPhotoCaption = 200
+
+// SessionPhotoService stores the photos coaches take during sessions. Coaches may only
+// attach them to completed sessions of members who agreed to it, and members decide
+// separately whether their photos appear in progress reports.
+type SessionPhotoService struct {
+	photoRepo    domain.SessionPhotoRepository
+	scheduleRepo domain.ScheduleRepository
+	fileRepo     domain.FileRepository // Optional: uploads are rejected when nil
+	maxBytes     int64
+	clock        domain.Clock
+}
+
+func NewSessionPhotoService(
+	photoRepo domain.SessionPhotoRepository,
+	scheduleRepo domain.ScheduleRepository,
+	fileRepo domain.FileRepository,
+	maxBytes int64,
+	clk domain.Clock,
+) *SessionPhotoService {
+	return &SessionPhotoService{
+		photoRepo:    photoRepo,
+		scheduleRepo: scheduleRepo,
+		fileRepo:     fileRepo,
+		maxBytes:     maxBytes,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// UploadPhoto attaches a coach's photo to a completed session of their tenant
+func (s *SessionPhotoService) UploadPhoto(ctx context.Context, coachID, tenantID, scheduleID string, file []byte, contentType, caption string) (*domain.SessionPhoto, error) {
+	if s.fileRepo == nil {
+		return nil, fmt.Errorf("file storage is not configured")
+	}
+	schedule, err := s.scheduleRepo.GetByID(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if !canAccessSchedule(schedule, coachID, tenantID, true) {
+		return nil, domain.ErrForbidden
+	}
+	if schedule.Status != domain.ScheduleStatusCompleted {
+		return nil, domain.ErrPhotoSessionNotCompleted
+	}
+	consent, err := s.photoRepo.GetConsent(ctx, schedule.TenantID, schedule.MemberID)
+	if err != nil {
+		return nil, err
+	}
+	if !consent.Capture {
+		return nil, domain.ErrPhotoConsentRequired
+	}
+
+	ext, ok := photoExtensions[strings.ToLower(contentType)]
+	if !ok {
+		return nil, domain.ErrUnsupportedPhoto
+	}
+	if s.maxBytes > 0 && int64(len(file)) > s.maxBytes {
+		return nil, domain.ErrPhotoTooLarge
+	}
+	caption = strings.TrimSpace(caption)
+	if runes := []rune(caption); len(runes) > maxPhotoCaption {
+		caption = string(runes[:maxPhotoCaption])
+	}
+
+	now := s.clock.Now()
+	key := fmt.Sprintf("photos/%s/%s/%d%s", schedule.TenantID, schedule.ID, now.UnixNano(), ext)
+	url, err := s.fileRepo.Upload(ctx, file, key, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	photo := &domain.SessionPhoto{
+		TenantID:    schedule.TenantID,
+		ScheduleID:  schedule.ID,
+		MemberID:    schedule.MemberID,
+		UploadedBy:  coachID,
+		URL:         url,
+		ContentType: contentType,
+		SizeBytes:   int64(len(file)),
+		Caption:     caption,
+		TakenAt:     schedule.StartTime,
+		CreatedAt:   now,
+	}
+	if err := s.photoRepo.Create(ctx, photo); err != nil {
+		return nil, err
+	}
+	return photo, nil
+}
+
+// ListSchedulePhotos returns the photos of a session the user can see
+func (s *SessionPhotoService) ListSchedulePhotos(ctx context.Context, userID, tenantID string, staff bool, scheduleID string) ([]*domain.SessionPhoto, error) {
+	schedule, err := s.scheduleRepo.GetByID(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if !canAccessSchedule(schedule, userID, tenantID, staff) {
+		return nil, domain.ErrForbidden
+	}
+	return s.photoRepo.ListBySchedule(ctx, schedule.ID)
+}
+
+// SchedulePhotos returns a session's photos, for callers that already checked access
+func (s *SessionPhotoService) SchedulePhotos(ctx context.Context, scheduleID string) ([]*domain.SessionPhoto, error) {
+	return s.photoRepo.ListBySchedule(ctx, scheduleID)
+}
+
+// DeletePhoto removes a photo. Staff can remove any photo of their tenant, members the
+// photos of themselves.
+func (s *SessionPhotoService) DeletePhoto(ctx context.Context, userID, tenantID string, staff bool, photoID string) error {
+	photo, err := s.photoRepo.GetByID(ctx, photoID)
+	if err != nil {
+		return err
+	}
+	if (staff && photo.TenantID != tenantID) || (!staff && photo.MemberID != userID) {
+		return domain.ErrSessionPhotoNotFound
+	}
+	if err := s.photoRepo.Delete(ctx, photoID); err != nil {
+		return err
+	}
+	if s.fileRepo != nil {
+		if err := s.fileRepo.Delete(ctx, photo.URL); err != nil {
+			log.Printf("Warning: session photo %s deleted but its file remains: %v", photoID, err)
+		}
+	}
+	return nil
+}
+
+// Consent returns what the member allows the tenant's coaches to do with photos
+func (s *SessionPhotoService) Consent(ctx context.Context, tenantID, memberID string) (*domain.PhotoConsent, error) {
+	return s.photoRepo.GetConsent(ctx, tenantID, memberID)
+}
+
+// SetConsent records the member's answer. Withdrawing consent to capture only stops new
+// photos; the member can delete the ones already taken.
+func (s *SessionPhotoService) SetConsent(ctx context.Context, tenantID, memberID string, capture, reports bool) (*domain.PhotoConsent, error) {
+	consent := &domain.PhotoConsent{
+		TenantID:  tenantID,
+		MemberID:  memberID,
+		Capture:   capture,
+		Reports:   reports,
+		UpdatedAt: s.clock.Now(),
+	}
+	if err := s.photoRepo.SaveConsent(ctx, consent); err != nil {
+		return nil, err
+	}
+	return consent, nil
+}
+
+// ReportPhotos returns the member's photos taken in [from, to) if they agreed to photos in
+// progress reports, oldest first
+func (s *SessionPhotoService) ReportPhotos(ctx context.Context, tenantID, memberID string, from, to time.Time) ([]*domain.SessionPhoto, error) {
+	consent, err := s.photoRepo.GetConsent(ctx, tenantID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if !consent.Reports {
+		return nil, nil
+	}
+	return s.photoRepo.ListByMember(ctx, tenantID, memberID, from, to)
+}
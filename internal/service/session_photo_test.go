@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionPhotoService_UploadPhoto(t *testing.T) {
+	ctx := context.Background()
+	jpeg := []byte{0xff, 0xd8, 0xff, 0xe0}
+	completed := &domain.Schedule{ID: "sched-1", TenantID: "gym", MemberID: "member-1",
+		Status: domain.ScheduleStatusCompleted, StartTime: testNow.Add(-2 * time.Hour)}
+
+	newService := func(t *testing.T, schedule *domain.Schedule, capture bool) (*SessionPhotoService, *mocks.SessionPhotoRepository, *mocks.FileRepository) {
+		photos, files, schedules := mocks.NewSessionPhotoRepository(t), mocks.NewFileRepository(t), mocks.NewScheduleRepository(t)
+		schedules.On("GetByID", ctx, schedule.ID).Return(schedule, nil)
+		photos.On("GetConsent", ctx, "gym", "member-1").
+			Return(&domain.PhotoConsent{TenantID: "gym", MemberID: "member-1", Capture: capture}, nil).Maybe()
+		return NewSessionPhotoService(photos, schedules, files, 1<<20, clock.NewFake(testNow)), photos, files
+	}
+
+	t.Run("stores the photo against the session", func(t *testing.T) {
+		svc, photos, files := newService(t, completed, true)
+		files.On("Upload", ctx, jpeg, "photos/gym/sched-1/1750068000000000000.jpg", "image/jpeg").Return("https://files/photo.jpg", nil)
+		photos.On("Create", ctx, mock.AnythingOfType("*domain.SessionPhoto")).Return(nil)
+
+		photo, err := svc.UploadPhoto(ctx, "coach-1", "gym", "sched-1", jpeg, "image/jpeg", "  First 100 kg squat ")
+
+		require.NoError(t, err)
+		assert.Equal(t, "member-1", photo.MemberID)
+		assert.Equal(t, "coach-1", photo.UploadedBy)
+		assert.Equal(t, "First 100 kg squat", photo.Caption)
+		assert.Equal(t, completed.StartTime, photo.TakenAt)
+	})
+
+	t.Run("needs the member's consent", func(t *testing.T) {
+		svc, _, _ := newService(t, completed, false)
+
+		_, err := svc.UploadPhoto(ctx, "coach-1", "gym", "sched-1", jpeg, "image/jpeg", "")
+
+		assert.ErrorIs(t, err, domain.ErrPhotoConsentRequired)
+	})
+
+	t.Run("only for completed sessions", func(t *testing.T) {
+		booked := *completed
+		booked.Status = domain.ScheduleStatusScheduled
+		svc, _, _ := newService(t, &booked, true)
+
+		_, err := svc.UploadPhoto(ctx, "coach-1", "gym", "sched-1", jpeg, "image/jpeg", "")
+
+		assert.ErrorIs(t, err, domain.ErrPhotoSessionNotCompleted)
+	})
+
+	t.Run("only sessions of the coach's tenant", func(t *testing.T) {
+		svc, _, _ := newService(t, completed, true)
+
+		_, err := svc.UploadPhoto(ctx, "coach-1", "other-gym", "sched-1", jpeg, "image/jpeg", "")
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("rejects other files", func(t *testing.T) {
+		svc, _, _ := newService(t, completed, true)
+
+		_, err := svc.UploadPhoto(ctx, "coach-1", "gym", "sched-1", []byte("GIF89a"), "image/gif", "")
+
+		assert.ErrorIs(t, err, domain.ErrUnsupportedPhoto)
+	})
+}
+
+func TestSessionPhotoService_DeletePhoto(t *testing.T) {
+	ctx := context.Background()
+	photo := &domain.SessionPhoto{ID: "photo-1", TenantID: "gym", MemberID: "member-1", UploadedBy: "coach-1", URL: "https://files/photo.jpg"}
+
+	newService := func(t *testing.T) (*SessionPhotoService, *mocks.SessionPhotoRepository, *mocks.FileRepository) {
+		photos, files := mocks.NewSessionPhotoRepository(t), mocks.NewFileRepository(t)
+		photos.On("GetByID", ctx, "photo-1").Return(photo, nil)
+		return NewSessionPhotoService(photos, nil, files, 0, clock.NewFake(testNow)), photos, files
+	}
+
+	t.Run("the member can remove photos of themselves", func(t *testing.T) {
+		svc, photos, files := newService(t)
+		photos.On("Delete", ctx, "photo-1").Return(nil)
+		files.On("Delete", ctx, "https://files/photo.jpg").Return(nil)
+
+		require.NoError(t, svc.DeletePhoto(ctx, "member-1", "gym", false, "photo-1"))
+	})
+
+	t.Run("nobody else's", func(t *testing.T) {
+		svc, _, _ := newService(t)
+
+		assert.ErrorIs(t, svc.DeletePhoto(ctx, "member-2", "gym", false, "photo-1"), domain.ErrSessionPhotoNotFound)
+		assert.ErrorIs(t, svc.DeletePhoto(ctx, "coach-9", "other-gym", true, "photo-1"), domain.ErrSessionPhotoNotFound)
+	})
+}
+
+func TestSessionPhotoService_ReportPhotos(t *testing.T) {
+	ctx := context.Background()
+	from, to := testNow.AddDate(0, -1, 0), testNow
+	photos := mocks.NewSessionPhotoRepository(t)
+	svc := NewSessionPhotoService(photos, nil, nil, 0, clock.NewFake(testNow))
+
+	photos.On("GetConsent", ctx, "gym", "member-1").Return(&domain.PhotoConsent{Capture: true}, nil).Once()
+	none, err := svc.ReportPhotos(ctx, "gym", "member-1", from, to)
+	require.NoError(t, err)
+	assert.Empty(t, none, "photos stay out of reports unless the member agreed")
+
+	taken := []*domain.SessionPhoto{{ID: "photo-1"}}
+	photos.On("GetConsent", ctx, "gym", "member-1").Return(&domain.PhotoConsent{Capture: true, Reports: true}, nil).Once()
+	photos.On("ListByMember", ctx, "gym", "member-1", from, to).Return(taken, nil)
+	included, err := svc.ReportPhotos(ctx, "gym", "member-1", from, to)
+	require.NoError(t, err)
+	assert.Equal(t, taken, included)
+}
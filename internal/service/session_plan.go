@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// SessionPlanService lets coaches brief members on an upcoming session, so they know
+// what's coming and can warm up for it
+type SessionPlanService struct {
+	scheduleRepo domain.ScheduleRepository
+	sessionRepo  domain.WorkoutSessionRepository
+	workout      *WorkoutService
+	notifier     *NotificationService // Optional: plans are never pushed when nil
+	clock        domain.Clock
+}
+
+func NewSessionPlanService(scheduleRepo domain.ScheduleRepository, sessionRepo domain.WorkoutSessionRepository, workout *WorkoutService, notifier *NotificationService, clk domain.Clock) *SessionPlanService {
+	return &SessionPlanService{
+		scheduleRepo: scheduleRepo,
+		sessionRepo:  sessionRepo,
+		workout:      workout,
+		notifier:     notifier,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// PlanSession sets the coach's notes on an upcoming session and, when templateID is
+// given, fills it with the template's exercises. A template can only seed a session
+// that has no exercises yet; after that the coach edits the exercises directly. With
+// notify the member is told the plan is ready.
+func (s *SessionPlanService) PlanSession(ctx context.Context, coachID, scheduleID, templateID, notes string, notify bool) (*domain.SessionPlan, error) {
+	schedule, err := s.scheduleRepo.GetByID(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.DeletedAt != nil {
+		return nil, domain.ErrScheduleNotFound
+	}
+	if schedule.CoachID != coachID {
+		return nil, domain.ErrForbidden
+	}
+	if !slices.Contains(reminderStatuses, schedule.Status) {
+		return nil, domain.ErrSessionAlreadyHeld
+	}
+
+	if templateID != "" {
+		if _, err := s.workout.templateRepo.GetByID(ctx, templateID); err != nil {
+			return nil, err
+		}
+		if existing, err := s.sessionRepo.GetByScheduleID(ctx, schedule.ID); err == nil && existing != nil {
+			return nil, domain.ErrSessionAlreadyPlanned
+		}
+		if _, err := s.workout.InitializeSession(ctx, schedule.ID, templateID); err != nil {
+			return nil, err
+		}
+	}
+
+	schedule.PlanNotes = strings.TrimSpace(notes)
+	if notify {
+		now := s.clock.Now()
+		schedule.PlanShared = &now
+	}
+	if err := s.scheduleRepo.Update(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	plan, err := s.plan(ctx, schedule)
+	if err != nil {
+		return nil, err
+	}
+	if notify && s.notifier != nil {
+		// The plan is saved either way; the member can still open it from their schedule
+		if err := s.notifier.Notify(ctx, planNotification(schedule, len(plan.Exercises))); err != nil {
+			log.Printf("Warning: plan for schedule %s saved but not sent: %v", schedule.ID, err)
+		}
+	}
+	return plan, nil
+}
+
+// MemberPlan returns the plan of one of the member's sessions, or ErrSessionPlanNotFound
+// while the coach hasn't planned anything
+func (s *SessionPlanService) MemberPlan(ctx context.Context, memberID, scheduleID string) (*domain.SessionPlan, error) {
+	schedule, err := s.scheduleRepo.GetByID(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.MemberID != memberID || schedule.DeletedAt != nil {
+		return nil, domain.ErrScheduleNotFound
+	}
+	plan, err := s.plan(ctx, schedule)
+	if err != nil {
+		return nil, err
+	}
+	if len(plan.Exercises) == 0 && plan.Notes == "" {
+		return nil, domain.ErrSessionPlanNotFound
+	}
+	return plan, nil
+}
+
+func (s *SessionPlanService) plan(ctx context.Context, schedule *domain.Schedule) (*domain.SessionPlan, error) {
+	planned, err := s.sessionRepo.GetPlannedExercisesByScheduleID(ctx, schedule.ID)
+	if err != nil {
+		return nil, err
+	}
+	exercises := make([]*domain.PlanExercise, 0, len(planned))
+	for _, p := range planned {
+		exercises = append(exercises, &domain.PlanExercise{
+			ExerciseID:  p.ExerciseID,
+			Name:        p.Name,
+			TargetSets:  p.TargetSets,
+			TargetReps:  p.TargetReps,
+			RestSeconds: p.RestSeconds,
+			Notes:       p.Notes,
+		})
+	}
+	return &domain.SessionPlan{
+		ScheduleID:  schedule.ID,
+		CoachID:     schedule.CoachID,
+		StartTime:   schedule.StartTime,
+		SessionGoal: schedule.SessionGoal,
+		FocusArea:   schedule.FocusArea,
+		Notes:       schedule.PlanNotes,
+		Exercises:   exercises,
+		SharedAt:    schedule.PlanShared,
+	}, nil
+}
+
+func planNotification(schedule *domain.Schedule, exercises int) *domain.Notification {
+	body := "Your coach planned your next session"
+	if schedule.SessionGoal != "" {
+		body += ": " + schedule.SessionGoal
+	}
+	switch {
+	case exercises == 1:
+		body += " (1 exercise)"
+	case exercises > 1:
+		body += fmt.Sprintf(" (%d exercises)", exercises)
+	}
+	return &domain.Notification{
+		UserID:   schedule.MemberID,
+		TenantID: schedule.TenantID,
+		Type:     domain.NotificationSessionPlan,
+		Title:    "Your session plan is ready",
+		Body:     body,
+		Data: map[string]string{
+			"schedule_id": schedule.ID,
+			"start_time":  schedule.StartTime.UTC().Format(time.RFC3339),
+		},
+		Link: &domain.DeepLink{Screen: domain.ScreenSessionPlan, ScheduleID: schedule.ID},
+	}
+}
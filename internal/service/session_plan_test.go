@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionPlanService_PlanSession(t *testing.T) {
+	ctx := context.Background()
+	tomorrow := testNow.AddDate(0, 0, 1)
+
+	t.Run("saves the notes and tells the member", func(t *testing.T) {
+		workout, m := newTestWorkoutService(t)
+		prefs := mocks.NewNotificationPreferencesRepository(t)
+		push := mocks.NewNotificationSender(t)
+		push.On("Channel").Return(domain.ChannelPush)
+		svc := NewSessionPlanService(m.scheduleRepo, m.sessionRepo, workout, NewNotificationService(prefs, nil, clock.NewFake(testNow), push), clock.NewFake(testNow))
+
+		schedule := &domain.Schedule{ID: testScheduleID, CoachID: "coach-1", MemberID: "member-1", StartTime: tomorrow, Status: domain.ScheduleStatusScheduled, SessionGoal: "Leg Day"}
+		m.scheduleRepo.On("GetByID", ctx, testScheduleID).Return(schedule, nil)
+		m.scheduleRepo.On("Update", ctx, mock.MatchedBy(func(s *domain.Schedule) bool {
+			return s.PlanNotes == "Bring lifting shoes" && s.PlanShared != nil
+		})).Return(nil)
+		m.sessionRepo.On("GetPlannedExercisesByScheduleID", ctx, testScheduleID).Return([]*domain.PlannedExercise{
+			{ExerciseID: "squat", Name: "Back Squat", TargetSets: 5, TargetReps: 5, RestSeconds: 180},
+		}, nil)
+		prefs.On("GetUser", ctx, "member-1").Return(&domain.NotificationPreferences{UserID: "member-1"}, nil)
+
+		var sent *domain.Notification
+		push.On("Send", ctx, mock.AnythingOfType("*domain.Notification")).Run(func(args mock.Arguments) {
+			sent = args.Get(1).(*domain.Notification)
+		}).Return(nil)
+
+		plan, err := svc.PlanSession(ctx, "coach-1", testScheduleID, "", "  Bring lifting shoes ", true)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Bring lifting shoes", plan.Notes)
+		require.Len(t, plan.Exercises, 1)
+		assert.Equal(t, "Back Squat", plan.Exercises[0].Name)
+		assert.Equal(t, testNow, *plan.SharedAt)
+		require.NotNil(t, sent)
+		assert.Equal(t, domain.NotificationSessionPlan, sent.Type)
+		assert.Equal(t, "Your coach planned your next session: Leg Day (1 exercise)", sent.Body)
+	})
+
+	t.Run("another coach's session is off limits", func(t *testing.T) {
+		workout, m := newTestWorkoutService(t)
+		svc := NewSessionPlanService(m.scheduleRepo, m.sessionRepo, workout, nil, clock.NewFake(testNow))
+		m.scheduleRepo.On("GetByID", ctx, testScheduleID).Return(&domain.Schedule{ID: testScheduleID, CoachID: "coach-2", Status: domain.ScheduleStatusScheduled}, nil)
+
+		_, err := svc.PlanSession(ctx, "coach-1", testScheduleID, "", "notes", false)
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("past sessions can't be planned", func(t *testing.T) {
+		workout, m := newTestWorkoutService(t)
+		svc := NewSessionPlanService(m.scheduleRepo, m.sessionRepo, workout, nil, clock.NewFake(testNow))
+		m.scheduleRepo.On("GetByID", ctx, testScheduleID).Return(&domain.Schedule{ID: testScheduleID, CoachID: "coach-1", Status: domain.ScheduleStatusCompleted}, nil)
+
+		_, err := svc.PlanSession(ctx, "coach-1", testScheduleID, "", "notes", false)
+
+		assert.ErrorIs(t, err, domain.ErrSessionAlreadyHeld)
+	})
+}
+
+func TestSessionPlanService_MemberPlan(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("nothing planned yet", func(t *testing.T) {
+		workout, m := newTestWorkoutService(t)
+		svc := NewSessionPlanService(m.scheduleRepo, m.sessionRepo, workout, nil, clock.NewFake(testNow))
+		m.scheduleRepo.On("GetByID", ctx, testScheduleID).Return(&domain.Schedule{ID: testScheduleID, MemberID: "member-1"}, nil)
+		m.sessionRepo.On("GetPlannedExercisesByScheduleID", ctx, testScheduleID).Return(nil, nil)
+
+		_, err := svc.MemberPlan(ctx, "member-1", testScheduleID)
+
+		assert.ErrorIs(t, err, domain.ErrSessionPlanNotFound)
+	})
+
+	t.Run("other members' sessions are hidden", func(t *testing.T) {
+		workout, m := newTestWorkoutService(t)
+		svc := NewSessionPlanService(m.scheduleRepo, m.sessionRepo, workout, nil, clock.NewFake(testNow))
+		m.scheduleRepo.On("GetByID", ctx, testScheduleID).Return(&domain.Schedule{ID: testScheduleID, MemberID: "member-2", PlanNotes: "Rest day"}, nil)
+
+		_, err := svc.MemberPlan(ctx, "member-1", testScheduleID)
+
+		assert.ErrorIs(t, err, domain.ErrScheduleNotFound)
+	})
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// videoExtensions are the accepted clip formats, both ISO base media files
+var videoExtensions = map[string]string{
+	"video/mp4":       ".mp4",
+	"video/quicktime": ".mov",
+}
+
+// VideoLimits bounds form-check uploads
+type VideoLimits struct {
+	MaxBytes    int64
+	MaxDuration time.Duration
+}
+
+// SetVideoService stores form-check videos against logged sets and the coach comments on them
+type SetVideoService struct {
+	videoRepo    domain.SetVideoRepository
+	setLogRepo   domain.SetLogRepository
+	scheduleRepo domain.ScheduleRepository
+	fileRepo     domain.FileRepository // Optional: uploads are rejected when nil
+	feedback     *FormFeedbackService  // Optional: no AI form review when nil
+	limits       VideoLimits
+	clock        domain.Clock
+}
+
+func NewSetVideoService(
+	videoRepo domain.SetVideoRepository,
+	setLogRepo domain.SetLogRepository,
+	scheduleRepo domain.ScheduleRepository,
+	fileRepo domain.FileRepository,
+	feedback *FormFeedbackService,
+	limits VideoLimits,
+	clk domain.Clock,
+) *SetVideoService {
+	return &SetVideoService{
+		videoRepo:    videoRepo,
+		setLogRepo:   setLogRepo,
+		scheduleRepo: scheduleRepo,
+		fileRepo:     fileRepo,
+		feedback:     feedback,
+		limits:       limits,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// UploadVideo attaches a clip to a set. Members may only upload to their own sets; staff
+// (coaches and admins) to any set in their tenant.
+func (s *SetVideoService) UploadVideo(ctx context.Context, userID, tenantID string, staff bool, setLogID string, file []byte, contentType string) (*domain.SetVideo, error) {
+	if s.fileRepo == nil {
+		return nil, fmt.Errorf("file storage is not configured")
+	}
+	setLog, err := s.setLogRepo.GetByID(ctx, setLogID)
+	if err != nil {
+		setLog, err = s.setLogRepo.GetByClientID(ctx, setLogID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	schedule, err := s.scheduleRepo.GetByID(ctx, setLog.ScheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if !canAccessSchedule(schedule, userID, tenantID, staff) {
+		return nil, domain.ErrForbidden
+	}
+
+	ext, ok := videoExtensions[strings.ToLower(contentType)]
+	if !ok {
+		return nil, domain.ErrUnsupportedVideo
+	}
+	if s.limits.MaxBytes > 0 && int64(len(file)) > s.limits.MaxBytes {
+		return nil, domain.ErrVideoTooLarge
+	}
+	duration, err := mp4Duration(file)
+	if err != nil {
+		return nil, err
+	}
+	if s.limits.MaxDuration > 0 && duration > s.limits.MaxDuration {
+		return nil, domain.ErrVideoTooLong
+	}
+
+	now := s.clock.Now()
+	key := fmt.Sprintf("videos/%s/%s/%d%s", schedule.TenantID, schedule.ID, now.UnixNano(), ext)
+	url, err := s.fileRepo.Upload(ctx, file, key, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	video := &domain.SetVideo{
+		TenantID:        schedule.TenantID,
+		ScheduleID:      schedule.ID,
+		SetLogID:        setLog.ID,
+		MemberID:        schedule.MemberID,
+		UploadedBy:      userID,
+		URL:             url,
+		ContentType:     contentType,
+		SizeBytes:       int64(len(file)),
+		DurationSeconds: duration.Seconds(),
+		CreatedAt:       now,
+	}
+	if err := s.videoRepo.Create(ctx, video); err != nil {
+		return nil, err
+	}
+	if s.feedback != nil {
+		s.feedback.Request(ctx, video)
+	}
+	return video, nil
+}
+
+// ListScheduleVideos returns the videos of a session the user can see
+func (s *SetVideoService) ListScheduleVideos(ctx context.Context, userID, tenantID string, staff bool, scheduleID string) ([]*domain.SetVideo, error) {
+	schedule, err := s.scheduleRepo.GetByID(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if !canAccessSchedule(schedule, userID, tenantID, staff) {
+		return nil, domain.ErrForbidden
+	}
+	return s.videoRepo.ListBySchedule(ctx, schedule.ID)
+}
+
+// VideosBySet groups a session's videos by set log ID, for callers that already checked access
+func (s *SetVideoService) VideosBySet(ctx context.Context, scheduleID string) (map[string][]*domain.SetVideo, error) {
+	videos, err := s.videoRepo.ListBySchedule(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	bySet := make(map[string][]*domain.SetVideo)
+	for _, v := range videos {
+		bySet[v.SetLogID] = append(bySet[v.SetLogID], v)
+	}
+	return bySet, nil
+}
+
+// AddComment annotates a video of the coach's tenant
+func (s *SetVideoService) AddComment(ctx context.Context, authorID, tenantID, videoID, text string, atSeconds *float64) (*domain.VideoComment, error) {
+	video, err := s.videoRepo.GetByID(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	if video.TenantID != tenantID {
+		return nil, domain.ErrSetVideoNotFound
+	}
+	text = strings.TrimSpace(text)
+	if text == "" || (atSeconds != nil && (*atSeconds < 0 || *atSeconds > video.DurationSeconds)) {
+		return nil, domain.ErrInvalidComment
+	}
+
+	comment := &domain.VideoComment{
+		AuthorID:  authorID,
+		Text:      text,
+		AtSeconds: atSeconds,
+		CreatedAt: s.clock.Now(),
+	}
+	if err := s.videoRepo.AddComment(ctx, videoID, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// DeleteVideo removes a video; members can only remove their own uploads
+func (s *SetVideoService) DeleteVideo(ctx context.Context, userID, tenantID string, staff bool, videoID string) error {
+	video, err := s.videoRepo.GetByID(ctx, videoID)
+	if err != nil {
+		return err
+	}
+	if (staff && video.TenantID != tenantID) || (!staff && video.UploadedBy != userID) {
+		return domain.ErrSetVideoNotFound
+	}
+	if err := s.videoRepo.Delete(ctx, videoID); err != nil {
+		return err
+	}
+	if s.fileRepo != nil {
+		if err := s.fileRepo.Delete(ctx, video.URL); err != nil {
+			log.Printf("Warning: video %s deleted but its file remains: %v", videoID, err)
+		}
+	}
+	return nil
+}
+
+func canAccessSchedule(schedule *domain.Schedule, userID, tenantID string, staff bool) bool {
+	if staff {
+		return schedule.TenantID == tenantID
+	}
+	return schedule.MemberID == userID
+}
+
+// mp4Duration reads a clip's length from the movie header (moov/mvhd) of an MP4 or
+// QuickTime file, so limits hold without decoding the video
+func mp4Duration(data []byte) (time.Duration, error) {
+	moov, ok := findBox(data, "moov")
+	if !ok {
+		return 0, domain.ErrUnsupportedVideo
+	}
+	mvhd, ok := findBox(moov, "mvhd")
+	if !ok || len(mvhd) < 20 {
+		return 0, domain.ErrUnsupportedVideo
+	}
+
+	var timescale uint32
+	var duration uint64
+	if mvhd[0] == 1 { // 64-bit times
+		if len(mvhd) < 32 {
+			return 0, domain.ErrUnsupportedVideo
+		}
+		timescale = binary.BigEndian.Uint32(mvhd[20:24])
+		duration = binary.BigEndian.Uint64(mvhd[24:32])
+	} else {
+		timescale = binary.BigEndian.Uint32(mvhd[12:16])
+		duration = uint64(binary.BigEndian.Uint32(mvhd[16:20]))
+	}
+	if timescale == 0 {
+		return 0, domain.ErrUnsupportedVideo
+	}
+	return time.Duration(float64(duration) / float64(timescale) * float64(time.Second)), nil
+}
+
+// findBox returns the payload of the first box of the given type at this level
+func findBox(data []byte, boxType string) ([]byte, bool) {
+	payload, _, ok := nextBox(data, boxType)
+	return payload, ok
+}
+
+// nextBox is findBox that also returns the boxes after the one found, to look for more
+func nextBox(data []byte, boxType string) (payload, rest []byte, ok bool) {
+	for len(data) >= 8 {
+		size, header := uint64(binary.BigEndian.Uint32(data[:4])), uint64(8)
+		switch size {
+		case 0: // Runs to the end of the file
+			size = uint64(len(data))
+		case 1: // 64-bit size follows the type
+			if len(data) < 16 {
+				return nil, nil, false
+			}
+			size, header = binary.BigEndian.Uint64(data[8:16]), 16
+		}
+		if size < header || size > uint64(len(data)) {
+			return nil, nil, false
+		}
+		if string(data[4:8]) == boxType {
+			return data[header:size], data[size:], true
+		}
+		data = data[size:]
+	}
+	return nil, nil, false
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func box(boxType string, payload ...[]byte) []byte {
+	size := 8
+	for _, p := range payload {
+		size += len(p)
+	}
+	b := binary.BigEndian.AppendUint32(nil, uint32(size))
+	b = append(b, boxType...)
+	for _, p := range payload {
+		b = append(b, p...)
+	}
+	return b
+}
+
+// testMP4 builds the boxes mp4Duration reads: ftyp, then moov holding a version 0 mvhd
+func testMP4(seconds uint32) []byte {
+	mvhd := make([]byte, 20)
+	binary.BigEndian.PutUint32(mvhd[12:16], 1000) // timescale
+	binary.BigEndian.PutUint32(mvhd[16:20], seconds*1000)
+	return append(box("ftyp", []byte("isom")), box("moov", box("mvhd", mvhd))...)
+}
+
+func TestMP4Duration(t *testing.T) {
+	d, err := mp4Duration(testMP4(12))
+	require.NoError(t, err)
+	assert.Equal(t, 12*time.Second, d)
+
+	_, err = mp4Duration([]byte("not a video"))
+	assert.ErrorIs(t, err, domain.ErrUnsupportedVideo)
+}
+
+func TestSetVideoService_UploadVideo(t *testing.T) {
+	ctx := context.Background()
+	limits := VideoLimits{MaxBytes: 1 << 20, MaxDuration: 30 * time.Second}
+	set := &domain.SetLogDocument{ID: testScheduleID, ScheduleID: "sched-1"}
+	schedule := &domain.Schedule{ID: "sched-1", TenantID: "gym", MemberID: "member-1"}
+
+	newService := func(t *testing.T) (*SetVideoService, *mocks.SetVideoRepository, *mocks.FileRepository) {
+		videos, files := mocks.NewSetVideoRepository(t), mocks.NewFileRepository(t)
+		setLogs, schedules := mocks.NewSetLogRepository(t), mocks.NewScheduleRepository(t)
+		setLogs.On("GetByID", ctx, testScheduleID).Return(set, nil)
+		schedules.On("GetByID", ctx, "sched-1").Return(schedule, nil)
+		return NewSetVideoService(videos, setLogs, schedules, files, nil, limits, clock.NewFake(testNow)), videos, files
+	}
+
+	t.Run("stores the clip against the set", func(t *testing.T) {
+		svc, videos, files := newService(t)
+		files.On("Upload", ctx, mock.Anything, mock.MatchedBy(func(key string) bool {
+			return key == "videos/gym/sched-1/1750068000000000000.mp4"
+		}), "video/mp4").Return("https://files/clip.mp4", nil)
+		videos.On("Create", ctx, mock.AnythingOfType("*domain.SetVideo")).Return(nil)
+
+		video, err := svc.UploadVideo(ctx, "member-1", "gym", false, testScheduleID, testMP4(12), "video/mp4")
+
+		require.NoError(t, err)
+		assert.Equal(t, 12.0, video.DurationSeconds)
+		assert.Equal(t, "member-1", video.MemberID)
+	})
+
+	t.Run("rejects clips over the duration limit", func(t *testing.T) {
+		svc, _, _ := newService(t)
+
+		_, err := svc.UploadVideo(ctx, "member-1", "gym", false, testScheduleID, testMP4(45), "video/mp4")
+
+		assert.ErrorIs(t, err, domain.ErrVideoTooLong)
+	})
+
+	t.Run("members only upload to their own sets", func(t *testing.T) {
+		svc, _, _ := newService(t)
+
+		_, err := svc.UploadVideo(ctx, "member-2", "gym", false, testScheduleID, testMP4(12), "video/mp4")
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// SettlementService matches the payment provider's settlement files against recorded
+// invoices, so finance can spot amounts that differ, payments whose webhook never
+// arrived and payments the provider hasn't settled.
+type SettlementService struct {
+	invoiceRepo    domain.InvoiceRepository
+	settlementRepo domain.SettlementRepository
+	clock          domain.Clock
+}
+
+func NewSettlementService(invoiceRepo domain.InvoiceRepository, settlementRepo domain.SettlementRepository, clk domain.Clock) *SettlementService {
+	return &SettlementService{
+		invoiceRepo:    invoiceRepo,
+		settlementRepo: settlementRepo,
+		clock:          clock.OrReal(clk),
+	}
+}
+
+// Reconcile matches an uploaded settlement file and saves the result. With a period
+// [from, to), provider payments recorded in it that the file doesn't list are flagged too.
+func (s *SettlementService) Reconcile(ctx context.Context, tenantID, actorID, fileName string, data []byte, from, to *time.Time) (*domain.SettlementReconciliation, error) {
+	if (from == nil) != (to == nil) || (from != nil && !from.Before(*to)) {
+		return nil, fmt.Errorf("%w: period needs a start before its end", domain.ErrInvalidSettlementFile)
+	}
+	rows, err := parseSettlementCSV(data)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &domain.SettlementReconciliation{
+		TenantID:   tenantID,
+		FileName:   fileName,
+		UploadedBy: actorID,
+		PeriodFrom: from,
+		PeriodTo:   to,
+		Currency:   domain.DefaultCurrency,
+		Items:      []domain.SettlementItem{},
+		CreatedAt:  s.clock.Now(),
+	}
+	settled := map[string]bool{} // Payment references, or invoice IDs for invoices without payments
+	seen := map[string]bool{}
+	for _, row := range rows {
+		item, ref, err := s.match(ctx, tenantID, row, seen)
+		if err != nil {
+			return nil, err
+		}
+		if ref != "" {
+			settled[ref] = true
+		}
+		rec.Items = append(rec.Items, item)
+		rec.Summary.Count(item)
+	}
+
+	if from != nil {
+		unsettled, err := s.unsettled(ctx, tenantID, *from, *to, settled)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range unsettled {
+			rec.Items = append(rec.Items, item)
+			rec.Summary.Count(item)
+		}
+	}
+
+	if err := s.settlementRepo.Create(ctx, rec); err != nil {
+		return nil, err
+	}
+	log.Printf("[Settlement] Tenant %s reconciled %s: %d rows, %d matched, %d mismatched, %d missing webhooks, %d unknown, %d not settled",
+		tenantID, fileName, rec.Summary.Rows, rec.Summary.Matched, rec.Summary.AmountMismatch,
+		rec.Summary.MissingWebhooks, rec.Summary.Unknown, rec.Summary.NotSettled)
+	return rec, nil
+}
+
+// Get returns one of the tenant's reconciliations with its items
+func (s *SettlementService) Get(ctx context.Context, tenantID, id string) (*domain.SettlementReconciliation, error) {
+	rec, err := s.settlementRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rec.TenantID != tenantID {
+		return nil, domain.ErrNotFound
+	}
+	return rec, nil
+}
+
+// List pages the tenant's reconciliations, newest first
+func (s *SettlementService) List(ctx context.Context, tenantID string, q domain.PageQuery) (*domain.Page[*domain.SettlementReconciliation], error) {
+	return s.settlementRepo.ListByTenant(ctx, tenantID, q)
+}
+
+// match finds the recorded payment for a settled row. It returns the reference of the
+// payment it settles, if any.
+func (s *SettlementService) match(ctx context.Context, tenantID string, row domain.SettlementRow, seen map[string]bool) (domain.SettlementItem, string, error) {
+	item := domain.SettlementItem{
+		Line:          row.Line,
+		SessionID:     row.SessionID,
+		TrxID:         row.TrxID,
+		SettledAmount: row.Amount,
+		Fee:           row.Fee,
+		SettledAt:     row.SettledAt,
+	}
+	key := fmt.Sprintf("%s:%d", row.SessionID, row.TrxID)
+	if seen[key] {
+		item.Status = domain.SettlementDuplicate
+		return item, "", nil
+	}
+	seen[key] = true
+
+	invoice, err := s.invoiceRepo.GetByPaymentSessionID(ctx, row.SessionID)
+	if errors.Is(err, domain.ErrNotFound) {
+		item.Status = domain.SettlementUnknown
+		return item, "", nil
+	}
+	if err != nil {
+		return item, "", err
+	}
+	if invoice.TenantID != tenantID {
+		item.Status = domain.SettlementUnknown
+		return item, "", nil
+	}
+	item.InvoiceID, item.UserID = invoice.ID, invoice.UserID
+
+	payment := settledPayment(invoice, row)
+	if payment == nil {
+		item.Status = domain.SettlementMissingWebhook
+		return item, "", nil
+	}
+	paidAt := payment.PaidAt
+	item.PaidAt = &paidAt
+	item.RecordedAmount = payment.Amount
+	item.Status = domain.SettlementMatched
+	if payment.Amount != row.Amount {
+		item.Status = domain.SettlementAmountMismatch
+	}
+	ref := payment.Reference
+	if ref == "" {
+		ref = invoice.ID
+	}
+	return item, ref, nil
+}
+
+// settledPayment returns the provider payment recorded for the row, or nil. Invoices paid
+// before payments were recorded one by one stand for a single payment of their amount.
+func settledPayment(invoice *domain.Invoice, row domain.SettlementRow) *domain.InvoicePayment {
+	if len(invoice.Payments) == 0 {
+		if invoice.Status != domain.InvoiceStatusPaid {
+			return nil
+		}
+		return &domain.InvoicePayment{Amount: invoice.Amount.Minor, PaidAt: invoice.UpdatedAt}
+	}
+	for i := range invoice.Payments {
+		p := &invoice.Payments[i]
+		if domain.IsManualPaymentChannel(p.Channel) {
+			continue
+		}
+		if p.Reference == fmt.Sprintf("%s:%d", row.SessionID, row.TrxID) ||
+			(row.TrxID == 0 && strings.HasPrefix(p.Reference, row.SessionID+":")) {
+			return p
+		}
+	}
+	return nil
+}
+
+// unsettled lists provider payments recorded in [from, to) that no row settled
+func (s *SettlementService) unsettled(ctx context.Context, tenantID string, from, to time.Time, settled map[string]bool) ([]domain.SettlementItem, error) {
+	invoices, err := s.invoiceRepo.ListPaidBetween(ctx, tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	var items []domain.SettlementItem
+	flag := func(invoice *domain.Invoice, ref, sessionID string, p domain.InvoicePayment) {
+		if settled[ref] || p.PaidAt.Before(from) || !p.PaidAt.Before(to) {
+			return
+		}
+		paidAt := p.PaidAt
+		items = append(items, domain.SettlementItem{
+			Status:         domain.SettlementNotSettled,
+			SessionID:      sessionID,
+			InvoiceID:      invoice.ID,
+			UserID:         invoice.UserID,
+			RecordedAmount: p.Amount,
+			PaidAt:         &paidAt,
+		})
+	}
+	for _, invoice := range invoices {
+		if len(invoice.Payments) == 0 {
+			if invoice.Status == domain.InvoiceStatusPaid {
+				flag(invoice, invoice.ID, invoice.PaymentSessionID, domain.InvoicePayment{Amount: invoice.Amount.Minor, PaidAt: invoice.UpdatedAt})
+			}
+			continue
+		}
+		for _, p := range invoice.Payments {
+			if domain.IsManualPaymentChannel(p.Channel) {
+				continue
+			}
+			sessionID, _, _ := strings.Cut(p.Reference, ":")
+			flag(invoice, p.Reference, sessionID, p)
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// Settlement file columns, by normalized header. Several names are listed where provider
+// exports differ.
+var (
+	settlementSessionColumns = []string{"sid", "sessionid", "paymentsessionid"}
+	settlementTrxColumns     = []string{"trxid", "transactionid", "trx"}
+	settlementAmountColumns  = []string{"amount", "grossamount", "total"}
+	settlementFeeColumns     = []string{"fee", "transactionfee", "mdr"}
+	settlementDateColumns    = []string{"settledat", "settlementdate", "settleddate", "date"}
+)
+
+// settlementColumns are the positions of the settlement file's columns, -1 if absent
+type settlementColumns struct {
+	session, trx, amount, fee, date int
+}
+
+// parseSettlementCSV reads a provider settlement file. The header is the first row with a
+// session and an amount column. Amounts may use comma thousands separators; dates without
+// a zone are UTC.
+func parseSettlementCSV(data []byte) ([]domain.SettlementRow, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	var cols *settlementColumns
+	var rows []domain.SettlementRow
+	for {
+		cells, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidSettlementFile, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		if cols == nil {
+			cols = settlementHeader(cells)
+			continue
+		}
+		if blankRow(cells) {
+			continue
+		}
+		if len(rows) == domain.MaxSettlementRows {
+			return nil, fmt.Errorf("%w: more than %d rows", domain.ErrInvalidSettlementFile, domain.MaxSettlementRows)
+		}
+		row, err := parseSettlementRow(cells, cols)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", domain.ErrInvalidSettlementFile, line, err)
+		}
+		row.Line = line
+		rows = append(rows, row)
+	}
+	if cols == nil {
+		return nil, fmt.Errorf("%w: no header with session and amount columns", domain.ErrInvalidSettlementFile)
+	}
+	return rows, nil
+}
+
+// settlementHeader returns the column positions, or nil if cells isn't the header
+func settlementHeader(cells []string) *settlementColumns {
+	find := func(names []string) int {
+		for i, cell := range cells {
+			name := normalizeCSVColumn(cell)
+			for _, want := range names {
+				if name == want {
+					return i
+				}
+			}
+		}
+		return -1
+	}
+	cols := &settlementColumns{
+		session: find(settlementSessionColumns),
+		trx:     find(settlementTrxColumns),
+		amount:  find(settlementAmountColumns),
+		fee:     find(settlementFeeColumns),
+		date:    find(settlementDateColumns),
+	}
+	if cols.session < 0 || cols.amount < 0 {
+		return nil
+	}
+	return cols
+}
+
+func parseSettlementRow(cells []string, cols *settlementColumns) (domain.SettlementRow, error) {
+	cell := func(i int) string {
+		if i < 0 || i >= len(cells) {
+			return ""
+		}
+		return strings.TrimSpace(cells[i])
+	}
+
+	row := domain.SettlementRow{SessionID: cell(cols.session)}
+	if row.SessionID == "" {
+		return row, errors.New("missing session")
+	}
+	amount, err := parseSettlementAmount(cell(cols.amount))
+	if err != nil {
+		return row, fmt.Errorf("amount: %w", err)
+	}
+	row.Amount = amount
+	if raw := cell(cols.fee); raw != "" {
+		if row.Fee, err = parseSettlementAmount(raw); err != nil {
+			return row, fmt.Errorf("fee: %w", err)
+		}
+	}
+	if raw := cell(cols.trx); raw != "" {
+		if row.TrxID, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			return row, fmt.Errorf("transaction %q is not a number", raw)
+		}
+	}
+	if raw := cell(cols.date); raw != "" {
+		at, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			if at, err = parseScanCSVDate(raw, time.UTC); err != nil {
+				return row, fmt.Errorf("unrecognised date %q", raw)
+			}
+		}
+		row.SettledAt = &at
+	}
+	return row, nil
+}
+
+// parseSettlementAmount reads an amount such as "1,500,000" or "1500000.00" in the
+// smallest currency unit
+func parseSettlementAmount(raw string) (int64, error) {
+	raw = strings.ReplaceAll(strings.TrimSpace(raw), ",", "")
+	if raw == "" {
+		return 0, errors.New("missing")
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%q is not an amount", raw)
+	}
+	return int64(math.Round(v)), nil
+}
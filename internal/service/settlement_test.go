@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSettlementCSV(t *testing.T) {
+	t.Run("reads columns by header after title rows", func(t *testing.T) {
+		rows, err := parseSettlementCSV([]byte("\xef\xbb\xbfSettlement report\n" +
+			"Settlement Date,SID,Trx ID,Amount,Fee\n" +
+			"2026-10-01 09:00:00,sid-1,42,\"1,500,000\",4000\n" +
+			",,,,\n"))
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.Equal(t, domain.SettlementRow{Line: 3, SessionID: "sid-1", TrxID: 42, Amount: 1_500_000, Fee: 4000,
+			SettledAt: ptrTime(time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC))}, rows[0])
+	})
+
+	t.Run("rejects files it can't read", func(t *testing.T) {
+		_, err := parseSettlementCSV([]byte("date,total\n2026-10-01,100\n"))
+		assert.ErrorIs(t, err, domain.ErrInvalidSettlementFile)
+		_, err = parseSettlementCSV([]byte("sid,amount\nsid-1,abc\n"))
+		assert.ErrorIs(t, err, domain.ErrInvalidSettlementFile)
+	})
+}
+
+func TestSettlementService_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	invoices, settlements := mocks.NewInvoiceRepository(t), mocks.NewSettlementRepository(t)
+	svc := NewSettlementService(invoices, settlements, clock.NewFake(testNow))
+
+	plan := threePart(testNow)
+	plan.Payments = []domain.InvoicePayment{
+		{Reference: "sid-1:1", Amount: 1_000_000, PaidAt: testNow.AddDate(0, 0, -3), Channel: domain.PaymentChannelProvider},
+		{Reference: "sid-2:2", Amount: 500_000, PaidAt: testNow.AddDate(0, 0, -2), Channel: domain.PaymentChannelProvider},
+		{Reference: "manual:x", Amount: 500_000, PaidAt: testNow.AddDate(0, 0, -1), Channel: domain.PaymentChannelCash},
+	}
+	pro := &domain.Invoice{ID: "pro", UserID: "m2", TenantID: "gym", Amount: domain.NewMoney(99_000, "IDR"), Status: domain.InvoiceStatusPaid,
+		PaymentSessionID: "sid-pro", UpdatedAt: testNow.AddDate(0, 0, -1)}
+	pending := &domain.Invoice{ID: "pending", UserID: "m3", TenantID: "gym", Amount: domain.NewMoney(99_000, "IDR"), Status: domain.InvoiceStatusPending}
+
+	invoices.On("GetByPaymentSessionID", ctx, "sid-1").Return(plan, nil)
+	invoices.On("GetByPaymentSessionID", ctx, "sid-pro").Return(pro, nil)
+	invoices.On("GetByPaymentSessionID", ctx, "sid-3").Return(pending, nil)
+	invoices.On("GetByPaymentSessionID", ctx, "sid-x").Return(nil, domain.ErrNotFound)
+	from, to := testNow.AddDate(0, 0, -7), testNow
+	invoices.On("ListPaidBetween", ctx, "gym", from, to).Return([]*domain.Invoice{plan, pro}, nil)
+	settlements.On("Create", ctx, mock.AnythingOfType("*domain.SettlementReconciliation")).Return(nil).Once()
+
+	csv := "sid,trx_id,amount,fee\n" +
+		"sid-1,1,1000000,4000\n" +
+		"sid-pro,9,90000,0\n" + // Settled less than the invoice
+		"sid-3,5,99000,0\n" + // Webhook never arrived
+		"sid-x,6,50000,0\n" +
+		"sid-1,1,1000000,4000\n"
+	rec, err := svc.Reconcile(ctx, "gym", "admin-1", "oct.csv", []byte(csv), &from, &to)
+	require.NoError(t, err)
+
+	statuses := []string{}
+	for _, item := range rec.Items {
+		statuses = append(statuses, item.Status)
+	}
+	assert.Equal(t, []string{domain.SettlementMatched, domain.SettlementAmountMismatch, domain.SettlementMissingWebhook,
+		domain.SettlementUnknown, domain.SettlementDuplicate, domain.SettlementNotSettled}, statuses)
+	assert.Equal(t, "sid-2", rec.Items[5].SessionID)
+	assert.Equal(t, int64(500_000), rec.Items[5].RecordedAmount)
+	assert.Equal(t, 5, rec.Summary.Rows)
+	assert.Equal(t, 1, rec.Summary.NotSettled)
+	assert.Equal(t, int64(8000), rec.Summary.Fees)
+}
+
+func ptrTime(t time.Time) *time.Time { return &t }
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+const (
+	shareTokenPrefix = "shr_"
+	shareViewsPerIP  = 60 // Public card views per visitor IP per window
+	shareRateWindow  = time.Minute
+	shareMonthLayout = "2006-01"
+)
+
+// ShareLinkService lets members share cards of their achievements, a PB or a month of
+// training, through public links that show the gym's branding. Cards hold a minimum of
+// data, fixed when the link is made, and links work until the member revokes them.
+type ShareLinkService struct {
+	links        domain.ShareLinkRepository
+	limiter      domain.TokenBucket
+	tenantRepo   domain.TenantRepository
+	userRepo     domain.UserRepository
+	pbRepo       domain.PersonalBestRepository
+	exerciseRepo domain.ExerciseRepository
+	volumeRepo   domain.DailyVolumeRepository
+	clock        domain.Clock
+}
+
+func NewShareLinkService(
+	links domain.ShareLinkRepository,
+	limiter domain.TokenBucket,
+	tenantRepo domain.TenantRepository,
+	userRepo domain.UserRepository,
+	pbRepo domain.PersonalBestRepository,
+	exerciseRepo domain.ExerciseRepository,
+	volumeRepo domain.DailyVolumeRepository,
+	clk domain.Clock,
+) *ShareLinkService {
+	return &ShareLinkService{
+		links:        links,
+		limiter:      limiter,
+		tenantRepo:   tenantRepo,
+		userRepo:     userRepo,
+		pbRepo:       pbRepo,
+		exerciseRepo: exerciseRepo,
+		volumeRepo:   volumeRepo,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// CreateLink makes a link to a card of the member's PB (subject is its ID) or month at the
+// gym (subject is YYYY-MM), returning it with its secret. The secret is only available now.
+// Minors can't share publicly.
+func (s *ShareLinkService) CreateLink(ctx context.Context, tenantID, memberID, kind, subject string) (*domain.ShareLink, string, error) {
+	member, err := s.userRepo.GetByID(ctx, memberID)
+	if err != nil {
+		return nil, "", err
+	}
+	tenant, err := s.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return nil, "", err
+	}
+	if member.IsMinorIn(tenant, s.clock.Now()) {
+		return nil, "", domain.ErrShareLinkMinor
+	}
+	var card *domain.ShareCard
+	switch kind {
+	case domain.ShareKindPersonalBest:
+		card, err = s.personalBestCard(ctx, memberID, subject)
+	case domain.ShareKindMonth:
+		card, err = s.monthCard(ctx, tenantID, memberID, subject)
+	default:
+		err = domain.ErrInvalidShareLink
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if names := strings.Fields(member.Name); len(names) > 0 {
+		card.FirstName = names[0]
+	}
+
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", fmt.Errorf("failed to generate share token: %w", err)
+	}
+	secret := shareTokenPrefix + hex.EncodeToString(raw)
+	link := &domain.ShareLink{
+		TenantID:  tenantID,
+		MemberID:  memberID,
+		Kind:      kind,
+		SubjectID: subject,
+		TokenHash: hashToken(secret),
+		Card:      *card,
+		CreatedAt: s.clock.Now().UTC(),
+	}
+	if err := s.links.Create(ctx, link); err != nil {
+		return nil, "", err
+	}
+	return link, secret, nil
+}
+
+func (s *ShareLinkService) ListLinks(ctx context.Context, tenantID, memberID string) ([]*domain.ShareLink, error) {
+	return s.links.ListByMember(ctx, tenantID, memberID)
+}
+
+func (s *ShareLinkService) RevokeLink(ctx context.Context, memberID, id string) error {
+	return s.links.Revoke(ctx, memberID, id, s.clock.Now().UTC())
+}
+
+// View returns the card behind a public link, limiting views per visitor IP. When rate
+// limited it also returns how long to wait. Revoked links and links of gyms that are gone
+// are not found.
+func (s *ShareLinkService) View(ctx context.Context, secret, ip string) (*domain.SharedAchievement, time.Duration, error) {
+	allowed, retryAfter, err := s.limiter.Take(ctx, []domain.Bucket{
+		{Key: "share:" + ip, Limit: domain.RateLimit{Requests: shareViewsPerIP, Per: shareRateWindow}},
+	})
+	if err != nil {
+		// Cards stay up when Redis is unavailable
+		log.Printf("Warning: share link rate limit unavailable: %v", err)
+	} else if !allowed {
+		return nil, retryAfter, domain.ErrRateLimited
+	}
+
+	if !strings.HasPrefix(secret, shareTokenPrefix) {
+		return nil, 0, domain.ErrShareLinkNotFound
+	}
+	link, err := s.links.FindByHash(ctx, hashToken(secret))
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && link.RevokedAt != nil) {
+		return nil, 0, domain.ErrShareLinkNotFound
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	tenant, err := s.tenantRepo.GetByID(ctx, link.TenantID)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && (tenant.DeletedAt != nil || tenant.DeactivatedAt != nil)) {
+		return nil, 0, domain.ErrShareLinkNotFound
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return &domain.SharedAchievement{ShareCard: link.Card, Gym: tenant.Name, LogoURL: tenant.LogoURL}, 0, nil
+}
+
+func (s *ShareLinkService) personalBestCard(ctx context.Context, memberID, pbID string) (*domain.ShareCard, error) {
+	pbs, err := s.pbRepo.GetByMember(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+	for _, pb := range pbs {
+		if pbID == "" || pb.ID != pbID {
+			continue
+		}
+		title := "New personal best"
+		if exercise, err := s.exerciseRepo.GetByID(ctx, pb.ExerciseID); err == nil {
+			title = fmt.Sprintf("New %s personal best", exercise.Name)
+		}
+		return &domain.ShareCard{
+			Kind:     domain.ShareKindPersonalBest,
+			Title:    title,
+			Headline: fmt.Sprintf("%g kg × %d", pb.Weight, pb.Reps),
+			Date:     pb.AchievedAt,
+		}, nil
+	}
+	return nil, domain.ErrInvalidShareLink
+}
+
+// monthCard sums up the member's sessions, volume and PBs at the gym in the month
+func (s *ShareLinkService) monthCard(ctx context.Context, tenantID, memberID, month string) (*domain.ShareCard, error) {
+	from, err := time.Parse(shareMonthLayout, month)
+	if err != nil || from.After(s.clock.Now()) {
+		return nil, domain.ErrInvalidShareLink
+	}
+	to := from.AddDate(0, 1, 0)
+
+	volumes, err := s.volumeRepo.GetByMemberIDAndDateRange(ctx, memberID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	sessions, volume := 0, 0.0
+	for _, v := range volumes {
+		if v.TenantID == tenantID {
+			sessions++
+			volume += v.TotalVolume
+		}
+	}
+	pbs, err := s.pbRepo.GetByMember(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+	newPBs := 0
+	for _, pb := range pbs {
+		if !pb.AchievedAt.Before(from) && pb.AchievedAt.Before(to) {
+			newPBs++
+		}
+	}
+
+	return &domain.ShareCard{
+		Kind:     domain.ShareKindMonth,
+		Title:    from.Format("January 2006") + " in review",
+		Headline: fmt.Sprintf("%d sessions", sessions),
+		Stats: []domain.ShareStat{
+			{Label: "Sessions", Value: fmt.Sprint(sessions)},
+			{Label: "Volume lifted", Value: fmt.Sprintf("%.0f kg", volume)},
+			{Label: "Personal bests", Value: fmt.Sprint(newPBs)},
+		},
+		Date: from,
+	}, nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type shareLinkMocks struct {
+	links   *mocks.ShareLinkRepository
+	limiter *mocks.TokenBucket
+	tenants *mocks.TenantRepository
+	users   *mocks.UserRepository
+	pbs     *mocks.PersonalBestRepository
+	volumes *mocks.DailyVolumeRepository
+}
+
+func newTestShareLinkService(t *testing.T) (*ShareLinkService, shareLinkMocks) {
+	m := shareLinkMocks{
+		links:   mocks.NewShareLinkRepository(t),
+		limiter: mocks.NewTokenBucket(t),
+		tenants: mocks.NewTenantRepository(t),
+		users:   mocks.NewUserRepository(t),
+		pbs:     mocks.NewPersonalBestRepository(t),
+		volumes: mocks.NewDailyVolumeRepository(t),
+	}
+	exercises := mocks.NewExerciseRepository(t)
+	exercises.On("GetByID", anyCtx, "squat").Return(&domain.Exercise{ID: "squat", Name: "Back Squat"}, nil).Maybe()
+	m.tenants.On("GetByID", anyCtx, "gym").Return(&domain.Tenant{ID: "gym", Name: "House of Metamorfit", LogoURL: "https://files/logo.png"}, nil).Maybe()
+	m.users.On("GetByID", anyCtx, "member-1").Return(&domain.User{ID: "member-1", Name: "Ana Putri", Email: "ana@example.com"}, nil).Maybe()
+	m.pbs.On("GetByMember", anyCtx, "member-1").Return([]*domain.PersonalBest{
+		{ID: "pb-1", MemberID: "member-1", ExerciseID: "squat", Weight: 92.5, Reps: 5, AchievedAt: testNow.AddDate(0, 0, -3)},
+		{ID: "pb-2", MemberID: "member-1", ExerciseID: "bench", Weight: 60, Reps: 3, AchievedAt: testNow.AddDate(0, -2, 0)},
+	}, nil).Maybe()
+	return NewShareLinkService(m.links, m.limiter, m.tenants, m.users, m.pbs, exercises, m.volumes, clock.NewFake(testNow)), m
+}
+
+func TestShareLinkService_CreateLink(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("a personal best shows the first name and the lift only", func(t *testing.T) {
+		svc, m := newTestShareLinkService(t)
+		m.links.On("Create", ctx, mock.AnythingOfType("*domain.ShareLink")).Return(nil)
+
+		link, secret, err := svc.CreateLink(ctx, "gym", "member-1", domain.ShareKindPersonalBest, "pb-1")
+
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(secret, shareTokenPrefix))
+		assert.Equal(t, hashToken(secret), link.TokenHash)
+		assert.Equal(t, domain.ShareCard{
+			Kind:      domain.ShareKindPersonalBest,
+			FirstName: "Ana",
+			Title:     "New Back Squat personal best",
+			Headline:  "92.5 kg × 5",
+			Date:      testNow.AddDate(0, 0, -3),
+		}, link.Card)
+	})
+
+	t.Run("a month counts sessions and volume at the gym only", func(t *testing.T) {
+		svc, m := newTestShareLinkService(t)
+		june := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+		m.volumes.On("GetByMemberIDAndDateRange", ctx, "member-1", june, june.AddDate(0, 1, 0)).Return([]*domain.DailyVolume{
+			{TenantID: "gym", TotalVolume: 4000},
+			{TenantID: "gym", TotalVolume: 5500},
+			{TenantID: "other-gym", TotalVolume: 9000},
+		}, nil)
+		m.links.On("Create", ctx, mock.AnythingOfType("*domain.ShareLink")).Return(nil)
+
+		link, _, err := svc.CreateLink(ctx, "gym", "member-1", domain.ShareKindMonth, "2025-06")
+
+		require.NoError(t, err)
+		assert.Equal(t, "June 2025 in review", link.Card.Title)
+		assert.Equal(t, []domain.ShareStat{
+			{Label: "Sessions", Value: "2"},
+			{Label: "Volume lifted", Value: "9500 kg"},
+			{Label: "Personal bests", Value: "1"},
+		}, link.Card.Stats)
+	})
+
+	t.Run("rejects unknown PBs and future months", func(t *testing.T) {
+		svc, _ := newTestShareLinkService(t)
+
+		_, _, err := svc.CreateLink(ctx, "gym", "member-1", domain.ShareKindPersonalBest, "pb-9")
+		assert.ErrorIs(t, err, domain.ErrInvalidShareLink)
+		_, _, err = svc.CreateLink(ctx, "gym", "member-1", domain.ShareKindMonth, "2025-07")
+		assert.ErrorIs(t, err, domain.ErrInvalidShareLink)
+		_, _, err = svc.CreateLink(ctx, "gym", "member-1", "scan", "scan-1")
+		assert.ErrorIs(t, err, domain.ErrInvalidShareLink)
+	})
+
+	t.Run("minors can't share", func(t *testing.T) {
+		svc, m := newTestShareLinkService(t)
+		born := testNow.AddDate(-15, 0, 0)
+		m.users.On("GetByID", ctx, "minor-1").Return(&domain.User{ID: "minor-1", Name: "Bima", DateOfBirth: &born}, nil)
+
+		_, _, err := svc.CreateLink(ctx, "gym", "minor-1", domain.ShareKindPersonalBest, "pb-1")
+
+		assert.ErrorIs(t, err, domain.ErrShareLinkMinor)
+	})
+}
+
+func TestShareLinkService_View(t *testing.T) {
+	ctx := context.Background()
+	card := domain.ShareCard{Kind: domain.ShareKindPersonalBest, FirstName: "Ana", Title: "New Back Squat personal best"}
+	visitor := []domain.Bucket{{Key: "share:10.0.0.1", Limit: domain.RateLimit{Requests: shareViewsPerIP, Per: shareRateWindow}}}
+	allow := func(m shareLinkMocks) {
+		m.limiter.On("Take", ctx, visitor).Return(true, time.Duration(0), nil)
+	}
+
+	t.Run("serves the card under the gym's branding", func(t *testing.T) {
+		svc, m := newTestShareLinkService(t)
+		allow(m)
+		m.links.On("FindByHash", ctx, hashToken("shr_abc")).Return(&domain.ShareLink{TenantID: "gym", Card: card}, nil)
+
+		shared, _, err := svc.View(ctx, "shr_abc", "10.0.0.1")
+
+		require.NoError(t, err)
+		assert.Equal(t, &domain.SharedAchievement{ShareCard: card, Gym: "House of Metamorfit", LogoURL: "https://files/logo.png"}, shared)
+	})
+
+	t.Run("revoked links are gone", func(t *testing.T) {
+		svc, m := newTestShareLinkService(t)
+		allow(m)
+		m.links.On("FindByHash", ctx, hashToken("shr_abc")).Return(&domain.ShareLink{TenantID: "gym", Card: card, RevokedAt: &testNow}, nil)
+
+		_, _, err := svc.View(ctx, "shr_abc", "10.0.0.1")
+
+		assert.ErrorIs(t, err, domain.ErrShareLinkNotFound)
+	})
+
+	t.Run("limits views per visitor", func(t *testing.T) {
+		svc, m := newTestShareLinkService(t)
+		m.limiter.On("Take", ctx, visitor).Return(false, 20*time.Second, nil)
+
+		_, retryAfter, err := svc.View(ctx, "shr_abc", "10.0.0.1")
+
+		assert.ErrorIs(t, err, domain.ErrRateLimited)
+		assert.Equal(t, 20*time.Second, retryAfter)
+	})
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// maxSessionLength bounds the lookback for sessions that could overlap one being covered
+const maxSessionLength = 4 * time.Hour
+
+// SubstitutionService lets a coach who can't make their sessions offer them to the other
+// coaches at the branch. Whoever claims a session first becomes its coach; tenant admins
+// can see every offer, assign a substitute themselves or withdraw an offer.
+type SubstitutionService struct {
+	userRepo     domain.UserRepository
+	schedRepo    domain.ScheduleRepository
+	contractRepo domain.PTContractRepository
+	offers       domain.SubstitutionRepository
+	notifier     *NotificationService
+	tx           domain.Transactor
+	clock        domain.Clock
+	handovers    *HandoverService // Optional: see BriefSubstitutes
+}
+
+func NewSubstitutionService(
+	userRepo domain.UserRepository,
+	schedRepo domain.ScheduleRepository,
+	contractRepo domain.PTContractRepository,
+	offers domain.SubstitutionRepository,
+	notifier *NotificationService,
+	tx domain.Transactor,
+	clk domain.Clock,
+) *SubstitutionService {
+	return &SubstitutionService{
+		userRepo:     userRepo,
+		schedRepo:    schedRepo,
+		contractRepo: contractRepo,
+		offers:       offers,
+		notifier:     notifier,
+		tx:           tx,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// BriefSubstitutes gives coaches a handover on the member of every session they take over
+func (s *SubstitutionService) BriefSubstitutes(handovers *HandoverService) {
+	s.handovers = handovers
+}
+
+// MarkUnavailable offers the coach's upcoming sessions in the tenant between from and to
+// for cover and tells the other coaches at those branches. Sessions already on offer are
+// skipped, so marking an overlapping period again is harmless.
+func (s *SubstitutionService) MarkUnavailable(ctx context.Context, coachID, tenantID string, from, to time.Time, reason string) ([]*domain.SubstitutionOffer, error) {
+	now := s.clock.Now()
+	if from.Before(now) {
+		from = now
+	}
+	if !to.After(from) || to.Sub(from) > domain.MaxUnavailableDays*24*time.Hour {
+		return nil, domain.ErrInvalidUnavailability
+	}
+
+	schedules, err := s.schedRepo.GetByCoach(ctx, coachID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the coach's sessions: %w", err)
+	}
+
+	offers := []*domain.SubstitutionOffer{}
+	for _, sched := range schedules {
+		if sched.TenantID != tenantID || sched.DeletedAt != nil || !slices.Contains(reminderStatuses, sched.Status) {
+			continue
+		}
+		offer := &domain.SubstitutionOffer{
+			TenantID:   tenantID,
+			BranchID:   sched.BranchID,
+			ScheduleID: sched.ID,
+			ContractID: sched.ContractID,
+			MemberID:   sched.MemberID,
+			CoachID:    coachID,
+			Reason:     strings.TrimSpace(reason),
+			StartTime:  sched.StartTime,
+			EndTime:    sched.EndTime,
+			Status:     domain.SubstitutionOpen,
+			CreatedAt:  now,
+		}
+		err := s.offers.Create(ctx, offer)
+		if err == domain.ErrSubstitutionExists {
+			continue
+		}
+		if err != nil {
+			return offers, err
+		}
+		offers = append(offers, offer)
+	}
+
+	if len(offers) > 0 {
+		s.broadcast(ctx, tenantID, coachID, offers)
+	}
+	return offers, nil
+}
+
+// ListOpen returns the offers the coach could claim: open, upcoming and at one of their
+// branches, excluding their own
+func (s *SubstitutionService) ListOpen(ctx context.Context, coach *domain.User) ([]*domain.SubstitutionOffer, error) {
+	branches := coach.CoachBranchIDs()
+	if len(branches) == 0 {
+		return []*domain.SubstitutionOffer{}, nil
+	}
+	offers, err := s.offers.ListOpen(ctx, coach.TenantID, branches, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(offers, func(o *domain.SubstitutionOffer) bool { return o.CoachID == coach.ID }), nil
+}
+
+// List pages through the tenant's offers for its admins. An empty status lists all.
+func (s *SubstitutionService) List(ctx context.Context, tenantID, status string, q domain.PageQuery) (*domain.Page[*domain.SubstitutionOffer], error) {
+	return s.offers.ListByTenant(ctx, tenantID, status, q)
+}
+
+// Claim makes the coach the substitute for an open offer at one of their branches. The
+// first claim wins; later ones get ErrSubstitutionClosed.
+func (s *SubstitutionService) Claim(ctx context.Context, coach *domain.User, offerID string) (*domain.SubstitutionOffer, error) {
+	return s.cover(ctx, coach, offerID, coach.ID)
+}
+
+// Assign lets a tenant admin pick the substitute for an open offer
+func (s *SubstitutionService) Assign(ctx context.Context, actorID, tenantID, offerID, coachID string) (*domain.SubstitutionOffer, error) {
+	coach, err := s.userRepo.GetByID(ctx, coachID)
+	if err != nil {
+		return nil, err
+	}
+	if scoped, err := coach.ScopedTo(tenantID); err == nil {
+		coach = scoped
+	}
+	if coach.TenantID != tenantID || !coach.HasRole(domain.RoleCoach) {
+		return nil, domain.ErrNotFound
+	}
+	offer, err := s.cover(ctx, coach, offerID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	s.notify(ctx, offer.TenantID, coach.ID, "Session assigned to you", "You're covering a session starting at ", offer)
+	return offer, nil
+}
+
+// Cancel withdraws an open offer; the session stays with its coach
+func (s *SubstitutionService) Cancel(ctx context.Context, actorID, tenantID, offerID string) (*domain.SubstitutionOffer, error) {
+	offer, err := s.get(ctx, tenantID, offerID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.clock.Now()
+	closed, err := s.offers.Close(ctx, offer.ID, domain.SubstitutionCancelled, "", actorID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !closed {
+		return nil, domain.ErrSubstitutionClosed
+	}
+	offer.Status, offer.ClosedBy, offer.ClosedAt = domain.SubstitutionCancelled, actorID, &now
+	log.Printf("[Audit] substitution offer %s for schedule %s withdrawn by %s", offer.ID, offer.ScheduleID, actorID)
+	return offer, nil
+}
+
+// cover hands the offer's session to coach, on behalf of actorID
+func (s *SubstitutionService) cover(ctx context.Context, coach *domain.User, offerID, actorID string) (*domain.SubstitutionOffer, error) {
+	offer, err := s.get(ctx, coach.TenantID, offerID)
+	if err != nil {
+		return nil, err
+	}
+	if offer.CoachID == coach.ID {
+		return nil, domain.ErrCannotCover
+	}
+	if !coach.WorksAt(offer.BranchID) {
+		return nil, domain.ErrBranchNotAllowed
+	}
+	now := s.clock.Now()
+	if offer.Status != domain.SubstitutionOpen || !offer.StartTime.After(now) {
+		return nil, domain.ErrSubstitutionClosed
+	}
+	if err := s.checkFree(ctx, coach.ID, offer); err != nil {
+		return nil, err
+	}
+
+	sched, err := s.schedRepo.GetByID(ctx, offer.ScheduleID)
+	if err != nil {
+		return nil, err
+	}
+	// Covering a cover still credits the session to the coach it originally belonged to
+	coveredFor := sched.CoveredFor
+	if coveredFor == "" {
+		coveredFor = offer.CoachID
+	}
+	if coveredFor == coach.ID {
+		coveredFor = ""
+	}
+
+	// Claiming the offer lets only one coach through; the schedule must still be the one
+	// that was offered, or neither changes
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		closed, err := s.offers.Close(ctx, offer.ID, domain.SubstitutionClaimed, coach.ID, actorID, now)
+		if err != nil {
+			return err
+		}
+		if !closed {
+			return domain.ErrSubstitutionClosed
+		}
+		moved, err := s.schedRepo.Reassign(ctx, offer.ScheduleID, offer.CoachID, coach.ID, coveredFor)
+		if err != nil {
+			return err
+		}
+		if !moved {
+			return domain.ErrSessionChanged
+		}
+		if coveredFor != "" && offer.ContractID != "" {
+			return s.contractRepo.AddCoverCoach(ctx, offer.ContractID, coach.ID)
+		}
+		return nil
+	})
+	if err == domain.ErrSessionChanged {
+		// The session was moved, cancelled or reassigned after it was offered
+		if _, cerr := s.offers.Close(ctx, offer.ID, domain.SubstitutionCancelled, "", actorID, now); cerr != nil {
+			log.Printf("Warning: stale substitution offer %s not withdrawn: %v", offer.ID, cerr)
+		}
+		return nil, domain.ErrSubstitutionClosed
+	}
+	if err != nil {
+		return nil, err
+	}
+	offer.Status, offer.ClaimedBy, offer.ClosedBy, offer.ClosedAt = domain.SubstitutionClaimed, coach.ID, actorID, &now
+	log.Printf("[Audit] schedule %s handed from coach %s to %s by %s (offer %s)", offer.ScheduleID, offer.CoachID, coach.ID, actorID, offer.ID)
+
+	coachName := coach.Name
+	if coachName == "" {
+		coachName = "another coach"
+	}
+	s.notify(ctx, offer.TenantID, offer.MemberID, "New coach for your session", coachName+" will coach your session starting at ", offer)
+	if s.handovers != nil {
+		if _, err := s.handovers.BriefSubstitute(ctx, offer); err != nil {
+			log.Printf("Warning: no handover for coach %s covering schedule %s: %v", coach.ID, offer.ScheduleID, err)
+		}
+	}
+	return offer, nil
+}
+
+// checkFree rejects a substitute who has a session overlapping the offered one
+func (s *SubstitutionService) checkFree(ctx context.Context, coachID string, offer *domain.SubstitutionOffer) error {
+	busy, err := s.schedRepo.GetByCoach(ctx, coachID, offer.StartTime.Add(-maxSessionLength), offer.EndTime)
+	if err != nil {
+		return err
+	}
+	for _, sched := range busy {
+		if sched.DeletedAt == nil && sched.StartTime.Before(offer.EndTime) && sched.EndTime.After(offer.StartTime) {
+			return domain.ErrCannotCover
+		}
+	}
+	return nil
+}
+
+func (s *SubstitutionService) get(ctx context.Context, tenantID, offerID string) (*domain.SubstitutionOffer, error) {
+	offer, err := s.offers.GetByID(ctx, offerID)
+	if err != nil {
+		return nil, err
+	}
+	if offer.TenantID != tenantID {
+		return nil, domain.ErrNotFound
+	}
+	return offer, nil
+}
+
+// broadcast tells every other coach working at the offers' branches what they could claim
+func (s *SubstitutionService) broadcast(ctx context.Context, tenantID, coachID string, offers []*domain.SubstitutionOffer) {
+	coaches, err := s.userRepo.GetByTenantAndRole(ctx, tenantID, domain.RoleCoach)
+	if err != nil {
+		log.Printf("Warning: sessions of coach %s offered for cover but coaches not told: %v", coachID, err)
+		return
+	}
+	for _, candidate := range coaches {
+		if candidate.ID == coachID {
+			continue
+		}
+		var claimable []*domain.SubstitutionOffer
+		for _, offer := range offers {
+			if candidate.WorksAt(offer.BranchID) {
+				claimable = append(claimable, offer)
+			}
+		}
+		if len(claimable) == 0 {
+			continue
+		}
+		body := fmt.Sprintf("%d session(s) at your branch need a coach, the first starting at ", len(claimable))
+		s.notify(ctx, tenantID, candidate.ID, "Sessions need cover", body, claimable[0])
+	}
+}
+
+// notify sends a substitution notification about offer; failures are only logged since
+// the offer itself is already saved
+func (s *SubstitutionService) notify(ctx context.Context, tenantID, userID, title, body string, offer *domain.SubstitutionOffer) {
+	kind, link := domain.NotificationCovered, &domain.DeepLink{Screen: domain.ScreenSchedule, ScheduleID: offer.ScheduleID}
+	if offer.Status == domain.SubstitutionOpen {
+		kind, link = domain.NotificationCoverNeeded, &domain.DeepLink{Screen: domain.ScreenCoverOffers, OfferID: offer.ID}
+	}
+	n := &domain.Notification{
+		UserID:   userID,
+		TenantID: tenantID,
+		Type:     kind,
+		Title:    title,
+		Body:     body + offer.StartTime.UTC().Format("Mon 2 Jan 15:04 MST"),
+		Data: map[string]string{
+			"offer_id":    offer.ID,
+			"schedule_id": offer.ScheduleID,
+			"branch_id":   offer.BranchID,
+			"start_time":  offer.StartTime.UTC().Format(time.RFC3339),
+		},
+		Link: link,
+	}
+	if err := s.notifier.Notify(ctx, n); err != nil {
+		log.Printf("Warning: substitution notification to %s not sent: %v", userID, err)
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type substitutionMocks struct {
+	userRepo  *mocks.UserRepository
+	schedRepo *mocks.ScheduleRepository
+	contracts *mocks.PTContractRepository
+	offers    *mocks.SubstitutionRepository
+	push      *mocks.NotificationSender
+}
+
+func newSubstitutionService(t *testing.T) (*SubstitutionService, *substitutionMocks) {
+	m := &substitutionMocks{
+		userRepo:  mocks.NewUserRepository(t),
+		schedRepo: mocks.NewScheduleRepository(t),
+		contracts: mocks.NewPTContractRepository(t),
+		offers:    mocks.NewSubstitutionRepository(t),
+		push:      mocks.NewNotificationSender(t),
+	}
+	prefs := mocks.NewNotificationPreferencesRepository(t)
+	prefs.On("GetUser", mock.Anything, mock.Anything).Return(&domain.NotificationPreferences{}, nil).Maybe()
+	m.push.On("Channel").Return(domain.ChannelPush).Maybe()
+	notifier := NewNotificationService(prefs, nil, clock.NewFake(testNow), m.push)
+
+	tx := mocks.NewTransactor(t)
+	tx.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInline).Maybe()
+	svc := NewSubstitutionService(m.userRepo, m.schedRepo, m.contracts, m.offers, notifier, tx, clock.NewFake(testNow))
+	return svc, m
+}
+
+func TestSubstitutionService_MarkUnavailable(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newSubstitutionService(t)
+	to := testNow.Add(48 * time.Hour)
+
+	m.schedRepo.On("GetByCoach", ctx, "c1", testNow, to).Return([]*domain.Schedule{
+		{ID: "s1", TenantID: "gym", BranchID: "b1", CoachID: "c1", MemberID: "m1", ContractID: "k1", Status: domain.ScheduleStatusScheduled, StartTime: testNow.Add(time.Hour)},
+		{ID: "s2", TenantID: "gym", BranchID: "b2", CoachID: "c1", MemberID: "m2", Status: domain.ScheduleStatusPendingConfirmation, StartTime: testNow.Add(2 * time.Hour)},
+		{ID: "s3", TenantID: "gym", BranchID: "b1", CoachID: "c1", Status: domain.ScheduleStatusCompleted, StartTime: testNow.Add(3 * time.Hour)},
+		{ID: "s4", TenantID: "other", BranchID: "x", CoachID: "c1", Status: domain.ScheduleStatusScheduled, StartTime: testNow.Add(4 * time.Hour)},
+		{ID: "s5", TenantID: "gym", BranchID: "b1", CoachID: "c1", Status: domain.ScheduleStatusScheduled, StartTime: testNow.Add(5 * time.Hour)},
+	}, nil)
+	m.offers.On("Create", ctx, mock.MatchedBy(func(o *domain.SubstitutionOffer) bool {
+		return o.ScheduleID == "s1" && o.ContractID == "k1" && o.Status == domain.SubstitutionOpen && o.Reason == "sick"
+	})).Return(nil).Once()
+	m.offers.On("Create", ctx, mock.MatchedBy(func(o *domain.SubstitutionOffer) bool { return o.ScheduleID == "s2" })).Return(nil).Once()
+	m.offers.On("Create", ctx, mock.MatchedBy(func(o *domain.SubstitutionOffer) bool { return o.ScheduleID == "s5" })).
+		Return(domain.ErrSubstitutionExists).Once() // Offered earlier
+
+	m.userRepo.On("GetByTenantAndRole", ctx, "gym", domain.RoleCoach).Return([]*domain.User{
+		{ID: "c1", HomeBranchID: "b1"},
+		{ID: "c2", HomeBranchID: "b1"},
+		{ID: "c3", HomeBranchID: "b3", WorkingBranchIDs: []string{"b2"}},
+		{ID: "c4", HomeBranchID: "b3"},
+	}, nil)
+	told := map[string]string{}
+	m.push.On("Send", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		n := args.Get(1).(*domain.Notification)
+		assert.Equal(t, domain.NotificationCoverNeeded, n.Type)
+		told[n.UserID] = n.Data["schedule_id"]
+	}).Return(nil)
+
+	offers, err := svc.MarkUnavailable(ctx, "c1", "gym", testNow.Add(-time.Hour), to, " sick ")
+	require.NoError(t, err)
+	require.Len(t, offers, 2)
+	assert.Equal(t, map[string]string{"c2": "s1", "c3": "s2"}, told)
+
+	_, err = svc.MarkUnavailable(ctx, "c1", "gym", testNow, testNow.Add(31*24*time.Hour), "")
+	assert.ErrorIs(t, err, domain.ErrInvalidUnavailability)
+}
+
+func TestSubstitutionService_Claim(t *testing.T) {
+	ctx := context.Background()
+	start := testNow.Add(24 * time.Hour)
+	openOffer := func() *domain.SubstitutionOffer {
+		return &domain.SubstitutionOffer{ID: "o1", TenantID: "gym", BranchID: "b1", ScheduleID: "s1", ContractID: "k1",
+			MemberID: "m1", CoachID: "c1", StartTime: start, EndTime: start.Add(time.Hour), Status: domain.SubstitutionOpen}
+	}
+	substitute := &domain.User{ID: "c2", TenantID: "gym", Name: "Rina", HomeBranchID: "b1", Roles: []string{domain.RoleCoach}}
+
+	t.Run("the substitute takes over the session", func(t *testing.T) {
+		svc, m := newSubstitutionService(t)
+		m.offers.On("GetByID", ctx, "o1").Return(openOffer(), nil)
+		m.schedRepo.On("GetByCoach", ctx, "c2", start.Add(-maxSessionLength), start.Add(time.Hour)).Return([]*domain.Schedule{
+			{ID: "earlier", StartTime: start.Add(-2 * time.Hour), EndTime: start.Add(-time.Hour)},
+		}, nil)
+		m.schedRepo.On("GetByID", ctx, "s1").Return(&domain.Schedule{ID: "s1", CoachID: "c1"}, nil)
+		m.offers.On("Close", ctx, "o1", domain.SubstitutionClaimed, "c2", "c2", testNow).Return(true, nil).Once()
+		m.schedRepo.On("Reassign", ctx, "s1", "c1", "c2", "c1").Return(true, nil).Once()
+		m.contracts.On("AddCoverCoach", ctx, "k1", "c2").Return(nil).Once()
+		m.push.On("Send", ctx, mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.UserID == "m1" && n.Type == domain.NotificationCovered
+		})).Return(nil).Once()
+
+		offer, err := svc.Claim(ctx, substitute, "o1")
+		require.NoError(t, err)
+		assert.Equal(t, domain.SubstitutionClaimed, offer.Status)
+		assert.Equal(t, "c2", offer.ClaimedBy)
+	})
+
+	t.Run("first claim wins", func(t *testing.T) {
+		svc, m := newSubstitutionService(t)
+		m.offers.On("GetByID", ctx, "o1").Return(openOffer(), nil)
+		m.schedRepo.On("GetByCoach", ctx, "c2", mock.Anything, mock.Anything).Return(nil, nil)
+		m.schedRepo.On("GetByID", ctx, "s1").Return(&domain.Schedule{ID: "s1", CoachID: "c1"}, nil)
+		m.offers.On("Close", ctx, "o1", domain.SubstitutionClaimed, "c2", "c2", testNow).Return(false, nil).Once()
+
+		_, err := svc.Claim(ctx, substitute, "o1")
+		assert.ErrorIs(t, err, domain.ErrSubstitutionClosed)
+		m.schedRepo.AssertNotCalled(t, "Reassign", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("a session changed since it was offered withdraws the offer", func(t *testing.T) {
+		svc, m := newSubstitutionService(t)
+		m.offers.On("GetByID", ctx, "o1").Return(openOffer(), nil)
+		m.schedRepo.On("GetByCoach", ctx, "c2", mock.Anything, mock.Anything).Return(nil, nil)
+		m.schedRepo.On("GetByID", ctx, "s1").Return(&domain.Schedule{ID: "s1", CoachID: "c1"}, nil)
+		m.offers.On("Close", ctx, "o1", domain.SubstitutionClaimed, "c2", "c2", testNow).Return(true, nil).Once()
+		m.schedRepo.On("Reassign", ctx, "s1", "c1", "c2", "c1").Return(false, nil).Once()
+		m.offers.On("Close", ctx, "o1", domain.SubstitutionCancelled, "", "c2", testNow).Return(true, nil).Once()
+
+		_, err := svc.Claim(ctx, substitute, "o1")
+		assert.ErrorIs(t, err, domain.ErrSubstitutionClosed)
+	})
+
+	t.Run("only free coaches at the branch", func(t *testing.T) {
+		svc, m := newSubstitutionService(t)
+		m.offers.On("GetByID", ctx, "o1").Return(openOffer(), nil)
+		m.schedRepo.On("GetByCoach", ctx, "c2", mock.Anything, mock.Anything).Return([]*domain.Schedule{
+			{ID: "clash", StartTime: start.Add(30 * time.Minute), EndTime: start.Add(90 * time.Minute)},
+		}, nil)
+
+		_, err := svc.Claim(ctx, substitute, "o1")
+		assert.ErrorIs(t, err, domain.ErrCannotCover)
+		_, err = svc.Claim(ctx, &domain.User{ID: "c3", TenantID: "gym", HomeBranchID: "b2"}, "o1")
+		assert.ErrorIs(t, err, domain.ErrBranchNotAllowed)
+		_, err = svc.Claim(ctx, &domain.User{ID: "c1", TenantID: "gym", HomeBranchID: "b1"}, "o1")
+		assert.ErrorIs(t, err, domain.ErrCannotCover)
+		_, err = svc.Claim(ctx, &domain.User{ID: "c9", TenantID: "elsewhere", HomeBranchID: "b1"}, "o1")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("an admin handing a covered session back keeps no cover record", func(t *testing.T) {
+		svc, m := newSubstitutionService(t)
+		// c2 covered for c1 and is now out too; c1 is back and gets their session again
+		offer := openOffer()
+		offer.CoachID = "c2"
+		m.offers.On("GetByID", ctx, "o1").Return(offer, nil)
+		original := &domain.User{ID: "c1", TenantID: "gym", HomeBranchID: "b1", Roles: []string{domain.RoleCoach}}
+		m.userRepo.On("GetByID", ctx, "c1").Return(original, nil)
+		m.schedRepo.On("GetByCoach", ctx, "c1", mock.Anything, mock.Anything).Return(nil, nil)
+		m.schedRepo.On("GetByID", ctx, "s1").Return(&domain.Schedule{ID: "s1", CoachID: "c2", CoveredFor: "c1"}, nil)
+		m.offers.On("Close", ctx, "o1", domain.SubstitutionClaimed, "c1", "admin", testNow).Return(true, nil).Once()
+		m.schedRepo.On("Reassign", ctx, "s1", "c2", "c1", "").Return(true, nil).Once()
+		m.push.On("Send", ctx, mock.Anything).Return(nil).Twice() // Member and coach
+
+		_, err := svc.Assign(ctx, "admin", "gym", "o1", "c1")
+		require.NoError(t, err)
+		m.contracts.AssertNotCalled(t, "AddCoverCoach", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// syncStatuses are the statuses the coach app may move a session to
+var syncStatuses = []string{
+	domain.ScheduleStatusScheduled,
+	domain.ScheduleStatusCompleted,
+	domain.ScheduleStatusCancelled,
+	domain.ScheduleStatusNoShow,
+}
+
+// syncRejection is a mutation that can't be applied as sent. It is reported in the
+// mutation's result; the rest of the batch goes ahead.
+type syncRejection struct {
+	reason string
+}
+
+func (r *syncRejection) Error() string { return r.reason }
+
+func reject(format string, args ...any) error {
+	return &syncRejection{reason: fmt.Sprintf(format, args...)}
+}
+
+// isRejection tells a mutation the coach app got wrong from a failure that should abort
+// the batch so it can be retried
+func isRejection(err error) bool {
+	var rejection *syncRejection
+	return errors.As(err, &rejection) ||
+		errors.Is(err, domain.ErrNotFound) ||
+		errors.Is(err, domain.ErrScheduleNotFound) ||
+		errors.Is(err, domain.ErrSessionNotFound) ||
+		errors.Is(err, domain.ErrForbidden)
+}
+
+// SyncService applies the mutations the coach app queued while offline and returns what
+// changed on the server since the app last synced. A batch is applied in one transaction;
+// each mutation's result is logged under its ID so a resent batch isn't applied twice.
+type SyncService struct {
+	schedRepo    domain.ScheduleRepository
+	setLogRepo   domain.SetLogRepository
+	sessionRepo  domain.WorkoutSessionRepository
+	userRepo     domain.UserRepository
+	contractRepo domain.PTContractRepository
+	syncLog      domain.SyncLogRepository
+	tx           domain.Transactor
+	workouts     *WorkoutService // Optional: records volume and PBs of sessions completed offline
+	clock        domain.Clock
+}
+
+func NewSyncService(
+	schedRepo domain.ScheduleRepository,
+	setLogRepo domain.SetLogRepository,
+	sessionRepo domain.WorkoutSessionRepository,
+	userRepo domain.UserRepository,
+	contractRepo domain.PTContractRepository,
+	syncLog domain.SyncLogRepository,
+	tx domain.Transactor,
+	workouts *WorkoutService,
+	clk domain.Clock,
+) *SyncService {
+	return &SyncService{
+		schedRepo:    schedRepo,
+		setLogRepo:   setLogRepo,
+		sessionRepo:  sessionRepo,
+		userRepo:     userRepo,
+		contractRepo: contractRepo,
+		syncLog:      syncLog,
+		tx:           tx,
+		workouts:     workouts,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// syncBatch is the state of one attempt at applying a batch
+type syncBatch struct {
+	results   []domain.SyncResult
+	completed []*domain.Schedule // Sessions the batch completed
+	members   []*domain.User     // Members the batch signed up
+}
+
+// Sync applies the coach's mutations in order and returns their results with the delta
+// since token (everything in the sync horizon when token is empty)
+func (s *SyncService) Sync(ctx context.Context, tenantID, coachID, token string, mutations []domain.SyncMutation) (*domain.SyncOutcome, error) {
+	if err := domain.ValidateSyncBatch(mutations); err != nil {
+		return nil, err
+	}
+	since, err := domain.ParseSyncToken(token)
+	if err != nil {
+		return nil, err
+	}
+	// Read before applying, so the next delta covers whatever changes alongside this sync
+	now := s.clock.Now()
+
+	batch := syncBatch{results: []domain.SyncResult{}}
+	if len(mutations) > 0 {
+		err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			// The transaction may be retried; start over each time
+			batch = syncBatch{results: make([]domain.SyncResult, 0, len(mutations))}
+			return s.apply(ctx, tenantID, coachID, mutations, &batch)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.workouts != nil {
+		for _, schedule := range batch.completed {
+			s.workouts.RecordSessionCompleted(ctx, schedule, coachID)
+		}
+	}
+
+	delta, err := s.delta(ctx, coachID, since, now)
+	if err != nil {
+		return nil, err
+	}
+	delta.Members = append(delta.Members, batch.members...)
+
+	return &domain.SyncOutcome{Results: batch.results, Delta: *delta, SyncToken: domain.SyncToken(now)}, nil
+}
+
+func (s *SyncService) apply(ctx context.Context, tenantID, coachID string, mutations []domain.SyncMutation, batch *syncBatch) error {
+	ids := make([]string, 0, len(mutations))
+	for _, m := range mutations {
+		ids = append(ids, m.ID)
+	}
+	logged, err := s.syncLog.GetByIDs(ctx, coachID, ids)
+	if err != nil {
+		return fmt.Errorf("failed to load sync log: %w", err)
+	}
+
+	for _, m := range mutations {
+		if entry, ok := logged[m.ID]; ok {
+			batch.results = append(batch.results, domain.SyncResult{
+				MutationID: m.ID,
+				Type:       entry.Type,
+				Status:     domain.SyncDuplicate,
+				ServerID:   entry.ServerID,
+				Error:      entry.Error,
+			})
+			continue
+		}
+
+		result := domain.SyncResult{MutationID: m.ID, Type: m.Type, Status: domain.SyncApplied}
+		serverID, err := s.applyOne(ctx, tenantID, coachID, m, batch)
+		if err != nil {
+			if !isRejection(err) {
+				return fmt.Errorf("mutation %s: %w", m.ID, err)
+			}
+			result.Status = domain.SyncRejected
+			result.Error = err.Error()
+		}
+		result.ServerID = serverID
+
+		entry := &domain.SyncLogEntry{
+			MutationID: m.ID,
+			CoachID:    coachID,
+			Type:       m.Type,
+			Status:     result.Status,
+			ServerID:   result.ServerID,
+			Error:      result.Error,
+			AppliedAt:  s.clock.Now(),
+		}
+		if err := s.syncLog.Record(ctx, entry); err != nil {
+			return fmt.Errorf("failed to log mutation %s: %w", m.ID, err)
+		}
+		batch.results = append(batch.results, result)
+	}
+	return nil
+}
+
+// applyOne applies a mutation and returns the server ID of what it created or changed
+func (s *SyncService) applyOne(ctx context.Context, tenantID, coachID string, m domain.SyncMutation, batch *syncBatch) (string, error) {
+	switch m.Type {
+	case domain.SyncSetLogUpsert:
+		if m.SetLog == nil || m.SetLog.ClientID == "" {
+			return "", reject("set_log with a client_id is required")
+		}
+		return s.upsertSetLog(ctx, tenantID, coachID, m.SetLog)
+	case domain.SyncSetLogDelete:
+		if m.SetLog == nil || m.SetLog.ClientID == "" {
+			return "", reject("set_log with a client_id is required")
+		}
+		return s.deleteSetLog(ctx, tenantID, coachID, m.SetLog.ClientID)
+	case domain.SyncScheduleStatus:
+		if m.ScheduleStatus == nil || m.ScheduleStatus.ScheduleID == "" {
+			return "", reject("schedule_status with a schedule_id is required")
+		}
+		return s.updateScheduleStatus(ctx, tenantID, coachID, m.ScheduleStatus, batch)
+	case domain.SyncMemberCreate:
+		if m.Member == nil {
+			return "", reject("member is required")
+		}
+		return s.createMember(ctx, tenantID, m.Member, batch)
+	default:
+		return "", reject("unknown mutation type %q", m.Type)
+	}
+}
+
+func (s *SyncService) upsertSetLog(ctx context.Context, tenantID, coachID string, in *domain.SyncSetLog) (string, error) {
+	setLog, err := s.setLogRepo.GetByClientID(ctx, in.ClientID)
+	if err != nil && !errors.Is(err, domain.ErrSessionNotFound) {
+		return "", err
+	}
+
+	if setLog == nil {
+		if in.PlannedExerciseID == "" {
+			return "", reject("planned_exercise_id is required to log a new set")
+		}
+		planned, err := s.resolvePlannedExercise(ctx, in.PlannedExerciseID)
+		if err != nil {
+			return "", err
+		}
+		schedule, err := s.ownSchedule(ctx, tenantID, coachID, planned.ScheduleID)
+		if err != nil {
+			return "", err
+		}
+		setLog = &domain.SetLogDocument{
+			ClientID:          in.ClientID,
+			PlannedExerciseID: planned.ID,
+			ScheduleID:        schedule.ID,
+			MemberID:          schedule.MemberID,
+			ExerciseID:        planned.ExerciseID,
+			SetIndex:          in.SetIndex,
+			Weight:            in.Weight,
+			Reps:              in.Reps,
+			Remarks:           in.Remarks,
+			Completed:         in.Completed,
+		}
+		if err := s.setLogRepo.Create(ctx, setLog); err != nil {
+			return "", err
+		}
+		return setLog.ID, nil
+	}
+
+	if _, err := s.ownSchedule(ctx, tenantID, coachID, setLog.ScheduleID); err != nil {
+		return "", err
+	}
+	if in.SetIndex > 0 {
+		setLog.SetIndex = in.SetIndex
+	}
+	setLog.Weight = in.Weight
+	setLog.Reps = in.Reps
+	setLog.Remarks = in.Remarks
+	setLog.Completed = in.Completed
+	if err := s.setLogRepo.Update(ctx, setLog); err != nil {
+		return "", err
+	}
+	return setLog.ID, nil
+}
+
+// deleteSetLog soft-deletes a set; one that doesn't exist (anymore) counts as deleted
+func (s *SyncService) deleteSetLog(ctx context.Context, tenantID, coachID, clientID string) (string, error) {
+	setLog, err := s.setLogRepo.GetByClientID(ctx, clientID)
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if _, err := s.ownSchedule(ctx, tenantID, coachID, setLog.ScheduleID); err != nil {
+		return "", err
+	}
+	if err := s.setLogRepo.SoftDelete(ctx, setLog.ID); err != nil {
+		return "", err
+	}
+	return setLog.ID, nil
+}
+
+// updateScheduleStatus moves a session like PUT /schedules/:id/status does
+func (s *SyncService) updateScheduleStatus(ctx context.Context, tenantID, coachID string, in *domain.SyncStatusChange, batch *syncBatch) (string, error) {
+	if !slices.Contains(syncStatuses, in.Status) {
+		return "", reject("status must be one of %s", strings.Join(syncStatuses, ", "))
+	}
+	schedule, err := s.ownSchedule(ctx, tenantID, coachID, in.ScheduleID)
+	if err != nil {
+		return "", err
+	}
+	if schedule.Status == in.Status {
+		return schedule.ID, nil
+	}
+	if err := s.schedRepo.UpdateStatus(ctx, schedule.ID, in.Status); err != nil {
+		return "", err
+	}
+	schedule.Status = in.Status
+	if in.Status == domain.ScheduleStatusCompleted {
+		batch.completed = append(batch.completed, schedule)
+	}
+	return schedule.ID, nil
+}
+
+func (s *SyncService) createMember(ctx context.Context, tenantID string, in *domain.SyncMember, batch *syncBatch) (string, error) {
+	if in.Email == "" || in.Name == "" {
+		return "", reject("member name and email are required")
+	}
+	// A duplicate key would abort the transaction, so the email is checked up front
+	_, err := s.userRepo.GetByEmail(ctx, in.Email)
+	if err == nil {
+		return "", reject("a member with this email already exists")
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return "", err
+	}
+
+	user := &domain.User{
+		Email:    in.Email,
+		Name:     in.Name,
+		Roles:    []string{domain.RoleMember},
+		TenantID: tenantID,
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return "", err
+	}
+	batch.members = append(batch.members, user)
+	return user.ID, nil
+}
+
+// ownSchedule loads a session by server ID or client ULID, making sure it's the coach's
+func (s *SyncService) ownSchedule(ctx context.Context, tenantID, coachID, idOrClientID string) (*domain.Schedule, error) {
+	schedule, err := s.schedRepo.GetByID(ctx, idOrClientID)
+	if err != nil {
+		schedule, err = s.schedRepo.GetByClientID(ctx, idOrClientID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if schedule.TenantID != tenantID || schedule.CoachID != coachID {
+		return nil, domain.ErrForbidden
+	}
+	return schedule, nil
+}
+
+// resolvePlannedExercise loads a planned exercise by server ID or client ULID
+func (s *SyncService) resolvePlannedExercise(ctx context.Context, idOrClientID string) (*domain.PlannedExercise, error) {
+	planned, err := s.sessionRepo.GetPlannedExerciseByID(ctx, idOrClientID)
+	if err == nil {
+		return planned, nil
+	}
+	return s.sessionRepo.GetPlannedExerciseByClientID(ctx, idOrClientID)
+}
+
+// delta collects what changed after since: sessions of the coach, the sets of sessions in
+// the sync horizon and of changed ones, and members whose contract or profile changed
+func (s *SyncService) delta(ctx context.Context, coachID string, since, now time.Time) (*domain.SyncDelta, error) {
+	horizon, err := s.schedRepo.GetByCoachAllStatuses(ctx, coachID, now.Add(-domain.SyncHorizon), now.Add(domain.SyncHorizon))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	schedules := horizon
+	if !since.IsZero() {
+		schedules, err = s.schedRepo.ListChangedByCoach(ctx, coachID, since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load changed sessions: %w", err)
+		}
+	}
+
+	var scheduleIDs []string
+	seen := make(map[string]bool)
+	for _, list := range [][]*domain.Schedule{horizon, schedules} {
+		for _, schedule := range list {
+			if !seen[schedule.ID] {
+				seen[schedule.ID] = true
+				scheduleIDs = append(scheduleIDs, schedule.ID)
+			}
+		}
+	}
+	setLogs, err := s.setLogRepo.ListChangedSince(ctx, scheduleIDs, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load changed sets: %w", err)
+	}
+
+	contracts, err := s.contractRepo.GetActiveContractsWithMembers(ctx, coachID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load members: %w", err)
+	}
+	members := []*domain.User{}
+	listed := make(map[string]bool)
+	for _, cm := range contracts {
+		if cm.Member == nil || cm.Contract == nil || listed[cm.Member.ID] {
+			continue
+		}
+		if since.IsZero() || cm.Member.UpdatedAt.After(since) || cm.Contract.CreatedAt.After(since) {
+			listed[cm.Member.ID] = true
+			members = append(members, cm.Member)
+		}
+	}
+
+	if schedules == nil {
+		schedules = []*domain.Schedule{}
+	}
+	if setLogs == nil {
+		setLogs = []*domain.SetLogDocument{}
+	}
+	return &domain.SyncDelta{Schedules: schedules, SetLogs: setLogs, Members: members}, nil
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type syncMocks struct {
+	schedRepo    *mocks.ScheduleRepository
+	setLogRepo   *mocks.SetLogRepository
+	sessionRepo  *mocks.WorkoutSessionRepository
+	userRepo     *mocks.UserRepository
+	contractRepo *mocks.PTContractRepository
+	syncLog      *mocks.SyncLogRepository
+}
+
+func newTestSyncService(t *testing.T) (*SyncService, *syncMocks) {
+	m := &syncMocks{
+		schedRepo:    mocks.NewScheduleRepository(t),
+		setLogRepo:   mocks.NewSetLogRepository(t),
+		sessionRepo:  mocks.NewWorkoutSessionRepository(t),
+		userRepo:     mocks.NewUserRepository(t),
+		contractRepo: mocks.NewPTContractRepository(t),
+		syncLog:      mocks.NewSyncLogRepository(t),
+	}
+	tx := mocks.NewTransactor(t)
+	tx.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInline).Maybe()
+	svc := NewSyncService(m.schedRepo, m.setLogRepo, m.sessionRepo, m.userRepo, m.contractRepo, m.syncLog, tx, nil, clock.NewFake(testNow))
+	return svc, m
+}
+
+// expectEmptyDelta stubs a first-sync delta with nothing in it
+func (m *syncMocks) expectEmptyDelta(ctx context.Context) {
+	m.schedRepo.On("GetByCoachAllStatuses", ctx, "coach-1", testNow.Add(-domain.SyncHorizon), testNow.Add(domain.SyncHorizon)).Return([]*domain.Schedule{}, nil)
+	m.setLogRepo.On("ListChangedSince", ctx, []string(nil), time.Time{}).Return(nil, nil)
+	m.contractRepo.On("GetActiveContractsWithMembers", ctx, "coach-1").Return([]*domain.ContractWithMember{}, nil)
+}
+
+func TestSyncService_Sync(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestSyncService(t)
+
+	own := &domain.Schedule{ID: "s1", TenantID: "gym", CoachID: "coach-1", MemberID: "member-1", Status: domain.ScheduleStatusScheduled}
+	m.schedRepo.On("GetByID", ctx, "s1").Return(own, nil)
+	m.schedRepo.On("GetByID", ctx, "s2").Return(&domain.Schedule{ID: "s2", TenantID: "gym", CoachID: "coach-2"}, nil)
+
+	m.syncLog.On("GetByIDs", ctx, "coach-1", []string{"m1", "m2", "m3", "m4"}).Return(map[string]*domain.SyncLogEntry{
+		"m4": {MutationID: "m4", Type: domain.SyncScheduleStatus, Status: domain.SyncApplied, ServerID: "s9"},
+	}, nil)
+
+	// m1 logs a set against an exercise the app only knows by its ULID
+	m.setLogRepo.On("GetByClientID", ctx, "set-ulid").Return(nil, domain.ErrSessionNotFound)
+	m.sessionRepo.On("GetPlannedExerciseByID", ctx, "pe-ulid").Return(nil, domain.ErrSessionNotFound)
+	m.sessionRepo.On("GetPlannedExerciseByClientID", ctx, "pe-ulid").Return(&domain.PlannedExercise{ID: "pe-1", ScheduleID: "s1", ExerciseID: "ex-1"}, nil)
+	m.setLogRepo.On("Create", ctx, mock.MatchedBy(func(l *domain.SetLogDocument) bool {
+		return l.ClientID == "set-ulid" && l.PlannedExerciseID == "pe-1" && l.MemberID == "member-1" &&
+			l.ExerciseID == "ex-1" && l.Weight == 60 && l.Reps == 8 && l.Completed
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.SetLogDocument).ID = "log-1"
+	}).Return(nil).Once()
+
+	// m2 completes the session; m3 touches another coach's
+	m.schedRepo.On("UpdateStatus", ctx, "s1", domain.ScheduleStatusCompleted).Return(nil).Once()
+
+	var logged []*domain.SyncLogEntry
+	m.syncLog.On("Record", ctx, mock.AnythingOfType("*domain.SyncLogEntry")).Run(func(args mock.Arguments) {
+		logged = append(logged, args.Get(1).(*domain.SyncLogEntry))
+	}).Return(nil)
+
+	m.schedRepo.On("GetByCoachAllStatuses", ctx, "coach-1", testNow.Add(-domain.SyncHorizon), testNow.Add(domain.SyncHorizon)).Return([]*domain.Schedule{own}, nil)
+	m.setLogRepo.On("ListChangedSince", ctx, []string{"s1"}, time.Time{}).Return([]*domain.SetLogDocument{{ID: "log-1"}}, nil)
+	member := &domain.User{ID: "member-1"}
+	m.contractRepo.On("GetActiveContractsWithMembers", ctx, "coach-1").Return([]*domain.ContractWithMember{
+		{Contract: &domain.PTContract{ID: "c1"}, Member: member},
+		{Contract: &domain.PTContract{ID: "c2"}, Member: member},
+	}, nil)
+
+	outcome, err := svc.Sync(ctx, "gym", "coach-1", "", []domain.SyncMutation{
+		{ID: "m1", Type: domain.SyncSetLogUpsert, SetLog: &domain.SyncSetLog{ClientID: "set-ulid", PlannedExerciseID: "pe-ulid", SetIndex: 1, Weight: 60, Reps: 8, Completed: true}},
+		{ID: "m2", Type: domain.SyncScheduleStatus, ScheduleStatus: &domain.SyncStatusChange{ScheduleID: "s1", Status: domain.ScheduleStatusCompleted}},
+		{ID: "m3", Type: domain.SyncScheduleStatus, ScheduleStatus: &domain.SyncStatusChange{ScheduleID: "s2", Status: domain.ScheduleStatusCancelled}},
+		{ID: "m4", Type: domain.SyncScheduleStatus, ScheduleStatus: &domain.SyncStatusChange{ScheduleID: "s9", Status: domain.ScheduleStatusCancelled}},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, outcome.Results, 4)
+	assert.Equal(t, domain.SyncResult{MutationID: "m1", Type: domain.SyncSetLogUpsert, Status: domain.SyncApplied, ServerID: "log-1"}, outcome.Results[0])
+	assert.Equal(t, domain.SyncResult{MutationID: "m2", Type: domain.SyncScheduleStatus, Status: domain.SyncApplied, ServerID: "s1"}, outcome.Results[1])
+	assert.Equal(t, domain.SyncRejected, outcome.Results[2].Status, "not the coach's session")
+	assert.Equal(t, domain.SyncDuplicate, outcome.Results[3].Status)
+	assert.Equal(t, "s9", outcome.Results[3].ServerID, "the first result is repeated")
+
+	require.Len(t, logged, 3, "duplicates aren't logged again")
+	assert.Equal(t, testNow, logged[0].AppliedAt)
+	assert.Equal(t, domain.SyncRejected, logged[2].Status)
+
+	assert.Equal(t, []*domain.Schedule{own}, outcome.Delta.Schedules)
+	assert.Len(t, outcome.Delta.SetLogs, 1)
+	assert.Equal(t, []*domain.User{member}, outcome.Delta.Members, "listed once")
+	assert.Equal(t, domain.SyncToken(testNow), outcome.SyncToken)
+}
+
+func TestSyncService_Sync_RejectsInvalidMutations(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestSyncService(t)
+
+	m.syncLog.On("GetByIDs", ctx, "coach-1", mock.Anything).Return(map[string]*domain.SyncLogEntry{}, nil)
+	m.syncLog.On("Record", ctx, mock.AnythingOfType("*domain.SyncLogEntry")).Return(nil)
+	m.userRepo.On("GetByEmail", ctx, "taken@example.com").Return(&domain.User{ID: "u1"}, nil)
+	m.expectEmptyDelta(ctx)
+
+	outcome, err := svc.Sync(ctx, "gym", "coach-1", "", []domain.SyncMutation{
+		{ID: "m1", Type: "schedule.reschedule"},
+		{ID: "m2", Type: domain.SyncScheduleStatus, ScheduleStatus: &domain.SyncStatusChange{ScheduleID: "s1", Status: domain.ScheduleStatusPendingConfirmation}},
+		{ID: "m3", Type: domain.SyncSetLogUpsert, SetLog: &domain.SyncSetLog{}},
+		{ID: "m4", Type: domain.SyncMemberCreate, Member: &domain.SyncMember{Name: "Ann", Email: "taken@example.com"}},
+	})
+
+	require.NoError(t, err)
+	for _, result := range outcome.Results {
+		assert.Equal(t, domain.SyncRejected, result.Status, result.MutationID)
+		assert.NotEmpty(t, result.Error, result.MutationID)
+	}
+	m.schedRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	m.userRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestSyncService_Sync_CreatesMembers(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestSyncService(t)
+
+	m.syncLog.On("GetByIDs", ctx, "coach-1", []string{"m1"}).Return(map[string]*domain.SyncLogEntry{}, nil)
+	m.syncLog.On("Record", ctx, mock.AnythingOfType("*domain.SyncLogEntry")).Return(nil)
+	m.userRepo.On("GetByEmail", ctx, "walkin@example.com").Return(nil, domain.ErrNotFound)
+	m.userRepo.On("Create", ctx, mock.MatchedBy(func(u *domain.User) bool {
+		return u.TenantID == "gym" && u.Name == "Walk In" && assert.ObjectsAreEqual([]string{domain.RoleMember}, u.Roles)
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.User).ID = "u-new"
+	}).Return(nil)
+	m.expectEmptyDelta(ctx)
+
+	outcome, err := svc.Sync(ctx, "gym", "coach-1", "", []domain.SyncMutation{
+		{ID: "m1", Type: domain.SyncMemberCreate, Member: &domain.SyncMember{Name: "Walk In", Email: "walkin@example.com"}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "u-new", outcome.Results[0].ServerID)
+	require.Len(t, outcome.Delta.Members, 1, "not under contract yet, but the app should have them")
+	assert.Equal(t, "u-new", outcome.Delta.Members[0].ID)
+}
+
+func TestSyncService_Sync_AbortsOnFailure(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestSyncService(t)
+
+	m.syncLog.On("GetByIDs", ctx, "coach-1", []string{"m1"}).Return(map[string]*domain.SyncLogEntry{}, nil)
+	m.setLogRepo.On("GetByClientID", ctx, "set-ulid").Return(nil, errors.New("connection reset"))
+
+	_, err := svc.Sync(ctx, "gym", "coach-1", "", []domain.SyncMutation{
+		{ID: "m1", Type: domain.SyncSetLogDelete, SetLog: &domain.SyncSetLog{ClientID: "set-ulid"}},
+	})
+
+	require.Error(t, err, "the batch is rolled back for the app to retry")
+	m.syncLog.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+}
+
+func TestSyncService_Sync_DeltaSinceToken(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestSyncService(t)
+	since := testNow.Add(-time.Hour)
+
+	horizon := []*domain.Schedule{{ID: "s1"}, {ID: "s2"}}
+	m.schedRepo.On("GetByCoachAllStatuses", ctx, "coach-1", testNow.Add(-domain.SyncHorizon), testNow.Add(domain.SyncHorizon)).Return(horizon, nil)
+	// s3 was moved out of the horizon, s2 changed inside it
+	m.schedRepo.On("ListChangedByCoach", ctx, "coach-1", since).Return([]*domain.Schedule{{ID: "s2"}, {ID: "s3"}}, nil)
+	m.setLogRepo.On("ListChangedSince", ctx, []string{"s1", "s2", "s3"}, since).Return([]*domain.SetLogDocument{{ID: "log-1"}}, nil)
+	m.contractRepo.On("GetActiveContractsWithMembers", ctx, "coach-1").Return([]*domain.ContractWithMember{
+		{Contract: &domain.PTContract{CreatedAt: since.Add(-time.Hour)}, Member: &domain.User{ID: "unchanged", UpdatedAt: since.Add(-time.Hour)}},
+		{Contract: &domain.PTContract{CreatedAt: since.Add(-time.Hour)}, Member: &domain.User{ID: "renamed", UpdatedAt: since.Add(time.Minute)}},
+		{Contract: &domain.PTContract{CreatedAt: since.Add(time.Minute)}, Member: &domain.User{ID: "signed", UpdatedAt: since.Add(-time.Hour)}},
+	}, nil)
+
+	outcome, err := svc.Sync(ctx, "gym", "coach-1", domain.SyncToken(since), nil)
+
+	require.NoError(t, err)
+	assert.Empty(t, outcome.Results)
+	assert.Len(t, outcome.Delta.Schedules, 2)
+	assert.Len(t, outcome.Delta.SetLogs, 1)
+	var ids []string
+	for _, u := range outcome.Delta.Members {
+		ids = append(ids, u.ID)
+	}
+	assert.Equal(t, []string{"renamed", "signed"}, ids)
+}
+
+func TestSyncService_Sync_InvalidToken(t *testing.T) {
+	svc, _ := newTestSyncService(t)
+
+	_, err := svc.Sync(context.Background(), "gym", "coach-1", "garbage!", nil)
+
+	assert.ErrorIs(t, err, domain.ErrInvalidSyncToken)
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+const (
+	dashboardFunnelDays      = 30 // Window of the sales conversion widget
+	dashboardLeaderboardSize = 5
+)
+
+// TenantDashboardService keeps each tenant admin's dashboard layout and fills its widgets
+// from the finance, sales, substitution, progress and presence services
+type TenantDashboardService struct {
+	layoutRepo    domain.DashboardLayoutRepository
+	contractRepo  domain.PTContractRepository
+	payments      *ManualPaymentService
+	funnel        *SalesFunnelService
+	substitutions *SubstitutionService
+	scores        *ProgressScoreService
+	presence      *PresenceService
+	clock         domain.Clock
+}
+
+func NewTenantDashboardService(
+	layoutRepo domain.DashboardLayoutRepository,
+	contractRepo domain.PTContractRepository,
+	payments *ManualPaymentService,
+	funnel *SalesFunnelService,
+	substitutions *SubstitutionService,
+	scores *ProgressScoreService,
+	presence *PresenceService,
+	clk domain.Clock,
+) *TenantDashboardService {
+	return &TenantDashboardService{
+		layoutRepo:    layoutRepo,
+		contractRepo:  contractRepo,
+		payments:      payments,
+		funnel:        funnel,
+		substitutions: substitutions,
+		scores:        scores,
+		presence:      presence,
+		clock:         clock.OrReal(clk),
+	}
+}
+
+// Layout returns the admin's layout in the tenant, or the default one if they never saved one
+func (s *TenantDashboardService) Layout(ctx context.Context, tenantID, userID string) (*domain.DashboardLayout, error) {
+	layout, err := s.layoutRepo.Get(ctx, tenantID, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.DefaultDashboardLayout(tenantID, userID), nil
+	}
+	return layout, err
+}
+
+// SaveLayout validates and stores the admin's layout
+func (s *TenantDashboardService) SaveLayout(ctx context.Context, layout *domain.DashboardLayout) (*domain.DashboardLayout, error) {
+	if layout.Widgets == nil {
+		layout.Widgets = []domain.DashboardWidget{}
+	}
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.layoutRepo.Upsert(ctx, layout); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// Dashboard fills the admin's widgets in their order
+func (s *TenantDashboardService) Dashboard(ctx context.Context, tenantID, userID string) (*domain.TenantDashboard, error) {
+	layout, err := s.Layout(ctx, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	dashboard := &domain.TenantDashboard{Layout: layout, Widgets: make([]domain.WidgetValue, 0, len(layout.Widgets))}
+	for _, widget := range layout.Widgets {
+		v := domain.WidgetValue{DashboardWidget: widget}
+		value, data, err := s.widget(ctx, tenantID, widget.Type)
+		if err != nil {
+			log.Printf("Warning: dashboard widget %s of tenant %s failed: %v", widget.Type, tenantID, err)
+			v.Error = "Data is unavailable right now"
+		} else {
+			v.Value, v.Data, v.Alert = value, data, widget.Threshold.Breached(value)
+		}
+		dashboard.Widgets = append(dashboard.Widgets, v)
+	}
+	return dashboard, nil
+}
+
+func (s *TenantDashboardService) widget(ctx context.Context, tenantID, kind string) (float64, interface{}, error) {
+	now := s.clock.Now().UTC()
+	switch kind {
+	case domain.WidgetRevenue:
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		report, err := s.payments.Reconcile(ctx, tenantID, monthStart, now)
+		if err != nil {
+			return 0, nil, err
+		}
+		return float64(report.Provider.Amount + report.Manual.Amount),
+			map[string]interface{}{"currency": report.Currency, "payments": report.Provider.Count + report.Manual.Count}, nil
+
+	case domain.WidgetActiveContracts:
+		contracts, err := s.contractRepo.GetByTenant(ctx, tenantID)
+		if err != nil {
+			return 0, nil, err
+		}
+		active, suspended := 0, 0
+		for _, contract := range contracts {
+			switch contract.Status {
+			case domain.PackageStatusActive:
+				active++
+			case domain.PackageStatusSuspended:
+				suspended++
+			}
+		}
+		return float64(active), map[string]int{"suspended": suspended}, nil
+
+	case domain.WidgetSalesConversion:
+		funnel, err := s.funnel.Funnel(ctx, tenantID, now.AddDate(0, 0, -dashboardFunnelDays), now)
+		if err != nil {
+			return 0, nil, err
+		}
+		viewed, paid := 0, 0
+		for _, pkg := range funnel.Packages {
+			if len(pkg.Steps) > 0 {
+				viewed += pkg.Steps[0].Members
+				paid += pkg.Steps[len(pkg.Steps)-1].Members
+			}
+		}
+		return math.Round(conversionRate(paid, viewed)*1000) / 10, nil, nil
+
+	case domain.WidgetOpenSubstitutions:
+		page, err := s.substitutions.List(ctx, tenantID, domain.SubstitutionOpen, domain.PageQuery{Limit: domain.MaxPageLimit})
+		if err != nil {
+			return 0, nil, err
+		}
+		return float64(len(page.Items)), nil, nil
+
+	case domain.WidgetLeaderboard:
+		entries, err := s.scores.Leaderboard(ctx, tenantID, dashboardLeaderboardSize)
+		if err != nil || len(entries) == 0 {
+			return 0, entries, err
+		}
+		return float64(entries[0].Score), entries, nil
+
+	case domain.WidgetCoachesOnline:
+		board, err := s.presence.Board(ctx, tenantID)
+		if err != nil {
+			return 0, nil, err
+		}
+		return float64(board.Online), board, nil
+	}
+	return 0, nil, fmt.Errorf("unknown widget %q", kind)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantDashboardService_Dashboard(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fills the saved widgets in order, isolating failures", func(t *testing.T) {
+		layouts, contracts, invoices := mocks.NewDashboardLayoutRepository(t), mocks.NewPTContractRepository(t), mocks.NewInvoiceRepository(t)
+		payments := NewManualPaymentService(invoices, nil, nil, clock.NewFake(testNow))
+		svc := NewTenantDashboardService(layouts, contracts, payments, nil, nil, nil, nil, clock.NewFake(testNow))
+
+		low := 3.0
+		layouts.On("Get", ctx, "gym", "admin-1").Return(&domain.DashboardLayout{TenantID: "gym", UserID: "admin-1", Widgets: []domain.DashboardWidget{
+			{Type: domain.WidgetActiveContracts, Threshold: &domain.WidgetThreshold{Low: &low}},
+			{Type: domain.WidgetRevenue},
+		}}, nil)
+		contracts.On("GetByTenant", ctx, "gym").Return([]*domain.PTContract{
+			{Status: domain.PackageStatusActive}, {Status: domain.PackageStatusActive},
+			{Status: domain.PackageStatusSuspended}, {Status: domain.PackageStatusDepleted},
+		}, nil)
+		invoices.On("ListPaidBetween", ctx, "gym", mock.Anything, mock.Anything).Return(nil, errors.New("mongo down"))
+
+		dashboard, err := svc.Dashboard(ctx, "gym", "admin-1")
+
+		require.NoError(t, err)
+		require.Len(t, dashboard.Widgets, 2)
+		active := dashboard.Widgets[0]
+		assert.Equal(t, domain.WidgetActiveContracts, active.Type)
+		assert.Equal(t, 2.0, active.Value)
+		assert.True(t, active.Alert)
+		assert.Equal(t, map[string]int{"suspended": 1}, active.Data)
+		assert.Equal(t, domain.WidgetRevenue, dashboard.Widgets[1].Type)
+		assert.NotEmpty(t, dashboard.Widgets[1].Error)
+	})
+
+	t.Run("starts from the default layout", func(t *testing.T) {
+		layouts := mocks.NewDashboardLayoutRepository(t)
+		svc := NewTenantDashboardService(layouts, nil, nil, nil, nil, nil, nil, clock.NewFake(testNow))
+		layouts.On("Get", ctx, "gym", "admin-1").Return(nil, domain.ErrNotFound)
+
+		layout, err := svc.Layout(ctx, "gym", "admin-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.DefaultDashboardLayout("gym", "admin-1"), layout)
+	})
+}
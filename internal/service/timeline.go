@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// TimelineService keeps each member's activity feed: completed sessions, personal bests,
+// scans and the goals they reached, and contracts bought or renewed. Entries are projected
+// as the events happen, by subscribing to the workout event log, the PB detector and the
+// outbox. Every entry is keyed by its event, so the at-least-once deliveries and replays
+// of those sources record it once.
+type TimelineService struct {
+	timelineRepo domain.TimelineRepository
+	scanRepo     domain.InBodyRepository
+	contractRepo domain.PTContractRepository
+	userRepo     domain.UserRepository
+	exerciseRepo domain.ExerciseRepository
+	clock        domain.Clock
+}
+
+func NewTimelineService(
+	timelineRepo domain.TimelineRepository,
+	scanRepo domain.InBodyRepository,
+	contractRepo domain.PTContractRepository,
+	userRepo domain.UserRepository,
+	exerciseRepo domain.ExerciseRepository,
+	clk domain.Clock,
+) *TimelineService {
+	return &TimelineService{
+		timelineRepo: timelineRepo,
+		scanRepo:     scanRepo,
+		contractRepo: contractRepo,
+		userRepo:     userRepo,
+		exerciseRepo: exerciseRepo,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// MemberTimeline returns a page of the member's timeline, most recent first. An empty
+// tenantID includes the member's activity at all their gyms.
+func (s *TimelineService) MemberTimeline(ctx context.Context, tenantID, memberID string, q domain.PageQuery) (*domain.Page[*domain.TimelineEntry], error) {
+	return s.timelineRepo.List(ctx, tenantID, memberID, q)
+}
+
+// HandleWorkoutEvent records completed sessions
+func (s *TimelineService) HandleWorkoutEvent(ctx context.Context, event *domain.WorkoutEvent) error {
+	if event.Type != domain.WorkoutEventSessionCompleted {
+		return nil
+	}
+	return s.record(ctx, &domain.TimelineEntry{
+		TenantID:   event.TenantID,
+		MemberID:   event.MemberID,
+		Key:        "session.completed:" + event.ScheduleID,
+		Type:       domain.TimelineSessionCompleted,
+		Title:      "Session completed",
+		Data:       map[string]interface{}{"schedule_id": event.ScheduleID, "coach_id": event.ActorID},
+		OccurredAt: event.OccurredAt,
+	})
+}
+
+// HandleNewPB records a personal best
+func (s *TimelineService) HandleNewPB(ctx context.Context, pb *domain.PersonalBest) error {
+	tenantID, err := s.memberTenant(ctx, pb.MemberID)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("New personal best: %g kg × %d", pb.Weight, pb.Reps)
+	if exercise, err := s.exerciseRepo.GetByID(ctx, pb.ExerciseID); err == nil {
+		title = fmt.Sprintf("New %s personal best: %g kg × %d", exercise.Name, pb.Weight, pb.Reps)
+	}
+	return s.record(ctx, &domain.TimelineEntry{
+		TenantID: tenantID,
+		MemberID: pb.MemberID,
+		Key:      "pb:" + pb.MemberID + ":" + pb.ExerciseID + ":" + pb.ScheduleID,
+		Type:     domain.TimelinePersonalBest,
+		Title:    title,
+		Data: map[string]interface{}{
+			"exercise_id": pb.ExerciseID,
+			"schedule_id": pb.ScheduleID,
+			"weight":      pb.Weight,
+			"reps":        pb.Reps,
+		},
+		OccurredAt: pb.AchievedAt,
+	})
+}
+
+// HandleScanDigitized is the outbox handler that records a digitized scan and, when it
+// reached the target weight of the member's scan before it, the goal
+func (s *TimelineService) HandleScanDigitized(ctx context.Context, msg *domain.OutboxMessage) error {
+	scan, err := s.scanRepo.FindByID(ctx, msg.Key)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil // Deleted since
+	}
+	if err != nil {
+		return err
+	}
+	tenantID, err := s.memberTenant(ctx, scan.UserID)
+	if err != nil {
+		return err
+	}
+
+	err = s.record(ctx, &domain.TimelineEntry{
+		TenantID: tenantID,
+		MemberID: scan.UserID,
+		Key:      "scan:" + scan.ID,
+		Type:     domain.TimelineScan,
+		Title:    fmt.Sprintf("InBody scan: %g kg, %g%% body fat", scan.Weight, scan.PBF),
+		Data: map[string]interface{}{
+			"scan_id": scan.ID,
+			"weight":  scan.Weight,
+			"pbf":     scan.PBF,
+			"smm":     scan.SMM,
+		},
+		OccurredAt: scan.TestDateTime,
+	})
+	if err != nil {
+		return err
+	}
+
+	previous, err := s.previousScan(ctx, scan)
+	if err != nil {
+		return err
+	}
+	if !domain.ReachedTargetWeight(previous, scan) {
+		return nil
+	}
+	return s.record(ctx, &domain.TimelineEntry{
+		TenantID: tenantID,
+		MemberID: scan.UserID,
+		Key:      "goal:" + scan.ID,
+		Type:     domain.TimelineGoalReached,
+		Title:    fmt.Sprintf("Reached the target weight of %g kg", previous.TargetWeight),
+		Data: map[string]interface{}{
+			"scan_id":       scan.ID,
+			"target_weight": previous.TargetWeight,
+			"weight":        scan.Weight,
+		},
+		OccurredAt: scan.TestDateTime,
+	})
+}
+
+// HandleContractCreated is the outbox handler that records a PT package bought for the member
+func (s *TimelineService) HandleContractCreated(ctx context.Context, msg *domain.OutboxMessage) error {
+	contract, err := s.contractRepo.GetByID(ctx, msg.Key)
+	if errors.Is(err, domain.ErrContractNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.record(ctx, &domain.TimelineEntry{
+		TenantID: contract.TenantID,
+		MemberID: contract.MemberID,
+		Key:      "contract.created:" + contract.ID,
+		Type:     domain.TimelineContractStarted,
+		Title:    fmt.Sprintf("Started a %d-session PT package", contract.TotalSessions),
+		Data: map[string]interface{}{
+			"contract_id":    contract.ID,
+			"package_id":     contract.PackageID,
+			"coach_id":       contract.CoachID,
+			"total_sessions": contract.TotalSessions,
+		},
+		OccurredAt: contract.CreatedAt,
+	})
+}
+
+// RecordRenewal records a contract renewed automatically; see RenewalService.ShowOnTimeline
+func (s *TimelineService) RecordRenewal(ctx context.Context, renewal *domain.PTContract) error {
+	return s.record(ctx, &domain.TimelineEntry{
+		TenantID: renewal.TenantID,
+		MemberID: renewal.MemberID,
+		Key:      "contract.renewed:" + renewal.ID,
+		Type:     domain.TimelineContractRenewed,
+		Title:    fmt.Sprintf("PT package renewed for %d sessions", renewal.TotalSessions),
+		Data: map[string]interface{}{
+			"contract_id":    renewal.ID,
+			"renewal_of":     renewal.RenewalOf,
+			"package_id":     renewal.PackageID,
+			"coach_id":       renewal.CoachID,
+			"total_sessions": renewal.TotalSessions,
+		},
+		OccurredAt: renewal.CreatedAt,
+	})
+}
+
+// record saves the entry, dated now if its event carried no time
+func (s *TimelineService) record(ctx context.Context, entry *domain.TimelineEntry) error {
+	entry.CreatedAt = s.clock.Now()
+	if entry.OccurredAt.IsZero() {
+		entry.OccurredAt = entry.CreatedAt
+	}
+	return s.timelineRepo.Record(ctx, entry)
+}
+
+// previousScan returns the member's scan taken before scan, or nil for their first
+func (s *TimelineService) previousScan(ctx context.Context, scan *domain.InBodyRecord) (*domain.InBodyRecord, error) {
+	scans, err := s.scanRepo.FindAllByUserID(ctx, scan.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scans: %w", err)
+	}
+	var previous *domain.InBodyRecord
+	for _, other := range scans {
+		if other.ID != scan.ID && other.TestDateTime.Before(scan.TestDateTime) &&
+			(previous == nil || other.TestDateTime.After(previous.TestDateTime)) {
+			previous = other
+		}
+	}
+	return previous, nil
+}
+
+// memberTenant returns the tenant the member belongs to, "" if they no longer exist
+func (s *TimelineService) memberTenant(ctx context.Context, memberID string) (string, error) {
+	user, err := s.userRepo.GetByID(ctx, memberID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return user.TenantID, nil
+}
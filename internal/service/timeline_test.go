@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type timelineMocks struct {
+	timeline  *mocks.TimelineRepository
+	scans     *mocks.InBodyRepository
+	contracts *mocks.PTContractRepository
+	users     *mocks.UserRepository
+	exercises *mocks.ExerciseRepository
+}
+
+func newTestTimelineService(t *testing.T) (*TimelineService, timelineMocks) {
+	m := timelineMocks{
+		timeline:  mocks.NewTimelineRepository(t),
+		scans:     mocks.NewInBodyRepository(t),
+		contracts: mocks.NewPTContractRepository(t),
+		users:     mocks.NewUserRepository(t),
+		exercises: mocks.NewExerciseRepository(t),
+	}
+	m.users.On("GetByID", anyCtx, "member-1").Return(&domain.User{ID: "member-1", TenantID: "gym"}, nil).Maybe()
+	return NewTimelineService(m.timeline, m.scans, m.contracts, m.users, m.exercises, clock.NewFake(testNow)), m
+}
+
+// expectEntry expects one entry with the key to be recorded and returns it once it was
+func (m timelineMocks) expectEntry(key string) *domain.TimelineEntry {
+	recorded := &domain.TimelineEntry{}
+	m.timeline.On("Record", anyCtx, mock.MatchedBy(func(e *domain.TimelineEntry) bool { return e.Key == key })).
+		Run(func(args mock.Arguments) { *recorded = *args.Get(1).(*domain.TimelineEntry) }).
+		Return(nil).Once()
+	return recorded
+}
+
+func TestTimelineService_HandleWorkoutEvent(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestTimelineService(t)
+	completedAt := testNow.Add(-time.Hour)
+	entry := m.expectEntry("session.completed:sched-1")
+
+	// Only completions make the timeline
+	require.NoError(t, svc.HandleWorkoutEvent(ctx, &domain.WorkoutEvent{Type: domain.WorkoutEventSetLogged, ScheduleID: "sched-1"}))
+	require.NoError(t, svc.HandleWorkoutEvent(ctx, &domain.WorkoutEvent{Type: domain.WorkoutEventSessionCompleted,
+		TenantID: "gym", ScheduleID: "sched-1", MemberID: "member-1", ActorID: "coach-1", OccurredAt: completedAt}))
+
+	assert.Equal(t, domain.TimelineSessionCompleted, entry.Type)
+	assert.Equal(t, "member-1", entry.MemberID)
+	assert.Equal(t, "gym", entry.TenantID)
+	assert.Equal(t, completedAt, entry.OccurredAt)
+	assert.Equal(t, testNow, entry.CreatedAt)
+	assert.Equal(t, "coach-1", entry.Data["coach_id"])
+}
+
+func TestTimelineService_HandleNewPB(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestTimelineService(t)
+	m.exercises.On("GetByID", ctx, "squat").Return(&domain.Exercise{ID: "squat", Name: "Back Squat"}, nil)
+	entry := m.expectEntry("pb:member-1:squat:sched-1")
+
+	require.NoError(t, svc.HandleNewPB(ctx, &domain.PersonalBest{MemberID: "member-1", ExerciseID: "squat",
+		Weight: 92.5, Reps: 5, ScheduleID: "sched-1", AchievedAt: testNow}))
+
+	assert.Equal(t, domain.TimelinePersonalBest, entry.Type)
+	assert.Equal(t, "gym", entry.TenantID)
+	assert.Equal(t, "New Back Squat personal best: 92.5 kg × 5", entry.Title)
+}
+
+func TestTimelineService_HandleScanDigitized(t *testing.T) {
+	ctx := context.Background()
+	first := &domain.InBodyRecord{ID: "scan-1", UserID: "member-1", TestDateTime: testNow.AddDate(0, -2, 0), Weight: 82, TargetWeight: 75}
+	middle := &domain.InBodyRecord{ID: "scan-2", UserID: "member-1", TestDateTime: testNow.AddDate(0, -1, 0), Weight: 78, TargetWeight: 75}
+	latest := &domain.InBodyRecord{ID: "scan-3", UserID: "member-1", TestDateTime: testNow, Weight: 74.8, PBF: 18.5, TargetWeight: 74}
+
+	t.Run("a scan at the target weight of the one before is a goal reached", func(t *testing.T) {
+		svc, m := newTestTimelineService(t)
+		m.scans.On("FindByID", ctx, "scan-3").Return(latest, nil)
+		m.scans.On("FindAllByUserID", ctx, "member-1").Return([]*domain.InBodyRecord{latest, middle, first}, nil)
+		scan := m.expectEntry("scan:scan-3")
+		goal := m.expectEntry("goal:scan-3")
+
+		require.NoError(t, svc.HandleScanDigitized(ctx, &domain.OutboxMessage{Key: "scan-3"}))
+
+		assert.Equal(t, "InBody scan: 74.8 kg, 18.5% body fat", scan.Title)
+		assert.Equal(t, testNow, scan.OccurredAt)
+		assert.Equal(t, domain.TimelineGoalReached, goal.Type)
+		assert.Equal(t, "Reached the target weight of 75 kg", goal.Title)
+	})
+
+	t.Run("a scan short of the target is only a scan", func(t *testing.T) {
+		svc, m := newTestTimelineService(t)
+		m.scans.On("FindByID", ctx, "scan-2").Return(middle, nil)
+		m.scans.On("FindAllByUserID", ctx, "member-1").Return([]*domain.InBodyRecord{latest, middle, first}, nil)
+		m.expectEntry("scan:scan-2")
+
+		require.NoError(t, svc.HandleScanDigitized(ctx, &domain.OutboxMessage{Key: "scan-2"}))
+	})
+
+	t.Run("a scan deleted since is skipped", func(t *testing.T) {
+		svc, m := newTestTimelineService(t)
+		m.scans.On("FindByID", ctx, "scan-9").Return(nil, domain.ErrNotFound)
+
+		require.NoError(t, svc.HandleScanDigitized(ctx, &domain.OutboxMessage{Key: "scan-9"}))
+	})
+}
+
+func TestTimelineService_Contracts(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestTimelineService(t)
+	boughtAt := testNow.AddDate(0, 0, -3)
+	m.contracts.On("GetByID", ctx, "k1").Return(&domain.PTContract{ID: "k1", TenantID: "gym", MemberID: "member-1",
+		TotalSessions: 10, CreatedAt: boughtAt}, nil)
+	started := m.expectEntry("contract.created:k1")
+	renewed := m.expectEntry("contract.renewed:k2")
+
+	require.NoError(t, svc.HandleContractCreated(ctx, &domain.OutboxMessage{Key: "k1"}))
+	require.NoError(t, svc.RecordRenewal(ctx, &domain.PTContract{ID: "k2", TenantID: "gym", MemberID: "member-1",
+		TotalSessions: 10, RenewalOf: "k1"}))
+
+	assert.Equal(t, "Started a 10-session PT package", started.Title)
+	assert.Equal(t, boughtAt, started.OccurredAt)
+	assert.Equal(t, domain.TimelineContractRenewed, renewed.Type)
+	assert.Equal(t, testNow, renewed.OccurredAt, "dated when recorded without a creation time")
+	assert.Equal(t, "k1", renewed.Data["renewal_of"])
+}
@@ -5,10 +5,11 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
-	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/mansoorceksport/metamorph/internal/clock"
 	"github.com/mansoorceksport/metamorph/internal/config"
 	"github.com/mansoorceksport/metamorph/internal/domain"
 )
@@ -18,6 +19,8 @@ type TokenService struct {
 	jwtConfig        config.JWTConfig
 	refreshTokenRepo domain.RefreshTokenRepository
 	userRepo         domain.UserRepository
+	tenantRepo       domain.TenantRepository // Optional: see RefuseDeactivatedTenants
+	clock            domain.Clock
 }
 
 // NewTokenService creates a new token service
@@ -25,14 +28,21 @@ func NewTokenService(
 	jwtConfig config.JWTConfig,
 	refreshTokenRepo domain.RefreshTokenRepository,
 	userRepo domain.UserRepository,
+	clk domain.Clock,
 ) *TokenService {
 	return &TokenService{
 		jwtConfig:        jwtConfig,
 		refreshTokenRepo: refreshTokenRepo,
 		userRepo:         userRepo,
+		clock:            clock.OrReal(clk),
 	}
 }
 
+// RefuseDeactivatedTenants stops issuing tokens scoped to tenants that are being off-boarded
+func (s *TokenService) RefuseDeactivatedTenants(tenantRepo domain.TenantRepository) {
+	s.tenantRepo = tenantRepo
+}
+
 // TokenPair contains both access and refresh tokens
 type TokenPair struct {
 	AccessToken  string `json:"access_token"`
@@ -40,16 +50,35 @@ type TokenPair struct {
 	ExpiresIn    int64  `json:"expires_in"` // Seconds until access token expires
 }
 
-// GenerateTokenPair creates both access and refresh tokens for a user
+// GenerateTokenPair creates both access and refresh tokens for a user, scoped to their primary tenant
 func (s *TokenService) GenerateTokenPair(ctx context.Context, user *domain.User, userAgent, ipAddress string) (*TokenPair, error) {
+	return s.GenerateScopedTokenPair(ctx, user, "", userAgent, ipAddress)
+}
+
+// GenerateScopedTokenPair creates a token pair scoped to one of the user's tenants (empty for
+// the primary one). Refreshing the pair keeps the scope. Returns ErrNotTenantMember if the
+// user doesn't belong to tenantID.
+func (s *TokenService) GenerateScopedTokenPair(ctx context.Context, user *domain.User, tenantID, userAgent, ipAddress string) (*TokenPair, error) {
+	scoped, err := user.ScopedTo(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTenantActive(ctx, scoped.TenantID); err != nil {
+		return nil, err
+	}
+	// Sessions on the primary tenant follow it if it changes (e.g. after joining another gym)
+	if tenantID == user.TenantID {
+		tenantID = ""
+	}
+
 	// Generate access token (short-lived JWT)
-	accessToken, err := s.generateAccessToken(user)
+	accessToken, err := s.generateAccessToken(scoped, user.TenantIDs())
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
 	// Generate refresh token (random string, stored in DB)
-	refreshToken, err := s.generateAndStoreRefreshToken(ctx, user.ID, userAgent, ipAddress)
+	refreshToken, err := s.generateAndStoreRefreshToken(ctx, user.ID, tenantID, userAgent, ipAddress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
@@ -61,6 +90,25 @@ func (s *TokenService) GenerateTokenPair(ctx context.Context, user *domain.User,
 	}, nil
 }
 
+// checkTenantActive returns ErrTenantDeactivated for a deactivated or deleted tenant. Users
+// without a tenant are let through.
+func (s *TokenService) checkTenantActive(ctx context.Context, tenantID string) error {
+	if s.tenantRepo == nil || tenantID == "" {
+		return nil
+	}
+	tenant, err := s.tenantRepo.GetByID(ctx, tenantID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.ErrTenantDeactivated
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load tenant: %w", err)
+	}
+	if tenant.DeactivatedAt != nil {
+		return domain.ErrTenantDeactivated
+	}
+	return nil
+}
+
 // RefreshAccessToken validates refresh token and returns new token pair
 func (s *TokenService) RefreshAccessToken(ctx context.Context, refreshToken, userAgent, ipAddress string) (*TokenPair, error) {
 	// Hash the provided refresh token
@@ -92,7 +140,24 @@ func (s *TokenService) RefreshAccessToken(ctx context.Context, refreshToken, use
 	}
 
 	// Generate new token pair
-	return s.GenerateTokenPair(ctx, user, userAgent, ipAddress)
+	return s.GenerateScopedTokenPair(ctx, user, storedToken.TenantID, userAgent, ipAddress)
+}
+
+// SwitchTenant issues a token pair scoped to another tenant the user belongs to
+func (s *TokenService) SwitchTenant(ctx context.Context, userID, tenantID, userAgent, ipAddress string) (*TokenPair, *domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	scoped, err := user.ScopedTo(tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	pair, err := s.GenerateScopedTokenPair(ctx, user, tenantID, userAgent, ipAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, scoped, nil
 }
 
 // RevokeRefreshToken invalidates a specific refresh token (logout)
@@ -107,7 +172,8 @@ func (s *TokenService) RevokeAllUserTokens(ctx context.Context, userID string) e
 }
 
 // generateAccessToken creates a short-lived JWT access token
-func (s *TokenService) generateAccessToken(user *domain.User) (string, error) {
+func (s *TokenService) generateAccessToken(user *domain.User, tenantIDs []string) (string, error) {
+	now := s.clock.Now()
 	claims := domain.MetamorphClaims{
 		UserID:       user.ID,
 		Name:         user.Name,  // For Sentry user tracking
@@ -116,10 +182,11 @@ func (s *TokenService) generateAccessToken(user *domain.User) (string, error) {
 		TenantID:     user.TenantID,
 		HomeBranchID: user.HomeBranchID,
 		BranchAccess: user.BranchAccess,
+		TenantIDs:    tenantIDs,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.jwtConfig.AccessTokenExpiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.jwtConfig.AccessTokenExpiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
@@ -128,7 +195,7 @@ func (s *TokenService) generateAccessToken(user *domain.User) (string, error) {
 }
 
 // generateAndStoreRefreshToken creates a random refresh token and stores its hash
-func (s *TokenService) generateAndStoreRefreshToken(ctx context.Context, userID, userAgent, ipAddress string) (string, error) {
+func (s *TokenService) generateAndStoreRefreshToken(ctx context.Context, userID, tenantID, userAgent, ipAddress string) (string, error) {
 	// Generate random 32-byte token
 	tokenBytes := make([]byte, 32)
 	if _, err := rand.Read(tokenBytes); err != nil {
@@ -140,8 +207,9 @@ func (s *TokenService) generateAndStoreRefreshToken(ctx context.Context, userID,
 	tokenHash := hashToken(rawToken)
 	refreshToken := &domain.RefreshToken{
 		UserID:    userID,
+		TenantID:  tenantID,
 		TokenHash: tokenHash,
-		ExpiresAt: time.Now().Add(s.jwtConfig.RefreshTokenExpiry),
+		ExpiresAt: s.clock.Now().Add(s.jwtConfig.RefreshTokenExpiry),
 		UserAgent: userAgent,
 		IPAddress: ipAddress,
 	}
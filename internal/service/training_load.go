@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+const (
+	maxTrainingLoadWeeks = 26
+	acwrSpike            = 1.5 // Load jumped well above what the member is used to
+	acwrDetraining       = 0.8
+)
+
+// TrainingLoadService records heart rate and perceived effort on completed sessions and
+// turns them into training-load metrics for members and their coaches
+type TrainingLoadService struct {
+	schedRepo domain.ScheduleRepository
+	userRepo  domain.UserRepository
+	clock     domain.Clock
+}
+
+func NewTrainingLoadService(schedRepo domain.ScheduleRepository, userRepo domain.UserRepository, clk domain.Clock) *TrainingLoadService {
+	return &TrainingLoadService{
+		schedRepo: schedRepo,
+		userRepo:  userRepo,
+		clock:     clock.OrReal(clk),
+	}
+}
+
+// RecordEffort attaches effort to a completed session of userID, who may be its coach or
+// its member. Values already recorded stay unless effort measures them again.
+func (s *TrainingLoadService) RecordEffort(ctx context.Context, userID, scheduleID string, effort domain.SessionEffort) (*domain.Schedule, error) {
+	if err := effort.Validate(); err != nil {
+		return nil, err
+	}
+	schedule, err := s.schedRepo.GetByID(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.DeletedAt != nil {
+		return nil, domain.ErrScheduleNotFound
+	}
+	if schedule.CoachID != userID && schedule.MemberID != userID {
+		return nil, domain.ErrForbidden
+	}
+	if !isCompleted(schedule) {
+		return nil, domain.ErrSessionNotCompleted
+	}
+
+	effort.RecordedAt = s.clock.Now().UTC()
+	merged := schedule.Effort.Merge(effort)
+	schedule.Effort = &merged
+	if err := s.schedRepo.Update(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// MemberLoad returns the member's load for the given number of weeks up to and including
+// the current one
+func (s *TrainingLoadService) MemberLoad(ctx context.Context, memberID string, weeks int) (*domain.TrainingLoad, error) {
+	if weeks < 4 || weeks > maxTrainingLoadWeeks {
+		weeks = 4 // Enough for the ACWR
+	}
+	now := s.clock.Now()
+	from := progressWeekStart(now).AddDate(0, 0, -7*(weeks-1))
+	schedules, err := s.schedRepo.GetByMember(ctx, memberID, from, now)
+	if err != nil {
+		return nil, err
+	}
+	completed := completedSessions(schedules)
+
+	load := &domain.TrainingLoad{MemberID: memberID, Weeks: make([]domain.WeeklyLoad, 0, weeks)}
+	for week := from; week.Before(now); week = week.AddDate(0, 0, 7) {
+		load.Weeks = append(load.Weeks, weeklyLoad(completed, week))
+	}
+	load.AcuteLoad, load.ChronicLoad, load.ACWR, load.Flag = workloadRatio(completed, now)
+	return load, nil
+}
+
+// WeeklyReview summarises the effort of the coach's members in the week starting at
+// weekStart, with each member's ACWR as of the end of that week
+func (s *TrainingLoadService) WeeklyReview(ctx context.Context, coachID string, weekStart time.Time) (*domain.CoachWeeklyReview, error) {
+	weekStart = progressWeekStart(weekStart)
+	weekEnd := weekStart.AddDate(0, 0, 7)
+	schedules, err := s.schedRepo.GetByCoach(ctx, coachID, weekEnd.AddDate(0, 0, -28), weekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	byMember := make(map[string][]*domain.Schedule)
+	for _, sched := range completedSessions(schedules) {
+		if sched.StartTime.Before(weekEnd) {
+			byMember[sched.MemberID] = append(byMember[sched.MemberID], sched)
+		}
+	}
+
+	review := &domain.CoachWeeklyReview{WeekStart: weekStart, Members: []domain.WeeklyReviewMember{}}
+	for memberID, sessions := range byMember {
+		week := weeklyLoad(sessions, weekStart)
+		if week.Sessions == 0 {
+			continue // Only trained with this coach earlier in the month
+		}
+		review.Sessions += week.Sessions
+		review.MissingEffort += week.Sessions - week.WithEffort
+
+		line := domain.WeeklyReviewMember{MemberID: memberID, Week: week}
+		_, _, line.ACWR, line.Flag = workloadRatio(sessions, weekEnd)
+		if member, err := s.userRepo.GetByID(ctx, memberID); err == nil {
+			line.MemberName = member.Name
+		} else if !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+		review.Members = append(review.Members, line)
+	}
+
+	// Members who need a look first, then the hardest working
+	sort.Slice(review.Members, func(i, j int) bool {
+		a, b := review.Members[i], review.Members[j]
+		if (a.Flag != "") != (b.Flag != "") {
+			return a.Flag != ""
+		}
+		if a.Week.Load != b.Week.Load {
+			return a.Week.Load > b.Week.Load
+		}
+		return a.MemberName < b.MemberName
+	})
+	return review, nil
+}
+
+func isCompleted(s *domain.Schedule) bool {
+	return strings.EqualFold(s.Status, domain.ScheduleStatusCompleted)
+}
+
+func completedSessions(schedules []*domain.Schedule) []*domain.Schedule {
+	completed := make([]*domain.Schedule, 0, len(schedules))
+	for _, sched := range schedules {
+		if sched.DeletedAt == nil && isCompleted(sched) {
+			completed = append(completed, sched)
+		}
+	}
+	return completed
+}
+
+// weeklyLoad sums the sessions starting in the week from weekStart
+func weeklyLoad(sessions []*domain.Schedule, weekStart time.Time) domain.WeeklyLoad {
+	week := domain.WeeklyLoad{WeekStart: weekStart}
+	weekEnd := weekStart.AddDate(0, 0, 7)
+	var rpeSum, rpeCount, hrSum, hrCount int
+	for _, sched := range sessions {
+		if sched.StartTime.Before(weekStart) || !sched.StartTime.Before(weekEnd) {
+			continue
+		}
+		week.Sessions++
+		week.Load += sched.Load()
+		e := sched.Effort
+		if e == nil {
+			continue
+		}
+		week.WithEffort++
+		if e.RPE != nil {
+			rpeSum += *e.RPE
+			rpeCount++
+		}
+		if e.AvgHR != nil {
+			hrSum += *e.AvgHR
+			hrCount++
+		}
+		if e.MaxHR != nil && *e.MaxHR > week.MaxHR {
+			week.MaxHR = *e.MaxHR
+		}
+	}
+	if rpeCount > 0 {
+		week.AvgRPE = math.Round(float64(rpeSum)/float64(rpeCount)*10) / 10
+	}
+	if hrCount > 0 {
+		week.AvgHR = math.Round(float64(hrSum) / float64(hrCount))
+	}
+	return week
+}
+
+// workloadRatio compares the load of the 7 days before at with the weekly average of the
+// 28 days before it. Without any chronic load there is no ratio.
+func workloadRatio(sessions []*domain.Schedule, at time.Time) (acute, chronic, acwr float64, flag string) {
+	acuteFrom, chronicFrom := at.AddDate(0, 0, -7), at.AddDate(0, 0, -28)
+	var chronicTotal float64
+	for _, sched := range sessions {
+		if !sched.StartTime.Before(at) || sched.StartTime.Before(chronicFrom) {
+			continue
+		}
+		chronicTotal += sched.Load()
+		if !sched.StartTime.Before(acuteFrom) {
+			acute += sched.Load()
+		}
+	}
+	chronic = chronicTotal / 4
+	if chronic == 0 {
+		return acute, chronic, 0, ""
+	}
+	acwr = math.Round(acute/chronic*100) / 100
+	switch {
+	case acwr > acwrSpike:
+		flag = domain.LoadFlagSpike
+	case acwr < acwrDetraining:
+		flag = domain.LoadFlagDetraining
+	}
+	return acute, chronic, acwr, flag
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTrainingLoadService(t *testing.T) (*TrainingLoadService, *mocks.ScheduleRepository, *mocks.UserRepository) {
+	schedRepo := mocks.NewScheduleRepository(t)
+	userRepo := mocks.NewUserRepository(t)
+	return NewTrainingLoadService(schedRepo, userRepo, clock.NewFake(testNow)), schedRepo, userRepo
+}
+
+// hourSession is a completed one-hour session at start with the given RPE (0 for none)
+func hourSession(memberID string, start time.Time, rpe int) *domain.Schedule {
+	sched := &domain.Schedule{MemberID: memberID, CoachID: "coach-1", Status: domain.ScheduleStatusCompleted, StartTime: start, EndTime: start.Add(time.Hour)}
+	if rpe > 0 {
+		sched.Effort = &domain.SessionEffort{RPE: intPtr(rpe), Source: domain.EffortSourceManual}
+	}
+	return sched
+}
+
+func TestTrainingLoadService_RecordEffort(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("wearable heart rate keeps the coach's RPE", func(t *testing.T) {
+		svc, schedRepo, _ := newTestTrainingLoadService(t)
+		sched := hourSession("member-1", testNow.Add(-3*time.Hour), 7)
+		sched.ID = "sched-1"
+		schedRepo.On("GetByID", ctx, "sched-1").Return(sched, nil)
+		schedRepo.On("Update", ctx, mock.AnythingOfType("*domain.Schedule")).Return(nil)
+
+		got, err := svc.RecordEffort(ctx, "member-1", "sched-1", domain.SessionEffort{AvgHR: intPtr(138), MaxHR: intPtr(171), Source: domain.EffortSourceWearable, Device: "Garmin"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 7, *got.Effort.RPE)
+		assert.Equal(t, 138, *got.Effort.AvgHR)
+		assert.Equal(t, domain.EffortSourceWearable, got.Effort.Source)
+		assert.Equal(t, testNow, got.Effort.RecordedAt)
+	})
+
+	t.Run("upcoming session", func(t *testing.T) {
+		svc, schedRepo, _ := newTestTrainingLoadService(t)
+		sched := hourSession("member-1", testNow.Add(time.Hour), 0)
+		sched.Status = domain.ScheduleStatusScheduled
+		schedRepo.On("GetByID", ctx, "sched-1").Return(sched, nil)
+
+		_, err := svc.RecordEffort(ctx, "coach-1", "sched-1", domain.SessionEffort{RPE: intPtr(6), Source: domain.EffortSourceManual})
+
+		assert.ErrorIs(t, err, domain.ErrSessionNotCompleted)
+	})
+
+	t.Run("someone else's session", func(t *testing.T) {
+		svc, schedRepo, _ := newTestTrainingLoadService(t)
+		schedRepo.On("GetByID", ctx, "sched-1").Return(hourSession("member-1", testNow, 0), nil)
+
+		_, err := svc.RecordEffort(ctx, "member-2", "sched-1", domain.SessionEffort{RPE: intPtr(6), Source: domain.EffortSourceManual})
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("average above max", func(t *testing.T) {
+		svc, _, _ := newTestTrainingLoadService(t)
+
+		_, err := svc.RecordEffort(ctx, "coach-1", "sched-1", domain.SessionEffort{AvgHR: intPtr(180), MaxHR: intPtr(170), Source: domain.EffortSourceManual})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidSessionEffort)
+	})
+}
+
+func TestTrainingLoadService_MemberLoad(t *testing.T) {
+	ctx := context.Background()
+	svc, schedRepo, _ := newTestTrainingLoadService(t)
+
+	// Three weeks at one RPE 5 hour a week (300 each), then a hard week of two RPE 9 hours
+	monday := progressWeekStart(testNow)
+	from := monday.AddDate(0, 0, -21)
+	cancelled := hourSession("member-1", monday.AddDate(0, 0, -5), 8)
+	cancelled.Status = domain.ScheduleStatusCancelled
+	schedRepo.On("GetByMember", ctx, "member-1", from, testNow).Return([]*domain.Schedule{
+		hourSession("member-1", from.Add(8*time.Hour), 5),
+		hourSession("member-1", from.AddDate(0, 0, 7).Add(8*time.Hour), 5),
+		hourSession("member-1", from.AddDate(0, 0, 14).Add(8*time.Hour), 5),
+		cancelled,
+		hourSession("member-1", testNow.Add(-48*time.Hour), 9),
+		hourSession("member-1", testNow.Add(-2*time.Hour), 9),
+	}, nil)
+
+	load, err := svc.MemberLoad(ctx, "member-1", 4)
+
+	require.NoError(t, err)
+	require.Len(t, load.Weeks, 4)
+	assert.Equal(t, 300.0, load.Weeks[0].Load)
+	assert.Equal(t, 1, load.Weeks[3].Sessions, "Saturday's session falls in the week before")
+	assert.Equal(t, 1080.0, load.AcuteLoad)
+	assert.Equal(t, 495.0, load.ChronicLoad, "1980 over four weeks")
+	assert.Equal(t, 2.18, load.ACWR)
+	assert.Equal(t, domain.LoadFlagSpike, load.Flag)
+}
+
+func TestTrainingLoadService_WeeklyReview(t *testing.T) {
+	ctx := context.Background()
+	svc, schedRepo, userRepo := newTestTrainingLoadService(t)
+
+	weekStart := progressWeekStart(testNow).AddDate(0, 0, -7)
+	weekEnd := weekStart.AddDate(0, 0, 7)
+	steady := hourSession("member-a", weekStart.Add(9*time.Hour), 6)
+	steady.Effort.AvgHR = intPtr(130)
+	schedRepo.On("GetByCoach", ctx, "coach-1", weekEnd.AddDate(0, 0, -28), weekEnd).Return([]*domain.Schedule{
+		hourSession("member-a", weekStart.AddDate(0, 0, -7).Add(9*time.Hour), 6),
+		hourSession("member-a", weekStart.AddDate(0, 0, -14).Add(9*time.Hour), 6),
+		hourSession("member-a", weekStart.AddDate(0, 0, -21).Add(9*time.Hour), 6),
+		steady,
+		hourSession("member-b", weekStart.AddDate(0, 0, 2), 8),
+		hourSession("member-b", weekStart.AddDate(0, 0, 4), 0),
+		hourSession("member-c", weekStart.AddDate(0, 0, -3), 7), // Nothing this week
+	}, nil)
+	userRepo.On("GetByID", ctx, "member-a").Return(&domain.User{ID: "member-a", Name: "Ayu"}, nil)
+	userRepo.On("GetByID", ctx, "member-b").Return(&domain.User{ID: "member-b", Name: "Budi"}, nil)
+
+	review, err := svc.WeeklyReview(ctx, "coach-1", weekStart.AddDate(0, 0, 3))
+
+	require.NoError(t, err)
+	assert.Equal(t, weekStart, review.WeekStart)
+	assert.Equal(t, 3, review.Sessions)
+	assert.Equal(t, 1, review.MissingEffort)
+	require.Len(t, review.Members, 2)
+
+	budi, ayu := review.Members[0], review.Members[1]
+	assert.Equal(t, "Budi", budi.MemberName, "a first week spikes the ratio and sorts first")
+	assert.Equal(t, domain.LoadFlagSpike, budi.Flag)
+	assert.Equal(t, "Ayu", ayu.MemberName)
+	assert.Equal(t, 1.0, ayu.ACWR)
+	assert.Empty(t, ayu.Flag)
+	assert.Equal(t, 130.0, ayu.Week.AvgHR)
+}
@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/mansoorceksport/metamorph/internal/clock"
 	"github.com/mansoorceksport/metamorph/internal/domain"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
@@ -19,16 +19,19 @@ const (
 type TrendService struct {
 	repository domain.InBodyRepository
 	cache      domain.CacheRepository
+	clock      domain.Clock
 }
 
 // NewTrendService creates a new trend service
 func NewTrendService(
 	repository domain.InBodyRepository,
 	cache domain.CacheRepository,
+	clk domain.Clock,
 ) *TrendService {
 	return &TrendService{
 		repository: repository,
 		cache:      cache,
+		clock:      clock.OrReal(clk),
 	}
 }
 
@@ -54,7 +57,7 @@ func (s *TrendService) GenerateTrendRecap(ctx context.Context, userID string) (*
 
 	// If MongoDB summary exists and is fresh (< 7 days old), use it
 	if dbSummary != nil {
-		age := time.Since(dbSummary.LastGeneratedAt)
+		age := s.clock.Now().Sub(dbSummary.LastGeneratedAt)
 		if age < trendRecapMaxAge {
 			fmt.Printf("MongoDB cache hit: trend summary for user %s (age: %v)\n", userID, age)
 
@@ -77,17 +80,12 @@ func (s *TrendService) GenerateTrendRecap(ctx context.Context, userID string) (*
 		return nil, fmt.Errorf("failed to fetch scan history: %w", err)
 	}
 
-	userObjectID, err := primitive.ObjectIDFromHex(userID)
-	if err != nil {
-		return nil, fmt.Errorf("invalid user id: %w", err)
-	}
-
 	// If no scans available, return empty summary
 	if len(scans) == 0 {
 		return &domain.TrendSummary{
-			UserID:          userObjectID,
+			UserID:          userID,
 			SummaryText:     "No scans available yet! Upload your first InBody scan to start tracking your progress.",
-			LastGeneratedAt: time.Now(),
+			LastGeneratedAt: s.clock.Now(),
 			IncludedScanIDs: []string{},
 		}, nil
 	}
@@ -105,9 +103,9 @@ func (s *TrendService) GenerateTrendRecap(ctx context.Context, userID string) (*
 			firstScan.PBF,
 		)
 		summary := &domain.TrendSummary{
-			UserID:          userObjectID,
+			UserID:          userID,
 			SummaryText:     summaryText,
-			LastGeneratedAt: time.Now(),
+			LastGeneratedAt: s.clock.Now(),
 			IncludedScanIDs: []string{firstScan.ID},
 		}
 
@@ -169,9 +167,9 @@ func (s *TrendService) GenerateTrendRecap(ctx context.Context, userID string) (*
 
 	// Create and save trend summary
 	summary := &domain.TrendSummary{
-		UserID:          userObjectID,
+		UserID:          userID,
 		SummaryText:     summaryText,
-		LastGeneratedAt: time.Now(),
+		LastGeneratedAt: s.clock.Now(),
 		IncludedScanIDs: scanIDs,
 	}
 
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// warehouseEntities maps the exported collections to their warehouse entity
+var warehouseEntities = map[string]string{
+	"inbody_records": domain.WarehouseEntityScan,
+	"schedules":      domain.WarehouseEntitySession,
+	"invoices":       domain.WarehouseEntityInvoice,
+}
+
+// Fields removed from anonymized rows: free text, payment details and image links
+var warehouseDroppedFields = map[string][]string{
+	domain.WarehouseEntityScan:    {"metadata"},
+	domain.WarehouseEntitySession: {"client_id", "session_goal", "remarks"},
+	domain.WarehouseEntityInvoice: {"va_number", "payment_session_id"},
+}
+
+// People references replaced by pseudonyms in anonymized rows
+var warehousePseudonymFields = []string{"user_id", "member_id", "coach_id"}
+
+// WarehouseCollections lists the collections the exporter consumes
+func WarehouseCollections() []string {
+	collections := make([]string, 0, len(warehouseEntities))
+	for coll := range warehouseEntities {
+		collections = append(collections, coll)
+	}
+	return collections
+}
+
+// WarehouseExporter turns database changes into versioned warehouse rows for the tenants
+// that opted in, anonymizing them when the tenant asked for it
+type WarehouseExporter struct {
+	sink         domain.WarehouseSink
+	tenantRepo   domain.TenantRepository
+	userRepo     domain.UserRepository
+	pseudonymKey []byte
+}
+
+func NewWarehouseExporter(sink domain.WarehouseSink, tenantRepo domain.TenantRepository, userRepo domain.UserRepository, pseudonymKey string) *WarehouseExporter {
+	return &WarehouseExporter{
+		sink:         sink,
+		tenantRepo:   tenantRepo,
+		userRepo:     userRepo,
+		pseudonymKey: []byte(pseudonymKey),
+	}
+}
+
+// HandleChanges exports one change-feed batch. An error fails the whole batch so the feed
+// redelivers it.
+func (e *WarehouseExporter) HandleChanges(ctx context.Context, batch []*domain.ChangeEvent) error {
+	// Opt-in modes and scan/invoice owners are looked up once per batch
+	modes := make(map[string]string)
+	owners := make(map[string]string)
+
+	var rows []*domain.WarehouseRow
+	for _, event := range batch {
+		entity, ok := warehouseEntities[event.Collection]
+		if !ok {
+			continue
+		}
+
+		row := &domain.WarehouseRow{
+			SchemaVersion: domain.WarehouseSchemaVersion,
+			Entity:        entity,
+			Op:            event.Op,
+			EntityID:      event.DocumentID,
+			OccurredAt:    event.OccurredAt,
+		}
+
+		// Deletes carry no document, so the tenant is unknown; the bare ID lets the warehouse
+		// drop rows it already has without exporting anything else
+		if event.Document == nil {
+			if event.Op == domain.ChangeOpDelete {
+				rows = append(rows, row)
+			}
+			continue
+		}
+
+		tenantID, err := e.tenantOf(ctx, event.Document, owners)
+		if err != nil {
+			return err
+		}
+		if tenantID == "" {
+			continue
+		}
+		mode, err := e.exportMode(ctx, tenantID, modes)
+		if err != nil {
+			return err
+		}
+		if mode == domain.WarehouseExportOff {
+			continue
+		}
+
+		row.TenantID = tenantID
+		row.Data = make(map[string]interface{}, len(event.Document))
+		for k, v := range event.Document {
+			if k != "_id" {
+				row.Data[k] = v
+			}
+		}
+		if mode == domain.WarehouseExportAnonymized {
+			e.anonymize(entity, row.Data)
+			row.Anonymized = true
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+	return e.sink.Write(ctx, rows)
+}
+
+// tenantOf returns the document's tenant; scans and invoices only reference their owner
+func (e *WarehouseExporter) tenantOf(ctx context.Context, doc map[string]interface{}, owners map[string]string) (string, error) {
+	if tenantID, _ := doc["tenant_id"].(string); tenantID != "" {
+		return tenantID, nil
+	}
+	userID, _ := doc["user_id"].(string)
+	if userID == "" {
+		return "", nil
+	}
+	if tenantID, ok := owners[userID]; ok {
+		return tenantID, nil
+	}
+
+	user, err := e.userRepo.GetByID(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrInvalidID) {
+		owners[userID] = ""
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up user %s: %w", userID, err)
+	}
+	owners[userID] = user.TenantID
+	return user.TenantID, nil
+}
+
+func (e *WarehouseExporter) exportMode(ctx context.Context, tenantID string, modes map[string]string) (string, error) {
+	if mode, ok := modes[tenantID]; ok {
+		return mode, nil
+	}
+	tenant, err := e.tenantRepo.GetByID(ctx, tenantID)
+	if errors.Is(err, domain.ErrNotFound) {
+		modes[tenantID] = domain.WarehouseExportOff
+		return domain.WarehouseExportOff, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up tenant %s: %w", tenantID, err)
+	}
+	modes[tenantID] = tenant.WarehouseExport
+	return tenant.WarehouseExport, nil
+}
+
+func (e *WarehouseExporter) anonymize(entity string, data map[string]interface{}) {
+	for _, field := range warehouseDroppedFields[entity] {
+		delete(data, field)
+	}
+	for _, field := range warehousePseudonymFields {
+		if id, ok := data[field].(string); ok && id != "" {
+			data[field] = e.pseudonym(id)
+		}
+	}
+}
+
+// pseudonym is stable for a given ID, so rows about the same person can still be joined
+func (e *WarehouseExporter) pseudonym(id string) string {
+	mac := hmac.New(sha256.New, e.pseudonymKey)
+	mac.Write([]byte(id))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
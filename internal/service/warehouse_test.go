@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarehouseExporter_HandleChanges(t *testing.T) {
+	ctx := context.Background()
+
+	tenantRepo := mocks.NewTenantRepository(t)
+	userRepo := mocks.NewUserRepository(t)
+	sink := mocks.NewWarehouseSink(t)
+	exporter := NewWarehouseExporter(sink, tenantRepo, userRepo, "secret")
+
+	tenantRepo.On("GetByID", ctx, "tenant-full").Return(&domain.Tenant{ID: "tenant-full", WarehouseExport: domain.WarehouseExportFull}, nil).Once()
+	tenantRepo.On("GetByID", ctx, "tenant-anon").Return(&domain.Tenant{ID: "tenant-anon", WarehouseExport: domain.WarehouseExportAnonymized}, nil).Once()
+	tenantRepo.On("GetByID", ctx, "tenant-off").Return(&domain.Tenant{ID: "tenant-off"}, nil).Once()
+	userRepo.On("GetByID", ctx, "member-1").Return(&domain.User{ID: "member-1", TenantID: "tenant-anon"}, nil).Once()
+
+	var rows []*domain.WarehouseRow
+	sink.On("Write", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		rows = args.Get(1).([]*domain.WarehouseRow)
+	}).Return(nil)
+
+	batch := []*domain.ChangeEvent{
+		{Collection: "schedules", Op: domain.ChangeOpUpdate, DocumentID: "s1", OccurredAt: testNow, Document: map[string]interface{}{
+			"_id": "s1", "tenant_id": "tenant-full", "member_id": "member-1", "remarks": "knee pain",
+		}},
+		{Collection: "inbody_records", Op: domain.ChangeOpInsert, DocumentID: "scan-1", Document: map[string]interface{}{
+			"user_id": "member-1", "weight": 80.5, "metadata": map[string]interface{}{"image_url": "https://..."},
+		}},
+		// Second scan of the same member: owner and tenant mode are looked up once
+		{Collection: "inbody_records", Op: domain.ChangeOpUpdate, DocumentID: "scan-2", Document: map[string]interface{}{
+			"user_id": "member-1", "weight": 80,
+		}},
+		{Collection: "invoices", Op: domain.ChangeOpInsert, DocumentID: "inv-1", Document: map[string]interface{}{
+			"tenant_id": "tenant-off", "amount": 100,
+		}},
+		{Collection: "inbody_records", Op: domain.ChangeOpDelete, DocumentID: "scan-3"},
+		{Collection: "users", Op: domain.ChangeOpInsert, DocumentID: "u1", Document: map[string]interface{}{"tenant_id": "tenant-full"}},
+	}
+
+	require.NoError(t, exporter.HandleChanges(ctx, batch))
+	require.Len(t, rows, 4)
+
+	full := rows[0]
+	assert.Equal(t, domain.WarehouseSchemaVersion, full.SchemaVersion)
+	assert.Equal(t, domain.WarehouseEntitySession, full.Entity)
+	assert.Equal(t, "tenant-full", full.TenantID)
+	assert.False(t, full.Anonymized)
+	assert.Equal(t, "knee pain", full.Data["remarks"])
+	assert.NotContains(t, full.Data, "_id")
+
+	anon := rows[1]
+	assert.True(t, anon.Anonymized)
+	assert.Equal(t, "tenant-anon", anon.TenantID)
+	assert.NotContains(t, anon.Data, "metadata")
+	assert.Equal(t, 80.5, anon.Data["weight"])
+	assert.NotEqual(t, "member-1", anon.Data["user_id"])
+	assert.Equal(t, anon.Data["user_id"], rows[2].Data["user_id"], "pseudonyms are stable")
+
+	deleted := rows[3]
+	assert.Equal(t, domain.ChangeOpDelete, deleted.Op)
+	assert.Equal(t, "scan-3", deleted.EntityID)
+	assert.Empty(t, deleted.TenantID)
+	assert.Nil(t, deleted.Data)
+}
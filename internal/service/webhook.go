@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+const (
+	webhookLease       = time.Minute // Longer than a post can take, so a delivery isn't posted twice at once
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 6 * time.Hour
+)
+
+// WebhookService manages tenants' webhooks and delivers domain events to them.
+// Events are recorded as pending deliveries and posted by DeliverDue, which retries
+// failures with backoff until MaxWebhookAttempts.
+type WebhookService struct {
+	webhookRepo  domain.WebhookRepository
+	deliveryRepo domain.WebhookDeliveryRepository
+	contractRepo domain.PTContractRepository
+	userRepo     domain.UserRepository
+	poster       domain.WebhookPoster
+	clock        domain.Clock
+}
+
+func NewWebhookService(
+	webhookRepo domain.WebhookRepository,
+	deliveryRepo domain.WebhookDeliveryRepository,
+	contractRepo domain.PTContractRepository,
+	userRepo domain.UserRepository,
+	poster domain.WebhookPoster,
+	clk domain.Clock,
+) *WebhookService {
+	return &WebhookService{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		contractRepo: contractRepo,
+		userRepo:     userRepo,
+		poster:       poster,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// Create registers an active webhook and returns its signing secret, which is not shown again
+func (s *WebhookService) Create(ctx context.Context, webhook *domain.Webhook) (string, error) {
+	webhook.Events = compactEvents(webhook.Events)
+	if err := webhook.Validate(); err != nil {
+		return "", err
+	}
+	if err := s.poster.CheckURL(ctx, webhook.URL); err != nil {
+		return "", err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return "", err
+	}
+	webhook.Secret = secret
+	webhook.Active = true
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// List returns the tenant's webhooks, oldest first
+func (s *WebhookService) List(ctx context.Context, tenantID string) ([]*domain.Webhook, error) {
+	return s.webhookRepo.ListByTenant(ctx, tenantID)
+}
+
+// Get returns one of the tenant's webhooks
+func (s *WebhookService) Get(ctx context.Context, tenantID, id string) (*domain.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.TenantID != tenantID {
+		return nil, domain.ErrNotFound
+	}
+	return webhook, nil
+}
+
+// Update replaces a webhook's URL, events, description and whether it is active.
+// The secret is kept; see RotateSecret.
+func (s *WebhookService) Update(ctx context.Context, tenantID, id string, changes *domain.Webhook) (*domain.Webhook, error) {
+	webhook, err := s.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	webhook.URL = changes.URL
+	webhook.Events = compactEvents(changes.Events)
+	webhook.Description = changes.Description
+	webhook.Active = changes.Active
+	if err := webhook.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.poster.CheckURL(ctx, webhook.URL); err != nil {
+		return nil, err
+	}
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// RotateSecret gives a webhook a new signing secret and returns it. Pending deliveries are
+// signed with the new secret.
+func (s *WebhookService) RotateSecret(ctx context.Context, tenantID, id string) (string, error) {
+	webhook, err := s.Get(ctx, tenantID, id)
+	if err != nil {
+		return "", err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return "", err
+	}
+	webhook.Secret = secret
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// Delete removes a webhook with its delivery log
+func (s *WebhookService) Delete(ctx context.Context, tenantID, id string) error {
+	if _, err := s.Get(ctx, tenantID, id); err != nil {
+		return err
+	}
+	if err := s.webhookRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	return s.deliveryRepo.DeleteByWebhook(ctx, id)
+}
+
+// Deliveries returns a page of a webhook's delivery log, newest first
+func (s *WebhookService) Deliveries(ctx context.Context, tenantID, id string, q domain.PageQuery) (*domain.Page[*domain.WebhookDelivery], error) {
+	if _, err := s.Get(ctx, tenantID, id); err != nil {
+		return nil, err
+	}
+	return s.deliveryRepo.ListByWebhook(ctx, id, q)
+}
+
+// webhookBody is the JSON posted for an event
+type webhookBody struct {
+	ID         string                 `json:"id"` // Same for every retry, so receivers can drop duplicates
+	Event      string                 `json:"event"`
+	TenantID   string                 `json:"tenant_id"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Data       map[string]interface{} `json:"data"`
+}
+
+// Publish records a pending delivery of the event to each of the tenant's webhooks that
+// subscribe to it. eventID identifies the occurrence: publishing it again, as at-least-once
+// sources do, adds nothing.
+func (s *WebhookService) Publish(ctx context.Context, tenantID, event, eventID string, data map[string]interface{}) error {
+	if tenantID == "" {
+		return nil
+	}
+	webhooks, err := s.webhookRepo.ListSubscribed(ctx, tenantID, event)
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	now := s.clock.Now()
+	body, err := json.Marshal(webhookBody{ID: eventID, Event: event, TenantID: tenantID, OccurredAt: now, Data: data})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook body: %w", err)
+	}
+	for _, webhook := range webhooks {
+		delivery := &domain.WebhookDelivery{
+			TenantID:      tenantID,
+			WebhookID:     webhook.ID,
+			Event:         event,
+			EventID:       eventID,
+			Body:          string(body),
+			CreatedAt:     now,
+			NextAttemptAt: now,
+		}
+		if _, err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeliverDue posts every delivery that is currently due and returns how many succeeded
+func (s *WebhookService) DeliverDue(ctx context.Context) (int, error) {
+	delivered := 0
+	for ctx.Err() == nil {
+		delivery, err := s.deliveryRepo.Claim(ctx, s.clock.Now(), webhookLease)
+		if err != nil {
+			return delivered, err
+		}
+		if delivery == nil {
+			return delivered, nil
+		}
+
+		if err := s.post(ctx, delivery); err != nil {
+			failed := delivery.Attempts >= domain.MaxWebhookAttempts
+			if failed {
+				log.Printf("Warning: giving up on webhook delivery %s (%s) after %d attempts: %v", delivery.ID, delivery.Event, delivery.Attempts, err)
+			}
+			now := s.clock.Now()
+			if markErr := s.deliveryRepo.MarkFailed(ctx, delivery.ID, err.Error(), now, now.Add(webhookBackoff(delivery.Attempts)), failed); markErr != nil {
+				return delivered, markErr
+			}
+			continue
+		}
+		if err := s.deliveryRepo.MarkDelivered(ctx, delivery.ID, s.clock.Now()); err != nil {
+			return delivered, err
+		}
+		delivered++
+	}
+	return delivered, ctx.Err()
+}
+
+func (s *WebhookService) post(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	webhook, err := s.webhookRepo.GetByID(ctx, delivery.WebhookID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return errors.New("webhook was deleted")
+	}
+	if err != nil {
+		return err
+	}
+	if !webhook.Active {
+		return errors.New("webhook is inactive")
+	}
+	return s.poster.Post(ctx, webhook.URL, webhook.Secret, []byte(delivery.Body))
+}
+
+// webhookBackoff doubles the retry delay with each attempt, up to webhookMaxBackoff
+func webhookBackoff(attempts int) time.Duration {
+	d := webhookBaseBackoff
+	for i := 1; i < attempts && d < webhookMaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, webhookMaxBackoff)
+}
+
+// compactEvents drops repeated events
+func compactEvents(events []string) []string {
+	var unique []string
+	for _, event := range events {
+		if !slices.Contains(unique, event) {
+			unique = append(unique, event)
+		}
+	}
+	return unique
+}
+
+// HandleContractCreated is the outbox handler that publishes contract.created
+func (s *WebhookService) HandleContractCreated(ctx context.Context, msg *domain.OutboxMessage) error {
+	contract, err := s.contractRepo.GetByID(ctx, msg.Key)
+	if errors.Is(err, domain.ErrContractNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.Publish(ctx, contract.TenantID, domain.WebhookEventContractCreated, "contract.created:"+contract.ID, map[string]interface{}{
+		"contract_id":    contract.ID,
+		"package_id":     contract.PackageID,
+		"branch_id":      contract.BranchID,
+		"member_id":      contract.MemberID,
+		"coach_id":       contract.CoachID,
+		"total_sessions": contract.TotalSessions,
+		"price":          contract.Price,
+	})
+}
+
+// HandleScanDigitized is the outbox handler that publishes scan.digitized to the member's tenant
+func (s *WebhookService) HandleScanDigitized(ctx context.Context, msg *domain.OutboxMessage) error {
+	userID, _ := msg.Payload["user_id"].(string)
+	tenantID, err := s.memberTenant(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.Publish(ctx, tenantID, domain.WebhookEventScanDigitized, "scan.digitized:"+msg.Key, map[string]interface{}{
+		"scan_id":   msg.Key,
+		"member_id": userID,
+		"test_date": msg.Payload["test_date"],
+	})
+}
+
+// HandleWorkoutEvent publishes schedule.completed when a session is completed
+func (s *WebhookService) HandleWorkoutEvent(ctx context.Context, event *domain.WorkoutEvent) error {
+	if event.Type != domain.WorkoutEventSessionCompleted {
+		return nil
+	}
+	return s.Publish(ctx, event.TenantID, domain.WebhookEventScheduleCompleted, "schedule.completed:"+event.ScheduleID, map[string]interface{}{
+		"schedule_id":  event.ScheduleID,
+		"member_id":    event.MemberID,
+		"coach_id":     event.ActorID,
+		"completed_at": event.OccurredAt,
+	})
+}
+
+// HandleNewPB publishes pb.achieved to the member's tenant
+func (s *WebhookService) HandleNewPB(ctx context.Context, pb *domain.PersonalBest) error {
+	tenantID, err := s.memberTenant(ctx, pb.MemberID)
+	if err != nil {
+		return err
+	}
+	eventID := fmt.Sprintf("pb.achieved:%s:%s:%g", pb.MemberID, pb.ExerciseID, pb.Weight)
+	return s.Publish(ctx, tenantID, domain.WebhookEventPBAchieved, eventID, map[string]interface{}{
+		"member_id":   pb.MemberID,
+		"exercise_id": pb.ExerciseID,
+		"weight":      pb.Weight,
+		"reps":        pb.Reps,
+		"schedule_id": pb.ScheduleID,
+	})
+}
+
+// memberTenant returns the tenant a member belongs to, or "" for a member who is gone
+func (s *WebhookService) memberTenant(ctx context.Context, userID string) (string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return user.TenantID, nil
+}
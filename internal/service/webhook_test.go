@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type webhookMocks struct {
+	webhooks   *mocks.WebhookRepository
+	deliveries *mocks.WebhookDeliveryRepository
+	contracts  *mocks.PTContractRepository
+	users      *mocks.UserRepository
+	poster     *mocks.WebhookPoster
+}
+
+func newTestWebhookService(t *testing.T) (*WebhookService, webhookMocks) {
+	m := webhookMocks{
+		webhooks:   mocks.NewWebhookRepository(t),
+		deliveries: mocks.NewWebhookDeliveryRepository(t),
+		contracts:  mocks.NewPTContractRepository(t),
+		users:      mocks.NewUserRepository(t),
+		poster:     mocks.NewWebhookPoster(t),
+	}
+	return NewWebhookService(m.webhooks, m.deliveries, m.contracts, m.users, m.poster, clock.NewFake(testNow)), m
+}
+
+func TestWebhookService_Create(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("activates the webhook and returns its secret", func(t *testing.T) {
+		svc, m := newTestWebhookService(t)
+		m.poster.On("CheckURL", ctx, "https://hooks.example.com/metamorph").Return(nil)
+		m.webhooks.On("Create", ctx, mock.AnythingOfType("*domain.Webhook")).Return(nil)
+		webhook := &domain.Webhook{TenantID: "gym", URL: "https://hooks.example.com/metamorph",
+			Events: []string{domain.WebhookEventPBAchieved, domain.WebhookEventPBAchieved}}
+
+		secret, err := svc.Create(ctx, webhook)
+
+		require.NoError(t, err)
+		assert.Len(t, secret, 48)
+		assert.Equal(t, secret, webhook.Secret)
+		assert.True(t, webhook.Active)
+		assert.Equal(t, []string{domain.WebhookEventPBAchieved}, webhook.Events)
+	})
+
+	t.Run("rejects plain HTTP and unknown events", func(t *testing.T) {
+		svc, _ := newTestWebhookService(t)
+		for _, webhook := range []*domain.Webhook{
+			{TenantID: "gym", URL: "http://hooks.example.com", Events: []string{domain.WebhookEventPBAchieved}},
+			{TenantID: "gym", URL: "https://hooks.example.com", Events: []string{"member.deleted"}},
+			{TenantID: "gym", URL: "https://hooks.example.com"},
+			{TenantID: "gym", URL: "https://169.254.169.254/latest/meta-data", Events: []string{domain.WebhookEventPBAchieved}},
+		} {
+			_, err := svc.Create(ctx, webhook)
+			assert.ErrorIs(t, err, domain.ErrInvalidWebhook, webhook.URL)
+		}
+	})
+
+	t.Run("rejects hosts that resolve inward", func(t *testing.T) {
+		svc, m := newTestWebhookService(t)
+		m.poster.On("CheckURL", ctx, "https://internal.example.com").Return(fmt.Errorf("%w: url must point to a public host", domain.ErrInvalidWebhook))
+
+		_, err := svc.Create(ctx, &domain.Webhook{TenantID: "gym", URL: "https://internal.example.com", Events: []string{domain.WebhookEventPBAchieved}})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidWebhook)
+		m.webhooks.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestWebhookService_Deliveries(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestWebhookService(t)
+	m.webhooks.On("GetByID", ctx, "wh-1").Return(&domain.Webhook{ID: "wh-1", TenantID: "gym"}, nil)
+
+	_, err := svc.Deliveries(ctx, "rival", "wh-1", domain.PageQuery{})
+	assert.ErrorIs(t, err, domain.ErrNotFound, "another tenant's webhook")
+
+	page := &domain.Page[*domain.WebhookDelivery]{Items: []*domain.WebhookDelivery{{ID: "d-1"}}}
+	m.deliveries.On("ListByWebhook", ctx, "wh-1", domain.PageQuery{Limit: 5}).Return(page, nil)
+
+	got, err := svc.Deliveries(ctx, "gym", "wh-1", domain.PageQuery{Limit: 5})
+	require.NoError(t, err)
+	assert.Equal(t, page, got)
+}
+
+func TestWebhookService_Publish(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestWebhookService(t)
+	m.webhooks.On("ListSubscribed", ctx, "gym", domain.WebhookEventScheduleCompleted).Return([]*domain.Webhook{{ID: "wh-1"}, {ID: "wh-2"}}, nil)
+	var created []*domain.WebhookDelivery
+	m.deliveries.On("Create", ctx, mock.AnythingOfType("*domain.WebhookDelivery")).Run(func(args mock.Arguments) {
+		created = append(created, args.Get(1).(*domain.WebhookDelivery))
+	}).Return(true, nil)
+
+	err := svc.HandleWorkoutEvent(ctx, &domain.WorkoutEvent{Type: domain.WorkoutEventSessionCompleted, TenantID: "gym",
+		ScheduleID: "sched-1", MemberID: "member-1", ActorID: "coach-1", OccurredAt: testNow})
+
+	require.NoError(t, err)
+	require.Len(t, created, 2)
+	assert.Equal(t, "wh-2", created[1].WebhookID)
+	for _, d := range created {
+		assert.Equal(t, "schedule.completed:sched-1", d.EventID)
+		assert.Equal(t, testNow, d.NextAttemptAt)
+	}
+	var body struct {
+		ID    string                 `json:"id"`
+		Event string                 `json:"event"`
+		Data  map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(created[0].Body), &body))
+	assert.Equal(t, "schedule.completed:sched-1", body.ID)
+	assert.Equal(t, domain.WebhookEventScheduleCompleted, body.Event)
+	assert.Equal(t, "coach-1", body.Data["coach_id"])
+
+	// Other workout events aren't published
+	require.NoError(t, svc.HandleWorkoutEvent(ctx, &domain.WorkoutEvent{Type: domain.WorkoutEventSetLogged, TenantID: "gym"}))
+}
+
+func TestWebhookService_HandleNewPB(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestWebhookService(t)
+	m.users.On("GetByID", ctx, "member-1").Return(&domain.User{ID: "member-1", TenantID: "gym"}, nil)
+	m.webhooks.On("ListSubscribed", ctx, "gym", domain.WebhookEventPBAchieved).Return([]*domain.Webhook{{ID: "wh-1"}}, nil)
+	m.deliveries.On("Create", ctx, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+		return d.TenantID == "gym" && d.EventID == "pb.achieved:member-1:squat:92.5"
+	})).Return(false, nil) // Already recorded
+
+	err := svc.HandleNewPB(ctx, &domain.PersonalBest{MemberID: "member-1", ExerciseID: "squat", Weight: 92.5, Reps: 3})
+
+	require.NoError(t, err)
+}
+
+func TestWebhookService_DeliverDue(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestWebhookService(t)
+	m.webhooks.On("GetByID", ctx, "wh-1").Return(&domain.Webhook{ID: "wh-1", URL: "https://hooks.example.com", Secret: "s3cret", Active: true}, nil)
+	m.webhooks.On("GetByID", ctx, "wh-gone").Return(nil, domain.ErrNotFound)
+
+	ok := &domain.WebhookDelivery{ID: "d-1", WebhookID: "wh-1", Body: `{"id":"a"}`, Attempts: 1}
+	retry := &domain.WebhookDelivery{ID: "d-2", WebhookID: "wh-1", Body: `{"id":"b"}`, Attempts: 3}
+	last := &domain.WebhookDelivery{ID: "d-3", WebhookID: "wh-gone", Attempts: domain.MaxWebhookAttempts}
+	m.deliveries.On("Claim", ctx, testNow, webhookLease).Return(ok, nil).Once()
+	m.deliveries.On("Claim", ctx, testNow, webhookLease).Return(retry, nil).Once()
+	m.deliveries.On("Claim", ctx, testNow, webhookLease).Return(last, nil).Once()
+	m.deliveries.On("Claim", ctx, testNow, webhookLease).Return(nil, nil).Once()
+
+	m.poster.On("Post", ctx, "https://hooks.example.com", "s3cret", []byte(`{"id":"a"}`)).Return(nil)
+	m.poster.On("Post", ctx, "https://hooks.example.com", "s3cret", []byte(`{"id":"b"}`)).Return(errors.New("webhook answered 503"))
+	m.deliveries.On("MarkDelivered", ctx, "d-1", testNow).Return(nil)
+	m.deliveries.On("MarkFailed", ctx, "d-2", "webhook answered 503", testNow, testNow.Add(2*time.Minute), false).Return(nil)
+	m.deliveries.On("MarkFailed", ctx, "d-3", "webhook was deleted", testNow, mock.Anything, true).Return(nil)
+
+	delivered, err := svc.DeliverDue(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, webhookBackoff(1))
+	assert.Equal(t, 4*time.Minute, webhookBackoff(4))
+	assert.Equal(t, webhookMaxBackoff, webhookBackoff(20))
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+const (
+	widgetTokenPrefix   = "wgt_"
+	widgetRateWindow    = time.Minute
+	widgetPerIPLimit    = 30 // Per token and visitor IP, whatever the token's own limit
+	widgetPrefixVisible = 8  // Characters of the secret shown in the admin's token list
+)
+
+// WidgetService issues the public read-only tokens tenants embed in widgets on their own
+// sites, and serves the coach, timetable and package data those widgets show
+type WidgetService struct {
+	tokens       domain.WidgetTokenRepository
+	limiter      domain.TokenBucket
+	userRepo     domain.UserRepository
+	branchRepo   domain.BranchRepository
+	availability domain.CoachAvailabilityRepository
+	packageRepo  domain.PTPackageRepository
+	clock        domain.Clock
+}
+
+func NewWidgetService(
+	tokens domain.WidgetTokenRepository,
+	limiter domain.TokenBucket,
+	userRepo domain.UserRepository,
+	branchRepo domain.BranchRepository,
+	availability domain.CoachAvailabilityRepository,
+	packageRepo domain.PTPackageRepository,
+	clk domain.Clock,
+) *WidgetService {
+	return &WidgetService{
+		tokens:       tokens,
+		limiter:      limiter,
+		userRepo:     userRepo,
+		branchRepo:   branchRepo,
+		availability: availability,
+		packageRepo:  packageRepo,
+		clock:        clock.OrReal(clk),
+	}
+}
+
+// CreateToken stores a new token and returns it with its secret. The secret is only
+// available now; afterwards the token can only be revoked.
+func (s *WidgetService) CreateToken(ctx context.Context, token *domain.WidgetToken) (string, error) {
+	if err := token.Validate(); err != nil {
+		return "", err
+	}
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate widget token: %w", err)
+	}
+	secret := widgetTokenPrefix + hex.EncodeToString(raw)
+
+	token.TokenHash = hashToken(secret)
+	token.Prefix = secret[:len(widgetTokenPrefix)+widgetPrefixVisible]
+	token.CreatedAt = s.clock.Now().UTC()
+	token.RevokedAt = nil
+	if err := s.tokens.Create(ctx, token); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+func (s *WidgetService) ListTokens(ctx context.Context, tenantID string) ([]*domain.WidgetToken, error) {
+	return s.tokens.ListByTenant(ctx, tenantID)
+}
+
+func (s *WidgetService) RevokeToken(ctx context.Context, tenantID, id string) error {
+	return s.tokens.Revoke(ctx, tenantID, id, s.clock.Now().UTC())
+}
+
+// Authenticate resolves a widget request to its token, checking scope, origin and both the
+// token's and the visitor's rate limits. When rate limited it also returns how long to wait.
+func (s *WidgetService) Authenticate(ctx context.Context, secret, scope, origin, ip string) (*domain.WidgetToken, time.Duration, error) {
+	if secret == "" {
+		return nil, 0, domain.ErrWidgetUnauthorized
+	}
+	token, err := s.tokens.FindByHash(ctx, hashToken(secret))
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && token.RevokedAt != nil) {
+		return nil, 0, domain.ErrWidgetUnauthorized
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	if !token.Permits(scope, origin) {
+		return nil, 0, domain.ErrWidgetScopeDenied
+	}
+
+	allowed, retryAfter, err := s.limiter.Take(ctx, []domain.Bucket{
+		{Key: "widget:" + token.ID, Limit: domain.RateLimit{Requests: token.RateLimit, Per: widgetRateWindow}},
+		{Key: "widget:" + token.ID + ":" + ip, Limit: domain.RateLimit{Requests: widgetPerIPLimit, Per: widgetRateWindow}},
+	})
+	if err != nil {
+		// Widgets stay up when Redis is unavailable
+		log.Printf("Warning: widget rate limit unavailable: %v", err)
+	} else if !allowed {
+		return nil, retryAfter, domain.ErrRateLimited
+	}
+	return token, 0, nil
+}
+
+// Coaches lists the public profiles of the tenant's coaches, by name
+func (s *WidgetService) Coaches(ctx context.Context, tenantID string) ([]domain.WidgetCoach, error) {
+	coaches, err := s.tenantCoaches(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	profiles := make([]domain.WidgetCoach, 0, len(coaches))
+	for _, coach := range coaches {
+		branchIDs := []string{}
+		if coach.HomeBranchID != "" {
+			branchIDs = append(branchIDs, coach.HomeBranchID)
+		}
+		for _, id := range coach.WorkingBranchIDs {
+			if id != coach.HomeBranchID {
+				branchIDs = append(branchIDs, id)
+			}
+		}
+		profiles = append(profiles, domain.WidgetCoach{ID: coach.ID, Name: coach.Name, AvatarURL: coach.AvatarURL, BranchIDs: branchIDs})
+	}
+	return profiles, nil
+}
+
+// Timetable lays the coaches' weekly working hours out per branch. Coaches who haven't set
+// their hours are bookable any time and so don't appear.
+func (s *WidgetService) Timetable(ctx context.Context, tenantID string) ([]domain.WidgetBranchTimetable, error) {
+	branches, err := s.branchRepo.GetByTenantID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	coaches, err := s.tenantCoaches(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make(map[string][]domain.WidgetSlot)
+	for _, coach := range coaches {
+		hours, err := s.availability.Get(ctx, coach.ID)
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range hours.Windows {
+			slots[w.BranchID] = append(slots[w.BranchID], domain.WidgetSlot{
+				Weekday:   w.Weekday,
+				Start:     w.Start,
+				End:       w.End,
+				CoachID:   coach.ID,
+				CoachName: coach.Name,
+			})
+		}
+	}
+
+	timetable := make([]domain.WidgetBranchTimetable, 0, len(branches))
+	for _, branch := range branches {
+		branchSlots := slots[branch.ID]
+		sort.Slice(branchSlots, func(i, j int) bool {
+			a, b := branchSlots[i], branchSlots[j]
+			if a.Weekday != b.Weekday {
+				return a.Weekday < b.Weekday
+			}
+			if a.Start != b.Start {
+				return a.Start < b.Start
+			}
+			return a.CoachName < b.CoachName
+		})
+		if branchSlots == nil {
+			branchSlots = []domain.WidgetSlot{}
+		}
+		timetable = append(timetable, domain.WidgetBranchTimetable{BranchID: branch.ID, Name: branch.Name, Slots: branchSlots})
+	}
+	return timetable, nil
+}
+
+// Packages lists the tenant's PT packages that can still be bought
+func (s *WidgetService) Packages(ctx context.Context, tenantID string) ([]domain.WidgetPackage, error) {
+	packages, err := s.packageRepo.GetByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	branches, err := s.branchRepo.GetByTenantID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	branchNames := make(map[string]string, len(branches))
+	for _, b := range branches {
+		branchNames[b.ID] = b.Name
+	}
+
+	catalog := []domain.WidgetPackage{}
+	for _, pkg := range packages {
+		if !pkg.Active {
+			continue
+		}
+		catalog = append(catalog, domain.WidgetPackage{
+			ID:            pkg.ID,
+			Name:          pkg.Name,
+			BranchID:      pkg.BranchID,
+			BranchName:    branchNames[pkg.BranchID],
+			TotalSessions: pkg.TotalSessions,
+			Price:         pkg.Price,
+			BranchPrices:  pkg.BranchPrices,
+		})
+	}
+	sort.SliceStable(catalog, func(i, j int) bool {
+		if catalog[i].BranchName != catalog[j].BranchName {
+			return catalog[i].BranchName < catalog[j].BranchName
+		}
+		return catalog[i].TotalSessions < catalog[j].TotalSessions
+	})
+	return catalog, nil
+}
+
+// tenantCoaches returns the tenant's coaches sorted by name, leaving out generated demo users
+func (s *WidgetService) tenantCoaches(ctx context.Context, tenantID string) ([]*domain.User, error) {
+	users, err := s.userRepo.GetByTenantAndRole(ctx, tenantID, domain.RoleCoach)
+	if err != nil {
+		return nil, err
+	}
+	coaches := make([]*domain.User, 0, len(users))
+	for _, u := range users {
+		if !u.Demo {
+			coaches = append(coaches, u)
+		}
+	}
+	sort.Slice(coaches, func(i, j int) bool { return coaches[i].Name < coaches[j].Name })
+	return coaches, nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type widgetMocks struct {
+	tokens       *mocks.WidgetTokenRepository
+	limiter      *mocks.TokenBucket
+	users        *mocks.UserRepository
+	branches     *mocks.BranchRepository
+	availability *mocks.CoachAvailabilityRepository
+}
+
+func newTestWidgetService(t *testing.T) (*WidgetService, widgetMocks) {
+	m := widgetMocks{
+		tokens:       mocks.NewWidgetTokenRepository(t),
+		limiter:      mocks.NewTokenBucket(t),
+		users:        mocks.NewUserRepository(t),
+		branches:     mocks.NewBranchRepository(t),
+		availability: mocks.NewCoachAvailabilityRepository(t),
+	}
+	return NewWidgetService(m.tokens, m.limiter, m.users, m.branches, m.availability, nil, clock.NewFake(testNow)), m
+}
+
+func TestWidgetService_CreateToken(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestWidgetService(t)
+
+	var stored *domain.WidgetToken
+	m.tokens.On("Create", ctx, mock.AnythingOfType("*domain.WidgetToken")).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*domain.WidgetToken)
+	}).Return(nil)
+
+	secret, err := svc.CreateToken(ctx, &domain.WidgetToken{TenantID: "tenant-1", Name: "Website", Scopes: []string{domain.WidgetScopeCoaches}})
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, stored.Prefix))
+	assert.Equal(t, hashToken(secret), stored.TokenHash, "only the hash is stored")
+	assert.Equal(t, domain.DefaultWidgetRateLimit, stored.RateLimit)
+
+	_, err = svc.CreateToken(ctx, &domain.WidgetToken{TenantID: "tenant-1", Name: "Website", Scopes: []string{"members"}})
+	assert.ErrorIs(t, err, domain.ErrInvalidWidgetToken)
+}
+
+func TestWidgetService_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	const secret = "wgt_secret"
+	token := &domain.WidgetToken{
+		ID:             "tok-1",
+		TenantID:       "tenant-1",
+		Scopes:         []string{domain.WidgetScopeCoaches, domain.WidgetScopeTimetable},
+		AllowedOrigins: []string{"https://gym.example"},
+		RateLimit:      60,
+	}
+	// The token's limit and the visitor's, taken from together
+	buckets := []domain.Bucket{
+		{Key: "widget:tok-1", Limit: domain.RateLimit{Requests: 60, Per: time.Minute}},
+		{Key: "widget:tok-1:203.0.113.7", Limit: domain.RateLimit{Requests: widgetPerIPLimit, Per: time.Minute}},
+	}
+
+	t.Run("within limits", func(t *testing.T) {
+		svc, m := newTestWidgetService(t)
+		m.tokens.On("FindByHash", ctx, hashToken(secret)).Return(token, nil)
+		m.limiter.On("Take", ctx, buckets).Return(true, time.Duration(0), nil)
+
+		got, _, err := svc.Authenticate(ctx, secret, domain.WidgetScopeCoaches, "https://gym.example", "203.0.113.7")
+
+		require.NoError(t, err)
+		assert.Equal(t, "tenant-1", got.TenantID)
+	})
+
+	t.Run("scope or origin not allowed", func(t *testing.T) {
+		svc, m := newTestWidgetService(t)
+		m.tokens.On("FindByHash", ctx, hashToken(secret)).Return(token, nil)
+
+		_, _, err := svc.Authenticate(ctx, secret, domain.WidgetScopePackages, "https://gym.example", "203.0.113.7")
+		assert.ErrorIs(t, err, domain.ErrWidgetScopeDenied)
+
+		_, _, err = svc.Authenticate(ctx, secret, domain.WidgetScopeCoaches, "https://other.example", "203.0.113.7")
+		assert.ErrorIs(t, err, domain.ErrWidgetScopeDenied)
+	})
+
+	t.Run("revoked token", func(t *testing.T) {
+		svc, m := newTestWidgetService(t)
+		revoked := *token
+		revoked.RevokedAt = &testNow
+		m.tokens.On("FindByHash", ctx, hashToken(secret)).Return(&revoked, nil)
+
+		_, _, err := svc.Authenticate(ctx, secret, domain.WidgetScopeCoaches, "", "203.0.113.7")
+
+		assert.ErrorIs(t, err, domain.ErrWidgetUnauthorized)
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		svc, m := newTestWidgetService(t)
+		m.tokens.On("FindByHash", ctx, hashToken(secret)).Return(token, nil)
+		m.limiter.On("Take", ctx, buckets).Return(false, 20*time.Second, nil)
+
+		_, retryAfter, err := svc.Authenticate(ctx, secret, domain.WidgetScopeCoaches, "", "203.0.113.7")
+
+		assert.ErrorIs(t, err, domain.ErrRateLimited)
+		assert.Equal(t, 20*time.Second, retryAfter)
+	})
+}
+
+func TestWidgetService_Timetable(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestWidgetService(t)
+
+	m.branches.On("GetByTenantID", ctx, "tenant-1").Return([]*domain.Branch{
+		{ID: "branch-1", Name: "Kemang"},
+		{ID: "branch-2", Name: "Senayan"},
+	}, nil)
+	m.users.On("GetByTenantAndRole", ctx, "tenant-1", domain.RoleCoach).Return([]*domain.User{
+		{ID: "coach-b", Name: "Budi"},
+		{ID: "coach-a", Name: "Ayu"},
+		{ID: "coach-demo", Name: "Demo Coach", Demo: true},
+	}, nil)
+	m.availability.On("Get", ctx, "coach-a").Return(&domain.CoachAvailability{Windows: []domain.AvailabilityWindow{
+		{BranchID: "branch-1", Weekday: time.Tuesday, Start: "07:00", End: "12:00"},
+	}}, nil)
+	m.availability.On("Get", ctx, "coach-b").Return(&domain.CoachAvailability{Windows: []domain.AvailabilityWindow{
+		{BranchID: "branch-1", Weekday: time.Monday, Start: "16:00", End: "21:00"},
+		{BranchID: "branch-1", Weekday: time.Tuesday, Start: "07:00", End: "10:00"},
+	}}, nil)
+
+	timetable, err := svc.Timetable(ctx, "tenant-1")
+
+	require.NoError(t, err)
+	require.Len(t, timetable, 2)
+	slots := timetable[0].Slots
+	require.Len(t, slots, 3)
+	assert.Equal(t, time.Monday, slots[0].Weekday)
+	assert.Equal(t, "Ayu", slots[1].CoachName, "same start time sorts by coach")
+	assert.Equal(t, "Budi", slots[2].CoachName)
+	assert.Empty(t, timetable[1].Slots)
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/jobs"
+)
+
+// WorkoutEventConsumer derives data from workout events.
+// Consumers must be idempotent: Rebuild replays already-consumed events through them.
+type WorkoutEventConsumer interface {
+	HandleWorkoutEvent(ctx context.Context, event *domain.WorkoutEvent) error
+}
+
+type namedConsumer struct {
+	name     string
+	consumer WorkoutEventConsumer
+}
+
+// WorkoutEventLog appends workout events and dispatches them to the subscribed consumers
+type WorkoutEventLog struct {
+	repo  domain.WorkoutEventRepository
+	clock domain.Clock
+
+	mu        sync.RWMutex
+	consumers []namedConsumer
+	runs      *jobs.Runner // Optional: see TrackRuns
+}
+
+func NewWorkoutEventLog(repo domain.WorkoutEventRepository, clk domain.Clock) *WorkoutEventLog {
+	return &WorkoutEventLog{repo: repo, clock: clock.OrReal(clk)}
+}
+
+// Subscribe registers a consumer; events are dispatched in subscription order
+func (l *WorkoutEventLog) Subscribe(name string, consumer WorkoutEventConsumer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.consumers = append(l.consumers, namedConsumer{name: name, consumer: consumer})
+	if l.runs != nil {
+		l.handleRetries(namedConsumer{name: name, consumer: consumer})
+	}
+}
+
+// TrackRuns records every consumer's handling of a completed session in the job history,
+// so a session whose volumes or PBs failed to update shows up there and can be retried
+func (l *WorkoutEventLog) TrackRuns(runs *jobs.Runner) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.runs = runs
+	for _, c := range l.consumers {
+		l.handleRetries(c)
+	}
+}
+
+// handleRetries lets a failed run be retried by replaying the schedule through that consumer only
+func (l *WorkoutEventLog) handleRetries(c namedConsumer) {
+	l.runs.Handle(workoutEventJobType(c.name), func(ctx context.Context, params map[string]interface{}) error {
+		scheduleID, _ := params["schedule_id"].(string)
+		events, err := l.repo.ListBySchedule(ctx, scheduleID)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if err := c.consumer.HandleWorkoutEvent(ctx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func workoutEventJobType(consumer string) string {
+	return "workout-event:" + consumer
+}
+
+// Emit appends the event and then dispatches it.
+// Only a failed append is returned: derived data that failed to update can be rebuilt later.
+func (l *WorkoutEventLog) Emit(ctx context.Context, event *domain.WorkoutEvent) error {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = l.clock.Now()
+	}
+	if err := l.repo.Append(ctx, event); err != nil {
+		return err
+	}
+	l.dispatch(ctx, event)
+	return nil
+}
+
+// Rebuild replays a schedule's events through every consumer and returns how many were replayed
+func (l *WorkoutEventLog) Rebuild(ctx context.Context, scheduleID string) (int, error) {
+	events, err := l.repo.ListBySchedule(ctx, scheduleID)
+	if err != nil {
+		return 0, err
+	}
+	for _, event := range events {
+		l.dispatch(ctx, event)
+	}
+	return len(events), nil
+}
+
+// RebuildAll replays every completed schedule
+func (l *WorkoutEventLog) RebuildAll(ctx context.Context) (schedules int, err error) {
+	ids, err := l.repo.ListScheduleIDs(ctx, domain.WorkoutEventSessionCompleted)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		if _, err := l.Rebuild(ctx, id); err != nil {
+			return schedules, fmt.Errorf("failed to rebuild schedule %s: %w", id, err)
+		}
+		schedules++
+	}
+	return schedules, nil
+}
+
+func (l *WorkoutEventLog) dispatch(ctx context.Context, event *domain.WorkoutEvent) {
+	l.mu.RLock()
+	consumers, runs := l.consumers, l.runs
+	l.mu.RUnlock()
+
+	for _, c := range consumers {
+		handle := func(ctx context.Context) error {
+			return c.consumer.HandleWorkoutEvent(ctx, event)
+		}
+		// Consumers only do real work on completion; recording every set logged would drown the history
+		var err error
+		if runs != nil && event.Type == domain.WorkoutEventSessionCompleted {
+			err = runs.Record(ctx, workoutEventJobType(c.name), event.TenantID, map[string]interface{}{"schedule_id": event.ScheduleID}, handle)
+		} else {
+			err = handle(ctx)
+		}
+		if err != nil {
+			fmt.Printf("Warning: %s failed to handle %s for schedule %s: %v\n", c.name, event.Type, event.ScheduleID, err)
+		}
+	}
+}
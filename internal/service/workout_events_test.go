@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingConsumer remembers the event types it was given
+type recordingConsumer struct {
+	seen []string
+	err  error
+}
+
+func (c *recordingConsumer) HandleWorkoutEvent(_ context.Context, event *domain.WorkoutEvent) error {
+	c.seen = append(c.seen, event.Type)
+	return c.err
+}
+
+func TestWorkoutEventLog_Emit(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("appends, stamps and dispatches to every consumer", func(t *testing.T) {
+		repo := mocks.NewWorkoutEventRepository(t)
+		repo.On("Append", ctx, mock.MatchedBy(func(e *domain.WorkoutEvent) bool {
+			return e.OccurredAt.Equal(testNow)
+		})).Return(nil)
+
+		log := NewWorkoutEventLog(repo, clock.NewFake(testNow))
+		failing := &recordingConsumer{err: errors.New("boom")}
+		after := &recordingConsumer{}
+		log.Subscribe("failing", failing)
+		log.Subscribe("after", after)
+
+		require.NoError(t, log.Emit(ctx, &domain.WorkoutEvent{Type: domain.WorkoutEventSessionCompleted, ScheduleID: testScheduleID}))
+		assert.Equal(t, []string{domain.WorkoutEventSessionCompleted}, failing.seen)
+		assert.Equal(t, []string{domain.WorkoutEventSessionCompleted}, after.seen, "a failing consumer doesn't block the others")
+	})
+
+	t.Run("does not dispatch when the append fails", func(t *testing.T) {
+		repo := mocks.NewWorkoutEventRepository(t)
+		repo.On("Append", ctx, mock.Anything).Return(errors.New("mongo unavailable"))
+
+		log := NewWorkoutEventLog(repo, clock.NewFake(testNow))
+		consumer := &recordingConsumer{}
+		log.Subscribe("consumer", consumer)
+
+		assert.Error(t, log.Emit(ctx, &domain.WorkoutEvent{Type: domain.WorkoutEventSetLogged}))
+		assert.Empty(t, consumer.seen)
+	})
+}
+
+func TestWorkoutEventLog_Rebuild(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewWorkoutEventRepository(t)
+	repo.On("ListBySchedule", ctx, testScheduleID).Return([]*domain.WorkoutEvent{
+		{Type: domain.WorkoutEventSessionInitialized},
+		{Type: domain.WorkoutEventSetLogged},
+		{Type: domain.WorkoutEventSessionCompleted},
+	}, nil)
+
+	log := NewWorkoutEventLog(repo, nil)
+	consumer := &recordingConsumer{}
+	log.Subscribe("consumer", consumer)
+
+	n, err := log.Rebuild(ctx, testScheduleID)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []string{domain.WorkoutEventSessionInitialized, domain.WorkoutEventSetLogged, domain.WorkoutEventSessionCompleted}, consumer.seen)
+}
+
+func TestWorkoutService_RecordSessionCompleted(t *testing.T) {
+	ctx := context.Background()
+	schedule := &domain.Schedule{ID: testScheduleID, TenantID: "tenant-1", MemberID: "member-1"}
+
+	repo := mocks.NewWorkoutEventRepository(t)
+	repo.On("Append", anyCtx, mock.MatchedBy(func(e *domain.WorkoutEvent) bool {
+		return e.Type == domain.WorkoutEventSessionCompleted && e.ScheduleID == testScheduleID &&
+			e.MemberID == "member-1" && e.TenantID == "tenant-1" && e.ActorID == "coach-1"
+	})).Return(nil)
+	events := NewWorkoutEventLog(repo, clock.NewFake(testNow))
+	consumer := &recordingConsumer{}
+	events.Subscribe("consumer", consumer)
+
+	svc := NewWorkoutService(nil, nil, nil, nil, nil, nil, nil, events, nil)
+
+	svc.RecordSessionCompleted(ctx, schedule, "coach-1")
+
+	assert.Equal(t, []string{domain.WorkoutEventSessionCompleted}, consumer.seen)
+}
@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/mansoorceksport/metamorph/internal/clock"
 	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/telemetry"
 	"github.com/oklog/ulid/v2"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -22,6 +24,8 @@ type WorkoutService struct {
 	setLogRepo   domain.SetLogRepository       // For atomic set operations
 	pbRepo       domain.PersonalBestRepository // For PB tracking
 	volumeRepo   domain.DailyVolumeRepository  // For volume aggregation
+	events       *WorkoutEventLog              // Optional: records session lifecycle events for the derived-data consumers
+	clock        domain.Clock
 }
 
 func NewWorkoutService(
@@ -32,6 +36,8 @@ func NewWorkoutService(
 	setLogRepo domain.SetLogRepository,
 	pbRepo domain.PersonalBestRepository,
 	volumeRepo domain.DailyVolumeRepository,
+	events *WorkoutEventLog,
+	clk domain.Clock,
 ) *WorkoutService {
 	return &WorkoutService{
 		exerciseRepo: exerciseRepo,
@@ -41,16 +47,70 @@ func NewWorkoutService(
 		setLogRepo:   setLogRepo,
 		pbRepo:       pbRepo,
 		volumeRepo:   volumeRepo,
+		events:       events,
+		clock:        clock.OrReal(clk),
 	}
 }
 
+// emit records a workout event. The state change it describes has already been saved,
+// so a failed append is logged rather than failing the request.
+func (s *WorkoutService) emit(ctx context.Context, event *domain.WorkoutEvent) {
+	if s.events == nil {
+		return
+	}
+	if err := s.events.Emit(ctx, event); err != nil {
+		fmt.Printf("Warning: failed to record %s for schedule %s: %v\n", event.Type, event.ScheduleID, err)
+	}
+}
+
+// RecordSessionCompleted emits session.completed for a schedule that was just completed.
+// Without an event log the volume and PB consumers are run directly.
+func (s *WorkoutService) RecordSessionCompleted(ctx context.Context, schedule *domain.Schedule, actorID string) {
+	ctx, span := telemetry.StartSpan(ctx, "WorkoutService.RecordSessionCompleted",
+		telemetry.TenantID(schedule.TenantID), telemetry.MemberID(schedule.MemberID))
+	defer span.End()
+
+	event := &domain.WorkoutEvent{
+		Type:       domain.WorkoutEventSessionCompleted,
+		TenantID:   schedule.TenantID,
+		ScheduleID: schedule.ID,
+		MemberID:   schedule.MemberID,
+		ActorID:    actorID,
+	}
+	if s.events != nil {
+		s.emit(ctx, event)
+		return
+	}
+
+	if err := s.HandleWorkoutEvent(ctx, event); err != nil {
+		fmt.Printf("Warning: failed to aggregate volume for schedule %s: %v\n", schedule.ID, err)
+	}
+	if s.pbRepo != nil {
+		if err := NewPersonalBestDetector(s.setLogRepo, s.pbRepo, s.scheduleRepo, s.exerciseRepo, nil).Detect(ctx, schedule.ID); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}
+}
+
+// HandleWorkoutEvent is the volume aggregator: it (re)computes a schedule's daily volume on completion
+func (s *WorkoutService) HandleWorkoutEvent(ctx context.Context, event *domain.WorkoutEvent) error {
+	if event.Type != domain.WorkoutEventSessionCompleted {
+		return nil
+	}
+	_, err := s.AggregateSessionVolume(ctx, event.ScheduleID, event.MemberID, event.TenantID)
+	return err
+}
+
 // generateULID creates a new ULID string
 func generateULID() string {
 	return ulid.MustNew(ulid.Timestamp(time.Now()), rand.Reader).String()
 }
 
 // InitializeSession creates a WorkoutSession from a Template linked to a Schedule
-func (s *WorkoutService) InitializeSession(ctx context.Context, scheduleID string, templateID string) (*domain.WorkoutSession, error) {
+func (s *WorkoutService) InitializeSession(ctx context.Context, scheduleID string, templateID string) (_ *domain.WorkoutSession, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "WorkoutService.InitializeSession", attribute.String("schedule_id", scheduleID))
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	// 1. Verify Schedule
 	schedule, err := s.scheduleRepo.GetByID(ctx, scheduleID)
 	if err != nil {
@@ -139,6 +199,15 @@ func (s *WorkoutService) InitializeSession(ctx context.Context, scheduleID strin
 		}
 	}
 
+	s.emit(ctx, &domain.WorkoutEvent{
+		Type:       domain.WorkoutEventSessionInitialized,
+		TenantID:   schedule.TenantID,
+		ScheduleID: schedule.ID,
+		MemberID:   schedule.MemberID,
+		ActorID:    schedule.CoachID,
+		Data:       map[string]interface{}{"session_id": session.ID, "template_id": templateID},
+	})
+
 	// Return full session with exercises inflated
 	return s.GetSession(ctx, session.ID)
 }
@@ -178,12 +247,20 @@ func (s *WorkoutService) resolveScheduleID(ctx context.Context, idOrClientID str
 
 	// Try to get by client_id (ULID)
 	schedule, err := s.scheduleRepo.GetByClientID(ctx, idOrClientID)
-	if err != nil {
-		span.RecordError(err)
-		return "", fmt.Errorf("schedule not found for ID/ULID: %s", idOrClientID)
+	if err == nil {
+		span.SetAttributes(attribute.String("resolved_via", "client_id"))
+		return schedule.ID, nil
+	}
+
+	// Schedules created since the ULID switch have a ULID server ID as well
+	if !isMongoID {
+		if schedule, idErr := s.scheduleRepo.GetByID(ctx, idOrClientID); idErr == nil {
+			span.SetAttributes(attribute.String("resolved_via", "ulid_id"))
+			return schedule.ID, nil
+		}
 	}
-	span.SetAttributes(attribute.String("resolved_via", "client_id"))
-	return schedule.ID, nil
+	span.RecordError(err)
+	return "", fmt.Errorf("schedule not found for ID/ULID: %s", idOrClientID)
 }
 
 // resolveExerciseID accepts either MongoDB ObjectID or ULID and resolves to Exercise's MongoDB ID
@@ -220,7 +297,10 @@ func (s *WorkoutService) resolveExerciseID(ctx context.Context, idOrClientID str
 // AddExerciseToSession adds an exercise dynamically (at end) - RETURNS the added exercise
 // clientID is the frontend ULID for dual-identity handshake
 // targetSets, targetReps, restSeconds, notes, order are passed from the frontend
-func (s *WorkoutService) AddExerciseToSession(ctx context.Context, scheduleID string, exerciseID string, clientID string, targetSets int, targetReps int, restSeconds int, notes string, order int) (*domain.PlannedExercise, error) {
+func (s *WorkoutService) AddExerciseToSession(ctx context.Context, scheduleID string, exerciseID string, clientID string, targetSets int, targetReps int, restSeconds int, notes string, order int) (_ *domain.PlannedExercise, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "WorkoutService.AddExerciseToSession", attribute.String("schedule_id", scheduleID))
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	// Resolve scheduleID (handles both MongoDB ObjectID and frontend ULID)
 	resolvedScheduleID, err := s.resolveScheduleID(ctx, scheduleID)
 	if err != nil {
@@ -228,7 +308,7 @@ func (s *WorkoutService) AddExerciseToSession(ctx context.Context, scheduleID st
 	}
 
 	// Get the schedule to find member_id for PB tracking (or just verify existence)
-	_, err = s.scheduleRepo.GetByID(ctx, resolvedScheduleID)
+	schedule, err := s.scheduleRepo.GetByID(ctx, resolvedScheduleID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get schedule: %w", err)
 	}
@@ -252,6 +332,9 @@ func (s *WorkoutService) AddExerciseToSession(ctx context.Context, scheduleID st
 	if err != nil {
 		return nil, err
 	}
+	if !ex.VisibleTo(schedule.TenantID) {
+		return nil, domain.ErrExerciseNotFound // Another tenant's private exercise
+	}
 
 	// Use defaults if not provided
 	if targetSets == 0 {
@@ -303,6 +386,15 @@ func (s *WorkoutService) AddExerciseToSession(ctx context.Context, scheduleID st
 		}
 	*/
 
+	s.emit(ctx, &domain.WorkoutEvent{
+		Type:       domain.WorkoutEventExerciseAdded,
+		TenantID:   schedule.TenantID,
+		ScheduleID: schedule.ID,
+		MemberID:   schedule.MemberID,
+		ActorID:    schedule.CoachID,
+		Data:       map[string]interface{}{"planned_exercise_id": planned.ID, "exercise_id": ex.ID, "order": order},
+	})
+
 	return planned, nil
 }
 
@@ -356,7 +448,47 @@ func (s *WorkoutService) GetExercisesBySchedule(ctx context.Context, scheduleID
 
 // UpdateSetLog atomically updates a set log document (new set_logs collection)
 // Resolves ID (can be MongoDB ObjectID or client_id ULID)
-func (s *WorkoutService) UpdateSetLog(ctx context.Context, idOrClientID string, weight float64, reps int, remarks string, completed bool) error {
+func (s *WorkoutService) UpdateSetLog(ctx context.Context, idOrClientID string, weight float64, reps int, remarks string, completed bool) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "WorkoutService.UpdateSetLog")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	setLog, err := s.resolveSetLog(ctx, idOrClientID)
+	if err != nil {
+		return err
+	}
+
+	// Update fields
+	setLog.Weight = weight
+	setLog.Reps = reps
+	setLog.Remarks = remarks
+	setLog.Completed = completed
+
+	if err := s.setLogRepo.Update(ctx, setLog); err != nil {
+		return err
+	}
+
+	s.emit(ctx, &domain.WorkoutEvent{
+		Type:       domain.WorkoutEventSetLogged,
+		ScheduleID: setLog.ScheduleID,
+		MemberID:   setLog.MemberID,
+		Data: map[string]interface{}{
+			"set_log_id":  setLog.ID,
+			"exercise_id": setLog.ExerciseID,
+			"set_index":   setLog.SetIndex,
+			"weight":      weight,
+			"reps":        reps,
+			"completed":   completed,
+		},
+	})
+
+	// Note: PB updates are now handled at session completion (PTService.CompleteSession)
+	// to ensure data integrity after coach finalization.
+
+	return nil
+}
+
+// resolveSetLog finds a set log by MongoDB ID or client_id
+func (s *WorkoutService) resolveSetLog(ctx context.Context, idOrClientID string) (*domain.SetLogDocument, error) {
 	// Check if it's a valid MongoDB ObjectID (24 hex chars)
 	isMongoID := len(idOrClientID) == 24
 	if isMongoID {
@@ -382,26 +514,12 @@ func (s *WorkoutService) UpdateSetLog(ctx context.Context, idOrClientID string,
 	}
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if setLog == nil {
-		return domain.ErrSessionNotFound
-	}
-
-	// Update fields
-	setLog.Weight = weight
-	setLog.Reps = reps
-	setLog.Remarks = remarks
-	setLog.Completed = completed
-
-	if err := s.setLogRepo.Update(ctx, setLog); err != nil {
-		return err
+		return nil, domain.ErrSessionNotFound
 	}
-
-	// Note: PB updates are now handled at session completion (PTService.CompleteSession)
-	// to ensure data integrity after coach finalization.
-
-	return nil
+	return setLog, nil
 }
 
 // DeleteSetLog soft-deletes a set log by ID (Mongo ID or Client ID)
@@ -502,6 +620,39 @@ func (s *WorkoutService) GetSetsBySchedule(ctx context.Context, scheduleID strin
 	return s.setLogRepo.GetByScheduleID(ctx, resolvedScheduleID)
 }
 
+// LastPerformance returns the schedule's member's sets of exerciseID from the last
+// session they did it in, so the coach app can pre-fill weights and reps. Only the
+// schedule's coach and the admins of its tenant may read it; anyone else is told the
+// schedule doesn't exist.
+func (s *WorkoutService) LastPerformance(ctx context.Context, tenantID, callerID, callerRole, scheduleID, exerciseID string) (_ *domain.LastPerformance, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "WorkoutService.LastPerformance", attribute.String("exercise_id", exerciseID))
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	resolvedID, err := s.resolveScheduleID(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := s.scheduleRepo.GetByID(ctx, resolvedID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.TenantID != tenantID || (schedule.CoachID != callerID && callerRole != domain.RoleTenantAdmin) {
+		return nil, domain.ErrScheduleNotFound
+	}
+
+	last := &domain.LastPerformance{ExerciseID: exerciseID, Sets: []*domain.SetLogDocument{}}
+	sets, err := s.setLogRepo.GetLastPerformance(ctx, schedule.MemberID, exerciseID, schedule.ID)
+	if err != nil || len(sets) == 0 {
+		return last, err
+	}
+	last.ScheduleID = sets[0].ScheduleID
+	last.Sets = sets
+	if previous, err := s.scheduleRepo.GetByID(ctx, last.ScheduleID); err == nil {
+		last.PerformedAt = &previous.StartTime
+	}
+	return last, nil
+}
+
 // resolvePlannedExercise finds a planned exercise by MongoDB ID or client_id
 func (s *WorkoutService) resolvePlannedExercise(ctx context.Context, idOrClientID string) (*domain.PlannedExercise, error) {
 	// Check if it's a valid MongoDB ObjectID (24 hex chars, all lowercase hex)
@@ -531,7 +682,11 @@ func (s *WorkoutService) resolvePlannedExercise(ctx context.Context, idOrClientI
 // AggregateSessionVolume calculates and saves the total volume for a completed schedule
 // This should be called when a Schedule status changes to 'completed'
 // Volume = sum(Weight * Reps) for all completed sets
-func (s *WorkoutService) AggregateSessionVolume(ctx context.Context, scheduleID string, memberID string, tenantID string) (*domain.DailyVolume, error) {
+func (s *WorkoutService) AggregateSessionVolume(ctx context.Context, scheduleID string, memberID string, tenantID string) (_ *domain.DailyVolume, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "WorkoutService.AggregateSessionVolume",
+		telemetry.TenantID(tenantID), telemetry.MemberID(memberID), attribute.String("schedule_id", scheduleID))
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	// Check if we already have a volume record for this schedule
 	existing, err := s.volumeRepo.GetByScheduleID(ctx, scheduleID)
 	if err != nil {
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/clock"
+	"github.com/mansoorceksport/metamorph/internal/domain"
+	"github.com/mansoorceksport/metamorph/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testScheduleID = "65a1b2c3d4e5f60718293a4b" // Looks like a Mongo ObjectID
+	testClientID   = "01HQZX3M7K9V2C4B6N8P0R1S3T"
+)
+
+type workoutServiceMocks struct {
+	exerciseRepo *mocks.ExerciseRepository
+	sessionRepo  *mocks.WorkoutSessionRepository
+	scheduleRepo *mocks.ScheduleRepository
+	setLogRepo   *mocks.SetLogRepository
+	volumeRepo   *mocks.DailyVolumeRepository
+}
+
+func newTestWorkoutService(t *testing.T) (*WorkoutService, *workoutServiceMocks) {
+	m := &workoutServiceMocks{
+		exerciseRepo: mocks.NewExerciseRepository(t),
+		sessionRepo:  mocks.NewWorkoutSessionRepository(t),
+		scheduleRepo: mocks.NewScheduleRepository(t),
+		setLogRepo:   mocks.NewSetLogRepository(t),
+		volumeRepo:   mocks.NewDailyVolumeRepository(t),
+	}
+	svc := NewWorkoutService(m.exerciseRepo, mocks.NewTemplateRepository(t), m.sessionRepo, m.scheduleRepo, m.setLogRepo, mocks.NewPersonalBestRepository(t), m.volumeRepo, nil, clock.NewFake(testNow))
+	return svc, m
+}
+
+func TestWorkoutService_AggregateSessionVolume(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, 3, 10, 7, 0, 0, 0, time.UTC)
+	setLogs := []*domain.SetLogDocument{
+		{ExerciseID: "squat", Weight: 100, Reps: 5},
+		{ExerciseID: "squat", Weight: 100, Reps: 5},
+		{ExerciseID: "bench", Weight: 60, Reps: 10},
+		{ExerciseID: "bench", Weight: 0, Reps: 10}, // Not filled in: ignored
+	}
+
+	t.Run("sums filled sets and copies the focus area", func(t *testing.T) {
+		svc, m := newTestWorkoutService(t)
+		m.volumeRepo.On("GetByScheduleID", anyCtx, testScheduleID).Return(nil, nil)
+		m.setLogRepo.On("GetByScheduleID", anyCtx, testScheduleID).Return(setLogs, nil)
+		m.scheduleRepo.On("GetByID", anyCtx, testScheduleID).Return(&domain.Schedule{ID: testScheduleID, StartTime: start, FocusArea: domain.FocusAreaLegDay}, nil)
+		m.volumeRepo.On("Create", anyCtx, mock.AnythingOfType("*domain.DailyVolume")).Return(nil)
+
+		volume, err := svc.AggregateSessionVolume(ctx, testScheduleID, "member-1", "tenant-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, 1600.0, volume.TotalVolume)
+		assert.Equal(t, 3, volume.TotalSets)
+		assert.Equal(t, 20, volume.TotalReps)
+		assert.Equal(t, 260.0, volume.TotalWeight)
+		assert.Equal(t, 2, volume.ExerciseCount)
+		assert.Equal(t, domain.FocusAreaLegDay, volume.FocusArea)
+		assert.Equal(t, start, volume.Date)
+	})
+
+	t.Run("replaces an existing record", func(t *testing.T) {
+		svc, m := newTestWorkoutService(t)
+		m.volumeRepo.On("GetByScheduleID", anyCtx, testScheduleID).Return(&domain.DailyVolume{ID: "vol-old"}, nil)
+		m.setLogRepo.On("GetByScheduleID", anyCtx, testScheduleID).Return(setLogs, nil)
+		m.scheduleRepo.On("GetByID", anyCtx, testScheduleID).Return(&domain.Schedule{ID: testScheduleID, StartTime: start}, nil)
+		m.volumeRepo.On("Delete", anyCtx, "vol-old").Return(nil).Once()
+		m.volumeRepo.On("Create", anyCtx, mock.AnythingOfType("*domain.DailyVolume")).Return(nil).Once()
+
+		_, err := svc.AggregateSessionVolume(ctx, testScheduleID, "member-1", "tenant-1")
+
+		require.NoError(t, err)
+	})
+}
+
+func TestWorkoutService_GetExercisesBySchedule_ResolvesClientID(t *testing.T) {
+	// resolveScheduleID wraps the context in a tracing span
+	anyCtx := mock.Anything
+
+	t.Run("mongo id", func(t *testing.T) {
+		svc, m := newTestWorkoutService(t)
+		m.scheduleRepo.On("GetByID", anyCtx, testScheduleID).Return(&domain.Schedule{ID: testScheduleID}, nil)
+		m.sessionRepo.On("GetPlannedExercisesByScheduleID", anyCtx, testScheduleID).Return([]*domain.PlannedExercise{{ID: "pe-1"}}, nil)
+
+		exercises, err := svc.GetExercisesBySchedule(context.Background(), testScheduleID)
+
+		require.NoError(t, err)
+		assert.Len(t, exercises, 1)
+	})
+
+	t.Run("ulid from a client that hasn't synced yet", func(t *testing.T) {
+		svc, m := newTestWorkoutService(t)
+		m.scheduleRepo.On("GetByClientID", anyCtx, testClientID).Return(&domain.Schedule{ID: testScheduleID}, nil)
+		m.sessionRepo.On("GetPlannedExercisesByScheduleID", anyCtx, testScheduleID).Return([]*domain.PlannedExercise{}, nil)
+
+		_, err := svc.GetExercisesBySchedule(context.Background(), testClientID)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("ulid server id", func(t *testing.T) {
+		serverID := "01J8ZK4R6T2W9Y3B5D7F1H0M2P"
+		svc, m := newTestWorkoutService(t)
+		m.scheduleRepo.On("GetByClientID", anyCtx, serverID).Return(nil, domain.ErrScheduleNotFound)
+		m.scheduleRepo.On("GetByID", anyCtx, serverID).Return(&domain.Schedule{ID: serverID}, nil)
+		m.sessionRepo.On("GetPlannedExercisesByScheduleID", anyCtx, serverID).Return([]*domain.PlannedExercise{}, nil)
+
+		_, err := svc.GetExercisesBySchedule(context.Background(), serverID)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("unknown id", func(t *testing.T) {
+		svc, m := newTestWorkoutService(t)
+		m.scheduleRepo.On("GetByClientID", anyCtx, "nope").Return(nil, domain.ErrScheduleNotFound)
+		m.scheduleRepo.On("GetByID", anyCtx, "nope").Return(nil, domain.ErrInvalidID)
+
+		_, err := svc.GetExercisesBySchedule(context.Background(), "nope")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestWorkoutService_AddExerciseToSession_TenantExercises(t *testing.T) {
+	ctx := context.Background()
+	exerciseID := "65a1b2c3d4e5f60718293a4c"
+	schedule := &domain.Schedule{ID: testScheduleID, TenantID: "tenant-1"}
+
+	t.Run("rejects another tenant's private exercise", func(t *testing.T) {
+		svc, m := newTestWorkoutService(t)
+		m.scheduleRepo.On("GetByID", anyCtx, testScheduleID).Return(schedule, nil)
+		m.exerciseRepo.On("GetByID", anyCtx, exerciseID).Return(&domain.Exercise{ID: exerciseID, TenantID: "tenant-2"}, nil)
+
+		_, err := svc.AddExerciseToSession(ctx, testScheduleID, exerciseID, "", 3, 8, 90, "", 1)
+
+		assert.ErrorIs(t, err, domain.ErrExerciseNotFound)
+	})
+
+	t.Run("accepts the tenant's own exercise", func(t *testing.T) {
+		svc, m := newTestWorkoutService(t)
+		m.scheduleRepo.On("GetByID", anyCtx, testScheduleID).Return(schedule, nil)
+		m.exerciseRepo.On("GetByID", anyCtx, exerciseID).Return(&domain.Exercise{ID: exerciseID, TenantID: "tenant-1", Name: "House Squat"}, nil)
+		m.sessionRepo.On("AddPlannedExercise", anyCtx, mock.AnythingOfType("*domain.PlannedExercise")).Return(nil)
+
+		planned, err := svc.AddExerciseToSession(ctx, testScheduleID, exerciseID, "", 3, 8, 90, "", 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, "House Squat", planned.Name)
+	})
+}
+
+func TestWorkoutService_AddSetToExercise(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newTestWorkoutService(t)
+	m.sessionRepo.On("GetPlannedExerciseByClientID", anyCtx, testClientID).Return(&domain.PlannedExercise{ID: "pe-1", ScheduleID: testScheduleID, ExerciseID: "squat"}, nil)
+	m.scheduleRepo.On("GetByID", anyCtx, testScheduleID).Return(&domain.Schedule{ID: testScheduleID, MemberID: "member-1"}, nil)
+	m.setLogRepo.On("GetByPlannedExerciseID", anyCtx, "pe-1").Return([]*domain.SetLogDocument{{}, {}}, nil)
+	m.setLogRepo.On("Create", anyCtx, mock.AnythingOfType("*domain.SetLogDocument")).Return(nil)
+
+	setLog, err := svc.AddSetToExercise(ctx, testClientID, "", 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, setLog.SetIndex, "appended after the existing sets")
+	assert.Equal(t, "member-1", setLog.MemberID)
+	assert.NotEmpty(t, setLog.ClientID)
+}
+
+func TestWorkoutService_DeleteSetLog(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("soft deletes", func(t *testing.T) {
+		svc, m := newTestWorkoutService(t)
+		m.setLogRepo.On("GetByClientID", anyCtx, testClientID).Return(&domain.SetLogDocument{ID: "set-1"}, nil)
+		m.setLogRepo.On("SoftDelete", anyCtx, "set-1").Return(nil)
+
+		require.NoError(t, svc.DeleteSetLog(ctx, testClientID))
+	})
+
+	t.Run("is idempotent for unknown sets", func(t *testing.T) {
+		svc, m := newTestWorkoutService(t)
+		m.setLogRepo.On("GetByClientID", anyCtx, testClientID).Return(nil, domain.ErrSessionNotFound)
+
+		require.NoError(t, svc.DeleteSetLog(ctx, testClientID))
+	})
+
+	t.Run("surfaces repository errors", func(t *testing.T) {
+		svc, m := newTestWorkoutService(t)
+		m.setLogRepo.On("GetByClientID", anyCtx, testClientID).Return(nil, errors.New("boom"))
+
+		assert.Error(t, svc.DeleteSetLog(ctx, testClientID))
+	})
+}
+
+func TestWorkoutService_LastPerformance(t *testing.T) {
+	ctx := context.Background()
+	previousID := "5f1d7f3e9b1e8a0001a2b3c4"
+	previousStart := testNow.AddDate(0, 0, -3)
+	schedule := &domain.Schedule{ID: testScheduleID, TenantID: "tenant-1", CoachID: "coach-1", MemberID: "member-1"}
+
+	t.Run("returns the sets of the previous session", func(t *testing.T) {
+		svc, m := newTestWorkoutService(t)
+		m.scheduleRepo.On("GetByID", anyCtx, testScheduleID).Return(schedule, nil)
+		m.scheduleRepo.On("GetByID", anyCtx, previousID).Return(&domain.Schedule{ID: previousID, StartTime: previousStart}, nil)
+		m.setLogRepo.On("GetLastPerformance", anyCtx, "member-1", "squat", testScheduleID).Return([]*domain.SetLogDocument{
+			{ScheduleID: previousID, SetIndex: 1, Weight: 100, Reps: 5, Completed: true},
+			{ScheduleID: previousID, SetIndex: 2, Weight: 100, Reps: 4, Completed: true},
+		}, nil)
+
+		last, err := svc.LastPerformance(ctx, "tenant-1", "coach-1", domain.RoleCoach, testScheduleID, "squat")
+
+		require.NoError(t, err)
+		assert.Equal(t, previousID, last.ScheduleID)
+		assert.Equal(t, previousStart, *last.PerformedAt)
+		assert.Len(t, last.Sets, 2)
+	})
+
+	t.Run("first time doing the exercise", func(t *testing.T) {
+		svc, m := newTestWorkoutService(t)
+		m.scheduleRepo.On("GetByID", anyCtx, testScheduleID).Return(schedule, nil)
+		m.setLogRepo.On("GetLastPerformance", anyCtx, "member-1", "squat", testScheduleID).Return(nil, nil)
+
+		last, err := svc.LastPerformance(ctx, "tenant-1", "admin-1", domain.RoleTenantAdmin, testScheduleID, "squat")
+
+		require.NoError(t, err)
+		assert.Empty(t, last.ScheduleID)
+		assert.NotNil(t, last.Sets)
+		assert.Empty(t, last.Sets)
+	})
+
+	t.Run("hides the schedules of other tenants and coaches", func(t *testing.T) {
+		for name, caller := range map[string]struct{ tenantID, userID, role string }{
+			"admin of another tenant": {"tenant-2", "admin-2", domain.RoleTenantAdmin},
+			"coach of another tenant": {"tenant-2", "coach-1", domain.RoleCoach},
+			"another coach":           {"tenant-1", "coach-2", domain.RoleCoach},
+		} {
+			t.Run(name, func(t *testing.T) {
+				svc, m := newTestWorkoutService(t)
+				m.scheduleRepo.On("GetByID", anyCtx, testScheduleID).Return(schedule, nil)
+
+				_, err := svc.LastPerformance(ctx, caller.tenantID, caller.userID, caller.role, testScheduleID, "squat")
+
+				assert.ErrorIs(t, err, domain.ErrScheduleNotFound)
+				m.setLogRepo.AssertNotCalled(t, "GetLastPerformance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			})
+		}
+	})
+}
@@ -50,6 +50,11 @@ func FiberMiddleware() fiber.Handler {
 		// Call next handler
 		err := c.Next()
 
+		// Auth middleware has resolved the tenant by now
+		if tenantID, ok := c.Locals("tenant_id").(string); ok && tenantID != "" {
+			span.SetAttributes(TenantID(tenantID))
+		}
+
 		// Record response status
 		statusCode := c.Response().StatusCode()
 		span.SetAttributes(
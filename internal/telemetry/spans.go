@@ -0,0 +1,100 @@
+package telemetry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StartSpan starts a child of the span carried by ctx (usually the HTTP request span).
+// When tracing is disabled the global tracer is a no-op, so this is cheap to call anywhere.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan marks the span as failed when err is set, then ends it
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TenantID is the tenant_id span attribute
+func TenantID(id string) attribute.KeyValue {
+	return attribute.String("tenant_id", id)
+}
+
+// MemberID is the member_id span attribute. The ID is hashed: traces leave the platform
+// for the tracing vendor, and a hash still lets one member's requests be grouped.
+func MemberID(id string) attribute.KeyValue {
+	return attribute.String("member_id", HashID(id))
+}
+
+// HashID returns a short, stable, non-reversible form of an identifier
+func HashID(id string) string {
+	if id == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:8])
+}
+
+// RedisHook traces every Redis command. Only the command name is recorded: keys and
+// arguments contain user IDs and cached payloads.
+type RedisHook struct {
+	tracer trace.Tracer
+}
+
+func NewRedisHook() *RedisHook {
+	return &RedisHook{tracer: otel.Tracer(tracerName)}
+}
+
+func (h *RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		ctx, span := h.start(ctx, "redis dial")
+		conn, err := next(ctx, network, addr)
+		EndSpan(span, err)
+		return conn, err
+	}
+}
+
+func (h *RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := h.start(ctx, "redis "+cmd.Name(), attribute.String("db.operation", cmd.Name()))
+		err := next(ctx, cmd)
+		EndSpan(span, redisError(err))
+		return err
+	}
+}
+
+func (h *RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := h.start(ctx, "redis pipeline", attribute.Int("db.redis.num_cmd", len(cmds)))
+		err := next(ctx, cmds)
+		EndSpan(span, redisError(err))
+		return err
+	}
+}
+
+func (h *RedisHook) start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.String("db.system", "redis"))
+	return h.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// redisError ignores cache misses, which are expected and not failures
+func redisError(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
@@ -38,7 +38,7 @@ SUPER_ADMIN_RESPONSE=$(curl -s -X POST "$API_URL/v1/auth/login" \
   -H "Content-Type: application/json" \
   -d '{"requested_role": "super_admin"}')
 
-SUPER_ADMIN_TOKEN=$(echo $SUPER_ADMIN_RESPONSE | jq -r '.token')
+SUPER_ADMIN_TOKEN=$(echo $SUPER_ADMIN_RESPONSE | jq -r '.data.token')
 echo "✅ Logged in as super_admin"
 
 # Step 3: Create HOM Fitness tenant
@@ -52,8 +52,8 @@ TENANT_RESPONSE=$(curl -s -X POST "$API_URL/v1/platform/tenants" \
     "logo_url": "https://homfitness.com/logo.png"
   }')
 
-TENANT_ID=$(echo $TENANT_RESPONSE | jq -r '.id')
-TENANT_NAME=$(echo $TENANT_RESPONSE | jq -r '.name')
+TENANT_ID=$(echo $TENANT_RESPONSE | jq -r '.data.id')
+TENANT_NAME=$(echo $TENANT_RESPONSE | jq -r '.data.name')
 echo "✅ Tenant created: $TENANT_NAME (ID: $TENANT_ID)"
 
 # Step 4: Pre-provision tenant_admin (EMAIL ONLY)
@@ -71,8 +71,8 @@ ADMIN_CREATE_RESPONSE=$(curl -s -X POST "$API_URL/v1/platform/users" \
     "tenant_id": "'$TENANT_ID'"
   }')
 
-ADMIN_USER_ID=$(echo $ADMIN_CREATE_RESPONSE | jq -r '.id')
-ADMIN_PRE_FIREBASE_UID=$(echo $ADMIN_CREATE_RESPONSE | jq -r '.firebase_uid')
+ADMIN_USER_ID=$(echo $ADMIN_CREATE_RESPONSE | jq -r '.data.id')
+ADMIN_PRE_FIREBASE_UID=$(echo $ADMIN_CREATE_RESPONSE | jq -r '.data.firebase_uid')
 
 if [ "$ADMIN_USER_ID" = "null" ]; then
     echo "❌ User creation failed!"
@@ -116,12 +116,12 @@ ADMIN_LOGIN_RESPONSE=$(curl -s -X POST "$API_URL/v1/auth/login" \
   -H "Content-Type: application/json" \
   -d '{"requested_role": "tenant_admin"}')
 
-ADMIN_TOKEN=$(echo $ADMIN_LOGIN_RESPONSE | jq -r '.token')
-ADMIN_LINKED_ID=$(echo $ADMIN_LOGIN_RESPONSE | jq -r '.user.id')
-ADMIN_LINKED_FIREBASE_UID=$(echo $ADMIN_LOGIN_RESPONSE | jq -r '.user.firebase_uid')
-ADMIN_LINKED_TENANT_ID=$(echo $ADMIN_LOGIN_RESPONSE | jq -r '.user.tenant_id')
-ADMIN_LINKED_ROLES=$(echo $ADMIN_LOGIN_RESPONSE | jq -r '.user.roles | join(", ")')
-IS_NEW_USER=$(echo $ADMIN_LOGIN_RESPONSE | jq -r '.is_new_user')
+ADMIN_TOKEN=$(echo $ADMIN_LOGIN_RESPONSE | jq -r '.data.token')
+ADMIN_LINKED_ID=$(echo $ADMIN_LOGIN_RESPONSE | jq -r '.data.user.id')
+ADMIN_LINKED_FIREBASE_UID=$(echo $ADMIN_LOGIN_RESPONSE | jq -r '.data.user.firebase_uid')
+ADMIN_LINKED_TENANT_ID=$(echo $ADMIN_LOGIN_RESPONSE | jq -r '.data.user.tenant_id')
+ADMIN_LINKED_ROLES=$(echo $ADMIN_LOGIN_RESPONSE | jq -r '.data.user.roles | join(", ")')
+IS_NEW_USER=$(echo $ADMIN_LOGIN_RESPONSE | jq -r '.data.is_new_user')
 
 echo "✅ Tenant admin logged in"
 echo ""
@@ -32,7 +32,7 @@ SUPER_ADMIN_RESPONSE=$(curl -s -X POST "$API_URL/v1/auth/login" \
   -H "Content-Type: application/json" \
   -d '{"requested_role": "super_admin"}')
 
-SUPER_ADMIN_TOKEN=$(echo $SUPER_ADMIN_RESPONSE | jq -r '.token')
+SUPER_ADMIN_TOKEN=$(echo $SUPER_ADMIN_RESPONSE | jq -r '.data.token')
 
 if [ -z "$SUPER_ADMIN_TOKEN" ] || [ "$SUPER_ADMIN_TOKEN" = "null" ]; then
     echo "❌ Failed to get super_admin token"
@@ -60,8 +60,8 @@ TENANT_RESPONSE=$(curl -s -X POST "$API_URL/v1/platform/tenants" \
     }
   }')
 
-TENANT_ID=$(echo $TENANT_RESPONSE | jq -r '.id')
-TENANT_NAME=$(echo $TENANT_RESPONSE | jq -r '.name')
+TENANT_ID=$(echo $TENANT_RESPONSE | jq -r '.data.id')
+TENANT_NAME=$(echo $TENANT_RESPONSE | jq -r '.data.name')
 
 if [ -z "$TENANT_ID" ] || [ "$TENANT_ID" = "null" ]; then
     echo "❌ Failed to create tenant"
@@ -79,7 +79,7 @@ echo "📝 Step 4a: Checking for existing user with email $ADMIN_EMAIL..."
 ALL_USERS=$(curl -s -X GET "$API_URL/v1/platform/users" \
   -H "Authorization: Bearer $SUPER_ADMIN_TOKEN")
 
-EXISTING_USER_ID=$(echo $ALL_USERS | jq -r '.[] | select(.email == "'$ADMIN_EMAIL'") | .id')
+EXISTING_USER_ID=$(echo $ALL_USERS | jq -r '.data[] | select(.email == "'$ADMIN_EMAIL'") | .id')
 
 if [ -n "$EXISTING_USER_ID" ] && [ "$EXISTING_USER_ID" != "null" ]; then
     echo "   Found existing user (ID: $EXISTING_USER_ID), deleting..."
@@ -105,8 +105,8 @@ ADMIN_CREATE_RESPONSE=$(curl -s -X POST "$API_URL/v1/platform/users" \
     "tenant_id": "'$TENANT_ID'"
   }')
 
-ADMIN_USER_ID=$(echo $ADMIN_CREATE_RESPONSE | jq -r '.id')
-ADMIN_FIREBASE_UID=$(echo $ADMIN_CREATE_RESPONSE | jq -r '.firebase_uid')
+ADMIN_USER_ID=$(echo $ADMIN_CREATE_RESPONSE | jq -r '.data.id')
+ADMIN_FIREBASE_UID=$(echo $ADMIN_CREATE_RESPONSE | jq -r '.data.firebase_uid')
 
 if [ "$ADMIN_USER_ID" = "null" ] || [ -z "$ADMIN_USER_ID" ]; then
     echo "❌ User creation failed!"
@@ -35,8 +35,8 @@ MEMBER_RESPONSE=$(curl -s -X POST "$API_URL/v1/auth/login" \
   -H "Content-Type: application/json" \
   -d '{"requested_role": "member"}')
 
-MEMBER_TOKEN=$(echo $MEMBER_RESPONSE | jq -r '.token')
-MEMBER_ID=$(echo $MEMBER_RESPONSE | jq -r '.user.id')
+MEMBER_TOKEN=$(echo $MEMBER_RESPONSE | jq -r '.data.token')
+MEMBER_ID=$(echo $MEMBER_RESPONSE | jq -r '.data.user.id')
 
 if [ -z "$MEMBER_TOKEN" ] || [ "$MEMBER_TOKEN" = "null" ]; then
     echo "❌ Failed to get member token"
@@ -102,8 +102,8 @@ COACH_RESPONSE=$(curl -s -X POST "$API_URL/v1/auth/login" \
   -H "Content-Type: application/json" \
   -d '{"requested_role": "coach"}')
 
-COACH_TOKEN=$(echo $COACH_RESPONSE | jq -r '.token')
-COACH_ROLES=$(echo $COACH_RESPONSE | jq -r '.user.roles')
+COACH_TOKEN=$(echo $COACH_RESPONSE | jq -r '.data.token')
+COACH_ROLES=$(echo $COACH_RESPONSE | jq -r '.data.user.roles')
 
 if [ -z "$COACH_TOKEN" ] || [ "$COACH_TOKEN" = "null" ]; then
     echo "❌ Failed to get coach token"
@@ -134,7 +134,7 @@ echo ""
 # Test 7: Multi-Role User (Member + Coach)
 echo "📝 Test 7: Multi-Role User"
 echo "--------------------------"
-MULTI_ROLES=$(echo $COACH_RESPONSE | jq -r '.user.roles | length')
+MULTI_ROLES=$(echo $COACH_RESPONSE | jq -r '.data.user.roles | length')
 if [ "$MULTI_ROLES" -gt 1 ]; then
     echo "✅ User now has multiple roles: $COACH_ROLES"
     
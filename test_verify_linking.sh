@@ -42,11 +42,11 @@ LOGIN_RESPONSE=$(curl -s -X POST "$API_URL/v1/auth/login" \
   -H "Content-Type: application/json" \
   -d '{"requested_role": "tenant_admin"}')
 
-USER_ID_RESPONSE=$(echo $LOGIN_RESPONSE | jq -r '.user.id')
-FIREBASE_UID_RESPONSE=$(echo $LOGIN_RESPONSE | jq -r '.user.firebase_uid')
-TENANT_ID_RESPONSE=$(echo $LOGIN_RESPONSE | jq -r '.user.tenant_id')
-ROLES_RESPONSE=$(echo $LOGIN_RESPONSE | jq -r '.user.roles | join(", ")')
-IS_NEW=$(echo $LOGIN_RESPONSE | jq -r '.is_new_user')
+USER_ID_RESPONSE=$(echo $LOGIN_RESPONSE | jq -r '.data.user.id')
+FIREBASE_UID_RESPONSE=$(echo $LOGIN_RESPONSE | jq -r '.data.user.firebase_uid')
+TENANT_ID_RESPONSE=$(echo $LOGIN_RESPONSE | jq -r '.data.user.tenant_id')
+ROLES_RESPONSE=$(echo $LOGIN_RESPONSE | jq -r '.data.user.roles | join(", ")')
+IS_NEW=$(echo $LOGIN_RESPONSE | jq -r '.data.is_new_user')
 
 echo "✅ Logged in"
 echo ""
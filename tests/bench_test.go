@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/mansoorceksport/metamorph/tests/scenario"
+)
+
+// BenchmarkHotEndpoints times the endpoints the apps call most against a seeded gym, reporting
+// p50/p95/p99 alongside ns/op. Needs Docker for MongoDB; run with `make bench`.
+// The gym is seeded once and shared by the sub-benchmarks.
+func BenchmarkHotEndpoints(b *testing.B) {
+	env := scenario.NewEnv(b, scenario.StartMongo(b), scenario.StartRedis(b))
+	data := env.SeedLoad(scenario.DefaultLoadProfile)
+	coach := data.Coaches[0]
+	clients := coach.Clients
+
+	// do sends a request and fails the benchmark on an unexpected status
+	do := func(b *testing.B, method, path, token string, body interface{}, want int) {
+		resp := env.Do(method, path, token, body)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			b.Fatalf("%s %s: status %d, want %d", method, path, resp.StatusCode, want)
+		}
+	}
+
+	// run times one call per iteration, rotating through the seeded clients
+	run := func(name string, call func(b *testing.B, client scenario.LoadClient, i int)) {
+		b.Run(name, func(b *testing.B) {
+			var latencies scenario.Latencies
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				client := clients[i%len(clients)]
+				latencies.Time(func() { call(b, client, i) })
+			}
+			b.StopTimer()
+			latencies.Report(b)
+		})
+	}
+
+	// The member dashboard is cached in Redis, so this mostly measures the warm path
+	run("me_dashboard", func(b *testing.B, client scenario.LoadClient, _ int) {
+		do(b, http.MethodGet, "/v1/me/dashboard", client.Token, nil, http.StatusOK)
+	})
+
+	run("pro_clients", func(b *testing.B, _ scenario.LoadClient, _ int) {
+		do(b, http.MethodGet, "/v1/pro/clients", coach.Token, nil, http.StatusOK)
+	})
+
+	run("pro_add_set", func(b *testing.B, client scenario.LoadClient, i int) {
+		do(b, http.MethodPost, "/v1/pro/exercises/"+client.PlannedExerciseID+"/sets", coach.Token,
+			map[string]interface{}{"set_index": i + 1}, http.StatusCreated)
+	})
+
+	run("pro_update_set", func(b *testing.B, client scenario.LoadClient, i int) {
+		do(b, http.MethodPut, "/v1/pro/sets/"+client.SetID, coach.Token,
+			map[string]interface{}{"weight": 60 + float64(i%10), "reps": 8, "completed": true}, http.StatusOK)
+	})
+
+	run("pro_schedule_sets", func(b *testing.B, client scenario.LoadClient, _ int) {
+		do(b, http.MethodGet, fmt.Sprintf("/v1/pro/schedules/%s/sets", client.ScheduleID), coach.Token, nil, http.StatusOK)
+	})
+}
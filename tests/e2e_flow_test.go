@@ -12,10 +12,10 @@ import (
 	"github.com/alicebob/miniredis/v2"
 	"github.com/mansoorceksport/metamorph/internal/config"
 	"github.com/mansoorceksport/metamorph/internal/server"
+	"github.com/mansoorceksport/metamorph/tests/scenario"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Define request/response structs inline or reuse from domain/dto if available.
@@ -25,8 +25,7 @@ import (
 func TestGoldenPath(t *testing.T) {
 	// 1. Setup Infrastructure
 	// MongoDB (Container)
-	db, cleanupDB := SetupTestDB(t)
-	defer cleanupDB()
+	db := scenario.StartMongo(t)
 
 	// Redis (Miniredis for speed/simplicity, or Container)
 	mr, err := miniredis.Run()
@@ -38,7 +37,7 @@ func TestGoldenPath(t *testing.T) {
 	})
 
 	// Mock Auth
-	mockAuth := NewMockAuthClient()
+	mockAuth := scenario.NewMockAuthClient()
 
 	// Config (Minimal)
 	cfg := &config.Config{}
@@ -71,6 +70,15 @@ func TestGoldenPath(t *testing.T) {
 		return resp
 	}
 
+	// decode reads the data of a response envelope into out
+	decode := func(resp *http.Response, out interface{}) {
+		var body struct {
+			Data json.RawMessage `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		require.NoError(t, json.Unmarshal(body.Data, out))
+	}
+
 	// ==========================================
 	// STEP 1: Super Admin Login & Setup
 	// ==========================================
@@ -101,7 +109,7 @@ func TestGoldenPath(t *testing.T) {
 	assert.Equal(t, 200, resp.StatusCode)
 
 	var loginData map[string]interface{}
-	json.NewDecoder(resp.Body).Decode(&loginData)
+	decode(resp, &loginData)
 	superToken := loginData["token"].(string)
 	require.NotEmpty(t, superToken)
 
@@ -118,7 +126,7 @@ func TestGoldenPath(t *testing.T) {
 	assert.Equal(t, 201, resp.StatusCode)
 
 	var tenantData map[string]interface{}
-	json.NewDecoder(resp.Body).Decode(&tenantData)
+	decode(resp, &tenantData)
 	tenantID := tenantData["id"].(string)
 	require.NotEmpty(t, tenantID)
 
@@ -135,7 +143,7 @@ func TestGoldenPath(t *testing.T) {
 	assert.Equal(t, 201, resp.StatusCode)
 
 	var branchData map[string]interface{}
-	json.NewDecoder(resp.Body).Decode(&branchData)
+	decode(resp, &branchData)
 	branchID := branchData["id"].(string)
 	require.NotEmpty(t, branchID)
 
@@ -164,7 +172,7 @@ func TestGoldenPath(t *testing.T) {
 	resp = request("POST", "/v1/auth/login", "token_tenant_admin", nil)
 	assert.Equal(t, 200, resp.StatusCode)
 
-	json.NewDecoder(resp.Body).Decode(&loginData)
+	decode(resp, &loginData)
 	adminToken := loginData["token"].(string)
 	require.NotEmpty(t, adminToken)
 
@@ -181,7 +189,7 @@ func TestGoldenPath(t *testing.T) {
 	assert.Equal(t, 201, resp.StatusCode)
 
 	var coachData map[string]interface{}
-	json.NewDecoder(resp.Body).Decode(&coachData)
+	decode(resp, &coachData)
 	coachID := coachData["id"].(string)
 
 	fmt.Println("✓ Coach Created")
@@ -196,7 +204,7 @@ func TestGoldenPath(t *testing.T) {
 	assert.Equal(t, 201, resp.StatusCode)
 
 	var memberData map[string]interface{}
-	json.NewDecoder(resp.Body).Decode(&memberData)
+	decode(resp, &memberData)
 	memberID := memberData["id"].(string)
 
 	fmt.Println("✓ Member Created")
@@ -213,7 +221,7 @@ func TestGoldenPath(t *testing.T) {
 	assert.Equal(t, 201, resp.StatusCode)
 
 	var pkgData map[string]interface{}
-	json.NewDecoder(resp.Body).Decode(&pkgData)
+	decode(resp, &pkgData)
 	pkgID := pkgData["id"].(string)
 	fmt.Println("✓ Package Template Created:", pkgID)
 
@@ -229,7 +237,7 @@ func TestGoldenPath(t *testing.T) {
 	assert.Equal(t, 201, resp.StatusCode)
 
 	var contractData map[string]interface{}
-	json.NewDecoder(resp.Body).Decode(&contractData)
+	decode(resp, &contractData)
 	contractID := contractData["id"].(string)
 
 	fmt.Println("✓ Contract Created (Coach Assigned):", contractID)
@@ -245,7 +253,7 @@ func TestGoldenPath(t *testing.T) {
 	resp = request("POST", "/v1/auth/login", "token_coach", nil)
 	assert.Equal(t, 200, resp.StatusCode)
 
-	json.NewDecoder(resp.Body).Decode(&loginData)
+	decode(resp, &loginData)
 
 	loggedInUser := loginData["user"].(map[string]interface{})
 	loggedInID := loggedInUser["id"].(string)
@@ -277,7 +285,7 @@ func TestGoldenPath(t *testing.T) {
 	assert.Equal(t, 200, resp.StatusCode)
 
 	var clients []map[string]interface{}
-	json.NewDecoder(resp.Body).Decode(&clients)
+	decode(resp, &clients)
 
 	found := false
 	for _, c := range clients {
@@ -302,7 +310,7 @@ func TestGoldenPath(t *testing.T) {
 	// Expect success
 	assert.Equal(t, 201, resp.StatusCode)
 	var scheduleData map[string]interface{}
-	json.NewDecoder(resp.Body).Decode(&scheduleData)
+	decode(resp, &scheduleData)
 	scheduleID := scheduleData["id"].(string)
 	fmt.Println("✓ Schedule Created:", scheduleID)
 
@@ -318,7 +326,7 @@ func TestGoldenPath(t *testing.T) {
 	})
 	assert.Equal(t, 201, resp.StatusCode)
 	var exerciseData map[string]interface{}
-	json.NewDecoder(resp.Body).Decode(&exerciseData)
+	decode(resp, &exerciseData)
 	exID := exerciseData["id"].(string)
 
 	resp = request("POST", "/v1/templates", superToken, map[string]interface{}{
@@ -328,7 +336,7 @@ func TestGoldenPath(t *testing.T) {
 	})
 	assert.Equal(t, 201, resp.StatusCode)
 	var templateData map[string]interface{}
-	json.NewDecoder(resp.Body).Decode(&templateData)
+	decode(resp, &templateData)
 	tplID := templateData["id"].(string)
 
 	fmt.Println("✓ Master Data Created")
@@ -343,7 +351,7 @@ func TestGoldenPath(t *testing.T) {
 	})
 	assert.Equal(t, 201, resp.StatusCode)
 	var sessionData map[string]interface{}
-	json.NewDecoder(resp.Body).Decode(&sessionData)
+	decode(resp, &sessionData)
 	sessionID := sessionData["id"].(string)
 
 	// Verify Deep Copy
@@ -356,7 +364,7 @@ func TestGoldenPath(t *testing.T) {
 		"exercise_id": exID,
 	})
 	assert.Equal(t, 200, resp.StatusCode)
-	json.NewDecoder(resp.Body).Decode(&sessionData)
+	decode(resp, &sessionData)
 	planned = sessionData["planned_exercises"].([]interface{})
 	assert.Len(t, planned, 2) // Added one
 
@@ -381,9 +389,9 @@ func TestGoldenPath(t *testing.T) {
 
 	// Verify Contract Decrement
 	var contractDoc map[string]interface{}
-	objID, _ := primitive.ObjectIDFromHex(contractID)
+	// New contracts are keyed by ULID strings
 	err = db.Collection("pt_contracts").FindOne(context.Background(), map[string]interface{}{
-		"_id": objID,
+		"_id": contractID,
 	}).Decode(&contractDoc)
 	require.NoError(t, err)
 
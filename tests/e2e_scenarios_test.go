@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/tests/scenario"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoachRosterCreditsAndScans(t *testing.T) {
+	env := scenario.NewEnv(t, scenario.StartMongo(t), scenario.StartRedis(t))
+
+	gym := env.CreateTenantWithBranch("Scenario Gym")
+	roster := gym.CreateCoachWithClients(3)
+	client := roster.Clients[0]
+
+	// Coach sees every contracted client
+	var clients []map[string]interface{}
+	env.MustJSON(http.MethodGet, "/v1/pro/clients", roster.Coach.Token, nil, http.StatusOK, &clients)
+	assert.Len(t, clients, 3)
+
+	// Completing a session consumes exactly one credit, even if "Complete" is sent twice
+	scheduleID := roster.BookSession(env, client, time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))
+	roster.CompleteSession(env, scheduleID)
+	resp := env.Do(http.MethodPost, "/v1/pro/schedules/"+scheduleID+"/complete", roster.Coach.Token, nil)
+	assert.NotEqual(t, http.StatusOK, resp.StatusCode)
+
+	var statement struct {
+		Summary struct {
+			Purchased int `json:"purchased"`
+			Consumed  int `json:"consumed"`
+			Balance   int `json:"balance"`
+		} `json:"summary"`
+	}
+	env.MustJSON(http.MethodGet, "/v1/me/contracts/"+client.ContractID+"/statement", client.Member.Token, nil, http.StatusOK, &statement)
+	assert.Equal(t, 10, statement.Summary.Purchased)
+	assert.Equal(t, 1, statement.Summary.Consumed)
+	assert.Equal(t, 9, statement.Summary.Balance)
+
+	// Seeded scans are visible to the coach
+	ids := env.SeedScans(client.Member, 6)
+	require.Len(t, ids, 6)
+
+	var scans []map[string]interface{}
+	env.MustJSON(http.MethodGet, "/v1/pro/members/"+client.Member.ID+"/scans", roster.Coach.Token, nil, http.StatusOK, &scans)
+	assert.Len(t, scans, 6)
+}
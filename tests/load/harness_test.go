@@ -0,0 +1,116 @@
+// Package load serves the API over HTTP against a seeded gym for k6 and vegeta scenarios.
+// See tests/load/README.md; `make load-k6` and `make load-vegeta` drive it.
+package load
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/tests/scenario"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadHarness seeds a gym and serves the API on LOAD_HARNESS_ADDR until interrupted or
+// LOAD_HARNESS_DURATION (default 30m) passes. It writes to LOAD_OUT_DIR:
+//   - seed.json: the seeded tokens and IDs (scenario.LoadData), read by the k6 script
+//   - <endpoint>.targets: vegeta targets, one file per endpoint, rendered from vegeta/*.targets.tmpl
+//   - ready: created last, once the server accepts requests
+//
+// Skipped unless LOAD_HARNESS_ADDR is set.
+func TestLoadHarness(t *testing.T) {
+	addr := os.Getenv("LOAD_HARNESS_ADDR")
+	if addr == "" {
+		t.Skip("LOAD_HARNESS_ADDR not set")
+	}
+	outDir := os.Getenv("LOAD_OUT_DIR")
+	if outDir == "" {
+		outDir = t.TempDir()
+	}
+	duration := 30 * time.Minute
+	if raw := os.Getenv("LOAD_HARNESS_DURATION"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		require.NoError(t, err)
+		duration = d
+	}
+	require.NoError(t, os.MkdirAll(outDir, 0o755))
+
+	env := scenario.NewEnv(t, scenario.StartMongo(t), scenario.StartRedis(t))
+	data := env.SeedLoad(scenario.DefaultLoadProfile)
+
+	seed, err := json.MarshalIndent(data, "", "  ")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(outDir, "seed.json"), seed, 0o644))
+	require.NoError(t, renderTargets(outDir, "http://"+addr, data))
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- env.App.Listen(addr) }()
+	t.Cleanup(func() { _ = env.App.Shutdown() })
+
+	waitForServer(t, "http://"+addr+"/health")
+	require.NoError(t, os.WriteFile(filepath.Join(outDir, "ready"), nil, 0o644))
+	t.Logf("serving %s on %s for %s", outDir, addr, duration)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	select {
+	case err := <-serveErr:
+		require.NoError(t, err)
+	case <-stop:
+	case <-time.After(duration):
+	}
+}
+
+// renderTargets writes each vegeta target template with the seeded data
+func renderTargets(outDir, baseURL string, data *scenario.LoadData) error {
+	templates, err := filepath.Glob(filepath.Join("vegeta", "*.targets.tmpl"))
+	if err != nil {
+		return err
+	}
+	bodiesDir, err := filepath.Abs(filepath.Join("vegeta", "bodies"))
+	if err != nil {
+		return err
+	}
+	for _, path := range templates {
+		tmpl, err := template.ParseFiles(path)
+		if err != nil {
+			return err
+		}
+		f, err := os.Create(filepath.Join(outDir, filepath.Base(path[:len(path)-len(".tmpl")])))
+		if err != nil {
+			return err
+		}
+		err = tmpl.Execute(f, struct {
+			BaseURL   string
+			BodiesDir string
+			*scenario.LoadData
+		}{baseURL, bodiesDir, data})
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForServer polls url until it answers
+func waitForServer(t *testing.T, url string) {
+	t.Helper()
+	client := &http.Client{Timeout: time.Second}
+	require.Eventually(t, func() bool {
+		resp, err := client.Get(url)
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, 10*time.Second, 100*time.Millisecond)
+}
@@ -0,0 +1,90 @@
+// Hot endpoint scenarios for k6, run against the seeded load harness (tests/load/harness_test.go).
+//
+//   k6 run -e BASE_URL=http://127.0.0.1:8089 -e SEED_FILE=tests/load/out/seed.json tests/load/k6/hot_endpoints.js
+//
+// Each scenario tags its requests with an endpoint, so the summary reports p95 per endpoint.
+// The thresholds are the latency budgets: k6 exits non-zero when one is exceeded.
+import http from 'k6/http';
+import { check } from 'k6';
+
+const BASE_URL = __ENV.BASE_URL || 'http://127.0.0.1:8089';
+const seed = JSON.parse(open(__ENV.SEED_FILE || './seed.json'));
+const RATE = parseInt(__ENV.RATE || '50', 10);
+const DURATION = __ENV.DURATION || '30s';
+
+const coaches = seed.coaches;
+const clients = coaches.flatMap((coach) => coach.clients.map((client) => ({ ...client, coachToken: coach.token })));
+
+function constantRate(exec) {
+  return {
+    executor: 'constant-arrival-rate',
+    exec,
+    rate: RATE,
+    timeUnit: '1s',
+    duration: DURATION,
+    preAllocatedVUs: 20,
+    maxVUs: 100,
+  };
+}
+
+export const options = {
+  scenarios: {
+    me_dashboard: constantRate('meDashboard'),
+    pro_clients: constantRate('proClients'),
+    set_logging: constantRate('setLogging'),
+  },
+  summaryTrendStats: ['avg', 'p(50)', 'p(95)', 'p(99)', 'max'],
+  thresholds: {
+    'http_req_failed': ['rate<0.01'],
+    'http_req_duration{endpoint:me_dashboard}': ['p(95)<300'],
+    'http_req_duration{endpoint:pro_clients}': ['p(95)<500'],
+    'http_req_duration{endpoint:add_set}': ['p(95)<250'],
+    'http_req_duration{endpoint:update_set}': ['p(95)<250'],
+    'http_req_duration{endpoint:schedule_sets}': ['p(95)<250'],
+  },
+};
+
+function pick(items) {
+  return items[Math.floor(Math.random() * items.length)];
+}
+
+function auth(token, endpoint) {
+  return {
+    headers: { Authorization: `Bearer ${token}`, 'Content-Type': 'application/json' },
+    tags: { endpoint },
+  };
+}
+
+export function meDashboard() {
+  const client = pick(clients);
+  const res = http.get(`${BASE_URL}/v1/me/dashboard`, auth(client.token, 'me_dashboard'));
+  check(res, { 'dashboard 200': (r) => r.status === 200 });
+}
+
+export function proClients() {
+  const coach = pick(coaches);
+  const res = http.get(`${BASE_URL}/v1/pro/clients`, auth(coach.token, 'pro_clients'));
+  check(res, { 'clients 200': (r) => r.status === 200 });
+}
+
+// setLogging follows the coach app during a session: add a set, record it, reload the session
+export function setLogging() {
+  const client = pick(clients);
+
+  const added = http.post(
+    `${BASE_URL}/v1/pro/exercises/${client.planned_exercise_id}/sets`,
+    JSON.stringify({ set_index: 1 }),
+    auth(client.coachToken, 'add_set'),
+  );
+  check(added, { 'add set 201': (r) => r.status === 201 });
+
+  const updated = http.put(
+    `${BASE_URL}/v1/pro/sets/${client.set_id}`,
+    JSON.stringify({ weight: 62.5, reps: 8, completed: true }),
+    auth(client.coachToken, 'update_set'),
+  );
+  check(updated, { 'update set 200': (r) => r.status === 200 });
+
+  const sets = http.get(`${BASE_URL}/v1/pro/schedules/${client.schedule_id}/sets`, auth(client.coachToken, 'schedule_sets'));
+  check(sets, { 'schedule sets 200': (r) => r.status === 200 });
+}
@@ -0,0 +1,236 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// Gym is a tenant with one branch and a logged-in tenant admin
+type Gym struct {
+	env      *Env
+	TenantID string
+	BranchID string
+	JoinCode string
+	Admin    *Actor
+}
+
+// Client is a member with an active PT contract
+type Client struct {
+	Member     *Actor
+	ContractID string
+}
+
+// Roster is a coach and the clients contracted to them
+type Roster struct {
+	Coach     *Actor
+	PackageID string
+	Clients   []*Client
+}
+
+// CreateTenantWithBranch creates a tenant, a branch and a logged-in tenant admin
+func (e *Env) CreateTenantWithBranch(name string) *Gym {
+	e.T.Helper()
+	super := e.SuperAdmin()
+	n := e.next()
+
+	gym := &Gym{env: e, JoinCode: fmt.Sprintf("JOIN%04d", n)}
+
+	var tenant struct {
+		ID string `json:"id"`
+	}
+	e.MustJSON(http.MethodPost, "/v1/platform/tenants", super.Token, map[string]string{
+		"name":      name,
+		"slug":      fmt.Sprintf("gym-%d", n),
+		"join_code": gym.JoinCode,
+	}, http.StatusCreated, &tenant)
+	gym.TenantID = tenant.ID
+
+	var branch struct {
+		ID string `json:"id"`
+	}
+	e.MustJSON(http.MethodPost, "/v1/platform/branches", super.Token, map[string]interface{}{
+		"name":      name + " Main",
+		"tenant_id": gym.TenantID,
+		"location":  "Downtown",
+	}, http.StatusCreated, &branch)
+	gym.BranchID = branch.ID
+
+	adminEmail := fmt.Sprintf("admin%d@gym.test", n)
+	e.MustJSON(http.MethodPost, "/v1/platform/tenant-admins", super.Token, map[string]interface{}{
+		"email":     adminEmail,
+		"name":      name + " Owner",
+		"tenant_id": gym.TenantID,
+	}, http.StatusCreated, nil)
+	gym.Admin = e.Login(adminEmail)
+
+	return gym
+}
+
+// CreateCoach creates a coach at the gym's branch and logs them in
+func (g *Gym) CreateCoach() *Actor {
+	g.env.T.Helper()
+	n := g.env.next()
+
+	email := fmt.Sprintf("coach%d@gym.test", n)
+	g.env.MustJSON(http.MethodPost, "/v1/tenant-admin/coaches", g.Admin.Token, map[string]interface{}{
+		"email":          email,
+		"name":           fmt.Sprintf("Coach %d", n),
+		"home_branch_id": g.BranchID,
+	}, http.StatusCreated, nil)
+	return g.env.Login(email)
+}
+
+// CreateMember creates a member in the tenant and logs them in
+func (g *Gym) CreateMember() *Actor {
+	g.env.T.Helper()
+	n := g.env.next()
+
+	email := fmt.Sprintf("member%d@gym.test", n)
+	g.env.MustJSON(http.MethodPost, "/v1/tenant-admin/users", g.Admin.Token, map[string]interface{}{
+		"email":         email,
+		"name":          fmt.Sprintf("Member %d", n),
+		"branch_access": []string{g.BranchID},
+	}, http.StatusCreated, nil)
+	return g.env.Login(email)
+}
+
+// CreatePackage creates a PT package template at the gym's branch
+func (g *Gym) CreatePackage(sessions int, price float64) string {
+	g.env.T.Helper()
+
+	var pkg struct {
+		ID string `json:"id"`
+	}
+	g.env.MustJSON(http.MethodPost, "/v1/tenant-admin/packages", g.Admin.Token, map[string]interface{}{
+		"name":           fmt.Sprintf("%d Session Pack", sessions),
+		"total_sessions": sessions,
+		"price":          price,
+		"branch_id":      g.BranchID,
+	}, http.StatusCreated, &pkg)
+	return pkg.ID
+}
+
+// CreateContract sells a package to a member with the given coach
+func (g *Gym) CreateContract(packageID string, member, coach *Actor) string {
+	g.env.T.Helper()
+
+	var contract struct {
+		ID string `json:"id"`
+	}
+	g.env.MustJSON(http.MethodPost, "/v1/tenant-admin/contracts", g.Admin.Token, map[string]interface{}{
+		"package_id": packageID,
+		"member_id":  member.ID,
+		"coach_id":   coach.ID,
+		"branch_id":  g.BranchID,
+	}, http.StatusCreated, &contract)
+	return contract.ID
+}
+
+// CreateCoachWithClients creates a coach and n members, each with a 10-session contract with that coach
+func (g *Gym) CreateCoachWithClients(n int) *Roster {
+	g.env.T.Helper()
+
+	roster := &Roster{
+		Coach:     g.CreateCoach(),
+		PackageID: g.CreatePackage(10, 2000000),
+	}
+	for i := 0; i < n; i++ {
+		member := g.CreateMember()
+		roster.Clients = append(roster.Clients, &Client{
+			Member:     member,
+			ContractID: g.CreateContract(roster.PackageID, member, roster.Coach),
+		})
+	}
+	return roster
+}
+
+// BookSession schedules a one-hour session for a client, starting at start
+func (r *Roster) BookSession(env *Env, client *Client, start time.Time) string {
+	env.T.Helper()
+
+	var schedule struct {
+		ID string `json:"id"`
+	}
+	env.MustJSON(http.MethodPost, "/v1/pro/schedules", r.Coach.Token, map[string]interface{}{
+		"contract_id": client.ContractID,
+		"member_id":   client.Member.ID,
+		"start_time":  start.UTC().Format(time.RFC3339),
+		"end_time":    start.Add(time.Hour).UTC().Format(time.RFC3339),
+	}, http.StatusCreated, &schedule)
+	return schedule.ID
+}
+
+// CompleteSession marks a booked session as completed by the roster's coach
+func (r *Roster) CompleteSession(env *Env, scheduleID string) {
+	env.T.Helper()
+	env.MustJSON(http.MethodPost, "/v1/pro/schedules/"+scheduleID+"/complete", r.Coach.Token, nil, http.StatusOK, nil)
+}
+
+// SeedScans inserts n InBody scans for a member, one every two weeks ending today.
+// Scans are written straight to the repository since digitizing needs the AI provider.
+func (e *Env) SeedScans(member *Actor, n int) []string {
+	e.T.Helper()
+
+	repo := repository.NewMongoInBodyRepository(e.DB)
+	ids := make([]string, 0, n)
+	for _, record := range ScanSeries(member.ID, n, time.Now().AddDate(0, 0, -14*(n-1)), 14*24*time.Hour) {
+		require.NoError(e.T, repo.Create(context.Background(), record))
+		ids = append(ids, record.ID)
+	}
+	return ids
+}
+
+// CreateExercise adds an exercise to the library as the given coach
+func (e *Env) CreateExercise(coach *Actor, name, muscleGroup string) string {
+	e.T.Helper()
+
+	var exercise struct {
+		ID string `json:"id"`
+	}
+	e.MustJSON(http.MethodPost, "/v1/exercises", coach.Token, map[string]interface{}{
+		"name":         name,
+		"muscle_group": muscleGroup,
+		"equipment":    "Barbell",
+	}, http.StatusCreated, &exercise)
+	return exercise.ID
+}
+
+// PlanExercise adds a library exercise to a booked session and returns the planned exercise
+func (r *Roster) PlanExercise(env *Env, scheduleID, exerciseID string, order int) string {
+	env.T.Helper()
+
+	var planned struct {
+		ID string `json:"id"`
+	}
+	env.MustJSON(http.MethodPost, "/v1/pro/schedules/"+scheduleID+"/exercises", r.Coach.Token, map[string]interface{}{
+		"exercise_id":  exerciseID,
+		"target_sets":  3,
+		"target_reps":  8,
+		"rest_seconds": 90,
+		"order":        order,
+	}, http.StatusCreated, &planned)
+	return planned.ID
+}
+
+// LogSet adds a set to a planned exercise and records it as done, the way the coach app does
+func (r *Roster) LogSet(env *Env, plannedExerciseID string, index int, weight float64, reps int) string {
+	env.T.Helper()
+
+	var set struct {
+		ID string `json:"id"`
+	}
+	env.MustJSON(http.MethodPost, "/v1/pro/exercises/"+plannedExerciseID+"/sets", r.Coach.Token, map[string]interface{}{
+		"set_index": index,
+	}, http.StatusCreated, &set)
+	env.MustJSON(http.MethodPut, "/v1/pro/sets/"+set.ID, r.Coach.Token, map[string]interface{}{
+		"weight":    weight,
+		"reps":      reps,
+		"completed": true,
+	}, http.StatusOK, nil)
+	return set.ID
+}
@@ -0,0 +1,60 @@
+package scenario
+
+import (
+	"math"
+	"time"
+
+	"github.com/mansoorceksport/metamorph/internal/domain"
+)
+
+// NewScan returns a plausible InBody scan for a 175 cm member.
+// progress (0..1) moves the member from a starting to an improved body composition,
+// so a series of scans produces meaningful trends.
+func NewScan(userID string, takenAt time.Time, progress float64) *domain.InBodyRecord {
+	progress = math.Max(0, math.Min(1, progress))
+
+	weight := round1(82 - 4*progress)
+	smm := round1(33 + 1.5*progress)
+	pbf := round1(26 - 5*progress)
+	bodyFatMass := round1(weight * pbf / 100)
+	fatFreeMass := round1(weight - bodyFatMass)
+
+	record := &domain.InBodyRecord{
+		UserID:                   userID,
+		TestDateTime:             takenAt,
+		Weight:                   weight,
+		SMM:                      smm,
+		BodyFatMass:              bodyFatMass,
+		BMI:                      round1(weight / (1.75 * 1.75)),
+		PBF:                      pbf,
+		BMR:                      int(370 + 21.6*fatFreeMass),
+		VisceralFatLevel:         int(math.Round(10 - 3*progress)),
+		WaistHipRatio:            round2(0.95 - 0.05*progress),
+		InBodyScore:              math.Round(72 + 10*progress),
+		ObesityDegree:            round1(118 - 6*progress),
+		FatFreeMass:              fatFreeMass,
+		RecommendedCalorieIntake: 2200,
+		TargetWeight:             76,
+		WeightControl:            round1(76 - weight),
+		FatControl:               round1(-(bodyFatMass - 13)),
+		MuscleControl:            round1(math.Max(0, 35-smm)),
+	}
+	record.Metadata.ImageURL = "https://example.test/scans/seed.jpg"
+	return record
+}
+
+// ScanSeries returns n scans taken every interval from start, progressing evenly
+func ScanSeries(userID string, n int, start time.Time, interval time.Duration) []*domain.InBodyRecord {
+	scans := make([]*domain.InBodyRecord, 0, n)
+	for i := 0; i < n; i++ {
+		progress := 0.0
+		if n > 1 {
+			progress = float64(i) / float64(n-1)
+		}
+		scans = append(scans, NewScan(userID, start.Add(time.Duration(i)*interval), progress))
+	}
+	return scans
+}
+
+func round1(v float64) float64 { return math.Round(v*10) / 10 }
+func round2(v float64) float64 { return math.Round(v*100) / 100 }
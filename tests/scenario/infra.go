@@ -0,0 +1,57 @@
+package scenario
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// StartMongo spins up a fresh MongoDB container for the test and removes it on cleanup
+func StartMongo(t testing.TB) *mongo.Database {
+	t.Helper()
+	ctx := context.Background()
+
+	mongodbContainer, err := mongodb.Run(ctx, "mongo:latest")
+	if err != nil {
+		t.Fatalf("failed to start container: %s", err)
+	}
+
+	endpoint, err := mongodbContainer.ConnectionString(ctx)
+	if err != nil {
+		t.Fatalf("failed to get connection string: %s", err)
+	}
+
+	mongoClient, err := mongo.Connect(ctx, options.Client().ApplyURI(endpoint))
+	if err != nil {
+		t.Fatalf("failed to connect to mongo: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := mongoClient.Disconnect(ctx); err != nil {
+			log.Printf("failed to disconnect mongo: %v", err)
+		}
+		if err := mongodbContainer.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %v", err)
+		}
+	})
+	return mongoClient.Database("test_db")
+}
+
+// StartRedis starts an in-process Redis (miniredis) for the test
+func StartRedis(t testing.TB) *redis.Client {
+	t.Helper()
+
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+
+	return redis.NewClient(&redis.Options{Addr: mr.Addr()})
+}
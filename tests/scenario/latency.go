@@ -0,0 +1,41 @@
+package scenario
+
+import (
+	"math"
+	"sort"
+	"testing"
+	"time"
+)
+
+// Latencies collects request timings so benchmarks can report percentiles alongside ns/op,
+// which averages away the slow tail that users notice
+type Latencies []time.Duration
+
+// Time runs fn and records how long it took
+func (l *Latencies) Time(fn func()) {
+	start := time.Now()
+	fn()
+	*l = append(*l, time.Since(start))
+}
+
+// Percentile returns the p-th percentile (0-100), nearest-rank
+func (l Latencies) Percentile(p float64) time.Duration {
+	if len(l) == 0 {
+		return 0
+	}
+	sorted := append(Latencies(nil), l...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	rank = max(0, min(rank, len(sorted)-1))
+	return sorted[rank]
+}
+
+// Report adds the p50, p95 and p99 latencies in milliseconds to the benchmark's results
+func (l Latencies) Report(b *testing.B) {
+	for _, p := range []struct {
+		unit       string
+		percentile float64
+	}{{"p50-ms", 50}, {"p95-ms", 95}, {"p99-ms", 99}} {
+		b.ReportMetric(float64(l.Percentile(p.percentile).Microseconds())/1000, p.unit)
+	}
+}
@@ -0,0 +1,105 @@
+package scenario
+
+import (
+	"fmt"
+	"time"
+)
+
+// LoadProfile sizes the data SeedLoad creates. The defaults give a coach a realistic
+// client list and each member a few weeks of logged training and scans.
+type LoadProfile struct {
+	Coaches           int
+	ClientsPerCoach   int
+	SessionsPerClient int
+	ExercisesPerPlan  int
+	SetsPerExercise   int
+	ScansPerClient    int
+}
+
+// DefaultLoadProfile is the data the benchmarks and the load harness run against
+var DefaultLoadProfile = LoadProfile{
+	Coaches:           2,
+	ClientsPerCoach:   15,
+	SessionsPerClient: 4,
+	ExercisesPerPlan:  3,
+	SetsPerExercise:   3,
+	ScansPerClient:    4,
+}
+
+// LoadData is what load tests need from a seeded gym: tokens to call the API as its users and
+// IDs to call it with. It's written out as JSON for k6 and vegeta.
+type LoadData struct {
+	TenantID string      `json:"tenant_id"`
+	Coaches  []LoadCoach `json:"coaches"`
+}
+
+// LoadCoach is a seeded coach and their clients
+type LoadCoach struct {
+	ID      string       `json:"id"`
+	Token   string       `json:"token"`
+	Clients []LoadClient `json:"clients"`
+}
+
+// LoadClient is a seeded member with a booked, not yet completed session to log sets against
+type LoadClient struct {
+	ID                string `json:"id"`
+	Token             string `json:"token"`
+	ContractID        string `json:"contract_id"`
+	ScheduleID        string `json:"schedule_id"`
+	PlannedExerciseID string `json:"planned_exercise_id"`
+	SetID             string `json:"set_id"`
+}
+
+// SeedLoad fills a new gym with coaches, clients, completed training history and scans
+// through the API, leaving each client an open session for set logging
+func (e *Env) SeedLoad(p LoadProfile) *LoadData {
+	e.T.Helper()
+
+	gym := e.CreateTenantWithBranch("Load Gym")
+	data := &LoadData{TenantID: gym.TenantID}
+
+	// Sessions are spaced two hours apart so no coach is double-booked
+	slot := time.Now().UTC().Truncate(time.Hour).AddDate(0, 0, -7*p.SessionsPerClient)
+	nextSlot := func() time.Time {
+		slot = slot.Add(2 * time.Hour)
+		return slot
+	}
+
+	for c := 0; c < p.Coaches; c++ {
+		roster := gym.CreateCoachWithClients(p.ClientsPerCoach)
+		exercises := make([]string, p.ExercisesPerPlan)
+		for i := range exercises {
+			exercises[i] = e.CreateExercise(roster.Coach, fmt.Sprintf("Lift %d-%d", c, i), "Legs")
+		}
+
+		coach := LoadCoach{ID: roster.Coach.ID, Token: roster.Coach.Token}
+		for _, client := range roster.Clients {
+			for s := 0; s < p.SessionsPerClient; s++ {
+				scheduleID := roster.BookSession(e, client, nextSlot())
+				for i, exerciseID := range exercises {
+					planned := roster.PlanExercise(e, scheduleID, exerciseID, i)
+					for set := 0; set < p.SetsPerExercise; set++ {
+						roster.LogSet(e, planned, set, 60+2.5*float64(s), 8)
+					}
+				}
+				roster.CompleteSession(e, scheduleID)
+			}
+			if p.ScansPerClient > 0 {
+				e.SeedScans(client.Member, p.ScansPerClient)
+			}
+
+			open := roster.BookSession(e, client, time.Now().UTC().Truncate(time.Hour).Add(time.Duration(len(coach.Clients)+1)*2*time.Hour))
+			planned := roster.PlanExercise(e, open, exercises[0], 0)
+			coach.Clients = append(coach.Clients, LoadClient{
+				ID:                client.Member.ID,
+				Token:             client.Member.Token,
+				ContractID:        client.ContractID,
+				ScheduleID:        open,
+				PlannedExerciseID: planned,
+				SetID:             roster.LogSet(e, planned, 0, 60, 8),
+			})
+		}
+		data.Coaches = append(data.Coaches, coach)
+	}
+	return data
+}